@@ -0,0 +1,34 @@
+//go:build linux
+
+// Command joblet-init is the init process of jobs, without the server, the
+// gRPC services and the clients the joblet binary carries. Point
+// filesystem.initBinary at it to launch jobs with the smaller binary.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/ehsaniara/joblet/internal/modes/jobinit"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func main() {
+	cfg, _, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if level, err := logger.ParseLevel(cfg.Logging.Level); err == nil {
+		logger.SetLevel(level)
+	} else {
+		logger.SetLevel(logger.INFO)
+	}
+	logger.SetGlobalMode("init")
+
+	if err := jobinit.Run(cfg); err != nil {
+		logger.WithField("component", "main").Error("joblet-init failed", "error", err)
+		os.Exit(1)
+	}
+}
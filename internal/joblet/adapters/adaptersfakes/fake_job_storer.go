@@ -49,6 +49,17 @@ type FakeJobStorer struct {
 	deleteJobLogsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	HasCompleteLogTailStub        func(string) bool
+	hasCompleteLogTailMutex       sync.RWMutex
+	hasCompleteLogTailArgsForCall []struct {
+		arg1 string
+	}
+	hasCompleteLogTailReturns struct {
+		result1 bool
+	}
+	hasCompleteLogTailReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	HealthCheckServicesStub        func(context.Context) error
 	healthCheckServicesMutex       sync.RWMutex
 	healthCheckServicesArgsForCall []struct {
@@ -96,6 +107,20 @@ type FakeJobStorer struct {
 	listJobsReturnsOnCall map[int]struct {
 		result1 []*domain.Job
 	}
+	MatchJobUUIDsStub        func(string, int) ([]string, int)
+	matchJobUUIDsMutex       sync.RWMutex
+	matchJobUUIDsArgsForCall []struct {
+		arg1 string
+		arg2 int
+	}
+	matchJobUUIDsReturns struct {
+		result1 []string
+		result2 int
+	}
+	matchJobUUIDsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 int
+	}
 	OutputStub        func(string) ([]byte, bool, error)
 	outputMutex       sync.RWMutex
 	outputArgsForCall []struct {
@@ -394,6 +419,67 @@ func (fake *FakeJobStorer) DeleteJobLogsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeJobStorer) HasCompleteLogTail(arg1 string) bool {
+	fake.hasCompleteLogTailMutex.Lock()
+	ret, specificReturn := fake.hasCompleteLogTailReturnsOnCall[len(fake.hasCompleteLogTailArgsForCall)]
+	fake.hasCompleteLogTailArgsForCall = append(fake.hasCompleteLogTailArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.HasCompleteLogTailStub
+	fakeReturns := fake.hasCompleteLogTailReturns
+	fake.recordInvocation("HasCompleteLogTail", []interface{}{arg1})
+	fake.hasCompleteLogTailMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeJobStorer) HasCompleteLogTailCallCount() int {
+	fake.hasCompleteLogTailMutex.RLock()
+	defer fake.hasCompleteLogTailMutex.RUnlock()
+	return len(fake.hasCompleteLogTailArgsForCall)
+}
+
+func (fake *FakeJobStorer) HasCompleteLogTailCalls(stub func(string) bool) {
+	fake.hasCompleteLogTailMutex.Lock()
+	defer fake.hasCompleteLogTailMutex.Unlock()
+	fake.HasCompleteLogTailStub = stub
+}
+
+func (fake *FakeJobStorer) HasCompleteLogTailArgsForCall(i int) string {
+	fake.hasCompleteLogTailMutex.RLock()
+	defer fake.hasCompleteLogTailMutex.RUnlock()
+	argsForCall := fake.hasCompleteLogTailArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeJobStorer) HasCompleteLogTailReturns(result1 bool) {
+	fake.hasCompleteLogTailMutex.Lock()
+	defer fake.hasCompleteLogTailMutex.Unlock()
+	fake.HasCompleteLogTailStub = nil
+	fake.hasCompleteLogTailReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeJobStorer) HasCompleteLogTailReturnsOnCall(i int, result1 bool) {
+	fake.hasCompleteLogTailMutex.Lock()
+	defer fake.hasCompleteLogTailMutex.Unlock()
+	fake.HasCompleteLogTailStub = nil
+	if fake.hasCompleteLogTailReturnsOnCall == nil {
+		fake.hasCompleteLogTailReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.hasCompleteLogTailReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeJobStorer) HealthCheckServices(arg1 context.Context) error {
 	fake.healthCheckServicesMutex.Lock()
 	ret, specificReturn := fake.healthCheckServicesReturnsOnCall[len(fake.healthCheckServicesArgsForCall)]
@@ -636,6 +722,71 @@ func (fake *FakeJobStorer) ListJobsReturnsOnCall(i int, result1 []*domain.Job) {
 	}{result1}
 }
 
+func (fake *FakeJobStorer) MatchJobUUIDs(arg1 string, arg2 int) ([]string, int) {
+	fake.matchJobUUIDsMutex.Lock()
+	ret, specificReturn := fake.matchJobUUIDsReturnsOnCall[len(fake.matchJobUUIDsArgsForCall)]
+	fake.matchJobUUIDsArgsForCall = append(fake.matchJobUUIDsArgsForCall, struct {
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.MatchJobUUIDsStub
+	fakeReturns := fake.matchJobUUIDsReturns
+	fake.recordInvocation("MatchJobUUIDs", []interface{}{arg1, arg2})
+	fake.matchJobUUIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeJobStorer) MatchJobUUIDsCallCount() int {
+	fake.matchJobUUIDsMutex.RLock()
+	defer fake.matchJobUUIDsMutex.RUnlock()
+	return len(fake.matchJobUUIDsArgsForCall)
+}
+
+func (fake *FakeJobStorer) MatchJobUUIDsCalls(stub func(string, int) ([]string, int)) {
+	fake.matchJobUUIDsMutex.Lock()
+	defer fake.matchJobUUIDsMutex.Unlock()
+	fake.MatchJobUUIDsStub = stub
+}
+
+func (fake *FakeJobStorer) MatchJobUUIDsArgsForCall(i int) (string, int) {
+	fake.matchJobUUIDsMutex.RLock()
+	defer fake.matchJobUUIDsMutex.RUnlock()
+	argsForCall := fake.matchJobUUIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeJobStorer) MatchJobUUIDsReturns(result1 []string, result2 int) {
+	fake.matchJobUUIDsMutex.Lock()
+	defer fake.matchJobUUIDsMutex.Unlock()
+	fake.MatchJobUUIDsStub = nil
+	fake.matchJobUUIDsReturns = struct {
+		result1 []string
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeJobStorer) MatchJobUUIDsReturnsOnCall(i int, result1 []string, result2 int) {
+	fake.matchJobUUIDsMutex.Lock()
+	defer fake.matchJobUUIDsMutex.Unlock()
+	fake.MatchJobUUIDsStub = nil
+	if fake.matchJobUUIDsReturnsOnCall == nil {
+		fake.matchJobUUIDsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 int
+		})
+	}
+	fake.matchJobUUIDsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 int
+	}{result1, result2}
+}
+
 func (fake *FakeJobStorer) Output(arg1 string) ([]byte, bool, error) {
 	fake.outputMutex.Lock()
 	ret, specificReturn := fake.outputReturnsOnCall[len(fake.outputArgsForCall)]
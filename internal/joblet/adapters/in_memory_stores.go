@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/outputlimit"
 	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	"github.com/ehsaniara/joblet/internal/joblet/redact"
 	"github.com/ehsaniara/joblet/internal/joblet/state"
 	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
 	"github.com/ehsaniara/joblet/pkg/client"
@@ -94,7 +96,13 @@ func NewJobStore(cfg *config.Config, persistEnabled bool, logger *logger.Logger)
 		pubsub.WithBufferSize[JobEvent](bufferSize),
 	)
 
-	logMgr := NewSimpleLogManager()
+	// Keep a bounded tail of each job's output in memory for tail/follow requests;
+	// persist holds the complete history
+	logTailKB := 1024 // Default
+	if cfg != nil {
+		logTailKB = cfg.Buffers.LogTailKB
+	}
+	logMgr := NewRingLogManager(logTailKB * 1024)
 
 	// Create persist client for historical log/metric deletion
 	// Health check is deferred - happens after subprocess startup in server.go
@@ -113,7 +121,7 @@ func NewJobStore(cfg *config.Config, persistEnabled bool, logger *logger.Logger)
 
 	// Create state client for persistent job state across restarts
 	// Health check is deferred - happens after subprocess startup in server.go
-	stateSocketPath := "/opt/joblet/run/state-ipc.sock"
+	stateSocketPath := state.SocketPath
 
 	// Use pooled client for high-performance concurrent access (1000+ jobs)
 	// Pool size defaults to 20 connections, tuned for high concurrency
@@ -126,8 +134,25 @@ func NewJobStore(cfg *config.Config, persistEnabled bool, logger *logger.Logger)
 	logger.Info("pooled state client created - will connect after subprocess starts",
 		"socket", stateSocketPath, "pool_size", poolSize)
 
+	// Secrets are redacted from output before it is buffered or forwarded
+	var redaction *redact.Policy
+	if cfg != nil {
+		policy, err := redact.NewPolicy(cfg.Redaction)
+		if err != nil {
+			logger.Error("invalid redaction config, job output will not be redacted", "error", err)
+		} else {
+			redaction = policy
+		}
+	}
+
+	// Output over the node's rate and size limits is dropped before it is buffered
+	var outputLimits *outputlimit.Policy
+	if cfg != nil {
+		outputLimits = outputlimit.NewPolicy(cfg.OutputLimits)
+	}
+
 	// Logs are buffered in-memory for real-time streaming and forwarded to persist via IPC
-	return NewJobStorer(store, logMgr, pubsubSystem, persistClient, stateClient, persistEnabled, logger)
+	return NewJobStorer(store, logMgr, pubsubSystem, persistClient, stateClient, persistEnabled, redaction, outputLimits, logger)
 }
 
 // NewVolumeStore creates a volume store directly
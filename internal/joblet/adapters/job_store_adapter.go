@@ -3,13 +3,18 @@ package adapters
 import (
 	"context"
 	"fmt"
-	"strings"
+	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 	"github.com/ehsaniara/joblet/internal/joblet/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/outputlimit"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
+	"github.com/ehsaniara/joblet/internal/joblet/progress"
 	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	"github.com/ehsaniara/joblet/internal/joblet/redact"
 	"github.com/ehsaniara/joblet/internal/joblet/state"
 	pb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
 	"github.com/ehsaniara/joblet/pkg/logger"
@@ -25,6 +30,10 @@ type jobStore[K comparable, V any] interface {
 	Close() error
 }
 
+// maxReportedPrefixMatches caps how many candidate UUIDs are reported when a
+// short-form UUID is ambiguous.
+const maxReportedPrefixMatches = 10
+
 // Error types to match store package behavior
 var (
 	ErrKeyExists   = fmt.Errorf("key already exists")
@@ -54,10 +63,19 @@ type jobStoreAdapter struct {
 	tasks      map[string]*taskWrapper
 	tasksMutex sync.RWMutex
 
+	// Sorted index of stored job UUIDs for short-form UUID resolution
+	uuids *prefixindex.Index[struct{}]
+
 	logger         *logger.Logger
 	closed         bool
 	closeMutex     sync.RWMutex
 	persistEnabled bool // If false, skip all buffering (live streaming only)
+
+	// Redaction policy applied to job output before it is buffered or published (nil = off)
+	redaction *redact.Policy
+
+	// Rate and size limits applied to job output after redaction (nil = unlimited)
+	outputLimits *outputlimit.Policy
 }
 
 // Ensure jobStoreAdapter implements the interfaces
@@ -72,6 +90,17 @@ type taskWrapper struct {
 	subMutex    sync.RWMutex
 	logger      *logger.Logger
 	pubsub      pubsub.PubSub[JobEvent]
+
+	// Output redaction for this job (nil = nothing to redact) and its match count
+	redactor   atomic.Pointer[redact.Redactor]
+	redactions atomic.Int64
+
+	// Output limits for this job (nil = unlimited)
+	limiter *outputlimit.Limiter
+
+	// Progress markers in the job's output and the latest progress they report
+	progress       *progress.Scanner
+	latestProgress atomic.Pointer[domain.JobProgress]
 }
 
 // subscriptionContext manages a single client subscription.
@@ -85,12 +114,17 @@ type subscriptionContext struct {
 
 // JobEvent represents events published about job state changes.
 type JobEvent struct {
-	Type      string            `json:"type"` // CREATED, UPDATED, DELETED, LOG_CHUNK
+	Type      string            `json:"type"` // CREATED, UPDATED, DELETED, LOG_CHUNK, PROGRESS
 	JobID     string            `json:"JobId"`
 	Status    string            `json:"status,omitempty"`
 	LogChunk  []byte            `json:"log_chunk,omitempty"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
 	Timestamp int64             `json:"timestamp"`
+	// Tenant of the job, set on LOG_CHUNK events so persist can pick the
+	// tenant's encryption key
+	Tenant string `json:"tenant,omitempty"`
+	// Progress the job reported, set on PROGRESS events
+	Progress *domain.JobProgress `json:"progress,omitempty"`
 }
 
 // NewJobStorer creates a new job store adapter with the specified backends.
@@ -101,6 +135,8 @@ func NewJobStorer(
 	persistClient pb.PersistServiceClient,
 	stateClient state.StateClient,
 	persistEnabled bool,
+	redaction *redact.Policy,
+	outputLimits *outputlimit.Policy,
 	logger *logger.Logger,
 ) JobStorer {
 	if logger == nil {
@@ -114,7 +150,10 @@ func NewJobStorer(
 		persistClient:  persistClient,
 		stateClient:    stateClient,
 		persistEnabled: persistEnabled,
+		redaction:      redaction,
+		outputLimits:   outputLimits,
 		tasks:          make(map[string]*taskWrapper),
+		uuids:          prefixindex.New[struct{}](),
 		logger:         logger,
 	}
 }
@@ -142,6 +181,7 @@ func (a *jobStoreAdapter) CreateNewJob(job *domain.Job) {
 		a.logger.Error("failed to create job in store", "jobId", job.Uuid, "error", err)
 		return
 	}
+	a.uuids.Put(job.Uuid, struct{}{})
 
 	logBuffer := a.logMgr.GetBuffer(job.Uuid)
 	a.logger.Debug("log buffer created successfully for job", "jobId", job.Uuid)
@@ -152,7 +192,10 @@ func (a *jobStoreAdapter) CreateNewJob(job *domain.Job) {
 		subscribers: make(map[string]*subscriptionContext),
 		logger:      a.logger.WithField("jobId", job.Uuid),
 		pubsub:      a.pubsub,
+		limiter:     a.outputLimits.ForJob(time.Now()),
+		progress:    progress.NewScanner(),
 	}
+	task.redactor.Store(a.redaction.ForJob(job.SecretEnvironment))
 
 	// Store task wrapper
 	a.tasksMutex.Lock()
@@ -210,8 +253,27 @@ func (a *jobStoreAdapter) UpdateJob(job *domain.Job) {
 
 	oldStatus := string(task.job.Status)
 	newStatus := string(job.Status)
+	secretsChanged := !maps.Equal(task.job.SecretEnvironment, job.SecretEnvironment)
 	a.tasksMutex.RUnlock()
 
+	// Secrets added after creation (delegated credentials of scheduled jobs)
+	// must be redacted too
+	if secretsChanged {
+		a.flushRedactor(task, job.Uuid, task.redactor.Swap(a.redaction.ForJob(job.SecretEnvironment)))
+	}
+	// The job wrote all its output, release what was held back before
+	// subscribers see it end
+	if job.IsCompleted() {
+		a.flushRedactor(task, job.Uuid, task.redactor.Load())
+	}
+
+	// Output counters are kept by the task, the caller's copy may be stale
+	if task.countersAhead(job) {
+		updated := *job
+		task.applyOutputCounters(&updated)
+		job = &updated
+	}
+
 	// Update in store
 	ctx := context.Background()
 	if err := a.jobStore.Update(ctx, job.Uuid, job); err != nil {
@@ -278,7 +340,11 @@ func (a *jobStoreAdapter) Job(id string) (*domain.Job, bool) {
 
 	if exists {
 		a.logger.Debug("job retrieved successfully", "jobId", id, "status", string(job.Status))
-		return job.DeepCopy(), true
+		jobCopy := job.DeepCopy()
+		a.tasksMutex.RLock()
+		a.applyOutputCounters(jobCopy)
+		a.tasksMutex.RUnlock()
+		return jobCopy, true
 	}
 
 	a.logger.Debug("job not found", "jobId", id)
@@ -303,29 +369,15 @@ func (a *jobStoreAdapter) JobByPrefix(prefix string) (*domain.Job, bool) {
 		}
 		a.closeMutex.RUnlock()
 
-		ctx := context.Background()
-		jobs, err := a.jobStore.List(ctx)
-		if err != nil {
-			a.logger.Error("failed to list jobs for prefix search", "prefix", prefix, "error", err)
-			return nil, false
-		}
-
-		var matches []*domain.Job
-		for _, job := range jobs {
-			if strings.HasPrefix(job.Uuid, prefix) {
-				matches = append(matches, job)
+		uuid, _, count := a.uuids.Resolve(prefix)
+		if count == 1 {
+			if job, exists := a.Job(uuid); exists {
+				a.logger.Debug("found unique job by prefix", "prefix", prefix, "jobId", uuid)
+				return job, true
 			}
-		}
-
-		if len(matches) == 1 {
-			a.logger.Debug("found unique job by prefix", "prefix", prefix, "jobId", matches[0].Uuid)
-			return matches[0].DeepCopy(), true
-		} else if len(matches) > 1 {
-			var matchedUuids []string
-			for _, job := range matches {
-				matchedUuids = append(matchedUuids, job.Uuid)
-			}
-			a.logger.Warn("prefix matches multiple jobs - ambiguous", "prefix", prefix, "matches", matchedUuids)
+		} else if count > 1 {
+			a.logger.Warn("prefix matches multiple jobs - ambiguous", "prefix", prefix,
+				"matchCount", count, "matches", a.uuids.Match(prefix, maxReportedPrefixMatches))
 			return nil, false
 		}
 	}
@@ -354,16 +406,56 @@ func (a *jobStoreAdapter) ListJobs() []*domain.Job {
 	}
 
 	result := make([]*domain.Job, len(jobs))
+	a.tasksMutex.RLock()
 	for i, job := range jobs {
 		result[i] = job.DeepCopy()
+		a.applyOutputCounters(result[i])
 	}
+	a.tasksMutex.RUnlock()
 
 	return result
 }
 
+// applyOutputCounters sets the job's current redaction and output limit
+// counters from its task. Callers hold tasksMutex.
+func (a *jobStoreAdapter) applyOutputCounters(job *domain.Job) {
+	if task, exists := a.tasks[job.Uuid]; exists {
+		task.applyOutputCounters(job)
+	}
+}
+
+// countersAhead reports whether the task counted output the job does not
+// show yet
+func (t *taskWrapper) countersAhead(job *domain.Job) bool {
+	return t.redactions.Load() > job.Redactions ||
+		t.limiter.Dropped() > job.OutputDroppedBytes ||
+		t.limiter.Truncated() && !job.OutputTruncated ||
+		t.progressAhead(job)
+}
+
+// progressAhead reports whether the task read progress newer than the job's
+func (t *taskWrapper) progressAhead(job *domain.Job) bool {
+	latest := t.latestProgress.Load()
+	return latest != nil && (job.Progress == nil || latest.UpdatedAt.After(job.Progress.UpdatedAt))
+}
+
+// applyOutputCounters raises the job's output counters to the task's
+func (t *taskWrapper) applyOutputCounters(job *domain.Job) {
+	job.Redactions = max(job.Redactions, t.redactions.Load())
+	job.OutputDroppedBytes = max(job.OutputDroppedBytes, t.limiter.Dropped())
+	job.OutputTruncated = job.OutputTruncated || t.limiter.Truncated()
+	if t.progressAhead(job) {
+		job.Progress = t.latestProgress.Load().DeepCopy()
+	}
+}
+
 // WriteToBuffer appends log data to the specified job's output buffer.
 // When persist is enabled: Buffers data + publishes to pubsub (for IPC forwarding and live streaming)
 // When persist is disabled: Only publishes to pubsub (live streaming only, no buffering)
+// Secrets are redacted first, so neither the buffer nor any subscriber sees them.
+// Progress marker lines are then read and published as PROGRESS events.
+// Output over the job's rate limit or size cap is then dropped, with a marker
+// line in the stream.
 // Supports UUID prefix resolution.
 func (a *jobStoreAdapter) WriteToBuffer(jobID string, chunk []byte) {
 	if len(chunk) == 0 {
@@ -387,6 +479,53 @@ func (a *jobStoreAdapter) WriteToBuffer(jobID string, chunk []byte) {
 		return
 	}
 
+	chunk, matches := task.redactor.Load().Redact(chunk)
+	if matches > 0 {
+		task.redactions.Add(int64(matches))
+		a.logger.Debug("redacted secrets from log chunk", "jobId", resolvedUuid, "matches", matches)
+	}
+	if len(chunk) == 0 {
+		return
+	}
+	a.writeOutput(task, resolvedUuid, chunk)
+}
+
+// flushRedactor releases the output a job's redactor held back in case a
+// secret continued in the next write, once no such write can come
+func (a *jobStoreAdapter) flushRedactor(task *taskWrapper, jobID string, redactor *redact.Redactor) {
+	rest, matches := redactor.Flush()
+	if matches > 0 {
+		task.redactions.Add(int64(matches))
+	}
+	if len(rest) > 0 {
+		a.writeOutput(task, jobID, rest)
+	}
+}
+
+// writeOutput buffers and publishes a redacted chunk of a job's output
+func (a *jobStoreAdapter) writeOutput(task *taskWrapper, resolvedUuid string, chunk []byte) {
+	// Progress is read before the limits, so it keeps up when output is dropped
+	if p := task.progress.Scan(chunk, time.Now()); p != nil {
+		task.latestProgress.Store(p)
+		if err := a.publishEvent(JobEvent{
+			Type:      "PROGRESS",
+			JobID:     resolvedUuid,
+			Progress:  p,
+			Timestamp: time.Now().Unix(),
+			Tenant:    task.job.Tenant,
+		}); err != nil {
+			a.logger.Warn("failed to publish job progress event", "jobId", resolvedUuid, "error", err)
+		}
+	}
+
+	chunk, dropped := task.limiter.Limit(chunk, time.Now())
+	if dropped > 0 {
+		a.logger.Debug("dropped log output over the output limits", "jobId", resolvedUuid, "dropped", dropped)
+	}
+	if len(chunk) == 0 {
+		return
+	}
+
 	// Only write to buffer if persist is enabled (gap prevention)
 	// When persist is disabled, skip buffering to avoid unbounded growth
 	if a.persistEnabled && task.logBuffer != nil {
@@ -405,6 +544,7 @@ func (a *jobStoreAdapter) WriteToBuffer(jobID string, chunk []byte) {
 		JobID:     resolvedUuid,
 		LogChunk:  chunk,
 		Timestamp: time.Now().Unix(),
+		Tenant:    task.job.Tenant,
 	}); err != nil {
 		a.logger.Warn("failed to publish log chunk event", "jobId", resolvedUuid, "error", err)
 	}
@@ -521,6 +661,26 @@ func (a *jobStoreAdapter) SendUpdatesToClientWithSkip(ctx context.Context, id st
 	return a.subscribeToJobUpdates(ctx, resolvedUuid, task, stream)
 }
 
+// HasCompleteLogTail reports whether the job's ring buffer still holds every log
+// chunk the job has written. Only true while persist is enabled (otherwise nothing
+// is buffered) and before the job's output has outgrown the configured tail size.
+func (a *jobStoreAdapter) HasCompleteLogTail(id string) bool {
+	if !a.persistEnabled {
+		return false
+	}
+
+	resolvedUuid, err := a.resolveUuidByPrefix(id)
+	if err != nil {
+		return false
+	}
+
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[resolvedUuid]
+	a.tasksMutex.RUnlock()
+
+	return exists && task.logBuffer != nil && task.logBuffer.Complete()
+}
+
 // PubSub returns the pub-sub instance for external integration (e.g., IPC)
 func (a *jobStoreAdapter) PubSub() pubsub.PubSub[JobEvent] {
 	return a.pubsub
@@ -559,6 +719,7 @@ func (a *jobStoreAdapter) SyncFromPersistentState(ctx context.Context) error {
 			a.logger.Warn("failed to restore job from persistent state", "jobId", job.Uuid, "error", err)
 			continue
 		}
+		a.uuids.Put(job.Uuid, struct{}{})
 
 		// Create task wrapper for job management
 		logBuffer := a.logMgr.GetBuffer(job.Uuid)
@@ -649,6 +810,13 @@ func (a *jobStoreAdapter) ResolveJobUUID(idOrPrefix string) (string, error) {
 	return a.resolveJobUuid(idOrPrefix, "ResolveJobUUID")
 }
 
+// MatchJobUUIDs returns up to limit UUIDs of jobs starting with prefix in
+// sorted order, and how many jobs match in all
+func (a *jobStoreAdapter) MatchJobUUIDs(prefix string, limit int) ([]string, int) {
+	_, _, count := a.uuids.Resolve(prefix)
+	return a.uuids.Match(prefix, limit), count
+}
+
 // validateAndResolveJob resolves a job ID to UUID and retrieves the job, used across multiple operations
 func (a *jobStoreAdapter) validateAndResolveJob(jobID string, operation string) (string, *domain.Job, error) {
 	resolvedUuid, err := a.resolveJobUuid(jobID, operation)
@@ -802,6 +970,7 @@ func (a *jobStoreAdapter) DeleteJob(jobID string) error {
 		a.logger.Error("failed to delete job from store", "jobId", resolvedUuid, "error", err)
 		return fmt.Errorf("failed to delete job from store: %w", err)
 	}
+	a.uuids.Delete(resolvedUuid)
 
 	// Delete from state backend (async fire-and-forget)
 	if a.stateClient != nil {
@@ -826,33 +995,31 @@ func (a *jobStoreAdapter) DeleteJob(jobID string) error {
 // Helper methods
 
 // resolveUuidByPrefix resolves a UUID prefix to a full UUID.
-// Returns the input if it's already a full UUID (36 chars).
-// Searches task map for unique prefix matches, returns error for ambiguous or missing matches.
+// Returns the input if it's already a full UUID (36 chars) or a known task ID.
+// Otherwise consults the sorted UUID index, returning an error for ambiguous or missing matches.
 func (a *jobStoreAdapter) resolveUuidByPrefix(prefix string) (string, error) {
 	// If it's already a full UUID (36 characters), return as-is
 	if len(prefix) == 36 {
 		return prefix, nil
 	}
 
-	// Search for matching UUIDs in tasks map
+	// Exact task IDs resolve to themselves
 	a.tasksMutex.RLock()
-	defer a.tasksMutex.RUnlock()
-
-	var matches []string
-	for uuid := range a.tasks {
-		if strings.HasPrefix(uuid, prefix) {
-			matches = append(matches, uuid)
-		}
+	_, exists := a.tasks[prefix]
+	a.tasksMutex.RUnlock()
+	if exists {
+		return prefix, nil
 	}
 
-	if len(matches) == 0 {
+	uuid, _, count := a.uuids.Resolve(prefix)
+	if count == 0 {
 		return "", fmt.Errorf("no job found with prefix %s", prefix)
 	}
-	if len(matches) > 1 {
-		return "", fmt.Errorf("prefix %s matches multiple jobs: %v", prefix, matches)
+	if count > 1 {
+		return "", fmt.Errorf("prefix %s matches multiple jobs: %v", prefix, a.uuids.Match(prefix, maxReportedPrefixMatches))
 	}
 
-	return matches[0], nil
+	return uuid, nil
 }
 
 // publishEvent publishes a job event to the pub-sub system.
@@ -988,7 +1155,7 @@ func (a *jobStoreAdapter) subscribeToJobUpdates(ctx context.Context, jobID strin
 				case "UPDATED":
 					a.logger.Debug("received job status update", "jobId", jobID, "status", event.Status)
 					// When job reaches final status, enter drain mode instead of immediately terminating
-					if event.Status == "COMPLETED" || event.Status == "FAILED" || event.Status == "STOPPED" {
+					if event.Status == "COMPLETED" || event.Status == "FAILED" || event.Status == "STOPPED" || event.Status == "EXPIRED" {
 						if !jobCompleted {
 							jobCompleted = true
 							// Set drain deadline to allow final log chunks to arrive
@@ -1041,7 +1208,7 @@ func (a *jobStoreAdapter) HealthCheckServices(ctx context.Context) error {
 		return fmt.Errorf("state service health check failed: %w", err)
 	}
 
-	a.logger.Info("state service health check passed (Ping)")
+	a.logger.Debug("state service health check passed (Ping)")
 
 	// Check persist service (if enabled)
 	if a.persistEnabled {
@@ -1058,7 +1225,7 @@ func (a *jobStoreAdapter) HealthCheckServices(ctx context.Context) error {
 		if _, err := a.persistClient.Ping(ctx, &pb.PingRequest{}); err != nil {
 			return fmt.Errorf("persist service health check failed: %w", err)
 		}
-		a.logger.Info("persist service health check passed (Ping)")
+		a.logger.Debug("persist service health check passed (Ping)")
 	}
 
 	return nil
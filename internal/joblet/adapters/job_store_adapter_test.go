@@ -1,10 +1,15 @@
 package adapters
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/outputlimit"
 	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	"github.com/ehsaniara/joblet/internal/joblet/redact"
+	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
@@ -20,7 +25,7 @@ func TestWriteToBuffer_PersistEnabled(t *testing.T) {
 	logMgr := NewSimpleLogManager()
 	ps := pubsub.NewPubSub[JobEvent]()
 
-	adapter := NewJobStorer(store, logMgr, ps, nil, nil, true, log) // persistClient=nil, stateClient=nil, persistEnabled = true
+	adapter := NewJobStorer(store, logMgr, ps, nil, nil, true, nil, nil, log) // persistClient=nil, stateClient=nil, persistEnabled = true
 	jobStoreAdapter := adapter.(*jobStoreAdapter)
 
 	// Create a test job
@@ -60,7 +65,7 @@ func TestWriteToBuffer_PersistDisabled(t *testing.T) {
 	logMgr := NewSimpleLogManager()
 	ps := pubsub.NewPubSub[JobEvent]()
 
-	adapter := NewJobStorer(store, logMgr, ps, nil, nil, false, log) // persistClient=nil, stateClient=nil, persistEnabled = false
+	adapter := NewJobStorer(store, logMgr, ps, nil, nil, false, nil, nil, log) // persistClient=nil, stateClient=nil, persistEnabled = false
 	jobStoreAdapter := adapter.(*jobStoreAdapter)
 
 	// Create a test job
@@ -99,7 +104,7 @@ func TestWriteToBuffer_MultipleWrites_PersistEnabled(t *testing.T) {
 	logMgr := NewSimpleLogManager()
 	ps := pubsub.NewPubSub[JobEvent]()
 
-	adapter := NewJobStorer(store, logMgr, ps, nil, nil, true, log) // persistClient=nil, stateClient=nil, persistEnabled = true
+	adapter := NewJobStorer(store, logMgr, ps, nil, nil, true, nil, nil, log) // persistClient=nil, stateClient=nil, persistEnabled = true
 	jobStoreAdapter := adapter.(*jobStoreAdapter)
 
 	// Create a test job
@@ -146,7 +151,7 @@ func TestWriteToBuffer_MultipleWrites_PersistDisabled(t *testing.T) {
 	logMgr := NewSimpleLogManager()
 	ps := pubsub.NewPubSub[JobEvent]()
 
-	adapter := NewJobStorer(store, logMgr, ps, nil, nil, false, log) // persistClient=nil, stateClient=nil, persistEnabled = false
+	adapter := NewJobStorer(store, logMgr, ps, nil, nil, false, nil, nil, log) // persistClient=nil, stateClient=nil, persistEnabled = false
 	jobStoreAdapter := adapter.(*jobStoreAdapter)
 
 	// Create a test job
@@ -174,3 +179,226 @@ func TestWriteToBuffer_MultipleWrites_PersistDisabled(t *testing.T) {
 	chunks := buffer.ReadAll()
 	assert.Equal(t, 0, len(chunks), "Buffer should remain empty when persist disabled (no buffering)")
 }
+
+// TestJobByPrefix_UsesUuidIndex verifies short-form UUID resolution through the sorted index
+func TestJobByPrefix_UsesUuidIndex(t *testing.T) {
+	// Setup
+	log := logger.New()
+	store := &SimpleJobStore{
+		jobs:   make(map[string]*domain.Job),
+		logger: log,
+	}
+	adapter := NewJobStorer(store, NewSimpleLogManager(), pubsub.NewPubSub[JobEvent](), nil, nil, true, nil, nil, log)
+
+	adapter.CreateNewJob(&domain.Job{Uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479", Status: "COMPLETED"})
+	adapter.CreateNewJob(&domain.Job{Uuid: "f47bd20c-58cc-4372-a567-0e02b2c3d479", Status: "COMPLETED"})
+	adapter.CreateNewJob(&domain.Job{Uuid: "0a1b2c3d-58cc-4372-a567-0e02b2c3d479", Status: "COMPLETED"})
+
+	// Unique prefix resolves
+	job, found := adapter.JobByPrefix("f47ac")
+	assert.True(t, found, "Unique prefix should resolve")
+	assert.Equal(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479", job.Uuid)
+
+	uuid, err := adapter.ResolveJobUUID("0a1b")
+	assert.NoError(t, err)
+	assert.Equal(t, "0a1b2c3d-58cc-4372-a567-0e02b2c3d479", uuid)
+
+	// Ambiguous prefix does not resolve
+	_, found = adapter.JobByPrefix("f47")
+	assert.False(t, found, "Ambiguous prefix should not resolve")
+	_, err = adapter.ResolveJobUUID("f47")
+	assert.Error(t, err)
+
+	// Deleted jobs leave the index
+	assert.NoError(t, adapter.DeleteJob("0a1b"))
+	_, found = adapter.JobByPrefix("0a1b")
+	assert.False(t, found, "Deleted job should not resolve by prefix")
+}
+
+// TestWriteToBuffer_Redaction verifies secrets are masked before buffering and counted on the job
+func TestWriteToBuffer_Redaction(t *testing.T) {
+	// Setup
+	log := logger.New()
+	store := &SimpleJobStore{
+		jobs:   make(map[string]*domain.Job),
+		logger: log,
+	}
+	policy, err := redact.NewPolicy(config.RedactionConfig{SecretValues: true, MinSecretLength: 4, Replacement: "***"})
+	assert.NoError(t, err)
+	logMgr := NewSimpleLogManager()
+	adapter := NewJobStorer(store, logMgr, pubsub.NewPubSub[JobEvent](), nil, nil, true, policy, nil, log)
+
+	jobID := "redacted-job"
+	adapter.CreateNewJob(&domain.Job{
+		Uuid:              jobID,
+		Status:            "RUNNING",
+		SecretEnvironment: map[string]string{"API_TOKEN": "s3cr3t-token"},
+	})
+
+	adapter.WriteToBuffer(jobID, []byte("token=s3cr3t-token\n"))
+	adapter.WriteToBuffer(jobID, []byte("no secrets here\n"))
+
+	// Verify: Only the masked output was buffered
+	chunks := logMgr.GetBuffer(jobID).ReadAll()
+	assert.Equal(t, [][]byte{[]byte("token=***\n"), []byte("no secrets here\n")}, chunks)
+
+	// Verify: The count is reported with the job and survives updates
+	job, found := adapter.Job(jobID)
+	assert.True(t, found)
+	assert.Equal(t, int64(1), job.Redactions)
+
+	job.Status = "COMPLETED"
+	job.Redactions = 0
+	adapter.UpdateJob(job)
+	jobs := adapter.ListJobs()
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, int64(1), jobs[0].Redactions)
+}
+
+// TestWriteToBuffer_RedactsSecretSplitAcrossWrites verifies a secret written in two parts is masked and held-back output is released when the job ends
+func TestWriteToBuffer_RedactsSecretSplitAcrossWrites(t *testing.T) {
+	log := logger.New()
+	store := &SimpleJobStore{
+		jobs:   make(map[string]*domain.Job),
+		logger: log,
+	}
+	policy, err := redact.NewPolicy(config.RedactionConfig{SecretValues: true, MinSecretLength: 4, Replacement: "***"})
+	assert.NoError(t, err)
+	logMgr := NewSimpleLogManager()
+	adapter := NewJobStorer(store, logMgr, pubsub.NewPubSub[JobEvent](), nil, nil, true, policy, nil, log)
+
+	jobID := "split-secret-job"
+	adapter.CreateNewJob(&domain.Job{
+		Uuid:              jobID,
+		Status:            "RUNNING",
+		SecretEnvironment: map[string]string{"API_TOKEN": "s3cr3t-token"},
+	})
+
+	adapter.WriteToBuffer(jobID, []byte("token=s3cr3t"))
+	adapter.WriteToBuffer(jobID, []byte("-token\ndone s3"))
+
+	job, _ := adapter.Job(jobID)
+	job.Status = "COMPLETED"
+	adapter.UpdateJob(job)
+
+	var output []byte
+	for _, chunk := range logMgr.GetBuffer(jobID).ReadAll() {
+		output = append(output, chunk...)
+	}
+	assert.Equal(t, "token=***\ndone s3", string(output))
+}
+
+// TestWriteToBuffer_RedactsSecretsAddedByUpdate verifies secrets added after creation are masked too
+func TestWriteToBuffer_RedactsSecretsAddedByUpdate(t *testing.T) {
+	log := logger.New()
+	store := &SimpleJobStore{
+		jobs:   make(map[string]*domain.Job),
+		logger: log,
+	}
+	policy, err := redact.NewPolicy(config.RedactionConfig{SecretValues: true, MinSecretLength: 4, Replacement: "***"})
+	assert.NoError(t, err)
+	logMgr := NewSimpleLogManager()
+	adapter := NewJobStorer(store, logMgr, pubsub.NewPubSub[JobEvent](), nil, nil, true, policy, nil, log)
+
+	// Scheduled jobs get delegated credentials when they start, after creation
+	jobID := "scheduled-job"
+	adapter.CreateNewJob(&domain.Job{Uuid: jobID, Status: "SCHEDULED"})
+	job, _ := adapter.Job(jobID)
+	job.Status = "INITIALIZING"
+	job.SecretEnvironment = map[string]string{"AWS_SESSION_TOKEN": "session-token"}
+	adapter.UpdateJob(job)
+
+	adapter.WriteToBuffer(jobID, []byte("token=session-token\n"))
+
+	chunks := logMgr.GetBuffer(jobID).ReadAll()
+	assert.Equal(t, [][]byte{[]byte("token=***\n")}, chunks)
+}
+
+// TestWriteToBuffer_OutputLimits verifies output over the size cap is dropped with a marker and counted on the job
+func TestWriteToBuffer_OutputLimits(t *testing.T) {
+	log := logger.New()
+	store := &SimpleJobStore{
+		jobs:   make(map[string]*domain.Job),
+		logger: log,
+	}
+	limits := outputlimit.NewPolicy(config.OutputLimitsConfig{MaxBytes: 6})
+	logMgr := NewSimpleLogManager()
+	adapter := NewJobStorer(store, logMgr, pubsub.NewPubSub[JobEvent](), nil, nil, true, nil, limits, log)
+
+	jobID := "chatty-job"
+	adapter.CreateNewJob(&domain.Job{Uuid: jobID, Status: "RUNNING"})
+
+	adapter.WriteToBuffer(jobID, []byte("line1\n"))
+	adapter.WriteToBuffer(jobID, []byte("line2\n"))
+	adapter.WriteToBuffer(jobID, []byte("line3\n"))
+
+	// Verify: The cap is marked once and nothing is buffered after it
+	chunks := logMgr.GetBuffer(jobID).ReadAll()
+	assert.Equal(t, [][]byte{
+		[]byte("line1\n"),
+		[]byte("[joblet: output truncated after 6 bytes, the rest of the job's output is dropped]\n"),
+	}, chunks)
+
+	// Verify: The counters are reported with the job and survive updates
+	job, found := adapter.Job(jobID)
+	assert.True(t, found)
+	assert.Equal(t, int64(12), job.OutputDroppedBytes)
+	assert.True(t, job.OutputTruncated)
+
+	job.Status = "COMPLETED"
+	job.OutputDroppedBytes = 0
+	job.OutputTruncated = false
+	adapter.UpdateJob(job)
+	jobs := adapter.ListJobs()
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, int64(12), jobs[0].OutputDroppedBytes)
+	assert.True(t, jobs[0].OutputTruncated)
+}
+
+// TestWriteToBuffer_Progress verifies progress markers are published and reported with the job
+func TestWriteToBuffer_Progress(t *testing.T) {
+	log := logger.New()
+	store := &SimpleJobStore{
+		jobs:   make(map[string]*domain.Job),
+		logger: log,
+	}
+	ps := pubsub.NewPubSub[JobEvent]()
+	logMgr := NewSimpleLogManager()
+	adapter := NewJobStorer(store, logMgr, ps, nil, nil, true, nil, nil, log)
+
+	jobID := "training-job"
+	adapter.CreateNewJob(&domain.Job{Uuid: jobID, Status: "RUNNING"})
+	updates, unsubscribe, err := ps.Subscribe(context.Background(), "jobs")
+	assert.NoError(t, err)
+	defer unsubscribe()
+
+	adapter.WriteToBuffer(jobID, []byte("::joblet-progress::{\"percent\":25,\"phase\":\"train\"}\nepoch 1\n"))
+
+	// Verify: The marker line stays in the log and a PROGRESS event is published
+	chunks := logMgr.GetBuffer(jobID).ReadAll()
+	assert.Len(t, chunks, 1)
+	var event JobEvent
+	for event.Type != "PROGRESS" {
+		select {
+		case msg := <-updates:
+			event = msg.Payload
+		case <-time.After(time.Second):
+			t.Fatal("no PROGRESS event published")
+		}
+	}
+	assert.Equal(t, jobID, event.JobID)
+	assert.Equal(t, 25.0, event.Progress.Percent)
+	assert.Equal(t, "train", event.Progress.Phase)
+
+	// Verify: The progress is reported with the job and survives updates
+	job, found := adapter.Job(jobID)
+	assert.True(t, found)
+	assert.Equal(t, 25.0, job.Progress.Percent)
+
+	job.Status = "COMPLETED"
+	job.Progress = nil
+	adapter.UpdateJob(job)
+	jobs := adapter.ListJobs()
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, "train", jobs[0].Progress.Phase)
+}
@@ -5,61 +5,93 @@ import (
 )
 
 // SimpleLogBuffer replaces the over-engineered buffer system
-// Just stores log chunks for jobs without unnecessary abstractions
+// Just stores log chunks for jobs without unnecessary abstractions.
+//
+// When created with a byte limit the buffer behaves as a ring: once the limit
+// is exceeded the oldest chunks are evicted, so it always holds the most recent
+// tail of a job's output. Persist keeps the full history; the ring only has to
+// serve tail and follow requests without a round trip to it.
 type SimpleLogBuffer struct {
-	jobID string
-	data  [][]byte
-	mutex sync.RWMutex
+	jobID    string
+	data     [][]byte
+	bytes    int
+	maxBytes int // 0 means unbounded
+	dropped  int // chunks evicted from the front since the job started
+	mutex    sync.RWMutex
 }
 
-// NewSimpleLogBuffer creates a basic log buffer for a job
+// NewSimpleLogBuffer creates a basic, unbounded log buffer for a job
 func NewSimpleLogBuffer(jobID string) *SimpleLogBuffer {
+	return NewRingLogBuffer(jobID, 0)
+}
+
+// NewRingLogBuffer creates a log buffer that keeps at most maxBytes of the most
+// recent output. A maxBytes of 0 disables eviction.
+func NewRingLogBuffer(jobID string, maxBytes int) *SimpleLogBuffer {
 	return &SimpleLogBuffer{
-		jobID: jobID,
-		data:  make([][]byte, 0),
+		jobID:    jobID,
+		data:     make([][]byte, 0),
+		maxBytes: maxBytes,
 	}
 }
 
-// Write appends log data to the buffer
+// Write appends log data to the buffer, evicting the oldest chunks when the
+// byte limit is exceeded. A single chunk larger than the limit keeps only its tail.
 func (b *SimpleLogBuffer) Write(data []byte) error {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
+	if b.maxBytes > 0 && len(data) > b.maxBytes {
+		data = data[len(data)-b.maxBytes:]
+	}
+
 	// Make a copy to avoid data races
 	chunk := make([]byte, len(data))
 	copy(chunk, data)
 	b.data = append(b.data, chunk)
+	b.bytes += len(chunk)
+
+	if b.maxBytes > 0 {
+		evict := 0
+		for b.bytes > b.maxBytes {
+			b.bytes -= len(b.data[evict])
+			b.data[evict] = nil
+			evict++
+		}
+		if evict > 0 {
+			b.data = b.data[evict:]
+			b.dropped += evict
+		}
+	}
 
 	return nil
 }
 
 // ReadAll returns all buffered data
 func (b *SimpleLogBuffer) ReadAll() [][]byte {
-	b.mutex.RLock()
-	defer b.mutex.RUnlock()
-
-	// Return copy to prevent external modification
-	result := make([][]byte, len(b.data))
-	for i, chunk := range b.data {
-		result[i] = make([]byte, len(chunk))
-		copy(result[i], chunk)
-	}
-	return result
+	return b.ReadAfterSkip(0)
 }
 
 // ReadAfterSkip returns buffered data starting after skipCount items
-// This is used to avoid duplicates when persist has already sent the first N items
+// This is used to avoid duplicates when persist has already sent the first N items.
+// skipCount counts chunks from the start of the job's output, including chunks
+// that have since been evicted from the ring.
 func (b *SimpleLogBuffer) ReadAfterSkip(skipCount int) [][]byte {
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
 
+	start := skipCount - b.dropped
+	if start < 0 {
+		start = 0
+	}
+
 	// If skip count is greater than or equal to data length, return empty
-	if skipCount >= len(b.data) {
+	if start >= len(b.data) {
 		return [][]byte{}
 	}
 
 	// Return items after skipCount
-	remaining := b.data[skipCount:]
+	remaining := b.data[start:]
 	result := make([][]byte, len(remaining))
 	for i, chunk := range remaining {
 		result[i] = make([]byte, len(chunk))
@@ -68,31 +100,57 @@ func (b *SimpleLogBuffer) ReadAfterSkip(skipCount int) [][]byte {
 	return result
 }
 
-// Size returns the number of log chunks
+// Size returns the number of log chunks currently held
 func (b *SimpleLogBuffer) Size() int {
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
 	return len(b.data)
 }
 
-// Clear removes all buffered data
+// Bytes returns the number of log bytes currently held
+func (b *SimpleLogBuffer) Bytes() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.bytes
+}
+
+// Complete reports whether the buffer still holds every chunk written to it,
+// in which case it can serve a job's full output without consulting persist.
+func (b *SimpleLogBuffer) Complete() bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.dropped == 0
+}
+
+// Clear removes all buffered data. Cleared chunks count as evicted so skip
+// offsets from persist keep lining up with later writes.
 func (b *SimpleLogBuffer) Clear() {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
+	b.dropped += len(b.data)
 	b.data = b.data[:0] // Keep capacity but reset length
+	b.bytes = 0
 }
 
 // SimpleLogManager manages log buffers for all jobs
 // Replaces complex buffer factory and manager abstractions
 type SimpleLogManager struct {
-	buffers map[string]*SimpleLogBuffer
-	mutex   sync.RWMutex
+	buffers      map[string]*SimpleLogBuffer
+	maxTailBytes int
+	mutex        sync.RWMutex
 }
 
-// NewSimpleLogManager creates a simple log manager
+// NewSimpleLogManager creates a simple log manager with unbounded buffers
 func NewSimpleLogManager() *SimpleLogManager {
+	return NewRingLogManager(0)
+}
+
+// NewRingLogManager creates a log manager whose buffers keep at most
+// maxTailBytes of recent output per job. A maxTailBytes of 0 disables eviction.
+func NewRingLogManager(maxTailBytes int) *SimpleLogManager {
 	return &SimpleLogManager{
-		buffers: make(map[string]*SimpleLogBuffer),
+		buffers:      make(map[string]*SimpleLogBuffer),
+		maxTailBytes: maxTailBytes,
 	}
 }
 
@@ -103,7 +161,7 @@ func (m *SimpleLogManager) GetBuffer(jobID string) *SimpleLogBuffer {
 
 	buffer, exists := m.buffers[jobID]
 	if !exists {
-		buffer = NewSimpleLogBuffer(jobID)
+		buffer = NewRingLogBuffer(jobID, m.maxTailBytes)
 		m.buffers[jobID] = buffer
 	}
 	return buffer
@@ -140,14 +198,17 @@ func (m *SimpleLogManager) Stats() SimpleLogStats {
 
 	totalBuffers := len(m.buffers)
 	totalChunks := 0
+	totalBytes := 0
 
 	for _, buffer := range m.buffers {
 		totalChunks += buffer.Size()
+		totalBytes += buffer.Bytes()
 	}
 
 	return SimpleLogStats{
 		ActiveBuffers: totalBuffers,
 		TotalChunks:   totalChunks,
+		TotalBytes:    totalBytes,
 	}
 }
 
@@ -155,4 +216,5 @@ func (m *SimpleLogManager) Stats() SimpleLogStats {
 type SimpleLogStats struct {
 	ActiveBuffers int
 	TotalChunks   int
+	TotalBytes    int
 }
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRingLogBuffer_EvictsOldestChunks verifies the buffer keeps only the most recent bytes
+func TestRingLogBuffer_EvictsOldestChunks(t *testing.T) {
+	buffer := NewRingLogBuffer("job-1", 10)
+
+	assert.NoError(t, buffer.Write([]byte("aaaa")))
+	assert.NoError(t, buffer.Write([]byte("bbbb")))
+	assert.True(t, buffer.Complete(), "Buffer should be complete before any eviction")
+
+	assert.NoError(t, buffer.Write([]byte("cccc")))
+
+	assert.Equal(t, [][]byte{[]byte("bbbb"), []byte("cccc")}, buffer.ReadAll())
+	assert.Equal(t, 8, buffer.Bytes())
+	assert.False(t, buffer.Complete(), "Buffer should report eviction")
+}
+
+// TestRingLogBuffer_OversizedChunk verifies a chunk larger than the limit keeps its tail
+func TestRingLogBuffer_OversizedChunk(t *testing.T) {
+	buffer := NewRingLogBuffer("job-1", 4)
+
+	assert.NoError(t, buffer.Write([]byte("ab")))
+	assert.NoError(t, buffer.Write([]byte("0123456789")))
+
+	assert.Equal(t, [][]byte{[]byte("6789")}, buffer.ReadAll())
+	assert.Equal(t, 4, buffer.Bytes())
+}
+
+// TestRingLogBuffer_ReadAfterSkipCountsEvictedChunks verifies skip offsets are absolute
+func TestRingLogBuffer_ReadAfterSkipCountsEvictedChunks(t *testing.T) {
+	buffer := NewRingLogBuffer("job-1", 6)
+	for _, chunk := range []string{"c0", "c1", "c2", "c3", "c4"} {
+		assert.NoError(t, buffer.Write([]byte(chunk)))
+	}
+
+	// c0 and c1 were evicted; the ring holds c2, c3, c4
+	assert.Equal(t, [][]byte{[]byte("c2"), []byte("c3"), []byte("c4")}, buffer.ReadAfterSkip(1))
+	assert.Equal(t, [][]byte{[]byte("c4")}, buffer.ReadAfterSkip(4))
+	assert.Empty(t, buffer.ReadAfterSkip(5))
+}
+
+// TestSimpleLogBuffer_Unbounded verifies a zero limit never evicts
+func TestSimpleLogBuffer_Unbounded(t *testing.T) {
+	buffer := NewSimpleLogBuffer("job-1")
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, buffer.Write([]byte("0123456789")))
+	}
+
+	assert.Equal(t, 1000, buffer.Size())
+	assert.True(t, buffer.Complete())
+}
+
+// TestRingLogManager_AppliesLimit verifies buffers created by the manager are bounded
+func TestRingLogManager_AppliesLimit(t *testing.T) {
+	manager := NewRingLogManager(4)
+	buffer := manager.GetBuffer("job-1")
+
+	assert.NoError(t, buffer.Write([]byte("abc")))
+	assert.NoError(t, buffer.Write([]byte("def")))
+
+	stats := manager.Stats()
+	assert.Equal(t, 1, stats.ActiveBuffers)
+	assert.Equal(t, 1, stats.TotalChunks)
+	assert.Equal(t, 3, stats.TotalBytes)
+}
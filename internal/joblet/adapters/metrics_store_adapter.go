@@ -149,6 +149,19 @@ func (a *MetricsStoreAdapter) StopCollector(jobID string) error {
 	return nil
 }
 
+// NetworkSummary returns the network activity of a job with a running
+// collector, nil when there is none or the job shares the host's network
+func (a *MetricsStoreAdapter) NetworkSummary(jobID string) *domain.NetworkSummary {
+	a.collectorsMutex.RLock()
+	defer a.collectorsMutex.RUnlock()
+
+	collector, exists := a.collectors[jobID]
+	if !exists {
+		return nil
+	}
+	return collector.NetworkSummary()
+}
+
 // PublishMetrics implements the MetricsPublisher interface
 // This is called by the Collector to publish metrics samples
 // When persist is enabled: Buffers data + publishes to pubsub (for gap prevention, IPC, and live streaming)
@@ -22,11 +22,17 @@ type JobStorer interface {
 	Job(id string) (*domain.Job, bool)
 	JobByPrefix(prefix string) (*domain.Job, bool)
 	ResolveJobUUID(idOrPrefix string) (string, error)
+	// MatchJobUUIDs lists up to limit job UUIDs starting with prefix, sorted,
+	// and counts every match
+	MatchJobUUIDs(prefix string, limit int) ([]string, int)
 	ListJobs() []*domain.Job
 	WriteToBuffer(jobID string, chunk []byte)
 	Output(id string) ([]byte, bool, error)
 	SendUpdatesToClient(ctx context.Context, id string, stream interfaces.DomainStreamer) error
 	SendUpdatesToClientWithSkip(ctx context.Context, id string, stream interfaces.DomainStreamer, skipCount int) error
+	// HasCompleteLogTail reports whether the in-memory log tail still holds all of a
+	// job's output, so log requests can be served without querying persist
+	HasCompleteLogTail(id string) bool
 
 	// Taking care of job logs
 	DeleteJobLogs(jobID string) error
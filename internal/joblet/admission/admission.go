@@ -0,0 +1,166 @@
+// Package admission decides which waiting job gets a running slot once the
+// node is saturated. The joblet core keeps the jobs that cannot start yet in
+// a Scheduler and asks it for the next one each time a slot frees up; which
+// Scheduler is used is picked by name with scheduling.policy.
+//
+// Organizations can compile in their own policy without touching the core:
+// implement Scheduler in a package of its own and register it from an init
+// function,
+//
+//	func init() {
+//		admission.Register("gpu-first", func(cfg *config.Config) (admission.Scheduler, error) {
+//			return newGPUFirst(cfg.Scheduling.Options), nil
+//		})
+//	}
+//
+// then import that package for its side effects from cmd/joblet and set
+// scheduling.policy to "gpu-first". Settings of such a policy go in
+// scheduling.options.
+package admission
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Built-in policies
+const (
+	FIFO       = "fifo"
+	Priority   = "priority"
+	FairShare  = "fair_share"
+	BinPacking = "bin_packing"
+)
+
+// Scheduler holds the jobs waiting for a running slot and picks the one to
+// start next. Implementations must be safe for concurrent use.
+type Scheduler interface {
+	// Add puts a job in line
+	Add(job *domain.Job)
+	// Remove takes a job out of line, reporting whether it was waiting
+	Remove(jobID string) bool
+	// Size returns the number of waiting jobs
+	Size() int
+	// Next removes and returns the job to start next on node, nil when no
+	// waiting job should start now. It must not modify node: the core
+	// assigns the returned job to it before asking again.
+	Next(node *Node) *domain.Job
+}
+
+// Charger is implemented by schedulers that account for the running time of
+// each tenant's jobs. The core charges them every second.
+type Charger interface {
+	Charge(tenant string, used time.Duration, now time.Time)
+}
+
+// Resources is an amount of CPU and memory
+type Resources struct {
+	CPU    int64 // Percent of a core, 100 per core
+	Memory int64 // Bytes
+}
+
+// Demand returns what a job's limits reserve. A job without a limit
+// reserves nothing of that resource.
+func Demand(job *domain.Job) Resources {
+	return Resources{
+		CPU:    int64(job.Limits.CPU.Value()),
+		Memory: job.Limits.Memory.Bytes(),
+	}
+}
+
+// Node is what a scheduler sees of the node when picking the next job
+type Node struct {
+	Now time.Time
+	// Running holds the number of jobs holding a running slot per tenant
+	Running map[string]int
+	// Capacity is the node's CPU and memory, zero when unknown
+	Capacity Resources
+	// Reserved is what the limits of the running jobs reserve
+	Reserved Resources
+}
+
+// Assign records that job takes a running slot on the node
+func (n *Node) Assign(job *domain.Job) {
+	if n.Running == nil {
+		n.Running = make(map[string]int)
+	}
+	n.Running[job.Tenant]++
+	demand := Demand(job)
+	n.Reserved.CPU += demand.CPU
+	n.Reserved.Memory += demand.Memory
+}
+
+// Free returns the capacity the running jobs have not reserved. A resource
+// of unknown capacity is reported as -1.
+func (n *Node) Free() Resources {
+	free := Resources{CPU: -1, Memory: -1}
+	if n.Capacity.CPU > 0 {
+		free.CPU = max(n.Capacity.CPU-n.Reserved.CPU, 0)
+	}
+	if n.Capacity.Memory > 0 {
+		free.Memory = max(n.Capacity.Memory-n.Reserved.Memory, 0)
+	}
+	return free
+}
+
+// Fits reports whether job's limits fit in what is free on the node.
+// Resources of unknown capacity always fit.
+func (n *Node) Fits(job *domain.Job) bool {
+	demand, free := Demand(job), n.Free()
+	return (free.CPU < 0 || demand.CPU <= free.CPU) && (free.Memory < 0 || demand.Memory <= free.Memory)
+}
+
+// Factory creates a Scheduler from the server configuration
+type Factory func(cfg *config.Config) (Scheduler, error)
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Factory{
+		FIFO:       func(*config.Config) (Scheduler, error) { return NewFIFO(), nil },
+		Priority:   newPriorityFromConfig,
+		FairShare:  newFairShareFromConfig,
+		BinPacking: func(*config.Config) (Scheduler, error) { return NewBinPacking(), nil },
+	}
+)
+
+// Register makes a policy available to scheduling.policy under name. It
+// panics when name is empty or already registered, as both are programming
+// errors; call it from an init function.
+func Register(name string, factory Factory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if name == "" || factory == nil {
+		panic("admission: Register needs a name and a factory")
+	}
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("admission: policy %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// Policies returns the names of the registered policies, sorted
+func Policies() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New creates the Scheduler registered under policy
+func New(policy string, cfg *config.Config) (Scheduler, error) {
+	registryMutex.RLock()
+	factory, exists := registry[policy]
+	registryMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown scheduling policy %q (available: %v)", policy, Policies())
+	}
+	return factory(cfg)
+}
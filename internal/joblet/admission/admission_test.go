@@ -0,0 +1,183 @@
+package admission
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+func queuedJob(id, tenant string) *domain.Job {
+	return &domain.Job{Uuid: id, Tenant: tenant, Status: domain.StatusQueued}
+}
+
+// drain assigns jobs from s to node until it returns nil
+func drain(s Scheduler, node *Node) []string {
+	var order []string
+	for job := s.Next(node); job != nil; job = s.Next(node) {
+		node.Assign(job)
+		order = append(order, job.Uuid)
+	}
+	return order
+}
+
+func TestNew_BuiltinPolicies(t *testing.T) {
+	cfg := config.DefaultConfig
+	for _, policy := range []string{FIFO, Priority, FairShare, BinPacking} {
+		s, err := New(policy, &cfg)
+		if err != nil || s == nil {
+			t.Errorf("New(%q) = %v, %v", policy, s, err)
+		}
+	}
+	if _, err := New("round-robin", &cfg); err == nil || !strings.Contains(err.Error(), "unknown scheduling policy") {
+		t.Errorf("New(round-robin) error = %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var got map[string]string
+	Register("test-custom", func(cfg *config.Config) (Scheduler, error) {
+		got = cfg.Scheduling.Options
+		return NewFIFO(), nil
+	})
+	if !slices.Contains(Policies(), "test-custom") {
+		t.Fatalf("Policies() = %v, missing test-custom", Policies())
+	}
+
+	cfg := config.DefaultConfig
+	cfg.Scheduling.Options = map[string]string{"gpu_weight": "4"}
+	if _, err := New("test-custom", &cfg); err != nil {
+		t.Fatalf("New(test-custom) error = %v", err)
+	}
+	if got["gpu_weight"] != "4" {
+		t.Errorf("factory got options %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a policy twice did not panic")
+		}
+	}()
+	Register(FIFO, func(*config.Config) (Scheduler, error) { return NewFIFO(), nil })
+}
+
+func TestFIFOScheduler(t *testing.T) {
+	s := NewFIFO()
+	s.Add(queuedJob("a", "web"))
+	s.Add(queuedJob("b", "ml"))
+	s.Add(queuedJob("c", "web"))
+	if !s.Remove("b") || s.Remove("b") {
+		t.Fatal("Remove should report a waiting job once")
+	}
+
+	order := drain(s, &Node{Now: time.Now()})
+	if !slices.Equal(order, []string{"a", "c"}) {
+		t.Errorf("order = %v, want [a c]", order)
+	}
+	if s.Size() != 0 {
+		t.Errorf("Size() = %d after draining", s.Size())
+	}
+}
+
+func TestPriorityScheduler(t *testing.T) {
+	s := NewPriority("")
+	labeled := func(id, priority string) *domain.Job {
+		job := queuedJob(id, "")
+		job.Labels = map[string]string{DefaultPriorityLabel: priority}
+		return job
+	}
+	s.Add(queuedJob("unlabeled", ""))
+	s.Add(labeled("low", "-5"))
+	s.Add(labeled("high-1", "10"))
+	s.Add(labeled("bogus", "urgent"))
+	s.Add(labeled("high-2", "10"))
+
+	// Equal priorities start in arrival order; bogus counts as 0
+	want := []string{"high-1", "high-2", "unlabeled", "bogus", "low"}
+	if order := drain(s, &Node{Now: time.Now()}); !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestFairShareScheduler_LeavesNodeAlone(t *testing.T) {
+	cfg := config.DefaultConfig
+	s, err := New(FairShare, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(queuedJob("web-1", "web"))
+	s.Add(queuedJob("web-2", "web"))
+	s.Add(queuedJob("ml-1", "ml"))
+
+	// ml already runs a job, so web goes first
+	node := &Node{Now: time.Now(), Running: map[string]int{"ml": 1}}
+	if job := s.Next(node); job == nil || job.Uuid != "web-1" {
+		t.Fatalf("Next = %v, want web-1", job)
+	}
+	if node.Running["web"] != 0 {
+		t.Errorf("Next modified the node: %v", node.Running)
+	}
+	if _, ok := s.(Charger); !ok {
+		t.Error("fair-share scheduler is not a Charger")
+	}
+}
+
+func TestBinPackingScheduler(t *testing.T) {
+	const gb = int64(1) << 30
+	sized := func(id string, memoryMB int32) *domain.Job {
+		job := queuedJob(id, "")
+		job.Limits = *domain.NewResourceLimitsFromParams(0, "", memoryMB, 0)
+		return job
+	}
+
+	s := NewBinPacking()
+	s.Add(sized("small", 512))
+	s.Add(sized("huge", 6*1024))
+	s.Add(sized("medium", 2*1024))
+	s.Add(sized("unlimited", 0))
+
+	// 4GB free: medium then small fill it, unlimited reserves nothing, huge
+	// waits for the running jobs to end
+	node := &Node{
+		Now:      time.Now(),
+		Running:  map[string]int{"": 1},
+		Capacity: Resources{CPU: 400, Memory: 8 * gb},
+		Reserved: Resources{Memory: 4 * gb},
+	}
+	want := []string{"medium", "small", "unlimited"}
+	if order := drain(s, node); !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	// On an idle node the oversized job starts anyway
+	idle := &Node{Now: time.Now(), Capacity: Resources{CPU: 400, Memory: 4 * gb}}
+	if job := s.Next(idle); job == nil || job.Uuid != "huge" {
+		t.Errorf("Next on idle node = %v, want huge", job)
+	}
+}
+
+func TestNode_Fits(t *testing.T) {
+	job := queuedJob("a", "")
+	job.Limits = *domain.NewResourceLimitsFromParams(200, "", 1024, 0)
+
+	tests := []struct {
+		name string
+		node Node
+		want bool
+	}{
+		{"unknown capacity", Node{}, true},
+		{"room left", Node{Capacity: Resources{CPU: 400, Memory: 2 << 30}, Reserved: Resources{CPU: 100}}, true},
+		{"cpu taken", Node{Capacity: Resources{CPU: 400, Memory: 2 << 30}, Reserved: Resources{CPU: 300}}, false},
+		{"memory taken", Node{Capacity: Resources{CPU: 400, Memory: 2 << 30}, Reserved: Resources{Memory: 3 << 29}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.Fits(job); got != tt.want {
+				t.Errorf("Fits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
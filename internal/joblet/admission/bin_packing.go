@@ -0,0 +1,45 @@
+package admission
+
+import (
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// BinPackingScheduler starts the waiting job that fills the node's free
+// memory best, then its free CPU: the largest job that still fits, the
+// oldest among equals. Jobs that do not fit wait for running jobs to end,
+// except on an idle node where the oldest job starts whatever its size so
+// that an oversized job cannot wait forever.
+type BinPackingScheduler struct {
+	line
+}
+
+// NewBinPacking creates an empty bin-packing scheduler
+func NewBinPacking() *BinPackingScheduler {
+	return &BinPackingScheduler{}
+}
+
+// Next returns the largest waiting job that fits on node
+func (s *BinPackingScheduler) Next(node *Node) *domain.Job {
+	idle := node.Reserved == (Resources{})
+	for _, count := range node.Running {
+		idle = idle && count == 0
+	}
+	return s.take(func(waiting []*domain.Job) int {
+		best := -1
+		var bestDemand Resources
+		for i, job := range waiting {
+			if !node.Fits(job) {
+				continue
+			}
+			demand := Demand(job)
+			if best < 0 || demand.Memory > bestDemand.Memory ||
+				(demand.Memory == bestDemand.Memory && demand.CPU > bestDemand.CPU) {
+				best, bestDemand = i, demand
+			}
+		}
+		if best < 0 && idle && len(waiting) > 0 {
+			return 0
+		}
+		return best
+	})
+}
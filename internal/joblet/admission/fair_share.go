@@ -0,0 +1,31 @@
+package admission
+
+import (
+	"maps"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/scheduler"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// FairShareScheduler starts the oldest job of the tenant with the least
+// recent usage per unit of weight, see scheduler.FairShareQueue. It is a
+// Charger: usage is the running time charged to each tenant.
+type FairShareScheduler struct {
+	*scheduler.FairShareQueue
+}
+
+// NewFairShare creates an empty fair-share scheduler over queue
+func NewFairShare(queue *scheduler.FairShareQueue) *FairShareScheduler {
+	return &FairShareScheduler{FairShareQueue: queue}
+}
+
+func newFairShareFromConfig(cfg *config.Config) (Scheduler, error) {
+	return NewFairShare(scheduler.NewFairShareQueue(cfg.FairShare.Window, cfg.FairShare.Slice, cfg.TenantWeights())), nil
+}
+
+// Next returns the oldest job of the tenant furthest behind its share
+func (s *FairShareScheduler) Next(node *Node) *domain.Job {
+	// The queue counts the job it returns as running; node is the core's
+	return s.FairShareQueue.Next(node.Now, maps.Clone(node.Running))
+}
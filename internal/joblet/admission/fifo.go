@@ -0,0 +1,74 @@
+package admission
+
+import (
+	"sync"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// line holds waiting jobs in arrival order for the list based schedulers
+type line struct {
+	mutex   sync.Mutex
+	waiting []*domain.Job
+}
+
+// Add appends a job to the line
+func (l *line) Add(job *domain.Job) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.waiting = append(l.waiting, job)
+}
+
+// Remove takes a job out of the line, reporting whether it was waiting
+func (l *line) Remove(jobID string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for i, job := range l.waiting {
+		if job.Uuid == jobID {
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of waiting jobs
+func (l *line) Size() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.waiting)
+}
+
+// take removes and returns the job picked by pick, nil when pick returns
+// -1. pick gets the waiting jobs in arrival order.
+func (l *line) take(pick func(waiting []*domain.Job) int) *domain.Job {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	i := pick(l.waiting)
+	if i < 0 || i >= len(l.waiting) {
+		return nil
+	}
+	job := l.waiting[i]
+	l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+	return job
+}
+
+// FIFOScheduler starts waiting jobs in the order they arrived
+type FIFOScheduler struct {
+	line
+}
+
+// NewFIFO creates an empty first in, first out scheduler
+func NewFIFO() *FIFOScheduler {
+	return &FIFOScheduler{}
+}
+
+// Next returns the job that has waited longest
+func (s *FIFOScheduler) Next(*Node) *domain.Job {
+	return s.take(func(waiting []*domain.Job) int {
+		if len(waiting) == 0 {
+			return -1
+		}
+		return 0
+	})
+}
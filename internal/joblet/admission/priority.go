@@ -0,0 +1,54 @@
+package admission
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// DefaultPriorityLabel is the job label holding its priority
+const DefaultPriorityLabel = "priority"
+
+// PriorityScheduler starts the waiting job with the highest priority first,
+// in arrival order among equals. A job's priority is the integer in one of
+// its labels; jobs without it, or with something else in it, have priority 0.
+type PriorityScheduler struct {
+	line
+	label string
+}
+
+// NewPriority creates an empty scheduler reading priorities from label
+func NewPriority(label string) *PriorityScheduler {
+	if label == "" {
+		label = DefaultPriorityLabel
+	}
+	return &PriorityScheduler{label: label}
+}
+
+func newPriorityFromConfig(cfg *config.Config) (Scheduler, error) {
+	return NewPriority(cfg.Scheduling.PriorityLabel), nil
+}
+
+// Next returns the oldest job of the highest priority
+func (s *PriorityScheduler) Next(*Node) *domain.Job {
+	return s.take(func(waiting []*domain.Job) int {
+		best, bestPriority := -1, 0
+		for i, job := range waiting {
+			if p := s.priority(job); best < 0 || p > bestPriority {
+				best, bestPriority = i, p
+			}
+		}
+		return best
+	})
+}
+
+// priority returns the priority in a job's label
+func (s *PriorityScheduler) priority(job *domain.Job) int {
+	p, err := strconv.Atoi(strings.TrimSpace(job.Labels[s.label]))
+	if err != nil {
+		return 0
+	}
+	return p
+}
@@ -0,0 +1,358 @@
+// Package anomaly flags named jobs that run much longer than they usually
+// do, so a nightly job taking four times its usual hour does not go
+// unnoticed.
+//
+// The usual duration of a name is the distribution of its latest completed
+// runs, read from the job store and from the job records archived to
+// persist. The detector archives the records of the runs that complete
+// while it runs, so the distributions outlive the job store.
+package anomaly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/retention"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// minDeviationShare is the smallest standard deviation used, as a share of
+// the mean, so names whose runs all take about as long are not flagged for
+// a few seconds more
+const minDeviationShare = 0.1
+
+// Store is the part of the job store the detector reads
+type Store interface {
+	ListJobs() []*domain.Job
+}
+
+// Anomaly is a run that exceeded the usual duration of its name
+type Anomaly struct {
+	JobID        string    `json:"jobId"`
+	Name         string    `json:"name"`
+	WorkflowUuid string    `json:"workflowUuid,omitempty"`
+	Status       string    `json:"status"` // Status when the run was flagged
+	Elapsed      float64   `json:"elapsedSeconds"`
+	Mean         float64   `json:"meanSeconds"`
+	StdDev       float64   `json:"stddevSeconds"`
+	Sigmas       float64   `json:"sigmas"`  // Standard deviations above the mean
+	Samples      int       `json:"samples"` // Completed runs the distribution was built from
+	DetectedAt   time.Time `json:"detectedAt"`
+	NodeID       string    `json:"nodeId,omitempty"`
+}
+
+// Stats describes the durations of a name's completed runs
+type Stats struct {
+	Mean    time.Duration
+	StdDev  time.Duration
+	Samples int
+}
+
+// NewStats returns the mean and sample standard deviation of durations
+func NewStats(durations []time.Duration) Stats {
+	stats := Stats{Samples: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+	var sum float64
+	for _, d := range durations {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(durations))
+	stats.Mean = time.Duration(mean)
+	if len(durations) < 2 {
+		return stats
+	}
+	var squares float64
+	for _, d := range durations {
+		squares += (float64(d) - mean) * (float64(d) - mean)
+	}
+	stats.StdDev = time.Duration(math.Sqrt(squares / float64(len(durations)-1)))
+	return stats
+}
+
+// Limit returns the duration above which a run is anomalous
+func (s Stats) Limit(threshold float64) time.Duration {
+	deviation := max(float64(s.StdDev), float64(s.Mean)*minDeviationShare)
+	return s.Mean + time.Duration(threshold*deviation)
+}
+
+// Detector checks running and just finished named jobs against the usual
+// duration of their name
+type Detector struct {
+	cfg     config.DurationAnomalyConfig
+	store   Store
+	persist persistpb.PersistServiceClient // nil: distributions come from the job store only
+	client  *http.Client
+	nodeID  string
+	logger  *logger.Logger
+	started time.Time
+
+	mu      sync.RWMutex
+	flagged map[string]Anomaly // By job ID
+	settled map[string]bool    // Finished runs already checked
+}
+
+// NewDetector creates a detector. persist may be nil.
+func NewDetector(cfg config.DurationAnomalyConfig, store Store, persist persistpb.PersistServiceClient, nodeID string) *Detector {
+	return &Detector{
+		cfg:     cfg,
+		store:   store,
+		persist: persist,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		nodeID:  nodeID,
+		logger:  logger.WithField("component", "duration-anomaly"),
+		started: time.Now(),
+		flagged: make(map[string]Anomaly),
+		settled: make(map[string]bool),
+	}
+}
+
+// Run checks the jobs every interval until ctx is done
+func (d *Detector) Run(ctx context.Context) {
+	d.logger.Info("duration anomaly detection enabled", "threshold", d.cfg.Threshold, "samples", d.cfg.Samples,
+		"minSamples", d.cfg.MinSamples, "interval", d.cfg.Interval, "webhook", d.cfg.WebhookURL != "")
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.Check(ctx, now)
+		}
+	}
+}
+
+// Anomaly returns the anomaly flagged for a job, if any
+func (d *Detector) Anomaly(jobID string) (Anomaly, bool) {
+	if d == nil {
+		return Anomaly{}, false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	a, exists := d.flagged[jobID]
+	return a, exists
+}
+
+// Check flags the named jobs running longer than usual at now, and the runs
+// that finished since the last check having taken longer than usual. Each
+// run is flagged once; the anomalies found are logged, sent to the webhook
+// and returned.
+func (d *Detector) Check(ctx context.Context, now time.Time) []Anomaly {
+	jobs := d.store.ListJobs()
+
+	d.mu.Lock()
+	present := make(map[string]bool, len(jobs))
+	var candidates []*domain.Job
+	var finished []*domain.Job
+	for _, job := range jobs {
+		present[job.Uuid] = true
+		if job.Name == "" || d.settled[job.Uuid] {
+			continue
+		}
+		if _, flagged := d.flagged[job.Uuid]; flagged && job.IsRunning() {
+			continue
+		}
+		switch {
+		case job.IsRunning():
+			candidates = append(candidates, job)
+		case job.IsCompleted() && job.EndTime != nil:
+			d.settled[job.Uuid] = true
+			// Runs that ended before the detector started were checked by
+			// an earlier server, or never will be
+			if job.EndTime.After(d.started) {
+				finished = append(finished, job)
+				if _, flagged := d.flagged[job.Uuid]; !flagged {
+					candidates = append(candidates, job)
+				}
+			}
+		}
+	}
+	// Forget the jobs removed from the store
+	for jobID := range d.settled {
+		if !present[jobID] {
+			delete(d.settled, jobID)
+		}
+	}
+	for jobID := range d.flagged {
+		if !present[jobID] {
+			delete(d.flagged, jobID)
+		}
+	}
+	d.mu.Unlock()
+
+	if d.persist != nil {
+		for _, job := range finished {
+			if job.Status != domain.StatusCompleted {
+				continue
+			}
+			if err := retention.Archive(ctx, d.persist, job, now); err != nil {
+				d.logger.Debug("failed to archive completed run", "jobID", job.Uuid, "error", err)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	history := d.history(ctx, jobs, candidates)
+	var found []Anomaly
+	for _, job := range candidates {
+		stats := NewStats(history.durations(job.Name, job.Uuid, d.cfg.Samples))
+		if stats.Samples < d.cfg.MinSamples {
+			continue
+		}
+		end := now
+		if job.EndTime != nil {
+			end = *job.EndTime
+		}
+		elapsed := end.Sub(job.StartTime)
+		if elapsed <= stats.Limit(d.cfg.Threshold) {
+			continue
+		}
+
+		a := Anomaly{
+			JobID:        job.Uuid,
+			Name:         job.Name,
+			WorkflowUuid: job.WorkflowUuid,
+			Status:       string(job.Status),
+			Elapsed:      elapsed.Seconds(),
+			Mean:         stats.Mean.Seconds(),
+			StdDev:       stats.StdDev.Seconds(),
+			Samples:      stats.Samples,
+			DetectedAt:   now,
+			NodeID:       d.nodeID,
+		}
+		if stats.StdDev > 0 {
+			a.Sigmas = float64(elapsed-stats.Mean) / float64(stats.StdDev)
+		}
+		d.mu.Lock()
+		d.flagged[job.Uuid] = a
+		d.mu.Unlock()
+		found = append(found, a)
+
+		d.logger.Warn("job is taking much longer than usual", "jobID", a.JobID, "name", a.Name, "status", a.Status,
+			"elapsed", elapsed.Round(time.Second), "mean", stats.Mean.Round(time.Second),
+			"stddev", stats.StdDev.Round(time.Second), "samples", stats.Samples)
+		if d.cfg.WebhookURL != "" {
+			if err := d.notify(ctx, a); err != nil {
+				d.logger.Warn("failed to send duration anomaly notification", "jobID", a.JobID, "error", err)
+			}
+		}
+	}
+	return found
+}
+
+// runHistory holds completed runs by name
+type runHistory map[string][]completedRun
+
+type completedRun struct {
+	jobID string
+	end   time.Time
+	took  time.Duration
+}
+
+// durations returns the durations of the newest samples runs of name,
+// leaving out the run being checked
+func (h runHistory) durations(name, exclude string, samples int) []time.Duration {
+	var durations []time.Duration
+	for _, run := range h[name] {
+		if run.jobID == exclude {
+			continue
+		}
+		if len(durations) == samples {
+			break
+		}
+		durations = append(durations, run.took)
+	}
+	return durations
+}
+
+// history collects the completed runs of the candidates' names from the
+// job store and persist, newest first. Runs flagged as anomalies are left
+// out so a slow run does not widen the distribution it is checked against.
+func (d *Detector) history(ctx context.Context, jobs, candidates []*domain.Job) runHistory {
+	d.mu.RLock()
+	seen := make(map[string]bool, len(d.flagged))
+	for jobID := range d.flagged {
+		seen[jobID] = true
+	}
+	d.mu.RUnlock()
+
+	wanted := make(map[string]bool)
+	var names []string
+	for _, job := range candidates {
+		if !wanted[job.Name] {
+			wanted[job.Name] = true
+			names = append(names, job.Name)
+		}
+	}
+
+	history := make(runHistory)
+	for _, job := range jobs {
+		if seen[job.Uuid] || !wanted[job.Name] || job.Status != domain.StatusCompleted || job.EndTime == nil {
+			continue
+		}
+		history[job.Name] = append(history[job.Name], completedRun{jobID: job.Uuid, end: *job.EndTime, took: job.EndTime.Sub(job.StartTime)})
+		seen[job.Uuid] = true
+	}
+
+	if d.persist != nil {
+		queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		// One more than needed, the run being checked may be among them
+		resp, err := d.persist.QueryJobDurations(queryCtx, &persistpb.QueryJobDurationsRequest{Names: names, Limit: int32(d.cfg.Samples + 1)})
+		cancel()
+		if err != nil {
+			d.logger.Debug("duration history from persist unavailable", "error", err)
+		} else {
+			for _, run := range resp.Durations {
+				if seen[run.JobId] {
+					continue
+				}
+				history[run.Name] = append(history[run.Name], completedRun{jobID: run.JobId, end: time.Unix(0, run.EndTime), took: time.Duration(run.EndTime - run.StartTime)})
+			}
+		}
+	}
+
+	for _, runs := range history {
+		sort.SliceStable(runs, func(i, j int) bool { return runs[i].end.After(runs[j].end) })
+	}
+	return history
+}
+
+// notify POSTs the anomaly to the webhook as JSON
+func (d *Detector) notify(ctx context.Context, a Anomaly) error {
+	body, err := json.Marshal(struct {
+		Event string `json:"event"`
+		Anomaly
+	}{Event: "job.duration_anomaly", Anomaly: a})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
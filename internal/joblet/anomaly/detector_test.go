@@ -0,0 +1,140 @@
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+)
+
+type fakeStore struct{ jobs []*domain.Job }
+
+func (f *fakeStore) ListJobs() []*domain.Job { return f.jobs }
+
+type fakePersist struct {
+	persistpb.PersistServiceClient
+	durations []*persistpb.JobDuration
+	archived  []string
+}
+
+func (f *fakePersist) QueryJobDurations(_ context.Context, req *persistpb.QueryJobDurationsRequest, _ ...grpc.CallOption) (*persistpb.QueryJobDurationsResponse, error) {
+	return &persistpb.QueryJobDurationsResponse{Durations: f.durations}, nil
+}
+
+func (f *fakePersist) ArchiveJob(_ context.Context, req *persistpb.ArchiveJobRequest, _ ...grpc.CallOption) (*persistpb.ArchiveJobResponse, error) {
+	f.archived = append(f.archived, req.JobId)
+	return &persistpb.ArchiveJobResponse{Success: true}, nil
+}
+
+func completedJob(id, name string, end time.Time, took time.Duration) *domain.Job {
+	return &domain.Job{Uuid: id, Name: name, Status: domain.StatusCompleted, StartTime: end.Add(-took), EndTime: &end}
+}
+
+func TestNewStats(t *testing.T) {
+	stats := NewStats([]time.Duration{2 * time.Minute, 4 * time.Minute, 4 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute, 7 * time.Minute, 9 * time.Minute})
+	if stats.Mean != 5*time.Minute || stats.Samples != 8 {
+		t.Errorf("stats = %+v", stats)
+	}
+	// Sample standard deviation of the classic 2,4,4,4,5,5,7,9 series
+	if got := stats.StdDev.Seconds(); got < 128 || got > 129 {
+		t.Errorf("stddev = %vs, want about 128.3s", got)
+	}
+	if limit := stats.Limit(3); limit != stats.Mean+3*stats.StdDev {
+		t.Errorf("limit = %v", limit)
+	}
+
+	// Runs that all take the same time still allow some slack
+	steady := NewStats([]time.Duration{time.Hour, time.Hour, time.Hour})
+	if limit := steady.Limit(2); limit != time.Hour+12*time.Minute {
+		t.Errorf("limit of steady runs = %v", limit)
+	}
+}
+
+func TestDetector_Check(t *testing.T) {
+	now := time.Date(2026, 5, 4, 3, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	var notified []map[string]any
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		notified = append(notified, body)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	store := &fakeStore{}
+	// Three nightly runs are still in the store, two more were archived
+	for i, took := range []time.Duration{55 * time.Minute, 60 * time.Minute, 65 * time.Minute} {
+		store.jobs = append(store.jobs, completedJob(fmt.Sprintf("nightly-%d", i), "nightly", now.AddDate(0, 0, -i-1), took))
+	}
+	persist := &fakePersist{}
+	for i, took := range []time.Duration{58 * time.Minute, 62 * time.Minute} {
+		end := now.AddDate(0, 0, -i-4)
+		persist.durations = append(persist.durations, &persistpb.JobDuration{
+			JobId: fmt.Sprintf("archived-%d", i), Name: "nightly", StartTime: end.Add(-took).UnixNano(), EndTime: end.UnixNano(),
+		})
+	}
+	store.jobs = append(store.jobs,
+		&domain.Job{Uuid: "slow", Name: "nightly", Status: domain.StatusRunning, StartTime: now.Add(-4 * time.Hour)},
+		&domain.Job{Uuid: "on-time", Name: "nightly", Status: domain.StatusRunning, StartTime: now.Add(-50 * time.Minute)},
+		&domain.Job{Uuid: "new", Name: "report", Status: domain.StatusRunning, StartTime: now.Add(-10 * time.Hour)},
+		&domain.Job{Uuid: "unnamed", Status: domain.StatusRunning, StartTime: now.Add(-10 * time.Hour)},
+	)
+
+	cfg := config.DurationAnomalyConfig{Enabled: true, Threshold: 3, Samples: 30, MinSamples: 5, Interval: time.Minute, WebhookURL: webhook.URL}
+	d := NewDetector(cfg, store, persist, "node-1")
+	d.started = now.Add(-time.Hour)
+
+	found := d.Check(context.Background(), now)
+	if len(found) != 1 || found[0].JobID != "slow" || found[0].Samples != 5 || found[0].Sigmas < 30 {
+		t.Fatalf("Check() = %+v", found)
+	}
+	if a, ok := d.Anomaly("slow"); !ok || a.Status != "RUNNING" || a.Elapsed != 4*3600 {
+		t.Errorf("Anomaly(slow) = %+v, %v", a, ok)
+	}
+	if _, ok := d.Anomaly("on-time"); ok {
+		t.Error("a run within its usual duration was flagged")
+	}
+	mu.Lock()
+	if len(notified) != 1 || notified[0]["event"] != "job.duration_anomaly" || notified[0]["jobId"] != "slow" || notified[0]["nodeId"] != "node-1" {
+		t.Errorf("webhook received %v", notified)
+	}
+	mu.Unlock()
+
+	// The slow run finishes: it is archived but not flagged again, and a
+	// run that finished late between checks is flagged
+	end := now.Add(time.Minute)
+	store.jobs[3] = completedJob("slow", "nightly", end, 4*time.Hour+time.Minute)
+	store.jobs = append(store.jobs, completedJob("late", "nightly", end, 3*time.Hour))
+	found = d.Check(context.Background(), end)
+	if len(found) != 1 || found[0].JobID != "late" || found[0].Status != "COMPLETED" {
+		t.Fatalf("second Check() = %+v", found)
+	}
+	if len(persist.archived) != 2 {
+		t.Errorf("archived %v, want the two runs that completed", persist.archived)
+	}
+
+	// Runs removed from the store are forgotten
+	store.jobs = store.jobs[:3]
+	d.Check(context.Background(), end)
+	if _, ok := d.Anomaly("slow"); ok {
+		t.Error("anomaly of a removed job is still reported")
+	}
+
+	var none *Detector
+	if _, ok := none.Anomaly("slow"); ok {
+		t.Error("nil detector reported an anomaly")
+	}
+}
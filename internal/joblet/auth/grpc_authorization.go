@@ -18,6 +18,7 @@ type ClientRole string
 const (
 	AdminRole   ClientRole = "admin"
 	ViewerRole  ClientRole = "viewer"
+	NodeRole    ClientRole = "node"
 	UnknownRole ClientRole = "unknown"
 )
 
@@ -33,6 +34,9 @@ const (
 	StreamJobsOp   Operation = "stream_jobs"
 	GetJobLogsOp   Operation = "get_job_logs"
 	GetJobStatusOp Operation = "get_job_status"
+	ProfileJobOp   Operation = "profile_job"
+	FreezeJobFSOp  Operation = "freeze_job_fs"
+	BrowseJobFSOp  Operation = "browse_job_fs"
 
 	// Network operations
 	CreateNetworkOp Operation = "create_network"
@@ -40,13 +44,41 @@ const (
 	RemoveNetworkOp Operation = "remove_network"
 
 	// Volume operations
-	CreateVolumeOp Operation = "create_volume"
-	ListVolumesOp  Operation = "list_volumes"
-	RemoveVolumeOp Operation = "remove_volume"
+	CreateVolumeOp     Operation = "create_volume"
+	ListVolumesOp      Operation = "list_volumes"
+	RemoveVolumeOp     Operation = "remove_volume"
+	DownloadArtifactOp Operation = "download_artifact"
 
 	// Persist operations (historical data queries)
 	QueryLogsOp    Operation = "query_logs"
 	QueryMetricsOp Operation = "query_metrics"
+	ArchiveJobOp   Operation = "archive_job"
+
+	// Node operations
+	GetNodeCapacityOp  Operation = "get_node_capacity"
+	RegisterNodeOp     Operation = "register_node"
+	ListNodesOp        Operation = "list_nodes"
+	QueryNodeMetricsOp Operation = "query_node_metrics"
+
+	// Workflow registry operations
+	RegisterWorkflowOp        Operation = "register_workflow"
+	ListRegisteredWorkflowsOp Operation = "list_registered_workflows"
+	RemoveWorkflowOp          Operation = "remove_registered_workflow"
+
+	// Spec linting and workflow validation run nothing
+	LintSpecOp         Operation = "lint_spec"
+	ValidateWorkflowOp Operation = "validate_workflow"
+
+	// Runtime channel operations
+	ListRuntimeChannelsOp  Operation = "list_runtime_channels"
+	UpdateRuntimeChannelOp Operation = "update_runtime_channel"
+
+	// Backup operations dump and restore job and workflow records
+	ExportBackupOp Operation = "export_backup"
+	ImportBackupOp Operation = "import_backup"
+
+	// Listing the volumes and runtimes of every tenant
+	ListAllResourcesOp Operation = "list_all_resources"
 )
 
 //counterfeiter:generate . GRPCAuthorization
@@ -78,6 +110,12 @@ func (n *noOpAuthorization) Authorized(ctx context.Context, operation Operation)
 }
 
 func (s *grpcAuthorization) extractClientRole(ctx context.Context) (ClientRole, error) {
+	// A token validated by the identity provider takes precedence over the
+	// connection's certificate
+	if id := IdentityFrom(ctx); id != nil {
+		return id.Role, nil
+	}
+
 	p, ok := peer.FromContext(ctx)
 	if !ok {
 		return UnknownRole, fmt.Errorf("no peer information found")
@@ -101,6 +139,8 @@ func (s *grpcAuthorization) extractClientRole(ctx context.Context) (ClientRole,
 			return AdminRole, nil
 		case "viewer":
 			return ViewerRole, nil
+		case "node":
+			return NodeRole, nil
 		}
 	}
 
@@ -108,6 +148,37 @@ func (s *grpcAuthorization) extractClientRole(ctx context.Context) (ClientRole,
 	return UnknownRole, nil
 }
 
+// ClientIdentity returns a stable name for the caller, used to key per-client
+// state such as rate limits. It prefers the principal of a token validated by
+// the identity provider, then the client certificate's common name, then its
+// first DNS SAN, and falls back to the peer address for connections without
+// either.
+func ClientIdentity(ctx context.Context) string {
+	if id := IdentityFrom(ctx); id != nil {
+		return id.Principal
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		cert := tlsInfo.State.PeerCertificates[0]
+		if cert.Subject.CommonName != "" {
+			return cert.Subject.CommonName
+		}
+		if len(cert.DNSNames) > 0 {
+			return cert.DNSNames[0]
+		}
+	}
+
+	if p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown"
+}
+
 func (s *grpcAuthorization) isOperationAllowed(role ClientRole, operation Operation) bool {
 	switch role {
 	case AdminRole:
@@ -116,26 +187,58 @@ func (s *grpcAuthorization) isOperationAllowed(role ClientRole, operation Operat
 	case ViewerRole:
 		switch operation {
 		// Job operations - viewers can read but not modify
-		case GetJobOp, ListJobsOp, StreamJobsOp, GetJobLogsOp, GetJobStatusOp:
+		case GetJobOp, ListJobsOp, StreamJobsOp, GetJobLogsOp, GetJobStatusOp, BrowseJobFSOp:
 			return true
-		case RunJobOp, StopJobOp, DeleteJobOp:
+		case RunJobOp, StopJobOp, DeleteJobOp, ProfileJobOp, FreezeJobFSOp:
 			return false
 		// Network operations - viewers can list but not create/remove
 		case ListNetworksOp:
 			return true
 		case CreateNetworkOp, RemoveNetworkOp:
 			return false
-		// Volume operations - viewers can list but not create/remove
-		case ListVolumesOp:
+		// Volume operations - viewers can list and download but not create/remove
+		case ListVolumesOp, DownloadArtifactOp:
 			return true
 		case CreateVolumeOp, RemoveVolumeOp:
 			return false
 		// Persist operations - viewers can query historical data (read-only)
 		case QueryLogsOp, QueryMetricsOp:
 			return true
+		case ArchiveJobOp:
+			return false
+		// Node operations - viewers can see capacity, metrics history and
+		// live nodes for placement
+		case GetNodeCapacityOp, ListNodesOp, QueryNodeMetricsOp:
+			return true
+		case RegisterNodeOp:
+			return false
+		// Workflow registry - viewers can list and read but not register, start or remove
+		case ListRegisteredWorkflowsOp:
+			return true
+		case RegisterWorkflowOp, RemoveWorkflowOp:
+			return false
+		// Linting and validation only read the spec sent
+		case LintSpecOp, ValidateWorkflowOp:
+			return true
+		// Runtime channels - viewers can see the canaries but not move them
+		case ListRuntimeChannelsOp:
+			return true
+		case UpdateRuntimeChannelOp:
+			return false
+		// Backups - exports hold every job's spec and imports rewrite
+		// history, so both are left to admins
+		case ExportBackupOp, ImportBackupOp:
+			return false
+		// Listing other tenants' volumes and runtimes is left to admins
+		case ListAllResourcesOp:
+			return false
 		default:
 			return false
 		}
+	case NodeRole:
+		// Node certificates only register their node with the coordination
+		// endpoint and list their peers
+		return operation == RegisterNodeOp || operation == ListNodesOp
 	default:
 		return false
 	}
@@ -101,6 +101,12 @@ func TestGrpcAuthorization_ExtractClientRole(t *testing.T) {
 			expectedRole: AdminRole,
 			expectError:  false,
 		},
+		{
+			name:         "Node role",
+			context:      createMockContext([]string{"node"}),
+			expectedRole: NodeRole,
+			expectError:  false,
+		},
 		{
 			name:         "Unknown role",
 			context:      createMockContext([]string{"unknown"}),
@@ -167,6 +173,11 @@ func TestGrpcAuthorization_IsOperationAllowed(t *testing.T) {
 		{AdminRole, StopJobOp, true},
 		{AdminRole, ListJobsOp, true},
 		{AdminRole, StreamJobsOp, true},
+		{AdminRole, ProfileJobOp, true},
+		{AdminRole, GetNodeCapacityOp, true},
+		{AdminRole, RegisterWorkflowOp, true},
+		{AdminRole, ImportBackupOp, true},
+		{AdminRole, ListAllResourcesOp, true},
 
 		// Viewer role - should allow only read operations
 		{ViewerRole, RunJobOp, false},
@@ -174,6 +185,28 @@ func TestGrpcAuthorization_IsOperationAllowed(t *testing.T) {
 		{ViewerRole, StopJobOp, false},
 		{ViewerRole, ListJobsOp, true},
 		{ViewerRole, StreamJobsOp, true},
+		{ViewerRole, ProfileJobOp, false},
+		{ViewerRole, GetNodeCapacityOp, true},
+		{ViewerRole, QueryNodeMetricsOp, true},
+		{ViewerRole, ListRegisteredWorkflowsOp, true},
+		{ViewerRole, RegisterWorkflowOp, false},
+		{ViewerRole, RemoveWorkflowOp, false},
+		{ViewerRole, LintSpecOp, true},
+		{ViewerRole, ValidateWorkflowOp, true},
+		{ViewerRole, ListRuntimeChannelsOp, true},
+		{ViewerRole, UpdateRuntimeChannelOp, false},
+		{ViewerRole, ExportBackupOp, false},
+		{ViewerRole, ImportBackupOp, false},
+		{ViewerRole, ListAllResourcesOp, false},
+		{ViewerRole, ListNodesOp, true},
+		{ViewerRole, RegisterNodeOp, false},
+
+		// Node role - may only register its node and list peers
+		{NodeRole, RegisterNodeOp, true},
+		{NodeRole, ListNodesOp, true},
+		{NodeRole, RunJobOp, false},
+		{NodeRole, GetJobOp, false},
+		{NodeRole, QueryNodeMetricsOp, false},
 
 		// Unknown role - should not allow any operations
 		{UnknownRole, RunJobOp, false},
@@ -330,6 +363,7 @@ func TestClientRole_String(t *testing.T) {
 	}{
 		{AdminRole, "admin"},
 		{ViewerRole, "viewer"},
+		{NodeRole, "node"},
 		{UnknownRole, "unknown"},
 	}
 
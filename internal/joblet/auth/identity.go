@@ -0,0 +1,36 @@
+package auth
+
+import "context"
+
+// Identity is a caller authenticated by the identity provider instead of a
+// client certificate, such as a Kerberos or LDAP user
+type Identity struct {
+	Principal string   // e.g. alice@CORP.EXAMPLE
+	Groups    []string // Groups the provider reported for the principal
+	Role      ClientRole
+	Tenant    string // Tenant the principal's mapping grants (empty = none)
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a context carrying the authenticated caller
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFrom returns the caller the identity provider authenticated, nil
+// when the request relies on its client certificate
+func IdentityFrom(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// ClientTenant returns the tenant of the caller behind ctx: the one its
+// identity mapping grants, otherwise the one tenants assigns its client
+// identity
+func ClientTenant(ctx context.Context, tenants func(client string) string) string {
+	if id := IdentityFrom(ctx); id != nil && id.Tenant != "" {
+		return id.Tenant
+	}
+	return tenants(ClientIdentity(ctx))
+}
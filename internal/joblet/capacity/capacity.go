@@ -0,0 +1,157 @@
+// Package capacity reports how much of a node is still free for new jobs:
+// host totals, the share held back by configuration, and what active jobs
+// and volumes have already claimed.
+package capacity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	monitoringdomain "github.com/ehsaniara/joblet/internal/joblet/monitoring/domain"
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// JobLister lists the jobs known to the node.
+type JobLister interface {
+	ListJobs() []*domain.Job
+}
+
+// VolumeLister lists the volumes created on the node.
+type VolumeLister interface {
+	ListVolumes() []*domain.Volume
+}
+
+// HostMetrics provides the latest host metrics; nil until the first sample.
+type HostMetrics interface {
+	GetLatestMetrics() *monitoringdomain.SystemMetrics
+}
+
+// GPUCounter reports how many GPUs the node can hand out.
+type GPUCounter interface {
+	GPUCount() int
+}
+
+// activeStatuses are the job states that hold on to their resources.
+var activeStatuses = map[domain.JobStatus]bool{
+	domain.StatusPending:      true,
+	domain.StatusScheduled:    true,
+	domain.StatusQueued:       true,
+	domain.StatusInitializing: true,
+	domain.StatusRunning:      true,
+	domain.StatusStopping:     true,
+}
+
+// Calculator builds capacity snapshots. Any source may be nil, in which case
+// the matching totals or allocations are reported as zero.
+type Calculator struct {
+	cfg     *config.Config
+	jobs    JobLister
+	volumes VolumeLister
+	host    HostMetrics
+	gpus    GPUCounter
+	now     func() time.Time
+}
+
+// NewCalculator creates a calculator over the node's stores and metrics.
+func NewCalculator(cfg *config.Config, jobs JobLister, volumes VolumeLister, host HostMetrics, gpus GPUCounter) *Calculator {
+	return &Calculator{
+		cfg:     cfg,
+		jobs:    jobs,
+		volumes: volumes,
+		host:    host,
+		gpus:    gpus,
+		now:     time.Now,
+	}
+}
+
+// Snapshot returns the node capacity at this moment.
+func (c *Calculator) Snapshot() *capacitypb.NodeCapacity {
+	var totalCPU, totalMemory, totalGPUs, totalDisk int64
+	var hostname string
+	if c.host != nil {
+		if m := c.host.GetLatestMetrics(); m != nil {
+			hostname = m.Host.Hostname
+			totalCPU = int64(m.CPU.Cores) * 100
+			totalMemory = int64(m.Memory.TotalBytes / (1024 * 1024))
+			totalDisk = int64(volumeDiskTotal(m.Disk, c.cfg.Volumes.BasePath))
+		}
+	}
+	if c.gpus != nil {
+		totalGPUs = int64(c.gpus.GPUCount())
+	}
+
+	var allocCPU, allocMemory, allocGPUs, allocDisk int64
+	var active, unbounded int32
+	if c.jobs != nil {
+		for _, job := range c.jobs.ListJobs() {
+			if !activeStatuses[job.Status] {
+				continue
+			}
+			active++
+
+			switch {
+			case job.Limits.HasCPULimit():
+				allocCPU += int64(job.Limits.CPU.Value())
+			case job.Limits.HasCoreRestriction():
+				allocCPU += int64(job.Limits.CPUCores.Count()) * 100
+			}
+			if job.Limits.HasMemoryLimit() {
+				allocMemory += int64(job.Limits.Memory.Megabytes())
+			}
+			if !job.Limits.HasMemoryLimit() || (!job.Limits.HasCPULimit() && !job.Limits.HasCoreRestriction()) {
+				unbounded++
+			}
+			allocGPUs += int64(job.GPUCount)
+		}
+	}
+	if c.volumes != nil {
+		for _, v := range c.volumes.ListVolumes() {
+			// Memory volumes are tmpfs and only use RAM as they fill up
+			if v.Type == domain.VolumeTypeFilesystem {
+				allocDisk += v.SizeBytes
+			}
+		}
+	}
+
+	reserved := c.cfg.Capacity
+	return &capacitypb.NodeCapacity{
+		NodeId:        c.cfg.Server.NodeId,
+		Hostname:      hostname,
+		Timestamp:     c.now().Unix(),
+		Cpu:           resource(totalCPU, int64(reserved.ReservedCPUPercent), allocCPU),
+		Memory:        resource(totalMemory, reserved.ReservedMemoryMB, allocMemory),
+		Gpus:          resource(totalGPUs, int64(reserved.ReservedGPUs), allocGPUs),
+		VolumeDisk:    resource(totalDisk, reserved.ReservedVolumeDiskBytes, allocDisk),
+		ActiveJobs:    active,
+		UnboundedJobs: unbounded,
+	}
+}
+
+func resource(total, reserved, allocated int64) *capacitypb.ResourceCapacity {
+	return &capacitypb.ResourceCapacity{
+		Total:       total,
+		Reserved:    reserved,
+		Allocated:   allocated,
+		Schedulable: max(total-reserved-allocated, 0),
+	}
+}
+
+// volumeDiskTotal returns the size of the mount that holds basePath, picking
+// the longest mount point that is a prefix of it.
+func volumeDiskTotal(disks []monitoringdomain.DiskMetrics, basePath string) uint64 {
+	var total uint64
+	best := -1
+	for _, d := range disks {
+		mp := d.MountPoint
+		if mp != "/" && basePath != mp && !strings.HasPrefix(basePath, strings.TrimSuffix(mp, "/")+"/") {
+			continue
+		}
+		if len(mp) > best {
+			best = len(mp)
+			total = d.TotalBytes
+		}
+	}
+	return total
+}
@@ -0,0 +1,107 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	monitoringdomain "github.com/ehsaniara/joblet/internal/joblet/monitoring/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobs []*domain.Job
+
+func (f fakeJobs) ListJobs() []*domain.Job { return f }
+
+type fakeVolumes []*domain.Volume
+
+func (f fakeVolumes) ListVolumes() []*domain.Volume { return f }
+
+type fakeHost struct {
+	metrics *monitoringdomain.SystemMetrics
+}
+
+func (f fakeHost) GetLatestMetrics() *monitoringdomain.SystemMetrics { return f.metrics }
+
+type fakeGPUs int
+
+func (f fakeGPUs) GPUCount() int { return int(f) }
+
+func job(status domain.JobStatus, cpu int32, cores string, memoryMB int32, gpus int32) *domain.Job {
+	return &domain.Job{
+		Status:   status,
+		Limits:   *domain.NewResourceLimitsFromParams(cpu, cores, memoryMB, 0),
+		GPUCount: gpus,
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.Server.NodeId = "node-1"
+	cfg.Volumes.BasePath = "/opt/joblet/volumes"
+	cfg.Capacity = config.CapacityConfig{
+		ReservedCPUPercent: 100,
+		ReservedMemoryMB:   1024,
+		ReservedGPUs:       1,
+	}
+
+	host := fakeHost{&monitoringdomain.SystemMetrics{
+		Host:   monitoringdomain.HostInfo{Hostname: "worker"},
+		CPU:    monitoringdomain.CPUMetrics{Cores: 8},
+		Memory: monitoringdomain.MemoryMetrics{TotalBytes: 16 * 1024 * 1024 * 1024},
+		Disk: []monitoringdomain.DiskMetrics{
+			{MountPoint: "/", TotalBytes: 100},
+			{MountPoint: "/opt", TotalBytes: 500},
+			{MountPoint: "/opt/jobletx", TotalBytes: 900},
+		},
+	}}
+	jobs := fakeJobs{
+		job(domain.StatusRunning, 200, "", 2048, 1),
+		job(domain.StatusScheduled, 0, "0-1", 512, 0),
+		job(domain.StatusRunning, 0, "", 0, 0),
+		job(domain.StatusCompleted, 400, "", 4096, 2),
+	}
+	volumes := fakeVolumes{
+		{Type: domain.VolumeTypeFilesystem, SizeBytes: 200},
+		{Type: domain.VolumeTypeMemory, SizeBytes: 1000},
+	}
+
+	calc := NewCalculator(&cfg, jobs, volumes, host, fakeGPUs(2))
+	calc.now = func() time.Time { return time.Unix(1700000000, 0) }
+	snap := calc.Snapshot()
+
+	assert.Equal(t, "node-1", snap.NodeId)
+	assert.Equal(t, "worker", snap.Hostname)
+	assert.Equal(t, int64(1700000000), snap.Timestamp)
+
+	assert.Equal(t, int64(800), snap.Cpu.Total)
+	assert.Equal(t, int64(400), snap.Cpu.Allocated)
+	assert.Equal(t, int64(300), snap.Cpu.Schedulable)
+
+	assert.Equal(t, int64(16384), snap.Memory.Total)
+	assert.Equal(t, int64(2560), snap.Memory.Allocated)
+	assert.Equal(t, int64(16384-1024-2560), snap.Memory.Schedulable)
+
+	// Reserved plus allocated exceed the total, schedulable stops at zero
+	assert.Equal(t, int64(2), snap.Gpus.Total)
+	assert.Equal(t, int64(1), snap.Gpus.Allocated)
+	assert.Equal(t, int64(0), snap.Gpus.Schedulable)
+
+	// /opt holds the volumes, /opt/jobletx only shares a name prefix
+	assert.Equal(t, int64(500), snap.VolumeDisk.Total)
+	assert.Equal(t, int64(200), snap.VolumeDisk.Allocated)
+
+	assert.Equal(t, int32(3), snap.ActiveJobs)
+	assert.Equal(t, int32(1), snap.UnboundedJobs)
+}
+
+func TestSnapshotWithoutSources(t *testing.T) {
+	cfg := config.DefaultConfig
+	snap := NewCalculator(&cfg, nil, nil, nil, nil).Snapshot()
+
+	assert.Equal(t, int64(0), snap.Cpu.Total)
+	assert.Equal(t, int64(0), snap.Memory.Schedulable)
+	assert.Equal(t, int32(0), snap.ActiveJobs)
+}
@@ -0,0 +1,200 @@
+// Package clocksync checks the node's clock, which scheduled jobs start by.
+// The clock is measured against NTP servers at startup and periodically; on
+// nodes without NTP access, the clocks of clients submitting scheduled jobs
+// serve as the reference instead. An offset above the configured maximum is
+// flagged in monitor status, and a job scheduled closer than the clock's
+// uncertainty gets a warning in its RunJob response.
+package clocksync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// Sources of a measurement
+const (
+	SourceNTP    = "ntp"
+	SourceClient = "client"
+)
+
+// Status is the latest measurement of the node's clock
+type Status struct {
+	Source      string        `json:"source"`
+	Reference   string        `json:"reference,omitempty"` // NTP server that answered
+	Offset      time.Duration `json:"offset"`              // Node clock minus the reference; positive when the node is ahead
+	Uncertainty time.Duration `json:"uncertainty"`         // Half the round trip of the measurement
+	MaxDrift    time.Duration `json:"maxDrift"`
+	Drifted     bool          `json:"drifted"` // The offset is above MaxDrift
+	CheckedAt   time.Time     `json:"checkedAt"`
+	Error       string        `json:"error,omitempty"` // Why the last NTP check failed
+}
+
+// Window is how far the node's clock may be from the reference's
+func (s Status) Window() time.Duration {
+	return abs(s.Offset) + s.Uncertainty
+}
+
+// Checker measures the node's clock and keeps the latest status
+type Checker struct {
+	cfg    config.ClockConfig
+	query  func(ctx context.Context, server string) (Sample, error)
+	logger *logger.Logger
+
+	mu     sync.RWMutex
+	status Status
+	known  bool
+}
+
+// NewChecker creates a checker for the configured NTP servers
+func NewChecker(cfg config.ClockConfig) *Checker {
+	return &Checker{
+		cfg:    cfg,
+		query:  QueryNTP,
+		logger: logger.WithField("component", "clocksync"),
+	}
+}
+
+// Run checks the clock now and every check interval until ctx is done. Without
+// NTP servers only client clocks are compared.
+func (c *Checker) Run(ctx context.Context) {
+	if len(c.cfg.NTPServers) == 0 {
+		c.logger.Info("no NTP servers configured, comparing the clock with clients only")
+		return
+	}
+	c.Check(ctx)
+	ticker := time.NewTicker(c.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Check measures the clock against the first NTP server that answers. When
+// none does, the previous measurement is kept with the error.
+func (c *Checker) Check(ctx context.Context) Status {
+	var lastErr error
+	for _, server := range c.cfg.NTPServers {
+		sample, err := c.query(ctx, server)
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", server, err)
+			continue
+		}
+		status := c.measured(SourceNTP, server, sample, time.Now())
+		c.record(status)
+		return status
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if lastErr != nil {
+		if c.status.Error == "" {
+			c.logger.Warn("clock check failed, no NTP server answered", "error", lastErr)
+		}
+		c.status.Error = lastErr.Error()
+	}
+	return c.status
+}
+
+// ObserveClient measures the clock against a client's, sent at clientTime
+// and received at receivedAt. The one-way network delay is not known, so
+// the measurement has no uncertainty of its own. It becomes the node's
+// status unless an NTP server answered the last check.
+func (c *Checker) ObserveClient(clientTime, receivedAt time.Time) Status {
+	status := c.measured(SourceClient, "", Sample{Offset: receivedAt.Sub(clientTime)}, receivedAt)
+	c.mu.RLock()
+	ntp := c.known && c.status.Source == SourceNTP && c.status.Error == ""
+	c.mu.RUnlock()
+	if !ntp {
+		c.record(status)
+	}
+	return status
+}
+
+// Status returns the latest measurement, if the clock was measured yet
+func (c *Checker) Status() (Status, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status, c.known
+}
+
+// ScheduleWarning returns a warning when a job scheduled at scheduled, as
+// submitted at now, falls within the clock's uncertainty: the node's idea of
+// the time is too far off for it to start when it was meant to. reference
+// is the measurement to judge by; empty when the clock is fine.
+func ScheduleWarning(reference Status, scheduled, now time.Time) string {
+	window := reference.Window()
+	if window <= 0 || scheduled.Sub(now) > window {
+		return ""
+	}
+	against := "the client's clock"
+	if reference.Source == SourceNTP {
+		against = "NTP server " + reference.Reference
+	}
+	return fmt.Sprintf("scheduled time %s is within %s of now, the uncertainty of the server clock (%s %s); the job may start early or late",
+		scheduled.Format(time.RFC3339), window.Round(time.Millisecond), formatOffset(reference.Offset), against)
+}
+
+// Reference returns the measurement a job submitted with client is judged
+// by: the NTP status when the last check succeeded, otherwise the client's
+func (c *Checker) Reference(client *Status) (Status, bool) {
+	status, known := c.Status()
+	if known && status.Source == SourceNTP && status.Error == "" {
+		return status, true
+	}
+	if client != nil {
+		return *client, true
+	}
+	return status, known
+}
+
+func (c *Checker) measured(source, reference string, sample Sample, at time.Time) Status {
+	status := Status{
+		Source:      source,
+		Reference:   reference,
+		Offset:      sample.Offset,
+		Uncertainty: sample.RoundTrip / 2,
+		MaxDrift:    c.cfg.MaxDrift,
+		CheckedAt:   at,
+	}
+	status.Drifted = c.cfg.MaxDrift > 0 && abs(status.Offset) > c.cfg.MaxDrift
+	return status
+}
+
+// record keeps a measurement, logging when the clock starts or stops
+// drifting
+func (c *Checker) record(status Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status.Drifted && (!c.known || !c.status.Drifted) {
+		c.logger.Warn("server clock drifted, scheduled jobs may start at the wrong time",
+			"offset", status.Offset, "maxDrift", status.MaxDrift, "source", status.Source, "reference", status.Reference)
+	} else if !status.Drifted && c.known && c.status.Drifted {
+		c.logger.Info("server clock back within the maximum drift", "offset", status.Offset, "source", status.Source)
+	}
+	c.status, c.known = status, true
+}
+
+// formatOffset describes an offset as ahead of or behind a reference
+func formatOffset(offset time.Duration) string {
+	if offset < 0 {
+		return abs(offset).Round(time.Millisecond).String() + " behind"
+	}
+	return offset.Round(time.Millisecond).String() + " ahead of"
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
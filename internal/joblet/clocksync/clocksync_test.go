@@ -0,0 +1,123 @@
+package clocksync
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// serveNTP answers SNTP requests with a clock behind by lag
+func serveNTP(t *testing.T, lag time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n != 48 {
+				continue
+			}
+			response := make([]byte, 48)
+			response[0] = 4<<3 | 4
+			response[1] = 2
+			copy(response[24:32], buf[40:48])
+			binary.BigEndian.PutUint64(response[32:], ntpTimestamp(time.Now().Add(-lag)))
+			binary.BigEndian.PutUint64(response[40:], ntpTimestamp(time.Now().Add(-lag)))
+			_, _ = conn.WriteTo(response, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestQueryNTP(t *testing.T) {
+	server := serveNTP(t, 2*time.Second)
+	sample, err := QueryNTP(context.Background(), server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample.Offset < 1900*time.Millisecond || sample.Offset > 2100*time.Millisecond {
+		t.Errorf("Offset = %v, want about 2s ahead", sample.Offset)
+	}
+	if sample.RoundTrip < 0 || sample.RoundTrip > time.Second {
+		t.Errorf("RoundTrip = %v", sample.RoundTrip)
+	}
+}
+
+func TestNTPTimestamp(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 500_000_000, time.UTC)
+	if got := ntpTime(ntpTimestamp(now)); got.Sub(now).Abs() > time.Microsecond {
+		t.Errorf("ntpTime(ntpTimestamp(%v)) = %v", now, got)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	answers := map[string]Sample{"good": {Offset: -3 * time.Second, RoundTrip: 40 * time.Millisecond}}
+	c := NewChecker(config.ClockConfig{NTPServers: []string{"down", "good"}, MaxDrift: time.Second})
+	c.query = func(_ context.Context, server string) (Sample, error) {
+		if sample, ok := answers[server]; ok {
+			return sample, nil
+		}
+		return Sample{}, errors.New("timeout")
+	}
+
+	if _, known := c.Status(); known {
+		t.Fatal("Status() known before any check")
+	}
+	status := c.Check(context.Background())
+	if status.Source != SourceNTP || status.Reference != "good" || !status.Drifted || status.Uncertainty != 20*time.Millisecond {
+		t.Errorf("Check() = %+v", status)
+	}
+
+	// Client clocks do not replace a working NTP server
+	now := time.Now()
+	client := c.ObserveClient(now.Add(-time.Minute), now)
+	if client.Source != SourceClient || client.Offset != time.Minute {
+		t.Errorf("ObserveClient() = %+v", client)
+	}
+	if reference, _ := c.Reference(&client); reference.Source != SourceNTP {
+		t.Errorf("Reference() = %+v, want the NTP status", reference)
+	}
+
+	// Once NTP fails, the previous status is kept with the error and clients
+	// become the reference
+	delete(answers, "good")
+	status = c.Check(context.Background())
+	if status.Reference != "good" || !strings.Contains(status.Error, "timeout") {
+		t.Errorf("Check() without NTP = %+v", status)
+	}
+	if reference, _ := c.Reference(&client); reference.Source != SourceClient {
+		t.Errorf("Reference() = %+v, want the client's", reference)
+	}
+	c.ObserveClient(now, now)
+	if status, _ := c.Status(); status.Source != SourceClient || status.Drifted {
+		t.Errorf("Status() after a client in sync = %+v", status)
+	}
+}
+
+func TestScheduleWarning(t *testing.T) {
+	now := time.Now()
+	reference := Status{Source: SourceNTP, Reference: "pool.ntp.org", Offset: -2 * time.Second, Uncertainty: 50 * time.Millisecond}
+
+	warning := ScheduleWarning(reference, now.Add(time.Second), now)
+	if !strings.Contains(warning, "2s behind NTP server pool.ntp.org") || !strings.Contains(warning, "within 2.05s") {
+		t.Errorf("ScheduleWarning() = %q", warning)
+	}
+	if warning := ScheduleWarning(reference, now.Add(time.Minute), now); warning != "" {
+		t.Errorf("ScheduleWarning() beyond the uncertainty = %q", warning)
+	}
+	if warning := ScheduleWarning(Status{Source: SourceClient}, now, now); warning != "" {
+		t.Errorf("ScheduleWarning() of an exact clock = %q", warning)
+	}
+}
@@ -0,0 +1,93 @@
+package clocksync
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ntpTimeout bounds one query to an NTP server
+const ntpTimeout = 5 * time.Second
+
+// ntpEpoch is where NTP timestamps start, 70 years before the Unix epoch
+var ntpEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Sample is one measurement of the node's clock against a reference
+type Sample struct {
+	Offset    time.Duration // Node clock minus the reference; positive when the node is ahead
+	RoundTrip time.Duration // Network delay of the measurement, which bounds its error
+}
+
+// QueryNTP measures the node's clock against an NTP server, host or
+// host:port, with one SNTP request
+func QueryNTP(ctx context.Context, server string) (Sample, error) {
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, "123")
+	}
+	ctx, cancel := context.WithTimeout(ctx, ntpTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return Sample{}, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	// Version 4, client mode; the transmit timestamp comes back as the
+	// originate timestamp, tying the answer to the request
+	request := make([]byte, 48)
+	request[0] = 4<<3 | 3
+	sent := time.Now()
+	binary.BigEndian.PutUint64(request[40:], ntpTimestamp(sent))
+	if _, err := conn.Write(request); err != nil {
+		return Sample{}, err
+	}
+	response := make([]byte, 48)
+	for {
+		n, err := conn.Read(response)
+		if err != nil {
+			return Sample{}, err
+		}
+		if n == 48 && bytes.Equal(response[24:32], request[40:48]) {
+			break
+		}
+	}
+	received := time.Now()
+
+	if mode := response[0] & 7; mode != 4 {
+		return Sample{}, fmt.Errorf("unexpected NTP mode %d from %s", mode, server)
+	}
+	if stratum := response[1]; stratum == 0 || stratum > 15 {
+		return Sample{}, fmt.Errorf("%s is not synchronized (stratum %d)", server, stratum)
+	}
+	serverReceived := ntpTime(binary.BigEndian.Uint64(response[32:]))
+	serverSent := ntpTime(binary.BigEndian.Uint64(response[40:]))
+
+	// The server's clock minus ours, halfway through the exchange
+	serverAhead := (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2
+	return Sample{
+		Offset:    -serverAhead,
+		RoundTrip: received.Sub(sent) - serverSent.Sub(serverReceived),
+	}, nil
+}
+
+// ntpTimestamp encodes t as 32.32 fixed-point seconds since the NTP epoch
+func ntpTimestamp(t time.Time) uint64 {
+	d := t.Sub(ntpEpoch)
+	seconds := uint64(d / time.Second)
+	fraction := uint64(d%time.Second) << 32 / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+// ntpTime decodes an NTP timestamp
+func ntpTime(ts uint64) time.Time {
+	seconds := time.Duration(ts>>32) * time.Second
+	fraction := time.Duration((ts & 0xffffffff) * uint64(time.Second) >> 32)
+	return ntpEpoch.Add(seconds + fraction)
+}
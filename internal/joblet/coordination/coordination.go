@@ -0,0 +1,50 @@
+// Package coordination registers the node with a coordination endpoint and
+// keeps the registration alive with heartbeats, so clients can list the live
+// nodes of a cluster instead of maintaining node lists by hand.
+//
+// Two endpoints are supported: the state service API, which keeps nodes in
+// memory, and etcd, where each node is a key attached to a lease. Either way a
+// node that stops sending heartbeats disappears after its TTL; there is no
+// explicit deregistration.
+package coordination
+
+import (
+	"context"
+	"fmt"
+
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Registrar publishes this node's registration and lists the live nodes.
+type Registrar interface {
+	// Heartbeat registers the node or refreshes its registration.
+	Heartbeat(ctx context.Context, node *nodespb.NodeInfo) error
+	// ListNodes returns the live nodes carrying all of the given labels,
+	// sorted by node ID.
+	ListNodes(ctx context.Context, labels map[string]string) ([]*nodespb.NodeInfo, error)
+	// Close releases the connection to the endpoint.
+	Close() error
+}
+
+// New creates the registrar for the configured backend.
+func New(cfg *config.Config) (Registrar, error) {
+	switch cfg.Coordination.Backend {
+	case "state":
+		return NewStateRegistrar(cfg)
+	case "etcd":
+		return NewEtcdRegistrar(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported coordination backend: %s", cfg.Coordination.Backend)
+	}
+}
+
+// MatchesLabels reports whether the node carries every wanted label.
+func MatchesLabels(node *nodespb.NodeInfo, wanted map[string]string) bool {
+	for key, value := range wanted {
+		if got, ok := node.Labels[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+package coordination
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// EtcdRegistrar keeps the registration under <prefix><node id> in etcd,
+// attached to a lease with the node's TTL, so etcd removes the key when
+// heartbeats stop. It talks to etcd's v3 JSON gateway, which every etcd
+// server serves on its client URL.
+type EtcdRegistrar struct {
+	endpoint string
+	prefix   string
+	ttl      time.Duration
+	client   *http.Client
+	now      func() time.Time
+
+	mu           sync.Mutex
+	leaseID      string
+	registeredAt int64
+}
+
+// NewEtcdRegistrar creates a registrar for the etcd client URL at the
+// coordination endpoint. For https endpoints the server certificate is
+// checked against the security section's CA, and the coordination client
+// certificate is presented when one is configured.
+func NewEtcdRegistrar(cfg *config.Config) (*EtcdRegistrar, error) {
+	endpoint := strings.TrimSuffix(cfg.Coordination.Endpoint, "/")
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("etcd endpoint must be an http:// or https:// URL: %s", cfg.Coordination.Endpoint)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if strings.HasPrefix(endpoint, "https://") {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.Security.CACert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(cfg.Security.CACert)) {
+				return nil, fmt.Errorf("failed to parse CA certificate")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if cfg.Coordination.ClientCert != "" {
+			cert, err := tls.X509KeyPair([]byte(cfg.Coordination.ClientCert), []byte(cfg.Coordination.ClientKey))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load coordination client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &EtcdRegistrar{
+		endpoint: endpoint,
+		prefix:   cfg.Coordination.Prefix,
+		ttl:      cfg.Coordination.TTL,
+		client:   &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+// Heartbeat renews the node's lease, granting a new one when it has expired,
+// and writes the current registration under it.
+func (r *EtcdRegistrar) Heartbeat(ctx context.Context, node *nodespb.NodeInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	leaseID, err := r.renewLease(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := r.now().Unix()
+	if r.registeredAt == 0 {
+		r.registeredAt = now
+	}
+	registration := &nodespb.NodeInfo{
+		NodeId:        node.NodeId,
+		Address:       node.Address,
+		Hostname:      node.Hostname,
+		Version:       node.Version,
+		Labels:        node.Labels,
+		Capacity:      node.Capacity,
+		TtlSeconds:    node.TtlSeconds,
+		RegisteredAt:  r.registeredAt,
+		LastHeartbeat: now,
+	}
+	value, err := protojson.Marshal(registration)
+	if err != nil {
+		return fmt.Errorf("failed to encode node registration: %w", err)
+	}
+
+	return r.call(ctx, "/v3/kv/put", map[string]string{
+		"key":   encodeKey(r.prefix + node.NodeId),
+		"value": base64.StdEncoding.EncodeToString(value),
+		"lease": leaseID,
+	}, nil)
+}
+
+// renewLease keeps the current lease alive or grants a new one. etcd answers
+// a keepalive for an expired lease with a zero TTL.
+func (r *EtcdRegistrar) renewLease(ctx context.Context) (string, error) {
+	if r.leaseID != "" {
+		var resp struct {
+			Result struct {
+				TTL string `json:"TTL"`
+			} `json:"result"`
+		}
+		if err := r.call(ctx, "/v3/lease/keepalive", map[string]string{"ID": r.leaseID}, &resp); err != nil {
+			return "", err
+		}
+		if resp.Result.TTL != "" && resp.Result.TTL != "0" {
+			return r.leaseID, nil
+		}
+		r.leaseID = ""
+	}
+
+	var resp struct {
+		ID string `json:"ID"`
+	}
+	ttl := int64(r.ttl / time.Second)
+	if err := r.call(ctx, "/v3/lease/grant", map[string]int64{"TTL": ttl}, &resp); err != nil {
+		return "", err
+	}
+	if resp.ID == "" {
+		return "", fmt.Errorf("etcd granted no lease")
+	}
+	r.leaseID = resp.ID
+	return r.leaseID, nil
+}
+
+// ListNodes reads every registration under the prefix.
+func (r *EtcdRegistrar) ListNodes(ctx context.Context, labels map[string]string) ([]*nodespb.NodeInfo, error) {
+	var resp struct {
+		Kvs []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := r.call(ctx, "/v3/kv/range", map[string]string{
+		"key":       encodeKey(r.prefix),
+		"range_end": encodeKey(prefixEnd(r.prefix)),
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*nodespb.NodeInfo, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in etcd: %w", err)
+		}
+		node := &nodespb.NodeInfo{}
+		if err := protojson.Unmarshal(value, node); err != nil {
+			// A foreign key under the prefix, not ours to interpret
+			continue
+		}
+		if MatchesLabels(node, labels) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeId < nodes[j].NodeId })
+	return nodes, nil
+}
+
+// Close releases idle connections to etcd.
+func (r *EtcdRegistrar) Close() error {
+	r.client.CloseIdleConnections()
+	return nil
+}
+
+// call posts a JSON request to the gateway and decodes the reply into out.
+func (r *EtcdRegistrar) call(ctx context.Context, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("etcd request %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read etcd response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd request %s failed: %s: %s", path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid etcd response to %s: %w", path, err)
+	}
+	return nil
+}
+
+func encodeKey(key string) string {
+	return base64.StdEncoding.EncodeToString([]byte(key))
+}
+
+// prefixEnd returns the range end covering every key with the prefix: the
+// prefix with its last byte incremented.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// All 0xff: range to the end of the keyspace
+	return "\x00"
+}
@@ -0,0 +1,212 @@
+package coordination
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// fakeEtcd serves the parts of etcd's v3 JSON gateway the registrar uses.
+// Keys are kept with their lease; revoking a lease drops its keys.
+type fakeEtcd struct {
+	mu     sync.Mutex
+	kvs    map[string]string // key -> base64 value
+	leases map[string]bool
+	owner  map[string]string // key -> lease ID
+	grants int
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{kvs: map[string]string{}, leases: map[string]bool{}, owner: map[string]string{}}
+}
+
+func (f *fakeEtcd) expire(leaseID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.leases, leaseID)
+	for key, owner := range f.owner {
+		if owner == leaseID {
+			delete(f.kvs, key)
+			delete(f.owner, key)
+		}
+	}
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		f.grants++
+		id := strconv.Itoa(1000 + f.grants)
+		f.leases[id] = true
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": id, "TTL": "30"})
+	case "/v3/lease/keepalive":
+		id, _ := body["ID"].(string)
+		result := map[string]string{"ID": id}
+		if f.leases[id] {
+			result["TTL"] = "30"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+	case "/v3/kv/put":
+		key, _ := body["key"].(string)
+		lease, _ := body["lease"].(string)
+		if !f.leases[lease] {
+			http.Error(w, `{"error":"etcdserver: requested lease not found"}`, http.StatusNotFound)
+			return
+		}
+		f.kvs[key] = body["value"].(string)
+		f.owner[key] = lease
+		_, _ = w.Write([]byte(`{}`))
+	case "/v3/kv/range":
+		start, _ := base64.StdEncoding.DecodeString(body["key"].(string))
+		end, _ := base64.StdEncoding.DecodeString(body["range_end"].(string))
+		var kvs []map[string]string
+		for key, value := range f.kvs {
+			raw, _ := base64.StdEncoding.DecodeString(key)
+			if string(raw) >= string(start) && string(raw) < string(end) {
+				kvs = append(kvs, map[string]string{"key": key, "value": value})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"kvs": kvs})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestEtcdRegistrar(t *testing.T, etcd *fakeEtcd) *EtcdRegistrar {
+	t.Helper()
+	srv := httptest.NewServer(etcd)
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{}
+	cfg.Coordination = config.CoordinationConfig{Backend: "etcd", Endpoint: srv.URL, Prefix: "/joblet/nodes/", TTL: 30 * time.Second}
+	r, err := NewEtcdRegistrar(cfg)
+	if err != nil {
+		t.Fatalf("NewEtcdRegistrar: %v", err)
+	}
+	return r
+}
+
+func TestEtcdRegistrar_HeartbeatAndList(t *testing.T) {
+	etcd := newFakeEtcd()
+	r := newTestEtcdRegistrar(t, etcd)
+	ctx := context.Background()
+
+	clock := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return clock }
+
+	for _, node := range []*nodespb.NodeInfo{
+		{NodeId: "node-b", Address: "b:50051", Labels: map[string]string{"zone": "eu"}},
+		{NodeId: "node-a", Address: "a:50051", Labels: map[string]string{"zone": "us", "gpu": "true"}},
+	} {
+		other := newTestEtcdRegistrarOn(t, r)
+		other.now = r.now
+		if err := other.Heartbeat(ctx, node); err != nil {
+			t.Fatalf("Heartbeat(%s): %v", node.NodeId, err)
+		}
+	}
+
+	// A foreign key under the prefix is skipped
+	etcd.kvs[encodeKey("/joblet/nodes/garbage")] = base64.StdEncoding.EncodeToString([]byte("not json"))
+
+	nodes, err := r.ListNodes(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].NodeId != "node-a" || nodes[1].NodeId != "node-b" {
+		t.Fatalf("nodes = %v, want node-a and node-b sorted", nodes)
+	}
+	if nodes[0].RegisteredAt != clock.Unix() || nodes[0].LastHeartbeat != clock.Unix() {
+		t.Errorf("timestamps = %d/%d, want %d", nodes[0].RegisteredAt, nodes[0].LastHeartbeat, clock.Unix())
+	}
+
+	nodes, err = r.ListNodes(ctx, map[string]string{"gpu": "true"})
+	if err != nil || len(nodes) != 1 || nodes[0].NodeId != "node-a" {
+		t.Errorf("ListNodes(gpu=true) = %v, %v", nodes, err)
+	}
+}
+
+// newTestEtcdRegistrarOn creates a second registrar, as another node would
+// have, against the same etcd.
+func newTestEtcdRegistrarOn(t *testing.T, r *EtcdRegistrar) *EtcdRegistrar {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Coordination = config.CoordinationConfig{Backend: "etcd", Endpoint: r.endpoint, Prefix: r.prefix, TTL: r.ttl}
+	other, err := NewEtcdRegistrar(cfg)
+	if err != nil {
+		t.Fatalf("NewEtcdRegistrar: %v", err)
+	}
+	return other
+}
+
+func TestEtcdRegistrar_RegrantsExpiredLease(t *testing.T) {
+	etcd := newFakeEtcd()
+	r := newTestEtcdRegistrar(t, etcd)
+	ctx := context.Background()
+	node := &nodespb.NodeInfo{NodeId: "node-a"}
+
+	clock := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return clock }
+	if err := r.Heartbeat(ctx, node); err != nil {
+		t.Fatalf("first heartbeat: %v", err)
+	}
+	if err := r.Heartbeat(ctx, node); err != nil {
+		t.Fatalf("second heartbeat: %v", err)
+	}
+	if etcd.grants != 1 {
+		t.Fatalf("grants = %d, want the lease kept alive", etcd.grants)
+	}
+
+	// The node was partitioned for longer than its TTL
+	etcd.expire(r.leaseID)
+	clock = clock.Add(time.Minute)
+	if err := r.Heartbeat(ctx, node); err != nil {
+		t.Fatalf("heartbeat after expiry: %v", err)
+	}
+	if etcd.grants != 2 {
+		t.Errorf("grants = %d, want a new lease", etcd.grants)
+	}
+
+	nodes, err := r.ListNodes(ctx, nil)
+	if err != nil || len(nodes) != 1 {
+		t.Fatalf("ListNodes = %v, %v", nodes, err)
+	}
+	if nodes[0].LastHeartbeat != clock.Unix() || nodes[0].RegisteredAt != clock.Add(-time.Minute).Unix() {
+		t.Errorf("timestamps = %d/%d", nodes[0].RegisteredAt, nodes[0].LastHeartbeat)
+	}
+}
+
+func TestNewEtcdRegistrar_RequiresURL(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Coordination = config.CoordinationConfig{Backend: "etcd", Endpoint: "etcd:2379"}
+	if _, err := NewEtcdRegistrar(cfg); err == nil || !strings.Contains(err.Error(), "http://") {
+		t.Errorf("got %v, want an error asking for a URL", err)
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	for prefix, want := range map[string]string{
+		"/joblet/nodes/": "/joblet/nodes0",
+		"a\xff":          "b",
+		"\xff":           "\x00",
+	} {
+		if got := prefixEnd(prefix); got != want {
+			t.Errorf("prefixEnd(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+}
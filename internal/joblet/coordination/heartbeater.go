@@ -0,0 +1,118 @@
+package coordination
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/version"
+)
+
+// Heartbeater sends the node's registration to the coordination endpoint on
+// every heartbeat interval, with a fresh capacity snapshot each time.
+type Heartbeater struct {
+	cfg       *config.Config
+	registrar Registrar
+	capacity  func() *capacitypb.NodeCapacity
+	hostname  string
+	logger    *logger.Logger
+}
+
+// NewHeartbeater creates a heartbeater publishing the capacity snapshots
+// returned by capacity.
+func NewHeartbeater(cfg *config.Config, registrar Registrar, capacity func() *capacitypb.NodeCapacity) *Heartbeater {
+	hostname, _ := os.Hostname()
+	return &Heartbeater{
+		cfg:       cfg,
+		registrar: registrar,
+		capacity:  capacity,
+		hostname:  hostname,
+		logger:    logger.WithField("component", "coordination"),
+	}
+}
+
+// Run sends a heartbeat right away and then on every interval until ctx is
+// done. Failures are logged and retried on the next interval; the node stays
+// listed as long as one heartbeat gets through within its TTL.
+func (h *Heartbeater) Run(ctx context.Context) {
+	interval := h.cfg.Coordination.HeartbeatInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	h.logger.Info("registering node", "backend", h.cfg.Coordination.Backend,
+		"endpoint", h.cfg.Coordination.Endpoint, "address", h.address(), "interval", interval)
+
+	failing := false
+	for {
+		beatCtx, cancel := context.WithTimeout(ctx, interval)
+		err := h.registrar.Heartbeat(beatCtx, h.NodeInfo())
+		cancel()
+		switch {
+		case err != nil && !failing:
+			h.logger.Warn("heartbeat failed", "endpoint", h.cfg.Coordination.Endpoint, "error", err)
+			failing = true
+		case err != nil:
+			h.logger.Debug("heartbeat failed", "error", err)
+		case failing:
+			h.logger.Info("heartbeat recovered", "endpoint", h.cfg.Coordination.Endpoint)
+			failing = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// NodeInfo returns the registration sent with the next heartbeat.
+func (h *Heartbeater) NodeInfo() *nodespb.NodeInfo {
+	node := &nodespb.NodeInfo{
+		NodeId:     h.cfg.Server.NodeId,
+		Address:    h.address(),
+		Hostname:   h.hostname,
+		Version:    version.GetVersion(),
+		Labels:     h.cfg.Coordination.Labels,
+		TtlSeconds: int32(h.cfg.Coordination.TTL / time.Second),
+	}
+
+	if snapshot := h.capacity(); snapshot != nil {
+		if node.NodeId == "" {
+			node.NodeId = snapshot.NodeId
+		}
+		node.Capacity = &nodespb.NodeCapacity{
+			CpuTotal:            snapshot.GetCpu().GetTotal(),
+			CpuSchedulable:      snapshot.GetCpu().GetSchedulable(),
+			MemoryTotalMb:       snapshot.GetMemory().GetTotal(),
+			MemorySchedulableMb: snapshot.GetMemory().GetSchedulable(),
+			GpuTotal:            snapshot.GetGpus().GetTotal(),
+			GpuSchedulable:      snapshot.GetGpus().GetSchedulable(),
+			ActiveJobs:          snapshot.ActiveJobs,
+		}
+	}
+	if node.NodeId == "" {
+		// Without a configured node ID the hostname still tells nodes apart
+		node.NodeId = h.hostname
+	}
+	return node
+}
+
+// address is the advertised address, or the hostname with the server port
+// when the server listens on all interfaces.
+func (h *Heartbeater) address() string {
+	if h.cfg.Coordination.AdvertiseAddress != "" {
+		return h.cfg.Coordination.AdvertiseAddress
+	}
+	host := h.cfg.Server.Address
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = h.hostname
+	}
+	return net.JoinHostPort(host, strconv.Itoa(h.cfg.Server.Port))
+}
@@ -0,0 +1,121 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// recordingRegistrar records heartbeats, failing the first `failures` of them
+type recordingRegistrar struct {
+	mu       sync.Mutex
+	beats    []*nodespb.NodeInfo
+	failures int
+}
+
+func (r *recordingRegistrar) Heartbeat(_ context.Context, node *nodespb.NodeInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beats = append(r.beats, node)
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (r *recordingRegistrar) ListNodes(context.Context, map[string]string) ([]*nodespb.NodeInfo, error) {
+	return nil, nil
+}
+
+func (r *recordingRegistrar) Close() error { return nil }
+
+func (r *recordingRegistrar) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.beats)
+}
+
+func testCoordinationConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Server = config.ServerConfig{Address: "10.0.0.5", Port: 50051, NodeId: "node-1"}
+	cfg.Coordination = config.CoordinationConfig{
+		Enabled:           true,
+		Backend:           "state",
+		Labels:            map[string]string{"zone": "eu"},
+		HeartbeatInterval: 10 * time.Millisecond,
+		TTL:               30 * time.Second,
+	}
+	return cfg
+}
+
+func TestHeartbeater_NodeInfo(t *testing.T) {
+	cfg := testCoordinationConfig()
+	h := NewHeartbeater(cfg, &recordingRegistrar{}, func() *capacitypb.NodeCapacity {
+		return &capacitypb.NodeCapacity{
+			NodeId:     "ignored",
+			Cpu:        &capacitypb.ResourceCapacity{Total: 800, Schedulable: 500},
+			Memory:     &capacitypb.ResourceCapacity{Total: 16384, Schedulable: 8192},
+			ActiveJobs: 3,
+		}
+	})
+
+	node := h.NodeInfo()
+	if node.NodeId != "node-1" || node.Address != "10.0.0.5:50051" || node.TtlSeconds != 30 {
+		t.Errorf("node = %v", node)
+	}
+	if node.Labels["zone"] != "eu" || node.Version == "" {
+		t.Errorf("labels/version = %v/%q", node.Labels, node.Version)
+	}
+	c := node.Capacity
+	if c.CpuTotal != 800 || c.CpuSchedulable != 500 || c.MemorySchedulableMb != 8192 || c.GpuTotal != 0 || c.ActiveJobs != 3 {
+		t.Errorf("capacity = %v", c)
+	}
+
+	cfg.Coordination.AdvertiseAddress = "joblet-1.example.com:443"
+	if got := h.NodeInfo().Address; got != "joblet-1.example.com:443" {
+		t.Errorf("address = %q, want the advertised address", got)
+	}
+}
+
+func TestHeartbeater_WildcardAddressUsesHostname(t *testing.T) {
+	cfg := testCoordinationConfig()
+	cfg.Server.Address = "0.0.0.0"
+	cfg.Server.NodeId = ""
+	h := NewHeartbeater(cfg, &recordingRegistrar{}, func() *capacitypb.NodeCapacity { return nil })
+	h.hostname = "worker-7"
+
+	node := h.NodeInfo()
+	if node.Address != "worker-7:50051" || node.NodeId != "worker-7" {
+		t.Errorf("node = %v, want hostname address and ID", node)
+	}
+}
+
+func TestHeartbeater_RunRetriesFailures(t *testing.T) {
+	registrar := &recordingRegistrar{failures: 2}
+	h := NewHeartbeater(testCoordinationConfig(), registrar, func() *capacitypb.NodeCapacity { return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for registrar.count() < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if registrar.count() < 4 {
+		t.Fatalf("heartbeats = %d, want retries after failures", registrar.count())
+	}
+}
@@ -0,0 +1,56 @@
+package coordination
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// StateRegistrar registers the node with the state service API over mutual
+// TLS. The coordination client certificate needs the node role (OU=node) or
+// admin role to send heartbeats.
+type StateRegistrar struct {
+	conn   *grpc.ClientConn
+	client nodespb.NodeRegistryServiceClient
+}
+
+// NewStateRegistrar connects to the state API at the coordination endpoint.
+// The connection is established lazily, so a state service that is not up
+// yet only fails the first heartbeats.
+func NewStateRegistrar(cfg *config.Config) (*StateRegistrar, error) {
+	tlsConfig, err := cfg.GetCoordinationTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("coordination requires TLS: %w", err)
+	}
+
+	conn, err := grpc.NewClient(cfg.Coordination.Endpoint, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to state api %s: %w", cfg.Coordination.Endpoint, err)
+	}
+	return &StateRegistrar{conn: conn, client: nodespb.NewNodeRegistryServiceClient(conn)}, nil
+}
+
+// Heartbeat registers the node or refreshes its registration.
+func (r *StateRegistrar) Heartbeat(ctx context.Context, node *nodespb.NodeInfo) error {
+	_, err := r.client.Heartbeat(ctx, node)
+	return err
+}
+
+// ListNodes returns the live nodes carrying all of the given labels.
+func (r *StateRegistrar) ListNodes(ctx context.Context, labels map[string]string) ([]*nodespb.NodeInfo, error) {
+	resp, err := r.client.ListNodes(ctx, &nodespb.ListNodesRequest{Labels: labels})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Nodes, nil
+}
+
+// Close closes the connection to the state API.
+func (r *StateRegistrar) Close() error {
+	return r.conn.Close()
+}
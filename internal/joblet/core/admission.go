@@ -0,0 +1,237 @@
+//go:build linux
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/admission"
+	"github.com/ehsaniara/joblet/internal/joblet/core/job"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// admissionTick is how often running jobs are charged to their tenants and
+// free slots are filled from the queue
+const admissionTick = time.Second
+
+// dispatcher queues jobs while the node is saturated and starts them as
+// running slots free up, in the order its admission scheduler picks
+type dispatcher struct {
+	queue      admission.Scheduler
+	policy     string
+	maxRunning int
+	capacity   admission.Resources
+	wake       chan struct{}
+}
+
+// newDispatcher returns nil when jobs never queue: admission scheduling is
+// off or the node has no running job limit. An unknown policy falls back to
+// FIFO rather than lifting the limit.
+func newDispatcher(cfg *config.Config, log *logger.Logger) *dispatcher {
+	policy, maxRunning := cfg.AdmissionPolicy()
+	if maxRunning <= 0 {
+		return nil
+	}
+	queue, err := admission.New(policy, cfg)
+	if err != nil {
+		log.Error("scheduling policy unavailable, using fifo", "policy", policy, "error", err)
+		policy, queue = admission.FIFO, admission.NewFIFO()
+	}
+	log.Info("admission scheduling enabled", "policy", policy, "maxRunningJobs", maxRunning)
+	return &dispatcher{
+		queue:      queue,
+		policy:     policy,
+		maxRunning: maxRunning,
+		capacity:   hostCapacity(),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// hostCapacity returns the node's CPU and memory, zero memory when it
+// cannot be read
+func hostCapacity() admission.Resources {
+	capacity := admission.Resources{CPU: int64(runtime.NumCPU()) * 100}
+	var info syscall.Sysinfo_t
+	if err := syscall.Sysinfo(&info); err == nil {
+		capacity.Memory = int64(info.Totalram) * int64(info.Unit)
+	}
+	return capacity
+}
+
+// notify asks the dispatcher to fill free slots now rather than on its next
+// tick. Safe on a nil dispatcher.
+func (d *dispatcher) notify() {
+	if d == nil {
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// remove takes a job out of the queue, reporting whether it was waiting.
+// Safe on a nil dispatcher.
+func (d *dispatcher) remove(jobID string) bool {
+	return d != nil && d.queue.Remove(jobID)
+}
+
+// occupiesSlot reports whether a job holds one of the node's running slots
+func occupiesSlot(job *domain.Job) bool {
+	switch job.Status {
+	case domain.StatusInitializing, domain.StatusRunning, domain.StatusStopping:
+		return true
+	}
+	return false
+}
+
+// nodeState describes the jobs holding a running slot, and the total
+// number of them, for the admission scheduler
+func (j *Joblet) nodeState(now time.Time) (*admission.Node, int) {
+	node := &admission.Node{Now: now, Running: make(map[string]int)}
+	if j.dispatcher != nil {
+		node.Capacity = j.dispatcher.capacity
+	}
+	total := 0
+	for _, jb := range j.store.ListJobs() {
+		if occupiesSlot(jb) {
+			node.Assign(jb)
+			total++
+		}
+	}
+	return node, total
+}
+
+// saturated reports whether a job ready to start has to wait in the queue:
+// every running slot is taken, or other jobs are already waiting for one
+func (j *Joblet) saturated() bool {
+	if j.dispatcher == nil {
+		return false
+	}
+	if j.dispatcher.queue.Size() > 0 {
+		return true
+	}
+	_, total := j.nodeState(time.Now())
+	return total >= j.dispatcher.maxRunning
+}
+
+// enqueue adds a queued job to the admission queue and wakes the dispatcher
+func (j *Joblet) enqueue(jb *domain.Job) {
+	j.dispatcher.queue.Add(jb)
+	j.dispatcher.notify()
+}
+
+// queueJob stores a job that cannot start yet and adds it to the admission
+// queue. Uploads are staged now, as for scheduled jobs.
+func (j *Joblet) queueJob(ctx context.Context, jb *domain.Job, req job.BuildRequest) (*domain.Job, error) {
+	if len(req.Uploads) > 0 {
+		if err := j.resourceManager.PrepareScheduledJobUploads(ctx, jb, req.Uploads); err != nil {
+			return nil, fmt.Errorf("upload preparation failed: %w", err)
+		}
+	}
+
+	jb.Status = domain.StatusQueued
+	jb.AddEvent(domain.JobEventQueued, fmt.Sprintf("all %d running slots taken, %d jobs waiting", j.dispatcher.maxRunning, j.dispatcher.queue.Size()))
+	j.store.CreateNewJob(jb)
+	j.enqueue(jb)
+	j.watchStartDeadline(jb)
+
+	j.logger.Info("node saturated, job queued", "jobID", jb.Uuid, "tenant", jb.Tenant, "policy", j.dispatcher.policy, "queueSize", j.dispatcher.queue.Size())
+	return jb, nil
+}
+
+// requeueScheduledJob moves a due scheduled job into the queue instead of
+// starting it on a saturated node
+func (j *Joblet) requeueScheduledJob(jb *domain.Job) {
+	jb.Status = domain.StatusQueued
+	jb.AddEvent(domain.JobEventQueued, fmt.Sprintf("scheduled job due, %d waiting", j.dispatcher.queue.Size()))
+	j.store.UpdateJob(jb)
+	j.enqueue(jb)
+}
+
+// runDispatcher charges running jobs to their tenants when the scheduler
+// keeps usage, and starts queued jobs while slots are free, until ctx is done
+func (j *Joblet) runDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(admissionTick)
+	defer ticker.Stop()
+
+	charger, charges := j.dispatcher.queue.(admission.Charger)
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if charges {
+				node, _ := j.nodeState(now)
+				elapsed := now.Sub(last)
+				for tenant, count := range node.Running {
+					charger.Charge(tenant, time.Duration(count)*elapsed, now)
+				}
+			}
+			last = now
+		case <-j.dispatcher.wake:
+		}
+		j.dispatchQueuedJobs(ctx)
+	}
+}
+
+// dispatchQueuedJobs starts queued jobs until the running slots are taken
+// or the scheduler holds the rest back. Nothing starts during a maintenance
+// window.
+func (j *Joblet) dispatchQueuedJobs(ctx context.Context) {
+	if _, open := j.maintenance.active(); open {
+		return
+	}
+	node, total := j.nodeState(time.Now())
+	for ; total < j.dispatcher.maxRunning; total++ {
+		next := j.dispatcher.queue.Next(node)
+		if next == nil {
+			return
+		}
+		node.Assign(next)
+		if err := j.startQueuedJob(ctx, next); err != nil {
+			j.logger.Warn("failed to start queued job", "jobID", next.Uuid, "error", err)
+		}
+	}
+}
+
+// startQueuedJob starts a job taken from the queue
+func (j *Joblet) startQueuedJob(ctx context.Context, queued *domain.Job) error {
+	freshJob, exists := j.store.Job(queued.Uuid)
+	if !exists {
+		return fmt.Errorf("job not found: %s", queued.Uuid)
+	}
+	if !freshJob.IsQueued() {
+		return fmt.Errorf("job is not queued (status: %s)", freshJob.Status)
+	}
+	if pastStartDeadline(freshJob, time.Now()) {
+		j.expireJob(freshJob)
+		return nil
+	}
+
+	if err := j.credentials.Inject(ctx, freshJob); err != nil {
+		j.handleExecutionFailure(freshJob)
+		return err
+	}
+
+	freshJob.Status = domain.StatusInitializing
+	j.store.UpdateJob(freshJob)
+
+	// Uploads were staged when the job was queued
+	if _, err := j.executeJob(ctx, freshJob, job.BuildRequest{}); err != nil {
+		// A failed launch already failed the job, a failed resource setup
+		// leaves it initializing and holding a slot
+		if freshJob.Status == domain.StatusInitializing {
+			j.handleExecutionFailure(freshJob)
+		}
+		return err
+	}
+	return nil
+}
@@ -3,16 +3,24 @@
 package cleanup
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/jobfs"
 	"github.com/ehsaniara/joblet/internal/joblet/network"
+	"github.com/ehsaniara/joblet/internal/joblet/orphans"
 
+	"github.com/ehsaniara/joblet/internal/joblet/core/filesystem"
 	"github.com/ehsaniara/joblet/internal/joblet/core/process"
 	"github.com/ehsaniara/joblet/internal/joblet/core/resource"
 	"github.com/ehsaniara/joblet/pkg/config"
@@ -35,6 +43,12 @@ type Coordinator struct {
 
 	networkSetup *network.NetworkSetup
 	networkStore adapters.NetworkStorer
+
+	// Failed jobs armed with freeze-fs keep their filesystem here
+	frozen *jobfs.Store
+
+	// Counts of the cgroups and network resources left by jobs
+	orphans orphans.Tracker
 }
 
 // CleanupStatus tracks the status of a cleanup operation with error collection,
@@ -74,9 +88,96 @@ func NewCoordinator(
 		logger:         logger.WithField("component", "cleanup-coordinator"),
 		networkStore:   networkStore,
 		networkSetup:   networkSetup,
+		frozen:         jobfs.NewStore(config.Filesystem.BaseDir, config.Filesystem.FreezeRetention),
 	}
 }
 
+// ArmFreeze marks a job so its filesystem is kept for browsing if it fails
+func (c *Coordinator) ArmFreeze(jobID string) error {
+	_, err := c.frozen.Arm(jobID, time.Now())
+	return err
+}
+
+// SettleFreeze is called once a job ended, before it is cleaned up. The
+// filesystem of an armed job that failed is frozen so the cleanup leaves it
+// in place; a job that did not fail is disarmed.
+func (c *Coordinator) SettleFreeze(jobID string, failed bool) {
+	log := c.logger.WithField("jobID", jobID)
+	if !failed {
+		if err := c.frozen.Disarm(jobID); err != nil {
+			log.Warn("failed to disarm filesystem freeze", "error", err)
+		}
+		return
+	}
+
+	rec, err := c.frozen.Freeze(jobID, c.jobDirs(jobID), time.Now())
+	if err != nil {
+		log.Error("failed to freeze job filesystem, cleaning it up", "error", err)
+		_ = c.frozen.Disarm(jobID)
+		return
+	}
+	if rec != nil {
+		log.Info("failed job filesystem frozen for inspection", "expiresAt", rec.ExpiresAt, "dirs", rec.Dirs)
+	}
+}
+
+// ReadJobFile reads a regular file of a job that ended, before it is cleaned
+// up. jobPath is the path the job saw; files larger than maxBytes are
+// rejected.
+func (c *Coordinator) ReadJobFile(jobID, jobPath string, maxBytes int64) ([]byte, error) {
+	root, name, err := jobfs.OpenDir(c.jobDirs(jobID), jobPath)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	f, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", jobPath)
+	}
+	if info.Size() > maxBytes {
+		return nil, fmt.Errorf("%s is %d bytes, more than the %d allowed", jobPath, info.Size(), maxBytes)
+	}
+	return io.ReadAll(io.LimitReader(f, maxBytes))
+}
+
+// OpenJobDir opens a directory of a job that ended as a root, before it is
+// cleaned up, so neither ".." nor symlinks the job left lead out of it.
+// jobPath is the path the job saw.
+func (c *Coordinator) OpenJobDir(jobID, jobPath string) (*os.Root, error) {
+	root, name, err := jobfs.OpenDir(c.jobDirs(jobID), jobPath)
+	if err != nil {
+		return nil, err
+	}
+	if name == "." {
+		return root, nil
+	}
+	defer root.Close()
+	return root.OpenRoot(name)
+}
+
+// jobDirs maps the job's mount points to the host directories behind them
+func (c *Coordinator) jobDirs(jobID string) map[string]string {
+	dirs := map[string]string{"/": filepath.Join(c.config.Filesystem.BaseDir, jobID)}
+	if jobTmpDir := strings.Replace(c.config.Filesystem.TmpDir, "{JOB_ID}", jobID, -1); jobTmpDir != c.config.Filesystem.TmpDir {
+		dirs["/tmp"] = jobTmpDir
+	}
+	for _, scratch := range c.config.Filesystem.Scratch {
+		if scratchDir := filesystem.ScratchDir(scratch, jobID); c.platform.DirExists(scratchDir) {
+			dirs["/work"] = scratchDir
+		}
+	}
+	return dirs
+}
+
 // CleanupJob performs all cleanup operations for a job.
 // Main cleanup entry point: handles process termination, cgroup cleanup,
 // filesystem removal, network cleanup with race condition protection.
@@ -283,6 +384,12 @@ func (c *Coordinator) cleanupFilesystem(jobID string) error {
 	log := c.logger.WithField("operation", "filesystem-cleanup")
 	log.Debug("cleaning up filesystem", "jobID", jobID)
 
+	// Frozen filesystems are removed when their retention ends
+	if rec, exists := c.frozen.Record(jobID); exists && rec.Frozen() {
+		log.Debug("keeping frozen job filesystem", "jobID", jobID, "expiresAt", rec.ExpiresAt)
+		return nil
+	}
+
 	errors := make([]error, 0)
 
 	// 1. Clean up main job directory
@@ -312,6 +419,23 @@ func (c *Coordinator) cleanupFilesystem(jobID string) error {
 		log.Debug("workspace directory cleanup", "error", err)
 	}
 
+	// 5. Clean up the work directory on a scratch device and lift its quota
+	for name, scratch := range c.config.Filesystem.Scratch {
+		scratchDir := filesystem.ScratchDir(scratch, jobID)
+		if !c.platform.DirExists(scratchDir) {
+			continue
+		}
+		if err := c.removeDirectory(scratchDir, "scratch "+name); err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if scratch.QuotaBytes > 0 {
+			if err := filesystem.ReleaseScratchQuota(c.platform, scratch, jobID); err != nil {
+				log.Warn("failed to release scratch quota", "scratch", name, "jobID", jobID, "error", err)
+			}
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("filesystem cleanup had %d errors: %v", len(errors), errors)
 	}
@@ -377,17 +501,45 @@ func (c *Coordinator) CleanupOrphanedResources(activeJobIDs map[string]bool) err
 		return fmt.Errorf("failed to read job base directory: %w", err)
 	}
 
+	// Scratch devices can hold work directories whose job directory is gone;
+	// only job IDs count there, the device may hold lost+found and the like
+	for name, scratch := range c.config.Filesystem.Scratch {
+		scratchEntries, err := c.platform.ReadDir(scratch.Path)
+		if err != nil {
+			log.Debug("failed to read scratch directory", "scratch", name, "error", err)
+			continue
+		}
+		for _, entry := range scratchEntries {
+			if orphans.IsJobID(entry.Name()) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	c.sweepFrozen(activeJobIDs, time.Now())
+	c.reconcileOrphans(activeJobIDs)
+
+	seen := make(map[string]bool, len(entries))
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || seen[entry.Name()] {
 			continue
 		}
 
 		jobID := entry.Name()
+		seen[jobID] = true
 
-		// Skip if job is active
+		// Skip internal directories such as the skeleton pool
+		if strings.HasPrefix(jobID, ".") {
+			continue
+		}
+
+		// Skip if job is active or its filesystem is frozen
 		if activeJobIDs[jobID] {
 			continue
 		}
+		if rec, exists := c.frozen.Record(jobID); exists && rec.Frozen() {
+			continue
+		}
 
 		// Skip if cleanup is in progress
 		if _, cleaning := c.activeCleanups.Load(jobID); cleaning {
@@ -416,6 +568,91 @@ func (c *Coordinator) CleanupOrphanedResources(activeJobIDs map[string]bool) err
 	return nil
 }
 
+// sweepFrozen removes the frozen filesystems whose retention ended, lifting
+// their scratch quota, and drops the arming of jobs that are gone
+func (c *Coordinator) sweepFrozen(activeJobIDs map[string]bool, now time.Time) {
+	log := c.logger.WithField("operation", "frozen-sweep")
+	for _, rec := range c.frozen.Records() {
+		switch {
+		case !rec.Frozen():
+			if !activeJobIDs[rec.JobID] {
+				_ = c.frozen.Disarm(rec.JobID)
+			}
+		case now.After(rec.ExpiresAt):
+			if err := c.frozen.Remove(rec.JobID); err != nil {
+				log.Warn("failed to remove expired frozen filesystem", "jobID", rec.JobID, "error", err)
+				continue
+			}
+			for name, scratch := range c.config.Filesystem.Scratch {
+				if rec.Dirs["/work"] == filesystem.ScratchDir(scratch, rec.JobID) && scratch.QuotaBytes > 0 {
+					if err := filesystem.ReleaseScratchQuota(c.platform, scratch, rec.JobID); err != nil {
+						log.Warn("failed to release scratch quota", "scratch", name, "jobID", rec.JobID, "error", err)
+					}
+				}
+			}
+			log.Info("expired frozen filesystem removed", "jobID", rec.JobID, "frozenAt", rec.FrozenAt)
+		}
+	}
+}
+
+// reconcileOrphans removes the cgroups, veth pairs and network namespaces
+// of jobs that are neither known nor being cleaned up, and records the pass
+func (c *Coordinator) reconcileOrphans(activeJobIDs map[string]bool) {
+	log := c.logger.WithField("operation", "orphan-reconcile")
+
+	known := make(map[string]bool, len(activeJobIDs))
+	for jobID := range activeJobIDs {
+		known[jobID] = true
+	}
+	c.activeCleanups.Range(func(jobID, _ any) bool {
+		known[jobID.(string)] = true
+		return true
+	})
+
+	found := orphans.Find(orphans.DefaultPaths(c.config.Cgroup.JobsDir()), known)
+	var failed []orphans.Resource
+	for _, r := range found {
+		if err := c.removeOrphan(r); err != nil {
+			log.Warn("failed to remove orphaned resource", "kind", r.Kind, "name", r.Name, "error", err)
+			failed = append(failed, r)
+			continue
+		}
+		log.Info("orphaned resource removed", "kind", r.Kind, "name", r.Name, "jobID", r.JobID)
+	}
+	c.orphans.Record(time.Now(), found, failed)
+}
+
+// removeOrphan removes a resource left by a job
+func (c *Coordinator) removeOrphan(r orphans.Resource) error {
+	switch r.Kind {
+	case orphans.KindCgroup:
+		// Kills the processes left in it before removing it
+		c.cgroup.CleanupCgroup(r.JobID)
+	case orphans.KindVeth:
+		cmd := c.platform.CreateCommand("ip", "link", "delete", r.Name)
+		var output bytes.Buffer
+		cmd.SetStdout(&output)
+		cmd.SetStderr(&output)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(output.String()))
+		}
+	case orphans.KindNetns:
+		if err := c.platform.Unmount(r.Path, syscall.MNT_DETACH); err != nil && !errors.Is(err, syscall.EINVAL) {
+			return fmt.Errorf("failed to unmount: %w", err)
+		}
+		if err := c.platform.Remove(r.Path); err != nil && !c.platform.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrphanedResources returns the counts of the orphaned resources reconciled
+// since joblet started
+func (c *Coordinator) OrphanedResources() orphans.Stats {
+	return c.orphans.Stats()
+}
+
 // SchedulePeriodicCleanup starts a periodic cleanup routine
 func (c *Coordinator) SchedulePeriodicCleanup(ctx context.Context, interval time.Duration, getActiveJobs func() map[string]bool) {
 	ticker := time.NewTicker(interval)
@@ -0,0 +1,205 @@
+//go:build linux
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/core/execution"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	joberrors "github.com/ehsaniara/joblet/pkg/errors"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+// containerRuntimeFailure is the exit status podman and docker run report
+// when the container could not be run at all
+const containerRuntimeFailure = 125
+
+// containerBackend is the experimental execution backend running each job in
+// a container of a container runtime such as podman, for hosts lacking the
+// kernel prerequisites of the namespace backend. The runtime isolates the
+// job; its work directory is bind-mounted at /work.
+type containerBackend struct {
+	config   config.ContainerBackendConfig
+	baseDir  string
+	platform platform.Platform
+	store    adapters.JobStorer
+	logger   *logger.Logger
+}
+
+func newContainerBackend(cfg *config.Config, platform platform.Platform, store adapters.JobStorer, logger *logger.Logger) *containerBackend {
+	return &containerBackend{
+		config:   cfg.Execution.Container,
+		baseDir:  cfg.Filesystem.BaseDir,
+		platform: platform,
+		store:    store,
+		logger:   logger.WithField("component", "container-backend"),
+	}
+}
+
+// StartJob implements execution.JobExecutor
+func (cb *containerBackend) StartJob(ctx context.Context, opts *execution.StartProcessOptions) (platform.Command, error) {
+	job := opts.Job
+	log := cb.logger.WithField("jobID", job.Uuid)
+	if err := containerSupports(job); err != nil {
+		return nil, err
+	}
+
+	workDir := filepath.Join(cb.baseDir, job.Uuid, "work")
+	if err := cb.platform.MkdirAll(workDir, 0755); err != nil {
+		return nil, joberrors.WrapInfrastructureError("filesystem", fmt.Errorf("failed to create work directory: %w", err))
+	}
+	if err := writeUploads(workDir, opts.Uploads); err != nil {
+		return nil, joberrors.WrapInfrastructureError("filesystem", err)
+	}
+
+	// Values are passed through the environment of the runtime rather than
+	// its arguments, where any user of the host could read the secrets
+	environment := cb.platform.Environ()
+	for _, env := range []map[string]string{job.Environment, job.SecretEnvironment} {
+		for key, value := range env {
+			environment = append(environment, key+"="+value)
+		}
+	}
+
+	outputWriter := NewWrite(cb.store, job.Uuid)
+	cmd := cb.platform.CreateCommand(cb.config.Runtime, containerRunArgs(job, cb.config, workDir)...)
+	cmd.SetEnv(environment)
+	cmd.SetStdout(outputWriter)
+	cmd.SetStderr(outputWriter)
+
+	log.Info("starting job container", "runtime", cb.config.Runtime, "image", cb.config.Image)
+	if err := cmd.Start(); err != nil {
+		return nil, joberrors.WrapInfrastructureError("container", fmt.Errorf("failed to start %s: %w", cb.config.Runtime, err))
+	}
+	return &containerCommand{Command: cmd, remove: func() { cb.removeContainer(job.Uuid) }}, nil
+}
+
+// StopJob implements execution.JobExecutor, removing the job's container in
+// case the runtime process was killed before it could
+func (cb *containerBackend) StopJob(ctx context.Context, jobID string) error {
+	cb.removeContainer(jobID)
+	return nil
+}
+
+func (cb *containerBackend) removeContainer(jobID string) {
+	cmd := cb.platform.CreateCommand(cb.config.Runtime, "rm", "--force", "--ignore", containerName(jobID))
+	if err := cmd.Run(); err != nil {
+		cb.logger.Debug("removing job container", "jobID", jobID, "error", err)
+	}
+}
+
+// containerSupports refuses jobs needing what only the namespace backend
+// provides
+func containerSupports(job *domain.Job) error {
+	var missing string
+	switch {
+	case job.IsRuntimeBuild():
+		missing = "runtime builds"
+	case job.Runtime != "":
+		missing = "runtimes"
+	case len(job.Volumes) > 0:
+		missing = "volumes"
+	case job.GPUCount > 0:
+		missing = "GPUs"
+	case job.Limits.HasIOLimit():
+		missing = "IO bandwidth limits"
+	case job.Scratch != "":
+		missing = "scratch devices"
+	case len(job.Egress) > 0:
+		missing = "egress policies"
+	case job.Network != "" && job.Network != "bridge" && job.Network != "none":
+		missing = "network " + job.Network
+	case len(job.SecretFiles) > 0:
+		missing = "secret files"
+	case len(job.CgroupParams) > 0:
+		missing = "cgroup parameters"
+	case job.StdinPath != "":
+		missing = "stdin uploads"
+	case job.IPCChannel != "":
+		missing = "IPC channels"
+	case job.ShmSizeBytes > 0 || job.TmpSizeBytes > 0:
+		missing = "/dev/shm and /tmp sizes"
+	case job.Profile != "":
+		missing = "profilers"
+	case job.WorkingDirectory != "" && filepath.Clean(job.WorkingDirectory) != "/work":
+		missing = "working directories other than /work"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s not supported by the container execution backend of this node", missing)
+}
+
+// containerRunArgs returns the arguments running a job in a container: its
+// limits, network and environment variable names, the extra arguments
+// configured, then the image and the job's command
+func containerRunArgs(job *domain.Job, cfg config.ContainerBackendConfig, workDir string) []string {
+	args := []string{
+		"run", "--rm",
+		"--name", containerName(job.Uuid),
+		"--label", "joblet.job=" + job.Uuid,
+		"--volume", workDir + ":/work",
+		"--workdir", "/work",
+	}
+	if job.Network == "none" {
+		args = append(args, "--network", "none")
+	}
+	if !job.Limits.CPU.IsUnlimited() {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(job.Limits.CPU.Cores()), 'f', -1, 32))
+	}
+	if !job.Limits.Memory.IsUnlimited() {
+		args = append(args, "--memory", strconv.FormatInt(job.Limits.Memory.Bytes(), 10))
+	}
+	if !job.Limits.CPUCores.IsEmpty() {
+		args = append(args, "--cpuset-cpus", job.Limits.CPUCores.String())
+	}
+
+	// Named only: the runtime takes the values from its own environment
+	var names []string
+	for _, env := range []map[string]string{job.Environment, job.SecretEnvironment} {
+		for key := range env {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--env", name)
+	}
+
+	args = append(args, cfg.ExtraArgs...)
+	args = append(args, cfg.Image, job.Command)
+	return append(args, job.Args...)
+}
+
+func containerName(jobID string) string {
+	return "joblet-" + jobID
+}
+
+// containerCommand is the runtime process of a job's container. The
+// container is removed once the process exits, even when it was killed
+// before it could remove the container itself.
+type containerCommand struct {
+	platform.Command
+	remove func()
+}
+
+// Wait waits for the runtime process, reporting a container that could not
+// be run as an infrastructure failure rather than the job's own
+func (c *containerCommand) Wait() error {
+	err := c.Command.Wait()
+	c.remove()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == containerRuntimeFailure {
+		return joberrors.WrapInfrastructureError("container", err)
+	}
+	return err
+}
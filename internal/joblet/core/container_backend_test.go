@@ -0,0 +1,97 @@
+//go:build linux
+
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+func TestContainerRunArgs(t *testing.T) {
+	cpu, _ := values.NewCPUPercentage(150)
+	memory, _ := values.NewMemorySizeFromMB(512)
+	cores, _ := values.ParseCPUCoreSet("0-1")
+	job := &domain.Job{
+		Uuid:              "7f3e2d1c",
+		Command:           "python3",
+		Args:              []string{"train.py", "--epochs=3"},
+		Network:           "none",
+		Limits:            domain.ResourceLimits{CPU: cpu, Memory: memory, CPUCores: cores},
+		Environment:       map[string]string{"MODE": "fast"},
+		SecretEnvironment: map[string]string{"API_KEY": "s3cret"},
+	}
+	cfg := config.ContainerBackendConfig{
+		Runtime:   "podman",
+		Image:     "docker.io/library/python:3.12",
+		ExtraArgs: []string{"--security-opt", "label=disable"},
+	}
+
+	got := containerRunArgs(job, cfg, "/opt/joblet/jobs/7f3e2d1c/work")
+	want := []string{
+		"run", "--rm",
+		"--name", "joblet-7f3e2d1c",
+		"--label", "joblet.job=7f3e2d1c",
+		"--volume", "/opt/joblet/jobs/7f3e2d1c/work:/work",
+		"--workdir", "/work",
+		"--network", "none",
+		"--cpus", "1.5",
+		"--memory", "536870912",
+		"--cpuset-cpus", "0-1",
+		"--env", "API_KEY",
+		"--env", "MODE",
+		"--security-opt", "label=disable",
+		"docker.io/library/python:3.12", "python3", "train.py", "--epochs=3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("containerRunArgs() =\n%q\nwant\n%q", got, want)
+	}
+	if strings.Contains(strings.Join(got, " "), "s3cret") {
+		t.Error("secret value in the runtime's arguments")
+	}
+}
+
+func TestContainerSupports(t *testing.T) {
+	ioLimit, _ := values.NewBandwidth(10 * 1024 * 1024)
+	tests := []struct {
+		name string
+		job  domain.Job
+		want string
+	}{
+		{name: "plain job", job: domain.Job{Network: "bridge"}},
+		{name: "no network", job: domain.Job{Network: "none"}},
+		{name: "runtime", job: domain.Job{Runtime: "python-3.11"}, want: "runtimes"},
+		{name: "volume", job: domain.Job{Volumes: []string{"data"}}, want: "volumes"},
+		{name: "GPU", job: domain.Job{GPUCount: 1}, want: "GPUs"},
+		{name: "IO limit", job: domain.Job{Limits: domain.ResourceLimits{IOBandwidth: ioLimit}}, want: "IO bandwidth limits"},
+		{name: "custom network", job: domain.Job{Network: "backend"}, want: "network backend"},
+		{name: "runtime build", job: domain.Job{Type: domain.JobTypeRuntimeBuild}, want: "runtime builds"},
+		{name: "secret file", job: domain.Job{SecretFiles: map[string]string{"/run/secrets/key": "KEY"}}, want: "secret files"},
+		{name: "cgroup parameter", job: domain.Job{CgroupParams: map[string]string{"cpu.weight": "50"}}, want: "cgroup parameters"},
+		{name: "stdin", job: domain.Job{StdinPath: "input.txt"}, want: "stdin uploads"},
+		{name: "IPC channel", job: domain.Job{IPCChannel: "pipeline"}, want: "IPC channels"},
+		{name: "shm size", job: domain.Job{ShmSizeBytes: 64 << 20}, want: "/dev/shm and /tmp sizes"},
+		{name: "tmp size", job: domain.Job{TmpSizeBytes: 64 << 20}, want: "/dev/shm and /tmp sizes"},
+		{name: "profiler", job: domain.Job{Profile: "strace"}, want: "profilers"},
+		{name: "default working directory", job: domain.Job{WorkingDirectory: "/work/"}},
+		{name: "working directory", job: domain.Job{WorkingDirectory: "/src"}, want: "working directories other than /work"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := containerSupports(&tt.job)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("containerSupports() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("containerSupports() error = %v, want it to name %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Package credentials mints short-lived cloud credentials for the jobs of a
+// tenant, so users don't have to pass long-lived keys as secret environment
+// variables.
+//
+// Joblet doesn't talk to AWS itself. A configured helper assumes the tenant's
+// role with the node's own credentials (instance profile, IRSA, ...) and
+// prints the temporary credentials, which are added to the job's secret
+// environment when the job starts.
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// Environment variables set in the job, as read by the AWS CLI and SDKs
+const (
+	EnvAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	EnvSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	EnvSessionToken    = "AWS_SESSION_TOKEN"
+	EnvExpiration      = "AWS_CREDENTIAL_EXPIRATION"
+)
+
+// defaultDuration is the credential lifetime when a role doesn't set one
+const defaultDuration = time.Hour
+
+// maxHelperOutput caps how much helper error output ends up in the job error
+const maxHelperOutput = 1024
+
+// Credentials are temporary AWS credentials
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+// Broker mints credentials for the tenants that have an AWS role
+type Broker struct {
+	cfg    *config.Config
+	logger *logger.Logger
+}
+
+// NewBroker creates a credential broker, or returns nil when no tenant has an
+// AWS role
+func NewBroker(cfg *config.Config, logger *logger.Logger) *Broker {
+	for _, tenant := range cfg.Tenants {
+		if tenant.AWSRole.RoleARN != "" {
+			return &Broker{cfg: cfg, logger: logger.WithField("component", "cloud-credentials")}
+		}
+	}
+	return nil
+}
+
+// Inject mints credentials for job's tenant and adds them to the job's secret
+// environment, replacing any AWS keys the job was submitted with. Jobs of
+// tenants without an AWS role are left alone.
+func (b *Broker) Inject(ctx context.Context, job *domain.Job) error {
+	if b == nil || job.Tenant == "" {
+		return nil
+	}
+	tenant, ok := b.cfg.Tenant(job.Tenant)
+	if !ok || tenant.AWSRole.RoleARN == "" {
+		return nil
+	}
+
+	creds, err := b.Mint(ctx, tenant, job.Uuid)
+	if err != nil {
+		return fmt.Errorf("failed to mint credentials for tenant %s: %w", tenant.Name, err)
+	}
+
+	if job.SecretEnvironment == nil {
+		job.SecretEnvironment = make(map[string]string)
+	}
+	if job.Environment == nil {
+		job.Environment = make(map[string]string)
+	}
+	if _, ok := job.SecretEnvironment[EnvAccessKeyID]; ok {
+		b.logger.Warn("replacing AWS keys submitted with the job by delegated credentials", "jobId", job.Uuid, "tenant", tenant.Name)
+	}
+	job.SecretEnvironment[EnvAccessKeyID] = creds.AccessKeyID
+	job.SecretEnvironment[EnvSecretAccessKey] = creds.SecretAccessKey
+	job.SecretEnvironment[EnvSessionToken] = creds.SessionToken
+	if !creds.Expiration.IsZero() {
+		job.Environment[EnvExpiration] = creds.Expiration.UTC().Format(time.RFC3339)
+	}
+
+	b.logger.Info("delegated credentials issued", "jobId", job.Uuid, "tenant", tenant.Name,
+		"role", tenant.AWSRole.RoleARN, "expires", creds.Expiration)
+	return nil
+}
+
+// Mint runs the credentials helper for tenant's role. The session is named
+// after the job so CloudTrail shows which job made a call.
+func (b *Broker) Mint(ctx context.Context, tenant config.TenantConfig, jobID string) (*Credentials, error) {
+	duration := tenant.AWSRole.Duration
+	if duration <= 0 {
+		duration = defaultDuration
+	}
+	timeout := b.cfg.CloudCredentials.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	helperCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(helperCtx, b.cfg.CloudCredentials.Command, b.cfg.CloudCredentials.Args...)
+	cmd.Env = append(os.Environ(),
+		"JOBLET_TENANT="+tenant.Name,
+		"JOBLET_ROLE_ARN="+tenant.AWSRole.RoleARN,
+		"JOBLET_ROLE_SESSION_NAME="+sessionName(jobID),
+		"JOBLET_DURATION_SECONDS="+strconv.Itoa(int(duration.Seconds())),
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if helperCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("credentials helper timed out after %s", timeout)
+		}
+		return nil, fmt.Errorf("credentials helper failed: %w: %s", err, helperOutput(stderr.Bytes()))
+	}
+	return parseCredentials(stdout.Bytes())
+}
+
+// helperOutputJSON is the helper's output, in the 'aws sts assume-role'
+// format with the credentials under "Credentials", or in the
+// credential_process format with them at the top level.
+type helperOutputJSON struct {
+	Credentials *credentialsJSON `json:"Credentials"`
+	credentialsJSON
+}
+
+type credentialsJSON struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+// parseCredentials parses the helper's output
+func parseCredentials(output []byte) (*Credentials, error) {
+	var parsed helperOutputJSON
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("credentials helper printed invalid JSON: %w", err)
+	}
+	c := parsed.credentialsJSON
+	if parsed.Credentials != nil {
+		c = *parsed.Credentials
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" || c.SessionToken == "" {
+		return nil, fmt.Errorf("credentials helper output lacks AccessKeyId, SecretAccessKey or SessionToken")
+	}
+
+	creds := &Credentials{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+	}
+	if c.Expiration != "" {
+		expiration, err := time.Parse(time.RFC3339, c.Expiration)
+		if err != nil {
+			return nil, fmt.Errorf("credentials helper printed invalid Expiration: %w", err)
+		}
+		creds.Expiration = expiration
+	}
+	return creds, nil
+}
+
+// sessionName names the role session after the job; STS allows up to 64
+// characters
+func sessionName(jobID string) string {
+	name := "joblet-" + jobID
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// helperOutput trims the helper's error output for the job error
+func helperOutput(output []byte) string {
+	text := strings.TrimSpace(string(output))
+	if len(text) > maxHelperOutput {
+		text = text[:maxHelperOutput] + "..."
+	}
+	if text == "" {
+		return "no output"
+	}
+	return text
+}
@@ -0,0 +1,127 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// writeHelper writes a shell script credentials helper
+func writeHelper(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "assume-role.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write helper: %v", err)
+	}
+	return path
+}
+
+func newTestBroker(t *testing.T, helper string) *Broker {
+	t.Helper()
+	cfg := &config.Config{
+		Tenants: []config.TenantConfig{
+			{Name: "analytics", Clients: []string{"analytics-ci"}, AWSRole: config.AWSRoleConfig{
+				RoleARN:  "arn:aws:iam::123456789012:role/analytics",
+				Duration: 15 * time.Minute,
+			}},
+			{Name: "docs", Clients: []string{"docs-ci"}},
+		},
+		CloudCredentials: config.CloudCredentialsConfig{Command: helper, Timeout: 5 * time.Second},
+	}
+	broker := NewBroker(cfg, logger.New())
+	if broker == nil {
+		t.Fatal("NewBroker() = nil, want a broker for a tenant with an AWS role")
+	}
+	return broker
+}
+
+func TestInjectAssumeRoleOutput(t *testing.T) {
+	// Echoes what it was asked for back in the credentials, in 'aws sts assume-role' format
+	helper := writeHelper(t, `cat <<EOF
+{"Credentials": {"AccessKeyId": "ASIA-$JOBLET_TENANT", "SecretAccessKey": "secret-$JOBLET_DURATION_SECONDS",
+ "SessionToken": "$JOBLET_ROLE_SESSION_NAME", "Expiration": "2026-01-02T03:04:05Z"}}
+EOF`)
+	job := &domain.Job{
+		Uuid:              "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		Tenant:            "analytics",
+		SecretEnvironment: map[string]string{EnvAccessKeyID: "AKIA-long-lived"},
+	}
+
+	if err := newTestBroker(t, helper).Inject(context.Background(), job); err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+
+	want := map[string]string{
+		EnvAccessKeyID:     "ASIA-analytics",
+		EnvSecretAccessKey: "secret-900",
+		EnvSessionToken:    "joblet-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}
+	for key, value := range want {
+		if got := job.SecretEnvironment[key]; got != value {
+			t.Errorf("SecretEnvironment[%s] = %q, want %q", key, got, value)
+		}
+	}
+	if got := job.Environment[EnvExpiration]; got != "2026-01-02T03:04:05Z" {
+		t.Errorf("Environment[%s] = %q", EnvExpiration, got)
+	}
+}
+
+func TestInjectCredentialProcessOutput(t *testing.T) {
+	helper := writeHelper(t, `echo '{"Version": 1, "AccessKeyId": "ASIAKEY", "SecretAccessKey": "s3cr3t", "SessionToken": "token"}'`)
+	job := &domain.Job{Uuid: "job-1", Tenant: "analytics"}
+
+	if err := newTestBroker(t, helper).Inject(context.Background(), job); err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if job.SecretEnvironment[EnvAccessKeyID] != "ASIAKEY" {
+		t.Errorf("SecretEnvironment[%s] = %q, want ASIAKEY", EnvAccessKeyID, job.SecretEnvironment[EnvAccessKeyID])
+	}
+	if _, ok := job.Environment[EnvExpiration]; ok {
+		t.Errorf("%s set without an expiration from the helper", EnvExpiration)
+	}
+}
+
+func TestInjectSkipsJobsWithoutRole(t *testing.T) {
+	broker := newTestBroker(t, writeHelper(t, "exit 1"))
+	for _, tenant := range []string{"", "docs", "unknown"} {
+		job := &domain.Job{Uuid: "job-1", Tenant: tenant}
+		if err := broker.Inject(context.Background(), job); err != nil {
+			t.Errorf("Inject() for tenant %q error = %v", tenant, err)
+		}
+		if len(job.SecretEnvironment) != 0 {
+			t.Errorf("Inject() for tenant %q set %v", tenant, job.SecretEnvironment)
+		}
+	}
+}
+
+func TestInjectHelperFailure(t *testing.T) {
+	broker := newTestBroker(t, writeHelper(t, `echo "AccessDenied: not authorized to perform sts:AssumeRole" >&2; exit 254`))
+	err := broker.Inject(context.Background(), &domain.Job{Uuid: "job-1", Tenant: "analytics"})
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("Inject() error = %v, want the helper's error output", err)
+	}
+}
+
+func TestParseCredentialsIncomplete(t *testing.T) {
+	if _, err := parseCredentials([]byte(`{"AccessKeyId": "ASIAKEY"}`)); err == nil {
+		t.Fatal("parseCredentials() accepted credentials without secret and token")
+	}
+}
+
+func TestNewBrokerWithoutRoles(t *testing.T) {
+	cfg := &config.Config{Tenants: []config.TenantConfig{{Name: "docs", Clients: []string{"docs-ci"}}}}
+	broker := NewBroker(cfg, logger.New())
+	if broker != nil {
+		t.Fatalf("NewBroker() = %v, want nil without AWS roles", broker)
+	}
+	if err := broker.Inject(context.Background(), &domain.Job{Tenant: "docs"}); err != nil {
+		t.Fatalf("nil Broker.Inject() error = %v", err)
+	}
+}
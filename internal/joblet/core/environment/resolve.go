@@ -0,0 +1,88 @@
+package environment
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPath is the job PATH when no layer sets one
+const DefaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// PathPrepend is the runtime.yml environment key whose directories go in front
+// of the PATH instead of replacing it
+const PathPrepend = "PATH_PREPEND"
+
+// Resolve merges KEY=VALUE environment layers into one environment without
+// duplicate keys. A later layer overrides an earlier one, so callers pass the
+// most general layer first (server, runtime, job). PATH is computed: the last
+// PATH set (DefaultPath when none), with the PATH_PREPEND directories of
+// every layer in front, the latest layer's first, and repeated or empty
+// entries dropped. Keys keep the order they first appeared in.
+func Resolve(layers ...[]string) []string {
+	values := make(map[string]string)
+	var keys []string
+	var prepends []string
+	path := ""
+
+	for _, layer := range layers {
+		for _, entry := range layer {
+			key, value, ok := strings.Cut(entry, "=")
+			if !ok || key == "" {
+				continue
+			}
+			switch key {
+			case PathPrepend:
+				prepends = append([]string{value}, prepends...)
+				continue
+			case "PATH":
+				path = value
+			}
+			if _, seen := values[key]; !seen {
+				keys = append(keys, key)
+			}
+			values[key] = value
+		}
+	}
+
+	if path == "" {
+		path = DefaultPath
+	}
+	if _, seen := values["PATH"]; !seen {
+		keys = append(keys, "PATH")
+	}
+	values["PATH"] = joinPath(append(prepends, path)...)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, key+"="+values[key])
+	}
+	return env
+}
+
+// Lookup returns the value of key in a KEY=VALUE environment, the last one
+// when it is set more than once
+func Lookup(env []string, key string) (string, bool) {
+	value, found := "", false
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			value, found = v, true
+		}
+	}
+	return value, found
+}
+
+// joinPath joins PATH lists, keeping the first of repeated directories
+func joinPath(lists ...string) string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, list := range lists {
+		for _, dir := range filepath.SplitList(list) {
+			if dir == "" || seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return strings.Join(dirs, string(filepath.ListSeparator))
+}
@@ -0,0 +1,77 @@
+package environment
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers [][]string
+		want   []string
+	}{
+		{
+			name:   "no PATH anywhere",
+			layers: [][]string{{"HOME=/work"}},
+			want:   []string{"HOME=/work", "PATH=" + DefaultPath},
+		},
+		{
+			name: "later layer overrides",
+			layers: [][]string{
+				{"PATH=/usr/bin:/bin", "LANG=C"},
+				{"JAVA_HOME=/opt/java"},
+				{"LANG=en_US.UTF-8", "JAVA_HOME=/usr/lib/jvm"},
+			},
+			want: []string{"PATH=/usr/bin:/bin", "LANG=en_US.UTF-8", "JAVA_HOME=/usr/lib/jvm"},
+		},
+		{
+			name: "runtime prepend keeps the server PATH",
+			layers: [][]string{
+				{"PATH=/usr/local/bin:/usr/bin:/bin"},
+				{"PATH_PREPEND=/opt/python/bin:/usr/local/bin", "PYTHON_HOME=/opt/python"},
+			},
+			want: []string{"PATH=/opt/python/bin:/usr/local/bin:/usr/bin:/bin", "PYTHON_HOME=/opt/python"},
+		},
+		{
+			name: "job PATH keeps the runtime prepend",
+			layers: [][]string{
+				{"PATH=/usr/bin:/bin"},
+				{"PATH_PREPEND=/opt/node/bin"},
+				{"PATH=/work/bin::/usr/bin"},
+			},
+			want: []string{"PATH=/opt/node/bin:/work/bin:/usr/bin"},
+		},
+		{
+			name: "latest prepend goes first",
+			layers: [][]string{
+				{"PATH_PREPEND=/opt/a"},
+				{"PATH_PREPEND=/opt/b"},
+			},
+			want: []string{"PATH=/opt/b:/opt/a:" + DefaultPath},
+		},
+		{
+			name:   "malformed entries are skipped",
+			layers: [][]string{{"NOEQUALS", "=value", "EMPTY="}},
+			want:   []string{"EMPTY=", "PATH=" + DefaultPath},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resolve(tt.layers...); !slices.Equal(got, tt.want) {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	env := []string{"PATH=/bin", "HOME=/work", "PATH=/usr/bin"}
+	if value, ok := Lookup(env, "PATH"); !ok || value != "/usr/bin" {
+		t.Errorf("Lookup(PATH) = %q, %v", value, ok)
+	}
+	if _, ok := Lookup(env, "LANG"); ok {
+		t.Error("Lookup(LANG) found an unset key")
+	}
+}
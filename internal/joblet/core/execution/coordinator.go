@@ -6,10 +6,15 @@ import (
 	"strings"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/errors"
 	"github.com/ehsaniara/joblet/pkg/logger"
 	"github.com/ehsaniara/joblet/pkg/platform"
 )
 
+// DefaultInitPath is the binary jobs start as their init process unless
+// SetInitPath names another
+const DefaultInitPath = "/opt/joblet/bin/joblet"
+
 // ExecutionCoordinator coordinates different execution services.
 // Replaces monolithic ExecutionEngine with focused coordinator that orchestrates
 // environment setup, networking, process management, isolation, and GPU management for job execution.
@@ -21,6 +26,7 @@ type ExecutionCoordinator struct {
 	gpuManager         GPUManager
 	platform           platform.Platform
 	logger             *logger.Logger
+	initPath           string
 }
 
 // NewExecutionCoordinator creates a new execution coordinator.
@@ -43,6 +49,15 @@ func NewExecutionCoordinator(
 		gpuManager:         gpuManager,
 		platform:           platform,
 		logger:             logger.WithField("component", "execution-coordinator"),
+		initPath:           DefaultInitPath,
+	}
+}
+
+// SetInitPath sets the binary jobs start as their init process, such as the
+// minimal joblet-init. An empty path keeps DefaultInitPath.
+func (ec *ExecutionCoordinator) SetInitPath(path string) {
+	if path != "" {
+		ec.initPath = path
 	}
 }
 
@@ -62,7 +77,7 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 		_, err = ec.isolationManager.CreateIsolatedEnvironment(opts.Job.Uuid)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create isolated environment: %w", err)
+		return nil, errors.WrapInfrastructureError("filesystem", fmt.Errorf("failed to create isolated environment: %w", err))
 	}
 
 	// 2. Setup workspace and process uploads
@@ -71,7 +86,7 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 		if destroyErr := ec.isolationManager.DestroyIsolatedEnvironment(opts.Job.Uuid); destroyErr != nil {
 			log.Warn("failed to destroy isolated environment during cleanup", "error", destroyErr)
 		}
-		return nil, fmt.Errorf("failed to prepare workspace: %w", err)
+		return nil, errors.WrapInfrastructureError("filesystem", fmt.Errorf("failed to prepare workspace: %w", err))
 	}
 
 	// 3. Allocate GPU resources if needed
@@ -93,7 +108,7 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 				log.Error("failed to setup GPU environment", "error", err)
 				ec.cleanup(opts.Job.Uuid, workspaceDir)
 				ec.cleanupGPU(ctx, opts.Job.Uuid, gpuAllocation)
-				return nil, fmt.Errorf("failed to setup GPU environment: %w", err)
+				return nil, errors.WrapInfrastructureError("gpu", fmt.Errorf("failed to setup GPU environment: %w", err))
 			}
 		} else {
 			log.Warn("job requested GPUs but none were allocated (GPU support may be disabled)")
@@ -109,7 +124,7 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 		if err != nil {
 			ec.cleanup(opts.Job.Uuid, workspaceDir)
 			ec.cleanupGPU(ctx, opts.Job.Uuid, gpuAllocation)
-			return nil, fmt.Errorf("failed to setup networking: %w", err)
+			return nil, errors.WrapInfrastructureError("network", fmt.Errorf("failed to setup networking: %w", err))
 		}
 		log.Info("networking setup completed", "allocation", networkAlloc != nil)
 	} else {
@@ -119,10 +134,10 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 	// 5. Build environment
 	environment := ec.environmentManager.BuildEnvironment(opts.Job, "execute")
 
-	// 6. Always use joblet binary as init for unified pub/sub logging
-	// The joblet binary runs in init mode, sets up runtime environment, then exec's to the actual command
+	// 6. Always use the joblet init binary for unified pub/sub logging
+	// It runs in init mode, sets up runtime environment, then exec's to the actual command
 	// This ensures all jobs (runtime and default) use the same logging mechanism
-	initPath := "/opt/joblet/bin/joblet"
+	initPath := ec.initPath
 	log.Debug("using joblet binary as init for namespace isolation and unified logging", "initPath", initPath)
 
 	// 7. Create network ready file for coordination if networking is enabled
@@ -156,7 +171,7 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 			}
 		}
 		ec.cleanupGPU(ctx, opts.Job.Uuid, gpuAllocation)
-		return nil, fmt.Errorf("failed to launch process: %w", err)
+		return nil, errors.WrapInfrastructureError("launch", fmt.Errorf("failed to launch process: %w", err))
 	}
 
 	// Phase 2: Configure network namespace now that we have the PID
@@ -169,12 +184,24 @@ func (ec *ExecutionCoordinator) StartJob(ctx context.Context, opts *StartProcess
 			log.Info("network namespace configured successfully")
 		}
 
+		// The egress policy must be in place before the job is released
+		if len(opts.Job.Egress) > 0 {
+			if err := ec.networkManager.ApplyEgressPolicy(ctx, opts.Job.Uuid, result.PID, opts.Job.Egress); err != nil {
+				log.Error("failed to apply egress policy", "error", err)
+				result.Command.Kill()
+				_ = result.Command.Wait()
+				return nil, errors.WrapInfrastructureError("network", fmt.Errorf("failed to apply egress policy: %w", err))
+			}
+		}
+
 		// Signal network ready to job process by creating the signal file
 		if networkReadyFile != "" {
 			log.Debug("signaling network ready to job process", "file", networkReadyFile)
 			if err := ec.platform.WriteFile(networkReadyFile, []byte("ready"), 0644); err != nil {
 				log.Error("failed to create network ready signal file", "error", err)
-				return nil, fmt.Errorf("failed to create network ready signal file: %w", err)
+				result.Command.Kill()
+				_ = result.Command.Wait()
+				return nil, errors.WrapInfrastructureError("network", fmt.Errorf("failed to create network ready signal file: %w", err))
 			}
 			log.Debug("network ready signal file created successfully")
 		}
@@ -86,6 +86,33 @@ func TestExecutionCoordinator_StartJob_DefaultRuntime(t *testing.T) {
 	}
 }
 
+func TestExecutionCoordinator_StartJob_InitBinary(t *testing.T) {
+	envManager := &executionfakes.FakeEnvironmentManager{}
+	processManager := &executionfakes.FakeProcessManager{}
+	envManager.PrepareWorkspaceReturns("/test/workspace", nil)
+	processManager.LaunchProcessReturns(&execution.ProcessResult{Command: &platformfakes.FakeCommand{}, PID: 12345}, nil)
+
+	coordinator := execution.NewExecutionCoordinator(
+		envManager,
+		&executionfakes.FakeNetworkManager{},
+		processManager,
+		&executionfakes.FakeIsolationManager{},
+		&executionfakes.FakeGPUManager{},
+		&platformfakes.FakePlatform{},
+		logger.New(),
+	)
+	coordinator.SetInitPath("")
+	coordinator.SetInitPath("/opt/joblet/bin/joblet-init")
+
+	opts := &execution.StartProcessOptions{Job: &domain.Job{Uuid: "test-job-123", Command: "true"}}
+	if _, err := coordinator.StartJob(context.Background(), opts); err != nil {
+		t.Fatalf("StartJob: %v", err)
+	}
+	if _, launchConfig := processManager.LaunchProcessArgsForCall(0); launchConfig.InitPath != "/opt/joblet/bin/joblet-init" {
+		t.Errorf("Expected the configured init binary, got '%s'", launchConfig.InitPath)
+	}
+}
+
 func TestExecutionCoordinator_StartJob_RuntimeJob(t *testing.T) {
 	envManager := &executionfakes.FakeEnvironmentManager{}
 	networkManager := &executionfakes.FakeNetworkManager{}
@@ -246,6 +273,52 @@ func TestExecutionCoordinator_StartJob_WithNetworking(t *testing.T) {
 	}
 }
 
+func TestExecutionCoordinator_StartJob_EgressPolicy(t *testing.T) {
+	envManager := &executionfakes.FakeEnvironmentManager{}
+	networkManager := &executionfakes.FakeNetworkManager{}
+	processManager := &executionfakes.FakeProcessManager{}
+	fakePlatform := &platformfakes.FakePlatform{}
+	envManager.PrepareWorkspaceReturns("/test/workspace", nil)
+	networkManager.SetupNetworkingReturns(&execution.NetworkAllocation{JobID: "test-job-123", Network: "bridge"}, nil)
+	mockCmd := &platformfakes.FakeCommand{}
+	processManager.LaunchProcessReturns(&execution.ProcessResult{Command: mockCmd, PID: 12345}, nil)
+
+	coordinator := execution.NewExecutionCoordinator(
+		envManager,
+		networkManager,
+		processManager,
+		&executionfakes.FakeIsolationManager{},
+		&executionfakes.FakeGPUManager{},
+		fakePlatform,
+		logger.New(),
+	)
+	job := &domain.Job{Uuid: "test-job-123", Command: "curl", Network: "bridge", Egress: []string{"allow:api.internal:443", "deny:*"}}
+
+	if _, err := coordinator.StartJob(context.Background(), &execution.StartProcessOptions{Job: job}); err != nil {
+		t.Fatalf("StartJob: %v", err)
+	}
+	if networkManager.ApplyEgressPolicyCallCount() != 1 {
+		t.Fatalf("Expected ApplyEgressPolicy to be called once, got %d", networkManager.ApplyEgressPolicyCallCount())
+	}
+	if _, jobID, pid, rules := networkManager.ApplyEgressPolicyArgsForCall(0); jobID != "test-job-123" || pid != 12345 || len(rules) != 2 {
+		t.Errorf("ApplyEgressPolicy(%s, %d, %v)", jobID, pid, rules)
+	}
+	if fakePlatform.WriteFileCallCount() != 1 {
+		t.Errorf("Expected the network ready file to be written, got %d writes", fakePlatform.WriteFileCallCount())
+	}
+
+	// A job whose policy fails to load never gets released
+	networkManager.ApplyEgressPolicyReturns(errors.New("nft: command not found"))
+	_, err := coordinator.StartJob(context.Background(), &execution.StartProcessOptions{Job: job})
+	if err == nil || !strings.Contains(err.Error(), "egress policy") {
+		t.Errorf("Expected an egress policy error, got %v", err)
+	}
+	if mockCmd.KillCallCount() != 1 || fakePlatform.WriteFileCallCount() != 1 {
+		t.Errorf("Expected the job killed before the network ready file, got %d kills and %d writes",
+			mockCmd.KillCallCount(), fakePlatform.WriteFileCallCount())
+	}
+}
+
 func TestExecutionCoordinator_StartJob_IsolationCreationFails(t *testing.T) {
 	envManager := &executionfakes.FakeEnvironmentManager{}
 	networkManager := &executionfakes.FakeNetworkManager{}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ehsaniara/joblet/internal/joblet/core/environment"
@@ -77,33 +78,88 @@ func (es *EnvironmentService) BuildEnvironment(job *domain.Job, phase string) []
 		jobEnv = append(jobEnv, fmt.Sprintf("JOB_RUNTIME=%s", job.Runtime))
 	}
 
-	// Combine all environment variables
-	env := append(baseEnv, jobEnv...)
+	if job.ShmSizeBytes > 0 {
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_SHM_SIZE=%d", job.ShmSizeBytes))
+	}
+
+	if job.IPCChannel != "" {
+		if dir, _, err := es.config.JobIPCChannel(job.Tenant, job.IPCChannel); err == nil {
+			jobEnv = append(jobEnv, fmt.Sprintf("JOB_IPC_DIR=%s", dir))
+		}
+	}
+
+	if job.TmpSizeBytes > 0 {
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_TMP_SIZE=%d", job.TmpSizeBytes))
+	}
+
+	if job.Scratch != "" {
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_SCRATCH=%s", job.Scratch))
+	}
 
+	if job.FreezeFS {
+		jobEnv = append(jobEnv, "JOB_FREEZE_FS=true")
+	}
+
+	if job.OutputsFile != "" {
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_OUTPUTS_FILE=%s", job.OutputsFile))
+	}
+
+	if job.Profile != "" {
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_PROFILE=%s", job.Profile))
+	}
+
+	if job.StdinPath != "" {
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_STDIN=%s", job.StdinPath))
+	}
+
+	// Secret variables delivered as files reach the init only, which mounts
+	// them and drops them before running the command
+	if len(job.SecretFiles) > 0 {
+		paths := make([]string, 0, len(job.SecretFiles))
+		for path := range job.SecretFiles {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		jobEnv = append(jobEnv, fmt.Sprintf("JOB_SECRET_FILES_COUNT=%d", len(paths)))
+		for i, path := range paths {
+			jobEnv = append(jobEnv,
+				fmt.Sprintf("JOB_SECRET_FILE_%d=%s", i, path),
+				fmt.Sprintf("JOB_SECRET_FILE_%d_DATA=%s", i, job.SecretEnvironment[job.SecretFiles[path]]))
+		}
+	}
+
+	var runtimeEnv []string
 	if job.Runtime != "" {
-		runtimeEnv, err := es.getRuntimeEnvironment(job.Runtime)
+		var err error
+		runtimeEnv, err = es.getRuntimeEnvironment(job.Runtime)
 		if err != nil {
 			es.logger.Warn("failed to load runtime environment", "runtime", job.Runtime, "error", err)
-		} else {
-			env = append(env, runtimeEnv...)
 		}
 	}
 
 	// Add GPU environment variables if GPUs are allocated
+	var gpuEnv []string
 	if job.HasGPURequirement() && job.IsGPUAllocated() {
-		gpuEnv := es.buildGPUEnvironment(job)
-		env = append(env, gpuEnv...)
+		gpuEnv = es.buildGPUEnvironment(job)
 	}
 
+	var userEnv []string
 	for key, value := range job.Environment {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
+		userEnv = append(userEnv, fmt.Sprintf("%s=%s", key, value))
+	}
+	asFiles := make(map[string]bool, len(job.SecretFiles))
+	for _, key := range job.SecretFiles {
+		asFiles[key] = true
 	}
-
 	for key, value := range job.SecretEnvironment {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
+		if !asFiles[key] {
+			userEnv = append(userEnv, fmt.Sprintf("%s=%s", key, value))
+		}
 	}
 
-	return env
+	// The job's own variables override the runtime's, which override the
+	// server's; the init variables come last so a job cannot change them
+	return environment.Resolve(baseEnv, runtimeEnv, gpuEnv, userEnv, jobEnv)
 }
 
 // PrepareWorkspace prepares the workspace and processes uploads
@@ -335,20 +391,11 @@ func (es *EnvironmentService) getRuntimeEnvironment(runtimeSpec string) ([]strin
 		}
 	}
 
-	// Convert to environment variable format
+	// Convert to environment variable format, PATH_PREPEND is applied by
+	// environment.Resolve
 	var envVars []string
 	for key, value := range environmentVars {
-		// Handle special PATH_PREPEND by prepending to existing PATH
-		if key == "PATH_PREPEND" {
-			currentPath := os.Getenv("PATH")
-			if currentPath != "" {
-				envVars = append(envVars, fmt.Sprintf("PATH=%s:%s", value, currentPath))
-			} else {
-				envVars = append(envVars, fmt.Sprintf("PATH=%s", value))
-			}
-		} else {
-			envVars = append(envVars, fmt.Sprintf("%s=%s", key, value))
-		}
+		envVars = append(envVars, fmt.Sprintf("%s=%s", key, value))
 	}
 
 	es.logger.Debug("loaded runtime environment variables", "runtime", runtimeSpec, "count", len(envVars), "vars", envVars)
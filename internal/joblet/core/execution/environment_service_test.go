@@ -0,0 +1,92 @@
+package execution_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/environment"
+	"github.com/ehsaniara/joblet/internal/joblet/core/execution"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform/platformfakes"
+)
+
+func TestEnvironmentService_BuildEnvironmentLayers(t *testing.T) {
+	fakePlatform := &platformfakes.FakePlatform{}
+	fakePlatform.EnvironReturns([]string{"PATH=/usr/bin:/bin", "LANG=C", "HOME=/root"})
+	fakePlatform.ReadFileReturns([]byte(`name: openjdk-21
+environment:
+  JAVA_HOME: "/usr/lib/jvm"
+  PATH_PREPEND: "/usr/lib/jvm/bin"
+`), nil)
+
+	cfg := &config.Config{}
+	cfg.Runtime.BasePath = "/opt/joblet/runtimes"
+	service := execution.NewEnvironmentService(nil, nil, fakePlatform, cfg, logger.New())
+
+	job := &domain.Job{
+		Uuid:        "job-1",
+		Command:     "java",
+		Runtime:     "openjdk-21",
+		Environment: map[string]string{"LANG": "en_US.UTF-8", "JOB_ID": "spoofed"},
+	}
+	env := service.BuildEnvironment(job, "execute")
+
+	expect := map[string]string{
+		"PATH":      "/usr/lib/jvm/bin:/usr/bin:/bin",
+		"LANG":      "en_US.UTF-8",
+		"JAVA_HOME": "/usr/lib/jvm",
+		"HOME":      "/root",
+		"JOB_ID":    "job-1",
+	}
+	for key, want := range expect {
+		if got, _ := environment.Lookup(env, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	for _, key := range []string{"PATH", "LANG", "JOB_ID"} {
+		count := 0
+		for _, entry := range env {
+			if strings.HasPrefix(entry, key+"=") {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Errorf("%s is set %d times", key, count)
+		}
+	}
+	if slices.ContainsFunc(env, func(entry string) bool { return strings.HasPrefix(entry, "PATH_PREPEND=") }) {
+		t.Error("PATH_PREPEND reached the job environment")
+	}
+}
+
+func TestEnvironmentService_BuildEnvironmentSecretFiles(t *testing.T) {
+	fakePlatform := &platformfakes.FakePlatform{}
+	fakePlatform.EnvironReturns([]string{"PATH=/usr/bin:/bin"})
+	service := execution.NewEnvironmentService(nil, nil, fakePlatform, &config.Config{}, logger.New())
+
+	job := &domain.Job{
+		Uuid:              "job-1",
+		Command:           "aws",
+		SecretEnvironment: map[string]string{"AWS_CREDENTIALS": "[default]\nkey=abc", "API_TOKEN": "t0k3n"},
+		SecretFiles:       map[string]string{"/root/.aws/credentials": "AWS_CREDENTIALS"},
+	}
+	env := service.BuildEnvironment(job, "execute")
+
+	expect := map[string]string{
+		"API_TOKEN":              "t0k3n",
+		"JOB_SECRET_FILES_COUNT": "1",
+		"JOB_SECRET_FILE_0":      "/root/.aws/credentials",
+		"JOB_SECRET_FILE_0_DATA": "[default]\nkey=abc",
+	}
+	for key, want := range expect {
+		if got, _ := environment.Lookup(env, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if _, found := environment.Lookup(env, "AWS_CREDENTIALS"); found {
+		t.Error("the secret delivered as a file is in the environment")
+	}
+}
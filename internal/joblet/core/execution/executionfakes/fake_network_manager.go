@@ -9,6 +9,20 @@ import (
 )
 
 type FakeNetworkManager struct {
+	ApplyEgressPolicyStub        func(context.Context, string, int, []string) error
+	applyEgressPolicyMutex       sync.RWMutex
+	applyEgressPolicyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 []string
+	}
+	applyEgressPolicyReturns struct {
+		result1 error
+	}
+	applyEgressPolicyReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CleanupNetworkingStub        func(context.Context, string) error
 	cleanupNetworkingMutex       sync.RWMutex
 	cleanupNetworkingArgsForCall []struct {
@@ -53,6 +67,75 @@ type FakeNetworkManager struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeNetworkManager) ApplyEgressPolicy(arg1 context.Context, arg2 string, arg3 int, arg4 []string) error {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.applyEgressPolicyMutex.Lock()
+	ret, specificReturn := fake.applyEgressPolicyReturnsOnCall[len(fake.applyEgressPolicyArgsForCall)]
+	fake.applyEgressPolicyArgsForCall = append(fake.applyEgressPolicyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 []string
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.ApplyEgressPolicyStub
+	fakeReturns := fake.applyEgressPolicyReturns
+	fake.recordInvocation("ApplyEgressPolicy", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.applyEgressPolicyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeNetworkManager) ApplyEgressPolicyCallCount() int {
+	fake.applyEgressPolicyMutex.RLock()
+	defer fake.applyEgressPolicyMutex.RUnlock()
+	return len(fake.applyEgressPolicyArgsForCall)
+}
+
+func (fake *FakeNetworkManager) ApplyEgressPolicyCalls(stub func(context.Context, string, int, []string) error) {
+	fake.applyEgressPolicyMutex.Lock()
+	defer fake.applyEgressPolicyMutex.Unlock()
+	fake.ApplyEgressPolicyStub = stub
+}
+
+func (fake *FakeNetworkManager) ApplyEgressPolicyArgsForCall(i int) (context.Context, string, int, []string) {
+	fake.applyEgressPolicyMutex.RLock()
+	defer fake.applyEgressPolicyMutex.RUnlock()
+	argsForCall := fake.applyEgressPolicyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeNetworkManager) ApplyEgressPolicyReturns(result1 error) {
+	fake.applyEgressPolicyMutex.Lock()
+	defer fake.applyEgressPolicyMutex.Unlock()
+	fake.ApplyEgressPolicyStub = nil
+	fake.applyEgressPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeNetworkManager) ApplyEgressPolicyReturnsOnCall(i int, result1 error) {
+	fake.applyEgressPolicyMutex.Lock()
+	defer fake.applyEgressPolicyMutex.Unlock()
+	fake.ApplyEgressPolicyStub = nil
+	if fake.applyEgressPolicyReturnsOnCall == nil {
+		fake.applyEgressPolicyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.applyEgressPolicyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeNetworkManager) CleanupNetworking(arg1 context.Context, arg2 string) error {
 	fake.cleanupNetworkingMutex.Lock()
 	ret, specificReturn := fake.cleanupNetworkingReturnsOnCall[len(fake.cleanupNetworkingArgsForCall)]
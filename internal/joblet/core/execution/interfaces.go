@@ -10,7 +10,9 @@ import (
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
-// JobExecutor handles the core job execution logic
+// JobExecutor handles the core job execution logic. It is the node's
+// execution backend: ExecutionCoordinator isolates jobs with namespaces and a
+// chroot, an experimental backend hands them to a container runtime.
 //
 //counterfeiter:generate . JobExecutor
 type JobExecutor interface {
@@ -35,6 +37,7 @@ type EnvironmentManager interface {
 type NetworkManager interface {
 	SetupNetworking(ctx context.Context, jobID, networkName string) (*NetworkAllocation, error)
 	ConfigureNetworkNamespace(ctx context.Context, jobID string, pid int) error
+	ApplyEgressPolicy(ctx context.Context, jobID string, pid int, rules []string) error
 	CleanupNetworking(ctx context.Context, jobID string) error
 }
 
@@ -6,6 +6,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
 	"github.com/ehsaniara/joblet/internal/joblet/network"
 	"github.com/ehsaniara/joblet/pkg/logger"
 )
@@ -148,6 +149,25 @@ func (ns *NetworkService) ConfigureNetworkNamespace(ctx context.Context, jobID s
 	return nil
 }
 
+// ApplyEgressPolicy restricts the destinations a job's process may connect
+// to, with the canonical egress rules of the job
+func (ns *NetworkService) ApplyEgressPolicy(ctx context.Context, jobID string, pid int, rules []string) error {
+	policy := make([]values.EgressRule, 0, len(rules))
+	for _, spec := range rules {
+		rule, err := values.ParseEgressRule(spec)
+		if err != nil {
+			return err
+		}
+		policy = append(policy, rule)
+	}
+
+	if err := ns.networkSetup.ApplyEgressPolicy(ctx, pid, policy); err != nil {
+		return err
+	}
+	ns.logger.Info("egress policy applied", "jobID", jobID, "policy", values.FormatEgressPolicy(policy))
+	return nil
+}
+
 // CleanupNetworking cleans up networking for a job
 func (ns *NetworkService) CleanupNetworking(ctx context.Context, jobID string) error {
 	log := ns.logger.WithField("jobID", jobID)
@@ -0,0 +1,133 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+// chrootProfiles are the host paths holding the commands and shared libraries
+// of each distro family, keyed by the names of config.ChrootProfiles. Debian
+// keeps libraries in multiarch directories under /lib and /usr/lib, the RHEL
+// and SUSE families in /usr/lib64, and both resolve some commands through
+// /etc/alternatives. Paths missing on the host are skipped when mounting.
+var chrootProfiles = map[string][]string{
+	"debian":  {"/bin", "/usr/bin", "/lib", "/lib64", "/usr/lib", "/etc/alternatives"},
+	"rhel":    {"/bin", "/usr/bin", "/lib", "/lib64", "/usr/lib", "/usr/lib64", "/etc/alternatives"},
+	"suse":    {"/bin", "/usr/bin", "/lib", "/lib64", "/usr/lib", "/usr/lib64", "/etc/alternatives"},
+	"alpine":  {"/bin", "/usr/bin", "/lib", "/usr/lib"},
+	"arch":    {"/bin", "/usr/bin", "/lib", "/lib64", "/usr/lib"},
+	"generic": {"/bin", "/usr/bin", "/lib", "/lib64", "/usr/lib", "/usr/lib64", "/etc/alternatives"},
+}
+
+// distroProfiles maps os-release ID and ID_LIKE values to their family's profile
+var distroProfiles = map[string]string{
+	"debian":              "debian",
+	"ubuntu":              "debian",
+	"linuxmint":           "debian",
+	"pop":                 "debian",
+	"raspbian":            "debian",
+	"rhel":                "rhel",
+	"fedora":              "rhel",
+	"centos":              "rhel",
+	"rocky":               "rhel",
+	"almalinux":           "rhel",
+	"ol":                  "rhel",
+	"amzn":                "rhel",
+	"suse":                "suse",
+	"sles":                "suse",
+	"opensuse":            "suse",
+	"opensuse-leap":       "suse",
+	"opensuse-tumbleweed": "suse",
+	"alpine":              "alpine",
+	"arch":                "arch",
+	"manjaro":             "arch",
+	"endeavouros":         "arch",
+}
+
+// osReleasePaths are read in order, as systemd does
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// DetectChrootProfile returns the profile of the host's distro family, from
+// the ID of os-release and then its ID_LIKE values, or "generic" when the
+// distro is unknown or os-release cannot be read
+func DetectChrootProfile(p platform.Platform) string {
+	release := ReadOSRelease(p)
+	ids := append([]string{release["ID"]}, strings.Fields(release["ID_LIKE"])...)
+	for _, id := range ids {
+		if profile, ok := distroProfiles[strings.ToLower(id)]; ok {
+			return profile
+		}
+	}
+	return "generic"
+}
+
+// ReadOSRelease returns the KEY=value pairs of the host's os-release file,
+// or nil when there is none
+func ReadOSRelease(p platform.Platform) map[string]string {
+	for _, path := range osReleasePaths {
+		if data, err := p.ReadFile(path); err == nil {
+			return parseOSRelease(string(data))
+		}
+	}
+	return nil
+}
+
+// ResolveChrootProfile returns the profile filesystem.chrootProfile selects,
+// detecting the host's for auto, or empty for none
+func ResolveChrootProfile(cfg *config.Config, p platform.Platform) string {
+	switch profile := cfg.Filesystem.ChrootProfile; profile {
+	case "", config.ChrootProfileAuto:
+		return DetectChrootProfile(p)
+	case config.ChrootProfileNone:
+		return ""
+	default:
+		return profile
+	}
+}
+
+// RuntimeChrootProfile returns the profile of a job using rt: the one its
+// runtime.yml names, for runtimes built on another distro's libraries, or
+// the host's
+func RuntimeChrootProfile(host string, rt *runtime.RuntimeConfig) string {
+	if rt == nil {
+		return host
+	}
+	switch rt.ChrootProfile {
+	case "", config.ChrootProfileAuto:
+		return host
+	case config.ChrootProfileNone:
+		return ""
+	default:
+		return rt.ChrootProfile
+	}
+}
+
+// ChrootMounts returns the allowed mounts followed by the paths of profile
+// they leave out
+func ChrootMounts(allowed []string, profile string) []string {
+	mounts := slices.Clone(allowed)
+	for _, path := range chrootProfiles[profile] {
+		if !slices.ContainsFunc(mounts, func(m string) bool { return filepath.Clean(m) == path }) {
+			mounts = append(mounts, path)
+		}
+	}
+	return mounts
+}
+
+// parseOSRelease reads the KEY=value lines of an os-release file
+func parseOSRelease(data string) map[string]string {
+	release := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		release[key] = strings.Trim(value, `"'`)
+	}
+	return release
+}
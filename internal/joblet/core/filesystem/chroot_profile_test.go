@@ -0,0 +1,105 @@
+package filesystem
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform/platformfakes"
+)
+
+func TestChrootProfilesCoverConfig(t *testing.T) {
+	for _, name := range config.ChrootProfiles {
+		if len(chrootProfiles[name]) == 0 {
+			t.Errorf("config names chroot profile %q without mounts", name)
+		}
+	}
+	for name := range chrootProfiles {
+		if !config.IsChrootProfile(name) {
+			t.Errorf("chroot profile %q cannot be configured", name)
+		}
+	}
+	for id, profile := range distroProfiles {
+		if _, ok := chrootProfiles[profile]; !ok {
+			t.Errorf("distro %q maps to unknown profile %q", id, profile)
+		}
+	}
+}
+
+func TestDetectChrootProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		osRelease map[string]string // Path -> content, missing paths fail to read
+		want      string
+	}{
+		{"ubuntu", map[string]string{"/etc/os-release": "NAME=\"Ubuntu\"\nID=ubuntu\nID_LIKE=debian\n"}, "debian"},
+		{"rocky", map[string]string{"/etc/os-release": "ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\n"}, "rhel"},
+		{"derivative by ID_LIKE", map[string]string{"/etc/os-release": "ID=mydistro\nID_LIKE=\"suse opensuse\"\n"}, "suse"},
+		{"usr lib fallback", map[string]string{"/usr/lib/os-release": "ID=alpine\n"}, "alpine"},
+		{"unknown distro", map[string]string{"/etc/os-release": "# comment\nID=gentoo\n"}, "generic"},
+		{"no os-release", nil, "generic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &platformfakes.FakePlatform{}
+			fake.ReadFileStub = func(path string) ([]byte, error) {
+				if content, ok := tt.osRelease[path]; ok {
+					return []byte(content), nil
+				}
+				return nil, errors.New("no such file")
+			}
+			if got := DetectChrootProfile(fake); got != tt.want {
+				t.Errorf("DetectChrootProfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveChrootProfile(t *testing.T) {
+	fake := &platformfakes.FakePlatform{}
+	fake.ReadFileReturns([]byte("ID=arch\n"), nil)
+	for setting, want := range map[string]string{
+		"":                       "arch",
+		config.ChrootProfileAuto: "arch",
+		config.ChrootProfileNone: "",
+		"rhel":                   "rhel",
+	} {
+		cfg := &config.Config{Filesystem: config.FilesystemConfig{ChrootProfile: setting}}
+		if got := ResolveChrootProfile(cfg, fake); got != want {
+			t.Errorf("ResolveChrootProfile(%q) = %q, want %q", setting, got, want)
+		}
+	}
+}
+
+func TestRuntimeChrootProfile(t *testing.T) {
+	for override, want := range map[string]string{
+		"":                       "debian",
+		config.ChrootProfileAuto: "debian",
+		config.ChrootProfileNone: "",
+		"rhel":                   "rhel",
+	} {
+		if got := RuntimeChrootProfile("debian", &runtime.RuntimeConfig{ChrootProfile: override}); got != want {
+			t.Errorf("RuntimeChrootProfile(debian, %q) = %q, want %q", override, got, want)
+		}
+	}
+	if got := RuntimeChrootProfile("debian", nil); got != "debian" {
+		t.Errorf("RuntimeChrootProfile(debian, nil) = %q, want debian", got)
+	}
+}
+
+func TestChrootMounts(t *testing.T) {
+	allowed := []string{"/usr/bin", "/bin/", "/opt/tools"}
+	got := ChrootMounts(allowed, "rhel")
+	want := []string{"/usr/bin", "/bin/", "/opt/tools", "/lib", "/lib64", "/usr/lib", "/usr/lib64", "/etc/alternatives"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ChrootMounts(rhel) = %v, want %v", got, want)
+	}
+	if got := ChrootMounts(allowed, ""); !slices.Equal(got, allowed) {
+		t.Errorf("ChrootMounts without profile = %v, want the allowed mounts", got)
+	}
+	if allowed[1] != "/bin/" || len(allowed) != 3 {
+		t.Errorf("ChrootMounts modified the allowed mounts: %v", allowed)
+	}
+}
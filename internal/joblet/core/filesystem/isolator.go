@@ -19,9 +19,10 @@ import (
 )
 
 type Isolator struct {
-	platform platform.Platform
-	config   *config.Config
-	logger   *logger.Logger
+	platform      platform.Platform
+	config        *config.Config
+	chrootProfile string // Distro profile of the host, detected once (empty = none)
+	logger        *logger.Logger
 }
 
 // NewIsolator creates a new filesystem isolator with the given configuration.
@@ -29,11 +30,20 @@ type Isolator struct {
 // chroot, bind mounts, and namespace isolation techniques.
 // Returns an Isolator instance ready to create isolated job filesystems.
 func NewIsolator(cfg *config.Config, platform platform.Platform) *Isolator {
-	return &Isolator{
-		platform: platform,
-		config:   cfg,
-		logger:   logger.New().WithField("component", "filesystem-isolator"),
-	}
+	i := &Isolator{
+		platform:      platform,
+		config:        cfg,
+		chrootProfile: ResolveChrootProfile(cfg, platform),
+		logger:        logger.New().WithField("component", "filesystem-isolator"),
+	}
+	i.logger.Debug("chroot profile selected", "setting", cfg.Filesystem.ChrootProfile, "profile", i.chrootProfile)
+	return i
+}
+
+// ChrootProfile returns the distro profile jobs are mounted with, unless
+// their runtime overrides it, or empty when profiles are disabled
+func (i *Isolator) ChrootProfile() string {
+	return i.chrootProfile
 }
 
 // JobFilesystem represents an isolated filesystem for a job
@@ -42,57 +52,25 @@ type JobFilesystem struct {
 	RootDir       string
 	TmpDir        string
 	WorkDir       string
-	InitPath      string            // Path to the init binary inside the isolated environment
 	Volumes       []string          // Volume names to mount
 	Runtime       string            // Runtime specification
 	RuntimePath   string            // Path to runtime base directory
 	RuntimeConfig interface{}       // Runtime configuration data
 	RuntimeEnv    map[string]string // Runtime environment variables from runtime.yml
 	IsBuilder     bool              // True for runtime build jobs requiring full host filesystem access
+	ShmSize       int64             // Size of the /dev/shm tmpfs in bytes (0 = no /dev/shm mount)
+	IPCDir        string            // Host directory of the IPC channel mounted at /dev/shm instead (empty = none)
+	TmpSize       int64             // Size of the /tmp tmpfs in bytes (0 = host-backed bind mount)
+	Scratch       string            // Scratch device holding the work directory (empty = default work directory)
+	FreezeFS      bool              // Work directory stays on the host so it can be kept if the job fails
+	OutputsFile   string            // Workflow outputs document the host reads from the work directory when the job ends
+	SecretFiles   []SecretFile      // Secret variables mounted as files from a tmpfs
+	chrootProfile string            // Distro profile of the host (empty = none)
 	platform      platform.Platform
 	config        *config.Config
 	logger        *logger.Logger
 }
 
-// PrepareInitBinary copies the joblet init binary into the isolated filesystem.
-// Creates /sbin directory in the chroot environment and copies the host binary
-// to /sbin/init with executable permissions. This binary will be executed as
-// PID 1 inside the isolated environment to manage the job process.
-// Returns error if directory creation or binary copying fails.
-func (f *JobFilesystem) PrepareInitBinary(hostBinaryPath string) error {
-	log := f.logger.WithField("operation", "prepare-init-binary")
-
-	// Create /sbin directory in the isolated root
-	sbinDir := filepath.Join(f.RootDir, "sbin")
-	if err := f.platform.MkdirAll(sbinDir, 0755); err != nil {
-		return fmt.Errorf("failed to create sbin directory: %w", err)
-	}
-
-	// Set the init path that will be used inside the chroot
-	f.InitPath = "/sbin/init"
-
-	// Copy the binary to the isolated filesystem
-	destPath := filepath.Join(f.RootDir, "sbin", "init")
-
-	// Read the host binary
-	data, err := f.platform.ReadFile(hostBinaryPath)
-	if err != nil {
-		return fmt.Errorf("failed to read host binary: %w", err)
-	}
-
-	// Write to the isolated location
-	if err := f.platform.WriteFile(destPath, data, 0755); err != nil {
-		return fmt.Errorf("failed to write init binary: %w", err)
-	}
-
-	log.Debug("init binary prepared in isolated filesystem",
-		"hostPath", hostBinaryPath,
-		"isolatedPath", destPath,
-		"chrootPath", f.InitPath)
-
-	return nil
-}
-
 // CreateJobFilesystem creates a new isolated filesystem environment for a job.
 // Sets up the directory structure needed for job execution:
 //   - Job root directory under configured base path
@@ -125,13 +103,14 @@ func (i *Isolator) CreateJobFilesystem(jobID string) (*JobFilesystem, error) {
 	}
 
 	filesystem := &JobFilesystem{
-		JobID:    jobID,
-		RootDir:  jobRootDir,
-		TmpDir:   jobTmpDir,
-		WorkDir:  jobWorkDir,
-		platform: i.platform,
-		config:   i.config,
-		logger:   log,
+		JobID:         jobID,
+		RootDir:       jobRootDir,
+		TmpDir:        jobTmpDir,
+		WorkDir:       jobWorkDir,
+		chrootProfile: i.chrootProfile,
+		platform:      i.platform,
+		config:        i.config,
+		logger:        log,
 	}
 
 	log.Debug("job filesystem structure created",
@@ -320,17 +299,19 @@ options ndots:0
 //
 //  5. Loads and mounts job volumes
 //
-//  6. Sets up limited work directory (1MB) if no volumes
+//  6. Places the work directory on the selected scratch device, or sets up
+//     a limited work directory (1MB) if no volumes, the filesystem is not
+//     to be kept when the job fails and no outputs file is read from it
 //
 //  7. Mounts upload pipes directory
 //
-//  8. Sets up isolated /tmp directory
+//  8. Sets up isolated /tmp directory (tmpfs when a size is requested)
 //
-//  9. Performs chroot to isolated environment
+//  9. Mounts a sized /dev/shm tmpfs
 //
-//  10. Mounts essential filesystems (/proc, /dev)
+//  10. Performs chroot to isolated environment
 //
-//  10. Mounts essential filesystems (/proc, /dev)
+//  11. Mounts essential filesystems (/proc, /dev)
 //
 // Returns error if any step fails - job cannot proceed without proper isolation.
 func (f *JobFilesystem) Setup() error {
@@ -353,14 +334,15 @@ func (f *JobFilesystem) Setup() error {
 		return fmt.Errorf("failed to create essential files: %w", err)
 	}
 
+	// Load runtime information from environment, its runtime.yml may
+	// override the chroot profile
+	f.loadRuntimeFromEnvironment()
+
 	// Mount allowed read-only directories from host FIRST (default minimal chroot)
 	if err := f.mountAllowedDirs(); err != nil {
 		return fmt.Errorf("failed to mount allowed directories: %w", err)
 	}
 
-	// Load runtime information from environment
-	f.loadRuntimeFromEnvironment()
-
 	// Mount runtime AFTER allowed directories to overlay runtime-specific files
 	// This allows runtime files to override/extend the default minimal chroot
 	f.logger.Debug("about to mount runtime", "runtime", f.Runtime)
@@ -394,7 +376,19 @@ func (f *JobFilesystem) Setup() error {
 		log.Debug("work directory contains uploaded files, skipping tmpfs mount", "fileCount", len(files))
 	}
 
-	if len(f.Volumes) == 0 && !workDirHasFiles {
+	f.Scratch = f.platform.Getenv("JOB_SCRATCH")
+	// The limited work directory is a tmpfs that goes away with the job's
+	// mount namespace, so it would leave nothing to freeze or to read
+	// outputs from
+	f.FreezeFS = f.platform.Getenv("JOB_FREEZE_FS") == "true"
+	f.OutputsFile = f.platform.Getenv("JOB_OUTPUTS_FILE")
+	if f.Scratch != "" {
+		// The job asked for a disk-backed work directory; without it the job
+		// would fill RAM or the root volume instead, so do not fall back
+		if err := f.setupScratchWorkDir(); err != nil {
+			return fmt.Errorf("failed to setup scratch work directory: %w", err)
+		}
+	} else if len(f.Volumes) == 0 && !workDirHasFiles && !f.FreezeFS && f.OutputsFile == "" {
 		if err := f.setupLimitedWorkDir(); err != nil {
 			log.Warn("failed to setup limited work directory, using unlimited work dir", "error", err)
 			// Ensure work directory is still accessible
@@ -416,11 +410,26 @@ func (f *JobFilesystem) Setup() error {
 		// Don't return error - continue without upload support
 	}
 
+	// Load /dev/shm and /tmp sizing from environment
+	f.loadSizingFromEnvironment()
+	f.IPCDir = f.platform.Getenv("JOB_IPC_DIR")
+
 	// Setup /tmp as isolated writable space
 	if err := f.setupTmpDir(); err != nil {
 		return fmt.Errorf("failed to setup tmp directory: %w", err)
 	}
 
+	// Mount /dev/shm for shared memory users (e.g. PyTorch dataloaders)
+	if err := f.setupShm(); err != nil {
+		return fmt.Errorf("failed to setup /dev/shm: %w", err)
+	}
+
+	// Mount the secret files last, over the work directory, volumes and /tmp
+	f.loadSecretFilesFromEnvironment()
+	if err := f.setupSecretFiles(); err != nil {
+		return fmt.Errorf("failed to setup secret files: %w", err)
+	}
+
 	// Finally, chroot to the isolated environment
 	if err := f.performChroot(); err != nil {
 		return fmt.Errorf("chroot failed: %w", err)
@@ -509,21 +518,8 @@ func (f *JobFilesystem) SetupBuilder() error {
 //
 // Directories for allowed mounts are created dynamically during mount operations.
 func (f *JobFilesystem) createEssentialDirs() error {
-	// Directories that must exist but won't be populated by mounts
-	essentialDirs := []string{
-		"etc",     // For resolv.conf, hosts, etc.
-		"tmp",     // Will be bind mounted to job-specific tmp
-		"proc",    // For /proc mount
-		"dev",     // For device nodes
-		"sys",     // For potential sysfs mount
-		"work",    // Working directory
-		"var",     // For various runtime needs
-		"var/run", // For runtime files
-		"var/tmp", // Alternative tmp
-		"volumes", // For volume mounts
-	}
-
-	// Create essential directories
+	// Create essential directories; when the root was claimed from the
+	// skeleton pool these already exist and MkdirAll only stats them
 	for _, dir := range essentialDirs {
 		fullPath := filepath.Join(f.RootDir, dir)
 		if err := f.platform.MkdirAll(fullPath, 0755); err != nil {
@@ -541,10 +537,15 @@ func (f *JobFilesystem) createEssentialDirs() error {
 // that are needed for job execution but should not be writable.
 // Automatically creates parent directories and handles missing host directories gracefully.
 // Each mount is first bound, then remounted as read-only for security.
+// The directories are filesystem.allowedMounts and those of the job's chroot
+// profile, so commands find their libraries on the host's distro.
 // Continues with remaining mounts if individual mounts fail.
 func (f *JobFilesystem) mountAllowedDirs() error {
+	profile := f.jobChrootProfile()
+	f.logger.Debug("mounting allowed directories", "chrootProfile", profile)
+
 	// Enhanced to create parent directories automatically
-	for _, allowedDir := range f.config.Filesystem.AllowedMounts {
+	for _, allowedDir := range ChrootMounts(f.config.Filesystem.AllowedMounts, profile) {
 		// Skip if the host directory doesn't exist
 		if _, err := f.platform.Stat(allowedDir); f.platform.IsNotExist(err) {
 			f.logger.Debug("skipping non-existent allowed directory", "dir", allowedDir)
@@ -609,6 +610,8 @@ func (f *JobFilesystem) mountAllowedDirs() error {
 // Bind mounts the job-specific temporary directory (from host) to /tmp
 // in the isolated environment, providing writable temporary space that
 // is automatically cleaned up when the job completes.
+// When a /tmp size is requested, a sized tmpfs is mounted instead so that
+// /tmp usage is capped independently of the work directory quota.
 // Each job gets its own isolated /tmp to prevent interference.
 func (f *JobFilesystem) setupTmpDir() error {
 	// Create the job-specific tmp directory on the host
@@ -623,6 +626,16 @@ func (f *JobFilesystem) setupTmpDir() error {
 		return fmt.Errorf("failed to create tmp mount point: %w", err)
 	}
 
+	if f.TmpSize > 0 {
+		opts := fmt.Sprintf("mode=1777,size=%d", f.TmpSize)
+		flags := uintptr(syscall.MS_NOSUID | syscall.MS_NODEV)
+		if err := f.platform.Mount("tmpfs", tmpPath, "tmpfs", flags, opts); err != nil {
+			return fmt.Errorf("failed to mount sized tmp directory: %w", err)
+		}
+		f.logger.Debug("setup sized tmpfs for /tmp", "isolatedTmp", tmpPath, "sizeBytes", f.TmpSize)
+		return nil
+	}
+
 	// Bind mount the job-specific tmp to /tmp in the isolated root
 	if err := f.platform.Mount(f.TmpDir, tmpPath, "", syscall.MS_BIND, ""); err != nil {
 		return fmt.Errorf("failed to bind mount tmp directory: %w", err)
@@ -632,6 +645,55 @@ func (f *JobFilesystem) setupTmpDir() error {
 	return nil
 }
 
+// setupShm mounts a size-limited tmpfs at /dev/shm in the isolated root.
+// POSIX shared memory (shm_open) and libraries such as PyTorch dataloaders
+// require a writable /dev/shm; without it they fail or fall back to slow paths.
+// A job joining an IPC channel gets the channel's tmpfs instead, shared with
+// the other jobs of the channel. Skipped when no size is configured.
+func (f *JobFilesystem) setupShm() error {
+	if f.IPCDir != "" {
+		return f.setupIPCChannel()
+	}
+	if f.ShmSize <= 0 {
+		f.logger.Debug("no /dev/shm size configured, skipping shm mount")
+		return nil
+	}
+
+	shmPath := filepath.Join(f.RootDir, "dev", "shm")
+	if err := f.platform.MkdirAll(shmPath, 01777); err != nil {
+		return fmt.Errorf("failed to create /dev/shm mount point: %w", err)
+	}
+
+	opts := fmt.Sprintf("mode=1777,size=%d", f.ShmSize)
+	flags := uintptr(syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC)
+	if err := f.platform.Mount("shm", shmPath, "tmpfs", flags, opts); err != nil {
+		return fmt.Errorf("failed to mount /dev/shm tmpfs: %w", err)
+	}
+
+	f.logger.Debug("setup /dev/shm tmpfs", "path", shmPath, "sizeBytes", f.ShmSize)
+	return nil
+}
+
+// setupIPCChannel bind mounts the IPC channel the host mounted for the job's
+// channel at /dev/shm. The channel is sized by the server configuration, not
+// by the job's shm size.
+func (f *JobFilesystem) setupIPCChannel() error {
+	shmPath := filepath.Join(f.RootDir, "dev", "shm")
+	if err := f.platform.MkdirAll(shmPath, 01777); err != nil {
+		return fmt.Errorf("failed to create /dev/shm mount point: %w", err)
+	}
+	if err := f.platform.Mount(f.IPCDir, shmPath, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to bind mount ipc channel: %w", err)
+	}
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC)
+	if err := f.platform.Mount("", shmPath, "", flags, ""); err != nil {
+		return fmt.Errorf("failed to remount ipc channel: %w", err)
+	}
+
+	f.logger.Debug("bound ipc channel at /dev/shm", "path", shmPath, "channelDir", f.IPCDir)
+	return nil
+}
+
 // performChroot executes the chroot system call to isolate the filesystem.
 // Changes to the prepared isolated root directory, performs chroot operation,
 // then changes working directory to the configured workspace (/work by default).
@@ -1188,6 +1250,27 @@ func (f *JobFilesystem) getDefaultDiskQuotaBytes() int64 {
 	return 1048576 // 1MB default
 }
 
+// loadSizingFromEnvironment reads /dev/shm and /tmp sizes from JOB_SHM_SIZE
+// and JOB_TMP_SIZE, which are set by the job execution system.
+// Falls back to the configured filesystem defaults when not present.
+func (f *JobFilesystem) loadSizingFromEnvironment() {
+	f.ShmSize = f.config.Filesystem.ShmSizeBytes
+	f.TmpSize = f.config.Filesystem.TmpSizeBytes
+
+	if shmStr := f.platform.Getenv("JOB_SHM_SIZE"); shmStr != "" {
+		if size, err := strconv.ParseInt(shmStr, 10, 64); err == nil && size >= 0 {
+			f.ShmSize = size
+		}
+	}
+	if tmpStr := f.platform.Getenv("JOB_TMP_SIZE"); tmpStr != "" {
+		if size, err := strconv.ParseInt(tmpStr, 10, 64); err == nil && size >= 0 {
+			f.TmpSize = size
+		}
+	}
+
+	f.logger.Debug("loaded filesystem sizing", "shmSize", f.ShmSize, "tmpSize", f.TmpSize)
+}
+
 // loadRuntimeFromEnvironment reads runtime configuration from environment variables
 func (f *JobFilesystem) loadRuntimeFromEnvironment() {
 	f.Runtime = f.platform.Getenv("JOB_RUNTIME")
@@ -1200,6 +1283,31 @@ func (f *JobFilesystem) loadRuntimeFromEnvironment() {
 	}
 }
 
+// runtimeBasePath returns the directory runtimes are installed under
+func (f *JobFilesystem) runtimeBasePath() string {
+	// Check if we have a runtime manager available through environment
+	if path := f.platform.Getenv("RUNTIME_MANAGER_PATH"); path != "" {
+		return path
+	}
+	// Try default runtime path
+	return f.config.Runtime.BasePath
+}
+
+// jobChrootProfile returns the chroot profile of the job, taking its
+// runtime's override into account
+func (f *JobFilesystem) jobChrootProfile() string {
+	if f.Runtime == "" {
+		return f.chrootProfile
+	}
+
+	rt, err := runtime.NewResolver(f.runtimeBasePath(), f.platform).ResolveRuntime(f.Runtime)
+	if err != nil {
+		// mountRuntime reports the runtime's errors
+		return f.chrootProfile
+	}
+	return RuntimeChrootProfile(f.chrootProfile, rt)
+}
+
 // mountRuntime mounts the runtime directories if runtime is specified
 func (f *JobFilesystem) mountRuntime() error {
 	if f.Runtime == "" {
@@ -1210,15 +1318,8 @@ func (f *JobFilesystem) mountRuntime() error {
 	log := f.logger.WithField("runtime", f.Runtime)
 	log.Debug("mounting runtime for job")
 
-	// Check if we have a runtime manager available through environment
-	runtimeManagerPath := f.platform.Getenv("RUNTIME_MANAGER_PATH")
-	if runtimeManagerPath == "" {
-		// Try default runtime path
-		runtimeManagerPath = f.config.Runtime.BasePath
-	}
-
 	// Create runtime manager to resolve and mount runtime
-	if err := f.mountRuntimeWithManager(runtimeManagerPath); err != nil {
+	if err := f.mountRuntimeWithManager(f.runtimeBasePath()); err != nil {
 		return fmt.Errorf("failed to mount runtime %s: %w", f.Runtime, err)
 	}
 
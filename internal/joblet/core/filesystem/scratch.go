@@ -0,0 +1,298 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+// Project quota interface of the kernel (linux/fs.h and linux/quota.h)
+const (
+	fsIocFsGetXattr    = 0x801c581f // FS_IOC_FSGETXATTR
+	fsIocFsSetXattr    = 0x401c5820 // FS_IOC_FSSETXATTR
+	fsXflagProjInherit = 0x00000200 // FS_XFLAG_PROJINHERIT
+	qSetQuota          = 0x800008   // Q_SETQUOTA
+	prjQuota           = 2          // PRJQUOTA
+	qifBLimits         = 1          // QIF_BLIMITS
+	quotaBlockSize     = 1024       // Unit of the if_dqblk block limits
+
+	// scratchProjectBase keeps the project IDs of scratch directories clear
+	// of the low IDs administrators assign by hand in /etc/projid
+	scratchProjectBase = 1 << 24
+)
+
+// fsxattr is struct fsxattr of linux/fs.h
+type fsxattr struct {
+	Xflags     uint32
+	Extsize    uint32
+	Nextents   uint32
+	Projid     uint32
+	Cowextsize uint32
+	_          [8]byte
+}
+
+// ifDqblk is struct if_dqblk of linux/quota.h
+type ifDqblk struct {
+	BHardLimit uint64
+	BSoftLimit uint64
+	CurSpace   uint64
+	IHardLimit uint64
+	ISoftLimit uint64
+	CurInodes  uint64
+	BTime      uint64
+	ITime      uint64
+	Valid      uint32
+	_          uint32
+}
+
+// ScratchDir returns the work directory of a job on a scratch device
+func ScratchDir(scratch config.ScratchConfig, jobID string) string {
+	return filepath.Join(scratch.Path, jobID)
+}
+
+// ScratchProjectID returns the project ID charged for a job's scratch
+// directory. It is derived from the job ID so cleanup can release the quota
+// without any recorded state.
+func ScratchProjectID(jobID string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(jobID))
+	return scratchProjectBase + h.Sum32()%(1<<30)
+}
+
+// ReleaseScratchQuota removes the project quota limit of a job's scratch
+// directory once the directory is gone
+func ReleaseScratchQuota(p platform.Platform, scratch config.ScratchConfig, jobID string) error {
+	device, err := scratchDevice(p, scratch)
+	if err != nil {
+		return err
+	}
+	return setProjectLimit(device, ScratchProjectID(jobID), 0)
+}
+
+// setupScratchWorkDir places the work directory on the scratch device the job
+// selected. The job's directory on the device gets its own project, capped by
+// the device's per-job quota, and is bind mounted over the work directory.
+// Files uploaded before the isolation was set up are copied over first.
+func (f *JobFilesystem) setupScratchWorkDir() error {
+	log := f.logger.WithFields("operation", "setup-scratch-work", "scratch", f.Scratch)
+
+	scratch, exists := f.config.Filesystem.Scratch[f.Scratch]
+	if !exists {
+		return fmt.Errorf("scratch device %q is not configured", f.Scratch)
+	}
+	dir := ScratchDir(scratch, f.JobID)
+	if err := f.platform.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+
+	if scratch.QuotaBytes > 0 {
+		device, err := scratchDevice(f.platform, scratch)
+		if err != nil {
+			return err
+		}
+		projectID := ScratchProjectID(f.JobID)
+		if err := setProjectInherit(dir, projectID); err != nil {
+			return fmt.Errorf("failed to assign project %d to %s: %w", projectID, dir, err)
+		}
+		if err := setProjectLimit(device, projectID, scratch.QuotaBytes); err != nil {
+			return fmt.Errorf("failed to set project quota on %s (is it mounted with prjquota?): %w", device, err)
+		}
+		log.Debug("scratch project quota set", "device", device, "projectID", projectID, "quotaBytes", scratch.QuotaBytes)
+	}
+
+	workPath := filepath.Join(f.RootDir, "work")
+	entries, err := f.platform.ReadDir(workPath)
+	if err != nil && !f.platform.IsNotExist(err) {
+		return fmt.Errorf("failed to read work directory: %w", err)
+	}
+	for _, entry := range entries {
+		if err := copyTree(filepath.Join(workPath, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to copy %s to the scratch directory: %w", entry.Name(), err)
+		}
+	}
+
+	if err := f.platform.MkdirAll(workPath, 0755); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	if err := f.platform.Mount(dir, workPath, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to bind mount scratch directory: %w", err)
+	}
+
+	log.Debug("work directory placed on scratch device", "dir", dir, "copiedEntries", len(entries))
+	return nil
+}
+
+// scratchDevice returns the block device quotactl needs for a scratch path:
+// the configured one, or the source of the mount holding the path
+func scratchDevice(p platform.Platform, scratch config.ScratchConfig) (string, error) {
+	if scratch.Device != "" {
+		return scratch.Device, nil
+	}
+	mountinfo, err := p.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return "", fmt.Errorf("failed to read mount table: %w", err)
+	}
+	return mountSource(mountinfo, scratch.Path)
+}
+
+// mountSource returns the source of the innermost mount holding path, from
+// the contents of /proc/self/mountinfo
+func mountSource(mountinfo []byte, path string) (string, error) {
+	path = filepath.Clean(path)
+	var best, source string
+	scanner := bufio.NewScanner(bytes.NewReader(mountinfo))
+	for scanner.Scan() {
+		// ID PARENT MAJ:MIN ROOT MOUNTPOINT OPTIONS [OPTIONAL...] - FSTYPE SOURCE SUPEROPTIONS
+		fields := strings.Fields(scanner.Text())
+		separator := -1
+		for i, field := range fields {
+			if field == "-" {
+				separator = i
+				break
+			}
+		}
+		if len(fields) < 5 || separator < 0 || separator+2 >= len(fields) {
+			continue
+		}
+		mountPoint := unescapeMountField(fields[4])
+		if !within(path, mountPoint) || len(mountPoint) < len(best) {
+			continue
+		}
+		best, source = mountPoint, unescapeMountField(fields[separator+2])
+	}
+	if source == "" || !strings.HasPrefix(source, "/dev/") {
+		return "", fmt.Errorf("no block device found for %s, set its scratch device", path)
+	}
+	return source, nil
+}
+
+// within reports whether path is dir or below it
+func within(path, dir string) bool {
+	return dir == "/" || path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// unescapeMountField decodes the octal escapes (\040 for a space) of the
+// mount table
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if code, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// setProjectInherit assigns dir to a project and makes what is created in it
+// inherit the project
+func setProjectInherit(dir string, projectID uint32) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var attr fsxattr
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), fsIocFsGetXattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return errno
+	}
+	attr.Projid = projectID
+	attr.Xflags |= fsXflagProjInherit
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), fsIocFsSetXattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// setProjectLimit sets the hard block limit of a project on device (0 = no
+// limit)
+func setProjectLimit(device string, projectID uint32, limitBytes int64) error {
+	devicePtr, err := syscall.BytePtrFromString(device)
+	if err != nil {
+		return err
+	}
+	quota := ifDqblk{
+		BHardLimit: uint64((limitBytes + quotaBlockSize - 1) / quotaBlockSize),
+		Valid:      qifBLimits,
+	}
+	cmd := qSetQuota<<8 | prjQuota
+	if _, _, errno := syscall.Syscall6(syscall.SYS_QUOTACTL, uintptr(cmd), uintptr(unsafe.Pointer(devicePtr)),
+		uintptr(projectID), uintptr(unsafe.Pointer(&quota)), 0, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// copyTree copies a file, symlink or directory tree from src to dst,
+// keeping permissions
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case entry.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil // Pipes and devices stay behind
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,84 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testMountinfo = `22 1 259:2 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw
+25 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw
+40 22 259:5 / /mnt/nvme rw,relatime shared:20 - xfs /dev/nvme1n1 rw,prjquota
+41 40 259:6 / /mnt/nvme/fast\040disk rw,relatime shared:21 - xfs /dev/nvme2n1 rw,prjquota
+42 22 0:40 / /mnt/nvme-tmp rw,relatime shared:22 - tmpfs tmpfs rw
+`
+
+func TestMountSource(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "/mnt/nvme/joblet", want: "/dev/nvme1n1"},
+		{path: "/mnt/nvme", want: "/dev/nvme1n1"},
+		{path: "/mnt/nvme/fast disk/jobs", want: "/dev/nvme2n1"},
+		{path: "/opt/joblet/scratch", want: "/dev/nvme0n1p2"},
+		{path: "/mnt/nvme-tmp/jobs", wantErr: true}, // tmpfs has no block device
+	}
+	for _, tt := range tests {
+		got, err := mountSource([]byte(testMountinfo), tt.path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("mountSource(%s) = %s, want an error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("mountSource(%s) = %s, %v; want %s", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestScratchProjectID(t *testing.T) {
+	a := ScratchProjectID("0b5c2a1e-7d7f-4c43-9f0a-5f2e3c9b1a11")
+	b := ScratchProjectID("d2a4c0f1-1f1e-4e0b-8d2c-9a6b7e5f3c22")
+	if a == b {
+		t.Errorf("different jobs share project ID %d", a)
+	}
+	if a != ScratchProjectID("0b5c2a1e-7d7f-4c43-9f0a-5f2e3c9b1a11") {
+		t.Error("project ID of a job changed between calls")
+	}
+	for _, id := range []uint32{a, b} {
+		if id < scratchProjectBase {
+			t.Errorf("project ID %d is below the reserved base", id)
+		}
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "data", "nested"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "data", "nested", "input.csv"), []byte("a,b\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("nested/input.csv", filepath.Join(src, "data", "latest")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyTree(filepath.Join(src, "data"), filepath.Join(dst, "data")); err != nil {
+		t.Fatalf("copyTree() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dst, "data", "latest"))
+	if err != nil || string(content) != "a,b\n" {
+		t.Errorf("copied symlink reads %q, %v", content, err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "data", "nested", "input.csv"))
+	if err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("copied file mode = %v, %v", info, err)
+	}
+}
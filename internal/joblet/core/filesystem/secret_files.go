@@ -0,0 +1,126 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// secretStagingDir holds the tmpfs the secret files are written to before
+// they are bind mounted at their paths; it is gone before the chroot
+const secretStagingDir = ".joblet-secrets"
+
+// SecretFile is a secret variable of the job delivered as a file
+type SecretFile struct {
+	Path string // Path in the job's filesystem
+	Data []byte
+}
+
+// loadSecretFilesFromEnvironment reads the secret files from
+// JOB_SECRET_FILES_COUNT and JOB_SECRET_FILE_<i>[_DATA], which are set by
+// the job execution system
+func (f *JobFilesystem) loadSecretFilesFromEnvironment() {
+	count, _ := strconv.Atoi(f.platform.Getenv("JOB_SECRET_FILES_COUNT"))
+	f.SecretFiles = nil
+	for i := 0; i < count; i++ {
+		path := f.platform.Getenv(fmt.Sprintf("JOB_SECRET_FILE_%d", i))
+		if path == "" {
+			continue
+		}
+		data := f.platform.Getenv(fmt.Sprintf("JOB_SECRET_FILE_%d_DATA", i))
+		f.SecretFiles = append(f.SecretFiles, SecretFile{Path: path, Data: []byte(data)})
+	}
+}
+
+// setupSecretFiles writes the secret files to a tmpfs of their own and bind
+// mounts each one read-only at its path, so their content never reaches
+// persistent storage. The tmpfs is only reachable through those mounts once
+// its staging directory is detached. Only an empty file is created on disk
+// where a path did not exist, to mount over.
+func (f *JobFilesystem) setupSecretFiles() error {
+	if len(f.SecretFiles) == 0 {
+		return nil
+	}
+
+	staging := filepath.Join(f.RootDir, secretStagingDir)
+	if err := f.platform.MkdirAll(staging, 0700); err != nil {
+		return fmt.Errorf("failed to create secret staging directory: %w", err)
+	}
+	size := int64(0)
+	for _, file := range f.SecretFiles {
+		size += int64(len(file.Data)) + 4096
+	}
+	flags := uintptr(syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC)
+	if err := f.platform.Mount("tmpfs", staging, "tmpfs", flags, fmt.Sprintf("mode=0700,size=%d", size)); err != nil {
+		return fmt.Errorf("failed to mount secret tmpfs: %w", err)
+	}
+	defer func() {
+		if err := f.platform.Unmount(staging, syscall.MNT_DETACH); err != nil {
+			f.logger.Warn("failed to detach secret staging directory", "error", err)
+		}
+		if err := f.platform.Remove(staging); err != nil {
+			f.logger.Warn("failed to remove secret staging directory", "error", err)
+		}
+	}()
+
+	for i, file := range f.SecretFiles {
+		source := filepath.Join(staging, strconv.Itoa(i))
+		if err := f.platform.WriteFile(source, file.Data, 0400); err != nil {
+			return fmt.Errorf("failed to write secret file %s: %w", file.Path, err)
+		}
+		target, err := f.secretFileTarget(file.Path)
+		if err != nil {
+			return err
+		}
+		if err := f.platform.Mount(source, target, "", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("failed to mount secret file %s: %w", file.Path, err)
+		}
+		remount := uintptr(syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY) | flags
+		if err := f.platform.Mount("", target, "", remount, ""); err != nil {
+			return fmt.Errorf("failed to remount secret file %s read-only: %w", file.Path, err)
+		}
+	}
+
+	f.logger.Debug("mounted secret files", "count", len(f.SecretFiles))
+	return nil
+}
+
+// secretFileTarget returns the host path of a secret file in the job root,
+// creating its directories and an empty file to mount over. Symbolic links
+// are refused so a secret cannot be mounted, or a file created, outside the
+// job root.
+func (f *JobFilesystem) secretFileTarget(path string) (string, error) {
+	target := f.RootDir
+	parts := strings.Split(strings.Trim(filepath.Clean(path), "/"), "/")
+	for i, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid secret file path %s", path)
+		}
+		target = filepath.Join(target, part)
+		info, err := os.Lstat(target)
+		switch {
+		case os.IsNotExist(err) && i < len(parts)-1:
+			if err := f.platform.MkdirAll(target, 0755); err != nil {
+				return "", fmt.Errorf("failed to create directory for secret file %s: %w", path, err)
+			}
+		case os.IsNotExist(err):
+			if err := f.platform.WriteFile(target, nil, 0400); err != nil {
+				return "", fmt.Errorf("failed to create secret file %s: %w", path, err)
+			}
+		case err != nil:
+			return "", fmt.Errorf("failed to check secret file %s: %w", path, err)
+		case info.Mode()&os.ModeSymlink != 0:
+			return "", fmt.Errorf("secret file %s: %s is a symbolic link", path, target)
+		case i < len(parts)-1 && !info.IsDir():
+			return "", fmt.Errorf("secret file %s: %s is not a directory", path, target)
+		case i == len(parts)-1 && info.IsDir():
+			return "", fmt.Errorf("secret file %s is a directory", path)
+		}
+	}
+	return target, nil
+}
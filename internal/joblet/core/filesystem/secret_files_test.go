@@ -0,0 +1,66 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+	"github.com/ehsaniara/joblet/pkg/platform/platformfakes"
+)
+
+func TestLoadSecretFilesFromEnvironment(t *testing.T) {
+	env := map[string]string{
+		"JOB_SECRET_FILES_COUNT": "2",
+		"JOB_SECRET_FILE_0":      "/root/.aws/credentials",
+		"JOB_SECRET_FILE_0_DATA": "[default]",
+		"JOB_SECRET_FILE_1":      "/run/token",
+		"JOB_SECRET_FILE_1_DATA": "t0k3n",
+	}
+	fakePlatform := &platformfakes.FakePlatform{}
+	fakePlatform.GetenvStub = func(key string) string { return env[key] }
+	jobFS := &JobFilesystem{platform: fakePlatform, config: &config.Config{}, logger: logger.New()}
+
+	jobFS.loadSecretFilesFromEnvironment()
+	if len(jobFS.SecretFiles) != 2 || jobFS.SecretFiles[1].Path != "/run/token" || string(jobFS.SecretFiles[1].Data) != "t0k3n" {
+		t.Errorf("SecretFiles = %+v", jobFS.SecretFiles)
+	}
+}
+
+func TestSecretFileTarget(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	jobFS := &JobFilesystem{RootDir: root, platform: platform.NewPlatform(), config: &config.Config{}, logger: logger.New()}
+
+	target, err := jobFS.secretFileTarget("/root/.aws/credentials")
+	if err != nil {
+		t.Fatalf("secretFileTarget() error = %v", err)
+	}
+	if target != filepath.Join(root, "root", ".aws", "credentials") {
+		t.Errorf("target = %s", target)
+	}
+	if info, err := os.Stat(target); err != nil || info.Size() != 0 || info.IsDir() {
+		t.Errorf("placeholder = %v, %v", info, err)
+	}
+	if _, err := jobFS.secretFileTarget("/root/.aws/credentials"); err != nil {
+		t.Errorf("existing placeholder: %v", err)
+	}
+
+	// Links must not lead out of the job root
+	if err := os.Symlink(outside, filepath.Join(root, "etc")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jobFS.secretFileTarget("/etc/token"); err == nil {
+		t.Error("secretFileTarget() followed a symbolic link")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "token")); !os.IsNotExist(err) {
+		t.Error("a file was created outside the job root")
+	}
+	if _, err := jobFS.secretFileTarget("/root/.aws"); err == nil {
+		t.Error("secretFileTarget() accepted a directory")
+	}
+}
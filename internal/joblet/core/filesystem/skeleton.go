@@ -0,0 +1,222 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+// SkeletonDirName is the directory under the job base directory that holds
+// pre-created chroot skeletons. It starts with a dot so that it can never
+// collide with a job UUID and is skipped by orphaned-job cleanup.
+const SkeletonDirName = ".skeletons"
+
+// essentialDirs are the directories every job root needs but which are not
+// populated by bind mounts. Shared by createEssentialDirs and the skeleton
+// template so both always produce the same layout.
+var essentialDirs = []string{
+	"etc",     // For resolv.conf, hosts, etc.
+	"tmp",     // Will be bind mounted to job-specific tmp
+	"proc",    // For /proc mount
+	"dev",     // For device nodes
+	"sys",     // For potential sysfs mount
+	"work",    // Working directory
+	"var",     // For various runtime needs
+	"var/run", // For runtime files
+	"var/tmp", // Alternative tmp
+	"volumes", // For volume mounts
+}
+
+// SkeletonPool keeps a number of pre-created job root directories ready to be
+// claimed. Each skeleton already contains the essential directories and the
+// mount points for every allowed host directory, so a job start only has to
+// rename one into place instead of creating the tree directory by directory.
+// The init process still runs the full setup; its MkdirAll calls simply find
+// the directories present.
+//
+// Claimed skeletons are replaced in the background, building several at once,
+// which keeps directory creation off the job start path.
+type SkeletonPool struct {
+	dir      string
+	size     int
+	template []string
+	platform platform.Platform
+	logger   *logger.Logger
+
+	mu     sync.Mutex
+	ready  []string
+	seq    atomic.Uint64
+	refill chan struct{}
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// SkeletonStats reports pool occupancy and how often claims were served.
+type SkeletonStats struct {
+	Ready  int
+	Hits   int64
+	Misses int64
+}
+
+// NewSkeletonPool creates a pool sized by filesystem.skeletonPoolSize. The
+// template is derived from the essential directories, allowed mounts and the
+// mounts of the host's chroot profile. A
+// size of zero disables the pool and every Claim misses.
+func NewSkeletonPool(cfg *config.Config, platform platform.Platform) *SkeletonPool {
+	mounts := ChrootMounts(cfg.Filesystem.AllowedMounts, ResolveChrootProfile(cfg, platform))
+	template := make([]string, 0, len(essentialDirs)+len(mounts))
+	template = append(template, essentialDirs...)
+	for _, mount := range mounts {
+		if rel := strings.TrimPrefix(filepath.Clean(mount), "/"); rel != "" && rel != "." {
+			template = append(template, rel)
+		}
+	}
+
+	return &SkeletonPool{
+		dir:      filepath.Join(cfg.Filesystem.BaseDir, SkeletonDirName),
+		size:     cfg.Filesystem.SkeletonPoolSize,
+		template: template,
+		platform: platform,
+		logger:   logger.New().WithField("component", "skeleton-pool"),
+		refill:   make(chan struct{}, 1),
+	}
+}
+
+// Start discards skeletons left over from a previous run, since the template
+// may have changed, fills the pool and keeps it topped up until ctx is done.
+func (sp *SkeletonPool) Start(ctx context.Context) error {
+	if sp.size <= 0 {
+		return nil
+	}
+
+	if err := sp.platform.RemoveAll(sp.dir); err != nil {
+		return fmt.Errorf("failed to remove stale skeletons: %w", err)
+	}
+	if err := sp.platform.MkdirAll(sp.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create skeleton directory: %w", err)
+	}
+
+	sp.fill()
+	sp.logger.Debug("skeleton pool ready", "size", sp.size, "templateDirs", len(sp.template))
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sp.refill:
+				sp.fill()
+			}
+		}
+	}()
+	return nil
+}
+
+// Claim moves a ready skeleton to dest, which must not exist or be an empty
+// directory. It returns false when the pool is empty or the rename fails, in
+// which case the caller creates dest itself.
+func (sp *SkeletonPool) Claim(dest string) bool {
+	if sp.size <= 0 {
+		return false
+	}
+
+	defer sp.requestRefill()
+
+	sp.mu.Lock()
+	if len(sp.ready) == 0 {
+		sp.mu.Unlock()
+		sp.misses.Add(1)
+		return false
+	}
+	skeleton := sp.ready[len(sp.ready)-1]
+	sp.ready = sp.ready[:len(sp.ready)-1]
+	sp.mu.Unlock()
+
+	if err := os.Rename(skeleton, dest); err != nil {
+		sp.logger.Warn("failed to claim skeleton", "skeleton", skeleton, "dest", dest, "error", err)
+		_ = sp.platform.RemoveAll(skeleton)
+		sp.misses.Add(1)
+		return false
+	}
+
+	sp.hits.Add(1)
+	return true
+}
+
+// Stats returns a snapshot of the pool counters.
+func (sp *SkeletonPool) Stats() SkeletonStats {
+	sp.mu.Lock()
+	ready := len(sp.ready)
+	sp.mu.Unlock()
+
+	return SkeletonStats{
+		Ready:  ready,
+		Hits:   sp.hits.Load(),
+		Misses: sp.misses.Load(),
+	}
+}
+
+// requestRefill wakes the refill loop without blocking; one pending signal is
+// enough because fill always tops the pool up completely.
+func (sp *SkeletonPool) requestRefill() {
+	select {
+	case sp.refill <- struct{}{}:
+	default:
+	}
+}
+
+// fill builds the missing skeletons concurrently and adds the ones that were
+// created successfully to the pool.
+func (sp *SkeletonPool) fill() {
+	sp.mu.Lock()
+	missing := sp.size - len(sp.ready)
+	sp.mu.Unlock()
+	if missing <= 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	built := make(chan string, missing)
+	for i := 0; i < missing; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path := filepath.Join(sp.dir, fmt.Sprintf("skeleton-%d", sp.seq.Add(1)))
+			if err := sp.build(path); err != nil {
+				sp.logger.Warn("failed to build skeleton", "path", path, "error", err)
+				_ = sp.platform.RemoveAll(path)
+				return
+			}
+			built <- path
+		}()
+	}
+	wg.Wait()
+	close(built)
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	for path := range built {
+		sp.ready = append(sp.ready, path)
+	}
+}
+
+// build creates one skeleton from the template.
+func (sp *SkeletonPool) build(root string) error {
+	for _, dir := range sp.template {
+		if err := sp.platform.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+func skeletonTestConfig(baseDir string, size int) *config.Config {
+	return &config.Config{
+		Filesystem: config.FilesystemConfig{
+			BaseDir:          baseDir,
+			AllowedMounts:    []string{"/usr/bin", "/bin", "/lib", "/lib64"},
+			SkeletonPoolSize: size,
+		},
+	}
+}
+
+// waitForReady polls until the pool has been topped back up to want skeletons.
+func waitForReady(t *testing.T, pool *SkeletonPool, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Stats().Ready < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("pool has %d ready skeletons, want %d", pool.Stats().Ready, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSkeletonPool_ClaimProvidesTemplate(t *testing.T) {
+	baseDir := t.TempDir()
+	pool := NewSkeletonPool(skeletonTestConfig(baseDir, 2), platform.NewPlatform())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if ready := pool.Stats().Ready; ready != 2 {
+		t.Fatalf("Ready = %d after Start, want 2", ready)
+	}
+
+	jobRoot := filepath.Join(baseDir, "job-1")
+	if !pool.Claim(jobRoot) {
+		t.Fatal("Claim() = false, want a skeleton from a full pool")
+	}
+
+	for _, dir := range []string{"etc", "proc", "work", "var/tmp", "volumes", "usr/bin", "lib64"} {
+		if info, err := os.Stat(filepath.Join(jobRoot, dir)); err != nil || !info.IsDir() {
+			t.Errorf("claimed root is missing %s: %v", dir, err)
+		}
+	}
+
+	waitForReady(t, pool, 2)
+	if stats := pool.Stats(); stats.Hits != 1 || stats.Misses != 0 {
+		t.Errorf("Stats() = %+v, want 1 hit and no misses", stats)
+	}
+}
+
+func TestSkeletonPool_StartDiscardsStaleSkeletons(t *testing.T) {
+	baseDir := t.TempDir()
+	stale := filepath.Join(baseDir, SkeletonDirName, "skeleton-99")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	pool := NewSkeletonPool(skeletonTestConfig(baseDir, 1), platform.NewPlatform())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale skeleton still present: %v", err)
+	}
+}
+
+func TestSkeletonPool_Misses(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		start bool
+	}{
+		{"disabled pool", 0, true},
+		{"pool never started", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseDir := t.TempDir()
+			pool := NewSkeletonPool(skeletonTestConfig(baseDir, tt.size), platform.NewPlatform())
+			if tt.start {
+				if err := pool.Start(context.Background()); err != nil {
+					t.Fatalf("Start() error = %v", err)
+				}
+			}
+
+			if pool.Claim(filepath.Join(baseDir, "job-1")) {
+				t.Error("Claim() = true, want a miss")
+			}
+			if _, err := os.Stat(filepath.Join(baseDir, SkeletonDirName)); tt.size == 0 && !os.IsNotExist(err) {
+				t.Errorf("disabled pool created %s", SkeletonDirName)
+			}
+		})
+	}
+}
+
+func TestSkeletonPool_ClaimOntoPopulatedDirFails(t *testing.T) {
+	baseDir := t.TempDir()
+	pool := NewSkeletonPool(skeletonTestConfig(baseDir, 1), platform.NewPlatform())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	jobRoot := filepath.Join(baseDir, "job-1")
+	if err := os.MkdirAll(filepath.Join(jobRoot, "existing"), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if pool.Claim(jobRoot) {
+		t.Fatal("Claim() = true over a populated directory")
+	}
+	if _, err := os.Stat(filepath.Join(jobRoot, "existing")); err != nil {
+		t.Errorf("existing content was disturbed: %v", err)
+	}
+	waitForReady(t, pool, 1)
+}
+
+// BenchmarkJobRootCreation compares building a job root directory by directory,
+// as happens without the pool, against claiming a pre-created skeleton.
+func BenchmarkJobRootCreation(b *testing.B) {
+	p := platform.NewPlatform()
+	template := NewSkeletonPool(skeletonTestConfig(b.TempDir(), 0), p).template
+
+	b.Run("cold", func(b *testing.B) {
+		baseDir := b.TempDir()
+		for i := 0; i < b.N; i++ {
+			root := filepath.Join(baseDir, fmt.Sprintf("job-%d", i))
+			for _, dir := range template {
+				if err := p.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("skeleton", func(b *testing.B) {
+		baseDir := b.TempDir()
+		pool := NewSkeletonPool(skeletonTestConfig(baseDir, b.N), p)
+		if err := p.MkdirAll(pool.dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		// Fill without the refill loop so background builds don't skew timing
+		pool.fill()
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if !pool.Claim(filepath.Join(baseDir, fmt.Sprintf("job-%d", i))) {
+				b.Fatal("pool ran dry")
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+//go:build linux
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/filesystem"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+	"github.com/ehsaniara/joblet/pkg/version"
+)
+
+// kernelReleasePath holds the running kernel's release, as uname -r prints it
+const kernelReleasePath = "/proc/sys/kernel/osrelease"
+
+// fingerprinter records the environment jobs start in
+type fingerprinter struct {
+	config   *config.Config
+	platform platform.Platform
+	runtimes *runtime.Resolver
+	// chrootProfile is the host's distro profile, detected once
+	chrootProfile string
+
+	kernelOnce sync.Once
+	kernel     string
+}
+
+func newFingerprinter(cfg *config.Config, platform platform.Platform) *fingerprinter {
+	return &fingerprinter{
+		config:   cfg,
+		platform: platform,
+		runtimes: runtime.NewResolver(cfg.Runtime.BasePath, platform),
+
+		chrootProfile: filesystem.ResolveChrootProfile(cfg, platform),
+	}
+}
+
+// Fingerprint describes the environment the job is about to start in. Parts
+// that cannot be determined are left empty rather than failing the job.
+func (f *fingerprinter) Fingerprint(job *domain.Job) *domain.JobFingerprint {
+	fp := &domain.JobFingerprint{
+		KernelVersion: f.kernelVersion(),
+		JobletVersion: version.GetVersion(),
+	}
+	if job.Runtime == "" {
+		fp.MountsHash = mountsHash(filesystem.ChrootMounts(f.config.Filesystem.AllowedMounts, f.chrootProfile))
+		return fp
+	}
+	rt, err := f.runtimes.ResolveRuntime(job.Runtime)
+	if err == nil {
+		fp.Runtime, fp.RuntimeVersion = rt.Name, rt.Version
+	}
+	profile := filesystem.RuntimeChrootProfile(f.chrootProfile, rt)
+	fp.MountsHash = mountsHash(filesystem.ChrootMounts(f.config.Filesystem.AllowedMounts, profile))
+	if sum, err := f.runtimes.Checksum(job.Runtime); err == nil {
+		fp.RuntimeChecksum = sum
+	}
+	return fp
+}
+
+// kernelVersion reads the kernel release once, it only changes with a reboot
+func (f *fingerprinter) kernelVersion() string {
+	f.kernelOnce.Do(func() {
+		if data, err := f.platform.ReadFile(kernelReleasePath); err == nil {
+			f.kernel = strings.TrimSpace(string(data))
+		}
+	})
+	return f.kernel
+}
+
+// mountsHash hashes the host directories mounted into every job's chroot,
+// independent of their order in the configuration
+func mountsHash(mounts []string) string {
+	cleaned := make([]string, 0, len(mounts))
+	for _, mount := range mounts {
+		cleaned = append(cleaned, filepath.Clean(mount))
+	}
+	sort.Strings(cleaned)
+	sum := sha256.Sum256([]byte(strings.Join(cleaned, "\n")))
+	return hex.EncodeToString(sum[:])
+}
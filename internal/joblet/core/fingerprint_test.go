@@ -0,0 +1,71 @@
+//go:build linux
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/filesystem"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+func TestMountsHash(t *testing.T) {
+	a := mountsHash([]string{"/usr/bin", "/bin", "/lib/"})
+	if b := mountsHash([]string{"/lib", "/bin", "/usr/bin"}); a != b {
+		t.Errorf("mountsHash() depends on order or trailing slashes: %s != %s", a, b)
+	}
+	if c := mountsHash([]string{"/usr/bin", "/bin"}); a == c {
+		t.Error("mountsHash() ignores a removed mount")
+	}
+}
+
+func TestFingerprintWithoutRuntime(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Filesystem.AllowedMounts = []string{"/bin"}
+	cfg.Filesystem.ChrootProfile = config.ChrootProfileNone
+	cfg.Runtime.BasePath = t.TempDir()
+	f := newFingerprinter(cfg, platform.NewPlatform())
+
+	fp := f.Fingerprint(&domain.Job{Uuid: "job-1", Command: "echo"})
+	if fp.Runtime != "" || fp.RuntimeChecksum != "" {
+		t.Errorf("runtime fields set for a job without runtime: %+v", fp)
+	}
+	if fp.MountsHash != mountsHash([]string{"/bin"}) || fp.JobletVersion == "" {
+		t.Errorf("Fingerprint() = %+v", fp)
+	}
+
+	// A missing runtime leaves its fields empty rather than failing
+	fp = f.Fingerprint(&domain.Job{Uuid: "job-2", Command: "python3", Runtime: "python-3.11"})
+	if fp.Runtime != "" || fp.RuntimeChecksum != "" {
+		t.Errorf("runtime fields set for a missing runtime: %+v", fp)
+	}
+}
+
+func TestFingerprintChrootProfile(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Filesystem.AllowedMounts = []string{"/bin"}
+	cfg.Filesystem.ChrootProfile = "debian"
+	cfg.Runtime.BasePath = t.TempDir()
+	runtimeDir := filepath.Join(cfg.Runtime.BasePath, "python-3.11")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	yml := "name: python-3.11\nlanguage: python\nversion: \"3.11\"\nchrootProfile: alpine\n"
+	if err := os.WriteFile(filepath.Join(runtimeDir, "runtime.yml"), []byte(yml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := newFingerprinter(cfg, platform.NewPlatform())
+
+	host := f.Fingerprint(&domain.Job{Uuid: "job-1", Command: "echo"})
+	if want := mountsHash(filesystem.ChrootMounts([]string{"/bin"}, "debian")); host.MountsHash != want {
+		t.Errorf("MountsHash = %s, want the debian profile's %s", host.MountsHash, want)
+	}
+	rt := f.Fingerprint(&domain.Job{Uuid: "job-2", Command: "python3", Runtime: "python-3.11"})
+	if want := mountsHash(filesystem.ChrootMounts([]string{"/bin"}, "alpine")); rt.MountsHash != want {
+		t.Errorf("MountsHash = %s, want the runtime's alpine profile's %s", rt.MountsHash, want)
+	}
+}
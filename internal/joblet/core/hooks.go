@@ -0,0 +1,20 @@
+//go:build linux
+
+package core
+
+import (
+	"github.com/ehsaniara/joblet/internal/joblet/hooks"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// newHooks returns the runner of the node's job hooks, nil when none is
+// configured or when they cannot run
+func newHooks(cfg *config.Config, log *logger.Logger) *hooks.Runner {
+	runner, err := hooks.New(cfg.Hooks, cfg.Server.NodeId)
+	if err != nil {
+		log.Error("job hooks disabled", "error", err)
+		return nil
+	}
+	return runner
+}
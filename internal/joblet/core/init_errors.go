@@ -0,0 +1,50 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/errors"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+// initErrorFD is the descriptor of the error pipe in the init process: the
+// first entry of ExtraFiles, after stdin, stdout and stderr. Init finds it
+// through JOB_INIT_ERROR_FD.
+const initErrorFD = 3
+
+// initErrorCommand waits for the init process and checks the error pipe it
+// was given. Init writes "<stage>: <error>" there when it fails to set up
+// the job (network, cgroup, mounts) before the job's command runs; such
+// failures are returned as infrastructure errors so the job can be retried.
+type initErrorCommand struct {
+	platform.Command
+	pipe *os.File
+}
+
+// Wait waits for the init process and returns its setup failure, if any,
+// in place of the bare exit status.
+func (c *initErrorCommand) Wait() error {
+	err := c.Command.Wait()
+	report := c.readReport()
+	if err == nil || report == "" {
+		return err
+	}
+	stage, message, ok := strings.Cut(report, ": ")
+	if !ok {
+		stage, message = "init", report
+	}
+	return errors.WrapInfrastructureError(stage, fmt.Errorf("%s: %w", message, err))
+}
+
+// readReport reads what init wrote to the pipe. The deadline covers
+// processes that inherited the write end and are still running.
+func (c *initErrorCommand) readReport() string {
+	defer c.pipe.Close()
+	_ = c.pipe.SetReadDeadline(time.Now().Add(time.Second))
+	data, _ := io.ReadAll(io.LimitReader(c.pipe, 64<<10))
+	return strings.TrimSpace(string(data))
+}
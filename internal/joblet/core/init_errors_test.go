@@ -0,0 +1,58 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	joberrors "github.com/ehsaniara/joblet/pkg/errors"
+	"github.com/ehsaniara/joblet/pkg/platform/platformfakes"
+)
+
+func newInitErrorCommand(t *testing.T, report string, waitErr error) *initErrorCommand {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report != "" {
+		if _, err := w.WriteString(report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	fake := &platformfakes.FakeCommand{}
+	fake.WaitReturns(waitErr)
+	return &initErrorCommand{Command: fake, pipe: r}
+}
+
+func TestInitErrorCommand_ReportedFailureIsInfrastructure(t *testing.T) {
+	exitErr := exec.Command("false").Run()
+	cmd := newInitErrorCommand(t, "network: failed to wait for network ready: timeout\n", exitErr)
+
+	err := cmd.Wait()
+	var infraErr *joberrors.InfrastructureError
+	if !errors.As(err, &infraErr) {
+		t.Fatalf("Wait() = %v, want an InfrastructureError", err)
+	}
+	if infraErr.Stage != "network" {
+		t.Errorf("Stage = %q, want network", infraErr.Stage)
+	}
+	// The exit status is still reachable for the exit code
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		t.Errorf("Wait() = %v, want the exit error wrapped", err)
+	}
+}
+
+func TestInitErrorCommand_JobFailureIsUnchanged(t *testing.T) {
+	jobErr := errors.New("exit status 2")
+	if err := newInitErrorCommand(t, "", jobErr).Wait(); err != jobErr {
+		t.Errorf("Wait() = %v, want the job's own error", err)
+	}
+	if err := newInitErrorCommand(t, "", nil).Wait(); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"time"
+
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 )
 
@@ -31,6 +33,15 @@ type StartJobRequest struct {
 	// Runtime specification
 	Runtime string // runtime specification (e.g., "python-3.11-ml")
 
+	// Runtime alias the client asked for and what resolved it to Runtime
+	// ("tenant ml pin", "server alias"); empty when Runtime was asked for
+	RequestedRuntime string
+	RuntimePinnedBy  string
+
+	// Uploaded dependency file the server selected Runtime for, when the
+	// client named no runtime (empty = none)
+	RuntimeDetectedFrom string
+
 	// Environment variables
 	Environment       map[string]string // Regular environment variables (visible in logs)
 	SecretEnvironment map[string]string // Secret environment variables (hidden from logs)
@@ -42,6 +53,61 @@ type StartJobRequest struct {
 	GPUCount    int32 // Number of GPUs requested (0 = no GPU)
 	GPUMemoryMB int64 // Minimum GPU memory requirement in MB (0 = any)
 
+	// Filesystem sizing
+	ShmSizeBytes int64  // /dev/shm tmpfs size in bytes (0 = server default)
+	TmpSizeBytes int64  // /tmp tmpfs size in bytes (0 = server default)
+	Scratch      string // scratch device for the work directory (empty = default work directory)
+	FreezeFS     bool   // keep the filesystem for browsing if the job fails
+
+	// Workflow outputs document, read when the job ends (empty = none)
+	OutputsFile string
+
+	// Profiling
+	Profile string // profiler wrapping the command: "strace", "perf" or empty
+
+	// Upload delivered to the command's stdin, relative to the workspace (empty = none)
+	StdinPath string
+
+	// Client signature of the submission, stored with the job (nil = unsigned)
+	Signature *domain.JobSignature
+
+	// Tenant of the submitting client, selects delegated cloud credentials
+	Tenant string
+
+	// Group of related jobs for bulk list and stop (empty = ungrouped)
+	Group string
+
+	// Labels for selecting the job in bulk operations
+	Labels map[string]string
+
+	// Annotations noting where the job comes from, such as its CI pipeline
+	Annotations map[string]string
+
+	// Job array the job belongs to and its index in it (empty = no array)
+	ArrayUuid  string
+	ArrayIndex int
+
+	// External destinations the job's output is copied to (s3:// or syslog:// URLs)
+	LogSinks []string
+
+	// Longest the job may wait to start before it expires (0 = no limit)
+	QueueTTL time.Duration
+
+	// IPC channel whose /dev/shm the job shares with the jobs joining it (empty = none)
+	IPCChannel string
+
+	// Objects downloaded into the workspace before the job starts (s3:// or gs:// inputs)
+	Inputs []string
+
+	// Workspace files uploaded to object storage when the job completes
+	OutputTargets []string
+
+	// Egress policy of the job, canonical rules matched in order (nil = none)
+	Egress []string
+
+	// Secret variables delivered as tmpfs files, path -> variable (nil = none)
+	SecretFiles map[string]string
+
 	// Workflow integration
 	WorkflowUuid     string   // UUID of parent workflow (empty for individual jobs)
 	WorkingDirectory string   // Execution directory path
@@ -54,6 +120,10 @@ type ResourceLimits struct {
 	MaxMemory int32  // Memory in MB (0 = unlimited)
 	MaxIOBPS  int32  // IO bandwidth in bytes/sec (0 = unlimited)
 	CPUCores  string // CPU core specification (empty = no restriction)
+
+	// Raw cgroup v2 files written to the job's cgroup, limited to the
+	// server's cgroup.allowedParams (nil = none)
+	CgroupParams map[string]string
 }
 
 // StopJobRequest encapsulates parameters for stopping a job
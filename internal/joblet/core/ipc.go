@@ -0,0 +1,107 @@
+//go:build linux
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+// ipcChannels mounts the tmpfs of an IPC channel when its first job starts
+// and unmounts it, discarding what the jobs left in it, when its last job
+// ends. Jobs bind mount the channel at /dev/shm.
+type ipcChannels struct {
+	config   *config.Config
+	platform platform.Platform
+	logger   *logger.Logger
+
+	mutex sync.Mutex
+	users map[string]map[string]bool // Channel directory -> IDs of the jobs using it
+	jobs  map[string]string          // Job ID -> channel directory
+}
+
+// newIPCChannels returns nil when IPC channels are disabled
+func newIPCChannels(cfg *config.Config, p platform.Platform, log *logger.Logger) *ipcChannels {
+	if !cfg.JobIPC.Enabled {
+		return nil
+	}
+	return &ipcChannels{
+		config:   cfg,
+		platform: p,
+		logger:   log.WithField("component", "ipc-channels"),
+		users:    make(map[string]map[string]bool),
+		jobs:     make(map[string]string),
+	}
+}
+
+// acquire mounts the job's channel unless another job has already. Safe on
+// a nil ipcChannels and for jobs without a channel; acquiring twice for the
+// same job does nothing.
+func (c *ipcChannels) acquire(jb *domain.Job) error {
+	if jb.IPCChannel == "" {
+		return nil
+	}
+	if c == nil {
+		return fmt.Errorf("ipc channels are disabled on this node (job_ipc.enabled)")
+	}
+	dir, size, err := c.config.JobIPCChannel(jb.Tenant, jb.IPCChannel)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, exists := c.jobs[jb.Uuid]; exists {
+		return nil
+	}
+	if len(c.users[dir]) == 0 {
+		if err := c.platform.MkdirAll(dir, 01777); err != nil {
+			return fmt.Errorf("failed to create ipc channel %s: %w", jb.IPCChannel, err)
+		}
+		opts := fmt.Sprintf("mode=1777,size=%d", size)
+		flags := uintptr(syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC)
+		if err := c.platform.Mount("joblet-ipc", dir, "tmpfs", flags, opts); err != nil {
+			return fmt.Errorf("failed to mount ipc channel %s: %w", jb.IPCChannel, err)
+		}
+		c.users[dir] = make(map[string]bool)
+		c.logger.Info("ipc channel opened", "channel", jb.IPCChannel, "dir", dir, "sizeBytes", size)
+	}
+	c.users[dir][jb.Uuid] = true
+	c.jobs[jb.Uuid] = dir
+	return nil
+}
+
+// release drops the job from its channel, unmounting the channel when no
+// job uses it anymore. Safe on a nil ipcChannels and for jobs that did not
+// acquire a channel.
+func (c *ipcChannels) release(jobID string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	dir, exists := c.jobs[jobID]
+	if !exists {
+		return
+	}
+	delete(c.jobs, jobID)
+	delete(c.users[dir], jobID)
+	if len(c.users[dir]) > 0 {
+		return
+	}
+	delete(c.users, dir)
+	if err := c.platform.Unmount(dir, syscall.MNT_DETACH); err != nil {
+		c.logger.Warn("failed to unmount ipc channel", "dir", dir, "error", err)
+		return
+	}
+	if err := c.platform.RemoveAll(dir); err != nil {
+		c.logger.Warn("failed to remove ipc channel directory", "dir", dir, "error", err)
+	}
+	c.logger.Info("ipc channel closed", "dir", dir)
+}
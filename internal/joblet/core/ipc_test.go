@@ -0,0 +1,58 @@
+//go:build linux
+
+package core
+
+import (
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform/platformfakes"
+)
+
+func TestIPCChannels_MountedWhileJobsUseThem(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.JobIPC.Enabled = true
+	fake := &platformfakes.FakePlatform{}
+	channels := newIPCChannels(&cfg, fake, logger.New())
+
+	producer := &domain.Job{Uuid: "producer", Tenant: "ml", IPCChannel: "arrow"}
+	consumer := &domain.Job{Uuid: "consumer", Tenant: "ml", IPCChannel: "arrow"}
+	for _, jb := range []*domain.Job{producer, consumer, producer} {
+		if err := channels.acquire(jb); err != nil {
+			t.Fatalf("acquire(%s) error = %v", jb.Uuid, err)
+		}
+	}
+	if fake.MountCallCount() != 1 {
+		t.Fatalf("Mount called %d times, want once for the shared channel", fake.MountCallCount())
+	}
+	_, dir, fstype, _, _ := fake.MountArgsForCall(0)
+	if fstype != "tmpfs" {
+		t.Errorf("channel mounted as %q, want tmpfs", fstype)
+	}
+
+	channels.release(producer.Uuid)
+	if fake.UnmountCallCount() != 0 {
+		t.Fatal("channel unmounted while the consumer still uses it")
+	}
+	channels.release(consumer.Uuid)
+	channels.release(consumer.Uuid)
+	if fake.UnmountCallCount() != 1 {
+		t.Fatalf("Unmount called %d times, want once", fake.UnmountCallCount())
+	}
+	if unmounted, _ := fake.UnmountArgsForCall(0); unmounted != dir {
+		t.Errorf("unmounted %q, want %q", unmounted, dir)
+	}
+}
+
+func TestIPCChannels_Disabled(t *testing.T) {
+	var channels *ipcChannels
+	if err := channels.acquire(&domain.Job{Uuid: "a"}); err != nil {
+		t.Errorf("job without a channel: error = %v", err)
+	}
+	if err := channels.acquire(&domain.Job{Uuid: "b", IPCChannel: "arrow"}); err == nil {
+		t.Error("expected error joining a channel with IPC channels disabled")
+	}
+	channels.release("b")
+}
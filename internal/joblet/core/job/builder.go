@@ -2,10 +2,13 @@ package job
 
 import (
 	"fmt"
-	"path/filepath"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
 	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
 )
@@ -40,6 +43,7 @@ type BuildRequest struct {
 	Network           string
 	Volumes           []string
 	Runtime           string
+	RequestedRuntime  string // alias resolved to Runtime (empty = none)
 	Environment       map[string]string
 	SecretEnvironment map[string]string
 	JobType           domain.JobType
@@ -47,14 +51,63 @@ type BuildRequest struct {
 	WorkingDirectory  string
 	Uploads           []domain.FileUpload
 	Dependencies      []string
-	GPUCount          int32 // Number of GPUs requested
-	GPUMemoryMB       int64 // GPU memory requirement in MB
+	GPUCount          int32  // Number of GPUs requested
+	GPUMemoryMB       int64  // GPU memory requirement in MB
+	ShmSizeBytes      int64  // /dev/shm size in bytes (0 = config default)
+	TmpSizeBytes      int64  // /tmp size in bytes (0 = config default)
+	Scratch           string // scratch device for the work directory (empty = default)
+	FreezeFS          bool   // keep the filesystem for browsing if the job fails
+	OutputsFile       string // workflow outputs document read when the job ends (empty = none)
+	Profile           string // profiler wrapping the command (empty = none)
+	StdinPath         string // upload delivered to stdin (empty = none)
+	Signature         *domain.JobSignature
+	Tenant            string
+	Group             string // Group for bulk operations (empty = none)
+	Labels            map[string]string
+	Annotations       map[string]string // Notes such as the submitting CI pipeline (nil = none)
+	ArrayUuid         string            // Job array the job belongs to (empty = none)
+	ArrayIndex        int               // Index of the job in its array
+	LogSinks          []string          // External log destinations (empty = persist only)
+	CgroupParams      map[string]string // Raw cgroup v2 files to set (nil = none)
+	QueueTTL          time.Duration     // Longest the job may wait to start (0 = no limit)
+	IPCChannel        string            // IPC channel shared with other jobs (empty = none)
+	Inputs            []string          // Objects fetched into the workspace (empty = none)
+	OutputTargets     []string          // Files uploaded when the job completes (empty = none)
+	Egress            []string          // Egress policy rules, matched in order (empty = none)
+	SecretFiles       map[string]string // Secret variables delivered as files, path -> variable (nil = none)
 }
 
 // Build creates a new job from the request.
 // Main construction method: generates UUID, populates all job fields,
 // applies resource defaults, and validates configuration.
 func (b *Builder) Build(req BuildRequest) (*domain.Job, error) {
+	if err := b.validateProfile(req.Profile); err != nil {
+		return nil, err
+	}
+	if len(req.LogSinks) > 0 && !b.config.LogSinks.Enabled {
+		return nil, fmt.Errorf("log sinks are disabled on this node (log_sinks.enabled)")
+	}
+	if err := b.validateCgroupParams(req.CgroupParams); err != nil {
+		return nil, err
+	}
+	if err := b.validateScratch(req.Scratch); err != nil {
+		return nil, err
+	}
+	if req.IPCChannel != "" {
+		if _, _, err := b.config.JobIPCChannel(req.Tenant, req.IPCChannel); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Inputs) > 0 && !b.config.Inputs.Enabled {
+		return nil, fmt.Errorf("job inputs are disabled on this node (inputs.enabled)")
+	}
+	if len(req.OutputTargets) > 0 && !b.config.OutputTargets.Enabled {
+		return nil, fmt.Errorf("output targets are disabled on this node (output_targets.enabled)")
+	}
+	if len(req.Egress) > 0 && (!b.config.Network.Enabled || req.Network == "none") {
+		return nil, fmt.Errorf("an egress policy needs a job network other than none, with networking enabled on this node (network.enabled)")
+	}
+
 	// Generate UUID
 	jobUuid := b.idGenerator.Next()
 
@@ -85,6 +138,7 @@ func (b *Builder) Build(req BuildRequest) (*domain.Job, error) {
 		Network:           req.Network,
 		Volumes:           volumes,
 		Runtime:           req.Runtime,
+		RequestedRuntime:  req.RequestedRuntime,
 		Environment:       b.copyEnvironment(req.Environment),
 		SecretEnvironment: b.copyEnvironment(req.SecretEnvironment),
 		WorkflowUuid:      req.WorkflowUuid,
@@ -95,10 +149,31 @@ func (b *Builder) Build(req BuildRequest) (*domain.Job, error) {
 		GPUMemoryMB:       req.GPUMemoryMB,        // GPU memory requirement
 		GPUIndices:        []int32{},              // Will be populated during allocation
 		NodeId:            b.config.Server.NodeId, // Unique identifier of the Joblet node
+		Profile:           req.Profile,
+		StdinPath:         req.StdinPath,
+		Signature:         req.Signature.DeepCopy(),
+		Tenant:            req.Tenant,
+		Group:             req.Group,
+		Labels:            b.copyEnvironment(req.Labels),
+		Annotations:       b.copyEnvironment(req.Annotations),
+		ArrayUuid:         req.ArrayUuid,
+		ArrayIndex:        req.ArrayIndex,
+		LogSinks:          b.copyStrings(req.LogSinks),
+		CgroupParams:      b.copyEnvironment(req.CgroupParams),
+		Scratch:           req.Scratch,
+		FreezeFS:          req.FreezeFS,
+		OutputsFile:       req.OutputsFile,
+		QueueTTL:          req.QueueTTL,
+		IPCChannel:        req.IPCChannel,
+		Inputs:            b.copyStrings(req.Inputs),
+		OutputTargets:     b.copyStrings(req.OutputTargets),
+		Egress:            b.copyStrings(req.Egress),
+		SecretFiles:       b.copyEnvironment(req.SecretFiles),
 	}
 
 	// Apply resource limits with defaults
 	job.Limits = b.applyResourceDefaults(req.Limits)
+	job.ShmSizeBytes, job.TmpSizeBytes = b.applyFilesystemDefaults(req.ShmSizeBytes, req.TmpSizeBytes)
 
 	// Basic resource limit validation (simplified)
 	if job.Limits.CPU.Value() < 0 || job.Limits.CPU.Value() > 100 {
@@ -146,9 +221,65 @@ func (b *Builder) applyResourceDefaults(limits domain.ResourceLimits) domain.Res
 	return *result
 }
 
+// applyFilesystemDefaults falls back to the configured /dev/shm and /tmp sizes
+// when the request leaves them unset
+func (b *Builder) applyFilesystemDefaults(shmSize, tmpSize int64) (int64, int64) {
+	if shmSize <= 0 {
+		shmSize = b.config.Filesystem.ShmSizeBytes
+	}
+	if tmpSize <= 0 {
+		tmpSize = b.config.Filesystem.TmpSizeBytes
+	}
+	return shmSize, tmpSize
+}
+
+// validateCgroupParams checks that every raw cgroup file is well formed and
+// allowed on this node
+func (b *Builder) validateCgroupParams(params map[string]string) error {
+	for name, value := range params {
+		if err := values.ValidateCgroupParam(name, value); err != nil {
+			return err
+		}
+		if !b.config.Cgroup.AllowsParam(name) {
+			return fmt.Errorf("cgroup parameter %s is not allowed on this node (cgroup.allowedParams)", name)
+		}
+	}
+	return nil
+}
+
+// validateProfile checks that profiling is enabled on this node and the
+// requested tool is allowed
+func (b *Builder) validateProfile(tool string) error {
+	if tool == "" {
+		return nil
+	}
+	if !b.config.Profiling.Enabled {
+		return fmt.Errorf("profiling is disabled on this node (profiling.enabled)")
+	}
+	if !slices.Contains(b.config.Profiling.Tools, tool) {
+		return fmt.Errorf("profiler %s is not allowed on this node (allowed: %s)", tool, strings.Join(b.config.Profiling.Tools, ", "))
+	}
+	return nil
+}
+
+// validateScratch checks that the scratch device is configured on this node
+func (b *Builder) validateScratch(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, exists := b.config.Filesystem.Scratch[name]; !exists {
+		configured := slices.Sorted(maps.Keys(b.config.Filesystem.Scratch))
+		if len(configured) == 0 {
+			return fmt.Errorf("scratch device %s is not configured, this node has none (filesystem.scratch)", name)
+		}
+		return fmt.Errorf("scratch device %s is not configured on this node (configured: %s)", name, strings.Join(configured, ", "))
+	}
+	return nil
+}
+
 // generateCgroupPath generates the cgroup path for a job
 func (b *Builder) generateCgroupPath(jobUUID string) string {
-	return filepath.Join(b.config.Cgroup.BaseDir, "job-"+jobUUID)
+	return b.config.Cgroup.JobCgroupPath(jobUUID)
 }
 
 // copyStrings creates a copy of string slice
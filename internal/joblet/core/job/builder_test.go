@@ -0,0 +1,81 @@
+package job
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+func TestBuildCgroupParams(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.Cgroup.AllowedParams = []string{"cpu.weight", "io.latency"}
+	builder := NewBuilder(&cfg, NewUUIDGenerator("", ""))
+
+	params := map[string]string{"cpu.weight": "50"}
+	job, err := builder.Build(BuildRequest{Command: "ls", CgroupParams: params})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	params["cpu.weight"] = "100"
+	if job.CgroupParams["cpu.weight"] != "50" {
+		t.Errorf("job cgroup params = %v, want a copy of the request's", job.CgroupParams)
+	}
+
+	_, err = builder.Build(BuildRequest{Command: "ls", CgroupParams: map[string]string{"memory.high": "1G"}})
+	if err == nil || !strings.Contains(err.Error(), "not allowed on this node") {
+		t.Errorf("Build() with a file outside the allowlist error = %v", err)
+	}
+	_, err = builder.Build(BuildRequest{Command: "ls", CgroupParams: map[string]string{"cpu.weight": "50\n100"}})
+	if err == nil {
+		t.Error("Build() with a multi-line value succeeded")
+	}
+}
+
+func TestBuildScratch(t *testing.T) {
+	cfg := config.DefaultConfig
+	builder := NewBuilder(&cfg, NewUUIDGenerator("", ""))
+
+	_, err := builder.Build(BuildRequest{Command: "ls", Scratch: "nvme"})
+	if err == nil || !strings.Contains(err.Error(), "this node has none") {
+		t.Errorf("Build() without scratch devices error = %v", err)
+	}
+
+	cfg.Filesystem.Scratch = map[string]config.ScratchConfig{"nvme": {Path: "/mnt/nvme/joblet"}, "ssd": {Path: "/mnt/ssd"}}
+	job, err := builder.Build(BuildRequest{Command: "ls", Scratch: "nvme"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if job.Scratch != "nvme" || job.DeepCopy().Scratch != "nvme" {
+		t.Errorf("job scratch = %q", job.Scratch)
+	}
+
+	_, err = builder.Build(BuildRequest{Command: "ls", Scratch: "hdd"})
+	if err == nil || !strings.Contains(err.Error(), "configured: nvme, ssd") {
+		t.Errorf("Build() with an unknown scratch device error = %v", err)
+	}
+}
+
+func TestBuildEgress(t *testing.T) {
+	cfg := config.DefaultConfig
+	builder := NewBuilder(&cfg, NewUUIDGenerator("", ""))
+
+	egress := []string{"allow:api.internal:443", "deny:*"}
+	job, err := builder.Build(BuildRequest{Command: "ls", Network: "bridge", Egress: egress})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	egress[0] = "allow:*"
+	if job.Egress[0] != "allow:api.internal:443" || len(job.DeepCopy().Egress) != 2 {
+		t.Errorf("job egress = %v, want a copy of the request's", job.Egress)
+	}
+
+	_, err = builder.Build(BuildRequest{Command: "ls", Network: "none", Egress: egress})
+	if err == nil || !strings.Contains(err.Error(), "other than none") {
+		t.Errorf("Build() with an egress policy and no network error = %v", err)
+	}
+	cfg.Network.Enabled = false
+	if _, err = builder.Build(BuildRequest{Command: "ls", Network: "bridge", Egress: egress}); err == nil {
+		t.Error("Build() with an egress policy and networking disabled succeeded")
+	}
+}
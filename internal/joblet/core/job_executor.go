@@ -25,11 +25,13 @@ import (
 // ExecutionEngineV2 is the main job execution engine using the coordinator pattern
 // for managing job lifecycle, isolation, networking, and process execution
 type ExecutionEngineV2 struct {
-	coordinator execution.JobExecutor
-	platform    platform.Platform
-	config      *config.Config
-	store       adapters.JobStorer
-	logger      *logger.Logger
+	// Runs job processes: the namespace coordinator, or the experimental
+	// container backend when the node's config selects it
+	backend  execution.JobExecutor
+	platform platform.Platform
+	config   *config.Config
+	store    adapters.JobStorer
+	logger   *logger.Logger
 }
 
 // StartProcessOptions contains options for starting a process
@@ -106,13 +108,20 @@ func NewExecutionEngineV2(
 		platform,
 		logger,
 	)
+	coordinator.SetInitPath(config.Filesystem.InitBinary)
+
+	var backend execution.JobExecutor = coordinator
+	if config.Execution.ContainerBackend() {
+		logger.Warn("running jobs with the experimental container execution backend", "runtime", config.Execution.Container.Runtime, "image", config.Execution.Container.Image)
+		backend = newContainerBackend(config, platform, store, logger)
+	}
 
 	return &ExecutionEngineV2{
-		coordinator: coordinator,
-		platform:    platform,
-		config:      config,
-		store:       store,
-		logger:      logger.WithField("component", "execution-engine-v2"),
+		backend:  backend,
+		platform: platform,
+		config:   config,
+		store:    store,
+		logger:   logger.WithField("component", "execution-engine-v2"),
 	}
 }
 
@@ -126,7 +135,7 @@ func (ee *ExecutionEngineV2) StartProcess(ctx context.Context, opts *StartProces
 		return ee.executeCICommand(ctx, opts)
 	}
 
-	// Use coordinator for full isolation
+	// Use the backend for full isolation
 	execOpts := &execution.StartProcessOptions{
 		Job:               opts.Job,
 		Uploads:           opts.Uploads,
@@ -135,8 +144,8 @@ func (ee *ExecutionEngineV2) StartProcess(ctx context.Context, opts *StartProces
 		PreProcessUploads: opts.PreProcessUploads,
 	}
 
-	log.Debug("delegating to coordinator")
-	return ee.coordinator.StartJob(ctx, execOpts)
+	log.Debug("delegating to execution backend")
+	return ee.backend.StartJob(ctx, execOpts)
 }
 
 // StartProcessWithUploads executes a job with file uploads and streaming enabled
@@ -149,6 +158,12 @@ func (ee *ExecutionEngineV2) StartProcessWithUploads(ctx context.Context, job *d
 	return ee.StartProcess(ctx, opts)
 }
 
+// ReleaseJobResources releases what StartProcess set up for a job whose
+// process has exited: its network, GPUs, workspace and isolated environment.
+func (ee *ExecutionEngineV2) ReleaseJobResources(ctx context.Context, jobID string) error {
+	return ee.backend.StopJob(ctx, jobID)
+}
+
 // executeCICommand executes a job in CI mode with minimal isolation
 func (ee *ExecutionEngineV2) executeCICommand(ctx context.Context, opts *StartProcessOptions) (platform.Command, error) {
 	log := ee.logger.WithField("jobID", opts.Job.Uuid).WithField("mode", "ci-isolated")
@@ -162,20 +177,8 @@ func (ee *ExecutionEngineV2) executeCICommand(ctx context.Context, opts *StartPr
 
 	// Process uploads if any
 	if len(opts.Uploads) > 0 {
-		for _, upload := range opts.Uploads {
-			fullPath := filepath.Join(workDir, upload.Path)
-			if upload.IsDirectory {
-				if err := os.MkdirAll(fullPath, os.FileMode(upload.Mode)); err != nil {
-					return nil, fmt.Errorf("failed to create directory %s: %w", upload.Path, err)
-				}
-			} else {
-				if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
-					return nil, fmt.Errorf("failed to create parent directory for %s: %w", upload.Path, err)
-				}
-				if err := os.WriteFile(fullPath, upload.Content, os.FileMode(upload.Mode)); err != nil {
-					return nil, fmt.Errorf("failed to write file %s: %w", upload.Path, err)
-				}
-			}
+		if err := writeUploads(workDir, opts.Uploads); err != nil {
+			return nil, err
 		}
 		log.Debug("processed uploads for CI mode", "count", len(opts.Uploads))
 	}
@@ -202,6 +205,27 @@ func (ee *ExecutionEngineV2) executeCICommand(ctx context.Context, opts *StartPr
 	return cmd, nil
 }
 
+// writeUploads writes uploaded files and directories into a job's work
+// directory, for the backends running jobs without the init process doing it
+func writeUploads(workDir string, uploads []domain.FileUpload) error {
+	for _, upload := range uploads {
+		fullPath := filepath.Join(workDir, upload.Path)
+		if upload.IsDirectory {
+			if err := os.MkdirAll(fullPath, os.FileMode(upload.Mode)); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", upload.Path, err)
+			}
+		} else {
+			if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+				return fmt.Errorf("failed to create parent directory for %s: %w", upload.Path, err)
+			}
+			if err := os.WriteFile(fullPath, upload.Content, os.FileMode(upload.Mode)); err != nil {
+				return fmt.Errorf("failed to write file %s: %w", upload.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
 // buildEnvironmentForCI creates a simplified environment for CI execution
 func (ee *ExecutionEngineV2) buildEnvironmentForCI(job *domain.Job) []string {
 	// Get base environment from platform
@@ -299,9 +323,17 @@ func (pma *processManagerAdapter) LaunchProcess(ctx context.Context, config *exe
 		"cloneflags", fmt.Sprintf("0x%x", sysProcAttr.Cloneflags),
 		"component", "process-manager-adapter")
 
+	// Init reports setup failures on this pipe, so they can be told apart
+	// from the job's own failures
+	errorsRead, errorsWrite, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create init error pipe: %w", err)
+	}
+	environment := append(config.Environment, fmt.Sprintf("JOB_INIT_ERROR_FD=%d", initErrorFD))
+
 	procConfig := &process.LaunchConfig{
 		InitPath:    config.InitPath,
-		Environment: config.Environment,
+		Environment: environment,
 		Stdout:      outputWriter,
 		Stderr:      outputWriter,
 		JobID:       config.JobID,
@@ -309,10 +341,14 @@ func (pma *processManagerAdapter) LaunchProcess(ctx context.Context, config *exe
 		Command:     config.Command,
 		Args:        config.Args,
 		SysProcAttr: sysProcAttr, // Isolation configured based on job type
+		ExtraFiles:  append([]*os.File{errorsWrite}, config.ExtraFiles...),
 	}
 
 	result, err := pma.manager.LaunchProcess(ctx, procConfig)
+	// The child has its own copy of the write end now
+	errorsWrite.Close()
 	if err != nil {
+		errorsRead.Close()
 		pma.logger.Error("failed to launch process with namespace isolation",
 			"jobID", config.JobID,
 			"error", err,
@@ -326,7 +362,7 @@ func (pma *processManagerAdapter) LaunchProcess(ctx context.Context, config *exe
 		"component", "process-manager-adapter")
 
 	return &execution.ProcessResult{
-		Command: result.Command,
+		Command: &initErrorCommand{Command: result.Command, pipe: errorsRead},
 		PID:     int(result.PID),
 	}, nil
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ehsaniara/joblet/internal/joblet/adapters"
 	"github.com/ehsaniara/joblet/internal/joblet/core/cleanup"
+	"github.com/ehsaniara/joblet/internal/joblet/core/credentials"
 	"github.com/ehsaniara/joblet/internal/joblet/core/filesystem"
 	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
 	"github.com/ehsaniara/joblet/internal/joblet/core/job"
@@ -21,9 +22,15 @@ import (
 	"github.com/ehsaniara/joblet/internal/joblet/core/upload"
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 	"github.com/ehsaniara/joblet/internal/joblet/gpu"
+	"github.com/ehsaniara/joblet/internal/joblet/hooks"
+	"github.com/ehsaniara/joblet/internal/joblet/inputs"
 	metricsdomain "github.com/ehsaniara/joblet/internal/joblet/metrics/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/orphans"
+	"github.com/ehsaniara/joblet/internal/joblet/publish"
 	"github.com/ehsaniara/joblet/internal/joblet/scheduler"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
 	"github.com/ehsaniara/joblet/pkg/config"
+	joberrors "github.com/ehsaniara/joblet/pkg/errors"
 	"github.com/ehsaniara/joblet/pkg/logger"
 	"github.com/ehsaniara/joblet/pkg/platform"
 )
@@ -47,6 +54,16 @@ type Joblet struct {
 	executionEngine *ExecutionEngineV2
 	scheduler       *scheduler.Scheduler
 	cleanup         *cleanup.Coordinator
+	gpuManager      gpu.GPUManagerInterface
+	uploadScanner   *upload.Scanner     // nil when upload scanning is disabled
+	credentials     *credentials.Broker // nil when no tenant has a cloud role
+	dispatcher      *dispatcher         // nil when jobs never queue for a running slot
+	maintenance     *maintenanceGate    // nil when no maintenance window is configured
+	hooks           *hooks.Runner       // nil when no hook is configured
+	ipc             *ipcChannels        // nil when IPC channels are disabled
+	inputs          *inputs.Fetcher     // nil when job inputs are disabled
+	publisher       *publish.Publisher  // nil when output targets are disabled
+	fingerprints    *fingerprinter
 }
 
 // NewPlatformJoblet creates a new Linux platform joblet with specialized components.
@@ -70,14 +87,27 @@ func NewPlatformJoblet(store adapters.JobStorer, metricsStore *adapters.MetricsS
 		resourceManager: c.resourceManager,
 		executionEngine: c.executionEngine,
 		cleanup:         c.cleanup,
+		gpuManager:      c.gpuManager,
+		uploadScanner:   c.uploadScanner,
+		credentials:     c.credentials,
+		dispatcher:      newDispatcher(cfg, jobletLogger),
+		maintenance:     newMaintenanceGate(cfg, jobletLogger),
+		hooks:           newHooks(cfg, jobletLogger),
+		ipc:             newIPCChannels(cfg, platformInterface, jobletLogger),
+		inputs:          inputs.NewFetcher(cfg.Inputs, jobletLogger),
+		publisher:       publish.NewPublisher(cfg.OutputTargets, jobletLogger),
+		fingerprints:    newFingerprinter(cfg, platformInterface),
 	}
 
 	// Create scheduler with simplified executor
 	s := scheduler.New(&jobletExecutor{j})
 	j.scheduler = s
 
-	// Setup cgroup controllers
-	if err := c.cgroup.EnsureControllers(); err != nil {
+	// Setup cgroup controllers; the container runtime confines the jobs of
+	// the container backend itself
+	if cfg.Execution.ContainerBackend() {
+		j.logger.Info("cgroup controller setup skipped for the container execution backend")
+	} else if err := c.cgroup.EnsureControllers(); err != nil {
 		j.logger.Fatal("cgroup controller setup failed", "error", err)
 	}
 
@@ -86,6 +116,22 @@ func NewPlatformJoblet(store adapters.JobStorer, metricsStore *adapters.MetricsS
 		j.logger.Fatal("scheduler start failed", "error", err)
 	}
 
+	// Start queued jobs as running slots free up
+	if j.dispatcher != nil {
+		go j.runDispatcher(context.Background())
+	}
+
+	// Start jobs deferred by maintenance windows once they end
+	if j.maintenance != nil {
+		go j.runMaintenance(context.Background())
+	}
+
+	// Pre-create job root skeletons; jobs fall back to creating their
+	// directories when the pool cannot be prepared
+	if err := c.resourceManager.skeletons.Start(context.Background()); err != nil {
+		j.logger.Warn("skeleton pool disabled", "error", err)
+	}
+
 	// Start periodic cleanup
 	go j.cleanup.SchedulePeriodicCleanup(
 		context.Background(),
@@ -128,6 +174,7 @@ func (j *Joblet) StartJob(ctx context.Context, req interfaces.StartJobRequest) (
 		Network:           req.Network,
 		Volumes:           req.Volumes,
 		Runtime:           req.Runtime,
+		RequestedRuntime:  req.RequestedRuntime,
 		Environment:       req.Environment,
 		SecretEnvironment: req.SecretEnvironment,
 		JobType:           req.JobType,
@@ -136,6 +183,28 @@ func (j *Joblet) StartJob(ctx context.Context, req interfaces.StartJobRequest) (
 		Dependencies:      req.Dependencies,
 		GPUCount:          req.GPUCount,    // GPU requirements
 		GPUMemoryMB:       req.GPUMemoryMB, // GPU memory requirement
+		ShmSizeBytes:      req.ShmSizeBytes,
+		TmpSizeBytes:      req.TmpSizeBytes,
+		Scratch:           req.Scratch,
+		FreezeFS:          req.FreezeFS,
+		OutputsFile:       req.OutputsFile,
+		Profile:           req.Profile,
+		StdinPath:         req.StdinPath,
+		Signature:         req.Signature,
+		Tenant:            req.Tenant,
+		Group:             req.Group,
+		Labels:            req.Labels,
+		Annotations:       req.Annotations,
+		ArrayUuid:         req.ArrayUuid,
+		ArrayIndex:        req.ArrayIndex,
+		LogSinks:          req.LogSinks,
+		CgroupParams:      req.Resources.CgroupParams,
+		QueueTTL:          req.QueueTTL,
+		IPCChannel:        req.IPCChannel,
+		Inputs:            req.Inputs,
+		OutputTargets:     req.OutputTargets,
+		Egress:            req.Egress,
+		SecretFiles:       req.SecretFiles,
 	}
 
 	log := j.logger.WithFields(
@@ -158,16 +227,43 @@ func (j *Joblet) StartJob(ctx context.Context, req interfaces.StartJobRequest) (
 		return nil, fmt.Errorf("command cannot be empty")
 	}
 
-	// 2. Build the job
+	// 2. Scan uploads before anything is set up for the job
+	if err := j.uploadScanner.Scan(ctx, internalReq.Uploads); err != nil {
+		return nil, err
+	}
+
+	// 3. Build the job
 	jb, err := j.jobBuilder.Build(internalReq)
 	if err != nil {
 		return nil, fmt.Errorf("job creation failed: %w", err)
 	}
+	if req.RequestedRuntime != "" {
+		jb.AddEvent(domain.JobEventRuntime, fmt.Sprintf("%s resolved to %s by %s", req.RequestedRuntime, req.Runtime, req.RuntimePinnedBy))
+	}
+	if req.RuntimeDetectedFrom != "" {
+		jb.AddEvent(domain.JobEventRuntime, fmt.Sprintf("%s selected for the uploaded %s", req.Runtime, req.RuntimeDetectedFrom))
+	}
+	if jb.FreezeFS {
+		if err := j.cleanup.ArmFreeze(jb.Uuid); err != nil {
+			return nil, fmt.Errorf("cannot keep the job filesystem: %w", err)
+		}
+	}
 
-	// 3. Route to appropriate handler
+	// 4. Route to appropriate handler. Delegated credentials are minted when
+	// the job starts, scheduled and queued jobs get theirs when they leave
+	// the scheduler or the admission queue
 	if internalReq.Schedule != "" {
 		return j.scheduleJob(ctx, jb, internalReq)
 	}
+	if window, open := j.maintenance.active(); open {
+		return j.deferJob(ctx, jb, internalReq, window)
+	}
+	if j.saturated() {
+		return j.queueJob(ctx, jb, internalReq)
+	}
+	if err := j.credentials.Inject(ctx, jb); err != nil {
+		return nil, err
+	}
 	return j.executeJob(ctx, jb, internalReq)
 }
 
@@ -214,6 +310,7 @@ func (j *Joblet) scheduleJob(ctx context.Context, job *domain.Job, req job.Build
 		}
 		return nil, fmt.Errorf("scheduling failed: %w", e)
 	}
+	j.watchStartDeadline(job)
 
 	return job, nil
 }
@@ -242,9 +339,32 @@ func (j *Joblet) executeJob(ctx context.Context, job *domain.Job, req job.BuildR
 
 	j.store.CreateNewJob(job)
 
+	// Site hooks see the job before it starts; a required one failing fails it
+	if err := j.hooks.PreStart(ctx, job); err != nil {
+		j.handleExecutionFailure(job)
+		return nil, fmt.Errorf("pre-start hook failed: %w", err)
+	}
+
+	// The job's IPC channel is mounted before its filesystem binds it
+	if err := j.ipc.acquire(job); err != nil {
+		j.handleExecutionFailure(job)
+		return nil, fmt.Errorf("ipc channel setup failed: %w", err)
+	}
+
+	// Inputs are downloaded into the workspace before the job sees it
+	stats, err := j.inputs.Fetch(ctx, job, j.resourceManager.getWorkspaceDir(job.Uuid))
+	if err != nil {
+		j.handleExecutionFailure(job)
+		return nil, fmt.Errorf("input fetch failed: %w", err)
+	}
+	if stats.Objects > 0 {
+		job.AddEvent(domain.JobEventInputs, fmt.Sprintf("%d objects, %d from cache, %d bytes downloaded",
+			stats.Objects, stats.CacheHits, stats.DownloadedBytes))
+	}
+
 	// Start execution
 	log.Debug("calling execution engine with job volumes", "jobId", job.Uuid, "volumes", job.Volumes, "volumeCount", len(job.Volumes))
-	cmd, err := j.executionEngine.StartProcessWithUploads(ctx, job, req.Uploads)
+	cmd, err := j.startProcess(ctx, job, req.Uploads)
 	if err != nil {
 		j.handleExecutionFailure(job)
 		return nil, fmt.Errorf("execution failed: %w", err)
@@ -290,7 +410,7 @@ func (j *Joblet) executeJob(ctx context.Context, job *domain.Job, req job.BuildR
 	}
 
 	// Monitor asynchronously
-	go j.monitorJob(ctx, cmd, job)
+	go j.monitorJob(ctx, cmd, job, req.Uploads)
 
 	log.Info("job started", "pid", job.Pid)
 	return job, nil
@@ -326,6 +446,29 @@ func (j *Joblet) executeScheduledJob(ctx context.Context, jobObj *domain.Job) er
 		return fmt.Errorf("job is not scheduled (status: %s)", freshJob.Status)
 	}
 
+	if pastStartDeadline(freshJob, time.Now()) {
+		j.expireJob(freshJob)
+		return nil
+	}
+
+	// A due job waits for the end of a maintenance window, and its turn on
+	// a saturated node, like any other
+	if window, open := j.maintenance.active(); open {
+		log.Info("maintenance window open, deferring scheduled job", "window", window.Name)
+		j.deferScheduledJob(freshJob, window)
+		return nil
+	}
+	if j.saturated() {
+		log.Info("node saturated, queuing scheduled job")
+		j.requeueScheduledJob(freshJob)
+		return nil
+	}
+
+	if err := j.credentials.Inject(ctx, freshJob); err != nil {
+		j.handleExecutionFailure(freshJob)
+		return err
+	}
+
 	// Transition state
 	freshJob.Status = domain.StatusInitializing
 	j.store.UpdateJob(freshJob)
@@ -362,6 +505,20 @@ func (j *Joblet) StopJob(ctx context.Context, req interfaces.StopJobRequest) err
 		return fmt.Errorf("failed to remove scheduled job")
 	}
 
+	// Handle queued jobs
+	if jb.IsQueued() {
+		if j.dispatcher.remove(req.JobID) || j.maintenance.remove(req.JobID) {
+			jb.Status = domain.StatusCanceled
+			j.store.UpdateJob(jb)
+			if !jb.Type.IsRuntimeBuild() {
+				_ = j.cleanup.CleanupJob(req.JobID)
+			}
+			log.Info("queued job cancelled")
+			return nil
+		}
+		return fmt.Errorf("failed to remove queued job")
+	}
+
 	// Handle running jobs
 	if !jb.IsRunning() {
 		return fmt.Errorf("job is not running: %s (status: %s)", req.JobID, jb.Status)
@@ -419,7 +576,7 @@ func (j *Joblet) DeleteJob(ctx context.Context, req interfaces.DeleteJobRequest)
 	}
 
 	// Prevent deletion of running jobs
-	if jb.IsRunning() || jb.IsScheduled() {
+	if jb.IsRunning() || jb.IsScheduled() || jb.IsQueued() {
 		return fmt.Errorf("cannot delete job %s (status: %s) - stop the job first", req.JobID, jb.Status)
 	}
 
@@ -474,8 +631,8 @@ func (j *Joblet) DeleteAllJobs(ctx context.Context, req interfaces.DeleteAllJobs
 	var errors []string
 
 	for _, job := range allJobs {
-		// Skip running and scheduled jobs
-		if job.IsRunning() || job.IsScheduled() {
+		// Skip running, scheduled and queued jobs
+		if job.IsRunning() || job.IsScheduled() || job.IsQueued() {
 			skippedCount++
 			log.Debug("skipping job", "jobID", job.Uuid, "status", job.Status)
 			continue
@@ -532,12 +689,34 @@ func (j *Joblet) DeleteAllJobs(ctx context.Context, req interfaces.DeleteAllJobs
 // monitorJob monitors a running job until completion asynchronously.
 // Waits for process completion, determines exit code, updates job status,
 // and triggers cleanup (special handling for runtime builds to preserve artifacts).
-func (j *Joblet) monitorJob(ctx context.Context, cmd platform.Command, job *domain.Job) {
+// Jobs whose init process fails to set them up are relaunched while they
+// have infrastructure retries left.
+func (j *Joblet) monitorJob(ctx context.Context, cmd platform.Command, job *domain.Job, uploads []domain.FileUpload) {
 	log := j.logger.WithField("jobID", job.Uuid)
 	log.Debug("starting job monitoring")
 
 	// Wait for completion
 	err := cmd.Wait()
+	for err != nil && joberrors.IsInfrastructureError(err) {
+		// The submitting request is long gone, relaunches run on their own
+		retryCtx := context.Background()
+
+		// Release what the failed attempt set up (network, GPUs) before
+		// the resources are reset for the next one
+		if releaseErr := j.executionEngine.ReleaseJobResources(retryCtx, job.Uuid); releaseErr != nil {
+			log.Debug("releasing resources of failed attempt", "error", releaseErr)
+		}
+		if !j.prepareRetry(job, err) {
+			break
+		}
+		next, startErr := j.startProcess(retryCtx, job, uploads)
+		if startErr != nil {
+			err = startErr
+			break
+		}
+		j.updateJobRunning(job, next)
+		err = next.Wait()
+	}
 
 	// Give a brief moment for final log chunks to be written and published
 	// cmd.Wait() ensures pipes are closed, but async pubsub publishes might still be in flight
@@ -562,6 +741,33 @@ func (j *Joblet) monitorJob(ctx context.Context, cmd platform.Command, job *doma
 		job.EndTime = &[]time.Time{time.Now()}[0]
 	}
 
+	// Declared output files are in object storage by the time the job is
+	// seen to complete
+	if job.Status == domain.StatusCompleted && len(job.OutputTargets) > 0 {
+		j.publishOutputs(job)
+	}
+
+	// The workflow reads the outputs as soon as it sees the job ended, so
+	// they are captured before the final status is stored
+	if job.OutputsFile != "" {
+		j.captureOutputs(job)
+	}
+
+	// The collector is still running, so the network activity it sampled
+	// is kept with the final status
+	if j.metricsStore != nil {
+		if summary := j.metricsStore.NetworkSummary(job.Uuid); summary != nil {
+			job.NetworkSummary = &domain.JobNetworkSummary{
+				RxBytes:         summary.RxBytes,
+				TxBytes:         summary.TxBytes,
+				RxPackets:       summary.RxPackets,
+				TxPackets:       summary.TxPackets,
+				PeakConnections: summary.PeakConnections,
+				Retransmits:     summary.Retransmits,
+			}
+		}
+	}
+
 	// Update state
 	j.store.UpdateJob(job)
 
@@ -584,17 +790,113 @@ func (j *Joblet) monitorJob(ctx context.Context, cmd platform.Command, job *doma
 				"jobType", job.Type, "runtimesPath", "/opt/joblet/runtimes")
 		}
 	} else {
-		// For regular jobs: full cleanup
+		// For regular jobs: full cleanup, except for the filesystem of a
+		// failed job armed with freeze-fs
+		j.cleanup.SettleFreeze(job.Uuid, job.Status == domain.StatusFailed)
 		if err := j.cleanup.CleanupJob(job.Uuid); err != nil {
 			log.Error("cleanup failed during monitoring", "error", err)
 		}
 	}
 
+	// The job's slot is free for a queued one
+	j.dispatcher.notify()
+	j.ipc.release(job.Uuid)
+	j.hooks.PostStop(job)
+
 	log.Info("job completed", "exitCode", exitCode)
 }
 
 // Helper methods
 
+// startProcess launches the job, relaunching it while launches fail on the
+// node's side and infrastructure retries are left. Every attempt is recorded
+// in the job's event timeline.
+func (j *Joblet) startProcess(ctx context.Context, job *domain.Job, uploads []domain.FileUpload) (platform.Command, error) {
+	job.Fingerprint = j.fingerprints.Fingerprint(job)
+	for {
+		job.Attempts++
+		job.AddEvent(domain.JobEventStarted, fmt.Sprintf("attempt %d", job.Attempts))
+		cmd, err := j.executionEngine.StartProcessWithUploads(ctx, job, uploads)
+		if err == nil {
+			return cmd, nil
+		}
+		if !j.prepareRetry(job, err) {
+			return nil, err
+		}
+	}
+}
+
+// prepareRetry records an infrastructure failure in the job's timeline and,
+// when the job has retries left and was not stopped meanwhile, resets its
+// resources for the next attempt. It returns false when the failure stands:
+// the error is the job's own, retries are used up or the reset failed.
+func (j *Joblet) prepareRetry(job *domain.Job, err error) bool {
+	if !joberrors.IsInfrastructureError(err) {
+		return false
+	}
+	log := j.logger.WithField("jobID", job.Uuid)
+	job.AddEvent(domain.JobEventInfraFailure, err.Error())
+
+	retries := j.config.Joblet.InfraRetries
+	if int(job.Attempts) > retries {
+		j.store.UpdateJob(job)
+		return false
+	}
+	if current, exists := j.store.Job(job.Uuid); exists &&
+		(current.Status == domain.StatusStopped || current.Status == domain.StatusStopping) {
+		return false
+	}
+
+	delay := j.config.Joblet.InfraRetryDelay
+	job.AddEvent(domain.JobEventRetrying, fmt.Sprintf("attempt %d of %d in %v", job.Attempts+1, retries+1, delay))
+	j.store.UpdateJob(job)
+	log.Warn("infrastructure failure, retrying job", "attempt", job.Attempts, "retries", retries, "error", err)
+
+	if cleanupErr := j.cleanup.CleanupJob(job.Uuid); cleanupErr != nil {
+		log.Warn("cleanup before retry failed", "error", cleanupErr)
+	}
+	time.Sleep(delay)
+	if setupErr := j.resourceManager.SetupJobResources(job); setupErr != nil {
+		job.AddEvent(domain.JobEventInfraFailure, fmt.Sprintf("resource setup failed: %v", setupErr))
+		j.store.UpdateJob(job)
+		return false
+	}
+	return true
+}
+
+// captureOutputs reads the workflow outputs document of a job that ended,
+// before its filesystem is cleaned up
+func (j *Joblet) captureOutputs(job *domain.Job) {
+	data, err := j.cleanup.ReadJobFile(job.Uuid, job.OutputsFile, workflow.MaxOutputsFileBytes)
+	if err != nil {
+		j.logger.Warn("failed to read job outputs", "jobID", job.Uuid, "file", job.OutputsFile, "error", err)
+		job.AddEvent(domain.JobEventOutputs, fmt.Sprintf("outputs not read: %v", err))
+		return
+	}
+	job.Outputs = data
+}
+
+// publishOutputs uploads the output files of a job that completed, before
+// its filesystem is cleaned up. A job whose outputs could not be published
+// fails.
+func (j *Joblet) publishOutputs(job *domain.Job) {
+	var stats publish.Stats
+	work, err := j.cleanup.OpenJobDir(job.Uuid, "/work")
+	if err == nil {
+		// The submitting request is long gone
+		stats, err = j.publisher.Publish(context.Background(), job, work)
+		work.Close()
+	}
+	if err != nil {
+		j.logger.Warn("failed to publish job outputs", "jobID", job.Uuid, "error", err)
+		job.Status = domain.StatusFailed
+		job.AddEvent(domain.JobEventPublish, fmt.Sprintf("outputs not published: %v", err))
+		return
+	}
+	job.AddEvent(domain.JobEventPublish, fmt.Sprintf("%d files, %d bytes, manifests: %s",
+		stats.Files, stats.Bytes, strings.Join(stats.Manifests, ", ")))
+}
+
 // updateJobRunning transitions job to running state and captures process PID.
 // Called after successful process start to record execution details.
 func (j *Joblet) updateJobRunning(job *domain.Job, cmd platform.Command) {
@@ -623,11 +925,29 @@ func (j *Joblet) handleExecutionFailure(job *domain.Job) {
 				"jobType", job.Type, "jobID", job.Uuid)
 		}
 	} else {
+		j.cleanup.SettleFreeze(job.Uuid, true)
 		if err := j.cleanup.CleanupJob(job.Uuid); err != nil {
 			j.logger.Error("cleanup failed after execution failure",
 				"jobID", job.Uuid, "error", err)
 		}
 	}
+	j.ipc.release(job.Uuid)
+	j.hooks.PostStop(job)
+}
+
+// GPUCount returns how many GPUs the node can allocate to jobs, zero when GPU
+// support is disabled. It feeds the capacity report.
+func (j *Joblet) GPUCount() int {
+	if j.gpuManager == nil {
+		return 0
+	}
+	return j.gpuManager.GetGPUCount()
+}
+
+// OrphanedResources returns the counts of the cgroups and network resources
+// of gone jobs that the periodic cleanup found and removed
+func (j *Joblet) OrphanedResources() orphans.Stats {
+	return j.cleanup.OrphanedResources()
 }
 
 // getActiveJobIDs returns a map of all active job IDs for cleanup coordination.
@@ -649,11 +969,14 @@ func initializeComponents(store adapters.JobStorer, cfg *config.Config, platform
 	// Create core resources
 	cgroupResource := resource.New(cfg.Cgroup)
 	filesystemIsolator := filesystem.NewIsolator(cfg, platform)
+	logger.Info("chroot profile selected", "setting", cfg.Filesystem.ChrootProfile, "profile", filesystemIsolator.ChrootProfile())
 	jobIsolation := unprivileged.NewJobIsolation()
 
 	// Create managers
 	processManager := process.NewProcessManager(platform, cfg)
 	uploadManager := upload.NewManager(platform, logger)
+	uploadScanner := upload.NewScanner(cfg.UploadScan, logger)
+	credentialBroker := credentials.NewBroker(cfg, logger)
 
 	// Create GPU manager
 	gpuManager := createGPUManager(cfg.GPU, platform, logger)
@@ -668,6 +991,7 @@ func initializeComponents(store adapters.JobStorer, cfg *config.Config, platform
 	resourceManager := &ResourceManager{
 		cgroup:     cgroupResource,
 		filesystem: filesystemIsolator,
+		skeletons:  filesystem.NewSkeletonPool(cfg, platform),
 		platform:   platform,
 		config:     cfg,
 		logger:     logger.WithField("component", "resource-manager"),
@@ -703,6 +1027,9 @@ func initializeComponents(store adapters.JobStorer, cfg *config.Config, platform
 		resourceManager: resourceManager,
 		executionEngine: executionEngine,
 		cleanup:         c,
+		gpuManager:      gpuManager,
+		uploadScanner:   uploadScanner,
+		credentials:     credentialBroker,
 	}
 }
 
@@ -715,6 +1042,9 @@ type components struct {
 	resourceManager *ResourceManager
 	executionEngine *ExecutionEngineV2
 	cleanup         *cleanup.Coordinator
+	gpuManager      gpu.GPUManagerInterface
+	uploadScanner   *upload.Scanner
+	credentials     *credentials.Broker
 }
 
 // jobletExecutor adapts joblet to scheduler.JobExecutor interface
@@ -0,0 +1,158 @@
+//go:build linux
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/job"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/maintenance"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// maintenanceTick is how often the end of a maintenance window is checked
+// for while jobs are deferred
+const maintenanceTick = 15 * time.Second
+
+// maintenanceGate defers the start of jobs while a maintenance window is open
+// and releases them when it ends
+type maintenanceGate struct {
+	calendar *maintenance.Calendar
+	now      func() time.Time
+
+	mu       sync.Mutex
+	deferred []string // IDs of the deferred jobs, in submission order
+}
+
+// newMaintenanceGate returns nil when no maintenance window is configured
+func newMaintenanceGate(cfg *config.Config, log *logger.Logger) *maintenanceGate {
+	calendar, err := maintenance.NewCalendar(cfg.Maintenance)
+	if err != nil {
+		log.Error("maintenance windows disabled", "error", err)
+		return nil
+	}
+	if calendar == nil {
+		return nil
+	}
+	return &maintenanceGate{calendar: calendar, now: time.Now}
+}
+
+// active returns the open maintenance window. Safe on a nil gate.
+func (g *maintenanceGate) active() (maintenance.Window, bool) {
+	if g == nil {
+		return maintenance.Window{}, false
+	}
+	return g.calendar.Active(g.now())
+}
+
+// remove drops a deferred job, reporting whether it was deferred. Safe on a
+// nil gate.
+func (g *maintenanceGate) remove(jobID string) bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	i := slices.Index(g.deferred, jobID)
+	if i < 0 {
+		return false
+	}
+	g.deferred = slices.Delete(g.deferred, i, i+1)
+	return true
+}
+
+// take returns the deferred jobs and forgets them
+func (g *maintenanceGate) take() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ids := g.deferred
+	g.deferred = nil
+	return ids
+}
+
+func (g *maintenanceGate) add(jobID string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.deferred = append(g.deferred, jobID)
+	return len(g.deferred)
+}
+
+// deferJob stores a job submitted during a maintenance window as queued.
+// Uploads are staged now, as for scheduled jobs.
+func (j *Joblet) deferJob(ctx context.Context, jb *domain.Job, req job.BuildRequest, window maintenance.Window) (*domain.Job, error) {
+	if len(req.Uploads) > 0 {
+		if err := j.resourceManager.PrepareScheduledJobUploads(ctx, jb, req.Uploads); err != nil {
+			return nil, fmt.Errorf("upload preparation failed: %w", err)
+		}
+	}
+
+	jb.Status = domain.StatusQueued
+	jb.AddEvent(domain.JobEventMaintenance, window.String())
+	j.store.CreateNewJob(jb)
+	waiting := j.maintenance.add(jb.Uuid)
+	j.watchStartDeadline(jb)
+
+	j.logger.Info("maintenance window open, job deferred", "jobID", jb.Uuid, "window", window.Name,
+		"until", window.End, "deferred", waiting)
+	return jb, nil
+}
+
+// deferScheduledJob queues a scheduled job that became due during a
+// maintenance window
+func (j *Joblet) deferScheduledJob(jb *domain.Job, window maintenance.Window) {
+	jb.Status = domain.StatusQueued
+	jb.AddEvent(domain.JobEventMaintenance, "scheduled job due during "+window.String())
+	j.store.UpdateJob(jb)
+	j.maintenance.add(jb.Uuid)
+}
+
+// runMaintenance releases the deferred jobs once no maintenance window is
+// open, until ctx is done
+func (j *Joblet) runMaintenance(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, open := j.maintenance.active(); open {
+			continue
+		}
+		j.releaseDeferredJobs(ctx)
+	}
+}
+
+// releaseDeferredJobs starts the jobs deferred by a maintenance window, or
+// hands them to the admission queue when the node is saturated
+func (j *Joblet) releaseDeferredJobs(ctx context.Context) {
+	ids := j.maintenance.take()
+	if len(ids) == 0 {
+		return
+	}
+	j.logger.Info("maintenance window ended, starting deferred jobs", "count", len(ids))
+
+	for _, id := range ids {
+		jb, exists := j.store.Job(id)
+		if !exists || !jb.IsQueued() {
+			continue
+		}
+		if j.saturated() {
+			jb.AddEvent(domain.JobEventQueued, fmt.Sprintf("maintenance window ended, %d waiting", j.dispatcher.queue.Size()))
+			j.store.UpdateJob(jb)
+			j.enqueue(jb)
+			continue
+		}
+		if err := j.startQueuedJob(ctx, jb); err != nil {
+			j.logger.Warn("failed to start deferred job", "jobID", id, "error", err)
+		}
+	}
+}
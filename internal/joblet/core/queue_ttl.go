@@ -0,0 +1,63 @@
+//go:build linux
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// watchStartDeadline expires a scheduled or queued job that has not started
+// by the end of its queue TTL. Jobs without a TTL wait as long as it takes.
+func (j *Joblet) watchStartDeadline(jb *domain.Job) {
+	deadline, ok := jb.StartDeadline()
+	if !ok {
+		return
+	}
+	jobID := jb.Uuid
+	time.AfterFunc(time.Until(deadline), func() { j.expireWaitingJob(jobID) })
+}
+
+// expireWaitingJob expires a job still waiting in the scheduler, the
+// admission queue or a maintenance window. A job that left them meanwhile
+// is checked again before it starts, see pastStartDeadline.
+func (j *Joblet) expireWaitingJob(jobID string) {
+	jb, exists := j.store.Job(jobID)
+	if !exists {
+		return
+	}
+	var removed bool
+	switch {
+	case jb.IsScheduled():
+		removed = j.scheduler.RemoveJob(jobID)
+	case jb.IsQueued():
+		removed = j.dispatcher.remove(jobID) || j.maintenance.remove(jobID)
+	}
+	if removed {
+		j.expireJob(jb)
+	}
+}
+
+// pastStartDeadline reports whether a job about to start is past its queue
+// TTL
+func pastStartDeadline(jb *domain.Job, now time.Time) bool {
+	deadline, ok := jb.StartDeadline()
+	return ok && now.After(deadline)
+}
+
+// expireJob ends a job that did not start within its queue TTL
+func (j *Joblet) expireJob(jb *domain.Job) {
+	deadline, _ := jb.StartDeadline()
+	now := time.Now()
+	jb.Status = domain.StatusExpired
+	jb.EndTime = &now
+	jb.AddEvent(domain.JobEventExpired, fmt.Sprintf("not started within its %s queue TTL, deadline was %s",
+		jb.QueueTTL, deadline.Format(time.RFC3339)))
+	j.store.UpdateJob(jb)
+	if !jb.Type.IsRuntimeBuild() {
+		_ = j.cleanup.CleanupJob(jb.Uuid)
+	}
+	j.logger.Info("job expired before starting", "jobID", jb.Uuid, "queueTTL", jb.QueueTTL)
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -20,6 +22,10 @@ type cgroup struct {
 	logger      *logger.Logger
 	initialized bool
 	config      config.CgroupConfig
+
+	// scopes talks to systemd with the systemd driver, dialed on first use
+	scopes   scopeManager
+	scopesMu sync.Mutex
 }
 
 func New(cfg config.CgroupConfig) Resource {
@@ -40,6 +46,13 @@ func (c *cgroup) EnsureControllers() error {
 		"controllers", c.config.EnableControllers,
 		"cleanupTimeout", c.config.CleanupTimeout)
 
+	// systemd delegates each job scope with its controllers enabled
+	if c.config.SystemdDriver() {
+		c.initialized = true
+		log.Info("cgroup controllers delegated by systemd", "slice", c.config.Slice)
+		return nil
+	}
+
 	// Use configured base directory
 	if err := c.moveJobletProcessToSubgroup(); err != nil {
 		log.Warn("failed to move joblet to subgroup", "error", err)
@@ -65,6 +78,7 @@ type Resource interface {
 	SetCPUCores(cgroupPath string, cores string) error
 	SetMemoryLimit(cgroupPath string, memoryLimitMB int) error
 	SetGPUDevices(cgroupPath string, gpuIndices []int) error
+	SetParams(cgroupPath string, params map[string]string) error
 	CleanupCgroup(jobID string)
 	EnsureControllers() error
 }
@@ -187,7 +201,7 @@ func (c *cgroup) Create(cgroupJobDir string, maxCPU int32, maxMemory int32, maxI
 	log.Debug("creating cgroup with strict resource enforcement")
 
 	// Ensure we're working within our delegated subtree
-	if !strings.HasPrefix(cgroupJobDir, c.config.BaseDir) {
+	if !strings.HasPrefix(cgroupJobDir, c.config.JobsDir()) {
 		return fmt.Errorf("security violation: cgroup path outside delegated subtree: %s", cgroupJobDir)
 	}
 
@@ -196,8 +210,13 @@ func (c *cgroup) Create(cgroupJobDir string, maxCPU int32, maxMemory int32, maxI
 		return fmt.Errorf("controller setup failed: %w", err)
 	}
 
-	// Create the cgroup directory
-	if err := os.MkdirAll(cgroupJobDir, 0755); err != nil {
+	// Create the cgroup directory, or have systemd create it as a scope
+	if c.config.SystemdDriver() {
+		if err := c.startScope(cgroupJobDir); err != nil {
+			_ = c.stopScope(jobIDOf(cgroupJobDir))
+			return fmt.Errorf("failed to create job scope: %w", err)
+		}
+	} else if err := os.MkdirAll(cgroupJobDir, 0755); err != nil {
 		return fmt.Errorf("failed to create cgroup directory: %w", err)
 	}
 
@@ -255,7 +274,7 @@ func (c *cgroup) Create(cgroupJobDir string, maxCPU int32, maxMemory int32, maxI
 			"failedLimits", len(enforcementErrors))
 
 		// Clean up the cgroup since we can't enforce limits
-		c.CleanupCgroup(filepath.Base(cgroupJobDir))
+		c.CleanupCgroup(jobIDOf(cgroupJobDir))
 
 		// Combine all errors
 		var errorMsgs []string
@@ -295,6 +314,28 @@ func (c *cgroup) SetCPUCores(cgroupPath string, cores string) error {
 	return nil
 }
 
+// SetParams writes raw cgroup v2 files, in name order. A file that does not
+// exist means its controller is not enabled for the job or the kernel lacks it.
+func (c *cgroup) SetParams(cgroupPath string, params map[string]string) error {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(cgroupPath, name)
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("cgroup parameter %s is not available on this node (controller not enabled or unsupported)", name)
+		}
+		if err := os.WriteFile(path, []byte(params[name]), 0644); err != nil {
+			return fmt.Errorf("failed to set cgroup parameter %s to %q: %w", name, params[name], err)
+		}
+		c.logger.Info("set cgroup parameter", "cgroupPath", cgroupPath, "name", name, "value", params[name])
+	}
+	return nil
+}
+
 // SetIOLimit sets IO limits for a cgroup
 func (c *cgroup) SetIOLimit(cgroupPath string, ioBPS int) error {
 	log := c.logger.WithFields("cgroupPath", cgroupPath, "ioBPS", ioBPS)
@@ -445,6 +486,11 @@ func (c *cgroup) CleanupCgroup(jobID string) {
 
 		done := make(chan bool)
 		go func() {
+			if c.config.SystemdDriver() {
+				if err := c.stopScope(jobID); err != nil {
+					cleanupLogger.Warn("failed to stop job scope", "error", err)
+				}
+			}
 			cleanupJobCgroup(jobID, cleanupLogger, &c.config)
 			done <- true
 		}()
@@ -462,11 +508,11 @@ func (c *cgroup) CleanupCgroup(jobID string) {
 // cleanupJobCgroup clean process first SIGTERM and SIGKILL then remove the cgroupPath items
 func cleanupJobCgroup(jobID string, logger *logger.Logger, cfg *config.CgroupConfig) {
 	// Use the delegated cgroup path
-	cgroupPath := filepath.Join(cfg.BaseDir, "job-"+jobID)
+	cgroupPath := cfg.JobCgroupPath(jobID)
 	cleanupLogger := logger.WithField("cgroupPath", cgroupPath)
 
 	// Security check: ensure we're only cleaning up within our delegated subtree
-	if !strings.HasPrefix(cgroupPath, cfg.BaseDir+"/job-") {
+	if !strings.HasPrefix(cgroupPath, cfg.JobsDir()+"/job-") {
 		cleanupLogger.Error("security violation: attempted to clean up non-job cgroup", "path", cgroupPath)
 		return
 	}
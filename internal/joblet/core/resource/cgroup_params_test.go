@@ -0,0 +1,38 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetParams(t *testing.T) {
+	cgroupDir := t.TempDir()
+	for _, name := range []string{"cpu.weight", "io.latency"} {
+		require.NoError(t, os.WriteFile(filepath.Join(cgroupDir, name), []byte("default\n"), 0644))
+	}
+	cg := &cgroup{
+		logger: logger.New().WithField("component", "test"),
+		config: config.CgroupConfig{BaseDir: cgroupDir},
+	}
+
+	err := cg.SetParams(cgroupDir, map[string]string{"cpu.weight": "50", "io.latency": "8:0 target=10"})
+	require.NoError(t, err)
+
+	weight, _ := os.ReadFile(filepath.Join(cgroupDir, "cpu.weight"))
+	latency, _ := os.ReadFile(filepath.Join(cgroupDir, "io.latency"))
+	assert.Equal(t, "50", string(weight))
+	assert.Equal(t, "8:0 target=10", string(latency))
+
+	// Files of controllers that are not enabled do not exist
+	err = cg.SetParams(cgroupDir, map[string]string{"hugetlb.2MB.max": "0"})
+	assert.ErrorContains(t, err, "not available on this node")
+	_, statErr := os.Stat(filepath.Join(cgroupDir, "hugetlb.2MB.max"))
+	assert.True(t, os.IsNotExist(statErr), "SetParams created a missing cgroup file")
+}
@@ -0,0 +1,121 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/sdbus"
+)
+
+// scopeManager creates and stops the scope units of jobs with the systemd
+// cgroup driver
+type scopeManager interface {
+	StartTransientScope(s sdbus.Scope) error
+	StopUnit(name string) error
+}
+
+// scopeTimeout is how long systemd gets to make the cgroup of a new scope
+const scopeTimeout = 5 * time.Second
+
+// keeperGroup is the subgroup of a job scope holding its keeper process
+const keeperGroup = "keeper"
+
+// withScopes runs fn with the connection to systemd, dialed on first use. A
+// connection that failed, rather than systemd refusing the call, is dropped
+// so the next call dials again.
+func (c *cgroup) withScopes(fn func(scopeManager) error) error {
+	c.scopesMu.Lock()
+	defer c.scopesMu.Unlock()
+
+	if c.scopes == nil {
+		conn, err := sdbus.Dial()
+		if err != nil {
+			return fmt.Errorf("failed to connect to systemd: %w", err)
+		}
+		c.scopes = conn
+	}
+	err := fn(c.scopes)
+	var dbusErr *sdbus.Error
+	if err != nil && !errors.As(err, &dbusErr) {
+		if closer, ok := c.scopes.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		c.scopes = nil
+	}
+	return err
+}
+
+// startScope asks systemd for a delegated scope under the configured slice
+// whose cgroup is cgroupJobDir. A scope needs a process, so a keeper that
+// sleeps until the scope stops holds it, in a subgroup of its own so the
+// scope can enable controllers for its children.
+func (c *cgroup) startScope(cgroupJobDir string) error {
+	jobID := jobIDOf(cgroupJobDir)
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		return fmt.Errorf("failed to find a keeper for the job scope: %w", err)
+	}
+	keeper := exec.Command(sleep, "infinity")
+	if err := keeper.Start(); err != nil {
+		return fmt.Errorf("failed to start the keeper of the job scope: %w", err)
+	}
+	// systemd kills the keeper when the scope stops
+	go func() { _ = keeper.Wait() }()
+
+	err = c.withScopes(func(m scopeManager) error {
+		return m.StartTransientScope(sdbus.Scope{
+			Name:        c.config.JobScope(jobID),
+			Slice:       c.config.Slice,
+			Description: "joblet job " + jobID,
+			PIDs:        []int{keeper.Process.Pid},
+			Delegate:    true,
+		})
+	})
+	if err != nil {
+		_ = keeper.Process.Kill()
+		return err
+	}
+
+	procs := filepath.Join(cgroupJobDir, "cgroup.procs")
+	deadline := time.Now().Add(scopeTimeout)
+	for {
+		if _, err := os.Stat(procs); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("systemd did not create the cgroup of %s within %s", c.config.JobScope(jobID), scopeTimeout)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	keeperDir := filepath.Join(cgroupJobDir, keeperGroup)
+	if err := os.MkdirAll(keeperDir, 0755); err != nil {
+		return fmt.Errorf("failed to create keeper subgroup: %w", err)
+	}
+	pid := []byte(strconv.Itoa(keeper.Process.Pid))
+	if err := os.WriteFile(filepath.Join(keeperDir, "cgroup.procs"), pid, 0644); err != nil {
+		return fmt.Errorf("failed to move keeper to its subgroup: %w", err)
+	}
+	return nil
+}
+
+// stopScope asks systemd to stop the scope of a job, killing what is left
+// in it
+func (c *cgroup) stopScope(jobID string) error {
+	return c.withScopes(func(m scopeManager) error {
+		return m.StopUnit(c.config.JobScope(jobID))
+	})
+}
+
+// jobIDOf returns the job ID of a job cgroup directory, job-<id> or
+// job-<id>.scope
+func jobIDOf(cgroupJobDir string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(filepath.Base(cgroupJobDir), "job-"), ".scope")
+}
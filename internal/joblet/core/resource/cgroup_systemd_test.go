@@ -0,0 +1,69 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/sdbus"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScopes makes the cgroup directory of started scopes like systemd
+type fakeScopes struct {
+	sliceDir string
+	started  []sdbus.Scope
+	stopped  []string
+}
+
+func (f *fakeScopes) StartTransientScope(s sdbus.Scope) error {
+	f.started = append(f.started, s)
+	dir := filepath.Join(f.sliceDir, s.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "cgroup.procs"), nil, 0644)
+}
+
+func (f *fakeScopes) StopUnit(name string) error {
+	f.stopped = append(f.stopped, name)
+	return nil
+}
+
+func TestStartScope(t *testing.T) {
+	scopes := &fakeScopes{sliceDir: t.TempDir()}
+	cg := &cgroup{
+		logger: logger.New().WithField("component", "test"),
+		config: config.CgroupConfig{Driver: config.CgroupDriverSystemd, Slice: "joblet-jobs.slice"},
+		scopes: scopes,
+	}
+	jobDir := filepath.Join(scopes.sliceDir, "job-1234.scope")
+
+	require.NoError(t, cg.startScope(jobDir))
+	require.Len(t, scopes.started, 1)
+	scope := scopes.started[0]
+	assert.Equal(t, "job-1234.scope", scope.Name)
+	assert.Equal(t, "joblet-jobs.slice", scope.Slice)
+	assert.True(t, scope.Delegate)
+	require.Len(t, scope.PIDs, 1)
+
+	// The keeper leaves the scope's own cgroup for a subgroup
+	keeperPID, err := os.ReadFile(filepath.Join(jobDir, keeperGroup, "cgroup.procs"))
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(scope.PIDs[0]), string(keeperPID))
+	assert.NoError(t, syscall.Kill(scope.PIDs[0], syscall.SIGKILL))
+
+	require.NoError(t, cg.stopScope("1234"))
+	assert.Equal(t, []string{"job-1234.scope"}, scopes.stopped)
+}
+
+func TestJobIDOf(t *testing.T) {
+	assert.Equal(t, "1234", jobIDOf("/sys/fs/cgroup/joblet.slice/joblet.service/job-1234"))
+	assert.Equal(t, "1234", jobIDOf("/sys/fs/cgroup/joblet.slice/joblet-jobs.slice/job-1234.scope"))
+}
@@ -22,6 +22,7 @@ import (
 type ResourceManager struct {
 	cgroup     resource.Resource
 	filesystem *filesystem.Isolator
+	skeletons  *filesystem.SkeletonPool
 	platform   platform.Platform
 	config     *config.Config
 	logger     *logger.Logger
@@ -61,6 +62,12 @@ func (rm *ResourceManager) SetupJobResources(job *domain.Job) error {
 		return err
 	}
 
+	// The container runtime confines jobs of the container backend itself
+	if rm.config.Execution.ContainerBackend() {
+		log.Info("job resources setup completed", "backend", config.ExecutionBackendContainer)
+		return nil
+	}
+
 	// Create cgroup with resource limits
 	if err := rm.createCgroup(job); err != nil {
 		rm.cleanupWorkspace(job.Uuid)
@@ -75,6 +82,15 @@ func (rm *ResourceManager) SetupJobResources(job *domain.Job) error {
 		}
 	}
 
+	// Write the raw cgroup files the job asked for, after the simple limits
+	// so they can refine them
+	if len(job.CgroupParams) > 0 {
+		if err := rm.cgroup.SetParams(job.CgroupPath, job.CgroupParams); err != nil {
+			rm.cleanupAll(job.Uuid)
+			return fmt.Errorf("cgroup parameter setup failed: %w", err)
+		}
+	}
+
 	// Setup GPU device permissions if GPUs are allocated
 	if job.IsGPUAllocated() {
 		if err := rm.setupGPUDevicePermissions(job); err != nil {
@@ -104,10 +120,13 @@ func (rm *ResourceManager) PrepareScheduledJobUploads(ctx context.Context, job *
 		return fmt.Errorf("failed to create workspace: %w", err)
 	}
 
-	// Setup cgroup early for resource limits during upload
-	if err := rm.createCgroup(job); err != nil {
-		_ = rm.platform.RemoveAll(filepath.Dir(workspaceDir))
-		return fmt.Errorf("failed to create cgroup: %w", err)
+	// Setup cgroup early for resource limits during upload (jobs of the
+	// container backend have none)
+	if !rm.config.Execution.ContainerBackend() {
+		if err := rm.createCgroup(job); err != nil {
+			_ = rm.platform.RemoveAll(filepath.Dir(workspaceDir))
+			return fmt.Errorf("failed to create cgroup: %w", err)
+		}
 	}
 
 	// Process uploads
@@ -131,8 +150,13 @@ func (rm *ResourceManager) PrepareScheduledJobUploads(ctx context.Context, job *
 // createWorkspace creates a dedicated workspace directory for the job.
 // The workspace is created under the base filesystem directory with the job ID
 // as the subdirectory name, with permissions set to 0755 for proper isolation.
+// A pre-created skeleton is claimed when one is available so the chroot tree
+// already exists by the time the init process sets it up.
 func (rm *ResourceManager) createWorkspace(jobID string) error {
 	baseDir := filepath.Join(rm.config.Filesystem.BaseDir, jobID)
+	if rm.skeletons != nil && rm.skeletons.Claim(baseDir) {
+		return nil
+	}
 	if err := rm.platform.MkdirAll(baseDir, 0755); err != nil {
 		return fmt.Errorf("failed to create workspace: %w", err)
 	}
@@ -0,0 +1,139 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// ErrUploadRejected is returned, wrapped, when the scanner reported findings
+// in a job's uploads.
+var ErrUploadRejected = errors.New("uploads rejected by scanner")
+
+// maxFindingsBytes caps how much scanner output is returned to the client
+const maxFindingsBytes = 4096
+
+// Scanner runs a job's uploads through the configured scanner command before
+// the job is started. The uploads are written to a private temporary
+// directory whose path is passed as the scanner's last argument.
+//
+// The scanner's exit status decides: 0 means clean, 1 means findings (the
+// job is rejected and the scanner output is returned as the reason), anything
+// else is a scanner failure. This is the convention of clamscan.
+type Scanner struct {
+	cfg    config.UploadScanConfig
+	logger *logger.Logger
+}
+
+// NewScanner creates an upload scanner, or returns nil when scanning is disabled
+func NewScanner(cfg config.UploadScanConfig, logger *logger.Logger) *Scanner {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Scanner{
+		cfg:    cfg,
+		logger: logger.WithField("component", "upload-scanner"),
+	}
+}
+
+// Scan stages uploads and runs the scanner on them. A scanner failure or
+// timeout rejects the job too, unless fail_open is set.
+func (s *Scanner) Scan(ctx context.Context, uploads []domain.FileUpload) error {
+	if s == nil || len(uploads) == 0 {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp(s.cfg.WorkDir, "joblet-scan-")
+	if err != nil {
+		return s.scannerFailure(fmt.Errorf("failed to create scan directory: %w", err))
+	}
+	defer os.RemoveAll(dir)
+
+	if err := stageUploads(dir, uploads); err != nil {
+		return s.scannerFailure(err)
+	}
+
+	timeout := s.cfg.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	scanCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := append(append([]string{}, s.cfg.Args...), dir)
+	cmd := exec.CommandContext(scanCtx, s.cfg.Command, args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	err = cmd.Run()
+	s.logger.Debug("upload scan finished", "files", len(uploads), "duration", time.Since(start), "error", err)
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && scanCtx.Err() == nil {
+		findings := findingsText(output.Bytes(), dir)
+		s.logger.Warn("upload scan reported findings, rejecting job", "findings", findings)
+		return fmt.Errorf("%w: %s", ErrUploadRejected, findings)
+	}
+	if scanCtx.Err() == context.DeadlineExceeded {
+		return s.scannerFailure(fmt.Errorf("scanner timed out after %s", timeout))
+	}
+	return s.scannerFailure(fmt.Errorf("scanner failed: %w: %s", err, findingsText(output.Bytes(), dir)))
+}
+
+// scannerFailure rejects the job unless the scanner is configured to fail open
+func (s *Scanner) scannerFailure(err error) error {
+	if s.cfg.FailOpen {
+		s.logger.Warn("upload scan failed, starting job unscanned (fail_open)", "error", err)
+		return nil
+	}
+	s.logger.Error("upload scan failed, rejecting job", "error", err)
+	return fmt.Errorf("upload scan failed: %w", err)
+}
+
+// stageUploads writes uploads below dir. Upload paths are confined to dir.
+func stageUploads(dir string, uploads []domain.FileUpload) error {
+	for _, upload := range uploads {
+		path := filepath.Join(dir, filepath.Clean("/"+upload.Path))
+		if upload.IsDirectory {
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return fmt.Errorf("failed to stage directory %s: %w", upload.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return fmt.Errorf("failed to stage directory for %s: %w", upload.Path, err)
+		}
+		if err := os.WriteFile(path, upload.Content, 0600); err != nil {
+			return fmt.Errorf("failed to stage file %s: %w", upload.Path, err)
+		}
+	}
+	return nil
+}
+
+// findingsText trims scanner output for the client and strips the staging
+// directory so reported paths match the upload paths.
+func findingsText(output []byte, dir string) string {
+	text := strings.TrimSpace(strings.ReplaceAll(string(output), dir+string(filepath.Separator), ""))
+	if len(text) > maxFindingsBytes {
+		text = text[:maxFindingsBytes] + "..."
+	}
+	if text == "" {
+		return "no details reported"
+	}
+	return text
+}
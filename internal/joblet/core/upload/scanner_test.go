@@ -0,0 +1,82 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// writeScanner writes a shell script scanner; the upload directory is $1
+func writeScanner(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scanner.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write scanner: %v", err)
+	}
+	return path
+}
+
+func newTestScanner(command string, failOpen bool) *Scanner {
+	return NewScanner(config.UploadScanConfig{
+		Enabled:  true,
+		Command:  command,
+		Timeout:  5 * time.Second,
+		FailOpen: failOpen,
+	}, logger.New())
+}
+
+var testUploads = []domain.FileUpload{
+	{Path: "src", IsDirectory: true},
+	{Path: "src/main.py", Content: []byte("print('hello')")},
+	{Path: "../escape.sh", Content: []byte("EICAR")},
+}
+
+func TestScannerClean(t *testing.T) {
+	// Fails unless the uploads were staged below the scan directory
+	scanner := newTestScanner(writeScanner(t, `test -f "$1/src/main.py" && test -f "$1/escape.sh"`), false)
+	if err := scanner.Scan(context.Background(), testUploads); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+}
+
+func TestScannerFindings(t *testing.T) {
+	scanner := newTestScanner(writeScanner(t, `grep -rl EICAR "$1"; exit 1`), false)
+	err := scanner.Scan(context.Background(), testUploads)
+	if !errors.Is(err, ErrUploadRejected) {
+		t.Fatalf("Scan() error = %v, want ErrUploadRejected", err)
+	}
+	if !strings.Contains(err.Error(), "escape.sh") || strings.Contains(err.Error(), "joblet-scan-") {
+		t.Errorf("findings should name the upload path without the staging directory, got %q", err.Error())
+	}
+}
+
+func TestScannerFailure(t *testing.T) {
+	failing := writeScanner(t, `echo "database missing" >&2; exit 2`)
+
+	err := newTestScanner(failing, false).Scan(context.Background(), testUploads)
+	if err == nil || errors.Is(err, ErrUploadRejected) {
+		t.Fatalf("Scan() error = %v, want scanner failure", err)
+	}
+
+	if err := newTestScanner(failing, true).Scan(context.Background(), testUploads); err != nil {
+		t.Fatalf("Scan() with fail_open error = %v", err)
+	}
+}
+
+func TestScannerDisabled(t *testing.T) {
+	scanner := NewScanner(config.UploadScanConfig{Enabled: false}, logger.New())
+	if scanner != nil {
+		t.Fatalf("NewScanner() = %v, want nil when disabled", scanner)
+	}
+	if err := scanner.Scan(context.Background(), testUploads); err != nil {
+		t.Fatalf("nil Scanner.Scan() error = %v", err)
+	}
+}
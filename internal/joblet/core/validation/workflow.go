@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/core/volume"
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
 	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	wf "github.com/ehsaniara/joblet/internal/joblet/workflow"
 	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
 	"github.com/ehsaniara/joblet/pkg/logger"
 )
@@ -31,157 +36,294 @@ func NewWorkflowValidator(volumeManager *volume.Manager, runtimeManager *runtime
 	}
 }
 
+// Checks run on a workflow, in order. CheckSyntax fails alone, for
+// workflows that do not parse.
+const (
+	CheckSyntax       = "syntax"
+	CheckDependencies = "dependencies"
+	CheckVolumes      = "volumes"
+	CheckNetworks     = "networks"
+	CheckRuntimes     = "runtimes"
+	CheckEnvironment  = "environment"
+	CheckTiming       = "timing"
+	CheckLabels       = "labels"
+	CheckCgroupParams = "cgroup-params"
+	CheckOutputs      = "outputs"
+	CheckUploads      = "uploads"
+)
+
+// Problem codes, stable for clients to match on
+const (
+	CodeInvalidSyntax      = "invalid-syntax"
+	CodeCircularDependency = "circular-dependency"
+	CodeUnknownDependency  = "unknown-dependency"
+	CodeMissingVolume      = "missing-volume"
+	CodeMissingNetwork     = "missing-network"
+	CodeMissingRuntime     = "missing-runtime"
+	CodeRuntimesUnlisted   = "runtimes-unlisted"
+	CodeInvalidEnvironment = "invalid-environment"
+	CodeInvalidTiming      = "invalid-timing"
+	CodeInvalidGroup       = "invalid-group"
+	CodeInvalidAnnotation  = "invalid-annotation"
+	CodeInvalidLabel       = "invalid-label"
+	CodeInvalidCgroupParam = "invalid-cgroup-param"
+	CodeInvalidOutputs     = "invalid-outputs"
+	CodeMissingUpload      = "missing-upload"
+)
+
+// Problem is one thing wrong with a workflow
+type Problem struct {
+	Check   string
+	Code    string
+	Job     string // Empty for problems of the whole workflow
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Job == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("job '%s': %s", p.Job, p.Message)
+}
+
+// Error lists every problem found in a workflow
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.String()
+	}
+	return fmt.Sprintf("%d problems: %s", len(e.Problems), strings.Join(messages, "; "))
+}
+
 // ValidateWorkflow performs comprehensive pre-execution validation of a workflow
-// This is the main entry point for server-side workflow validation
+// This is the main entry point for server-side workflow validation. Every
+// check runs, and the returned *Error lists all the problems found.
 func (wv *WorkflowValidator) ValidateWorkflow(workflow types.WorkflowYAML) error {
 	wv.logger.Info("starting comprehensive workflow validation")
 
-	// 1. Check for circular dependencies
-	if err := wv.validateNonCircularDependencies(workflow); err != nil {
-		wv.logger.Error("circular dependency validation failed", "error", err)
-		return fmt.Errorf("circular dependency detected: %w", err)
+	var problems []Problem
+	wv.Check(workflow, func(check string, found []Problem) {
+		problems = append(problems, found...)
+	})
+	if len(problems) > 0 {
+		wv.logger.Error("workflow validation failed", "problems", len(problems))
+		return &Error{Problems: problems}
 	}
-	wv.logger.Debug("✅ No circular dependencies found")
 
-	// 2. Validate all volumes exist
-	if err := wv.validateVolumesExist(workflow); err != nil {
-		wv.logger.Error("volume validation failed", "error", err)
-		return fmt.Errorf("volume validation failed: %w", err)
+	wv.logger.Info("workflow validation completed successfully")
+	return nil
+}
+
+// Check runs every check on a workflow and reports the problems of each as
+// it completes, none when it passed
+func (wv *WorkflowValidator) Check(workflow types.WorkflowYAML, report func(check string, problems []Problem)) {
+	checks := []struct {
+		name string
+		run  func(types.WorkflowYAML) []Problem
+	}{
+		{CheckDependencies, wv.checkDependencies},
+		{CheckVolumes, wv.checkVolumes},
+		{CheckNetworks, wv.checkNetworks},
+		{CheckRuntimes, wv.checkRuntimes},
+		{CheckEnvironment, wv.checkEnvironmentVariables},
+		// Schedules and time windows
+		{CheckTiming, wv.checkJobTiming},
+		// The job group name, annotations and job labels
+		{CheckLabels, wv.checkGroupAndLabels},
+		// Raw cgroup parameters; the node's allowlist is checked when
+		// each job starts
+		{CheckCgroupParams, wv.checkCgroupParams},
+		{CheckOutputs, checkOutputs},
+	}
+	for _, check := range checks {
+		problems := check.run(workflow)
+		for i := range problems {
+			problems[i].Check = check.name
+		}
+		if len(problems) > 0 {
+			wv.logger.Debug("workflow check failed", "check", check.name, "problems", len(problems))
+		}
+		report(check.name, problems)
 	}
-	wv.logger.Debug("✅ All required volumes exist")
+}
 
-	// 3. Validate all networks exist
-	if err := wv.validateNetworksExist(workflow); err != nil {
-		wv.logger.Error("network validation failed", "error", err)
-		return fmt.Errorf("network validation failed: %w", err)
+// UploadProblems checks that the uploads of every job match the workflow
+// files a client sends
+func UploadProblems(workflow types.WorkflowYAML, files []string) []Problem {
+	var problems []Problem
+	for _, jobName := range sortedJobNames(workflow) {
+		if _, err := wf.UploadTargets(workflow.Jobs[jobName].Uploads, files); err != nil {
+			problems = append(problems, Problem{Check: CheckUploads, Code: CodeMissingUpload, Job: jobName, Message: err.Error()})
+		}
 	}
-	wv.logger.Debug("✅ All required networks exist")
+	return problems
+}
 
-	// 4. Validate all runtimes exist
-	if err := wv.validateRuntimesExist(workflow); err != nil {
-		wv.logger.Error("runtime validation failed", "error", err)
-		return fmt.Errorf("runtime validation failed: %w", err)
+// sortedJobNames lists the jobs of a workflow in name order, so problems
+// are reported the same way every time
+func sortedJobNames(workflow types.WorkflowYAML) []string {
+	names := make([]string, 0, len(workflow.Jobs))
+	for name := range workflow.Jobs {
+		names = append(names, name)
 	}
-	wv.logger.Debug("✅ All required runtimes exist")
+	sort.Strings(names)
+	return names
+}
 
-	// 5. Validate job dependencies reference existing jobs
-	if err := wv.validateJobDependencies(workflow); err != nil {
-		wv.logger.Error("job dependency validation failed", "error", err)
-		return fmt.Errorf("job dependency validation failed: %w", err)
+// checkGroupAndLabels checks the workflow's job group name and
+// annotations, and the labels of its jobs
+func (wv *WorkflowValidator) checkGroupAndLabels(workflow types.WorkflowYAML) []Problem {
+	var problems []Problem
+	if workflow.Group != "" {
+		if _, err := values.NewGroupName(workflow.Group); err != nil {
+			problems = append(problems, Problem{Code: CodeInvalidGroup, Message: err.Error()})
+		}
+	}
+	if len(workflow.Annotations) > values.MaxAnnotations {
+		problems = append(problems, Problem{Code: CodeInvalidAnnotation,
+			Message: fmt.Sprintf("too many annotations: %d (at most %d)", len(workflow.Annotations), values.MaxAnnotations)})
+	}
+	for _, key := range sortedKeys(workflow.Annotations) {
+		if err := values.ValidateAnnotation(key, workflow.Annotations[key]); err != nil {
+			problems = append(problems, Problem{Code: CodeInvalidAnnotation, Message: err.Error()})
+		}
 	}
-	wv.logger.Debug("✅ All job dependencies are valid")
+	for _, jobName := range sortedJobNames(workflow) {
+		labels := workflow.Jobs[jobName].Labels
+		for _, key := range sortedKeys(labels) {
+			if err := values.ValidateLabel(key, labels[key]); err != nil {
+				problems = append(problems, Problem{Code: CodeInvalidLabel, Job: jobName, Message: err.Error()})
+			}
+		}
+	}
+	return problems
+}
 
-	// 6. Validate environment variables
-	if err := wv.validateEnvironmentVariables(workflow); err != nil {
-		wv.logger.Error("environment variable validation failed", "error", err)
-		return fmt.Errorf("environment variable validation failed: %w", err)
+// checkCgroupParams checks the workflow's and its jobs' cgroup_params
+func (wv *WorkflowValidator) checkCgroupParams(workflow types.WorkflowYAML) []Problem {
+	var problems []Problem
+	for _, name := range sortedKeys(workflow.CgroupParams) {
+		if err := values.ValidateCgroupParam(name, workflow.CgroupParams[name]); err != nil {
+			problems = append(problems, Problem{Code: CodeInvalidCgroupParam, Message: err.Error()})
+		}
+	}
+	for _, jobName := range sortedJobNames(workflow) {
+		params := workflow.Jobs[jobName].Resources.CgroupParams
+		for _, name := range sortedKeys(params) {
+			if err := values.ValidateCgroupParam(name, params[name]); err != nil {
+				problems = append(problems, Problem{Code: CodeInvalidCgroupParam, Job: jobName, Message: err.Error()})
+			}
+		}
 	}
-	wv.logger.Debug("✅ All environment variables are valid")
+	return problems
+}
 
-	wv.logger.Info("workflow validation completed successfully")
+// checkOutputs checks job outputs and the references to them
+func checkOutputs(workflow types.WorkflowYAML) []Problem {
+	if err := wf.ValidateOutputs(workflow); err != nil {
+		return []Problem{{Code: CodeInvalidOutputs, Message: err.Error()}}
+	}
 	return nil
 }
 
-// validateNonCircularDependencies checks for circular dependencies using DFS
-func (wv *WorkflowValidator) validateNonCircularDependencies(workflow types.WorkflowYAML) error {
-	// Build dependency graph
+// checkDependencies checks that job dependencies reference existing jobs
+// and form no cycle, found with a DFS
+func (wv *WorkflowValidator) checkDependencies(workflow types.WorkflowYAML) []Problem {
+	var problems []Problem
 	graph := make(map[string][]string)
-	for jobName, job := range workflow.Jobs {
-		graph[jobName] = []string{}
-		for _, req := range job.Requires {
-			for depJob := range req {
-				graph[jobName] = append(graph[jobName], depJob)
+	for _, jobName := range sortedJobNames(workflow) {
+		// Direct dependencies and jobs named in dependency expressions
+		for _, depJobName := range wf.RequiredJobNames(workflow.Jobs[jobName].Requires) {
+			if _, exists := workflow.Jobs[depJobName]; !exists {
+				wv.logger.Error("invalid job dependency", "job", jobName, "dependency", depJobName)
+				problems = append(problems, Problem{Code: CodeUnknownDependency, Job: jobName,
+					Message: fmt.Sprintf("depends on non-existent job '%s'", depJobName)})
+				continue
 			}
+			graph[jobName] = append(graph[jobName], depJobName)
 		}
 	}
 
 	// Check for cycles using DFS with coloring
 	color := make(map[string]int) // 0=white, 1=gray, 2=black
 
-	var detectCycle func(string) error
-	detectCycle = func(node string) error {
+	var detectCycle func(string) bool
+	detectCycle = func(node string) bool {
 		if color[node] == 1 { // gray = currently being processed
-			return fmt.Errorf("circular dependency involving job '%s'", node)
+			problems = append(problems, Problem{Code: CodeCircularDependency, Job: node,
+				Message: fmt.Sprintf("circular dependency involving job '%s'", node)})
+			return true
 		}
 		if color[node] == 2 { // black = already processed
-			return nil
+			return false
 		}
 
 		color[node] = 1 // mark as gray
 		for _, neighbor := range graph[node] {
-			if err := detectCycle(neighbor); err != nil {
-				return err
+			if detectCycle(neighbor) {
+				return true
 			}
 		}
 		color[node] = 2 // mark as black
-		return nil
+		return false
 	}
 
-	for jobName := range workflow.Jobs {
-		if color[jobName] == 0 {
-			if err := detectCycle(jobName); err != nil {
-				return err
-			}
+	for _, jobName := range sortedJobNames(workflow) {
+		if color[jobName] == 0 && detectCycle(jobName) {
+			// One cycle is reported; the jobs on it stay gray
+			break
 		}
 	}
 
-	return nil
+	return problems
 }
 
-// validateVolumesExist checks that all referenced volumes exist on the server
-func (wv *WorkflowValidator) validateVolumesExist(workflow types.WorkflowYAML) error {
-	requiredVolumes := make(map[string]bool)
-
-	// Collect all volumes referenced in jobs
-	for _, job := range workflow.Jobs {
-		for _, volume := range job.Volumes {
-			if volume != "" {
-				requiredVolumes[volume] = true
+// checkVolumes checks that all referenced volumes exist on the server
+func (wv *WorkflowValidator) checkVolumes(workflow types.WorkflowYAML) []Problem {
+	var problems []Problem
+	found := make(map[string]bool)
+	for _, jobName := range sortedJobNames(workflow) {
+		for _, volumeName := range workflow.Jobs[jobName].Volumes {
+			if volumeName == "" {
+				continue
 			}
-		}
-	}
-
-	if len(requiredVolumes) == 0 {
-		wv.logger.Debug("no volumes required by workflow")
-		return nil
-	}
-
-	// Check each volume exists using concrete volume manager
-	var missingVolumes []string
-	for volumeName := range requiredVolumes {
-		if _, exists := wv.volumeManager.GetVolume(volumeName); !exists {
-			// Also check filesystem path as fallback
-			volumePath := filepath.Join("/opt/joblet/volumes", volumeName, "data")
-			if _, err := os.Stat(volumePath); os.IsNotExist(err) {
-				missingVolumes = append(missingVolumes, volumeName)
-				wv.logger.Warn("volume not found", "volume", volumeName)
+			exists, checked := found[volumeName]
+			if !checked {
+				exists = wv.volumeExists(volumeName)
+				found[volumeName] = exists
+				if !exists {
+					wv.logger.Warn("volume not found", "volume", volumeName)
+				}
+			}
+			if !exists {
+				problems = append(problems, Problem{Code: CodeMissingVolume, Job: jobName,
+					Message: fmt.Sprintf("volume '%s' does not exist", volumeName)})
 			}
 		}
 	}
-
-	if len(missingVolumes) > 0 {
-		return fmt.Errorf("missing volumes: %v", missingVolumes)
-	}
-
-	return nil
+	return problems
 }
 
-// validateNetworksExist checks that all referenced networks exist on the server
-func (wv *WorkflowValidator) validateNetworksExist(workflow types.WorkflowYAML) error {
-	requiredNetworks := make(map[string]bool)
-
-	// Collect all networks referenced in jobs
-	for _, job := range workflow.Jobs {
-		if job.Network != "" {
-			requiredNetworks[job.Network] = true
-		}
-	}
-
-	if len(requiredNetworks) == 0 {
-		wv.logger.Debug("no custom networks specified in workflow")
-		return nil
+// volumeExists looks a volume up in the volume manager, then on disk
+func (wv *WorkflowValidator) volumeExists(name string) bool {
+	if _, exists := wv.volumeManager.GetVolume(name); exists {
+		return true
 	}
+	// Also check filesystem path as fallback
+	volumePath := filepath.Join("/opt/joblet/volumes", name, "data")
+	_, err := os.Stat(volumePath)
+	return !os.IsNotExist(err)
+}
 
+// checkNetworks checks that all referenced networks exist on the server
+func (wv *WorkflowValidator) checkNetworks(workflow types.WorkflowYAML) []Problem {
 	// Built-in networks are always available
 	builtinNetworks := map[string]bool{
 		"none":     true,
@@ -189,36 +331,27 @@ func (wv *WorkflowValidator) validateNetworksExist(workflow types.WorkflowYAML)
 		"bridge":   true,
 	}
 
-	// Check each required network
-	var missingNetworks []string
-	for networkName := range requiredNetworks {
-		if !builtinNetworks[networkName] {
+	for _, jobName := range sortedJobNames(workflow) {
+		network := workflow.Jobs[jobName].Network
+		if network != "" && !builtinNetworks[network] {
 			// For now, we accept any custom network name
 			// In future, this could check against a network manager interface
-			// similar to how volume and runtime validation work
-			wv.logger.Debug("custom network specified", "network", networkName)
+			// similar to how volume and runtime validation work, reporting
+			// CodeMissingNetwork
+			wv.logger.Debug("custom network specified", "network", network)
 		}
 	}
-
-	if len(missingNetworks) > 0 {
-		return fmt.Errorf("missing networks: %v", missingNetworks)
-	}
-
 	return nil
 }
 
-// validateRuntimesExist checks that all referenced runtimes exist and are available
-func (wv *WorkflowValidator) validateRuntimesExist(workflow types.WorkflowYAML) error {
-	requiredRuntimes := make(map[string]bool)
-
-	// Collect all runtimes referenced in jobs
-	for _, job := range workflow.Jobs {
-		if job.Runtime != "" {
-			requiredRuntimes[job.Runtime] = true
-		}
+// checkRuntimes checks that all referenced runtimes exist and are available
+func (wv *WorkflowValidator) checkRuntimes(workflow types.WorkflowYAML) []Problem {
+	jobNames := sortedJobNames(workflow)
+	required := false
+	for _, jobName := range jobNames {
+		required = required || workflow.Jobs[jobName].Runtime != ""
 	}
-
-	if len(requiredRuntimes) == 0 {
+	if !required {
 		wv.logger.Debug("no runtimes specified in workflow")
 		return nil
 	}
@@ -227,12 +360,15 @@ func (wv *WorkflowValidator) validateRuntimesExist(workflow types.WorkflowYAML)
 	availableRuntimes := make(map[string]bool)
 	runtimes, err := wv.runtimeManager.ListRuntimes()
 	if err != nil {
-		return fmt.Errorf("failed to list available runtimes: %w", err)
+		return []Problem{{Code: CodeRuntimesUnlisted, Message: fmt.Sprintf("failed to list available runtimes: %v", err)}}
 	}
 	for _, runtime := range runtimes {
 		if runtime.Available {
-			// Support both hyphen and colon format
+			// Support both hyphen and colon format, and name@version pins
 			availableRuntimes[runtime.Name] = true
+			if runtime.Version != "" {
+				availableRuntimes[runtime.Name+"@"+runtime.Version] = true
+			}
 			// Normalize runtime name (server may store as "python-3.11-ml" but workflow uses "python-3.11-ml")
 			if colonVersion := normalizeRuntimeName(runtime.Name); colonVersion != runtime.Name {
 				availableRuntimes[colonVersion] = true
@@ -240,47 +376,31 @@ func (wv *WorkflowValidator) validateRuntimesExist(workflow types.WorkflowYAML)
 		}
 	}
 
-	// Check each required runtime exists
-	var missingRuntimes []string
-	for runtimeName := range requiredRuntimes {
-		// Try both original name and normalized version
-		normalizedName := normalizeRuntimeName(runtimeName)
-
-		if !availableRuntimes[runtimeName] && !availableRuntimes[normalizedName] {
-			missingRuntimes = append(missingRuntimes, runtimeName)
-			wv.logger.Warn("runtime not found", "runtime", runtimeName)
+	// Check each required runtime exists, trying both the original name
+	// and the normalized one
+	var problems []Problem
+	for _, jobName := range jobNames {
+		runtimeName := workflow.Jobs[jobName].Runtime
+		if runtimeName == "" || availableRuntimes[runtimeName] || availableRuntimes[normalizeRuntimeName(runtimeName)] {
+			continue
 		}
+		wv.logger.Warn("runtime not found", "runtime", runtimeName)
+		problems = append(problems, Problem{Code: CodeMissingRuntime, Job: jobName,
+			Message: fmt.Sprintf("runtime '%s' is not installed", runtimeName)})
 	}
-
-	if len(missingRuntimes) > 0 {
-		return fmt.Errorf("missing runtimes: %v", missingRuntimes)
-	}
-
-	return nil
+	return problems
 }
 
-// validateJobDependencies checks that all job dependencies reference existing jobs
-func (wv *WorkflowValidator) validateJobDependencies(workflow types.WorkflowYAML) error {
-	// Get all job names
-	allJobs := make(map[string]bool)
-	for jobName := range workflow.Jobs {
-		allJobs[jobName] = true
-	}
-
-	// Check dependencies
-	for jobName, job := range workflow.Jobs {
-		for _, req := range job.Requires {
-			// req is map[string]string, iterate through key-value pairs
-			for depJobName := range req {
-				if !allJobs[depJobName] {
-					wv.logger.Error("invalid job dependency", "job", jobName, "dependency", depJobName)
-					return fmt.Errorf("job '%s' depends on non-existent job '%s'", jobName, depJobName)
-				}
-			}
+// checkJobTiming checks the schedule, not_before, window and estimate fields of all jobs
+func (wv *WorkflowValidator) checkJobTiming(workflow types.WorkflowYAML) []Problem {
+	var problems []Problem
+	now := time.Now()
+	for _, jobName := range sortedJobNames(workflow) {
+		if _, err := wf.ParseJobTiming(workflow.Jobs[jobName], now); err != nil {
+			problems = append(problems, Problem{Code: CodeInvalidTiming, Job: jobName, Message: err.Error()})
 		}
 	}
-
-	return nil
+	return problems
 }
 
 // normalizeRuntimeName converts between hyphen and colon format
@@ -304,119 +424,44 @@ func normalizeRuntimeName(runtimeName string) string {
 	return runtimeName
 }
 
-// ValidationSummary provides a summary of validation results
-type ValidationSummary struct {
-	Valid                     bool
-	CircularDependencies      bool
-	VolumesValid              bool
-	NetworksValid             bool
-	RuntimesValid             bool
-	DependenciesValid         bool
-	EnvironmentVariablesValid bool
-	Errors                    []string
-	Warnings                  []string
-}
-
-// ValidateWorkflowWithSummary performs validation and returns detailed summary
-func (wv *WorkflowValidator) ValidateWorkflowWithSummary(workflow types.WorkflowYAML) *ValidationSummary {
-	summary := &ValidationSummary{
-		Valid:                     true,
-		CircularDependencies:      true,
-		VolumesValid:              true,
-		NetworksValid:             true,
-		RuntimesValid:             true,
-		DependenciesValid:         true,
-		EnvironmentVariablesValid: true,
-		Errors:                    []string{},
-		Warnings:                  []string{},
-	}
-
-	// Check circular dependencies
-	if err := wv.validateNonCircularDependencies(workflow); err != nil {
-		summary.Valid = false
-		summary.CircularDependencies = false
-		summary.Errors = append(summary.Errors, fmt.Sprintf("Circular dependencies: %v", err))
-	}
-
-	// Check volumes
-	if err := wv.validateVolumesExist(workflow); err != nil {
-		summary.Valid = false
-		summary.VolumesValid = false
-		summary.Errors = append(summary.Errors, fmt.Sprintf("Volume validation: %v", err))
-	}
-
-	// Check networks
-	if err := wv.validateNetworksExist(workflow); err != nil {
-		summary.Valid = false
-		summary.NetworksValid = false
-		summary.Errors = append(summary.Errors, fmt.Sprintf("Network validation: %v", err))
-	}
-
-	// Check runtimes
-	if err := wv.validateRuntimesExist(workflow); err != nil {
-		summary.Valid = false
-		summary.RuntimesValid = false
-		summary.Errors = append(summary.Errors, fmt.Sprintf("Runtime validation: %v", err))
-	}
-
-	// Check job dependencies
-	if err := wv.validateJobDependencies(workflow); err != nil {
-		summary.Valid = false
-		summary.DependenciesValid = false
-		summary.Errors = append(summary.Errors, fmt.Sprintf("Job dependencies: %v", err))
-	}
-
-	// Check environment variables
-	if err := wv.validateEnvironmentVariables(workflow); err != nil {
-		summary.Valid = false
-		summary.EnvironmentVariablesValid = false
-		summary.Errors = append(summary.Errors, fmt.Sprintf("Environment variables: %v", err))
-	}
-
-	return summary
-}
-
-// validateEnvironmentVariables performs comprehensive validation of environment variables
-func (wv *WorkflowValidator) validateEnvironmentVariables(workflow types.WorkflowYAML) error {
-	for jobName, job := range workflow.Jobs {
+// checkEnvironmentVariables performs comprehensive validation of environment variables
+func (wv *WorkflowValidator) checkEnvironmentVariables(workflow types.WorkflowYAML) []Problem {
+	var problems []Problem
+	for _, jobName := range sortedJobNames(workflow) {
 		jobLog := wv.logger.WithField("job", jobName)
+		environment := workflow.Jobs[jobName].Environment
+
+		for _, key := range sortedKeys(environment) {
+			value := environment[key]
+			// Use common validation logic
+			if err := ValidateEnvironmentVariable(key, value); err != nil {
+				jobLog.Error("invalid environment variable", "key", key, "error", err)
+				problems = append(problems, Problem{Code: CodeInvalidEnvironment, Job: jobName, Message: err.Error()})
+				continue
+			}
+
+			// Check for reserved environment variable names
+			if wv.isReservedEnvironmentVariable(key) {
+				jobLog.Warn("reserved environment variable used", "key", key)
+			}
 
-		// Validate regular environment variables
-		if err := wv.validateEnvironmentVariableMap(job.Environment, jobName, "environment", jobLog); err != nil {
-			return err
+			// Check for potentially dangerous values
+			if ContainsDangerousPatterns(value) {
+				jobLog.Warn("potentially dangerous environment variable value", "key", key)
+			}
 		}
 	}
-
-	return nil
+	return problems
 }
 
-// validateEnvironmentVariableMap validates a map of environment variables
-func (wv *WorkflowValidator) validateEnvironmentVariableMap(envVars map[string]string, jobName, envType string, jobLog *logger.Logger) error {
-	if len(envVars) == 0 {
-		return nil
-	}
-
-	for key, value := range envVars {
-		// Use common validation logic
-		if err := ValidateEnvironmentVariable(key, value); err != nil {
-			jobLog.Error("invalid environment variable", "type", envType, "key", key, "error", err)
-			return fmt.Errorf("job '%s': %s - %w", jobName, envType, err)
-		}
-
-		// Check for reserved environment variable names
-		if wv.isReservedEnvironmentVariable(key) {
-			jobLog.Warn("reserved environment variable used", "type", envType, "key", key)
-		}
-
-		// Check for potentially dangerous values
-		if ContainsDangerousPatterns(value) {
-			jobLog.Warn("potentially dangerous environment variable value", "type", envType, "key", key)
-		}
-
-		jobLog.Debug("environment variable validated", "type", envType, "key", key, "valueLength", len(value))
+// sortedKeys lists the keys of a map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-
-	return nil
+	sort.Strings(keys)
+	return keys
 }
 
 // isReservedEnvironmentVariable checks if an environment variable name is reserved by the system
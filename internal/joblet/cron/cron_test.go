@@ -0,0 +1,53 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	// Wednesday
+	base := time.Date(2025, 7, 16, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 7, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, 7, 17, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 7, 16, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2025, 7, 17, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 6 1,15 * *", time.Date(2025, 8, 1, 6, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match (the 20th is a Sunday, Friday comes first)
+		{"0 0 20 * 5", time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"18 10 * * *", time.Date(2025, 7, 16, 10, 18, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2025, 7, 16, 11, 47, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(base))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 10s", "@fortnightly"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParse_NeverFires(t *testing.T) {
+	schedule, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
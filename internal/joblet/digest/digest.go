@@ -0,0 +1,363 @@
+// Package digest sums up the workflow runs of a label in one notification on a
+// schedule, such as a morning digest of the nightly pipelines: how many runs
+// succeeded, which failed and with what first error line, and how their
+// durations compare with the runs before.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/cron"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/report"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// checkInterval is how often the sender looks for digests due
+const checkInterval = 30 * time.Second
+
+// defaultWindow is how far back a digest looks when its window is not set
+const defaultWindow = 24 * time.Hour
+
+// EventDigest is the event of the notification
+const EventDigest = "workflow.digest"
+
+// stderrLines caps the stderr lines read looking for a failed job's first error
+const stderrLines = 100
+
+// maxErrorBytes caps the quoted error line
+const maxErrorBytes = 512
+
+// Digest is the JSON body POSTed to the webhook
+type Digest struct {
+	Event     string    `json:"event"`
+	Name      string    `json:"name"`
+	Label     string    `json:"label"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Runs      int       `json:"runs"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+	Failures  []Run     `json:"failures,omitempty"` // Failed runs, latest first
+	Trends    []Trend   `json:"trends,omitempty"`   // By workflow name
+	NodeID    string    `json:"nodeId,omitempty"`
+}
+
+// Run is a failed workflow run
+type Run struct {
+	WorkflowUUID    string      `json:"workflowUuid"`
+	Workflow        string      `json:"workflow"`
+	Status          string      `json:"status"`
+	CompletedAt     time.Time   `json:"completedAt"`
+	DurationSeconds float64     `json:"durationSeconds"`
+	Jobs            []FailedJob `json:"jobs,omitempty"`
+}
+
+// FailedJob is a job that failed in a run
+type FailedJob struct {
+	Name       string `json:"name"`
+	UUID       string `json:"uuid"`
+	ExitCode   int32  `json:"exitCode"`
+	FirstError string `json:"firstError,omitempty"` // First non-blank stderr line
+}
+
+// Trend compares the durations of a workflow's runs in the window with those
+// of its runs before it that the node still knows
+type Trend struct {
+	Workflow           string  `json:"workflow"`
+	Runs               int     `json:"runs"`
+	AvgSeconds         float64 `json:"avgSeconds"`
+	MinSeconds         float64 `json:"minSeconds"`
+	MaxSeconds         float64 `json:"maxSeconds"`
+	PreviousRuns       int     `json:"previousRuns"`
+	PreviousAvgSeconds float64 `json:"previousAvgSeconds,omitempty"`
+	ChangePercent      float64 `json:"changePercent,omitempty"` // Of the average against the previous one
+}
+
+// Source is where digests read workflow runs
+type Source struct {
+	// Workflows returns the workflow runs the node knows
+	Workflows func() []*workflow.WorkflowState
+	// WorkflowUUID returns the UUID clients know a run by
+	WorkflowUUID func(id int) string
+	// Jobs returns a job record still in the job store
+	Jobs func(uuid string) (*domain.Job, bool)
+	// Persist holds the jobs' logs (nil = failures are sent without their error)
+	Persist persistpb.PersistServiceClient
+}
+
+// Build sums up the runs matching the digest's label that ended in the window
+// up to now
+func Build(ctx context.Context, d config.WorkflowDigest, src Source, now time.Time) Digest {
+	window := d.Window
+	if window == 0 {
+		window = defaultWindow
+	}
+	digest := Digest{Event: EventDigest, Name: d.Name, Label: d.Label, From: now.Add(-window), To: now}
+
+	// Durations by workflow name, in and before the window
+	current := make(map[string][]float64)
+	previous := make(map[string][]float64)
+
+	var failures []Run
+	for _, state := range src.Workflows() {
+		if state.CompletedAt == nil || !state.CompletedAt.Before(now) || !matchesLabel(state, d.Label, src) {
+			continue
+		}
+		duration := 0.0
+		if state.StartedAt != nil {
+			duration = state.CompletedAt.Sub(*state.StartedAt).Seconds()
+		}
+		if state.CompletedAt.Before(digest.From) {
+			previous[state.Workflow] = append(previous[state.Workflow], duration)
+			continue
+		}
+		current[state.Workflow] = append(current[state.Workflow], duration)
+
+		digest.Runs++
+		r := report.Build(ctx, state, src.WorkflowUUID(state.ID), report.Source{Jobs: src.Jobs}, 0, now)
+		var failed []FailedJob
+		for _, job := range r.Jobs {
+			if job.UUID != "" && job.Failed() {
+				failed = append(failed, FailedJob{Name: job.Name, UUID: job.UUID, ExitCode: job.ExitCode,
+					FirstError: firstErrorLine(ctx, src.Persist, job.UUID)})
+			}
+		}
+		if state.Status == workflow.WorkflowCompleted && len(failed) == 0 {
+			digest.Succeeded++
+			continue
+		}
+		digest.Failed++
+		failures = append(failures, Run{
+			WorkflowUUID:    r.WorkflowUUID,
+			Workflow:        state.Workflow,
+			Status:          string(state.Status),
+			CompletedAt:     *state.CompletedAt,
+			DurationSeconds: duration,
+			Jobs:            failed,
+		})
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].CompletedAt.After(failures[j].CompletedAt) })
+	digest.Failures = failures
+	digest.Trends = trends(current, previous)
+	return digest
+}
+
+// matchesLabel reports whether a job of the run carries the label, either
+// key=value or a bare key
+func matchesLabel(state *workflow.WorkflowState, label string, src Source) bool {
+	key, value, hasValue := strings.Cut(label, "=")
+	for _, dep := range state.Jobs {
+		if dep.JobID == dep.InternalName {
+			continue // Never started, so there is no record
+		}
+		job, ok := src.Jobs(dep.JobID)
+		if !ok {
+			continue
+		}
+		if v, ok := job.Labels[key]; ok && (!hasValue || v == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// trends compares the durations of each workflow in the window with its
+// earlier ones
+func trends(current, previous map[string][]float64) []Trend {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []Trend
+	for _, name := range names {
+		durations := current[name]
+		t := Trend{Workflow: name, Runs: len(durations), MinSeconds: durations[0], MaxSeconds: durations[0]}
+		for _, d := range durations {
+			t.AvgSeconds += d
+			t.MinSeconds = min(t.MinSeconds, d)
+			t.MaxSeconds = max(t.MaxSeconds, d)
+		}
+		t.AvgSeconds /= float64(len(durations))
+
+		if before := previous[name]; len(before) > 0 {
+			t.PreviousRuns = len(before)
+			for _, d := range before {
+				t.PreviousAvgSeconds += d
+			}
+			t.PreviousAvgSeconds /= float64(len(before))
+			if t.PreviousAvgSeconds > 0 {
+				t.ChangePercent = (t.AvgSeconds - t.PreviousAvgSeconds) / t.PreviousAvgSeconds * 100
+			}
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// firstErrorLine returns the first non-blank line the job wrote to stderr
+func firstErrorLine(ctx context.Context, persist persistpb.PersistServiceClient, jobID string) string {
+	if persist == nil {
+		return ""
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := persist.QueryLogs(ctx, &persistpb.QueryLogsRequest{
+		JobId:  jobID,
+		Stream: persistpb.StreamType_STREAM_TYPE_STDERR,
+		Limit:  stderrLines,
+	})
+	if err != nil {
+		return ""
+	}
+	var stderr bytes.Buffer
+	for {
+		record, err := stream.Recv()
+		if err != nil {
+			break // io.EOF, or the end of what can be read
+		}
+		stderr.Write(record.Content)
+		for {
+			i := bytes.IndexByte(stderr.Bytes(), '\n')
+			if i < 0 {
+				break
+			}
+			if line := strings.TrimSpace(string(stderr.Next(i + 1)[:i])); line != "" {
+				return truncate(line)
+			}
+		}
+	}
+	return truncate(strings.TrimSpace(stderr.String()))
+}
+
+func truncate(line string) string {
+	if len(line) > maxErrorBytes {
+		return line[:maxErrorBytes]
+	}
+	return line
+}
+
+type scheduled struct {
+	digest   config.WorkflowDigest
+	schedule cron.Schedule
+	next     time.Time // Next send, zero until the first check
+}
+
+// Sender builds and sends the digests on their schedules
+type Sender struct {
+	digests    []*scheduled
+	webhookURL string
+	src        Source
+	client     *http.Client
+	nodeID     string
+	logger     *logger.Logger
+}
+
+// NewSender parses the configured digests; it returns nil when there are none
+func NewSender(cfg config.DigestConfig, src Source, nodeID string) (*Sender, error) {
+	if len(cfg.Digests) == 0 {
+		return nil, nil
+	}
+	s := &Sender{
+		webhookURL: cfg.WebhookURL,
+		src:        src,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		nodeID:     nodeID,
+		logger:     logger.WithField("component", "digest"),
+	}
+	for _, d := range cfg.Digests {
+		schedule, err := cron.Parse(d.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("digest %q: %w", d.Name, err)
+		}
+		s.digests = append(s.digests, &scheduled{digest: d, schedule: schedule})
+	}
+	return s, nil
+}
+
+// Run sends the digests until ctx is done
+func (s *Sender) Run(ctx context.Context) {
+	s.logger.Info("workflow digests configured", "digests", len(s.digests), "webhook", s.webhookURL != "")
+
+	s.Check(ctx, time.Now())
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Check(ctx, now)
+		}
+	}
+}
+
+// Check sends the digests due at now and returns them. Each scheduled time is
+// sent once; the first check only plans the next one.
+func (s *Sender) Check(ctx context.Context, now time.Time) []Digest {
+	var due []Digest
+	for _, sc := range s.digests {
+		if !sc.next.IsZero() && !now.Before(sc.next) {
+			d := Build(ctx, sc.digest, s.src, now)
+			d.NodeID = s.nodeID
+			due = append(due, d)
+			s.send(ctx, sc.digest, d)
+		}
+		if sc.next.IsZero() || !now.Before(sc.next) {
+			sc.next = sc.schedule.Next(now)
+		}
+	}
+	return due
+}
+
+// send logs a digest and POSTs it to its webhook
+func (s *Sender) send(ctx context.Context, cfg config.WorkflowDigest, d Digest) {
+	log := s.logger.WithFields("digest", d.Name, "label", d.Label)
+	log.Info("workflow digest", "runs", d.Runs, "succeeded", d.Succeeded, "failed", d.Failed)
+
+	webhook := cfg.WebhookURL
+	if webhook == "" {
+		webhook = s.webhookURL
+	}
+	if webhook == "" {
+		return
+	}
+	if err := s.notify(ctx, webhook, d); err != nil {
+		log.Warn("failed to send workflow digest", "error", err)
+	}
+}
+
+// notify POSTs a digest to the webhook as JSON
+func (s *Sender) notify(ctx context.Context, webhook string, d Digest) error {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
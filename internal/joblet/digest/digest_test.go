@@ -0,0 +1,189 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+)
+
+// fakePersist answers with fixed stderr records per job
+type fakePersist struct {
+	persistpb.PersistServiceClient
+	stderr map[string][]string
+}
+
+type logStream struct {
+	grpc.ClientStream
+	lines []*persistpb.LogLine
+}
+
+func (s *logStream) Recv() (*persistpb.LogLine, error) {
+	if len(s.lines) == 0 {
+		return nil, io.EOF
+	}
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line, nil
+}
+
+func (f *fakePersist) QueryLogs(_ context.Context, req *persistpb.QueryLogsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[persistpb.LogLine], error) {
+	if req.Stream != persistpb.StreamType_STREAM_TYPE_STDERR {
+		return nil, fmt.Errorf("digest read stream %v", req.Stream)
+	}
+	stream := &logStream{}
+	for _, content := range f.stderr[req.JobId] {
+		stream.lines = append(stream.lines, &persistpb.LogLine{JobId: req.JobId, Content: []byte(content)})
+	}
+	return stream, nil
+}
+
+var testNow = time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+
+// run is a finished run of a single-job workflow
+func run(id int, name string, status workflow.WorkflowStatus, jobID string, ended time.Time, duration time.Duration) *workflow.WorkflowState {
+	started := ended.Add(-duration)
+	jobStatus := domain.StatusCompleted
+	if status != workflow.WorkflowCompleted {
+		jobStatus = domain.StatusFailed
+	}
+	return &workflow.WorkflowState{
+		ID:          id,
+		Workflow:    name,
+		Status:      status,
+		Jobs:        map[string]*workflow.JobDependency{jobID: {JobID: jobID, InternalName: "main", Status: jobStatus}},
+		JobOrder:    []string{"main"},
+		StartedAt:   &started,
+		CompletedAt: &ended,
+	}
+}
+
+func testSource() Source {
+	states := []*workflow.WorkflowState{
+		run(1, "etl.yaml", workflow.WorkflowCompleted, "job-1", testNow.Add(-30*time.Hour), 10*time.Minute),
+		run(2, "etl.yaml", workflow.WorkflowCompleted, "job-2", testNow.Add(-5*time.Hour), 12*time.Minute),
+		run(3, "etl.yaml", workflow.WorkflowFailed, "job-3", testNow.Add(-4*time.Hour), 18*time.Minute),
+		run(4, "train.yaml", workflow.WorkflowCompleted, "job-4", testNow.Add(-3*time.Hour), time.Hour),
+		run(5, "adhoc.yaml", workflow.WorkflowFailed, "job-5", testNow.Add(-2*time.Hour), time.Minute),
+		run(6, "etl.yaml", workflow.WorkflowRunning, "job-6", testNow, 0),
+	}
+	states[5].CompletedAt = nil
+
+	records := map[string]*domain.Job{
+		"job-1": {Uuid: "job-1", Labels: map[string]string{"schedule": "nightly"}},
+		"job-2": {Uuid: "job-2", Labels: map[string]string{"schedule": "nightly"}},
+		"job-3": {Uuid: "job-3", Status: domain.StatusFailed, ExitCode: 3, Labels: map[string]string{"schedule": "nightly"}},
+		"job-4": {Uuid: "job-4", Labels: map[string]string{"schedule": "nightly"}},
+		"job-5": {Uuid: "job-5", Status: domain.StatusFailed, ExitCode: 1, Labels: map[string]string{"schedule": "manual"}},
+		"job-6": {Uuid: "job-6", Labels: map[string]string{"schedule": "nightly"}},
+	}
+	return Source{
+		Workflows:    func() []*workflow.WorkflowState { return states },
+		WorkflowUUID: func(id int) string { return fmt.Sprintf("wf-%d", id) },
+		Jobs: func(uuid string) (*domain.Job, bool) {
+			job, ok := records[uuid]
+			return job, ok
+		},
+		Persist: &fakePersist{stderr: map[string][]string{
+			"job-3": {"\n  \n", "psycopg2.OperationalError: connection ", "refused\nTraceback (most recent call last):\n"},
+		}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	d := Build(context.Background(), config.WorkflowDigest{Name: "nightly", Label: "schedule=nightly"}, testSource(), testNow)
+
+	if d.Event != EventDigest || !d.From.Equal(testNow.Add(-24*time.Hour)) || !d.To.Equal(testNow) {
+		t.Errorf("digest = %+v", d)
+	}
+	if d.Runs != 3 || d.Succeeded != 2 || d.Failed != 1 {
+		t.Errorf("runs = %d, succeeded = %d, failed = %d, want 3, 2, 1", d.Runs, d.Succeeded, d.Failed)
+	}
+	if len(d.Failures) != 1 || d.Failures[0].WorkflowUUID != "wf-3" || len(d.Failures[0].Jobs) != 1 {
+		t.Fatalf("failures = %+v", d.Failures)
+	}
+	if job := d.Failures[0].Jobs[0]; job.Name != "main" || job.ExitCode != 3 ||
+		job.FirstError != "psycopg2.OperationalError: connection refused" {
+		t.Errorf("failed job = %+v", job)
+	}
+
+	if len(d.Trends) != 2 || d.Trends[0].Workflow != "etl.yaml" || d.Trends[1].Workflow != "train.yaml" {
+		t.Fatalf("trends = %+v", d.Trends)
+	}
+	etl := d.Trends[0]
+	if etl.Runs != 2 || etl.AvgSeconds != 900 || etl.MinSeconds != 720 || etl.MaxSeconds != 1080 ||
+		etl.PreviousRuns != 1 || etl.PreviousAvgSeconds != 600 || etl.ChangePercent != 50 {
+		t.Errorf("etl trend = %+v", etl)
+	}
+	if train := d.Trends[1]; train.PreviousRuns != 0 || train.ChangePercent != 0 {
+		t.Errorf("train trend = %+v", train)
+	}
+
+	// A bare key matches any value
+	if d := Build(context.Background(), config.WorkflowDigest{Label: "schedule", Window: 48 * time.Hour}, testSource(), testNow); d.Runs != 5 {
+		t.Errorf("runs labeled schedule = %d, want 5", d.Runs)
+	}
+}
+
+func TestSenderCheck(t *testing.T) {
+	received := make(chan Digest, 4)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var d Digest
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		received <- d
+	}))
+	defer webhook.Close()
+
+	sender, err := NewSender(config.DigestConfig{
+		WebhookURL: webhook.URL,
+		Digests:    []config.WorkflowDigest{{Name: "nightly", Label: "schedule=nightly", Schedule: "0 7 * * *"}},
+	}, testSource(), "node-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	// The first check plans the next send without sending
+	if due := sender.Check(ctx, testNow.Add(-time.Hour)); len(due) != 0 {
+		t.Fatalf("first check sent %+v", due)
+	}
+	if due := sender.Check(ctx, testNow.Add(-time.Minute)); len(due) != 0 {
+		t.Fatalf("sent before the schedule: %+v", due)
+	}
+	due := sender.Check(ctx, testNow)
+	if len(due) != 1 || due[0].NodeID != "node-1" || due[0].Runs != 3 {
+		t.Fatalf("due = %+v", due)
+	}
+	select {
+	case d := <-received:
+		if d.Name != "nightly" || d.Failed != 1 {
+			t.Errorf("webhook received %+v", d)
+		}
+	default:
+		t.Error("webhook received nothing")
+	}
+
+	// Sent once per scheduled time
+	if due := sender.Check(ctx, testNow.Add(30*time.Second)); len(due) != 0 {
+		t.Errorf("sent twice: %+v", due)
+	}
+}
+
+func TestNewSenderWithoutDigests(t *testing.T) {
+	if sender, err := NewSender(config.DigestConfig{}, Source{}, ""); sender != nil || err != nil {
+		t.Errorf("NewSender() = %v, %v, want nil, nil", sender, err)
+	}
+}
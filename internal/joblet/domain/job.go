@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"strings"
 	"time"
@@ -21,6 +24,8 @@ const (
 	StatusInitializing JobStatus = "INITIALIZING"
 	StatusCanceled     JobStatus = "CANCELED"
 	StatusStopping     JobStatus = "STOPPING"
+	StatusQueued       JobStatus = "QUEUED"  // Waiting for a running slot on a saturated node
+	StatusExpired      JobStatus = "EXPIRED" // Did not start within its queue TTL
 )
 
 var (
@@ -65,6 +70,8 @@ type Job struct {
 	Network string   // Network name
 	Volumes []string // Volume names to mount
 	Runtime string   // Runtime specification
+	// Runtime alias the job asked for, resolved to Runtime (empty = asked for Runtime itself)
+	RequestedRuntime string
 
 	// Workflow integration
 	WorkflowUuid     string       // UUID of parent workflow (empty for individual jobs)
@@ -81,6 +88,97 @@ type Job struct {
 	GPUCount    int32   // Number of GPUs requested/allocated
 	GPUMemoryMB int64   // GPU memory requirement in MB
 
+	// Filesystem sizing
+	ShmSizeBytes int64  // Size of the /dev/shm tmpfs (0 = no /dev/shm mount)
+	TmpSizeBytes int64  // Size of the /tmp tmpfs (0 = host-backed, unlimited)
+	Scratch      string // Scratch device holding the work directory (empty = default work directory)
+	FreezeFS     bool   // Filesystem is kept for browsing if the job fails
+
+	// Profiling
+	Profile string // Profiler wrapping the command: "strace", "perf" or empty
+
+	// Upload delivered to the command's stdin, relative to the workspace (empty = no stdin)
+	StdinPath string
+
+	// Workflow outputs
+	OutputsFile string // JSON document the job writes under /work (empty = none)
+	Outputs     []byte // Content of OutputsFile, read when the job ended
+
+	// Output redaction
+	Redactions int64 // Secret matches masked in the job's output
+
+	// Output limits
+	OutputDroppedBytes int64 // Output dropped over the node's rate limit or size cap
+	OutputTruncated    bool  // The job reached the size cap and its remaining output was dropped
+
+	// Progress the job reported with marker lines in its output (nil = none yet)
+	Progress *JobProgress
+
+	// Network activity of the job's namespace, recorded when it ended (nil
+	// when it shared the host's network or was never sampled)
+	NetworkSummary *JobNetworkSummary
+
+	// Submission signature (nil for unsigned jobs)
+	Signature *JobSignature
+
+	// Tenant of the submitting client (empty when it belongs to none)
+	Tenant string
+
+	// Group of related jobs managed together (empty when ungrouped)
+	Group string
+
+	// Labels are key=value tags for selecting jobs in bulk operations
+	Labels map[string]string
+
+	// Annotations are key=value notes on where the job comes from, such as
+	// the CI pipeline and commit that submitted it; unlike labels, values are
+	// free text and are not used to select jobs
+	Annotations map[string]string
+
+	// Job array the job was submitted with (empty when not part of one) and
+	// its index in the array
+	ArrayUuid  string
+	ArrayIndex int
+
+	// External destinations the job's output is copied to as it is written
+	LogSinks []string
+
+	// Raw cgroup v2 files written to the job's cgroup (e.g. cpu.weight)
+	CgroupParams map[string]string
+
+	// Longest the job may wait to start after its submission, or its
+	// scheduled time, before it expires (0 = no limit)
+	QueueTTL time.Duration
+
+	// IPC channel whose /dev/shm the job shares with the other jobs that
+	// joined it (empty = a /dev/shm of its own)
+	IPCChannel string
+
+	// Objects downloaded into the workspace before the job starts, as
+	// "s3://bucket/key:/work/path"
+	Inputs []string
+
+	// Workspace files uploaded to object storage when the job completes, as
+	// "/work/out/*.parquet:s3://bucket/prefix/"
+	OutputTargets []string
+
+	// Egress policy the job's network namespace enforces, canonical rules
+	// such as "allow:api.internal:443" matched in order (nil = no policy)
+	Egress []string
+
+	// Secret variables delivered as files on a tmpfs instead of through the
+	// environment, path in the job's filesystem -> variable (nil = none)
+	SecretFiles map[string]string
+
+	// Launch attempts, more than one when infrastructure failures were retried
+	Attempts int32
+
+	// Timeline of launch attempts and infrastructure failures
+	Events []JobEvent
+
+	// Environment of the last launch attempt (nil until the job starts)
+	Fingerprint *JobFingerprint
+
 	// Node identification
 	NodeId string // Unique identifier of the Joblet node that executed this job
 
@@ -110,6 +208,23 @@ func (j *Job) IsScheduled() bool {
 	return j.Status == StatusScheduled
 }
 
+// IsQueued returns true if the job waits in the admission queue
+func (j *Job) IsQueued() bool {
+	return j.Status == StatusQueued
+}
+
+// StartDeadline returns when a job waiting to start expires: its queue TTL
+// after its scheduled time, or after its submission. False without a TTL.
+func (j *Job) StartDeadline() (time.Time, bool) {
+	if j.QueueTTL <= 0 {
+		return time.Time{}, false
+	}
+	if j.ScheduledTime != nil {
+		return j.ScheduledTime.Add(j.QueueTTL), true
+	}
+	return j.StartTime.Add(j.QueueTTL), true
+}
+
 // IsRuntimeBuild returns true if this is a runtime build job
 func (j *Job) IsRuntimeBuild() bool {
 	return j.Type == JobTypeRuntimeBuild
@@ -405,9 +520,10 @@ func (j *Job) DeepCopy() *Job {
 		ExitCode:  j.ExitCode,
 
 		// Infrastructure
-		Network: j.Network,
-		Volumes: make([]string, len(j.Volumes)),
-		Runtime: j.Runtime,
+		Network:          j.Network,
+		Volumes:          make([]string, len(j.Volumes)),
+		Runtime:          j.Runtime,
+		RequestedRuntime: j.RequestedRuntime,
 
 		// Environment
 		Environment:       make(map[string]string),
@@ -418,6 +534,57 @@ func (j *Job) DeepCopy() *Job {
 		GPUCount:    j.GPUCount,
 		GPUMemoryMB: j.GPUMemoryMB,
 
+		// Filesystem sizing
+		ShmSizeBytes: j.ShmSizeBytes,
+		TmpSizeBytes: j.TmpSizeBytes,
+		Scratch:      j.Scratch,
+		FreezeFS:     j.FreezeFS,
+
+		// Profiling
+		Profile: j.Profile,
+
+		// Stdin
+		StdinPath: j.StdinPath,
+
+		// Workflow outputs
+		OutputsFile: j.OutputsFile,
+		Outputs:     bytes.Clone(j.Outputs),
+
+		// Output redaction
+		Redactions: j.Redactions,
+
+		// Output limits
+		OutputDroppedBytes: j.OutputDroppedBytes,
+		OutputTruncated:    j.OutputTruncated,
+
+		// Progress
+		Progress: j.Progress.DeepCopy(),
+
+		// Network summary
+		NetworkSummary: j.NetworkSummary.DeepCopy(),
+
+		// Submission signature
+		Signature: j.Signature.DeepCopy(),
+
+		// Tenant
+		Tenant: j.Tenant,
+
+		// Group
+		Group: j.Group,
+
+		// Job array
+		ArrayUuid:  j.ArrayUuid,
+		ArrayIndex: j.ArrayIndex,
+
+		// Queue TTL
+		QueueTTL: j.QueueTTL,
+
+		// IPC channel
+		IPCChannel: j.IPCChannel,
+
+		// Attempts
+		Attempts: j.Attempts,
+
 		// Node identification
 		NodeId: j.NodeId,
 	}
@@ -426,6 +593,21 @@ func (j *Job) DeepCopy() *Job {
 	copy(jobCopy.Args, j.Args)
 	copy(jobCopy.Volumes, j.Volumes)
 	copy(jobCopy.GPUIndices, j.GPUIndices)
+	if j.Events != nil {
+		jobCopy.Events = append([]JobEvent(nil), j.Events...)
+	}
+	if j.LogSinks != nil {
+		jobCopy.LogSinks = append([]string(nil), j.LogSinks...)
+	}
+	if j.Inputs != nil {
+		jobCopy.Inputs = append([]string(nil), j.Inputs...)
+	}
+	if j.OutputTargets != nil {
+		jobCopy.OutputTargets = append([]string(nil), j.OutputTargets...)
+	}
+	if j.Egress != nil {
+		jobCopy.Egress = append([]string(nil), j.Egress...)
+	}
 
 	// Deep copy environment maps
 	for k, v := range j.Environment {
@@ -434,6 +616,30 @@ func (j *Job) DeepCopy() *Job {
 	for k, v := range j.SecretEnvironment {
 		jobCopy.SecretEnvironment[k] = v
 	}
+	if j.Labels != nil {
+		jobCopy.Labels = make(map[string]string, len(j.Labels))
+		for k, v := range j.Labels {
+			jobCopy.Labels[k] = v
+		}
+	}
+	if j.Annotations != nil {
+		jobCopy.Annotations = make(map[string]string, len(j.Annotations))
+		for k, v := range j.Annotations {
+			jobCopy.Annotations[k] = v
+		}
+	}
+	if j.CgroupParams != nil {
+		jobCopy.CgroupParams = make(map[string]string, len(j.CgroupParams))
+		for k, v := range j.CgroupParams {
+			jobCopy.CgroupParams[k] = v
+		}
+	}
+	if j.SecretFiles != nil {
+		jobCopy.SecretFiles = make(map[string]string, len(j.SecretFiles))
+		for k, v := range j.SecretFiles {
+			jobCopy.SecretFiles[k] = v
+		}
+	}
 
 	// Copy pointers
 	if j.EndTime != nil {
@@ -444,10 +650,118 @@ func (j *Job) DeepCopy() *Job {
 		scheduledTime := *j.ScheduledTime
 		jobCopy.ScheduledTime = &scheduledTime
 	}
+	if j.Fingerprint != nil {
+		fingerprint := *j.Fingerprint
+		jobCopy.Fingerprint = &fingerprint
+	}
 
 	return jobCopy
 }
 
+// JobSignature is a client's Ed25519 signature of a job or workflow
+// submission, see pkg/jobsign for what it covers.
+type JobSignature struct {
+	PublicKey []byte    // Raw Ed25519 public key of the signer
+	Signature []byte    // Signature of the submission digest
+	SignedAt  time.Time // Signing time, part of the digest
+}
+
+// DeepCopy creates a copy of the signature
+func (s *JobSignature) DeepCopy() *JobSignature {
+	if s == nil {
+		return nil
+	}
+	return &JobSignature{
+		PublicKey: append([]byte(nil), s.PublicKey...),
+		Signature: append([]byte(nil), s.Signature...),
+		SignedAt:  s.SignedAt,
+	}
+}
+
+// JobProgress is the progress a job last reported, see internal/joblet/progress
+// for the marker lines it comes from
+type JobProgress struct {
+	Percent   float64   // 0-100, -1 until the job reports one
+	Phase     string    // Current phase, e.g. "download" or "train"
+	Message   string    // Free-form detail, e.g. "epoch 4/10"
+	UpdatedAt time.Time // When the last marker line was read
+}
+
+// DeepCopy creates a copy of the progress
+func (p *JobProgress) DeepCopy() *JobProgress {
+	if p == nil {
+		return nil
+	}
+	progress := *p
+	return &progress
+}
+
+// JobNetworkSummary is the network activity of a job's namespace over its run
+type JobNetworkSummary struct {
+	RxBytes         uint64 `json:"rxBytes"`
+	TxBytes         uint64 `json:"txBytes"`
+	RxPackets       uint64 `json:"rxPackets"`
+	TxPackets       uint64 `json:"txPackets"`
+	PeakConnections uint64 `json:"peakConnections"` // Most established TCP connections in a sample
+	Retransmits     uint64 `json:"retransmits"`     // TCP segments retransmitted
+}
+
+// DeepCopy creates a copy of the network summary
+func (s *JobNetworkSummary) DeepCopy() *JobNetworkSummary {
+	if s == nil {
+		return nil
+	}
+	summary := *s
+	return &summary
+}
+
+// Job event types recorded in the job's timeline
+const (
+	JobEventStarted      = "STARTED"       // A launch attempt began
+	JobEventInfraFailure = "INFRA_FAILURE" // The node failed to set up or launch the job
+	JobEventRetrying     = "RETRYING"      // The job is being relaunched after an infrastructure failure
+	JobEventQueued       = "QUEUED"        // The node was saturated, the job waits for a running slot
+	JobEventMaintenance  = "MAINTENANCE"   // A maintenance window is open, the job waits for it to end
+	JobEventRuntime      = "RUNTIME"       // The requested runtime was an alias, or was selected for an uploaded dependency file
+	JobEventOutputs      = "OUTPUTS"       // The job's workflow outputs document could not be read
+	JobEventHook         = "HOOK"          // A pre_start hook configured on the node failed
+	JobEventExpired      = "EXPIRED"       // The job did not start within its queue TTL
+	JobEventInputs       = "INPUTS"        // The job's inputs were fetched from object storage
+	JobEventPublish      = "PUBLISH"       // The job's output files were uploaded to object storage, or failed to be
+)
+
+// JobFingerprint describes the environment a job ran in, so runs of the same
+// job can be compared when one of them behaves differently
+type JobFingerprint struct {
+	Runtime         string `json:"runtime,omitempty"`         // Installed runtime name, empty without a runtime
+	RuntimeVersion  string `json:"runtimeVersion,omitempty"`  // Version from the runtime's runtime.yml
+	RuntimeChecksum string `json:"runtimeChecksum,omitempty"` // SHA-256 of the installed runtime, see runtime.Resolver.Checksum
+	MountsHash      string `json:"mountsHash"`                // SHA-256 of the host directories mounted into the chroot
+	KernelVersion   string `json:"kernelVersion"`
+	JobletVersion   string `json:"jobletVersion"`
+}
+
+// Digest is a short hash of every fingerprint field, equal for two jobs only
+// when they ran in the same environment
+func (f *JobFingerprint) Digest() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		f.Runtime, f.RuntimeVersion, f.RuntimeChecksum, f.MountsHash, f.KernelVersion, f.JobletVersion,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// JobEvent is an entry in a job's timeline
+type JobEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`    // One of the JobEvent* constants
+	Message string    `json:"message"` // Attempt number or failure details
+}
+
+// AddEvent appends an event to the job's timeline
+func (j *Job) AddEvent(eventType, message string) {
+	j.Events = append(j.Events, JobEvent{Time: time.Now(), Type: eventType, Message: message})
+}
+
 // MaskedSecretEnvironment returns secret environment with masked values for DTO conversion
 func (j *Job) MaskedSecretEnvironment() map[string]string {
 	if len(j.SecretEnvironment) == 0 {
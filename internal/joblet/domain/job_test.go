@@ -174,6 +174,41 @@ func TestJobDeepCopy(t *testing.T) {
 	}
 }
 
+func TestJobDeepCopyEvents(t *testing.T) {
+	original := &Job{Uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479", Command: "echo", Attempts: 1}
+	original.AddEvent(JobEventStarted, "attempt 1")
+	original.AddEvent(JobEventInfraFailure, "network setup failed")
+
+	cp := original.DeepCopy()
+	if cp.Attempts != 1 || len(cp.Events) != 2 || cp.Events[1].Type != JobEventInfraFailure {
+		t.Fatalf("attempts/events not copied: %d %v", cp.Attempts, cp.Events)
+	}
+
+	original.Events[0].Message = "changed"
+	original.AddEvent(JobEventRetrying, "attempt 2")
+	if cp.Events[0].Message != "attempt 1" || len(cp.Events) != 2 {
+		t.Error("Deep copy failed: events slice was not properly copied")
+	}
+}
+
+func TestJobFingerprint(t *testing.T) {
+	fp := &JobFingerprint{Runtime: "python-3.11", RuntimeVersion: "3.11.9", KernelVersion: "6.8.0", JobletVersion: "v4.3.3"}
+	original := &Job{Uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479", Command: "python3", Fingerprint: fp}
+
+	cp := original.DeepCopy()
+	if cp.Fingerprint == fp || *cp.Fingerprint != *fp {
+		t.Fatalf("fingerprint not deep copied: %+v", cp.Fingerprint)
+	}
+	if cp.Fingerprint.Digest() != fp.Digest() || len(fp.Digest()) != 12 {
+		t.Errorf("Digest() = %q, copy %q", fp.Digest(), cp.Fingerprint.Digest())
+	}
+
+	cp.Fingerprint.KernelVersion = "6.9.0"
+	if cp.Fingerprint.Digest() == fp.Digest() {
+		t.Error("Digest() ignores the kernel version")
+	}
+}
+
 func TestJobIsRunning(t *testing.T) {
 	tests := []struct {
 		status   JobStatus
@@ -215,3 +250,18 @@ func TestJobIsCompleted(t *testing.T) {
 		}
 	}
 }
+
+func TestJobStartDeadline(t *testing.T) {
+	submitted := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scheduled := submitted.Add(3 * time.Hour)
+
+	if _, ok := (&Job{StartTime: submitted}).StartDeadline(); ok {
+		t.Error("job without a queue TTL has a start deadline")
+	}
+	if deadline, ok := (&Job{StartTime: submitted, QueueTTL: 2 * time.Hour}).StartDeadline(); !ok || !deadline.Equal(submitted.Add(2*time.Hour)) {
+		t.Errorf("StartDeadline() = %v, %v, want 2h after submission", deadline, ok)
+	}
+	if deadline, ok := (&Job{StartTime: submitted, ScheduledTime: &scheduled, QueueTTL: time.Hour}).StartDeadline(); !ok || !deadline.Equal(scheduled.Add(time.Hour)) {
+		t.Errorf("StartDeadline() = %v, %v, want 1h after the scheduled time", deadline, ok)
+	}
+}
@@ -0,0 +1,71 @@
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxAnnotations caps the annotations of a job
+const MaxAnnotations = 64
+
+// maxAnnotationValue caps an annotation value, room for a pipeline URL
+const maxAnnotationValue = 1024
+
+// ValidateAnnotation checks a job annotation, a key=value note such as the CI
+// pipeline that submitted the job. Keys follow the label keys; values are
+// free text, such as URLs, on a single line.
+func ValidateAnnotation(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("annotation key cannot be empty")
+	}
+	if len(key) > 63 || !labelKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid annotation key: %s", key)
+	}
+	if len(value) > maxAnnotationValue || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return fmt.Errorf("invalid value for annotation %s: must be a single line of at most %d bytes", key, maxAnnotationValue)
+	}
+	return nil
+}
+
+// ParseAnnotation parses and validates a KEY=VALUE annotation; the value may
+// contain '='
+func ParseAnnotation(pair string) (string, string, error) {
+	key, value, found := strings.Cut(pair, "=")
+	if !found {
+		return "", "", fmt.Errorf("invalid annotation %q: expected KEY=VALUE", pair)
+	}
+	if err := ValidateAnnotation(key, value); err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// ParseAnnotations parses annotations sent as a JSON object, as produced by
+// FormatAnnotations
+func ParseAnnotations(data string) (map[string]string, error) {
+	var annotations map[string]string
+	if err := json.Unmarshal([]byte(data), &annotations); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of strings: %w", err)
+	}
+	if annotations == nil {
+		annotations = make(map[string]string) // "null"
+	}
+	if len(annotations) > MaxAnnotations {
+		return nil, fmt.Errorf("too many annotations: %d (at most %d)", len(annotations), MaxAnnotations)
+	}
+	for key, value := range annotations {
+		if err := ValidateAnnotation(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return annotations, nil
+}
+
+// FormatAnnotations renders annotations as a JSON object; values may hold
+// commas and '=', which a KEY=VALUE list could not carry
+func FormatAnnotations(annotations map[string]string) string {
+	data, _ := json.Marshal(annotations) // A map of strings always encodes
+	return string(data)
+}
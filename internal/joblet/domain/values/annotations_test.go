@@ -0,0 +1,59 @@
+package values
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAnnotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		pair      string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{"url value", "ci.url=https://github.com/acme/app/actions/runs/42?a=b,c", "ci.url", "https://github.com/acme/app/actions/runs/42?a=b,c", false},
+		{"empty value", "note=", "note", "", false},
+		{"no equals", "ci.url", "", "", true},
+		{"empty key", "=x", "", "", true},
+		{"space in key", "ci url=x", "", "", true},
+		{"newline in value", "note=a\nb", "", "", true},
+		{"value too long", "note=" + strings.Repeat("x", 1025), "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value, err := ParseAnnotation(tt.pair)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAnnotation(%q) error = %v, wantErr %v", tt.pair, err, tt.wantErr)
+			}
+			if !tt.wantErr && (key != tt.wantKey || value != tt.wantValue) {
+				t.Errorf("ParseAnnotation(%q) = %q, %q", tt.pair, key, value)
+			}
+		})
+	}
+}
+
+func TestFormatAndParseAnnotations(t *testing.T) {
+	annotations := map[string]string{"ci.provider": "github-actions", "ci.url": "https://ci.example.com/run?id=1,2"}
+	parsed, err := ParseAnnotations(FormatAnnotations(annotations))
+	if err != nil || !reflect.DeepEqual(parsed, annotations) {
+		t.Errorf("ParseAnnotations(FormatAnnotations()) = %v, %v", parsed, err)
+	}
+
+	if _, err := ParseAnnotations("ci.provider=github"); err == nil {
+		t.Error("ParseAnnotations accepted a KEY=VALUE list")
+	}
+	if _, err := ParseAnnotations(`{"bad key":"x"}`); err == nil {
+		t.Error("ParseAnnotations accepted an invalid key")
+	}
+	many := make(map[string]string)
+	for i := 0; i <= MaxAnnotations; i++ {
+		many[strings.Repeat("k", i+1)] = "v"
+	}
+	if _, err := ParseAnnotations(FormatAnnotations(many)); err == nil {
+		t.Error("ParseAnnotations accepted more than MaxAnnotations annotations")
+	}
+}
@@ -0,0 +1,36 @@
+package values
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// cgroupParamPattern matches cgroup v2 interface files of a controller,
+// such as cpu.weight or memory.swap.max
+var cgroupParamPattern = regexp.MustCompile(`^[a-z]+(\.[a-zA-Z0-9_]+)+$`)
+
+// ValidateCgroupParamName checks that name is a controller's cgroup v2
+// interface file. The cgroup.* core files are never accepted, they control
+// membership and delegation rather than resources.
+func ValidateCgroupParamName(name string) error {
+	if !cgroupParamPattern.MatchString(name) {
+		return fmt.Errorf("invalid cgroup parameter %q: expected a cgroup v2 file such as cpu.weight", name)
+	}
+	if strings.HasPrefix(name, "cgroup.") {
+		return fmt.Errorf("invalid cgroup parameter %q: cgroup.* core files cannot be set", name)
+	}
+	return nil
+}
+
+// ValidateCgroupParam checks a raw cgroup v2 setting, the file name and the
+// single line written to it
+func ValidateCgroupParam(name, value string) error {
+	if err := ValidateCgroupParamName(name); err != nil {
+		return err
+	}
+	if value == "" || len(value) > 256 || strings.ContainsAny(value, "\n\x00") {
+		return fmt.Errorf("invalid value for cgroup parameter %s: must be a single line of at most 256 bytes", name)
+	}
+	return nil
+}
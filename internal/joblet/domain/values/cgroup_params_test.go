@@ -0,0 +1,32 @@
+package values
+
+import "testing"
+
+func TestValidateCgroupParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   string
+		value   string
+		wantErr bool
+	}{
+		{"weight", "cpu.weight", "200", false},
+		{"nested file", "memory.swap.max", "0", false},
+		{"hugetlb size", "hugetlb.2MB.max", "0", false},
+		{"value with spaces", "io.latency", "8:0 target=10", false},
+		{"core file", "cgroup.procs", "1", true},
+		{"subtree control", "cgroup.subtree_control", "+cpu", true},
+		{"path", "../cpu.weight", "200", true},
+		{"no controller", "weight", "200", true},
+		{"upper case", "CPU.weight", "200", true},
+		{"empty value", "cpu.weight", "", true},
+		{"multi-line value", "cpu.weight", "200\n100", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCgroupParam(tt.param, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCgroupParam(%q, %q) error = %v, wantErr %v", tt.param, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
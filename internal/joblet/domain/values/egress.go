@@ -0,0 +1,176 @@
+package values
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// egressHostPattern matches the DNS names an egress rule may name
+var egressHostPattern = regexp.MustCompile(`^[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?(\.[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?)*$`)
+
+// EgressRule is one rule of a job's egress policy, "allow:HOST[:PORT]" or
+// "deny:HOST[:PORT]". The rules of a policy are matched in order and the
+// first one matching a connection decides; connections no rule matches are
+// allowed, so policies restricting a job end with "deny:*".
+type EgressRule struct {
+	Allow bool
+	// Host is "*", an IP address, a CIDR or a DNS name the node resolves
+	// when the job starts
+	Host string
+	// FromPort and ToPort are the TCP and UDP destination ports the rule
+	// matches (0 = any port and protocol)
+	FromPort int
+	ToPort   int
+}
+
+// AnyHost returns true if the rule matches every destination address
+func (r EgressRule) AnyHost() bool {
+	return r.Host == "*"
+}
+
+// IsName returns true if the rule names a host to resolve rather than
+// addresses
+func (r EgressRule) IsName() bool {
+	if r.AnyHost() || net.ParseIP(r.Host) != nil {
+		return false
+	}
+	_, _, err := net.ParseCIDR(r.Host)
+	return err != nil
+}
+
+// String returns the rule in the canonical form ParseEgressRule accepts
+func (r EgressRule) String() string {
+	action := "deny"
+	if r.Allow {
+		action = "allow"
+	}
+	host := r.Host
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	switch {
+	case r.FromPort == 0:
+		return action + ":" + host
+	case r.FromPort == r.ToPort:
+		return fmt.Sprintf("%s:%s:%d", action, host, r.FromPort)
+	default:
+		return fmt.Sprintf("%s:%s:%d-%d", action, host, r.FromPort, r.ToPort)
+	}
+}
+
+// ParseEgressPolicy parses a comma-separated list of egress rules such as
+// "allow:api.internal:443,deny:*"
+func ParseEgressPolicy(policy string) ([]EgressRule, error) {
+	var rules []EgressRule
+	for _, spec := range strings.Split(policy, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		rule, err := ParseEgressRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("invalid egress policy %q: expected rules such as allow:api.internal:443,deny:*", policy)
+	}
+	return rules, nil
+}
+
+// FormatEgressPolicy returns rules as the comma-separated list
+// ParseEgressPolicy accepts
+func FormatEgressPolicy(rules []EgressRule) string {
+	specs := make([]string, len(rules))
+	for i, rule := range rules {
+		specs[i] = rule.String()
+	}
+	return strings.Join(specs, ",")
+}
+
+// ParseEgressRule parses and validates a single egress rule. IPv6 addresses
+// are written in brackets, "allow:[2001:db8::/32]:443".
+func ParseEgressRule(spec string) (EgressRule, error) {
+	action, target, found := strings.Cut(spec, ":")
+	if !found || target == "" {
+		return EgressRule{}, fmt.Errorf("invalid egress rule %q: expected allow:HOST[:PORT] or deny:HOST[:PORT]", spec)
+	}
+	var rule EgressRule
+	switch action {
+	case "allow":
+		rule.Allow = true
+	case "deny":
+	default:
+		return EgressRule{}, fmt.Errorf("invalid egress rule %q: the action must be allow or deny", spec)
+	}
+
+	host, port := target, ""
+	if strings.HasPrefix(target, "[") {
+		end := strings.Index(target, "]")
+		if end < 0 {
+			return EgressRule{}, fmt.Errorf("invalid egress rule %q: unclosed [ around the IPv6 address", spec)
+		}
+		host = target[1:end]
+		if rest := target[end+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return EgressRule{}, fmt.Errorf("invalid egress rule %q: expected :PORT after the IPv6 address", spec)
+			}
+			port = rest[1:]
+		}
+		if !strings.Contains(host, ":") {
+			return EgressRule{}, fmt.Errorf("invalid egress rule %q: only IPv6 addresses are written in brackets", spec)
+		}
+	} else if h, p, found := strings.Cut(target, ":"); found {
+		host, port = h, p
+	}
+
+	var err error
+	if rule.Host, err = canonicalEgressHost(host); err != nil {
+		return EgressRule{}, fmt.Errorf("invalid egress rule %q: %w", spec, err)
+	}
+	if port != "" {
+		if rule.FromPort, rule.ToPort, err = parsePortRange(port); err != nil {
+			return EgressRule{}, fmt.Errorf("invalid egress rule %q: %w", spec, err)
+		}
+	}
+	return rule, nil
+}
+
+// canonicalEgressHost validates the host of a rule and returns it in
+// canonical form: addresses and networks as Go prints them, names lowercase
+func canonicalEgressHost(host string) (string, error) {
+	if host == "*" {
+		return host, nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	if _, ipNet, err := net.ParseCIDR(host); err == nil {
+		return ipNet.String(), nil
+	}
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if len(name) > 253 || !egressHostPattern.MatchString(name) {
+		return "", fmt.Errorf("%q is not *, an IP address, a CIDR or a host name (write IPv6 addresses in brackets)", host)
+	}
+	return name, nil
+}
+
+// parsePortRange parses "443" or "8000-8100"
+func parsePortRange(spec string) (int, int, error) {
+	from, to, isRange := strings.Cut(spec, "-")
+	if !isRange {
+		to = from
+	}
+	fromPort, err := strconv.Atoi(from)
+	if err != nil || fromPort < 1 || fromPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid port %q: must be 1-65535 or a range such as 8000-8100", spec)
+	}
+	toPort, err := strconv.Atoi(to)
+	if err != nil || toPort < fromPort || toPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid port %q: must be 1-65535 or a range such as 8000-8100", spec)
+	}
+	return fromPort, toPort, nil
+}
@@ -0,0 +1,64 @@
+package values
+
+import (
+	"testing"
+)
+
+func TestParseEgressRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"allow:api.internal:443", "allow:api.internal:443", false},
+		{"deny:*", "deny:*", false},
+		{"allow:*:53", "allow:*:53", false},
+		{"allow:API.Internal.:8000-8100", "allow:api.internal:8000-8100", false},
+		{"allow:10.1.2.3/8", "allow:10.0.0.0/8", false},
+		{"deny:192.168.1.10:22", "deny:192.168.1.10:22", false},
+		{"allow:[2001:db8::1]:443", "allow:[2001:db8::1]:443", false},
+		{"allow:[2001:DB8::/32]", "allow:[2001:db8::/32]", false},
+		{"allow:2001:db8::1", "", true},
+		{"allow:[10.0.0.1]", "", true},
+		{"allow:[2001:db8::1", "", true},
+		{"permit:api.internal", "", true},
+		{"allow", "", true},
+		{"allow:", "", true},
+		{"allow:api.internal:0", "", true},
+		{"allow:api.internal:65536", "", true},
+		{"allow:api.internal:9000-8000", "", true},
+		{"allow:api internal", "", true},
+		{"allow:-bad.example", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			rule, err := ParseEgressRule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEgressRule(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && rule.String() != tt.want {
+				t.Errorf("ParseEgressRule(%q) = %s, want %s", tt.spec, rule, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEgressPolicy(t *testing.T) {
+	rules, err := ParseEgressPolicy("allow:api.internal:443, allow:10.0.0.0/8 ,deny:*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatEgressPolicy(rules); got != "allow:api.internal:443,allow:10.0.0.0/8,deny:*" {
+		t.Errorf("FormatEgressPolicy = %s", got)
+	}
+	if !rules[0].IsName() || rules[1].IsName() || rules[2].IsName() || !rules[2].AnyHost() {
+		t.Errorf("IsName/AnyHost of %v", rules)
+	}
+
+	for _, policy := range []string{"", " , ", "allow:api.internal,nope"} {
+		if _, err := ParseEgressPolicy(policy); err == nil {
+			t.Errorf("ParseEgressPolicy(%q) succeeded", policy)
+		}
+	}
+}
@@ -149,6 +149,40 @@ func (v VolumeName) Value() string {
 	return v.value
 }
 
+// GroupName identifies a group of related jobs managed together
+type GroupName struct {
+	value string
+}
+
+// NewGroupName creates a new GroupName with validation
+func NewGroupName(name string) (GroupName, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return GroupName{}, fmt.Errorf("group name cannot be empty")
+	}
+
+	// Same format as volume names, short enough to show in job listings
+	if matched, _ := regexp.MatchString(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`, name); !matched {
+		return GroupName{}, fmt.Errorf("invalid group name format: %s", name)
+	}
+
+	if len(name) > 64 {
+		return GroupName{}, fmt.Errorf("group name too long: %s", name)
+	}
+
+	return GroupName{value: name}, nil
+}
+
+// String returns the string representation
+func (g GroupName) String() string {
+	return g.value
+}
+
+// Value returns the underlying string value
+func (g GroupName) Value() string {
+	return g.value
+}
+
 // RuntimeSpec represents a runtime specification
 type RuntimeSpec struct {
 	value string
@@ -0,0 +1,73 @@
+package values
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxJobArraySize caps the jobs a single array submission can start
+const MaxJobArraySize = 10000
+
+// ParseArrayIndices parses a job array spec into its sorted indices. The spec
+// is a comma-separated list of indices (3), ranges (0-99) and stepped ranges
+// (0-99:10), e.g. "0-9,20,30-40:5". Indices are non-negative and unique.
+func ParseArrayIndices(spec string) ([]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, fmt.Errorf("job array spec cannot be empty")
+	}
+
+	seen := make(map[int]bool)
+	var indices []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		rangeSpec, stepSpec, stepped := strings.Cut(part, ":")
+		first, last, isRange := strings.Cut(rangeSpec, "-")
+		if !isRange {
+			last = first
+		}
+		if stepped && !isRange {
+			return nil, fmt.Errorf("invalid job array range %q: a step needs a range, e.g. 0-99:10", part)
+		}
+
+		start, err := parseArrayIndex(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job array range %q: %w", part, err)
+		}
+		end, err := parseArrayIndex(last)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job array range %q: %w", part, err)
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid job array range %q: end is before start", part)
+		}
+		step := 1
+		if stepped {
+			if step, err = strconv.Atoi(stepSpec); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid job array range %q: step must be a positive integer", part)
+			}
+		}
+
+		for index := start; index <= end; index += step {
+			if seen[index] {
+				return nil, fmt.Errorf("job array index %d is listed more than once", index)
+			}
+			if len(indices) == MaxJobArraySize {
+				return nil, fmt.Errorf("job array has more than %d jobs", MaxJobArraySize)
+			}
+			seen[index] = true
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+func parseArrayIndex(s string) (int, error) {
+	index, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("%q is not a non-negative index", s)
+	}
+	return index, nil
+}
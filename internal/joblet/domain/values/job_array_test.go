@@ -0,0 +1,38 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArrayIndices(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int
+		wantErr bool
+	}{
+		{"0-3", []int{0, 1, 2, 3}, false},
+		{"7", []int{7}, false},
+		{"10,1,3-4", []int{1, 3, 4, 10}, false},
+		{"0-20:10", []int{0, 10, 20}, false},
+		{"", nil, true},
+		{"5-2", nil, true},
+		{"-1", nil, true},
+		{"1-3,2", nil, true},
+		{"4:2", nil, true},
+		{"0-9:0", nil, true},
+		{"a-b", nil, true},
+		{"0-10000", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseArrayIndices(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseArrayIndices(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseArrayIndices(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package values
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_./-]*$`)
+	labelValuePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]*$`)
+)
+
+// ValidateLabel checks a job label, a key=value tag used to select jobs.
+// Keys and values are limited to characters that need no quoting in flags
+// and the comma-separated list they are sent in.
+func ValidateLabel(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("label key cannot be empty")
+	}
+	if len(key) > 63 || !labelKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid label key: %s", key)
+	}
+	if len(value) > 63 || !labelValuePattern.MatchString(value) {
+		return fmt.Errorf("invalid value for label %s: %s", key, value)
+	}
+	return nil
+}
+
+// ParseLabel parses and validates a KEY=VALUE label
+func ParseLabel(pair string) (string, string, error) {
+	key, value, found := strings.Cut(pair, "=")
+	if !found {
+		return "", "", fmt.Errorf("invalid label %q: expected KEY=VALUE", pair)
+	}
+	if err := ValidateLabel(key, value); err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// ParseLabels parses a comma-separated list of KEY=VALUE labels as produced
+// by FormatLabels
+func ParseLabels(list string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if list == "" {
+		return labels, nil
+	}
+	for _, pair := range strings.Split(list, ",") {
+		key, value, err := ParseLabel(pair)
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// FormatLabels renders labels as a comma-separated list sorted by key
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,56 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		pair      string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{"simple", "env=staging", "env", "staging", false},
+		{"empty value", "canary=", "canary", "", false},
+		{"prefixed key", "team.io/owner=ml", "team.io/owner", "ml", false},
+		{"value with equals", "a=b=c", "", "", true},
+		{"no equals", "env", "", "", true},
+		{"empty key", "=staging", "", "", true},
+		{"comma in value", "env=a,b", "", "", true},
+		{"space in value", "env=a b", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value, err := ParseLabel(tt.pair)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLabel(%q) error = %v, wantErr %v", tt.pair, err, tt.wantErr)
+			}
+			if !tt.wantErr && (key != tt.wantKey || value != tt.wantValue) {
+				t.Errorf("ParseLabel(%q) = %q, %q", tt.pair, key, value)
+			}
+		})
+	}
+}
+
+func TestFormatAndParseLabels(t *testing.T) {
+	labels := map[string]string{"team": "ml", "env": "staging"}
+	list := FormatLabels(labels)
+	if list != "env=staging,team=ml" {
+		t.Errorf("FormatLabels = %q", list)
+	}
+
+	parsed, err := ParseLabels(list)
+	if err != nil || !reflect.DeepEqual(parsed, labels) {
+		t.Errorf("ParseLabels(%q) = %v, %v", list, parsed, err)
+	}
+	if parsed, err := ParseLabels(""); err != nil || len(parsed) != 0 {
+		t.Errorf("ParseLabels(\"\") = %v, %v", parsed, err)
+	}
+	if _, err := ParseLabels("env=staging,broken"); err == nil {
+		t.Error("expected error for a malformed label in the list")
+	}
+}
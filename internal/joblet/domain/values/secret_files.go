@@ -0,0 +1,79 @@
+package values
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretKeyPattern matches the names of environment variables
+var secretKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretFileForbiddenDirs are mounted over once the job's root is set up, so
+// a file placed under them would be hidden
+var secretFileForbiddenDirs = []string{"/proc", "/sys", "/dev"}
+
+// ValidateSecretFile checks that a secret variable of a job may be delivered
+// as a file at filePath: an absolute, clean path outside /proc, /sys and
+// /dev, and the name of an environment variable.
+func ValidateSecretFile(filePath, key string) error {
+	if !secretKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid secret file %s: %q is not an environment variable name", filePath, key)
+	}
+	if !path.IsAbs(filePath) || path.Clean(filePath) != filePath || filePath == "/" {
+		return fmt.Errorf("invalid secret file %q: must be an absolute, clean file path such as /run/secrets/token", filePath)
+	}
+	if strings.ContainsAny(filePath, ",=\n") {
+		return fmt.Errorf("invalid secret file %q: must not contain ',', '=' or newlines", filePath)
+	}
+	for _, dir := range secretFileForbiddenDirs {
+		if filePath == dir || strings.HasPrefix(filePath, dir+"/") {
+			return fmt.Errorf("invalid secret file %q: must not be under %s", filePath, dir)
+		}
+	}
+	return nil
+}
+
+// ParseSecretFiles parses a comma-separated list of PATH=KEY pairs such as
+// "/root/.aws/credentials=AWS_CREDENTIALS", each delivering the secret
+// variable KEY as a file at PATH
+func ParseSecretFiles(spec string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		filePath, key, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid secret file %q: expected PATH=KEY", pair)
+		}
+		if err := ValidateSecretFile(filePath, key); err != nil {
+			return nil, err
+		}
+		if _, exists := files[filePath]; exists {
+			return nil, fmt.Errorf("invalid secret file %s: given twice", filePath)
+		}
+		files[filePath] = key
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("invalid secret files %q: expected PATH=KEY pairs", spec)
+	}
+	return files, nil
+}
+
+// FormatSecretFiles returns the secret files in the form ParseSecretFiles
+// accepts, sorted by path
+func FormatSecretFiles(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	pairs := make([]string, 0, len(paths))
+	for _, filePath := range paths {
+		pairs = append(pairs, filePath+"="+files[filePath])
+	}
+	return strings.Join(pairs, ",")
+}
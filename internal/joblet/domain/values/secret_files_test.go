@@ -0,0 +1,35 @@
+package values
+
+import (
+	"testing"
+)
+
+func TestParseSecretFiles(t *testing.T) {
+	files, err := ParseSecretFiles(" /root/.aws/credentials=AWS_CREDENTIALS, /work/.kube/config=KUBECONFIG_DATA ,")
+	if err != nil {
+		t.Fatalf("ParseSecretFiles() error = %v", err)
+	}
+	if len(files) != 2 || files["/root/.aws/credentials"] != "AWS_CREDENTIALS" || files["/work/.kube/config"] != "KUBECONFIG_DATA" {
+		t.Errorf("ParseSecretFiles() = %v", files)
+	}
+	if got, want := FormatSecretFiles(files), "/root/.aws/credentials=AWS_CREDENTIALS,/work/.kube/config=KUBECONFIG_DATA"; got != want {
+		t.Errorf("FormatSecretFiles() = %q, want %q", got, want)
+	}
+
+	for _, spec := range []string{
+		"",
+		"/run/token",
+		"run/token=TOKEN",
+		"/run/../etc/token=TOKEN",
+		"/=TOKEN",
+		"/proc/self/token=TOKEN",
+		"/dev/token=TOKEN",
+		"/run/token=API-TOKEN",
+		"/run/token=1TOKEN",
+		"/run/token=A,/run/token=B",
+	} {
+		if files, err := ParseSecretFiles(spec); err == nil {
+			t.Errorf("ParseSecretFiles(%q) = %v, want an error", spec, files)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package envexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// runtimeContext is the build context holding the runtime's installation
+const runtimeContext = "runtime"
+
+// Dockerfile renders the environment as a Dockerfile building an image of
+// the job, with the uploads it still holds under work/ next to it
+func Dockerfile(env *Environment) ([]File, []string, error) {
+	files, notes := uploadFiles(env)
+	notes = append(notes, secretNote(env)...)
+
+	var b strings.Builder
+	b.WriteString("# syntax=docker/dockerfile:1\n")
+	fmt.Fprintf(&b, "# Environment of joblet job %s\n", env.JobUUID)
+	b.WriteString("#\n# Build from this directory")
+	if env.Runtime != "" {
+		fmt.Fprintf(&b, ", with the runtime copied from the node:\n#   docker build --build-context %s=%s -t %s .\n",
+			runtimeContext, env.RuntimeDir, imageName(env))
+	} else {
+		fmt.Fprintf(&b, ":\n#   docker build -t %s .\n", imageName(env))
+	}
+	fmt.Fprintf(&b, "# and run it as the job ran:\n#   docker run --rm %s\n", strings.Join(append(runFlags(env), imageName(env)), " "))
+	fmt.Fprintf(&b, "\nFROM %s\n", env.BaseImage)
+
+	if env.Runtime != "" {
+		fmt.Fprintf(&b, "\n# Runtime %s\n", env.Runtime)
+		for _, m := range env.RuntimeMounts {
+			fmt.Fprintf(&b, "COPY --from=%s %s %s\n", runtimeContext, dockerPath(m.Source), dockerPath(m.Target))
+		}
+	}
+
+	var skipped []string
+	if len(env.Env) > 0 {
+		b.WriteString("\n")
+		for _, entry := range env.Env {
+			key, value, _ := strings.Cut(entry, "=")
+			if strings.ContainsAny(value, "\n\r") {
+				skipped = append(skipped, key)
+				continue
+			}
+			fmt.Fprintf(&b, "ENV %s=%s\n", key, dockerQuote(value))
+		}
+	}
+	if len(skipped) > 0 {
+		notes = append(notes, "environment variables spanning lines are left out of the Dockerfile: "+strings.Join(skipped, ", "))
+	}
+
+	fmt.Fprintf(&b, "\nWORKDIR %s\n", env.WorkDir)
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "COPY work/ %s/\n", strings.TrimSuffix(env.WorkDir, "/"))
+	}
+
+	cmd, err := json.Marshal(append([]string{env.Command}, env.Args...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode command: %w", err)
+	}
+	fmt.Fprintf(&b, "CMD %s\n", cmd)
+
+	return append([]File{{Path: "Dockerfile", Content: []byte(b.String()), Mode: 0644}}, files...), notes, nil
+}
+
+// runFlags are the docker run flags applying the job's limits, network,
+// volumes and secrets
+func runFlags(env *Environment) []string {
+	var flags []string
+	if env.MemoryBytes > 0 {
+		flags = append(flags, fmt.Sprintf("--memory %db", env.MemoryBytes))
+	}
+	if env.CPUPercent > 0 {
+		flags = append(flags, "--cpus "+cpus(env.CPUPercent))
+	}
+	if env.CPUCores != "" {
+		flags = append(flags, "--cpuset-cpus "+env.CPUCores)
+	}
+	if env.ShmSizeBytes > 0 {
+		flags = append(flags, fmt.Sprintf("--shm-size %db", env.ShmSizeBytes))
+	}
+	if env.TmpSizeBytes > 0 {
+		flags = append(flags, fmt.Sprintf("--tmpfs /tmp:size=%d", env.TmpSizeBytes))
+	}
+	if env.Network == "none" {
+		flags = append(flags, "--network none")
+	}
+	for _, v := range env.Volumes {
+		flags = append(flags, "-v "+v.Source+":"+v.Target)
+	}
+	for _, key := range env.SecretKeys {
+		flags = append(flags, "-e "+key)
+	}
+	return flags
+}
+
+// imageName tags the image after the job
+func imageName(env *Environment) string {
+	id, _, _ := strings.Cut(env.JobUUID, "-")
+	return "joblet-job-" + id
+}
+
+// dockerPath makes a mount path absolute, COPY takes sources from the root
+// of their build context
+func dockerPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// dockerQuote quotes an ENV value, escaping what the Dockerfile parser
+// would otherwise interpret
+func dockerQuote(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(value) + `"`
+}
@@ -0,0 +1,174 @@
+// Package envexport describes the environment a job ran in as a Dockerfile or
+// an OCI runtime bundle, to reproduce it outside joblet when debugging.
+//
+// The Dockerfile starts from the image of the node's distro, copies the
+// runtime's mounts in as layers from a named build context and sets the
+// job's environment, working directory and command; the resource limits,
+// volumes and secrets become docker run flags. The OCI bundle runs in place
+// on the node with runc: its config.json bind mounts the same host
+// directories, runtime and volumes the job saw.
+//
+// Secret environment variables are only named, never exported with their
+// values.
+package envexport
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatDockerfile = "dockerfile"
+	FormatOCI        = "oci"
+)
+
+// Mount is a directory mounted into the job's root
+type Mount struct {
+	Source   string // Host path
+	Target   string // Path inside the job
+	ReadOnly bool
+}
+
+// File is a file of an export, relative to the output directory
+type File struct {
+	Path    string
+	Content []byte
+	Mode    uint32
+}
+
+// Upload is a file or directory the job was submitted with
+type Upload struct {
+	Path        string // Relative to the working directory
+	Content     []byte // Nil when the server no longer holds it
+	Mode        uint32
+	IsDirectory bool
+}
+
+// Environment is what a job saw when it ran
+type Environment struct {
+	JobUUID    string
+	BaseImage  string   // Image of the node's distro, e.g. ubuntu:22.04
+	HostMounts []string // Host directories mounted read-only at the same path
+
+	Runtime       string  // Runtime the job ran on (empty = none)
+	RuntimeDir    string  // Directory the runtime is installed in on the node
+	RuntimeMounts []Mount // Sources relative to RuntimeDir
+
+	Volumes []Mount // Sources are the volume's data directory on the node
+
+	Env        []string // KEY=VALUE, as resolved for the job
+	SecretKeys []string // Names of the secret environment variables
+	Command    string
+	Args       []string
+	WorkDir    string // Working directory inside the job
+	Uploads    []Upload
+
+	Network      string // Network the job joined (empty = default)
+	MemoryBytes  int64  // 0 = unlimited
+	CPUPercent   int32  // 100 per core, 0 = unlimited
+	CPUCores     string // Cores the job was pinned to (empty = any)
+	ShmSizeBytes int64  // 0 = no /dev/shm mount
+	TmpSizeBytes int64  // 0 = unlimited
+}
+
+// Export renders the environment in format, with notes on what it could not
+// reproduce
+func Export(env *Environment, format string) ([]File, []string, error) {
+	switch format {
+	case "", FormatDockerfile:
+		return Dockerfile(env)
+	case FormatOCI:
+		return OCIBundle(env)
+	default:
+		return nil, nil, fmt.Errorf("unknown export format %q, must be %s or %s", format, FormatDockerfile, FormatOCI)
+	}
+}
+
+// baseImages maps os-release IDs to the image of the distro, given its
+// VERSION_ID
+var baseImages = map[string]func(version string) string{
+	"ubuntu":        tagged("ubuntu"),
+	"debian":        tagged("debian"),
+	"fedora":        tagged("fedora"),
+	"rocky":         tagged("rockylinux"),
+	"almalinux":     tagged("almalinux"),
+	"centos":        func(v string) string { return "quay.io/centos/centos:stream" + major(v) },
+	"rhel":          func(v string) string { return "registry.access.redhat.com/ubi" + major(v) },
+	"ol":            tagged("oraclelinux"),
+	"amzn":          tagged("amazonlinux"),
+	"opensuse-leap": tagged("opensuse/leap"),
+	"sles":          tagged("registry.suse.com/suse/sle15"),
+	"alpine":        func(v string) string { return "alpine:" + minor(v) },
+	"arch":          func(string) string { return "archlinux:latest" },
+}
+
+// BaseImage returns the image of the distro an os-release file describes,
+// or debian:stable-slim when it is unknown
+func BaseImage(osRelease map[string]string) string {
+	version := osRelease["VERSION_ID"]
+	if image, ok := baseImages[strings.ToLower(osRelease["ID"])]; ok && (version != "" || osRelease["ID"] == "arch") {
+		return image(version)
+	}
+	return "debian:stable-slim"
+}
+
+func tagged(repository string) func(string) string {
+	return func(version string) string { return repository + ":" + version }
+}
+
+// major returns the major version of a VERSION_ID such as 9.3
+func major(version string) string {
+	v, _, _ := strings.Cut(version, ".")
+	return v
+}
+
+// minor returns the major and minor version of a VERSION_ID such as 3.19.1
+func minor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	return strings.Join(parts[:min(len(parts), 2)], ".")
+}
+
+// uploadFiles returns the uploads the server still holds as files under
+// work/, with a note naming those it does not
+func uploadFiles(env *Environment) ([]File, []string) {
+	var files []File
+	var missing []string
+	for _, upload := range env.Uploads {
+		if upload.IsDirectory {
+			continue
+		}
+		if upload.Content == nil {
+			missing = append(missing, upload.Path)
+			continue
+		}
+		mode := upload.Mode & 0777
+		if mode == 0 {
+			mode = 0644
+		}
+		files = append(files, File{Path: path.Join("work", path.Clean("/"+upload.Path)), Content: upload.Content, Mode: mode})
+	}
+	if len(missing) == 0 {
+		return files, nil
+	}
+	sort.Strings(missing)
+	return files, []string{"uploaded files are no longer held by the server, copy them into work/: " + strings.Join(missing, ", ")}
+}
+
+// secretNote names the secret environment variables left out of an export
+func secretNote(env *Environment) []string {
+	if len(env.SecretKeys) == 0 {
+		return nil
+	}
+	keys := append([]string(nil), env.SecretKeys...)
+	sort.Strings(keys)
+	return []string{"secret environment variables are not exported, set them yourself: " + strings.Join(keys, ", ")}
+}
+
+// cpus formats a CPU percentage as a number of cores
+func cpus(percent int32) string {
+	return strconv.FormatFloat(float64(percent)/100, 'f', -1, 64)
+}
@@ -0,0 +1,144 @@
+package envexport
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testEnvironment() *Environment {
+	return &Environment{
+		JobUUID:       "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		BaseImage:     "ubuntu:22.04",
+		HostMounts:    []string{"/bin", "/usr/lib"},
+		Runtime:       "python-3.11@1.0",
+		RuntimeDir:    "/opt/joblet/runtimes/python-3.11/1.0",
+		RuntimeMounts: []Mount{{Source: "isolated/usr/bin", Target: "/usr/local/bin", ReadOnly: true}},
+		Volumes:       []Mount{{Source: "/opt/joblet/volumes/data/data", Target: "/volumes/data"}},
+		Env:           []string{"GREETING=say \"hi\" $HOME", "PATH=/usr/local/bin:/usr/bin:/bin", "MULTI=a\nb"},
+		SecretKeys:    []string{"TOKEN", "API_KEY"},
+		Command:       "python3",
+		Args:          []string{"train.py", "--epochs", "10"},
+		WorkDir:       "/work",
+		Uploads: []Upload{
+			{Path: "train.py", Content: []byte("print(1)\n"), Mode: 0755},
+			{Path: "data", IsDirectory: true},
+			{Path: "data/big.csv"},
+		},
+		MemoryBytes:  512 << 20,
+		CPUPercent:   150,
+		CPUCores:     "0-3",
+		ShmSizeBytes: 64 << 20,
+	}
+}
+
+func TestDockerfile(t *testing.T) {
+	files, notes, err := Export(testEnvironment(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != "Dockerfile" || files[1].Path != "work/train.py" || files[1].Mode != 0755 {
+		t.Fatalf("files = %+v", files)
+	}
+
+	dockerfile := string(files[0].Content)
+	for _, want := range []string{
+		"# syntax=docker/dockerfile:1\n",
+		"docker build --build-context runtime=/opt/joblet/runtimes/python-3.11/1.0 -t joblet-job-f47ac10b .",
+		"docker run --rm --memory 536870912b --cpus 1.5 --cpuset-cpus 0-3 --shm-size 67108864b -v /opt/joblet/volumes/data/data:/volumes/data -e TOKEN -e API_KEY joblet-job-f47ac10b",
+		"FROM ubuntu:22.04\n",
+		"COPY --from=runtime /isolated/usr/bin /usr/local/bin\n",
+		`ENV GREETING="say \"hi\" \$HOME"` + "\n",
+		"WORKDIR /work\nCOPY work/ /work/\n",
+		`CMD ["python3","train.py","--epochs","10"]` + "\n",
+	} {
+		if !strings.Contains(dockerfile, want) {
+			t.Errorf("Dockerfile lacks %q:\n%s", want, dockerfile)
+		}
+	}
+	if strings.Contains(dockerfile, "MULTI") {
+		t.Errorf("Dockerfile sets a variable spanning lines:\n%s", dockerfile)
+	}
+
+	all := strings.Join(notes, "\n")
+	for _, want := range []string{"data/big.csv", "API_KEY, TOKEN", "MULTI"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("notes lack %q: %v", want, notes)
+		}
+	}
+}
+
+func TestOCIBundle(t *testing.T) {
+	files, notes, err := Export(testEnvironment(), FormatOCI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 || files[0].Path != "config.json" || files[1].Path != "README.md" || files[2].Path != "work/train.py" {
+		t.Fatalf("files = %+v", files)
+	}
+
+	var spec ociSpec
+	if err := json.Unmarshal(files[0].Content, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.OCIVersion != ociVersion || spec.Root.Path != "rootfs" || spec.Process.Cwd != "/work" ||
+		strings.Join(spec.Process.Args, " ") != "python3 train.py --epochs 10" || len(spec.Process.Env) != 3 {
+		t.Errorf("spec = %+v", spec)
+	}
+	r := spec.Linux.Resources
+	if r == nil || r.Memory.Limit != 512<<20 || r.CPU.Quota != 150000 || r.CPU.Period != cpuPeriod || r.CPU.Cpus != "0-3" {
+		t.Errorf("resources = %+v", r)
+	}
+
+	binds := map[string]ociMount{}
+	for _, m := range spec.Mounts {
+		if m.Type == "bind" {
+			binds[m.Destination] = m
+		}
+	}
+	for dest, source := range map[string]string{
+		"/bin":           "/bin",
+		"/usr/lib":       "/usr/lib",
+		"/usr/local/bin": "/opt/joblet/runtimes/python-3.11/1.0/isolated/usr/bin",
+		"/volumes/data":  "/opt/joblet/volumes/data/data",
+		"/work":          "work",
+	} {
+		if binds[dest].Source != source {
+			t.Errorf("mount of %s = %+v, want source %s", dest, binds[dest], source)
+		}
+	}
+	if !strings.Contains(strings.Join(binds["/bin"].Options, ","), "ro") || strings.Contains(strings.Join(binds["/work"].Options, ","), "ro") {
+		t.Errorf("read-only options: /bin %v, /work %v", binds["/bin"].Options, binds["/work"].Options)
+	}
+	if !strings.Contains(string(files[1].Content), "runc run joblet-job-f47ac10b") || len(notes) != 3 {
+		t.Errorf("README = %s, notes = %v", files[1].Content, notes)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if _, _, err := Export(testEnvironment(), "tarball"); err == nil {
+		t.Error("Export(tarball) succeeded")
+	}
+}
+
+func TestBaseImage(t *testing.T) {
+	for release, want := range map[string]string{
+		"ID=ubuntu VERSION_ID=22.04":  "ubuntu:22.04",
+		"ID=rocky VERSION_ID=9.3":     "rockylinux:9.3",
+		"ID=rhel VERSION_ID=9.3":      "registry.access.redhat.com/ubi9",
+		"ID=alpine VERSION_ID=3.19.1": "alpine:3.19",
+		"ID=arch":                     "archlinux:latest",
+		"ID=debian":                   "debian:stable-slim",
+		"ID=gentoo VERSION_ID=2.14":   "debian:stable-slim",
+		"":                            "debian:stable-slim",
+	} {
+		fields := map[string]string{}
+		for _, field := range strings.Fields(release) {
+			key, value, _ := strings.Cut(field, "=")
+			fields[key] = value
+		}
+		if got := BaseImage(fields); got != want {
+			t.Errorf("BaseImage(%s) = %s, want %s", release, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package envexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ociVersion is the runtime-spec version of the exported config.json
+const ociVersion = "1.0.2"
+
+// cpuPeriod is the CFS period CPU quotas are expressed in, in microseconds
+const cpuPeriod = 100000
+
+// The subset of the OCI runtime-spec an exported bundle uses
+type (
+	ociSpec struct {
+		OCIVersion string     `json:"ociVersion"`
+		Process    ociProcess `json:"process"`
+		Root       ociRoot    `json:"root"`
+		Hostname   string     `json:"hostname"`
+		Mounts     []ociMount `json:"mounts"`
+		Linux      ociLinux   `json:"linux"`
+	}
+	ociProcess struct {
+		Terminal bool     `json:"terminal"`
+		User     ociUser  `json:"user"`
+		Args     []string `json:"args"`
+		Env      []string `json:"env"`
+		Cwd      string   `json:"cwd"`
+	}
+	ociUser struct {
+		UID uint32 `json:"uid"`
+		GID uint32 `json:"gid"`
+	}
+	ociRoot struct {
+		Path     string `json:"path"`
+		Readonly bool   `json:"readonly"`
+	}
+	ociMount struct {
+		Destination string   `json:"destination"`
+		Type        string   `json:"type"`
+		Source      string   `json:"source"`
+		Options     []string `json:"options,omitempty"`
+	}
+	ociLinux struct {
+		Resources  *ociResources  `json:"resources,omitempty"`
+		Namespaces []ociNamespace `json:"namespaces"`
+	}
+	ociResources struct {
+		Memory *ociMemory `json:"memory,omitempty"`
+		CPU    *ociCPU    `json:"cpu,omitempty"`
+	}
+	ociMemory struct {
+		Limit int64 `json:"limit"`
+	}
+	ociCPU struct {
+		Quota  int64  `json:"quota,omitempty"`
+		Period uint64 `json:"period,omitempty"`
+		Cpus   string `json:"cpus,omitempty"`
+	}
+	ociNamespace struct {
+		Type string `json:"type"`
+	}
+)
+
+// OCIBundle renders the environment as an OCI runtime bundle to run with
+// runc on the node the job ran on: a config.json mounting what the job saw
+// into an empty rootfs, the uploads it still holds under work/ and a README
+func OCIBundle(env *Environment) ([]File, []string, error) {
+	files, notes := uploadFiles(env)
+	notes = append(notes, secretNote(env)...)
+	if env.Network != "none" {
+		notes = append(notes, "the bundle runs without network, join a network namespace in config.json for the job's network")
+	}
+
+	spec := ociSpec{
+		OCIVersion: ociVersion,
+		Process: ociProcess{
+			Args: append([]string{env.Command}, env.Args...),
+			Env:  env.Env,
+			Cwd:  env.WorkDir,
+		},
+		Root:     ociRoot{Path: "rootfs", Readonly: true},
+		Hostname: imageName(env),
+		Mounts:   ociMounts(env),
+		Linux: ociLinux{
+			Resources: ociLimits(env),
+			Namespaces: []ociNamespace{
+				{Type: "pid"}, {Type: "mount"}, {Type: "ipc"}, {Type: "uts"}, {Type: "network"},
+			},
+		},
+	}
+	config, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode config.json: %w", err)
+	}
+
+	readme := fmt.Sprintf(`# Environment of joblet job %s
+
+config.json mounts the host directories, runtime and volumes the job saw, from
+their paths on the node it ran on. Run it there as root, from this directory:
+
+    mkdir -p rootfs work
+    runc run %s
+`, env.JobUUID, imageName(env))
+	if len(notes) > 0 {
+		readme += "\nNot reproduced:\n\n"
+		for _, note := range notes {
+			readme += "- " + note + "\n"
+		}
+	}
+
+	return append([]File{
+		{Path: "config.json", Content: append(config, '\n'), Mode: 0644},
+		{Path: "README.md", Content: []byte(readme), Mode: 0644},
+	}, files...), notes, nil
+}
+
+// ociMounts are the kernel filesystems every container gets, then the host
+// directories, the runtime, the volumes and the work directory
+func ociMounts(env *Environment) []ociMount {
+	shm := []string{"nosuid", "noexec", "nodev", "mode=1777"}
+	if env.ShmSizeBytes > 0 {
+		shm = append(shm, fmt.Sprintf("size=%d", env.ShmSizeBytes))
+	}
+	tmp := []string{"nosuid", "nodev", "mode=1777"}
+	if env.TmpSizeBytes > 0 {
+		tmp = append(tmp, fmt.Sprintf("size=%d", env.TmpSizeBytes))
+	}
+	mounts := []ociMount{
+		{Destination: "/proc", Type: "proc", Source: "proc"},
+		{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: []string{"nosuid", "strictatime", "mode=755", "size=65536k"}},
+		{Destination: "/dev/pts", Type: "devpts", Source: "devpts", Options: []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620"}},
+		{Destination: "/dev/shm", Type: "tmpfs", Source: "shm", Options: shm},
+		{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "noexec", "nodev", "ro"}},
+		{Destination: "/tmp", Type: "tmpfs", Source: "tmpfs", Options: tmp},
+	}
+	for _, dir := range env.HostMounts {
+		mounts = append(mounts, bindMount(dir, dir, true))
+	}
+	for _, m := range env.RuntimeMounts {
+		mounts = append(mounts, bindMount(strings.TrimSuffix(env.RuntimeDir, "/")+dockerPath(m.Source), dockerPath(m.Target), m.ReadOnly))
+	}
+	for _, v := range env.Volumes {
+		mounts = append(mounts, bindMount(v.Source, v.Target, v.ReadOnly))
+	}
+	// runc resolves relative sources from the directory it runs in
+	return append(mounts, bindMount("work", env.WorkDir, false))
+}
+
+func bindMount(source, target string, readOnly bool) ociMount {
+	options := []string{"rbind", "nosuid", "nodev"}
+	if readOnly {
+		options = append(options, "ro")
+	}
+	return ociMount{Destination: target, Type: "bind", Source: source, Options: options}
+}
+
+// ociLimits are the job's memory and CPU limits, nil without any
+func ociLimits(env *Environment) *ociResources {
+	var r ociResources
+	if env.MemoryBytes > 0 {
+		r.Memory = &ociMemory{Limit: env.MemoryBytes}
+	}
+	if env.CPUPercent > 0 || env.CPUCores != "" {
+		r.CPU = &ociCPU{Cpus: env.CPUCores}
+		if env.CPUPercent > 0 {
+			r.CPU.Quota = int64(env.CPUPercent) * cpuPeriod / 100
+			r.CPU.Period = cpuPeriod
+		}
+	}
+	if r.Memory == nil && r.CPU == nil {
+		return nil
+	}
+	return &r
+}
@@ -0,0 +1,205 @@
+// Package gitsource fetches workflows and job specs from Git repositories, so
+// clients can run them without a checkout of their own ('rnx run --repo').
+//
+// A run fetches one ref with git into a short-lived checkout, reads the spec
+// and the files its jobs upload, and removes the checkout. Only repositories
+// the server configuration allows are fetched, with the deploy key configured
+// for them.
+package gitsource
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// DefaultRef is fetched when a repository is given without a ref
+const DefaultRef = "HEAD"
+
+// maxGitOutput caps how much git error output ends up in errors
+const maxGitOutput = 1024
+
+// ErrNotAllowed is returned for repositories no configured entry allows
+var ErrNotAllowed = errors.New("repository is not allowed by the server's git_sources")
+
+// Fetcher fetches refs of the allowed repositories
+type Fetcher struct {
+	cfg    config.GitSourcesConfig
+	logger *logger.Logger
+}
+
+// Checkout is a fetched ref; Close removes it
+type Checkout struct {
+	Dir    string
+	Commit string
+}
+
+// NewFetcher creates the fetcher, or returns nil when git sources are not
+// enabled
+func NewFetcher(cfg config.GitSourcesConfig, logger *logger.Logger) *Fetcher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Fetcher{cfg: cfg, logger: logger.WithField("component", "git-sources")}
+}
+
+// MaxBytes is the cap on the spec and files of a run, 0 for none
+func (f *Fetcher) MaxBytes() int64 {
+	return f.cfg.MaxSizeMB * 1024 * 1024
+}
+
+// Fetch checks out ref of the repository at url. The caller must Close the
+// checkout.
+func (f *Fetcher) Fetch(ctx context.Context, url, ref string) (*Checkout, error) {
+	if url == "" || strings.HasPrefix(url, "-") {
+		return nil, fmt.Errorf("invalid repository %q", url)
+	}
+	if ref == "" {
+		ref = DefaultRef
+	}
+	if strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " \t\n") {
+		return nil, fmt.Errorf("invalid ref %q", ref)
+	}
+	repo, ok := f.repository(url)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", url, ErrNotAllowed)
+	}
+
+	if err := os.MkdirAll(f.cfg.WorkDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create git work directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(f.cfg.WorkDir, "checkout-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout: %w", err)
+	}
+	checkout := &Checkout{Dir: dir}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, f.cfg.Timeout)
+	defer cancel()
+
+	env := gitEnv(repo)
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", "--no-tags", "--", url, ref},
+		{"-c", "advice.detachedHead=false", "checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if _, err := f.git(fetchCtx, dir, env, args...); err != nil {
+			checkout.Close()
+			if fetchCtx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("fetching %s@%s timed out after %s", url, ref, f.cfg.Timeout)
+			}
+			return nil, fmt.Errorf("failed to fetch %s@%s: %w", url, ref, err)
+		}
+	}
+
+	commit, err := f.git(fetchCtx, dir, env, "rev-parse", "HEAD")
+	if err != nil {
+		checkout.Close()
+		return nil, fmt.Errorf("failed to resolve %s@%s: %w", url, ref, err)
+	}
+	checkout.Commit = commit
+
+	f.logger.Info("ref fetched", "repository", url, "ref", ref, "commit", commit)
+	return checkout, nil
+}
+
+// Close removes the checkout
+func (c *Checkout) Close() error {
+	return os.RemoveAll(c.Dir)
+}
+
+// repository returns the allowed repository entry with the longest URL
+// prefix of url
+func (f *Fetcher) repository(url string) (config.GitRepositoryConfig, bool) {
+	var best config.GitRepositoryConfig
+	found := false
+	for _, repo := range f.cfg.Repositories {
+		if strings.HasPrefix(url, repo.URL) && (!found || len(repo.URL) > len(best.URL)) {
+			best, found = repo, true
+		}
+	}
+	return best, found
+}
+
+// git runs a git command in dir and returns its trimmed output
+func (f *Fetcher) git(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, f.cfg.Command, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, gitOutput(stderr.Bytes()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitEnv is the environment git runs with: never prompting for credentials,
+// and authenticating over SSH with the repository's deploy key
+func gitEnv(repo config.GitRepositoryConfig) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if repo.DeployKey == "" && repo.KnownHosts == "" {
+		return env
+	}
+
+	ssh := []string{"ssh", "-o", "BatchMode=yes"}
+	if repo.DeployKey != "" {
+		ssh = append(ssh, "-i", shellQuote(repo.DeployKey), "-o", "IdentitiesOnly=yes")
+	}
+	if repo.KnownHosts != "" {
+		ssh = append(ssh, "-o", "UserKnownHostsFile="+shellQuote(repo.KnownHosts), "-o", "StrictHostKeyChecking=yes")
+	}
+	return append(env, "GIT_SSH_COMMAND="+strings.Join(ssh, " "))
+}
+
+// shellQuote quotes s for the shell git runs GIT_SSH_COMMAND with
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// gitOutput trims git's error output for errors
+func gitOutput(output []byte) string {
+	text := strings.TrimSpace(string(output))
+	if len(text) > maxGitOutput {
+		text = text[:maxGitOutput] + "..."
+	}
+	if text == "" {
+		return "no output"
+	}
+	return text
+}
+
+// ParseRepoRef splits "URL@REF" into the repository URL and the ref, e.g.
+// "https://git.internal/team/app@main" or "git@git.internal:team/app@v1.2".
+// The user part of an SSH URL is not mistaken for a ref; without a ref,
+// DefaultRef is returned.
+func ParseRepoRef(s string) (string, string) {
+	// The ref can only follow the host, so look for it in the path
+	pathStart := 0
+	if i := strings.Index(s, "://"); i >= 0 {
+		if slash := strings.Index(s[i+3:], "/"); slash >= 0 {
+			pathStart = i + 3 + slash
+		} else {
+			pathStart = len(s)
+		}
+	} else if colon := strings.Index(s, ":"); colon >= 0 {
+		pathStart = colon
+	}
+	if at := strings.LastIndex(s[pathStart:], "@"); at >= 0 {
+		at += pathStart
+		if ref := s[at+1:]; ref != "" {
+			return s[:at], ref
+		}
+		return s[:at], DefaultRef
+	}
+	return s, DefaultRef
+}
@@ -0,0 +1,161 @@
+package gitsource
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseRepoRef(t *testing.T) {
+	tests := []struct {
+		in, url, ref string
+	}{
+		{"https://git.internal/team/app@main", "https://git.internal/team/app", "main"},
+		{"https://git.internal/team/app", "https://git.internal/team/app", DefaultRef},
+		{"https://ci@git.internal/team/app@v1.2", "https://ci@git.internal/team/app", "v1.2"},
+		{"git@git.internal:team/app@feature/x", "git@git.internal:team/app", "feature/x"},
+		{"git@git.internal:team/app", "git@git.internal:team/app", DefaultRef},
+		{"ssh://git@git.internal/team/app@3f2a9c1", "ssh://git@git.internal/team/app", "3f2a9c1"},
+	}
+	for _, tt := range tests {
+		url, ref := ParseRepoRef(tt.in)
+		if url != tt.url || ref != tt.ref {
+			t.Errorf("ParseRepoRef(%q) = %q, %q; want %q, %q", tt.in, url, ref, tt.url, tt.ref)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"jobs/pipeline.yaml": "jobs:\n  train:\n    command: python3\n    args: [train.py]\n    uploads:\n      files: [train.py, ../lib/util.py]\n",
+		"jobs/train.py":      "print('train')",
+		"lib/util.py":        "pass",
+		"jobs/single.yaml":   "name: train\ncommand: python3\nargs: [train.py]\nuploads:\n  files: [train.py]\n  directories: [configs]\n",
+		"jobs/configs/a.yml": "a: 1",
+		"jobs/secret.yaml":   "command: env\nsecret_environment:\n  TOKEN: ${TOKEN}\n",
+		"jobs/escape.yaml":   "jobs:\n  x:\n    command: cat\n    uploads:\n      files: [../../outside.txt]\n",
+	})
+
+	spec, err := LoadSpec(root, "jobs/pipeline.yaml", 0)
+	if err != nil {
+		t.Fatalf("LoadSpec(workflow) error = %v", err)
+	}
+	if spec.Kind != KindWorkflow || len(spec.Files) != 2 || spec.Files[0].Path != "train.py" || spec.Files[1].Path != "../lib/util.py" {
+		t.Errorf("LoadSpec(workflow) = %+v", spec)
+	}
+
+	spec, err = LoadSpec(root, "jobs/single.yaml", 0)
+	if err != nil {
+		t.Fatalf("LoadSpec(job) error = %v", err)
+	}
+	var workflow types.WorkflowYAML
+	if err := yaml.Unmarshal([]byte(spec.YamlContent), &workflow); err != nil {
+		t.Fatalf("converted job spec is not a workflow: %v", err)
+	}
+	job, ok := workflow.Jobs["train"]
+	if spec.Kind != KindJob || !ok || job.Command != "python3" || job.Uploads == nil ||
+		strings.Join(job.Uploads.Files, ",") != "train.py,configs/a.yml" || len(spec.Files) != 2 {
+		t.Errorf("LoadSpec(job) = %+v, workflow %+v", spec, workflow)
+	}
+
+	for _, tt := range []struct{ path, wantErr string }{
+		{"jobs/secret.yaml", "secret_environment"},
+		{"jobs/escape.yaml", "outside the repository"},
+		{"../etc/passwd", "outside the repository"},
+		{"jobs/missing.yaml", "not found"},
+	} {
+		if _, err := LoadSpec(root, tt.path, 0); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("LoadSpec(%s) error = %v, want %q", tt.path, err, tt.wantErr)
+		}
+	}
+
+	if _, err := LoadSpec(root, "jobs/pipeline.yaml", 10); err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("LoadSpec(over limit) error = %v", err)
+	}
+
+	if err := os.Symlink("/etc/hostname", filepath.Join(root, "jobs", "link.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSpec(root, "jobs/link.yaml", 0); err == nil || !strings.Contains(err.Error(), "outside the repository") {
+		t.Errorf("LoadSpec(symlink) error = %v", err)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{"jobs/hello.yaml": "command: echo\nargs: [hello]\n"})
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "hello"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, output)
+		}
+	}
+
+	fetcher := NewFetcher(config.GitSourcesConfig{
+		Enabled:      true,
+		Command:      "git",
+		WorkDir:      t.TempDir(),
+		Timeout:      time.Minute,
+		Repositories: []config.GitRepositoryConfig{{URL: "file://" + repo}},
+	}, logger.New())
+
+	checkout, err := fetcher.Fetch(context.Background(), "file://"+repo, "main")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(checkout.Commit) != 40 {
+		t.Errorf("commit = %q, want a full hash", checkout.Commit)
+	}
+	if _, err := os.Stat(filepath.Join(checkout.Dir, "jobs", "hello.yaml")); err != nil {
+		t.Errorf("checkout lacks the spec: %v", err)
+	}
+	if err := checkout.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(checkout.Dir); !os.IsNotExist(err) {
+		t.Errorf("checkout was not removed: %v", err)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), "https://github.com/other/repo", "main"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Fetch(unlisted) error = %v, want ErrNotAllowed", err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), "file://"+repo, "--upload-pack=touch"); err == nil {
+		t.Error("Fetch(option as ref) succeeded, want an error")
+	}
+	if _, err := fetcher.Fetch(context.Background(), "file://"+repo, "missing"); err == nil {
+		t.Error("Fetch(unknown ref) succeeded, want an error")
+	}
+}
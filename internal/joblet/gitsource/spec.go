@@ -0,0 +1,298 @@
+package gitsource
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec kinds
+const (
+	KindWorkflow = "workflow"
+	KindJob      = "job"
+)
+
+// Spec is a workflow read from a checkout, with the files its jobs upload.
+// A job spec is turned into a workflow of that one job.
+type Spec struct {
+	Kind        string
+	YamlContent string
+	Jobs        []string // Job names, sorted
+	Files       []*pb.FileUpload
+}
+
+// jobSpec is the part of the 'rnx job run -f' spec format a job run from a
+// repository supports. Options that depend on the client, such as
+// secret_environment expanded from the local environment, are rejected.
+type jobSpec struct {
+	Kind        string             `yaml:"kind,omitempty"`
+	Name        string             `yaml:"name,omitempty"`
+	Command     string             `yaml:"command"`
+	Args        []string           `yaml:"args,omitempty"`
+	Runtime     string             `yaml:"runtime,omitempty"`
+	Network     string             `yaml:"network,omitempty"`
+	Volumes     []string           `yaml:"volumes,omitempty"`
+	Environment map[string]string  `yaml:"environment,omitempty"`
+	Uploads     jobSpecUploads     `yaml:"uploads,omitempty"`
+	Resources   types.JobResources `yaml:"resources,omitempty"`
+	Labels      map[string]string  `yaml:"labels,omitempty"`
+}
+
+type jobSpecUploads struct {
+	Files       []string `yaml:"files,omitempty"`
+	Directories []string `yaml:"directories,omitempty"`
+}
+
+// LoadSpec reads the workflow or job spec at specPath, relative to the
+// checkout root, and the files its jobs upload, relative to the spec. Nothing
+// outside the checkout is read, and the spec and files together may not
+// exceed maxBytes (0 = no limit).
+func LoadSpec(root, specPath string, maxBytes int64) (*Spec, error) {
+	files := &fileReader{root: root, maxBytes: maxBytes, seen: make(map[string]bool)}
+	specFile := path.Clean(filepath.ToSlash(specPath))
+	data, err := files.read(specFile)
+	if err != nil {
+		return nil, err
+	}
+	specDir := path.Dir(specFile)
+
+	var top map[string]any
+	if err := yaml.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", specPath, err)
+	}
+
+	spec := &Spec{Kind: KindWorkflow, YamlContent: string(data)}
+	var wf types.WorkflowYAML
+	if _, isWorkflow := top["jobs"]; isWorkflow {
+		if err := yaml.Unmarshal(data, &wf); err != nil {
+			return nil, fmt.Errorf("invalid workflow %s: %w", specPath, err)
+		}
+	} else {
+		spec.Kind = KindJob
+		if wf, err = jobSpecWorkflow(data, specPath, files, specDir); err != nil {
+			return nil, err
+		}
+		content, err := yaml.Marshal(wf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert job spec %s: %w", specPath, err)
+		}
+		spec.YamlContent = string(content)
+	}
+
+	// Upload paths are sent relative to the spec, like 'rnx workflow run' does
+	jobNames := make([]string, 0, len(wf.Jobs))
+	for name := range wf.Jobs {
+		jobNames = append(jobNames, name)
+	}
+	sort.Strings(jobNames)
+	spec.Jobs = jobNames
+	sent := make(map[string]bool)
+	for _, name := range jobNames {
+		job := wf.Jobs[name]
+		if job.Uploads == nil {
+			continue
+		}
+		sources, err := workflow.UploadSources(job.Uploads, func(dir string) ([]string, error) {
+			return files.listRelative(specDir, dir)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("uploads of job %s: %w", name, err)
+		}
+		for _, file := range sources {
+			if sent[file] {
+				continue
+			}
+			if path.IsAbs(file) {
+				return nil, fmt.Errorf("file %s uploaded by job %s must be relative to the spec", file, name)
+			}
+			repoPath := path.Join(specDir, file)
+			content, err := files.read(repoPath)
+			if err != nil {
+				return nil, fmt.Errorf("file %s uploaded by job %s: %w", file, name, err)
+			}
+			spec.Files = append(spec.Files, &pb.FileUpload{Path: file, Content: content, Mode: files.mode(repoPath)})
+			sent[file] = true
+		}
+	}
+	return spec, nil
+}
+
+// jobSpecWorkflow turns a job spec into a workflow of that one job, named
+// after the spec or its file. Upload directories become the files in them.
+func jobSpecWorkflow(data []byte, specPath string, files *fileReader, specDir string) (types.WorkflowYAML, error) {
+	var spec jobSpec
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		return types.WorkflowYAML{}, fmt.Errorf("invalid job spec %s (jobs run from a repository support command, args, runtime, network, volumes, environment, uploads, resources and labels): %w", specPath, err)
+	}
+	if spec.Kind != "" && spec.Kind != "Job" {
+		return types.WorkflowYAML{}, fmt.Errorf("invalid job spec %s: kind must be Job, got %s", specPath, spec.Kind)
+	}
+	if spec.Command == "" {
+		return types.WorkflowYAML{}, fmt.Errorf("invalid job spec %s: command is required", specPath)
+	}
+
+	name := spec.Name
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(specPath), path.Ext(specPath))
+	}
+
+	uploads := append([]string(nil), spec.Uploads.Files...)
+	for _, dir := range spec.Uploads.Directories {
+		if path.IsAbs(dir) {
+			return types.WorkflowYAML{}, fmt.Errorf("directory %s uploaded by job %s must be relative to the spec", dir, name)
+		}
+		dirFiles, err := files.list(path.Join(specDir, dir))
+		if err != nil {
+			return types.WorkflowYAML{}, fmt.Errorf("directory %s uploaded by job %s: %w", dir, name, err)
+		}
+		for _, file := range dirFiles {
+			uploads = append(uploads, relativeTo(specDir, file))
+		}
+	}
+
+	job := types.JobSpec{
+		Command:     spec.Command,
+		Args:        spec.Args,
+		Runtime:     spec.Runtime,
+		Network:     spec.Network,
+		Volumes:     spec.Volumes,
+		Environment: spec.Environment,
+		Resources:   spec.Resources,
+		Labels:      spec.Labels,
+	}
+	if len(uploads) > 0 {
+		job.Uploads = &types.JobUploads{Files: uploads}
+	}
+	return types.WorkflowYAML{Name: name, Jobs: map[string]types.JobSpec{name: job}}, nil
+}
+
+// fileReader reads regular files of a checkout, refusing paths and symlinks
+// that lead out of it and keeping a running total against maxBytes
+type fileReader struct {
+	root     string
+	maxBytes int64
+	total    int64
+	seen     map[string]bool
+}
+
+// resolve returns the path of a checkout file on disk, following symlinks
+// only within the checkout
+func (r *fileReader) resolve(repoPath string) (string, error) {
+	repoPath = path.Clean(repoPath)
+	if path.IsAbs(repoPath) || repoPath == ".." || strings.HasPrefix(repoPath, "../") {
+		return "", fmt.Errorf("%s is outside the repository", repoPath)
+	}
+	root, err := filepath.EvalSymlinks(r.root)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(repoPath)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s not found in the repository", repoPath)
+		}
+		return "", err
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s links outside the repository", repoPath)
+	}
+	return resolved, nil
+}
+
+func (r *fileReader) read(repoPath string) ([]byte, error) {
+	resolved, err := r.resolve(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", repoPath)
+	}
+	if !r.seen[resolved] {
+		r.seen[resolved] = true
+		r.total += info.Size()
+		if r.maxBytes > 0 && r.total > r.maxBytes {
+			return nil, fmt.Errorf("the spec and its files exceed the server's limit of %d MB", r.maxBytes/(1024*1024))
+		}
+	}
+	return os.ReadFile(resolved)
+}
+
+// mode returns the permission bits of a checkout file, 0644 if unknown
+func (r *fileReader) mode(repoPath string) uint32 {
+	if resolved, err := r.resolve(repoPath); err == nil {
+		if info, err := os.Stat(resolved); err == nil {
+			return uint32(info.Mode().Perm())
+		}
+	}
+	return 0644
+}
+
+// listRelative returns the regular files under dir, relative to specDir, as
+// paths relative to specDir
+func (r *fileReader) listRelative(specDir, dir string) ([]string, error) {
+	files, err := r.list(path.Join(specDir, dir))
+	if err != nil {
+		return nil, err
+	}
+	for i, file := range files {
+		files[i] = relativeTo(specDir, file)
+	}
+	return files, nil
+}
+
+// relativeTo returns a checkout path relative to the spec's directory
+func relativeTo(specDir, file string) string {
+	if specDir == "." {
+		return file
+	}
+	return strings.TrimPrefix(file, specDir+"/")
+}
+
+// list returns the regular files under a checkout directory as checkout
+// paths, skipping the .git directory
+func (r *fileReader) list(repoDir string) ([]string, error) {
+	resolved, err := r.resolve(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	err = filepath.WalkDir(resolved, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(resolved, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, path.Join(path.Clean(repoDir), filepath.ToSlash(rel)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,189 @@
+// Package hooks runs the site scripts configured to run on the node around
+// each job's lifecycle, e.g. to register jobs in a CMDB without patching the
+// joblet. Hooks see the job's metadata, never its environment or secrets.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// Lifecycle points hooks run at, passed to them in JOBLET_HOOK
+const (
+	PreStart = "pre_start"
+	PostStop = "post_stop"
+)
+
+const (
+	// defaultTimeout is how long a hook without a timeout may run
+	defaultTimeout = 10 * time.Second
+	// maxOutput bounds the hook output kept for logs and job events
+	maxOutput = 1024
+	// hookPath is the PATH hooks run with
+	hookPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+// Runner runs the configured hooks. A nil Runner has no hooks.
+type Runner struct {
+	cfg    config.HooksConfig
+	nodeID string
+	// Process attributes sandboxing every hook
+	attr   *syscall.SysProcAttr
+	logger *logger.Logger
+}
+
+// New returns a Runner for the configured hooks, or nil when none is
+// configured. It fails when the account the hooks run as does not exist.
+func New(cfg config.HooksConfig, nodeID string) (*Runner, error) {
+	if len(cfg.PreStart) == 0 && len(cfg.PostStop) == 0 {
+		return nil, nil
+	}
+	attr, err := sandbox(cfg.User)
+	if err != nil {
+		return nil, err
+	}
+	r := &Runner{cfg: cfg, nodeID: nodeID, attr: attr, logger: logger.WithField("component", "job-hooks")}
+	r.logger.Info("job hooks enabled", "preStart", len(cfg.PreStart), "postStop", len(cfg.PostStop), "user", cfg.User)
+	return r, nil
+}
+
+// PreStart runs the pre_start hooks in order before the job's process is
+// launched. A failed hook is recorded in the job's timeline; when it is
+// required the remaining hooks are skipped and the error is returned so the
+// job fails. Safe on a nil Runner.
+func (r *Runner) PreStart(ctx context.Context, job *domain.Job) error {
+	if r == nil || len(r.cfg.PreStart) == 0 {
+		return nil
+	}
+	env := Environment(PreStart, job, r.nodeID)
+	for _, hook := range r.cfg.PreStart {
+		if err := r.run(ctx, hook, env); err != nil {
+			job.AddEvent(domain.JobEventHook, fmt.Sprintf("%s hook %s: %v", PreStart, hook.Name, err))
+			if hook.Required {
+				return fmt.Errorf("required hook %s: %w", hook.Name, err)
+			}
+			r.logger.Warn("pre_start hook failed, starting the job anyway", "hook", hook.Name, "jobId", job.Uuid, "error", err)
+		}
+	}
+	return nil
+}
+
+// PostStop runs the post_stop hooks in order in the background once the job
+// has ended. Failures are logged only: the job's outcome is settled. Safe on a
+// nil Runner.
+func (r *Runner) PostStop(job *domain.Job) {
+	if r == nil || len(r.cfg.PostStop) == 0 {
+		return
+	}
+	env := Environment(PostStop, job, r.nodeID)
+	go func() {
+		for _, hook := range r.cfg.PostStop {
+			if err := r.run(context.Background(), hook, env); err != nil {
+				r.logger.Warn("post_stop hook failed", "hook", hook.Name, "jobId", job.Uuid, "error", err)
+			}
+		}
+	}()
+}
+
+// run runs one hook with env, killing it and everything it started when it
+// outlives its timeout
+func (r *Runner) run(ctx context.Context, hook config.HookConfig, env []string) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, hook.Command, hook.Args...)
+	cmd.Env = env
+	cmd.Dir = "/"
+	attr := *r.attr
+	cmd.SysProcAttr = &attr
+	cmd.Cancel = func() error { return killTree(cmd) }
+	// Children holding the output open must not hold up the joblet
+	cmd.WaitDelay = time.Second
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	err := cmd.Run()
+	if hookCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("exited with code %d: %s", exitErr.ExitCode(), hookOutput(output.Bytes()))
+		}
+		return err
+	}
+	r.logger.Debug("hook completed", "hook", hook.Name, "duration", time.Since(start))
+	return nil
+}
+
+// Environment is what a hook gets to see at point: the job's metadata in
+// JOBLET_* variables and a minimal PATH. The exit code and end time are only
+// set once the job has ended.
+func Environment(point string, job *domain.Job, nodeID string) []string {
+	vars := map[string]string{
+		"JOBLET_HOOK":           point,
+		"JOBLET_NODE_ID":        nodeID,
+		"JOBLET_JOB_UUID":       job.Uuid,
+		"JOBLET_JOB_NAME":       job.Name,
+		"JOBLET_JOB_COMMAND":    job.Command,
+		"JOBLET_JOB_STATUS":     string(job.Status),
+		"JOBLET_JOB_TENANT":     job.Tenant,
+		"JOBLET_JOB_GROUP":      job.Group,
+		"JOBLET_JOB_RUNTIME":    job.Runtime,
+		"JOBLET_JOB_NETWORK":    job.Network,
+		"JOBLET_WORKFLOW_UUID":  job.WorkflowUuid,
+		"JOBLET_JOB_START_TIME": job.StartTime.UTC().Format(time.RFC3339),
+		"JOBLET_JOB_LABELS":     formatLabels(job.Labels),
+	}
+	if job.EndTime != nil {
+		vars["JOBLET_JOB_EXIT_CODE"] = strconv.Itoa(int(job.ExitCode))
+		vars["JOBLET_JOB_END_TIME"] = job.EndTime.UTC().Format(time.RFC3339)
+	}
+
+	env := []string{"PATH=" + hookPath, "HOME=/"}
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		env = append(env, key+"="+vars[key])
+	}
+	return env
+}
+
+// formatLabels joins labels as sorted key=value pairs separated by commas
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// hookOutput shortens a hook's output for logs and job events
+func hookOutput(output []byte) string {
+	text := strings.TrimSpace(string(output))
+	if len(text) > maxOutput {
+		text = text[:maxOutput] + "..."
+	}
+	if text == "" {
+		return "no output"
+	}
+	return text
+}
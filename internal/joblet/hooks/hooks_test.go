@@ -0,0 +1,133 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// writeHook writes an executable shell script for a hook
+func writeHook(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewWithoutHooks(t *testing.T) {
+	r, err := New(config.HooksConfig{User: "nobody"}, "node-1")
+	if err != nil || r != nil {
+		t.Fatalf("New() = %v, %v, want no runner", r, err)
+	}
+	// A nil runner runs nothing
+	if err := r.PreStart(context.Background(), &domain.Job{}); err != nil {
+		t.Errorf("PreStart() on nil runner = %v", err)
+	}
+	r.PostStop(&domain.Job{})
+}
+
+func TestPreStartPassesJobMetadata(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	hook := writeHook(t, "env > "+out)
+	r, err := New(config.HooksConfig{PreStart: []config.HookConfig{{Name: "cmdb", Command: hook}}}, "node-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := &domain.Job{
+		Uuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479", Command: "python3", Status: domain.StatusInitializing,
+		Tenant: "team-a", Labels: map[string]string{"team": "ml", "env": "prod"},
+		Environment:       map[string]string{"VISIBLE": "no"},
+		SecretEnvironment: map[string]string{"API_KEY": "hunter2"},
+	}
+	if err := r.PreStart(context.Background(), job); err != nil {
+		t.Fatalf("PreStart() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for _, want := range []string{
+		"JOBLET_HOOK=pre_start",
+		"JOBLET_NODE_ID=node-1",
+		"JOBLET_JOB_UUID=f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"JOBLET_JOB_COMMAND=python3",
+		"JOBLET_JOB_TENANT=team-a",
+		"JOBLET_JOB_LABELS=env=prod,team=ml",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("hook environment missing %s:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "VISIBLE") {
+		t.Errorf("hook saw the job's environment:\n%s", data)
+	}
+	if strings.Contains(string(data), "JOBLET_JOB_EXIT_CODE") {
+		t.Errorf("pre_start hook got an exit code:\n%s", data)
+	}
+}
+
+func TestPreStartFailures(t *testing.T) {
+	failing := writeHook(t, "echo cmdb unreachable >&2; exit 3")
+
+	t.Run("optional hook", func(t *testing.T) {
+		r, _ := New(config.HooksConfig{PreStart: []config.HookConfig{{Name: "cmdb", Command: failing}}}, "")
+		job := &domain.Job{}
+		if err := r.PreStart(context.Background(), job); err != nil {
+			t.Fatalf("PreStart() error = %v, want the job to start", err)
+		}
+		if len(job.Events) != 1 || job.Events[0].Type != domain.JobEventHook ||
+			!strings.Contains(job.Events[0].Message, "exited with code 3: cmdb unreachable") {
+			t.Errorf("events = %v", job.Events)
+		}
+	})
+
+	t.Run("required hook", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "ran")
+		r, _ := New(config.HooksConfig{PreStart: []config.HookConfig{
+			{Name: "cmdb", Command: failing, Required: true},
+			{Name: "next", Command: writeHook(t, "touch "+marker)},
+		}}, "")
+		if err := r.PreStart(context.Background(), &domain.Job{}); err == nil {
+			t.Fatal("PreStart() succeeded with a failing required hook")
+		}
+		if _, err := os.Stat(marker); err == nil {
+			t.Error("hooks after a failed required hook ran")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r, _ := New(config.HooksConfig{PreStart: []config.HookConfig{
+			{Name: "slow", Command: writeHook(t, "sleep 30 & wait"), Timeout: 200 * time.Millisecond, Required: true},
+		}}, "")
+		start := time.Now()
+		err := r.PreStart(context.Background(), &domain.Job{})
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Fatalf("PreStart() error = %v, want a timeout", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("hook was killed after %v", elapsed)
+		}
+	})
+}
+
+func TestEnvironmentAfterStop(t *testing.T) {
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	env := Environment(PostStop, &domain.Job{Status: domain.StatusFailed, ExitCode: 2, EndTime: &end}, "node-1")
+	for _, want := range []string{"JOBLET_HOOK=post_stop", "JOBLET_JOB_STATUS=FAILED", "JOBLET_JOB_EXIT_CODE=2", "JOBLET_JOB_END_TIME=2026-03-01T12:00:00Z"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("environment missing %s: %v", want, env)
+		}
+	}
+}
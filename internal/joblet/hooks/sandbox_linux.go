@@ -0,0 +1,41 @@
+//go:build linux
+
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// sandbox returns the process attributes hooks run with: their own process
+// group so a timeout kills everything they started, killed with the joblet,
+// and dropped to account when the joblet runs as root
+func sandbox(account string) (*syscall.SysProcAttr, error) {
+	attr := &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	if account == "" || account == "root" || os.Geteuid() != 0 {
+		return attr, nil
+	}
+	u, err := user.Lookup(account)
+	if err != nil {
+		return nil, fmt.Errorf("hook user %q: %w", account, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("hook user %q has invalid uid %q", account, u.Uid)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("hook user %q has invalid gid %q", account, u.Gid)
+	}
+	attr.Credential = &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: []uint32{}}
+	return attr, nil
+}
+
+// killTree kills the hook's process group
+func killTree(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package hooks
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// sandbox returns no process attributes: hooks only run on Linux nodes
+func sandbox(string) (*syscall.SysProcAttr, error) {
+	return &syscall.SysProcAttr{}, nil
+}
+
+// killTree kills the hook process
+func killTree(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,281 @@
+// Package identity authenticates clients that have no certificate of their
+// own, such as enterprise users with Kerberos or LDAP accounts, by a token
+// they send in the "authorization" request header.
+//
+// Joblet doesn't speak Kerberos or LDAP itself. A configured helper reads the
+// token on stdin, validates it (accepting a SPNEGO ticket with the service
+// keytab, binding to LDAP, ...) and prints the principal and its groups. The
+// identity mappings of the server configuration turn those into a role and a
+// tenant. Requests without the header keep using their client certificate.
+package identity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Header is the request metadata key carrying the token
+const Header = "authorization"
+
+// Token schemes, as sent before the token in the header
+const (
+	SchemeNegotiate = "negotiate" // Base64 SPNEGO/Kerberos ticket
+	SchemeBearer    = "bearer"    // Opaque token, e.g. one backed by an LDAP bind
+)
+
+// maxHelperOutput caps how much helper error output ends up in logs
+const maxHelperOutput = 1024
+
+// helperFunc runs the identity helper on a token and returns its output
+type helperFunc func(ctx context.Context, scheme, token string) ([]byte, error)
+
+// Provider validates tokens with the identity helper and maps principals to
+// roles and tenants
+type Provider struct {
+	cfg    config.IdentityConfig
+	helper helperFunc
+	now    func() time.Time
+	logger *logger.Logger
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]cachedIdentity
+}
+
+type cachedIdentity struct {
+	id      *auth.Identity
+	expires time.Time
+}
+
+// NewProvider creates the identity provider, or returns nil when it is not
+// enabled
+func NewProvider(cfg config.IdentityConfig, logger *logger.Logger) *Provider {
+	if !cfg.Enabled {
+		return nil
+	}
+	p := &Provider{
+		cfg:    cfg,
+		now:    time.Now,
+		logger: logger.WithField("component", "identity"),
+		cache:  make(map[[sha256.Size]byte]cachedIdentity),
+	}
+	p.helper = p.runHelper
+	return p
+}
+
+// Authenticate validates an authorization header value and returns the
+// caller it identifies. Unknown schemes and tokens the helper rejects are
+// Unauthenticated; principals no mapping matches are PermissionDenied.
+func (p *Provider) Authenticate(ctx context.Context, header string) (*auth.Identity, error) {
+	scheme, token, _ := strings.Cut(strings.TrimSpace(header), " ")
+	scheme = strings.ToLower(scheme)
+	token = strings.TrimSpace(token)
+	if (scheme != SchemeNegotiate && scheme != SchemeBearer) || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization header must be 'Negotiate <ticket>' or 'Bearer <token>'")
+	}
+
+	key := sha256.Sum256([]byte(scheme + " " + token))
+	now := p.now()
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.id, nil
+	}
+
+	output, err := p.helper(ctx, scheme, token)
+	if err != nil {
+		p.logger.Warn("token rejected", "scheme", scheme, "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "%s token rejected", scheme)
+	}
+	principal, groups, expires, err := parseHelperOutput(output)
+	if err != nil {
+		p.logger.Warn("invalid identity helper output", "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "%s token rejected", scheme)
+	}
+
+	id, mapped := p.mapIdentity(principal, groups)
+	if !mapped {
+		p.logger.Warn("principal is not mapped to a role", "principal", principal, "groups", groups)
+		return nil, status.Errorf(codes.PermissionDenied, "principal %s is not mapped to a role", principal)
+	}
+
+	if p.cfg.CacheTTL > 0 {
+		until := now.Add(p.cfg.CacheTTL)
+		if !expires.IsZero() && expires.Before(until) {
+			until = expires
+		}
+		p.store(key, cachedIdentity{id: id, expires: until}, now)
+	}
+	p.logger.Debug("token accepted", "principal", id.Principal, "role", id.Role, "tenant", id.Tenant)
+	return id, nil
+}
+
+// mapIdentity applies the first mapping matching the principal and its
+// groups
+func (p *Provider) mapIdentity(principal string, groups []string) (*auth.Identity, bool) {
+	for _, m := range p.cfg.Mappings {
+		if m.Principal != "" {
+			if matched, _ := path.Match(m.Principal, principal); !matched {
+				continue
+			}
+		}
+		if m.Group != "" && !containsFold(groups, m.Group) {
+			continue
+		}
+		return &auth.Identity{
+			Principal: principal,
+			Groups:    groups,
+			Role:      auth.ClientRole(m.Role),
+			Tenant:    m.Tenant,
+		}, true
+	}
+	return nil, false
+}
+
+// store caches an identity and drops the expired ones
+func (p *Provider) store(key [sha256.Size]byte, entry cachedIdentity, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for k, cached := range p.cache {
+		if !now.Before(cached.expires) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = entry
+}
+
+// runHelper runs the identity helper with the token on stdin, so it never
+// shows up in the process list
+func (p *Provider) runHelper(ctx context.Context, scheme, token string) ([]byte, error) {
+	helperCtx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(helperCtx, p.cfg.Command, p.cfg.Args...)
+	cmd.Env = append(os.Environ(), "JOBLET_AUTH_SCHEME="+scheme)
+	cmd.Stdin = strings.NewReader(token)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if helperCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("identity helper timed out after %s", p.cfg.Timeout)
+		}
+		return nil, fmt.Errorf("identity helper failed: %w: %s", err, helperOutput(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// helperOutputJSON is what the helper prints for a valid token
+type helperOutputJSON struct {
+	Principal string   `json:"principal"`
+	Groups    []string `json:"groups"`
+	Expires   string   `json:"expires"` // RFC3339, when the ticket or token expires (optional)
+}
+
+// parseHelperOutput parses the helper's output
+func parseHelperOutput(output []byte) (string, []string, time.Time, error) {
+	var parsed helperOutputJSON
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("identity helper printed invalid JSON: %w", err)
+	}
+	if parsed.Principal == "" {
+		return "", nil, time.Time{}, fmt.Errorf("identity helper output lacks a principal")
+	}
+	var expires time.Time
+	if parsed.Expires != "" {
+		var err error
+		if expires, err = time.Parse(time.RFC3339, parsed.Expires); err != nil {
+			return "", nil, time.Time{}, fmt.Errorf("identity helper printed invalid expires: %w", err)
+		}
+	}
+	return parsed.Principal, parsed.Groups, expires, nil
+}
+
+// authenticate adds the caller identified by the request's authorization
+// header to ctx. Requests without the header are left to their client
+// certificate.
+func (p *Provider) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(Header)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	id, err := p.Authenticate(ctx, values[0])
+	if err != nil {
+		return nil, err
+	}
+	return auth.WithIdentity(ctx, id), nil
+}
+
+// UnaryInterceptor authenticates token-carrying requests before the handler
+// and any later interceptor, such as the rate limiter, runs
+func (p *Provider) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := p.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates token-carrying streams when they open
+func (p *Provider) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := p.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// identityStream is a server stream whose context carries the caller
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// helperOutput trims the helper's error output for the log
+func helperOutput(output []byte) string {
+	text := strings.TrimSpace(string(output))
+	if len(text) > maxHelperOutput {
+		text = text[:maxHelperOutput] + "..."
+	}
+	if text == "" {
+		return "no output"
+	}
+	return text
+}
@@ -0,0 +1,163 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func testProvider(t *testing.T, helper helperFunc) *Provider {
+	t.Helper()
+	p := NewProvider(config.IdentityConfig{
+		Enabled:  true,
+		Command:  "/bin/true",
+		Timeout:  time.Second,
+		CacheTTL: time.Minute,
+		Mappings: []config.IdentityMapping{
+			{Principal: "svc-*@CORP.EXAMPLE", Role: "admin"},
+			{Group: "ML-Engineers", Role: "admin", Tenant: "ml"},
+			{Principal: "*@CORP.EXAMPLE", Role: "viewer"},
+		},
+	}, logger.New())
+	p.helper = helper
+	return p
+}
+
+func TestAuthenticate(t *testing.T) {
+	users := map[string]string{
+		"alice-ticket": `{"principal": "alice@CORP.EXAMPLE", "groups": ["ml-engineers"]}`,
+		"bob-token":    `{"principal": "bob@CORP.EXAMPLE"}`,
+		"ci-ticket":    `{"principal": "svc-ci@CORP.EXAMPLE", "groups": ["ml-engineers"]}`,
+		"eve-token":    `{"principal": "eve@PARTNER.EXAMPLE"}`,
+	}
+	p := testProvider(t, func(ctx context.Context, scheme, token string) ([]byte, error) {
+		if output, ok := users[token]; ok {
+			return []byte(output), nil
+		}
+		return nil, fmt.Errorf("unknown token")
+	})
+
+	tests := []struct {
+		header string
+		want   auth.Identity
+		code   codes.Code
+	}{
+		{"Negotiate alice-ticket", auth.Identity{Principal: "alice@CORP.EXAMPLE", Role: auth.AdminRole, Tenant: "ml"}, codes.OK},
+		{"bearer bob-token", auth.Identity{Principal: "bob@CORP.EXAMPLE", Role: auth.ViewerRole}, codes.OK},
+		{"Negotiate ci-ticket", auth.Identity{Principal: "svc-ci@CORP.EXAMPLE", Role: auth.AdminRole}, codes.OK},
+		{"Bearer eve-token", auth.Identity{}, codes.PermissionDenied},
+		{"Bearer forged", auth.Identity{}, codes.Unauthenticated},
+		{"Basic YWxpY2U6cHc=", auth.Identity{}, codes.Unauthenticated},
+		{"Negotiate", auth.Identity{}, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		id, err := p.Authenticate(context.Background(), tt.header)
+		if status.Code(err) != tt.code {
+			t.Errorf("Authenticate(%q) error = %v, want %v", tt.header, err, tt.code)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if id.Principal != tt.want.Principal || id.Role != tt.want.Role || id.Tenant != tt.want.Tenant {
+			t.Errorf("Authenticate(%q) = %+v, want %+v", tt.header, *id, tt.want)
+		}
+	}
+}
+
+func TestAuthenticateCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	p := testProvider(t, func(ctx context.Context, scheme, token string) ([]byte, error) {
+		calls++
+		return []byte(fmt.Sprintf(`{"principal": "alice@CORP.EXAMPLE", "expires": %q}`, now.Add(30*time.Second).Format(time.RFC3339))), nil
+	})
+	p.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.Authenticate(context.Background(), "Negotiate alice-ticket"); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("helper ran %d times, want 1 while cached", calls)
+	}
+
+	// The ticket expires before the cache TTL does
+	now = now.Add(31 * time.Second)
+	if _, err := p.Authenticate(context.Background(), "Negotiate alice-ticket"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("helper ran %d times, want 2 after the ticket expired", calls)
+	}
+}
+
+func TestRunHelper(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "helper.sh")
+	helper := `#!/bin/sh
+token=$(cat)
+if [ "$JOBLET_AUTH_SCHEME" = negotiate ] && [ "$token" = good ]; then
+  echo '{"principal": "alice@CORP.EXAMPLE"}'
+else
+  echo "bad ticket" >&2
+  exit 1
+fi
+`
+	if err := os.WriteFile(script, []byte(helper), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	p := NewProvider(config.IdentityConfig{
+		Enabled:  true,
+		Command:  script,
+		Timeout:  5 * time.Second,
+		Mappings: []config.IdentityMapping{{Principal: "*", Role: "viewer"}},
+	}, logger.New())
+
+	id, err := p.Authenticate(context.Background(), "Negotiate good")
+	if err != nil || id.Principal != "alice@CORP.EXAMPLE" {
+		t.Errorf("Authenticate(good) = %v, %v", id, err)
+	}
+	if _, err := p.helper(context.Background(), SchemeNegotiate, "bad"); err == nil || !strings.Contains(err.Error(), "bad ticket") {
+		t.Errorf("helper(bad) error = %v, want the helper's stderr", err)
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	p := testProvider(t, func(ctx context.Context, scheme, token string) ([]byte, error) {
+		return []byte(`{"principal": "bob@CORP.EXAMPLE"}`), nil
+	})
+	interceptor := p.UnaryInterceptor()
+
+	var seen *auth.Identity
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen = auth.IdentityFrom(ctx)
+		return nil, nil
+	}
+
+	// Without the header the request falls through to its certificate
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil || seen != nil {
+		t.Errorf("interceptor without header: identity = %v, error = %v", seen, err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "Bearer bob-token"))
+	if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("interceptor error = %v", err)
+	}
+	if seen == nil || seen.Principal != "bob@CORP.EXAMPLE" || seen.Role != auth.ViewerRole {
+		t.Errorf("handler saw identity %+v", seen)
+	}
+}
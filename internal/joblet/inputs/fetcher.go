@@ -0,0 +1,279 @@
+package inputs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/objectstore"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// tempPrefix names downloads in progress in the cache directory
+const tempPrefix = ".fetch-"
+
+// Stats summarizes the inputs of one job
+type Stats struct {
+	Objects         int
+	CacheHits       int
+	DownloadedBytes int64
+}
+
+// Fetcher downloads job inputs. Objects with an ETag are kept in the cache
+// directory under a name derived from their URL and ETag; every job still
+// checks the object with its own credentials before a cached copy is used,
+// so the cache never hands an object to a job that could not read it.
+type Fetcher struct {
+	config config.InputsConfig
+	client *http.Client
+	logger *logger.Logger
+	now    func() time.Time
+
+	mutex sync.Mutex // Serializes cache eviction
+}
+
+// NewFetcher returns nil when inputs are disabled
+func NewFetcher(cfg config.InputsConfig, log *logger.Logger) *Fetcher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Fetcher{
+		config: cfg,
+		client: &http.Client{},
+		logger: log.WithField("component", "inputs"),
+		now:    time.Now,
+	}
+}
+
+// Fetch downloads the inputs of job into workDir, its workspace on the host.
+// Safe on a nil Fetcher for jobs without inputs.
+func (f *Fetcher) Fetch(ctx context.Context, job *domain.Job, workDir string) (Stats, error) {
+	var stats Stats
+	if len(job.Inputs) == 0 {
+		return stats, nil
+	}
+	if f == nil {
+		return stats, fmt.Errorf("job inputs are disabled on this node (inputs.enabled)")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.config.Timeout)
+	defer cancel()
+	for _, raw := range job.Inputs {
+		in, err := Parse(raw)
+		if err != nil {
+			return stats, err
+		}
+		st, err := objectstore.JobStore(in.Scheme, f.config.S3, f.config.GCS, job.SecretEnvironment)
+		if err != nil {
+			return stats, err
+		}
+		hit, n, err := f.fetch(ctx, in, st, filepath.Join(workDir, filepath.FromSlash(in.Target)))
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return stats, fmt.Errorf("fetching inputs took longer than %s", f.config.Timeout)
+			}
+			return stats, err
+		}
+		stats.Objects++
+		stats.DownloadedBytes += n
+		if hit {
+			stats.CacheHits++
+		}
+	}
+	f.logger.Info("job inputs fetched", "jobID", job.Uuid, "objects", stats.Objects,
+		"cacheHits", stats.CacheHits, "downloadedBytes", stats.DownloadedBytes)
+	return stats, nil
+}
+
+// fetch places one input at dst, from the cache when it holds the object's
+// current ETag. It returns whether the cache was hit and the bytes downloaded.
+func (f *Fetcher) fetch(ctx context.Context, in Input, st objectstore.Store, dst string) (bool, int64, error) {
+	etag, size, err := f.head(ctx, in, st)
+	if err != nil {
+		return false, 0, err
+	}
+	if size > f.config.MaxObjectBytes {
+		return false, 0, fmt.Errorf("input %s is %d bytes, over the node's limit of %d", in.Source(), size, f.config.MaxObjectBytes)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return false, 0, fmt.Errorf("failed to create directory for input %s: %w", in.Target, err)
+	}
+
+	// Objects without an ETag cannot be told apart from a newer version
+	if etag == "" || f.config.MaxCacheBytes == 0 {
+		n, err := f.download(ctx, in, st, "", dst)
+		return false, n, err
+	}
+
+	// A cached copy evicted meanwhile is downloaded again
+	cached := f.cachePath(in, etag)
+	if err := copyFile(cached, dst); err == nil {
+		now := f.now()
+		_ = os.Chtimes(cached, now, now)
+		return true, 0, nil
+	} else if !os.IsNotExist(err) {
+		return false, 0, fmt.Errorf("failed to copy cached input %s: %w", in.Source(), err)
+	}
+	if err := os.MkdirAll(f.config.CacheDir, 0700); err != nil {
+		return false, 0, fmt.Errorf("failed to create input cache: %w", err)
+	}
+	n, err := f.download(ctx, in, st, etag, cached)
+	if err != nil {
+		return false, 0, err
+	}
+	// Jobs write to their copy, never to the cache
+	if err := copyFile(cached, dst); err != nil {
+		return false, n, err
+	}
+	f.evict()
+	return false, n, nil
+}
+
+// cachePath names the cached copy of one version of an object
+func (f *Fetcher) cachePath(in Input, etag string) string {
+	sum := sha256.Sum256([]byte(in.Source() + "\n" + etag))
+	return filepath.Join(f.config.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// request builds a request for the object of in, signed by the caller
+func (f *Fetcher) request(ctx context.Context, method string, in Input, st objectstore.Store) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, st.URL(in.Bucket, in.Key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input %s: %w", in.Source(), err)
+	}
+	return req, nil
+}
+
+// head returns the ETag and size of the object, checking that the
+// credentials can read it
+func (f *Fetcher) head(ctx context.Context, in Input, st objectstore.Store) (string, int64, error) {
+	req, err := f.request(ctx, http.MethodHead, in, st)
+	if err != nil {
+		return "", 0, err
+	}
+	objectstore.Sign(req, nil, st.Creds, st.Region, f.now())
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to fetch input %s: %w", in.Source(), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", 0, fmt.Errorf("failed to fetch input %s: %s", in.Source(), resp.Status)
+	}
+	return resp.Header.Get("ETag"), resp.ContentLength, nil
+}
+
+// download writes the object to dst through a temporary file in the same
+// directory. With an ETag the download fails if the object changed since
+// head.
+func (f *Fetcher) download(ctx context.Context, in Input, st objectstore.Store, etag, dst string) (int64, error) {
+	req, err := f.request(ctx, http.MethodGet, in, st)
+	if err != nil {
+		return 0, err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+	objectstore.Sign(req, nil, st.Creds, st.Region, f.now())
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch input %s: %w", in.Source(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("failed to fetch input %s: %s: %s", in.Source(), resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), tempPrefix+"*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to store input %s: %w", in.Source(), err)
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, io.LimitReader(resp.Body, f.config.MaxObjectBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, fmt.Errorf("failed to fetch input %s: %w", in.Source(), err)
+	}
+	if n > f.config.MaxObjectBytes {
+		return n, fmt.Errorf("input %s is over the node's limit of %d bytes", in.Source(), f.config.MaxObjectBytes)
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return n, fmt.Errorf("failed to fetch input %s: got %d of %d bytes", in.Source(), n, resp.ContentLength)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return n, err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return n, fmt.Errorf("failed to store input %s: %w", in.Source(), err)
+	}
+	return n, nil
+}
+
+// evict removes the least recently used objects until the cache fits
+// max_cache_bytes
+func (f *Fetcher) evict() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entries, err := os.ReadDir(f.config.CacheDir)
+	if err != nil {
+		f.logger.Warn("failed to read input cache", "error", err)
+		return
+	}
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), tempPrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime().Before(files[j].ModTime()) })
+	for _, info := range files {
+		if total <= f.config.MaxCacheBytes {
+			return
+		}
+		if err := os.Remove(filepath.Join(f.config.CacheDir, info.Name())); err != nil {
+			f.logger.Warn("failed to evict cached input", "file", info.Name(), "error", err)
+			continue
+		}
+		total -= info.Size()
+	}
+}
+
+// copyFile copies src to dst, replacing dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,204 @@
+package inputs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestParse(t *testing.T) {
+	valid := map[string]Input{
+		"s3://data/sets/train.csv":                  {Scheme: SchemeS3, Bucket: "data", Key: "sets/train.csv", Target: "train.csv"},
+		"s3://data/sets/train.csv:/work/in/a.csv":   {Scheme: SchemeS3, Bucket: "data", Key: "sets/train.csv", Target: "in/a.csv"},
+		"gs://data/model.bin:/work/models/":         {Scheme: SchemeGCS, Bucket: "data", Key: "model.bin", Target: "models/model.bin"},
+		"s3://data/a:b.txt":                         {Scheme: SchemeS3, Bucket: "data", Key: "a:b.txt", Target: "a:b.txt"},
+		"s3://data/x.bin:/work/./nested/../x.bin":   {Scheme: SchemeS3, Bucket: "data", Key: "x.bin", Target: "x.bin"},
+		"s3://my.bucket-1/2024/01/part-0.parquet":   {Scheme: SchemeS3, Bucket: "my.bucket-1", Key: "2024/01/part-0.parquet", Target: "part-0.parquet"},
+		"s3://data/x.bin:/work/very/deep/file.data": {Scheme: SchemeS3, Bucket: "data", Key: "x.bin", Target: "very/deep/file.data"},
+	}
+	for raw, want := range valid {
+		got, err := Parse(raw)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{
+		"https://data/x.bin",
+		"s3://data",
+		"s3://data/prefix/",
+		"s3://Data/x.bin",
+		"s3://data/x.bin:/etc/passwd",
+		"s3://data/x.bin:/work/../etc/passwd",
+		"s3://data/x.bin:/work",
+	} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", raw)
+		}
+	}
+}
+
+func TestInput_String(t *testing.T) {
+	in, _ := Parse("s3://data/sets/train.csv:/work/in/")
+	if got := in.String(); got != "s3://data/sets/train.csv:/work/in/train.csv" {
+		t.Errorf("String() = %q", got)
+	}
+	if again, err := Parse(in.String()); err != nil || again != in {
+		t.Errorf("Parse(String()) = %+v, %v", again, err)
+	}
+}
+
+// fakeStore serves objects and counts the GETs
+type fakeStore struct {
+	mu      sync.Mutex
+	objects map[string]string // Path -> content
+	etag    string
+	gets    int
+	auth    []string
+}
+
+func (f *fakeStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	body, ok := f.objects[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if f.etag != "" {
+		w.Header().Set("ETag", f.etag)
+	}
+	if r.Method == http.MethodGet {
+		f.gets++
+	}
+	_, _ = w.Write([]byte(body))
+}
+
+func newTestFetcher(t *testing.T, endpoint string) *Fetcher {
+	cfg := config.DefaultConfig.Inputs
+	cfg.Enabled = true
+	cfg.CacheDir = filepath.Join(t.TempDir(), "cache")
+	cfg.S3 = config.S3SinkConfig{Region: "eu-west-1", Endpoint: endpoint, AccessKeyID: "NODE", SecretAccessKey: "secret"}
+	cfg.GCS = config.S3SinkConfig{Endpoint: endpoint, AccessKeyID: "GOOG", SecretAccessKey: "secret"}
+	return NewFetcher(cfg, logger.New())
+}
+
+func TestFetcher_CachesByETag(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{"/data/sets/train.csv": "a,b\n1,2\n"}, etag: `"v1"`}
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	f := newTestFetcher(t, srv.URL)
+
+	job := &domain.Job{Uuid: "job-1", Inputs: []string{"s3://data/sets/train.csv:/work/in/train.csv"}}
+	work := t.TempDir()
+	stats, err := f.Fetch(context.Background(), job, work)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if stats.Objects != 1 || stats.CacheHits != 0 || stats.DownloadedBytes != 8 {
+		t.Errorf("first fetch stats = %+v", stats)
+	}
+	if got, _ := os.ReadFile(filepath.Join(work, "in", "train.csv")); string(got) != "a,b\n1,2\n" {
+		t.Errorf("input content = %q", got)
+	}
+
+	// Same ETag: served from the cache, still checked against the store
+	stats, err = f.Fetch(context.Background(), job, t.TempDir())
+	if err != nil || stats.CacheHits != 1 || store.gets != 1 {
+		t.Errorf("second fetch stats = %+v, err = %v, gets = %d", stats, err, store.gets)
+	}
+
+	// New ETag: downloaded again
+	store.etag = `"v2"`
+	if stats, err = f.Fetch(context.Background(), job, t.TempDir()); err != nil || stats.CacheHits != 0 || store.gets != 2 {
+		t.Errorf("fetch after change stats = %+v, err = %v, gets = %d", stats, err, store.gets)
+	}
+}
+
+func TestFetcher_Credentials(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{"/data/x.bin": "x"}}
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	f := newTestFetcher(t, srv.URL)
+
+	// Delegated keys in the job's secret environment win over the node's
+	job := &domain.Job{
+		Uuid:              "job-1",
+		Inputs:            []string{"s3://data/x.bin", "gs://data/x.bin:/work/gcs.bin"},
+		SecretEnvironment: map[string]string{"AWS_ACCESS_KEY_ID": "TENANT", "AWS_SECRET_ACCESS_KEY": "s"},
+	}
+	if _, err := f.Fetch(context.Background(), job, t.TempDir()); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	for i, want := range []string{"Credential=TENANT/", "Credential=TENANT/", "Credential=GOOG/", "Credential=GOOG/"} {
+		if i >= len(store.auth) || !strings.Contains(store.auth[i], want) {
+			t.Errorf("request %d Authorization = %q, want %s", i, store.auth, want)
+		}
+	}
+	if !strings.Contains(store.auth[2], "/auto/s3/aws4_request") {
+		t.Errorf("GCS request not signed for region auto: %q", store.auth[2])
+	}
+}
+
+func TestFetcher_Errors(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{"/data/big.bin": strings.Repeat("x", 64)}}
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	f := newTestFetcher(t, srv.URL)
+	f.config.MaxObjectBytes = 32
+
+	tests := map[string]string{
+		"s3://data/missing.bin": "404",
+		"s3://data/big.bin":     "over the node's limit",
+	}
+	for input, want := range tests {
+		job := &domain.Job{Uuid: "job-1", Inputs: []string{input}}
+		if _, err := f.Fetch(context.Background(), job, t.TempDir()); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Fetch(%s) error = %v, want %q", input, err, want)
+		}
+	}
+
+	var disabled *Fetcher
+	job := &domain.Job{Uuid: "job-1", Inputs: []string{"s3://data/x.bin"}}
+	if _, err := disabled.Fetch(context.Background(), job, t.TempDir()); err == nil {
+		t.Error("nil Fetcher accepted a job with inputs")
+	}
+	if _, err := disabled.Fetch(context.Background(), &domain.Job{}, t.TempDir()); err != nil {
+		t.Errorf("nil Fetcher rejected a job without inputs: %v", err)
+	}
+}
+
+func TestFetcher_Evict(t *testing.T) {
+	f := newTestFetcher(t, "")
+	f.config.MaxCacheBytes = 10
+	if err := os.MkdirAll(f.config.CacheDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, name := range []string{"old", "mid", "new"} {
+		file := filepath.Join(f.config.CacheDir, name)
+		if err := os.WriteFile(file, []byte("12345"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		at := now.Add(time.Duration(i) * time.Minute)
+		_ = os.Chtimes(file, at, at)
+	}
+
+	f.evict()
+	for name, kept := range map[string]bool{"old": false, "mid": true, "new": true} {
+		if _, err := os.Stat(filepath.Join(f.config.CacheDir, name)); (err == nil) != kept {
+			t.Errorf("%s kept = %v, want %v", name, err == nil, kept)
+		}
+	}
+}
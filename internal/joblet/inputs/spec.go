@@ -0,0 +1,79 @@
+// Package inputs downloads the objects a job names in S3 or Google Cloud
+// Storage into its workspace before it starts, so that users don't have to
+// download datasets to their machine and upload them again with the job.
+package inputs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/objectstore"
+)
+
+// Object store schemes an input may name
+const (
+	SchemeS3  = objectstore.SchemeS3
+	SchemeGCS = objectstore.SchemeGCS
+)
+
+// workMount is where the job sees its workspace
+const workMount = "/work"
+
+// Input is an object to download and where the job finds it
+type Input struct {
+	Scheme string
+	Bucket string
+	Key    string
+	Target string // Path relative to the job's workspace
+}
+
+// Source returns the URL of the object
+func (in Input) Source() string {
+	return in.Scheme + "://" + in.Bucket + "/" + in.Key
+}
+
+// String returns the canonical form of the input, as Parse accepts it
+func (in Input) String() string {
+	return in.Source() + ":" + path.Join(workMount, in.Target)
+}
+
+// Parse reads an input in the form s3://bucket/key[:/work/path] or
+// gs://bucket/key[:/work/path]. Without a path, or with a path ending in a
+// slash, the object keeps its base name. Paths must stay under /work.
+func Parse(raw string) (Input, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok || (scheme != SchemeS3 && scheme != SchemeGCS) {
+		return Input{}, fmt.Errorf("invalid input %q: must start with s3:// or gs://", raw)
+	}
+
+	target := ""
+	if i := strings.LastIndex(rest, ":/"); i >= 0 {
+		rest, target = rest[:i], rest[i+1:]
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if !objectstore.ValidBucket(bucket) {
+		return Input{}, fmt.Errorf("invalid input %q: bad bucket name %q", raw, bucket)
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return Input{}, fmt.Errorf("invalid input %q: must name an object, not a bucket or prefix", raw)
+	}
+
+	if target == "" || strings.HasSuffix(target, "/") {
+		target += path.Base(key)
+	}
+	if !strings.HasPrefix(target, "/") {
+		target = path.Join(workMount, target)
+	}
+	cleaned := path.Clean(target)
+	if !strings.HasPrefix(cleaned, workMount+"/") {
+		return Input{}, fmt.Errorf("invalid input %q: target %s must be under %s", raw, target, workMount)
+	}
+
+	return Input{
+		Scheme: scheme,
+		Bucket: bucket,
+		Key:    key,
+		Target: strings.TrimPrefix(cleaned, workMount+"/"),
+	}, nil
+}
@@ -0,0 +1,31 @@
+// Package introspection registers the standard gRPC health (grpc.health.v1)
+// and server reflection services, so grpcurl, load balancers and Kubernetes
+// probes can query the joblet and persist servers without a custom client.
+package introspection
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Register adds the enabled services to srv. Call it once every other service
+// is registered: each of them, and the server as a whole (service ""), is
+// reported SERVING. The health server is returned so the caller can report
+// NOT_SERVING with Shutdown before stopping; it is nil with health off.
+func Register(srv *grpc.Server, withHealth, withReflection bool) *health.Server {
+	if withReflection {
+		reflection.Register(srv)
+	}
+	var hs *health.Server
+	if withHealth {
+		hs = health.NewServer()
+		for name := range srv.GetServiceInfo() {
+			hs.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+		}
+		hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(srv, hs)
+	}
+	return hs
+}
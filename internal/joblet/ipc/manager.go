@@ -24,6 +24,8 @@ type ManagerConfig struct {
 	BufferSize     int
 	ReconnectDelay time.Duration
 	MaxReconnects  int
+	BatchSize      int
+	FlushInterval  time.Duration
 }
 
 // NewManager creates a new IPC manager with both log and metrics subscribers
@@ -44,6 +46,8 @@ func NewManager(
 		BufferSize:     cfg.BufferSize,
 		ReconnectDelay: cfg.ReconnectDelay,
 		MaxReconnects:  cfg.MaxReconnects,
+		BatchSize:      cfg.BatchSize,
+		FlushInterval:  cfg.FlushInterval,
 	}
 
 	writer := NewWriter(writerCfg, log)
@@ -84,6 +88,11 @@ func (m *Manager) Start() error {
 	return nil
 }
 
+// Writer returns the IPC writer, nil when IPC is disabled
+func (m *Manager) Writer() *Writer {
+	return m.writer
+}
+
 // Stop stops the IPC manager and all subscribers
 func (m *Manager) Stop() error {
 	if m.writer == nil {
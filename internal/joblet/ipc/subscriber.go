@@ -99,7 +99,7 @@ func (s *Subscriber) processEvents(updates <-chan pubsub.Message[adapters.JobEve
 				timestamp = event.Timestamp * 1000000000 // Convert seconds to nanos
 			}
 
-			if err := s.writer.WriteLog(jobID, streamType, timestamp, seq, event.LogChunk); err != nil {
+			if err := s.writer.WriteLog(jobID, event.Tenant, streamType, timestamp, seq, event.LogChunk); err != nil {
 				s.errors.Add(1)
 				s.logger.Warn("Failed to write log to IPC",
 					"jobID", jobID,
@@ -26,13 +26,23 @@ type Writer struct {
 	writeChan  chan *ipcpb.IPCMessage
 	bufferSize int
 
+	// Batching - queued messages are coalesced into a single socket write
+	batchSize     int
+	flushInterval time.Duration
+
 	// Reconnection
-	reconnect *reconnectManager
+	reconnect     *reconnectManager
+	reconnectNow  chan struct{} // Asks reconnectLoop to retry without waiting for its tick
+	reconnected   chan struct{} // Signaled by connect for batches waiting to be resent
+	everConnected atomic.Bool   // A persist process has been reached; hold messages while it is replaced
+	gaveUp        atomic.Bool   // Max reconnects reached, messages are dropped
+	droppedMark   atomic.Uint64 // msgsDropped when the last drop was reported
 
 	// Metrics
 	msgsSent    atomic.Uint64
 	msgsDropped atomic.Uint64
 	writeErrors atomic.Uint64
+	batchesSent atomic.Uint64
 
 	// Lifecycle
 	ctx    context.Context
@@ -46,21 +56,32 @@ type Config struct {
 	Socket         string
 	BufferSize     int
 	ReconnectDelay time.Duration
-	MaxReconnects  int // 0 = infinite
+	MaxReconnects  int           // 0 = infinite
+	BatchSize      int           // Max messages per socket write (<= 1 disables batching)
+	FlushInterval  time.Duration // Max time a partial batch waits for more messages
 }
 
 // NewWriter creates a new IPC writer
 func NewWriter(cfg *Config, log *logger.Logger) *Writer {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	batchSize := cfg.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
 	w := &Writer{
-		socket:     cfg.Socket,
-		writeChan:  make(chan *ipcpb.IPCMessage, cfg.BufferSize),
-		bufferSize: cfg.BufferSize,
-		reconnect:  newReconnectManager(cfg.ReconnectDelay, cfg.MaxReconnects),
-		ctx:        ctx,
-		cancel:     cancel,
-		logger:     log.WithField("component", "ipc-writer"),
+		socket:        cfg.Socket,
+		writeChan:     make(chan *ipcpb.IPCMessage, cfg.BufferSize),
+		bufferSize:    cfg.BufferSize,
+		batchSize:     batchSize,
+		flushInterval: cfg.FlushInterval,
+		reconnect:     newReconnectManager(cfg.ReconnectDelay, cfg.MaxReconnects),
+		reconnectNow:  make(chan struct{}, 1),
+		reconnected:   make(chan struct{}, 1),
+		ctx:           ctx,
+		cancel:        cancel,
+		logger:        log.WithField("component", "ipc-writer"),
 	}
 
 	// Start background workers
@@ -71,8 +92,8 @@ func NewWriter(cfg *Config, log *logger.Logger) *Writer {
 	return w
 }
 
-// WriteLog sends a log line (non-blocking)
-func (w *Writer) WriteLog(jobID string, stream ipcpb.StreamType, timestamp int64, sequence uint64, content []byte) error {
+// WriteLog sends a log line of a job owned by tenant (empty for none) (non-blocking)
+func (w *Writer) WriteLog(jobID, tenant string, stream ipcpb.StreamType, timestamp int64, sequence uint64, content []byte) error {
 	// Create log line
 	logLine := &ipcpb.LogLine{
 		JobId:     jobID,
@@ -80,6 +101,7 @@ func (w *Writer) WriteLog(jobID string, stream ipcpb.StreamType, timestamp int64
 		Timestamp: timestamp,
 		Sequence:  sequence,
 		Content:   content,
+		Tenant:    tenant,
 	}
 
 	// Marshal log line
@@ -130,9 +152,11 @@ func (w *Writer) WriteMetric(jobID string, timestamp int64, sequence uint64, dat
 	return w.write(msg)
 }
 
-// write sends a message (non-blocking)
+// write sends a message (non-blocking). While a persist process that was
+// reached goes away (restart, or a standby taking over) messages are queued
+// up to the buffer size and sent once the writer reconnected.
 func (w *Writer) write(msg *ipcpb.IPCMessage) error {
-	if !w.connected.Load() {
+	if (!w.connected.Load() && !w.everConnected.Load()) || w.gaveUp.Load() {
 		w.msgsDropped.Add(1)
 		return fmt.Errorf("not connected to persist service")
 	}
@@ -148,59 +172,140 @@ func (w *Writer) write(msg *ipcpb.IPCMessage) error {
 	}
 }
 
-// writeLoop processes the write queue
+// writeLoop processes the write queue.
+// Messages are flushed in batches: the first queued message opens a batch, which
+// is sent once it reaches batchSize messages or flushInterval has elapsed. Each
+// batch goes out as a single socket write of length-prefixed frames, so the wire
+// format seen by persist is unchanged.
 func (w *Writer) writeLoop() {
 	defer w.wg.Done()
 
-	lengthBuf := make([]byte, 4)
+	batch := make([]*ipcpb.IPCMessage, 0, w.batchSize)
+	var frames []byte
 
 	for {
 		select {
 		case <-w.ctx.Done():
 			return
 		case msg := <-w.writeChan:
-			if err := w.sendMessage(msg, lengthBuf); err != nil {
-				w.writeErrors.Add(1)
-				w.logger.Error("Failed to send IPC message", "error", err, "jobID", msg.JobId)
-
-				// Mark as disconnected on write error
-				w.connected.Store(false)
-				w.closeConnection()
-			} else {
-				w.msgsSent.Add(1)
+			batch = w.fillBatch(append(batch[:0], msg))
+			frames = w.deliver(batch, frames)
+
+			// Drop references so sent messages can be collected
+			clear(batch)
+		}
+	}
+}
+
+// deliver sends a batch, waiting out reconnects: a batch whose write failed
+// is sent again once the writer reconnected, to the restarted persist process
+// or the standby that took over its socket. Persist may then receive part of
+// the batch twice. The batch is only dropped when the writer stops or gives
+// up reconnecting. Returns frames for reuse by the next batch.
+func (w *Writer) deliver(batch []*ipcpb.IPCMessage, frames []byte) []byte {
+	for {
+		if w.connected.Load() {
+			var err error
+			frames, err = w.sendBatch(batch, frames[:0])
+			if err == nil {
+				w.msgsSent.Add(uint64(len(batch)))
+				w.batchesSent.Add(1)
+				return frames
+			}
+
+			w.writeErrors.Add(uint64(len(batch)))
+			w.logger.Error("Failed to send IPC batch, resending after reconnect", "error", err, "messages", len(batch))
+
+			// Mark as disconnected on write error and reconnect right away
+			w.connected.Store(false)
+			w.closeConnection()
+			select {
+			case w.reconnectNow <- struct{}{}:
+			default:
+			}
+		}
+
+		if w.gaveUp.Load() {
+			w.msgsDropped.Add(uint64(len(batch)))
+			return frames
+		}
+
+		select {
+		case <-w.ctx.Done():
+			w.msgsDropped.Add(uint64(len(batch)))
+			return frames
+		case <-w.reconnected:
+		}
+	}
+}
+
+// fillBatch adds queued messages to batch until it is full, the flush interval
+// elapses or the writer is stopped. Messages already waiting are taken without delay.
+func (w *Writer) fillBatch(batch []*ipcpb.IPCMessage) []*ipcpb.IPCMessage {
+	if len(batch) >= w.batchSize {
+		return batch
+	}
+
+	var timeout <-chan time.Time
+	if w.flushInterval > 0 {
+		timer := time.NewTimer(w.flushInterval)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for len(batch) < w.batchSize {
+		if timeout == nil {
+			// No flush interval - only take what is already queued
+			select {
+			case msg := <-w.writeChan:
+				batch = append(batch, msg)
+				continue
+			default:
+				return batch
 			}
 		}
+
+		select {
+		case msg := <-w.writeChan:
+			batch = append(batch, msg)
+		case <-timeout:
+			return batch
+		case <-w.ctx.Done():
+			return batch
+		}
 	}
+
+	return batch
 }
 
-// sendMessage sends a single message to the socket
-func (w *Writer) sendMessage(msg *ipcpb.IPCMessage, lengthBuf []byte) error {
+// sendBatch encodes messages as length-prefixed frames into buf and writes them
+// to the socket in one call. Returns buf for reuse by the next batch.
+func (w *Writer) sendBatch(batch []*ipcpb.IPCMessage, buf []byte) ([]byte, error) {
 	w.connMu.RLock()
 	conn := w.conn
 	w.connMu.RUnlock()
 
 	if conn == nil {
-		return fmt.Errorf("no connection")
+		return buf, fmt.Errorf("no connection")
 	}
 
-	// Marshal protobuf
-	data, err := proto.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
-	}
+	for _, msg := range batch {
+		// Marshal protobuf
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return buf, fmt.Errorf("failed to marshal message for job %s: %w", msg.JobId, err)
+		}
 
-	// Write length prefix
-	binary.BigEndian.PutUint32(lengthBuf, uint32(len(data)))
-	if _, err := conn.Write(lengthBuf); err != nil {
-		return fmt.Errorf("failed to write length: %w", err)
+		// Length prefix followed by the message
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+		buf = append(buf, data...)
 	}
 
-	// Write message
-	if _, err := conn.Write(data); err != nil {
-		return fmt.Errorf("failed to write message: %w", err)
+	if _, err := conn.Write(buf); err != nil {
+		return buf, fmt.Errorf("failed to write batch: %w", err)
 	}
 
-	return nil
+	return buf, nil
 }
 
 // reconnectLoop handles reconnection logic
@@ -219,21 +324,30 @@ func (w *Writer) reconnectLoop() {
 		select {
 		case <-w.ctx.Done():
 			return
+		case <-w.reconnectNow:
 		case <-ticker.C:
-			if !w.connected.Load() {
-				if !w.reconnect.shouldRetry() {
-					w.logger.Error("Max reconnection attempts reached, giving up")
-					return
-				}
-
-				if err := w.connect(); err != nil {
-					w.logger.Warn("Reconnection attempt failed",
-						"error", err,
-						"attempt", w.reconnect.attempts)
-				} else {
-					w.reconnect.reset()
-				}
+		}
+
+		if w.connected.Load() {
+			continue
+		}
+		if !w.reconnect.shouldRetry() {
+			w.logger.Error("Max reconnection attempts reached, giving up",
+				"msgsQueued", len(w.writeChan))
+			w.gaveUp.Store(true)
+			select {
+			case w.reconnected <- struct{}{}: // Wake deliver to drop its batch
+			default:
 			}
+			return
+		}
+
+		if err := w.connect(); err != nil {
+			w.logger.Warn("Reconnection attempt failed",
+				"error", err,
+				"attempt", w.reconnect.attempts)
+		} else {
+			w.reconnect.reset()
 		}
 	}
 }
@@ -265,8 +379,18 @@ func (w *Writer) connect() error {
 
 	w.conn = conn
 	w.connected.Store(true)
+	w.everConnected.Store(true)
+
+	w.logger.Info("Connected to persist", "socket", w.socket, "msgsQueued", len(w.writeChan))
+	if dropped := w.msgsDropped.Load() - w.droppedMark.Swap(w.msgsDropped.Load()); dropped > 0 {
+		w.logger.Warn("Messages were dropped while persist was unavailable", "dropped", dropped)
+	}
 
-	w.logger.Info("Connected to persist", "socket", w.socket)
+	// Resend the batch waiting for the connection
+	select {
+	case w.reconnected <- struct{}{}:
+	default:
+	}
 
 	return nil
 }
@@ -292,6 +416,7 @@ func (w *Writer) Close() error {
 
 	w.logger.Info("IPC writer closed",
 		"msgsSent", w.msgsSent.Load(),
+		"batchesSent", w.batchesSent.Load(),
 		"msgsDropped", w.msgsDropped.Load(),
 		"writeErrors", w.writeErrors.Load())
 
@@ -0,0 +1,188 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// readFrames reads length-prefixed IPC messages from conn until count messages
+// have arrived and returns them in order.
+func readFrames(t *testing.T, conn net.Conn, count int) []*ipcpb.IPCMessage {
+	t.Helper()
+
+	var msgs []*ipcpb.IPCMessage
+	lengthBuf := make([]byte, 4)
+	for len(msgs) < count {
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatalf("SetReadDeadline() error = %v", err)
+		}
+		if _, err := io.ReadFull(conn, lengthBuf); err != nil {
+			t.Fatalf("failed to read frame length after %d messages: %v", len(msgs), err)
+		}
+		data := make([]byte, binary.BigEndian.Uint32(lengthBuf))
+		if _, err := io.ReadFull(conn, data); err != nil {
+			t.Fatalf("failed to read frame: %v", err)
+		}
+
+		var msg ipcpb.IPCMessage
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to unmarshal frame: %v", err)
+		}
+		msgs = append(msgs, &msg)
+	}
+	return msgs
+}
+
+func TestWriter_BatchesLogs(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "persist-ipc.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	writer := NewWriter(&Config{
+		Socket:         socket,
+		BufferSize:     100,
+		ReconnectDelay: time.Second,
+		BatchSize:      10,
+		FlushInterval:  20 * time.Millisecond,
+	}, logger.New())
+	defer writer.Close()
+
+	var conn net.Conn
+	select {
+	case conn = <-accepted:
+		defer conn.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("writer did not connect")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !writer.connected.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	const total = 25
+	for i := 0; i < total; i++ {
+		if err := writer.WriteLog("job-1", "", ipcpb.StreamType_STREAM_TYPE_STDOUT, int64(i), uint64(i), []byte("line")); err != nil {
+			t.Fatalf("WriteLog() error = %v", err)
+		}
+	}
+
+	msgs := readFrames(t, conn, total)
+	for i, msg := range msgs {
+		if msg.Sequence != uint64(i) {
+			t.Fatalf("message %d has sequence %d, want in-order delivery", i, msg.Sequence)
+		}
+	}
+
+	deadline = time.Now().Add(5 * time.Second)
+	for writer.msgsSent.Load() < total && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sent := writer.msgsSent.Load(); sent != total {
+		t.Errorf("msgsSent = %d, want %d", sent, total)
+	}
+	if batches := writer.batchesSent.Load(); batches == 0 || batches >= total {
+		t.Errorf("batchesSent = %d, want between 1 and %d", batches, total-1)
+	}
+}
+
+func TestWriter_ResendsAfterPersistReplaced(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "persist-ipc.sock")
+	accept := func() (net.Listener, chan net.Conn) {
+		t.Helper()
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			t.Fatalf("Listen() error = %v", err)
+		}
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			if conn, err := listener.Accept(); err == nil {
+				accepted <- conn
+			}
+		}()
+		return listener, accepted
+	}
+	waitConn := func(accepted chan net.Conn) net.Conn {
+		t.Helper()
+		select {
+		case conn := <-accepted:
+			return conn
+		case <-time.After(5 * time.Second):
+			t.Fatal("writer did not connect")
+			return nil
+		}
+	}
+
+	active, accepted := accept()
+	writer := NewWriter(&Config{
+		Socket:         socket,
+		BufferSize:     100,
+		ReconnectDelay: 20 * time.Millisecond,
+		BatchSize:      1,
+	}, logger.New())
+	defer writer.Close()
+	conn := waitConn(accepted)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !writer.connected.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The active persist process dies
+	conn.Close()
+	active.Close()
+	_ = writer.WriteLog("job-1", "", ipcpb.StreamType_STREAM_TYPE_STDOUT, 0, 0, []byte("line"))
+	deadline = time.Now().Add(5 * time.Second)
+	for writer.connected.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Lines written while no process serves the socket are held
+	for i := 1; i < 5; i++ {
+		if err := writer.WriteLog("job-1", "", ipcpb.StreamType_STREAM_TYPE_STDOUT, int64(i), uint64(i), []byte("line")); err != nil {
+			t.Fatalf("WriteLog() while persist is down error = %v", err)
+		}
+	}
+
+	// A standby binds the socket and receives everything, in order
+	standby, accepted := accept()
+	defer standby.Close()
+	conn = waitConn(accepted)
+	defer conn.Close()
+
+	// The line whose write failed is resent first, unless the failing write
+	// was the one before it
+	msgs := readFrames(t, conn, 4)
+	if msgs[0].Sequence == 0 {
+		msgs = append(msgs, readFrames(t, conn, 1)...)
+	}
+	first := 5 - len(msgs)
+	for i, msg := range msgs {
+		if msg.Sequence != uint64(first+i) {
+			t.Fatalf("standby received %d at %d, want sequences in order up to 4", msg.Sequence, i)
+		}
+	}
+	if dropped := writer.msgsDropped.Load(); dropped != 0 {
+		t.Errorf("msgsDropped = %d, want 0", dropped)
+	}
+}
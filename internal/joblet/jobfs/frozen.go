@@ -0,0 +1,361 @@
+// Package jobfs keeps the filesystem of failed jobs for postmortem
+// inspection, so "what files did it actually produce?" can be answered after
+// the job is gone.
+//
+// A job is armed before it ends, with rnx job freeze-fs or rnx job run
+// --freeze-fs. When an armed job fails, its directories are frozen instead
+// of being removed: the job root moves under <base_dir>/.frozen/<job ID>,
+// the /tmp and scratch directories stay where they are, and all of them can
+// be listed and read until the retention period ends.
+//
+// Records live next to the frozen roots as <job ID>.json, so frozen
+// filesystems survive server restarts.
+package jobfs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FrozenDirName is the directory under the job base directory holding the
+// records and roots of frozen jobs. Its leading dot keeps it out of the
+// orphan cleanup.
+const FrozenDirName = ".frozen"
+
+// ErrNotFrozen is returned when browsing a job whose filesystem was not
+// frozen, or whose retention period ended
+var ErrNotFrozen = errors.New("job filesystem is not frozen")
+
+// Record describes an armed or frozen job
+type Record struct {
+	JobID     string            `json:"jobId"`
+	ArmedAt   time.Time         `json:"armedAt"`
+	FrozenAt  time.Time         `json:"frozenAt,omitzero"`  // Zero while the job has not failed
+	ExpiresAt time.Time         `json:"expiresAt,omitzero"` // When the frozen directories are removed
+	Dirs      map[string]string `json:"dirs,omitempty"`     // Host directory of each preserved job path ("/", "/tmp", "/work")
+}
+
+// Frozen reports whether the job failed and its directories were kept
+func (r *Record) Frozen() bool {
+	return !r.FrozenAt.IsZero()
+}
+
+// Entry describes a file of a frozen filesystem
+type Entry struct {
+	Name    string
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+// Store keeps the records of armed and frozen jobs on disk
+type Store struct {
+	dir       string
+	retention time.Duration
+
+	mu sync.Mutex
+}
+
+// NewStore creates a store under baseDir, the job base directory. A zero
+// retention disables freezing.
+func NewStore(baseDir string, retention time.Duration) *Store {
+	return &Store{dir: filepath.Join(baseDir, FrozenDirName), retention: retention}
+}
+
+// Enabled reports whether jobs can be armed
+func (s *Store) Enabled() bool {
+	return s.retention > 0
+}
+
+// Retention returns how long frozen directories are kept
+func (s *Store) Retention() time.Duration {
+	return s.retention
+}
+
+// Arm marks a job so its filesystem is frozen if it fails. Arming an armed
+// or frozen job returns its record unchanged.
+func (s *Store) Arm(jobID string, now time.Time) (*Record, error) {
+	if !s.Enabled() {
+		return nil, errors.New("freezing job filesystems is disabled (filesystem.freezeRetention)")
+	}
+	if err := checkJobID(jobID); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, err := s.read(jobID); err == nil {
+		return rec, nil
+	}
+	rec := &Record{JobID: jobID, ArmedAt: now}
+	if err := s.write(rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Disarm drops the record of an armed job that did not fail. Frozen jobs
+// keep theirs.
+func (s *Store) Disarm(jobID string) error {
+	if checkJobID(jobID) != nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.read(jobID)
+	if err != nil || rec.Frozen() {
+		return nil
+	}
+	return s.removeRecord(jobID)
+}
+
+// Record returns the record of an armed or frozen job
+func (s *Store) Record(jobID string) (*Record, bool) {
+	if checkJobID(jobID) != nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.read(jobID)
+	return rec, err == nil
+}
+
+// Records returns the records of all armed and frozen jobs
+func (s *Store) Records() []*Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil
+	}
+	var records []*Record
+	for _, entry := range entries {
+		jobID, isRecord := strings.CutSuffix(entry.Name(), ".json")
+		if !isRecord || entry.IsDir() {
+			continue
+		}
+		if rec, err := s.read(jobID); err == nil {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+// Freeze keeps the directories of an armed job that failed. dirs maps the
+// job paths to their host directories; the root ("/") moves into the store,
+// the others stay in place, and the ones that do not exist are left out. It
+// returns nil when the job was not armed.
+func (s *Store) Freeze(jobID string, dirs map[string]string, now time.Time) (*Record, error) {
+	if checkJobID(jobID) != nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.read(jobID)
+	if err != nil {
+		return nil, nil
+	}
+	if rec.Frozen() {
+		return rec, nil
+	}
+
+	rec.Dirs = make(map[string]string, len(dirs))
+	for jobPath, dir := range dirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if jobPath == "/" {
+			frozenRoot := filepath.Join(s.dir, jobID)
+			if err := os.Rename(dir, frozenRoot); err != nil {
+				return nil, fmt.Errorf("failed to move job root: %w", err)
+			}
+			dir = frozenRoot
+		}
+		rec.Dirs[jobPath] = dir
+	}
+	rec.FrozenAt = now
+	rec.ExpiresAt = now.Add(s.retention)
+	if err := s.write(rec); err != nil {
+		// Put the root back so the cleanup finds it
+		if frozenRoot, moved := rec.Dirs["/"]; moved {
+			_ = os.Rename(frozenRoot, dirs["/"])
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Remove deletes the directories of a frozen job and its record
+func (s *Store) Remove(jobID string) error {
+	if err := checkJobID(jobID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.read(jobID)
+	if err != nil {
+		return s.removeRecord(jobID)
+	}
+	var errs []error
+	for _, dir := range rec.Dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return s.removeRecord(jobID)
+}
+
+// List returns the entries of a directory of a frozen job, sorted by name
+func (s *Store) List(jobID, jobPath string) ([]Entry, error) {
+	root, name, err := s.resolve(jobID, jobPath)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	dir, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	dirEntries, err := dir.ReadDir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue // Removed meanwhile
+		}
+		entries = append(entries, Entry{Name: dirEntry.Name(), Size: info.Size(), Mode: info.Mode(), ModTime: info.ModTime()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// Open opens a regular file of a frozen job for reading
+func (s *Store) Open(jobID, jobPath string) (*os.File, error) {
+	root, name, err := s.resolve(jobID, jobPath)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	f, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a regular file", jobPath)
+	}
+	return f, nil
+}
+
+// resolve finds the preserved directory holding jobPath, see OpenDir
+func (s *Store) resolve(jobID, jobPath string) (*os.Root, string, error) {
+	rec, exists := s.Record(jobID)
+	if !exists || !rec.Frozen() {
+		return nil, "", ErrNotFrozen
+	}
+	return OpenDir(rec.Dirs, jobPath)
+}
+
+// OpenDir finds the host directory holding jobPath, a path as the job saw
+// it, in dirs, which maps the job's mount points to host directories. It
+// opens the directory as a root, so neither ".." nor symlinks leave it, and
+// returns the root and the name of jobPath inside it.
+func OpenDir(dirs map[string]string, jobPath string) (*os.Root, string, error) {
+	jobPath = path.Clean("/" + jobPath)
+	var mountPoint string
+	for candidate := range dirs {
+		inside := candidate == "/" || jobPath == candidate || strings.HasPrefix(jobPath, candidate+"/")
+		if inside && len(candidate) > len(mountPoint) {
+			mountPoint = candidate
+		}
+	}
+	if mountPoint == "" {
+		return nil, "", fmt.Errorf("%s: %w", jobPath, fs.ErrNotExist)
+	}
+
+	root, err := os.OpenRoot(dirs[mountPoint])
+	if err != nil {
+		return nil, "", err
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(jobPath, mountPoint), "/")
+	if name == "" {
+		name = "."
+	}
+	return root, name, nil
+}
+
+func (s *Store) recordPath(jobID string) string {
+	return filepath.Join(s.dir, jobID+".json")
+}
+
+func (s *Store) read(jobID string) (*Record, error) {
+	data, err := os.ReadFile(s.recordPath(jobID))
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("corrupt freeze record of job %s: %w", jobID, err)
+	}
+	return &rec, nil
+}
+
+// write replaces the record through a rename so readers never see half of it
+func (s *Store) write(rec *Record) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", s.dir, err)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	tmp := s.recordPath(rec.JobID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.recordPath(rec.JobID))
+}
+
+func (s *Store) removeRecord(jobID string) error {
+	if err := os.Remove(s.recordPath(jobID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// checkJobID rejects IDs that would name a path outside the store
+func checkJobID(jobID string) error {
+	if jobID == "" || jobID == "." || jobID == ".." || strings.ContainsAny(jobID, `/\`) {
+		return fmt.Errorf("invalid job ID %q", jobID)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package jobfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const jobID = "0b5c2a1e-7d7f-4c43-9f0a-5f2e3c9b1a11"
+
+var now = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_FreezeAndBrowse(t *testing.T) {
+	baseDir := t.TempDir()
+	tmpDir := filepath.Join(t.TempDir(), "job-"+jobID)
+	rootDir := filepath.Join(baseDir, jobID)
+	writeFile(t, filepath.Join(rootDir, "work", "out", "result.csv"), "a,b\n")
+	writeFile(t, filepath.Join(rootDir, "work", "run.log"), "started\n")
+	writeFile(t, filepath.Join(tmpDir, "core.123"), "dump")
+	if err := os.Symlink("/etc/passwd", filepath.Join(rootDir, "work", "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(baseDir, 24*time.Hour)
+	if rec, err := s.Freeze(jobID, map[string]string{"/": rootDir}, now); err != nil || rec != nil {
+		t.Fatalf("Freeze() of an unarmed job = %+v, %v", rec, err)
+	}
+	if _, err := s.Arm(jobID, now); err != nil {
+		t.Fatalf("Arm() error = %v", err)
+	}
+	if _, err := s.List(jobID, "/"); !errors.Is(err, ErrNotFrozen) {
+		t.Errorf("List() of an armed job error = %v, want ErrNotFrozen", err)
+	}
+
+	rec, err := s.Freeze(jobID, map[string]string{"/": rootDir, "/tmp": tmpDir, "/work": filepath.Join(baseDir, "missing")}, now)
+	if err != nil || rec == nil || !rec.Frozen() {
+		t.Fatalf("Freeze() = %+v, %v", rec, err)
+	}
+	if !rec.ExpiresAt.Equal(now.Add(24 * time.Hour)) {
+		t.Errorf("ExpiresAt = %v", rec.ExpiresAt)
+	}
+	if _, exists := rec.Dirs["/work"]; exists {
+		t.Error("a missing directory was recorded")
+	}
+	if _, err := os.Stat(rootDir); !os.IsNotExist(err) {
+		t.Errorf("job root was not moved out of the base directory: %v", err)
+	}
+
+	entries, err := s.List(jobID, "/work")
+	if err != nil {
+		t.Fatalf("List(/work) error = %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	if len(names) != 3 || names[0] != "escape" || names[1] != "out" || names[2] != "run.log" {
+		t.Errorf("List(/work) = %v", names)
+	}
+	if entries, err := s.List(jobID, "tmp"); err != nil || len(entries) != 1 || entries[0].Name != "core.123" {
+		t.Errorf("List(tmp) = %+v, %v", entries, err)
+	}
+
+	f, err := s.Open(jobID, "/work/out/../out/result.csv")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	content, _ := io.ReadAll(f)
+	f.Close()
+	if string(content) != "a,b\n" {
+		t.Errorf("Open() read %q", content)
+	}
+	if _, err := s.Open(jobID, "/work/out"); err == nil {
+		t.Error("Open() of a directory succeeded")
+	}
+	if _, err := s.Open(jobID, "/work/escape"); err == nil {
+		t.Error("Open() followed a symlink out of the job root")
+	}
+	if _, err := s.Open(jobID, "/../../etc/passwd"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open() above the job root error = %v", err)
+	}
+
+	// The record survives a restart
+	if rec, exists := NewStore(baseDir, time.Hour).Record(jobID); !exists || !rec.Frozen() {
+		t.Errorf("Record() after reopening = %+v, %v", rec, exists)
+	}
+
+	if err := s.Remove(jobID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(tmpDir); !os.IsNotExist(err) {
+		t.Errorf("tmp directory survived Remove(): %v", err)
+	}
+	if _, exists := s.Record(jobID); exists {
+		t.Error("record survived Remove()")
+	}
+}
+
+func TestStore_Disarm(t *testing.T) {
+	s := NewStore(t.TempDir(), time.Hour)
+	if _, err := s.Arm("../escape", now); err == nil {
+		t.Error("Arm() accepted a path as job ID")
+	}
+	if _, err := s.Arm(jobID, now); err != nil {
+		t.Fatal(err)
+	}
+	if rec, err := s.Arm(jobID, now.Add(time.Minute)); err != nil || !rec.ArmedAt.Equal(now) {
+		t.Errorf("second Arm() = %+v, %v", rec, err)
+	}
+	if len(s.Records()) != 1 {
+		t.Errorf("Records() = %v", s.Records())
+	}
+	if err := s.Disarm(jobID); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := s.Record(jobID); exists {
+		t.Error("record survived Disarm()")
+	}
+
+	if _, err := NewStore(t.TempDir(), 0).Arm(jobID, now); err == nil {
+		t.Error("Arm() succeeded with freezing disabled")
+	}
+}
@@ -0,0 +1,173 @@
+// Package leader elects one active instance among the persist or state
+// processes of a node, so a standby can take over when the active process
+// dies instead of logs and job states being lost until it is restarted.
+//
+// Instances hold a named lease with a TTL in a shared store (the state
+// service for persist, the state storage backend for state itself). Only the
+// holder binds the service's sockets; standbys retry the lease until it
+// expires, then bind the same socket paths, which the clients reconnect to.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// DefaultTTL is how long a lease lasts without renewal
+const DefaultTTL = 10 * time.Second
+
+// ErrLost is returned by Hold when another instance holds the lease
+var ErrLost = errors.New("leadership lost")
+
+// ErrExpired is returned by Hold when the lease could not be renewed for a
+// whole TTL, after which another instance may hold it
+var ErrExpired = errors.New("lease expired without renewal")
+
+// Lease is a named lease in a store shared by all instances
+type Lease interface {
+	// Acquire takes the lease for holder or extends it when holder already
+	// has it. It reports false when another holder's lease has not expired.
+	Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+	// Release gives the lease up if holder has it
+	Release(ctx context.Context, holder string) error
+}
+
+// Elector campaigns for a lease and keeps it renewed
+type Elector struct {
+	lease  Lease
+	holder string
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewElector creates an elector for holder (see Holder); ttl <= 0 uses DefaultTTL
+func NewElector(lease Lease, holder string, ttl time.Duration, log *logger.Logger) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Elector{lease: lease, holder: holder, ttl: ttl, logger: log.WithField("component", "leader")}
+}
+
+// Holder identifies this process as a lease holder
+func Holder() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s/%d", host, os.Getpid())
+}
+
+// interval is how often the lease is renewed or retried, three times per TTL
+// so one missed renewal doesn't lose it
+func (e *Elector) interval() time.Duration {
+	return e.ttl / 3
+}
+
+// Campaign blocks until this instance holds the lease or ctx ends. Errors
+// reaching the store are retried.
+func (e *Elector) Campaign(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval())
+	defer ticker.Stop()
+
+	standby := false
+	for {
+		acquired, err := e.lease.Acquire(ctx, e.holder, e.ttl)
+		switch {
+		case err != nil:
+			e.logger.Warn("failed to acquire lease, retrying", "holder", e.holder, "error", err)
+		case acquired:
+			e.logger.Info("acquired lease, now active", "holder", e.holder)
+			return nil
+		case !standby:
+			e.logger.Info("lease held by another instance, running as standby", "holder", e.holder)
+			standby = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Hold renews the lease until ctx ends, then releases it. It returns ErrLost
+// when another instance took the lease. Failed renewals are retried while the
+// lease lasts; once no renewal succeeded for a whole TTL, Hold returns
+// ErrExpired, since a standby that can reach the store may hold the lease by
+// then. Hold expects the lease to have just been acquired by Campaign.
+func (e *Elector) Hold(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval())
+	defer ticker.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), e.interval())
+			defer cancel()
+			if err := e.lease.Release(releaseCtx, e.holder); err != nil {
+				e.logger.Warn("failed to release lease", "holder", e.holder, "error", err)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		acquired, err := e.lease.Acquire(ctx, e.holder, e.ttl)
+		if err != nil {
+			if time.Since(renewed) >= e.ttl {
+				e.logger.Error("lease not renewed within its TTL, stepping down", "holder", e.holder, "error", err)
+				return ErrExpired
+			}
+			e.logger.Warn("failed to renew lease", "holder", e.holder, "error", err)
+			continue
+		}
+		if !acquired {
+			e.logger.Error("lease taken by another instance", "holder", e.holder)
+			return ErrLost
+		}
+		renewed = time.Now()
+	}
+}
+
+// MemoryLease is a Lease kept in memory, for stores that keep their leases
+// in a map and for tests
+type MemoryLease struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	now     func() time.Time
+}
+
+// NewMemoryLease creates an unheld lease
+func NewMemoryLease() *MemoryLease {
+	return &MemoryLease{now: time.Now}
+}
+
+// Acquire implements Lease
+func (l *MemoryLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.holder != "" && l.holder != holder && now.Before(l.expires) {
+		return false, nil
+	}
+	l.holder, l.expires = holder, now.Add(ttl)
+	return true, nil
+}
+
+// Release implements Lease
+func (l *MemoryLease) Release(ctx context.Context, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.holder == holder {
+		l.holder = ""
+	}
+	return nil
+}
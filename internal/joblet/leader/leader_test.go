@@ -0,0 +1,129 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestMemoryLease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	lease := NewMemoryLease()
+	lease.now = func() time.Time { return now }
+
+	if ok, _ := lease.Acquire(ctx, "a", 10*time.Second); !ok {
+		t.Fatal("a could not take a free lease")
+	}
+	if ok, _ := lease.Acquire(ctx, "b", 10*time.Second); ok {
+		t.Fatal("b took a lease a holds")
+	}
+	now = now.Add(5 * time.Second)
+	if ok, _ := lease.Acquire(ctx, "a", 10*time.Second); !ok {
+		t.Fatal("a could not renew its lease")
+	}
+	now = now.Add(11 * time.Second)
+	if ok, _ := lease.Acquire(ctx, "b", 10*time.Second); !ok {
+		t.Fatal("b could not take an expired lease")
+	}
+
+	_ = lease.Release(ctx, "a") // Not a's anymore
+	if ok, _ := lease.Acquire(ctx, "a", 10*time.Second); ok {
+		t.Fatal("a released b's lease")
+	}
+	_ = lease.Release(ctx, "b")
+	if ok, _ := lease.Acquire(ctx, "a", 10*time.Second); !ok {
+		t.Fatal("a could not take a released lease")
+	}
+}
+
+func TestElectorFailover(t *testing.T) {
+	lease := NewMemoryLease()
+	active := NewElector(lease, "active", 30*time.Millisecond, logger.New())
+	standby := NewElector(lease, "standby", 30*time.Millisecond, logger.New())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := active.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+
+	holdCtx, stopHolding := context.WithCancel(ctx)
+	held := make(chan error, 1)
+	go func() { held <- active.Hold(holdCtx) }()
+
+	elected := make(chan error, 1)
+	go func() { elected <- standby.Campaign(ctx) }()
+	select {
+	case err := <-elected:
+		t.Fatalf("standby elected while the lease is renewed: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The active instance goes away and releases its lease
+	stopHolding()
+	if err := <-held; !errors.Is(err, context.Canceled) {
+		t.Errorf("Hold() = %v, want context.Canceled", err)
+	}
+	if err := <-elected; err != nil {
+		t.Fatalf("standby Campaign() error = %v", err)
+	}
+
+	// The old instance comes back and finds the lease taken
+	if ok, _ := lease.Acquire(ctx, "active", time.Second); ok {
+		t.Fatal("old instance took the standby's lease")
+	}
+}
+
+func TestElectorHoldLost(t *testing.T) {
+	lease := NewMemoryLease()
+	e := NewElector(lease, "a", 30*time.Millisecond, logger.New())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+	lease.mu.Lock()
+	lease.holder, lease.expires = "b", time.Now().Add(time.Hour)
+	lease.mu.Unlock()
+	if err := e.Hold(ctx); !errors.Is(err, ErrLost) {
+		t.Errorf("Hold() = %v, want ErrLost", err)
+	}
+}
+
+// failingLease fails every renewal after the first acquire
+type failingLease struct {
+	*MemoryLease
+	failing bool
+}
+
+func (l *failingLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	if l.failing {
+		return false, errors.New("store unreachable")
+	}
+	return l.MemoryLease.Acquire(ctx, holder, ttl)
+}
+
+func TestElectorHoldExpired(t *testing.T) {
+	lease := &failingLease{MemoryLease: NewMemoryLease()}
+	ttl := 30 * time.Millisecond
+	e := NewElector(lease, "a", ttl, logger.New())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() error = %v", err)
+	}
+
+	// The store stays unreachable for longer than the TTL
+	lease.failing = true
+	start := time.Now()
+	if err := e.Hold(ctx); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Hold() = %v, want ErrExpired", err)
+	}
+	if held := time.Since(start); held < ttl {
+		t.Errorf("stepped down after %v, before the TTL of %v", held, ttl)
+	}
+}
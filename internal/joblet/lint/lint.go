@@ -0,0 +1,316 @@
+// Package lint checks job and workflow specs against best practices before
+// they are submitted: jobs without resource limits, secrets passed as plain
+// environment, runtimes not pinned to a version and broad networks.
+//
+// The same rules run in 'rnx lint' on the client and in the lint service on
+// the node, which also knows its runtime aliases, networks and default limits.
+// Each finding has a severity so pre-commit hooks can fail on errors and only
+// report the rest.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity of a finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Severities lists the severities from the most to the least severe
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo}
+
+// ParseSeverity parses a severity name
+func ParseSeverity(name string) (Severity, error) {
+	for _, s := range Severities {
+		if string(s) == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity %q, expected error, warning or info", name)
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as severe as threshold or more
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+// Rules
+const (
+	RuleNoResourceLimits = "no-resource-limits"
+	RuleSecretInPlainEnv = "secret-in-plain-env"
+	RuleLiteralSecret    = "literal-secret"
+	RuleUnpinnedRuntime  = "unpinned-runtime"
+	RuleBroadNetwork     = "broad-network"
+	RuleUnknownNetwork   = "unknown-network"
+)
+
+// Spec kinds
+const (
+	KindJob      = "Job"
+	KindWorkflow = "Workflow"
+)
+
+// Finding is one problem found in a spec
+type Finding struct {
+	File     string   `json:"file,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Job is the workflow job the finding is about, empty for a job spec or
+	// the workflow itself
+	Job string `json:"job,omitempty"`
+	// Field is the path of the offending field, such as jobs.train.resources
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// String formats the finding as file: severity [rule] field: message
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.Rule, f.Field, f.Message)
+	if f.File != "" {
+		s = f.File + ": " + s
+	}
+	return s
+}
+
+// Options carry what the node knows about itself. The zero value is what the
+// client knows.
+type Options struct {
+	// ResolveRuntime returns the exact runtime a runtime name stands for on
+	// the node and what pinned it, "" when nothing did
+	ResolveRuntime func(spec string) (string, string)
+	// Networks are the node's networks; nil skips the unknown network check
+	Networks []string
+	// DefaultLimits describes the limits the node gives jobs without any
+	DefaultLimits string
+}
+
+// jobSpec is the part of a job spec file the rules look at
+type jobSpec struct {
+	Runtime           string             `yaml:"runtime"`
+	Network           string             `yaml:"network"`
+	Environment       map[string]string  `yaml:"environment"`
+	SecretEnvironment map[string]string  `yaml:"secret_environment"`
+	Resources         types.JobResources `yaml:"resources"`
+}
+
+// Lint checks a job spec or a workflow, told apart by its jobs key, and
+// returns the spec's kind with the findings
+func Lint(data []byte, opts Options) (string, []Finding, error) {
+	var top map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &top); err != nil {
+		return "", nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	if _, isWorkflow := top["jobs"]; isWorkflow {
+		var wf types.WorkflowYAML
+		if err := decode(data, &wf); err != nil {
+			return "", nil, fmt.Errorf("invalid workflow: %w", err)
+		}
+		return KindWorkflow, lintWorkflow(wf, opts), nil
+	}
+
+	var spec jobSpec
+	if err := decode(data, &spec); err != nil {
+		return "", nil, fmt.Errorf("invalid job spec: %w", err)
+	}
+	l := linter{opts: opts}
+	l.resources("resources", "", spec.Resources)
+	l.plainEnvironment("environment", "", spec.Environment, false)
+	l.literalSecrets("secret_environment", spec.SecretEnvironment)
+	l.runtime("runtime", "", spec.Runtime)
+	l.network("network", "", spec.Network)
+	return KindJob, l.findings, nil
+}
+
+func decode(data []byte, v any) error {
+	return yaml.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func lintWorkflow(wf types.WorkflowYAML, opts Options) []Finding {
+	l := linter{opts: opts}
+	l.plainEnvironment("environment", "", wf.Environment, true)
+	if wf.Defaults != nil {
+		l.plainEnvironment("defaults.environment", "", wf.Defaults.Environment, true)
+	}
+
+	// Jobs are checked with the defaults they get, but their environment
+	// without them so a default variable is reported once
+	defaulted := types.WorkflowYAML{Defaults: wf.Defaults, Jobs: maps.Clone(wf.Jobs)}
+	workflow.ApplyDefaults(&defaulted)
+
+	names := make([]string, 0, len(wf.Jobs))
+	for name := range wf.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		job := defaulted.Jobs[name]
+		prefix := "jobs." + name + "."
+		l.resources(prefix+"resources", name, job.Resources)
+		l.plainEnvironment(prefix+"environment", name, wf.Jobs[name].Environment, true)
+		l.runtime(prefix+"runtime", name, job.Runtime)
+		l.network(prefix+"network", name, job.Network)
+	}
+	return l.findings
+}
+
+type linter struct {
+	opts     Options
+	findings []Finding
+}
+
+func (l *linter) add(rule string, severity Severity, field, job, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		Job:      job,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) resources(field, job string, r types.JobResources) {
+	cpu := r.MaxCPU > 0 || r.CPUCores != ""
+	memory := r.MaxMemory > 0
+	switch {
+	case !cpu && !memory && l.opts.DefaultLimits != "":
+		l.add(RuleNoResourceLimits, SeverityWarning, field, job,
+			"no CPU or memory limit set, the node's defaults apply (%s); set max_cpu and max_memory for what the job needs", l.opts.DefaultLimits)
+	case !cpu && !memory:
+		l.add(RuleNoResourceLimits, SeverityWarning, field, job,
+			"no CPU or memory limit set, the job can take the whole node; set max_cpu and max_memory")
+	case !memory:
+		l.add(RuleNoResourceLimits, SeverityInfo, field, job, "no max_memory set")
+	case !cpu:
+		l.add(RuleNoResourceLimits, SeverityInfo, field, job, "no max_cpu or cpu_cores set")
+	}
+}
+
+// credentialValue matches values shaped like well-known credentials
+var credentialValue = regexp.MustCompile(`AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{22,}|glpat-[A-Za-z0-9_-]{20}|xox[abpr]-[A-Za-z0-9-]{10,}|sk_live_[A-Za-z0-9]{16,}|-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+
+// secretWords are parts of variable names that hold secrets
+var secretWords = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "APIKEY", "API_KEY", "PRIVATE_KEY", "CREDENTIAL", "ACCESS_KEY"}
+
+func looksSecret(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, word := range secretWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// plainEnvironment flags plain variables holding credentials or named like
+// secrets. Workflow variables named by the secret convention are passed as
+// secrets already.
+func (l *linter) plainEnvironment(field, job string, env map[string]string, workflow bool) {
+	hint := "move it to secret_environment"
+	if workflow {
+		hint = "name it SECRET_<NAME> or move it to secrets"
+	}
+	for _, key := range sortedKeys(env) {
+		if workflow && types.IsSecretKey(key) {
+			continue
+		}
+		switch {
+		case credentialValue.MatchString(env[key]):
+			l.add(RuleSecretInPlainEnv, SeverityError, field+"."+key, job,
+				"value looks like a credential and is passed as plain environment, visible in job status; %s", hint)
+		case looksSecret(key):
+			l.add(RuleSecretInPlainEnv, SeverityWarning, field+"."+key, job,
+				"%s looks like a secret but is passed as plain environment, visible in job status; %s", key, hint)
+		}
+	}
+}
+
+// literalSecrets flags job spec secrets written in the file instead of
+// referenced from the local environment
+func (l *linter) literalSecrets(field string, secrets map[string]string) {
+	for _, key := range sortedKeys(secrets) {
+		if strings.Contains(secrets[key], "${") {
+			continue
+		}
+		l.add(RuleLiteralSecret, SeverityWarning, field+"."+key, "",
+			"secret value is written in the spec; reference it from the environment with ${%s}", key)
+	}
+}
+
+func (l *linter) runtime(field, job, runtime string) {
+	if runtime == "" || strings.Contains(runtime, "@") {
+		return
+	}
+	if l.opts.ResolveRuntime != nil {
+		resolved, pinnedBy := l.opts.ResolveRuntime(runtime)
+		if pinnedBy != "" && strings.Contains(resolved, "@") {
+			return
+		}
+	}
+	l.add(RuleUnpinnedRuntime, SeverityWarning, field, job,
+		"runtime %s is not pinned to a version and changes when the runtime is upgraded; use %s@<version>", runtime, runtime)
+}
+
+// builtinNetworks exist on every node
+var builtinNetworks = []string{"none", "isolated", "bridge"}
+
+func (l *linter) network(field, job, network string) {
+	switch network {
+	case "", "bridge":
+		l.add(RuleBroadNetwork, SeverityInfo, field, job,
+			"job runs on the bridge network, reaching the internet and every other bridge job; use none, isolated or a dedicated network if it needs less")
+		return
+	}
+	if l.opts.Networks == nil || slices.Contains(builtinNetworks, network) || slices.Contains(l.opts.Networks, network) {
+		return
+	}
+	l.add(RuleUnknownNetwork, SeverityError, field, job, "network %s does not exist on the node", network)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Count returns the number of findings at or above threshold
+func Count(findings []Finding, threshold Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity.AtLeast(threshold) {
+			n++
+		}
+	}
+	return n
+}
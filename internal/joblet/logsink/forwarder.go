@@ -0,0 +1,203 @@
+package logsink
+
+import (
+	"context"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// drainDelay is how long sinks stay open after a job finished, for output
+// chunks published after its final status
+const drainDelay = 5 * time.Second
+
+// closedTTL is how long a job whose sinks closed is remembered
+const closedTTL = 10 * time.Minute
+
+// JobLookup returns a stored job
+type JobLookup func(jobID string) (*domain.Job, bool)
+
+// Forwarder subscribes to job output and copies it to the sinks each job
+// asked for. Output arrives redacted, as it is for persist.
+type Forwarder struct {
+	cfg    config.LogSinksConfig
+	pubsub pubsub.PubSub[adapters.JobEvent]
+	lookup JobLookup
+	logger *logger.Logger
+
+	// Owned by the event loop
+	jobs    map[string]*jobSinks
+	closing map[string]time.Time // Finished jobs and when their sinks close
+	closed  map[string]time.Time // Jobs whose sinks closed and when
+
+	ctx         context.Context
+	cancel      context.CancelFunc
+	unsubscribe func()
+	done        chan struct{}
+}
+
+// jobSinks holds the open sinks of a job, none for jobs without sinks
+type jobSinks struct {
+	sinks []*asyncSink
+}
+
+// NewForwarder creates a forwarder for the job events on ps
+func NewForwarder(cfg config.LogSinksConfig, ps pubsub.PubSub[adapters.JobEvent], lookup JobLookup) *Forwarder {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Forwarder{
+		cfg:     cfg,
+		pubsub:  ps,
+		lookup:  lookup,
+		logger:  logger.WithField("component", "log-sinks"),
+		jobs:    make(map[string]*jobSinks),
+		closing: make(map[string]time.Time),
+		closed:  make(map[string]time.Time),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to job events
+func (f *Forwarder) Start() error {
+	updates, unsubscribe, err := f.pubsub.Subscribe(f.ctx, "jobs")
+	if err != nil {
+		return err
+	}
+	f.unsubscribe = unsubscribe
+	go f.processEvents(updates)
+	f.logger.Info("log sink forwarder started")
+	return nil
+}
+
+// Stop unsubscribes and closes every open sink, sending its queued output
+func (f *Forwarder) Stop() {
+	f.cancel()
+	if f.unsubscribe != nil {
+		f.unsubscribe()
+		<-f.done
+	}
+	for jobID := range f.jobs {
+		f.closeJob(jobID, false)
+	}
+}
+
+func (f *Forwarder) processEvents(updates <-chan pubsub.Message[adapters.JobEvent]) {
+	defer close(f.done)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case msg, ok := <-updates:
+			if !ok {
+				return
+			}
+			f.handle(msg.Payload, time.Now())
+		case now := <-ticker.C:
+			for jobID, at := range f.closing {
+				if now.After(at) {
+					f.closeJob(jobID, true)
+					f.closed[jobID] = now
+				}
+			}
+			for jobID, at := range f.closed {
+				if now.Sub(at) > closedTTL {
+					delete(f.closed, jobID)
+				}
+			}
+		}
+	}
+}
+
+// handle routes one job event
+func (f *Forwarder) handle(event adapters.JobEvent, now time.Time) {
+	switch event.Type {
+	case "LOG_CHUNK":
+		if len(event.LogChunk) == 0 {
+			return
+		}
+		for _, s := range f.sinksFor(event.JobID) {
+			s.Send(event.LogChunk)
+		}
+	case "UPDATED":
+		if _, open := f.jobs[event.JobID]; !open {
+			return
+		}
+		job := domain.Job{Status: domain.JobStatus(event.Status)}
+		if job.IsCompleted() || job.Status == domain.StatusCanceled || job.Status == domain.StatusExpired {
+			f.closing[event.JobID] = now.Add(drainDelay)
+		}
+	case "DELETED":
+		if _, open := f.jobs[event.JobID]; open {
+			f.closeJob(event.JobID, true)
+			f.closed[event.JobID] = now
+		}
+	}
+}
+
+// sinksFor returns the job's sinks, opening them on its first output
+func (f *Forwarder) sinksFor(jobID string) []*asyncSink {
+	if js, exists := f.jobs[jobID]; exists {
+		return js.sinks
+	}
+
+	// Output straggling in after the sinks closed is not sent, reopening
+	// them would replace the first S3 object
+	if _, wasClosed := f.closed[jobID]; wasClosed {
+		return nil
+	}
+
+	js := &jobSinks{}
+	f.jobs[jobID] = js
+	job, exists := f.lookup(jobID)
+	if !exists || len(job.LogSinks) == 0 {
+		return nil
+	}
+
+	log := f.logger.WithField("jobID", jobID)
+	for _, raw := range job.LogSinks {
+		target, err := Parse(raw)
+		if err != nil {
+			log.Warn("skipping invalid log sink", "error", err)
+			continue
+		}
+		s, err := open(target, jobID, f.cfg)
+		if err != nil {
+			log.Warn("failed to open log sink", "sink", target.String(), "error", err)
+			continue
+		}
+		js.sinks = append(js.sinks, newAsyncSink(target, s, f.cfg.FlushInterval, log))
+		log.Info("copying job output to log sink", "sink", target.String())
+	}
+	return js.sinks
+}
+
+// closeJob closes the job's sinks, in the background when async so a slow
+// final upload does not hold up other jobs' output
+func (f *Forwarder) closeJob(jobID string, async bool) {
+	js := f.jobs[jobID]
+	delete(f.jobs, jobID)
+	delete(f.closing, jobID)
+	if js == nil || len(js.sinks) == 0 {
+		return
+	}
+
+	closeAll := func() {
+		for _, s := range js.sinks {
+			s.Close()
+		}
+	}
+	if async {
+		go closeAll()
+	} else {
+		closeAll()
+	}
+}
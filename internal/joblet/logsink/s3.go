@@ -0,0 +1,123 @@
+package logsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/objectstore"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// maxBufferedChunks bounds the output kept while uploads fail, in units of
+// max_chunk_bytes; older output is dropped beyond it
+const maxBufferedChunks = 4
+
+// s3Sink uploads output as numbered objects under
+// <prefix><jobID>/output-NNNNNN.log, one object per flush. S3 objects cannot
+// be appended to, so "near real time" means every flush_interval or
+// max_chunk_bytes, whichever comes first.
+type s3Sink struct {
+	bucket    string
+	keyPrefix string
+	maxChunk  int
+	creds     objectstore.Credentials
+	region    string
+	endpoint  string // Path-style endpoint, empty for AWS virtual-hosted style
+	client    *http.Client
+	now       func() time.Time
+
+	buf []byte
+	seq int
+}
+
+func newS3Sink(target Target, jobID string, cfg config.LogSinksConfig) *s3Sink {
+	creds := objectstore.Credentials{
+		AccessKeyID:     cfg.S3.AccessKeyID,
+		SecretAccessKey: cfg.S3.SecretAccessKey,
+		SessionToken:    cfg.S3.SessionToken,
+	}
+	if creds.AccessKeyID == "" {
+		creds = objectstore.EnvCredentials()
+	}
+	region := cfg.S3.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3Sink{
+		bucket:    target.Host,
+		keyPrefix: path.Join(target.Path, jobID) + "/",
+		maxChunk:  cfg.MaxChunkBytes,
+		creds:     creds,
+		region:    region,
+		endpoint:  strings.TrimSuffix(cfg.S3.Endpoint, "/"),
+		client:    &http.Client{Timeout: 60 * time.Second},
+		now:       time.Now,
+	}
+}
+
+func (s *s3Sink) Write(chunk []byte) error {
+	s.buf = append(s.buf, chunk...)
+	if len(s.buf) < s.maxChunk {
+		return nil
+	}
+	return s.Flush()
+}
+
+func (s *s3Sink) Flush() error {
+	if len(s.buf) == 0 {
+		return nil
+	}
+	key := fmt.Sprintf("%soutput-%06d.log", s.keyPrefix, s.seq+1)
+	if err := s.put(key, s.buf); err != nil {
+		// Keep the output for the next flush, within bounds
+		if limit := maxBufferedChunks * s.maxChunk; len(s.buf) > limit {
+			s.buf = s.buf[len(s.buf)-limit:]
+		}
+		return err
+	}
+	s.seq++
+	s.buf = s.buf[:0]
+	return nil
+}
+
+func (s *s3Sink) Close() error {
+	return s.Flush()
+}
+
+// objectURL returns the URL of key in the sink's bucket
+func (s *s3Sink) objectURL(key string) string {
+	return objectstore.ObjectURL(s.endpoint, s.region, s.bucket, key)
+}
+
+// put uploads one object with a SigV4 signed PUT request
+func (s *s3Sink) put(key string, body []byte) error {
+	if !s.creds.Valid() {
+		return fmt.Errorf("no S3 credentials: set log_sinks.s3 or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	objectstore.Sign(req, body, s.creds, s.region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to upload %s: %s: %s", key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
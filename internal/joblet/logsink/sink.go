@@ -0,0 +1,172 @@
+// Package logsink copies job output to external destinations (S3 buckets and
+// syslog servers) as the job writes it, next to the copy kept by persist.
+package logsink
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// Supported sink URL schemes
+const (
+	SchemeS3        = "s3"         // s3://bucket/prefix/
+	SchemeSyslog    = "syslog"     // syslog://host[:port], UDP
+	SchemeSyslogTCP = "syslog+tcp" // syslog+tcp://host[:port]
+)
+
+const defaultSyslogPort = "514"
+
+// Target is a parsed sink URL
+type Target struct {
+	Scheme string
+	Host   string // Bucket for S3, host:port for syslog
+	Path   string // S3 key prefix without leading slash, empty for syslog
+}
+
+// String returns the target as a URL
+func (t Target) String() string {
+	if t.Scheme == SchemeS3 {
+		return fmt.Sprintf("s3://%s/%s", t.Host, t.Path)
+	}
+	return fmt.Sprintf("%s://%s", t.Scheme, t.Host)
+}
+
+// Parse checks a sink URL such as "s3://bucket/prefix/" or "syslog://host"
+func Parse(raw string) (Target, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid log sink %q: %w", raw, err)
+	}
+	if u.Host == "" {
+		return Target{}, fmt.Errorf("invalid log sink %q: missing host", raw)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return Target{}, fmt.Errorf("invalid log sink %q: credentials, query and fragment are not supported", raw)
+	}
+
+	switch u.Scheme {
+	case SchemeS3:
+		if u.Port() != "" {
+			return Target{}, fmt.Errorf("invalid log sink %q: bucket name cannot have a port", raw)
+		}
+		return Target{Scheme: SchemeS3, Host: u.Host, Path: strings.TrimPrefix(u.Path, "/")}, nil
+	case SchemeSyslog, SchemeSyslogTCP:
+		if u.Path != "" && u.Path != "/" {
+			return Target{}, fmt.Errorf("invalid log sink %q: syslog sinks have no path", raw)
+		}
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), defaultSyslogPort)
+		}
+		return Target{Scheme: u.Scheme, Host: host}, nil
+	default:
+		return Target{}, fmt.Errorf("invalid log sink %q: scheme must be %s, %s or %s", raw, SchemeS3, SchemeSyslog, SchemeSyslogTCP)
+	}
+}
+
+// sink writes job output to one destination. Calls come from a single
+// goroutine.
+type sink interface {
+	Write(chunk []byte) error
+	Flush() error // Send buffered output
+	Close() error // Flush and release the destination
+}
+
+// open creates the sink for a target
+func open(target Target, jobID string, cfg config.LogSinksConfig) (sink, error) {
+	switch target.Scheme {
+	case SchemeS3:
+		return newS3Sink(target, jobID, cfg), nil
+	case SchemeSyslog:
+		return newSyslogSink("udp", target.Host, jobID)
+	case SchemeSyslogTCP:
+		return newSyslogSink("tcp", target.Host, jobID)
+	default:
+		return nil, fmt.Errorf("unsupported log sink scheme %q", target.Scheme)
+	}
+}
+
+// queueSize bounds the chunks waiting for a slow destination; further chunks
+// are dropped so the job's output path never blocks on a sink
+const queueSize = 256
+
+// asyncSink feeds a sink from its own goroutine and flushes it every
+// flushInterval
+type asyncSink struct {
+	target  Target
+	sink    sink
+	queue   chan []byte
+	done    chan struct{}
+	dropped atomic.Int64
+	logger  *logger.Logger
+}
+
+func newAsyncSink(target Target, s sink, flushInterval time.Duration, log *logger.Logger) *asyncSink {
+	a := &asyncSink{
+		target: target,
+		sink:   s,
+		queue:  make(chan []byte, queueSize),
+		done:   make(chan struct{}),
+		logger: log.WithField("sink", target.String()),
+	}
+	go a.run(flushInterval)
+	return a
+}
+
+// Send queues a chunk, dropping it when the destination falls behind
+func (a *asyncSink) Send(chunk []byte) {
+	select {
+	case a.queue <- chunk:
+	default:
+		if a.dropped.Add(1) == 1 {
+			a.logger.Warn("log sink is falling behind, dropping output")
+		}
+	}
+}
+
+// Close sends the queued output and closes the sink
+func (a *asyncSink) Close() {
+	close(a.queue)
+	<-a.done
+}
+
+func (a *asyncSink) run(flushInterval time.Duration) {
+	defer close(a.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	failing := false
+	report := func(err error) {
+		// Log the first error of a run of failures only
+		if err != nil && !failing {
+			a.logger.Warn("failed to write job output to log sink", "error", err)
+		}
+		failing = err != nil
+	}
+
+	for {
+		select {
+		case chunk, ok := <-a.queue:
+			if !ok {
+				if err := a.sink.Close(); err != nil {
+					a.logger.Warn("failed to close log sink", "error", err)
+				}
+				if dropped := a.dropped.Load(); dropped > 0 {
+					a.logger.Warn("log sink dropped output", "chunks", dropped)
+				}
+				return
+			}
+			report(a.sink.Write(chunk))
+		case <-ticker.C:
+			report(a.sink.Flush())
+		}
+	}
+}
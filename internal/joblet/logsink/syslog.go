@@ -0,0 +1,129 @@
+package logsink
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// syslogPriority is facility user (1) with severity info (6), used for every message
+const syslogPriority = 1*8 + 6
+
+// maxSyslogMessage keeps a UDP datagram below common MTU-safe limits
+const maxSyslogMessage = 1024
+
+// syslogSink sends each line of output as an RFC 5424 message from APP-NAME
+// "joblet" with the job ID as PROCID
+type syslogSink struct {
+	network string
+	address string
+	jobID   string
+	host    string
+	conn    net.Conn
+	partial []byte // Last line, until its newline arrives
+	now     func() time.Time
+}
+
+func newSyslogSink(network, address, jobID string) (*syslogSink, error) {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "-"
+	}
+	s := &syslogSink{network: network, address: address, jobID: jobID, host: host, now: time.Now}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *syslogSink) connect() error {
+	conn, err := net.DialTimeout(s.network, s.address, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog %s: %w", s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *syslogSink) Write(chunk []byte) error {
+	s.partial = append(s.partial, chunk...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := s.partial[:i]
+		s.partial = s.partial[i+1:]
+		if err := s.send(line); err != nil {
+			return err
+		}
+	}
+	// A line that never ends is sent in pieces
+	if len(s.partial) >= maxSyslogMessage {
+		line := s.partial
+		s.partial = nil
+		return s.send(line)
+	}
+	return nil
+}
+
+func (s *syslogSink) Flush() error {
+	if len(s.partial) == 0 {
+		return nil
+	}
+	line := s.partial
+	s.partial = nil
+	return s.send(line)
+}
+
+func (s *syslogSink) Close() error {
+	err := s.Flush()
+	if s.conn != nil {
+		if closeErr := s.conn.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// send writes one message, reconnecting once when a TCP connection dropped
+func (s *syslogSink) send(line []byte) error {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) > maxSyslogMessage {
+		line = line[:maxSyslogMessage]
+	}
+	msg := s.format(line)
+
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		if s.network == "udp" {
+			return err
+		}
+		if err := s.connect(); err != nil {
+			return err
+		}
+		_, err = s.conn.Write(msg)
+		return err
+	}
+	return nil
+}
+
+// format builds "<PRI>1 TIMESTAMP HOST APP-NAME PROCID MSGID SD MSG". TCP
+// messages are newline framed.
+func (s *syslogSink) format(line []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s joblet %s - - ", syslogPriority, s.now().UTC().Format(time.RFC3339Nano), s.host, s.jobID)
+	buf.Write(line)
+	if s.network == "tcp" {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,211 @@
+// Package maintenance tracks the node's recurring maintenance windows, during
+// which the joblet starts no job, and notifies ahead of them so OS patch
+// automation and operators know when the node is about to be quiet.
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/cron"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// checkInterval is how often the notifier looks for windows starting or ending
+const checkInterval = 30 * time.Second
+
+// Notification events
+const (
+	EventUpcoming = "maintenance.upcoming"
+	EventStarted  = "maintenance.started"
+	EventEnded    = "maintenance.ended"
+)
+
+// Window is one occurrence of a maintenance window
+type Window struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// String describes the window for job events and logs
+func (w Window) String() string {
+	return fmt.Sprintf("window %s until %s", w.Name, w.End.Format(time.RFC3339))
+}
+
+type recurring struct {
+	name     string
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// openAt returns the occurrence open at now. The latest start that still
+// covers now is the first start after now minus the duration.
+func (r recurring) openAt(now time.Time) (Window, bool) {
+	start := r.schedule.Next(now.Add(-r.duration))
+	if start.IsZero() || start.After(now) {
+		return Window{}, false
+	}
+	return Window{Name: r.name, Start: start, End: start.Add(r.duration)}, true
+}
+
+// Calendar holds the configured windows. A nil Calendar has no windows.
+type Calendar struct {
+	windows []recurring
+}
+
+// NewCalendar parses the configured windows; it returns nil when there are none
+func NewCalendar(cfg config.MaintenanceConfig) (*Calendar, error) {
+	if len(cfg.Windows) == 0 {
+		return nil, nil
+	}
+	c := &Calendar{}
+	for _, w := range cfg.Windows {
+		schedule, err := cron.Parse(w.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: %w", w.Name, err)
+		}
+		c.windows = append(c.windows, recurring{name: w.Name, schedule: schedule, duration: w.Duration})
+	}
+	return c, nil
+}
+
+// Active returns the window open at now. When windows overlap, the one
+// ending last is returned.
+func (c *Calendar) Active(now time.Time) (Window, bool) {
+	if c == nil {
+		return Window{}, false
+	}
+	var active Window
+	found := false
+	for _, r := range c.windows {
+		if w, open := r.openAt(now); open && (!found || w.End.After(active.End)) {
+			active, found = w, true
+		}
+	}
+	return active, found
+}
+
+// Notifier logs and POSTs notifications ahead of, at the start of and at the
+// end of each window
+type Notifier struct {
+	calendar *Calendar
+	cfg      config.MaintenanceConfig
+	client   *http.Client
+	nodeID   string
+	logger   *logger.Logger
+
+	announced map[string]time.Time // Window name -> start of the occurrence announced
+	active    map[string]Window    // Open windows by name, notified as started
+}
+
+// NewNotifier creates a notifier for the calendar's windows
+func NewNotifier(calendar *Calendar, cfg config.MaintenanceConfig, nodeID string) *Notifier {
+	return &Notifier{
+		calendar:  calendar,
+		cfg:       cfg,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		nodeID:    nodeID,
+		logger:    logger.WithField("component", "maintenance"),
+		announced: make(map[string]time.Time),
+		active:    make(map[string]Window),
+	}
+}
+
+// Run checks the windows until ctx is done
+func (n *Notifier) Run(ctx context.Context) {
+	n.logger.Info("maintenance windows configured", "windows", len(n.calendar.windows),
+		"noticeBefore", n.cfg.NoticeBefore, "webhook", n.cfg.WebhookURL != "")
+
+	n.Check(ctx, time.Now())
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n.Check(ctx, now)
+		}
+	}
+}
+
+// Notification is the JSON body POSTed to the webhook
+type Notification struct {
+	Event  string `json:"event"`
+	Window Window `json:"window"`
+	NodeID string `json:"nodeId,omitempty"`
+}
+
+// Check sends the notifications due at now and returns them. Each occurrence
+// of a window is announced, started and ended once.
+func (n *Notifier) Check(ctx context.Context, now time.Time) []Notification {
+	var due []Notification
+	for _, r := range n.calendar.windows {
+		open, isOpen := r.openAt(now)
+		if prev, was := n.active[r.name]; was && (!isOpen || !prev.Start.Equal(open.Start)) {
+			delete(n.active, r.name)
+			due = append(due, Notification{Event: EventEnded, Window: prev})
+		}
+		if isOpen {
+			if _, was := n.active[r.name]; !was {
+				n.active[r.name] = open
+				n.announced[r.name] = open.Start
+				due = append(due, Notification{Event: EventStarted, Window: open})
+			}
+		}
+
+		next := r.schedule.Next(now)
+		if !next.IsZero() && next.Sub(now) <= n.cfg.NoticeBefore && !n.announced[r.name].Equal(next) {
+			n.announced[r.name] = next
+			due = append(due, Notification{Event: EventUpcoming, Window: Window{Name: r.name, Start: next, End: next.Add(r.duration)}})
+		}
+	}
+
+	for i := range due {
+		due[i].NodeID = n.nodeID
+		w := due[i].Window
+		switch due[i].Event {
+		case EventUpcoming:
+			n.logger.Warn("maintenance window coming up, no job will start during it", "window", w.Name,
+				"start", w.Start, "end", w.End)
+		case EventStarted:
+			n.logger.Warn("maintenance window started, new jobs are queued until it ends", "window", w.Name, "end", w.End)
+		case EventEnded:
+			n.logger.Info("maintenance window ended", "window", w.Name)
+		}
+		if n.cfg.WebhookURL != "" {
+			if err := n.notify(ctx, due[i]); err != nil {
+				n.logger.Warn("failed to send maintenance notification", "event", due[i].Event, "window", w.Name, "error", err)
+			}
+		}
+	}
+	return due
+}
+
+// notify POSTs a notification to the webhook as JSON
+func (n *Notifier) notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
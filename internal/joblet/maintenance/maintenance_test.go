@@ -0,0 +1,121 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// sundayPatching opens every Sunday at 03:00 for two hours
+var sundayPatching = config.MaintenanceConfig{
+	Windows:      []config.MaintenanceWindow{{Name: "patching", Schedule: "0 3 * * 0", Duration: 2 * time.Hour}},
+	NoticeBefore: 15 * time.Minute,
+}
+
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestCalendarActive(t *testing.T) {
+	calendar, err := NewCalendar(sundayPatching)
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+
+	// 2026-10-18 is a Sunday
+	tests := []struct {
+		now  string
+		open bool
+	}{
+		{"2026-10-18 02:59:59", false},
+		{"2026-10-18 03:00:00", true},
+		{"2026-10-18 04:59:59", true},
+		{"2026-10-18 05:00:00", false},
+		{"2026-10-19 03:30:00", false},
+	}
+	for _, tt := range tests {
+		window, open := calendar.Active(at(t, tt.now))
+		if open != tt.open {
+			t.Errorf("Active(%s) = %v, want %v", tt.now, open, tt.open)
+			continue
+		}
+		if open && (window.Name != "patching" || !window.End.Equal(at(t, "2026-10-18 05:00:00"))) {
+			t.Errorf("Active(%s) window = %+v", tt.now, window)
+		}
+	}
+}
+
+func TestCalendarWithoutWindows(t *testing.T) {
+	calendar, err := NewCalendar(config.MaintenanceConfig{})
+	if err != nil || calendar != nil {
+		t.Fatalf("NewCalendar() = %v, %v, want nil calendar", calendar, err)
+	}
+	if _, open := calendar.Active(time.Now()); open {
+		t.Error("nil calendar reported an open window")
+	}
+}
+
+func TestNotifierCheck(t *testing.T) {
+	var mu sync.Mutex
+	var received []Notification
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		mu.Lock()
+		received = append(received, n)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	cfg := sundayPatching
+	cfg.WebhookURL = webhook.URL
+	calendar, err := NewCalendar(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := NewNotifier(calendar, cfg, "node-1")
+
+	steps := []struct {
+		now  string
+		want []string
+	}{
+		{"2026-10-18 02:30:00", nil},
+		{"2026-10-18 02:45:00", []string{EventUpcoming}},
+		{"2026-10-18 02:50:00", nil},
+		{"2026-10-18 03:00:30", []string{EventStarted}},
+		{"2026-10-18 04:00:00", nil},
+		{"2026-10-18 05:00:30", []string{EventEnded}},
+		{"2026-10-18 05:01:00", nil},
+	}
+	for _, step := range steps {
+		var events []string
+		for _, n := range notifier.Check(context.Background(), at(t, step.now)) {
+			events = append(events, n.Event)
+		}
+		if len(events) != len(step.want) || (len(events) > 0 && events[0] != step.want[0]) {
+			t.Errorf("Check(%s) = %v, want %v", step.now, events, step.want)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 3 {
+		t.Fatalf("webhook received %d notifications, want 3", len(received))
+	}
+	if received[0].NodeID != "node-1" || received[0].Window.Name != "patching" {
+		t.Errorf("notification = %+v", received[0])
+	}
+}
@@ -17,7 +17,7 @@ import (
 )
 
 // Collector gathers resource usage metrics for a job by reading cgroup statistics.
-// It periodically samples CPU, memory, I/O, and GPU metrics, then publishes them
+// It periodically samples CPU, memory, I/O, network, and GPU metrics, then publishes them
 // for real-time streaming and persistence.
 type Collector struct {
 	jobID          string
@@ -26,10 +26,19 @@ type Collector struct {
 	limits         *domain.ResourceLimits
 	gpuIndices     []int
 
+	// procRoot is where the processes of the job are read from, for the
+	// counters of its network namespace
+	procRoot string
+
 	// We keep the previous sample around to calculate rates like bytes/sec and IOPS
 	previousSample *domain.JobMetricsSample
 	previousTime   time.Time
 
+	// Network activity over the samples taken so far (nil until the job's
+	// namespace was sampled)
+	networkSummary *domain.NetworkSummary
+	networkMutex   sync.Mutex
+
 	// Lifecycle management - context for graceful shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -63,6 +72,7 @@ func NewCollector(
 		sampleInterval:   sampleInterval,
 		limits:           limits,
 		gpuIndices:       gpuIndices,
+		procRoot:         "/proc",
 		ctx:              ctx,
 		cancel:           cancel,
 		metricsPublisher: publisher,
@@ -127,6 +137,42 @@ func (c *Collector) collectAndPublish() {
 	// Store for next rate calculation
 	c.previousSample = sample
 	c.previousTime = sample.Timestamp
+
+	if sample.Network != nil {
+		c.recordNetworkSummary(sample.Network)
+	}
+}
+
+// recordNetworkSummary folds a network sample into the job's summary
+func (c *Collector) recordNetworkSummary(network *domain.NetworkMetrics) {
+	c.networkMutex.Lock()
+	defer c.networkMutex.Unlock()
+
+	peak := network.ActiveConnections
+	if c.networkSummary != nil && c.networkSummary.PeakConnections > peak {
+		peak = c.networkSummary.PeakConnections
+	}
+	c.networkSummary = &domain.NetworkSummary{
+		RxBytes:         network.TotalRxBytes,
+		TxBytes:         network.TotalTxBytes,
+		RxPackets:       network.TotalRxPackets,
+		TxPackets:       network.TotalTxPackets,
+		PeakConnections: peak,
+		Retransmits:     network.Retransmits,
+	}
+}
+
+// NetworkSummary returns the network activity of the job so far, nil when
+// its namespace was never sampled (e.g. the job shares the host's network)
+func (c *Collector) NetworkSummary() *domain.NetworkSummary {
+	c.networkMutex.Lock()
+	defer c.networkMutex.Unlock()
+
+	if c.networkSummary == nil {
+		return nil
+	}
+	summary := *c.networkSummary
+	return &summary
 }
 
 // CollectSample collects a single metrics sample
@@ -174,6 +220,14 @@ func (c *Collector) CollectSample() (*domain.JobMetricsSample, error) {
 	}
 	sample.Process = *procMetrics
 
+	// Collect network metrics from the job's network namespace
+	netMetrics, err := c.collectNetworkMetrics()
+	if err != nil {
+		c.logger.Debug("failed to collect network metrics", "error", err)
+	} else {
+		sample.Network = netMetrics
+	}
+
 	// Collect GPU metrics if GPU allocated
 	if len(c.gpuIndices) > 0 {
 		gpuMetrics, err := c.collectGPUMetrics()
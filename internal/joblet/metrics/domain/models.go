@@ -140,11 +140,26 @@ type NetworkMetrics struct {
 	TotalRxDropped uint64 `json:"total_rx_dropped"`
 	TotalTxDropped uint64 `json:"total_tx_dropped"`
 
+	// TCP state of the job's network namespace
+	ActiveConnections uint64 `json:"active_connections"` // Established TCP connections (IPv4 and IPv6)
+	Retransmits       uint64 `json:"retransmits"`        // TCP segments retransmitted since the namespace was created
+
 	// Calculated rates
 	RxBPS float64 `json:"rx_bps"` // Receive bandwidth (bytes/sec)
 	TxBPS float64 `json:"tx_bps"` // Transmit bandwidth (bytes/sec)
 }
 
+// NetworkSummary sums up the network activity of a job's namespace over its
+// run, from the last sample taken while the job was running
+type NetworkSummary struct {
+	RxBytes         uint64 `json:"rxBytes"`
+	TxBytes         uint64 `json:"txBytes"`
+	RxPackets       uint64 `json:"rxPackets"`
+	TxPackets       uint64 `json:"txPackets"`
+	PeakConnections uint64 `json:"peakConnections"` // Most established TCP connections in a sample
+	Retransmits     uint64 `json:"retransmits"`     // TCP segments retransmitted
+}
+
 // NetworkInterfaceMetrics contains per-interface network statistics
 type NetworkInterfaceMetrics struct {
 	Interface string `json:"interface"`
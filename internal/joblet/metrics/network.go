@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/metrics/domain"
+)
+
+// collectNetworkMetrics reads the counters of the job's network namespace
+// through one of its processes: traffic per interface from net/dev and the
+// TCP connections and retransmits from net/snmp. Jobs sharing the host's
+// namespace have no counters of their own, so none are reported for them.
+func (c *Collector) collectNetworkMetrics() (*domain.NetworkMetrics, error) {
+	pid, err := c.jobPID()
+	if err != nil {
+		return nil, err
+	}
+
+	jobNS, err := os.Readlink(filepath.Join(c.procRoot, strconv.Itoa(pid), "ns", "net"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the network namespace of pid %d: %w", pid, err)
+	}
+	if hostNS, err := os.Readlink(filepath.Join(c.procRoot, "self", "ns", "net")); err == nil && hostNS == jobNS {
+		return nil, nil
+	}
+
+	netDir := filepath.Join(c.procRoot, strconv.Itoa(pid), "net")
+	dev, err := os.ReadFile(filepath.Join(netDir, "dev"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read net/dev: %w", err)
+	}
+	metrics := parseNetDev(string(dev))
+
+	if snmp, err := os.ReadFile(filepath.Join(netDir, "snmp")); err == nil {
+		tcp := parseSNMPTcp(string(snmp))
+		metrics.ActiveConnections = tcp["CurrEstab"]
+		metrics.Retransmits = tcp["RetransSegs"]
+	}
+
+	// Counters are cumulative, so bandwidth is the delta since the previous sample
+	if c.previousSample != nil && c.previousSample.Network != nil && c.previousTime.Before(time.Now()) {
+		timeDelta := time.Since(c.previousTime).Seconds()
+		previous := c.previousSample.Network
+		if timeDelta > 0 && metrics.TotalRxBytes >= previous.TotalRxBytes && metrics.TotalTxBytes >= previous.TotalTxBytes {
+			metrics.RxBPS = float64(metrics.TotalRxBytes-previous.TotalRxBytes) / timeDelta
+			metrics.TxBPS = float64(metrics.TotalTxBytes-previous.TotalTxBytes) / timeDelta
+		}
+	}
+
+	return metrics, nil
+}
+
+// jobPID returns a process of the job from its cgroup
+func (c *Collector) jobPID() (int, error) {
+	data, err := os.ReadFile(filepath.Join(c.cgroupPath, "cgroup.procs"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cgroup.procs: %w", err)
+	}
+	for _, line := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(line); err == nil && pid > 0 {
+			return pid, nil
+		}
+	}
+	return 0, fmt.Errorf("no process left in the cgroup")
+}
+
+// parseNetDev parses /proc/<pid>/net/dev into per-interface and total
+// counters, leaving out the loopback interface
+func parseNetDev(data string) *domain.NetworkMetrics {
+	metrics := &domain.NetworkMetrics{
+		Interfaces: make(map[string]*domain.NetworkInterfaceMetrics),
+	}
+
+	// The first two lines are headers; each interface line is
+	// "name: rx bytes packets errs drop fifo frame compressed multicast tx bytes packets errs drop ..."
+	for _, line := range strings.Split(data, "\n") {
+		name, counters, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "lo" {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 12 {
+			continue
+		}
+		value := func(i int) uint64 {
+			v, _ := strconv.ParseUint(fields[i], 10, 64)
+			return v
+		}
+
+		iface := &domain.NetworkInterfaceMetrics{
+			Interface: name,
+			RxBytes:   value(0),
+			RxPackets: value(1),
+			RxErrors:  value(2),
+			RxDropped: value(3),
+			TxBytes:   value(8),
+			TxPackets: value(9),
+			TxErrors:  value(10),
+			TxDropped: value(11),
+		}
+		metrics.Interfaces[name] = iface
+
+		metrics.TotalRxBytes += iface.RxBytes
+		metrics.TotalTxBytes += iface.TxBytes
+		metrics.TotalRxPackets += iface.RxPackets
+		metrics.TotalTxPackets += iface.TxPackets
+		metrics.TotalRxErrors += iface.RxErrors
+		metrics.TotalTxErrors += iface.TxErrors
+		metrics.TotalRxDropped += iface.RxDropped
+		metrics.TotalTxDropped += iface.TxDropped
+	}
+
+	return metrics
+}
+
+// parseSNMPTcp returns the Tcp counters of /proc/<pid>/net/snmp by name. The
+// file pairs a line of names with a line of values for each protocol; the
+// kernel keeps a single set of TCP counters for IPv4 and IPv6.
+func parseSNMPTcp(data string) map[string]uint64 {
+	counters := make(map[string]uint64)
+	var names []string
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "Tcp:" {
+			continue
+		}
+		if names == nil {
+			names = fields[1:]
+			continue
+		}
+		for i, field := range fields[1:] {
+			if i < len(names) {
+				// MaxConn is -1, which no counter we read is
+				counters[names[i]], _ = strconv.ParseUint(field, 10, 64)
+			}
+		}
+		break
+	}
+	return counters
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    5000      50    0    0    0     0          0         0     5000      50    0    0    0     0       0          0
+veth-p-f47ac10b: 2000000    1500    1    2    0     0          0         0   300000     900    3    4    0     0       0          0
+`
+
+const testSNMP = `Ip: Forwarding DefaultTTL InReceives
+Ip: 1 64 1500
+Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens PassiveOpens AttemptFails EstabResets CurrEstab InSegs OutSegs RetransSegs InErrs OutRsts InCsumErrors
+Tcp: 1 200 120000 -1 12 0 0 1 3 1400 880 17 0 2 0
+Udp: InDatagrams NoPorts InErrors OutDatagrams
+Udp: 10 0 0 10
+`
+
+func TestParseNetDev(t *testing.T) {
+	metrics := parseNetDev(testNetDev)
+	if _, exists := metrics.Interfaces["lo"]; exists || len(metrics.Interfaces) != 1 {
+		t.Fatalf("interfaces = %v, want the veth only", metrics.Interfaces)
+	}
+	veth := metrics.Interfaces["veth-p-f47ac10b"]
+	if veth.RxBytes != 2000000 || veth.RxPackets != 1500 || veth.RxErrors != 1 || veth.RxDropped != 2 ||
+		veth.TxBytes != 300000 || veth.TxPackets != 900 || veth.TxErrors != 3 || veth.TxDropped != 4 {
+		t.Errorf("veth = %+v", veth)
+	}
+	if metrics.TotalRxBytes != 2000000 || metrics.TotalTxPackets != 900 {
+		t.Errorf("totals = %+v", metrics)
+	}
+}
+
+func TestParseSNMPTcp(t *testing.T) {
+	tcp := parseSNMPTcp(testSNMP)
+	if tcp["CurrEstab"] != 3 || tcp["RetransSegs"] != 17 || tcp["OutSegs"] != 880 {
+		t.Errorf("parseSNMPTcp = %v", tcp)
+	}
+	if len(parseSNMPTcp("Tcp: CurrEstab\n")) != 0 {
+		t.Error("parseSNMPTcp read counters without a line of values")
+	}
+}
+
+// fakeProc lays out the /proc entries the network collector reads for a job
+// process, pid 4242, in netNS
+func fakeProc(t *testing.T, netNS string) (procRoot, cgroupPath string) {
+	t.Helper()
+	procRoot, cgroupPath = t.TempDir(), t.TempDir()
+	for _, dir := range []string{"self/ns", "4242/ns", "4242/net"} {
+		if err := os.MkdirAll(filepath.Join(procRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{"self/ns/net": "net:[4026531840]", "4242/ns/net": netNS}
+	for path, target := range links {
+		if err := os.Symlink(target, filepath.Join(procRoot, path)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(procRoot, "4242/net/dev"):   testNetDev,
+		filepath.Join(procRoot, "4242/net/snmp"):  testSNMP,
+		filepath.Join(cgroupPath, "cgroup.procs"): "4242\n4243\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return procRoot, cgroupPath
+}
+
+func TestCollectNetworkMetrics(t *testing.T) {
+	procRoot, cgroupPath := fakeProc(t, "net:[4026532999]")
+	c := NewCollector("f47ac10b-58cc-4372-a567-0e02b2c3d479", cgroupPath, time.Second, nil, nil, nil)
+	c.procRoot = procRoot
+
+	sample, err := c.CollectSample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	network := sample.Network
+	if network == nil || network.TotalRxBytes != 2000000 || network.ActiveConnections != 3 || network.Retransmits != 17 {
+		t.Fatalf("network = %+v", network)
+	}
+	if network.RxBPS != 0 {
+		t.Errorf("first sample has a bandwidth of %f", network.RxBPS)
+	}
+
+	// Bandwidth is the delta since the previous sample
+	c.previousSample = sample
+	c.previousTime = time.Now().Add(-2 * time.Second)
+	sample.Network = parseNetDev(testNetDev)
+	sample.Network.TotalRxBytes -= 1000000
+	sample.Network.ActiveConnections = 8
+	c.recordNetworkSummary(sample.Network)
+	next, _ := c.CollectSample()
+	if next.Network.RxBPS < 400000 || next.Network.RxBPS > 500000 {
+		t.Errorf("RxBPS = %f, want about 500000", next.Network.RxBPS)
+	}
+
+	c.recordNetworkSummary(next.Network)
+	summary := c.NetworkSummary()
+	if summary == nil || summary.RxBytes != 2000000 || summary.PeakConnections != 8 || summary.Retransmits != 17 {
+		t.Errorf("NetworkSummary = %+v", summary)
+	}
+}
+
+func TestCollectNetworkMetricsHostNetwork(t *testing.T) {
+	procRoot, cgroupPath := fakeProc(t, "net:[4026531840]")
+	c := NewCollector("f47ac10b-58cc-4372-a567-0e02b2c3d479", cgroupPath, time.Second, nil, nil, nil)
+	c.procRoot = procRoot
+
+	sample, err := c.CollectSample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample.Network != nil || c.NetworkSummary() != nil {
+		t.Errorf("the job shares the host's network but reports %+v", sample.Network)
+	}
+}
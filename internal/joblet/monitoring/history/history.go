@@ -0,0 +1,238 @@
+// Package history records host metrics to persist and reads them back, so
+// 'rnx monitor history' can show what a node looked like in the past.
+//
+// Samples are stored as metrics of one persist series per UTC day, named
+// node-metrics-YYYYMMDD. Job IDs are UUIDs, so the series never collide with
+// job metrics, and expiring a day is a single persist DeleteJob call.
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/monitoring/domain"
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+const (
+	seriesPrefix = "node-metrics-"
+	seriesLayout = "20060102"
+
+	// pruneInterval is how often expired days are looked for
+	pruneInterval = time.Hour
+	// pruneLookback is how many expired days the first prune removes,
+	// covering days that expired while joblet was down
+	pruneLookback = 30
+)
+
+// SeriesID names the persist series holding the samples of t's UTC day
+func SeriesID(t time.Time) string {
+	return seriesPrefix + t.UTC().Format(seriesLayout)
+}
+
+// day returns the start of t's UTC day
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Source provides the latest host metrics
+type Source interface {
+	GetLatestMetrics() *domain.SystemMetrics
+}
+
+// Writer sends metric samples to persist
+type Writer interface {
+	WriteMetric(jobID string, timestamp int64, sequence uint64, data *ipcpb.MetricData) error
+}
+
+// Recorder samples the host every interval and removes the days that fell
+// out of retention
+type Recorder struct {
+	cfg     config.MonitoringHistoryConfig
+	source  Source
+	writer  Writer
+	persist persistpb.PersistServiceClient // nil: expired days are kept
+	logger  *logger.Logger
+
+	sequence   uint64
+	lastSample time.Time
+	prunedTo   time.Time // Newest day removed, zero before the first prune
+}
+
+// NewRecorder creates a recorder. persist may be nil, in which case samples
+// are recorded but never expire.
+func NewRecorder(cfg config.MonitoringHistoryConfig, source Source, writer Writer, persist persistpb.PersistServiceClient) *Recorder {
+	return &Recorder{
+		cfg:     cfg,
+		source:  source,
+		writer:  writer,
+		persist: persist,
+		logger:  logger.WithField("component", "monitor-history"),
+	}
+}
+
+// Run records a sample every interval until ctx is done
+func (r *Recorder) Run(ctx context.Context) {
+	r.logger.Info("recording host metrics history", "interval", r.cfg.Interval, "retention", r.cfg.Retention)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	for {
+		now := time.Now()
+		if err := r.Record(); err != nil {
+			r.logger.Debug("host metrics sample not recorded", "error", err)
+		}
+		if now.Sub(lastPrune) >= pruneInterval {
+			if err := r.Prune(ctx, now); err != nil {
+				r.logger.Warn("failed to remove expired host metrics", "error", err)
+			}
+			lastPrune = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Record writes the latest host metrics as a sample. A snapshot already
+// recorded is skipped, so a monitoring interval longer than the history
+// interval does not store duplicates.
+func (r *Recorder) Record() error {
+	metrics := r.source.GetLatestMetrics()
+	if metrics == nil || metrics.Timestamp.IsZero() {
+		return fmt.Errorf("no host metrics collected yet")
+	}
+	if metrics.Timestamp.Equal(r.lastSample) {
+		return nil
+	}
+	if err := r.writer.WriteMetric(SeriesID(metrics.Timestamp), metrics.Timestamp.UnixNano(), r.sequence, toMetricData(metrics)); err != nil {
+		return err
+	}
+	r.sequence++
+	r.lastSample = metrics.Timestamp
+	return nil
+}
+
+// Prune removes the days that ended more than the retention before now.
+// The first prune looks pruneLookback days back, later ones only remove the
+// days expired since.
+func (r *Recorder) Prune(ctx context.Context, now time.Time) error {
+	if r.persist == nil {
+		return nil
+	}
+	newest := day(now.Add(-r.cfg.Retention)).AddDate(0, 0, -1)
+	oldest := newest.AddDate(0, 0, 1-pruneLookback)
+	if !r.prunedTo.IsZero() {
+		oldest = r.prunedTo.AddDate(0, 0, 1)
+	}
+
+	for d := oldest; !d.After(newest); d = d.AddDate(0, 0, 1) {
+		resp, err := r.persist.DeleteJob(ctx, &persistpb.DeleteJobRequest{JobId: SeriesID(d)})
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return fmt.Errorf("%s: %s", SeriesID(d), resp.Message)
+		}
+		r.prunedTo = d
+	}
+	return nil
+}
+
+// toMetricData stores a host snapshot in the job metric format: CPU as busy
+// cores, and cumulative disk and network counters
+func toMetricData(m *domain.SystemMetrics) *ipcpb.MetricData {
+	data := &ipcpb.MetricData{
+		CpuUsage:    m.CPU.UsagePercent / 100 * float64(m.CPU.Cores),
+		MemoryUsage: int64(m.Memory.UsedBytes),
+		DiskIo: &ipcpb.DiskIO{
+			ReadBytes:  int64(m.IO.ReadBytes),
+			WriteBytes: int64(m.IO.WriteBytes),
+			ReadOps:    int64(m.IO.ReadsCompleted),
+			WriteOps:   int64(m.IO.WritesCompleted),
+		},
+		NetworkIo: &ipcpb.NetworkIO{},
+	}
+	for _, iface := range m.Network {
+		data.NetworkIo.RxBytes += int64(iface.BytesReceived)
+		data.NetworkIo.TxBytes += int64(iface.BytesSent)
+		data.NetworkIo.RxPackets += int64(iface.PacketsReceived)
+		data.NetworkIo.TxPackets += int64(iface.PacketsSent)
+	}
+	return data
+}
+
+// Query sends the samples recorded between start and end to send, oldest
+// first. Days without samples are skipped.
+func Query(ctx context.Context, persist persistpb.PersistServiceClient, start, end time.Time, send func(*nodemetricspb.NodeMetricsSample) error) error {
+	var previous *persistpb.Metric
+	for d := day(start); !d.After(end); d = d.AddDate(0, 0, 1) {
+		stream, err := persist.QueryMetrics(ctx, &persistpb.QueryMetricsRequest{
+			JobId:     SeriesID(d),
+			StartTime: start.UnixNano(),
+			EndTime:   end.UnixNano(),
+		})
+		if err != nil {
+			return err
+		}
+		received := 0
+		for {
+			metric, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				// persist fails queries of series that were never written
+				if received == 0 {
+					break
+				}
+				return err
+			}
+			received++
+			if err := send(toSample(metric, previous)); err != nil {
+				return err
+			}
+			previous = metric
+		}
+	}
+	return nil
+}
+
+// toSample converts a stored sample, turning the counters into rates since
+// previous. Counters that went down, as after a reboot, give zero rates.
+func toSample(metric, previous *persistpb.Metric) *nodemetricspb.NodeMetricsSample {
+	data := metric.GetData()
+	sample := &nodemetricspb.NodeMetricsSample{
+		Timestamp:       metric.Timestamp,
+		CpuCores:        data.GetCpuUsage(),
+		MemoryUsedBytes: data.GetMemoryUsage(),
+	}
+	if previous == nil || metric.Timestamp <= previous.Timestamp {
+		return sample
+	}
+	seconds := float64(metric.Timestamp-previous.Timestamp) / float64(time.Second)
+	rate := func(current, before int64) float64 {
+		if current < before {
+			return 0
+		}
+		return float64(current-before) / seconds
+	}
+	before := previous.GetData()
+	sample.DiskReadBps = rate(data.GetDiskIo().GetReadBytes(), before.GetDiskIo().GetReadBytes())
+	sample.DiskWriteBps = rate(data.GetDiskIo().GetWriteBytes(), before.GetDiskIo().GetWriteBytes())
+	sample.NetworkRxBps = rate(data.GetNetworkIo().GetRxBytes(), before.GetNetworkIo().GetRxBytes())
+	sample.NetworkTxBps = rate(data.GetNetworkIo().GetTxBytes(), before.GetNetworkIo().GetTxBytes())
+	return sample
+}
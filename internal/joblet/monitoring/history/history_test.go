@@ -0,0 +1,176 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/monitoring/domain"
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+)
+
+type fakeSource struct{ metrics *domain.SystemMetrics }
+
+func (f *fakeSource) GetLatestMetrics() *domain.SystemMetrics { return f.metrics }
+
+type writtenMetric struct {
+	series string
+	data   *ipcpb.MetricData
+}
+
+type fakeWriter struct{ written []writtenMetric }
+
+func (f *fakeWriter) WriteMetric(jobID string, _ int64, _ uint64, data *ipcpb.MetricData) error {
+	f.written = append(f.written, writtenMetric{jobID, data})
+	return nil
+}
+
+// fakePersist keeps series in memory; series missing from it fail like
+// persist does for series that were never written
+type fakePersist struct {
+	persistpb.PersistServiceClient
+	series  map[string][]*persistpb.Metric
+	deleted []string
+}
+
+type fakeMetricStream struct {
+	grpc.ClientStream
+	metrics []*persistpb.Metric
+	err     error
+}
+
+func (s *fakeMetricStream) Recv() (*persistpb.Metric, error) {
+	if len(s.metrics) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	metric := s.metrics[0]
+	s.metrics = s.metrics[1:]
+	return metric, nil
+}
+
+func (f *fakePersist) QueryMetrics(_ context.Context, req *persistpb.QueryMetricsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[persistpb.Metric], error) {
+	metrics, exists := f.series[req.JobId]
+	if !exists {
+		return &fakeMetricStream{err: errors.New("no metrics found for job " + req.JobId)}, nil
+	}
+	var selected []*persistpb.Metric
+	for _, metric := range metrics {
+		if metric.Timestamp >= req.StartTime && metric.Timestamp <= req.EndTime {
+			selected = append(selected, metric)
+		}
+	}
+	return &fakeMetricStream{metrics: selected}, nil
+}
+
+func (f *fakePersist) DeleteJob(_ context.Context, req *persistpb.DeleteJobRequest, _ ...grpc.CallOption) (*persistpb.DeleteJobResponse, error) {
+	f.deleted = append(f.deleted, req.JobId)
+	return &persistpb.DeleteJobResponse{Success: true}, nil
+}
+
+func TestRecorder_Record(t *testing.T) {
+	at := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	source := &fakeSource{metrics: &domain.SystemMetrics{
+		Timestamp: at,
+		CPU:       domain.CPUMetrics{UsagePercent: 50, Cores: 8},
+		Memory:    domain.MemoryMetrics{UsedBytes: 1 << 30},
+		Network:   []domain.NetworkMetrics{{BytesReceived: 100}, {BytesReceived: 50, BytesSent: 7}},
+	}}
+	writer := &fakeWriter{}
+	r := NewRecorder(config.MonitoringHistoryConfig{Interval: time.Minute}, source, writer, nil)
+
+	for range 2 {
+		if err := r.Record(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The same snapshot is recorded once
+	if len(writer.written) != 1 {
+		t.Fatalf("wrote %d samples, want 1", len(writer.written))
+	}
+	got := writer.written[0]
+	if got.series != "node-metrics-20260301" || got.data.CpuUsage != 4 || got.data.MemoryUsage != 1<<30 ||
+		got.data.NetworkIo.RxBytes != 150 || got.data.NetworkIo.TxBytes != 7 {
+		t.Errorf("wrote %s %+v", got.series, got.data)
+	}
+
+	if err := NewRecorder(config.MonitoringHistoryConfig{}, &fakeSource{}, writer, nil).Record(); err == nil {
+		t.Error("Record() without metrics succeeded")
+	}
+}
+
+func TestRecorder_Prune(t *testing.T) {
+	persist := &fakePersist{}
+	r := NewRecorder(config.MonitoringHistoryConfig{Interval: time.Minute, Retention: 48 * time.Hour}, &fakeSource{}, &fakeWriter{}, persist)
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	if err := r.Prune(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	// The cutoff is March 8 noon, so March 7 is the newest day fully expired
+	if len(persist.deleted) != pruneLookback || persist.deleted[len(persist.deleted)-1] != "node-metrics-20260307" {
+		t.Fatalf("first prune deleted %d days ending with %v", len(persist.deleted), persist.deleted[len(persist.deleted)-1])
+	}
+
+	persist.deleted = nil
+	if err := r.Prune(context.Background(), now.Add(time.Hour)); err != nil || len(persist.deleted) != 0 {
+		t.Errorf("prune within the same day deleted %v, %v", persist.deleted, err)
+	}
+	if err := r.Prune(context.Background(), now.Add(24*time.Hour)); err != nil || !slices.Equal(persist.deleted, []string{"node-metrics-20260308"}) {
+		t.Errorf("prune a day later deleted %v, %v", persist.deleted, err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	at := func(day, hour int) int64 { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC).UnixNano() }
+	metric := func(ts int64, cores float64, readBytes, rxBytes int64) *persistpb.Metric {
+		return &persistpb.Metric{Timestamp: ts, Data: &persistpb.MetricData{
+			CpuUsage:  cores,
+			DiskIo:    &persistpb.DiskIO{ReadBytes: readBytes},
+			NetworkIo: &persistpb.NetworkIO{RxBytes: rxBytes},
+		}}
+	}
+	persist := &fakePersist{series: map[string][]*persistpb.Metric{
+		"node-metrics-20260301": {metric(at(1, 22), 1, 0, 0), metric(at(1, 23), 2, 3600, 7200)},
+		// March 2 was never written
+		"node-metrics-20260303": {metric(at(3, 1), 3, 3600, 0)},
+	}}
+
+	var samples []*nodemetricspb.NodeMetricsSample
+	err := Query(context.Background(), persist, time.Unix(0, at(1, 23)), time.Unix(0, at(3, 2)), func(s *nodemetricspb.NodeMetricsSample) error {
+		samples = append(samples, s)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("got %d samples, want 2", len(samples))
+	}
+	// The first sample in range has no rates, and the counter reset gives zero
+	if samples[0].CpuCores != 2 || samples[0].DiskReadBps != 0 {
+		t.Errorf("first sample = %+v", samples[0])
+	}
+	if samples[1].CpuCores != 3 || samples[1].DiskReadBps != 0 || samples[1].NetworkRxBps != 0 {
+		t.Errorf("second sample = %+v", samples[1])
+	}
+
+	samples = nil
+	_ = Query(context.Background(), persist, time.Unix(0, at(1, 0)), time.Unix(0, at(1, 23)), func(s *nodemetricspb.NodeMetricsSample) error {
+		samples = append(samples, s)
+		return nil
+	})
+	if len(samples) != 2 || samples[1].DiskReadBps != 1 || samples[1].NetworkRxBps != 2 {
+		t.Errorf("rates over an hour = %+v", samples)
+	}
+}
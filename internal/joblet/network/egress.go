@@ -0,0 +1,126 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+)
+
+// egressTable is the nftables table holding a job's egress policy. It lives
+// in the job's own network namespace and goes away with it.
+const egressTable = "joblet_egress"
+
+// egressResolveTimeout bounds resolving the host names of an egress policy
+const egressResolveTimeout = 10 * time.Second
+
+// EgressResolver returns the addresses of a host name an egress rule names
+type EgressResolver func(ctx context.Context, host string) ([]net.IP, error)
+
+// EgressRuleset renders an egress policy as an nftables script for the job's
+// network namespace. Its output chain keeps loopback traffic and replies to
+// connections made to the job, then matches the policy's rules in order:
+// allowed destinations are accepted and denied ones rejected, so the job
+// fails fast instead of timing out. Host names are resolved with resolve.
+func EgressRuleset(ctx context.Context, rules []values.EgressRule, resolve EgressResolver) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table inet %s {\n", egressTable)
+	b.WriteString("\tchain output {\n")
+	b.WriteString("\t\ttype filter hook output priority 0; policy accept;\n")
+	b.WriteString("\t\toifname \"lo\" accept\n")
+	b.WriteString("\t\tct state established,related accept\n")
+
+	for _, rule := range rules {
+		verdict := "reject"
+		if rule.Allow {
+			verdict = "accept"
+		}
+		ports := ""
+		if rule.FromPort > 0 {
+			ports = fmt.Sprintf("meta l4proto { tcp, udp } th dport %d ", rule.FromPort)
+			if rule.ToPort != rule.FromPort {
+				ports = fmt.Sprintf("meta l4proto { tcp, udp } th dport %d-%d ", rule.FromPort, rule.ToPort)
+			}
+		}
+
+		if rule.AnyHost() {
+			fmt.Fprintf(&b, "\t\t%s%s\n", ports, verdict)
+			continue
+		}
+		addrs, err := egressAddresses(ctx, rule, resolve)
+		if err != nil {
+			return "", err
+		}
+		var v4, v6 []string
+		for _, addr := range addrs {
+			if strings.Contains(addr, ":") {
+				v6 = append(v6, addr)
+			} else {
+				v4 = append(v4, addr)
+			}
+		}
+		if len(v4) > 0 {
+			fmt.Fprintf(&b, "\t\tip daddr { %s } %s%s\n", strings.Join(v4, ", "), ports, verdict)
+		}
+		if len(v6) > 0 {
+			fmt.Fprintf(&b, "\t\tip6 daddr { %s } %s%s\n", strings.Join(v6, ", "), ports, verdict)
+		}
+	}
+
+	b.WriteString("\t}\n}\n")
+	return b.String(), nil
+}
+
+// egressAddresses returns the addresses and networks a rule matches,
+// resolving the host name it names
+func egressAddresses(ctx context.Context, rule values.EgressRule, resolve EgressResolver) ([]string, error) {
+	if !rule.IsName() {
+		return []string{rule.Host}, nil
+	}
+	ips, err := resolve(ctx, rule.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s of egress rule %s: %w", rule.Host, rule, err)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%s of egress rule %s resolves to no address", rule.Host, rule)
+	}
+	addrs := make([]string, 0, len(ips))
+	seen := make(map[string]bool, len(ips))
+	for _, ip := range ips {
+		if addr := ip.String(); !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// ApplyEgressPolicy loads a job's egress policy into the network namespace
+// of its process with nft. Host names are resolved on the node, once, so the
+// job reaches the addresses they had when it started.
+func (ns *NetworkSetup) ApplyEgressPolicy(ctx context.Context, pid int, rules []values.EgressRule) error {
+	resolveCtx, cancel := context.WithTimeout(ctx, egressResolveTimeout)
+	defer cancel()
+	ruleset, err := EgressRuleset(resolveCtx, rules, func(ctx context.Context, host string) ([]net.IP, error) {
+		return net.DefaultResolver.LookupIP(ctx, "ip", host)
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd := ns.platform.CreateCommand("nsenter", fmt.Sprintf("--net=/proc/%d/ns/net", pid), "nft", "-f", "-")
+	cmd.SetStdin(strings.NewReader(ruleset))
+	var output bytes.Buffer
+	cmd.SetStdout(&output)
+	cmd.SetStderr(&output)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to load the egress policy with nft: %s: %s", err, output.String())
+	}
+
+	ns.logger.Debug("egress policy applied", "pid", pid, "rules", len(rules))
+	return nil
+}
@@ -0,0 +1,58 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+)
+
+func TestEgressRuleset(t *testing.T) {
+	rules, err := values.ParseEgressPolicy("allow:api.internal:443,allow:10.0.0.0/8:8000-8100,deny:[2001:db8::1],allow:*:53,deny:*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolve := func(_ context.Context, host string) ([]net.IP, error) {
+		if host != "api.internal" {
+			t.Errorf("resolved %s", host)
+		}
+		return []net.IP{net.ParseIP("10.1.0.5"), net.ParseIP("10.1.0.6"), net.ParseIP("10.1.0.5"), net.ParseIP("fd00::5")}, nil
+	}
+
+	ruleset, err := EgressRuleset(context.Background(), rules, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `table inet joblet_egress {
+	chain output {
+		type filter hook output priority 0; policy accept;
+		oifname "lo" accept
+		ct state established,related accept
+		ip daddr { 10.1.0.5, 10.1.0.6 } meta l4proto { tcp, udp } th dport 443 accept
+		ip6 daddr { fd00::5 } meta l4proto { tcp, udp } th dport 443 accept
+		ip daddr { 10.0.0.0/8 } meta l4proto { tcp, udp } th dport 8000-8100 accept
+		ip6 daddr { 2001:db8::1 } reject
+		meta l4proto { tcp, udp } th dport 53 accept
+		reject
+	}
+}
+`
+	if ruleset != want {
+		t.Errorf("EgressRuleset =\n%s\nwant\n%s", ruleset, want)
+	}
+}
+
+func TestEgressRulesetResolveFailure(t *testing.T) {
+	rules, _ := values.ParseEgressPolicy("allow:api.internal:443,deny:*")
+	for _, resolve := range []EgressResolver{
+		func(context.Context, string) ([]net.IP, error) { return nil, errors.New("no such host") },
+		func(context.Context, string) ([]net.IP, error) { return nil, nil },
+	} {
+		if _, err := EgressRuleset(context.Background(), rules, resolve); err == nil || !strings.Contains(err.Error(), "api.internal") {
+			t.Errorf("EgressRuleset error = %v", err)
+		}
+	}
+}
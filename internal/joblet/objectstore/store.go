@@ -0,0 +1,86 @@
+package objectstore
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Object store URL schemes
+const (
+	SchemeS3  = "s3"
+	SchemeGCS = "gs"
+)
+
+// Environment variables holding the AWS keys a job carries
+const (
+	envAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	envSessionToken    = "AWS_SESSION_TOKEN"
+)
+
+// bucketPattern accepts S3 and GCS bucket names
+var bucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,221}[a-z0-9]$`)
+
+// ValidBucket reports whether name is a valid S3 or GCS bucket name
+func ValidBucket(name string) bool {
+	return bucketPattern.MatchString(name)
+}
+
+// Store is an object store and the keys its requests are signed with
+type Store struct {
+	Endpoint string // Path-style endpoint, empty for AWS virtual-hosted style
+	Region   string
+	Creds    Credentials
+}
+
+// URL returns the URL of key in bucket
+func (s Store) URL(bucket, key string) string {
+	return ObjectURL(s.Endpoint, s.Region, bucket, key)
+}
+
+// JobStore returns the store a job's objects of scheme are read from and
+// written to. s3 uses the AWS keys in the job's secret environment, which
+// are its tenant's delegated credentials when the tenant has a role, else
+// the node's s3 settings, else the server's own environment. gs uses the
+// HMAC keys of the node's gcs settings.
+func JobStore(scheme string, s3, gcs config.S3SinkConfig, secretEnv map[string]string) (Store, error) {
+	switch scheme {
+	case SchemeGCS:
+		st := Store{
+			Endpoint: gcs.Endpoint,
+			Region:   "auto",
+			Creds:    Credentials{AccessKeyID: gcs.AccessKeyID, SecretAccessKey: gcs.SecretAccessKey},
+		}
+		if st.Endpoint == "" {
+			st.Endpoint = GCSEndpoint
+		}
+		if !st.Creds.Valid() {
+			return Store{}, fmt.Errorf("no GCS credentials: set the node's gcs HMAC keys")
+		}
+		return st, nil
+
+	case SchemeS3:
+		st := Store{Endpoint: s3.Endpoint, Region: s3.Region}
+		if st.Region == "" {
+			st.Region = "us-east-1"
+		}
+		st.Creds = Credentials{
+			AccessKeyID:     secretEnv[envAccessKeyID],
+			SecretAccessKey: secretEnv[envSecretAccessKey],
+			SessionToken:    secretEnv[envSessionToken],
+		}
+		if !st.Creds.Valid() {
+			st.Creds = Credentials{AccessKeyID: s3.AccessKeyID, SecretAccessKey: s3.SecretAccessKey, SessionToken: s3.SessionToken}
+		}
+		if !st.Creds.Valid() {
+			st.Creds = EnvCredentials()
+		}
+		if !st.Creds.Valid() {
+			return Store{}, fmt.Errorf("no S3 credentials: set the node's s3 keys, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or give the tenant an AWS role")
+		}
+		return st, nil
+	}
+	return Store{}, fmt.Errorf("unsupported object store scheme %q", scheme)
+}
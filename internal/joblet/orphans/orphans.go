@@ -0,0 +1,205 @@
+// Package orphans finds the kernel resources joblet creates for a job that
+// outlive it: the job's cgroup directory, the host end of its veth pair and a
+// network namespace mounted under its ID. They leak when joblet stops before
+// cleaning up, such as on a crash; the cleanup coordinator removes them on its
+// periodic pass and records the counts here.
+package orphans
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of an orphaned resource
+type Kind string
+
+const (
+	KindCgroup Kind = "cgroup"
+	KindVeth   Kind = "veth"
+	KindNetns  Kind = "netns"
+)
+
+// Kinds lists the kinds of resources in the order they are reported
+var Kinds = []Kind{KindCgroup, KindVeth, KindNetns}
+
+// Name prefixes of the resources of a job
+const (
+	cgroupPrefix = "job-"
+	scopeSuffix  = ".scope"  // Job cgroups are scopes with the systemd driver
+	vethPrefix   = "veth-h-" // Host end; deleting it deletes the pair
+	vethIDLength = 8         // Job ID characters in a veth name
+)
+
+// Resource is a resource left by a job no longer in the job store
+type Resource struct {
+	Kind  Kind   `json:"kind"`
+	Name  string `json:"name"`  // Directory, interface or namespace name
+	Path  string `json:"path"`  // Where the resource shows on the host
+	JobID string `json:"jobId"` // Job UUID, its first 8 characters for a veth
+}
+
+// Paths are where the resources of jobs show on the host
+type Paths struct {
+	CgroupDir string // Holds a job-<uuid> directory per job (cgroup.baseDir), or job-<uuid>.scope in the slice
+	NetDir    string // Network interfaces of the host
+	NetnsDir  string // Mounted network namespaces
+}
+
+// DefaultPaths returns the paths of the host for the cgroup base directory
+func DefaultPaths(cgroupDir string) Paths {
+	return Paths{CgroupDir: cgroupDir, NetDir: "/sys/class/net", NetnsDir: "/run/netns"}
+}
+
+// Find lists the resources of jobs that are not in known. Missing or
+// unreadable paths have nothing to report.
+func Find(paths Paths, known map[string]bool) []Resource {
+	prefixes := make(map[string]bool, len(known))
+	for jobID := range known {
+		if len(jobID) >= vethIDLength {
+			prefixes[jobID[:vethIDLength]] = true
+		}
+	}
+
+	var found []Resource
+	for _, name := range entries(paths.CgroupDir, true) {
+		jobID, ok := strings.CutPrefix(strings.TrimSuffix(name, scopeSuffix), cgroupPrefix)
+		if ok && IsJobID(jobID) && !known[jobID] {
+			found = append(found, Resource{Kind: KindCgroup, Name: name, Path: filepath.Join(paths.CgroupDir, name), JobID: jobID})
+		}
+	}
+	for _, name := range entries(paths.NetDir, false) {
+		if prefix, ok := strings.CutPrefix(name, vethPrefix); ok && isIDPrefix(prefix) && !prefixes[prefix] {
+			found = append(found, Resource{Kind: KindVeth, Name: name, Path: filepath.Join(paths.NetDir, name), JobID: prefix})
+		}
+	}
+	for _, name := range entries(paths.NetnsDir, false) {
+		jobID := strings.TrimPrefix(name, cgroupPrefix)
+		if IsJobID(jobID) && !known[jobID] {
+			found = append(found, Resource{Kind: KindNetns, Name: name, Path: filepath.Join(paths.NetnsDir, name), JobID: jobID})
+		}
+	}
+	return found
+}
+
+// entries returns the names in dir, only those of directories when dirsOnly
+// is set. Interfaces in /sys/class/net are symlinks, so they are not checked.
+func entries(dir string, dirsOnly bool) []string {
+	if dir == "" {
+		return nil
+	}
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(list))
+	for _, entry := range list {
+		if !dirsOnly || entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// IsJobID reports whether name has the form of a job UUID
+func IsJobID(name string) bool {
+	if len(name) != 36 {
+		return false
+	}
+	for i, r := range name {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !isHex(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isIDPrefix reports whether name has the form of the start of a job UUID
+// used in veth names
+func isIDPrefix(name string) bool {
+	if len(name) != vethIDLength {
+		return false
+	}
+	for _, r := range name {
+		if !isHex(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(r rune) bool {
+	return strings.ContainsRune("0123456789abcdef", r)
+}
+
+// Stats sums up the reconciliation passes since joblet started. A resource
+// that could not be removed is found again by the next pass.
+type Stats struct {
+	LastPass time.Time      `json:"lastPass"`
+	Orphans  []Resource     `json:"orphans"` // Left after the last pass, as removing them failed
+	Found    map[Kind]int64 `json:"found"`
+	Cleaned  map[Kind]int64 `json:"cleaned"`
+	Failed   map[Kind]int64 `json:"failed"`
+}
+
+// Lister is implemented by the joblets that reconcile orphaned resources
+type Lister interface {
+	OrphanedResources() Stats
+}
+
+// Tracker records the reconciliation passes; the zero value is ready to use
+// and it is safe for concurrent use
+type Tracker struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Record adds a pass at now that found the resources and failed to remove
+// those in failed
+func (t *Tracker) Record(now time.Time, found, failed []Resource) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stats.Found == nil {
+		t.stats.Found = make(map[Kind]int64)
+		t.stats.Cleaned = make(map[Kind]int64)
+		t.stats.Failed = make(map[Kind]int64)
+	}
+	t.stats.LastPass = now
+	for _, r := range found {
+		t.stats.Found[r.Kind]++
+		t.stats.Cleaned[r.Kind]++
+	}
+	for _, r := range failed {
+		t.stats.Cleaned[r.Kind]--
+		t.stats.Failed[r.Kind]++
+	}
+	t.stats.Orphans = append([]Resource(nil), failed...)
+}
+
+// Stats returns a copy of the recorded counts
+func (t *Tracker) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := Stats{
+		LastPass: t.stats.LastPass,
+		Orphans:  append([]Resource(nil), t.stats.Orphans...),
+		Found:    make(map[Kind]int64, len(Kinds)),
+		Cleaned:  make(map[Kind]int64, len(Kinds)),
+		Failed:   make(map[Kind]int64, len(Kinds)),
+	}
+	for _, kind := range Kinds {
+		stats.Found[kind] = t.stats.Found[kind]
+		stats.Cleaned[kind] = t.stats.Cleaned[kind]
+		stats.Failed[kind] = t.stats.Failed[kind]
+	}
+	return stats
+}
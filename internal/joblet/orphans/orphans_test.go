@@ -0,0 +1,120 @@
+package orphans
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	activeJob = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	goneJob   = "0b6c3a2e-9d41-4f8e-b5a7-2c1d0e9f8a76"
+)
+
+// fakeHost lays out the cgroup, interface and namespace entries of an active
+// job, a gone one and unrelated ones
+func fakeHost(t *testing.T) Paths {
+	t.Helper()
+	root := t.TempDir()
+	paths := Paths{
+		CgroupDir: filepath.Join(root, "cgroup"),
+		NetDir:    filepath.Join(root, "net"),
+		NetnsDir:  filepath.Join(root, "netns"),
+	}
+	dirs := []string{
+		filepath.Join(paths.CgroupDir, "job-"+activeJob),
+		filepath.Join(paths.CgroupDir, "job-"+goneJob),
+		filepath.Join(paths.CgroupDir, "joblet-main"),
+		filepath.Join(paths.NetDir, "lo"),
+		filepath.Join(paths.NetDir, "joblet0"),
+		filepath.Join(paths.NetDir, "veth-h-"+activeJob[:8]),
+		filepath.Join(paths.NetDir, "veth-h-"+goneJob[:8]),
+		filepath.Join(paths.NetDir, "viso1234"),
+		paths.NetnsDir,
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"job-" + goneJob, "blue", "job-" + activeJob} {
+		if err := os.WriteFile(filepath.Join(paths.NetnsDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file is not a cgroup
+	if err := os.WriteFile(filepath.Join(paths.CgroupDir, "job-"+goneJob[:35]+"0"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+func TestFindScopes(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"job-" + activeJob + ".scope", "job-" + goneJob + ".scope", "other.scope"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := Find(Paths{CgroupDir: dir}, map[string]bool{activeJob: true})
+	name := "job-" + goneJob + ".scope"
+	if len(found) != 1 || found[0] != (Resource{Kind: KindCgroup, Name: name, Path: filepath.Join(dir, name), JobID: goneJob}) {
+		t.Errorf("Find() = %+v", found)
+	}
+}
+
+func TestFind(t *testing.T) {
+	paths := fakeHost(t)
+
+	found := Find(paths, map[string]bool{activeJob: true})
+	want := []Resource{
+		{Kind: KindCgroup, Name: "job-" + goneJob, Path: filepath.Join(paths.CgroupDir, "job-"+goneJob), JobID: goneJob},
+		{Kind: KindVeth, Name: "veth-h-" + goneJob[:8], Path: filepath.Join(paths.NetDir, "veth-h-"+goneJob[:8]), JobID: goneJob[:8]},
+		{Kind: KindNetns, Name: "job-" + goneJob, Path: filepath.Join(paths.NetnsDir, "job-"+goneJob), JobID: goneJob},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("Find() = %+v, want %+v", found, want)
+	}
+	for i := range want {
+		if found[i] != want[i] {
+			t.Errorf("Find()[%d] = %+v, want %+v", i, found[i], want[i])
+		}
+	}
+
+	if found := Find(paths, map[string]bool{activeJob: true, goneJob: true}); len(found) != 0 {
+		t.Errorf("Find() with every job known = %+v", found)
+	}
+	if found := Find(Paths{CgroupDir: filepath.Join(t.TempDir(), "missing")}, nil); len(found) != 0 {
+		t.Errorf("Find() on missing paths = %+v", found)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	var tracker Tracker
+	if stats := tracker.Stats(); !stats.LastPass.IsZero() || stats.Found[KindCgroup] != 0 {
+		t.Errorf("zero tracker stats = %+v", stats)
+	}
+
+	cgroup := Resource{Kind: KindCgroup, Name: "job-" + goneJob, JobID: goneJob}
+	veth := Resource{Kind: KindVeth, Name: "veth-h-" + goneJob[:8], JobID: goneJob[:8]}
+	first := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	tracker.Record(first, []Resource{cgroup, veth}, []Resource{veth})
+	tracker.Record(first.Add(5*time.Minute), []Resource{veth}, nil)
+
+	stats := tracker.Stats()
+	if !stats.LastPass.Equal(first.Add(5*time.Minute)) || len(stats.Orphans) != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.Found[KindCgroup] != 1 || stats.Found[KindVeth] != 2 || stats.Cleaned[KindCgroup] != 1 ||
+		stats.Cleaned[KindVeth] != 1 || stats.Failed[KindVeth] != 1 || stats.Found[KindNetns] != 0 {
+		t.Errorf("counts = found %v, cleaned %v, failed %v", stats.Found, stats.Cleaned, stats.Failed)
+	}
+
+	// Stats are copies
+	stats.Found[KindVeth] = 100
+	if tracker.Stats().Found[KindVeth] != 2 {
+		t.Error("Stats() shares its counts with the tracker")
+	}
+}
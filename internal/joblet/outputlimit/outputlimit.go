@@ -0,0 +1,158 @@
+// Package outputlimit bounds the output a job can push into the log buffer,
+// live streams and persist.
+//
+// Each job gets a token bucket for its output rate and a cap on its total
+// output. Output over a limit is dropped instead of ingested, and a marker
+// line is written to the stream where it happened, so readers of the log can
+// tell that it is incomplete. The dropped byte count is kept with the job.
+package outputlimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Policy is the node's output limits
+type Policy struct {
+	rate     int64
+	burst    int64
+	maxBytes int64
+}
+
+// NewPolicy returns the policy for cfg, or nil when no limit is set
+func NewPolicy(cfg config.OutputLimitsConfig) *Policy {
+	if cfg.RateBytesPerSec <= 0 && cfg.MaxBytes <= 0 {
+		return nil
+	}
+	p := &Policy{rate: max(cfg.RateBytesPerSec, 0), burst: cfg.BurstBytes, maxBytes: max(cfg.MaxBytes, 0)}
+	if p.burst <= 0 {
+		p.burst = p.rate
+	}
+	return p
+}
+
+// ForJob returns a limiter for a job starting now, or nil when the policy
+// has no limits
+func (p *Policy) ForJob(now time.Time) *Limiter {
+	if p == nil {
+		return nil
+	}
+	return &Limiter{policy: p, tokens: float64(p.burst), last: now}
+}
+
+// Limiter applies the policy to one job's output. It is safe for concurrent
+// use, as a job's stdout and stderr are written concurrently.
+type Limiter struct {
+	policy *Policy
+
+	mu        sync.Mutex
+	tokens    float64   // Bytes the job can write now without going over the rate
+	last      time.Time // Last refill of tokens
+	written   int64     // Output kept so far
+	dropped   int64     // Output dropped so far
+	episode   int64     // Output dropped since the job went over the rate
+	throttled bool
+	truncated bool
+	midLine   bool // The kept output does not end with a newline
+}
+
+// Limit returns the part of chunk within the limits, with a marker line
+// where output starts or stops being dropped, and the number of bytes
+// dropped. A nil Limiter keeps everything.
+func (l *Limiter) Limit(chunk []byte, now time.Time) ([]byte, int64) {
+	if l == nil || len(chunk) == 0 {
+		return chunk, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.truncated {
+		l.dropped += int64(len(chunk))
+		return nil, int64(len(chunk))
+	}
+
+	p := l.policy
+	allowed := int64(len(chunk))
+	if p.maxBytes > 0 {
+		allowed = min(allowed, p.maxBytes-l.written)
+	}
+	if p.rate > 0 {
+		if elapsed := now.Sub(l.last); elapsed > 0 {
+			l.tokens = min(float64(p.burst), l.tokens+elapsed.Seconds()*float64(p.rate))
+			l.last = now
+		}
+		switch {
+		case !l.throttled:
+			allowed = min(allowed, int64(l.tokens))
+		case l.tokens < float64(len(chunk)):
+			// Once over the rate, output resumes with whole chunks only
+			allowed = 0
+		}
+	}
+
+	var out []byte
+	if l.throttled && allowed > 0 {
+		out = l.marker(out, fmt.Sprintf("[joblet: output resumed, %d bytes were dropped over the rate limit of %d bytes/s]", l.episode, p.rate))
+		l.throttled = false
+		l.episode = 0
+	}
+	out = append(out, chunk[:allowed]...)
+	if allowed > 0 {
+		l.midLine = chunk[allowed-1] != '\n'
+	}
+	l.written += allowed
+	if p.rate > 0 {
+		l.tokens -= float64(allowed)
+	}
+
+	dropped := int64(len(chunk)) - allowed
+	if dropped == 0 {
+		return out, 0
+	}
+	l.dropped += dropped
+	switch {
+	case p.maxBytes > 0 && l.written >= p.maxBytes:
+		l.truncated = true
+		out = l.marker(out, fmt.Sprintf("[joblet: output truncated after %d bytes, the rest of the job's output is dropped]", p.maxBytes))
+	case !l.throttled:
+		l.throttled = true
+		l.episode = dropped
+		out = l.marker(out, fmt.Sprintf("[joblet: output over the rate limit of %d bytes/s, dropping output]", p.rate))
+	default:
+		l.episode += dropped
+	}
+	return out, dropped
+}
+
+// marker appends a marker line to out, on a line of its own
+func (l *Limiter) marker(out []byte, text string) []byte {
+	if l.midLine {
+		out = append(out, '\n')
+	}
+	l.midLine = false
+	return append(append(out, text...), '\n')
+}
+
+// Dropped returns the number of output bytes dropped so far
+func (l *Limiter) Dropped() int64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped
+}
+
+// Truncated reports whether the job reached the total output cap
+func (l *Limiter) Truncated() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.truncated
+}
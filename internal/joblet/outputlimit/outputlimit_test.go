@@ -0,0 +1,73 @@
+package outputlimit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+func TestNewPolicy(t *testing.T) {
+	if p := NewPolicy(config.OutputLimitsConfig{BurstBytes: 100}); p != nil {
+		t.Errorf("NewPolicy(no limits) = %+v, want nil", p)
+	}
+	if l := (*Policy)(nil).ForJob(time.Now()); l != nil {
+		t.Errorf("ForJob() on a nil policy = %+v, want nil", l)
+	}
+	out, dropped := (*Limiter)(nil).Limit([]byte("hello"), time.Now())
+	if string(out) != "hello" || dropped != 0 {
+		t.Errorf("Limit() on a nil limiter = %q, %d", out, dropped)
+	}
+}
+
+func TestLimitRate(t *testing.T) {
+	start := time.Now()
+	l := NewPolicy(config.OutputLimitsConfig{RateBytesPerSec: 10, BurstBytes: 20}).ForJob(start)
+
+	out, dropped := l.Limit([]byte("0123456789\n"), start)
+	if string(out) != "0123456789\n" || dropped != 0 {
+		t.Fatalf("Limit() within the burst = %q, %d", out, dropped)
+	}
+
+	// 9 bytes of burst left: the rest of the chunk is dropped and marked
+	out, dropped = l.Limit([]byte("abcdefghijklmnop\n"), start)
+	if dropped != 8 {
+		t.Errorf("dropped = %d, want 8", dropped)
+	}
+	if !strings.HasPrefix(string(out), "abcdefghi\n[joblet: output over the rate limit of 10 bytes/s") {
+		t.Errorf("Limit() over the rate = %q", out)
+	}
+
+	// While throttled, chunks are dropped until one fits whole
+	if out, dropped = l.Limit([]byte("0123456789\n"), start.Add(500*time.Millisecond)); out != nil || dropped != 11 {
+		t.Errorf("Limit() while throttled = %q, %d", out, dropped)
+	}
+	out, dropped = l.Limit([]byte("resumed\n"), start.Add(2*time.Second))
+	if dropped != 0 || string(out) != "[joblet: output resumed, 19 bytes were dropped over the rate limit of 10 bytes/s]\nresumed\n" {
+		t.Errorf("Limit() after the refill = %q, %d", out, dropped)
+	}
+
+	if got := l.Dropped(); got != 19 {
+		t.Errorf("Dropped() = %d, want 19", got)
+	}
+	if l.Truncated() {
+		t.Error("Truncated() = true, want false")
+	}
+}
+
+func TestLimitMaxBytes(t *testing.T) {
+	now := time.Now()
+	l := NewPolicy(config.OutputLimitsConfig{MaxBytes: 8}).ForJob(now)
+
+	out, dropped := l.Limit([]byte("hello world\n"), now)
+	if dropped != 4 || string(out) != "hello wo\n[joblet: output truncated after 8 bytes, the rest of the job's output is dropped]\n" {
+		t.Errorf("Limit() over the cap = %q, %d", out, dropped)
+	}
+	if out, dropped = l.Limit([]byte("more\n"), now); out != nil || dropped != 5 {
+		t.Errorf("Limit() after the cap = %q, %d", out, dropped)
+	}
+	if !l.Truncated() || l.Dropped() != 9 {
+		t.Errorf("Truncated() = %v, Dropped() = %d, want true, 9", l.Truncated(), l.Dropped())
+	}
+}
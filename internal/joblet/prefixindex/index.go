@@ -0,0 +1,117 @@
+// Package prefixindex provides a sorted key index for resolving short
+// identifiers, such as abbreviated job and workflow UUIDs, to full keys.
+package prefixindex
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Index maps string keys to values and keeps the keys sorted so that every
+// prefix query is a pair of binary searches. Lookups stay O(log n) even with
+// hundreds of thousands of entries; inserts and deletes pay an O(n) copy, which
+// is cheap compared to the scans they replace and happens once per key.
+//
+// Index is safe for concurrent use.
+type Index[V any] struct {
+	mu     sync.RWMutex
+	keys   []string
+	values map[string]V
+}
+
+// New creates an empty index.
+func New[V any]() *Index[V] {
+	return &Index[V]{
+		values: make(map[string]V),
+	}
+}
+
+// Put stores value under key, replacing any previous value.
+func (ix *Index[V]) Put(key string, value V) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if _, exists := ix.values[key]; !exists {
+		i := sort.SearchStrings(ix.keys, key)
+		ix.keys = append(ix.keys, "")
+		copy(ix.keys[i+1:], ix.keys[i:])
+		ix.keys[i] = key
+	}
+	ix.values[key] = value
+}
+
+// Delete removes key from the index. Deleting a missing key is a no-op.
+func (ix *Index[V]) Delete(key string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if _, exists := ix.values[key]; !exists {
+		return
+	}
+	delete(ix.values, key)
+
+	i := sort.SearchStrings(ix.keys, key)
+	ix.keys = append(ix.keys[:i], ix.keys[i+1:]...)
+}
+
+// Get returns the value stored under the exact key.
+func (ix *Index[V]) Get(key string) (V, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	value, exists := ix.values[key]
+	return value, exists
+}
+
+// Resolve finds the key that prefix identifies. It returns the key and its
+// value when exactly one key starts with prefix, and always reports how many
+// keys matched so callers can tell a miss from an ambiguous prefix.
+func (ix *Index[V]) Resolve(prefix string) (string, V, int) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var zero V
+	lo, hi := ix.span(prefix)
+	if hi-lo != 1 {
+		return "", zero, hi - lo
+	}
+
+	key := ix.keys[lo]
+	return key, ix.values[key], 1
+}
+
+// Match returns the keys that start with prefix in sorted order. A positive
+// limit caps the number of keys returned.
+func (ix *Index[V]) Match(prefix string, limit int) []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	lo, hi := ix.span(prefix)
+	if limit > 0 && hi-lo > limit {
+		hi = lo + limit
+	}
+
+	matches := make([]string, hi-lo)
+	copy(matches, ix.keys[lo:hi])
+	return matches
+}
+
+// Len returns the number of keys in the index.
+func (ix *Index[V]) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.keys)
+}
+
+// span returns the half-open range of sorted keys that start with prefix.
+// Keys sharing a prefix are contiguous, so the range is found with one search
+// for its start and one for its end. Callers must hold ix.mu.
+func (ix *Index[V]) span(prefix string) (int, int) {
+	lo := sort.SearchStrings(ix.keys, prefix)
+	n := sort.Search(len(ix.keys)-lo, func(i int) bool {
+		return !strings.HasPrefix(ix.keys[lo+i], prefix)
+	})
+	return lo, lo + n
+}
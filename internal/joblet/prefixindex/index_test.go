@@ -0,0 +1,98 @@
+package prefixindex
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestIndex_Resolve(t *testing.T) {
+	ix := New[int]()
+	ix.Put("f47ac10b-58cc-4372-a567-0e02b2c3d479", 1)
+	ix.Put("f47bd20c-1111-4372-a567-0e02b2c3d479", 2)
+	ix.Put("0a1b2c3d-58cc-4372-a567-0e02b2c3d479", 3)
+
+	tests := []struct {
+		name      string
+		prefix    string
+		wantKey   string
+		wantValue int
+		wantCount int
+	}{
+		{"unique short prefix", "0a", "0a1b2c3d-58cc-4372-a567-0e02b2c3d479", 3, 1},
+		{"unique longer prefix", "f47ac", "f47ac10b-58cc-4372-a567-0e02b2c3d479", 1, 1},
+		{"full key", "f47bd20c-1111-4372-a567-0e02b2c3d479", "f47bd20c-1111-4372-a567-0e02b2c3d479", 2, 1},
+		{"ambiguous prefix", "f47", "", 0, 2},
+		{"no match", "ff", "", 0, 0},
+		{"empty prefix matches all", "", "", 0, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value, count := ix.Resolve(tt.prefix)
+			if key != tt.wantKey || value != tt.wantValue || count != tt.wantCount {
+				t.Errorf("Resolve(%q) = (%q, %d, %d), want (%q, %d, %d)",
+					tt.prefix, key, value, count, tt.wantKey, tt.wantValue, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestIndex_PutDelete(t *testing.T) {
+	ix := New[string]()
+	ix.Put("b", "first")
+	ix.Put("a", "x")
+	ix.Put("c", "y")
+	ix.Put("b", "second")
+
+	if n := ix.Len(); n != 3 {
+		t.Fatalf("Len() = %d, want 3", n)
+	}
+	if value, ok := ix.Get("b"); !ok || value != "second" {
+		t.Errorf("Get(b) = %q, %v, want second, true", value, ok)
+	}
+
+	ix.Delete("b")
+	ix.Delete("missing")
+
+	if got := ix.Match("", 0); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("Match() = %v, want [a c]", got)
+	}
+	if _, ok := ix.Get("b"); ok {
+		t.Error("Get(b) found a deleted key")
+	}
+}
+
+func TestIndex_MatchLimit(t *testing.T) {
+	ix := New[struct{}]()
+	for i := 0; i < 10; i++ {
+		ix.Put(fmt.Sprintf("job-%d", i), struct{}{})
+	}
+	ix.Put("other", struct{}{})
+
+	if got := ix.Match("job-", 0); len(got) != 10 {
+		t.Errorf("len(Match(job-, 0)) = %d, want 10", len(got))
+	}
+	if got := ix.Match("job-", 3); !reflect.DeepEqual(got, []string{"job-0", "job-1", "job-2"}) {
+		t.Errorf("Match(job-, 3) = %v, want [job-0 job-1 job-2]", got)
+	}
+}
+
+func BenchmarkIndex_Resolve(b *testing.B) {
+	for _, size := range []int{1000, 100000, 500000} {
+		b.Run(fmt.Sprintf("keys=%d", size), func(b *testing.B) {
+			ix := New[int]()
+			keys := make([]string, size)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("%08x-58cc-4372-a567-0e02b2c3d479", i*2654435761)
+				ix.Put(keys[i], i)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ix.Resolve(keys[i%size][:8])
+			}
+		})
+	}
+}
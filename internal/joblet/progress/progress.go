@@ -0,0 +1,167 @@
+// Package progress reads the progress jobs report in their output.
+//
+// A job reports progress by printing marker lines to stdout or stderr,
+// either a bare percentage or a JSON object:
+//
+//	::joblet-progress::40
+//	::joblet-progress::{"percent": 40, "phase": "train", "message": "epoch 4/10"}
+//
+// Fields a marker leaves out keep their last value, so a job can announce a
+// phase once and then report percentages only. Marker lines stay in the log
+// like any other output. Lines that start with the marker but do not parse
+// are ignored.
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// Marker starts the lines a job reports progress with
+const Marker = "::joblet-progress::"
+
+const (
+	// maxLineBytes bounds a marker line; longer lines are not read as one
+	maxLineBytes = 4096
+
+	// maxTextBytes bounds the phase and the message a job reports
+	maxTextBytes = 256
+)
+
+// Scanner reads the marker lines of one job's output, written in chunks that
+// may split lines. It is safe for concurrent use.
+type Scanner struct {
+	mu       sync.Mutex
+	partial  []byte // Start of a line that may be a marker, from earlier chunks
+	skipping bool   // The current line cannot be a marker
+	state    domain.JobProgress
+}
+
+// NewScanner returns a scanner for a job that has not reported progress yet
+func NewScanner() *Scanner {
+	return &Scanner{state: domain.JobProgress{Percent: -1}}
+}
+
+// Scan reads the marker lines that chunk completes and returns the job's
+// progress after the last of them, or nil when chunk completes none. Only
+// the latest progress of a chunk is returned, which keeps a job printing
+// markers in a tight loop from flooding subscribers.
+func (s *Scanner) Scan(chunk []byte, now time.Time) *domain.JobProgress {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := false
+	for len(chunk) > 0 {
+		line, rest, complete := bytes.Cut(chunk, []byte("\n"))
+		chunk = rest
+		if !complete {
+			s.hold(line)
+			break
+		}
+		if s.skipping {
+			s.skipping = false
+			continue
+		}
+		if len(s.partial) > 0 {
+			line = append(s.partial, line...)
+			s.partial = nil
+		}
+		if s.apply(string(bytes.TrimSuffix(line, []byte("\r"))), now) {
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+	progress := s.state
+	return &progress
+}
+
+// hold keeps the incomplete end of a chunk while it may still become a
+// marker line
+func (s *Scanner) hold(tail []byte) {
+	if s.skipping {
+		return
+	}
+	line := append(s.partial, tail...)
+	n := min(len(line), len(Marker))
+	if len(line) > maxLineBytes || string(line[:n]) != Marker[:n] {
+		s.partial = nil
+		s.skipping = true
+		return
+	}
+	s.partial = bytes.Clone(line)
+}
+
+// apply updates the state from line when it is a valid marker line
+func (s *Scanner) apply(line string, now time.Time) bool {
+	report, ok := strings.CutPrefix(line, Marker)
+	if !ok {
+		return false
+	}
+	next, ok := Parse(report, s.state)
+	if !ok {
+		return false
+	}
+	next.UpdatedAt = now
+	s.state = next
+	return true
+}
+
+// report is the JSON form of a marker; absent fields keep their last value
+type report struct {
+	Percent *float64 `json:"percent"`
+	Phase   *string  `json:"phase"`
+	Message *string  `json:"message"`
+}
+
+// Parse applies the text following a marker to the last progress
+func Parse(text string, last domain.JobProgress) (domain.JobProgress, bool) {
+	text = strings.TrimSpace(text)
+	next := last
+	if strings.HasPrefix(text, "{") {
+		var r report
+		if err := json.Unmarshal([]byte(text), &r); err != nil {
+			return last, false
+		}
+		if r.Percent != nil {
+			next.Percent = *r.Percent
+		}
+		if r.Phase != nil {
+			next.Phase = truncate(*r.Phase)
+		}
+		if r.Message != nil {
+			next.Message = truncate(*r.Message)
+		}
+	} else {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+		if err != nil {
+			return last, false
+		}
+		next.Percent = percent
+	}
+	if math.IsNaN(next.Percent) {
+		return last, false
+	}
+	if next.Percent != -1 {
+		next.Percent = min(max(next.Percent, 0), 100)
+	}
+	return next, true
+}
+
+func truncate(text string) string {
+	if len(text) <= maxTextBytes {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxTextBytes], "")
+}
@@ -0,0 +1,81 @@
+package progress
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+func TestParse(t *testing.T) {
+	last := domain.JobProgress{Percent: 10, Phase: "download", Message: "1/3"}
+	valid := map[string]domain.JobProgress{
+		"40":                          {Percent: 40, Phase: "download", Message: "1/3"},
+		" 12.5% ":                     {Percent: 12.5, Phase: "download", Message: "1/3"},
+		"150":                         {Percent: 100, Phase: "download", Message: "1/3"},
+		`{"phase":"train"}`:           {Percent: 10, Phase: "train", Message: "1/3"},
+		`{"percent":-3}`:              {Percent: 0, Phase: "download", Message: "1/3"},
+		`{"percent":55,"message":""}`: {Percent: 55, Phase: "download"},
+	}
+	for text, want := range valid {
+		got, ok := Parse(text, last)
+		if !ok || got != want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", text, got, ok, want)
+		}
+	}
+
+	for _, text := range []string{"", "forty", "NaN", `{"percent":"40"}`, `{"phase":`} {
+		if got, ok := Parse(text, last); ok || got != last {
+			t.Errorf("Parse(%q) = %+v, %v, want the last progress kept", text, got, ok)
+		}
+	}
+
+	long, _ := Parse(`{"message":"`+strings.Repeat("x", 1000)+`"}`, last)
+	if len(long.Message) != maxTextBytes {
+		t.Errorf("message of %d bytes kept, want %d", len(long.Message), maxTextBytes)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewScanner()
+
+	if got := s.Scan([]byte("starting\n::joblet-progress::{\"phase\":\"download\"}\n"), now); got == nil ||
+		got.Percent != -1 || got.Phase != "download" || !got.UpdatedAt.Equal(now) {
+		t.Fatalf("first marker = %+v", got)
+	}
+	if got := s.Scan([]byte("plain output\n::joblet-progress::oops\n"), now); got != nil {
+		t.Errorf("chunk without a valid marker = %+v, want nil", got)
+	}
+
+	// Markers split across chunks, the last of a chunk wins
+	if got := s.Scan([]byte("::joblet-progress::10\n::joblet-prog"), now); got == nil || got.Percent != 10 {
+		t.Errorf("progress = %+v, want 10%%", got)
+	}
+	if got := s.Scan([]byte("ress::3"), now); got != nil {
+		t.Errorf("incomplete marker = %+v, want nil", got)
+	}
+	if got := s.Scan([]byte("5\r\n::joblet-progress::{\"percent\":60,\"message\":\"epoch 6/10\"}\n"), now); got == nil ||
+		got.Percent != 60 || got.Phase != "download" || got.Message != "epoch 6/10" {
+		t.Errorf("progress = %+v, want 60%% of download", got)
+	}
+
+	// The marker must start the line, even when the line spans chunks
+	for _, chunks := range [][]string{
+		{"log ", "::joblet-progress::90\n"},
+		{"log ::joblet-progress::90\n"},
+		{strings.Repeat("x", maxLineBytes+1), "::joblet-progress::90\n"},
+	} {
+		for _, chunk := range chunks {
+			if got := s.Scan([]byte(chunk), now); got != nil {
+				t.Errorf("chunks %q reported %+v", chunks, got)
+			}
+		}
+	}
+
+	var missing *Scanner
+	if got := missing.Scan([]byte("::joblet-progress::1\n"), now); got != nil {
+		t.Errorf("nil scanner reported %+v", got)
+	}
+}
@@ -0,0 +1,213 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestParse(t *testing.T) {
+	valid := map[string]Target{
+		"/work/out/*.parquet:s3://results/runs/${JOB_UUID}/": {Pattern: "out/*.parquet", Scheme: "s3", Bucket: "results", Prefix: "runs/${JOB_UUID}/"},
+		"report.html:gs://reports/daily":                     {Pattern: "report.html", Scheme: "gs", Bucket: "reports", Prefix: "daily/"},
+		"/work:s3://results":                                 {Pattern: ".", Scheme: "s3", Bucket: "results"},
+		"/work/a:b/*.csv:s3://results/x/":                    {Pattern: "a:b/*.csv", Scheme: "s3", Bucket: "results", Prefix: "x/"},
+	}
+	for raw, want := range valid {
+		got, err := Parse(raw)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{
+		"/work/out/*.parquet",
+		"/work/out:https://results/x/",
+		"/etc/*:s3://results/x/",
+		"/work/../etc/*:s3://results/x/",
+		"/work/[:s3://results/x/",
+		"/work/out:s3://Results/x/",
+		"/work/out:s3://results/${HOME}/",
+	} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", raw)
+		}
+	}
+}
+
+func TestTarget_Base(t *testing.T) {
+	tests := map[string]string{
+		"out/*.parquet":       "out",
+		"out/*/part.parquet":  "out",
+		"*.csv":               ".",
+		"results":             ".",
+		"out/report.html":     "out",
+		"out/2024/[0-9]*/x.c": "out/2024",
+		".":                   ".",
+	}
+	for pattern, want := range tests {
+		if got := (Target{Pattern: pattern}).Base(); got != want {
+			t.Errorf("Base(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+// fakeStore keeps the objects put to it and fails the first failures PUTs
+type fakeStore struct {
+	mu       sync.Mutex
+	objects  map[string]string
+	order    []string
+	failures int
+}
+
+func (f *fakeStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		http.Error(w, "SlowDown", http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.objects[r.URL.Path] = string(body)
+	f.order = append(f.order, r.URL.Path)
+	w.Header().Set("ETag", `"etag"`)
+}
+
+func newTestPublisher(endpoint string) *Publisher {
+	cfg := config.DefaultConfig.OutputTargets
+	cfg.Enabled = true
+	cfg.RetryDelay = 0
+	cfg.S3 = config.S3SinkConfig{Endpoint: endpoint, AccessKeyID: "NODE", SecretAccessKey: "secret"}
+	return NewPublisher(cfg, logger.New())
+}
+
+// workspace creates files, relative to a new workspace, and opens it
+func workspace(t *testing.T, files map[string]string) *os.Root {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { root.Close() })
+	return root
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{}, failures: 2}
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	p := newTestPublisher(srv.URL)
+
+	work := workspace(t, map[string]string{
+		"out/a.parquet":      "aaa",
+		"out/b.parquet":      "bb",
+		"out/notes.txt":      "skip",
+		"report/index.html":  "<html>",
+		"report/css/app.css": "body{}",
+	})
+	job := &domain.Job{
+		Uuid: "job-1",
+		Name: "etl",
+		OutputTargets: []string{
+			"/work/out/*.parquet:s3://results/runs/${JOB_UUID}/",
+			"/work/report:s3://results/runs/${JOB_UUID}/",
+		},
+	}
+	stats, err := p.Publish(context.Background(), job, work)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if stats.Files != 4 || stats.Bytes != 17 || len(stats.Manifests) != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	for key, want := range map[string]string{
+		"/results/runs/job-1/a.parquet":          "aaa",
+		"/results/runs/job-1/b.parquet":          "bb",
+		"/results/runs/job-1/report/index.html":  "<html>",
+		"/results/runs/job-1/report/css/app.css": "body{}",
+	} {
+		if got := store.objects[key]; got != want {
+			t.Errorf("object %s = %q, want %q", key, got, want)
+		}
+	}
+	if _, exists := store.objects["/results/runs/job-1/notes.txt"]; exists {
+		t.Error("file outside the pattern was published")
+	}
+
+	// One manifest per prefix, written after the files it lists
+	if last := store.order[len(store.order)-1]; last != "/results/runs/job-1/manifest.json" {
+		t.Fatalf("last object = %s, want the manifest", last)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal([]byte(store.objects["/results/runs/job-1/manifest.json"]), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.JobUuid != "job-1" || manifest.JobName != "etl" || len(manifest.Files) != 4 {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	first := manifest.Files[0]
+	if first.Path != "/work/out/a.parquet" || first.URL != "s3://results/runs/job-1/a.parquet" || first.Size != 3 ||
+		first.SHA256 != "9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0" || first.ETag != `"etag"` {
+		t.Errorf("manifest file = %+v", first)
+	}
+}
+
+func TestPublisher_Errors(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{}}
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	p := newTestPublisher(srv.URL)
+	p.config.MaxFiles = 2
+	p.config.MaxObjectBytes = 4
+
+	work := workspace(t, map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c", "big.bin": "too large"})
+	tests := map[string]string{
+		"/work/*.txt:s3://results/x/":   "over the node's limit of 2",
+		"/work/big.bin:s3://results/x/": "over the node's limit of 4 bytes",
+	}
+	for target, want := range tests {
+		job := &domain.Job{Uuid: "job-1", OutputTargets: []string{target}}
+		if _, err := p.Publish(context.Background(), job, work); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Publish(%s) error = %v, want %q", target, err, want)
+		}
+	}
+
+	// Refusals other than throttling and server errors are not retried
+	store.failures = 0
+	p.config.Retries = 5
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	})
+	job := &domain.Job{Uuid: "job-1", OutputTargets: []string{"/work/a.txt:s3://results/x/"}}
+	if _, err := p.Publish(context.Background(), job, work); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Publish error = %v, want 403", err)
+	}
+
+	var disabled *Publisher
+	if _, err := disabled.Publish(context.Background(), job, work); err == nil {
+		t.Error("nil Publisher accepted a job with output targets")
+	}
+}
@@ -0,0 +1,309 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/objectstore"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// Manifest lists the files a job published under one destination prefix
+type Manifest struct {
+	JobUuid  string         `json:"jobUuid"`
+	JobName  string         `json:"jobName,omitempty"`
+	ExitCode int32          `json:"exitCode"`
+	EndTime  time.Time      `json:"endTime,omitzero"`
+	Files    []ManifestFile `json:"files"`
+}
+
+// ManifestFile is one published file
+type ManifestFile struct {
+	Path   string `json:"path"` // As the job saw it
+	URL    string `json:"url"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	ETag   string `json:"etag,omitempty"`
+}
+
+// Stats summarizes the outputs a job published
+type Stats struct {
+	Files     int
+	Bytes     int64
+	Manifests []string // URLs of the manifests written
+}
+
+// Publisher uploads job outputs
+type Publisher struct {
+	config config.OutputTargetsConfig
+	client *http.Client
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewPublisher returns nil when output targets are disabled
+func NewPublisher(cfg config.OutputTargetsConfig, log *logger.Logger) *Publisher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Publisher{
+		config: cfg,
+		client: &http.Client{},
+		logger: log.WithField("component", "output-targets"),
+		now:    time.Now,
+	}
+}
+
+// destination is a bucket prefix and the files published under it
+type destination struct {
+	scheme, bucket, prefix string
+	store                  objectstore.Store
+	files                  []ManifestFile
+}
+
+// upload is a workspace file and the object it becomes
+type upload struct {
+	name string // Relative to the workspace
+	key  string
+	dest *destination
+}
+
+// Publish uploads the outputs of job from work, the root of its workspace,
+// then writes a manifest under each destination prefix. Manifests are
+// written last, so their presence tells downstream systems that every file
+// listed is in place. Safe on a nil Publisher for jobs without outputs.
+func (p *Publisher) Publish(ctx context.Context, job *domain.Job, work *os.Root) (Stats, error) {
+	var stats Stats
+	if len(job.OutputTargets) == 0 {
+		return stats, nil
+	}
+	if p == nil {
+		return stats, fmt.Errorf("output targets are disabled on this node (output_targets.enabled)")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	uploads, dests, err := p.plan(job, work)
+	if err != nil {
+		return stats, err
+	}
+	for _, u := range uploads {
+		file, err := p.put(ctx, work, u)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return stats, fmt.Errorf("publishing outputs took longer than %s", p.config.Timeout)
+			}
+			return stats, err
+		}
+		u.dest.files = append(u.dest.files, file)
+		stats.Files++
+		stats.Bytes += file.Size
+	}
+
+	for _, d := range dests {
+		manifest := Manifest{JobUuid: job.Uuid, JobName: job.Name, ExitCode: job.ExitCode, Files: d.files}
+		if job.EndTime != nil {
+			manifest.EndTime = *job.EndTime
+		}
+		if manifest.Files == nil {
+			manifest.Files = []ManifestFile{}
+		}
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return stats, err
+		}
+		key := d.prefix + ManifestName
+		if _, err := p.putWithRetries(ctx, d.store, d.bucket, key, "application/json", func() (io.Reader, int64, string, error) {
+			sum := sha256.Sum256(data)
+			return bytes.NewReader(data), int64(len(data)), hex.EncodeToString(sum[:]), nil
+		}); err != nil {
+			return stats, err
+		}
+		stats.Manifests = append(stats.Manifests, d.scheme+"://"+d.bucket+"/"+key)
+	}
+
+	p.logger.Info("job outputs published", "jobID", job.Uuid, "files", stats.Files, "bytes", stats.Bytes, "manifests", stats.Manifests)
+	return stats, nil
+}
+
+// plan lists the files to upload and the destinations they go to, in the
+// order the job declared them
+func (p *Publisher) plan(job *domain.Job, work *os.Root) ([]upload, []*destination, error) {
+	fsys := work.FS()
+	var uploads []upload
+	var dests []*destination
+	byURL := make(map[string]*destination)
+	seen := make(map[string]bool) // Object URLs, a file matched twice is uploaded once
+
+	for _, raw := range job.OutputTargets {
+		target, err := Parse(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		prefix := target.KeyPrefix(job.Uuid)
+		url := target.Scheme + "://" + target.Bucket + "/" + prefix
+		dest, exists := byURL[url]
+		if !exists {
+			store, err := objectstore.JobStore(target.Scheme, p.config.S3, p.config.GCS, job.SecretEnvironment)
+			if err != nil {
+				return nil, nil, err
+			}
+			dest = &destination{scheme: target.Scheme, bucket: target.Bucket, prefix: prefix, store: store}
+			byURL[url] = dest
+			dests = append(dests, dest)
+		}
+
+		matches, err := fs.Glob(fsys, target.Pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid output %s: %w", raw, err)
+		}
+		base := target.Base()
+		for _, match := range matches {
+			names, err := regularFiles(fsys, match)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read output %s: %w", path.Join(workMount, match), err)
+			}
+			for _, name := range names {
+				key := prefix + name
+				if base != "." {
+					key = prefix + strings.TrimPrefix(name, base+"/")
+				}
+				if seen[url+key] {
+					continue
+				}
+				seen[url+key] = true
+				uploads = append(uploads, upload{name: name, key: key, dest: dest})
+			}
+		}
+	}
+	if len(uploads) > p.config.MaxFiles {
+		return nil, nil, fmt.Errorf("outputs match %d files, over the node's limit of %d", len(uploads), p.config.MaxFiles)
+	}
+	return uploads, dests, nil
+}
+
+// regularFiles returns name when it is a regular file, or the regular files
+// under it when it is a directory. Symlinks are followed only within the
+// workspace.
+func regularFiles(fsys fs.FS, name string) ([]string, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode().IsRegular() {
+		return []string{name}, nil
+	}
+	if !info.IsDir() {
+		return nil, nil
+	}
+	var names []string
+	err = fs.WalkDir(fsys, name, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			names = append(names, p)
+		}
+		return nil
+	})
+	return names, err
+}
+
+// put uploads one workspace file, hashing it first: SigV4 signs the hash of
+// the payload, and the manifest lists it
+func (p *Publisher) put(ctx context.Context, work *os.Root, u upload) (ManifestFile, error) {
+	f, err := work.Open(u.name)
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("failed to open output %s: %w", path.Join(workMount, u.name), err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, io.LimitReader(f, p.config.MaxObjectBytes+1))
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("failed to read output %s: %w", path.Join(workMount, u.name), err)
+	}
+	if size > p.config.MaxObjectBytes {
+		return ManifestFile{}, fmt.Errorf("output %s is over the node's limit of %d bytes", path.Join(workMount, u.name), p.config.MaxObjectBytes)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	etag, err := p.putWithRetries(ctx, u.dest.store, u.dest.bucket, u.key, "application/octet-stream", func() (io.Reader, int64, string, error) {
+		return io.NewSectionReader(f, 0, size), size, sum, nil
+	})
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	return ManifestFile{
+		Path:   path.Join(workMount, u.name),
+		URL:    u.dest.scheme + "://" + u.dest.bucket + "/" + u.key,
+		Size:   size,
+		SHA256: sum,
+		ETag:   etag,
+	}, nil
+}
+
+// putWithRetries uploads the payload body returns, again for each retry,
+// and returns the object's ETag. Network errors, throttling and server
+// errors are retried; other refusals are not.
+func (p *Publisher) putWithRetries(ctx context.Context, store objectstore.Store, bucket, key, contentType string, body func() (io.Reader, int64, string, error)) (string, error) {
+	delay := p.config.RetryDelay
+	for attempt := 0; ; attempt++ {
+		etag, retry, err := p.putOnce(ctx, store, bucket, key, contentType, body)
+		if err == nil {
+			return etag, nil
+		}
+		if !retry || attempt >= p.config.Retries {
+			return "", err
+		}
+		p.logger.Warn("output upload failed, retrying", "key", key, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// putOnce makes one PUT request, reporting whether a failure is worth
+// retrying
+func (p *Publisher) putOnce(ctx context.Context, store objectstore.Store, bucket, key, contentType string, body func() (io.Reader, int64, string, error)) (string, bool, error) {
+	reader, size, sum, err := body()
+	if err != nil {
+		return "", false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, store.URL(bucket, key), reader)
+	if err != nil {
+		return "", false, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	objectstore.SignHashed(req, sum, store.Creds, store.Region, p.now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", ctx.Err() == nil, fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return "", retry, fmt.Errorf("failed to upload %s: %s: %s", key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Header.Get("ETag"), false, nil
+}
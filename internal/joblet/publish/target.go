@@ -0,0 +1,98 @@
+// Package publish uploads the files a job declares as outputs to S3 or
+// Google Cloud Storage once it completes, followed by a manifest listing
+// them, so downstream systems read results from object storage rather than
+// from the node.
+package publish
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/objectstore"
+)
+
+// workMount is where the job sees its workspace
+const workMount = "/work"
+
+// jobUUIDVar is replaced by the job's UUID in destination prefixes
+const jobUUIDVar = "${JOB_UUID}"
+
+// ManifestName is the object written under each destination prefix once
+// all of its files are uploaded
+const ManifestName = "manifest.json"
+
+// Target is a set of workspace files and the prefix they are uploaded under
+type Target struct {
+	Pattern string // Glob relative to the job's workspace, "." for all of it
+	Scheme  string
+	Bucket  string
+	Prefix  string // Key prefix, empty or ending in a slash; may hold ${JOB_UUID}
+}
+
+// String returns the canonical form of the target, as Parse accepts it
+func (t Target) String() string {
+	return path.Join(workMount, t.Pattern) + ":" + t.Scheme + "://" + t.Bucket + "/" + t.Prefix
+}
+
+// KeyPrefix returns the prefix with ${JOB_UUID} replaced
+func (t Target) KeyPrefix(jobUUID string) string {
+	return strings.ReplaceAll(t.Prefix, jobUUIDVar, jobUUID)
+}
+
+// Base returns the directory, relative to the workspace, that object keys
+// are relative to: the pattern's leading components without wildcards, or
+// the parent of a pattern without wildcards
+func (t Target) Base() string {
+	parts := strings.Split(t.Pattern, "/")
+	for i, part := range parts {
+		if strings.ContainsAny(part, `*?[\`) {
+			return path.Join(append([]string{"."}, parts[:i]...)...)
+		}
+	}
+	return path.Dir(t.Pattern)
+}
+
+// Parse reads a target in the form /work/GLOB:s3://bucket/prefix/ or
+// /work/GLOB:gs://bucket/prefix/. The glob uses path.Match syntax and may be
+// relative to /work; a directory it matches is uploaded with its content.
+func Parse(raw string) (Target, error) {
+	pattern, dest, scheme := "", "", ""
+	for _, s := range []string{objectstore.SchemeS3, objectstore.SchemeGCS} {
+		if i := strings.Index(raw, ":"+s+"://"); i >= 0 {
+			pattern, dest, scheme = raw[:i], raw[i+len(s)+4:], s
+			break
+		}
+	}
+	if scheme == "" {
+		return Target{}, fmt.Errorf("invalid output %q: must be /work/PATTERN:s3://bucket/prefix/ or /work/PATTERN:gs://bucket/prefix/", raw)
+	}
+
+	if !strings.HasPrefix(pattern, "/") {
+		pattern = path.Join(workMount, pattern)
+	}
+	cleaned := path.Clean(pattern)
+	if cleaned != workMount && !strings.HasPrefix(cleaned, workMount+"/") {
+		return Target{}, fmt.Errorf("invalid output %q: %s must be under %s", raw, pattern, workMount)
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(cleaned, workMount), "/")
+	if rel == "" {
+		rel = "."
+	}
+	if _, err := path.Match(rel, ""); err != nil {
+		return Target{}, fmt.Errorf("invalid output %q: bad pattern %s", raw, pattern)
+	}
+
+	bucket, prefix, _ := strings.Cut(dest, "/")
+	if !objectstore.ValidBucket(bucket) {
+		return Target{}, fmt.Errorf("invalid output %q: bad bucket name %q", raw, bucket)
+	}
+	if strings.Contains(strings.ReplaceAll(prefix, jobUUIDVar, ""), "${") {
+		return Target{}, fmt.Errorf("invalid output %q: only %s may be expanded in the prefix", raw, jobUUIDVar)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return Target{Pattern: rel, Scheme: scheme, Bucket: bucket, Prefix: prefix}, nil
+}
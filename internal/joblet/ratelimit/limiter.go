@@ -0,0 +1,282 @@
+// Package ratelimit throttles gRPC calls per client identity so that a single
+// runaway client cannot flood job submission or hold open unbounded log
+// streams.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// idleClientTTL is how long a client's buckets are kept after its last call.
+	// A returning client starts with full buckets, which is what it would have
+	// accumulated over that time anyway.
+	idleClientTTL = 10 * time.Minute
+
+	// throttleLogInterval limits throttle warnings to one per client per interval.
+	throttleLogInterval = 30 * time.Second
+)
+
+// submitMethods draw from the run job bucket in addition to the general one.
+var submitMethods = map[string]bool{
+	pb.JobService_RunJob_FullMethodName:      true,
+	pb.JobService_RunWorkflow_FullMethodName: true,
+}
+
+// Stats reports how many calls were admitted and rejected.
+type Stats struct {
+	Allowed   int64 `json:"allowed"`
+	Throttled int64 `json:"throttled"`
+	// ThrottledByClient counts rejections per client identity
+	ThrottledByClient map[string]int64 `json:"throttledByClient,omitempty"`
+}
+
+// Limiter enforces RateLimitConfig using token buckets kept per client.
+type Limiter struct {
+	cfg    config.RateLimitConfig
+	logger *logger.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*clientState
+	lastSweep time.Time
+
+	allowed   atomic.Int64
+	throttled atomic.Int64
+}
+
+// clientState holds the buckets and open stream count for one client.
+type clientState struct {
+	quota     config.ClientQuota
+	requests  bucket
+	submits   bucket
+	streams   int
+	throttled int64
+	lastSeen  time.Time
+	lastWarn  time.Time
+}
+
+// bucket is a token bucket refilled continuously at rate tokens per second.
+type bucket struct {
+	tokens float64
+	rate   float64
+	burst  float64
+	last   time.Time
+}
+
+// take refills the bucket for the time elapsed since the last call and
+// consumes one token. When empty it reports how long until a token is due.
+func (b *bucket) take(now time.Time) (bool, time.Duration) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// New creates a limiter. A disabled configuration yields a limiter whose
+// interceptors pass every call through.
+func New(cfg config.RateLimitConfig) *Limiter {
+	return &Limiter{
+		cfg:     cfg,
+		logger:  logger.WithField("component", "rate-limiter"),
+		now:     time.Now,
+		clients: make(map[string]*clientState),
+	}
+}
+
+// Allow charges one call to method against client's quota. It returns a
+// RESOURCE_EXHAUSTED status describing the limit and when to retry if the
+// call must be rejected.
+func (l *Limiter) Allow(client, method string) error {
+	if !l.cfg.Enabled {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	state := l.client(client, now)
+
+	if ok, wait := state.requests.take(now); !ok {
+		return l.reject(client, state, now, "request rate limit exceeded for client %q: %.4g requests/s with burst %d, retry in %s",
+			client, state.requests.rate, int(state.requests.burst), roundWait(wait))
+	}
+
+	if submitMethods[method] {
+		if ok, wait := state.submits.take(now); !ok {
+			return l.reject(client, state, now, "job submission rate limit exceeded for client %q: %.4g submissions/s with burst %d, retry in %s",
+				client, state.submits.rate, int(state.submits.burst), roundWait(wait))
+		}
+	}
+
+	l.allowed.Add(1)
+	return nil
+}
+
+// AcquireStream charges a stream open against client's quota and reserves one
+// of its concurrent stream slots. The returned release function must be called
+// when the stream ends.
+func (l *Limiter) AcquireStream(client, method string) (func(), error) {
+	if err := l.Allow(client, method); err != nil {
+		return nil, err
+	}
+	if !l.cfg.Enabled {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	state := l.client(client, now)
+	maxStreams := state.maxStreams(l.cfg)
+	if maxStreams > 0 && state.streams >= maxStreams {
+		l.allowed.Add(-1)
+		return nil, l.reject(client, state, now, "stream limit exceeded for client %q: %d concurrent streams open, limit %d",
+			client, state.streams, maxStreams)
+	}
+	state.streams++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			state.streams--
+			state.lastSeen = l.now()
+		})
+	}, nil
+}
+
+// Stats returns a snapshot of the admission counters.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	byClient := make(map[string]int64)
+	for name, state := range l.clients {
+		if state.throttled > 0 {
+			byClient[name] = state.throttled
+		}
+	}
+
+	return Stats{
+		Allowed:           l.allowed.Load(),
+		Throttled:         l.throttled.Load(),
+		ThrottledByClient: byClient,
+	}
+}
+
+// UnaryInterceptor rate limits unary calls.
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.Allow(auth.ClientIdentity(ctx), info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rate limits stream opens and caps concurrent streams.
+func (l *Limiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := l.AcquireStream(auth.ClientIdentity(ss.Context()), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// client returns the state for name, creating it with full buckets on first
+// use. Callers must hold l.mu.
+func (l *Limiter) client(name string, now time.Time) *clientState {
+	l.sweep(now)
+
+	state, exists := l.clients[name]
+	if !exists {
+		quota := l.cfg.Clients[name]
+		state = &clientState{quota: quota}
+		state.requests = newBucket(pick(quota.RequestsPerSecond, l.cfg.RequestsPerSecond), pick(quota.Burst, l.cfg.Burst), now)
+		state.submits = newBucket(pick(quota.RunJobPerSecond, l.cfg.RunJobPerSecond), pick(quota.RunJobBurst, l.cfg.RunJobBurst), now)
+		l.clients[name] = state
+	}
+	state.lastSeen = now
+	return state
+}
+
+// sweep drops clients that have been idle for idleClientTTL and have no open
+// streams. It runs at most once per TTL. Callers must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleClientTTL {
+		return
+	}
+	l.lastSweep = now
+
+	for name, state := range l.clients {
+		if state.streams == 0 && now.Sub(state.lastSeen) >= idleClientTTL {
+			delete(l.clients, name)
+		}
+	}
+}
+
+// reject records a throttled call and builds its status. Callers must hold l.mu.
+func (l *Limiter) reject(client string, state *clientState, now time.Time, format string, args ...interface{}) error {
+	l.throttled.Add(1)
+	state.throttled++
+
+	if now.Sub(state.lastWarn) >= throttleLogInterval {
+		state.lastWarn = now
+		l.logger.Warn("throttling client", "client", client, "throttledTotal", state.throttled)
+	}
+
+	return status.Errorf(codes.ResourceExhausted, format, args...)
+}
+
+func (s *clientState) maxStreams(cfg config.RateLimitConfig) int {
+	if s.quota.MaxStreams > 0 {
+		return s.quota.MaxStreams
+	}
+	return cfg.MaxStreams
+}
+
+func newBucket(rate float64, burst int, now time.Time) bucket {
+	return bucket{tokens: float64(burst), rate: rate, burst: float64(burst), last: now}
+}
+
+// pick returns override when it is set and fallback otherwise.
+func pick[T int | float64](override, fallback T) T {
+	if override > 0 {
+		return override
+	}
+	return fallback
+}
+
+// roundWait keeps retry hints readable.
+func roundWait(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return time.Millisecond
+	}
+	return d.Round(time.Millisecond)
+}
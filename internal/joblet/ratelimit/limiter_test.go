@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const listJobs = "/joblet.JobService/ListJobs"
+
+// newTestLimiter returns a limiter driven by a manual clock.
+func newTestLimiter(cfg config.RateLimitConfig) (*Limiter, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	l := New(cfg)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func testConfig() config.RateLimitConfig {
+	return config.RateLimitConfig{
+		Enabled:           true,
+		RequestsPerSecond: 2,
+		Burst:             3,
+		RunJobPerSecond:   1,
+		RunJobBurst:       1,
+		MaxStreams:        1,
+	}
+}
+
+func assertExhausted(t *testing.T, err error, wantMsg string) {
+	t.Helper()
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("error = %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if !strings.Contains(status.Convert(err).Message(), wantMsg) {
+		t.Errorf("message %q does not mention %q", status.Convert(err).Message(), wantMsg)
+	}
+}
+
+func TestLimiter_BurstThenRefill(t *testing.T) {
+	l, now := newTestLimiter(testConfig())
+
+	for i := 0; i < 3; i++ {
+		if err := l.Allow("alice", listJobs); err != nil {
+			t.Fatalf("call %d within burst rejected: %v", i, err)
+		}
+	}
+	err := l.Allow("alice", listJobs)
+	assertExhausted(t, err, "retry in 500ms")
+
+	// Another client has its own bucket
+	if err := l.Allow("bob", listJobs); err != nil {
+		t.Errorf("independent client rejected: %v", err)
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if err := l.Allow("alice", listJobs); err != nil {
+		t.Errorf("call after refill rejected: %v", err)
+	}
+
+	stats := l.Stats()
+	if stats.Allowed != 5 || stats.Throttled != 1 || stats.ThrottledByClient["alice"] != 1 {
+		t.Errorf("Stats() = %+v, want 5 allowed and 1 throttled for alice", stats)
+	}
+}
+
+func TestLimiter_SubmitBucket(t *testing.T) {
+	l, _ := newTestLimiter(testConfig())
+
+	if err := l.Allow("alice", pb.JobService_RunJob_FullMethodName); err != nil {
+		t.Fatalf("first submission rejected: %v", err)
+	}
+	assertExhausted(t, l.Allow("alice", pb.JobService_RunWorkflow_FullMethodName), "job submission rate limit")
+
+	// Reads still flow while submissions are throttled
+	if err := l.Allow("alice", listJobs); err != nil {
+		t.Errorf("read rejected while submissions are throttled: %v", err)
+	}
+}
+
+func TestLimiter_ClientOverrides(t *testing.T) {
+	cfg := testConfig()
+	cfg.Clients = map[string]config.ClientQuota{
+		"ci-runner": {Burst: 10, RunJobBurst: 3, MaxStreams: 2},
+	}
+	l, _ := newTestLimiter(cfg)
+
+	for i := 0; i < 3; i++ {
+		if err := l.Allow("ci-runner", pb.JobService_RunJob_FullMethodName); err != nil {
+			t.Fatalf("submission %d within overridden burst rejected: %v", i, err)
+		}
+	}
+
+	assertExhausted(t, l.Allow("ci-runner", pb.JobService_RunJob_FullMethodName), "burst 3")
+
+	for i := 0; i < 2; i++ {
+		if _, err := l.AcquireStream("ci-runner", "/joblet.JobService/GetJobLogs"); err != nil {
+			t.Fatalf("stream %d within overridden limit rejected: %v", i, err)
+		}
+	}
+
+	// Clients without an override keep the defaults
+	if err := l.Allow("alice", pb.JobService_RunJob_FullMethodName); err != nil {
+		t.Fatal(err)
+	}
+	assertExhausted(t, l.Allow("alice", pb.JobService_RunJob_FullMethodName), "burst 1")
+}
+
+func TestLimiter_StreamLimit(t *testing.T) {
+	l, _ := newTestLimiter(testConfig())
+
+	release, err := l.AcquireStream("alice", "/joblet.JobService/GetJobLogs")
+	if err != nil {
+		t.Fatalf("first stream rejected: %v", err)
+	}
+
+	_, err = l.AcquireStream("alice", "/joblet.JobService/GetJobLogs")
+	assertExhausted(t, err, "1 concurrent streams open, limit 1")
+
+	release()
+	release() // releasing twice must not free a second slot
+
+	if _, err := l.AcquireStream("alice", "/joblet.JobService/GetJobLogs"); err != nil {
+		t.Errorf("stream after release rejected: %v", err)
+	}
+	if _, err := l.AcquireStream("alice", "/joblet.JobService/GetJobLogs"); err == nil {
+		t.Error("double release freed an extra stream slot")
+	}
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	l, _ := newTestLimiter(config.RateLimitConfig{})
+
+	for i := 0; i < 100; i++ {
+		if err := l.Allow("alice", pb.JobService_RunJob_FullMethodName); err != nil {
+			t.Fatalf("disabled limiter rejected call %d: %v", i, err)
+		}
+	}
+	if stats := l.Stats(); stats.Throttled != 0 {
+		t.Errorf("disabled limiter counted %d throttled calls", stats.Throttled)
+	}
+}
+
+func TestLimiter_SweepsIdleClients(t *testing.T) {
+	l, now := newTestLimiter(testConfig())
+
+	if err := l.Allow("alice", listJobs); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(idleClientTTL)
+	if err := l.Allow("bob", listJobs); err != nil {
+		t.Fatal(err)
+	}
+
+	l.mu.Lock()
+	_, aliceKept := l.clients["alice"]
+	l.mu.Unlock()
+	if aliceKept {
+		t.Error("idle client was not swept")
+	}
+}
+
+func TestUnaryInterceptor_KeysByCertificate(t *testing.T) {
+	l, _ := newTestLimiter(testConfig())
+	interceptor := l.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: pb.JobService_RunJob_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}},
+		}},
+	})
+
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("first call rejected: %v", err)
+	}
+	_, err := interceptor(ctx, nil, info, handler)
+	assertExhausted(t, err, `client "alice"`)
+}
@@ -0,0 +1,153 @@
+// Package redact masks secrets in job output before it is buffered, streamed
+// to clients or forwarded to persist.
+//
+// Two kinds of matches are masked: the values of the job's secret environment
+// variables, and the regular expressions configured under redaction.patterns.
+// Output is redacted chunk by chunk as the job writes it. A chunk ending in
+// the start of a secret value has that end held back until the next write
+// shows whether the rest of the secret follows, or until the job ends, so a
+// secret split across writes is still masked.
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Policy is the node's redaction configuration, compiled once at startup
+type Policy struct {
+	secretValues    bool
+	minSecretLength int
+	replacement     []byte
+	patterns        []*regexp.Regexp
+}
+
+// NewPolicy compiles the configured patterns
+func NewPolicy(cfg config.RedactionConfig) (*Policy, error) {
+	p := &Policy{
+		secretValues:    cfg.SecretValues,
+		minSecretLength: cfg.MinSecretLength,
+		replacement:     []byte(cfg.Replacement),
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern.Name, err)
+		}
+		p.patterns = append(p.patterns, re)
+	}
+	return p, nil
+}
+
+// ForJob returns the redactor for a job with the given secret environment, or
+// nil when there is nothing to redact.
+func (p *Policy) ForJob(secretEnv map[string]string) *Redactor {
+	if p == nil {
+		return nil
+	}
+
+	r := &Redactor{replacement: p.replacement, patterns: p.patterns}
+	if p.secretValues {
+		seen := make(map[string]bool)
+		for _, value := range secretEnv {
+			if len(value) == 0 || len(value) < p.minSecretLength || seen[value] {
+				continue
+			}
+			seen[value] = true
+			r.secrets = append(r.secrets, []byte(value))
+		}
+		// Longest first, so a secret that contains another is masked whole
+		sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	}
+
+	if len(r.secrets) == 0 && len(r.patterns) == 0 {
+		return nil
+	}
+	return r
+}
+
+// Redactor masks one job's secrets
+type Redactor struct {
+	secrets     [][]byte
+	patterns    []*regexp.Regexp
+	replacement []byte
+
+	mu      sync.Mutex
+	pending []byte // End of the last chunk that may start a secret, held back
+}
+
+// Redact returns the output that can be released after chunk, with every
+// match replaced, and the number of matches. Output ending in the start of a
+// secret is held back and released by the next call or by Flush, so the
+// result may be shorter than chunk or empty. chunk is returned unchanged when
+// nothing matched or was held back.
+func (r *Redactor) Redact(chunk []byte) ([]byte, int) {
+	if r == nil {
+		return chunk, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) > 0 {
+		chunk = append(r.pending, chunk...)
+		r.pending = nil
+	}
+	matches := 0
+	for _, secret := range r.secrets {
+		if n := bytes.Count(chunk, secret); n > 0 {
+			matches += n
+			chunk = bytes.ReplaceAll(chunk, secret, r.replacement)
+		}
+	}
+	if held := r.secretPrefixAtEnd(chunk); held > 0 {
+		r.pending = bytes.Clone(chunk[len(chunk)-held:])
+		chunk = chunk[:len(chunk)-held]
+	}
+	chunk, n := r.maskPatterns(chunk)
+	return chunk, matches + n
+}
+
+// Flush releases the output held back by Redact, once the job wrote its last
+// chunk, and the number of pattern matches in it
+func (r *Redactor) Flush() ([]byte, int) {
+	if r == nil {
+		return nil, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rest := r.pending
+	r.pending = nil
+	return r.maskPatterns(rest)
+}
+
+// secretPrefixAtEnd returns the length of the longest end of chunk that is the
+// start of a secret, without being the whole secret
+func (r *Redactor) secretPrefixAtEnd(chunk []byte) int {
+	longest := 0
+	for _, secret := range r.secrets {
+		for n := min(len(secret)-1, len(chunk)); n > longest; n-- {
+			if bytes.HasSuffix(chunk, secret[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
+
+func (r *Redactor) maskPatterns(chunk []byte) ([]byte, int) {
+	matches := 0
+	for _, re := range r.patterns {
+		chunk = re.ReplaceAllFunc(chunk, func([]byte) []byte {
+			matches++
+			return r.replacement
+		})
+	}
+	return chunk, matches
+}
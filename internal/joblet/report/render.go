@@ -0,0 +1,248 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Report formats
+const (
+	FormatHTML  = "html"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// Formats are the formats a report renders as
+var Formats = []string{FormatHTML, FormatJSON, FormatJUnit}
+
+// Render renders the report in a format
+func Render(r *Report, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatJUnit:
+		return renderJUnit(r)
+	case FormatHTML:
+		var buf bytes.Buffer
+		if err := htmlReport.Execute(&buf, r); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown report format %q, expected %s", format, strings.Join(Formats, ", "))
+}
+
+// JUnit XML as CI systems read it: the workflow is a test suite, each job a
+// test case failing with its log excerpt
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func renderJUnit(r *Report) ([]byte, error) {
+	name := r.Workflow
+	if name == "" {
+		name = r.WorkflowUUID
+	}
+	suite := junitSuite{
+		Name:       name,
+		Time:       seconds(r.DurationSeconds),
+		Properties: []junitProperty{{Name: "workflow.uuid", Value: r.WorkflowUUID}, {Name: "workflow.status", Value: r.Status}},
+	}
+	if r.StartedAt != nil {
+		suite.Timestamp = r.StartedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	for i := range r.Jobs {
+		job := &r.Jobs[i]
+		tc := junitCase{Name: job.Name, ClassName: name, Time: seconds(job.DurationSeconds)}
+		switch {
+		case job.Failed():
+			suite.Failures++
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("%s with exit code %d", job.Status, job.ExitCode),
+				Type:    job.Status,
+				Text:    strings.Join(job.LogExcerpt, "\n"),
+			}
+		case job.Skipped():
+			suite.Skipped++
+			tc.Skipped = &junitMessage{Message: job.Status}
+		}
+		tc.SystemOut = jobSummary(job)
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	suites := junitSuites{
+		Name:     name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// jobSummary is a job's UUID and resource usage as text
+func jobSummary(job *Job) string {
+	var lines []string
+	if job.UUID != "" {
+		lines = append(lines, "job: "+job.UUID)
+	}
+	if u := job.Usage; u != nil {
+		lines = append(lines,
+			fmt.Sprintf("cpu: avg %.1f%%, peak %.1f%%", u.AvgCPUPercent, u.PeakCPUPercent),
+			"peak memory: "+formatBytes(u.PeakMemoryBytes),
+			fmt.Sprintf("disk: %s read, %s written", formatBytes(u.DiskReadBytes), formatBytes(u.DiskWriteBytes)),
+			fmt.Sprintf("network: %s received, %s sent", formatBytes(u.NetRxBytes), formatBytes(u.NetTxBytes)))
+	}
+	if job.Note != "" {
+		lines = append(lines, "note: "+job.Note)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func seconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDuration(s float64) string {
+	return (time.Duration(s * float64(time.Second))).Round(time.Millisecond).String()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"bytes":    formatBytes,
+	"duration": formatDuration,
+	"time":     formatTime,
+	"join":     strings.Join,
+	"lower":    strings.ToLower,
+	"ptr":      func(t time.Time) *time.Time { return &t },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Workflow {{.Workflow}} - {{.Status}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+.status { font-weight: bold; }
+.completed { color: #1a7f37; } .failed, .stopped { color: #cf222e; } .canceled { color: #9a6700; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; font-size: 12px; }
+.note { color: #666; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Workflow {{.Workflow}}</h1>
+<table>
+<tr><th>UUID</th><td>{{.WorkflowUUID}}</td></tr>
+<tr><th>Status</th><td class="status {{lower .Status}}">{{.Status}}</td></tr>
+<tr><th>Started</th><td>{{time .StartedAt}}</td></tr>
+<tr><th>Completed</th><td>{{time .CompletedAt}}</td></tr>
+<tr><th>Duration</th><td>{{duration .DurationSeconds}}</td></tr>
+<tr><th>Jobs</th><td>{{.TotalJobs}} total, {{.CompletedJobs}} completed, {{.FailedJobs}} failed, {{.CanceledJobs}} canceled</td></tr>
+</table>
+<h2>Jobs</h2>
+<table>
+<tr><th>Job</th><th>Status</th><th>Exit</th><th>Depends on</th><th>Started</th><th>Duration</th><th>CPU avg / peak</th><th>Peak memory</th><th>Disk read / write</th><th>Network rx / tx</th></tr>
+{{- range .Jobs}}
+<tr>
+<td>{{.Name}}{{if .UUID}}<br><small>{{.UUID}}</small>{{end}}</td>
+<td class="status {{lower .Status}}">{{.Status}}</td>
+<td>{{.ExitCode}}</td>
+<td>{{join .DependsOn ", "}}</td>
+<td>{{time .StartTime}}</td>
+<td>{{duration .DurationSeconds}}</td>
+{{- with .Usage}}
+<td>{{printf "%.1f" .AvgCPUPercent}}% / {{printf "%.1f" .PeakCPUPercent}}%</td>
+<td>{{bytes .PeakMemoryBytes}}</td>
+<td>{{bytes .DiskReadBytes}} / {{bytes .DiskWriteBytes}}</td>
+<td>{{bytes .NetRxBytes}} / {{bytes .NetTxBytes}}</td>
+{{- else}}
+<td>-</td><td>-</td><td>-</td><td>-</td>
+{{- end}}
+</tr>
+{{- end}}
+</table>
+{{- range .Jobs}}{{if .Failed}}
+<h2 id="{{.Name}}">{{.Name}}: {{.Status}} (exit code {{.ExitCode}})</h2>
+{{- if .Note}}<p class="note">{{.Note}}</p>{{end}}
+{{- if .LogExcerpt}}
+<pre>{{join .LogExcerpt "\n"}}</pre>
+{{- end}}
+{{- end}}{{end}}
+<p class="note">Generated {{time (ptr .GeneratedAt)}}</p>
+</body>
+</html>
+`))
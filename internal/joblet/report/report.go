@@ -0,0 +1,314 @@
+// Package report builds reports of workflow runs for 'rnx workflow report':
+// the status, timing and resource usage of every job, with the end of the log
+// of the jobs that failed.
+//
+// A report is built from the workflow state, the job records still in the job
+// store, and the metrics and logs the persist service stored, then rendered
+// as HTML, JSON or JUnit XML for CI systems.
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+)
+
+// DefaultLogLines is how many log lines are quoted per failed job
+const DefaultLogLines = 50
+
+// MaxLogLines caps the log lines a report quotes per failed job
+const MaxLogLines = 1000
+
+// maxLineBytes caps a quoted log line; longer output without a newline is
+// cut into lines of this size
+const maxLineBytes = 4096
+
+// Report is a workflow run
+type Report struct {
+	WorkflowUUID    string     `json:"workflowUuid"`
+	Workflow        string     `json:"workflow"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	StartedAt       *time.Time `json:"startedAt,omitempty"`
+	CompletedAt     *time.Time `json:"completedAt,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	TotalJobs       int        `json:"totalJobs"`
+	CompletedJobs   int        `json:"completedJobs"`
+	FailedJobs      int        `json:"failedJobs"`
+	CanceledJobs    int        `json:"canceledJobs"`
+	Jobs            []Job      `json:"jobs"` // In workflow order
+	GeneratedAt     time.Time  `json:"generatedAt"`
+}
+
+// Job is one job of a workflow run
+type Job struct {
+	Name            string     `json:"name"`
+	UUID            string     `json:"uuid,omitempty"` // Empty when the job never started
+	Status          string     `json:"status"`
+	ExitCode        int32      `json:"exitCode"`
+	DependsOn       []string   `json:"dependsOn,omitempty"`
+	StartTime       *time.Time `json:"startTime,omitempty"`
+	EndTime         *time.Time `json:"endTime,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	Limits          *Limits    `json:"limits,omitempty"`
+	Usage           *Usage     `json:"usage,omitempty"`
+	LogExcerpt      []string   `json:"logExcerpt,omitempty"` // Last log lines of a failed job
+	Note            string     `json:"note,omitempty"`       // Why details are missing
+}
+
+// Limits are the resource limits a job ran with (0 = unlimited)
+type Limits struct {
+	MaxCPU      int32  `json:"maxCpu,omitempty"`      // Percent
+	MaxMemoryMB int32  `json:"maxMemoryMb,omitempty"` // Megabytes
+	MaxIOBPS    int64  `json:"maxIoBps,omitempty"`    // Bytes per second
+	CPUCores    string `json:"cpuCores,omitempty"`
+	Runtime     string `json:"runtime,omitempty"`
+}
+
+// Usage summarizes the metrics samples persist stored for a job
+type Usage struct {
+	Samples         int     `json:"samples"`
+	AvgCPUPercent   float64 `json:"avgCpuPercent"`
+	PeakCPUPercent  float64 `json:"peakCpuPercent"`
+	PeakMemoryBytes int64   `json:"peakMemoryBytes"`
+	DiskReadBytes   int64   `json:"diskReadBytes"`
+	DiskWriteBytes  int64   `json:"diskWriteBytes"`
+	NetRxBytes      int64   `json:"netRxBytes"`
+	NetTxBytes      int64   `json:"netTxBytes"`
+}
+
+// Failed reports whether the job counts as failed in the report
+func (j *Job) Failed() bool {
+	switch domain.JobStatus(j.Status) {
+	case domain.StatusFailed, domain.StatusStopped:
+		return true
+	case domain.StatusCompleted:
+		return j.ExitCode != 0
+	}
+	return false
+}
+
+// Skipped reports whether the job never ran to an end, such as a job canceled
+// after a dependency failed
+func (j *Job) Skipped() bool {
+	return !j.Failed() && domain.JobStatus(j.Status) != domain.StatusCompleted
+}
+
+// Source is where a report reads the jobs of a workflow
+type Source struct {
+	// Jobs returns a job record still in the job store
+	Jobs func(uuid string) (*domain.Job, bool)
+	// Persist holds the jobs' metrics and logs (nil = reported without)
+	Persist persistpb.PersistServiceClient
+}
+
+// Build builds the report of a workflow run, quoting logLines log lines of
+// each failed job
+func Build(ctx context.Context, state *workflow.WorkflowState, workflowUUID string, src Source, logLines int, now time.Time) *Report {
+	if logLines <= 0 {
+		logLines = DefaultLogLines
+	}
+	if logLines > MaxLogLines {
+		logLines = MaxLogLines
+	}
+
+	r := &Report{
+		WorkflowUUID:  workflowUUID,
+		Workflow:      state.Workflow,
+		Status:        string(state.Status),
+		CreatedAt:     state.CreatedAt,
+		StartedAt:     state.StartedAt,
+		CompletedAt:   state.CompletedAt,
+		TotalJobs:     state.TotalJobs,
+		CompletedJobs: state.CompletedJobs,
+		FailedJobs:    state.FailedJobs,
+		CanceledJobs:  state.CanceledJobs,
+		GeneratedAt:   now,
+	}
+	if state.StartedAt != nil {
+		end := now
+		if state.CompletedAt != nil {
+			end = *state.CompletedAt
+		}
+		r.DurationSeconds = end.Sub(*state.StartedAt).Seconds()
+	}
+
+	for _, dep := range jobsInOrder(state) {
+		r.Jobs = append(r.Jobs, buildJob(ctx, dep, src, logLines, now))
+	}
+	return r
+}
+
+// jobsInOrder returns the workflow's jobs in the order they were declared,
+// by name for jobs the order misses
+func jobsInOrder(state *workflow.WorkflowState) []*workflow.JobDependency {
+	byName := make(map[string]*workflow.JobDependency, len(state.Jobs))
+	for _, dep := range state.Jobs {
+		byName[dep.InternalName] = dep
+	}
+	var jobs []*workflow.JobDependency
+	for _, name := range state.JobOrder {
+		if dep, ok := byName[name]; ok {
+			jobs = append(jobs, dep)
+			delete(byName, name)
+		}
+	}
+	rest := make([]string, 0, len(byName))
+	for name := range byName {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		jobs = append(jobs, byName[name])
+	}
+	return jobs
+}
+
+func buildJob(ctx context.Context, dep *workflow.JobDependency, src Source, logLines int, now time.Time) Job {
+	job := Job{Name: dep.InternalName, Status: string(dep.Status)}
+	for _, req := range dep.Requirements {
+		if req.Type == workflow.RequirementExpression {
+			job.DependsOn = append(job.DependsOn, workflow.ExpressionJobNames(req.Expression)...)
+			continue
+		}
+		job.DependsOn = append(job.DependsOn, req.JobID)
+	}
+
+	// Jobs that never started still carry their name as ID
+	if dep.JobID == dep.InternalName {
+		return job
+	}
+	job.UUID = dep.JobID
+
+	if record, ok := src.Jobs(dep.JobID); ok {
+		job.Status = string(record.Status)
+		job.ExitCode = record.ExitCode
+		if !record.StartTime.IsZero() {
+			start := record.StartTime
+			job.StartTime = &start
+			end := now
+			if record.EndTime != nil {
+				end = *record.EndTime
+				job.EndTime = record.EndTime
+			}
+			job.DurationSeconds = end.Sub(start).Seconds()
+		}
+		job.Limits = &Limits{
+			MaxCPU:      record.Limits.CPU.Value(),
+			MaxMemoryMB: record.Limits.Memory.Megabytes(),
+			MaxIOBPS:    record.Limits.IOBandwidth.BytesPerSecond(),
+			CPUCores:    record.Limits.CPUCores.String(),
+			Runtime:     record.Runtime,
+		}
+	} else {
+		job.Note = "job record no longer kept on the node"
+	}
+
+	if src.Persist == nil {
+		return job
+	}
+	if usage, err := queryUsage(ctx, src.Persist, dep.JobID); err == nil && usage.Samples > 0 {
+		job.Usage = usage
+	}
+	if job.Failed() {
+		lines, err := queryLogTail(ctx, src.Persist, dep.JobID, logLines)
+		if err != nil {
+			job.Note = "logs unavailable: " + err.Error()
+		}
+		job.LogExcerpt = lines
+	}
+	return job
+}
+
+// queryUsage summarizes the job's persisted metrics samples. Disk and
+// network counters are totals, so the highest sample is the job's total.
+func queryUsage(ctx context.Context, persist persistpb.PersistServiceClient, jobID string) (*Usage, error) {
+	stream, err := persist.QueryMetrics(ctx, &persistpb.QueryMetricsRequest{JobId: jobID})
+	if err != nil {
+		return nil, err
+	}
+	usage := &Usage{}
+	var cpuTotal float64
+	for {
+		metric, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data := metric.GetData()
+		if data == nil {
+			continue
+		}
+		usage.Samples++
+		cpu := data.CpuUsage * 100
+		cpuTotal += cpu
+		usage.PeakCPUPercent = max(usage.PeakCPUPercent, cpu)
+		usage.PeakMemoryBytes = max(usage.PeakMemoryBytes, data.MemoryUsage)
+		if disk := data.DiskIo; disk != nil {
+			usage.DiskReadBytes = max(usage.DiskReadBytes, disk.ReadBytes)
+			usage.DiskWriteBytes = max(usage.DiskWriteBytes, disk.WriteBytes)
+		}
+		if net := data.NetworkIo; net != nil {
+			usage.NetRxBytes = max(usage.NetRxBytes, net.RxBytes)
+			usage.NetTxBytes = max(usage.NetTxBytes, net.TxBytes)
+		}
+	}
+	if usage.Samples > 0 {
+		usage.AvgCPUPercent = cpuTotal / float64(usage.Samples)
+	}
+	return usage, nil
+}
+
+// queryLogTail returns the last lines of the job's persisted log, stdout
+// followed by stderr as persist returns them
+func queryLogTail(ctx context.Context, persist persistpb.PersistServiceClient, jobID string, lines int) ([]string, error) {
+	stream, err := persist.QueryLogs(ctx, &persistpb.QueryLogsRequest{JobId: jobID})
+	if err != nil {
+		return nil, err
+	}
+	tail := make([]string, 0, lines)
+	var partial bytes.Buffer
+	add := func(line string) {
+		if len(line) > maxLineBytes {
+			line = line[:maxLineBytes]
+		}
+		if len(tail) == lines {
+			tail = append(tail[:0], tail[1:]...)
+		}
+		tail = append(tail, line)
+	}
+	for {
+		record, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return tail, err
+		}
+		partial.Write(record.Content)
+		for {
+			i := bytes.IndexByte(partial.Bytes(), '\n')
+			if i < 0 {
+				break
+			}
+			add(strings.TrimSuffix(string(partial.Next(i + 1)[:i]), "\r"))
+		}
+		for partial.Len() > maxLineBytes {
+			add(string(partial.Next(maxLineBytes)))
+		}
+	}
+	if partial.Len() > 0 {
+		add(partial.String())
+	}
+	return tail, nil
+}
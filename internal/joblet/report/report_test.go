@@ -0,0 +1,164 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+
+	"google.golang.org/grpc"
+)
+
+// fakePersist answers with fixed metrics and log records per job
+type fakePersist struct {
+	persistpb.PersistServiceClient
+	metrics map[string][]*persistpb.Metric
+	logs    map[string][]*persistpb.LogLine
+}
+
+type sliceStream[T any] struct {
+	grpc.ClientStream
+	items []T
+}
+
+func (s *sliceStream[T]) Recv() (T, error) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, io.EOF
+	}
+	item := s.items[0]
+	s.items = s.items[1:]
+	return item, nil
+}
+
+func (f *fakePersist) QueryMetrics(_ context.Context, req *persistpb.QueryMetricsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[persistpb.Metric], error) {
+	return &sliceStream[*persistpb.Metric]{items: f.metrics[req.JobId]}, nil
+}
+
+func (f *fakePersist) QueryLogs(_ context.Context, req *persistpb.QueryLogsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[persistpb.LogLine], error) {
+	return &sliceStream[*persistpb.LogLine]{items: f.logs[req.JobId]}, nil
+}
+
+func testReport(t *testing.T) *Report {
+	t.Helper()
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	extractEnd, trainEnd := start.Add(30*time.Second), start.Add(90*time.Second)
+	state := &workflow.WorkflowState{
+		Workflow: "etl.yaml",
+		Status:   workflow.WorkflowFailed,
+		Jobs: map[string]*workflow.JobDependency{
+			"job-1":  {JobID: "job-1", InternalName: "extract", Status: domain.StatusCompleted},
+			"job-2":  {JobID: "job-2", InternalName: "train", Status: domain.StatusFailed, Requirements: []workflow.Requirement{{JobID: "extract"}}},
+			"report": {JobID: "report", InternalName: "report", Status: domain.StatusCanceled, Requirements: []workflow.Requirement{{JobID: "train"}}},
+		},
+		JobOrder:      []string{"extract", "train", "report"},
+		StartedAt:     &start,
+		CompletedAt:   &trainEnd,
+		TotalJobs:     3,
+		CompletedJobs: 1,
+		FailedJobs:    1,
+		CanceledJobs:  1,
+	}
+	records := map[string]*domain.Job{
+		"job-1": {Uuid: "job-1", Status: domain.StatusCompleted, StartTime: start, EndTime: &extractEnd},
+		"job-2": {Uuid: "job-2", Status: domain.StatusFailed, ExitCode: 2, StartTime: extractEnd, EndTime: &trainEnd},
+	}
+	persist := &fakePersist{
+		metrics: map[string][]*persistpb.Metric{
+			"job-2": {
+				{Data: &persistpb.MetricData{CpuUsage: 0.5, MemoryUsage: 100 << 20, DiskIo: &persistpb.DiskIO{ReadBytes: 10}}},
+				{Data: &persistpb.MetricData{CpuUsage: 1.5, MemoryUsage: 300 << 20, DiskIo: &persistpb.DiskIO{ReadBytes: 50}}},
+			},
+		},
+		logs: map[string][]*persistpb.LogLine{
+			"job-2": {
+				{Content: []byte("loading\nepoch 1\n")},
+				{Content: []byte("epoch 2\nTraceback <most recent call last>\n")},
+				{Content: []byte("ValueError: bad input")},
+			},
+		},
+	}
+	src := Source{
+		Jobs:    func(uuid string) (*domain.Job, bool) { job, ok := records[uuid]; return job, ok },
+		Persist: persist,
+	}
+	return Build(context.Background(), state, "wf-uuid", src, 3, trainEnd)
+}
+
+func TestBuild(t *testing.T) {
+	r := testReport(t)
+
+	if r.DurationSeconds != 90 || len(r.Jobs) != 3 {
+		t.Fatalf("report = %+v", r)
+	}
+	extract, train, report := r.Jobs[0], r.Jobs[1], r.Jobs[2]
+	if extract.Name != "extract" || extract.DurationSeconds != 30 || extract.Failed() || extract.Usage != nil {
+		t.Errorf("extract = %+v", extract)
+	}
+	if train.Name != "train" || !train.Failed() || train.ExitCode != 2 || train.DurationSeconds != 60 ||
+		strings.Join(train.DependsOn, ",") != "extract" {
+		t.Errorf("train = %+v", train)
+	}
+	if u := train.Usage; u == nil || u.Samples != 2 || u.AvgCPUPercent != 100 || u.PeakCPUPercent != 150 ||
+		u.PeakMemoryBytes != 300<<20 || u.DiskReadBytes != 50 {
+		t.Errorf("train usage = %+v", train.Usage)
+	}
+	want := []string{"epoch 2", "Traceback <most recent call last>", "ValueError: bad input"}
+	if strings.Join(train.LogExcerpt, "|") != strings.Join(want, "|") {
+		t.Errorf("train log excerpt = %q, want %q", train.LogExcerpt, want)
+	}
+	if report.UUID != "" || !report.Skipped() || report.LogExcerpt != nil {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := testReport(t)
+
+	data, err := Render(r, FormatJSON)
+	if err != nil {
+		t.Fatalf("Render(json) error = %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.WorkflowUUID != "wf-uuid" || len(decoded.Jobs) != 3 {
+		t.Errorf("Render(json) = %s, %v", data, err)
+	}
+
+	data, err = Render(r, FormatJUnit)
+	if err != nil {
+		t.Fatalf("Render(junit) error = %v", err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("Render(junit) is not XML: %v\n%s", err, data)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Skipped != 1 || len(suites.Suites) != 1 {
+		t.Errorf("junit totals = %+v", suites)
+	}
+	train := suites.Suites[0].Cases[1]
+	if train.Failure == nil || train.Failure.Message != "FAILED with exit code 2" || !strings.Contains(train.Failure.Text, "ValueError") {
+		t.Errorf("junit train case = %+v", train)
+	}
+
+	data, err = Render(r, FormatHTML)
+	if err != nil {
+		t.Fatalf("Render(html) error = %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"<h1>Workflow etl.yaml</h1>", "train: FAILED (exit code 2)", "Traceback &lt;most recent call last&gt;", "300.0 MiB"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Render(html) lacks %q", want)
+		}
+	}
+
+	if _, err := Render(r, "pdf"); err == nil {
+		t.Error("Render(pdf) succeeded, want an error")
+	}
+}
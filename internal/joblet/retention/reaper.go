@@ -0,0 +1,181 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// Store is the part of the job store the reaper prunes
+type Store interface {
+	ListJobs() []*domain.Job
+	DeleteJob(jobID string) error
+}
+
+// Status is the reaper's policy together with the outcome of its sweeps
+type Status struct {
+	Policy       config.RetentionConfig
+	LastSweep    time.Time // Zero until the first sweep
+	LastRemoved  int
+	TotalRemoved int64
+}
+
+// Reaper removes finished job records beyond the retention limits. Records
+// are archived to persist first when archiving is on; a record whose archive
+// fails stays in the store for the next sweep.
+type Reaper struct {
+	policy  config.RetentionConfig
+	store   Store
+	persist persistpb.PersistServiceClient // nil when persist is unavailable
+	logger  *logger.Logger
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewReaper creates a reaper for the policy. persist may be nil, in which
+// case records are removed without an archive.
+func NewReaper(policy config.RetentionConfig, store Store, persist persistpb.PersistServiceClient) *Reaper {
+	return &Reaper{
+		policy:  policy,
+		store:   store,
+		persist: persist,
+		logger:  logger.WithField("component", "retention"),
+		status:  Status{Policy: policy},
+	}
+}
+
+// Run sweeps on every interval until ctx is done. It returns right away when
+// no retention limit is set.
+func (r *Reaper) Run(ctx context.Context) {
+	if !r.policy.Enabled() {
+		return
+	}
+	r.logger.Info("job retention enabled", "keepLast", r.policy.KeepLast, "maxAge", r.policy.MaxAge,
+		"interval", r.policy.Interval, "archive", r.policy.Archive && r.persist != nil)
+
+	ticker := time.NewTicker(r.policy.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Sweep(ctx, time.Now())
+		}
+	}
+}
+
+// Sweep removes the records expired at now and returns how many it removed
+func (r *Reaper) Sweep(ctx context.Context, now time.Time) int {
+	removed := 0
+	for _, job := range Expired(r.store.ListJobs(), r.policy, now) {
+		log := r.logger.WithField("jobID", job.Uuid)
+		if r.policy.Archive && r.persist != nil {
+			if err := Archive(ctx, r.persist, job, now); err != nil {
+				log.Warn("failed to archive job record, keeping it", "error", err)
+				continue
+			}
+		}
+		if err := r.store.DeleteJob(job.Uuid); err != nil {
+			log.Warn("failed to remove expired job record", "error", err)
+			continue
+		}
+		removed++
+	}
+
+	r.mu.Lock()
+	r.status.LastSweep = now
+	r.status.LastRemoved = removed
+	r.status.TotalRemoved += int64(removed)
+	r.mu.Unlock()
+
+	if removed > 0 {
+		r.logger.Info("removed expired job records", "count", removed)
+	}
+	return removed
+}
+
+// Status returns the policy and the outcome of the sweeps so far
+func (r *Reaper) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// Archive sends the job record, with secret values masked, to persist
+func Archive(ctx context.Context, persist persistpb.PersistServiceClient, job *domain.Job, now time.Time) error {
+	record := job.DeepCopy()
+	record.SecretEnvironment = job.MaskedSecretEnvironment()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode job record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	resp, err := persist.ArchiveJob(ctx, &persistpb.ArchiveJobRequest{
+		JobId:      job.Uuid,
+		Record:     data,
+		ArchivedAt: now.UnixNano(),
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("persist archive failed: %s", resp.Message)
+	}
+	return nil
+}
+
+// Expired returns the finished jobs the policy removes at now: those beyond
+// the newest KeepLast of their name, and those that ended more than MaxAge
+// ago. Jobs without a name are counted per command.
+func Expired(jobs []*domain.Job, policy config.RetentionConfig, now time.Time) []*domain.Job {
+	byName := make(map[string][]*domain.Job)
+	for _, job := range jobs {
+		if !finished(job) {
+			continue
+		}
+		key := job.Name
+		if key == "" {
+			key = job.Command
+		}
+		byName[key] = append(byName[key], job)
+	}
+
+	var expired []*domain.Job
+	for _, group := range byName {
+		// Newest first
+		sort.Slice(group, func(i, j int) bool { return endedAt(group[i]).After(endedAt(group[j])) })
+		for i, job := range group {
+			tooMany := policy.KeepLast > 0 && i >= policy.KeepLast
+			tooOld := policy.MaxAge > 0 && now.Sub(endedAt(job)) > policy.MaxAge
+			if tooMany || tooOld {
+				expired = append(expired, job)
+			}
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return endedAt(expired[i]).Before(endedAt(expired[j])) })
+	return expired
+}
+
+func finished(job *domain.Job) bool {
+	return job.IsCompleted() || job.Status == domain.StatusCanceled || job.Status == domain.StatusExpired
+}
+
+// endedAt is when the job finished, its start time when it never ran
+func endedAt(job *domain.Job) time.Time {
+	if job.EndTime != nil {
+		return *job.EndTime
+	}
+	return job.StartTime
+}
@@ -0,0 +1,118 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+)
+
+var now = time.Date(2025, 8, 3, 12, 0, 0, 0, time.UTC)
+
+func finishedJob(id, name string, endedAgo time.Duration) *domain.Job {
+	end := now.Add(-endedAgo)
+	return &domain.Job{Uuid: id, Name: name, Command: "python3", Status: domain.StatusCompleted, StartTime: end.Add(-time.Minute), EndTime: &end}
+}
+
+func ids(jobs []*domain.Job) []string {
+	out := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		out = append(out, job.Uuid)
+	}
+	return out
+}
+
+func TestExpired_KeepLastPerName(t *testing.T) {
+	jobs := []*domain.Job{
+		finishedJob("a1", "train", 3*time.Hour),
+		finishedJob("a2", "train", 2*time.Hour),
+		finishedJob("a3", "train", 1*time.Hour),
+		finishedJob("b1", "report", 5*time.Hour),
+		{Uuid: "running", Name: "train", Status: domain.StatusRunning, StartTime: now.Add(-10 * time.Hour)},
+	}
+
+	got := ids(Expired(jobs, config.RetentionConfig{KeepLast: 2}, now))
+	if len(got) != 1 || got[0] != "a1" {
+		t.Errorf("expired = %v, want only the oldest train job", got)
+	}
+}
+
+func TestExpired_MaxAgeAndUnnamedJobs(t *testing.T) {
+	jobs := []*domain.Job{
+		finishedJob("old", "", 48*time.Hour),
+		finishedJob("new", "", time.Hour),
+		{Uuid: "canceled", Command: "sleep", Status: domain.StatusCanceled, StartTime: now.Add(-72 * time.Hour)},
+	}
+
+	got := ids(Expired(jobs, config.RetentionConfig{MaxAge: 24 * time.Hour}, now))
+	if len(got) != 2 || got[0] != "canceled" || got[1] != "old" {
+		t.Errorf("expired = %v, want [canceled old] oldest first", got)
+	}
+}
+
+type fakeStore struct {
+	jobs    []*domain.Job
+	deleted []string
+}
+
+func (f *fakeStore) ListJobs() []*domain.Job { return f.jobs }
+
+func (f *fakeStore) DeleteJob(jobID string) error {
+	f.deleted = append(f.deleted, jobID)
+	return nil
+}
+
+type fakePersist struct {
+	persistpb.PersistServiceClient
+	archived []*persistpb.ArchiveJobRequest
+	failFor  string
+}
+
+func (f *fakePersist) ArchiveJob(_ context.Context, req *persistpb.ArchiveJobRequest, _ ...grpc.CallOption) (*persistpb.ArchiveJobResponse, error) {
+	if req.JobId == f.failFor {
+		return nil, errors.New("persist unavailable")
+	}
+	f.archived = append(f.archived, req)
+	return &persistpb.ArchiveJobResponse{Success: true}, nil
+}
+
+func TestReaper_SweepArchivesBeforeRemoval(t *testing.T) {
+	secret := finishedJob("a1", "train", 3*time.Hour)
+	secret.SecretEnvironment = map[string]string{"TOKEN": "hunter2"}
+	store := &fakeStore{jobs: []*domain.Job{
+		secret,
+		finishedJob("a2", "train", 2*time.Hour),
+		finishedJob("a3", "train", 1*time.Hour),
+	}}
+	persist := &fakePersist{failFor: "a2"}
+	policy := config.RetentionConfig{KeepLast: 1, Interval: time.Minute, Archive: true}
+	r := NewReaper(policy, store, persist)
+
+	if removed := r.Sweep(context.Background(), now); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	// a2 could not be archived and is kept for the next sweep
+	if len(store.deleted) != 1 || store.deleted[0] != "a1" {
+		t.Errorf("deleted = %v, want [a1]", store.deleted)
+	}
+
+	var record domain.Job
+	if err := json.Unmarshal(persist.archived[0].Record, &record); err != nil {
+		t.Fatalf("archived record: %v", err)
+	}
+	if record.Uuid != "a1" || record.SecretEnvironment["TOKEN"] != "***" {
+		t.Errorf("archived record = %s/%v, want a1 with masked secrets", record.Uuid, record.SecretEnvironment)
+	}
+
+	status := r.Status()
+	if !status.LastSweep.Equal(now) || status.LastRemoved != 1 || status.TotalRemoved != 1 {
+		t.Errorf("status = %+v", status)
+	}
+}
@@ -0,0 +1,326 @@
+// Package channels keeps the stable and canary channels of runtime names.
+//
+// Admins pin a new runtime version to the canary channel of a name; jobs
+// asking for "python@canary" run on it while every other job asking for
+// python keeps its stable runtime. The failure rates of both are compared
+// from the job store, and promoting the canary makes it the stable runtime of
+// the name in one step. Pins are kept in a JSON file so they survive restarts.
+package channels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Channels jobs may ask for after a runtime name
+const (
+	Stable = "stable"
+	Canary = "canary"
+)
+
+// ErrNoCanary is returned when promoting or comparing a name without a canary
+var ErrNoCanary = errors.New("no canary pinned")
+
+// Pin is where the channels of a runtime name point
+type Pin struct {
+	Name        string    `json:"name"`
+	Stable      string    `json:"stable,omitempty"` // Empty = what the name resolves to without channels
+	StableSince time.Time `json:"stable_since,omitempty"`
+	Canary      string    `json:"canary,omitempty"`
+	CanarySince time.Time `json:"canary_since,omitempty"`
+	UpdatedBy   string    `json:"updated_by,omitempty"`
+}
+
+// Store keeps the channel pins in a file; a nil store has no pins
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	pins map[string]Pin
+}
+
+// Open loads the pins kept in path, starting without any when it does not
+// exist yet
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no runtime channels file configured")
+	}
+	s := &Store{path: path, pins: make(map[string]Pin)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pins []Pin
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for _, pin := range pins {
+		s.pins[pin.Name] = pin
+	}
+	return s, nil
+}
+
+// SplitChannel splits "python@canary" into its name and channel. ok is false
+// for specs without a channel, such as "python-3.11@1.2.0".
+func SplitChannel(spec string) (name, channel string, ok bool) {
+	i := strings.LastIndex(spec, "@")
+	if i <= 0 {
+		return spec, "", false
+	}
+	switch spec[i+1:] {
+	case Stable, Canary:
+		return spec[:i], spec[i+1:], true
+	}
+	return spec, "", false
+}
+
+// Get returns the pin of a runtime name
+func (s *Store) Get(name string) (Pin, bool) {
+	if s == nil {
+		return Pin{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pin, ok := s.pins[name]
+	return pin, ok
+}
+
+// List returns the pins in name order
+func (s *Store) List() []Pin {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins := make([]Pin, 0, len(s.pins))
+	for _, pin := range s.pins {
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Name < pins[j].Name })
+	return pins
+}
+
+// SetCanary pins target to the canary channel of name, replacing the canary
+// it had
+func (s *Store) SetCanary(name, target, by string, now time.Time) (Pin, error) {
+	if err := validatePin(name, target); err != nil {
+		return Pin{}, err
+	}
+	return s.update(name, func(pin *Pin) error {
+		pin.Canary, pin.CanarySince, pin.UpdatedBy = target, now, by
+		return nil
+	})
+}
+
+// ClearCanary removes the canary of name, sending its jobs back to stable
+func (s *Store) ClearCanary(name, by string) (Pin, error) {
+	return s.update(name, func(pin *Pin) error {
+		if pin.Canary == "" {
+			return fmt.Errorf("%w for %s", ErrNoCanary, name)
+		}
+		pin.Canary, pin.CanarySince, pin.UpdatedBy = "", time.Time{}, by
+		return nil
+	})
+}
+
+// Promote makes the canary of name its stable runtime
+func (s *Store) Promote(name, by string, now time.Time) (Pin, error) {
+	return s.update(name, func(pin *Pin) error {
+		if pin.Canary == "" {
+			return fmt.Errorf("%w for %s", ErrNoCanary, name)
+		}
+		pin.Stable, pin.StableSince, pin.UpdatedBy = pin.Canary, now, by
+		pin.Canary, pin.CanarySince = "", time.Time{}
+		return nil
+	})
+}
+
+// update changes the pin of name and writes the pins, keeping the previous
+// pin when the write fails
+func (s *Store) update(name string, change func(*Pin) error) (Pin, error) {
+	if s == nil {
+		return Pin{}, fmt.Errorf("runtime channels are unavailable")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pin, ok := s.pins[name]
+	if !ok {
+		pin = Pin{Name: name}
+	}
+	if err := change(&pin); err != nil {
+		return Pin{}, err
+	}
+	previous, existed := s.pins[name]
+	if pin.Stable == "" && pin.Canary == "" {
+		delete(s.pins, name)
+	} else {
+		s.pins[name] = pin
+	}
+	if err := s.save(); err != nil {
+		if existed {
+			s.pins[name] = previous
+		} else {
+			delete(s.pins, name)
+		}
+		return Pin{}, err
+	}
+	return pin, nil
+}
+
+// save replaces the file atomically; callers hold the lock
+func (s *Store) save() error {
+	pins := make([]Pin, 0, len(s.pins))
+	for _, pin := range s.pins {
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Name < pins[j].Name })
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// validatePin checks that a channel points a runtime name to an exact runtime
+func validatePin(name, target string) error {
+	if name == "" || target == "" {
+		return fmt.Errorf("a runtime channel needs a runtime name and a target")
+	}
+	if strings.Contains(name, "@") {
+		return fmt.Errorf("runtime name %q must not carry a version or channel", name)
+	}
+	if _, _, ok := SplitChannel(target); ok {
+		return fmt.Errorf("channel target %q must be an exact runtime, not a channel", target)
+	}
+	if target == name {
+		return fmt.Errorf("channel target of %s must differ from the name", name)
+	}
+	return nil
+}
+
+// Resolve returns the exact runtime a tenant's job asking for spec runs on.
+// "name@canary" runs on the canary of name and falls back to stable without
+// one. Otherwise the tenant's pin for name wins, then the stable channel,
+// then the server alias. pinnedBy is empty when spec was not resolved.
+func (s *Store) Resolve(cfg *config.Config, tenant, spec string) (resolved, pinnedBy string) {
+	name, channel, hasChannel := SplitChannel(spec)
+	pin, _ := s.Get(name)
+	if channel == Canary && pin.Canary != "" {
+		return pin.Canary, "canary channel"
+	}
+	if target, pinnedBy, pinned := cfg.TenantRuntimePin(tenant, name); pinned {
+		return target, pinnedBy
+	}
+	if pin.Stable != "" {
+		return pin.Stable, "stable channel"
+	}
+	resolved, pinnedBy = cfg.ResolveRuntime(tenant, name)
+	if hasChannel && pinnedBy == "" {
+		pinnedBy = "stable channel"
+	}
+	return resolved, pinnedBy
+}
+
+// Stats counts the jobs that ran on one side of a canary
+type Stats struct {
+	Runtime  string  `json:"runtime"`
+	Jobs     int     `json:"jobs"`     // Started since the canary was pinned
+	Finished int     `json:"finished"` // Completed or failed
+	Failed   int     `json:"failed"`
+	Rate     float64 `json:"failureRate"` // Failed / finished, 0 without finished jobs
+}
+
+// Verdicts of a comparison
+const (
+	VerdictPending   = "pending"   // Too few finished canary jobs
+	VerdictHealthy   = "healthy"   // The canary fails no more than allowed
+	VerdictRegressed = "regressed" // The canary fails more than stable by over the allowed increase
+)
+
+// Comparison sets the failure rate of the canary of a name against its
+// stable runtime over the same period
+type Comparison struct {
+	Name    string    `json:"name"`
+	Since   time.Time `json:"since"`
+	Canary  Stats     `json:"canary"`
+	Stable  Stats     `json:"stable"`
+	Verdict string    `json:"verdict"`
+}
+
+// Compare counts the jobs started since the canary of pin was pinned on the
+// canary and on stable, the runtime name resolves to outside the canary
+func Compare(pin Pin, stable string, jobs []*domain.Job, cfg config.RuntimeChannelsConfig) (Comparison, error) {
+	if pin.Canary == "" {
+		return Comparison{}, fmt.Errorf("%w for %s", ErrNoCanary, pin.Name)
+	}
+	c := Comparison{
+		Name:   pin.Name,
+		Since:  pin.CanarySince,
+		Canary: Stats{Runtime: pin.Canary},
+		Stable: Stats{Runtime: stable},
+	}
+	for _, job := range jobs {
+		if job.StartTime.Before(pin.CanarySince) {
+			continue
+		}
+		switch job.Runtime {
+		case pin.Canary:
+			c.Canary.add(job.Status)
+		case stable:
+			c.Stable.add(job.Status)
+		}
+	}
+	c.Canary.rate()
+	c.Stable.rate()
+
+	switch {
+	case c.Canary.Finished < cfg.MinSamples || c.Canary.Finished == 0:
+		c.Verdict = VerdictPending
+	case c.Canary.Rate > c.Stable.Rate+cfg.MaxRateIncrease:
+		c.Verdict = VerdictRegressed
+	default:
+		c.Verdict = VerdictHealthy
+	}
+	return c, nil
+}
+
+func (s *Stats) add(status domain.JobStatus) {
+	s.Jobs++
+	switch status {
+	case domain.StatusCompleted:
+		s.Finished++
+	case domain.StatusFailed:
+		s.Finished++
+		s.Failed++
+	}
+}
+
+func (s *Stats) rate() {
+	if s.Finished > 0 {
+		s.Rate = float64(s.Failed) / float64(s.Finished)
+	}
+}
@@ -0,0 +1,170 @@
+package channels
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+func TestSplitChannel(t *testing.T) {
+	tests := []struct {
+		spec, name, channel string
+		ok                  bool
+	}{
+		{"python@canary", "python", Canary, true},
+		{"python@stable", "python", Stable, true},
+		{"python-3.11@1.2.0", "python-3.11@1.2.0", "", false},
+		{"python", "python", "", false},
+		{"@canary", "@canary", "", false},
+	}
+	for _, tt := range tests {
+		name, channel, ok := SplitChannel(tt.spec)
+		if name != tt.name || channel != tt.channel || ok != tt.ok {
+			t.Errorf("SplitChannel(%q) = %q, %q, %v; want %q, %q, %v", tt.spec, name, channel, ok, tt.name, tt.channel, tt.ok)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "channels.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := store.SetCanary("python", "python@canary", "admin", now); err == nil {
+		t.Error("SetCanary() accepted a channel as target")
+	}
+	if _, err := store.Promote("python", "admin", now); !errors.Is(err, ErrNoCanary) {
+		t.Errorf("Promote() without a canary = %v", err)
+	}
+	if _, err := store.SetCanary("python", "python-3.12@2.0.0", "admin", now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pins survive a restart
+	store, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pin, ok := store.Get("python")
+	if !ok || pin.Canary != "python-3.12@2.0.0" || !pin.CanarySince.Equal(now) || pin.UpdatedBy != "admin" {
+		t.Fatalf("Get() after reopening = %+v, %v", pin, ok)
+	}
+
+	pin, err = store.Promote("python", "ops", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pin.Stable != "python-3.12@2.0.0" || pin.Canary != "" || !pin.StableSince.Equal(now.Add(time.Hour)) {
+		t.Errorf("Promote() = %+v", pin)
+	}
+	if _, err := store.ClearCanary("python", "ops"); !errors.Is(err, ErrNoCanary) {
+		t.Errorf("ClearCanary() without a canary = %v", err)
+	}
+
+	// A name without pins left is dropped
+	if _, err := store.SetCanary("java", "openjdk-22", "ops", now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ClearCanary("java", "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if pins := store.List(); len(pins) != 1 || pins[0].Name != "python" {
+		t.Errorf("List() = %+v", pins)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cfg := &config.Config{
+		Runtime: config.RuntimeConfig{Aliases: map[string]string{"python": "python-3.11@1.2.0", "java": "openjdk-21"}},
+		Tenants: []config.TenantConfig{{Name: "ml", RuntimePins: map[string]string{"python": "python-3.11-ml@2.0.0"}}},
+	}
+	store, err := Open(filepath.Join(t.TempDir(), "channels.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if _, err := store.SetCanary("python", "python-3.12@2.0.0", "admin", now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetCanary("java", "openjdk-22", "admin", now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Promote("java", "admin", now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		store              *Store
+		tenant, spec       string
+		resolved, pinnedBy string
+	}{
+		{store, "", "python", "python-3.11@1.2.0", "server alias"},
+		{store, "", "python@stable", "python-3.11@1.2.0", "server alias"},
+		{store, "", "python@canary", "python-3.12@2.0.0", "canary channel"},
+		{store, "ml", "python", "python-3.11-ml@2.0.0", "tenant ml pin"},
+		{store, "ml", "python@canary", "python-3.12@2.0.0", "canary channel"},
+		{store, "", "java", "openjdk-22", "stable channel"},
+		{store, "", "java@canary", "openjdk-22", "stable channel"},
+		{store, "", "go@stable", "go", "stable channel"},
+		{store, "", "go-1.22", "go-1.22", ""},
+		{nil, "", "python@canary", "python-3.11@1.2.0", "server alias"},
+		{nil, "", "java", "openjdk-21", "server alias"},
+	}
+	for _, tt := range tests {
+		resolved, pinnedBy := tt.store.Resolve(cfg, tt.tenant, tt.spec)
+		if resolved != tt.resolved || pinnedBy != tt.pinnedBy {
+			t.Errorf("Resolve(%q, %q) = %q, %q; want %q, %q", tt.tenant, tt.spec, resolved, pinnedBy, tt.resolved, tt.pinnedBy)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	since := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	pin := Pin{Name: "python", Canary: "python-3.12@2.0.0", CanarySince: since}
+	job := func(runtime string, status domain.JobStatus, started time.Time) *domain.Job {
+		return &domain.Job{Runtime: runtime, Status: status, StartTime: started}
+	}
+	after := since.Add(time.Minute)
+	jobs := []*domain.Job{
+		job("python-3.12@2.0.0", domain.StatusCompleted, after),
+		job("python-3.12@2.0.0", domain.StatusFailed, after),
+		job("python-3.12@2.0.0", domain.StatusRunning, after),
+		job("python-3.11@1.2.0", domain.StatusCompleted, after),
+		job("python-3.11@1.2.0", domain.StatusCompleted, after),
+		job("python-3.11@1.2.0", domain.StatusCompleted, after),
+		job("python-3.11@1.2.0", domain.StatusFailed, after),
+		job("python-3.11@1.2.0", domain.StatusFailed, since.Add(-time.Minute)), // Before the canary
+		job("openjdk-21", domain.StatusFailed, after),
+	}
+
+	c, err := Compare(pin, "python-3.11@1.2.0", jobs, config.RuntimeChannelsConfig{MinSamples: 2, MaxRateIncrease: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Canary.Jobs != 3 || c.Canary.Finished != 2 || c.Canary.Failed != 1 || c.Canary.Rate != 0.5 {
+		t.Errorf("canary = %+v", c.Canary)
+	}
+	if c.Stable.Jobs != 4 || c.Stable.Finished != 4 || c.Stable.Failed != 1 || c.Stable.Rate != 0.25 {
+		t.Errorf("stable = %+v", c.Stable)
+	}
+	if c.Verdict != VerdictRegressed {
+		t.Errorf("verdict = %s, want %s", c.Verdict, VerdictRegressed)
+	}
+
+	if c, _ := Compare(pin, "python-3.11@1.2.0", jobs, config.RuntimeChannelsConfig{MinSamples: 2, MaxRateIncrease: 0.3}); c.Verdict != VerdictHealthy {
+		t.Errorf("verdict with a larger allowance = %s", c.Verdict)
+	}
+	if c, _ := Compare(pin, "python-3.11@1.2.0", jobs, config.RuntimeChannelsConfig{MinSamples: 5}); c.Verdict != VerdictPending {
+		t.Errorf("verdict with too few samples = %s", c.Verdict)
+	}
+	if _, err := Compare(Pin{Name: "python"}, "python-3.11@1.2.0", jobs, config.RuntimeChannelsConfig{}); !errors.Is(err, ErrNoCanary) {
+		t.Errorf("Compare() without a canary = %v", err)
+	}
+}
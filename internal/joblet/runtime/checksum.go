@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedChecksum is a runtime checksum with the runtime.yml it was computed
+// for; reinstalling a runtime rewrites runtime.yml
+type cachedChecksum struct {
+	sum     string
+	modTime time.Time
+	size    int64
+}
+
+// Checksum identifies the installed build of a runtime: a SHA-256 over its
+// runtime.yml and the path, mode and size of every file below the runtime
+// directory. Other file contents are not read, runtimes run to gigabytes.
+// Checksums are cached until runtime.yml changes.
+func (r *Resolver) Checksum(spec string) (string, error) {
+	runtimeDir, err := r.FindRuntimeDirectory(spec)
+	if err != nil {
+		return "", fmt.Errorf("runtime not found: %w", err)
+	}
+	configPath := filepath.Join(runtimeDir, "runtime.yml")
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat runtime config: %w", err)
+	}
+
+	r.mu.Lock()
+	cached, ok := r.checksums[runtimeDir]
+	r.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.sum, nil
+	}
+
+	config, err := r.platform.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read runtime config: %w", err)
+	}
+	h := sha256.New()
+	h.Write(config)
+	err = filepath.WalkDir(runtimeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		entry, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(runtimeDir, path)
+		fmt.Fprintf(h, "%s\x00%o\x00%d\x00", rel, entry.Mode(), entry.Size())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to walk runtime directory: %w", err)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	r.mu.Lock()
+	if r.checksums == nil {
+		r.checksums = make(map[string]cachedChecksum)
+	}
+	r.checksums[runtimeDir] = cachedChecksum{sum: sum, modTime: info.ModTime(), size: info.Size()}
+	r.mu.Unlock()
+	return sum, nil
+}
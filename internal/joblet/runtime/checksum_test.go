@@ -0,0 +1,43 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/platform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Checksum(t *testing.T) {
+	runtimesPath := filepath.Join(t.TempDir(), "runtimes")
+	runtimeDir := filepath.Join(runtimesPath, "python-3.11")
+	require.NoError(t, os.MkdirAll(filepath.Join(runtimeDir, "bin"), 0755))
+	configPath := filepath.Join(runtimeDir, "runtime.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("name: python-3.11\nversion: \"3.11.9\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(runtimeDir, "bin", "python3"), []byte("v1"), 0755))
+
+	resolver := NewResolver(runtimesPath, platform.NewPlatform())
+
+	first, err := resolver.Checksum("python-3.11")
+	require.NoError(t, err)
+	assert.Len(t, first, 64)
+
+	again, err := resolver.Checksum("python-3.11")
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	// A reinstall rewrites runtime.yml, which invalidates the cached checksum
+	require.NoError(t, os.WriteFile(filepath.Join(runtimeDir, "bin", "python3"), []byte("v2 build"), 0755))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(configPath, later, later))
+	reinstalled, err := resolver.Checksum("python-3.11")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, reinstalled)
+
+	_, err = resolver.Checksum("non-existent")
+	assert.Error(t, err)
+}
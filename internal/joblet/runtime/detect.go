@@ -0,0 +1,196 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// dependencyFile is a file naming a job's dependencies, the runtime languages
+// that install them, and how
+type dependencyFile struct {
+	name      string
+	languages []string // runtime.yml language values, first match wins
+	// install returns the shell commands installing the dependencies, into
+	// dir when the install is cached, next to the file otherwise
+	install func(dir string) string
+}
+
+// dependencyFiles are the files detected, in order of preference when a job
+// uploads several
+var dependencyFiles = []dependencyFile{
+	{
+		name:      "requirements.txt",
+		languages: []string{"python"},
+		install: func(dir string) string {
+			if dir == "" {
+				return `python3 -m pip install --quiet --disable-pip-version-check --target .deps/python -r requirements.txt && ` +
+					`export PYTHONPATH="$PWD/.deps/python${PYTHONPATH:+:$PYTHONPATH}"`
+			}
+			return cachedInstall(dir, `python3 -m pip install --quiet --disable-pip-version-check --target "$t" -r requirements.txt`) +
+				` && export PYTHONPATH=` + shellQuote(dir) + `"${PYTHONPATH:+:$PYTHONPATH}"`
+		},
+	},
+	{
+		name:      "package.json",
+		languages: []string{"node", "nodejs"},
+		install: func(dir string) string {
+			if dir == "" {
+				return `npm install --no-audit --no-fund --silent`
+			}
+			return cachedInstall(dir, `cp package.json "$t"/ && { [ ! -f package-lock.json ] || cp package-lock.json "$t"/; } && `+
+				`npm install --no-audit --no-fund --silent --prefix "$t"`) +
+				` && export NODE_PATH=` + shellQuote(dir+"/node_modules") + `"${NODE_PATH:+:$NODE_PATH}"`
+		},
+	},
+	{
+		name:      "go.mod",
+		languages: []string{"go", "golang"},
+		install: func(dir string) string {
+			if dir == "" {
+				return `go mod download`
+			}
+			// The module cache is shared by content already
+			return `export GOMODCACHE=` + shellQuote(path.Join(path.Dir(dir), "mod")) + ` && go mod download`
+		},
+	},
+}
+
+// DependencyFiles returns the names of the dependency files detected
+func DependencyFiles() []string {
+	names := make([]string, len(dependencyFiles))
+	for i, file := range dependencyFiles {
+		names[i] = file.name
+	}
+	return names
+}
+
+// Detector picks a runtime for jobs that name none from the dependency file
+// they upload
+type Detector struct {
+	overrides map[string]string // Dependency file -> runtime
+	list      func() ([]*RuntimeInfo, error)
+}
+
+// NewDetector creates a detector choosing among the resolver's runtimes,
+// unless overrides names the runtime for a dependency file
+func NewDetector(overrides map[string]string, resolver *Resolver) *Detector {
+	return &Detector{overrides: overrides, list: resolver.ListRuntimes}
+}
+
+// Detect returns the dependency file among the uploaded paths and the runtime
+// for it. Only files at the top of the upload count, as that is where the job
+// runs. file is empty when no dependency file was uploaded, runtime when no
+// installed runtime matches it.
+func (d *Detector) Detect(uploads []string) (file, runtime string, err error) {
+	uploaded := make(map[string]bool, len(uploads))
+	for _, p := range uploads {
+		uploaded[path.Clean(p)] = true
+	}
+
+	for _, dep := range dependencyFiles {
+		if !uploaded[dep.name] {
+			continue
+		}
+		if override := d.overrides[dep.name]; override != "" {
+			return dep.name, override, nil
+		}
+		runtimes, err := d.list()
+		if err != nil {
+			return dep.name, "", err
+		}
+		return dep.name, newestRuntime(runtimes, dep.languages), nil
+	}
+	return "", "", nil
+}
+
+// InstallScript returns the shell commands installing the dependencies of a
+// detected file for a runtime. With a cache directory, the install is kept
+// there by runtime and file content and only runs when it is missing.
+func InstallScript(file string, content []byte, runtime, cacheDir string) (string, error) {
+	for _, dep := range dependencyFiles {
+		if dep.name != file {
+			continue
+		}
+		if cacheDir == "" {
+			return dep.install(""), nil
+		}
+		sum := sha256.Sum256(append([]byte(runtime+"\x00"), content...))
+		key := hex.EncodeToString(sum[:8])
+		return dep.install(path.Join(cacheDir, dep.languages[0], key)), nil
+	}
+	return "", fmt.Errorf("%s is not a dependency file", file)
+}
+
+// cachedInstall runs install into a temporary directory "$t" and renames it
+// to dir, unless dir already exists. Jobs installing the same dependencies at
+// once each install, the first rename is kept.
+func cachedInstall(dir, install string) string {
+	return `d=` + shellQuote(dir) + `; if [ ! -d "$d" ]; then t="$d.tmp.$$"; ` +
+		`mkdir -p "$t" && ` + install + ` && { mv -T "$t" "$d" 2>/dev/null || true; }; ` +
+		`s=$?; rm -rf "$t"; [ $s -eq 0 ]; fi`
+}
+
+// shellQuote quotes s for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// newestRuntime returns the runtime of one of the languages with the highest
+// language version in its name, plain runtimes before tagged ones
+// ("python-3.12" before "python-3.12-ml" before "python-3.11")
+func newestRuntime(runtimes []*RuntimeInfo, languages []string) string {
+	var best *RuntimeInfo
+	for _, lang := range languages {
+		for _, rt := range runtimes {
+			if !rt.Available || !strings.EqualFold(rt.Language, lang) {
+				continue
+			}
+			if best == nil || runtimeNewer(rt.Name, best.Name) {
+				best = rt
+			}
+		}
+		if best != nil {
+			return best.Name
+		}
+	}
+	return ""
+}
+
+// runtimeNewer reports whether runtime name a is preferred over b
+func runtimeNewer(a, b string) bool {
+	aParts, bParts := strings.Split(a, "-"), strings.Split(b, "-")
+	if len(aParts) > 1 && len(bParts) > 1 {
+		if c := compareNumeric(aParts[1], bParts[1]); c != 0 {
+			return c > 0
+		}
+	}
+	if len(aParts) != len(bParts) {
+		return len(aParts) < len(bParts)
+	}
+	return a < b
+}
+
+// compareNumeric compares dotted version numbers such as "3.11" and "3.9"
+func compareNumeric(a, b string) int {
+	aFields, bFields := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aFields) || i < len(bFields); i++ {
+		var x, y int
+		if i < len(aFields) {
+			x, _ = strconv.Atoi(aFields[i])
+		}
+		if i < len(bFields) {
+			y, _ = strconv.Atoi(bFields[i])
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
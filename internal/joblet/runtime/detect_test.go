@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector_Detect(t *testing.T) {
+	installed := []*RuntimeInfo{
+		{Name: "python-3.9", Language: "python", Available: true},
+		{Name: "python-3.12-ml", Language: "python", Available: true},
+		{Name: "python-3.12", Language: "python", Available: true},
+		{Name: "python-3.13", Language: "python", Available: false},
+		{Name: "openjdk-21", Language: "java", Available: true},
+		{Name: "golang-1.22", Language: "golang", Available: true},
+	}
+	d := &Detector{
+		overrides: map[string]string{"package.json": "node-20"},
+		list:      func() ([]*RuntimeInfo, error) { return installed, nil },
+	}
+
+	tests := []struct {
+		uploads       []string
+		file, runtime string
+	}{
+		{[]string{"main.py", "requirements.txt"}, "requirements.txt", "python-3.12"},
+		{[]string{"./go.mod", "main.go"}, "go.mod", "golang-1.22"},
+		{[]string{"package.json"}, "package.json", "node-20"},
+		{[]string{"requirements.txt", "package.json"}, "requirements.txt", "python-3.12"},
+		{[]string{"src/requirements.txt"}, "", ""},
+		{[]string{"Main.java"}, "", ""},
+	}
+	for _, tt := range tests {
+		file, runtime, err := d.Detect(tt.uploads)
+		require.NoError(t, err)
+		assert.Equal(t, tt.file, file, "uploads %v", tt.uploads)
+		assert.Equal(t, tt.runtime, runtime, "uploads %v", tt.uploads)
+	}
+
+	d.list = func() ([]*RuntimeInfo, error) { return installed[4:5], nil }
+	file, runtime, err := d.Detect([]string{"requirements.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "requirements.txt", file)
+	assert.Empty(t, runtime, "no python runtime is installed")
+}
+
+func TestInstallScript(t *testing.T) {
+	script, err := InstallScript("requirements.txt", []byte("requests\n"), "python-3.12", "")
+	require.NoError(t, err)
+	assert.Contains(t, script, "--target .deps/python")
+
+	cached, err := InstallScript("requirements.txt", []byte("requests\n"), "python-3.12", "/volumes/deps")
+	require.NoError(t, err)
+	assert.Contains(t, cached, "/volumes/deps/python/")
+	other, err := InstallScript("requirements.txt", []byte("numpy\n"), "python-3.12", "/volumes/deps")
+	require.NoError(t, err)
+	assert.NotEqual(t, cached, other, "different requirements are cached apart")
+
+	goScript, err := InstallScript("go.mod", []byte("module x\n"), "golang-1.22", "/volumes/deps")
+	require.NoError(t, err)
+	assert.Contains(t, goScript, "GOMODCACHE='/volumes/deps/go/mod'")
+
+	_, err = InstallScript("Gemfile", nil, "ruby", "")
+	assert.Error(t, err)
+}
+
+func TestCachedInstall(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "python", "abc")
+	runs := filepath.Join(t.TempDir(), "runs")
+	run := func(install string) error {
+		return exec.Command("sh", "-c", cachedInstall(dir, install)).Run()
+	}
+
+	// A failed install leaves nothing behind and fails the script
+	require.Error(t, run(`false`))
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	install := `echo run >> '` + runs + `' && touch "$t/installed"`
+	require.NoError(t, run(install))
+	require.NoError(t, run(install))
+	assert.FileExists(t, filepath.Join(dir, "installed"))
+	data, err := os.ReadFile(runs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "run"), "the second run uses the cache")
+
+	matches, _ := filepath.Glob(dir + ".tmp.*")
+	assert.Empty(t, matches)
+}
@@ -6,7 +6,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
+	jobletconfig "github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
 	"github.com/ehsaniara/joblet/pkg/platform"
 
@@ -19,6 +21,9 @@ type Resolver struct {
 	runtimesPath string
 	platform     platform.Platform
 	logger       *logger.Logger
+
+	mu        sync.Mutex
+	checksums map[string]cachedChecksum // By runtime directory
 }
 
 // NewResolver creates a new simple runtime resolver (maintains API compatibility)
@@ -167,17 +172,42 @@ func (r *Resolver) extractTypeFromName(runtimeName string) string {
 
 // getDirectorySize calculates the total size of a directory
 func (r *Resolver) getDirectorySize(path string) (int64, error) {
-	var size int64
+	size, _, err := diskFootprint(path)
+	return size, err
+}
+
+// diskFootprint returns the total size and the number of files under path
+func diskFootprint(path string) (int64, int64, error) {
+	var size, files int64
 	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if !info.IsDir() {
 			size += info.Size()
+			files++
 		}
 		return nil
 	})
-	return size, err
+	return size, files, err
+}
+
+// Inspect loads the runtime a spec resolves to, with its directory and the
+// disk it takes
+func (r *Resolver) Inspect(spec string) (*InstalledRuntime, error) {
+	runtimeDir, err := r.FindRuntimeDirectory(spec)
+	if err != nil {
+		return nil, fmt.Errorf("runtime not found: %w", err)
+	}
+	config, err := r.loadRuntimeConfig(filepath.Join(runtimeDir, "runtime.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runtime config: %w", err)
+	}
+	size, files, err := diskFootprint(runtimeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure runtime directory: %w", err)
+	}
+	return &InstalledRuntime{Config: config, Path: runtimeDir, SizeBytes: size, Files: files}, nil
 }
 
 // ResolveRuntime resolves a runtime spec to config (for runtime info command)
@@ -448,6 +478,13 @@ func (r *Resolver) validateRuntime(config *RuntimeConfig) error {
 		}
 	}
 
+	switch p := config.ChrootProfile; {
+	case p == "", p == jobletconfig.ChrootProfileAuto, p == jobletconfig.ChrootProfileNone, jobletconfig.IsChrootProfile(p):
+	default:
+		return fmt.Errorf("unknown chroot profile %q, must be auto, none or one of %s",
+			p, strings.Join(jobletconfig.ChrootProfiles, ", "))
+	}
+
 	return nil
 }
 
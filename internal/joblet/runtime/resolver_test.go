@@ -222,6 +222,34 @@ packages:
 	assert.Nil(t, resolvedConfig)
 }
 
+func TestResolver_Inspect(t *testing.T) {
+	runtimesPath := filepath.Join(t.TempDir(), "runtimes")
+	runtimeDir := filepath.Join(runtimesPath, "python-3.11", "1.2.0")
+	require.NoError(t, os.MkdirAll(filepath.Join(runtimeDir, "isolated", "bin"), 0755))
+
+	config := `name: python-3.11
+version: "1.2.0"
+environment:
+  PYTHONHOME: /usr/local
+tools:
+  python3: 3.11.9
+  pip: "24.0"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(runtimeDir, "runtime.yml"), []byte(config), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(runtimeDir, "isolated", "bin", "python3"), make([]byte, 1000), 0755))
+
+	resolver := NewResolver(runtimesPath, platform.NewPlatform())
+	installed, err := resolver.Inspect("python-3.11@1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, runtimeDir, installed.Path)
+	assert.Equal(t, map[string]string{"python3": "3.11.9", "pip": "24.0"}, installed.Config.Tools)
+	assert.Equal(t, int64(2), installed.Files)
+	assert.Equal(t, int64(1000+len(config)), installed.SizeBytes)
+
+	_, err = resolver.Inspect("python-3.11@2.0.0")
+	assert.Error(t, err)
+}
+
 func TestResolver_extractTypeFromName(t *testing.T) {
 	testPlatform := platform.NewPlatform()
 	resolver := NewResolver("/test", testPlatform)
@@ -410,6 +438,23 @@ func TestResolver_validateRuntime(t *testing.T) {
 			expectError: true,
 			errorText:   "runtime requires architecture",
 		},
+		{
+			name: "chroot profile override",
+			config: &RuntimeConfig{
+				Name:          "test-runtime",
+				ChrootProfile: "rhel",
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown chroot profile",
+			config: &RuntimeConfig{
+				Name:          "test-runtime",
+				ChrootProfile: "gentoo",
+			},
+			expectError: true,
+			errorText:   "unknown chroot profile",
+		},
 	}
 
 	for _, tt := range tests {
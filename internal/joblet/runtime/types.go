@@ -17,6 +17,12 @@ type RuntimeConfig struct {
 	// Keep only implemented features
 	Requirements RuntimeRequirements `yaml:"requirements" json:"requirements"`
 	Packages     []string            `yaml:"packages,omitempty" json:"packages,omitempty"`
+	// Tools are the versions of the tools the runtime ships, such as
+	// python3: 3.11.9, recorded by the setup script when the runtime is built
+	Tools map[string]string `yaml:"tools,omitempty" json:"tools,omitempty"`
+	// ChrootProfile overrides the host's filesystem.chrootProfile for jobs
+	// using the runtime, such as rhel for a runtime built on RHEL libraries
+	ChrootProfile string `yaml:"chrootProfile,omitempty" json:"chrootProfile,omitempty"`
 
 	// Removed unused fields:
 	// - Init string - not used anywhere in codebase
@@ -52,6 +58,15 @@ type RuntimeSpec struct {
 	Tags     []string // e.g., ["ml", "gpu"], ["scientific"]
 }
 
+// InstalledRuntime is a runtime's configuration with the directory it is
+// installed in and the disk it takes
+type InstalledRuntime struct {
+	Config    *RuntimeConfig
+	Path      string
+	SizeBytes int64
+	Files     int64
+}
+
 // RuntimeInfo contains metadata about an available runtime
 type RuntimeInfo struct {
 	Name        string
@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// usageSlice is the running time a tenant's jobs used during one slice
+type usageSlice struct {
+	start time.Time
+	used  time.Duration
+}
+
+// FairShareQueue holds the jobs waiting for a running slot on a saturated
+// node. Jobs leave it tenant by tenant, the tenant with the least recent usage
+// per unit of weight first, and in arrival order within a tenant. Usage is
+// kept in time slices over a sliding window, so a tenant that ran a lot an
+// hour ago is not held back by it today.
+type FairShareQueue struct {
+	window  time.Duration
+	slice   time.Duration
+	weights map[string]float64 // Tenants without a weight have weight 1
+
+	mutex   sync.Mutex
+	waiting []*domain.Job
+	usage   map[string][]usageSlice // Oldest slice first
+}
+
+// NewFairShareQueue creates an empty queue remembering usage for window in
+// slices of slice
+func NewFairShareQueue(window, slice time.Duration, weights map[string]float64) *FairShareQueue {
+	return &FairShareQueue{
+		window:  window,
+		slice:   slice,
+		weights: weights,
+		usage:   make(map[string][]usageSlice),
+	}
+}
+
+// Add appends a job to its tenant's line
+func (q *FairShareQueue) Add(job *domain.Job) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.waiting = append(q.waiting, job)
+}
+
+// Remove takes a job out of the queue, reporting whether it was waiting
+func (q *FairShareQueue) Remove(jobID string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for i, job := range q.waiting {
+		if job.Uuid == jobID {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of waiting jobs
+func (q *FairShareQueue) Size() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.waiting)
+}
+
+// Charge adds running time used by a tenant's jobs at now
+func (q *FairShareQueue) Charge(tenant string, used time.Duration, now time.Time) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	slices := q.usage[tenant]
+	start := now.Truncate(q.slice)
+	if n := len(slices); n > 0 && slices[n-1].start.Equal(start) {
+		slices[n-1].used += used
+	} else {
+		slices = append(slices, usageSlice{start: start, used: used})
+	}
+	q.usage[tenant] = slices
+}
+
+// Usage returns the running time a tenant used within the window
+func (q *FairShareQueue) Usage(tenant string, now time.Time) time.Duration {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.usageLocked(tenant, now)
+}
+
+// usageLocked sums the tenant's slices within the window, dropping older ones
+func (q *FairShareQueue) usageLocked(tenant string, now time.Time) time.Duration {
+	slices := q.usage[tenant]
+	cutoff := now.Add(-q.window)
+	expired := 0
+	for expired < len(slices) && !slices[expired].start.Add(q.slice).After(cutoff) {
+		expired++
+	}
+	if expired == len(slices) {
+		delete(q.usage, tenant)
+		return 0
+	}
+	slices = slices[expired:]
+	q.usage[tenant] = slices
+
+	var total time.Duration
+	for _, s := range slices {
+		total += s.used
+	}
+	return total
+}
+
+// share is the tenant's usage per unit of weight. Each job the tenant runs
+// right now counts as a slice of usage, so a burst of free slots is not all
+// handed to the tenant that is behind.
+func (q *FairShareQueue) share(tenant string, running int, now time.Time) float64 {
+	weight := q.weights[tenant]
+	if weight <= 0 {
+		weight = 1
+	}
+	used := q.usageLocked(tenant, now) + time.Duration(running)*q.slice
+	return used.Seconds() / weight
+}
+
+// Next removes and returns the job to start next, nil when none waits.
+// running holds each tenant's running jobs and is incremented for the
+// returned job's tenant, so repeated calls fill several slots fairly.
+func (q *FairShareQueue) Next(now time.Time, running map[string]int) *domain.Job {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	i := q.pick(q.waiting, now, running)
+	if i < 0 {
+		return nil
+	}
+	job := q.waiting[i]
+	q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+	running[job.Tenant]++
+	return job
+}
+
+// pick returns the index in waiting of the oldest job of the tenant with the
+// smallest share, -1 when waiting is empty. Callers must hold the mutex.
+func (q *FairShareQueue) pick(waiting []*domain.Job, now time.Time, running map[string]int) int {
+	best := -1
+	var bestShare float64
+	seen := make(map[string]bool)
+	for i, job := range waiting {
+		// The first job of a tenant is its oldest
+		if seen[job.Tenant] {
+			continue
+		}
+		seen[job.Tenant] = true
+		if share := q.share(job.Tenant, running[job.Tenant], now); best < 0 || share < bestShare {
+			best, bestShare = i, share
+		}
+	}
+	return best
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+func queuedJob(id, tenant string) *domain.Job {
+	return &domain.Job{Uuid: id, Tenant: tenant, Status: domain.StatusQueued}
+}
+
+func TestFairShareQueue_Order(t *testing.T) {
+	now := time.Date(2025, 8, 3, 10, 0, 0, 0, time.UTC)
+	q := NewFairShareQueue(time.Hour, time.Minute, map[string]float64{"ml": 2})
+
+	// "web" used the node for 20 minutes, "ml" for 30 minutes at twice the weight
+	q.Charge("web", 20*time.Minute, now.Add(-10*time.Minute))
+	q.Charge("ml", 30*time.Minute, now.Add(-5*time.Minute))
+
+	q.Add(queuedJob("web-1", "web"))
+	q.Add(queuedJob("web-2", "web"))
+	q.Add(queuedJob("ml-1", "ml"))
+	q.Add(queuedJob("batch-1", ""))
+
+	running := map[string]int{}
+	var order []string
+	for job := q.Next(now, running); job != nil; job = q.Next(now, running) {
+		order = append(order, job.Uuid)
+	}
+
+	// The idle tenant goes first, then ml (15m per unit of weight) before web
+	// (20m); running jobs count against a tenant, a slice each
+	want := []string{"batch-1", "ml-1", "web-1", "web-2"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+	if running["web"] != 2 || running["ml"] != 1 || running[""] != 1 {
+		t.Errorf("running = %v", running)
+	}
+}
+
+func TestFairShareQueue_ArrivalOrderOnTies(t *testing.T) {
+	q := NewFairShareQueue(time.Hour, time.Minute, nil)
+	q.Add(queuedJob("b-1", "b"))
+	q.Add(queuedJob("a-1", "a"))
+
+	if job := q.Next(time.Now(), map[string]int{}); job == nil || job.Uuid != "b-1" {
+		t.Errorf("Next = %v, want b-1", job)
+	}
+}
+
+func TestFairShareQueue_Window(t *testing.T) {
+	now := time.Date(2025, 8, 3, 10, 0, 0, 0, time.UTC)
+	q := NewFairShareQueue(time.Hour, 10*time.Minute, nil)
+
+	q.Charge("web", 30*time.Minute, now.Add(-90*time.Minute))
+	q.Charge("web", 5*time.Minute, now.Add(-30*time.Minute))
+	q.Charge("web", time.Minute, now.Add(-28*time.Minute)) // Same slice
+
+	if got := q.Usage("web", now); got != 6*time.Minute {
+		t.Errorf("Usage = %v, want 6m once the old slice left the window", got)
+	}
+	if got := q.Usage("web", now.Add(time.Hour)); got != 0 {
+		t.Errorf("Usage an hour later = %v, want 0", got)
+	}
+}
+
+func TestFairShareQueue_Remove(t *testing.T) {
+	q := NewFairShareQueue(time.Hour, time.Minute, nil)
+	q.Add(queuedJob("a-1", "a"))
+	q.Add(queuedJob("a-2", "a"))
+
+	if !q.Remove("a-1") || q.Remove("a-1") {
+		t.Error("Remove should succeed once")
+	}
+	if q.Size() != 1 {
+		t.Errorf("Size = %d, want 1", q.Size())
+	}
+	if job := q.Next(time.Now(), map[string]int{}); job == nil || job.Uuid != "a-2" {
+		t.Errorf("Next = %v, want a-2", job)
+	}
+}
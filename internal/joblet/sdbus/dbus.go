@@ -0,0 +1,435 @@
+// Package sdbus calls the systemd manager over D-Bus, so joblet can run jobs
+// in transient scope units under a slice the operator set up instead of
+// making cgroup directories behind systemd's back.
+//
+// It speaks just enough of the D-Bus wire protocol for the few method calls
+// joblet makes: EXTERNAL authentication, method calls with string, boolean,
+// uint32 array and property list arguments, and their returns and errors. It
+// talks to systemd's private socket, or the system bus when that is missing.
+package sdbus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sockets systemd's manager answers on
+const (
+	PrivateSocket   = "/run/systemd/private"
+	SystemBusSocket = "/run/dbus/system_bus_socket"
+)
+
+// Message types
+const (
+	typeMethodCall   = 1
+	typeMethodReturn = 2
+	typeError        = 3
+)
+
+// Header fields
+const (
+	fieldPath        = 1
+	fieldInterface   = 2
+	fieldMember      = 3
+	fieldErrorName   = 4
+	fieldReplySerial = 5
+	fieldDestination = 6
+	fieldSignature   = 8
+)
+
+// maxMessageSize is the largest message the D-Bus specification allows
+const maxMessageSize = 128 << 20
+
+// Error is an error reply to a method call
+type Error struct {
+	Name    string // e.g. org.freedesktop.systemd1.UnitExists
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return e.Name
+	}
+	return e.Name + ": " + e.Message
+}
+
+// Conn is an authenticated connection to a D-Bus peer. Calls are serialized.
+type Conn struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	serial  uint32
+	timeout time.Duration
+}
+
+// Dial connects to systemd's private socket, or to the system bus when the
+// private socket is missing
+func Dial() (*Conn, error) {
+	c, err := DialSocket(PrivateSocket, false)
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return DialSocket(SystemBusSocket, true)
+}
+
+// DialSocket connects to the D-Bus socket at path and authenticates as the
+// process's user. A bus needs a Hello before any other call.
+func DialSocket(path string, bus bool) (*Conn, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", path, err)
+	}
+	c := &Conn{conn: conn, reader: bufio.NewReader(conn), timeout: 30 * time.Second}
+	if err := c.auth(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to authenticate on %s: %w", path, err)
+	}
+	if bus {
+		if _, err := c.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello", "", nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to register on %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// auth runs the EXTERNAL mechanism with the process's user ID
+func (c *Conn) auth() error {
+	_ = c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer c.conn.SetDeadline(time.Time{})
+
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := io.WriteString(c.conn, "\x00AUTH EXTERNAL "+uid+"\r\n"); err != nil {
+		return err
+	}
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return fmt.Errorf("rejected: %s", strings.TrimSpace(line))
+	}
+	_, err = io.WriteString(c.conn, "BEGIN\r\n")
+	return err
+}
+
+// Call calls a method and returns the body of its return, in the
+// signature's encoding. body encodes the arguments of signature.
+func (c *Conn) Call(destination, path, iface, member, signature string, body func(*Encoder)) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.serial++
+	serial := c.serial
+	var args Encoder
+	if body != nil {
+		body(&args)
+	}
+	fields := []headerField{
+		{fieldPath, "o", path},
+		{fieldInterface, "s", iface},
+		{fieldMember, "s", member},
+		{fieldDestination, "s", destination},
+	}
+	if signature != "" {
+		fields = append(fields, headerField{fieldSignature, "g", signature})
+	}
+
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetDeadline(time.Time{})
+	if _, err := c.conn.Write(encodeMessage(typeMethodCall, serial, fields, args.buf)); err != nil {
+		return nil, err
+	}
+
+	// Signals and replies to nothing we sent are skipped
+	for {
+		msg, err := readMessage(c.reader)
+		if err != nil {
+			return nil, err
+		}
+		if msg.replySerial != serial {
+			continue
+		}
+		switch msg.typ {
+		case typeMethodReturn:
+			return msg.body, nil
+		case typeError:
+			e := &Error{Name: msg.errorName}
+			if strings.HasPrefix(msg.signature, "s") {
+				d := decoder{buf: msg.body, order: msg.order}
+				e.Message, _ = d.string()
+			}
+			return nil, e
+		}
+	}
+}
+
+// Encoder writes D-Bus values, aligned as the wire format wants. Offsets
+// are from the start of the body, which the header pads to 8 bytes.
+type Encoder struct {
+	buf []byte
+}
+
+func (e *Encoder) align(n int) {
+	for len(e.buf)%n != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+// Byte writes a y
+func (e *Encoder) Byte(v byte) {
+	e.buf = append(e.buf, v)
+}
+
+// Bool writes a b
+func (e *Encoder) Bool(v bool) {
+	var u uint32
+	if v {
+		u = 1
+	}
+	e.Uint32(u)
+}
+
+// Uint32 writes a u
+func (e *Encoder) Uint32(v uint32) {
+	e.align(4)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+// String writes an s or an o
+func (e *Encoder) String(v string) {
+	e.Uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+	e.buf = append(e.buf, 0)
+}
+
+// Signature writes a g
+func (e *Encoder) Signature(v string) {
+	e.buf = append(e.buf, byte(len(v)))
+	e.buf = append(e.buf, v...)
+	e.buf = append(e.buf, 0)
+}
+
+// Array writes an array whose elements align to elemAlign, written by
+// elems. Structs and dict entries align to 8.
+func (e *Encoder) Array(elemAlign int, elems func()) {
+	e.Uint32(0)
+	lengthAt := len(e.buf) - 4
+	e.align(elemAlign)
+	start := len(e.buf)
+	elems()
+	binary.LittleEndian.PutUint32(e.buf[lengthAt:], uint32(len(e.buf)-start))
+}
+
+// Struct starts a struct, whose fields the caller writes next
+func (e *Encoder) Struct() {
+	e.align(8)
+}
+
+// Variant writes a v holding a value of signature, written by value
+func (e *Encoder) Variant(signature string, value func()) {
+	e.Signature(signature)
+	value()
+}
+
+// headerField is a header field with the signature of its value, a string
+// or a uint32
+type headerField struct {
+	code      byte
+	signature string
+	value     interface{}
+}
+
+// encodeMessage writes a little-endian message
+func encodeMessage(typ byte, serial uint32, fields []headerField, body []byte) []byte {
+	var e Encoder
+	e.buf = append(e.buf, 'l', typ, 0, 1)
+	e.Uint32(uint32(len(body)))
+	e.Uint32(serial)
+	e.Array(8, func() {
+		for _, f := range fields {
+			e.Struct()
+			e.Byte(f.code)
+			e.Variant(f.signature, func() {
+				switch v := f.value.(type) {
+				case uint32:
+					e.Uint32(v)
+				case string:
+					if f.signature == "g" {
+						e.Signature(v)
+					} else {
+						e.String(v)
+					}
+				}
+			})
+		}
+	})
+	e.align(8)
+	return append(e.buf, body...)
+}
+
+// message is a message read from the peer
+type message struct {
+	typ         byte
+	serial      uint32
+	order       binary.ByteOrder
+	path        string
+	member      string
+	errorName   string
+	replySerial uint32
+	signature   string
+	body        []byte
+}
+
+// readMessage reads a whole message, in either byte order
+func readMessage(r io.Reader) (*message, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, err
+	}
+	msg := &message{typ: fixed[1]}
+	switch fixed[0] {
+	case 'l':
+		msg.order = binary.LittleEndian
+	case 'B':
+		msg.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid byte order %q", fixed[0])
+	}
+	bodyLen := msg.order.Uint32(fixed[4:])
+	msg.serial = msg.order.Uint32(fixed[8:])
+	fieldsLen := msg.order.Uint32(fixed[12:])
+	headerLen := (16 + fieldsLen + 7) &^ 7
+	if uint64(headerLen)+uint64(bodyLen) > maxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes is too large", uint64(headerLen)+uint64(bodyLen))
+	}
+
+	rest := make([]byte, headerLen-16+bodyLen)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, err
+	}
+	header := append(fixed, rest[:headerLen-16]...)
+	msg.body = rest[headerLen-16:]
+
+	d := decoder{buf: header[:16+fieldsLen], pos: 16, order: msg.order}
+	for d.pos < len(d.buf) {
+		d.align(8)
+		code, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		signature, err := d.signature()
+		if err != nil {
+			return nil, err
+		}
+		var (
+			text   string
+			number uint32
+		)
+		switch signature {
+		case "s", "o":
+			text, err = d.string()
+		case "g":
+			text, err = d.signature()
+		case "u":
+			number, err = d.uint32()
+		default:
+			return nil, fmt.Errorf("unexpected header field signature %q", signature)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch code {
+		case fieldPath:
+			msg.path = text
+		case fieldMember:
+			msg.member = text
+		case fieldErrorName:
+			msg.errorName = text
+		case fieldReplySerial:
+			msg.replySerial = number
+		case fieldSignature:
+			msg.signature = text
+		}
+	}
+	return msg, nil
+}
+
+// decoder reads D-Bus values from buf
+type decoder struct {
+	buf   []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+var errShort = errors.New("message too short")
+
+func (d *decoder) align(n int) {
+	for d.pos%n != 0 {
+		d.pos++
+	}
+}
+
+func (d *decoder) byte() (byte, error) {
+	if d.pos >= len(d.buf) {
+		return 0, errShort
+	}
+	d.pos++
+	return d.buf[d.pos-1], nil
+}
+
+func (d *decoder) uint32() (uint32, error) {
+	d.align(4)
+	if d.pos+4 > len(d.buf) {
+		return 0, errShort
+	}
+	d.pos += 4
+	return d.order.Uint32(d.buf[d.pos-4:]), nil
+}
+
+func (d *decoder) string() (string, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return "", err
+	}
+	if d.pos+int(n)+1 > len(d.buf) {
+		return "", errShort
+	}
+	s := string(d.buf[d.pos : d.pos+int(n)])
+	d.pos += int(n) + 1
+	return s, nil
+}
+
+func (d *decoder) signature() (string, error) {
+	n, err := d.byte()
+	if err != nil {
+		return "", err
+	}
+	if d.pos+int(n)+1 > len(d.buf) {
+		return "", errShort
+	}
+	s := string(d.buf[d.pos : d.pos+int(n)])
+	d.pos += int(n) + 1
+	return s, nil
+}
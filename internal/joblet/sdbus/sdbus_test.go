@@ -0,0 +1,147 @@
+package sdbus
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeManager answers method calls on a socket like systemd's private one
+type fakeManager struct {
+	calls   chan *message
+	replies []func(call *message) []byte
+}
+
+func (f *fakeManager) serve(t *testing.T, listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	if b, err := r.ReadByte(); err != nil || b != 0 {
+		t.Errorf("connection did not start with a NUL byte")
+		return
+	}
+	if line, _ := r.ReadString('\n'); !strings.HasPrefix(line, "AUTH EXTERNAL ") {
+		t.Errorf("auth line = %q", line)
+		return
+	}
+	_, _ = conn.Write([]byte("OK 0123456789abcdef\r\n"))
+	if line, _ := r.ReadString('\n'); line != "BEGIN\r\n" {
+		t.Errorf("begin line = %q", line)
+		return
+	}
+	for _, reply := range f.replies {
+		call, err := readMessage(r)
+		if err != nil {
+			t.Errorf("readMessage() error = %v", err)
+			return
+		}
+		f.calls <- call
+		_, _ = conn.Write(reply(call))
+	}
+}
+
+func methodReturn(call *message) []byte {
+	var body Encoder
+	body.String("/org/freedesktop/systemd1/job/42")
+	return encodeMessage(typeMethodReturn, 100, []headerField{
+		{fieldReplySerial, "u", call.serial},
+		{fieldSignature, "g", "o"},
+	}, body.buf)
+}
+
+func errorReply(name, text string) func(call *message) []byte {
+	return func(call *message) []byte {
+		var body Encoder
+		body.String(text)
+		return encodeMessage(typeError, 101, []headerField{
+			{fieldErrorName, "s", name},
+			{fieldReplySerial, "u", call.serial},
+			{fieldSignature, "g", "s"},
+		}, body.buf)
+	}
+}
+
+func TestConn(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "private")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	manager := &fakeManager{calls: make(chan *message, 4), replies: []func(*message) []byte{
+		methodReturn,
+		errorReply("org.freedesktop.systemd1.UnitExists", "Unit job-1.scope already exists."),
+		errorReply(ErrNoSuchUnit, "Unit job-1.scope not loaded."),
+	}}
+	go manager.serve(t, listener)
+
+	conn, err := DialSocket(socket, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	scope := Scope{Name: "job-1.scope", Slice: "joblet-jobs.slice", Description: "joblet job 1", PIDs: []int{4242}, Delegate: true}
+	if err := conn.StartTransientScope(scope); err != nil {
+		t.Fatalf("StartTransientScope() error = %v", err)
+	}
+	call := <-manager.calls
+	if call.member != "StartTransientUnit" || call.path != managerPath || call.signature != "ssa(sv)a(sa(sv))" {
+		t.Errorf("call = %s %s(%s)", call.path, call.member, call.signature)
+	}
+	d := decoder{buf: call.body, order: call.order}
+	if name, _ := d.string(); name != "job-1.scope" {
+		t.Errorf("unit name = %q", name)
+	}
+	if mode, _ := d.string(); mode != "fail" {
+		t.Errorf("mode = %q", mode)
+	}
+	for _, want := range []string{"Slice", "joblet-jobs.slice", "Delegate", "PIDs"} {
+		if !bytes.Contains(call.body, []byte(want)) {
+			t.Errorf("properties are missing %q", want)
+		}
+	}
+
+	err = conn.StartTransientScope(scope)
+	var dbusErr *Error
+	if !errors.As(err, &dbusErr) || dbusErr.Name != "org.freedesktop.systemd1.UnitExists" || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("StartTransientScope() of an existing scope = %v", err)
+	}
+	<-manager.calls
+
+	// A unit already gone is stopped
+	if err := conn.StopUnit("job-1.scope"); err != nil {
+		t.Errorf("StopUnit() of an unknown unit = %v", err)
+	}
+	if call := <-manager.calls; call.member != "StopUnit" || call.signature != "ss" {
+		t.Errorf("call = %s(%s)", call.member, call.signature)
+	}
+}
+
+func TestSliceDir(t *testing.T) {
+	tests := []struct {
+		slice, dir string
+		ok         bool
+	}{
+		{"joblet-jobs.slice", "joblet.slice/joblet-jobs.slice", true},
+		{"system.slice", "system.slice", true},
+		{"a-b-c.slice", "a.slice/a-b.slice/a-b-c.slice", true},
+		{"-.slice", "", true},
+		{"joblet--jobs.slice", "", false},
+		{"joblet.service", "", false},
+		{"../x.slice", "", false},
+	}
+	for _, tt := range tests {
+		dir, err := SliceDir(tt.slice)
+		if dir != tt.dir || (err == nil) != tt.ok {
+			t.Errorf("SliceDir(%q) = %q, %v", tt.slice, dir, err)
+		}
+	}
+}
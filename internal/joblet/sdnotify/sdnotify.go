@@ -0,0 +1,124 @@
+// Package sdnotify speaks the systemd notify protocol (sd_notify(3)) so the
+// joblet, persist and state daemons report when they are ready and feed the
+// service watchdog. systemd then restarts a daemon that hangs instead of
+// leaving it wedged while jobs silently stop being scheduled.
+//
+// Every call is a no-op when the process was not started by systemd with a
+// notify socket, so the daemons behave the same when run by hand.
+package sdnotify
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// States sent to systemd
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Alive    = "WATCHDOG=1"
+)
+
+// Environment variables systemd sets for a notify service
+const (
+	envSocket       = "NOTIFY_SOCKET"
+	envWatchdogUsec = "WATCHDOG_USEC"
+	envWatchdogPID  = "WATCHDOG_PID"
+)
+
+// Status returns the STATUS= state shown by systemctl status
+func Status(status string) string {
+	return "STATUS=" + status
+}
+
+// Notify sends the states to systemd in one datagram. It reports false
+// without error when there is no notify socket.
+func Notify(states ...string) (bool, error) {
+	socket := os.Getenv(envSocket)
+	if socket == "" {
+		return false, nil
+	}
+	// A leading @ names a socket in the abstract namespace
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the service's WatchdogSec, or 0 when the watchdog
+// is off or meant for another process of the service
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv(envWatchdogUsec), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv(envWatchdogPID); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// RunWatchdog pings the watchdog at half its interval until ctx is done.
+// A ping is skipped while check fails, so systemd restarts the service once
+// the daemon has been unhealthy for a whole interval. A nil check only proves
+// the process is still scheduling goroutines.
+func RunWatchdog(ctx context.Context, check func(context.Context) error, log *logger.Logger) {
+	interval := WatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	log.Info("systemd watchdog enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if check != nil {
+			checkCtx, cancel := context.WithTimeout(ctx, interval/4)
+			err := check(checkCtx)
+			cancel()
+			if err != nil {
+				log.Warn("health check failed, withholding systemd watchdog ping", "error", err)
+				continue
+			}
+		}
+		if _, err := Notify(Alive); err != nil {
+			log.Warn("failed to ping systemd watchdog", "error", err)
+		}
+	}
+}
+
+// ChildEnv returns env without the notify variables, for subprocesses that
+// must not notify systemd on behalf of the service
+func ChildEnv(env []string) []string {
+	child := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		switch name {
+		case envSocket, envWatchdogUsec, envWatchdogPID:
+			continue
+		}
+		child = append(child, kv)
+	}
+	return child
+}
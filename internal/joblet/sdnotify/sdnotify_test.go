@@ -0,0 +1,119 @@
+package sdnotify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// listen creates a notify socket and points NOTIFY_SOCKET at it
+func listen(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv(envSocket, path)
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UnixConn, timeout time.Duration) (string, bool) {
+	t.Helper()
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(timeout))
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", false
+	}
+	return string(buf[:n]), true
+}
+
+func TestNotify(t *testing.T) {
+	conn := listen(t)
+
+	sent, err := Notify(Ready, Status("serving"))
+	if err != nil || !sent {
+		t.Fatalf("Notify() = %v, %v", sent, err)
+	}
+	if msg, _ := receive(t, conn, time.Second); msg != "READY=1\nSTATUS=serving" {
+		t.Errorf("received %q", msg)
+	}
+}
+
+func TestNotifyWithoutSocket(t *testing.T) {
+	t.Setenv(envSocket, "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Errorf("Notify() = %v, %v, want no-op", sent, err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	self := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		usec, pid string
+		want      time.Duration
+	}{
+		{"", "", 0},
+		{"30000000", "", 30 * time.Second},
+		{"30000000", self, 30 * time.Second},
+		{"30000000", "1", 0},
+		{"garbage", "", 0},
+	}
+	for _, tt := range tests {
+		t.Setenv(envWatchdogUsec, tt.usec)
+		t.Setenv(envWatchdogPID, tt.pid)
+		if got := WatchdogInterval(); got != tt.want {
+			t.Errorf("WatchdogInterval(%q, %q) = %v, want %v", tt.usec, tt.pid, got, tt.want)
+		}
+	}
+}
+
+func TestRunWatchdog(t *testing.T) {
+	conn := listen(t)
+	t.Setenv(envWatchdogUsec, "200000")
+	t.Setenv(envWatchdogPID, "")
+
+	healthy := make(chan bool, 1)
+	healthy <- false
+	check := func(context.Context) error {
+		select {
+		case ok := <-healthy:
+			if !ok {
+				return errors.New("state service unreachable")
+			}
+		default:
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	go RunWatchdog(ctx, check, logger.New())
+
+	// The first tick fails its check, the second one pings
+	msg, ok := receive(t, conn, 2*time.Second)
+	if !ok || msg != Alive {
+		t.Fatalf("received %q, %v, want %q", msg, ok, Alive)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("first ping after %v, the failed check should have skipped one", elapsed)
+	}
+}
+
+func TestChildEnv(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "NOTIFY_SOCKET=/run/systemd/notify", "WATCHDOG_USEC=30000000", "WATCHDOG_PID=1", "JOBLET_MODE=server"}
+	got := ChildEnv(env)
+	if len(got) != 2 || got[0] != "PATH=/usr/bin" || got[1] != "JOBLET_MODE=server" {
+		t.Errorf("ChildEnv() = %v", got)
+	}
+}
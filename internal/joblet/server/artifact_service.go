@@ -0,0 +1,137 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	artifactspb "github.com/ehsaniara/joblet/internal/proto/gen/artifacts"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// artifactChunkSize is the data size of each streamed chunk
+	artifactChunkSize = 256 * 1024
+	// defaultArtifactWindow is sent when the request names no window,
+	// maxArtifactWindow caps the ones it names
+	defaultArtifactWindow = 64 * 1024 * 1024
+	maxArtifactWindow     = 1024 * 1024 * 1024
+)
+
+// VolumeLookup finds volumes by name
+type VolumeLookup interface {
+	GetVolume(name string) (*domain.Volume, bool)
+}
+
+// ArtifactServiceServer implements the gRPC service downloading files from
+// volumes in windows
+type ArtifactServiceServer struct {
+	artifactspb.UnimplementedArtifactServiceServer
+	auth    auth2.GRPCAuthorization
+	volumes VolumeLookup
+	config  *config.Config // Tenants and the volumes they may use (nil = every client reads all)
+	logger  *logger.Logger
+}
+
+// NewArtifactServiceServer creates a new artifact download service server
+func NewArtifactServiceServer(auth auth2.GRPCAuthorization, volumes VolumeLookup) *ArtifactServiceServer {
+	return &ArtifactServiceServer{
+		auth:    auth,
+		volumes: volumes,
+		logger:  logger.WithField("component", "artifact-download"),
+	}
+}
+
+// DownloadArtifact streams up to one window of a volume file starting at
+// req.Offset and sets the window's SHA-256 as a trailer
+func (s *ArtifactServiceServer) DownloadArtifact(req *artifactspb.DownloadArtifactRequest, stream artifactspb.ArtifactService_DownloadArtifactServer) error {
+	log := s.logger.WithFields("operation", "DownloadArtifact", "volume", req.Volume, "path", req.Path, "offset", req.Offset)
+	if err := s.auth.Authorized(stream.Context(), auth2.DownloadArtifactOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+	if req.Offset < 0 || req.Window < 0 {
+		return status.Error(codes.InvalidArgument, "offset and window cannot be negative")
+	}
+	window := req.Window
+	if window == 0 {
+		window = defaultArtifactWindow
+	}
+	window = min(window, maxArtifactWindow)
+
+	// Volumes the caller's tenant may not use are hidden, as in ListVolumes
+	if s.config != nil {
+		if tenant := auth2.ClientTenant(stream.Context(), s.config.TenantOf); !s.config.TenantCanUseVolume(tenant, req.Volume) {
+			return status.Errorf(codes.NotFound, "volume %s not found", req.Volume)
+		}
+	}
+	vol, exists := s.volumes.GetVolume(req.Volume)
+	if !exists {
+		return status.Errorf(codes.NotFound, "volume %s not found", req.Volume)
+	}
+
+	// Opened through a root so neither ".." nor symlinks leave the volume
+	root, err := os.OpenRoot(filepath.Join(vol.Path, "data"))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open volume %s: %v", req.Volume, err)
+	}
+	defer root.Close()
+	f, err := root.Open(filepath.Clean(req.Path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return status.Errorf(codes.NotFound, "%s not found in volume %s", req.Path, req.Volume)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "cannot open %s: %v", req.Path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to stat %s: %v", req.Path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return status.Errorf(codes.InvalidArgument, "%s is not a regular file", req.Path)
+	}
+	if req.Offset > info.Size() {
+		return status.Errorf(codes.OutOfRange, "offset %d is past the end of %s (%d bytes)", req.Offset, req.Path, info.Size())
+	}
+
+	h := sha256.New()
+	r := io.TeeReader(io.NewSectionReader(f, req.Offset, min(window, info.Size()-req.Offset)), h)
+	chunk := &artifactspb.ArtifactChunk{Offset: req.Offset, Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+	buf := make([]byte, artifactChunkSize)
+	sent := int64(0)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || sent == 0 {
+			chunk.Data = buf[:n]
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			sent += int64(n)
+			chunk = &artifactspb.ArtifactChunk{Offset: req.Offset + sent}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read %s: %v", req.Path, err)
+		}
+	}
+
+	stream.SetTrailer(metadata.Pairs(constants.ArtifactSHA256Trailer, hex.EncodeToString(h.Sum(nil))))
+	log.Debug("artifact window sent", "bytes", sent, "size", info.Size())
+	return nil
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	artifactspb "github.com/ehsaniara/joblet/internal/proto/gen/artifacts"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeVolumes map[string]*domain.Volume
+
+func (f fakeVolumes) GetVolume(name string) (*domain.Volume, bool) {
+	v, ok := f[name]
+	return v, ok
+}
+
+// artifactClient serves the artifact service over an in-memory connection
+func artifactClient(t *testing.T, s *ArtifactServiceServer) artifactspb.ArtifactServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	artifactspb.RegisterArtifactServiceServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return artifactspb.NewArtifactServiceClient(conn)
+}
+
+// downloadWindow reads one window and returns its bytes and trailer checksum
+func downloadWindow(client artifactspb.ArtifactServiceClient, req *artifactspb.DownloadArtifactRequest) ([]byte, []*artifactspb.ArtifactChunk, string, error) {
+	stream, err := client.DownloadArtifact(context.Background(), req)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	var data []byte
+	var chunks []*artifactspb.ArtifactChunk
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, "", err
+		}
+		data = append(data, chunk.Data...)
+		chunks = append(chunks, chunk)
+	}
+	sums := stream.Trailer().Get(constants.ArtifactSHA256Trailer)
+	if len(sums) != 1 {
+		return data, chunks, "", nil
+	}
+	return data, chunks, sums[0], nil
+}
+
+func TestArtifactService_DownloadArtifact(t *testing.T) {
+	volumePath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(volumePath, "data", "run-1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := make([]byte, 3*artifactChunkSize+100)
+	for i := range content {
+		content[i] = byte(i % 253)
+	}
+	if err := os.WriteFile(filepath.Join(volumePath, "data", "run-1", "model.bin"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(volumePath, "data", "escape")); err != nil {
+		t.Fatal(err)
+	}
+	client := artifactClient(t, NewArtifactServiceServer(&authfakes.FakeGRPCAuthorization{},
+		fakeVolumes{"models": {Name: "models", Path: volumePath}}))
+
+	window := int64(artifactChunkSize + 10)
+	var got []byte
+	for offset := int64(0); offset < int64(len(content)); offset += window {
+		data, chunks, sum, err := downloadWindow(client, &artifactspb.DownloadArtifactRequest{Volume: "models", Path: "run-1/model.bin", Offset: offset, Window: window})
+		if err != nil {
+			t.Fatalf("window at %d: %v", offset, err)
+		}
+		if chunks[0].Offset != offset || chunks[0].Size != int64(len(content)) || chunks[0].ModTime == 0 {
+			t.Errorf("window at %d starts with %+v", offset, chunks[0])
+		}
+		if want := sha256.Sum256(data); sum != hex.EncodeToString(want[:]) {
+			t.Errorf("window at %d: trailer checksum %q does not match its bytes", offset, sum)
+		}
+		got = append(got, data...)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("downloaded %d bytes differ from the %d byte file", len(got), len(content))
+	}
+
+	// The end of the file is an empty window
+	data, chunks, sum, err := downloadWindow(client, &artifactspb.DownloadArtifactRequest{Volume: "models", Path: "run-1/model.bin", Offset: int64(len(content))})
+	if err != nil || len(data) != 0 || len(chunks) != 1 || sum == "" {
+		t.Errorf("window at the end = %d bytes, %d chunks, %v", len(data), len(chunks), err)
+	}
+
+	for name, tc := range map[string]struct {
+		req  *artifactspb.DownloadArtifactRequest
+		code codes.Code
+	}{
+		"unknown volume":   {&artifactspb.DownloadArtifactRequest{Volume: "other", Path: "a"}, codes.NotFound},
+		"missing file":     {&artifactspb.DownloadArtifactRequest{Volume: "models", Path: "run-2/model.bin"}, codes.NotFound},
+		"directory":        {&artifactspb.DownloadArtifactRequest{Volume: "models", Path: "run-1"}, codes.InvalidArgument},
+		"parent directory": {&artifactspb.DownloadArtifactRequest{Volume: "models", Path: "../../etc/passwd"}, codes.InvalidArgument},
+		"symlink out":      {&artifactspb.DownloadArtifactRequest{Volume: "models", Path: "escape"}, codes.InvalidArgument},
+		"past the end":     {&artifactspb.DownloadArtifactRequest{Volume: "models", Path: "run-1/model.bin", Offset: int64(len(content)) + 1}, codes.OutOfRange},
+		"negative window":  {&artifactspb.DownloadArtifactRequest{Volume: "models", Path: "run-1/model.bin", Window: -1}, codes.InvalidArgument},
+	} {
+		if _, _, _, err := downloadWindow(client, tc.req); status.Code(err) != tc.code {
+			t.Errorf("%s: got %v, want %s", name, err, tc.code)
+		}
+	}
+}
+
+func TestArtifactService_Unauthorized(t *testing.T) {
+	authorization := &authfakes.FakeGRPCAuthorization{}
+	authorization.AuthorizedReturns(status.Error(codes.PermissionDenied, "denied"))
+	client := artifactClient(t, NewArtifactServiceServer(authorization, fakeVolumes{}))
+
+	if _, _, _, err := downloadWindow(client, &artifactspb.DownloadArtifactRequest{Volume: "models", Path: "a"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
+
+// contextStream carries the caller's context into a direct DownloadArtifact
+// call; the tests using it end before anything is sent
+type contextStream struct {
+	artifactspb.ArtifactService_DownloadArtifactServer
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context { return s.ctx }
+
+func TestArtifactService_OtherTenantVolume(t *testing.T) {
+	s := NewArtifactServiceServer(&authfakes.FakeGRPCAuthorization{},
+		fakeVolumes{"web-assets": {Name: "web-assets", Path: t.TempDir()}})
+	s.config = &config.Config{
+		Tenants: []config.TenantConfig{{Name: "ml", Volumes: []string{"ml-*"}}},
+	}
+
+	ctx := auth2.WithIdentity(context.Background(), &auth2.Identity{Principal: "alice", Tenant: "ml"})
+	err := s.DownloadArtifact(&artifactspb.DownloadArtifactRequest{Volume: "web-assets", Path: "."}, contextStream{ctx: ctx})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("download from another tenant's volume = %v, want NotFound", err)
+	}
+}
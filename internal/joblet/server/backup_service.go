@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/mappers"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	backuppb "github.com/ehsaniara/joblet/internal/proto/gen/backup"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BackupServiceServer implements the gRPC service exporting the job and
+// workflow records of the node and importing them, through the workflow
+// service's job store and workflow manager
+type BackupServiceServer struct {
+	backuppb.UnimplementedBackupServiceServer
+	auth      auth2.GRPCAuthorization
+	workflows *WorkflowServiceServer
+	logger    *logger.Logger
+}
+
+// NewBackupServiceServer creates a new backup service server
+func NewBackupServiceServer(auth auth2.GRPCAuthorization, workflows *WorkflowServiceServer) *BackupServiceServer {
+	return &BackupServiceServer{
+		auth:      auth,
+		workflows: workflows,
+		logger:    logger.WithField("component", "backup"),
+	}
+}
+
+// ExportJobs streams the records of the node's jobs, oldest first
+func (s *BackupServiceServer) ExportJobs(req *backuppb.ExportJobsRequest, stream backuppb.BackupService_ExportJobsServer) error {
+	log := s.logger.WithFields("operation", "ExportJobs", "since", req.Since, "endedOnly", req.EndedOnly)
+	if err := s.auth.Authorized(stream.Context(), auth2.ExportBackupOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+	since, err := parseSince(req.Since)
+	if err != nil {
+		return err
+	}
+
+	jobs := s.workflows.jobStore.ListJobs()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartTime.Before(jobs[j].StartTime) })
+	exported := 0
+	for _, job := range jobs {
+		if job.StartTime.Before(since) || (req.EndedOnly && !endedJobStatuses[job.Status]) {
+			continue
+		}
+		if err := stream.Send(exportJob(job)); err != nil {
+			return err
+		}
+		exported++
+	}
+
+	log.Info("jobs exported", "count", exported)
+	return nil
+}
+
+// ExportWorkflows streams the records of the node's workflows, oldest first
+func (s *BackupServiceServer) ExportWorkflows(req *backuppb.ExportWorkflowsRequest, stream backuppb.BackupService_ExportWorkflowsServer) error {
+	log := s.logger.WithFields("operation", "ExportWorkflows", "since", req.Since, "endedOnly", req.EndedOnly)
+	if err := s.auth.Authorized(stream.Context(), auth2.ExportBackupOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+	since, err := parseSince(req.Since)
+	if err != nil {
+		return err
+	}
+
+	states := s.workflows.workflowManager.ListWorkflows()
+	sort.Slice(states, func(i, j int) bool { return states[i].CreatedAt.Before(states[j].CreatedAt) })
+	exported := 0
+	for _, state := range states {
+		if state.CreatedAt.Before(since) || (req.EndedOnly && !state.Status.Ended()) {
+			continue
+		}
+		if err := stream.Send(exportWorkflow(state, s.workflows.getFullUuidForWorkflowID(state.ID))); err != nil {
+			return err
+		}
+		exported++
+	}
+
+	log.Info("workflows exported", "count", exported)
+	return nil
+}
+
+// ImportJobs stores the records of ended jobs. Jobs the node already has and
+// jobs that had not ended when they were exported are skipped; nothing is
+// run.
+func (s *BackupServiceServer) ImportJobs(ctx context.Context, req *backuppb.ImportJobsRequest) (*backuppb.ImportResult, error) {
+	log := s.logger.WithFields("operation", "ImportJobs", "jobs", len(req.Jobs))
+	if err := s.auth.Authorized(ctx, auth2.ImportBackupOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+
+	result := &backuppb.ImportResult{}
+	for _, record := range req.Jobs {
+		job, err := importJob(record)
+		if err == nil {
+			if _, exists := s.workflows.jobStore.Job(job.Uuid); exists {
+				err = fmt.Errorf("already exists")
+			}
+		}
+		if err != nil {
+			result.Skipped = append(result.Skipped, &backuppb.SkippedRecord{Uuid: record.Uuid, Reason: err.Error()})
+			continue
+		}
+		s.workflows.jobStore.CreateNewJob(job)
+		result.Imported++
+	}
+
+	log.Info("jobs imported", "imported", result.Imported, "skipped", len(result.Skipped))
+	return result, nil
+}
+
+// ImportWorkflows stores the records of ended workflows under new workflow
+// IDs, keeping their UUIDs. Workflows the node already has and workflows
+// that had not ended are skipped.
+func (s *BackupServiceServer) ImportWorkflows(ctx context.Context, req *backuppb.ImportWorkflowsRequest) (*backuppb.ImportResult, error) {
+	log := s.logger.WithFields("operation", "ImportWorkflows", "workflows", len(req.Workflows))
+	if err := s.auth.Authorized(ctx, auth2.ImportBackupOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+
+	result := &backuppb.ImportResult{}
+	for _, record := range req.Workflows {
+		state, err := importWorkflow(record)
+		if err == nil {
+			if _, exists := s.workflows.workflowUuids.Get(record.Uuid); exists {
+				err = fmt.Errorf("already exists")
+			}
+		}
+		var workflowID int
+		if err == nil {
+			workflowID, err = s.workflows.workflowManager.RestoreWorkflow(state)
+		}
+		if err != nil {
+			result.Skipped = append(result.Skipped, &backuppb.SkippedRecord{Uuid: record.Uuid, Reason: err.Error()})
+			continue
+		}
+		s.workflows.storeWorkflowMapping(record.Uuid, workflowID)
+		s.workflows.checkpointNewWorkflow(context.Background(), workflowID, nil, nil)
+		result.Imported++
+	}
+
+	log.Info("workflows imported", "imported", result.Imported, "skipped", len(result.Skipped))
+	return result, nil
+}
+
+// exportJob converts a job to its record, masking its secrets and leaving
+// out the contents of its uploads
+func exportJob(job *domain.Job) *backuppb.ExportedJob {
+	limits := mappers.NewJobMapper().ResourceLimitsToRequestObject(&job.Limits)
+	record := &backuppb.ExportedJob{
+		Uuid:    job.Uuid,
+		Name:    job.Name,
+		NodeId:  job.NodeId,
+		Command: job.Command,
+		Args:    job.Args,
+		Type:    string(job.Type),
+		Resources: &backuppb.JobResources{
+			MaxCpu:      limits.MaxCPU,
+			CpuCores:    limits.CPUCores,
+			MaxMemory:   limits.MaxMemory,
+			MaxIoBps:    limits.MaxIOBPS,
+			GpuCount:    job.GPUCount,
+			GpuMemoryMb: job.GPUMemoryMB,
+		},
+		Network:            job.Network,
+		Volumes:            job.Volumes,
+		Runtime:            job.Runtime,
+		RequestedRuntime:   job.RequestedRuntime,
+		WorkingDirectory:   job.WorkingDirectory,
+		Environment:        job.Environment,
+		SecretEnvironment:  job.MaskedSecretEnvironment(),
+		CgroupParams:       job.CgroupParams,
+		WorkflowUuid:       job.WorkflowUuid,
+		Dependencies:       job.Dependencies,
+		Tenant:             job.Tenant,
+		Group:              job.Group,
+		ArrayUuid:          job.ArrayUuid,
+		ArrayIndex:         int32(job.ArrayIndex),
+		Status:             string(job.Status),
+		StartTime:          formatRecordTime(job.StartTime),
+		ExitCode:           job.ExitCode,
+		Attempts:           job.Attempts,
+		Outputs:            job.Outputs,
+		Redactions:         job.Redactions,
+		OutputDroppedBytes: job.OutputDroppedBytes,
+		OutputTruncated:    job.OutputTruncated,
+		Labels:             job.Labels,
+		Annotations:        job.Annotations,
+	}
+	if job.EndTime != nil {
+		record.EndTime = formatRecordTime(*job.EndTime)
+	}
+	if job.ScheduledTime != nil {
+		record.ScheduledTime = formatRecordTime(*job.ScheduledTime)
+	}
+	for _, upload := range job.Uploads {
+		record.Uploads = append(record.Uploads, upload.Path)
+	}
+	for _, event := range job.Events {
+		record.Events = append(record.Events, &backuppb.JobEventRecord{
+			Time:    formatRecordTime(event.Time),
+			Type:    event.Type,
+			Message: event.Message,
+		})
+	}
+	return record
+}
+
+// importJob converts the record of an ended job back to a job
+func importJob(record *backuppb.ExportedJob) (*domain.Job, error) {
+	if record.Uuid == "" {
+		return nil, fmt.Errorf("no UUID")
+	}
+	jobStatus := domain.JobStatus(record.Status)
+	if !endedJobStatuses[jobStatus] {
+		return nil, fmt.Errorf("job is %s, only ended jobs are imported", record.Status)
+	}
+
+	job := &domain.Job{
+		Uuid:               record.Uuid,
+		Name:               record.Name,
+		NodeId:             record.NodeId,
+		Command:            record.Command,
+		Args:               record.Args,
+		Type:               domain.JobType(record.Type),
+		Network:            record.Network,
+		Volumes:            record.Volumes,
+		Runtime:            record.Runtime,
+		RequestedRuntime:   record.RequestedRuntime,
+		WorkingDirectory:   record.WorkingDirectory,
+		Environment:        record.Environment,
+		SecretEnvironment:  record.SecretEnvironment,
+		CgroupParams:       record.CgroupParams,
+		WorkflowUuid:       record.WorkflowUuid,
+		Dependencies:       record.Dependencies,
+		Tenant:             record.Tenant,
+		Group:              record.Group,
+		ArrayUuid:          record.ArrayUuid,
+		ArrayIndex:         int(record.ArrayIndex),
+		Status:             jobStatus,
+		ExitCode:           record.ExitCode,
+		Attempts:           record.Attempts,
+		Outputs:            record.Outputs,
+		Redactions:         record.Redactions,
+		OutputDroppedBytes: record.OutputDroppedBytes,
+		OutputTruncated:    record.OutputTruncated,
+		Labels:             record.Labels,
+		Annotations:        record.Annotations,
+	}
+	if resources := record.Resources; resources != nil {
+		job.Limits = *domain.NewResourceLimitsFromParams(resources.MaxCpu, resources.CpuCores, resources.MaxMemory, int64(resources.MaxIoBps))
+		job.GPUCount = resources.GpuCount
+		job.GPUMemoryMB = resources.GpuMemoryMb
+	} else {
+		job.Limits = *domain.NewResourceLimits()
+	}
+
+	var err error
+	if job.StartTime, err = parseRecordTime(record.StartTime); err != nil {
+		return nil, fmt.Errorf("invalid start time: %w", err)
+	}
+	if job.EndTime, err = parseOptionalRecordTime(record.EndTime); err != nil {
+		return nil, fmt.Errorf("invalid end time: %w", err)
+	}
+	if job.ScheduledTime, err = parseOptionalRecordTime(record.ScheduledTime); err != nil {
+		return nil, fmt.Errorf("invalid scheduled time: %w", err)
+	}
+	for _, path := range record.Uploads {
+		job.Uploads = append(job.Uploads, domain.FileUpload{Path: path})
+	}
+	for _, event := range record.Events {
+		at, err := parseRecordTime(event.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event time: %w", err)
+		}
+		job.Events = append(job.Events, domain.JobEvent{Time: at, Type: event.Type, Message: event.Message})
+	}
+	return job, nil
+}
+
+// exportWorkflow converts a workflow to its record, with its jobs in
+// workflow order
+func exportWorkflow(state *workflow.WorkflowState, uuid string) *backuppb.ExportedWorkflow {
+	record := &backuppb.ExportedWorkflow{
+		Uuid:          uuid,
+		Workflow:      state.Workflow,
+		YamlContent:   state.YamlContent,
+		Status:        string(state.Status),
+		CreatedAt:     formatRecordTime(state.CreatedAt),
+		TotalJobs:     int32(state.TotalJobs),
+		CompletedJobs: int32(state.CompletedJobs),
+		FailedJobs:    int32(state.FailedJobs),
+		CanceledJobs:  int32(state.CanceledJobs),
+	}
+	if state.StartedAt != nil {
+		record.StartedAt = formatRecordTime(*state.StartedAt)
+	}
+	if state.CompletedAt != nil {
+		record.CompletedAt = formatRecordTime(*state.CompletedAt)
+	}
+
+	byName := make(map[string]*workflow.JobDependency, len(state.Jobs))
+	for _, job := range state.Jobs {
+		byName[job.InternalName] = job
+	}
+	names := slices.Clone(state.JobOrder)
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		job, exists := byName[name]
+		if !exists {
+			continue
+		}
+		jobRecord := &backuppb.WorkflowJobRecord{Name: job.InternalName, Status: string(job.Status)}
+		if job.JobID != job.InternalName {
+			jobRecord.JobUuid = job.JobID
+		}
+		for _, req := range job.Requirements {
+			if req.Type == workflow.RequirementExpression {
+				jobRecord.Requirements = append(jobRecord.Requirements, &backuppb.WorkflowRequirement{Expression: req.Expression})
+				continue
+			}
+			jobRecord.Requirements = append(jobRecord.Requirements, &backuppb.WorkflowRequirement{Job: req.JobID, Status: req.Status})
+		}
+		record.Jobs = append(record.Jobs, jobRecord)
+	}
+	return record
+}
+
+// importWorkflow converts the record of a workflow back to its state
+func importWorkflow(record *backuppb.ExportedWorkflow) (*workflow.WorkflowState, error) {
+	if record.Uuid == "" {
+		return nil, fmt.Errorf("no UUID")
+	}
+	state := &workflow.WorkflowState{
+		Workflow:      record.Workflow,
+		YamlContent:   record.YamlContent,
+		Jobs:          make(map[string]*workflow.JobDependency, len(record.Jobs)),
+		Status:        workflow.WorkflowStatus(record.Status),
+		TotalJobs:     int(record.TotalJobs),
+		CompletedJobs: int(record.CompletedJobs),
+		FailedJobs:    int(record.FailedJobs),
+		CanceledJobs:  int(record.CanceledJobs),
+	}
+
+	var err error
+	if state.CreatedAt, err = parseRecordTime(record.CreatedAt); err != nil {
+		return nil, fmt.Errorf("invalid creation time: %w", err)
+	}
+	if state.StartedAt, err = parseOptionalRecordTime(record.StartedAt); err != nil {
+		return nil, fmt.Errorf("invalid start time: %w", err)
+	}
+	if state.CompletedAt, err = parseOptionalRecordTime(record.CompletedAt); err != nil {
+		return nil, fmt.Errorf("invalid completion time: %w", err)
+	}
+
+	for _, jobRecord := range record.Jobs {
+		// Jobs that never started are known by their name, like in a live
+		// workflow
+		jobID := jobRecord.JobUuid
+		if jobID == "" {
+			jobID = jobRecord.Name
+		}
+		job := &workflow.JobDependency{
+			JobID:        jobID,
+			InternalName: jobRecord.Name,
+			Status:       domain.JobStatus(jobRecord.Status),
+		}
+		for _, req := range jobRecord.Requirements {
+			if req.Expression != "" {
+				job.Requirements = append(job.Requirements, workflow.Requirement{Type: workflow.RequirementExpression, Expression: req.Expression})
+				continue
+			}
+			job.Requirements = append(job.Requirements, workflow.Requirement{Type: workflow.RequirementSimple, JobID: req.Job, Status: req.Status})
+		}
+		state.Jobs[jobID] = job
+		state.JobOrder = append(state.JobOrder, jobRecord.Name)
+	}
+	return state, nil
+}
+
+// parseSince parses the since filter of an export, the zero time when empty
+func parseSince(since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid since %q, want RFC 3339: %v", since, err)
+	}
+	return t, nil
+}
+
+// formatRecordTime formats a time of a record, empty for the zero time
+func formatRecordTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// parseRecordTime parses a time of a record, the zero time when empty
+func parseRecordTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// parseOptionalRecordTime parses a time of a record that may be unset
+func parseOptionalRecordTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
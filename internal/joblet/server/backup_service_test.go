@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	backuppb "github.com/ehsaniara/joblet/internal/proto/gen/backup"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc"
+)
+
+type fakeJobExportStream struct {
+	grpc.ServerStream
+	jobs []*backuppb.ExportedJob
+}
+
+func (f *fakeJobExportStream) Context() context.Context { return context.Background() }
+
+func (f *fakeJobExportStream) Send(job *backuppb.ExportedJob) error {
+	f.jobs = append(f.jobs, job)
+	return nil
+}
+
+type fakeWorkflowExportStream struct {
+	grpc.ServerStream
+	workflows []*backuppb.ExportedWorkflow
+}
+
+func (f *fakeWorkflowExportStream) Context() context.Context { return context.Background() }
+
+func (f *fakeWorkflowExportStream) Send(wf *backuppb.ExportedWorkflow) error {
+	f.workflows = append(f.workflows, wf)
+	return nil
+}
+
+// newBackupTestServer serves the jobs of store and the workflows of manager
+func newBackupTestServer(store *adaptersfakes.FakeJobStorer, manager *workflow.WorkflowManager) (*BackupServiceServer, *WorkflowServiceServer) {
+	workflows := &WorkflowServiceServer{
+		jobStore:         store,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+	}
+	return NewBackupServiceServer(&authfakes.FakeGRPCAuthorization{}, workflows), workflows
+}
+
+func TestBackupJobsRoundTrip(t *testing.T) {
+	started := time.Date(2026, 10, 1, 9, 0, 0, 123456789, time.UTC)
+	ended := started.Add(time.Minute)
+	completed := &domain.Job{
+		Uuid:              "uuid-train",
+		Name:              "train",
+		Command:           "python",
+		Args:              []string{"train.py"},
+		Limits:            *domain.NewResourceLimitsFromParams(200, "0-1", 1024, 0),
+		GPUCount:          1,
+		Status:            domain.StatusCompleted,
+		StartTime:         started,
+		EndTime:           &ended,
+		Uploads:           []domain.FileUpload{{Path: "train.py", Content: []byte("print(1)")}},
+		SecretEnvironment: map[string]string{"TOKEN": "s3cret"},
+		WorkflowUuid:      "7b1d2c3e-4f5a-6789-abcd-ef0123456789",
+		Annotations:       map[string]string{"ci.pipeline": "42"},
+		Attempts:          2,
+		Events:            []domain.JobEvent{{Time: started, Type: domain.JobEventStarted, Message: "attempt 1"}},
+	}
+	running := &domain.Job{Uuid: "uuid-serve", Command: "serve", Status: domain.StatusRunning, StartTime: started.Add(-time.Hour)}
+
+	source := &adaptersfakes.FakeJobStorer{}
+	source.ListJobsReturns([]*domain.Job{completed, running})
+	s, _ := newBackupTestServer(source, workflow.NewWorkflowManager())
+
+	stream := &fakeJobExportStream{}
+	if err := s.ExportJobs(&backuppb.ExportJobsRequest{}, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.jobs) != 2 || stream.jobs[0].Uuid != "uuid-serve" {
+		t.Fatalf("ExportJobs() = %v, want both jobs oldest first", stream.jobs)
+	}
+	record := stream.jobs[1]
+	if record.SecretEnvironment["TOKEN"] == "s3cret" {
+		t.Error("ExportJobs() exported a secret value")
+	}
+	if len(record.Uploads) != 1 || record.Uploads[0] != "train.py" {
+		t.Errorf("Uploads = %v, want the upload paths", record.Uploads)
+	}
+
+	endedOnly := &fakeJobExportStream{}
+	if err := s.ExportJobs(&backuppb.ExportJobsRequest{EndedOnly: true}, endedOnly); err != nil {
+		t.Fatal(err)
+	}
+	if len(endedOnly.jobs) != 1 {
+		t.Errorf("ExportJobs(ended only) = %d jobs, want 1", len(endedOnly.jobs))
+	}
+
+	target := &adaptersfakes.FakeJobStorer{}
+	target.JobStub = func(id string) (*domain.Job, bool) { return nil, id == "uuid-dup" }
+	s, _ = newBackupTestServer(target, workflow.NewWorkflowManager())
+	dup := &backuppb.ExportedJob{Uuid: "uuid-dup", Status: "FAILED"}
+	result, err := s.ImportJobs(context.Background(), &backuppb.ImportJobsRequest{Jobs: append(stream.jobs, dup)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 || len(result.Skipped) != 2 {
+		t.Fatalf("ImportJobs() = %v, want the running and duplicate jobs skipped", result)
+	}
+	if target.CreateNewJobCallCount() != 1 {
+		t.Fatalf("CreateNewJob called %d times", target.CreateNewJobCallCount())
+	}
+
+	imported := target.CreateNewJobArgsForCall(0)
+	if imported.Uuid != "uuid-train" || imported.Status != domain.StatusCompleted || !imported.StartTime.Equal(started) ||
+		imported.EndTime == nil || !imported.EndTime.Equal(ended) || imported.Attempts != 2 || len(imported.Events) != 1 {
+		t.Errorf("imported job = %+v", imported)
+	}
+	if imported.Limits.CPU.Value() != 200 || imported.Limits.Memory.Megabytes() != 1024 || imported.GPUCount != 1 {
+		t.Errorf("imported limits = %+v, GPUs %d", imported.Limits, imported.GPUCount)
+	}
+	if imported.Annotations["ci.pipeline"] != "42" || imported.WorkflowUuid != completed.WorkflowUuid {
+		t.Errorf("imported annotations = %v, workflow %s", imported.Annotations, imported.WorkflowUuid)
+	}
+}
+
+func TestBackupWorkflowsRoundTrip(t *testing.T) {
+	const workflowUUID = "7b1d2c3e-4f5a-6789-abcd-ef0123456789"
+
+	manager := workflow.NewWorkflowManager()
+	workflowID, err := manager.CreateWorkflowWithYaml("pipeline.yaml", "jobs: {}", map[string]*workflow.JobDependency{
+		"fetch": {JobID: "fetch", InternalName: "fetch", Status: domain.StatusPending},
+		"train": {JobID: "train", InternalName: "train", Status: domain.StatusPending,
+			Requirements: []workflow.Requirement{{Type: workflow.RequirementSimple, JobID: "fetch", Status: "COMPLETED"}}},
+	}, []string{"fetch", "train"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UpdateJobID("fetch", "uuid-fetch"); err != nil {
+		t.Fatal(err)
+	}
+	manager.OnJobStateChange("uuid-fetch", domain.StatusRunning)
+	manager.OnJobStateChange("uuid-fetch", domain.StatusFailed)
+
+	ended, err := manager.GetWorkflowStatus(workflowID)
+	if err != nil || !ended.Status.Ended() {
+		t.Fatalf("workflow = %+v, %v, want it ended", ended, err)
+	}
+
+	s, workflows := newBackupTestServer(&adaptersfakes.FakeJobStorer{}, manager)
+	workflows.storeWorkflowMapping(workflowUUID, workflowID)
+	stream := &fakeWorkflowExportStream{}
+	if err := s.ExportWorkflows(&backuppb.ExportWorkflowsRequest{}, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.workflows) != 1 {
+		t.Fatalf("ExportWorkflows() = %v", stream.workflows)
+	}
+	record := stream.workflows[0]
+	if record.Uuid != workflowUUID || record.Status != string(ended.Status) || len(record.Jobs) != 2 ||
+		record.Jobs[0].JobUuid != "uuid-fetch" || record.Jobs[1].JobUuid != "" || len(record.Jobs[1].Requirements) != 1 {
+		t.Fatalf("exported workflow = %v", record)
+	}
+
+	target, targetWorkflows := newBackupTestServer(&adaptersfakes.FakeJobStorer{}, workflow.NewWorkflowManager())
+	result, err := target.ImportWorkflows(context.Background(), &backuppb.ImportWorkflowsRequest{Workflows: stream.workflows})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 {
+		t.Fatalf("ImportWorkflows() = %v", result)
+	}
+	importedID, found := targetWorkflows.lookupWorkflowID("7b1d2c3e")
+	if !found {
+		t.Fatal("imported workflow not found by its UUID")
+	}
+	state, err := targetWorkflows.workflowManager.GetWorkflowStatus(importedID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != ended.Status || state.YamlContent != "jobs: {}" || state.Jobs["uuid-fetch"] == nil ||
+		state.Jobs["train"].Requirements[0].JobID != "fetch" {
+		t.Errorf("imported workflow = %+v", state)
+	}
+
+	// A second import finds the workflow in place
+	result, err = target.ImportWorkflows(context.Background(), &backuppb.ImportWorkflowsRequest{Workflows: stream.workflows})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != "already exists" {
+		t.Errorf("second ImportWorkflows() = %v", result)
+	}
+}
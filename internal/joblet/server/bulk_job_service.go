@@ -0,0 +1,440 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/internal/joblet/retention"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxParallelStops bounds the jobs stopped at once by a bulk stop; each stop
+// waits for its process to exit
+const maxParallelStops = 16
+
+// Completed runs per job name GetJobDurationHistory averages by default and
+// at most
+const (
+	defaultDurationSamples = 5
+	maxDurationSamples     = 50
+)
+
+// BulkJobServiceServer implements the gRPC bulk job service
+type BulkJobServiceServer struct {
+	jobspb.UnimplementedBulkJobServiceServer
+	auth     auth2.GRPCAuthorization
+	jobStore adapters.JobStorer
+	joblet   interfaces.Joblet
+	reaper   *retention.Reaper
+	persist  persistpb.PersistServiceClient // nil when persist is unavailable
+	logger   *logger.Logger
+}
+
+// NewBulkJobServiceServer creates a new bulk job service server. persist may
+// be nil, in which case duration history comes from the job store only.
+func NewBulkJobServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, joblet interfaces.Joblet, reaper *retention.Reaper, persist persistpb.PersistServiceClient) *BulkJobServiceServer {
+	return &BulkJobServiceServer{
+		auth:     auth,
+		jobStore: jobStore,
+		joblet:   joblet,
+		reaper:   reaper,
+		persist:  persist,
+		logger:   logger.WithField("component", "bulk-jobs-grpc"),
+	}
+}
+
+// GetRetentionPolicy returns the finished job retention policy and the
+// outcome of the reaper's sweeps
+func (s *BulkJobServiceServer) GetRetentionPolicy(ctx context.Context, _ *jobspb.GetRetentionPolicyRequest) (*jobspb.RetentionPolicy, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListJobsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetRetentionPolicy", "error", err)
+		return nil, err
+	}
+	if s.reaper == nil {
+		return &jobspb.RetentionPolicy{}, nil
+	}
+
+	st := s.reaper.Status()
+	policy := &jobspb.RetentionPolicy{
+		Enabled:         st.Policy.Enabled(),
+		KeepLast:        int32(st.Policy.KeepLast),
+		MaxAgeSeconds:   int64(st.Policy.MaxAge.Seconds()),
+		IntervalSeconds: int64(st.Policy.Interval.Seconds()),
+		Archive:         st.Policy.Archive,
+		LastRemoved:     int32(st.LastRemoved),
+		TotalRemoved:    st.TotalRemoved,
+	}
+	if !st.LastSweep.IsZero() {
+		policy.LastSweep = st.LastSweep.Unix()
+	}
+	return policy, nil
+}
+
+// completedRun is one completed run of a named job
+type completedRun struct {
+	jobID string
+	name  string
+	end   time.Time
+	took  time.Duration
+}
+
+// GetJobDurationHistory summarizes the most recent completed runs of each
+// named job, from the job store and the records archived to persist
+func (s *BulkJobServiceServer) GetJobDurationHistory(ctx context.Context, req *jobspb.JobDurationHistoryRequest) (*jobspb.JobDurationHistory, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListJobsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetJobDurationHistory", "error", err)
+		return nil, err
+	}
+	samples := int(req.Samples)
+	if samples <= 0 {
+		samples = defaultDurationSamples
+	}
+	samples = min(samples, maxDurationSamples)
+
+	wanted := make(map[string]bool, len(req.Names))
+	for _, name := range req.Names {
+		wanted[name] = true
+	}
+	var runs []completedRun
+	seen := make(map[string]bool)
+	for _, job := range s.jobStore.ListJobs() {
+		if job.Name == "" || !wanted[job.Name] || job.Status != domain.StatusCompleted || job.EndTime == nil {
+			continue
+		}
+		runs = append(runs, completedRun{jobID: job.Uuid, name: job.Name, end: *job.EndTime, took: job.EndTime.Sub(job.StartTime)})
+		seen[job.Uuid] = true
+	}
+
+	// Records archived by the retention reaper have left the job store
+	if s.persist != nil && len(req.Names) > 0 {
+		queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		resp, err := s.persist.QueryJobDurations(queryCtx, &persistpb.QueryJobDurationsRequest{Names: req.Names, Limit: int32(samples)})
+		cancel()
+		if err != nil {
+			s.logger.Debug("duration history from persist unavailable", "error", err)
+		} else {
+			for _, d := range resp.Durations {
+				if seen[d.JobId] {
+					continue
+				}
+				runs = append(runs, completedRun{jobID: d.JobId, name: d.Name, end: time.Unix(0, d.EndTime), took: time.Duration(d.EndTime - d.StartTime)})
+			}
+		}
+	}
+
+	return &jobspb.JobDurationHistory{Stats: durationStats(runs, samples)}, nil
+}
+
+// durationStats averages the newest samples runs of each name, ordered by name
+func durationStats(runs []completedRun, samples int) []*jobspb.JobDurationStats {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].name != runs[j].name {
+			return runs[i].name < runs[j].name
+		}
+		return runs[i].end.After(runs[j].end)
+	})
+
+	var stats []*jobspb.JobDurationStats
+	var current *jobspb.JobDurationStats
+	var total time.Duration
+	for _, run := range runs {
+		if current == nil || current.Name != run.name {
+			current = &jobspb.JobDurationStats{Name: run.name, LastSeconds: run.took.Seconds()}
+			stats = append(stats, current)
+			total = 0
+		}
+		if int(current.Samples) == samples {
+			continue
+		}
+		current.Samples++
+		total += run.took
+		current.MeanSeconds = total.Seconds() / float64(current.Samples)
+	}
+	return stats
+}
+
+// ListJobGroups returns the job groups known to the server, or the one named
+// in the request
+func (s *BulkJobServiceServer) ListJobGroups(ctx context.Context, req *jobspb.ListJobGroupsRequest) (*jobspb.ListJobGroupsResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListJobsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ListJobGroups", "error", err)
+		return nil, err
+	}
+
+	jobs := s.jobStore.ListJobs()
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].StartTime.Before(jobs[j].StartTime) })
+
+	groups := make(map[string]*jobspb.JobGroup)
+	for _, job := range jobs {
+		if job.Group == "" || (req.Name != "" && job.Group != req.Name) {
+			continue
+		}
+		group, exists := groups[job.Group]
+		if !exists {
+			group = &jobspb.JobGroup{Name: job.Group, StatusCounts: make(map[string]int32)}
+			groups[job.Group] = group
+		}
+		group.JobUuids = append(group.JobUuids, job.Uuid)
+		group.StatusCounts[string(job.Status)]++
+	}
+
+	if req.Name != "" && groups[req.Name] == nil {
+		return nil, status.Errorf(codes.NotFound, "job group %s not found", req.Name)
+	}
+
+	response := &jobspb.ListJobGroupsResponse{}
+	for _, group := range groups {
+		response.Groups = append(response.Groups, group)
+	}
+	sort.Slice(response.Groups, func(i, j int) bool { return response.Groups[i].Name < response.Groups[j].Name })
+	return response, nil
+}
+
+// StopJobGroup stops every running job of a group and cancels its scheduled
+// ones
+func (s *BulkJobServiceServer) StopJobGroup(ctx context.Context, req *jobspb.StopJobGroupRequest) (*jobspb.StopJobsResponse, error) {
+	log := s.logger.WithFields("operation", "StopJobGroup", "group", req.Name)
+	if err := s.auth.Authorized(ctx, auth2.StopJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if _, err := values.NewGroupName(req.Name); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	var members []*domain.Job
+	for _, job := range s.jobStore.ListJobs() {
+		if job.Group == req.Name {
+			members = append(members, job)
+		}
+	}
+	if len(members) == 0 {
+		return nil, status.Errorf(codes.NotFound, "job group %s not found", req.Name)
+	}
+
+	response := s.stopJobs(ctx, members, false)
+	log.Info("job group stopped", "stopped", len(response.Stopped), "failed", len(response.Failed), "skipped", response.Skipped)
+	return response, nil
+}
+
+// StopAllJobs stops the running and scheduled jobs matching every filter of
+// the request, or only lists them for a dry run
+func (s *BulkJobServiceServer) StopAllJobs(ctx context.Context, req *jobspb.StopAllJobsRequest) (*jobspb.StopJobsResponse, error) {
+	log := s.logger.WithFields("operation", "StopAllJobs", "dryRun", req.DryRun)
+	if err := s.auth.Authorized(ctx, auth2.StopJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+
+	filter, err := newStopFilter(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	var matched []*domain.Job
+	for _, job := range s.jobStore.ListJobs() {
+		if filter.matches(job) {
+			matched = append(matched, job)
+		}
+	}
+
+	response := s.stopJobs(ctx, matched, req.DryRun)
+	log.Info("bulk stop completed", "stopped", len(response.Stopped), "failed", len(response.Failed), "skipped", response.Skipped)
+	return response, nil
+}
+
+// StopWorkflowJobs stops every running job of a workflow and cancels its
+// scheduled ones
+func (s *BulkJobServiceServer) StopWorkflowJobs(ctx context.Context, req *jobspb.StopWorkflowJobsRequest) (*jobspb.StopJobsResponse, error) {
+	log := s.logger.WithFields("operation", "StopWorkflowJobs", "workflowUuid", req.WorkflowUuid)
+	if err := s.auth.Authorized(ctx, auth2.StopJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if req.WorkflowUuid == "" {
+		return nil, status.Error(codes.InvalidArgument, "workflow UUID is required")
+	}
+
+	filter := &stopFilter{workflowUuid: req.WorkflowUuid}
+	var members []*domain.Job
+	for _, job := range s.jobStore.ListJobs() {
+		if filter.matches(job) {
+			members = append(members, job)
+		}
+	}
+	if len(members) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no jobs found for workflow %s", req.WorkflowUuid)
+	}
+
+	response := s.stopJobs(ctx, members, false)
+	log.Info("workflow jobs stopped", "stopped", len(response.Stopped), "failed", len(response.Failed), "skipped", response.Skipped)
+	return response, nil
+}
+
+// GetJobArray returns the jobs of a job array, named by its UUID or a unique
+// prefix of it, with their status counts
+func (s *BulkJobServiceServer) GetJobArray(ctx context.Context, req *jobspb.GetJobArrayRequest) (*jobspb.JobArray, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListJobsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetJobArray", "error", err)
+		return nil, err
+	}
+	if req.Uuid == "" {
+		return nil, status.Error(codes.InvalidArgument, "job array UUID is required")
+	}
+
+	var array *jobspb.JobArray
+	for _, job := range s.jobStore.ListJobs() {
+		if job.ArrayUuid == "" || !strings.HasPrefix(job.ArrayUuid, req.Uuid) {
+			continue
+		}
+		if array == nil {
+			array = &jobspb.JobArray{Uuid: job.ArrayUuid, StatusCounts: make(map[string]int32)}
+		} else if array.Uuid != job.ArrayUuid {
+			return nil, status.Errorf(codes.InvalidArgument, "job array UUID prefix %s is ambiguous", req.Uuid)
+		}
+		array.Tasks = append(array.Tasks, &jobspb.JobArrayTask{
+			Index:    int32(job.ArrayIndex),
+			JobUuid:  job.Uuid,
+			Status:   string(job.Status),
+			ExitCode: job.ExitCode,
+		})
+		array.StatusCounts[string(job.Status)]++
+	}
+	if array == nil {
+		return nil, status.Errorf(codes.NotFound, "job array %s not found", req.Uuid)
+	}
+
+	array.Size = int32(len(array.Tasks))
+	sort.Slice(array.Tasks, func(i, j int) bool { return array.Tasks[i].Index < array.Tasks[j].Index })
+	return array, nil
+}
+
+// stopFilter selects jobs for StopAllJobs; empty fields match every job
+type stopFilter struct {
+	statuses     map[domain.JobStatus]bool
+	labels       map[string]string
+	group        string
+	workflowUuid string // Full UUID or prefix
+	arrayUuid    string // Full UUID or prefix
+	jobUuids     map[string]bool
+}
+
+// newStopFilter validates the filters of a StopAllJobs request
+func newStopFilter(req *jobspb.StopAllJobsRequest) (*stopFilter, error) {
+	filter := &stopFilter{labels: req.Labels, group: req.Group, workflowUuid: req.WorkflowUuid, arrayUuid: req.ArrayUuid}
+
+	for _, name := range req.Statuses {
+		jobStatus := domain.JobStatus(strings.ToUpper(name))
+		if jobStatus != domain.StatusRunning && jobStatus != domain.StatusScheduled && jobStatus != domain.StatusQueued {
+			return nil, fmt.Errorf("invalid status filter %s: only RUNNING, SCHEDULED and QUEUED jobs can be stopped", name)
+		}
+		if filter.statuses == nil {
+			filter.statuses = make(map[domain.JobStatus]bool)
+		}
+		filter.statuses[jobStatus] = true
+	}
+	for key, value := range req.Labels {
+		if err := values.ValidateLabel(key, value); err != nil {
+			return nil, err
+		}
+	}
+	if req.Group != "" {
+		if _, err := values.NewGroupName(req.Group); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.JobUuids) > 0 {
+		filter.jobUuids = make(map[string]bool, len(req.JobUuids))
+		for _, uuid := range req.JobUuids {
+			filter.jobUuids[uuid] = true
+		}
+	}
+	return filter, nil
+}
+
+// matches reports whether job passes every filter. The status filter only
+// applies to jobs that can still be stopped, so ended jobs that match the
+// other filters are reported as skipped.
+func (f *stopFilter) matches(job *domain.Job) bool {
+	if f.group != "" && job.Group != f.group {
+		return false
+	}
+	if f.workflowUuid != "" && (job.WorkflowUuid == "" || !strings.HasPrefix(job.WorkflowUuid, f.workflowUuid)) {
+		return false
+	}
+	if f.arrayUuid != "" && (job.ArrayUuid == "" || !strings.HasPrefix(job.ArrayUuid, f.arrayUuid)) {
+		return false
+	}
+	if f.jobUuids != nil && !f.jobUuids[job.Uuid] {
+		return false
+	}
+	for key, value := range f.labels {
+		if jobValue, exists := job.Labels[key]; !exists || jobValue != value {
+			return false
+		}
+	}
+	if f.statuses != nil && stoppable(job) && !f.statuses[job.Status] {
+		return false
+	}
+	return true
+}
+
+// stoppable reports whether StopJob can act on the job
+func stoppable(job *domain.Job) bool {
+	return job.IsRunning() || job.IsScheduled() || job.IsQueued()
+}
+
+// stopJobs stops the running, scheduled and queued jobs among jobs, a few at a time.
+// Jobs that already ended are counted as skipped. A dry run only lists the
+// jobs that would be stopped.
+func (s *BulkJobServiceServer) stopJobs(ctx context.Context, jobs []*domain.Job, dryRun bool) *jobspb.StopJobsResponse {
+	response := &jobspb.StopJobsResponse{DryRun: dryRun}
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxParallelStops)
+	)
+	for _, job := range jobs {
+		if !stoppable(job) {
+			response.Skipped++
+			continue
+		}
+		if dryRun {
+			response.Stopped = append(response.Stopped, job.Uuid)
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(jobID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := s.joblet.StopJob(ctx, interfaces.StopJobRequest{JobID: jobID, Reason: "bulk_stop"})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				response.Failed = append(response.Failed, &jobspb.JobStopFailure{JobUuid: jobID, Error: err.Error()})
+				return
+			}
+			response.Stopped = append(response.Stopped, jobID)
+		}(job.Uuid)
+	}
+	wg.Wait()
+
+	sort.Strings(response.Stopped)
+	sort.Slice(response.Failed, func(i, j int) bool { return response.Failed[i].JobUuid < response.Failed[j].JobUuid })
+	return response
+}
@@ -0,0 +1,336 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces/interfacesfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/retention"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestBulkJobService(jobs []*domain.Job) (*BulkJobServiceServer, *interfacesfakes.FakeJoblet) {
+	store := &adaptersfakes.FakeJobStorer{}
+	store.ListJobsReturns(jobs)
+	joblet := &interfacesfakes.FakeJoblet{}
+	return NewBulkJobServiceServer(&authfakes.FakeGRPCAuthorization{}, store, joblet, nil, nil), joblet
+}
+
+func groupTestJobs() []*domain.Job {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []*domain.Job{
+		{Uuid: "job-3", Group: "load-test", Status: domain.StatusCompleted, StartTime: start.Add(2 * time.Minute)},
+		{Uuid: "job-1", Group: "load-test", Status: domain.StatusRunning, StartTime: start},
+		{Uuid: "job-2", Group: "load-test", Status: domain.StatusScheduled, StartTime: start.Add(time.Minute)},
+		{Uuid: "job-4", Group: "nightly", Status: domain.StatusRunning, StartTime: start},
+		{Uuid: "job-5", Status: domain.StatusRunning, StartTime: start},
+	}
+}
+
+func TestListJobGroups(t *testing.T) {
+	s, _ := newTestBulkJobService(groupTestJobs())
+
+	res, err := s.ListJobGroups(context.Background(), &jobspb.ListJobGroupsRequest{})
+	if err != nil {
+		t.Fatalf("ListJobGroups: %v", err)
+	}
+	if len(res.Groups) != 2 || res.Groups[0].Name != "load-test" || res.Groups[1].Name != "nightly" {
+		t.Fatalf("groups = %v", res.Groups)
+	}
+	loadTest := res.Groups[0]
+	if got := loadTest.JobUuids; len(got) != 3 || got[0] != "job-1" || got[1] != "job-2" || got[2] != "job-3" {
+		t.Errorf("job UUIDs = %v, want start order", got)
+	}
+	if loadTest.StatusCounts["RUNNING"] != 1 || loadTest.StatusCounts["SCHEDULED"] != 1 || loadTest.StatusCounts["COMPLETED"] != 1 {
+		t.Errorf("status counts = %v", loadTest.StatusCounts)
+	}
+
+	res, err = s.ListJobGroups(context.Background(), &jobspb.ListJobGroupsRequest{Name: "nightly"})
+	if err != nil || len(res.Groups) != 1 || res.Groups[0].Name != "nightly" {
+		t.Errorf("ListJobGroups(nightly) = %v, %v", res, err)
+	}
+
+	if _, err := s.ListJobGroups(context.Background(), &jobspb.ListJobGroupsRequest{Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown group: got %v, want NotFound", err)
+	}
+}
+
+func TestStopJobGroup(t *testing.T) {
+	s, joblet := newTestBulkJobService(groupTestJobs())
+	joblet.StopJobCalls(func(_ context.Context, req interfaces.StopJobRequest) error {
+		if req.JobID == "job-2" {
+			return errors.New("failed to remove scheduled job")
+		}
+		return nil
+	})
+
+	res, err := s.StopJobGroup(context.Background(), &jobspb.StopJobGroupRequest{Name: "load-test"})
+	if err != nil {
+		t.Fatalf("StopJobGroup: %v", err)
+	}
+	if len(res.Stopped) != 1 || res.Stopped[0] != "job-1" {
+		t.Errorf("stopped = %v, want [job-1]", res.Stopped)
+	}
+	if len(res.Failed) != 1 || res.Failed[0].JobUuid != "job-2" {
+		t.Errorf("failed = %v, want job-2", res.Failed)
+	}
+	if res.Skipped != 1 {
+		t.Errorf("skipped = %d, want the completed job", res.Skipped)
+	}
+	if joblet.StopJobCallCount() != 2 {
+		t.Errorf("StopJob called %d times, want 2", joblet.StopJobCallCount())
+	}
+
+	if _, err := s.StopJobGroup(context.Background(), &jobspb.StopJobGroupRequest{Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown group: got %v, want NotFound", err)
+	}
+	if _, err := s.StopJobGroup(context.Background(), &jobspb.StopJobGroupRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty group: got %v, want InvalidArgument", err)
+	}
+}
+
+func filterTestJobs() []*domain.Job {
+	staging := map[string]string{"env": "staging"}
+	return []*domain.Job{
+		{Uuid: "run-staging", Status: domain.StatusRunning, Labels: staging},
+		{Uuid: "sched-staging", Status: domain.StatusScheduled, Labels: map[string]string{"env": "staging", "team": "ml"}},
+		{Uuid: "done-staging", Status: domain.StatusCompleted, Labels: staging},
+		{Uuid: "run-prod", Status: domain.StatusRunning, Labels: map[string]string{"env": "prod"}},
+		{Uuid: "run-workflow", Status: domain.StatusRunning, WorkflowUuid: "7b1d2c3e-4f5a-6789-abcd-ef0123456789", Group: "7b1d2c3e-4f5a-6789-abcd-ef0123456789"},
+		{Uuid: "run-array", Status: domain.StatusRunning, ArrayUuid: "a1b2c3d4-0000-4000-8000-000000000000", ArrayIndex: 3},
+		{Uuid: "run-plain", Status: domain.StatusRunning},
+	}
+}
+
+func TestStopAllJobs_Filters(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *jobspb.StopAllJobsRequest
+		wantStopped []string
+		wantSkipped int32
+	}{
+		{
+			name:        "label",
+			req:         &jobspb.StopAllJobsRequest{Labels: map[string]string{"env": "staging"}},
+			wantStopped: []string{"run-staging", "sched-staging"},
+			wantSkipped: 1,
+		},
+		{
+			name:        "label and status",
+			req:         &jobspb.StopAllJobsRequest{Labels: map[string]string{"env": "staging"}, Statuses: []string{"running"}},
+			wantStopped: []string{"run-staging"},
+			wantSkipped: 1,
+		},
+		{
+			name:        "all labels must match",
+			req:         &jobspb.StopAllJobsRequest{Labels: map[string]string{"env": "staging", "team": "ml"}},
+			wantStopped: []string{"sched-staging"},
+		},
+		{
+			name:        "workflow prefix",
+			req:         &jobspb.StopAllJobsRequest{WorkflowUuid: "7b1d2c3e"},
+			wantStopped: []string{"run-workflow"},
+		},
+		{
+			name:        "group",
+			req:         &jobspb.StopAllJobsRequest{Group: "7b1d2c3e-4f5a-6789-abcd-ef0123456789"},
+			wantStopped: []string{"run-workflow"},
+		},
+		{
+			name:        "array prefix",
+			req:         &jobspb.StopAllJobsRequest{ArrayUuid: "a1b2c3d4"},
+			wantStopped: []string{"run-array"},
+		},
+		{
+			name:        "job UUIDs",
+			req:         &jobspb.StopAllJobsRequest{JobUuids: []string{"run-prod", "run-plain"}, Statuses: []string{"RUNNING"}},
+			wantStopped: []string{"run-plain", "run-prod"},
+		},
+		{
+			name:        "no filters",
+			req:         &jobspb.StopAllJobsRequest{},
+			wantStopped: []string{"run-array", "run-plain", "run-prod", "run-staging", "run-workflow", "sched-staging"},
+			wantSkipped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, joblet := newTestBulkJobService(filterTestJobs())
+			res, err := s.StopAllJobs(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("StopAllJobs: %v", err)
+			}
+			if !reflect.DeepEqual(res.Stopped, tt.wantStopped) {
+				t.Errorf("stopped = %v, want %v", res.Stopped, tt.wantStopped)
+			}
+			if res.Skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", res.Skipped, tt.wantSkipped)
+			}
+			if joblet.StopJobCallCount() != len(tt.wantStopped) {
+				t.Errorf("StopJob called %d times, want %d", joblet.StopJobCallCount(), len(tt.wantStopped))
+			}
+		})
+	}
+}
+
+func TestStopAllJobs_DryRun(t *testing.T) {
+	s, joblet := newTestBulkJobService(filterTestJobs())
+
+	res, err := s.StopAllJobs(context.Background(), &jobspb.StopAllJobsRequest{Labels: map[string]string{"env": "staging"}, DryRun: true})
+	if err != nil {
+		t.Fatalf("StopAllJobs: %v", err)
+	}
+	if !res.DryRun || !reflect.DeepEqual(res.Stopped, []string{"run-staging", "sched-staging"}) {
+		t.Errorf("dry run = %v", res)
+	}
+	if joblet.StopJobCallCount() != 0 {
+		t.Errorf("dry run stopped %d jobs", joblet.StopJobCallCount())
+	}
+}
+
+func TestStopAllJobs_InvalidFilters(t *testing.T) {
+	s, _ := newTestBulkJobService(filterTestJobs())
+
+	for name, req := range map[string]*jobspb.StopAllJobsRequest{
+		"completed status": {Statuses: []string{"COMPLETED"}},
+		"bad label":        {Labels: map[string]string{"env": "a b"}},
+		"bad group":        {Group: "-x"},
+	} {
+		if _, err := s.StopAllJobs(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestStopWorkflowJobs(t *testing.T) {
+	s, joblet := newTestBulkJobService(filterTestJobs())
+
+	res, err := s.StopWorkflowJobs(context.Background(), &jobspb.StopWorkflowJobsRequest{WorkflowUuid: "7b1d2c3e-4f5a-6789-abcd-ef0123456789"})
+	if err != nil || !reflect.DeepEqual(res.Stopped, []string{"run-workflow"}) || joblet.StopJobCallCount() != 1 {
+		t.Errorf("StopWorkflowJobs = %v, %v", res, err)
+	}
+	if _, err := s.StopWorkflowJobs(context.Background(), &jobspb.StopWorkflowJobsRequest{WorkflowUuid: "ffffffff"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown workflow: got %v, want NotFound", err)
+	}
+}
+
+func TestGetJobArray(t *testing.T) {
+	s, _ := newTestBulkJobService([]*domain.Job{
+		{Uuid: "job-b", ArrayUuid: "a1b2c3d4-0000", ArrayIndex: 1, Status: domain.StatusFailed, ExitCode: 2},
+		{Uuid: "job-a", ArrayUuid: "a1b2c3d4-0000", ArrayIndex: 0, Status: domain.StatusCompleted},
+		{Uuid: "job-c", ArrayUuid: "a1b2c3d4-0000", ArrayIndex: 2, Status: domain.StatusCompleted},
+		{Uuid: "job-d", ArrayUuid: "a1ffffff-0000", Status: domain.StatusRunning},
+		{Uuid: "job-e", Status: domain.StatusRunning},
+	})
+
+	array, err := s.GetJobArray(context.Background(), &jobspb.GetJobArrayRequest{Uuid: "a1b2"})
+	if err != nil {
+		t.Fatalf("GetJobArray: %v", err)
+	}
+	if array.Uuid != "a1b2c3d4-0000" || array.Size != 3 {
+		t.Errorf("array = %s with %d jobs", array.Uuid, array.Size)
+	}
+	for i, want := range []string{"job-a", "job-b", "job-c"} {
+		if array.Tasks[i].JobUuid != want || array.Tasks[i].Index != int32(i) {
+			t.Errorf("task %d = %v, want %s", i, array.Tasks[i], want)
+		}
+	}
+	if array.Tasks[1].ExitCode != 2 || array.StatusCounts["COMPLETED"] != 2 || array.StatusCounts["FAILED"] != 1 {
+		t.Errorf("array = %v", array)
+	}
+
+	if _, err := s.GetJobArray(context.Background(), &jobspb.GetJobArrayRequest{Uuid: "a1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ambiguous prefix: got %v, want InvalidArgument", err)
+	}
+	if _, err := s.GetJobArray(context.Background(), &jobspb.GetJobArrayRequest{Uuid: "ffff"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown array: got %v, want NotFound", err)
+	}
+}
+
+func TestGetRetentionPolicy(t *testing.T) {
+	end := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &adaptersfakes.FakeJobStorer{}
+	store.ListJobsReturns([]*domain.Job{
+		{Uuid: "old", Name: "nightly", Status: domain.StatusCompleted, StartTime: end.Add(-time.Hour), EndTime: &end},
+	})
+	policy := config.RetentionConfig{MaxAge: 24 * time.Hour, Interval: 10 * time.Minute}
+	reaper := retention.NewReaper(policy, store, nil)
+	s := NewBulkJobServiceServer(&authfakes.FakeGRPCAuthorization{}, store, &interfacesfakes.FakeJoblet{}, reaper, nil)
+
+	sweep := end.Add(48 * time.Hour)
+	reaper.Sweep(context.Background(), sweep)
+
+	res, err := s.GetRetentionPolicy(context.Background(), &jobspb.GetRetentionPolicyRequest{})
+	if err != nil {
+		t.Fatalf("GetRetentionPolicy: %v", err)
+	}
+	if !res.Enabled || res.KeepLast != 0 || res.MaxAgeSeconds != 86400 || res.IntervalSeconds != 600 || res.Archive {
+		t.Errorf("policy = %v", res)
+	}
+	if res.LastSweep != sweep.Unix() || res.LastRemoved != 1 || res.TotalRemoved != 1 || store.DeleteJobArgsForCall(0) != "old" {
+		t.Errorf("sweep stats = %v", res)
+	}
+}
+
+// fakeDurationPersist answers QueryJobDurations with fixed runs
+type fakeDurationPersist struct {
+	persistpb.PersistServiceClient
+	durations []*persistpb.JobDuration
+}
+
+func (f *fakeDurationPersist) QueryJobDurations(_ context.Context, req *persistpb.QueryJobDurationsRequest, _ ...grpc.CallOption) (*persistpb.QueryJobDurationsResponse, error) {
+	return &persistpb.QueryJobDurationsResponse{Durations: f.durations}, nil
+}
+
+func TestGetJobDurationHistory(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	completed := func(id, name string, start time.Time, took time.Duration) *domain.Job {
+		end := start.Add(took)
+		return &domain.Job{Uuid: id, Name: name, Status: domain.StatusCompleted, StartTime: start, EndTime: &end}
+	}
+	failedEnd := day.Add(time.Minute)
+	store := &adaptersfakes.FakeJobStorer{}
+	store.ListJobsReturns([]*domain.Job{
+		completed("train-3", "train", day.Add(48*time.Hour), 30*time.Minute),
+		completed("train-2", "train", day.Add(24*time.Hour), 20*time.Minute),
+		{Uuid: "train-failed", Name: "train", Status: domain.StatusFailed, StartTime: day, EndTime: &failedEnd},
+		completed("other", "other", day, time.Minute),
+	})
+	// An archived run, and one still in the store that persist also reports
+	persist := &fakeDurationPersist{durations: []*persistpb.JobDuration{
+		{JobId: "train-1", Name: "train", StartTime: day.UnixNano(), EndTime: day.Add(10 * time.Minute).UnixNano()},
+		{JobId: "train-2", Name: "train", StartTime: day.UnixNano(), EndTime: day.Add(time.Hour).UnixNano()},
+	}}
+	s := NewBulkJobServiceServer(&authfakes.FakeGRPCAuthorization{}, store, &interfacesfakes.FakeJoblet{}, nil, persist)
+
+	res, err := s.GetJobDurationHistory(context.Background(), &jobspb.JobDurationHistoryRequest{Names: []string{"train", "never-ran"}})
+	if err != nil {
+		t.Fatalf("GetJobDurationHistory: %v", err)
+	}
+	if len(res.Stats) != 1 {
+		t.Fatalf("stats = %v, want train only", res.Stats)
+	}
+	train := res.Stats[0]
+	if train.Name != "train" || train.Samples != 3 || train.MeanSeconds != 1200 || train.LastSeconds != 1800 {
+		t.Errorf("train = %v, want 3 samples, mean 20m, last 30m", train)
+	}
+
+	res, _ = s.GetJobDurationHistory(context.Background(), &jobspb.JobDurationHistoryRequest{Names: []string{"train"}, Samples: 2})
+	if got := res.Stats[0]; got.Samples != 2 || got.MeanSeconds != 1500 {
+		t.Errorf("train = %v, want the 2 newest runs averaged", got)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/capacity"
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// CapacityServiceServer implements the gRPC capacity service
+type CapacityServiceServer struct {
+	capacitypb.UnimplementedCapacityServiceServer
+	auth       auth2.GRPCAuthorization
+	calculator *capacity.Calculator
+	logger     *logger.Logger
+}
+
+// NewCapacityServiceServer creates a new capacity service server
+func NewCapacityServiceServer(auth auth2.GRPCAuthorization, calculator *capacity.Calculator) *CapacityServiceServer {
+	return &CapacityServiceServer{
+		auth:       auth,
+		calculator: calculator,
+		logger:     logger.WithField("component", "capacity-grpc"),
+	}
+}
+
+// GetNodeCapacity returns total, reserved and allocated resources of the node
+func (s *CapacityServiceServer) GetNodeCapacity(ctx context.Context, req *capacitypb.GetNodeCapacityRequest) (*capacitypb.NodeCapacity, error) {
+	if err := s.auth.Authorized(ctx, auth2.GetNodeCapacityOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetNodeCapacity", "error", err)
+		return nil, err
+	}
+
+	return s.calculator.Snapshot(), nil
+}
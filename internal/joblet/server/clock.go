@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/clocksync"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// clientClock is the submission time of a scheduled job and the client's
+// clock when it sent the request, if it sent it
+type clientClock struct {
+	received time.Time
+	client   *clocksync.Status
+}
+
+// observeClientClock measures the server's clock against the one of the
+// client submitting a scheduled job
+func (s *WorkflowServiceServer) observeClientClock(ctx context.Context, scheduled bool) clientClock {
+	observed := clientClock{received: time.Now()}
+	if s.clock == nil || !scheduled {
+		return observed
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(constants.ClientTimeHeader); len(values) > 0 {
+		if sent, err := time.Parse(time.RFC3339Nano, values[0]); err == nil {
+			status := s.clock.ObserveClient(sent, observed.received)
+			observed.client = &status
+		}
+	}
+	return observed
+}
+
+// warnScheduleClock warns in the RunJob response when a job's scheduled time
+// falls within the uncertainty of the server's clock
+func (s *WorkflowServiceServer) warnScheduleClock(ctx context.Context, observed clientClock, scheduled time.Time) {
+	if s.clock == nil {
+		return
+	}
+	reference, known := s.clock.Reference(observed.client)
+	if !known {
+		return
+	}
+	warning := clocksync.ScheduleWarning(reference, scheduled, observed.received)
+	if warning == "" {
+		return
+	}
+	s.logger.Warn("scheduled job within the clock's uncertainty", "scheduledTime", scheduled, "offset", reference.Offset, "source", reference.Source)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(constants.ClockWarningHeader, warning)); err != nil {
+		s.logger.Warn("failed to set response header", "header", constants.ClockWarningHeader, "error", err)
+	}
+}
+
+// setClockStatusHeader reports the latest check of the server's clock in
+// the GetSystemStatus response
+func (s *MonitoringServiceServer) setClockStatusHeader(ctx context.Context) {
+	if s.clock == nil {
+		return
+	}
+	status, known := s.clock.Status()
+	if !known {
+		return
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(constants.ClockStatusHeader, string(data))); err != nil {
+		s.logger.Warn("failed to set response header", "header", constants.ClockStatusHeader, "error", err)
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+	"sync"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// jobDeduplicator remembers which active job was started for each request
+// hash, so an identical deduplicated submission can be answered with the
+// existing job. Submissions with the same hash are serialized while the first
+// one is starting, otherwise two racing requests would both start a job.
+type jobDeduplicator struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	started chan struct{} // closed once the job was started, or failed to start
+	jobID   string
+}
+
+func newJobDeduplicator() *jobDeduplicator {
+	return &jobDeduplicator{entries: make(map[string]*dedupEntry)}
+}
+
+// acquire returns the ID of an active job started for hash. When there is
+// none it reserves hash and returns a release func that must be called with
+// the new job's ID, or "" if starting the job failed.
+func (d *jobDeduplicator) acquire(ctx context.Context, hash string, active func(jobID string) bool) (string, func(jobID string), error) {
+	for {
+		d.mu.Lock()
+		d.prune(active)
+
+		entry, exists := d.entries[hash]
+		if !exists {
+			entry = &dedupEntry{started: make(chan struct{})}
+			d.entries[hash] = entry
+			d.mu.Unlock()
+			return "", d.releaseFunc(hash, entry), nil
+		}
+
+		select {
+		case <-entry.started:
+			// prune dropped every entry whose job is no longer active
+			jobID := entry.jobID
+			d.mu.Unlock()
+			return jobID, nil, nil
+		default:
+		}
+		d.mu.Unlock()
+
+		// An identical request is starting its job right now
+		select {
+		case <-entry.started:
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		}
+	}
+}
+
+func (d *jobDeduplicator) releaseFunc(hash string, entry *dedupEntry) func(jobID string) {
+	var once sync.Once
+	return func(jobID string) {
+		once.Do(func() {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			entry.jobID = jobID
+			if jobID == "" {
+				delete(d.entries, hash)
+			}
+			close(entry.started)
+		})
+	}
+}
+
+// prune drops entries whose job is no longer active. Callers hold d.mu.
+func (d *jobDeduplicator) prune(active func(jobID string) bool) {
+	for hash, entry := range d.entries {
+		select {
+		case <-entry.started:
+			if !active(entry.jobID) {
+				delete(d.entries, hash)
+			}
+		default:
+		}
+	}
+}
+
+// isDedupActive reports whether job still counts as a duplicate target:
+// it has been accepted and has not finished yet.
+func isDedupActive(job *domain.Job) bool {
+	switch job.Status {
+	case domain.StatusPending, domain.StatusQueued, domain.StatusInitializing, domain.StatusRunning:
+		return true
+	}
+	return false
+}
+
+// jobRequestHash hashes what determines a job's result: command, args,
+// runtime, uploads, volumes, network, working directory, resource limits, the
+// environment after reserved keys were removed, and the tenant, whose cloud
+// credentials the job runs with. Secret values are only ever hashed, never
+// stored.
+func jobRequestHash(req *pb.RunJobRequest, tenant string, env, secretEnv map[string]string) string {
+	h := sha256.New()
+	writeField(h, []byte(req.Command))
+	writeUint(h, uint64(len(req.Args)))
+	for _, arg := range req.Args {
+		writeField(h, []byte(arg))
+	}
+	writeField(h, []byte(req.Runtime))
+
+	uploads := make([]*pb.FileUpload, len(req.Uploads))
+	copy(uploads, req.Uploads)
+	sort.Slice(uploads, func(i, j int) bool { return uploads[i].Path < uploads[j].Path })
+	writeUint(h, uint64(len(uploads)))
+	for _, upload := range uploads {
+		writeField(h, []byte(upload.Path))
+		writeField(h, upload.Content)
+		writeUint(h, uint64(upload.Mode))
+		if upload.IsDirectory {
+			writeUint(h, 1)
+		} else {
+			writeUint(h, 0)
+		}
+	}
+
+	volumes := make([]string, len(req.Volumes))
+	copy(volumes, req.Volumes)
+	sort.Strings(volumes)
+	writeUint(h, uint64(len(volumes)))
+	for _, volume := range volumes {
+		writeField(h, []byte(volume))
+	}
+	writeField(h, []byte(req.Network))
+	writeField(h, []byte(req.WorkDir))
+
+	writeField(h, []byte(req.CpuCores))
+	for _, limit := range []int32{req.MaxCpu, req.MaxMemory, req.MaxIobps, req.GpuCount, req.GpuMemoryMb} {
+		writeUint(h, uint64(uint32(limit)))
+	}
+
+	writeMap(h, env)
+	writeMap(h, secretEnv)
+	writeField(h, []byte(tenant))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeMap(h hash.Hash, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeUint(h, uint64(len(keys)))
+	for _, key := range keys {
+		writeField(h, []byte(key))
+		writeField(h, []byte(m[key]))
+	}
+}
+
+// writeField writes b length-prefixed so field boundaries cannot shift
+func writeField(h hash.Hash, b []byte) {
+	writeUint(h, uint64(len(b)))
+	h.Write(b)
+}
+
+func writeUint(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
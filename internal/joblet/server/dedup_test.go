@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+func TestJobRequestHash(t *testing.T) {
+	req := &pb.RunJobRequest{
+		Command: "python3",
+		Args:    []string{"train.py", "--epochs=10"},
+		Uploads: []*pb.FileUpload{
+			{Path: "b.py", Content: []byte("b")},
+			{Path: "a.py", Content: []byte("a")},
+		},
+	}
+	env := map[string]string{"A": "1", "B": "2"}
+	base := jobRequestHash(req, "", env, nil)
+
+	// Upload and environment order do not matter
+	reordered := &pb.RunJobRequest{
+		Command: "python3",
+		Args:    []string{"train.py", "--epochs=10"},
+		Uploads: []*pb.FileUpload{req.Uploads[1], req.Uploads[0]},
+	}
+	if got := jobRequestHash(reordered, "", map[string]string{"B": "2", "A": "1"}, nil); got != base {
+		t.Error("hash changed with upload or environment order")
+	}
+
+	// Nor does volume order
+	mounted := &pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, Volumes: []string{"a", "b"}}
+	remounted := &pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, Volumes: []string{"b", "a"}}
+	if jobRequestHash(mounted, "", env, nil) != jobRequestHash(remounted, "", env, nil) {
+		t.Error("hash changed with volume order")
+	}
+
+	changes := map[string]func() string{
+		"args": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: []string{"train.py", "--epochs=1"}, Uploads: req.Uploads}, "", env, nil)
+		},
+		"arg boundaries": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: []string{"train.py--epochs=10"}, Uploads: req.Uploads}, "", env, nil)
+		},
+		"upload content": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: []*pb.FileUpload{{Path: "a.py", Content: []byte("x")}, req.Uploads[0]}}, "", env, nil)
+		},
+		"env": func() string { return jobRequestHash(req, "", map[string]string{"A": "1", "B": "3"}, nil) },
+		"secret env": func() string {
+			return jobRequestHash(req, "", env, map[string]string{"TOKEN": "x"})
+		},
+		"tenant": func() string { return jobRequestHash(req, "analytics", env, nil) },
+		"volume": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, Volumes: []string{"datasets"}}, "", env, nil)
+		},
+		"network": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, Network: "none"}, "", env, nil)
+		},
+		"workdir": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, WorkDir: "/work/src"}, "", env, nil)
+		},
+		"memory limit": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, MaxMemory: 512}, "", env, nil)
+		},
+		"gpu count": func() string {
+			return jobRequestHash(&pb.RunJobRequest{Command: "python3", Args: req.Args, Uploads: req.Uploads, GpuCount: 1}, "", env, nil)
+		},
+	}
+	for name, hash := range changes {
+		if hash() == base {
+			t.Errorf("hash did not change with %s", name)
+		}
+	}
+}
+
+func TestJobDeduplicator(t *testing.T) {
+	d := newJobDeduplicator()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	activeJobs := map[string]bool{}
+	active := func(jobID string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return activeJobs[jobID]
+	}
+
+	existing, release, err := d.acquire(ctx, "h", active)
+	if err != nil || existing != "" || release == nil {
+		t.Fatalf("first acquire = %q, %v", existing, err)
+	}
+
+	// An identical request waits while the first job is starting
+	waited := make(chan string)
+	go func() {
+		existing, _, _ := d.acquire(ctx, "h", active)
+		waited <- existing
+	}()
+	select {
+	case <-waited:
+		t.Fatal("second acquire did not wait for the first job to start")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	mu.Lock()
+	activeJobs["job-1"] = true
+	mu.Unlock()
+	release("job-1")
+	if got := <-waited; got != "job-1" {
+		t.Fatalf("second acquire = %q, want job-1", got)
+	}
+
+	// Once the job finished, the next request starts a new one
+	mu.Lock()
+	activeJobs["job-1"] = false
+	mu.Unlock()
+	existing, release, err = d.acquire(ctx, "h", active)
+	if err != nil || existing != "" {
+		t.Fatalf("acquire after finish = %q, %v", existing, err)
+	}
+
+	// A failed start releases the hash
+	release("")
+	if _, release, _ = d.acquire(ctx, "h", active); release == nil {
+		t.Fatal("expected hash to be free after a failed start")
+	}
+
+	// Waiting respects the request context
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := d.acquire(cancelled, "h", active); err == nil {
+		t.Error("expected context error while an identical job is starting")
+	}
+}
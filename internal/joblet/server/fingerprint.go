@@ -0,0 +1,48 @@
+package server
+
+import (
+	"github.com/ehsaniara/joblet/internal/joblet/anomaly"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+)
+
+// fingerprintJSON is a job's environment fingerprint as sent in response
+// headers, with its digest so clients can compare runs at a glance
+type fingerprintJSON struct {
+	*domain.JobFingerprint
+	Digest string `json:"digest"`
+}
+
+func newFingerprintJSON(fp *domain.JobFingerprint) fingerprintJSON {
+	return fingerprintJSON{JobFingerprint: fp, Digest: fp.Digest()}
+}
+
+// workflowFingerprints returns the fingerprints of a workflow's started jobs
+// by job name
+func (s *WorkflowServiceServer) workflowFingerprints(jobs map[string]*workflow.JobDependency) map[string]fingerprintJSON {
+	fingerprints := make(map[string]fingerprintJSON)
+	for _, jobDep := range jobs {
+		if jobDep.JobID == jobDep.InternalName {
+			continue // Not started yet
+		}
+		if job, exists := s.jobStore.Job(jobDep.JobID); exists && job.Fingerprint != nil {
+			fingerprints[jobDep.InternalName] = newFingerprintJSON(job.Fingerprint)
+		}
+	}
+	return fingerprints
+}
+
+// workflowAnomalies returns the duration anomalies flagged for a workflow's
+// started jobs by job name
+func (s *WorkflowServiceServer) workflowAnomalies(jobs map[string]*workflow.JobDependency) map[string]anomaly.Anomaly {
+	anomalies := make(map[string]anomaly.Anomaly)
+	for _, jobDep := range jobs {
+		if jobDep.JobID == jobDep.InternalName {
+			continue // Not started yet
+		}
+		if a, flagged := s.anomalies.Anomaly(jobDep.JobID); flagged {
+			anomalies[jobDep.InternalName] = a
+		}
+	}
+	return anomalies
+}
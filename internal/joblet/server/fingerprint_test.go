@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+)
+
+func TestWorkflowFingerprints(t *testing.T) {
+	fp := &domain.JobFingerprint{Runtime: "python-3.11", RuntimeVersion: "3.11.9", KernelVersion: "6.8.0", JobletVersion: "v4.3.3"}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobCalls(func(id string) (*domain.Job, bool) {
+		switch id {
+		case "job-1":
+			return &domain.Job{Uuid: id, Fingerprint: fp}, true
+		case "job-2":
+			return &domain.Job{Uuid: id}, true // Started before fingerprints were recorded
+		}
+		return nil, false
+	})
+	s := &WorkflowServiceServer{jobStore: store}
+
+	got := s.workflowFingerprints(map[string]*workflow.JobDependency{
+		"train":  {JobID: "job-1", InternalName: "train"},
+		"report": {JobID: "job-2", InternalName: "report"},
+		"deploy": {JobID: "deploy", InternalName: "deploy"},
+	})
+	if len(got) != 1 || got["train"].JobFingerprint != fp || got["train"].Digest != fp.Digest() {
+		t.Fatalf("workflowFingerprints() = %+v", got)
+	}
+
+	data, err := json.Marshal(got["train"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["runtime"] != "python-3.11" || fields["kernelVersion"] != "6.8.0" || fields["digest"] != fp.Digest() {
+		t.Errorf("fingerprint JSON = %s", data)
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/gitsource"
+	gitsourcepb "github.com/ehsaniara/joblet/internal/proto/gen/gitsource"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GitSourceServiceServer implements the gRPC service running specs from Git
+// repositories. Runs are started through the workflow service, like 'rnx
+// workflow run'.
+type GitSourceServiceServer struct {
+	gitsourcepb.UnimplementedGitSourceServiceServer
+	auth      auth2.GRPCAuthorization
+	fetcher   *gitsource.Fetcher
+	workflows *WorkflowServiceServer
+	logger    *logger.Logger
+}
+
+// NewGitSourceServiceServer creates a new git source service server; a nil
+// fetcher rejects every run
+func NewGitSourceServiceServer(auth auth2.GRPCAuthorization, fetcher *gitsource.Fetcher, workflows *WorkflowServiceServer) *GitSourceServiceServer {
+	return &GitSourceServiceServer{
+		auth:      auth,
+		fetcher:   fetcher,
+		workflows: workflows,
+		logger:    logger.WithField("component", "git-sources"),
+	}
+}
+
+// RunFromGit fetches a ref and starts the workflow or job spec at a path of
+// it, with the files its jobs upload
+func (s *GitSourceServiceServer) RunFromGit(ctx context.Context, req *gitsourcepb.RunFromGitRequest) (*gitsourcepb.RunFromGitResponse, error) {
+	log := s.logger.WithFields("operation", "RunFromGit", "repository", req.Repository, "ref", req.Ref, "path", req.Path)
+	if err := s.auth.Authorized(ctx, auth2.RunJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if s.fetcher == nil {
+		return nil, status.Error(codes.FailedPrecondition, "running from Git repositories is disabled on this server (git_sources.enabled)")
+	}
+	if req.Repository == "" || req.Path == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository and path are required")
+	}
+	// The spec was never signed by the client, so servers requiring
+	// signatures only run what clients submit themselves
+	if s.workflows.signatures.required {
+		return nil, status.Error(codes.FailedPrecondition, "this server only accepts signed workflows, which runs from a repository are not")
+	}
+
+	checkout, err := s.fetcher.Fetch(ctx, req.Repository, req.Ref)
+	if err != nil {
+		log.Warn("fetch failed", "error", err)
+		if errors.Is(err, gitsource.ErrNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	defer func() {
+		if err := checkout.Close(); err != nil {
+			log.Warn("failed to remove checkout", "dir", checkout.Dir, "error", err)
+		}
+	}()
+
+	spec, err := gitsource.LoadSpec(checkout.Dir, req.Path, s.fetcher.MaxBytes())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s@%s: %v", req.Repository, checkout.Commit, err)
+	}
+
+	workflowUuid, err := s.workflows.StartWorkflowOrchestrationWithContent(ctx, spec.YamlContent, spec.Files, nil)
+	if err != nil {
+		log.Error("failed to start workflow orchestration", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to start workflow orchestration: %v", err)
+	}
+
+	log.Info("spec from repository started", "commit", checkout.Commit, "kind", spec.Kind,
+		"files", len(spec.Files), "workflowUuid", workflowUuid, "by", auth2.ClientIdentity(ctx))
+	return &gitsourcepb.RunFromGitResponse{
+		WorkflowUuid: workflowUuid,
+		Commit:       checkout.Commit,
+		Kind:         spec.Kind,
+		Jobs:         spec.Jobs,
+		Files:        int32(len(spec.Files)),
+	}, nil
+}
@@ -1,17 +1,56 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
 	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/anomaly"
 	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/capacity"
+	"github.com/ehsaniara/joblet/internal/joblet/clocksync"
+	"github.com/ehsaniara/joblet/internal/joblet/coordination"
 	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
 	"github.com/ehsaniara/joblet/internal/joblet/core/volume"
+	"github.com/ehsaniara/joblet/internal/joblet/digest"
+	"github.com/ehsaniara/joblet/internal/joblet/gitsource"
+	"github.com/ehsaniara/joblet/internal/joblet/identity"
+	"github.com/ehsaniara/joblet/internal/joblet/introspection"
+	"github.com/ehsaniara/joblet/internal/joblet/jobfs"
+	"github.com/ehsaniara/joblet/internal/joblet/maintenance"
 	"github.com/ehsaniara/joblet/internal/joblet/monitoring"
+	"github.com/ehsaniara/joblet/internal/joblet/orphans"
+	"github.com/ehsaniara/joblet/internal/joblet/ratelimit"
+	"github.com/ehsaniara/joblet/internal/joblet/retention"
 	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime/channels"
+	"github.com/ehsaniara/joblet/internal/joblet/state"
+	"github.com/ehsaniara/joblet/internal/joblet/uploadsync"
 	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/checkpoint"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/registry"
+	artifactspb "github.com/ehsaniara/joblet/internal/proto/gen/artifacts"
+	backuppb "github.com/ehsaniara/joblet/internal/proto/gen/backup"
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	eventspb "github.com/ehsaniara/joblet/internal/proto/gen/events"
+	gitsourcepb "github.com/ehsaniara/joblet/internal/proto/gen/gitsource"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
+	jobenvpb "github.com/ehsaniara/joblet/internal/proto/gen/jobenv"
+	jobfspb "github.com/ehsaniara/joblet/internal/proto/gen/jobfs"
+	jobnetpb "github.com/ehsaniara/joblet/internal/proto/gen/jobnet"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	lintpb "github.com/ehsaniara/joblet/internal/proto/gen/lint"
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	registrypb "github.com/ehsaniara/joblet/internal/proto/gen/registry"
+	reportspb "github.com/ehsaniara/joblet/internal/proto/gen/reports"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	uploadspb "github.com/ehsaniara/joblet/internal/proto/gen/uploads"
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
 	"github.com/ehsaniara/joblet/pkg/client"
 	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
@@ -48,11 +87,39 @@ func StartGRPCServer(jobStore adapters.JobStorer, metricsStore *adapters.Metrics
 			Timeout: cfg.GRPC.KeepAliveTimeout,
 		}),
 		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
-			MinTime:             cfg.GRPC.KeepAliveTime / 2, // Allow keepalive pings every KeepAliveTime/2
+			MinTime:             keepAliveMinTime(cfg.GRPC), // Most frequent keepalive pings allowed
 			PermitWithoutStream: true,                       // Allow keepalive pings even when no streams are active
 		}),
 	}
 
+	// Authenticate token-carrying clients (Kerberos, LDAP, ...) first, so
+	// the rate limiter and handlers see their principal
+	if provider := identity.NewProvider(cfg.Identity, serverLogger); provider != nil {
+		grpcOptions = append(grpcOptions,
+			grpc.ChainUnaryInterceptor(provider.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(provider.StreamInterceptor()),
+		)
+		serverLogger.Info("identity provider enabled",
+			"command", cfg.Identity.Command,
+			"mappings", len(cfg.Identity.Mappings),
+			"cacheTTL", cfg.Identity.CacheTTL)
+	}
+
+	// Throttle per client certificate identity before any handler runs
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter = ratelimit.New(cfg.RateLimit)
+		grpcOptions = append(grpcOptions,
+			grpc.ChainUnaryInterceptor(limiter.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(limiter.StreamInterceptor()),
+		)
+		serverLogger.Info("rate limiting enabled",
+			"requestsPerSecond", cfg.RateLimit.RequestsPerSecond,
+			"runJobPerSecond", cfg.RateLimit.RunJobPerSecond,
+			"maxStreams", cfg.RateLimit.MaxStreams,
+			"clientOverrides", len(cfg.RateLimit.Clients))
+	}
+
 	grpcServer := grpc.NewServer(grpcOptions...)
 
 	auth := auth2.NewGRPCAuthorization()
@@ -76,24 +143,201 @@ func StartGRPCServer(jobStore adapters.JobStorer, metricsStore *adapters.Metrics
 
 	// Create workflow manager and unified job service with validation
 	workflowManager := workflow.NewWorkflowManager()
-	jobService := NewWorkflowServiceServer(auth, jobStore, metricsStore, joblet, workflowManager, volumeManager, runtimeResolver, persistClient)
+	signatures, err := newSignatureVerifier(cfg.Signing)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing configuration: %w", err)
+	}
+
+	// Cache of workflow files so repeated runs only send the changed blocks
+	var uploadCache *uploadsync.Cache
+	if cfg.UploadSync.Enabled {
+		uploadCache, err = uploadsync.NewCache(cfg.UploadSync.Dir, cfg.UploadSync.MaxSizeMB<<20)
+		if err != nil {
+			serverLogger.Warn("upload cache unavailable, workflow files will be sent whole", "dir", cfg.UploadSync.Dir, "error", err)
+			uploadCache = nil
+		} else {
+			uploadspb.RegisterUploadSyncServiceServer(grpcServer, NewUploadSyncServiceServer(auth, uploadCache))
+		}
+	}
+
+	// Stable and canary channels of runtime names; without their file jobs
+	// only resolve through the configured aliases
+	channelStore, err := channels.Open(cfg.Runtime.Channels.File)
+	if err != nil {
+		serverLogger.Warn("runtime channels unavailable", "file", cfg.Runtime.Channels.File, "error", err)
+		channelStore = nil
+	}
+	resolveRuntime := func(tenant, spec string) (string, string) {
+		return channelStore.Resolve(cfg, tenant, spec)
+	}
+
+	jobService := NewWorkflowServiceServer(auth, jobStore, metricsStore, joblet, workflowManager, volumeManager, runtimeResolver, persistClient, signatures, cfg.TenantOf, resolveRuntime, uploadCache, cfg.GRPC.LogStreamHeartbeat, cfg.GRPC.LogStreamSendTimeout)
+	jobService.access = cfg
+	jobService.detection = newRuntimeDetection(cfg.Runtime.Detection, runtimeResolver, func(name string) bool {
+		_, exists := volumeManager.GetVolume(name)
+		return exists
+	})
 	pb.RegisterJobServiceServer(grpcServer, jobService)
 
+	// Drive workflows by job events; without them they poll for changes
+	if err := jobService.WatchJobEvents(context.Background()); err != nil {
+		serverLogger.Warn("job events unavailable, workflows poll for job changes", "error", err)
+	}
+
 	// Create and register network service
 	networkService := NewNetworkServiceServer(auth, networkStore)
 	pb.RegisterNetworkServiceServer(grpcServer, networkService)
 
 	// Create and register volume service
 	volumeService := NewVolumeServiceServer(auth, volumeManager)
+	volumeService.config = cfg
 	pb.RegisterVolumeServiceServer(grpcServer, volumeService)
 
+	// Create and register artifact service for downloading volume files
+	artifactService := NewArtifactServiceServer(auth, volumeManager)
+	artifactService.config = cfg
+	artifactspb.RegisterArtifactServiceServer(grpcServer, artifactService)
+
 	// Create and register monitoring service
 	monitoringGrpcService := NewMonitoringServiceServer(monitoringService, cfg)
+	monitoringGrpcService.limiter = limiter // Throttling counters reported in monitor status
 	pb.RegisterMonitoringServiceServer(grpcServer, monitoringGrpcService)
 
+	// Check the clock scheduled jobs start by, reported in monitor status
+	// and warned about when a job is scheduled within its uncertainty
+	clock := clocksync.NewChecker(cfg.Clock)
+	go clock.Run(context.Background())
+	jobService.clock = clock
+	monitoringGrpcService.clock = clock
+
 	// Create and register runtime service with direct installation capabilities (no job system)
 	runtimeService := NewRuntimeServiceServer(auth, cfg.Runtime.BasePath, platform, cfg)
+	runtimeService.channels = channelStore
 	pb.RegisterRuntimeServiceServer(grpcServer, runtimeService)
+	runtimeInfoService := NewRuntimeInfoServiceServer(auth, runtimeResolver, cfg)
+	runtimeInfoService.channels = channelStore
+	runtimespb.RegisterRuntimeInfoServiceServer(grpcServer, runtimeInfoService)
+	if channelStore != nil {
+		runtimespb.RegisterRuntimeChannelServiceServer(grpcServer, NewRuntimeChannelServiceServer(auth, channelStore, runtimeResolver, jobStore, cfg))
+	}
+
+	// Create and register capacity service; the GPU count comes from the
+	// platform joblet when it exposes one
+	gpuCounter, _ := joblet.(capacity.GPUCounter)
+	calculator := capacity.NewCalculator(cfg, jobStore, volumeManager, monitoringService, gpuCounter)
+	capacitypb.RegisterCapacityServiceServer(grpcServer, NewCapacityServiceServer(auth, calculator))
+
+	// Create and register the service reading the recorded host metrics; the
+	// counts of orphaned job resources come from the platform joblet's cleanup
+	orphanLister, _ := joblet.(orphans.Lister)
+	nodemetricspb.RegisterNodeMetricsServiceServer(grpcServer, NewNodeMetricsServiceServer(auth, cfg.Monitoring.History, persistClient, orphanLister))
+
+	// Register the node with the coordination endpoint and serve its node
+	// listing; nodes expire there after their TTL once heartbeats stop
+	if cfg.Coordination.Enabled {
+		if registrar, err := coordination.New(cfg); err != nil {
+			serverLogger.Warn("node coordination unavailable", "backend", cfg.Coordination.Backend, "error", err)
+		} else {
+			nodespb.RegisterNodeRegistryServiceServer(grpcServer, NewNodesServiceServer(auth, registrar))
+			go coordination.NewHeartbeater(cfg, registrar, calculator.Snapshot).Run(context.Background())
+		}
+	}
+
+	// Create and register the service keeping and browsing the filesystem of
+	// failed jobs
+	jobfspb.RegisterJobFilesystemServiceServer(grpcServer, NewJobFSServiceServer(auth, jobStore,
+		jobfs.NewStore(cfg.Filesystem.BaseDir, cfg.Filesystem.FreezeRetention)))
+
+	// Create and register the service streaming job status and progress
+	eventspb.RegisterJobEventServiceServer(grpcServer, NewJobEventServiceServer(auth, jobStore))
+
+	// Create and register the service streaming the network counters of jobs
+	jobnetpb.RegisterJobNetworkServiceServer(grpcServer, NewJobNetworkServiceServer(auth, jobStore, metricsStore))
+
+	// Create and register the service exporting job environments
+	jobenvpb.RegisterJobEnvironmentServiceServer(grpcServer, NewJobEnvServiceServer(auth, jobStore, runtimeResolver, cfg, platform))
+
+	// Create and register log download service
+	logspb.RegisterLogServiceServer(grpcServer, NewLogServiceServer(auth, jobStore, persistClient))
+
+	// Create the finished job retention reaper and register the bulk job
+	// service, which reports its policy
+	reaper := retention.NewReaper(cfg.Retention, jobStore, persistClient)
+	go reaper.Run(context.Background())
+
+	// Flag runs far above the usual duration of their job name
+	if cfg.DurationAnomaly.Enabled {
+		jobService.anomalies = anomaly.NewDetector(cfg.DurationAnomaly, jobStore, persistClient, cfg.Server.NodeId)
+		go jobService.anomalies.Run(context.Background())
+	}
+
+	// Notify ahead of the maintenance windows during which no job starts
+	if calendar, err := maintenance.NewCalendar(cfg.Maintenance); err != nil {
+		serverLogger.Warn("maintenance notifications disabled", "error", err)
+	} else if calendar != nil {
+		go maintenance.NewNotifier(calendar, cfg.Maintenance, cfg.Server.NodeId).Run(context.Background())
+	}
+
+	// Send the scheduled digests of labeled workflow runs
+	digestSource := digest.Source{
+		Workflows:    workflowManager.ListWorkflows,
+		WorkflowUUID: jobService.getFullUuidForWorkflowID,
+		Jobs:         jobStore.Job,
+		Persist:      persistClient,
+	}
+	if sender, err := digest.NewSender(cfg.Digest, digestSource, cfg.Server.NodeId); err != nil {
+		serverLogger.Warn("workflow digests disabled", "error", err)
+	} else if sender != nil {
+		go sender.Run(context.Background())
+	}
+	jobspb.RegisterBulkJobServiceServer(grpcServer, NewBulkJobServiceServer(auth, jobStore, joblet, reaper, persistClient))
+
+	// Create and register the service running specs from Git repositories
+	gitsourcepb.RegisterGitSourceServiceServer(grpcServer, NewGitSourceServiceServer(auth,
+		gitsource.NewFetcher(cfg.GitSources, serverLogger), jobService))
+
+	// Create and register the service rendering workflow run reports
+	reportspb.RegisterWorkflowReportServiceServer(grpcServer, NewReportServiceServer(auth, jobService))
+
+	// Create and register the service canceling parts of running workflows
+	workflowspb.RegisterWorkflowControlServiceServer(grpcServer, NewWorkflowControlServiceServer(auth, jobService))
+
+	// Create and register the service resolving short job and workflow UUIDs
+	idspb.RegisterIdResolverServiceServer(grpcServer, NewIdResolverServiceServer(auth, jobService))
+
+	// Create and register the service exporting and importing job and
+	// workflow records
+	backuppb.RegisterBackupServiceServer(grpcServer, NewBackupServiceServer(auth, jobService))
+
+	// Create and register the service linting job and workflow specs
+	lintpb.RegisterSpecLintServiceServer(grpcServer, NewLintServiceServer(auth, jobService, networkStore, cfg))
+
+	// Save workflows in the state service as they run and resume those saved
+	// before a restart; disabled, workflows are kept in memory only
+	if cfg.WorkflowState.Enabled {
+		stateClient := state.NewPooledClient(state.SocketPath, 2, serverLogger.WithField("component", "workflow-checkpoint"))
+		jobService.checkpoints = checkpoint.New(stateClient, serverLogger)
+		jobService.checkpointRetention = cfg.WorkflowState.Retention
+		if err := jobService.ResumeWorkflows(); err != nil {
+			serverLogger.Warn("failed to resume saved workflows", "error", err)
+		}
+	}
+
+	// Create and register workflow registry service; without its directory
+	// the server runs without it
+	if workflowRegistry, err := registry.New(cfg.WorkflowRegistry.Dir, cfg.WorkflowRegistry.MaxVersions); err != nil {
+		serverLogger.Warn("workflow registry unavailable", "dir", cfg.WorkflowRegistry.Dir, "error", err)
+	} else {
+		registryService := NewRegistryServiceServer(auth, workflowRegistry, jobService)
+		registrypb.RegisterWorkflowRegistryServiceServer(grpcServer, registryService)
+		go registryService.RunSchedules(context.Background())
+	}
+
+	// Health and reflection go last, so every service above is reported
+	if cfg.GRPC.HealthService || cfg.GRPC.Reflection {
+		introspection.Register(grpcServer, cfg.GRPC.HealthService, cfg.GRPC.Reflection)
+		serverLogger.Info("gRPC introspection enabled", "health", cfg.GRPC.HealthService, "reflection", cfg.GRPC.Reflection)
+	}
 
 	lis, err := net.Listen("tcp", serverAddress)
 	if err != nil {
@@ -115,3 +359,12 @@ func StartGRPCServer(jobStore adapters.JobStorer, metricsStore *adapters.Metrics
 
 	return grpcServer, nil
 }
+
+// keepAliveMinTime is the most frequent client keepalive ping the server
+// accepts, half the server's own keepalive time unless configured
+func keepAliveMinTime(cfg config.GRPCConfig) time.Duration {
+	if cfg.KeepAliveMinTime > 0 {
+		return cfg.KeepAliveMinTime
+	}
+	return cfg.KeepAliveTime / 2
+}
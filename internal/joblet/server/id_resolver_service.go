@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// defaultIdMatches caps the matches of a ResolveId request without a limit
+const defaultIdMatches = 20
+
+// IdResolverServiceServer implements the gRPC service listing the jobs and
+// workflows a short UUID may mean, from the stores of the workflow service
+type IdResolverServiceServer struct {
+	idspb.UnimplementedIdResolverServiceServer
+	auth      auth2.GRPCAuthorization
+	workflows *WorkflowServiceServer
+	logger    *logger.Logger
+}
+
+// NewIdResolverServiceServer creates a new ID resolver service server
+func NewIdResolverServiceServer(auth auth2.GRPCAuthorization, workflows *WorkflowServiceServer) *IdResolverServiceServer {
+	return &IdResolverServiceServer{
+		auth:      auth,
+		workflows: workflows,
+		logger:    logger.WithField("component", "id-resolver"),
+	}
+}
+
+// ResolveId lists the jobs, then the workflows, whose UUID starts with the
+// prefix. Unlike the prefix lookups of the other RPCs it never fails on an
+// ambiguous prefix; the total tells the caller how many there are.
+func (s *IdResolverServiceServer) ResolveId(ctx context.Context, req *idspb.ResolveIdRequest) (*idspb.ResolveIdResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListJobsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ResolveId", "error", err)
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultIdMatches
+	}
+	resp := &idspb.ResolveIdResponse{}
+	w := s.workflows
+
+	if req.Type != idspb.IdType_ID_TYPE_WORKFLOW {
+		uuids, count := w.jobStore.MatchJobUUIDs(req.Prefix, limit)
+		resp.Total += int32(count)
+		for _, uuid := range uuids {
+			job, exists := w.jobStore.Job(uuid)
+			if !exists {
+				continue
+			}
+			resp.Matches = append(resp.Matches, &idspb.IdMatch{
+				Type:      idspb.IdType_ID_TYPE_JOB,
+				Uuid:      uuid,
+				Name:      job.Name,
+				Status:    string(job.Status),
+				CreatedAt: job.StartTime.UnixNano(),
+			})
+		}
+	}
+
+	if req.Type != idspb.IdType_ID_TYPE_JOB {
+		_, _, count := w.workflowUuids.Resolve(req.Prefix)
+		resp.Total += int32(count)
+		var uuids []string
+		if remaining := limit - len(resp.Matches); remaining > 0 {
+			uuids = w.workflowUuids.Match(req.Prefix, remaining)
+		}
+		for _, uuid := range uuids {
+			workflowID, exists := w.workflowUuids.Get(uuid)
+			if !exists {
+				continue
+			}
+			state, err := w.workflowManager.GetWorkflowStatus(workflowID)
+			if err != nil {
+				continue
+			}
+			resp.Matches = append(resp.Matches, &idspb.IdMatch{
+				Type:      idspb.IdType_ID_TYPE_WORKFLOW,
+				Uuid:      uuid,
+				Name:      state.Workflow,
+				Status:    string(state.Status),
+				CreatedAt: state.CreatedAt.UnixNano(),
+			})
+		}
+	}
+
+	return resp, nil
+}
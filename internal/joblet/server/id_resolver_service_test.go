@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResolveId(t *testing.T) {
+	created := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	jobs := map[string]*domain.Job{
+		"f47ac10b-58cc-4372-a567-0e02b2c3d479": {Name: "train", Status: domain.StatusRunning, StartTime: created},
+		"f47bd20c-1111-4372-a567-0e02b2c3d479": {Name: "eval", Status: domain.StatusCompleted, StartTime: created},
+	}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.MatchJobUUIDsStub = func(prefix string, limit int) ([]string, int) {
+		ix := prefixindex.New[struct{}]()
+		for uuid := range jobs {
+			ix.Put(uuid, struct{}{})
+		}
+		_, _, count := ix.Resolve(prefix)
+		return ix.Match(prefix, limit), count
+	}
+	store.JobStub = func(uuid string) (*domain.Job, bool) {
+		job, exists := jobs[uuid]
+		return job, exists
+	}
+
+	manager := workflow.NewWorkflowManager()
+	workflowID, err := manager.CreateWorkflow("pipeline.yaml", map[string]*workflow.JobDependency{
+		"fetch": {JobID: "fetch", InternalName: "fetch", Status: domain.StatusPending},
+	}, []string{"fetch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflows := &WorkflowServiceServer{
+		jobStore:         store,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+	}
+	workflows.storeWorkflowMapping("f47c0000-4f5a-6789-abcd-ef0123456789", workflowID)
+	s := NewIdResolverServiceServer(&authfakes.FakeGRPCAuthorization{}, workflows)
+	ctx := context.Background()
+
+	resp, err := s.ResolveId(ctx, &idspb.ResolveIdRequest{Prefix: "f47"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 3 || len(resp.Matches) != 3 {
+		t.Fatalf("ResolveId(f47) = %v", resp)
+	}
+	if m := resp.Matches[0]; m.Type != idspb.IdType_ID_TYPE_JOB || m.Name != "train" || m.Status != "RUNNING" ||
+		m.CreatedAt != created.UnixNano() {
+		t.Errorf("first match = %v", m)
+	}
+	if m := resp.Matches[2]; m.Type != idspb.IdType_ID_TYPE_WORKFLOW || m.Name != "pipeline.yaml" || m.Status != "PENDING" {
+		t.Errorf("workflow match = %v", m)
+	}
+
+	// The limit caps the matches but not the total
+	resp, _ = s.ResolveId(ctx, &idspb.ResolveIdRequest{Prefix: "f47", Limit: 1})
+	if resp.Total != 3 || len(resp.Matches) != 1 {
+		t.Errorf("ResolveId(f47, limit 1) = %v", resp)
+	}
+	resp, _ = s.ResolveId(ctx, &idspb.ResolveIdRequest{Prefix: "f47", Type: idspb.IdType_ID_TYPE_WORKFLOW})
+	if resp.Total != 1 || len(resp.Matches) != 1 || resp.Matches[0].Uuid != "f47c0000-4f5a-6789-abcd-ef0123456789" {
+		t.Errorf("ResolveId(f47, workflows) = %v", resp)
+	}
+
+	// Lookups elsewhere tell an ambiguous prefix from a missing one
+	if err := workflows.jobLookupError("f47"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("jobLookupError(f47) = %v, want InvalidArgument", err)
+	}
+	if err := workflows.jobLookupError("0a1b"); status.Code(err) != codes.NotFound {
+		t.Errorf("jobLookupError(0a1b) = %v, want NotFound", err)
+	}
+	workflows.storeWorkflowMapping("f47c1111-4f5a-6789-abcd-ef0123456789", workflowID+1)
+	if err := workflows.workflowLookupError("f47c"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("workflowLookupError(f47c) = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/core/environment"
+	"github.com/ehsaniara/joblet/internal/joblet/core/filesystem"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/envexport"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	jobenvpb "github.com/ehsaniara/joblet/internal/proto/gen/jobenv"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JobEnvServiceServer implements the gRPC service exporting the environment
+// of a job as a Dockerfile or an OCI bundle
+type JobEnvServiceServer struct {
+	jobenvpb.UnimplementedJobEnvironmentServiceServer
+	auth          auth2.GRPCAuthorization
+	jobStore      adapters.JobStorer
+	resolver      *runtime.Resolver
+	config        *config.Config
+	baseImage     string // Image of the node's distro
+	chrootProfile string // Distro profile of the node (empty = none)
+	logger        *logger.Logger
+}
+
+// NewJobEnvServiceServer creates a new job environment service server
+func NewJobEnvServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, resolver *runtime.Resolver,
+	cfg *config.Config, p platform.Platform) *JobEnvServiceServer {
+	return &JobEnvServiceServer{
+		auth:          auth,
+		jobStore:      jobStore,
+		resolver:      resolver,
+		config:        cfg,
+		baseImage:     envexport.BaseImage(filesystem.ReadOSRelease(p)),
+		chrootProfile: filesystem.ResolveChrootProfile(cfg, p),
+		logger:        logger.WithField("component", "job-env"),
+	}
+}
+
+// ExportJobEnvironment renders the environment of a job in the requested
+// format, with notes on what the export leaves out
+func (s *JobEnvServiceServer) ExportJobEnvironment(ctx context.Context, req *jobenvpb.ExportJobEnvironmentRequest) (*jobenvpb.ExportJobEnvironmentResponse, error) {
+	log := s.logger.WithFields("operation", "ExportJobEnvironment", "jobId", req.JobUuid)
+	if err := s.auth.Authorized(ctx, auth2.GetJobStatusOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+
+	jobUUID := req.JobUuid
+	if resolved, err := s.jobStore.ResolveJobUUID(jobUUID); err == nil {
+		jobUUID = resolved
+	}
+	job, exists := s.jobStore.Job(jobUUID)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "job %s not found", req.JobUuid)
+	}
+	if job.IsRuntimeBuild() {
+		return nil, status.Errorf(codes.FailedPrecondition, "job %s builds a runtime on the host filesystem and has no environment to export", jobUUID)
+	}
+
+	env, notes, err := s.jobEnvironment(job)
+	if err != nil {
+		return nil, err
+	}
+	files, exportNotes, err := envexport.Export(env, req.Format)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	format := req.Format
+	if format == "" {
+		format = envexport.FormatDockerfile
+	}
+	resp := &jobenvpb.ExportJobEnvironmentResponse{JobUuid: jobUUID, Format: format, Notes: append(notes, exportNotes...)}
+	for _, f := range files {
+		resp.Files = append(resp.Files, &jobenvpb.ExportedFile{Path: f.Path, Content: f.Content, Mode: f.Mode})
+	}
+	log.Debug("job environment exported", "format", format, "files", len(resp.Files))
+	return resp, nil
+}
+
+// jobEnvironment collects what the job saw: the node's distro and host
+// directories, its runtime, volumes, environment, command and limits
+func (s *JobEnvServiceServer) jobEnvironment(job *domain.Job) (*envexport.Environment, []string, error) {
+	env := &envexport.Environment{
+		JobUUID:      job.Uuid,
+		BaseImage:    s.baseImage,
+		Command:      job.Command,
+		Args:         job.Args,
+		WorkDir:      job.WorkingDirectory,
+		Network:      job.Network,
+		MemoryBytes:  job.Limits.Memory.Bytes(),
+		CPUPercent:   job.Limits.CPU.Value(),
+		ShmSizeBytes: job.ShmSizeBytes,
+		TmpSizeBytes: job.TmpSizeBytes,
+	}
+	if env.WorkDir == "" {
+		env.WorkDir = s.config.Filesystem.WorkspaceDir
+	}
+	if !job.Limits.CPUCores.IsEmpty() {
+		env.CPUCores = job.Limits.CPUCores.String()
+	}
+
+	var notes []string
+	var rt *runtime.RuntimeConfig
+	var runtimeEnv []string
+	if job.Runtime != "" {
+		runtimeDir, err := s.resolver.FindRuntimeDirectory(job.Runtime)
+		if err == nil {
+			rt, err = s.resolver.ResolveRuntime(job.Runtime)
+		}
+		if err != nil {
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "runtime %s of job %s is no longer installed: %v", job.Runtime, job.Uuid, err)
+		}
+		env.Runtime, env.RuntimeDir = job.Runtime, runtimeDir
+		for _, m := range rt.Mounts {
+			env.RuntimeMounts = append(env.RuntimeMounts, envexport.Mount{Source: m.Source, Target: m.Target, ReadOnly: m.ReadOnly})
+		}
+		if len(env.RuntimeMounts) == 0 {
+			// Jobs get the whole runtime directory as their root then
+			env.RuntimeMounts = []envexport.Mount{{Source: ".", Target: "/", ReadOnly: true}}
+		}
+		runtimeEnv = sortedEnv(rt.Environment)
+	}
+
+	for _, dir := range filesystem.ChrootMounts(s.config.Filesystem.AllowedMounts, filesystem.RuntimeChrootProfile(s.chrootProfile, rt)) {
+		if _, err := os.Stat(dir); err == nil {
+			env.HostMounts = append(env.HostMounts, filepath.Clean(dir))
+		}
+	}
+	for _, name := range job.Volumes {
+		env.Volumes = append(env.Volumes, envexport.Mount{
+			Source: filepath.Join(s.config.Volumes.BasePath, name, "data"),
+			Target: filepath.Join("/volumes", name),
+		})
+	}
+
+	// The server's own environment is left out, it is not the job's to reproduce
+	env.Env = environment.Resolve(runtimeEnv, sortedEnv(job.Environment))
+	for key := range job.SecretEnvironment {
+		env.SecretKeys = append(env.SecretKeys, key)
+	}
+	sort.Strings(env.SecretKeys)
+
+	for _, upload := range job.Uploads {
+		u := envexport.Upload{Path: upload.Path, Mode: upload.Mode, IsDirectory: upload.IsDirectory}
+		if int64(len(upload.Content)) == upload.Size {
+			u.Content = append([]byte{}, upload.Content...)
+		}
+		env.Uploads = append(env.Uploads, u)
+	}
+	if len(job.Volumes) > 0 {
+		notes = append(notes, "volumes are mounted from their data on the node, copy them to reproduce elsewhere")
+	}
+	return env, notes, nil
+}
+
+// sortedEnv turns an environment map into KEY=VALUE entries sorted by key
+func sortedEnv(vars map[string]string) []string {
+	env := make([]string, 0, len(vars))
+	for key, value := range vars {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	jobenvpb "github.com/ehsaniara/joblet/internal/proto/gen/jobenv"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// jobEnvClient serves the job environment service over an in-memory connection
+func jobEnvClient(t *testing.T, s *JobEnvServiceServer) jobenvpb.JobEnvironmentServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	jobenvpb.RegisterJobEnvironmentServiceServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return jobenvpb.NewJobEnvironmentServiceClient(conn)
+}
+
+func TestJobEnvService(t *testing.T) {
+	runtimes := t.TempDir()
+	runtimeDir := filepath.Join(runtimes, "python-3.11")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	yml := `name: python-3.11
+version: "3.11"
+mounts:
+  - source: isolated/usr/bin
+    target: /usr/local/bin
+    readonly: true
+environment:
+  PATH_PREPEND: /usr/local/bin
+  PYTHONUNBUFFERED: "1"
+`
+	if err := os.WriteFile(filepath.Join(runtimeDir, "runtime.yml"), []byte(yml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Filesystem.AllowedMounts = []string{"/bin", "/does/not/exist"}
+	cfg.Filesystem.ChrootProfile = config.ChrootProfileNone
+	cfg.Filesystem.WorkspaceDir = "/work"
+	cfg.Volumes.BasePath = "/opt/joblet/volumes"
+
+	const jobID, buildID = "f47ac10b-58cc-4372-a567-0e02b2c3d479", "66666666-7777-8888-9999-000000000000"
+	jobs := map[string]*domain.Job{
+		jobID: {
+			Uuid: jobID, Command: "python3", Args: []string{"train.py"}, Runtime: "python-3.11",
+			Volumes:           []string{"datasets"},
+			Environment:       map[string]string{"EPOCHS": "10"},
+			SecretEnvironment: map[string]string{"TOKEN": "s3cret"},
+			Uploads:           []domain.FileUpload{{Path: "train.py", Content: []byte("print(1)"), Size: 8, Mode: 0644}},
+		},
+		buildID: {Uuid: buildID, Command: "build", Type: domain.JobTypeRuntimeBuild},
+	}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(id string) (*domain.Job, bool) {
+		job, exists := jobs[id]
+		return job, exists
+	}
+	store.ResolveJobUUIDStub = func(id string) (string, error) { return id, nil }
+	client := jobEnvClient(t, NewJobEnvServiceServer(&authfakes.FakeGRPCAuthorization{}, store,
+		runtime.NewResolver(runtimes, platform.NewPlatform()), cfg, platform.NewPlatform()))
+	ctx := context.Background()
+
+	resp, err := client.ExportJobEnvironment(ctx, &jobenvpb.ExportJobEnvironmentRequest{JobUuid: jobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Format != "dockerfile" || len(resp.Files) != 2 || resp.Files[0].Path != "Dockerfile" ||
+		resp.Files[1].Path != "work/train.py" || string(resp.Files[1].Content) != "print(1)" {
+		t.Fatalf("response = %v", resp)
+	}
+	dockerfile := string(resp.Files[0].Content)
+	for _, want := range []string{
+		"--build-context runtime=" + runtimeDir,
+		"COPY --from=runtime /isolated/usr/bin /usr/local/bin",
+		`ENV PYTHONUNBUFFERED="1"`,
+		`ENV EPOCHS="10"`,
+		`ENV PATH="/usr/local/bin:/usr/local/sbin:`,
+		"-v /opt/joblet/volumes/datasets/data:/volumes/datasets",
+		"-e TOKEN",
+		"WORKDIR /work",
+	} {
+		if !strings.Contains(dockerfile, want) {
+			t.Errorf("Dockerfile lacks %q:\n%s", want, dockerfile)
+		}
+	}
+	if strings.Contains(dockerfile, "s3cret") || strings.Contains(dockerfile, "PATH_PREPEND") {
+		t.Errorf("Dockerfile exports a secret value or PATH_PREPEND:\n%s", dockerfile)
+	}
+
+	resp, err = client.ExportJobEnvironment(ctx, &jobenvpb.ExportJobEnvironmentRequest{JobUuid: jobID, Format: "oci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := string(resp.Files[0].Content)
+	if !strings.Contains(config, `"destination": "/bin"`) || strings.Contains(config, "/does/not/exist") {
+		t.Errorf("config.json mounts the host directories missing or not existing:\n%s", config)
+	}
+
+	for _, tt := range []struct {
+		req  *jobenvpb.ExportJobEnvironmentRequest
+		code codes.Code
+	}{
+		{&jobenvpb.ExportJobEnvironmentRequest{JobUuid: "missing"}, codes.NotFound},
+		{&jobenvpb.ExportJobEnvironmentRequest{JobUuid: buildID}, codes.FailedPrecondition},
+		{&jobenvpb.ExportJobEnvironmentRequest{JobUuid: jobID, Format: "tarball"}, codes.InvalidArgument},
+	} {
+		if _, err := client.ExportJobEnvironment(ctx, tt.req); status.Code(err) != tt.code {
+			t.Errorf("ExportJobEnvironment(%v) error = %v, want %v", tt.req, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	eventspb "github.com/ehsaniara/joblet/internal/proto/gen/events"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// endedJobStatuses end a job's event stream
+var endedJobStatuses = map[domain.JobStatus]bool{
+	domain.StatusCompleted: true,
+	domain.StatusFailed:    true,
+	domain.StatusStopped:   true,
+	domain.StatusCanceled:  true,
+	domain.StatusExpired:   true,
+}
+
+// JobEventServiceServer implements the gRPC service streaming the status and
+// progress of jobs
+type JobEventServiceServer struct {
+	eventspb.UnimplementedJobEventServiceServer
+	auth     auth2.GRPCAuthorization
+	jobStore adapters.JobStorer
+	logger   *logger.Logger
+}
+
+// NewJobEventServiceServer creates a new job event service server
+func NewJobEventServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer) *JobEventServiceServer {
+	return &JobEventServiceServer{
+		auth:     auth,
+		jobStore: jobStore,
+		logger:   logger.WithField("component", "job-events"),
+	}
+}
+
+// StreamJobEvents sends a snapshot of the job, then its status changes and
+// the progress it reports, until it ends
+func (s *JobEventServiceServer) StreamJobEvents(req *eventspb.StreamJobEventsRequest, stream eventspb.JobEventService_StreamJobEventsServer) error {
+	log := s.logger.WithFields("operation", "StreamJobEvents", "jobId", req.JobUuid)
+	if err := s.auth.Authorized(stream.Context(), auth2.GetJobStatusOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+
+	jobUUID := req.JobUuid
+	if resolved, err := s.jobStore.ResolveJobUUID(jobUUID); err == nil {
+		jobUUID = resolved
+	}
+
+	// Subscribe before the snapshot, so no change falls between the two
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	updates, unsubscribe, err := s.jobStore.PubSub().Subscribe(ctx, "jobs")
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to subscribe to job events: %v", err)
+	}
+	defer unsubscribe()
+
+	job, exists := s.jobStore.Job(jobUUID)
+	if !exists {
+		return status.Errorf(codes.NotFound, "job %s not found", req.JobUuid)
+	}
+	current := snapshotJobEvent(job)
+	if err := stream.Send(current); err != nil {
+		return err
+	}
+
+	for !endedJobStatuses[domain.JobStatus(current.Status)] {
+		var msg adapters.JobEvent
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			msg = update.Payload
+		}
+		if msg.JobID != jobUUID {
+			continue
+		}
+
+		switch {
+		case msg.Type == "PROGRESS" && msg.Progress != nil:
+			current.Type = "PROGRESS"
+			current.Percent = msg.Progress.Percent
+			current.Phase = msg.Progress.Phase
+			current.Message = msg.Progress.Message
+		case msg.Type == "UPDATED" && msg.Status != "" && msg.Status != current.Status:
+			current.Type = "STATUS"
+			current.Status = msg.Status
+		default:
+			continue
+		}
+		current.Timestamp = time.Now().UnixNano()
+		if err := stream.Send(current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotJobEvent is the STATUS event opening a job's stream
+func snapshotJobEvent(job *domain.Job) *eventspb.JobEvent {
+	event := &eventspb.JobEvent{
+		Type:      "STATUS",
+		Timestamp: time.Now().UnixNano(),
+		JobUuid:   job.Uuid,
+		Status:    string(job.Status),
+		Percent:   -1,
+	}
+	if job.Progress != nil {
+		event.Percent = job.Progress.Percent
+		event.Phase = job.Progress.Phase
+		event.Message = job.Progress.Message
+	}
+	return event
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	eventspb "github.com/ehsaniara/joblet/internal/proto/gen/events"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// jobEventClient serves the job event service over an in-memory connection
+func jobEventClient(t *testing.T, s *JobEventServiceServer) eventspb.JobEventServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	eventspb.RegisterJobEventServiceServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return eventspb.NewJobEventServiceClient(conn)
+}
+
+func TestJobEventService(t *testing.T) {
+	const running, completed = "11111111-2222-3333-4444-555555555555", "66666666-7777-8888-9999-000000000000"
+	jobs := map[string]*domain.Job{
+		running:   {Uuid: running, Status: domain.StatusRunning, Progress: &domain.JobProgress{Percent: 10, Phase: "download"}},
+		completed: {Uuid: completed, Status: domain.StatusCompleted},
+	}
+	events := pubsub.NewPubSub[adapters.JobEvent]()
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(id string) (*domain.Job, bool) {
+		job, exists := jobs[id]
+		return job, exists
+	}
+	store.ResolveJobUUIDStub = func(id string) (string, error) { return id, nil }
+	store.PubSubReturns(events)
+	client := jobEventClient(t, NewJobEventServiceServer(&authfakes.FakeGRPCAuthorization{}, store))
+	ctx := context.Background()
+
+	stream, err := client.StreamJobEvents(ctx, &eventspb.StreamJobEventsRequest{JobUuid: running})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := stream.Recv()
+	if err != nil || snapshot.Type != "STATUS" || snapshot.Status != "RUNNING" || snapshot.Percent != 10 || snapshot.Phase != "download" {
+		t.Fatalf("snapshot = %v, %v", snapshot, err)
+	}
+
+	for _, event := range []adapters.JobEvent{
+		{Type: "PROGRESS", JobID: completed, Progress: &domain.JobProgress{Percent: 99}},
+		{Type: "LOG_CHUNK", JobID: running, LogChunk: []byte("epoch 5\n")},
+		{Type: "PROGRESS", JobID: running, Progress: &domain.JobProgress{Percent: 50, Phase: "train", Message: "epoch 5/10"}},
+		{Type: "UPDATED", JobID: running, Status: "RUNNING"},
+		{Type: "UPDATED", JobID: running, Status: "COMPLETED"},
+	} {
+		if err := events.Publish(ctx, "jobs", event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []*eventspb.JobEvent
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, event)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events after the snapshot, want 2: %v", len(got), got)
+	}
+	if progress := got[0]; progress.Type != "PROGRESS" || progress.Percent != 50 || progress.Phase != "train" ||
+		progress.Message != "epoch 5/10" || progress.Status != "RUNNING" {
+		t.Errorf("progress event = %v", progress)
+	}
+	if end := got[1]; end.Type != "STATUS" || end.Status != "COMPLETED" || end.Percent != 50 {
+		t.Errorf("status event = %v", end)
+	}
+
+	// Ended jobs send their snapshot only
+	stream, err = client.StreamJobEvents(ctx, &eventspb.StreamJobEventsRequest{JobUuid: completed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot, err := stream.Recv(); err != nil || snapshot.Status != "COMPLETED" || snapshot.Percent != -1 {
+		t.Errorf("snapshot = %v, %v", snapshot, err)
+	}
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("stream of an ended job = %v, want EOF", err)
+	}
+
+	stream, err = client.StreamJobEvents(ctx, &eventspb.StreamJobEventsRequest{JobUuid: "missing"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("StreamJobEvents(missing) error = %v, want NotFound", err)
+	}
+}
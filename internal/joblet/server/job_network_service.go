@@ -0,0 +1,82 @@
+package server
+
+import (
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	metricsdomain "github.com/ehsaniara/joblet/internal/joblet/metrics/domain"
+	jobnetpb "github.com/ehsaniara/joblet/internal/proto/gen/jobnet"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JobNetworkServiceServer implements the gRPC service streaming the network
+// counters of jobs
+type JobNetworkServiceServer struct {
+	jobnetpb.UnimplementedJobNetworkServiceServer
+	auth         auth2.GRPCAuthorization
+	jobStore     adapters.JobStorer
+	metricsStore *adapters.MetricsStoreAdapter
+	logger       *logger.Logger
+}
+
+// NewJobNetworkServiceServer creates a new job network service server
+func NewJobNetworkServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, metricsStore *adapters.MetricsStoreAdapter) *JobNetworkServiceServer {
+	return &JobNetworkServiceServer{
+		auth:         auth,
+		jobStore:     jobStore,
+		metricsStore: metricsStore,
+		logger:       logger.WithField("component", "job-network"),
+	}
+}
+
+// StreamJobNetworkMetrics sends the network part of the job's metrics
+// samples, the buffered ones first, until its collector stopped
+func (s *JobNetworkServiceServer) StreamJobNetworkMetrics(req *jobnetpb.StreamJobNetworkMetricsRequest, stream jobnetpb.JobNetworkService_StreamJobNetworkMetricsServer) error {
+	log := s.logger.WithFields("operation", "StreamJobNetworkMetrics", "jobId", req.JobUuid)
+	if err := s.auth.Authorized(stream.Context(), auth2.GetJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+
+	jobUUID := req.JobUuid
+	if resolved, err := s.jobStore.ResolveJobUUID(jobUUID); err == nil {
+		jobUUID = resolved
+	}
+	if _, exists := s.jobStore.Job(jobUUID); !exists {
+		return status.Errorf(codes.NotFound, "job %s not found", req.JobUuid)
+	}
+	if s.metricsStore == nil {
+		return status.Error(codes.Unavailable, "metrics collection is not available")
+	}
+
+	err := s.metricsStore.StreamMetrics(stream.Context(), jobUUID, func(sample *metricsdomain.JobMetricsSample) error {
+		if sample.Network == nil {
+			return nil // Shares the host's network, or its namespace is gone
+		}
+		return stream.Send(convertNetworkSample(sample))
+	})
+	if err != nil && stream.Context().Err() == nil {
+		log.Error("network metrics streaming failed", "error", err)
+		return status.Errorf(codes.Internal, "failed to stream network metrics: %v", err)
+	}
+	return nil
+}
+
+// convertNetworkSample converts the network part of a metrics sample
+func convertNetworkSample(sample *metricsdomain.JobMetricsSample) *jobnetpb.JobNetworkSample {
+	network := sample.Network
+	return &jobnetpb.JobNetworkSample{
+		JobUuid:           sample.JobID,
+		Timestamp:         sample.Timestamp.UnixNano(),
+		RxBytes:           network.TotalRxBytes,
+		TxBytes:           network.TotalTxBytes,
+		RxPackets:         network.TotalRxPackets,
+		TxPackets:         network.TotalTxPackets,
+		RxBps:             network.RxBPS,
+		TxBps:             network.TxBPS,
+		ActiveConnections: network.ActiveConnections,
+		Retransmits:       network.Retransmits,
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	metricsdomain "github.com/ehsaniara/joblet/internal/joblet/metrics/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	jobnetpb "github.com/ehsaniara/joblet/internal/proto/gen/jobnet"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// jobNetworkClient serves the job network service over an in-memory connection
+func jobNetworkClient(t *testing.T, s *JobNetworkServiceServer) jobnetpb.JobNetworkServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	jobnetpb.RegisterJobNetworkServiceServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return jobnetpb.NewJobNetworkServiceClient(conn)
+}
+
+func TestJobNetworkService(t *testing.T) {
+	const jobID = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(id string) (*domain.Job, bool) {
+		return &domain.Job{Uuid: id, Status: domain.StatusRunning}, id == jobID
+	}
+	store.ResolveJobUUIDStub = func(id string) (string, error) { return id, nil }
+	metricsStore := adapters.NewMetricsStoreAdapter(pubsub.NewPubSub[adapters.MetricsEvent](), nil, true, nil)
+	client := jobNetworkClient(t, NewJobNetworkServiceServer(&authfakes.FakeGRPCAuthorization{}, store, metricsStore))
+
+	// Buffered samples come first; those without a network namespace are left out
+	now := time.Now()
+	for _, sample := range []*metricsdomain.JobMetricsSample{
+		{JobID: jobID, Timestamp: now.Add(-2 * time.Second)},
+		{JobID: jobID, Timestamp: now.Add(-time.Second), Network: &metricsdomain.NetworkMetrics{
+			TotalRxBytes: 4096, TotalTxBytes: 1024, RxBPS: 512, ActiveConnections: 3, Retransmits: 7,
+		}},
+	} {
+		if err := metricsStore.PublishMetrics(context.Background(), sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamJobNetworkMetrics(ctx, &jobnetpb.StreamJobNetworkMetricsRequest{JobUuid: jobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample.JobUuid != jobID || sample.RxBytes != 4096 || sample.TxBytes != 1024 || sample.RxBps != 512 ||
+		sample.ActiveConnections != 3 || sample.Retransmits != 7 || sample.Timestamp != now.Add(-time.Second).UnixNano() {
+		t.Errorf("sample = %v", sample)
+	}
+	cancel()
+
+	stream, err = client.StreamJobNetworkMetrics(context.Background(), &jobnetpb.StreamJobNetworkMetricsRequest{JobUuid: "missing"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("StreamJobNetworkMetrics(missing) error = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/jobfs"
+	jobfspb "github.com/ehsaniara/joblet/internal/proto/gen/jobfs"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// jobFileChunkSize is the data size of each streamed file chunk
+const jobFileChunkSize = 256 * 1024
+
+// JobFSServiceServer implements the gRPC service freezing the filesystem of
+// failed jobs and browsing it
+type JobFSServiceServer struct {
+	jobfspb.UnimplementedJobFilesystemServiceServer
+	auth     auth2.GRPCAuthorization
+	jobStore adapters.JobStorer
+	frozen   *jobfs.Store
+	logger   *logger.Logger
+}
+
+// NewJobFSServiceServer creates a new job filesystem service server
+func NewJobFSServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, frozen *jobfs.Store) *JobFSServiceServer {
+	return &JobFSServiceServer{
+		auth:     auth,
+		jobStore: jobStore,
+		frozen:   frozen,
+		logger:   logger.WithField("component", "job-fs"),
+	}
+}
+
+// FreezeJobFilesystem arms a job that has not ended so its filesystem is
+// kept if it fails. Jobs whose filesystem is already frozen report when it
+// expires.
+func (s *JobFSServiceServer) FreezeJobFilesystem(ctx context.Context, req *jobfspb.FreezeJobFilesystemRequest) (*jobfspb.FreezeJobFilesystemResponse, error) {
+	log := s.logger.WithFields("operation", "FreezeJobFilesystem", "jobId", req.JobUuid)
+	if err := s.auth.Authorized(ctx, auth2.FreezeJobFSOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if !s.frozen.Enabled() {
+		return nil, status.Error(codes.FailedPrecondition, "freezing job filesystems is disabled on this server (filesystem.freezeRetention)")
+	}
+
+	jobUUID := s.resolveJobUUID(req.JobUuid)
+	if rec, exists := s.frozen.Record(jobUUID); exists && rec.Frozen() {
+		return s.freezeResponse(rec), nil
+	}
+	job, exists := s.jobStore.Job(jobUUID)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "job %s not found", req.JobUuid)
+	}
+	if job.IsCompleted() {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"job %s already ended (%s) and its filesystem was cleaned up; arm jobs before they end, or run them with --freeze-fs", jobUUID, job.Status)
+	}
+
+	rec, err := s.frozen.Arm(jobUUID, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to arm job %s: %v", jobUUID, err)
+	}
+	// The job may have ended between the check and the arming, too late
+	// for its cleanup to see the record
+	if job, exists := s.jobStore.Job(jobUUID); exists && job.IsCompleted() {
+		if current, _ := s.frozen.Record(jobUUID); current == nil || !current.Frozen() {
+			_ = s.frozen.Disarm(jobUUID)
+			return nil, status.Errorf(codes.FailedPrecondition, "job %s ended (%s) before its filesystem could be kept", jobUUID, job.Status)
+		}
+	}
+
+	log.Info("job filesystem will be kept if the job fails")
+	return s.freezeResponse(rec), nil
+}
+
+// ListJobFiles lists a directory of a frozen job filesystem
+func (s *JobFSServiceServer) ListJobFiles(ctx context.Context, req *jobfspb.ListJobFilesRequest) (*jobfspb.ListJobFilesResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.BrowseJobFSOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ListJobFiles", "error", err)
+		return nil, err
+	}
+
+	jobUUID := s.resolveJobUUID(req.JobUuid)
+	entries, err := s.frozen.List(jobUUID, req.Path)
+	if err != nil {
+		return nil, jobFSError(jobUUID, req.Path, err)
+	}
+	resp := &jobfspb.ListJobFilesResponse{Files: make([]*jobfspb.JobFile, 0, len(entries))}
+	if rec, exists := s.frozen.Record(jobUUID); exists {
+		resp.ExpiresAt = rec.ExpiresAt.UnixNano()
+	}
+	for _, entry := range entries {
+		resp.Files = append(resp.Files, &jobfspb.JobFile{
+			Name:    entry.Name,
+			Size:    entry.Size,
+			Mode:    uint32(entry.Mode),
+			ModTime: entry.ModTime.UnixNano(),
+		})
+	}
+	return resp, nil
+}
+
+// ReadJobFile streams a file of a frozen job filesystem from req.Offset, at
+// most req.Limit bytes when set
+func (s *JobFSServiceServer) ReadJobFile(req *jobfspb.ReadJobFileRequest, stream jobfspb.JobFilesystemService_ReadJobFileServer) error {
+	if err := s.auth.Authorized(stream.Context(), auth2.BrowseJobFSOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ReadJobFile", "error", err)
+		return err
+	}
+	if req.Offset < 0 || req.Limit < 0 {
+		return status.Error(codes.InvalidArgument, "offset and limit cannot be negative")
+	}
+
+	jobUUID := s.resolveJobUUID(req.JobUuid)
+	f, err := s.frozen.Open(jobUUID, req.Path)
+	if err != nil {
+		return jobFSError(jobUUID, req.Path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to stat %s: %v", req.Path, err)
+	}
+
+	length := info.Size() - req.Offset
+	if req.Limit > 0 {
+		length = min(length, req.Limit)
+	}
+	r := io.NewSectionReader(f, req.Offset, max(length, 0))
+	buf := make([]byte, jobFileChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&jobfspb.JobFileChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read %s: %v", req.Path, err)
+		}
+	}
+}
+
+// resolveJobUUID expands a short job ID the store knows. Frozen jobs may be
+// gone from the store, so unknown IDs are used as given.
+func (s *JobFSServiceServer) resolveJobUUID(jobUUID string) string {
+	if resolved, err := s.jobStore.ResolveJobUUID(jobUUID); err == nil {
+		return resolved
+	}
+	return jobUUID
+}
+
+func (s *JobFSServiceServer) freezeResponse(rec *jobfs.Record) *jobfspb.FreezeJobFilesystemResponse {
+	resp := &jobfspb.FreezeJobFilesystemResponse{Frozen: rec.Frozen(), Retention: int64(s.frozen.Retention())}
+	if rec.Frozen() {
+		resp.ExpiresAt = rec.ExpiresAt.UnixNano()
+	}
+	return resp
+}
+
+// jobFSError maps browsing errors to gRPC statuses
+func jobFSError(jobUUID, path string, err error) error {
+	switch {
+	case errors.Is(err, jobfs.ErrNotFrozen):
+		return status.Errorf(codes.NotFound, "job %s has no frozen filesystem; it was not armed, did not fail, or its retention ended", jobUUID)
+	case errors.Is(err, fs.ErrNotExist):
+		return status.Errorf(codes.NotFound, "%s not found in the filesystem of job %s", path, jobUUID)
+	default:
+		return status.Errorf(codes.InvalidArgument, "cannot open %s: %v", path, err)
+	}
+}
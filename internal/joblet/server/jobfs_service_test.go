@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/jobfs"
+	jobfspb "github.com/ehsaniara/joblet/internal/proto/gen/jobfs"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// jobFSClient serves the job filesystem service over an in-memory connection
+func jobFSClient(t *testing.T, s *JobFSServiceServer) jobfspb.JobFilesystemServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	jobfspb.RegisterJobFilesystemServiceServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return jobfspb.NewJobFilesystemServiceClient(conn)
+}
+
+func readJobFile(client jobfspb.JobFilesystemServiceClient, req *jobfspb.ReadJobFileRequest) ([]byte, error) {
+	stream, err := client.ReadJobFile(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk.Data...)
+	}
+}
+
+func TestJobFSService(t *testing.T) {
+	const running, failed = "11111111-2222-3333-4444-555555555555", "66666666-7777-8888-9999-000000000000"
+	baseDir := t.TempDir()
+	rootDir := filepath.Join(baseDir, failed)
+	if err := os.MkdirAll(filepath.Join(rootDir, "work"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "work", "result.csv"), []byte("id,score\n1,0.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := map[string]*domain.Job{
+		running: {Uuid: running, Status: domain.StatusRunning},
+		failed:  {Uuid: failed, Status: domain.StatusFailed},
+	}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(id string) (*domain.Job, bool) {
+		job, exists := jobs[id]
+		return job, exists
+	}
+	store.ResolveJobUUIDStub = func(id string) (string, error) { return id, nil }
+
+	frozen := jobfs.NewStore(baseDir, time.Hour)
+	client := jobFSClient(t, NewJobFSServiceServer(&authfakes.FakeGRPCAuthorization{}, store, frozen))
+	ctx := context.Background()
+
+	resp, err := client.FreezeJobFilesystem(ctx, &jobfspb.FreezeJobFilesystemRequest{JobUuid: running})
+	if err != nil || resp.Frozen || resp.Retention != int64(time.Hour) {
+		t.Fatalf("FreezeJobFilesystem(running) = %v, %v", resp, err)
+	}
+	_, err = client.FreezeJobFilesystem(ctx, &jobfspb.FreezeJobFilesystemRequest{JobUuid: failed})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("FreezeJobFilesystem(ended job) error = %v, want FailedPrecondition", err)
+	}
+	_, err = client.ListJobFiles(ctx, &jobfspb.ListJobFilesRequest{JobUuid: running, Path: "/"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ListJobFiles(armed job) error = %v, want NotFound", err)
+	}
+
+	// The failed job was armed before it ended
+	if _, err := frozen.Arm(failed, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := frozen.Freeze(failed, map[string]string{"/": rootDir}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = client.FreezeJobFilesystem(ctx, &jobfspb.FreezeJobFilesystemRequest{JobUuid: failed})
+	if err != nil || !resp.Frozen || resp.ExpiresAt == 0 {
+		t.Errorf("FreezeJobFilesystem(frozen job) = %v, %v", resp, err)
+	}
+
+	list, err := client.ListJobFiles(ctx, &jobfspb.ListJobFilesRequest{JobUuid: failed, Path: "/work"})
+	if err != nil || len(list.Files) != 1 || list.Files[0].Name != "result.csv" || list.Files[0].Size != 15 {
+		t.Fatalf("ListJobFiles(/work) = %v, %v", list, err)
+	}
+	data, err := readJobFile(client, &jobfspb.ReadJobFileRequest{JobUuid: failed, Path: "/work/result.csv", Offset: 3, Limit: 5})
+	if err != nil || string(data) != "score" {
+		t.Errorf("ReadJobFile() = %q, %v", data, err)
+	}
+	_, err = readJobFile(client, &jobfspb.ReadJobFileRequest{JobUuid: failed, Path: "/work/missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ReadJobFile(missing) error = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/lint"
+	lintpb "github.com/ehsaniara/joblet/internal/proto/gen/lint"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LintServiceServer implements the gRPC service linting job and workflow
+// specs with the node's runtime aliases, networks and default limits
+type LintServiceServer struct {
+	lintpb.UnimplementedSpecLintServiceServer
+	auth          auth2.GRPCAuthorization
+	workflows     *WorkflowServiceServer
+	networkStore  adapters.NetworkStorer
+	defaultLimits string
+	logger        *logger.Logger
+}
+
+// NewLintServiceServer creates a new spec lint service server
+func NewLintServiceServer(auth auth2.GRPCAuthorization, workflows *WorkflowServiceServer, networkStore adapters.NetworkStorer, cfg *config.Config) *LintServiceServer {
+	s := &LintServiceServer{
+		auth:         auth,
+		workflows:    workflows,
+		networkStore: networkStore,
+		logger:       logger.WithField("component", "spec-lint"),
+	}
+	if cpu, memory := cfg.Joblet.DefaultCPULimit, cfg.Joblet.DefaultMemoryLimit; cpu > 0 && memory > 0 {
+		s.defaultLimits = fmt.Sprintf("%d%% CPU, %dMB memory", cpu, memory)
+	}
+	return s
+}
+
+// LintSpec lints a job spec or workflow as the caller would submit it
+func (s *LintServiceServer) LintSpec(ctx context.Context, req *lintpb.LintSpecRequest) (*lintpb.LintSpecResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.LintSpecOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "LintSpec", "error", err)
+		return nil, err
+	}
+
+	opts := lint.Options{DefaultLimits: s.defaultLimits}
+	if runtimes := s.workflows.runtimes; runtimes != nil {
+		tenant := s.workflows.tenantOf(ctx)
+		opts.ResolveRuntime = func(spec string) (string, string) { return runtimes(tenant, spec) }
+	}
+	if s.networkStore != nil {
+		opts.Networks = []string{}
+		for _, network := range s.networkStore.ListNetworks() {
+			opts.Networks = append(opts.Networks, network.Name)
+		}
+	}
+
+	kind, findings, err := lint.Lint(req.Spec, opts)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &lintpb.LintSpecResponse{Kind: kind}
+	for _, f := range findings {
+		resp.Findings = append(resp.Findings, &lintpb.LintFinding{
+			Rule:     f.Rule,
+			Severity: string(f.Severity),
+			Job:      f.Job,
+			Field:    f.Field,
+			Message:  f.Message,
+		})
+	}
+	s.logger.Debug("spec linted", "kind", kind, "findings", len(findings))
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	lintpb "github.com/ehsaniara/joblet/internal/proto/gen/lint"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeNetworks lists a fixed set of networks
+type fakeNetworks struct {
+	adapters.NetworkStorer
+	names []string
+}
+
+func (f fakeNetworks) ListNetworks() []*adapters.NetworkConfig {
+	var networks []*adapters.NetworkConfig
+	for _, name := range f.names {
+		networks = append(networks, &adapters.NetworkConfig{Name: name})
+	}
+	return networks
+}
+
+func TestLintService_LintSpec(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Joblet.DefaultCPULimit = 100
+	cfg.Joblet.DefaultMemoryLimit = 512
+	workflows := &WorkflowServiceServer{runtimes: func(tenant, spec string) (string, string) {
+		if spec == "python" {
+			return "python-3.11@1.2.0", "server alias"
+		}
+		return spec, ""
+	}}
+	s := NewLintServiceServer(&authfakes.FakeGRPCAuthorization{}, workflows, fakeNetworks{names: []string{"training"}}, cfg)
+
+	spec := "command: python3\nruntime: python\nnetwork: training\n"
+	resp, err := s.LintSpec(context.Background(), &lintpb.LintSpecRequest{Spec: []byte(spec)})
+	if err != nil {
+		t.Fatalf("LintSpec() error = %v", err)
+	}
+	if resp.Kind != "Job" {
+		t.Errorf("kind = %s, want Job", resp.Kind)
+	}
+	// Only the missing limits: the alias is pinned and the network exists
+	if len(resp.Findings) != 1 || resp.Findings[0].Rule != "no-resource-limits" {
+		t.Fatalf("findings = %v", resp.Findings)
+	}
+	if want := "no CPU or memory limit set, the node's defaults apply (100% CPU, 512MB memory); set max_cpu and max_memory for what the job needs"; resp.Findings[0].Message != want {
+		t.Errorf("message = %q", resp.Findings[0].Message)
+	}
+
+	spec = "command: python3\nnetwork: missing\nresources: {max_cpu: 10, max_memory: 10}\n"
+	resp, err = s.LintSpec(context.Background(), &lintpb.LintSpecRequest{Spec: []byte(spec)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Findings) != 1 || resp.Findings[0].Rule != "unknown-network" || resp.Findings[0].Severity != "error" {
+		t.Errorf("findings = %v", resp.Findings)
+	}
+
+	_, err = s.LintSpec(context.Background(), &lintpb.LintSpecRequest{Spec: []byte("command: [")})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid spec error = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,278 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// logChunkSize is the content size at which records are sent as a chunk
+const logChunkSize = 1024 * 1024
+
+// maxLogRange bounds the records read by one ReadLogRange call
+const maxLogRange = 5000
+
+// LogServiceServer implements the gRPC log download service over the logs
+// the persist service stored
+type LogServiceServer struct {
+	logspb.UnimplementedLogServiceServer
+	auth          auth2.GRPCAuthorization
+	jobStore      adapters.JobStorer
+	persistClient persistpb.PersistServiceClient
+	logger        *logger.Logger
+}
+
+// NewLogServiceServer creates a new log download service server
+func NewLogServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, persistClient persistpb.PersistServiceClient) *LogServiceServer {
+	return &LogServiceServer{
+		auth:          auth,
+		jobStore:      jobStore,
+		persistClient: persistClient,
+		logger:        logger.WithField("component", "log-download"),
+	}
+}
+
+// DownloadJobLogs streams a finished job's persisted log records after
+// req.Offset, grouped into chunks of about logChunkSize
+func (s *LogServiceServer) DownloadJobLogs(req *logspb.DownloadJobLogsRequest, stream logspb.LogService_DownloadJobLogsServer) error {
+	log := s.logger.WithFields("operation", "DownloadJobLogs", "jobId", req.JobUuid, "offset", req.Offset)
+	if err := s.auth.Authorized(stream.Context(), auth2.GetJobLogsOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+	if s.persistClient == nil {
+		return status.Error(codes.Unavailable, "log persistence is not available on this server")
+	}
+	if req.Offset < 0 {
+		return status.Error(codes.InvalidArgument, "offset cannot be negative")
+	}
+
+	// Jobs no longer in memory may still have persisted logs, so only
+	// resolve and check the ones the store knows
+	jobUUID := req.JobUuid
+	if resolved, err := s.jobStore.ResolveJobUUID(req.JobUuid); err == nil {
+		jobUUID = resolved
+	}
+	if job, exists := s.jobStore.Job(jobUUID); exists && !job.IsCompleted() {
+		return status.Errorf(codes.FailedPrecondition, "job %s is still %s; follow it with 'rnx job log' or save the log once it finishes", jobUUID, job.Status)
+	}
+
+	records, err := s.persistClient.QueryLogs(stream.Context(), &persistpb.QueryLogsRequest{
+		JobId:  jobUUID,
+		Stream: persistpb.StreamType_STREAM_TYPE_UNSPECIFIED,
+		Offset: int32(req.Offset),
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to query persisted logs: %v", err)
+	}
+
+	chunk := &logspb.LogChunk{JobUuid: jobUUID}
+	sent := int64(0)
+	flush := func() error {
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+		sent += chunk.Records
+		chunk = &logspb.LogChunk{}
+		return nil
+	}
+
+	for {
+		record, err := records.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read persisted logs: %v", status.Convert(err).Message())
+		}
+		chunk.Content = append(chunk.Content, record.Content...)
+		chunk.Records++
+		if len(chunk.Content) >= logChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if chunk.Records > 0 || sent == 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	log.Info("persisted log downloaded", "records", sent)
+	return nil
+}
+
+// browsedJob checks that the caller may read logs and returns the full UUID
+// of the job, or the UUID as given for jobs no longer in memory
+func (s *LogServiceServer) browsedJob(ctx context.Context, jobUUID string) (string, error) {
+	if err := s.auth.Authorized(ctx, auth2.GetJobLogsOp); err != nil {
+		return "", err
+	}
+	if s.persistClient == nil {
+		return "", status.Error(codes.Unavailable, "log persistence is not available on this server")
+	}
+	if resolved, err := s.jobStore.ResolveJobUUID(jobUUID); err == nil {
+		return resolved, nil
+	}
+	return jobUUID, nil
+}
+
+func persistStream(stream logspb.LogStream) persistpb.StreamType {
+	switch stream {
+	case logspb.LogStream_LOG_STREAM_STDOUT:
+		return persistpb.StreamType_STREAM_TYPE_STDOUT
+	case logspb.LogStream_LOG_STREAM_STDERR:
+		return persistpb.StreamType_STREAM_TYPE_STDERR
+	}
+	return persistpb.StreamType_STREAM_TYPE_UNSPECIFIED
+}
+
+// scanLogs calls visit with each persisted record from offset on, at most
+// limit of them (0 = all), until visit returns false
+func (s *LogServiceServer) scanLogs(ctx context.Context, jobUUID string, stream logspb.LogStream, offset, limit int64, visit func(offset int64, record *persistpb.LogLine) bool) error {
+	records, err := s.persistClient.QueryLogs(ctx, &persistpb.QueryLogsRequest{
+		JobId:  jobUUID,
+		Stream: persistStream(stream),
+		Offset: int32(offset),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to query persisted logs: %v", err)
+	}
+	for i := offset; ; i++ {
+		record, err := records.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read persisted logs: %v", status.Convert(err).Message())
+		}
+		if !visit(i, record) {
+			return nil
+		}
+	}
+}
+
+// ReadLogRange returns up to req.Limit persisted records from req.Offset on,
+// stopping early once about logChunkSize of content is read, without the
+// rest of the log leaving the node
+func (s *LogServiceServer) ReadLogRange(ctx context.Context, req *logspb.ReadLogRangeRequest) (*logspb.LogRange, error) {
+	jobUUID, err := s.browsedJob(ctx, req.JobUuid)
+	if err != nil {
+		s.logger.Warn("log range refused", "jobId", req.JobUuid, "error", err)
+		return nil, err
+	}
+	if req.Offset < 0 || req.Limit <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset cannot be negative and limit must be positive")
+	}
+	limit := min(int64(req.Limit), maxLogRange)
+
+	// One record more than asked tells whether the window reaches the end
+	resp := &logspb.LogRange{JobUuid: jobUUID, End: true}
+	size := 0
+	err = s.scanLogs(ctx, jobUUID, req.Stream, req.Offset, limit+1, func(offset int64, record *persistpb.LogLine) bool {
+		if int64(len(resp.Records)) == limit || size >= logChunkSize {
+			resp.End = false
+			return false
+		}
+		resp.Records = append(resp.Records, &logspb.LogRecord{
+			Offset:    offset,
+			Timestamp: record.Timestamp,
+			Stderr:    record.Stream == persistpb.StreamType_STREAM_TYPE_STDERR,
+			Content:   record.Content,
+		})
+		size += len(record.Content)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// LocateLogRecord finds the next or previous record matching a pattern, the
+// first record at or after a time, or the end of the log. The node reads
+// the log; only the location is sent back.
+func (s *LogServiceServer) LocateLogRecord(ctx context.Context, req *logspb.LocateLogRecordRequest) (*logspb.LogLocation, error) {
+	jobUUID, err := s.browsedJob(ctx, req.JobUuid)
+	if err != nil {
+		s.logger.Warn("log search refused", "jobId", req.JobUuid, "error", err)
+		return nil, err
+	}
+	if req.From < -1 {
+		return nil, status.Error(codes.InvalidArgument, "from cannot be below -1")
+	}
+
+	matches := func(*persistpb.LogLine) bool { return true }
+	switch {
+	case req.Pattern != "":
+		// Records hold whole writes, so anchors match at each line
+		expr := "(?m)" + req.Pattern
+		if !req.Regex {
+			expr = regexp.QuoteMeta(req.Pattern)
+		}
+		if req.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid pattern: %v", err)
+		}
+		matches = func(record *persistpb.LogLine) bool { return re.Match(record.Content) }
+	case req.Timestamp > 0:
+		matches = func(record *persistpb.LogLine) bool { return record.Timestamp >= req.Timestamp }
+	}
+
+	location := &logspb.LogLocation{}
+	found := func(offset int64, record *persistpb.LogLine) {
+		location.Found, location.Offset, location.Timestamp = true, offset, record.Timestamp
+	}
+
+	// Backward searches read up to the start record and keep the last match
+	if req.Backward {
+		if req.From <= 0 {
+			return location, nil
+		}
+		err = s.scanLogs(ctx, jobUUID, req.Stream, 0, req.From, func(offset int64, record *persistpb.LogLine) bool {
+			if matches(record) {
+				found(offset, record)
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		return location, nil
+	}
+
+	// Without a pattern or time the scan only counts the records
+	counting := req.Pattern == "" && req.Timestamp <= 0
+	start := req.From + 1
+	scanned := int64(0)
+	err = s.scanLogs(ctx, jobUUID, req.Stream, start, 0, func(offset int64, record *persistpb.LogLine) bool {
+		scanned++
+		if !counting && matches(record) {
+			found(offset, record)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !location.Found {
+		location.Records = start + scanned
+	}
+	return location, nil
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLogPersist serves a fixed log, honouring the query's offset and limit
+type fakeLogPersist struct {
+	persistpb.PersistServiceClient
+	lines   []*persistpb.LogLine
+	queries []*persistpb.QueryLogsRequest
+}
+
+type logLineStream struct {
+	grpc.ClientStream
+	lines []*persistpb.LogLine
+}
+
+func (s *logLineStream) Recv() (*persistpb.LogLine, error) {
+	if len(s.lines) == 0 {
+		return nil, io.EOF
+	}
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line, nil
+}
+
+func (f *fakeLogPersist) QueryLogs(_ context.Context, req *persistpb.QueryLogsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[persistpb.LogLine], error) {
+	f.queries = append(f.queries, req)
+	lines := f.lines[min(int(req.Offset), len(f.lines)):]
+	if req.Limit > 0 && int(req.Limit) < len(lines) {
+		lines = lines[:req.Limit]
+	}
+	return &logLineStream{lines: lines}, nil
+}
+
+func newBrowsedLogService(lines ...string) (*LogServiceServer, *fakeLogPersist) {
+	persist := &fakeLogPersist{}
+	for i, line := range lines {
+		persist.lines = append(persist.lines, &persistpb.LogLine{
+			JobId:     "job-1",
+			Stream:    persistpb.StreamType_STREAM_TYPE_STDOUT,
+			Content:   []byte(line),
+			Timestamp: int64(i+1) * 100,
+		})
+	}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.ResolveJobUUIDReturns("", errors.New("not in memory"))
+	return NewLogServiceServer(&authfakes.FakeGRPCAuthorization{}, store, persist), persist
+}
+
+func TestLogService_ReadLogRange(t *testing.T) {
+	s, persist := newBrowsedLogService("a\n", "b\n", "c\n", "d\n")
+
+	resp, err := s.ReadLogRange(context.Background(), &logspb.ReadLogRangeRequest{JobUuid: "job-1", Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("ReadLogRange() error = %v", err)
+	}
+	if len(resp.Records) != 2 || resp.Records[0].Offset != 1 || string(resp.Records[1].Content) != "c\n" || resp.End {
+		t.Errorf("range = %v", resp)
+	}
+	if q := persist.queries[0]; q.JobId != "job-1" || q.Offset != 1 || q.Limit != 3 {
+		t.Errorf("query = %v, want one record past the range", q)
+	}
+
+	resp, err = s.ReadLogRange(context.Background(), &logspb.ReadLogRangeRequest{JobUuid: "job-1", Offset: 2, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Records) != 2 || !resp.End {
+		t.Errorf("range to the end = %v", resp)
+	}
+
+	_, err = s.ReadLogRange(context.Background(), &logspb.ReadLogRangeRequest{JobUuid: "job-1", Offset: -1, Limit: 10})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative offset error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestLogService_LocateLogRecord(t *testing.T) {
+	s, _ := newBrowsedLogService("start\n", "ERROR one\n", "ok\n", "error two\n", "done\n")
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		req       *logspb.LocateLogRecordRequest
+		found     bool
+		offset    int64
+		records   int64
+		wantError codes.Code
+	}{
+		{name: "forward", req: &logspb.LocateLogRecordRequest{From: -1, Pattern: "ERROR"}, found: true, offset: 1},
+		{name: "next excludes from", req: &logspb.LocateLogRecordRequest{From: 1, Pattern: "ERROR", IgnoreCase: true}, found: true, offset: 3},
+		{name: "case sensitive miss", req: &logspb.LocateLogRecordRequest{From: 1, Pattern: "ERROR"}, records: 5},
+		{name: "backward", req: &logspb.LocateLogRecordRequest{From: 4, Pattern: "error", IgnoreCase: true, Backward: true}, found: true, offset: 3},
+		{name: "backward excludes from", req: &logspb.LocateLogRecordRequest{From: 3, Pattern: "error", IgnoreCase: true, Backward: true}, found: true, offset: 1},
+		{name: "regex", req: &logspb.LocateLogRecordRequest{From: -1, Pattern: `^(ok|done)$`, Regex: true}, found: true, offset: 2},
+		{name: "literal", req: &logspb.LocateLogRecordRequest{From: -1, Pattern: `^(ok|done)$`}, records: 5},
+		{name: "timestamp", req: &logspb.LocateLogRecordRequest{From: -1, Timestamp: 250}, found: true, offset: 2},
+		{name: "end", req: &logspb.LocateLogRecordRequest{From: -1}, records: 5},
+		{name: "bad regex", req: &logspb.LocateLogRecordRequest{From: -1, Pattern: "(", Regex: true}, wantError: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.JobUuid = "job-1"
+			loc, err := s.LocateLogRecord(ctx, tt.req)
+			if tt.wantError != codes.OK {
+				if status.Code(err) != tt.wantError {
+					t.Fatalf("error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LocateLogRecord() error = %v", err)
+			}
+			if loc.Found != tt.found || loc.Offset != tt.offset || loc.Records != tt.records {
+				t.Errorf("location = %v, want found %v at %d, %d records", loc, tt.found, tt.offset, tt.records)
+			}
+		})
+	}
+}
+
+func TestLogService_BrowseWithoutPersist(t *testing.T) {
+	s := NewLogServiceServer(&authfakes.FakeGRPCAuthorization{}, &adaptersfakes.FakeJobStorer{}, nil)
+	_, err := s.ReadLogRange(context.Background(), &logspb.ReadLogRangeRequest{JobUuid: "job-1", Limit: 1})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("error = %v, want Unavailable", err)
+	}
+}
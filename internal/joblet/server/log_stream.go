@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errLogStreamStalled ends log streams whose client stopped reading
+var errLogStreamStalled = status.Error(codes.Unavailable, "client stopped reading the log stream")
+
+// logStream wraps a GetJobLogs stream to detect dead clients: a send that
+// blocks longer than sendTimeout, because the client's flow control window
+// stays full, abandons the stream, and idle streams get an empty chunk every
+// heartbeat so NAT mappings stay alive and failed sends surface. Its context
+// is cancelled when the stream is abandoned.
+type logStream struct {
+	pb.JobService_GetJobLogsServer
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	sendTimeout time.Duration
+
+	mu       sync.Mutex
+	lastSend time.Time
+	dead     bool
+}
+
+// watchLogStream wraps stream and starts its heartbeats until the returned
+// stop is called
+func watchLogStream(stream pb.JobService_GetJobLogsServer, heartbeat, sendTimeout time.Duration) (*logStream, func()) {
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	s := &logStream{
+		JobService_GetJobLogsServer: stream,
+		ctx:                         ctx,
+		cancel:                      cancel,
+		sendTimeout:                 sendTimeout,
+		lastSend:                    time.Now(),
+	}
+	if heartbeat > 0 {
+		go s.heartbeats(heartbeat)
+	}
+	return s, func() { cancel(context.Canceled) }
+}
+
+func (s *logStream) Context() context.Context {
+	return s.ctx
+}
+
+// Send sends chunk, giving up after sendTimeout
+func (s *logStream) Send(chunk *pb.DataChunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dead {
+		return context.Cause(s.ctx)
+	}
+	if s.sendTimeout <= 0 {
+		return s.send(chunk)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.JobService_GetJobLogsServer.Send(chunk) }()
+	timer := time.NewTimer(s.sendTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err == nil {
+			s.lastSend = time.Now()
+		}
+		return err
+	case <-timer.C:
+		// The blocked send returns once the handler ends the stream; no
+		// other send may start meanwhile
+		s.dead = true
+		s.cancel(errLogStreamStalled)
+		return errLogStreamStalled
+	}
+}
+
+func (s *logStream) send(chunk *pb.DataChunk) error {
+	err := s.JobService_GetJobLogsServer.Send(chunk)
+	if err == nil {
+		s.lastSend = time.Now()
+	}
+	return err
+}
+
+// heartbeats sends an empty chunk whenever the stream was idle for interval
+func (s *logStream) heartbeats(interval time.Duration) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.mu.Lock()
+		idle := time.Since(s.lastSend) >= interval
+		s.mu.Unlock()
+		if !idle {
+			continue
+		}
+		if err := s.Send(&pb.DataChunk{}); err != nil {
+			s.cancel(err)
+			return
+		}
+	}
+}
+
+// Err is why the stream was abandoned, nil while it is healthy
+func (s *logStream) Err() error {
+	if s.ctx.Err() == nil {
+		return nil
+	}
+	if cause := context.Cause(s.ctx); cause != context.Canceled {
+		return cause
+	}
+	return nil
+}
+
+// logResumeOffset reads the non-empty chunks a reconnecting client already
+// received from the joblet-log-offset request header
+func logResumeOffset(ctx context.Context) int {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(constants.LogOffsetHeader)
+	if len(values) == 0 {
+		return 0
+	}
+	offset, err := strconv.Atoi(values[0])
+	if err != nil || offset < 0 {
+		return 0
+	}
+	return offset
+}
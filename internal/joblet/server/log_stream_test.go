@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeLogsServer records sent chunks; sends block while blocked is set
+type fakeLogsServer struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	mu      sync.Mutex
+	chunks  []*pb.DataChunk
+	blocked chan struct{}
+}
+
+func (f *fakeLogsServer) Context() context.Context { return f.ctx }
+
+func (f *fakeLogsServer) Send(chunk *pb.DataChunk) error {
+	if f.blocked != nil {
+		select {
+		case <-f.blocked:
+		case <-f.ctx.Done():
+			return f.ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chunks = append(f.chunks, chunk)
+	return nil
+}
+
+func (f *fakeLogsServer) sent() []*pb.DataChunk {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*pb.DataChunk(nil), f.chunks...)
+}
+
+func TestLogStream_Heartbeat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake := &fakeLogsServer{ctx: ctx}
+	stream, stop := watchLogStream(fake, 20*time.Millisecond, time.Second)
+	defer stop()
+
+	if err := stream.Send(&pb.DataChunk{Payload: []byte("line\n")}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(fake.sent()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	chunks := fake.sent()
+	if len(chunks) < 3 || string(chunks[0].Payload) != "line\n" || len(chunks[1].Payload) != 0 {
+		t.Fatalf("sent %d chunks, want the line followed by empty heartbeats", len(chunks))
+	}
+	if stream.Err() != nil {
+		t.Errorf("healthy stream Err() = %v", stream.Err())
+	}
+}
+
+func TestLogStream_StalledClient(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake := &fakeLogsServer{ctx: ctx, blocked: make(chan struct{})}
+	stream, stop := watchLogStream(fake, 0, 20*time.Millisecond)
+	defer stop()
+
+	if err := stream.Send(&pb.DataChunk{Payload: []byte("line\n")}); !errors.Is(err, errLogStreamStalled) {
+		t.Fatalf("blocked Send() = %v, want errLogStreamStalled", err)
+	}
+	select {
+	case <-stream.Context().Done():
+	default:
+		t.Fatal("stream context not cancelled after the stall")
+	}
+	if !errors.Is(stream.Err(), errLogStreamStalled) {
+		t.Errorf("Err() = %v", stream.Err())
+	}
+	// Later sends fail at once instead of queueing behind the blocked one
+	if err := stream.Send(&pb.DataChunk{}); err == nil {
+		t.Error("Send() on a dead stream succeeded")
+	}
+}
+
+func TestLogStream_ClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, stop := watchLogStream(&fakeLogsServer{ctx: ctx}, 0, time.Second)
+	defer stop()
+	cancel()
+	<-stream.Context().Done()
+	if stream.Err() != nil {
+		t.Errorf("Err() after the client left = %v, want nil", stream.Err())
+	}
+}
+
+func TestLogResumeOffset(t *testing.T) {
+	for value, want := range map[string]int{"42": 42, "-1": 0, "x": 0} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constants.LogOffsetHeader, value))
+		if got := logResumeOffset(ctx); got != want {
+			t.Errorf("logResumeOffset(%q) = %d, want %d", value, got, want)
+		}
+	}
+	if got := logResumeOffset(context.Background()); got != 0 {
+		t.Errorf("logResumeOffset without header = %d", got)
+	}
+}
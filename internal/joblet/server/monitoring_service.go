@@ -10,8 +10,10 @@ import (
 	"google.golang.org/grpc/status"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/clocksync"
 	"github.com/ehsaniara/joblet/internal/joblet/monitoring"
 	"github.com/ehsaniara/joblet/internal/joblet/monitoring/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/ratelimit"
 	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
 	"github.com/ehsaniara/joblet/pkg/version"
@@ -23,6 +25,10 @@ type MonitoringServiceServer struct {
 	monitor *monitoring.Service
 	config  *config.Config
 	logger  *logger.Logger
+	// Checks the clock scheduled jobs start by (nil = not reported)
+	clock *clocksync.Checker
+	// Throttles calls per client (nil = rate limiting disabled)
+	limiter *ratelimit.Limiter
 }
 
 // NewMonitoringServiceServer creates a new monitoring service server
@@ -41,6 +47,8 @@ func (s *MonitoringServiceServer) GetSystemStatus(ctx context.Context, req *pb.E
 	if systemStatus == nil {
 		return nil, status.Errorf(codes.Internal, "failed to get system status")
 	}
+	s.setClockStatusHeader(ctx)
+	s.setRateLimitHeader(ctx)
 
 	return s.systemStatusToProto(systemStatus), nil
 }
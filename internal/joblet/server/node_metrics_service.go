@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/monitoring/history"
+	"github.com/ehsaniara/joblet/internal/joblet/orphans"
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NodeMetricsServiceServer serves the host metrics recorded to persist and
+// the counts of orphaned job resources
+type NodeMetricsServiceServer struct {
+	nodemetricspb.UnimplementedNodeMetricsServiceServer
+	auth    auth2.GRPCAuthorization
+	cfg     config.MonitoringHistoryConfig
+	persist persistpb.PersistServiceClient // nil when persist is unavailable
+	orphans orphans.Lister                 // nil when the joblet does not reconcile them
+	logger  *logger.Logger
+}
+
+// NewNodeMetricsServiceServer creates a new node metrics service server
+func NewNodeMetricsServiceServer(auth auth2.GRPCAuthorization, cfg config.MonitoringHistoryConfig, persist persistpb.PersistServiceClient, orphans orphans.Lister) *NodeMetricsServiceServer {
+	return &NodeMetricsServiceServer{
+		auth:    auth,
+		cfg:     cfg,
+		persist: persist,
+		orphans: orphans,
+		logger:  logger.WithField("component", "node-metrics-grpc"),
+	}
+}
+
+// QueryNodeMetrics streams the samples recorded in the requested range,
+// which is clipped to the retention period
+func (s *NodeMetricsServiceServer) QueryNodeMetrics(req *nodemetricspb.QueryNodeMetricsRequest, stream nodemetricspb.NodeMetricsService_QueryNodeMetricsServer) error {
+	if err := s.auth.Authorized(stream.Context(), auth2.QueryNodeMetricsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "QueryNodeMetrics", "error", err)
+		return err
+	}
+	if !s.cfg.Enabled {
+		return status.Error(codes.FailedPrecondition, "metrics history is disabled on this node (monitoring.history.enabled)")
+	}
+	if s.persist == nil {
+		return status.Error(codes.Unavailable, "persist service unavailable")
+	}
+
+	now := time.Now()
+	start := now.Add(-s.cfg.Retention)
+	if req.StartTime > start.UnixNano() {
+		start = time.Unix(0, req.StartTime)
+	}
+	end := now
+	if req.EndTime > 0 && req.EndTime < end.UnixNano() {
+		end = time.Unix(0, req.EndTime)
+	}
+	if end.Before(start) {
+		return status.Error(codes.InvalidArgument, "end time is before the start time or the retention period")
+	}
+
+	if err := history.Query(stream.Context(), s.persist, start, end, stream.Send); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "failed to read metrics history: %v", err)
+	}
+	return nil
+}
+
+// GetOrphanedResources returns the counts of the cgroups and network
+// resources of gone jobs that the periodic cleanup found
+func (s *NodeMetricsServiceServer) GetOrphanedResources(ctx context.Context, _ *nodemetricspb.GetOrphanedResourcesRequest) (*nodemetricspb.OrphanedResources, error) {
+	if err := s.auth.Authorized(ctx, auth2.QueryNodeMetricsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetOrphanedResources", "error", err)
+		return nil, err
+	}
+	if s.orphans == nil {
+		return nil, status.Error(codes.Unimplemented, "orphaned resources are not reconciled on this platform")
+	}
+
+	stats := s.orphans.OrphanedResources()
+	resp := &nodemetricspb.OrphanedResources{}
+	if !stats.LastPass.IsZero() {
+		resp.LastPass = stats.LastPass.UnixNano()
+	}
+	for _, kind := range orphans.Kinds {
+		resp.Counts = append(resp.Counts, &nodemetricspb.OrphanedResourceCount{
+			Kind:    string(kind),
+			Found:   stats.Found[kind],
+			Cleaned: stats.Cleaned[kind],
+			Failed:  stats.Failed[kind],
+		})
+	}
+	for _, r := range stats.Orphans {
+		resp.Remaining = append(resp.Remaining, &nodemetricspb.OrphanedResource{
+			Kind:  string(r.Kind),
+			Name:  r.Name,
+			Path:  r.Path,
+			JobId: r.JobID,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/orphans"
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeHistoryPersist records the metric queries and answers them empty
+type fakeHistoryPersist struct {
+	persistpb.PersistServiceClient
+	queries []*persistpb.QueryMetricsRequest
+}
+
+type emptyMetricStream struct{ grpc.ClientStream }
+
+func (emptyMetricStream) Recv() (*persistpb.Metric, error) { return nil, io.EOF }
+
+func (f *fakeHistoryPersist) QueryMetrics(_ context.Context, req *persistpb.QueryMetricsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[persistpb.Metric], error) {
+	f.queries = append(f.queries, req)
+	return emptyMetricStream{}, nil
+}
+
+type fakeNodeMetricsStream struct {
+	grpc.ServerStream
+	samples []*nodemetricspb.NodeMetricsSample
+}
+
+func (f *fakeNodeMetricsStream) Context() context.Context { return context.Background() }
+
+func (f *fakeNodeMetricsStream) Send(sample *nodemetricspb.NodeMetricsSample) error {
+	f.samples = append(f.samples, sample)
+	return nil
+}
+
+func TestNodeMetricsService_QueryNodeMetrics(t *testing.T) {
+	cfg := config.MonitoringHistoryConfig{Enabled: true, Interval: time.Minute, Retention: 48 * time.Hour}
+	persist := &fakeHistoryPersist{}
+	s := NewNodeMetricsServiceServer(&authfakes.FakeGRPCAuthorization{}, cfg, persist, nil)
+
+	// A start before the retention period is clipped to it
+	since := time.Now().Add(-30 * 24 * time.Hour).UnixNano()
+	if err := s.QueryNodeMetrics(&nodemetricspb.QueryNodeMetricsRequest{StartTime: since}, &fakeNodeMetricsStream{}); err != nil {
+		t.Fatalf("QueryNodeMetrics() error = %v", err)
+	}
+	if len(persist.queries) < 2 || len(persist.queries) > 3 {
+		t.Fatalf("queried %d days, want the 2 or 3 days of the retention period", len(persist.queries))
+	}
+	if oldest := time.Unix(0, persist.queries[0].StartTime); time.Since(oldest) > cfg.Retention+time.Minute {
+		t.Errorf("queried from %v, before the retention period", oldest)
+	}
+
+	err := s.QueryNodeMetrics(&nodemetricspb.QueryNodeMetricsRequest{EndTime: since}, &fakeNodeMetricsStream{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("range before the retention period: %v", err)
+	}
+
+	cfg.Enabled = false
+	s = NewNodeMetricsServiceServer(&authfakes.FakeGRPCAuthorization{}, cfg, persist, nil)
+	if err := s.QueryNodeMetrics(&nodemetricspb.QueryNodeMetricsRequest{}, &fakeNodeMetricsStream{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("disabled history: %v", err)
+	}
+}
+
+// fakeOrphanLister returns fixed orphan counts
+type fakeOrphanLister struct{ stats orphans.Stats }
+
+func (f fakeOrphanLister) OrphanedResources() orphans.Stats { return f.stats }
+
+func TestNodeMetricsService_GetOrphanedResources(t *testing.T) {
+	ctx := context.Background()
+	s := NewNodeMetricsServiceServer(&authfakes.FakeGRPCAuthorization{}, config.MonitoringHistoryConfig{}, nil, nil)
+	if _, err := s.GetOrphanedResources(ctx, &nodemetricspb.GetOrphanedResourcesRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("without a lister: %v", err)
+	}
+
+	var tracker orphans.Tracker
+	veth := orphans.Resource{Kind: orphans.KindVeth, Name: "veth-h-0b6c3a2e", Path: "/sys/class/net/veth-h-0b6c3a2e", JobID: "0b6c3a2e"}
+	pass := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	tracker.Record(pass, []orphans.Resource{{Kind: orphans.KindCgroup, JobID: "0b6c3a2e-9d41-4f8e-b5a7-2c1d0e9f8a76"}, veth}, []orphans.Resource{veth})
+
+	s = NewNodeMetricsServiceServer(&authfakes.FakeGRPCAuthorization{}, config.MonitoringHistoryConfig{}, nil, fakeOrphanLister{tracker.Stats()})
+	resp, err := s.GetOrphanedResources(ctx, &nodemetricspb.GetOrphanedResourcesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.LastPass != pass.UnixNano() || len(resp.Counts) != 3 {
+		t.Fatalf("response = %v", resp)
+	}
+	if cgroup := resp.Counts[0]; cgroup.Kind != "cgroup" || cgroup.Found != 1 || cgroup.Cleaned != 1 || cgroup.Failed != 0 {
+		t.Errorf("cgroup count = %v", cgroup)
+	}
+	if v := resp.Counts[1]; v.Kind != "veth" || v.Found != 1 || v.Cleaned != 0 || v.Failed != 1 {
+		t.Errorf("veth count = %v", v)
+	}
+	if len(resp.Remaining) != 1 || resp.Remaining[0].Name != veth.Name || resp.Remaining[0].JobId != veth.JobID {
+		t.Errorf("remaining = %v", resp.Remaining)
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/coordination"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NodesServiceServer lists the live nodes registered with the coordination
+// endpoint, so clients connected to any node can discover the others.
+// Heartbeats go to the coordination endpoint itself and are not accepted here.
+type NodesServiceServer struct {
+	nodespb.UnimplementedNodeRegistryServiceServer
+	auth      auth2.GRPCAuthorization
+	registrar coordination.Registrar
+	logger    *logger.Logger
+}
+
+// NewNodesServiceServer creates a new nodes service server
+func NewNodesServiceServer(auth auth2.GRPCAuthorization, registrar coordination.Registrar) *NodesServiceServer {
+	return &NodesServiceServer{
+		auth:      auth,
+		registrar: registrar,
+		logger:    logger.WithField("component", "nodes-grpc"),
+	}
+}
+
+// ListNodes returns the live nodes carrying all of the requested labels
+func (s *NodesServiceServer) ListNodes(ctx context.Context, req *nodespb.ListNodesRequest) (*nodespb.ListNodesResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListNodesOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ListNodes", "error", err)
+		return nil, err
+	}
+
+	nodes, err := s.registrar.ListNodes(ctx, req.Labels)
+	if err != nil {
+		s.logger.Error("failed to list nodes", "error", err)
+		return nil, status.Errorf(codes.Unavailable, "coordination endpoint unavailable: %v", err)
+	}
+	return &nodespb.ListNodesResponse{Nodes: nodes}, nil
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRegistrar struct {
+	nodes  []*nodespb.NodeInfo
+	err    error
+	labels map[string]string
+}
+
+func (f *fakeRegistrar) Heartbeat(context.Context, *nodespb.NodeInfo) error { return nil }
+
+func (f *fakeRegistrar) ListNodes(_ context.Context, labels map[string]string) ([]*nodespb.NodeInfo, error) {
+	f.labels = labels
+	return f.nodes, f.err
+}
+
+func (f *fakeRegistrar) Close() error { return nil }
+
+func TestNodesService_ListNodes(t *testing.T) {
+	registrar := &fakeRegistrar{nodes: []*nodespb.NodeInfo{{NodeId: "node-1"}, {NodeId: "node-2"}}}
+	s := NewNodesServiceServer(&authfakes.FakeGRPCAuthorization{}, registrar)
+
+	res, err := s.ListNodes(context.Background(), &nodespb.ListNodesRequest{Labels: map[string]string{"zone": "eu"}})
+	if err != nil || len(res.Nodes) != 2 {
+		t.Fatalf("ListNodes = %v, %v", res, err)
+	}
+	if registrar.labels["zone"] != "eu" {
+		t.Errorf("labels passed = %v", registrar.labels)
+	}
+
+	registrar.err = errors.New("connection refused")
+	if _, err := s.ListNodes(context.Background(), &nodespb.ListNodesRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("endpoint down: got %v, want Unavailable", err)
+	}
+}
+
+func TestNodesService_ListNodesUnauthorized(t *testing.T) {
+	authorization := &authfakes.FakeGRPCAuthorization{}
+	authorization.AuthorizedReturns(status.Error(codes.PermissionDenied, "denied"))
+	s := NewNodesServiceServer(authorization, &fakeRegistrar{})
+
+	if _, err := s.ListNodes(context.Background(), &nodespb.ListNodesRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// setRateLimitHeader reports the rate limiter's counters of allowed and
+// throttled calls in the GetSystemStatus response
+func (s *MonitoringServiceServer) setRateLimitHeader(ctx context.Context) {
+	if s.limiter == nil {
+		return
+	}
+	data, err := json.Marshal(s.limiter.Stats())
+	if err != nil {
+		return
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(constants.RateLimitStatusHeader, string(data))); err != nil {
+		s.logger.Warn("failed to set response header", "header", constants.RateLimitStatusHeader, "error", err)
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/ratelimit"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// headerStream records the headers a handler sets
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (h *headerStream) SetHeader(md metadata.MD) error {
+	h.header = metadata.Join(h.header, md)
+	return nil
+}
+
+func TestSetRateLimitHeader(t *testing.T) {
+	limiter := ratelimit.New(config.RateLimitConfig{Enabled: true, RequestsPerSecond: 1, Burst: 1, RunJobPerSecond: 1, RunJobBurst: 1})
+	if err := limiter.Allow("ci-runner", "/joblet.JobService/ListJobs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Allow("ci-runner", "/joblet.JobService/ListJobs"); err == nil {
+		t.Fatal("second call within the burst of 1 allowed")
+	}
+
+	s := &MonitoringServiceServer{logger: logger.New(), limiter: limiter}
+	stream := &headerStream{}
+	s.setRateLimitHeader(grpc.NewContextWithServerTransportStream(context.Background(), stream))
+
+	values := stream.header.Get(constants.RateLimitStatusHeader)
+	if len(values) != 1 {
+		t.Fatalf("header %s = %v, want one value", constants.RateLimitStatusHeader, values)
+	}
+	var stats ratelimit.Stats
+	if err := json.Unmarshal([]byte(values[0]), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Allowed != 1 || stats.Throttled != 1 || stats.ThrottledByClient["ci-runner"] != 1 {
+		t.Errorf("reported stats = %+v, want 1 allowed and 1 throttled for ci-runner", stats)
+	}
+
+	// Without rate limiting nothing is reported
+	stream = &headerStream{}
+	(&MonitoringServiceServer{logger: logger.New()}).setRateLimitHeader(grpc.NewContextWithServerTransportStream(context.Background(), stream))
+	if len(stream.header) != 0 {
+		t.Errorf("header set without a limiter: %v", stream.header)
+	}
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/registry"
+	registrypb "github.com/ehsaniara/joblet/internal/proto/gen/registry"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// registryScheduleInterval is how often scheduled workflows are checked; cron
+// schedules have minute resolution
+const registryScheduleInterval = 30 * time.Second
+
+// RegistryServiceServer implements the gRPC workflow registry service. Runs
+// are started through the workflow service, like 'rnx workflow run'.
+type RegistryServiceServer struct {
+	registrypb.UnimplementedWorkflowRegistryServiceServer
+	auth      auth2.GRPCAuthorization
+	registry  *registry.Registry
+	workflows *WorkflowServiceServer
+	logger    *logger.Logger
+}
+
+// NewRegistryServiceServer creates a new workflow registry service server
+func NewRegistryServiceServer(auth auth2.GRPCAuthorization, registry *registry.Registry, workflows *WorkflowServiceServer) *RegistryServiceServer {
+	return &RegistryServiceServer{
+		auth:      auth,
+		registry:  registry,
+		workflows: workflows,
+		logger:    logger.WithField("component", "workflow-registry"),
+	}
+}
+
+// RegisterWorkflow validates a workflow and stores it as the next version of
+// its name
+func (s *RegistryServiceServer) RegisterWorkflow(ctx context.Context, req *registrypb.RegisterWorkflowRequest) (*registrypb.RegisteredWorkflow, error) {
+	log := s.logger.WithFields("operation", "RegisterWorkflow", "name", req.Name)
+	if err := s.auth.Authorized(ctx, auth2.RegisterWorkflowOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if req.Name == "" || req.YamlContent == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and YAML content are required")
+	}
+
+	workflowYAML, err := s.workflows.parseWorkflowYAMLContent(req.YamlContent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := s.workflows.workflowValidator.ValidateWorkflow(s.workflows.withResolvedRuntimes(ctx, *workflowYAML)); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow validation failed: %v", err)
+	}
+
+	// Runs read the files from the registry, so every upload must be there now
+	files := make([]registry.File, 0, len(req.Files))
+	uploaded := make(map[string]bool, len(req.Files))
+	for _, f := range req.Files {
+		files = append(files, registry.File{Path: f.Path, Content: f.Content, Mode: f.Mode})
+		uploaded[f.Path] = true
+	}
+	sent := make([]string, 0, len(uploaded))
+	for path := range uploaded {
+		sent = append(sent, path)
+	}
+	for jobName, job := range workflowYAML.Jobs {
+		if _, err := workflow.UploadTargets(job.Uploads, sent); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "uploads of job %s were not sent with the workflow: %v", jobName, err)
+		}
+	}
+
+	signature, err := s.workflows.signatures.fromRequest(ctx, func(signedAt time.Time) []byte {
+		return jobsign.WorkflowDigest(req.YamlContent, signedAt)
+	})
+	if err != nil {
+		log.Warn("request signature rejected", "error", err)
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = workflowYAML.Description
+	}
+	entry, err := s.registry.Register(registry.Registration{
+		Name:        req.Name,
+		Description: description,
+		Schedule:    req.Schedule,
+		Tenant:      s.workflows.tenantOf(ctx),
+		Version: registry.Version{
+			YamlContent: req.YamlContent,
+			Files:       files,
+			CreatedBy:   auth2.ClientIdentity(ctx),
+			Signature:   signature,
+		},
+	}, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	log.Info("workflow registered", "version", entry.LatestVersion, "schedule", entry.Schedule, "by", entry.UpdatedBy)
+	return registeredWorkflowToProto(entry), nil
+}
+
+// ListRegisteredWorkflows lists the registered workflows by name
+func (s *RegistryServiceServer) ListRegisteredWorkflows(ctx context.Context, req *registrypb.ListRegisteredWorkflowsRequest) (*registrypb.ListRegisteredWorkflowsResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListRegisteredWorkflowsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ListRegisteredWorkflows", "error", err)
+		return nil, err
+	}
+
+	resp := &registrypb.ListRegisteredWorkflowsResponse{}
+	for _, entry := range s.registry.List() {
+		resp.Workflows = append(resp.Workflows, registeredWorkflowToProto(entry))
+	}
+	return resp, nil
+}
+
+// GetRegisteredWorkflow returns one version of a workflow with its YAML
+func (s *RegistryServiceServer) GetRegisteredWorkflow(ctx context.Context, req *registrypb.GetRegisteredWorkflowRequest) (*registrypb.RegisteredWorkflowVersion, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListRegisteredWorkflowsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetRegisteredWorkflow", "error", err)
+		return nil, err
+	}
+
+	version, err := s.registry.Version(req.Name, int(req.Version))
+	if err != nil {
+		return nil, registryError(err)
+	}
+	paths := make([]string, 0, len(version.Files))
+	for _, f := range version.Files {
+		paths = append(paths, f.Path)
+	}
+	return &registrypb.RegisteredWorkflowVersion{
+		Name:        req.Name,
+		Version:     int32(version.Version),
+		YamlContent: version.YamlContent,
+		FilePaths:   paths,
+		CreatedAt:   version.CreatedAt.Unix(),
+		CreatedBy:   version.CreatedBy,
+		Signed:      version.Signature != nil,
+	}, nil
+}
+
+// RemoveRegisteredWorkflow removes a workflow with all its versions. Runs
+// already started are not affected.
+func (s *RegistryServiceServer) RemoveRegisteredWorkflow(ctx context.Context, req *registrypb.RemoveRegisteredWorkflowRequest) (*registrypb.RemoveRegisteredWorkflowResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.RemoveWorkflowOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "RemoveRegisteredWorkflow", "error", err)
+		return nil, err
+	}
+
+	removed, err := s.registry.Remove(req.Name)
+	if err != nil {
+		return nil, registryError(err)
+	}
+	s.logger.Info("registered workflow removed", "name", req.Name, "versions", removed, "by", auth2.ClientIdentity(ctx))
+	return &registrypb.RemoveRegisteredWorkflowResponse{VersionsRemoved: int32(removed)}, nil
+}
+
+// StartRegisteredWorkflow starts a run of a registered workflow
+func (s *RegistryServiceServer) StartRegisteredWorkflow(ctx context.Context, req *registrypb.StartRegisteredWorkflowRequest) (*registrypb.StartRegisteredWorkflowResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.RunJobOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "StartRegisteredWorkflow", "error", err)
+		return nil, err
+	}
+
+	workflowUuid, version, err := s.start(ctx, req.Name, int(req.Version))
+	if err != nil {
+		return nil, err
+	}
+	return &registrypb.StartRegisteredWorkflowResponse{WorkflowUuid: workflowUuid, Version: int32(version)}, nil
+}
+
+// RunSchedules starts scheduled workflows when they are due, until ctx ends
+func (s *RegistryServiceServer) RunSchedules(ctx context.Context) {
+	ticker := time.NewTicker(registryScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, entry := range s.registry.Due(now) {
+				// Scheduled runs belong to the tenant of the client that registered the workflow
+				runCtx := withTenant(ctx, entry.Tenant)
+				workflowUuid, version, err := s.start(runCtx, entry.Name, 0)
+				if err != nil {
+					s.logger.Error("failed to start scheduled workflow", "name", entry.Name, "error", err)
+					continue
+				}
+				s.logger.Info("scheduled workflow started", "name", entry.Name, "version", version,
+					"workflowUuid", workflowUuid, "nextRun", entry.NextRun)
+			}
+		}
+	}
+}
+
+// start runs a version of a registered workflow, the latest for version 0
+func (s *RegistryServiceServer) start(ctx context.Context, name string, version int) (string, int, error) {
+	v, err := s.registry.Version(name, version)
+	if err != nil {
+		return "", 0, registryError(err)
+	}
+
+	// The signature was checked at registration; check it again in case
+	// signing became required or the key is no longer trusted
+	if v.Signature == nil && s.workflows.signatures.required {
+		return "", 0, status.Errorf(codes.FailedPrecondition, "%s version %d is unsigned and this server only accepts signed workflows, register it again", name, v.Version)
+	}
+	if v.Signature != nil {
+		if result := s.workflows.signatures.check(v.Signature, jobsign.WorkflowDigest(v.YamlContent, v.Signature.SignedAt)); result != signatureValid {
+			return "", 0, status.Errorf(codes.PermissionDenied, "signature of %s version %d is %s", name, v.Version, result)
+		}
+	}
+
+	files := make([]*pb.FileUpload, 0, len(v.Files))
+	for _, f := range v.Files {
+		files = append(files, &pb.FileUpload{Path: f.Path, Content: f.Content, Mode: f.Mode})
+	}
+	workflowUuid, err := s.workflows.StartWorkflowOrchestrationWithContent(ctx, v.YamlContent, files, v.Signature)
+	if err != nil {
+		return "", 0, status.Errorf(codes.Internal, "failed to start workflow orchestration: %v", err)
+	}
+
+	if err := s.registry.RecordRun(name, workflowUuid, time.Now()); err != nil {
+		s.logger.Warn("failed to record workflow run", "name", name, "workflowUuid", workflowUuid, "error", err)
+	}
+	return workflowUuid, v.Version, nil
+}
+
+// registryError maps registry errors to gRPC status errors
+func registryError(err error) error {
+	if errors.Is(err, registry.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, fmt.Sprintf("workflow registry: %v", err))
+}
+
+func registeredWorkflowToProto(entry registry.Entry) *registrypb.RegisteredWorkflow {
+	w := &registrypb.RegisteredWorkflow{
+		Name:          entry.Name,
+		Description:   entry.Description,
+		LatestVersion: int32(entry.LatestVersion),
+		Schedule:      entry.Schedule,
+		UpdatedAt:     entry.UpdatedAt.Unix(),
+		UpdatedBy:     entry.UpdatedBy,
+		LastRunUuid:   entry.LastRunUUID,
+	}
+	if !entry.NextRun.IsZero() {
+		w.NextRun = entry.NextRun.Unix()
+	}
+	if !entry.LastRunAt.IsZero() {
+		w.LastRunAt = entry.LastRunAt.Unix()
+	}
+	return w
+}
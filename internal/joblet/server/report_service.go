@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/report"
+	reportspb "github.com/ehsaniara/joblet/internal/proto/gen/reports"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReportServiceServer implements the gRPC service rendering workflow run
+// reports from the workflow service's state, job store and persist client
+type ReportServiceServer struct {
+	reportspb.UnimplementedWorkflowReportServiceServer
+	auth      auth2.GRPCAuthorization
+	workflows *WorkflowServiceServer
+	logger    *logger.Logger
+}
+
+// NewReportServiceServer creates a new workflow report service server
+func NewReportServiceServer(auth auth2.GRPCAuthorization, workflows *WorkflowServiceServer) *ReportServiceServer {
+	return &ReportServiceServer{
+		auth:      auth,
+		workflows: workflows,
+		logger:    logger.WithField("component", "workflow-reports"),
+	}
+}
+
+// GetWorkflowReport renders the report of a workflow run. It quotes the logs
+// of failed jobs, so it needs log access as well.
+func (s *ReportServiceServer) GetWorkflowReport(ctx context.Context, req *reportspb.WorkflowReportRequest) (*reportspb.WorkflowReport, error) {
+	log := s.logger.WithFields("operation", "GetWorkflowReport", "workflowUuid", req.WorkflowUuid, "format", req.Format)
+	for _, op := range []auth2.Operation{auth2.GetJobOp, auth2.GetJobLogsOp} {
+		if err := s.auth.Authorized(ctx, op); err != nil {
+			log.Warn("authorization failed", "error", err)
+			return nil, err
+		}
+	}
+
+	format := req.Format
+	if format == "" {
+		format = report.FormatHTML
+	}
+	if !slices.Contains(report.Formats, format) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown report format %q, expected %s", format, strings.Join(report.Formats, ", "))
+	}
+	if req.LogLines < 0 {
+		return nil, status.Error(codes.InvalidArgument, "log lines cannot be negative")
+	}
+
+	w := s.workflows
+	workflowID, found := w.lookupWorkflowID(req.WorkflowUuid)
+	if !found {
+		return nil, w.workflowLookupError(req.WorkflowUuid)
+	}
+	state, err := w.workflowManager.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "workflow not found: %v", err)
+	}
+	workflowUUID := w.getFullUuidForWorkflowID(workflowID)
+
+	src := report.Source{Jobs: w.jobStore.Job, Persist: w.persistClient}
+	r := report.Build(ctx, state, workflowUUID, src, int(req.LogLines), time.Now())
+	content, err := report.Render(r, format)
+	if err != nil {
+		log.Error("failed to render report", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to render report: %v", err)
+	}
+
+	log.Debug("workflow report rendered", "jobs", len(r.Jobs), "bytes", len(content))
+	return &reportspb.WorkflowReport{
+		WorkflowUuid: workflowUUID,
+		Format:       format,
+		Content:      content,
+		Status:       r.Status,
+	}, nil
+}
@@ -0,0 +1,412 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/internal/joblet/inputs"
+	"github.com/ehsaniara/joblet/internal/joblet/logsink"
+	"github.com/ehsaniara/joblet/internal/joblet/publish"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+// filesystemSizing holds the /dev/shm and /tmp sizes, the scratch device and
+// the freeze-on-failure flag requested for a job
+type filesystemSizing struct {
+	ShmSizeBytes int64
+	TmpSizeBytes int64
+	Scratch      string
+	FreezeFS     bool
+}
+
+// extractFilesystemSizing pulls the reserved JOBLET_SHM_SIZE, JOBLET_TMP_SIZE,
+// JOBLET_SCRATCH and JOBLET_FREEZE_FS keys out of the request environment. The
+// keys are removed so they never reach the job process. Zero values mean "use
+// the server default".
+func extractFilesystemSizing(env map[string]string) (filesystemSizing, error) {
+	var sizing filesystemSizing
+	var err error
+
+	if sizing.ShmSizeBytes, err = popSizeOption(env, constants.EnvShmSize); err != nil {
+		return filesystemSizing{}, err
+	}
+	if sizing.TmpSizeBytes, err = popSizeOption(env, constants.EnvTmpSize); err != nil {
+		return filesystemSizing{}, err
+	}
+	if scratch, exists := env[constants.EnvScratch]; exists {
+		delete(env, constants.EnvScratch)
+		sizing.Scratch = scratch
+	}
+	if value, exists := env[constants.EnvFreezeFS]; exists {
+		delete(env, constants.EnvFreezeFS)
+		if sizing.FreezeFS, err = strconv.ParseBool(value); err != nil {
+			return filesystemSizing{}, fmt.Errorf("invalid %s value %q: must be true or false", constants.EnvFreezeFS, value)
+		}
+	}
+
+	return sizing, nil
+}
+
+// popSizeOption removes key from env and parses its value as a byte count
+func popSizeOption(env map[string]string, key string) (int64, error) {
+	value, exists := env[key]
+	if !exists {
+		return 0, nil
+	}
+	delete(env, key)
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s value %q: must be a non-negative byte count", key, value)
+	}
+	return size, nil
+}
+
+// extractProfile removes the reserved JOBLET_PROFILE key from the request
+// environment and returns the requested profiler, empty when none.
+func extractProfile(env map[string]string) (string, error) {
+	tool, exists := env[constants.EnvProfile]
+	if !exists {
+		return "", nil
+	}
+	delete(env, constants.EnvProfile)
+
+	if !slices.Contains(constants.ProfileTools, tool) {
+		return "", fmt.Errorf("invalid %s value %q: must be one of %s", constants.EnvProfile, tool, strings.Join(constants.ProfileTools, ", "))
+	}
+	return tool, nil
+}
+
+// extractDedup removes the reserved JOBLET_DEDUP key from the request
+// environment and reports whether deduplication was requested.
+func extractDedup(env map[string]string) (bool, error) {
+	value, exists := env[constants.EnvDedup]
+	if !exists {
+		return false, nil
+	}
+	delete(env, constants.EnvDedup)
+
+	dedup, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value %q: must be true or false", constants.EnvDedup, value)
+	}
+	return dedup, nil
+}
+
+// extractGroup removes the reserved JOBLET_GROUP key from the request
+// environment and returns the group the job joins, empty when none.
+func extractGroup(env map[string]string) (string, error) {
+	name, exists := env[constants.EnvGroup]
+	if !exists {
+		return "", nil
+	}
+	delete(env, constants.EnvGroup)
+
+	group, err := values.NewGroupName(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s value %q: %w", constants.EnvGroup, name, err)
+	}
+	return group.Value(), nil
+}
+
+// extractLabels removes the reserved JOBLET_LABELS key from the request
+// environment and returns the job's labels, nil when none.
+func extractLabels(env map[string]string) (map[string]string, error) {
+	list, exists := env[constants.EnvLabels]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvLabels)
+
+	labels, err := values.ParseLabels(list)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %w", constants.EnvLabels, list, err)
+	}
+	return labels, nil
+}
+
+// extractAnnotations removes the reserved JOBLET_ANNOTATIONS key from the
+// request environment and returns the job's annotations, nil when none.
+func extractAnnotations(env map[string]string) (map[string]string, error) {
+	data, exists := env[constants.EnvAnnotations]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvAnnotations)
+
+	annotations, err := values.ParseAnnotations(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", constants.EnvAnnotations, err)
+	}
+	return annotations, nil
+}
+
+// extractLogSinks removes the reserved JOBLET_LOG_SINKS key from the request
+// environment and returns the sink URLs the job's output is copied to, nil
+// when none.
+func extractLogSinks(env map[string]string) ([]string, error) {
+	list, exists := env[constants.EnvLogSinks]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvLogSinks)
+
+	var sinks []string
+	for _, raw := range strings.Split(list, ",") {
+		target, err := logsink.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, target.String())
+	}
+	return sinks, nil
+}
+
+// extractCgroupParams removes the reserved JOBLET_CGROUP_PARAMS key from the
+// request environment and returns the raw cgroup files to set, nil when none.
+// Whether the node allows them is checked when the job is built.
+func extractCgroupParams(env map[string]string) (map[string]string, error) {
+	raw, exists := env[constants.EnvCgroupParams]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvCgroupParams)
+
+	var params map[string]string
+	if err := json.Unmarshal([]byte(raw), &params); err != nil {
+		return nil, fmt.Errorf("invalid %s value: must be a JSON object of cgroup files to values", constants.EnvCgroupParams)
+	}
+	for name, value := range params {
+		if err := values.ValidateCgroupParam(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// extractStdin removes the reserved JOBLET_STDIN key from the request
+// environment and returns the uploaded file delivered to the job's stdin,
+// empty when none. The file must be one of the request's uploads.
+func extractStdin(env map[string]string, uploads []*pb.FileUpload) (string, error) {
+	path, exists := env[constants.EnvStdin]
+	if !exists {
+		return "", nil
+	}
+	delete(env, constants.EnvStdin)
+
+	if path == "" || filepath.IsAbs(path) || filepath.Clean(path) != path || strings.HasPrefix(path, "..") {
+		return "", fmt.Errorf("invalid %s value %q: must be a relative upload path", constants.EnvStdin, path)
+	}
+	for _, upload := range uploads {
+		if upload.Path == path && !upload.IsDirectory {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s value %q: no such file among the uploads", constants.EnvStdin, path)
+}
+
+// cacheOptions holds the result cache options of a request
+type cacheOptions struct {
+	TTL     time.Duration // Reuse results this fresh, 0 = caching off
+	NoCache bool          // Skip the lookup but record the new result
+}
+
+// extractCacheOptions removes the reserved JOBLET_CACHE_TTL and
+// JOBLET_NO_CACHE keys from the request environment.
+func extractCacheOptions(env map[string]string) (cacheOptions, error) {
+	var opts cacheOptions
+	if value, exists := env[constants.EnvCacheTTL]; exists {
+		delete(env, constants.EnvCacheTTL)
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return cacheOptions{}, fmt.Errorf("invalid %s value %q: must be a positive duration such as 24h", constants.EnvCacheTTL, value)
+		}
+		opts.TTL = ttl
+	}
+	if value, exists := env[constants.EnvNoCache]; exists {
+		delete(env, constants.EnvNoCache)
+		noCache, err := strconv.ParseBool(value)
+		if err != nil {
+			return cacheOptions{}, fmt.Errorf("invalid %s value %q: must be true or false", constants.EnvNoCache, value)
+		}
+		opts.NoCache = noCache
+	}
+	return opts, nil
+}
+
+// extractArray removes the reserved JOBLET_ARRAY key from the request
+// environment and returns the indices of the job array to start, nil when
+// the request is for a single job.
+func extractArray(env map[string]string) ([]int, error) {
+	spec, exists := env[constants.EnvArray]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvArray)
+
+	indices, err := values.ParseArrayIndices(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %w", constants.EnvArray, spec, err)
+	}
+	return indices, nil
+}
+
+// extractQueueTTL removes the reserved JOBLET_QUEUE_TTL key from the request
+// environment and returns how long the job may wait to start, 0 for no limit.
+func extractQueueTTL(env map[string]string) (time.Duration, error) {
+	value, exists := env[constants.EnvQueueTTL]
+	if !exists {
+		return 0, nil
+	}
+	delete(env, constants.EnvQueueTTL)
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid %s value %q: must be a positive duration such as 2h", constants.EnvQueueTTL, value)
+	}
+	return ttl, nil
+}
+
+// extractInputs removes the reserved JOBLET_INPUTS key from the request
+// environment and returns the job's inputs in canonical form, nil when none.
+// Whether the node fetches inputs is checked when the job is built.
+func extractInputs(env map[string]string) ([]string, error) {
+	list, exists := env[constants.EnvInputs]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvInputs)
+	return parseInputs(strings.Split(list, "\n"))
+}
+
+// parseInputs validates inputs and returns them in canonical form
+func parseInputs(raw []string) ([]string, error) {
+	var list []string
+	for _, spec := range raw {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		in, err := inputs.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, in.String())
+	}
+	return list, nil
+}
+
+// extractOutputTargets removes the reserved JOBLET_OUTPUT_TARGETS key from the
+// request environment and returns the job's output targets in canonical form,
+// nil when none. Whether the node publishes outputs is checked when the job is
+// built.
+func extractOutputTargets(env map[string]string) ([]string, error) {
+	list, exists := env[constants.EnvOutputTargets]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvOutputTargets)
+	return parseOutputTargets(strings.Split(list, "\n"))
+}
+
+// parseOutputTargets validates output targets and returns them in canonical
+// form
+func parseOutputTargets(raw []string) ([]string, error) {
+	var list []string
+	for _, spec := range raw {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		target, err := publish.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, target.String())
+	}
+	return list, nil
+}
+
+// extractEgress removes the reserved JOBLET_EGRESS key from the request
+// environment and returns the job's egress policy as canonical rules, nil
+// when none. Whether the job's network can enforce it is checked when the job
+// is built.
+func extractEgress(env map[string]string) ([]string, error) {
+	policy, exists := env[constants.EnvEgress]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvEgress)
+	return parseEgress([]string{policy})
+}
+
+// extractSecretFiles removes the reserved JOBLET_SECRET_FILES key from the
+// request environment and returns the files the job's secret variables are
+// delivered as, nil when none
+func extractSecretFiles(env, secretEnv map[string]string) (map[string]string, error) {
+	spec, exists := env[constants.EnvSecretFiles]
+	if !exists {
+		return nil, nil
+	}
+	delete(env, constants.EnvSecretFiles)
+	files, err := values.ParseSecretFiles(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSecretFiles(files, secretEnv); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// checkSecretFiles checks that every secret file names a secret variable of
+// the job
+func checkSecretFiles(files, secretEnv map[string]string) error {
+	for filePath, key := range files {
+		if err := values.ValidateSecretFile(filePath, key); err != nil {
+			return err
+		}
+		if _, exists := secretEnv[key]; !exists {
+			return fmt.Errorf("secret file %s names %s, which is not a secret environment variable of the job", filePath, key)
+		}
+	}
+	return nil
+}
+
+// parseEgress validates egress policies, each a comma-separated list of
+// rules, and returns their rules in canonical form
+func parseEgress(policies []string) ([]string, error) {
+	var list []string
+	for _, policy := range policies {
+		rules, err := values.ParseEgressPolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			list = append(list, rule.String())
+		}
+	}
+	return list, nil
+}
+
+// extractIPC removes the reserved JOBLET_IPC key from the request environment
+// and returns the IPC channel the job joins. The server configuration decides
+// whether the job may join it.
+func extractIPC(env map[string]string) (string, error) {
+	name, exists := env[constants.EnvIPC]
+	if !exists {
+		return "", nil
+	}
+	delete(env, constants.EnvIPC)
+
+	if name == "" {
+		return "", fmt.Errorf("invalid %s value: the channel name must not be empty", constants.EnvIPC)
+	}
+	return name, nil
+}
@@ -0,0 +1,384 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+func TestExtractFilesystemSizing(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    filesystemSizing
+		expectError bool
+	}{
+		{
+			name:     "no sizing keys",
+			env:      map[string]string{"FOO": "bar"},
+			expected: filesystemSizing{},
+		},
+		{
+			name: "shm and tmp sizes",
+			env: map[string]string{
+				constants.EnvShmSize: "2147483648",
+				constants.EnvTmpSize: "536870912",
+				"FOO":                "bar",
+			},
+			expected: filesystemSizing{ShmSizeBytes: 2147483648, TmpSizeBytes: 536870912},
+		},
+		{
+			name:     "scratch device",
+			env:      map[string]string{constants.EnvScratch: "nvme", constants.EnvTmpSize: "1024"},
+			expected: filesystemSizing{TmpSizeBytes: 1024, Scratch: "nvme"},
+		},
+		{
+			name:     "freeze on failure",
+			env:      map[string]string{constants.EnvFreezeFS: "true"},
+			expected: filesystemSizing{FreezeFS: true},
+		},
+		{
+			name:        "invalid freeze flag",
+			env:         map[string]string{constants.EnvFreezeFS: "sometimes"},
+			expectError: true,
+		},
+		{
+			name:        "invalid size",
+			env:         map[string]string{constants.EnvShmSize: "2g"},
+			expectError: true,
+		},
+		{
+			name:        "negative size",
+			env:         map[string]string{constants.EnvTmpSize: "-1"},
+			expectError: true,
+		},
+		{
+			name:     "nil environment",
+			env:      nil,
+			expected: filesystemSizing{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizing, err := extractFilesystemSizing(tt.env)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sizing != tt.expected {
+				t.Errorf("sizing = %+v, want %+v", sizing, tt.expected)
+			}
+			if _, exists := tt.env[constants.EnvShmSize]; exists {
+				t.Errorf("%s was not stripped from environment", constants.EnvShmSize)
+			}
+			if _, exists := tt.env[constants.EnvTmpSize]; exists {
+				t.Errorf("%s was not stripped from environment", constants.EnvTmpSize)
+			}
+			if _, exists := tt.env[constants.EnvScratch]; exists {
+				t.Errorf("%s was not stripped from environment", constants.EnvScratch)
+			}
+			if _, exists := tt.env[constants.EnvFreezeFS]; exists {
+				t.Errorf("%s was not stripped from environment", constants.EnvFreezeFS)
+			}
+		})
+	}
+}
+
+func TestExtractProfile(t *testing.T) {
+	env := map[string]string{constants.EnvProfile: "strace", "FOO": "bar"}
+	tool, err := extractProfile(env)
+	if err != nil || tool != constants.ProfileStrace {
+		t.Fatalf("extractProfile = %q, %v", tool, err)
+	}
+	if _, exists := env[constants.EnvProfile]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvProfile)
+	}
+
+	if tool, err := extractProfile(map[string]string{"FOO": "bar"}); err != nil || tool != "" {
+		t.Errorf("no profile key: got %q, %v", tool, err)
+	}
+	if _, err := extractProfile(map[string]string{constants.EnvProfile: "gdb"}); err == nil {
+		t.Error("expected error for unsupported profiler")
+	}
+}
+
+func TestExtractDedup(t *testing.T) {
+	env := map[string]string{constants.EnvDedup: "true", "FOO": "bar"}
+	dedup, err := extractDedup(env)
+	if err != nil || !dedup {
+		t.Fatalf("extractDedup = %v, %v", dedup, err)
+	}
+	if _, exists := env[constants.EnvDedup]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvDedup)
+	}
+
+	if dedup, err := extractDedup(map[string]string{"FOO": "bar"}); err != nil || dedup {
+		t.Errorf("no dedup key: got %v, %v", dedup, err)
+	}
+	if _, err := extractDedup(map[string]string{constants.EnvDedup: "maybe"}); err == nil {
+		t.Error("expected error for invalid dedup value")
+	}
+}
+
+func TestExtractGroup(t *testing.T) {
+	env := map[string]string{constants.EnvGroup: "load-test-2024", "FOO": "bar"}
+	group, err := extractGroup(env)
+	if err != nil || group != "load-test-2024" {
+		t.Fatalf("extractGroup = %q, %v", group, err)
+	}
+	if _, exists := env[constants.EnvGroup]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvGroup)
+	}
+
+	if group, err := extractGroup(map[string]string{"FOO": "bar"}); err != nil || group != "" {
+		t.Errorf("no group key: got %q, %v", group, err)
+	}
+	for _, value := range []string{"", "-leading-dash", "has space", "a/b"} {
+		if _, err := extractGroup(map[string]string{constants.EnvGroup: value}); err == nil {
+			t.Errorf("expected error for group %q", value)
+		}
+	}
+}
+
+func TestExtractArray(t *testing.T) {
+	env := map[string]string{constants.EnvArray: "0-2,5", "FOO": "bar"}
+	indices, err := extractArray(env)
+	if err != nil || !reflect.DeepEqual(indices, []int{0, 1, 2, 5}) {
+		t.Fatalf("extractArray = %v, %v", indices, err)
+	}
+	if _, exists := env[constants.EnvArray]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvArray)
+	}
+
+	if indices, err := extractArray(map[string]string{"FOO": "bar"}); err != nil || indices != nil {
+		t.Errorf("no array key: got %v, %v", indices, err)
+	}
+	if _, err := extractArray(map[string]string{constants.EnvArray: "9-0"}); err == nil {
+		t.Error("expected error for invalid array spec")
+	}
+}
+
+func TestExtractCacheOptions(t *testing.T) {
+	env := map[string]string{constants.EnvCacheTTL: "24h", constants.EnvNoCache: "true", "FOO": "bar"}
+	opts, err := extractCacheOptions(env)
+	if err != nil || opts.TTL != 24*time.Hour || !opts.NoCache {
+		t.Fatalf("extractCacheOptions = %+v, %v", opts, err)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved keys were not stripped: %v", env)
+	}
+
+	for _, value := range []string{"0s", "-1h", "tomorrow"} {
+		if _, err := extractCacheOptions(map[string]string{constants.EnvCacheTTL: value}); err == nil {
+			t.Errorf("expected error for cache TTL %q", value)
+		}
+	}
+}
+
+func TestExtractQueueTTL(t *testing.T) {
+	env := map[string]string{constants.EnvQueueTTL: "2h", "FOO": "bar"}
+	ttl, err := extractQueueTTL(env)
+	if err != nil || ttl != 2*time.Hour {
+		t.Fatalf("extractQueueTTL = %v, %v", ttl, err)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved key was not stripped: %v", env)
+	}
+
+	for _, value := range []string{"0s", "-1h", "later"} {
+		if _, err := extractQueueTTL(map[string]string{constants.EnvQueueTTL: value}); err == nil {
+			t.Errorf("expected error for queue TTL %q", value)
+		}
+	}
+}
+
+func TestExtractIPC(t *testing.T) {
+	env := map[string]string{constants.EnvIPC: "arrow", "FOO": "bar"}
+	channel, err := extractIPC(env)
+	if err != nil || channel != "arrow" {
+		t.Fatalf("extractIPC = %q, %v", channel, err)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved key was not stripped: %v", env)
+	}
+	if _, err := extractIPC(map[string]string{constants.EnvIPC: ""}); err == nil {
+		t.Error("expected error for an empty channel name")
+	}
+}
+
+func TestExtractInputs(t *testing.T) {
+	env := map[string]string{constants.EnvInputs: "s3://data/train.csv\ngs://models/v1.bin:/work/models/", "FOO": "bar"}
+	list, err := extractInputs(env)
+	want := []string{"s3://data/train.csv:/work/train.csv", "gs://models/v1.bin:/work/models/v1.bin"}
+	if err != nil || !reflect.DeepEqual(list, want) {
+		t.Fatalf("extractInputs = %v, %v, want %v", list, err, want)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved key was not stripped: %v", env)
+	}
+	if _, err := extractInputs(map[string]string{constants.EnvInputs: "s3://data/x.bin:/etc/x.bin"}); err == nil {
+		t.Error("expected error for a target outside /work")
+	}
+}
+
+func TestExtractOutputTargets(t *testing.T) {
+	env := map[string]string{constants.EnvOutputTargets: "out/*.parquet:s3://results/${JOB_UUID}\n/work:gs://archive/", "FOO": "bar"}
+	list, err := extractOutputTargets(env)
+	want := []string{"/work/out/*.parquet:s3://results/${JOB_UUID}/", "/work:gs://archive/"}
+	if err != nil || !reflect.DeepEqual(list, want) {
+		t.Fatalf("extractOutputTargets = %v, %v, want %v", list, err, want)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved key was not stripped: %v", env)
+	}
+	if _, err := extractOutputTargets(map[string]string{constants.EnvOutputTargets: "/tmp/*:s3://results/"}); err == nil {
+		t.Error("expected error for a pattern outside /work")
+	}
+}
+
+func TestExtractEgress(t *testing.T) {
+	env := map[string]string{constants.EnvEgress: "allow:API.internal:443, allow:10.1.2.3/8,deny:*", "FOO": "bar"}
+	rules, err := extractEgress(env)
+	want := []string{"allow:api.internal:443", "allow:10.0.0.0/8", "deny:*"}
+	if err != nil || !reflect.DeepEqual(rules, want) {
+		t.Fatalf("extractEgress = %v, %v, want %v", rules, err, want)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved key was not stripped: %v", env)
+	}
+	if rules, err := extractEgress(map[string]string{}); rules != nil || err != nil {
+		t.Errorf("extractEgress without the key = %v, %v", rules, err)
+	}
+	if _, err := extractEgress(map[string]string{constants.EnvEgress: "permit:api.internal"}); err == nil {
+		t.Error("expected error for an unknown action")
+	}
+}
+
+func TestExtractSecretFiles(t *testing.T) {
+	secretEnv := map[string]string{"AWS_CREDENTIALS": "[default]"}
+	env := map[string]string{constants.EnvSecretFiles: "/root/.aws/credentials=AWS_CREDENTIALS", "FOO": "bar"}
+	files, err := extractSecretFiles(env, secretEnv)
+	if err != nil || !reflect.DeepEqual(files, map[string]string{"/root/.aws/credentials": "AWS_CREDENTIALS"}) {
+		t.Fatalf("extractSecretFiles = %v, %v", files, err)
+	}
+	if len(env) != 1 {
+		t.Errorf("reserved key was not stripped: %v", env)
+	}
+	if files, err := extractSecretFiles(map[string]string{}, secretEnv); files != nil || err != nil {
+		t.Errorf("extractSecretFiles without the key = %v, %v", files, err)
+	}
+	if _, err := extractSecretFiles(map[string]string{constants.EnvSecretFiles: "/run/token=API_TOKEN"}, secretEnv); err == nil {
+		t.Error("expected error for a variable that is not a secret of the job")
+	}
+	if _, err := extractSecretFiles(map[string]string{constants.EnvSecretFiles: "/proc/token=AWS_CREDENTIALS"}, secretEnv); err == nil {
+		t.Error("expected error for a path under /proc")
+	}
+}
+
+func TestExtractLabels(t *testing.T) {
+	env := map[string]string{constants.EnvLabels: "env=staging,team=ml", "FOO": "bar"}
+	labels, err := extractLabels(env)
+	if err != nil || labels["env"] != "staging" || labels["team"] != "ml" || len(labels) != 2 {
+		t.Fatalf("extractLabels = %v, %v", labels, err)
+	}
+	if _, exists := env[constants.EnvLabels]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvLabels)
+	}
+
+	if labels, err := extractLabels(map[string]string{"FOO": "bar"}); err != nil || labels != nil {
+		t.Errorf("no labels key: got %v, %v", labels, err)
+	}
+	if _, err := extractLabels(map[string]string{constants.EnvLabels: "env"}); err == nil {
+		t.Error("expected error for a label without a value")
+	}
+}
+
+func TestExtractAnnotations(t *testing.T) {
+	env := map[string]string{constants.EnvAnnotations: `{"ci.run_id":"42","ci.url":"https://ci.example.com/runs/42"}`, "FOO": "bar"}
+	annotations, err := extractAnnotations(env)
+	if err != nil || annotations["ci.run_id"] != "42" || annotations["ci.url"] != "https://ci.example.com/runs/42" {
+		t.Fatalf("extractAnnotations = %v, %v", annotations, err)
+	}
+	if _, exists := env[constants.EnvAnnotations]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvAnnotations)
+	}
+
+	if annotations, err := extractAnnotations(map[string]string{"FOO": "bar"}); err != nil || annotations != nil {
+		t.Errorf("no annotations key: got %v, %v", annotations, err)
+	}
+	if _, err := extractAnnotations(map[string]string{constants.EnvAnnotations: "ci.run_id=42"}); err == nil {
+		t.Error("expected error for annotations that are not a JSON object")
+	}
+}
+
+func TestExtractLogSinks(t *testing.T) {
+	env := map[string]string{constants.EnvLogSinks: "s3://logs/team-a/,syslog://collector", "FOO": "bar"}
+	sinks, err := extractLogSinks(env)
+	if err != nil || len(sinks) != 2 || sinks[0] != "s3://logs/team-a/" || sinks[1] != "syslog://collector:514" {
+		t.Fatalf("extractLogSinks = %v, %v", sinks, err)
+	}
+	if _, exists := env[constants.EnvLogSinks]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvLogSinks)
+	}
+
+	if sinks, err := extractLogSinks(map[string]string{"FOO": "bar"}); err != nil || sinks != nil {
+		t.Errorf("no log sinks key: got %v, %v", sinks, err)
+	}
+	for _, value := range []string{"", "http://logs/", "s3://logs/,"} {
+		if _, err := extractLogSinks(map[string]string{constants.EnvLogSinks: value}); err == nil {
+			t.Errorf("expected error for log sinks %q", value)
+		}
+	}
+}
+
+func TestExtractCgroupParams(t *testing.T) {
+	env := map[string]string{constants.EnvCgroupParams: `{"cpu.weight":"200","io.latency":"8:0 target=10"}`, "FOO": "bar"}
+	params, err := extractCgroupParams(env)
+	if err != nil || len(params) != 2 || params["cpu.weight"] != "200" || params["io.latency"] != "8:0 target=10" {
+		t.Fatalf("extractCgroupParams = %v, %v", params, err)
+	}
+	if _, exists := env[constants.EnvCgroupParams]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvCgroupParams)
+	}
+
+	if params, err := extractCgroupParams(map[string]string{"FOO": "bar"}); err != nil || params != nil {
+		t.Errorf("no cgroup params key: got %v, %v", params, err)
+	}
+	for _, value := range []string{"cpu.weight=200", `{"cpu.weight":200}`, `{"cgroup.procs":"1"}`, `{"cpu.weight":""}`} {
+		if _, err := extractCgroupParams(map[string]string{constants.EnvCgroupParams: value}); err == nil {
+			t.Errorf("expected error for cgroup params %s", value)
+		}
+	}
+}
+
+func TestExtractStdin(t *testing.T) {
+	uploads := []*pb.FileUpload{{Path: "data"}, {Path: ".joblet-stdin", Content: []byte("a,b\n")}}
+	env := map[string]string{constants.EnvStdin: ".joblet-stdin", "FOO": "bar"}
+	path, err := extractStdin(env, uploads)
+	if err != nil || path != ".joblet-stdin" {
+		t.Fatalf("extractStdin = %q, %v", path, err)
+	}
+	if _, exists := env[constants.EnvStdin]; exists {
+		t.Errorf("%s was not stripped from environment", constants.EnvStdin)
+	}
+
+	if path, err := extractStdin(map[string]string{"FOO": "bar"}, uploads); err != nil || path != "" {
+		t.Errorf("no stdin key: got %q, %v", path, err)
+	}
+	uploads[0].IsDirectory = true
+	for _, value := range []string{"", "/etc/passwd", "../.joblet-stdin", "./.joblet-stdin", "missing", "data"} {
+		if _, err := extractStdin(map[string]string{constants.EnvStdin: value}, uploads); err == nil {
+			t.Errorf("expected error for stdin %q", value)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// resultCacheSweepInterval is how often record drops entries whose result
+// expired or can no longer be replayed
+const resultCacheSweepInterval = time.Minute
+
+// resultCache remembers the latest job started for each request hash with a
+// cache TTL, so an identical submission can be answered with that job's
+// result while it is fresh. The jobs themselves stay in the job store, which
+// keeps serving their logs and exit code.
+type resultCache struct {
+	mu        sync.Mutex
+	entries   map[string]resultCacheEntry
+	lastSweep time.Time
+}
+
+type resultCacheEntry struct {
+	jobID string
+	ttl   time.Duration
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{entries: make(map[string]resultCacheEntry)}
+}
+
+// record makes jobID the cached result for hash once it completes, kept for
+// ttl after the job ends. Entries that expired in the meantime are swept at
+// most once per resultCacheSweepInterval.
+func (c *resultCache) record(hash, jobID string, ttl time.Duration, now time.Time, job func(jobID string) (*domain.Job, bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[hash] = resultCacheEntry{jobID: jobID, ttl: ttl}
+
+	if now.Sub(c.lastSweep) < resultCacheSweepInterval {
+		return
+	}
+	c.lastSweep = now
+	for key, entry := range c.entries {
+		if key == hash {
+			continue
+		}
+		if cached, exists := job(entry.jobID); resultExpired(cached, exists, entry.ttl, now) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// lookup returns the job cached for hash if it completed successfully no
+// longer than ttl before now. Entries whose job failed, is gone or expired
+// are dropped.
+func (c *resultCache) lookup(hash string, ttl time.Duration, now time.Time, job func(jobID string) (*domain.Job, bool)) *domain.Job {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[hash]
+	if !exists {
+		return nil
+	}
+	cached, exists := job(entry.jobID)
+	if resultExpired(cached, exists, ttl, now) {
+		delete(c.entries, hash)
+		return nil
+	}
+	if cached.Status != domain.StatusCompleted || cached.EndTime == nil {
+		return nil
+	}
+	return cached
+}
+
+// resultExpired reports whether a cached job can no longer be replayed: it is
+// gone, ended without success, or completed longer than ttl before now
+func resultExpired(job *domain.Job, exists bool, ttl time.Duration, now time.Time) bool {
+	if !exists {
+		return true
+	}
+	switch job.Status {
+	case domain.StatusFailed, domain.StatusStopped, domain.StatusCanceled, domain.StatusExpired:
+		return true
+	case domain.StatusCompleted:
+		return job.EndTime != nil && now.Sub(*job.EndTime) > ttl
+	}
+	return false
+}
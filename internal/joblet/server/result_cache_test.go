@@ -0,0 +1,91 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+func TestResultCacheLookup(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	endedAt := func(ago time.Duration) *time.Time {
+		end := now.Add(-ago)
+		return &end
+	}
+	jobs := map[string]*domain.Job{
+		"fresh":   {Uuid: "fresh", Status: domain.StatusCompleted, EndTime: endedAt(time.Hour)},
+		"stale":   {Uuid: "stale", Status: domain.StatusCompleted, EndTime: endedAt(48 * time.Hour)},
+		"running": {Uuid: "running", Status: domain.StatusRunning},
+		"failed":  {Uuid: "failed", Status: domain.StatusFailed, EndTime: endedAt(time.Hour)},
+	}
+	lookupJob := func(jobID string) (*domain.Job, bool) {
+		job, exists := jobs[jobID]
+		return job, exists
+	}
+
+	c := newResultCache()
+	for hash, jobID := range map[string]string{"h1": "fresh", "h2": "stale", "h3": "running", "h4": "failed", "h5": "deleted"} {
+		c.record(hash, jobID, 24*time.Hour, now, lookupJob)
+	}
+
+	if got := c.lookup("h1", 24*time.Hour, now, lookupJob); got == nil || got.Uuid != "fresh" {
+		t.Errorf("fresh result: got %v", got)
+	}
+	if got := c.lookup("h1", 30*time.Minute, now, lookupJob); got != nil {
+		t.Error("result older than the TTL must not be returned")
+	}
+	if _, exists := c.entries["h1"]; exists {
+		t.Error("expired result was not dropped")
+	}
+	if got := c.lookup("h2", 24*time.Hour, now, lookupJob); got != nil {
+		t.Error("stale result must not be returned")
+	}
+	if got := c.lookup("h3", 24*time.Hour, now, lookupJob); got != nil {
+		t.Error("running job has no result yet")
+	}
+	if got := c.lookup("missing", 24*time.Hour, now, lookupJob); got != nil {
+		t.Error("unknown hash must not return a job")
+	}
+
+	// Stale, failed and deleted jobs are never replayed and are forgotten
+	for _, hash := range []string{"h2", "h4", "h5"} {
+		if got := c.lookup(hash, 24*time.Hour, now, lookupJob); got != nil {
+			t.Errorf("%s: got %v", hash, got)
+		}
+		if _, exists := c.entries[hash]; exists {
+			t.Errorf("%s was not dropped", hash)
+		}
+	}
+	if _, exists := c.entries["h3"]; !exists {
+		t.Error("running job entry must be kept")
+	}
+}
+
+func TestResultCacheSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ended := now.Add(-2 * time.Hour)
+	jobs := map[string]*domain.Job{
+		"old":     {Uuid: "old", Status: domain.StatusCompleted, EndTime: &ended},
+		"running": {Uuid: "running", Status: domain.StatusRunning},
+	}
+	lookupJob := func(jobID string) (*domain.Job, bool) {
+		job, exists := jobs[jobID]
+		return job, exists
+	}
+
+	c := newResultCache()
+	c.record("h1", "old", time.Hour, now.Add(-3*time.Hour), lookupJob)
+	c.record("h2", "running", time.Hour, now.Add(-3*time.Hour), lookupJob)
+
+	// Recording a new entry sweeps expired ones without any lookup
+	c.record("h3", "new", time.Hour, now, lookupJob)
+	if _, exists := c.entries["h1"]; exists {
+		t.Error("expired entry was not swept")
+	}
+	for _, hash := range []string{"h2", "h3"} {
+		if _, exists := c.entries[hash]; !exists {
+			t.Errorf("%s must be kept", hash)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+)
+
+// resolveRuntime sets the job's runtime to the exact runtime spec stands for
+// with the caller's tenant pins and the server's aliases, keeping spec as the
+// requested runtime when it was an alias
+func (s *WorkflowServiceServer) resolveRuntime(ctx context.Context, req *interfaces.StartJobRequest, spec string) {
+	req.Runtime = spec
+	if s.runtimes == nil || spec == "" {
+		return
+	}
+	resolved, pinnedBy := s.runtimes(s.tenantOf(ctx), spec)
+	if pinnedBy == "" {
+		return
+	}
+	req.Runtime = resolved
+	req.RequestedRuntime = spec
+	req.RuntimePinnedBy = pinnedBy
+}
+
+// withResolvedRuntimes returns a copy of the workflow whose jobs name the
+// runtimes their aliases resolve to, for validation
+func (s *WorkflowServiceServer) withResolvedRuntimes(ctx context.Context, wf WorkflowYAML) WorkflowYAML {
+	if s.runtimes == nil {
+		return wf
+	}
+	tenant := s.tenantOf(ctx)
+	jobs := make(map[string]JobSpec, len(wf.Jobs))
+	for name, job := range wf.Jobs {
+		job.Runtime, _ = s.runtimes(tenant, job.Runtime)
+		jobs[name] = job
+	}
+	wf.Jobs = jobs
+	return wf
+}
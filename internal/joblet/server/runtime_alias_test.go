@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestResolveRuntimeAliases(t *testing.T) {
+	cfg := &config.Config{
+		Runtime: config.RuntimeConfig{Aliases: map[string]string{"python": "python-3.11@1.2.0"}},
+		Tenants: []config.TenantConfig{{Name: "ml", RuntimePins: map[string]string{"python": "python-3.11-ml@2.0.0"}}},
+	}
+	s := &WorkflowServiceServer{logger: logger.New(), runtimes: cfg.ResolveRuntime}
+
+	var req interfaces.StartJobRequest
+	s.resolveRuntime(context.Background(), &req, "python")
+	if req.Runtime != "python-3.11@1.2.0" || req.RequestedRuntime != "python" || req.RuntimePinnedBy != "server alias" {
+		t.Errorf("server alias: got %q from %q by %q", req.Runtime, req.RequestedRuntime, req.RuntimePinnedBy)
+	}
+
+	req = interfaces.StartJobRequest{}
+	s.resolveRuntime(withTenant(context.Background(), "ml"), &req, "python")
+	if req.Runtime != "python-3.11-ml@2.0.0" || req.RuntimePinnedBy != "tenant ml pin" {
+		t.Errorf("tenant pin: got %q by %q", req.Runtime, req.RuntimePinnedBy)
+	}
+
+	req = interfaces.StartJobRequest{}
+	s.resolveRuntime(context.Background(), &req, "openjdk-21")
+	if req.Runtime != "openjdk-21" || req.RequestedRuntime != "" {
+		t.Errorf("exact runtime: got %q from %q", req.Runtime, req.RequestedRuntime)
+	}
+
+	wf := WorkflowYAML{Jobs: map[string]types.JobSpec{"train": {Runtime: "python"}, "report": {Runtime: "openjdk-21"}}}
+	resolved := s.withResolvedRuntimes(withTenant(context.Background(), "ml"), wf)
+	if resolved.Jobs["train"].Runtime != "python-3.11-ml@2.0.0" || resolved.Jobs["report"].Runtime != "openjdk-21" {
+		t.Errorf("withResolvedRuntimes() = %+v", resolved.Jobs)
+	}
+	if wf.Jobs["train"].Runtime != "python" {
+		t.Error("withResolvedRuntimes() changed the original workflow")
+	}
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime/channels"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RuntimeChannelServiceServer implements the gRPC service moving the stable
+// and canary channels of runtime names
+type RuntimeChannelServiceServer struct {
+	runtimespb.UnimplementedRuntimeChannelServiceServer
+	auth     auth2.GRPCAuthorization
+	channels *channels.Store
+	resolver *runtime.Resolver
+	jobStore adapters.JobStorer
+	config   *config.Config
+	logger   *logger.Logger
+}
+
+// NewRuntimeChannelServiceServer creates a new runtime channel service server
+func NewRuntimeChannelServiceServer(auth auth2.GRPCAuthorization, store *channels.Store, resolver *runtime.Resolver, jobStore adapters.JobStorer, cfg *config.Config) *RuntimeChannelServiceServer {
+	return &RuntimeChannelServiceServer{
+		auth:     auth,
+		channels: store,
+		resolver: resolver,
+		jobStore: jobStore,
+		config:   cfg,
+		logger:   logger.WithField("component", "runtime-channels"),
+	}
+}
+
+// ListRuntimeChannels lists the channels of every runtime name with pins,
+// comparing the canaries against stable
+func (s *RuntimeChannelServiceServer) ListRuntimeChannels(ctx context.Context, _ *runtimespb.ListRuntimeChannelsRequest) (*runtimespb.ListRuntimeChannelsResponse, error) {
+	if err := s.auth.Authorized(ctx, auth2.ListRuntimeChannelsOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ListRuntimeChannels", "error", err)
+		return nil, err
+	}
+	resp := &runtimespb.ListRuntimeChannelsResponse{}
+	for _, pin := range s.channels.List() {
+		resp.Channels = append(resp.Channels, s.toProto(pin))
+	}
+	return resp, nil
+}
+
+// SetRuntimeCanary pins an installed runtime to the canary channel of a name
+func (s *RuntimeChannelServiceServer) SetRuntimeCanary(ctx context.Context, req *runtimespb.SetRuntimeCanaryRequest) (*runtimespb.RuntimeChannel, error) {
+	if err := s.auth.Authorized(ctx, auth2.UpdateRuntimeChannelOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "SetRuntimeCanary", "error", err)
+		return nil, err
+	}
+	if _, err := s.resolver.ResolveRuntime(req.Runtime); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "runtime %s is not installed", req.Runtime)
+	}
+	pin, err := s.channels.SetCanary(req.Name, req.Runtime, auth2.ClientIdentity(ctx), time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	s.logger.Info("runtime canary pinned", "name", pin.Name, "canary", pin.Canary, "by", pin.UpdatedBy)
+	return s.toProto(pin), nil
+}
+
+// ClearRuntimeCanary removes the canary of a name
+func (s *RuntimeChannelServiceServer) ClearRuntimeCanary(ctx context.Context, req *runtimespb.RuntimeChannelRequest) (*runtimespb.RuntimeChannel, error) {
+	if err := s.auth.Authorized(ctx, auth2.UpdateRuntimeChannelOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "ClearRuntimeCanary", "error", err)
+		return nil, err
+	}
+	pin, err := s.channels.ClearCanary(req.Name, auth2.ClientIdentity(ctx))
+	if err != nil {
+		return nil, channelError(err)
+	}
+	s.logger.Info("runtime canary cleared", "name", req.Name, "by", auth2.ClientIdentity(ctx))
+	return s.toProto(pin), nil
+}
+
+// PromoteRuntimeCanary makes the canary of a name its stable runtime, unless
+// it failed more than stable allows and the request does not force it
+func (s *RuntimeChannelServiceServer) PromoteRuntimeCanary(ctx context.Context, req *runtimespb.PromoteRuntimeCanaryRequest) (*runtimespb.RuntimeChannel, error) {
+	if err := s.auth.Authorized(ctx, auth2.UpdateRuntimeChannelOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "PromoteRuntimeCanary", "error", err)
+		return nil, err
+	}
+	pin, exists := s.channels.Get(req.Name)
+	if !exists || pin.Canary == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "no canary pinned for %s", req.Name)
+	}
+	if !req.Force {
+		if c, err := s.compare(pin); err == nil && c.Verdict == channels.VerdictRegressed {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"canary %s of %s failed %.1f%% of %d jobs against %.1f%% on %s; use force to promote it anyway",
+				pin.Canary, pin.Name, c.Canary.Rate*100, c.Canary.Finished, c.Stable.Rate*100, c.Stable.Runtime)
+		}
+	}
+	previous, _ := s.stableOf(pin.Name)
+	pin, err := s.channels.Promote(req.Name, auth2.ClientIdentity(ctx), time.Now())
+	if err != nil {
+		return nil, channelError(err)
+	}
+	s.logger.Info("runtime canary promoted", "name", pin.Name, "stable", pin.Stable, "previous", previous,
+		"forced", req.Force, "by", pin.UpdatedBy)
+	return s.toProto(pin), nil
+}
+
+// stableOf returns the runtime jobs asking for name run on, outside of tenant
+// pins and the canary
+func (s *RuntimeChannelServiceServer) stableOf(name string) (string, string) {
+	return s.channels.Resolve(s.config, "", name)
+}
+
+// compare sets the canary of pin against stable from the jobs in the store
+func (s *RuntimeChannelServiceServer) compare(pin channels.Pin) (channels.Comparison, error) {
+	stable, _ := s.stableOf(pin.Name)
+	return channels.Compare(pin, stable, s.jobStore.ListJobs(), s.config.Runtime.Channels)
+}
+
+func (s *RuntimeChannelServiceServer) toProto(pin channels.Pin) *runtimespb.RuntimeChannel {
+	stable, pinnedBy := s.stableOf(pin.Name)
+	channel := &runtimespb.RuntimeChannel{
+		Name:           pin.Name,
+		Stable:         stable,
+		StablePinnedBy: pinnedBy,
+		Canary:         pin.Canary,
+		UpdatedBy:      pin.UpdatedBy,
+	}
+	if !pin.StableSince.IsZero() {
+		channel.StableSince = pin.StableSince.UnixNano()
+	}
+	if pin.Canary == "" {
+		return channel
+	}
+	channel.CanarySince = pin.CanarySince.UnixNano()
+	if c, err := s.compare(pin); err == nil {
+		channel.Comparison = &runtimespb.RuntimeChannelComparison{
+			Canary:          statsToProto(c.Canary),
+			Stable:          statsToProto(c.Stable),
+			Verdict:         c.Verdict,
+			MinSamples:      int32(s.config.Runtime.Channels.MinSamples),
+			MaxRateIncrease: s.config.Runtime.Channels.MaxRateIncrease,
+		}
+	}
+	return channel
+}
+
+func statsToProto(stats channels.Stats) *runtimespb.RuntimeChannelStats {
+	return &runtimespb.RuntimeChannelStats{
+		Runtime:     stats.Runtime,
+		Jobs:        int32(stats.Jobs),
+		Finished:    int32(stats.Finished),
+		Failed:      int32(stats.Failed),
+		FailureRate: stats.Rate,
+	}
+}
+
+func channelError(err error) error {
+	if errors.Is(err, channels.ErrNoCanary) {
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
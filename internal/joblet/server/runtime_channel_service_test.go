@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime/channels"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRuntimeChannelService(t *testing.T) {
+	runtimesPath := t.TempDir()
+	for _, rt := range []struct{ name, version string }{{"python-3.11", "1.2.0"}, {"python-3.12", "2.0.0"}} {
+		dir := filepath.Join(runtimesPath, rt.name, rt.version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		spec := "name: " + rt.name + "\nversion: \"" + rt.version + "\"\n"
+		if err := os.WriteFile(filepath.Join(dir, "runtime.yml"), []byte(spec), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := channels.Open(filepath.Join(t.TempDir(), "channels.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Runtime: config.RuntimeConfig{
+		Aliases:  map[string]string{"python": "python-3.11@1.2.0"},
+		Channels: config.RuntimeChannelsConfig{MinSamples: 2, MaxRateIncrease: 0.1},
+	}}
+	jobStore := &adaptersfakes.FakeJobStorer{}
+	s := NewRuntimeChannelServiceServer(&authfakes.FakeGRPCAuthorization{}, store,
+		runtime.NewResolver(runtimesPath, platform.NewPlatform()), jobStore, cfg)
+	ctx := context.Background()
+
+	if _, err := s.SetRuntimeCanary(ctx, &runtimespb.SetRuntimeCanaryRequest{Name: "python", Runtime: "python-3.13@1.0.0"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("SetRuntimeCanary() with a runtime not installed = %v", err)
+	}
+	channel, err := s.SetRuntimeCanary(ctx, &runtimespb.SetRuntimeCanaryRequest{Name: "python", Runtime: "python-3.12@2.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Stable != "python-3.11@1.2.0" || channel.StablePinnedBy != "server alias" || channel.Canary != "python-3.12@2.0.0" {
+		t.Errorf("SetRuntimeCanary() = %v", channel)
+	}
+
+	// Every canary job failed against half of the stable ones
+	started := time.Now().Add(time.Minute)
+	jobStore.ListJobsReturns([]*domain.Job{
+		{Runtime: "python-3.12@2.0.0", Status: domain.StatusFailed, StartTime: started},
+		{Runtime: "python-3.12@2.0.0", Status: domain.StatusFailed, StartTime: started},
+		{Runtime: "python-3.11@1.2.0", Status: domain.StatusFailed, StartTime: started},
+		{Runtime: "python-3.11@1.2.0", Status: domain.StatusCompleted, StartTime: started},
+	})
+	list, err := s.ListRuntimeChannels(ctx, &runtimespb.ListRuntimeChannelsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Channels) != 1 || list.Channels[0].Comparison.GetVerdict() != channels.VerdictRegressed ||
+		list.Channels[0].Comparison.Canary.FailureRate != 1 || list.Channels[0].Comparison.Stable.FailureRate != 0.5 {
+		t.Fatalf("ListRuntimeChannels() = %v", list.Channels)
+	}
+
+	if _, err := s.PromoteRuntimeCanary(ctx, &runtimespb.PromoteRuntimeCanaryRequest{Name: "python"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("PromoteRuntimeCanary() of a regressed canary = %v", err)
+	}
+	channel, err = s.PromoteRuntimeCanary(ctx, &runtimespb.PromoteRuntimeCanaryRequest{Name: "python", Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channel.Stable != "python-3.12@2.0.0" || channel.StablePinnedBy != "stable channel" || channel.Canary != "" || channel.Comparison != nil {
+		t.Errorf("PromoteRuntimeCanary() = %v", channel)
+	}
+	if _, err := s.ClearRuntimeCanary(ctx, &runtimespb.RuntimeChannelRequest{Name: "python"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ClearRuntimeCanary() without a canary = %v", err)
+	}
+}
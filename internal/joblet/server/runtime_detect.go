@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"path"
+	"slices"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// runtimeDetection picks a runtime for RunJob requests naming none from the
+// dependency file they upload (runtime.detection)
+type runtimeDetection struct {
+	cfg      config.RuntimeDetectionConfig
+	detector *runtime.Detector
+	// Reports whether the install cache volume exists
+	volumeExists func(name string) bool
+}
+
+// newRuntimeDetection returns the runtime detection of the configured policy,
+// nil when it is off
+func newRuntimeDetection(cfg config.RuntimeDetectionConfig, resolver *runtime.Resolver, volumeExists func(name string) bool) *runtimeDetection {
+	if cfg.Policy == "" || cfg.Policy == "off" {
+		return nil
+	}
+	return &runtimeDetection{cfg: cfg, detector: runtime.NewDetector(cfg.Runtimes, resolver), volumeExists: volumeExists}
+}
+
+// detectRuntime suggests a runtime for a job naming none that uploads a
+// dependency file, or with the auto policy runs the job with it, installing
+// the dependencies first when configured. Signed requests only get the
+// suggestion, as their signature covers the runtime and command sent.
+func (s *WorkflowServiceServer) detectRuntime(ctx context.Context, req *interfaces.StartJobRequest) {
+	if s.detection == nil || req.Runtime != "" || len(req.Uploads) == 0 || req.JobType != domain.JobTypeStandard {
+		return
+	}
+	paths := make([]string, len(req.Uploads))
+	for i, upload := range req.Uploads {
+		paths[i] = upload.Path
+	}
+	file, detected, err := s.detection.detector.Detect(paths)
+	if err != nil {
+		s.logger.Warn("runtime detection failed", "error", err)
+		return
+	}
+	if detected == "" {
+		if file != "" {
+			s.logger.Debug("no installed runtime for the uploaded dependency file", "file", file)
+		}
+		return
+	}
+
+	header := constants.RuntimeSuggestedHeader
+	if s.detection.cfg.Policy == "auto" && req.Signature == nil {
+		s.resolveRuntime(ctx, req, detected)
+		req.RuntimeDetectedFrom = file
+		if s.detection.cfg.Install {
+			if err := s.installDependencies(req, file); err != nil {
+				s.logger.Warn("dependency install not added", "file", file, "error", err)
+			}
+		}
+		header = constants.RuntimeSelectedHeader
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(header, file+"="+detected)); err != nil {
+		s.logger.Warn("failed to set response header", "header", header, "error", err)
+	}
+}
+
+// installDependencies runs the install of the dependency file before the
+// job's command, keeping it in the cache volume when there is one
+func (s *WorkflowServiceServer) installDependencies(req *interfaces.StartJobRequest, file string) error {
+	var content []byte
+	for _, upload := range req.Uploads {
+		if path.Clean(upload.Path) == file {
+			content = upload.Content
+		}
+	}
+
+	cacheDir := ""
+	if volume := s.detection.cfg.CacheVolume; volume != "" {
+		if s.detection.volumeExists(volume) {
+			cacheDir = path.Join("/volumes", volume, "deps")
+			if !slices.Contains(req.Volumes, volume) {
+				req.Volumes = append(req.Volumes, volume)
+			}
+		} else {
+			s.logger.Warn("dependency cache volume not found, installing without cache (create it with 'rnx volume create')", "volume", volume)
+		}
+	}
+
+	script, err := runtime.InstallScript(file, content, req.Runtime, cacheDir)
+	if err != nil {
+		return err
+	}
+	req.Args = append([]string{"-c", script + ` && exec "$@"`, "sh", req.Command}, req.Args...)
+	req.Command = "/bin/sh"
+	return nil
+}
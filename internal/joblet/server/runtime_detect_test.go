@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+func TestDetectRuntime(t *testing.T) {
+	resolver := runtime.NewResolver(t.TempDir(), platform.NewPlatform())
+	detection := func(cfg config.RuntimeDetectionConfig) *WorkflowServiceServer {
+		cfg.Runtimes = map[string]string{"requirements.txt": "python-3.12"}
+		return &WorkflowServiceServer{logger: logger.New(), detection: newRuntimeDetection(cfg, resolver, func(name string) bool {
+			return name == "deps"
+		})}
+	}
+	request := func() *interfaces.StartJobRequest {
+		return &interfaces.StartJobRequest{
+			Command: "python3",
+			Args:    []string{"main.py"},
+			Uploads: []domain.FileUpload{{Path: "main.py"}, {Path: "requirements.txt", Content: []byte("requests\n")}},
+			JobType: domain.JobTypeStandard,
+		}
+	}
+
+	if newRuntimeDetection(config.RuntimeDetectionConfig{Policy: "off"}, resolver, nil) != nil {
+		t.Error("policy off created a detection")
+	}
+
+	// Suggest leaves the job as it was sent
+	req := request()
+	detection(config.RuntimeDetectionConfig{Policy: "suggest"}).detectRuntime(context.Background(), req)
+	if req.Runtime != "" || req.Command != "python3" {
+		t.Errorf("suggest changed the job: runtime %q, command %q", req.Runtime, req.Command)
+	}
+
+	// Auto selects the runtime
+	req = request()
+	detection(config.RuntimeDetectionConfig{Policy: "auto"}).detectRuntime(context.Background(), req)
+	if req.Runtime != "python-3.12" || req.RuntimeDetectedFrom != "requirements.txt" || req.Command != "python3" {
+		t.Errorf("auto: runtime %q from %q, command %q", req.Runtime, req.RuntimeDetectedFrom, req.Command)
+	}
+
+	// Signed requests are never changed
+	req = request()
+	req.Signature = &domain.JobSignature{}
+	detection(config.RuntimeDetectionConfig{Policy: "auto"}).detectRuntime(context.Background(), req)
+	if req.Runtime != "" {
+		t.Errorf("auto changed a signed request: runtime %q", req.Runtime)
+	}
+
+	// Install runs the cached install before the command
+	req = request()
+	detection(config.RuntimeDetectionConfig{Policy: "auto", Install: true, CacheVolume: "deps"}).detectRuntime(context.Background(), req)
+	if req.Command != "/bin/sh" || len(req.Args) != 5 || req.Args[3] != "python3" || req.Args[4] != "main.py" ||
+		!strings.Contains(req.Args[1], "/volumes/deps/deps/python/") || strings.Join(req.Volumes, ",") != "deps" {
+		t.Errorf("install: command %q, args %q, volumes %v", req.Command, req.Args, req.Volumes)
+	}
+
+	// Without the cache volume the install runs every time
+	req = request()
+	detection(config.RuntimeDetectionConfig{Policy: "auto", Install: true, CacheVolume: "missing"}).detectRuntime(context.Background(), req)
+	if req.Command != "/bin/sh" || strings.Contains(req.Args[1], "/volumes/") || len(req.Volumes) != 0 {
+		t.Errorf("install without cache: args %q, volumes %v", req.Args, req.Volumes)
+	}
+
+	// Jobs naming a runtime are left alone
+	req = request()
+	req.Runtime = "python-3.11"
+	detection(config.RuntimeDetectionConfig{Policy: "auto", Install: true}).detectRuntime(context.Background(), req)
+	if req.Runtime != "python-3.11" || req.Command != "python3" {
+		t.Errorf("named runtime changed: runtime %q, command %q", req.Runtime, req.Command)
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/core/environment"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime/channels"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RuntimeInfoServiceServer implements the gRPC service describing what an
+// installed runtime gives its jobs
+type RuntimeInfoServiceServer struct {
+	runtimespb.UnimplementedRuntimeInfoServiceServer
+	auth     auth2.GRPCAuthorization
+	resolver *runtime.Resolver
+	config   *config.Config
+	channels *channels.Store // Stable and canary channels of runtime names (nil = none)
+	logger   *logger.Logger
+}
+
+// NewRuntimeInfoServiceServer creates a new runtime info service server
+func NewRuntimeInfoServiceServer(auth auth2.GRPCAuthorization, resolver *runtime.Resolver, cfg *config.Config) *RuntimeInfoServiceServer {
+	return &RuntimeInfoServiceServer{
+		auth:     auth,
+		resolver: resolver,
+		config:   cfg,
+		logger:   logger.WithField("component", "runtime-info"),
+	}
+}
+
+// GetRuntimeInfo describes the runtime req.Runtime resolves to for the
+// caller: its mounts, environment, tools and disk footprint
+func (s *RuntimeInfoServiceServer) GetRuntimeInfo(ctx context.Context, req *runtimespb.GetRuntimeInfoRequest) (*runtimespb.RuntimeDetails, error) {
+	if err := s.auth.Authorized(ctx, auth2.GetJobOp); err != nil {
+		s.logger.Warn("authorization failed", "operation", "GetRuntimeInfo", "error", err)
+		return nil, err
+	}
+	if req.Runtime == "" {
+		return nil, status.Error(codes.InvalidArgument, "runtime name is required")
+	}
+
+	spec, pinnedBy := req.Runtime, ""
+	if s.config != nil {
+		spec, pinnedBy = s.channels.Resolve(s.config, auth2.ClientTenant(ctx, s.config.TenantOf), spec)
+	}
+	installed, err := s.resolver.Inspect(spec)
+	if err != nil {
+		if pinnedBy != "" {
+			return nil, status.Errorf(codes.NotFound, "runtime %s resolves to %s (%s), which is not installed", req.Runtime, spec, pinnedBy)
+		}
+		return nil, status.Errorf(codes.NotFound, "runtime %s is not installed", req.Runtime)
+	}
+
+	rt := installed.Config
+	details := &runtimespb.RuntimeDetails{
+		Name:          rt.Name,
+		Version:       rt.Version,
+		Language:      rt.Language,
+		Description:   rt.Description,
+		Resolved:      rt.Name,
+		PinnedBy:      pinnedBy,
+		Path:          installed.Path,
+		Environment:   rt.Environment,
+		Packages:      rt.Packages,
+		Architectures: rt.Requirements.Architectures,
+		SizeBytes:     installed.SizeBytes,
+		Files:         installed.Files,
+	}
+	if details.Language == "" {
+		details.Language = extractLanguageFromName(rt.Name)
+	}
+	if rt.Version != "" {
+		details.Resolved += "@" + rt.Version
+	}
+
+	for _, m := range rt.Mounts {
+		_, err := os.Stat(filepath.Join(installed.Path, m.Source))
+		details.Mounts = append(details.Mounts, &runtimespb.RuntimeMount{
+			Source:   m.Source,
+			Target:   m.Target,
+			Readonly: m.ReadOnly,
+			Missing:  err != nil,
+		})
+	}
+
+	// The PATH the runtime layer leaves, as jobs see it before their own
+	// variables are applied
+	runtimeEnv := make([]string, 0, len(rt.Environment))
+	for key, value := range rt.Environment {
+		runtimeEnv = append(runtimeEnv, key+"="+value)
+	}
+	sort.Strings(runtimeEnv)
+	details.JobPath, _ = environment.Lookup(environment.Resolve(runtimeEnv), "PATH")
+
+	for name, version := range rt.Tools {
+		details.Tools = append(details.Tools, &runtimespb.RuntimeTool{Name: name, Version: version})
+	}
+	sort.Slice(details.Tools, func(i, j int) bool { return details.Tools[i].Name < details.Tools[j].Name })
+
+	return details, nil
+}
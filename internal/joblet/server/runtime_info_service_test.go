@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/platform"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRuntimeInfoService_GetRuntimeInfo(t *testing.T) {
+	runtimesPath := t.TempDir()
+	runtimeDir := filepath.Join(runtimesPath, "python-3.11-ml", "1.2.0")
+	if err := os.MkdirAll(filepath.Join(runtimeDir, "isolated", "usr", "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	spec := `name: python-3.11-ml
+version: "1.2.0"
+description: Python with ML packages
+mounts:
+  - source: isolated/usr/bin
+    target: /usr/bin
+    readonly: true
+  - source: isolated/lib64
+    target: /lib64
+environment:
+  PYTHONDONTWRITEBYTECODE: "1"
+  PATH_PREPEND: /opt/venv/bin
+tools:
+  python3: 3.11.9
+  pip: "24.0"
+packages: [numpy]
+`
+	if err := os.WriteFile(filepath.Join(runtimeDir, "runtime.yml"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Runtime: config.RuntimeConfig{Aliases: map[string]string{"python": "python-3.11-ml@1.2.0"}}}
+	s := NewRuntimeInfoServiceServer(&authfakes.FakeGRPCAuthorization{}, runtime.NewResolver(runtimesPath, platform.NewPlatform()), cfg)
+
+	details, err := s.GetRuntimeInfo(context.Background(), &runtimespb.GetRuntimeInfoRequest{Runtime: "python"})
+	if err != nil {
+		t.Fatalf("GetRuntimeInfo() error = %v", err)
+	}
+	if details.Resolved != "python-3.11-ml@1.2.0" || details.PinnedBy != "server alias" || details.Language != "python" || details.Path != runtimeDir {
+		t.Errorf("details = %v", details)
+	}
+	if len(details.Mounts) != 2 || details.Mounts[0].Missing || !details.Mounts[1].Missing || !details.Mounts[0].Readonly {
+		t.Errorf("mounts = %v", details.Mounts)
+	}
+	if details.JobPath != "/opt/venv/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin" {
+		t.Errorf("job PATH = %s", details.JobPath)
+	}
+	if len(details.Tools) != 2 || details.Tools[0].Name != "pip" || details.Tools[1].Version != "3.11.9" {
+		t.Errorf("tools = %v", details.Tools)
+	}
+	if details.Files != 1 || details.SizeBytes != int64(len(spec)) {
+		t.Errorf("footprint = %d files, %d bytes", details.Files, details.SizeBytes)
+	}
+
+	_, err = s.GetRuntimeInfo(context.Background(), &runtimespb.GetRuntimeInfoRequest{Runtime: "openjdk-21"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("missing runtime error = %v, want NotFound", err)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
 	"github.com/ehsaniara/joblet/internal/joblet/auth"
 	"github.com/ehsaniara/joblet/internal/joblet/core"
 	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime/channels"
 	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
 	"github.com/ehsaniara/joblet/pkg/platform"
@@ -28,6 +30,8 @@ type RuntimeServiceServer struct {
 	resolver         *runtime.Resolver
 	runtimeInstaller *core.RuntimeInstaller
 	runtimesPath     string
+	config           *config.Config
+	channels         *channels.Store // Stable and canary channels of runtime names (nil = none)
 	logger           *logger.Logger
 }
 
@@ -42,6 +46,7 @@ func NewRuntimeServiceServer(auth auth.GRPCAuthorization, runtimesBasePath strin
 		resolver:         runtime.NewResolver(runtimesBasePath, platform),
 		runtimeInstaller: core.NewRuntimeInstaller(config, runtimeLogger, platform),
 		runtimesPath:     runtimesBasePath,
+		config:           config,
 		logger:           runtimeLogger,
 	}
 }
@@ -56,6 +61,17 @@ func (s *RuntimeServiceServer) ListRuntimes(ctx context.Context, req *pb.EmptyRe
 		return nil, err
 	}
 
+	// Clients of a tenant see the runtimes their jobs may use, unless an
+	// admin asks for all
+	var tenant string
+	if s.config != nil {
+		var err error
+		if tenant, err = listingTenant(ctx, s.auth, s.config.TenantOf); err != nil {
+			log.Warn("authorization failed", "error", err)
+			return nil, err
+		}
+	}
+
 	// Get runtimes from resolver
 	runtimeInfos, err := s.resolver.ListRuntimes()
 	if err != nil {
@@ -66,6 +82,9 @@ func (s *RuntimeServiceServer) ListRuntimes(ctx context.Context, req *pb.EmptyRe
 	// Convert to protobuf format
 	pbRuntimes := make([]*pb.RuntimeInfo, 0, len(runtimeInfos))
 	for _, info := range runtimeInfos {
+		if tenant != "" && !s.config.TenantCanUseRuntime(tenant, info.Name) {
+			continue
+		}
 		pbRuntime := &pb.RuntimeInfo{
 			Name:        info.Name,
 			Language:    info.Language,
@@ -82,12 +101,65 @@ func (s *RuntimeServiceServer) ListRuntimes(ctx context.Context, req *pb.EmptyRe
 
 		pbRuntimes = append(pbRuntimes, pbRuntime)
 	}
+	pbRuntimes = append(pbRuntimes, s.aliasRuntimes(ctx, pbRuntimes, tenant)...)
 
 	return &pb.RuntimesRes{
 		Runtimes: pbRuntimes,
 	}, nil
 }
 
+// aliasRuntimes lists the caller's runtime aliases and the channels of
+// runtime names next to the installed runtimes they resolve to, unavailable
+// when the target is not installed. With a listing tenant, aliases of
+// runtimes the tenant may not use are left out.
+func (s *RuntimeServiceServer) aliasRuntimes(ctx context.Context, installed []*pb.RuntimeInfo, listing string) []*pb.RuntimeInfo {
+	if s.config == nil {
+		return nil
+	}
+	byName := make(map[string]*pb.RuntimeInfo, len(installed))
+	for _, rt := range installed {
+		byName[rt.Name] = rt
+		if rt.Version != "" {
+			byName[rt.Name+"@"+rt.Version] = rt
+		}
+	}
+
+	tenant := auth.ClientTenant(ctx, s.config.TenantOf)
+	aliases := s.config.RuntimeAliases(tenant)
+	for _, pin := range s.channels.List() {
+		aliases[pin.Name] = ""
+		aliases[pin.Name+"@"+channels.Stable] = ""
+		if pin.Canary != "" {
+			aliases[pin.Name+"@"+channels.Canary] = ""
+		}
+	}
+	for name := range aliases {
+		aliases[name], _ = s.channels.Resolve(s.config, tenant, name)
+	}
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []*pb.RuntimeInfo
+	for _, name := range names {
+		target := aliases[name]
+		if listing != "" && !s.config.TenantCanUseRuntime(listing, target) {
+			continue
+		}
+		info := &pb.RuntimeInfo{Name: name, Description: "Alias for " + target, Packages: []string{}}
+		if rt, ok := byName[target]; ok {
+			info.Language, info.Version, info.Available = rt.Language, rt.Version, rt.Available
+			info.SizeBytes, info.Requirements = rt.SizeBytes, rt.Requirements
+		} else {
+			info.Description += " (not installed)"
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
 // GetRuntimeInfo returns detailed metadata and configuration for a specific runtime
 func (s *RuntimeServiceServer) GetRuntimeInfo(ctx context.Context, req *pb.RuntimeInfoReq) (*pb.RuntimeInfoRes, error) {
 	log := s.logger.WithFields("operation", "GetRuntimeInfo", "runtime", req.Runtime)
@@ -103,8 +175,19 @@ func (s *RuntimeServiceServer) GetRuntimeInfo(ctx context.Context, req *pb.Runti
 		return nil, status.Errorf(codes.InvalidArgument, "runtime name is required")
 	}
 
-	// Resolve runtime
-	config, err := s.resolver.ResolveRuntime(req.Runtime)
+	// Resolve runtime, through the caller's aliases
+	spec := req.Runtime
+	if s.config != nil {
+		tenant := auth.ClientTenant(ctx, s.config.TenantOf)
+		spec, _ = s.channels.Resolve(s.config, tenant, spec)
+		// Runtimes the caller's tenant may not use are hidden, as in ListRuntimes
+		if !s.config.TenantCanUseRuntime(tenant, spec) {
+			return &pb.RuntimeInfoRes{
+				Found: false,
+			}, nil
+		}
+	}
+	config, err := s.resolver.ResolveRuntime(spec)
 	if err != nil {
 		return &pb.RuntimeInfoRes{
 			Found: false,
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
 	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
 	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/platform"
@@ -105,3 +106,17 @@ func TestExtractLanguageFromName(t *testing.T) {
 		})
 	}
 }
+
+func TestRuntimeServiceServer_GetRuntimeInfo_OtherTenantRuntime(t *testing.T) {
+	fakeAuth := &authfakes.FakeGRPCAuthorization{}
+	testConfig := &config.Config{
+		Tenants: []config.TenantConfig{{Name: "ml", Runtimes: []string{"python-*"}}},
+	}
+	server := NewRuntimeServiceServer(fakeAuth, t.TempDir(), platform.NewPlatform(), testConfig)
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Principal: "alice", Tenant: "ml"})
+	resp, err := server.GetRuntimeInfo(ctx, &pb.RuntimeInfoReq{Runtime: "openjdk-21"})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Found, "a runtime the tenant may not use is found")
+}
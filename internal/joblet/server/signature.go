@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Results of checking a stored signature again on read
+const (
+	signatureValid     = "valid"
+	signatureInvalid   = "invalid"
+	signatureUntrusted = "untrusted"
+)
+
+// signatureVerifier checks the signatures clients send with RunJob and
+// RunWorkflow, and checks stored signatures again when a record is read.
+type signatureVerifier struct {
+	required bool
+	trusted  map[string]bool // Fingerprints of trusted keys, empty = any key
+}
+
+func newSignatureVerifier(cfg config.SigningConfig) (*signatureVerifier, error) {
+	v := &signatureVerifier{required: cfg.Required, trusted: make(map[string]bool)}
+	for _, pemKey := range cfg.TrustedKeys {
+		key, err := jobsign.ParsePublicKey(pemKey)
+		if err != nil {
+			return nil, err
+		}
+		v.trusted[jobsign.Fingerprint(key)] = true
+	}
+	return v, nil
+}
+
+// fromRequest reads the signature sent with the request and checks it against
+// digest. It returns nil for an unsigned request when signatures are optional.
+func (v *signatureVerifier) fromRequest(ctx context.Context, digest func(signedAt time.Time) []byte) (*domain.JobSignature, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	value := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	if value(constants.SignatureHeader) == "" {
+		if v.required {
+			return nil, status.Error(codes.Unauthenticated, "this server only accepts signed requests, add a signingKey to the client configuration")
+		}
+		return nil, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(value(constants.SignatureHeader))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", constants.SignatureHeader, err)
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(value(constants.SignatureKeyHeader))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", constants.SignatureKeyHeader, err)
+	}
+	signedAtUnix, err := strconv.ParseInt(value(constants.SignedAtHeader), 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", constants.SignedAtHeader, err)
+	}
+
+	sig := &domain.JobSignature{
+		PublicKey: publicKey,
+		Signature: signature,
+		SignedAt:  time.Unix(signedAtUnix, 0).UTC(),
+	}
+	switch v.check(sig, digest(sig.SignedAt)) {
+	case signatureInvalid:
+		return nil, status.Error(codes.InvalidArgument, "request signature does not match the request")
+	case signatureUntrusted:
+		return nil, status.Errorf(codes.PermissionDenied, "signing key %s is not trusted by this server", jobsign.Fingerprint(publicKey))
+	}
+	return sig, nil
+}
+
+// check verifies sig against digest and the trusted keys
+func (v *signatureVerifier) check(sig *domain.JobSignature, digest []byte) string {
+	if !jobsign.Verify(sig.PublicKey, digest, sig.Signature) {
+		return signatureInvalid
+	}
+	if len(v.trusted) > 0 && !v.trusted[jobsign.Fingerprint(sig.PublicKey)] {
+		return signatureUntrusted
+	}
+	return signatureValid
+}
+
+// setHeader checks a stored signature again and reports the result and the
+// signer with the response. Unsigned records get no header.
+func (v *signatureVerifier) setHeader(ctx context.Context, sig *domain.JobSignature, digest func(signedAt time.Time) []byte) error {
+	if sig == nil {
+		return nil
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(
+		constants.SignatureStatusHeader, v.check(sig, digest(sig.SignedAt)),
+		constants.SignerHeader, jobsign.Fingerprint(sig.PublicKey),
+	))
+}
+
+// requestDigest is the digest a RunJob request's signature covers
+func requestDigest(req *pb.RunJobRequest) func(signedAt time.Time) []byte {
+	return func(signedAt time.Time) []byte {
+		return jobsign.JobDigest(req.Command, req.Args, req.Runtime, signedAt)
+	}
+}
+
+// jobDigest is the digest a stored job's signature covers. The client signed
+// the runtime it asked for, an alias when the server resolved one.
+func jobDigest(job *domain.Job) func(signedAt time.Time) []byte {
+	runtime := job.Runtime
+	if job.RequestedRuntime != "" {
+		runtime = job.RequestedRuntime
+	}
+	return func(signedAt time.Time) []byte {
+		return jobsign.JobDigest(job.Command, job.Args, runtime, signedAt)
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strconv"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// signedContext returns an incoming context carrying key's signature of req
+func signedContext(key ed25519.PrivateKey, req *pb.RunJobRequest) context.Context {
+	signedAt := time.Now()
+	signature := jobsign.Sign(key, jobsign.JobDigest(req.Command, req.Args, req.Runtime, signedAt))
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		constants.SignatureHeader, base64.StdEncoding.EncodeToString(signature),
+		constants.SignatureKeyHeader, base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		constants.SignedAtHeader, strconv.FormatInt(signedAt.Unix(), 10),
+	))
+}
+
+func publicKeyPEM(t *testing.T, key ed25519.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestSignatureVerifierFromRequest(t *testing.T) {
+	trustedPublic, trustedKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	req := &pb.RunJobRequest{Command: "python3", Args: []string{"train.py"}}
+
+	optional, err := newSignatureVerifier(config.SigningConfig{})
+	if err != nil {
+		t.Fatalf("newSignatureVerifier() error = %v", err)
+	}
+	required, err := newSignatureVerifier(config.SigningConfig{Required: true, TrustedKeys: []string{publicKeyPEM(t, trustedPublic)}})
+	if err != nil {
+		t.Fatalf("newSignatureVerifier() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier *signatureVerifier
+		ctx      context.Context
+		req      *pb.RunJobRequest
+		wantCode codes.Code
+		wantSig  bool
+	}{
+		{"unsigned, optional", optional, context.Background(), req, codes.OK, false},
+		{"unsigned, required", required, context.Background(), req, codes.Unauthenticated, false},
+		{"signed by any key", optional, signedContext(otherKey, req), req, codes.OK, true},
+		{"signed by trusted key", required, signedContext(trustedKey, req), req, codes.OK, true},
+		{"signed by untrusted key", required, signedContext(otherKey, req), req, codes.PermissionDenied, false},
+		{"command changed after signing", optional, signedContext(otherKey, req), &pb.RunJobRequest{Command: "rm", Args: []string{"-rf", "/"}}, codes.InvalidArgument, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := tt.verifier.fromRequest(tt.ctx, requestDigest(tt.req))
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("fromRequest() error = %v, want code %v", err, tt.wantCode)
+			}
+			if (sig != nil) != tt.wantSig {
+				t.Fatalf("fromRequest() signature = %v, want signature %v", sig, tt.wantSig)
+			}
+		})
+	}
+}
+
+func TestSignatureVerifierCheckStoredJob(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	req := &pb.RunJobRequest{Command: "python3", Args: []string{"train.py"}, Runtime: "python-3.11"}
+	verifier, _ := newSignatureVerifier(config.SigningConfig{})
+
+	sig, err := verifier.fromRequest(signedContext(key, req), requestDigest(req))
+	if err != nil {
+		t.Fatalf("fromRequest() error = %v", err)
+	}
+
+	job := &domain.Job{Command: req.Command, Args: req.Args, Runtime: req.Runtime, Signature: sig}
+	if got := verifier.check(job.Signature, jobDigest(job)(sig.SignedAt)); got != signatureValid {
+		t.Errorf("check() = %q for the stored job, want %q", got, signatureValid)
+	}
+
+	job.Args = []string{"evil.py"}
+	if got := verifier.check(job.Signature, jobDigest(job)(sig.SignedAt)); got != signatureInvalid {
+		t.Errorf("check() = %q after the record was modified, want %q", got, signatureInvalid)
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+)
+
+type tenantKey struct{}
+
+// withTenant carries the submitting client's tenant into work that outlives
+// the request, such as workflow orchestration
+func withTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// tenantOf returns the tenant of the client behind ctx, "" when the client
+// belongs to none
+func (s *WorkflowServiceServer) tenantOf(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+		return tenant
+	}
+	if s.tenants == nil {
+		return ""
+	}
+	return auth2.ClientTenant(ctx, s.tenants)
+}
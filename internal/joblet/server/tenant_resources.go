@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// resourceAccess tells which volumes and runtimes the jobs of a tenant may
+// use; the server configuration implements it
+type resourceAccess interface {
+	TenantCanUseVolume(tenant, volume string) bool
+	TenantCanUseRuntime(tenant, runtime string) bool
+}
+
+// listingTenant returns the tenant whose volumes or runtimes a list request
+// shows: the caller's, or "" for all of them when an admin sets
+// ListAllHeader or the caller belongs to no tenant
+func listingTenant(ctx context.Context, auth auth2.GRPCAuthorization, tenants func(client string) string) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if all := md.Get(constants.ListAllHeader); len(all) > 0 && all[0] == "true" {
+		if err := auth.Authorized(ctx, auth2.ListAllResourcesOp); err != nil {
+			return "", err
+		}
+		return "", nil
+	}
+	if tenants == nil {
+		return "", nil
+	}
+	return auth2.ClientTenant(ctx, tenants), nil
+}
+
+// checkResourceAccess refuses a job that uses a volume or runtime its
+// tenant may not use
+func checkResourceAccess(access resourceAccess, req *interfaces.StartJobRequest) error {
+	if access == nil || req.Tenant == "" {
+		return nil
+	}
+	for _, volume := range req.Volumes {
+		if !access.TenantCanUseVolume(req.Tenant, volume) {
+			return status.Errorf(codes.PermissionDenied, "tenant %s may not use volume %s", req.Tenant, volume)
+		}
+	}
+	if req.Runtime != "" && !access.TenantCanUseRuntime(req.Tenant, req.Runtime) {
+		return status.Errorf(codes.PermissionDenied, "tenant %s may not use runtime %s", req.Tenant, req.Runtime)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestListingTenant(t *testing.T) {
+	noTenant := func(string) string { return "" }
+	ctx := auth2.WithIdentity(context.Background(), &auth2.Identity{Principal: "alice", Tenant: "ml"})
+	allCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(constants.ListAllHeader, "true"))
+
+	auth := &authfakes.FakeGRPCAuthorization{}
+	if tenant, err := listingTenant(ctx, auth, noTenant); err != nil || tenant != "ml" {
+		t.Errorf("listingTenant() = %q, %v; want the caller's tenant", tenant, err)
+	}
+	if auth.AuthorizedCallCount() != 0 {
+		t.Error("listing the caller's tenant checked an extra operation")
+	}
+
+	if tenant, err := listingTenant(allCtx, auth, noTenant); err != nil || tenant != "" {
+		t.Errorf("listingTenant() for an admin = %q, %v; want all", tenant, err)
+	}
+	if _, op := auth.AuthorizedArgsForCall(0); op != auth2.ListAllResourcesOp {
+		t.Errorf("authorized %s, want %s", op, auth2.ListAllResourcesOp)
+	}
+
+	auth.AuthorizedReturns(status.Error(codes.PermissionDenied, "viewer"))
+	if _, err := listingTenant(allCtx, auth, noTenant); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("listingTenant() for a viewer error = %v, want PermissionDenied", err)
+	}
+}
+
+func TestCheckResourceAccess(t *testing.T) {
+	cfg := &config.Config{
+		Tenants: []config.TenantConfig{{Name: "ml", Volumes: []string{"ml-*"}, Runtimes: []string{"python-*"}}},
+	}
+
+	tests := []struct {
+		name string
+		req  interfaces.StartJobRequest
+		want codes.Code
+	}{
+		{"allowed", interfaces.StartJobRequest{Tenant: "ml", Volumes: []string{"ml-data"}, Runtime: "python-3.11"}, codes.OK},
+		{"other volume", interfaces.StartJobRequest{Tenant: "ml", Volumes: []string{"ml-data", "web-assets"}}, codes.PermissionDenied},
+		{"other runtime", interfaces.StartJobRequest{Tenant: "ml", Runtime: "openjdk-21"}, codes.PermissionDenied},
+		{"no tenant", interfaces.StartJobRequest{Volumes: []string{"web-assets"}, Runtime: "openjdk-21"}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(checkResourceAccess(cfg, &tt.req)); got != tt.want {
+				t.Errorf("checkResourceAccess() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
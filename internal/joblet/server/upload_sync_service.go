@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/uploadsync"
+	uploadspb "github.com/ehsaniara/joblet/internal/proto/gen/uploads"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UploadSyncServiceServer implements the gRPC service syncing workflow files
+// as deltas against the copies in the upload cache
+type UploadSyncServiceServer struct {
+	uploadspb.UnimplementedUploadSyncServiceServer
+	auth   auth2.GRPCAuthorization
+	cache  *uploadsync.Cache
+	logger *logger.Logger
+}
+
+// NewUploadSyncServiceServer creates a new upload sync service server
+func NewUploadSyncServiceServer(auth auth2.GRPCAuthorization, cache *uploadsync.Cache) *UploadSyncServiceServer {
+	return &UploadSyncServiceServer{
+		auth:   auth,
+		cache:  cache,
+		logger: logger.WithField("component", "upload-sync"),
+	}
+}
+
+// GetFileSignature returns the block checksums of the caller's cached copy
+// of a file
+func (s *UploadSyncServiceServer) GetFileSignature(ctx context.Context, req *uploadspb.FileSignatureRequest) (*uploadspb.FileSignature, error) {
+	if err := s.auth.Authorized(ctx, auth2.RunJobOp); err != nil {
+		return nil, err
+	}
+	if req.Path == "" {
+		return nil, status.Error(codes.InvalidArgument, "path is required")
+	}
+
+	sig, err := s.cache.Signature(auth2.ClientIdentity(ctx), req.Path)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	res := &uploadspb.FileSignature{Basis: sig.Basis, Size: sig.Size, BlockSize: int32(sig.BlockSize)}
+	for _, block := range sig.Blocks {
+		res.Blocks = append(res.Blocks, &uploadspb.BlockChecksum{Weak: block.Weak, Strong: block.Strong})
+	}
+	return res, nil
+}
+
+// SyncFile rebuilds a file from a delta against the caller's cached copy and
+// caches the result for RunWorkflow to refer to
+func (s *UploadSyncServiceServer) SyncFile(ctx context.Context, req *uploadspb.FileDelta) (*uploadspb.SyncFileResponse, error) {
+	log := s.logger.WithFields("operation", "SyncFile", "path", req.Path)
+	if err := s.auth.Authorized(ctx, auth2.RunJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if req.Path == "" || req.Sha256 == "" {
+		return nil, status.Error(codes.InvalidArgument, "path and sha256 are required")
+	}
+
+	ops := make([]uploadsync.Op, 0, len(req.Ops))
+	for _, op := range req.Ops {
+		ops = append(ops, uploadsync.Op{Block: op.Block, Count: op.Count, Data: op.Data})
+	}
+	copied, added, err := s.cache.Apply(auth2.ClientIdentity(ctx), req.Path, req.Basis, int(req.BlockSize), ops, req.Sha256)
+	if errors.Is(err, uploadsync.ErrNotCached) {
+		return nil, status.Errorf(codes.FailedPrecondition, "cached copy of %s changed, sync it again", req.Path)
+	}
+	if err != nil {
+		log.Warn("sync failed", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "sync of %s failed: %v", req.Path, err)
+	}
+
+	log.Debug("file synced", "copiedBytes", copied, "dataBytes", added)
+	return &uploadspb.SyncFileResponse{Ref: req.Sha256, CopiedBytes: copied, DataBytes: added}, nil
+}
+
+// resolveUploadRefs fills in the content of the workflow files the request's
+// joblet-upload-refs-bin header names by content hash. A file no longer in
+// the cache fails the request with FailedPrecondition, rnx then sends it
+// whole.
+func resolveUploadRefs(ctx context.Context, cache *uploadsync.Cache, files []*pb.FileUpload) ([]*pb.FileUpload, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(constants.UploadRefsHeader)
+	if len(values) == 0 {
+		return files, nil
+	}
+	var refs map[string]string
+	if err := json.Unmarshal([]byte(values[0]), &refs); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s header: %v", constants.UploadRefsHeader, err)
+	}
+	if cache == nil {
+		return nil, status.Error(codes.FailedPrecondition, "upload sync is not enabled on this server")
+	}
+
+	owner := auth2.ClientIdentity(ctx)
+	resolved := make([]*pb.FileUpload, 0, len(files))
+	for _, file := range files {
+		ref, ok := refs[file.Path]
+		if !ok || len(file.Content) > 0 {
+			resolved = append(resolved, file)
+			continue
+		}
+		content, err := cache.Content(owner, file.Path, ref)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "synced upload %s is no longer cached: %v", file.Path, err)
+		}
+		resolved = append(resolved, &pb.FileUpload{Path: file.Path, Content: content, Mode: file.Mode, IsDirectory: file.IsDirectory})
+	}
+	return resolved, nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/uploadsync"
+	uploadspb "github.com/ehsaniara/joblet/internal/proto/gen/uploads"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadSyncService_SyncAndResolve(t *testing.T) {
+	cache, err := uploadsync.NewCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewUploadSyncServiceServer(&authfakes.FakeGRPCAuthorization{}, cache)
+	ctx := context.Background()
+
+	model := bytes.Repeat([]byte("weights"), 20000)
+	sig, err := s.GetFileSignature(ctx, &uploadspb.FileSignatureRequest{Path: "model.bin"})
+	if err != nil || sig.Basis != "" {
+		t.Fatalf("GetFileSignature of an uncached file = %v, %v", sig, err)
+	}
+	res, err := s.SyncFile(ctx, &uploadspb.FileDelta{
+		Path:   "model.bin",
+		Sha256: sha256Hex(model),
+		Ops:    []*uploadspb.DeltaOp{{Data: model}},
+	})
+	if err != nil || res.Ref != sha256Hex(model) || res.DataBytes != int64(len(model)) {
+		t.Fatalf("SyncFile = %v, %v", res, err)
+	}
+
+	// The second sync copies the cached blocks
+	sig, err = s.GetFileSignature(ctx, &uploadspb.FileSignatureRequest{Path: "model.bin"})
+	if err != nil || sig.Basis != res.Ref || len(sig.Blocks) == 0 {
+		t.Fatalf("GetFileSignature = %v, %v", sig, err)
+	}
+	changed := append(append([]byte{}, model...), "tail"...)
+	res, err = s.SyncFile(ctx, &uploadspb.FileDelta{
+		Path:      "model.bin",
+		Basis:     sig.Basis,
+		BlockSize: sig.BlockSize,
+		Sha256:    sha256Hex(changed),
+		Ops:       []*uploadspb.DeltaOp{{Block: 0, Count: int64(len(sig.Blocks))}, {Data: []byte("tail")}},
+	})
+	if err != nil || res.CopiedBytes != int64(len(model)) || res.DataBytes != 4 {
+		t.Fatalf("SyncFile with basis = %v, %v", res, err)
+	}
+
+	// A stale basis asks the client to sync again
+	_, err = s.SyncFile(ctx, &uploadspb.FileDelta{Path: "model.bin", Basis: sig.Basis, BlockSize: sig.BlockSize, Sha256: sha256Hex(model)})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("stale basis: got %v, want FailedPrecondition", err)
+	}
+
+	refs, _ := json.Marshal(map[string]string{"model.bin": res.Ref})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(constants.UploadRefsHeader, string(refs)))
+	files, err := resolveUploadRefs(ctx, cache, []*pb.FileUpload{{Path: "model.bin", Mode: 0644}, {Path: "run.py", Content: []byte("print()")}})
+	if err != nil || len(files) != 2 || !bytes.Equal(files[0].Content, changed) || files[0].Mode != 0644 || string(files[1].Content) != "print()" {
+		t.Fatalf("resolveUploadRefs = %v, %v", files, err)
+	}
+
+	refs, _ = json.Marshal(map[string]string{"model.bin": sha256Hex(model)})
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(constants.UploadRefsHeader, string(refs)))
+	if _, err := resolveUploadRefs(ctx, cache, []*pb.FileUpload{{Path: "model.bin"}}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("uncached ref: got %v, want FailedPrecondition", err)
+	}
+	if _, err := resolveUploadRefs(ctx, nil, []*pb.FileUpload{{Path: "model.bin"}}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("sync disabled: got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestUploadSyncService_Unauthorized(t *testing.T) {
+	authorization := &authfakes.FakeGRPCAuthorization{}
+	authorization.AuthorizedReturns(status.Error(codes.PermissionDenied, "denied"))
+	s := NewUploadSyncServiceServer(authorization, nil)
+
+	if _, err := s.GetFileSignature(context.Background(), &uploadspb.FileSignatureRequest{Path: "a"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetFileSignature: got %v, want PermissionDenied", err)
+	}
+	if _, err := s.SyncFile(context.Background(), &uploadspb.FileDelta{Path: "a", Sha256: "x"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("SyncFile: got %v, want PermissionDenied", err)
+	}
+}
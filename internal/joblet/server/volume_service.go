@@ -10,6 +10,7 @@ import (
 	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
 	"github.com/ehsaniara/joblet/internal/joblet/core/volume"
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
 )
 
@@ -18,6 +19,7 @@ type VolumeServiceServer struct {
 	pb.UnimplementedVolumeServiceServer
 	auth          auth2.GRPCAuthorization
 	volumeManager *volume.Manager
+	config        *config.Config // Tenants and the volumes they may use (nil = every client sees all)
 	logger        *logger.Logger
 }
 
@@ -71,6 +73,17 @@ func (s *VolumeServiceServer) ListVolumes(ctx context.Context, req *pb.EmptyRequ
 		return nil, err
 	}
 
+	// Clients of a tenant see the volumes their jobs may use, unless an
+	// admin asks for all
+	var tenant string
+	if s.config != nil {
+		var err error
+		if tenant, err = listingTenant(ctx, s.auth, s.config.TenantOf); err != nil {
+			log.Warn("authorization failed", "error", err)
+			return nil, err
+		}
+	}
+
 	volumes := s.volumeManager.ListVolumes()
 
 	resp := &pb.Volumes{
@@ -78,6 +91,9 @@ func (s *VolumeServiceServer) ListVolumes(ctx context.Context, req *pb.EmptyRequ
 	}
 
 	for _, vol := range volumes {
+		if tenant != "" && !s.config.TenantCanUseVolume(tenant, vol.Name) {
+			continue
+		}
 		resp.Volumes = append(resp.Volumes, &pb.Volume{
 			Name:        vol.Name,
 			Size:        vol.Size,
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/checkpoint"
+)
+
+// checkpointNewWorkflow saves a workflow just created with what resuming it
+// needs: the submitting client's tenant, the workflow's annotations and the
+// files uploaded for its jobs
+func (s *WorkflowServiceServer) checkpointNewWorkflow(ctx context.Context, workflowID int, workflowYAML *WorkflowYAML, uploadedFiles map[string][]byte) {
+	if s.checkpoints == nil {
+		return
+	}
+	wf := checkpoint.Workflow{
+		UUID:   s.getFullUuidForWorkflowID(workflowID),
+		Tenant: s.tenantOf(ctx),
+		Files:  uploadedFiles,
+	}
+	if workflowYAML != nil {
+		wf.Annotations = workflowYAML.Annotations
+	}
+
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	state, err := s.workflowManager.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return
+	}
+	if err := s.checkpoints.Create(wf, state); err != nil {
+		s.logger.Warn("failed to save workflow, it will not survive a restart", "workflowUuid", wf.UUID, "error", err)
+	}
+}
+
+// checkpointWorkflow saves the current state of a workflow. States are read
+// and written under one lock, so an older state never replaces a newer one.
+func (s *WorkflowServiceServer) checkpointWorkflow(workflowID int) {
+	if s.checkpoints == nil {
+		return
+	}
+
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	state, err := s.workflowManager.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return
+	}
+	uuid := s.getFullUuidForWorkflowID(workflowID)
+	if err := s.checkpoints.Save(uuid, state); err != nil {
+		s.logger.Warn("failed to save workflow state", "workflowUuid", uuid, "error", err)
+	}
+	if state.Status.Ended() {
+		s.schedulePrune(workflowID, state)
+	}
+}
+
+// schedulePrune deletes the saved workflow once it has been ended for the
+// retention, unless it is scheduled already
+func (s *WorkflowServiceServer) schedulePrune(workflowID int, state *workflow.WorkflowState) {
+	ended, ok := checkpoint.EndedAt(state)
+	if !ok || s.checkpointRetention <= 0 {
+		return
+	}
+	if _, scheduled := s.prunesScheduled.LoadOrStore(workflowID, struct{}{}); scheduled {
+		return
+	}
+	time.AfterFunc(time.Until(ended.Add(s.checkpointRetention)), func() {
+		s.prunesScheduled.Delete(workflowID)
+		s.pruneCheckpoint(workflowID)
+	})
+}
+
+// pruneCheckpoint deletes the saved workflow if it is still ended and past
+// the retention, and schedules it again if it ended again since
+func (s *WorkflowServiceServer) pruneCheckpoint(workflowID int) {
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	state, err := s.workflowManager.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return
+	}
+	ended, ok := checkpoint.EndedAt(state)
+	if !ok {
+		return // Running again; scheduled when it ends
+	}
+	if time.Since(ended) < s.checkpointRetention {
+		s.schedulePrune(workflowID, state)
+		return
+	}
+	uuid := s.getFullUuidForWorkflowID(workflowID)
+	if err := s.checkpoints.Delete(uuid); err != nil {
+		s.logger.Warn("failed to prune saved workflow", "workflowUuid", uuid, "error", err)
+		return
+	}
+	s.logger.Debug("pruned saved workflow past its retention", "workflowUuid", uuid, "retention", s.checkpointRetention)
+}
+
+// sweepCheckpoints deletes, every checkpointSweepInterval, the saved
+// workflows past their retention that no scheduled prune covers, such as
+// those whose deletion failed
+func (s *WorkflowServiceServer) sweepCheckpoints() {
+	ticker := time.NewTicker(checkpointSweepInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		pruned, err := s.checkpoints.Prune(time.Now().Add(-s.checkpointRetention))
+		if err != nil {
+			s.logger.Warn("failed to prune saved workflows", "error", err)
+		}
+		if pruned > 0 {
+			s.logger.Debug("pruned saved workflows past their retention", "workflows", pruned, "retention", s.checkpointRetention)
+		}
+	}
+}
+
+// ResumeWorkflows loads the workflows saved before the joblet restarted and
+// deletes those past their retention. Ended workflows are only listed again. For the others, the jobs they had
+// started take the status the job store has now and are monitored again, a
+// job the store lost counts as failed, and orchestration carries on with the
+// jobs still to start.
+func (s *WorkflowServiceServer) ResumeWorkflows() error {
+	if s.checkpoints == nil {
+		return nil
+	}
+	records, err := s.checkpoints.Load()
+	if err != nil {
+		return err
+	}
+	if s.checkpointRetention > 0 {
+		go s.sweepCheckpoints()
+	}
+
+	resumed, pruned := 0, 0
+	for _, record := range records {
+		log := s.logger.WithFields("workflowUuid", record.UUID, "status", record.State.Status)
+		if ended, ok := checkpoint.EndedAt(record.State); ok && s.checkpointRetention > 0 && time.Since(ended) >= s.checkpointRetention {
+			if err := s.checkpoints.Delete(record.UUID); err != nil {
+				log.Warn("failed to prune saved workflow", "error", err)
+			}
+			pruned++
+			continue
+		}
+		workflowID := s.workflowManager.ResumeWorkflow(record.State)
+		s.storeWorkflowMapping(record.UUID, workflowID)
+		if record.State.Status.Ended() {
+			s.schedulePrune(workflowID, record.State)
+			continue
+		}
+		if len(record.State.Jobs) == 0 {
+			continue
+		}
+
+		var workflowYAML *WorkflowYAML
+		if record.State.YamlContent != "" {
+			workflowYAML, err = s.parseWorkflowYAMLContent(record.State.YamlContent)
+		} else {
+			workflowYAML, err = s.parseWorkflowYAML(record.State.Workflow)
+		}
+		if err != nil {
+			log.Warn("cannot resume workflow, its YAML is unreadable", "error", err)
+			continue
+		}
+		if record.Annotations != nil {
+			workflowYAML.Annotations = record.Annotations
+		}
+		ctx := withTenant(context.Background(), record.Tenant)
+
+		for jobID, dep := range record.State.Jobs {
+			if dep.JobID == dep.InternalName {
+				continue // Never started
+			}
+			job, exists := s.jobStore.Job(jobID)
+			if !exists {
+				log.Warn("workflow job lost across the restart, counting it as failed", "jobName", dep.InternalName, "jobId", jobID)
+				s.workflowManager.OnJobStateChange(jobID, domain.StatusFailed)
+				continue
+			}
+			s.workflowManager.OnJobStateChange(jobID, job.Status)
+			if !endedJobStatuses[job.Status] {
+				s.monitorWorkflowJob(jobID)
+			}
+		}
+
+		s.checkpointWorkflow(workflowID)
+		go s.orchestrateWorkflow(ctx, workflowID, workflowYAML, record.Files)
+		resumed++
+		log.Info("workflow resumed", "workflowId", workflowID)
+	}
+
+	s.logger.Info("saved workflows loaded", "workflows", len(records)-pruned, "resumed", resumed, "pruned", pruned)
+	return nil
+}
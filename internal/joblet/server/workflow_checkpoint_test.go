@@ -0,0 +1,127 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/checkpoint"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestResumeWorkflows(t *testing.T) {
+	const (
+		runningUUID = "3c2b1a09-8f7e-4d6c-b5a4-938271605f4e"
+		endedUUID   = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+		expiredUUID = "1f2e3d4c-5b6a-4978-8a7b-6c5d4e3f2a1b"
+	)
+	checkpoints := checkpoint.New(checkpoint.NewMemoryService(), logger.New())
+
+	// A workflow running when joblet stopped: extract completed meanwhile,
+	// lost is gone from the job store and load waits for lost
+	running := &workflow.WorkflowState{
+		Workflow: "etl",
+		YamlContent: `jobs:
+  extract: {command: ./extract.sh}
+  lost: {command: ./lost.sh}
+  load: {command: ./load.sh, requires: [{lost: COMPLETED}]}`,
+		Jobs: map[string]*workflow.JobDependency{
+			"uuid-extract": {JobID: "uuid-extract", InternalName: "extract", Status: domain.StatusRunning},
+			"uuid-lost":    {JobID: "uuid-lost", InternalName: "lost", Status: domain.StatusRunning},
+			"load": {JobID: "load", InternalName: "load", Status: domain.StatusPending, Requirements: []workflow.Requirement{
+				{Type: workflow.RequirementSimple, JobID: "lost", Status: "COMPLETED"},
+			}},
+		},
+		JobOrder:  []string{"extract", "lost", "load"},
+		Status:    workflow.WorkflowRunning,
+		CreatedAt: time.Now(),
+		TotalJobs: 3,
+	}
+	if err := checkpoints.Create(checkpoint.Workflow{UUID: runningUUID, Tenant: "analytics"}, running); err != nil {
+		t.Fatal(err)
+	}
+	endedAt := time.Now().Add(-time.Hour)
+	ended := &workflow.WorkflowState{
+		Workflow:      "report",
+		CreatedAt:     endedAt,
+		CompletedAt:   &endedAt,
+		Jobs:          map[string]*workflow.JobDependency{"uuid-report": {JobID: "uuid-report", InternalName: "report", Status: domain.StatusCompleted}},
+		Status:        workflow.WorkflowCompleted,
+		TotalJobs:     1,
+		CompletedJobs: 1,
+	}
+	if err := checkpoints.Create(checkpoint.Workflow{UUID: endedUUID}, ended); err != nil {
+		t.Fatal(err)
+	}
+	longAgo := time.Now().Add(-8 * 24 * time.Hour)
+	expired := &workflow.WorkflowState{Workflow: "old", Status: workflow.WorkflowFailed, CreatedAt: longAgo, CompletedAt: &longAgo}
+	if err := checkpoints.Create(checkpoint.Workflow{UUID: expiredUUID}, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(id string) (*domain.Job, bool) {
+		if id == "uuid-extract" {
+			return &domain.Job{Uuid: id, Status: domain.StatusCompleted}, true
+		}
+		return nil, false
+	}
+	s := &WorkflowServiceServer{
+		jobStore:            store,
+		workflowManager:     workflow.NewWorkflowManager(),
+		logger:              logger.New(),
+		workflowUuids:       prefixindex.New[int](),
+		workflowIDToUuid:    make(map[int]string),
+		checkpoints:         checkpoints,
+		checkpointRetention: 7 * 24 * time.Hour,
+	}
+	if err := s.ResumeWorkflows(); err != nil {
+		t.Fatalf("ResumeWorkflows() error = %v", err)
+	}
+
+	workflowID, found := s.lookupWorkflowID(runningUUID)
+	if !found {
+		t.Fatal("resumed workflow not found by its UUID")
+	}
+	state, err := s.workflowManager.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Jobs["uuid-extract"].Status != domain.StatusCompleted || state.Jobs["uuid-lost"].Status != domain.StatusFailed {
+		t.Errorf("resumed jobs = extract %s, lost %s, want the job store's word", state.Jobs["uuid-extract"].Status, state.Jobs["uuid-lost"].Status)
+	}
+	if !state.Jobs["load"].Impossible || !state.Status.Ended() {
+		t.Errorf("resumed workflow = %s with load impossible %v, want it ended", state.Status, state.Jobs["load"].Impossible)
+	}
+	if id, _ := s.workflowManager.GetJobWorkflow("load"); id != workflowID {
+		t.Errorf("GetJobWorkflow(load) = %d, want the resumed workflow", id)
+	}
+
+	if endedID, found := s.lookupWorkflowID(endedUUID); !found {
+		t.Error("ended workflow not listed again")
+	} else if state, _ := s.workflowManager.GetWorkflowStatus(endedID); state.Status != workflow.WorkflowCompleted {
+		t.Errorf("ended workflow = %s", state.Status)
+	}
+
+	// A workflow past its retention is deleted instead of listed again
+	if _, found := s.lookupWorkflowID(expiredUUID); found {
+		t.Error("workflow past its retention listed again")
+	}
+
+	// The outcome of the resume is saved in turn
+	records, err := checkpoints.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Errorf("saved workflows = %d, want the workflow past its retention deleted", len(records))
+	}
+	for _, record := range records {
+		if record.UUID == runningUUID && record.State.Status != state.Status {
+			t.Errorf("saved workflow = %s, want the resumed status", record.State.Status)
+		}
+	}
+}
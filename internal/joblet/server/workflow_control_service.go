@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/core/validation"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WorkflowControlServiceServer implements the gRPC service changing running
+// workflows through the workflow service's manager, and validating workflows
+// with its validator
+type WorkflowControlServiceServer struct {
+	workflowspb.UnimplementedWorkflowControlServiceServer
+	auth      auth2.GRPCAuthorization
+	workflows *WorkflowServiceServer
+	logger    *logger.Logger
+}
+
+// NewWorkflowControlServiceServer creates a new workflow control service server
+func NewWorkflowControlServiceServer(auth auth2.GRPCAuthorization, workflows *WorkflowServiceServer) *WorkflowControlServiceServer {
+	return &WorkflowControlServiceServer{
+		auth:      auth,
+		workflows: workflows,
+		logger:    logger.WithField("component", "workflow-control"),
+	}
+}
+
+// CancelWorkflowJobs cancels a job of a workflow and, with cascade, every job
+// downstream of it. Jobs that have not started are canceled so they never
+// start, running and scheduled ones are stopped, and the rest of the workflow
+// carries on.
+func (s *WorkflowControlServiceServer) CancelWorkflowJobs(ctx context.Context, req *workflowspb.CancelWorkflowJobsRequest) (*workflowspb.CancelWorkflowJobsResponse, error) {
+	log := s.logger.WithFields("operation", "CancelWorkflowJobs", "workflowUuid", req.WorkflowUuid, "job", req.Job, "cascade", req.Cascade)
+	if err := s.auth.Authorized(ctx, auth2.StopJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if req.WorkflowUuid == "" || req.Job == "" {
+		return nil, status.Error(codes.InvalidArgument, "workflow UUID and job name are required")
+	}
+
+	w := s.workflows
+	workflowID, found := w.lookupWorkflowID(req.WorkflowUuid)
+	if !found {
+		return nil, w.workflowLookupError(req.WorkflowUuid)
+	}
+	selected, err := w.workflowManager.CancelJobs(workflowID, req.Job, req.Cascade)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	w.checkpointWorkflow(workflowID)
+
+	response := w.stopCanceledJobs(ctx, workflowID, selected, "workflow_job_canceled")
+	log.Info("workflow jobs canceled", "canceled", len(response.Canceled), "stopped", len(response.Stopped),
+		"failed", len(response.Failed), "skipped", len(response.Skipped))
+	return response, nil
+}
+
+// CancelWorkflow cancels a whole workflow: its orchestration stops starting
+// jobs, the jobs that have not started are canceled and the running and
+// scheduled ones are stopped. The workflow ends CANCELED.
+func (s *WorkflowControlServiceServer) CancelWorkflow(ctx context.Context, req *workflowspb.CancelWorkflowRequest) (*workflowspb.CancelWorkflowJobsResponse, error) {
+	log := s.logger.WithFields("operation", "CancelWorkflow", "workflowUuid", req.WorkflowUuid)
+	if err := s.auth.Authorized(ctx, auth2.StopJobOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return nil, err
+	}
+	if req.WorkflowUuid == "" {
+		return nil, status.Error(codes.InvalidArgument, "workflow UUID is required")
+	}
+
+	w := s.workflows
+	workflowID, found := w.lookupWorkflowID(req.WorkflowUuid)
+	if !found {
+		return nil, w.workflowLookupError(req.WorkflowUuid)
+	}
+	w.haltOrchestration(ctx, workflowID)
+	selected, err := w.workflowManager.CancelWorkflow(workflowID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	w.checkpointWorkflow(workflowID)
+
+	response := w.stopCanceledJobs(ctx, workflowID, selected, "workflow_canceled")
+	w.checkpointWorkflow(workflowID)
+
+	log.Info("workflow canceled", "canceled", len(response.Canceled), "stopped", len(response.Stopped),
+		"failed", len(response.Failed), "skipped", len(response.Skipped), "status", response.WorkflowStatus)
+	return response, nil
+}
+
+// stopCanceledJobs stops the started jobs among those CancelJobs or
+// CancelWorkflow selected, a few at a time, and reports what became of each
+func (s *WorkflowServiceServer) stopCanceledJobs(ctx context.Context, workflowID int, selected []workflow.CanceledJob, reason string) *workflowspb.CancelWorkflowJobsResponse {
+	outcomes := make([]*workflowspb.WorkflowJobOutcome, len(selected))
+	stopped := make([]bool, len(selected))
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxParallelStops)
+	)
+	for i, job := range selected {
+		outcome := &workflowspb.WorkflowJobOutcome{Name: job.Name, Status: string(job.Status)}
+		outcomes[i] = outcome
+		if job.Status == domain.StatusPending {
+			continue
+		}
+		outcome.JobUuid = job.JobID
+		// The workflow learns of status changes by polling, so the job store
+		// has the latest word on whether the job is still going
+		current, exists := s.jobStore.Job(job.JobID)
+		if exists {
+			outcome.Status = string(current.Status)
+		}
+		if !job.Started() || !exists || !stoppable(current) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := s.joblet.StopJob(ctx, interfaces.StopJobRequest{JobID: outcomes[i].JobUuid, Reason: reason})
+			if err != nil {
+				outcomes[i].Error = err.Error()
+				return
+			}
+			stopped[i] = true
+			if job, exists := s.jobStore.Job(outcomes[i].JobUuid); exists {
+				s.workflowManager.OnJobStateChange(job.Uuid, job.Status)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	response := &workflowspb.CancelWorkflowJobsResponse{WorkflowUuid: s.getFullUuidForWorkflowID(workflowID)}
+	for i, outcome := range outcomes {
+		switch {
+		case outcome.Status == string(domain.StatusPending):
+			response.Canceled = append(response.Canceled, outcome)
+		case stopped[i]:
+			response.Stopped = append(response.Stopped, outcome)
+		case outcome.Error != "":
+			response.Failed = append(response.Failed, outcome)
+		default:
+			response.Skipped = append(response.Skipped, outcome)
+		}
+	}
+	if state, err := s.workflowManager.GetWorkflowStatus(workflowID); err == nil {
+		response.WorkflowStatus = string(state.Status)
+	}
+	return response
+}
+
+// ValidateWorkflow runs every check the server makes before running a
+// workflow, sending the result of each check as it completes so clients can
+// list all the problems at once. The uploads are checked against the files
+// the client would send. A workflow that does not parse gets a single
+// failed syntax check.
+func (s *WorkflowControlServiceServer) ValidateWorkflow(req *workflowspb.ValidateWorkflowRequest, stream workflowspb.WorkflowControlService_ValidateWorkflowServer) error {
+	ctx := stream.Context()
+	log := s.logger.WithFields("operation", "ValidateWorkflow", "contentLength", len(req.YamlContent), "files", len(req.Files))
+	if err := s.auth.Authorized(ctx, auth2.ValidateWorkflowOp); err != nil {
+		log.Warn("authorization failed", "error", err)
+		return err
+	}
+	if req.YamlContent == "" {
+		return status.Error(codes.InvalidArgument, "workflow YAML content is required")
+	}
+
+	send := func(check string, problems []validation.Problem) error {
+		result := &workflowspb.WorkflowCheckResult{Check: check}
+		for _, p := range problems {
+			result.Problems = append(result.Problems, &workflowspb.WorkflowProblem{Code: p.Code, Job: p.Job, Message: p.Message})
+		}
+		return stream.Send(result)
+	}
+
+	workflowYAML, err := s.workflows.parseWorkflowYAMLContent(req.YamlContent)
+	if err != nil {
+		log.Info("workflow does not parse", "error", err)
+		return send(validation.CheckSyntax, []validation.Problem{{Code: validation.CodeInvalidSyntax, Message: err.Error()}})
+	}
+	if err := applyRequestAnnotations(ctx, workflowYAML); err != nil {
+		return err
+	}
+
+	failed := 0
+	var sendErr error
+	s.workflows.workflowValidator.Check(s.workflows.withResolvedRuntimes(ctx, *workflowYAML), func(check string, problems []validation.Problem) {
+		failed += len(problems)
+		if sendErr == nil {
+			sendErr = send(check, problems)
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	uploads := validation.UploadProblems(*workflowYAML, req.Files)
+	failed += len(uploads)
+	if err := send(validation.CheckUploads, uploads); err != nil {
+		return err
+	}
+
+	log.Info("workflow validated", "problems", failed)
+	return nil
+}
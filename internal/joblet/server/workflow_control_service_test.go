@@ -0,0 +1,319 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces/interfacesfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/core/validation"
+	"github.com/ehsaniara/joblet/internal/joblet/core/volume"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
+	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+
+	"google.golang.org/grpc"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelWorkflowJobs(t *testing.T) {
+	const workflowUUID = "7b1d2c3e-4f5a-6789-abcd-ef0123456789"
+
+	manager := workflow.NewWorkflowManager()
+	jobs := map[string]*workflow.JobDependency{
+		"fetch": {JobID: "fetch", InternalName: "fetch", Status: domain.StatusPending},
+		"train": {JobID: "train", InternalName: "train", Status: domain.StatusPending,
+			Requirements: []workflow.Requirement{{Type: workflow.RequirementSimple, JobID: "fetch", Status: "COMPLETED"}}},
+		"docs": {JobID: "docs", InternalName: "docs", Status: domain.StatusPending},
+	}
+	workflowID, err := manager.CreateWorkflow("pipeline", jobs, []string{"fetch", "train", "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	stored := map[string]*domain.Job{}
+	for _, name := range []string{"fetch", "docs"} {
+		jobID := "uuid-" + name
+		if err := manager.UpdateJobID(name, jobID); err != nil {
+			t.Fatal(err)
+		}
+		manager.OnJobStateChange(jobID, domain.StatusRunning)
+		stored[jobID] = &domain.Job{Uuid: jobID, Name: name, Status: domain.StatusRunning}
+	}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(jobID string) (*domain.Job, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		job, exists := stored[jobID]
+		if !exists {
+			return nil, false
+		}
+		copied := *job
+		return &copied, true
+	}
+	joblet := &interfacesfakes.FakeJoblet{}
+	joblet.StopJobStub = func(_ context.Context, req interfaces.StopJobRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		stored[req.JobID].Status = domain.StatusStopped
+		return nil
+	}
+
+	workflows := &WorkflowServiceServer{
+		jobStore:         store,
+		joblet:           joblet,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+	}
+	workflows.storeWorkflowMapping(workflowUUID, workflowID)
+	s := NewWorkflowControlServiceServer(&authfakes.FakeGRPCAuthorization{}, workflows)
+
+	res, err := s.CancelWorkflowJobs(context.Background(), &workflowspb.CancelWorkflowJobsRequest{
+		WorkflowUuid: "7b1d2c3e", Job: "fetch", Cascade: true,
+	})
+	if err != nil {
+		t.Fatalf("CancelWorkflowJobs() error = %v", err)
+	}
+	if res.WorkflowUuid != workflowUUID || len(res.Stopped) != 1 || res.Stopped[0].JobUuid != "uuid-fetch" ||
+		len(res.Canceled) != 1 || res.Canceled[0].Name != "train" || len(res.Failed) != 0 || len(res.Skipped) != 0 {
+		t.Fatalf("response = %v", res)
+	}
+	if joblet.StopJobCallCount() != 1 {
+		t.Errorf("stopped %d jobs, want fetch only", joblet.StopJobCallCount())
+	}
+	// docs keeps the workflow running
+	if res.WorkflowStatus != string(workflow.WorkflowRunning) {
+		t.Errorf("workflow status = %s, want RUNNING", res.WorkflowStatus)
+	}
+
+	// A second cancel finds the sub-tree already over
+	res, err = s.CancelWorkflowJobs(context.Background(), &workflowspb.CancelWorkflowJobsRequest{
+		WorkflowUuid: workflowUUID, Job: "fetch", Cascade: true,
+	})
+	if err != nil || len(res.Skipped) != 2 || len(res.Stopped) != 0 || len(res.Canceled) != 0 {
+		t.Errorf("second cancel = %v, %v, want both jobs skipped", res, err)
+	}
+
+	for _, req := range []*workflowspb.CancelWorkflowJobsRequest{
+		{WorkflowUuid: workflowUUID, Job: "deploy"},
+		{WorkflowUuid: "ffffffff", Job: "fetch"},
+	} {
+		if _, err := s.CancelWorkflowJobs(context.Background(), req); status.Code(err) != codes.NotFound {
+			t.Errorf("CancelWorkflowJobs(%v) error = %v, want NotFound", req, err)
+		}
+	}
+	if _, err := s.CancelWorkflowJobs(context.Background(), &workflowspb.CancelWorkflowJobsRequest{WorkflowUuid: workflowUUID}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CancelWorkflowJobs() without a job error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestCancelWorkflow(t *testing.T) {
+	const workflowUUID = "5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9"
+
+	manager := workflow.NewWorkflowManager()
+	jobs := map[string]*workflow.JobDependency{
+		"fetch": {JobID: "fetch", InternalName: "fetch", Status: domain.StatusPending},
+		"train": {JobID: "train", InternalName: "train", Status: domain.StatusPending,
+			Requirements: []workflow.Requirement{{Type: workflow.RequirementSimple, JobID: "fetch", Status: "COMPLETED"}}},
+		"docs": {JobID: "docs", InternalName: "docs", Status: domain.StatusPending},
+	}
+	workflowID, err := manager.CreateWorkflow("pipeline", jobs, []string{"fetch", "train", "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	stored := map[string]*domain.Job{}
+	for _, name := range []string{"fetch", "docs"} {
+		jobID := "uuid-" + name
+		if err := manager.UpdateJobID(name, jobID); err != nil {
+			t.Fatal(err)
+		}
+		manager.OnJobStateChange(jobID, domain.StatusRunning)
+		stored[jobID] = &domain.Job{Uuid: jobID, Name: name, Status: domain.StatusRunning}
+	}
+	store := &adaptersfakes.FakeJobStorer{}
+	store.JobStub = func(jobID string) (*domain.Job, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		job, exists := stored[jobID]
+		if !exists {
+			return nil, false
+		}
+		copied := *job
+		return &copied, true
+	}
+	joblet := &interfacesfakes.FakeJoblet{}
+	joblet.StopJobStub = func(_ context.Context, req interfaces.StopJobRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		stored[req.JobID].Status = domain.StatusStopped
+		return nil
+	}
+
+	workflows := &WorkflowServiceServer{
+		jobStore:         store,
+		joblet:           joblet,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+	}
+	workflows.storeWorkflowMapping(workflowUUID, workflowID)
+	s := NewWorkflowControlServiceServer(&authfakes.FakeGRPCAuthorization{}, workflows)
+
+	orchestrated := make(chan struct{})
+	go func() {
+		defer close(orchestrated)
+		workflows.orchestrateWorkflow(context.Background(), workflowID, &WorkflowYAML{}, nil)
+	}()
+	for {
+		if _, running := workflows.orchestrations.Load(workflowID); running {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	res, err := s.CancelWorkflow(context.Background(), &workflowspb.CancelWorkflowRequest{WorkflowUuid: "5e6f7a8b"})
+	if err != nil {
+		t.Fatalf("CancelWorkflow() error = %v", err)
+	}
+	if res.WorkflowUuid != workflowUUID || len(res.Stopped) != 2 || len(res.Canceled) != 1 || res.Canceled[0].Name != "train" ||
+		len(res.Failed) != 0 || len(res.Skipped) != 0 {
+		t.Fatalf("response = %v", res)
+	}
+	if res.WorkflowStatus != string(workflow.WorkflowCanceled) {
+		t.Errorf("workflow status = %s, want CANCELED", res.WorkflowStatus)
+	}
+	select {
+	case <-orchestrated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("orchestration still running after the workflow was canceled")
+	}
+
+	if _, err := s.CancelWorkflow(context.Background(), &workflowspb.CancelWorkflowRequest{WorkflowUuid: "ffffffff"}); status.Code(err) != codes.NotFound {
+		t.Errorf("CancelWorkflow() of an unknown workflow error = %v, want NotFound", err)
+	}
+	if _, err := s.CancelWorkflow(context.Background(), &workflowspb.CancelWorkflowRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CancelWorkflow() without UUID error = %v, want InvalidArgument", err)
+	}
+}
+
+// knownVolumes is a volume store holding the named volumes
+type knownVolumes struct {
+	adapters.VolumeStorer
+	names []string
+}
+
+func (v knownVolumes) GetVolume(name string) (*domain.Volume, bool) {
+	for _, known := range v.names {
+		if known == name {
+			return &domain.Volume{Name: name}, true
+		}
+	}
+	return nil, false
+}
+
+type fakeCheckStream struct {
+	grpc.ServerStream
+	results []*workflowspb.WorkflowCheckResult
+}
+
+func (f *fakeCheckStream) Context() context.Context { return context.Background() }
+
+func (f *fakeCheckStream) Send(result *workflowspb.WorkflowCheckResult) error {
+	f.results = append(f.results, result)
+	return nil
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	runtimesPath := t.TempDir()
+	dir := filepath.Join(runtimesPath, "python-3.11", "1.2.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "runtime.yml"), []byte("name: python-3.11\nversion: \"1.2.0\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := platform.NewPlatform()
+	workflows := &WorkflowServiceServer{
+		logger: logger.New(),
+		workflowValidator: validation.NewWorkflowValidator(
+			volume.NewManager(knownVolumes{names: []string{"data"}}, p, t.TempDir()),
+			runtime.NewResolver(runtimesPath, p)),
+	}
+	s := NewWorkflowControlServiceServer(&authfakes.FakeGRPCAuthorization{}, workflows)
+
+	validate := func(yamlContent string, files ...string) []*workflowspb.WorkflowCheckResult {
+		t.Helper()
+		stream := &fakeCheckStream{}
+		if err := s.ValidateWorkflow(&workflowspb.ValidateWorkflowRequest{YamlContent: yamlContent, Files: files}, stream); err != nil {
+			t.Fatal(err)
+		}
+		return stream.results
+	}
+	failed := func(results []*workflowspb.WorkflowCheckResult) map[string][]string {
+		codes := make(map[string][]string)
+		for _, result := range results {
+			for _, p := range result.Problems {
+				codes[result.Check] = append(codes[result.Check], p.Job+":"+p.Code)
+			}
+		}
+		return codes
+	}
+
+	// Every problem is reported, not only the first one
+	results := validate(`
+jobs:
+  fetch:
+    command: "python3"
+    runtime: "python-3.12"
+    volumes: ["data", "cache"]
+    uploads:
+      files: ["fetch.py"]
+  train:
+    command: "python3"
+    runtime: "python-3.11@1.2.0"
+    volumes: ["models"]
+    requires:
+      - prepare: "COMPLETED"
+`, "train.py")
+	if len(results) != 10 || results[0].Check != validation.CheckDependencies || results[9].Check != validation.CheckUploads {
+		t.Fatalf("ValidateWorkflow() sent %d results: %v", len(results), results)
+	}
+	want := map[string][]string{
+		validation.CheckDependencies: {"train:" + validation.CodeUnknownDependency},
+		validation.CheckVolumes:      {"fetch:" + validation.CodeMissingVolume, "train:" + validation.CodeMissingVolume},
+		validation.CheckRuntimes:     {"fetch:" + validation.CodeMissingRuntime},
+		validation.CheckUploads:      {"fetch:" + validation.CodeMissingUpload},
+	}
+	if got := failed(results); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateWorkflow() problems = %v, want %v", got, want)
+	}
+
+	if got := failed(validate("jobs:\n  fetch:\n    command: \"true\"\n")); len(got) != 0 {
+		t.Errorf("ValidateWorkflow() of a valid workflow = %v", got)
+	}
+
+	results = validate("jobs: [")
+	if len(results) != 1 || results[0].Check != validation.CheckSyntax || results[0].Problems[0].Code != validation.CodeInvalidSyntax {
+		t.Errorf("ValidateWorkflow() of broken YAML = %v", results)
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMergeEnvironmentVariables(t *testing.T) {
+	s := &WorkflowServiceServer{logger: logger.New()}
+	workflowYAML := &WorkflowYAML{
+		Environment: map[string]string{
+			"STAGE":     "prod",
+			"DATA_DIR":  "/data/${STAGE}",
+			"PATH_EXTS": "/opt/tools",
+			"API_TOKEN": "global-token",
+		},
+		Secrets: map[string]string{
+			"DATABASE_URL": "postgres://db/${STAGE}",
+		},
+	}
+	jobSpec := JobSpec{
+		Environment: map[string]string{
+			"STAGE":        "staging",                  // Overrides the global
+			"OUTPUT":       "${DATA_DIR}/out",          // Resolved global
+			"PATH_EXTS":    "${PATH_EXTS}:/job/bin",    // Extends the global
+			"SOURCE":       "${DATABASE_URL}/raw",      // References a secret (the job's)
+			"DATABASE_URL": "${DATABASE_URL}?ssl=true", // Stays secret
+		},
+	}
+
+	env, secretEnv := s.mergeEnvironmentVariables(workflowYAML, jobSpec)
+
+	wantEnv := map[string]string{
+		"STAGE":     "staging",
+		"DATA_DIR":  "/data/prod",
+		"OUTPUT":    "/data/prod/out",
+		"PATH_EXTS": "/opt/tools:/job/bin",
+	}
+	wantSecretEnv := map[string]string{
+		"API_TOKEN":    "global-token",
+		"DATABASE_URL": "postgres://db/prod?ssl=true",
+		"SOURCE":       "postgres://db/prod?ssl=true/raw",
+	}
+	if !reflect.DeepEqual(env, wantEnv) {
+		t.Errorf("environment = %v, want %v", env, wantEnv)
+	}
+	if !reflect.DeepEqual(secretEnv, wantSecretEnv) {
+		t.Errorf("secret environment = %v, want %v", secretEnv, wantSecretEnv)
+	}
+}
+
+func TestMergeEnvironmentVariablesJobOnly(t *testing.T) {
+	s := &WorkflowServiceServer{logger: logger.New()}
+	env, secretEnv := s.mergeEnvironmentVariables(&WorkflowYAML{}, JobSpec{
+		Environment: map[string]string{"MODE": "batch", "GITHUB_TOKEN": "ghp_123"},
+	})
+
+	if !reflect.DeepEqual(env, map[string]string{"MODE": "batch"}) {
+		t.Errorf("environment = %v", env)
+	}
+	if !reflect.DeepEqual(secretEnv, map[string]string{"GITHUB_TOKEN": "ghp_123"}) {
+		t.Errorf("secret environment = %v", secretEnv)
+	}
+}
+
+func TestMergeCgroupParams(t *testing.T) {
+	merged := mergeCgroupParams(map[string]string{"cpu.weight": "50", "io.latency": "8:0 target=10"}, map[string]string{"cpu.weight": "200"})
+	if want := map[string]string{"cpu.weight": "200", "io.latency": "8:0 target=10"}; !reflect.DeepEqual(merged, want) {
+		t.Errorf("merged = %v, want %v", merged, want)
+	}
+	if merged := mergeCgroupParams(nil, nil); merged != nil {
+		t.Errorf("merged without params = %v, want nil", merged)
+	}
+}
+
+func TestApplyRequestAnnotations(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constants.AnnotationsHeader,
+		`{"ci.provider":"gitlab","ci.run_id":"1234","ticket":"from-ci"}`))
+	workflowYAML := &WorkflowYAML{Annotations: map[string]string{"ticket": "OPS-42"}}
+	if err := applyRequestAnnotations(ctx, workflowYAML); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"ci.provider": "gitlab", "ci.run_id": "1234", "ticket": "OPS-42"}; !reflect.DeepEqual(workflowYAML.Annotations, want) {
+		t.Errorf("annotations = %v, want %v", workflowYAML.Annotations, want)
+	}
+
+	workflowYAML = &WorkflowYAML{}
+	if err := applyRequestAnnotations(context.Background(), workflowYAML); err != nil || workflowYAML.Annotations != nil {
+		t.Errorf("without annotations: %v, %v", workflowYAML.Annotations, err)
+	}
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(constants.AnnotationsHeader, "ci.run_id=1234"))
+	if err := applyRequestAnnotations(ctx, &WorkflowYAML{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid header error = %v, want InvalidArgument", err)
+	}
+}
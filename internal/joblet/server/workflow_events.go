@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// WatchJobEvents feeds the status changes the job store publishes to the
+// workflows, so a job ending starts the jobs waiting for it at once rather
+// than at the next poll. Polling carries on only as a fallback, less often,
+// until ctx ends or the job store stops publishing.
+func (s *WorkflowServiceServer) WatchJobEvents(ctx context.Context) error {
+	events := s.jobStore.PubSub()
+	if events == nil {
+		return nil
+	}
+	updates, unsubscribe, err := events.Subscribe(ctx, "jobs")
+	if err != nil {
+		return err
+	}
+
+	s.setEventDriven(true)
+	go func() {
+		defer s.setEventDriven(false)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-updates:
+				if !ok {
+					s.logger.Warn("job events ended, workflows fall back to polling")
+					return
+				}
+				s.onJobEvent(update.Payload)
+			}
+		}
+	}()
+	return nil
+}
+
+// onJobEvent applies a job's new status to its workflow, if any
+func (s *WorkflowServiceServer) onJobEvent(event adapters.JobEvent) {
+	if event.Type != "UPDATED" || event.Status == "" {
+		return
+	}
+	workflowID, ok := s.workflowManager.GetJobWorkflow(event.JobID)
+	if !ok {
+		return
+	}
+	status := domain.JobStatus(event.Status)
+	if s.workflowManager.OnJobStateChange(event.JobID, status) {
+		s.queueCheckpoint(workflowID)
+	}
+	if endedJobStatuses[status] {
+		s.monitoredJobs.Delete(event.JobID)
+	}
+}
+
+// queueCheckpoint saves a workflow's state in the background, so writing it
+// does not hold up the events that follow. Changes made while a checkpoint is
+// queued are saved with it, as it reads the state only once it runs.
+func (s *WorkflowServiceServer) queueCheckpoint(workflowID int) {
+	if s.checkpoints == nil {
+		return
+	}
+	if _, queued := s.checkpointsQueued.LoadOrStore(workflowID, struct{}{}); queued {
+		return
+	}
+	go func() {
+		s.checkpointsQueued.Delete(workflowID)
+		s.checkpointWorkflow(workflowID)
+	}()
+}
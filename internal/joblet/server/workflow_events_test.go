@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/adapters/adaptersfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces/interfacesfakes"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
+	"github.com/ehsaniara/joblet/internal/joblet/pubsub"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/checkpoint"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestWatchJobEvents(t *testing.T) {
+	manager := workflow.NewWorkflowManager()
+	jobs := map[string]*workflow.JobDependency{
+		"build": {JobID: "build", InternalName: "build", Status: domain.StatusPending},
+		"test": {JobID: "test", InternalName: "test", Status: domain.StatusPending,
+			Requirements: []workflow.Requirement{{Type: workflow.RequirementSimple, JobID: "build", Status: "COMPLETED"}}},
+	}
+	workflowID, err := manager.CreateWorkflow("ci", jobs, []string{"build", "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UpdateJobID("build", "uuid-build"); err != nil {
+		t.Fatal(err)
+	}
+	manager.OnJobStateChange("uuid-build", domain.StatusRunning)
+
+	events := pubsub.NewPubSub[adapters.JobEvent]()
+	defer events.Close()
+	store := &adaptersfakes.FakeJobStorer{}
+	store.PubSubReturns(events)
+	store.JobReturns(&domain.Job{Uuid: "uuid-test", Status: domain.StatusRunning}, true)
+	started := make(chan string, 1)
+	joblet := &interfacesfakes.FakeJoblet{}
+	joblet.StartJobStub = func(_ context.Context, req interfaces.StartJobRequest) (*domain.Job, error) {
+		started <- req.Name
+		return &domain.Job{Uuid: "uuid-" + req.Name, Status: domain.StatusRunning}, nil
+	}
+
+	s := &WorkflowServiceServer{
+		jobStore:         store,
+		joblet:           joblet,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, modeChanged := s.pollInterval(workflowOrchestrationInterval)
+	if err := s.WatchJobEvents(ctx); err != nil {
+		t.Fatalf("WatchJobEvents() error = %v", err)
+	}
+	select {
+	case <-modeChanged:
+	default:
+		t.Error("mode change not signalled when job events started")
+	}
+	if got, _ := s.pollInterval(workflowOrchestrationInterval); got != eventFallbackInterval {
+		t.Errorf("poll interval with job events = %v, want %v", got, eventFallbackInterval)
+	}
+
+	workflowYAML := &WorkflowYAML{Jobs: map[string]JobSpec{
+		"build": {Command: "make"},
+		"test":  {Command: "make", Args: []string{"test"}},
+	}}
+	go s.orchestrateWorkflow(ctx, workflowID, workflowYAML, nil)
+
+	// Nothing is ready until build completes, which starts test right away
+	// rather than at the next poll
+	select {
+	case name := <-started:
+		t.Fatalf("job %s started before build completed", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := events.Publish(ctx, "jobs", adapters.JobEvent{Type: "UPDATED", JobID: "uuid-build", Status: string(domain.StatusCompleted)}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case name := <-started:
+		if name != "test" {
+			t.Errorf("started job %s, want test", name)
+		}
+	case <-time.After(workflowOrchestrationInterval / 2):
+		t.Fatal("test not started on the job event")
+	}
+}
+
+func TestWatchJobEventsWithoutPubSub(t *testing.T) {
+	s := &WorkflowServiceServer{
+		jobStore:        &adaptersfakes.FakeJobStorer{},
+		workflowManager: workflow.NewWorkflowManager(),
+		logger:          logger.New(),
+	}
+	if err := s.WatchJobEvents(context.Background()); err != nil {
+		t.Fatalf("WatchJobEvents() error = %v", err)
+	}
+	if got, _ := s.pollInterval(jobMonitoringInterval); got != jobMonitoringInterval {
+		t.Errorf("poll interval without job events = %v, want %v", got, jobMonitoringInterval)
+	}
+}
+
+func TestWatchJobEventsCheckpointsInBackground(t *testing.T) {
+	const workflowUUID = "5e4d3c2b-1a09-4f8e-9d7c-6b5a49382716"
+	manager := workflow.NewWorkflowManager()
+	jobs := map[string]*workflow.JobDependency{
+		"build": {JobID: "build", InternalName: "build", Status: domain.StatusPending},
+		"lint":  {JobID: "lint", InternalName: "lint", Status: domain.StatusPending},
+	}
+	workflowID, err := manager.CreateWorkflow("ci", jobs, []string{"build", "lint"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"build", "lint"} {
+		if err := manager.UpdateJobID(name, "uuid-"+name); err != nil {
+			t.Fatal(err)
+		}
+		manager.OnJobStateChange("uuid-"+name, domain.StatusRunning)
+	}
+
+	checkpoints := checkpoint.New(checkpoint.NewMemoryService(), logger.New())
+	state, _ := manager.GetWorkflowStatus(workflowID)
+	if err := checkpoints.Create(checkpoint.Workflow{UUID: workflowUUID}, state); err != nil {
+		t.Fatal(err)
+	}
+
+	events := pubsub.NewPubSub[adapters.JobEvent]()
+	defer events.Close()
+	store := &adaptersfakes.FakeJobStorer{}
+	store.PubSubReturns(events)
+	s := &WorkflowServiceServer{
+		jobStore:         store,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+		checkpoints:      checkpoints,
+	}
+	s.storeWorkflowMapping(workflowUUID, workflowID)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.WatchJobEvents(ctx); err != nil {
+		t.Fatalf("WatchJobEvents() error = %v", err)
+	}
+
+	// With the checkpoint stuck on slow storage, later events still apply
+	s.checkpointMu.Lock()
+	for _, jobID := range []string{"uuid-build", "uuid-lint"} {
+		if err := events.Publish(ctx, "jobs", adapters.JobEvent{Type: "UPDATED", JobID: jobID, Status: string(domain.StatusCompleted)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		state, _ := manager.GetWorkflowStatus(workflowID)
+		if state.Status == workflow.WorkflowCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			s.checkpointMu.Unlock()
+			t.Fatalf("workflow status = %s while a checkpoint is pending, want COMPLETED", state.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.checkpointMu.Unlock()
+
+	// The queued checkpoint saves the latest state once it runs
+	deadline = time.Now().Add(time.Second)
+	for {
+		records, err := checkpoints.Load()
+		if err == nil && len(records) == 1 && records[0].State.Status == workflow.WorkflowCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("saved workflows = %+v, %v, want the completed state", records, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchJobEventsEndMonitoring(t *testing.T) {
+	manager := workflow.NewWorkflowManager()
+	jobs := map[string]*workflow.JobDependency{
+		"build": {JobID: "build", InternalName: "build", Status: domain.StatusPending},
+	}
+	if _, err := manager.CreateWorkflow("ci", jobs, []string{"build"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.UpdateJobID("build", "uuid-build"); err != nil {
+		t.Fatal(err)
+	}
+
+	events := pubsub.NewPubSub[adapters.JobEvent]()
+	store := &adaptersfakes.FakeJobStorer{}
+	store.PubSubReturns(events)
+	store.JobReturns(&domain.Job{Uuid: "uuid-build", Status: domain.StatusRunning}, true)
+	s := &WorkflowServiceServer{
+		jobStore:         store,
+		workflowManager:  manager,
+		logger:           logger.New(),
+		workflowUuids:    prefixindex.New[int](),
+		workflowIDToUuid: make(map[int]string),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.WatchJobEvents(ctx); err != nil {
+		t.Fatalf("WatchJobEvents() error = %v", err)
+	}
+	s.monitorWorkflowJob("uuid-build")
+	if _, monitored := s.monitoredJobs.Load("uuid-build"); !monitored {
+		t.Fatal("running job not monitored")
+	}
+
+	// The job ending is taken from its event, not from the next poll
+	if err := events.Publish(ctx, "jobs", adapters.JobEvent{Type: "UPDATED", JobID: "uuid-build", Status: string(domain.StatusCompleted)}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, monitored := s.monitoredJobs.Load("uuid-build"); !monitored {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job still monitored after its completion event")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Events ending signal the loops to poll at the shorter interval again
+	_, modeChanged := s.pollInterval(jobMonitoringInterval)
+	if err := events.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-modeChanged:
+	case <-time.After(time.Second):
+		t.Fatal("mode change not signalled when job events ended")
+	}
+	if got, _ := s.pollInterval(jobMonitoringInterval); got != jobMonitoringInterval {
+		t.Errorf("poll interval after job events ended = %v, want %v", got, jobMonitoringInterval)
+	}
+}
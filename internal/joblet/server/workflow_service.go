@@ -2,30 +2,45 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
 	"github.com/ehsaniara/joblet/internal/joblet/adapters"
+	"github.com/ehsaniara/joblet/internal/joblet/anomaly"
 	auth2 "github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/clocksync"
 	"github.com/ehsaniara/joblet/internal/joblet/core/interfaces"
+	"github.com/ehsaniara/joblet/internal/joblet/core/upload"
 	"github.com/ehsaniara/joblet/internal/joblet/core/validation"
 	"github.com/ehsaniara/joblet/internal/joblet/core/volume"
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
 	"github.com/ehsaniara/joblet/internal/joblet/mappers"
 	metricsdomain "github.com/ehsaniara/joblet/internal/joblet/metrics/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/prefixindex"
 	"github.com/ehsaniara/joblet/internal/joblet/runtime"
+	"github.com/ehsaniara/joblet/internal/joblet/uploadsync"
 	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/checkpoint"
 	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
 	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
 	"github.com/ehsaniara/joblet/pkg/logger"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
 )
@@ -35,13 +50,28 @@ const (
 	defaultNetworkName = "bridge"
 
 	// workflowOrchestrationInterval is how often we check for ready jobs
+	// when job events are unavailable
 	workflowOrchestrationInterval = 5 * time.Second
 
-	// jobMonitoringInterval is how often we check job status
+	// jobMonitoringInterval is how often we check job status when job
+	// events are unavailable
 	jobMonitoringInterval = 2 * time.Second
 
+	// eventFallbackInterval is how often we check for ready jobs and job
+	// status while job events drive the workflows, in case one was missed:
+	// the job store drops the events a subscriber doesn't take in time
+	eventFallbackInterval = 30 * time.Second
+
+	// checkpointSweepInterval is how often saved workflows are swept for
+	// those past their retention that no scheduled prune deleted
+	checkpointSweepInterval = 24 * time.Hour
+
 	// defaultVolumeSize is the default size for auto-created volumes
 	defaultVolumeSize = "100MB"
+
+	// maxReportedWorkflowMatches caps how many candidate UUIDs are logged
+	// when a workflow UUID prefix is ambiguous
+	maxReportedWorkflowMatches = 10
 )
 
 type WorkflowServiceServer struct {
@@ -55,16 +85,71 @@ type WorkflowServiceServer struct {
 	persistClient     persistpb.PersistServiceClient // Client for historical queries via Unix socket IPC
 	logger            *logger.Logger
 
-	// UUID to workflow ID mapping
-	workflowUuidMap  map[string]int
+	// UUID to workflow ID mapping, kept sorted for prefix lookups,
+	// plus the reverse mapping for reporting full UUIDs
+	workflowUuids    *prefixindex.Index[int]
+	workflowIDToUuid map[int]string
 	workflowMapMutex sync.RWMutex
+
+	// Active jobs by request hash, for RunJob requests with JOBLET_DEDUP
+	dedup *jobDeduplicator
+	// Latest job by request hash, for RunJob requests with JOBLET_CACHE_TTL
+	results *resultCache
+	// Checks client signatures of RunJob and RunWorkflow requests
+	signatures *signatureVerifier
+	// Maps a client identity to its tenant (nil = no tenants)
+	tenants func(client string) string
+	// Resolves a tenant's runtime aliases (nil = no aliases)
+	runtimes func(tenant, spec string) (resolved, pinnedBy string)
+	// Holds workflow files synced by content hash (nil = upload sync disabled)
+	uploads *uploadsync.Cache
+	// Idle time before a log stream sends a heartbeat (0 = none) and how
+	// long a blocked send may take before the stream is abandoned
+	logHeartbeat   time.Duration
+	logSendTimeout time.Duration
+	// Flags runs far above their usual duration (nil = detection disabled)
+	anomalies *anomaly.Detector
+	// Picks runtimes from uploaded dependency files (nil = detection off)
+	detection *runtimeDetection
+	// Checks the clock scheduled jobs start by (nil = no warnings)
+	clock *clocksync.Checker
+	// Volumes and runtimes each tenant may use (nil = all of them)
+	access resourceAccess
+	// Saves workflows so they survive restarts (nil = kept in memory only),
+	// ended ones for checkpointRetention (0 = forever)
+	checkpoints         *checkpoint.Store
+	checkpointRetention time.Duration
+	checkpointMu        sync.Mutex
+	// Workflow ID -> struct{}, for the ended workflows whose checkpoint is
+	// due for deletion
+	prunesScheduled sync.Map
+	// Workflow ID -> struct{}, for the workflows whose checkpoint job
+	// events have queued
+	checkpointsQueued sync.Map
+	// Workflow ID -> *orchestration, for the workflows being orchestrated
+	orchestrations sync.Map
+	// Job ID -> struct{}, for the running workflow jobs the monitor checks
+	monitoredJobs sync.Map
+	monitorOnce   sync.Once
+	// Set while job events drive the workflows, which then poll only as a
+	// fallback; modeChanged is closed when it changes
+	modeMu      sync.Mutex
+	eventDriven bool
+	modeChanged chan struct{}
+}
+
+// orchestration lets CancelWorkflow halt the goroutine starting the jobs of
+// a workflow
+type orchestration struct {
+	halt context.CancelFunc
+	done chan struct{} // Closed once the goroutine has returned
 }
 
 // NewWorkflowServiceServer creates a new gRPC service server for workflow operations.
 // This server handles workflow creation, status monitoring, and job orchestration.
 // It requires authentication, job store access, joblet interface for job execution,
 // a workflow manager for dependency tracking and job coordination, and managers for validation.
-func NewWorkflowServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, metricsStore *adapters.MetricsStoreAdapter, joblet interfaces.Joblet, workflowManager *workflow.WorkflowManager, volumeManager *volume.Manager, runtimeResolver *runtime.Resolver, persistClient persistpb.PersistServiceClient) *WorkflowServiceServer {
+func NewWorkflowServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.JobStorer, metricsStore *adapters.MetricsStoreAdapter, joblet interfaces.Joblet, workflowManager *workflow.WorkflowManager, volumeManager *volume.Manager, runtimeResolver *runtime.Resolver, persistClient persistpb.PersistServiceClient, signatures *signatureVerifier, tenants func(client string) string, runtimes func(tenant, spec string) (string, string), uploads *uploadsync.Cache, logHeartbeat, logSendTimeout time.Duration) *WorkflowServiceServer {
 	// Create workflow validator with concrete managers (no adapter pattern needed)
 	workflowValidator := validation.NewWorkflowValidator(volumeManager, runtimeResolver)
 
@@ -77,7 +162,16 @@ func NewWorkflowServiceServer(auth auth2.GRPCAuthorization, jobStore adapters.Jo
 		persistClient:     persistClient,
 		workflowValidator: workflowValidator,
 		logger:            logger.WithField("component", "workflow-grpc"),
-		workflowUuidMap:   make(map[string]int),
+		workflowUuids:     prefixindex.New[int](),
+		workflowIDToUuid:  make(map[int]string),
+		dedup:             newJobDeduplicator(),
+		results:           newResultCache(),
+		signatures:        signatures,
+		tenants:           tenants,
+		runtimes:          runtimes,
+		uploads:           uploads,
+		logHeartbeat:      logHeartbeat,
+		logSendTimeout:    logSendTimeout,
 	}
 }
 
@@ -102,10 +196,23 @@ func (s *WorkflowServiceServer) RunWorkflow(ctx context.Context, req *pb.RunWork
 		return nil, status.Errorf(codes.InvalidArgument, "workflow is required")
 	}
 
+	signature, err := s.signatures.fromRequest(ctx, func(signedAt time.Time) []byte {
+		return jobsign.WorkflowDigest(req.YamlContent, signedAt)
+	})
+	if err != nil {
+		log.Warn("request signature rejected", "error", err)
+		return nil, err
+	}
+
 	// Check if we have YAML content (client-side upload) or just a workflow path
 	if req.YamlContent != "" {
 		log.Info("detected client-side YAML content, starting workflow orchestration with uploaded files")
-		workflowUuid, err := s.StartWorkflowOrchestrationWithContent(ctx, req.YamlContent, req.WorkflowFiles)
+		workflowFiles, err := resolveUploadRefs(ctx, s.uploads, req.WorkflowFiles)
+		if err != nil {
+			log.Warn("failed to resolve synced uploads", "error", err)
+			return nil, err
+		}
+		workflowUuid, err := s.StartWorkflowOrchestrationWithContent(ctx, req.YamlContent, workflowFiles, signature)
 		if err != nil {
 			log.Error("failed to start workflow orchestration with content", "error", err)
 			return nil, status.Errorf(codes.Internal, "failed to start workflow orchestration: %v", err)
@@ -145,6 +252,7 @@ func (s *WorkflowServiceServer) RunWorkflow(ctx context.Context, req *pb.RunWork
 
 	// Store workflow UUID -> ID mapping
 	s.storeWorkflowMapping(workflowUuid, workflowID)
+	s.checkpointNewWorkflow(ctx, workflowID, nil, nil)
 	log.Info("workflow created successfully", "workflowId", workflowID, "workflowUuid", workflowUuid)
 	return &pb.RunWorkflowResponse{
 		WorkflowUuid: workflowUuid,
@@ -168,7 +276,7 @@ func (s *WorkflowServiceServer) GetWorkflowStatus(ctx context.Context, req *pb.G
 	workflowID, found := s.lookupWorkflowID(req.WorkflowUuid)
 	if !found {
 		log.Error("workflow not found", "workflowUuid", req.WorkflowUuid)
-		return nil, status.Errorf(codes.NotFound, "workflow not found: %s", req.WorkflowUuid)
+		return nil, s.workflowLookupError(req.WorkflowUuid)
 	}
 
 	// Find the full UUID for this workflow ID
@@ -180,11 +288,33 @@ func (s *WorkflowServiceServer) GetWorkflowStatus(ctx context.Context, req *pb.G
 		return nil, status.Errorf(codes.NotFound, "workflow not found: %v", err)
 	}
 
+	if err := s.signatures.setHeader(ctx, workflowState.Signature, func(signedAt time.Time) []byte {
+		return jobsign.WorkflowDigest(workflowState.YamlContent, signedAt)
+	}); err != nil {
+		log.Warn("failed to set response header", "header", constants.SignatureStatusHeader, "error", err)
+	}
+
 	workflowInfo := s.convertWorkflowStateToInfo(workflowState)
 	// Override the UUID with the actual full UUID
 	workflowInfo.Uuid = fullUuid
 	workflowJobs := s.convertJobDependenciesToWorkflowJobs(workflowState.Jobs)
 
+	if fingerprints := s.workflowFingerprints(workflowState.Jobs); len(fingerprints) > 0 {
+		if data, err := json.Marshal(fingerprints); err == nil {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(constants.WorkflowFingerprintsHeader, string(data))); err != nil {
+				log.Warn("failed to set response header", "header", constants.WorkflowFingerprintsHeader, "error", err)
+			}
+		}
+	}
+
+	if anomalies := s.workflowAnomalies(workflowState.Jobs); len(anomalies) > 0 {
+		if data, err := json.Marshal(anomalies); err == nil {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(constants.WorkflowAnomaliesHeader, string(data))); err != nil {
+				log.Warn("failed to set response header", "header", constants.WorkflowAnomaliesHeader, "error", err)
+			}
+		}
+	}
+
 	return &pb.GetWorkflowStatusResponse{
 		Workflow: workflowInfo,
 		Jobs:     workflowJobs,
@@ -275,6 +405,7 @@ func (s *WorkflowServiceServer) runIndividualJob(ctx context.Context, req *pb.Ru
 	)
 
 	log.Debug("processing individual job request (original JobService logic)")
+	clientClock := s.observeClientClock(ctx, req.Schedule != "")
 
 	// CRITICAL: Verify persist is healthy before accepting jobs
 	// If persist is down, we'll lose all logs and metrics for this job
@@ -285,13 +416,50 @@ func (s *WorkflowServiceServer) runIndividualJob(ctx context.Context, req *pb.Ru
 		}
 	}
 
+	dedup, err := extractDedup(req.Environment)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	cache, err := extractCacheOptions(req.Environment)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	arrayIndices, err := extractArray(req.Environment)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if arrayIndices != nil && (dedup || cache.TTL > 0) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: a job array cannot be deduplicated or cached")
+	}
+
 	// Convert protobuf request to domain request object (reuse JobService conversion logic)
-	jobRequest, err := s.convertToIndividualJobRequest(req)
+	jobRequest, err := s.convertToIndividualJobRequest(ctx, req)
 	if err != nil {
 		log.Error("failed to convert request", "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
 	}
 
+	// Profiling exposes syscall-level detail of the job, so it is admin-only
+	if jobRequest.Profile != "" {
+		if err := s.auth.Authorized(ctx, auth2.ProfileJobOp); err != nil {
+			log.Warn("profiling not authorized", "error", err)
+			return nil, err
+		}
+	}
+
+	// A signed request keeps its signature with the job record
+	jobRequest.Signature, err = s.signatures.fromRequest(ctx, requestDigest(req))
+	if err != nil {
+		log.Warn("request signature rejected", "error", err)
+		return nil, err
+	}
+	jobRequest.Tenant = s.tenantOf(ctx)
+	s.detectRuntime(ctx, jobRequest)
+	if err := checkResourceAccess(s.access, jobRequest); err != nil {
+		log.Warn("job uses resources of another tenant", "error", err)
+		return nil, err
+	}
+
 	// Log the request (excluding sensitive environment variables)
 	envCount := 0
 	if jobRequest.Environment != nil {
@@ -311,11 +479,53 @@ func (s *WorkflowServiceServer) runIndividualJob(ctx context.Context, req *pb.Ru
 		"envVarsCount", envCount,
 		"secretEnvVarsCount", len(jobRequest.SecretEnvironment))
 
+	if arrayIndices != nil {
+		return s.runJobArray(ctx, jobRequest, arrayIndices)
+	}
+
+	var hash string
+	if dedup || cache.TTL > 0 {
+		hash = jobRequestHash(req, jobRequest.Tenant, jobRequest.Environment, jobRequest.SecretEnvironment)
+	}
+
+	// With a cache TTL, a fresh successful result of an identical job is
+	// returned instead of computing it again
+	if cache.TTL > 0 && !cache.NoCache {
+		if cached := s.results.lookup(hash, cache.TTL, time.Now(), s.jobStore.Job); cached != nil {
+			log.Info("returning cached job result", "jobUuid", cached.Uuid, "endTime", cached.EndTime)
+			return s.existingJobResponse(ctx, cached, constants.CachedHeader), nil
+		}
+	}
+
+	// With dedup, an identical job that is still active is returned instead
+	// of starting the same computation twice
+	release := func(jobID string) {}
+	if dedup {
+		existingID, releaseHash, err := s.dedup.acquire(ctx, hash, s.isDedupTarget)
+		if err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		if existingID != "" {
+			existing, exists := s.jobStore.Job(existingID)
+			if !exists {
+				return nil, status.Errorf(codes.Internal, "deduplicated job %s disappeared", existingID)
+			}
+			log.Info("returning identical active job", "jobUuid", existingID)
+			return s.existingJobResponse(ctx, existing, constants.DeduplicatedHeader), nil
+		}
+		release = releaseHash
+	}
+
 	// Use joblet interface directly (bypasses workflow validation, handles volume creation on-demand)
 	newJob, err := s.joblet.StartJob(ctx, *jobRequest)
 	if err != nil {
+		release("")
 		log.Error("individual job creation failed", "error", err)
-		return nil, status.Errorf(codes.Internal, "job run failed: %v", err)
+		return nil, jobRunError(err)
+	}
+	release(newJob.Uuid)
+	if cache.TTL > 0 {
+		s.results.record(hash, newJob.Uuid, cache.TTL, time.Now(), s.jobStore.Job)
 	}
 
 	// Log success
@@ -323,6 +533,9 @@ func (s *WorkflowServiceServer) runIndividualJob(ctx context.Context, req *pb.Ru
 		log.Info("individual job scheduled successfully",
 			"jobUuid", newJob.Uuid,
 			"scheduledTime", req.Schedule)
+		if newJob.ScheduledTime != nil {
+			s.warnScheduleClock(ctx, clientClock, *newJob.ScheduledTime)
+		}
 	} else {
 		log.Info("individual job started successfully",
 			"jobUuid", newJob.Uuid,
@@ -336,6 +549,78 @@ func (s *WorkflowServiceServer) runIndividualJob(ctx context.Context, req *pb.Ru
 	}, nil
 }
 
+// runJobArray starts one job per index of a job array. Every job gets the
+// array UUID, its index and the array size in its environment. A job that
+// fails to start aborts the submission and the jobs already started are
+// stopped, so an array is never left half submitted.
+func (s *WorkflowServiceServer) runJobArray(ctx context.Context, jobRequest *interfaces.StartJobRequest, indices []int) (*pb.RunJobResponse, error) {
+	arrayID := s.generateWorkflowUUID()
+	log := s.logger.WithFields("operation", "RunJobArray", "arrayUuid", arrayID, "size", len(indices))
+
+	var first *domain.Job
+	started := make([]string, 0, len(indices))
+	for _, index := range indices {
+		req := *jobRequest
+		req.Environment = make(map[string]string, len(jobRequest.Environment)+3)
+		maps.Copy(req.Environment, jobRequest.Environment)
+		req.Environment[constants.ArrayIDVar] = arrayID
+		req.Environment[constants.ArrayIndexVar] = strconv.Itoa(index)
+		req.Environment[constants.ArraySizeVar] = strconv.Itoa(len(indices))
+		req.ArrayUuid = arrayID
+		req.ArrayIndex = index
+
+		job, err := s.joblet.StartJob(ctx, req)
+		if err != nil {
+			log.Error("job array submission failed, stopping the jobs already started", "index", index, "started", len(started), "error", err)
+			for _, jobID := range started {
+				stopReq := interfaces.StopJobRequest{JobID: jobID, Reason: "job_array_aborted"}
+				if stopErr := s.joblet.StopJob(context.WithoutCancel(ctx), stopReq); stopErr != nil {
+					log.Warn("failed to stop job of aborted array", "jobUuid", jobID, "error", stopErr)
+				}
+			}
+			return nil, jobRunError(fmt.Errorf("job array index %d: %w", index, err))
+		}
+		if first == nil {
+			first = job
+		}
+		started = append(started, job.Uuid)
+	}
+
+	log.Info("job array started", "firstJobUuid", first.Uuid)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(constants.ArraySizeHeader, strconv.Itoa(len(indices)))); err != nil {
+		log.Warn("failed to set response header", "header", constants.ArraySizeHeader, "error", err)
+	}
+	return &pb.RunJobResponse{
+		JobUuid: arrayID,
+		Status:  string(first.Status),
+	}, nil
+}
+
+// isDedupTarget reports whether jobID is still active and can be returned
+// for an identical deduplicated request
+func (s *WorkflowServiceServer) isDedupTarget(jobID string) bool {
+	job, exists := s.jobStore.Job(jobID)
+	return exists && isDedupActive(job)
+}
+
+// existingJobResponse answers a RunJob with an existing job instead of a new
+// one and sets header to "true" so the client can tell
+func (s *WorkflowServiceServer) existingJobResponse(ctx context.Context, job *domain.Job, header string) *pb.RunJobResponse {
+	if err := grpc.SetHeader(ctx, metadata.Pairs(header, "true")); err != nil {
+		s.logger.Warn("failed to set response header", "header", header, "error", err)
+	}
+	return mappers.NewJobMapper().DomainToRunJobResponse(job)
+}
+
+// jobRunError converts a StartJob error to a gRPC status. Uploads rejected by
+// the scanner are the client's problem, not the server's.
+func jobRunError(err error) error {
+	if errors.Is(err, upload.ErrUploadRejected) {
+		return status.Errorf(codes.InvalidArgument, "job run failed: %v", err)
+	}
+	return status.Errorf(codes.Internal, "job run failed: %v", err)
+}
+
 // runExistingWorkflowJob handles jobs that are part of existing workflows
 func (s *WorkflowServiceServer) runExistingWorkflowJob(ctx context.Context, req *pb.RunJobRequest) (*pb.RunJobResponse, error) {
 	log := s.logger.WithField("workflowUuid", req.WorkflowUuid)
@@ -349,12 +634,23 @@ func (s *WorkflowServiceServer) runExistingWorkflowJob(ctx context.Context, req
 		}
 	}
 
-	jobRequest, err := s.convertToWorkflowJobRequest(req)
+	jobRequest, err := s.convertToWorkflowJobRequest(ctx, req)
 	if err != nil {
 		log.Error("failed to convert request", "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
 	}
 
+	jobRequest.Signature, err = s.signatures.fromRequest(ctx, requestDigest(req))
+	if err != nil {
+		log.Warn("request signature rejected", "error", err)
+		return nil, err
+	}
+	jobRequest.Tenant = s.tenantOf(ctx)
+	if err := checkResourceAccess(s.access, jobRequest); err != nil {
+		log.Warn("job uses resources of another tenant", "error", err)
+		return nil, err
+	}
+
 	workflowID := s.convertWorkflowUUIDToID(req.WorkflowUuid)
 	readyJobs := s.workflowManager.GetReadyJobs(workflowID)
 	canRun := false
@@ -375,7 +671,7 @@ func (s *WorkflowServiceServer) runExistingWorkflowJob(ctx context.Context, req
 	newJob, err := s.joblet.StartJob(ctx, *jobRequest)
 	if err != nil {
 		log.Error("job creation failed", "error", err)
-		return nil, status.Errorf(codes.Internal, "job run failed: %v", err)
+		return nil, jobRunError(err)
 	}
 
 	s.workflowManager.OnJobStateChange(newJob.Uuid, newJob.Status)
@@ -387,7 +683,7 @@ func (s *WorkflowServiceServer) runExistingWorkflowJob(ctx context.Context, req
 	}, nil
 }
 
-func (s *WorkflowServiceServer) convertToWorkflowJobRequest(req *pb.RunJobRequest) (*interfaces.StartJobRequest, error) {
+func (s *WorkflowServiceServer) convertToWorkflowJobRequest(ctx context.Context, req *pb.RunJobRequest) (*interfaces.StartJobRequest, error) {
 	if req.Command == "" {
 		return nil, fmt.Errorf("command is required")
 	}
@@ -415,6 +711,66 @@ func (s *WorkflowServiceServer) convertToWorkflowJobRequest(req *pb.RunJobReques
 		}
 	}
 
+	sizing, err := extractFilesystemSizing(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := extractGroup(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := extractLabels(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := extractAnnotations(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	logSinks, err := extractLogSinks(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	cgroupParams, err := extractCgroupParams(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	queueTTL, err := extractQueueTTL(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	ipcChannel, err := extractIPC(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	jobInputs, err := extractInputs(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	outputTargets, err := extractOutputTargets(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	egress, err := extractEgress(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	secretFiles, err := extractSecretFiles(req.Environment, req.SecretEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
 	jobRequest := &interfaces.StartJobRequest{
 		Name:    req.Name, // Pass through job name from request
 		Command: req.Command,
@@ -424,22 +780,38 @@ func (s *WorkflowServiceServer) convertToWorkflowJobRequest(req *pb.RunJobReques
 			MaxMemory: req.MaxMemory,
 			MaxIOBPS:  req.MaxIobps,
 			CPUCores:  req.CpuCores,
+
+			CgroupParams: cgroupParams,
 		},
 		Uploads:           domainUploads,
 		Schedule:          req.Schedule,
 		Network:           network,
 		Volumes:           req.Volumes,
-		Runtime:           req.Runtime,
 		Environment:       req.Environment,       // Regular environment variables
 		SecretEnvironment: req.SecretEnvironment, // Secret environment variables
 		JobType:           jobType,               // Pass job type to the core
-	}
+		ShmSizeBytes:      sizing.ShmSizeBytes,
+		TmpSizeBytes:      sizing.TmpSizeBytes,
+		Scratch:           sizing.Scratch,
+		FreezeFS:          sizing.FreezeFS,
+		Group:             group,
+		Labels:            labels,
+		Annotations:       annotations,
+		LogSinks:          logSinks,
+		QueueTTL:          queueTTL,
+		IPCChannel:        ipcChannel,
+		Inputs:            jobInputs,
+		OutputTargets:     outputTargets,
+		Egress:            egress,
+		SecretFiles:       secretFiles,
+	}
+	s.resolveRuntime(ctx, jobRequest, req.Runtime)
 
 	return jobRequest, nil
 }
 
 // convertToIndividualJobRequest converts protobuf request to domain request object (for individual jobs)
-func (s *WorkflowServiceServer) convertToIndividualJobRequest(req *pb.RunJobRequest) (*interfaces.StartJobRequest, error) {
+func (s *WorkflowServiceServer) convertToIndividualJobRequest(ctx context.Context, req *pb.RunJobRequest) (*interfaces.StartJobRequest, error) {
 	// Validate required fields
 	if req.Command == "" {
 		return nil, fmt.Errorf("command is required")
@@ -481,8 +853,80 @@ func (s *WorkflowServiceServer) convertToIndividualJobRequest(req *pb.RunJobRequ
 		}
 	}
 
+	// Extract /dev/shm and /tmp sizing (reserved environment keys)
+	sizing, err := extractFilesystemSizing(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := extractProfile(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	stdinPath, err := extractStdin(req.Environment, req.Uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := extractGroup(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := extractLabels(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := extractAnnotations(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	logSinks, err := extractLogSinks(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	cgroupParams, err := extractCgroupParams(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	queueTTL, err := extractQueueTTL(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	ipcChannel, err := extractIPC(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	jobInputs, err := extractInputs(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	outputTargets, err := extractOutputTargets(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	egress, err := extractEgress(req.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	secretFiles, err := extractSecretFiles(req.Environment, req.SecretEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the request object with validation
 	jobRequest := &interfaces.StartJobRequest{
+		Name:    req.Name,
 		Command: req.Command,
 		Args:    req.Args,
 		Resources: interfaces.ResourceLimits{
@@ -490,16 +934,34 @@ func (s *WorkflowServiceServer) convertToIndividualJobRequest(req *pb.RunJobRequ
 			MaxMemory: req.MaxMemory,
 			MaxIOBPS:  req.MaxIobps,
 			CPUCores:  req.CpuCores,
+
+			CgroupParams: cgroupParams,
 		},
 		Uploads:           domainUploads,
 		Schedule:          req.Schedule,
 		Network:           network,
 		Volumes:           req.Volumes,
-		Runtime:           req.Runtime,
 		Environment:       req.Environment,       // Regular environment variables (logged)
 		SecretEnvironment: req.SecretEnvironment, // Secret environment variables (not logged)
 		JobType:           jobType,               // Set job type for isolation configuration
-	}
+		ShmSizeBytes:      sizing.ShmSizeBytes,
+		TmpSizeBytes:      sizing.TmpSizeBytes,
+		Scratch:           sizing.Scratch,
+		FreezeFS:          sizing.FreezeFS,
+		Profile:           profile,
+		StdinPath:         stdinPath,
+		Group:             group,
+		Labels:            labels,
+		Annotations:       annotations,
+		LogSinks:          logSinks,
+		QueueTTL:          queueTTL,
+		IPCChannel:        ipcChannel,
+		Inputs:            jobInputs,
+		OutputTargets:     outputTargets,
+		Egress:            egress,
+		SecretFiles:       secretFiles,
+	}
+	s.resolveRuntime(ctx, jobRequest, req.Runtime)
 
 	// Validate the request (reuse validation logic from JobService)
 	if err := s.validateIndividualJobRequest(jobRequest); err != nil {
@@ -632,7 +1094,8 @@ func (s *WorkflowServiceServer) convertWorkflowStateToInfo(ws *workflow.Workflow
 // 3. Sets JobId from JobDependency.JobID (actual ID if started, job name if not)
 // 4. Sets JobName from JobDependency.InternalName (always the workflow YAML name)
 // 5. Maps job status to string representation
-// 6. Builds dependency list from job requirements
+// 6. Adds start time, end time and exit code of started jobs from the job store
+// 7. Builds dependency list from job requirements
 //
 // PARAMETERS:
 // - jobs: Map of job identifiers to JobDependency structures from workflow manager
@@ -665,7 +1128,24 @@ func (s *WorkflowServiceServer) convertJobDependenciesToWorkflowJobs(jobs map[st
 			Status:  string(jobDep.Status),
 		}
 
+		// Started jobs report their timing and exit code from the job store
+		if jobID != "0" {
+			if job, exists := s.jobStore.Job(jobID); exists {
+				if !job.StartTime.IsZero() {
+					wfJob.StartTime = s.convertTimeToTimestamp(job.StartTime)
+				}
+				if job.EndTime != nil {
+					wfJob.EndTime = s.convertTimeToTimestamp(*job.EndTime)
+				}
+				wfJob.ExitCode = job.ExitCode
+			}
+		}
+
 		for _, req := range jobDep.Requirements {
+			if req.Type == workflow.RequirementExpression {
+				wfJob.Dependencies = append(wfJob.Dependencies, workflow.ExpressionJobNames(req.Expression)...)
+				continue
+			}
 			wfJob.Dependencies = append(wfJob.Dependencies, req.JobID)
 		}
 
@@ -696,10 +1176,13 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestration(ctx context.Context,
 	if err != nil {
 		return "", fmt.Errorf("failed to parse workflow YAML: %w", err)
 	}
+	if err := applyRequestAnnotations(ctx, workflowYAML); err != nil {
+		return "", err
+	}
 
 	// Validate workflow before execution
 	log.Info("performing server-side workflow validation")
-	if err := s.workflowValidator.ValidateWorkflow(*workflowYAML); err != nil {
+	if err := s.workflowValidator.ValidateWorkflow(s.withResolvedRuntimes(ctx, *workflowYAML)); err != nil {
 		log.Error("workflow validation failed", "error", err)
 		return "", fmt.Errorf("workflow validation failed: %w", err)
 	}
@@ -709,27 +1192,10 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestration(ctx context.Context,
 	var jobOrder []string
 
 	for jobName, jobSpec := range workflowYAML.Jobs {
-		dependencies := make(map[string]string)
-		if jobSpec.Requires != nil {
-			for _, req := range jobSpec.Requires {
-				for depJob, status := range req {
-					dependencies[depJob] = status
-				}
-			}
-		}
-
-		var requirements []workflow.Requirement
-		for depJob, status := range dependencies {
-			requirements = append(requirements, workflow.Requirement{
-				Type:   workflow.RequirementSimple,
-				JobID:  depJob,
-				Status: status,
-			})
-		}
 		jobs[jobName] = &workflow.JobDependency{
 			JobID:        jobName,
 			InternalName: jobName,
-			Requirements: requirements,
+			Requirements: workflow.RequirementsFromYAML(jobSpec.Requires),
 			Status:       domain.StatusPending,
 		}
 		jobOrder = append(jobOrder, jobName)
@@ -746,6 +1212,7 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestration(ctx context.Context,
 
 	// Store workflow UUID -> ID mapping
 	s.storeWorkflowMapping(workflowUuid, workflowID)
+	s.checkpointNewWorkflow(ctx, workflowID, workflowYAML, nil)
 
 	log.Info("workflow created, starting job orchestration", "workflowId", workflowID)
 
@@ -763,46 +1230,102 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestration(ctx context.Context,
 
 func (s *WorkflowServiceServer) orchestrateWorkflow(ctx context.Context, workflowID int, workflowYAML *WorkflowYAML, uploadedFiles map[string][]byte) {
 	log := s.logger.WithField("workflowId", workflowID)
-	ticker := time.NewTicker(workflowOrchestrationInterval)
+	interval, modeChanged := s.pollInterval(workflowOrchestrationInterval)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// Jobs changing status wake the orchestration up, the ticker only
+	// catches what no event reported
+	changes, stopWatching := s.workflowManager.Watch(workflowID)
+	defer stopWatching()
+
+	// Halting stops starting jobs; the jobs started keep the context they
+	// were started with
+	halted, halt := context.WithCancel(ctx)
+	run := &orchestration{halt: halt, done: make(chan struct{})}
+	s.orchestrations.Store(workflowID, run)
+	defer func() {
+		s.orchestrations.Delete(workflowID)
+		halt()
+		close(run.done)
+	}()
+
 	for {
-		select {
-		case <-ctx.Done():
-			log.Info("workflow orchestration context canceled")
+		if halted.Err() != nil {
+			log.Info("workflow orchestration halted")
+			return
+		}
+		if s.startReadyJobs(ctx, halted, workflowID, workflowYAML, uploadedFiles) {
 			return
+		}
+
+		select {
+		case <-halted.Done():
+		case <-changes:
+			log.Debug("job status changed - checking for ready jobs")
+		case <-modeChanged:
+			interval, modeChanged = s.pollInterval(workflowOrchestrationInterval)
+			ticker.Reset(interval)
 		case <-ticker.C:
 			log.Debug("orchestration tick - checking for ready jobs")
-			readyJobs := s.workflowManager.GetReadyJobs(workflowID)
-			log.Debug("orchestration ready jobs check", "readyJobsCount", len(readyJobs), "readyJobs", readyJobs)
-			if len(readyJobs) == 0 {
-				workflowState, err := s.workflowManager.GetWorkflowStatus(workflowID)
-				if err != nil {
-					log.Warn("failed to get workflow status during orchestration", "error", err)
-					continue
-				}
-				log.Debug("orchestration status check", "workflowStatus", workflowState.Status, "completedJobs", workflowState.CompletedJobs, "totalJobs", workflowState.TotalJobs)
-				if workflowState.Status == workflow.WorkflowCompleted || workflowState.Status == workflow.WorkflowFailed {
-					log.Info("workflow orchestration completed", "status", workflowState.Status)
-					return
-				}
-				continue
-			}
+		}
+	}
+}
 
-			log.Info("found ready jobs for orchestration", "readyJobs", readyJobs)
+// startReadyJobs starts the jobs of a workflow whose requirements are met,
+// until halted. Returns true once the workflow has ended.
+func (s *WorkflowServiceServer) startReadyJobs(ctx, halted context.Context, workflowID int, workflowYAML *WorkflowYAML, uploadedFiles map[string][]byte) bool {
+	log := s.logger.WithField("workflowId", workflowID)
+	readyJobs := s.workflowManager.GetReadyJobs(workflowID)
+	log.Debug("orchestration ready jobs check", "readyJobsCount", len(readyJobs), "readyJobs", readyJobs)
+	if len(readyJobs) == 0 {
+		workflowState, err := s.workflowManager.GetWorkflowStatus(workflowID)
+		if err != nil {
+			log.Warn("failed to get workflow status during orchestration", "error", err)
+			return false
+		}
+		log.Debug("orchestration status check", "workflowStatus", workflowState.Status, "completedJobs", workflowState.CompletedJobs, "totalJobs", workflowState.TotalJobs)
+		if workflowState.Status == workflow.WorkflowCompleted || workflowState.Status == workflow.WorkflowFailed ||
+			workflowState.Status == workflow.WorkflowCanceled {
+			log.Info("workflow orchestration completed", "status", workflowState.Status)
+			s.checkpointWorkflow(workflowID)
+			return true
+		}
+		return false
+	}
 
-			for _, jobName := range readyJobs {
-				if jobSpec, exists := workflowYAML.Jobs[jobName]; exists {
-					err := s.executeWorkflowJob(ctx, workflowID, jobName, jobSpec, workflowYAML, uploadedFiles)
-					if err != nil {
-						log.Error("failed to execute workflow job", "jobName", jobName, "error", err)
-						// For failed job startup, we still use jobName since no actual job ID was created
-						s.workflowManager.OnJobStateChange(jobName, domain.StatusFailed)
-					}
-				}
+	log.Info("found ready jobs for orchestration", "readyJobs", readyJobs)
+
+	for _, jobName := range readyJobs {
+		if halted.Err() != nil {
+			break
+		}
+		if jobSpec, exists := workflowYAML.Jobs[jobName]; exists {
+			err := s.executeWorkflowJob(ctx, workflowID, jobName, jobSpec, workflowYAML, uploadedFiles)
+			if err != nil {
+				log.Error("failed to execute workflow job", "jobName", jobName, "error", err)
+				// For failed job startup, we still use jobName since no actual job ID was created
+				s.workflowManager.OnJobStateChange(jobName, domain.StatusFailed)
 			}
 		}
 	}
+	s.checkpointWorkflow(workflowID)
+	return false
+}
+
+// haltOrchestration stops the orchestration of a workflow from starting
+// jobs, waiting for a job it is starting, if any
+func (s *WorkflowServiceServer) haltOrchestration(ctx context.Context, workflowID int) {
+	value, running := s.orchestrations.Load(workflowID)
+	if !running {
+		return
+	}
+	run := value.(*orchestration)
+	run.halt()
+	select {
+	case <-run.done:
+	case <-ctx.Done():
+	}
 }
 
 // executeWorkflowJob executes a single job within a workflow context.
@@ -853,35 +1376,29 @@ func (s *WorkflowServiceServer) executeWorkflowJob(ctx context.Context, workflow
 	log.Info("executing workflow job")
 
 	// RACE CONDITION FIX: Process ALL file uploads BEFORE starting the job
-	uploadCount := 0
-	if jobSpec.Uploads != nil {
-		uploadCount = len(jobSpec.Uploads.Files)
-	}
-	log.Debug("processing file uploads for job", "uploadCount", uploadCount)
 	uploads := []domain.FileUpload{}
 	if jobSpec.Uploads != nil {
-		// Validate that ALL required files are available BEFORE proceeding
-		for _, file := range jobSpec.Uploads.Files {
-			if uploadedFiles == nil {
-				return fmt.Errorf("server-side workflow file reading not supported. Use 'rnx workflow run' with client-side file upload")
-			}
+		if uploadedFiles == nil {
+			return fmt.Errorf("server-side workflow file reading not supported. Use 'rnx workflow run' with client-side file upload")
+		}
 
-			// Check file availability first (fail fast if missing)
-			if _, exists := uploadedFiles[file]; !exists {
-				log.Error("required file not found in uploaded files", "file", file, "availableFiles", getFileKeys(uploadedFiles))
-				return fmt.Errorf("file %s not found in uploaded files", file)
-			}
+		// Expand patterns, directories and mappings over the uploaded files,
+		// failing fast when any of them is missing
+		targets, err := workflow.UploadTargets(jobSpec.Uploads, getFileKeys(uploadedFiles))
+		if err != nil {
+			log.Error("required files not found in uploaded files", "error", err, "availableFiles", getFileKeys(uploadedFiles))
+			return err
 		}
+		log.Debug("processing file uploads for job", "uploadCount", len(targets))
 
-		// Now process all files (we know they're all available)
-		for _, file := range jobSpec.Uploads.Files {
-			fileContent := uploadedFiles[file] // We already validated this exists
+		for _, target := range targets {
+			fileContent := uploadedFiles[target.Source]
 			uploads = append(uploads, domain.FileUpload{
-				Path:    file,
+				Path:    target.Path,
 				Content: fileContent,
 				Size:    int64(len(fileContent)),
 			})
-			log.Debug("prepared file upload for job", "file", file, "size", len(fileContent))
+			log.Debug("prepared file upload for job", "file", target.Source, "path", target.Path, "size", len(fileContent))
 		}
 	}
 
@@ -896,24 +1413,104 @@ func (s *WorkflowServiceServer) executeWorkflowJob(ctx context.Context, workflow
 	// Merge environment variables: global workflow vars + job-specific vars (job overrides global)
 	mergedEnvironment, mergedSecretEnvironment := s.mergeEnvironmentVariables(workflowYAML, jobSpec)
 
+	// Values published by the jobs this one requires
+	args := jobSpec.Args
+	if refs := workflow.JobOutputRefs(jobSpec); len(refs) > 0 {
+		outputs, err := s.jobOutputs(workflowID, workflowYAML, refs)
+		if err != nil {
+			return err
+		}
+		if args, err = expandOutputs(outputs, args); err != nil {
+			return err
+		}
+		for _, env := range []map[string]string{mergedEnvironment, mergedSecretEnvironment} {
+			for key, value := range env {
+				if env[key], err = workflow.ExpandOutputs(value, outputs); err != nil {
+					return err
+				}
+			}
+		}
+		log.Info("job outputs referenced", "refs", len(refs))
+	}
+
+	shmSize, err := values.ParseMemorySize(jobSpec.Resources.ShmSize)
+	if err != nil {
+		return fmt.Errorf("invalid shm_size: %w", err)
+	}
+	tmpSize, err := values.ParseMemorySize(jobSpec.Resources.TmpSize)
+	if err != nil {
+		return fmt.Errorf("invalid tmp_size: %w", err)
+	}
+	jobInputs, err := parseInputs(jobSpec.Inputs)
+	if err != nil {
+		return err
+	}
+	outputTargets, err := parseOutputTargets(jobSpec.OutputTargets)
+	if err != nil {
+		return err
+	}
+	egress, err := parseEgress(jobSpec.Egress)
+	if err != nil {
+		return err
+	}
+	if err := checkSecretFiles(jobSpec.SecretFiles, mergedSecretEnvironment); err != nil {
+		return err
+	}
+
 	jobRequest := interfaces.StartJobRequest{
 		Name:    jobName, // Use the workflow job name
 		Command: jobSpec.Command,
-		Args:    jobSpec.Args,
+		Args:    args,
 		Resources: interfaces.ResourceLimits{
 			MaxCPU:    int32(jobSpec.Resources.MaxCPU),
 			MaxMemory: int32(jobSpec.Resources.MaxMemory),
 			MaxIOBPS:  int32(jobSpec.Resources.MaxIOBPS),
 			CPUCores:  jobSpec.Resources.CPUCores,
+
+			CgroupParams: mergeCgroupParams(workflowYAML.CgroupParams, jobSpec.Resources.CgroupParams),
 		},
 		Uploads:           uploads,
 		Network:           network,
 		Volumes:           jobSpec.Volumes,
-		Runtime:           jobSpec.Runtime,
 		Environment:       mergedEnvironment,                    // Merged global + job-specific environment variables
 		SecretEnvironment: mergedSecretEnvironment,              // Merged global + job-specific secret environment variables
 		GPUCount:          int32(jobSpec.Resources.GPUCount),    // GPU requirements from YAML
 		GPUMemoryMB:       int64(jobSpec.Resources.GPUMemoryMB), // GPU memory requirement
+		ShmSizeBytes:      shmSize.Bytes(),
+		TmpSizeBytes:      tmpSize.Bytes(),
+		Scratch:           jobSpec.Resources.Scratch,
+		IPCChannel:        jobSpec.IPC,
+		Inputs:            jobInputs,
+		OutputTargets:     outputTargets,
+		Egress:            egress,
+		SecretFiles:       jobSpec.SecretFiles,
+		Tenant:            s.tenantOf(ctx),
+		Group:             workflowYAML.Group,
+		Labels:            jobSpec.Labels,
+		Annotations:       workflowYAML.Annotations,
+		WorkflowUuid:      s.getFullUuidForWorkflowID(workflowID),
+	}
+	if jobSpec.Outputs != nil {
+		jobRequest.OutputsFile = jobSpec.Outputs.File
+	}
+
+	s.resolveRuntime(ctx, &jobRequest, jobSpec.Runtime)
+	if err := checkResourceAccess(s.access, &jobRequest); err != nil {
+		return err
+	}
+
+	// Workflow jobs are grouped by workflow unless the workflow names a group
+	if jobRequest.Group == "" {
+		jobRequest.Group = jobRequest.WorkflowUuid
+	}
+
+	// Delayed jobs are started as scheduled jobs, so they show up as SCHEDULED
+	// in the workflow until their time comes
+	if startTime, err := s.workflowJobStartTime(workflowID, jobSpec); err != nil {
+		return err
+	} else if !startTime.IsZero() {
+		jobRequest.Schedule = startTime.Format(time.RFC3339)
+		log.Info("workflow job delayed", "startTime", jobRequest.Schedule)
 	}
 
 	job, err := s.joblet.StartJob(ctx, jobRequest)
@@ -929,39 +1526,167 @@ func (s *WorkflowServiceServer) executeWorkflowJob(ctx context.Context, workflow
 	s.workflowManager.OnJobStateChange(job.Uuid, job.Status)
 	log.Info("workflow job started", "jobId", job.Uuid)
 
-	go s.monitorWorkflowJob(ctx, job.Uuid, job.Uuid)
+	s.monitorWorkflowJob(job.Uuid)
 
 	return nil
 }
 
-// monitorWorkflowJob continuously monitors a workflow job's status and updates the workflow manager.
-// Runs in a separate goroutine for each job, checking status at regular intervals.
-// Handles job state changes and notifies the workflow manager for dependency processing.
-// Terminates when the job reaches a terminal state (completed, failed, canceled, stopped).
-func (s *WorkflowServiceServer) monitorWorkflowJob(ctx context.Context, jobName, jobID string) {
-	log := s.logger.WithFields("jobName", jobName, "jobId", jobID)
-	ticker := time.NewTicker(jobMonitoringInterval)
+// jobOutputs reads the outputs the referenced jobs published, keyed by job
+// name and var. The jobs ended before the referencing job became ready.
+func (s *WorkflowServiceServer) jobOutputs(workflowID int, workflowYAML *WorkflowYAML, refs []workflow.OutputRef) (map[string]map[string]string, error) {
+	state, err := s.workflowManager.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	jobIDs := make(map[string]string, len(state.Jobs))
+	for jobID, dep := range state.Jobs {
+		jobIDs[dep.InternalName] = jobID
+	}
+
+	outputs := make(map[string]map[string]string)
+	for _, ref := range refs {
+		if _, done := outputs[ref.Job]; done {
+			continue
+		}
+		spec := workflowYAML.Jobs[ref.Job].Outputs
+		job, exists := s.jobStore.Job(jobIDs[ref.Job])
+		if !exists || spec == nil {
+			return nil, fmt.Errorf("outputs of job '%s' are not available", ref.Job)
+		}
+		if job.Outputs == nil {
+			return nil, fmt.Errorf("job '%s' did not leave its outputs in %s, see its events", ref.Job, spec.File)
+		}
+		vars, err := workflow.ExtractOutputs(spec, job.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("outputs of job '%s': %w", ref.Job, err)
+		}
+		outputs[ref.Job] = vars
+	}
+	return outputs, nil
+}
+
+// expandOutputs replaces the output references in args
+func expandOutputs(outputs map[string]map[string]string, args []string) ([]string, error) {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		if expanded[i], err = workflow.ExpandOutputs(arg, outputs); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// workflowJobStartTime returns when a ready job may start according to its
+// schedule, not_before and window fields, or the zero time if it may start now
+func (s *WorkflowServiceServer) workflowJobStartTime(workflowID int, jobSpec JobSpec) (time.Time, error) {
+	if jobSpec.Schedule == "" && jobSpec.NotBefore == "" && jobSpec.Window == "" {
+		return time.Time{}, nil
+	}
+	submitted := time.Now()
+	if state, err := s.workflowManager.GetWorkflowStatus(workflowID); err == nil {
+		submitted = state.CreatedAt
+	}
+	timing, err := workflow.ParseJobTiming(jobSpec, submitted)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// The scheduler works in whole seconds; anything sooner starts now
+	now := time.Now()
+	if startTime := timing.StartTime(now); startTime.Sub(now) >= time.Second {
+		return startTime, nil
+	}
+	return time.Time{}, nil
+}
+
+// monitorWorkflowJob checks a started workflow job at once, for a job that
+// changed before it was known to its workflow, then hands it to the monitor
+// until it ends. Job events end it sooner, as they arrive.
+func (s *WorkflowServiceServer) monitorWorkflowJob(jobID string) {
+	s.monitoredJobs.Store(jobID, struct{}{})
+	if s.checkWorkflowJob(jobID) {
+		s.monitoredJobs.Delete(jobID)
+		return
+	}
+	s.monitorOnce.Do(func() { go s.monitorWorkflowJobs() })
+}
+
+// monitorWorkflowJobs checks the status of every monitored job at regular
+// intervals, and drops those that ended. While job events drive the
+// workflows this only catches the events the job store dropped.
+func (s *WorkflowServiceServer) monitorWorkflowJobs() {
+	interval, modeChanged := s.pollInterval(jobMonitoringInterval)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ctx.Done():
-			return
+		case <-modeChanged:
+			interval, modeChanged = s.pollInterval(jobMonitoringInterval)
+			ticker.Reset(interval)
+			continue
 		case <-ticker.C:
-			job, exists := s.jobStore.Job(jobID)
-			if !exists {
-				log.Warn("job not found in store")
-				continue
-			}
-
-			s.workflowManager.OnJobStateChange(jobID, job.Status)
+		}
 
-			if job.Status == domain.StatusCompleted || job.Status == domain.StatusFailed {
-				log.Info("job monitoring completed", "status", job.Status)
-				return
+		s.monitoredJobs.Range(func(key, _ any) bool {
+			jobID := key.(string)
+			if s.checkWorkflowJob(jobID) {
+				s.monitoredJobs.Delete(jobID)
 			}
+			return true
+		})
+	}
+}
+
+// checkWorkflowJob applies a job's status to its workflow. Returns true once
+// the job has ended.
+func (s *WorkflowServiceServer) checkWorkflowJob(jobID string) bool {
+	job, exists := s.jobStore.Job(jobID)
+	if !exists {
+		s.logger.Warn("workflow job not found in store", "jobId", jobID)
+		return false
+	}
+	if s.workflowManager.OnJobStateChange(jobID, job.Status) {
+		if workflowID, ok := s.workflowManager.GetJobWorkflow(jobID); ok {
+			s.checkpointWorkflow(workflowID)
 		}
 	}
+	if endedJobStatuses[job.Status] {
+		s.logger.Debug("workflow job monitoring completed", "jobId", jobID, "status", job.Status)
+		return true
+	}
+	return false
+}
+
+// pollInterval returns how often to poll for what job events report,
+// interval without them and eventFallbackInterval with them, and a channel
+// closed once that changes
+func (s *WorkflowServiceServer) pollInterval(interval time.Duration) (time.Duration, <-chan struct{}) {
+	s.modeMu.Lock()
+	defer s.modeMu.Unlock()
+	if s.modeChanged == nil {
+		s.modeChanged = make(chan struct{})
+	}
+	if s.eventDriven {
+		return eventFallbackInterval, s.modeChanged
+	}
+	return interval, s.modeChanged
+}
+
+// setEventDriven records whether job events drive the workflows, waking up
+// the loops polling for them to pick their new interval
+func (s *WorkflowServiceServer) setEventDriven(on bool) {
+	s.modeMu.Lock()
+	defer s.modeMu.Unlock()
+	if s.eventDriven == on {
+		return
+	}
+	s.eventDriven = on
+	if s.modeChanged != nil {
+		close(s.modeChanged)
+	}
+	s.modeChanged = make(chan struct{})
 }
 
 // parseWorkflowYAML reads and parses a workflow YAML file from the filesystem.
@@ -973,12 +1698,16 @@ func (s *WorkflowServiceServer) parseWorkflowYAML(yamlPath string) (*WorkflowYAM
 		return nil, fmt.Errorf("failed to read YAML file: %w", err)
 	}
 
-	var workflow WorkflowYAML
-	if err := yaml.Unmarshal(data, &workflow); err != nil {
+	var wf WorkflowYAML
+	if err := yaml.Unmarshal(data, &wf); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
+	if err := workflow.ExpandStages(&wf); err != nil {
+		return nil, fmt.Errorf("invalid stages: %w", err)
+	}
+	workflow.ApplyDefaults(&wf)
 
-	return &workflow, nil
+	return &wf, nil
 }
 
 // Use shared types from workflow/types package
@@ -991,7 +1720,7 @@ type JobResources = types.JobResources
 // Handles client-uploaded workflow definitions with associated files.
 // Creates necessary volumes, processes file uploads, creates jobs, and starts orchestration.
 // This is the primary method for client-side workflow execution via the CLI.
-func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx context.Context, yamlContent string, workflowFiles []*pb.FileUpload) (string, error) {
+func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx context.Context, yamlContent string, workflowFiles []*pb.FileUpload, signature *domain.JobSignature) (string, error) {
 	// Generate UUID for this workflow
 	workflowUuid := s.generateWorkflowUUID()
 	log := s.logger.WithFields("contentLength", len(yamlContent), "workflowUuid", workflowUuid)
@@ -1002,10 +1731,13 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx contex
 	if err != nil {
 		return "", fmt.Errorf("failed to parse workflow YAML content: %w", err)
 	}
+	if err := applyRequestAnnotations(ctx, workflowYAML); err != nil {
+		return "", err
+	}
 
 	// Validate workflow before execution
 	log.Info("performing server-side workflow validation")
-	if err := s.workflowValidator.ValidateWorkflow(*workflowYAML); err != nil {
+	if err := s.workflowValidator.ValidateWorkflow(s.withResolvedRuntimes(ctx, *workflowYAML)); err != nil {
 		log.Error("workflow validation failed", "error", err)
 		return "", fmt.Errorf("workflow validation failed: %w", err)
 	}
@@ -1023,28 +1755,10 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx contex
 	var jobOrder []string
 
 	for jobName, jobSpec := range workflowYAML.Jobs {
-		dependencies := make(map[string]string)
-		if jobSpec.Requires != nil {
-			for _, req := range jobSpec.Requires {
-				for depJob, depStatus := range req {
-					dependencies[depJob] = depStatus
-				}
-			}
-		}
-
-		var requirements []workflow.Requirement
-		for depJob, status := range dependencies {
-			requirements = append(requirements, workflow.Requirement{
-				Type:   workflow.RequirementSimple,
-				JobID:  depJob,
-				Status: status,
-			})
-		}
-
 		jobs[jobName] = &workflow.JobDependency{
 			JobID:        jobName,
 			InternalName: jobName,
-			Requirements: requirements,
+			Requirements: workflow.RequirementsFromYAML(jobSpec.Requires),
 			Status:       domain.StatusPending,
 		}
 		jobOrder = append(jobOrder, jobName)
@@ -1063,9 +1777,15 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx contex
 	if err != nil {
 		return "", fmt.Errorf("failed to create workflow: %w", err)
 	}
+	if signature != nil {
+		if err := s.workflowManager.SetWorkflowSignature(workflowID, signature); err != nil {
+			log.Warn("failed to store workflow signature", "error", err)
+		}
+	}
 
 	// Store workflow UUID -> ID mapping
 	s.storeWorkflowMapping(workflowUuid, workflowID)
+	s.checkpointNewWorkflow(ctx, workflowID, workflowYAML, uploadedFiles)
 
 	log.Info("workflow created from client content, starting job orchestration", "workflowId", workflowID)
 
@@ -1077,7 +1797,7 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx contex
 	}
 
 	// Start orchestration with background context and uploaded files
-	go s.orchestrateWorkflow(context.Background(), workflowID, workflowYAML, uploadedFiles)
+	go s.orchestrateWorkflow(withTenant(context.Background(), s.tenantOf(ctx)), workflowID, workflowYAML, uploadedFiles)
 
 	return workflowUuid, nil
 }
@@ -1086,11 +1806,15 @@ func (s *WorkflowServiceServer) StartWorkflowOrchestrationWithContent(ctx contex
 // Used for client-uploaded workflow definitions sent via gRPC.
 // Returns the parsed workflow structure ready for job creation and orchestration.
 func (s *WorkflowServiceServer) parseWorkflowYAMLContent(yamlContent string) (*WorkflowYAML, error) {
-	var workflow WorkflowYAML
-	if err := yaml.Unmarshal([]byte(yamlContent), &workflow); err != nil {
+	var wf WorkflowYAML
+	if err := yaml.Unmarshal([]byte(yamlContent), &wf); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML content: %w", err)
 	}
-	return &workflow, nil
+	if err := workflow.ExpandStages(&wf); err != nil {
+		return nil, fmt.Errorf("invalid stages: %w", err)
+	}
+	workflow.ApplyDefaults(&wf)
+	return &wf, nil
 }
 
 func (s *WorkflowServiceServer) autoCreateWorkflowVolumes(workflowYAML *WorkflowYAML) error {
@@ -1189,7 +1913,7 @@ func (s *WorkflowServiceServer) GetJobStatus(ctx context.Context, req *pb.GetJob
 	job, exists := s.jobStore.JobByPrefix(req.GetUuid())
 	if !exists {
 		log.Error("job not found", "jobId", req.GetUuid())
-		return nil, status.Errorf(codes.NotFound, "job %s not found", req.GetUuid())
+		return nil, s.jobLookupError(req.GetUuid())
 	}
 
 	// Convert to protobuf using mapper
@@ -1198,6 +1922,65 @@ func (s *WorkflowServiceServer) GetJobStatus(ctx context.Context, req *pb.GetJob
 
 	log.Debug("job status retrieved successfully", "status", job.Status)
 
+	if err := s.signatures.setHeader(ctx, job.Signature, jobDigest(job)); err != nil {
+		log.Warn("failed to set response header", "header", constants.SignatureStatusHeader, "error", err)
+	}
+
+	if job.Redactions > 0 {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(constants.RedactionsHeader, strconv.FormatInt(job.Redactions, 10))); err != nil {
+			log.Warn("failed to set response header", "header", constants.RedactionsHeader, "error", err)
+		}
+	}
+
+	if job.OutputDroppedBytes > 0 {
+		md := metadata.Pairs(constants.OutputDroppedHeader, strconv.FormatInt(job.OutputDroppedBytes, 10))
+		if job.OutputTruncated {
+			md.Set(constants.OutputTruncatedHeader, "true")
+		}
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			log.Warn("failed to set response header", "header", constants.OutputDroppedHeader, "error", err)
+		}
+	}
+
+	if len(job.Events) > 0 {
+		if events, err := json.Marshal(job.Events); err == nil {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(constants.EventsHeader, string(events))); err != nil {
+				log.Warn("failed to set response header", "header", constants.EventsHeader, "error", err)
+			}
+		}
+	}
+
+	if job.Fingerprint != nil {
+		if fingerprint, err := json.Marshal(newFingerprintJSON(job.Fingerprint)); err == nil {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(constants.FingerprintHeader, string(fingerprint))); err != nil {
+				log.Warn("failed to set response header", "header", constants.FingerprintHeader, "error", err)
+			}
+		}
+	}
+
+	if a, flagged := s.anomalies.Anomaly(job.Uuid); flagged {
+		if data, err := json.Marshal(a); err == nil {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(constants.DurationAnomalyHeader, string(data))); err != nil {
+				log.Warn("failed to set response header", "header", constants.DurationAnomalyHeader, "error", err)
+			}
+		}
+	}
+
+	if job.NetworkSummary != nil {
+		if data, err := json.Marshal(job.NetworkSummary); err == nil {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(constants.NetworkSummaryHeader, string(data))); err != nil {
+				log.Warn("failed to set response header", "header", constants.NetworkSummaryHeader, "error", err)
+			}
+		}
+	}
+
+	if len(job.Annotations) > 0 {
+		header := values.FormatAnnotations(job.Annotations)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(constants.AnnotationsHeader, header)); err != nil {
+			log.Warn("failed to set response header", "header", constants.AnnotationsHeader, "error", err)
+		}
+	}
+
 	// Mask secret environment variables for status display
 	maskedSecretEnv := make(map[string]string)
 	for key := range pbJob.SecretEnvironment {
@@ -1242,6 +2025,12 @@ func (s *WorkflowServiceServer) StopJob(ctx context.Context, req *pb.StopJobReq)
 		return nil, err
 	}
 
+	// An unknown UUID may name a job array, which clients stop in bulk
+	if _, exists := s.jobStore.Job(req.GetUuid()); !exists {
+		log.Warn("job not found")
+		return nil, status.Errorf(codes.NotFound, "job %s not found", req.GetUuid())
+	}
+
 	// Create stop request object
 	stopRequest := interfaces.StopJobRequest{
 		JobID: req.GetUuid(),
@@ -1353,8 +2142,37 @@ func (s *WorkflowServiceServer) GetJobLogs(req *pb.GetJobLogsReq, stream pb.JobS
 		return err
 	}
 
+	// Tell the client the heartbeat interval, and that reconnects may resume
+	// after the chunks it already has
+	offset := logResumeOffset(stream.Context())
+	if err := stream.SetHeader(metadata.Pairs(constants.LogHeartbeatHeader, s.logHeartbeat.String())); err != nil {
+		log.Debug("failed to set log stream header", "error", err)
+	}
+
+	watched, stop := watchLogStream(stream, s.logHeartbeat, s.logSendTimeout)
+	defer stop()
+	err := s.streamJobLogs(req, watched, offset)
+	if streamErr := watched.Err(); streamErr != nil {
+		log.Warn("abandoned dead log stream", "error", streamErr)
+		return streamErr
+	}
+	return err
+}
+
+// streamJobLogs streams a job's log from persist and the job store, skipping
+// the first offset chunks a reconnecting client already received
+func (s *WorkflowServiceServer) streamJobLogs(req *pb.GetJobLogsReq, stream pb.JobService_GetJobLogsServer, offset int) error {
+	log := s.logger.WithFields("operation", "GetJobLogs", "jobId", req.GetUuid(), "offset", offset)
+
+	// Fast path: while the job's in-memory ring buffer still holds all of its
+	// output (short or running jobs), serve tail and follow entirely from memory
+	if s.jobStore.HasCompleteLogTail(req.GetUuid()) {
+		log.Debug("serving logs from in-memory tail")
+		return s.streamBufferedLogs(req, stream, offset)
+	}
+
 	// Step 1: Fetch and stream historical logs from persist (if available)
-	// Always query persist first for complete historical data
+	// The tail has been trimmed, so persist is the source for older output
 	historicalCount := 0
 	if s.persistClient != nil {
 		log.Debug("fetching historical logs from persist")
@@ -1382,6 +2200,12 @@ func (s *WorkflowServiceServer) GetJobLogs(req *pb.GetJobLogsReq, stream pb.JobS
 					break
 				}
 
+				// Skip the lines a reconnecting client already has
+				if historicalCount < offset {
+					historicalCount++
+					continue
+				}
+
 				// Send historical log line to client as DataChunk
 				if err := stream.Send(&pb.DataChunk{Payload: logLine.Content}); err != nil {
 					log.Error("failed to send historical log to client", "error", err)
@@ -1407,11 +2231,19 @@ func (s *WorkflowServiceServer) GetJobLogs(req *pb.GetJobLogsReq, stream pb.JobS
 		}
 	}
 
-	// Job is still running or persist has no data - stream from buffer + live subscription
-	log.Debug("starting live log streaming from buffer")
+	// Job is still running or persist has no data - stream from buffer + live subscription,
+	// skipping the chunks persist already sent
+	log.Debug("starting live log streaming from buffer", "totalFromPersist", historicalCount)
+	return s.streamBufferedLogs(req, stream, max(historicalCount, offset))
+}
+
+// streamBufferedLogs streams a job's buffered log tail followed by live updates,
+// skipping the first skipCount chunks of the job's output.
+func (s *WorkflowServiceServer) streamBufferedLogs(req *pb.GetJobLogsReq, stream pb.JobService_GetJobLogsServer, skipCount int) error {
+	log := s.logger.WithFields("operation", "GetJobLogs", "jobId", req.GetUuid())
 	streamer := &workflowGrpcToDomainStreamer{stream: stream}
 
-	err := s.jobStore.SendUpdatesToClient(stream.Context(), req.GetUuid(), streamer)
+	err := s.jobStore.SendUpdatesToClientWithSkip(stream.Context(), req.GetUuid(), streamer, skipCount)
 	if err != nil {
 		log.Error("failed to stream logs", "error", err)
 		if err.Error() == "job not found" {
@@ -1420,7 +2252,7 @@ func (s *WorkflowServiceServer) GetJobLogs(req *pb.GetJobLogsReq, stream pb.JobS
 		return status.Errorf(codes.Internal, "failed to stream logs: %v", err)
 	}
 
-	log.Debug("log streaming completed successfully", "totalFromPersist", historicalCount)
+	log.Debug("log streaming completed successfully", "skipped", skipCount)
 	return nil
 }
 
@@ -1446,31 +2278,71 @@ func (g *workflowGrpcToDomainStreamer) Context() context.Context {
 	return g.stream.Context()
 }
 
+// mergeCgroupParams combines the workflow's cgroup_params with a job's own,
+// which take precedence; nil when neither sets any
+func mergeCgroupParams(workflowParams, jobParams map[string]string) map[string]string {
+	if len(workflowParams) == 0 && len(jobParams) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(workflowParams)+len(jobParams))
+	maps.Copy(merged, workflowParams)
+	maps.Copy(merged, jobParams)
+	return merged
+}
+
+// applyRequestAnnotations adds the annotations of the request's
+// joblet-annotations-bin header, such as the CI pipeline rnx runs in, to those
+// of the workflow file, which win for the keys both set. The workflow
+// validation checks the result.
+func applyRequestAnnotations(ctx context.Context, workflowYAML *WorkflowYAML) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := md.Get(constants.AnnotationsHeader)
+	if len(header) == 0 {
+		return nil
+	}
+	annotations, err := values.ParseAnnotations(header[0])
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s header: %v", constants.AnnotationsHeader, err)
+	}
+	maps.Copy(annotations, workflowYAML.Annotations)
+	workflowYAML.Annotations = annotations
+	return nil
+}
+
 // mergeEnvironmentVariables combines global workflow environment variables with job-specific ones.
 // Job-specific variables take precedence over global workflow variables.
-// Supports basic templating for referencing workflow-level variables.
+// Values can reference variables of either level with ${VAR_NAME}, see resolveEnvironment.
 func (s *WorkflowServiceServer) mergeEnvironmentVariables(workflowYAML *WorkflowYAML, jobSpec JobSpec) (map[string]string, map[string]string) {
 	log := s.logger.WithField("operation", "merge-environment-variables")
 
 	// Start with global workflow environment variables
+	globals := make(map[string]envVar)
+	if workflowYAML != nil {
+		for key, value := range workflowYAML.Environment {
+			globals[key] = envVar{value: value, secret: types.IsSecretKey(key)}
+		}
+		for key, value := range workflowYAML.Secrets {
+			globals[key] = envVar{value: value, secret: true}
+		}
+	}
+	resolvedGlobals := resolveEnvironment(globals, nil)
+
+	// Process job-specific environment variables; separate secrets from regular
+	// environment variables based on naming convention, and keep overridden
+	// global secrets secret
+	jobVars := make(map[string]envVar, len(jobSpec.Environment))
+	for key, value := range jobSpec.Environment {
+		jobVars[key] = envVar{value: value, secret: types.IsSecretKey(key) || resolvedGlobals[key].secret}
+		log.Debug("job environment variable", "key", key, "secret", jobVars[key].secret)
+	}
+
 	mergedEnvironment := make(map[string]string)
 	mergedSecretEnvironment := make(map[string]string)
-
-	// Process job-specific environment variables
-	if jobSpec.Environment != nil {
-		for key, value := range jobSpec.Environment {
-			// Separate secrets from regular environment variables based on naming convention
-			if isSecretKey(key) {
-				// Apply templating to secret variables
-				processedValue := s.processEnvironmentTemplating(value, mergedEnvironment, mergedSecretEnvironment)
-				mergedSecretEnvironment[key] = processedValue
-				log.Debug("job secret environment variable", "key", key)
-			} else {
-				// Apply templating to regular variables
-				processedValue := s.processEnvironmentTemplating(value, mergedEnvironment, mergedSecretEnvironment)
-				mergedEnvironment[key] = processedValue
-				log.Debug("job environment variable", "key", key, "value", processedValue)
-			}
+	for key, v := range resolveEnvironment(jobVars, resolvedGlobals) {
+		if v.secret {
+			mergedSecretEnvironment[key] = v.value
+		} else {
+			mergedEnvironment[key] = v.value
 		}
 	}
 
@@ -1478,45 +2350,58 @@ func (s *WorkflowServiceServer) mergeEnvironmentVariables(workflowYAML *Workflow
 	return mergedEnvironment, mergedSecretEnvironment
 }
 
-// isSecretKey determines if an environment variable key represents a secret based on naming conventions.
-// Keys starting with "SECRET_" or ending with "_TOKEN", "_KEY", "_PASSWORD", "_SECRET" are considered secrets.
-func isSecretKey(key string) bool {
-	key = strings.ToUpper(key)
-	return strings.HasPrefix(key, "SECRET_") ||
-		strings.HasSuffix(key, "_TOKEN") ||
-		strings.HasSuffix(key, "_KEY") ||
-		strings.HasSuffix(key, "_PASSWORD") ||
-		strings.HasSuffix(key, "_SECRET")
+// envVar is a workflow environment variable
+type envVar struct {
+	value  string
+	secret bool
 }
 
-// processEnvironmentTemplating processes basic environment variable templating.
-// Supports ${VAR_NAME} syntax for referencing other environment variables.
-// This provides a simple templating system for workflow environment variable inheritance.
-func (s *WorkflowServiceServer) processEnvironmentTemplating(value string, envVars map[string]string, secretEnvVars map[string]string) string {
-	// Simple templating: replace ${VAR_NAME} with the value of VAR_NAME
-	// This is a basic implementation - could be enhanced with more sophisticated templating later
-
-	processedValue := value
-
-	// Process regular environment variable references
-	for refKey, refValue := range envVars {
-		placeholder := fmt.Sprintf("${%s}", refKey)
-		if strings.Contains(processedValue, placeholder) {
-			processedValue = strings.ReplaceAll(processedValue, placeholder, refValue)
-			s.logger.Debug("templating: replaced environment variable reference", "placeholder", placeholder, "value", refValue)
+// envReference matches a ${VAR_NAME} reference
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveEnvironment resolves ${VAR_NAME} references in vars and returns them
+// on top of inherited. A reference is resolved to another variable of vars
+// (recursively), else to an inherited variable; a variable referencing its own
+// name gets the inherited value, which lets a job extend a global variable.
+// Unknown and circular references are left as they are. A variable whose
+// value references a secret becomes a secret itself, so secrets never end up
+// in regular environment variables.
+func resolveEnvironment(vars, inherited map[string]envVar) map[string]envVar {
+	resolved := make(map[string]envVar, len(inherited)+len(vars))
+	for key, v := range inherited {
+		resolved[key] = v
+	}
+
+	const resolving, done = 1, 2
+	state := make(map[string]int, len(vars))
+	var resolve func(key string) envVar
+	resolve = func(key string) envVar {
+		if state[key] == done {
+			return resolved[key]
 		}
+		state[key] = resolving
+		v := vars[key]
+		v.value = envReference.ReplaceAllStringFunc(v.value, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			var target envVar
+			if _, ok := vars[name]; ok && name != key && state[name] != resolving {
+				target = resolve(name)
+			} else if inheritedVar, ok := inherited[name]; ok {
+				target = inheritedVar
+			} else {
+				return ref
+			}
+			v.secret = v.secret || target.secret
+			return target.value
+		})
+		state[key] = done
+		resolved[key] = v
+		return v
 	}
-
-	// Process secret environment variable references
-	for refKey, refValue := range secretEnvVars {
-		placeholder := fmt.Sprintf("${%s}", refKey)
-		if strings.Contains(processedValue, placeholder) {
-			processedValue = strings.ReplaceAll(processedValue, placeholder, refValue)
-			s.logger.Debug("templating: replaced secret environment variable reference", "placeholder", placeholder)
-		}
+	for key := range vars {
+		resolve(key)
 	}
-
-	return processedValue
+	return resolved
 }
 
 // generateWorkflowUUID generates a UUID for workflow identification
@@ -1534,36 +2419,27 @@ func (s *WorkflowServiceServer) generateWorkflowUUID() string {
 func (s *WorkflowServiceServer) storeWorkflowMapping(uuid string, workflowID int) {
 	s.workflowMapMutex.Lock()
 	defer s.workflowMapMutex.Unlock()
-	s.workflowUuidMap[uuid] = workflowID
+	s.workflowUuids.Put(uuid, workflowID)
+	s.workflowIDToUuid[workflowID] = uuid
 	s.logger.Debug("stored workflow UUID mapping", "uuid", uuid, "workflowID", workflowID)
 }
 
 // lookupWorkflowID looks up workflow ID by UUID (supports prefix matching)
 func (s *WorkflowServiceServer) lookupWorkflowID(uuid string) (int, bool) {
-	s.workflowMapMutex.RLock()
-	defer s.workflowMapMutex.RUnlock()
-
 	// First try exact match
-	if id, exists := s.workflowUuidMap[uuid]; exists {
+	if id, exists := s.workflowUuids.Get(uuid); exists {
 		return id, true
 	}
 
 	// If exact match fails and it's a prefix (less than 36 chars), try prefix matching
 	if len(uuid) < 36 {
-		var matches []int
-		var matchedUuids []string
-		for storedUuid, id := range s.workflowUuidMap {
-			if strings.HasPrefix(storedUuid, uuid) {
-				matches = append(matches, id)
-				matchedUuids = append(matchedUuids, storedUuid)
-			}
-		}
-
-		if len(matches) == 1 {
-			s.logger.Debug("found unique workflow by prefix", "prefix", uuid, "fullUuid", matchedUuids[0], "workflowID", matches[0])
-			return matches[0], true
-		} else if len(matches) > 1 {
-			s.logger.Warn("workflow prefix matches multiple workflows", "prefix", uuid, "matches", matchedUuids)
+		fullUuid, id, count := s.workflowUuids.Resolve(uuid)
+		if count == 1 {
+			s.logger.Debug("found unique workflow by prefix", "prefix", uuid, "fullUuid", fullUuid, "workflowID", id)
+			return id, true
+		} else if count > 1 {
+			s.logger.Warn("workflow prefix matches multiple workflows", "prefix", uuid,
+				"matchCount", count, "matches", s.workflowUuids.Match(uuid, maxReportedWorkflowMatches))
 			return 0, false
 		}
 	}
@@ -1572,15 +2448,38 @@ func (s *WorkflowServiceServer) lookupWorkflowID(uuid string) (int, bool) {
 	return 0, false
 }
 
+// workflowLookupError explains why lookupWorkflowID found no workflow for
+// uuid: a prefix shared by several workflows is an invalid argument that
+// lists them, anything else is not found
+func (s *WorkflowServiceServer) workflowLookupError(uuid string) error {
+	if len(uuid) < 36 {
+		if _, _, count := s.workflowUuids.Resolve(uuid); count > 1 {
+			return status.Errorf(codes.InvalidArgument, "workflow prefix %s is ambiguous, it matches %d workflows: %s",
+				uuid, count, strings.Join(s.workflowUuids.Match(uuid, maxReportedWorkflowMatches), ", "))
+		}
+	}
+	return status.Errorf(codes.NotFound, "workflow not found: %s", uuid)
+}
+
+// jobLookupError explains why JobByPrefix found no job for id, like
+// workflowLookupError does for workflows
+func (s *WorkflowServiceServer) jobLookupError(id string) error {
+	if len(id) < 36 {
+		if matches, count := s.jobStore.MatchJobUUIDs(id, maxReportedWorkflowMatches); count > 1 {
+			return status.Errorf(codes.InvalidArgument, "job prefix %s is ambiguous, it matches %d jobs: %s",
+				id, count, strings.Join(matches, ", "))
+		}
+	}
+	return status.Errorf(codes.NotFound, "job %s not found", id)
+}
+
 // getFullUuidForWorkflowID gets the full UUID for a given workflow ID
 func (s *WorkflowServiceServer) getFullUuidForWorkflowID(workflowID int) string {
 	s.workflowMapMutex.RLock()
 	defer s.workflowMapMutex.RUnlock()
 
-	for uuid, id := range s.workflowUuidMap {
-		if id == workflowID {
-			return uuid
-		}
+	if uuid, exists := s.workflowIDToUuid[workflowID]; exists {
+		return uuid
 	}
 
 	// Fallback if not found (shouldn't happen with our implementation)
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"time"
@@ -176,24 +177,11 @@ func (c *PooledClient) Stats() map[string]interface{} {
 // sendMessageFireAndForget sends a message and waits for acknowledgment
 // This ensures the message was received, but doesn't wait for full processing
 func (c *PooledClient) sendMessageFireAndForget(ctx context.Context, msg Message) error {
-	// Get connection from pool
-	conn, err := c.pool.Get(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to acquire connection: %w", err)
-	}
-
-	// Send message and get acknowledgment
-	response, err := c.pool.sendMessageWithResponse(ctx, conn, msg)
-
+	response, err := c.roundTrip(ctx, msg)
 	if err != nil {
-		// Connection is broken, remove from pool
-		c.pool.Remove(conn)
 		return err
 	}
 
-	// Return connection to pool
-	c.pool.Put(conn)
-
 	// Check if operation succeeded
 	if response != nil && !response.Success {
 		return fmt.Errorf("operation failed: %s", response.Error)
@@ -204,25 +192,39 @@ func (c *PooledClient) sendMessageFireAndForget(ctx context.Context, msg Message
 
 // sendMessageWithResponse sends a message and waits for full response
 func (c *PooledClient) sendMessageWithResponse(ctx context.Context, msg Message) (*Response, error) {
-	// Get connection from pool
-	conn, err := c.pool.Get(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to acquire connection: %w", err)
-	}
-
-	// Send message and get response
-	response, err := c.pool.sendMessageWithResponse(ctx, conn, msg)
+	return c.roundTrip(ctx, msg)
+}
 
-	if err != nil {
-		// Connection is broken, remove from pool
-		c.pool.Remove(conn)
-		return nil, err
+// roundTrip sends a message on a pooled connection and reads the response.
+// Pooled connections to a state process that went away (restarted, or
+// replaced by a standby) fail on write before anything is sent, so the
+// message is retried on the next connection until a new one reaches the
+// process now serving the socket.
+func (c *PooledClient) roundTrip(ctx context.Context, msg Message) (*Response, error) {
+	for attempt := 0; ; attempt++ {
+		// Get connection from pool
+		conn, err := c.pool.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire connection: %w", err)
+		}
+
+		// Send message and get response
+		response, err := c.pool.sendMessageWithResponse(ctx, conn, msg)
+		if err != nil {
+			// Connection is broken, remove from pool
+			c.pool.Remove(conn)
+			if errors.Is(err, errConnGone) && attempt < c.pool.poolSize {
+				c.logger.Debug("state connection gone, retrying on another", "operation", msg.Operation)
+				continue
+			}
+			return nil, err
+		}
+
+		// Return connection to pool
+		c.pool.Put(conn)
+
+		return response, nil
 	}
-
-	// Return connection to pool
-	c.pool.Put(conn)
-
-	return response, nil
 }
 
 // nextRequestID generates a unique request ID (thread-safe)
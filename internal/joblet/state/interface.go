@@ -8,6 +8,9 @@ import (
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 )
 
+// SocketPath is the Unix socket the state service listens on
+const SocketPath = "/opt/joblet/run/state-ipc.sock"
+
 // StateClient defines the interface for state persistence operations
 // Both Client and PooledClient implement this interface
 //
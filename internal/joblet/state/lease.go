@@ -0,0 +1,62 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/leader"
+)
+
+// AcquireLease takes or extends a named lease in the state service for
+// holder; false when another holder's lease has not expired
+func (c *PooledClient) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	msg := Message{
+		Operation: "acquireLease",
+		Lease:     &Lease{Name: name, Holder: holder, TTLMillis: ttl.Milliseconds()},
+		RequestID: c.nextRequestID(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	response, err := c.sendMessageWithResponse(ctx, msg)
+	if err != nil {
+		return false, err
+	}
+
+	if !response.Success {
+		return false, fmt.Errorf("acquire lease failed: %s", response.Error)
+	}
+
+	return response.Acquired, nil
+}
+
+// ReleaseLease gives a named lease up if holder has it
+func (c *PooledClient) ReleaseLease(ctx context.Context, name, holder string) error {
+	msg := Message{
+		Operation: "releaseLease",
+		Lease:     &Lease{Name: name, Holder: holder},
+		RequestID: c.nextRequestID(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return c.sendMessageFireAndForget(ctx, msg)
+}
+
+// NewLease returns a named lease kept by the state service, which persist
+// instances elect their active process with
+func NewLease(client *PooledClient, name string) leader.Lease {
+	return &serviceLease{client: client, name: name}
+}
+
+type serviceLease struct {
+	client *PooledClient
+	name   string
+}
+
+func (l *serviceLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	return l.client.AcquireLease(ctx, l.name, holder, ttl)
+}
+
+func (l *serviceLease) Release(ctx context.Context, holder string) error {
+	return l.client.ReleaseLease(ctx, l.name, holder)
+}
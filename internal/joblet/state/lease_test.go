@@ -0,0 +1,129 @@
+package state
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// leaseServer answers pings and lease requests like a state process, and
+// drops its connections when it stops the way a crashed process does
+type leaseServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	conns    []net.Conn
+	holder   string
+}
+
+func startLeaseServer(t *testing.T, socketPath string) *leaseServer {
+	t.Helper()
+	_ = os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create listener: %v", err)
+	}
+	s := &leaseServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *leaseServer) serve(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		response := Response{RequestID: msg.RequestID, Success: true}
+		s.mu.Lock()
+		switch msg.Operation {
+		case "acquireLease":
+			if s.holder == "" || s.holder == msg.Lease.Holder {
+				s.holder = msg.Lease.Holder
+				response.Acquired = true
+			}
+		case "releaseLease":
+			if s.holder == msg.Lease.Holder {
+				s.holder = ""
+			}
+		}
+		s.mu.Unlock()
+		_ = encoder.Encode(response)
+	}
+}
+
+func (s *leaseServer) stop() {
+	s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func TestPooledClient_Lease(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "state.sock")
+	server := startLeaseServer(t, socketPath)
+	defer server.stop()
+
+	client := NewPooledClient(socketPath, 5, logger.WithField("test", "lease"))
+	defer client.Close()
+	lease := NewLease(client, "persist")
+	ctx := context.Background()
+
+	if ok, err := lease.Acquire(ctx, "a", time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(a) = %v, %v", ok, err)
+	}
+	if ok, err := lease.Acquire(ctx, "b", time.Minute); err != nil || ok {
+		t.Fatalf("Acquire(b) = %v, %v, want the lease held by a", ok, err)
+	}
+	if err := lease.Release(ctx, "a"); err != nil {
+		t.Fatalf("Release(a) error = %v", err)
+	}
+	if ok, err := lease.Acquire(ctx, "b", time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire(b) after release = %v, %v", ok, err)
+	}
+}
+
+func TestPooledClient_FailsOverToNewProcess(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "state.sock")
+	active := startLeaseServer(t, socketPath)
+
+	client := NewPooledClient(socketPath, 5, logger.WithField("test", "failover"))
+	defer client.Close()
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	// The active process dies and a standby binds the same socket; the
+	// pooled connection to the old process must not fail the next call
+	active.stop()
+	standby := startLeaseServer(t, socketPath)
+	defer standby.stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() after failover error = %v", err)
+	}
+}
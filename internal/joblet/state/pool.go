@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
@@ -24,6 +25,10 @@ const (
 	defaultDialTimeout = 5 * time.Second
 )
 
+// errConnGone marks a failed write: the state process on the other end went
+// away and the message was not sent
+var errConnGone = errors.New("state connection gone")
+
 // pooledConn represents a single pooled connection
 type pooledConn struct {
 	conn     net.Conn
@@ -280,7 +285,7 @@ func (p *ConnectionPool) sendMessageWithResponse(ctx context.Context, conn *pool
 	// Write message
 	if _, err := conn.conn.Write(data); err != nil {
 		p.errors.Add(1)
-		return nil, fmt.Errorf("failed to write to state socket: %w", err)
+		return nil, fmt.Errorf("failed to write to state socket: %w: %w", errConnGone, err)
 	}
 
 	// Reset write deadline
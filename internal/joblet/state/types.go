@@ -1,6 +1,8 @@
 package state
 
 import (
+	"encoding/json"
+
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 )
 
@@ -12,16 +14,34 @@ type Message struct {
 	Job       *domain.Job   `json:"job,omitempty"`
 	Jobs      []*domain.Job `json:"jobs,omitempty"`
 	Filter    *Filter       `json:"filter,omitempty"`
+	Lease     *Lease        `json:"lease,omitempty"`
+	Workflow  *Workflow     `json:"workflow,omitempty"`
 	RequestID string        `json:"requestId"`
 	Timestamp int64         `json:"timestamp"`
 }
 
 type Response struct {
-	RequestID string        `json:"requestId"`
-	Success   bool          `json:"success"`
-	Job       *domain.Job   `json:"job,omitempty"`
-	Jobs      []*domain.Job `json:"jobs,omitempty"`
-	Error     string        `json:"error,omitempty"`
+	RequestID     string        `json:"requestId"`
+	Success       bool          `json:"success"`
+	Job           *domain.Job   `json:"job,omitempty"`
+	Jobs          []*domain.Job `json:"jobs,omitempty"`
+	Acquired      bool          `json:"acquired,omitempty"`
+	Workflow      *Workflow     `json:"workflow,omitempty"`
+	WorkflowUUIDs []string      `json:"workflowUuids,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+type Lease struct {
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	TTLMillis int64  `json:"ttlMs,omitempty"`
+}
+
+// Workflow is a workflow checkpoint, opaque to the state service
+type Workflow struct {
+	UUID  string          `json:"uuid"`
+	Meta  json.RawMessage `json:"meta,omitempty"` // Omitted to replace the state only
+	State json.RawMessage `json:"state,omitempty"`
 }
 
 type Filter struct {
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PutWorkflow saves a workflow checkpoint. Without Meta only the state of a
+// workflow saved before is replaced.
+func (c *PooledClient) PutWorkflow(ctx context.Context, wf *Workflow) error {
+	msg := Message{
+		Operation: "putWorkflow",
+		Workflow:  wf,
+		RequestID: c.nextRequestID(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return c.sendMessageFireAndForget(ctx, msg)
+}
+
+// GetWorkflow returns a workflow checkpoint
+func (c *PooledClient) GetWorkflow(ctx context.Context, uuid string) (*Workflow, error) {
+	msg := Message{
+		Operation: "getWorkflow",
+		Workflow:  &Workflow{UUID: uuid},
+		RequestID: c.nextRequestID(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	response, err := c.sendMessageWithResponse(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("get workflow failed: %s", response.Error)
+	}
+
+	return response.Workflow, nil
+}
+
+// DeleteWorkflow removes a workflow checkpoint
+func (c *PooledClient) DeleteWorkflow(ctx context.Context, uuid string) error {
+	msg := Message{
+		Operation: "deleteWorkflow",
+		Workflow:  &Workflow{UUID: uuid},
+		RequestID: c.nextRequestID(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return c.sendMessageFireAndForget(ctx, msg)
+}
+
+// ListWorkflows returns the UUIDs of the saved workflows
+func (c *PooledClient) ListWorkflows(ctx context.Context) ([]string, error) {
+	msg := Message{
+		Operation: "listWorkflows",
+		RequestID: c.nextRequestID(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	response, err := c.sendMessageWithResponse(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("list workflows failed: %s", response.Error)
+	}
+
+	return response.WorkflowUUIDs, nil
+}
@@ -0,0 +1,250 @@
+package uploadsync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotCached is returned for files the cache has no copy of, or a
+// different copy than the one asked for
+var ErrNotCached = errors.New("file is not in the upload cache")
+
+const (
+	indexFile  = "index.json"
+	objectsDir = "objects"
+)
+
+// entry is the cached copy of one file of one owner
+type entry struct {
+	Sum  string    `json:"sum"`
+	Size int64     `json:"size"`
+	Used time.Time `json:"used"`
+}
+
+// Cache keeps the last synced copy of each workflow file per owner, the
+// client identity that synced it, so later runs only send what changed.
+// Contents are stored once per SHA-256 under objects/ in the cache
+// directory; index.json maps owners and paths to them. The least recently
+// used copies are dropped when the contents exceed maxBytes.
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	index map[string]map[string]entry // Owner, then path
+}
+
+// NewCache opens or creates a cache in dir. Index entries whose content is
+// gone are dropped.
+func NewCache(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, objectsDir), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create upload cache: %w", err)
+	}
+	c := &Cache{dir: dir, maxBytes: maxBytes, index: make(map[string]map[string]entry)}
+
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read upload cache index: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &c.index); err != nil {
+			return nil, fmt.Errorf("failed to parse upload cache index: %w", err)
+		}
+	}
+	for owner, files := range c.index {
+		for path, e := range files {
+			if _, err := os.Stat(c.objectPath(e.Sum)); err != nil {
+				delete(files, path)
+			}
+		}
+		if len(files) == 0 {
+			delete(c.index, owner)
+		}
+	}
+	return c, nil
+}
+
+func (c *Cache) objectPath(sum string) string {
+	return filepath.Join(c.dir, objectsDir, sum)
+}
+
+// Signature describes the owner's cached copy of path, an empty signature
+// when there is none
+func (c *Cache) Signature(owner, path string) (Signature, error) {
+	c.mu.Lock()
+	e, ok := c.index[owner][path]
+	c.mu.Unlock()
+	if !ok {
+		return Signature{}, nil
+	}
+
+	f, err := os.Open(c.objectPath(e.Sum))
+	if err != nil {
+		return Signature{}, nil // Evicted meanwhile, the file is sent whole
+	}
+	defer f.Close()
+
+	bs := BlockSize(e.Size)
+	blocks, err := Sign(f, bs)
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to sign cached copy of %s: %w", path, err)
+	}
+	return Signature{Basis: e.Sum, Size: e.Size, BlockSize: bs, Blocks: blocks}, nil
+}
+
+// Apply rebuilds path from ops against the owner's cached copy with the
+// given basis, checks the result against sum and caches it as the owner's
+// new copy. An empty basis rebuilds the file from data ops alone.
+func (c *Cache) Apply(owner, path, basis string, blockSize int, ops []Op, sum string) (copied, added int64, err error) {
+	var basisFile io.ReaderAt
+	var basisSize int64
+	if basis != "" {
+		c.mu.Lock()
+		e, ok := c.index[owner][path]
+		c.mu.Unlock()
+		if !ok || e.Sum != basis {
+			return 0, 0, ErrNotCached
+		}
+		f, err := os.Open(c.objectPath(basis))
+		if err != nil {
+			return 0, 0, ErrNotCached
+		}
+		defer f.Close()
+		basisFile, basisSize = f, e.Size
+	} else {
+		for _, op := range ops {
+			if op.Count > 0 {
+				return 0, 0, fmt.Errorf("delta copies blocks without a cached copy")
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(c.dir, objectsDir), ".sync-*")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	copied, added, err = Apply(basisFile, basisSize, blockSize, ops, io.MultiWriter(tmp, h))
+	if err != nil {
+		return copied, added, err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != sum {
+		return copied, added, fmt.Errorf("rebuilt %s has checksum %s, expected %s", path, got, sum)
+	}
+	if err := tmp.Close(); err != nil {
+		return copied, added, fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	// Stored under the lock, so an eviction cannot take it for unused
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Rename(tmp.Name(), c.objectPath(sum)); err != nil {
+		return copied, added, fmt.Errorf("failed to store cache file: %w", err)
+	}
+	if c.index[owner] == nil {
+		c.index[owner] = make(map[string]entry)
+	}
+	c.index[owner][path] = entry{Sum: sum, Size: copied + added, Used: time.Now()}
+	c.evictLocked(owner, path)
+	return copied, added, c.saveLocked()
+}
+
+// Content returns the owner's cached copy of path when its checksum is sum
+func (c *Cache) Content(owner, path, sum string) ([]byte, error) {
+	c.mu.Lock()
+	e, ok := c.index[owner][path]
+	if ok && e.Sum == sum {
+		e.Used = time.Now()
+		c.index[owner][path] = e
+	}
+	c.mu.Unlock()
+	if !ok || e.Sum != sum {
+		return nil, ErrNotCached
+	}
+
+	content, err := os.ReadFile(c.objectPath(sum))
+	if os.IsNotExist(err) {
+		return nil, ErrNotCached
+	}
+	return content, err
+}
+
+// evictLocked drops the least recently used copies, except the owner's copy
+// of path that was just stored, until the contents fit maxBytes, then
+// removes the contents no copy refers to
+func (c *Cache) evictLocked(owner, path string) {
+	type ref struct {
+		owner, path string
+		entry
+	}
+	var refs []ref
+	sizes := make(map[string]int64)
+	for owner, files := range c.index {
+		for path, e := range files {
+			refs = append(refs, ref{owner, path, e})
+			sizes[e.Sum] = e.Size
+		}
+	}
+	total := int64(0)
+	for _, size := range sizes {
+		total += size
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Used.Before(refs[j].Used) })
+
+	users := make(map[string]int)
+	for _, r := range refs {
+		users[r.Sum]++
+	}
+	for _, r := range refs {
+		if c.maxBytes <= 0 || total <= c.maxBytes {
+			break
+		}
+		if r.owner == owner && r.path == path {
+			continue
+		}
+		delete(c.index[r.owner], r.path)
+		if len(c.index[r.owner]) == 0 {
+			delete(c.index, r.owner)
+		}
+		if users[r.Sum]--; users[r.Sum] == 0 {
+			total -= r.Size
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.dir, objectsDir))
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		// Files starting with a dot are syncs in progress
+		if users[e.Name()] == 0 && !strings.HasPrefix(e.Name(), ".") {
+			os.Remove(filepath.Join(c.dir, objectsDir, e.Name()))
+		}
+	}
+}
+
+// saveLocked writes the index atomically
+func (c *Cache) saveLocked() error {
+	data, err := json.Marshal(c.index)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(c.dir, indexFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write upload cache index: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(c.dir, indexFile))
+}
@@ -0,0 +1,103 @@
+package uploadsync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// syncFile runs one sync the way rnx does
+func syncFile(t *testing.T, c *Cache, owner, path string, data []byte) (copied, added int64) {
+	t.Helper()
+	sig, err := c.Signature(owner, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, added, err = c.Apply(owner, path, sig.Basis, sig.BlockSize, Delta(sig, data), checksum(data))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	return copied, added
+}
+
+func TestCacheSync(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := bytes.Repeat([]byte("model weights v1 "), 20000)
+	if copied, added := syncFile(t, c, "alice", "model.bin", v1); copied != 0 || added != int64(len(v1)) {
+		t.Errorf("first sync copied %d and sent %d bytes", copied, added)
+	}
+
+	v2 := append(append([]byte(nil), v1...), []byte("fine-tuned")...)
+	copied, added := syncFile(t, c, "alice", "model.bin", v2)
+	if added > int64(BlockSize(int64(len(v1))))+10 || copied+added != int64(len(v2)) {
+		t.Errorf("second sync copied %d and sent %d bytes", copied, added)
+	}
+
+	content, err := c.Content("alice", "model.bin", checksum(v2))
+	if err != nil || !bytes.Equal(content, v2) {
+		t.Fatalf("Content() = %d bytes, %v", len(content), err)
+	}
+	if _, err := c.Content("alice", "model.bin", checksum(v1)); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Content() of the replaced copy: %v", err)
+	}
+	if _, err := c.Content("bob", "model.bin", checksum(v2)); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Content() of another owner's copy: %v", err)
+	}
+
+	// The index survives a restart
+	reopened, err := NewCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig, err := reopened.Signature("alice", "model.bin"); err != nil || sig.Basis != checksum(v2) {
+		t.Errorf("Signature() after reopening = %q, %v", sig.Basis, err)
+	}
+}
+
+func TestCacheApplyChecks(t *testing.T) {
+	c, err := NewCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+
+	if _, _, err := c.Apply("alice", "a.txt", "", 0, []Op{{Data: data}}, checksum([]byte("other"))); err == nil {
+		t.Error("Apply() accepted a file with the wrong checksum")
+	}
+	if _, _, err := c.Apply("alice", "a.txt", checksum(data), 4096, nil, checksum(data)); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Apply() against a missing basis: %v", err)
+	}
+	if _, _, err := c.Apply("alice", "a.txt", "", 4096, []Op{{Block: 0, Count: 1}}, checksum(data)); err == nil {
+		t.Error("Apply() copied blocks without a basis")
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	c, err := NewCache(t.TempDir(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := bytes.Repeat([]byte("a"), 60)
+	b := bytes.Repeat([]byte("b"), 60)
+	syncFile(t, c, "alice", "a.txt", a)
+	syncFile(t, c, "alice", "b.txt", b)
+
+	if _, err := c.Content("alice", "a.txt", checksum(a)); !errors.Is(err, ErrNotCached) {
+		t.Errorf("least recently used copy kept: %v", err)
+	}
+	if _, err := c.Content("alice", "b.txt", checksum(b)); err != nil {
+		t.Errorf("newest copy evicted: %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+// Package uploadsync transfers workflow files as deltas against a copy the
+// node cached from an earlier run, the way rsync does: the node describes its
+// copy as block checksums, the client finds the blocks its file still has at
+// any offset using a rolling checksum and sends only the bytes in between.
+package uploadsync
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// MinBlockSize and MaxBlockSize bound the block size picked for a file
+	MinBlockSize = 4 * 1024
+	MaxBlockSize = 1024 * 1024
+
+	// strongSize is the length of the truncated SHA-256 of a block
+	strongSize = 16
+)
+
+// Block is the checksum pair of one block of the cached copy
+type Block struct {
+	Weak   uint32
+	Strong []byte
+}
+
+// Signature describes the cached copy of a file. A file without a cached
+// copy has an empty Basis and no blocks.
+type Signature struct {
+	Basis     string // Hex SHA-256 of the cached copy
+	Size      int64  // Bytes of the cached copy
+	BlockSize int
+	Blocks    []Block
+}
+
+// Op copies Count blocks of the cached copy starting at Block, or adds Data
+// when Count is 0
+type Op struct {
+	Block int64
+	Count int64
+	Data  []byte
+}
+
+// BlockSize picks a block size for a file of the given size: about its
+// square root, so the signature and the delta overhead grow slowly, rounded
+// to 1KB and kept within MinBlockSize and MaxBlockSize
+func BlockSize(size int64) int {
+	bs := int(math.Sqrt(float64(size))) &^ 1023
+	return min(max(bs, MinBlockSize), MaxBlockSize)
+}
+
+// weakSum is the rsync rolling checksum of a block: a is the sum of its
+// bytes and b the sum of the running a's, both mod 2^16
+func weakSum(block []byte) (a, b uint32) {
+	n := uint32(len(block))
+	for i, c := range block {
+		a += uint32(c)
+		b += (n - uint32(i)) * uint32(c)
+	}
+	return a & 0xffff, b & 0xffff
+}
+
+// roll slides a weak checksum over a window of n bytes by one byte
+func roll(a, b uint32, out, in byte, n int) (uint32, uint32) {
+	a = (a - uint32(out) + uint32(in)) & 0xffff
+	b = (b - uint32(n)*uint32(out) + a) & 0xffff
+	return a, b
+}
+
+func strongSum(block []byte) []byte {
+	sum := sha256.Sum256(block)
+	return sum[:strongSize]
+}
+
+// Sign computes the block checksums of r
+func Sign(r io.Reader, blockSize int) ([]Block, error) {
+	if blockSize <= 0 {
+		return nil, fmt.Errorf("invalid block size %d", blockSize)
+	}
+	var blocks []Block
+	buf := make([]byte, blockSize)
+	br := bufio.NewReaderSize(r, blockSize)
+	for {
+		n, err := io.ReadFull(br, buf)
+		if n > 0 {
+			a, b := weakSum(buf[:n])
+			blocks = append(blocks, Block{Weak: a | b<<16, Strong: strongSum(buf[:n])})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Delta computes the ops that rebuild data from the copy sig describes.
+// Runs of consecutive blocks become one copy op.
+func Delta(sig Signature, data []byte) []Op {
+	bs := sig.BlockSize
+	if len(sig.Blocks) == 0 || bs <= 0 {
+		if len(data) == 0 {
+			return nil
+		}
+		return []Op{{Data: data}}
+	}
+
+	// Full blocks by weak checksum. The last block may be shorter, it can
+	// only match the end of data.
+	last := int64(len(sig.Blocks) - 1)
+	lastLen := int(sig.Size - last*int64(bs))
+	full := sig.Blocks
+	if lastLen < bs {
+		full = sig.Blocks[:last]
+	}
+	// The filter answers most misses without a map lookup
+	byWeak := make(map[uint32][]int64, len(full))
+	var filter [1 << 16]bool
+	for i, block := range full {
+		byWeak[block.Weak] = append(byWeak[block.Weak], int64(i))
+		filter[uint16(block.Weak^block.Weak>>16)] = true
+	}
+
+	var ops []Op
+	literal := 0
+	emit := func(end int, block int64) {
+		if end > literal {
+			ops = append(ops, Op{Data: data[literal:end]})
+		}
+		if n := len(ops); n > 0 && ops[n-1].Count > 0 && ops[n-1].Block+ops[n-1].Count == block {
+			ops[n-1].Count++
+			return
+		}
+		ops = append(ops, Op{Block: block, Count: 1})
+	}
+	// match prefers the block after the last one copied, which keeps runs
+	// of repeated blocks in one op
+	match := func(window []byte, candidates []int64) (int64, bool) {
+		next := int64(-1)
+		if n := len(ops); n > 0 && ops[n-1].Count > 0 {
+			next = ops[n-1].Block + ops[n-1].Count
+		}
+		found := int64(-1)
+		var strong []byte
+		for _, i := range candidates {
+			if strong == nil {
+				strong = strongSum(window)
+			}
+			if !bytes.Equal(strong, sig.Blocks[i].Strong) {
+				continue
+			}
+			if i == next {
+				return i, true
+			}
+			if found < 0 {
+				found = i
+			}
+		}
+		return found, found >= 0
+	}
+
+	i := 0
+	var a, b uint32
+	fresh := true
+	for i+bs <= len(data) {
+		if fresh {
+			a, b = weakSum(data[i : i+bs])
+			fresh = false
+		}
+		if weak := a | b<<16; filter[uint16(weak^weak>>16)] {
+			if block, ok := match(data[i:i+bs], byWeak[weak]); ok {
+				emit(i, block)
+				i += bs
+				literal = i
+				fresh = true
+				continue
+			}
+		}
+		if i+bs < len(data) {
+			a, b = roll(a, b, data[i], data[i+bs], bs)
+		}
+		i++
+	}
+
+	if tail := len(data) - lastLen; lastLen > 0 && lastLen < bs && tail >= literal {
+		window := data[tail:]
+		if wa, wb := weakSum(window); wa|wb<<16 == sig.Blocks[last].Weak {
+			if _, ok := match(window, []int64{last}); ok {
+				emit(tail, last)
+				literal = len(data)
+			}
+		}
+	}
+
+	if literal < len(data) {
+		ops = append(ops, Op{Data: data[literal:]})
+	}
+	return ops
+}
+
+// Apply writes the file the ops rebuild from basis, a copy of basisSize
+// bytes, to w. It returns the bytes copied from basis and the bytes of data.
+func Apply(basis io.ReaderAt, basisSize int64, blockSize int, ops []Op, w io.Writer) (copied, added int64, err error) {
+	blocks := int64(0)
+	if blockSize > 0 {
+		blocks = (basisSize + int64(blockSize) - 1) / int64(blockSize)
+	}
+	for _, op := range ops {
+		if op.Count == 0 {
+			n, err := w.Write(op.Data)
+			added += int64(n)
+			if err != nil {
+				return copied, added, err
+			}
+			continue
+		}
+		if op.Block < 0 || op.Count < 0 || op.Block+op.Count > blocks {
+			return copied, added, fmt.Errorf("delta copies blocks %d-%d, the cached copy has %d", op.Block, op.Block+op.Count-1, blocks)
+		}
+		offset := op.Block * int64(blockSize)
+		length := min(op.Count*int64(blockSize), basisSize-offset)
+		n, err := io.Copy(w, io.NewSectionReader(basis, offset, length))
+		copied += n
+		if err != nil {
+			return copied, added, err
+		}
+	}
+	return copied, added, nil
+}
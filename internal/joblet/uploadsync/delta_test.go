@@ -0,0 +1,90 @@
+package uploadsync
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func sign(t *testing.T, basis []byte, blockSize int) Signature {
+	t.Helper()
+	blocks, err := Sign(bytes.NewReader(basis), blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Signature{Basis: "basis", Size: int64(len(basis)), BlockSize: blockSize, Blocks: blocks}
+}
+
+func TestDeltaRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+	const bs = 1024
+	basis := random(10*bs + 300) // Short last block
+
+	concat := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+	tests := []struct {
+		name    string
+		data    []byte
+		maxData int64 // Most bytes the delta may send as data
+	}{
+		{"identical", basis, 0},
+		{"appended", concat(basis, random(500)), 300 + 500}, // The short last block only matches at the end
+		{"inserted at an odd offset", concat(basis[:3*bs+17], random(40), basis[3*bs+17:]), 40 + bs},
+		{"block removed", concat(basis[:2*bs], basis[3*bs:]), 0},
+		{"changed byte", concat(basis[:5*bs], []byte{basis[5*bs] + 1}, basis[5*bs+1:]), bs},
+		{"tail changed", concat(basis[:10*bs], random(300)), 300},
+		{"unrelated", random(4 * bs), 4 * bs},
+		{"empty", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := Delta(sign(t, basis, bs), tt.data)
+			var out bytes.Buffer
+			copied, added, err := Apply(bytes.NewReader(basis), int64(len(basis)), bs, ops, &out)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), tt.data) {
+				t.Fatalf("rebuilt %d bytes, want %d", out.Len(), len(tt.data))
+			}
+			if added > tt.maxData || copied+added != int64(len(tt.data)) {
+				t.Errorf("copied %d and sent %d bytes, at most %d should be sent", copied, added, tt.maxData)
+			}
+		})
+	}
+}
+
+func TestDeltaMergesBlockRuns(t *testing.T) {
+	basis := bytes.Repeat([]byte("0123456789abcdef"), 256) // Every 1KB block alike
+	basis = append(basis, []byte("unique tail")...)
+	ops := Delta(sign(t, basis, 1024), basis)
+	if len(ops) > 3 {
+		t.Errorf("Delta() = %d ops for an unchanged file", len(ops))
+	}
+}
+
+func TestDeltaWithoutBasis(t *testing.T) {
+	ops := Delta(Signature{}, []byte("new file"))
+	if len(ops) != 1 || string(ops[0].Data) != "new file" {
+		t.Errorf("Delta() = %+v", ops)
+	}
+}
+
+func TestApplyRejectsBlocksOutOfRange(t *testing.T) {
+	_, _, err := Apply(bytes.NewReader(make([]byte, 2048)), 2048, 1024, []Op{{Block: 1, Count: 2}}, &bytes.Buffer{})
+	if err == nil {
+		t.Error("Apply() copied past the end of the cached copy")
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	for size, want := range map[int64]int{0: MinBlockSize, 1 << 20: MinBlockSize, 1 << 30: 32768, 1 << 42: MaxBlockSize} {
+		if got := BlockSize(size); got != want {
+			t.Errorf("BlockSize(%d) = %d, want %d", size, got, want)
+		}
+	}
+}
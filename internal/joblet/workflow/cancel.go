@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// CanceledJob is a workflow job selected by CancelJobs
+type CanceledJob struct {
+	Name   string
+	JobID  string           // Actual job ID once started, the name before
+	Status domain.JobStatus // Status when the job was selected
+}
+
+// Started reports whether the job has been started and has not ended yet, so
+// it still has to be stopped
+func (c CanceledJob) Started() bool {
+	return c.Status != domain.StatusPending && !isTerminalState(c.Status)
+}
+
+// CancelJobs cancels a job of a workflow and, with cascade, every job that
+// requires it directly or through other jobs, whatever status they require.
+// Jobs outside that sub-tree keep running.
+//
+// Selected jobs that have not started yet are canceled at once so they never
+// start. Jobs that are running or scheduled are only returned: the caller stops
+// them and their final status reaches the workflow like any other. Jobs that
+// have already ended are returned unchanged. The jobs are returned in workflow
+// order.
+func (dr *DependencyResolver) CancelJobs(workflowID int, jobName string, cascade bool) ([]CanceledJob, error) {
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
+		return nil, fmt.Errorf("workflow %d not found", workflowID)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	workflow := entry.state
+	byName := make(map[string]*JobDependency, len(workflow.Jobs))
+	for _, job := range workflow.Jobs {
+		byName[job.InternalName] = job
+	}
+	if byName[jobName] == nil {
+		return nil, fmt.Errorf("job %s not found in workflow %d", jobName, workflowID)
+	}
+
+	selected := map[string]bool{jobName: true}
+	if cascade {
+		dependents := make(map[string][]string)
+		for name, job := range byName {
+			for _, req := range job.Requirements {
+				for _, required := range requiredJobNames(req) {
+					dependents[required] = append(dependents[required], name)
+				}
+			}
+		}
+		queue := []string{jobName}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			for _, dependent := range dependents[name] {
+				if !selected[dependent] {
+					selected[dependent] = true
+					queue = append(queue, dependent)
+				}
+			}
+		}
+	}
+
+	return dr.cancelSelected(entry, byName, selected), nil
+}
+
+// CancelWorkflow cancels every job of a workflow, like CancelJobs does for a
+// sub-tree: the jobs that have not started are canceled at once and the
+// running and scheduled ones are returned for the caller to stop. The
+// workflow ends CANCELED even when every job it stops had already started.
+func (dr *DependencyResolver) CancelWorkflow(workflowID int) ([]CanceledJob, error) {
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
+		return nil, fmt.Errorf("workflow %d not found", workflowID)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	entry.state.Canceled = true
+	byName := make(map[string]*JobDependency, len(entry.state.Jobs))
+	selected := make(map[string]bool, len(entry.state.Jobs))
+	for _, job := range entry.state.Jobs {
+		byName[job.InternalName] = job
+		selected[job.InternalName] = true
+	}
+	return dr.cancelSelected(entry, byName, selected), nil
+}
+
+// cancelSelected cancels the selected jobs that have not started and returns
+// all the selected jobs in workflow order. The caller holds the entry's lock.
+func (dr *DependencyResolver) cancelSelected(entry *workflowEntry, byName map[string]*JobDependency, selected map[string]bool) []CanceledJob {
+	workflow := entry.state
+	var canceled []CanceledJob
+	var pending []string
+	for name := range selected {
+		job := byName[name]
+		canceled = append(canceled, CanceledJob{Name: name, JobID: job.JobID, Status: job.Status})
+		if job.Status == domain.StatusPending {
+			pending = append(pending, job.JobID)
+		}
+	}
+
+	// Mark every pending job before cascading, so the cascade does not count
+	// a selected job twice
+	for _, jobID := range pending {
+		job := workflow.Jobs[jobID]
+		job.Impossible = true
+		job.CanStart = false
+		job.Status = domain.StatusCanceled
+		entry.setJobState(job.InternalName, domain.StatusCanceled)
+		dr.updateWorkflowCounters(workflow, domain.StatusPending, domain.StatusCanceled)
+	}
+	for _, jobID := range pending {
+		dr.handleTerminalState(entry, jobID, domain.StatusCanceled)
+	}
+	dr.updateWorkflowStatus(workflow)
+
+	order := make(map[string]int, len(workflow.JobOrder))
+	for i, name := range workflow.JobOrder {
+		order[name] = i
+	}
+	sort.Slice(canceled, func(i, j int) bool {
+		if order[canceled[i].Name] != order[canceled[j].Name] {
+			return order[canceled[i].Name] < order[canceled[j].Name]
+		}
+		return canceled[i].Name < canceled[j].Name
+	})
+	return canceled
+}
+
+// requiredJobNames returns the names of the jobs a requirement refers to
+func requiredJobNames(req Requirement) []string {
+	switch req.Type {
+	case RequirementSimple:
+		return []string{req.JobID}
+	case RequirementExpression:
+		return ExpressionJobNames(req.Expression)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// newCancelTestWorkflow creates two independent branches, fetch and docs,
+// with fetch running and docs completed
+func newCancelTestWorkflow(t *testing.T) (*DependencyResolver, int) {
+	t.Helper()
+	dr := NewDependencyResolver()
+	job := func(name string, reqs ...Requirement) *JobDependency {
+		return &JobDependency{JobID: name, InternalName: name, Requirements: reqs, Status: domain.StatusPending}
+	}
+	jobs := map[string]*JobDependency{
+		"fetch":    job("fetch"),
+		"train":    job("train", Requirement{Type: RequirementSimple, JobID: "fetch", Status: "COMPLETED"}),
+		"notify":   job("notify", Requirement{Type: RequirementSimple, JobID: "train", Status: "FAILED"}),
+		"evaluate": job("evaluate", Requirement{Type: RequirementExpression, Expression: "train=COMPLETED OR train=FAILED"}),
+		"docs":     job("docs"),
+		"publish":  job("publish", Requirement{Type: RequirementSimple, JobID: "docs", Status: "COMPLETED"}),
+	}
+	order := []string{"fetch", "train", "notify", "evaluate", "docs", "publish"}
+	workflowID, err := dr.CreateWorkflow("pipeline", jobs, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"fetch", "docs"} {
+		if err := dr.renameJob(name, "uuid-"+name); err != nil {
+			t.Fatal(err)
+		}
+		dr.OnJobStateChange("uuid-"+name, domain.StatusRunning)
+	}
+	return dr, workflowID
+}
+
+func TestDependencyResolver_CancelJobsCascade(t *testing.T) {
+	dr, workflowID := newCancelTestWorkflow(t)
+
+	canceled, err := dr.CancelJobs(workflowID, "fetch", true)
+	if err != nil {
+		t.Fatalf("CancelJobs() error = %v", err)
+	}
+	var names []string
+	for _, job := range canceled {
+		names = append(names, job.Name)
+	}
+	if !slices.Equal(names, []string{"fetch", "train", "notify", "evaluate"}) {
+		t.Fatalf("canceled %v, want the fetch sub-tree in workflow order", names)
+	}
+	if !canceled[0].Started() || canceled[0].JobID != "uuid-fetch" || canceled[1].Started() {
+		t.Errorf("canceled = %+v, want only fetch left to stop", canceled)
+	}
+
+	// The other branch carries on
+	dr.OnJobStateChange("uuid-fetch", domain.StatusStopped)
+	dr.OnJobStateChange("uuid-docs", domain.StatusCompleted)
+	if ready := dr.GetReadyJobs(workflowID); !slices.Equal(ready, []string{"publish"}) {
+		t.Fatalf("ready jobs = %v, want publish", ready)
+	}
+	if err := dr.renameJob("publish", "uuid-publish"); err != nil {
+		t.Fatal(err)
+	}
+	dr.OnJobStateChange("uuid-publish", domain.StatusCompleted)
+
+	state, _ := dr.GetWorkflowStatus(workflowID)
+	if state.Status != WorkflowCanceled || state.CanceledJobs != 3 || state.CompletedJobs != 2 {
+		t.Errorf("workflow %s with %d canceled and %d completed jobs, want CANCELED with 3 and 2",
+			state.Status, state.CanceledJobs, state.CompletedJobs)
+	}
+}
+
+func TestDependencyResolver_CancelJobsWithoutCascade(t *testing.T) {
+	dr, workflowID := newCancelTestWorkflow(t)
+	dr.OnJobStateChange("uuid-fetch", domain.StatusCompleted)
+
+	canceled, err := dr.CancelJobs(workflowID, "train", false)
+	if err != nil {
+		t.Fatalf("CancelJobs() error = %v", err)
+	}
+	if len(canceled) != 1 || canceled[0].Name != "train" || canceled[0].Started() {
+		t.Fatalf("canceled = %+v, want the pending train job only", canceled)
+	}
+
+	// Dependents follow their requirements: both can no longer be met
+	state, _ := dr.GetWorkflowStatus(workflowID)
+	for _, job := range state.Jobs {
+		if (job.InternalName == "notify" || job.InternalName == "evaluate") && job.Status != domain.StatusCanceled {
+			t.Errorf("%s is %s, want CANCELED", job.InternalName, job.Status)
+		}
+	}
+
+	// Ended jobs are returned unchanged
+	canceled, _ = dr.CancelJobs(workflowID, "fetch", false)
+	if len(canceled) != 1 || canceled[0].Status != domain.StatusCompleted || canceled[0].Started() {
+		t.Errorf("canceled = %+v, want the completed fetch job untouched", canceled)
+	}
+
+	if _, err := dr.CancelJobs(workflowID, "deploy", true); err == nil {
+		t.Error("CancelJobs() accepted an unknown job")
+	}
+	if _, err := dr.CancelJobs(workflowID+1, "train", true); err == nil {
+		t.Error("CancelJobs() accepted an unknown workflow")
+	}
+}
+
+func TestDependencyResolver_CancelWorkflow(t *testing.T) {
+	dr, workflowID := newCancelTestWorkflow(t)
+	dr.OnJobStateChange("uuid-docs", domain.StatusCompleted)
+
+	canceled, err := dr.CancelWorkflow(workflowID)
+	if err != nil {
+		t.Fatalf("CancelWorkflow() error = %v", err)
+	}
+	if len(canceled) != 6 {
+		t.Fatalf("canceled %d jobs, want all 6", len(canceled))
+	}
+	var toStop []string
+	for _, job := range canceled {
+		if job.Started() {
+			toStop = append(toStop, job.JobID)
+		}
+	}
+	if !slices.Equal(toStop, []string{"uuid-fetch"}) {
+		t.Errorf("jobs to stop = %v, want only the running fetch", toStop)
+	}
+	if ready := dr.GetReadyJobs(workflowID); len(ready) != 0 {
+		t.Errorf("ready jobs = %v after canceling the workflow", ready)
+	}
+
+	// Stopping the last running job ends the workflow canceled
+	dr.OnJobStateChange("uuid-fetch", domain.StatusStopped)
+	state, _ := dr.GetWorkflowStatus(workflowID)
+	if state.Status != WorkflowCanceled || state.CanceledJobs != 4 {
+		t.Errorf("workflow %s with %d canceled jobs, want CANCELED with 4", state.Status, state.CanceledJobs)
+	}
+
+	if _, err := dr.CancelWorkflow(workflowID + 1); err == nil {
+		t.Error("CancelWorkflow() accepted an unknown workflow")
+	}
+}
+
+func TestDependencyResolver_CancelWorkflowOfRunningJobs(t *testing.T) {
+	dr := NewDependencyResolver()
+	jobs := map[string]*JobDependency{"serve": {JobID: "serve", InternalName: "serve", Status: domain.StatusPending}}
+	workflowID, err := dr.CreateWorkflow("service", jobs, []string{"serve"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dr.renameJob("serve", "uuid-serve"); err != nil {
+		t.Fatal(err)
+	}
+	dr.OnJobStateChange("uuid-serve", domain.StatusRunning)
+
+	if _, err := dr.CancelWorkflow(workflowID); err != nil {
+		t.Fatal(err)
+	}
+	dr.OnJobStateChange("uuid-serve", domain.StatusStopped)
+	if state, _ := dr.GetWorkflowStatus(workflowID); state.Status != WorkflowCanceled {
+		t.Errorf("workflow %s, want CANCELED though no job was pending", state.Status)
+	}
+}
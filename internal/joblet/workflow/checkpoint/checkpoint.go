@@ -0,0 +1,240 @@
+// Package checkpoint saves workflows in the state service so they survive
+// joblet restarts. The state service otherwise only keeps jobs; without
+// checkpoints a restart forgets every workflow, running or ended.
+//
+// Each workflow is saved under its UUID with what resuming it needs besides
+// its state (tenant, request annotations, uploaded files), written once, and
+// its dependency state, replaced on every change. Once a workflow ends its
+// uploaded files are dropped, and Prune removes ended workflows past their
+// retention.
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/state"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// serviceTimeout bounds each call to the state service
+const serviceTimeout = 10 * time.Second
+
+// validUUID keeps UUIDs to what the joblet generates
+var validUUID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,63}$`)
+
+// Service is the part of the state client workflows are saved with
+type Service interface {
+	PutWorkflow(ctx context.Context, wf *state.Workflow) error
+	GetWorkflow(ctx context.Context, uuid string) (*state.Workflow, error)
+	DeleteWorkflow(ctx context.Context, uuid string) error
+	ListWorkflows(ctx context.Context) ([]string, error)
+}
+
+// Workflow is what resuming a workflow needs besides its state
+type Workflow struct {
+	UUID        string            `json:"uuid"`
+	Tenant      string            `json:"tenant,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"` // Workflow annotations with the request's merged in
+	Files       map[string][]byte `json:"files,omitempty"`       // Uploaded files, for the jobs still to start
+}
+
+// Record is a saved workflow
+type Record struct {
+	Workflow
+	State *workflow.WorkflowState
+}
+
+// Store saves workflows in the state service
+type Store struct {
+	service Service
+	logger  *logger.Logger
+
+	// Saved workflows still holding uploaded files, without the files, to
+	// save again once they end
+	mu        sync.Mutex
+	withFiles map[string]Workflow
+}
+
+// New returns a store saving workflows with service
+func New(service Service, log *logger.Logger) *Store {
+	return &Store{
+		service:   service,
+		logger:    log.WithField("component", "workflow-checkpoint"),
+		withFiles: make(map[string]Workflow),
+	}
+}
+
+// Create saves a new workflow with its initial state
+func (s *Store) Create(wf Workflow, state *workflow.WorkflowState) error {
+	if !validUUID.MatchString(wf.UUID) {
+		return fmt.Errorf("invalid workflow UUID %q", wf.UUID)
+	}
+	if len(wf.Files) > 0 && !state.Status.Ended() {
+		s.rememberFiles(wf)
+	}
+	return s.put(wf.UUID, &wf, state)
+}
+
+// Save replaces the saved state of a workflow created before. Once the state
+// has ended, the uploaded files are dropped: no job is left to use them.
+func (s *Store) Save(uuid string, state *workflow.WorkflowState) error {
+	if !validUUID.MatchString(uuid) {
+		return fmt.Errorf("invalid workflow UUID %q", uuid)
+	}
+	if !state.Status.Ended() {
+		return s.put(uuid, nil, state)
+	}
+
+	s.mu.Lock()
+	wf, hadFiles := s.withFiles[uuid]
+	delete(s.withFiles, uuid)
+	s.mu.Unlock()
+	if hadFiles {
+		return s.put(uuid, &wf, state)
+	}
+	return s.put(uuid, nil, state)
+}
+
+// Delete removes a saved workflow
+func (s *Store) Delete(uuid string) error {
+	if !validUUID.MatchString(uuid) {
+		return fmt.Errorf("invalid workflow UUID %q", uuid)
+	}
+	s.mu.Lock()
+	delete(s.withFiles, uuid)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+	if err := s.service.DeleteWorkflow(ctx, uuid); err != nil {
+		return fmt.Errorf("failed to delete workflow %s: %w", uuid, err)
+	}
+	return nil
+}
+
+// Prune deletes the workflows that ended before cutoff and returns how many
+// it deleted. It reads every saved workflow, so it is for occasional sweeps;
+// workflows it cannot read are left for Load to report.
+func (s *Store) Prune(cutoff time.Time) (int, error) {
+	records, err := s.load(false)
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, record := range records {
+		if ended, ok := EndedAt(record.State); !ok || !ended.Before(cutoff) {
+			continue
+		}
+		if err := s.Delete(record.UUID); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// Load reads every saved workflow. Workflows that are unreadable or not
+// saved by the joblet are skipped with a warning, so one corrupt record does
+// not lose the others.
+func (s *Store) Load() ([]Record, error) {
+	return s.load(true)
+}
+
+func (s *Store) load(warn bool) ([]Record, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+	uuids, err := s.service.ListWorkflows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved workflows: %w", err)
+	}
+
+	var records []Record
+	for _, uuid := range uuids {
+		if !validUUID.MatchString(uuid) {
+			if warn {
+				s.logger.Warn("skipping saved workflow with an invalid UUID", "workflowUuid", uuid)
+			}
+			continue
+		}
+		record, err := s.get(ctx, uuid)
+		if err != nil {
+			if warn {
+				s.logger.Warn("skipping unreadable saved workflow", "workflowUuid", uuid, "error", err)
+			}
+			continue
+		}
+		if len(record.Files) > 0 && !record.State.Status.Ended() {
+			s.rememberFiles(record.Workflow)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// EndedAt returns when a workflow state ended; false while it runs
+func EndedAt(state *workflow.WorkflowState) (time.Time, bool) {
+	if !state.Status.Ended() {
+		return time.Time{}, false
+	}
+	if state.CompletedAt != nil {
+		return *state.CompletedAt, true
+	}
+	return state.CreatedAt, true
+}
+
+func (s *Store) get(ctx context.Context, uuid string) (Record, error) {
+	var record Record
+	saved, err := s.service.GetWorkflow(ctx, uuid)
+	if err != nil {
+		return record, err
+	}
+	if saved == nil || saved.Meta == nil || saved.State == nil {
+		return record, fmt.Errorf("incomplete checkpoint")
+	}
+	if err := json.Unmarshal(saved.Meta, &record.Workflow); err != nil {
+		return record, err
+	}
+	if err := json.Unmarshal(saved.State, &record.State); err != nil {
+		return record, err
+	}
+	record.UUID = uuid
+	return record, nil
+}
+
+// put saves the state of a workflow, with wf when given, otherwise keeping
+// the one saved before
+func (s *Store) put(uuid string, wf *Workflow, wfState *workflow.WorkflowState) error {
+	saved := &state.Workflow{UUID: uuid}
+	var err error
+	if saved.State, err = json.Marshal(wfState); err != nil {
+		return err
+	}
+	if wf != nil {
+		if saved.Meta, err = json.Marshal(wf); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+	if err := s.service.PutWorkflow(ctx, saved); err != nil {
+		return fmt.Errorf("failed to save workflow %s: %w", uuid, err)
+	}
+	return nil
+}
+
+// rememberFiles keeps wf without its files, to save in its place once the
+// workflow ends
+func (s *Store) rememberFiles(wf Workflow) {
+	wf.Files = nil
+	s.mu.Lock()
+	s.withFiles[wf.UUID] = wf
+	s.mu.Unlock()
+}
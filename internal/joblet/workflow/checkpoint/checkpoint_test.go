@@ -0,0 +1,127 @@
+package checkpoint
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	stateclient "github.com/ehsaniara/joblet/internal/joblet/state"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	service := NewMemoryService()
+	store := New(service, logger.New())
+
+	state := &workflow.WorkflowState{
+		ID:          3,
+		Workflow:    "etl",
+		YamlContent: "jobs: {extract: {command: ./extract.sh}}",
+		Jobs: map[string]*workflow.JobDependency{
+			"uuid-extract": {JobID: "uuid-extract", InternalName: "extract", Status: domain.StatusRunning},
+			"load": {JobID: "load", InternalName: "load", Status: domain.StatusPending, Requirements: []workflow.Requirement{
+				{Type: workflow.RequirementSimple, JobID: "extract", Status: "COMPLETED"},
+			}},
+		},
+		JobOrder:  []string{"extract", "load"},
+		Status:    workflow.WorkflowRunning,
+		CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		TotalJobs: 2,
+	}
+	wf := Workflow{
+		UUID:        "0b1c9e2a-5d4f-4c1e-9a7b-3f2e1d0c9b8a",
+		Tenant:      "analytics",
+		Annotations: map[string]string{"ci.pipeline": "42"},
+		Files:       map[string][]byte{"extract.sh": []byte("#!/bin/sh\n")},
+	}
+	if err := store.Create(wf, state); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if loaded, _ := store.Load(); len(loaded) != 1 || string(loaded[0].Files["extract.sh"]) != "#!/bin/sh\n" {
+		t.Fatalf("Load() before the end = %+v, want the uploaded files", loaded)
+	}
+
+	state.Jobs["load"].Status = domain.StatusCompleted
+	state.Status = workflow.WorkflowCompleted
+	if err := store.Save(wf.UUID, state); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A corrupt workflow is skipped, and so is one with an invalid UUID
+	ctx := context.Background()
+	_ = service.PutWorkflow(ctx, &stateclient.Workflow{UUID: "corrupt", Meta: []byte("{"), State: []byte("{}")})
+	_ = service.PutWorkflow(ctx, &stateclient.Workflow{UUID: "../escape", Meta: []byte("{}"), State: []byte("{}")})
+
+	// A new store, as after a restart, reads what the service kept
+	loaded, err := New(service, logger.New()).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("Load() = %d records, want 1", len(loaded))
+	}
+	got := loaded[0]
+	if got.UUID != wf.UUID || got.Tenant != "analytics" || got.Annotations["ci.pipeline"] != "42" {
+		t.Errorf("Load() workflow = %+v", got.Workflow)
+	}
+	if got.Files != nil {
+		t.Errorf("Load() kept the uploaded files of an ended workflow: %v", got.Files)
+	}
+	if got.State.Status != workflow.WorkflowCompleted || got.State.Jobs["load"].Status != domain.StatusCompleted {
+		t.Errorf("Load() state = %+v", got.State)
+	}
+	if req := got.State.Jobs["load"].Requirements; len(req) != 1 || req[0].JobID != "extract" {
+		t.Errorf("Load() requirements = %+v", req)
+	}
+	if !got.State.CreatedAt.Equal(state.CreatedAt) {
+		t.Errorf("Load() created at = %v, want %v", got.State.CreatedAt, state.CreatedAt)
+	}
+}
+
+func TestStoreRejectsInvalidUUID(t *testing.T) {
+	store := New(NewMemoryService(), logger.New())
+	if err := store.Create(Workflow{UUID: "../escape"}, &workflow.WorkflowState{}); err == nil {
+		t.Error("Create() with a path as UUID succeeded")
+	}
+	if err := store.Save("", &workflow.WorkflowState{}); err == nil {
+		t.Error("Save() without UUID succeeded")
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	store := New(NewMemoryService(), logger.New())
+	now := time.Date(2026, 10, 8, 9, 0, 0, 0, time.UTC)
+	longAgo, recently := now.Add(-8*24*time.Hour), now.Add(-time.Hour)
+	states := map[string]*workflow.WorkflowState{
+		"ended-long-ago": {Status: workflow.WorkflowCompleted, CreatedAt: longAgo, CompletedAt: &longAgo},
+		"ended-recently": {Status: workflow.WorkflowFailed, CreatedAt: longAgo, CompletedAt: &recently},
+		"still-running":  {Status: workflow.WorkflowRunning, CreatedAt: longAgo},
+	}
+	for uuid, state := range states {
+		if err := store.Create(Workflow{UUID: uuid}, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruned, err := store.Prune(now.Add(-7 * 24 * time.Hour))
+	if err != nil || pruned != 1 {
+		t.Fatalf("Prune() = %d, %v, want 1", pruned, err)
+	}
+	loaded, _ := store.Load()
+	kept := map[string]bool{}
+	for _, record := range loaded {
+		kept[record.UUID] = true
+	}
+	if len(kept) != 2 || !kept["ended-recently"] || !kept["still-running"] {
+		t.Errorf("kept %v, want ended-recently and still-running", kept)
+	}
+
+	if err := store.Delete("still-running"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if loaded, _ := store.Load(); len(loaded) != 1 {
+		t.Errorf("Load() after Delete() = %d records, want 1", len(loaded))
+	}
+}
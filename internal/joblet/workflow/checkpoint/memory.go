@@ -0,0 +1,73 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ehsaniara/joblet/internal/joblet/state"
+)
+
+// MemoryService keeps checkpoints in memory the way the state service does,
+// standing in for it in tests
+type MemoryService struct {
+	mu        sync.Mutex
+	workflows map[string]state.Workflow
+}
+
+// NewMemoryService returns an empty MemoryService
+func NewMemoryService() *MemoryService {
+	return &MemoryService{workflows: make(map[string]state.Workflow)}
+}
+
+// PutWorkflow implements Service
+func (m *MemoryService) PutWorkflow(_ context.Context, wf *state.Workflow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := *wf
+	if saved.Meta == nil {
+		existing, ok := m.workflows[wf.UUID]
+		if !ok {
+			return fmt.Errorf("workflow not found")
+		}
+		saved.Meta = existing.Meta
+	}
+	m.workflows[wf.UUID] = saved
+	return nil
+}
+
+// GetWorkflow implements Service
+func (m *MemoryService) GetWorkflow(_ context.Context, uuid string) (*state.Workflow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wf, ok := m.workflows[uuid]
+	if !ok {
+		return nil, fmt.Errorf("workflow not found")
+	}
+	return &wf, nil
+}
+
+// DeleteWorkflow implements Service
+func (m *MemoryService) DeleteWorkflow(_ context.Context, uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.workflows, uuid)
+	return nil
+}
+
+// ListWorkflows implements Service
+func (m *MemoryService) ListWorkflows(context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uuids := make([]string, 0, len(m.workflows))
+	for uuid := range m.workflows {
+		uuids = append(uuids, uuid)
+	}
+	return uuids, nil
+}
+
+var _ Service = (*MemoryService)(nil)
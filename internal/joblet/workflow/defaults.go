@@ -0,0 +1,71 @@
+package workflow
+
+import "github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+// ApplyDefaults gives the jobs of wf the settings of its defaults block they
+// do not set themselves, so the rest of the server sees fully specified jobs
+func ApplyDefaults(wf *types.WorkflowYAML) {
+	defaults := wf.Defaults
+	if defaults == nil {
+		return
+	}
+	for name, spec := range wf.Jobs {
+		if spec.Runtime == "" {
+			spec.Runtime = defaults.Runtime
+		}
+		if spec.Network == "" {
+			spec.Network = defaults.Network
+		}
+		spec.Resources = defaultResources(spec.Resources, defaults.Resources)
+		spec.Environment = defaultMap(spec.Environment, defaults.Environment)
+		wf.Jobs[name] = spec
+	}
+}
+
+// defaultResources fills the unset limits of r from defaults
+func defaultResources(r, defaults types.JobResources) types.JobResources {
+	if r.MaxCPU == 0 {
+		r.MaxCPU = defaults.MaxCPU
+	}
+	if r.MaxMemory == 0 {
+		r.MaxMemory = defaults.MaxMemory
+	}
+	if r.MaxIOBPS == 0 {
+		r.MaxIOBPS = defaults.MaxIOBPS
+	}
+	if r.CPUCores == "" {
+		r.CPUCores = defaults.CPUCores
+	}
+	if r.GPUCount == 0 {
+		r.GPUCount = defaults.GPUCount
+	}
+	if r.GPUMemoryMB == 0 {
+		r.GPUMemoryMB = defaults.GPUMemoryMB
+	}
+	if r.ShmSize == "" {
+		r.ShmSize = defaults.ShmSize
+	}
+	if r.TmpSize == "" {
+		r.TmpSize = defaults.TmpSize
+	}
+	if r.Scratch == "" {
+		r.Scratch = defaults.Scratch
+	}
+	r.CgroupParams = defaultMap(r.CgroupParams, defaults.CgroupParams)
+	return r
+}
+
+// defaultMap returns a copy of m with the keys of defaults it lacks
+func defaultMap(m, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return m
+	}
+	merged := make(map[string]string, len(m)+len(defaults))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range m {
+		merged[key] = value
+	}
+	return merged
+}
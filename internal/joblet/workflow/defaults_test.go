@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	wf := &types.WorkflowYAML{
+		Defaults: &types.JobDefaults{
+			Runtime: "python-3.11-ml",
+			Network: "bridge",
+			Resources: types.JobResources{
+				MaxCPU:       100,
+				MaxMemory:    2048,
+				ShmSize:      "1GB",
+				CgroupParams: map[string]string{"cpu.weight": "50", "io.weight": "100"},
+			},
+			Environment: map[string]string{"LOG_LEVEL": "info", "REGION": "eu"},
+		},
+		Jobs: map[string]types.JobSpec{
+			"extract": {Command: "python3"},
+			"train": {
+				Command: "python3",
+				Runtime: "pytorch-cuda",
+				Network: "none",
+				Resources: types.JobResources{
+					MaxMemory:    8192,
+					GPUCount:     1,
+					CgroupParams: map[string]string{"cpu.weight": "200"},
+				},
+				Environment: map[string]string{"LOG_LEVEL": "debug"},
+			},
+		},
+	}
+	ApplyDefaults(wf)
+
+	extract := wf.Jobs["extract"]
+	if extract.Runtime != "python-3.11-ml" || extract.Network != "bridge" {
+		t.Errorf("extract runtime, network = %q, %q", extract.Runtime, extract.Network)
+	}
+	if extract.Resources.MaxCPU != 100 || extract.Resources.MaxMemory != 2048 || extract.Resources.ShmSize != "1GB" {
+		t.Errorf("extract resources = %+v", extract.Resources)
+	}
+	if !maps.Equal(extract.Environment, wf.Defaults.Environment) {
+		t.Errorf("extract environment = %v", extract.Environment)
+	}
+
+	train := wf.Jobs["train"]
+	if train.Runtime != "pytorch-cuda" || train.Network != "none" {
+		t.Errorf("train runtime, network = %q, %q", train.Runtime, train.Network)
+	}
+	if train.Resources.MaxCPU != 100 || train.Resources.MaxMemory != 8192 || train.Resources.GPUCount != 1 {
+		t.Errorf("train resources = %+v", train.Resources)
+	}
+	if want := map[string]string{"cpu.weight": "200", "io.weight": "100"}; !maps.Equal(train.Resources.CgroupParams, want) {
+		t.Errorf("train cgroup params = %v, want %v", train.Resources.CgroupParams, want)
+	}
+	if want := map[string]string{"LOG_LEVEL": "debug", "REGION": "eu"}; !maps.Equal(train.Environment, want) {
+		t.Errorf("train environment = %v, want %v", train.Environment, want)
+	}
+
+	// The defaults are copied, not shared
+	extract.Environment["REGION"] = "us"
+	if wf.Defaults.Environment["REGION"] != "eu" || wf.Jobs["train"].Environment["REGION"] != "eu" {
+		t.Error("jobs share their environment with the defaults")
+	}
+}
+
+func TestApplyDefaultsWithoutDefaults(t *testing.T) {
+	wf := &types.WorkflowYAML{Jobs: map[string]types.JobSpec{"a": {Command: "ls"}}}
+	ApplyDefaults(wf)
+	if a := wf.Jobs["a"]; a.Runtime != "" || a.Environment != nil {
+		t.Errorf("job = %+v", a)
+	}
+}
@@ -117,3 +117,27 @@ func (e *SimpleExpressionEvaluator) evaluateSimpleComparison(expr string) bool {
 
 	return string(currentStatus) == expectedStatus
 }
+
+// ExpressionJobNames returns the jobs a dependency expression refers to, in
+// order of first appearance
+func ExpressionJobNames(expr string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, disjunct := range strings.Split(expr, " OR ") {
+		for _, term := range strings.Split(disjunct, " AND ") {
+			term = strings.Trim(strings.TrimSpace(term), "()")
+			var name string
+			if i := strings.Index(term, " IN "); i >= 0 {
+				name = term[:i]
+			} else if i := strings.Index(term, "="); i >= 0 {
+				name = term[:i]
+			}
+			name = strings.Trim(strings.TrimSpace(name), "()")
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a compiled JSONPath expression. The supported subset is the
+// root $, child access by .name or ['name'], array indexes [N] (negative
+// from the end) and the wildcards .* and [*].
+type JSONPath struct {
+	expr     string
+	segments []pathSegment
+}
+
+// pathSegment selects the children of a node: a key, an index or all of
+// them
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// CompileJSONPath parses expr
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+	p := &JSONPath{expr: expr}
+	rest := expr[1:]
+	for rest != "" {
+		var seg pathSegment
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return nil, fmt.Errorf("JSONPath %q: recursive descent (..) is not supported", expr)
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q: empty name after '.'", expr)
+			}
+			seg = pathSegment{key: name, wildcard: name == "*"}
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q: missing ']'", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "*":
+				seg = pathSegment{wildcard: true}
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				seg = pathSegment{key: inner[1 : len(inner)-1]}
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("JSONPath %q: invalid selector [%s]", expr, inner)
+				}
+				seg = pathSegment{index: index, isIndex: true}
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %q: unexpected %q", expr, rest[:1])
+		}
+		p.segments = append(p.segments, seg)
+	}
+	return p, nil
+}
+
+// String returns the expression p was compiled from
+func (p *JSONPath) String() string {
+	return p.expr
+}
+
+// Select returns the nodes of doc, a decoded JSON document, that p matches
+func (p *JSONPath) Select(doc any) []any {
+	nodes := []any{doc}
+	for _, seg := range p.segments {
+		var next []any
+		for _, node := range nodes {
+			next = append(next, seg.children(node)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (seg pathSegment) children(node any) []any {
+	switch v := node.(type) {
+	case map[string]any:
+		if seg.wildcard {
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			children := make([]any, 0, len(keys))
+			for _, key := range keys {
+				children = append(children, v[key])
+			}
+			return children
+		}
+		if child, exists := v[seg.key]; exists && !seg.isIndex {
+			return []any{child}
+		}
+	case []any:
+		if seg.wildcard {
+			return v
+		}
+		if seg.isIndex {
+			index := seg.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index >= 0 && index < len(v) {
+				return []any{v[index]}
+			}
+		}
+	}
+	return nil
+}
@@ -1,28 +1,26 @@
 package workflow
 
 import (
-	"fmt"
 	"sync"
-	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 )
 
-// WorkflowManager manages workflows without requiring store changes
+// WorkflowManager manages workflows without requiring store changes.
+// Workflow state lives in the dependency resolver, which shards it by workflow
+// ID and guards each workflow with its own lock, so orchestrating thousands of
+// concurrent workflows does not serialize on a manager-wide mutex.
 type WorkflowManager struct {
-	mu              sync.RWMutex
-	workflows       map[int]*WorkflowState
-	jobToWorkflow   map[string]int
-	workflowCounter int
-	resolver        *DependencyResolver
+	resolver *DependencyResolver
+	// Workflow ID -> chan struct{}, signaled when a job of the workflow
+	// changes status
+	watchers sync.Map
 }
 
 // NewWorkflowManager creates a new workflow manager
 func NewWorkflowManager() *WorkflowManager {
 	return &WorkflowManager{
-		workflows:     make(map[int]*WorkflowState),
-		jobToWorkflow: make(map[string]int),
-		resolver:      NewDependencyResolver(),
+		resolver: NewDependencyResolver(),
 	}
 }
 
@@ -37,64 +35,58 @@ func (wm *WorkflowManager) CreateWorkflow(workflow string, jobs map[string]*JobD
 // CreateWorkflowWithYaml creates a new workflow with YAML content for client access.
 // This is the preferred method for workflows that need to store original YAML content.
 func (wm *WorkflowManager) CreateWorkflowWithYaml(workflow string, yamlContent string, jobs map[string]*JobDependency, order []string) (int, error) {
-	wm.mu.Lock()
-	defer wm.mu.Unlock()
-
-	wm.workflowCounter++
-	workflowID := wm.workflowCounter
-
-	workflowState := &WorkflowState{
-		ID:          workflowID,
-		Workflow:    workflow,
-		YamlContent: yamlContent,
-		Jobs:        jobs,
-		JobOrder:    order,
-		Status:      WorkflowPending,
-		CreatedAt:   time.Now(),
-		TotalJobs:   len(jobs),
-	}
+	return wm.resolver.CreateWorkflowWithYaml(workflow, yamlContent, jobs, order)
+}
 
-	wm.workflows[workflowID] = workflowState
+// RestoreWorkflow stores the record of an ended workflow under a new workflow
+// ID, without starting any of its jobs. Backups are imported this way.
+func (wm *WorkflowManager) RestoreWorkflow(state *WorkflowState) (int, error) {
+	return wm.resolver.RestoreWorkflow(state)
+}
 
-	// Map jobs to workflow
-	for jobID := range jobs {
-		wm.jobToWorkflow[jobID] = workflowID
-	}
+// ResumeWorkflow stores a workflow saved before the joblet restarted under a
+// new workflow ID, so orchestration can carry on where it stopped.
+func (wm *WorkflowManager) ResumeWorkflow(state *WorkflowState) int {
+	return wm.resolver.ResumeWorkflow(state)
+}
 
-	// Create in resolver
-	_, err := wm.resolver.CreateWorkflowWithYaml(workflow, yamlContent, jobs, order)
-	return workflowID, err
+// SetWorkflowSignature stores the client signature of a workflow's YAML, so
+// its status can prove the workflow was not modified after submission.
+func (wm *WorkflowManager) SetWorkflowSignature(workflowID int, signature *domain.JobSignature) error {
+	return wm.resolver.SetWorkflowSignature(workflowID, signature)
 }
 
 // OnJobStateChange handles job state changes and updates the corresponding workflow.
 // This method is called by the job execution system whenever a job status changes.
 // It automatically propagates the job status to the dependency resolver and updates
 // the workflow's overall status based on completion of its constituent jobs.
-func (wm *WorkflowManager) OnJobStateChange(jobID string, newStatus domain.JobStatus) {
-	wm.resolver.OnJobStateChange(jobID, newStatus)
-
-	wm.mu.Lock()
-	defer wm.mu.Unlock()
-
-	workflowID, exists := wm.jobToWorkflow[jobID]
-	if !exists {
-		return
+// Returns whether the job's status changed, in which case the watcher of its
+// workflow is signaled.
+func (wm *WorkflowManager) OnJobStateChange(jobID string, newStatus domain.JobStatus) bool {
+	workflowID, changed := wm.resolver.OnJobStateChange(jobID, newStatus)
+	if changed {
+		wm.notify(workflowID)
 	}
+	return changed
+}
 
-	if workflow, exists := wm.workflows[workflowID]; exists {
-		// Update job status in workflow
-		if job, exists := workflow.Jobs[jobID]; exists {
-			job.Status = newStatus
-		}
+// Watch returns a channel receiving a value whenever a job of the workflow
+// changes status or is canceled, so the workflow's orchestration evaluates
+// its ready jobs at once instead of polling for them. Signals coalesce: a
+// watcher busy starting jobs gets one signal for all the changes meanwhile.
+// A workflow has one watcher at a time; stop ends the watch.
+func (wm *WorkflowManager) Watch(workflowID int) (changes <-chan struct{}, stop func()) {
+	ch := make(chan struct{}, 1)
+	wm.watchers.Store(workflowID, ch)
+	return ch, func() { wm.watchers.CompareAndDelete(workflowID, ch) }
+}
 
-		// Update workflow state
-		if updatedWF, err := wm.resolver.GetWorkflowStatus(workflowID); err == nil {
-			// Copy updated state
-			workflow.Status = updatedWF.Status
-			workflow.CompletedJobs = updatedWF.CompletedJobs
-			workflow.FailedJobs = updatedWF.FailedJobs
-			workflow.StartedAt = updatedWF.StartedAt
-			workflow.CompletedAt = updatedWF.CompletedAt
+// notify signals the watcher of a workflow, if any, without blocking
+func (wm *WorkflowManager) notify(workflowID int) {
+	if value, watched := wm.watchers.Load(workflowID); watched {
+		select {
+		case value.(chan struct{}) <- struct{}{}:
+		default: // A signal is already pending
 		}
 	}
 }
@@ -112,7 +104,6 @@ func (wm *WorkflowManager) OnJobStateChange(jobID string, newStatus domain.JobSt
 // 2. Updates the JobDependency.JobID field from job name to actual job ID
 // 3. Remaps workflow.Jobs dictionary from jobName key to actualJobID key
 // 4. Updates jobToWorkflow mapping to use actual job ID
-// 5. Synchronizes changes with the dependency resolver
 //
 // PARAMETERS:
 // - jobName: Original job name from workflow YAML (e.g., "setup-data", "process-data")
@@ -122,44 +113,10 @@ func (wm *WorkflowManager) OnJobStateChange(jobID string, newStatus domain.JobSt
 // - error: If job name not found in workflow or update fails
 //
 // THREAD SAFETY:
-// - Uses write lock to ensure atomic updates across all data structures
+// - Locks only the owning workflow while the job entry is moved
 // - Safe for concurrent access with other workflow manager operations
 func (wm *WorkflowManager) UpdateJobID(jobName string, actualJobID string) error {
-	wm.mu.Lock()
-	defer wm.mu.Unlock()
-
-	// Find the workflow that contains this job name
-	workflowID, exists := wm.jobToWorkflow[jobName]
-	if !exists {
-		return fmt.Errorf("job name %s not found in any workflow", jobName)
-	}
-
-	workflow, exists := wm.workflows[workflowID]
-	if !exists {
-		return fmt.Errorf("workflow %d not found", workflowID)
-	}
-
-	// Find the job dependency entry
-	jobDep, exists := workflow.Jobs[jobName]
-	if !exists {
-		return fmt.Errorf("job %s not found in workflow %d", jobName, workflowID)
-	}
-
-	// Update the JobID field to the actual job ID
-	jobDep.JobID = actualJobID
-
-	// Update workflow.Jobs map: move from jobName key to actualJobID key
-	delete(workflow.Jobs, jobName)
-	workflow.Jobs[actualJobID] = jobDep
-
-	// Update the jobToWorkflow mapping
-	delete(wm.jobToWorkflow, jobName)
-	wm.jobToWorkflow[actualJobID] = workflowID
-
-	// Update resolver mappings as well
-	wm.resolver.UpdateJobID(jobName, actualJobID)
-
-	return nil
+	return wm.resolver.renameJob(jobName, actualJobID)
 }
 
 // GetReadyJobs returns a list of job IDs that are ready to execute for the given workflow.
@@ -173,52 +130,21 @@ func (wm *WorkflowManager) GetReadyJobs(workflowID int) []string {
 // Returns error if the workflow is not found. The returned WorkflowState is a copy to
 // prevent race conditions when accessing workflow data from multiple goroutines.
 func (wm *WorkflowManager) GetWorkflowStatus(workflowID int) (*WorkflowState, error) {
-	wm.mu.RLock()
-	defer wm.mu.RUnlock()
-
-	workflow, exists := wm.workflows[workflowID]
-	if !exists {
-		return nil, fmt.Errorf("workflow %d not found", workflowID)
-	}
-
-	// Check for nil before dereferencing
-	if workflow == nil {
-		return nil, fmt.Errorf("workflow %d is nil", workflowID)
-	}
-
-	// Create a copy to avoid race conditions
-	copy := *workflow
-	return &copy, nil
+	return wm.resolver.GetWorkflowStatus(workflowID)
 }
 
 // ListWorkflows returns a list of all workflows managed by this WorkflowManager.
 // Each returned WorkflowState is a copy to prevent external modifications to internal state.
 // The list includes workflows in all states (pending, running, completed, failed, canceled).
 func (wm *WorkflowManager) ListWorkflows() []*WorkflowState {
-	wm.mu.RLock()
-	defer wm.mu.RUnlock()
-
-	var result []*WorkflowState
-	for _, wf := range wm.workflows {
-		if wf == nil {
-			continue
-		}
-		copy := *wf
-		result = append(result, &copy)
-	}
-
-	return result
+	return wm.resolver.ListWorkflows()
 }
 
 // GetJobWorkflow returns the workflow ID that contains the given job.
 // Returns the workflow ID and true if the job is part of a workflow,
 // or 0 and false if the job is not associated with any workflow.
 func (wm *WorkflowManager) GetJobWorkflow(jobID string) (int, bool) {
-	wm.mu.RLock()
-	defer wm.mu.RUnlock()
-
-	workflowID, exists := wm.jobToWorkflow[jobID]
-	return workflowID, exists
+	return wm.resolver.GetJobWorkflow(jobID)
 }
 
 // IsJobPartOfWorkflow checks if the given job ID belongs to any workflow.
@@ -227,3 +153,25 @@ func (wm *WorkflowManager) IsJobPartOfWorkflow(jobID string) bool {
 	_, exists := wm.GetJobWorkflow(jobID)
 	return exists
 }
+
+// CancelJobs cancels a job of a workflow and, with cascade, every job
+// downstream of it. Pending jobs are canceled at once; the running and
+// scheduled ones are returned for the caller to stop.
+func (wm *WorkflowManager) CancelJobs(workflowID int, jobName string, cascade bool) ([]CanceledJob, error) {
+	canceled, err := wm.resolver.CancelJobs(workflowID, jobName, cascade)
+	if err == nil {
+		wm.notify(workflowID)
+	}
+	return canceled, err
+}
+
+// CancelWorkflow cancels every job of a workflow. Pending jobs are canceled
+// at once; the running and scheduled ones are returned for the caller to
+// stop.
+func (wm *WorkflowManager) CancelWorkflow(workflowID int) ([]CanceledJob, error) {
+	canceled, err := wm.resolver.CancelWorkflow(workflowID)
+	if err == nil {
+		wm.notify(workflowID)
+	}
+	return canceled, err
+}
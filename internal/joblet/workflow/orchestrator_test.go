@@ -13,20 +13,16 @@ func TestNewWorkflowManager(t *testing.T) {
 		t.Fatal("NewWorkflowManager() returned nil")
 	}
 
-	if wm.workflows == nil {
-		t.Error("workflows map not initialized")
-	}
-
-	if wm.jobToWorkflow == nil {
-		t.Error("jobToWorkflow map not initialized")
+	if wm.resolver == nil {
+		t.Fatal("resolver not initialized")
 	}
 
-	if wm.resolver == nil {
-		t.Error("resolver not initialized")
+	if n := wm.resolver.workflows.len(); n != 0 {
+		t.Errorf("workflows.len() = %d, want 0", n)
 	}
 
-	if wm.workflowCounter != 0 {
-		t.Errorf("workflowCounter = %d, want 0", wm.workflowCounter)
+	if counter := wm.resolver.workflowCounter.Load(); counter != 0 {
+		t.Errorf("workflowCounter = %d, want 0", counter)
 	}
 }
 
@@ -67,12 +63,9 @@ func TestWorkflowManager_CreateWorkflow(t *testing.T) {
 	}
 
 	// Check workflow was stored
-	wm.mu.RLock()
-	workflow, exists := wm.workflows[workflowID]
-	wm.mu.RUnlock()
-
-	if !exists {
-		t.Fatal("Workflow not found in workflows map")
+	workflow, err := wm.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatalf("Workflow not found: %v", err)
 	}
 
 	if workflow.ID != workflowID {
@@ -108,12 +101,9 @@ func TestWorkflowManager_CreateWorkflowWithYaml(t *testing.T) {
 	}
 
 	// Check YAML content was stored
-	wm.mu.RLock()
-	workflow, exists := wm.workflows[workflowID]
-	wm.mu.RUnlock()
-
-	if !exists {
-		t.Fatal("Workflow not found")
+	workflow, err := wm.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatalf("Workflow not found: %v", err)
 	}
 
 	if workflow.YamlContent != yamlContent {
@@ -145,9 +135,7 @@ func TestWorkflowManager_UpdateJobID(t *testing.T) {
 	}
 
 	// Check job-to-workflow mapping
-	wm.mu.RLock()
-	mappedWorkflowID, exists := wm.jobToWorkflow["actual-job-123"]
-	wm.mu.RUnlock()
+	mappedWorkflowID, exists := wm.GetJobWorkflow("actual-job-123")
 
 	if !exists {
 		t.Fatal("Job ID not found in jobToWorkflow mapping")
@@ -265,3 +253,152 @@ func TestWorkflowManager_ListWorkflows(t *testing.T) {
 		t.Errorf("len(ListWorkflows()) = %d, want 2", len(workflows))
 	}
 }
+
+func TestWorkflowManager_RestoreWorkflow(t *testing.T) {
+	wm := NewWorkflowManager()
+	if _, err := wm.CreateWorkflow("live.yaml", map[string]*JobDependency{
+		"build": {JobID: "build", InternalName: "build", Status: domain.StatusPending},
+	}, []string{"build"}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := &WorkflowState{
+		ID:       42,
+		Workflow: "etl.yaml",
+		Jobs: map[string]*JobDependency{
+			"uuid-extract": {JobID: "uuid-extract", InternalName: "extract", Status: domain.StatusFailed},
+			"build":        {JobID: "build", InternalName: "build", Status: domain.StatusCanceled, Impossible: true},
+		},
+		JobOrder:   []string{"extract", "build"},
+		Status:     WorkflowFailed,
+		TotalJobs:  2,
+		FailedJobs: 1,
+	}
+	workflowID, err := wm.RestoreWorkflow(state)
+	if err != nil {
+		t.Fatalf("RestoreWorkflow() error = %v", err)
+	}
+	if workflowID != 2 {
+		t.Errorf("RestoreWorkflow() = %d, want a new ID 2", workflowID)
+	}
+
+	restored, err := wm.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Status != WorkflowFailed || restored.Workflow != "etl.yaml" || len(restored.Jobs) != 2 {
+		t.Errorf("GetWorkflowStatus() = %+v", restored)
+	}
+	if id, ok := wm.GetJobWorkflow("uuid-extract"); !ok || id != workflowID {
+		t.Errorf("GetJobWorkflow(uuid-extract) = %d, %v", id, ok)
+	}
+	// The job that never started keeps pointing at the live workflow
+	if id, _ := wm.GetJobWorkflow("build"); id != 1 {
+		t.Errorf("GetJobWorkflow(build) = %d, want the live workflow", id)
+	}
+
+	state.Status = WorkflowRunning
+	if _, err := wm.RestoreWorkflow(state); err == nil {
+		t.Error("RestoreWorkflow() of a running workflow succeeded")
+	}
+}
+
+func TestWorkflowManager_ResumeWorkflow(t *testing.T) {
+	wm := NewWorkflowManager()
+	state := &WorkflowState{
+		ID:       7,
+		Workflow: "etl.yaml",
+		Jobs: map[string]*JobDependency{
+			"uuid-extract": {JobID: "uuid-extract", InternalName: "extract", Status: domain.StatusCompleted},
+			"load": {JobID: "load", InternalName: "load", Status: domain.StatusPending, Requirements: []Requirement{
+				{Type: RequirementSimple, JobID: "extract", Status: "COMPLETED"},
+			}},
+		},
+		JobOrder:      []string{"extract", "load"},
+		Status:        WorkflowRunning,
+		TotalJobs:     2,
+		CompletedJobs: 1,
+	}
+	workflowID := wm.ResumeWorkflow(state)
+
+	// The job that never started can start and take its job ID
+	if ready := wm.GetReadyJobs(workflowID); len(ready) != 1 || ready[0] != "load" {
+		t.Fatalf("GetReadyJobs() = %v, want load", ready)
+	}
+	if err := wm.UpdateJobID("load", "uuid-load"); err != nil {
+		t.Fatalf("UpdateJobID() error = %v", err)
+	}
+	wm.OnJobStateChange("uuid-load", domain.StatusCompleted)
+
+	resumed, err := wm.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Status != WorkflowCompleted || resumed.CompletedJobs != 2 {
+		t.Errorf("GetWorkflowStatus() = %s with %d completed, want COMPLETED with 2", resumed.Status, resumed.CompletedJobs)
+	}
+	// The saved state is left alone
+	if state.Jobs["load"].Status != domain.StatusPending {
+		t.Errorf("saved load = %s, want it untouched", state.Jobs["load"].Status)
+	}
+}
+
+func TestWorkflowManager_Watch(t *testing.T) {
+	wm := NewWorkflowManager()
+
+	jobs := map[string]*JobDependency{
+		"build": {JobID: "build", InternalName: "build", Status: domain.StatusPending},
+		"test": {JobID: "test", InternalName: "test", Status: domain.StatusPending,
+			Requirements: []Requirement{{Type: RequirementSimple, JobID: "build", Status: "COMPLETED"}}},
+	}
+	workflowID, err := wm.CreateWorkflow("ci", jobs, []string{"build", "test"})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+	if err := wm.UpdateJobID("build", "uuid-build"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, stop := wm.Watch(workflowID)
+	signaled := func() bool {
+		select {
+		case <-changes:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// Changes coalesce into one pending signal
+	if !wm.OnJobStateChange("uuid-build", domain.StatusRunning) {
+		t.Error("OnJobStateChange(RUNNING) reported no change")
+	}
+	wm.OnJobStateChange("uuid-build", domain.StatusCompleted)
+	if !signaled() {
+		t.Fatal("no signal after the job changed status")
+	}
+	if signaled() {
+		t.Error("second signal pending, want changes coalesced")
+	}
+	if ready := wm.GetReadyJobs(workflowID); len(ready) != 1 || ready[0] != "test" {
+		t.Errorf("GetReadyJobs() = %v, want [test]", ready)
+	}
+
+	// The same status again is no change
+	if wm.OnJobStateChange("uuid-build", domain.StatusCompleted) || signaled() {
+		t.Error("unchanged status signaled")
+	}
+
+	if _, err := wm.CancelJobs(workflowID, "test", false); err != nil {
+		t.Fatal(err)
+	}
+	if !signaled() {
+		t.Error("no signal after a job was canceled")
+	}
+
+	stop()
+	wm.OnJobStateChange("uuid-build", domain.StatusFailed)
+	if signaled() {
+		t.Error("signal after the watch stopped")
+	}
+}
@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// OutputsDir is the job directory outputs files must be written to. It is
+// kept on the host until the job's document has been read.
+const OutputsDir = "/work"
+
+// MaxOutputsFileBytes caps the size of an outputs document
+const MaxOutputsFileBytes = 1024 * 1024
+
+var (
+	// outputReference matches a ${jobs.<job>.<VAR>} reference
+	outputReference = regexp.MustCompile(`\$\{jobs\.([^.{}]+)\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+	outputVarName   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// OutputRef is a reference to a variable another job publishes
+type OutputRef struct {
+	Job string
+	Var string
+}
+
+// JobOutputRefs returns the output references in a job's environment and
+// args
+func JobOutputRefs(spec types.JobSpec) []OutputRef {
+	var refs []OutputRef
+	add := func(s string) {
+		for _, m := range outputReference.FindAllStringSubmatch(s, -1) {
+			refs = append(refs, OutputRef{Job: m[1], Var: m[2]})
+		}
+	}
+	for _, arg := range spec.Args {
+		add(arg)
+	}
+	for _, value := range spec.Environment {
+		add(value)
+	}
+	return refs
+}
+
+// ValidateOutputs checks the outputs the jobs of wf declare, and that every
+// reference names a declared variable of a job the referencing job requires,
+// directly or not
+func ValidateOutputs(wf types.WorkflowYAML) error {
+	for _, name := range sortedJobNames(wf) {
+		outputs := wf.Jobs[name].Outputs
+		if outputs == nil {
+			continue
+		}
+		if !strings.HasPrefix(path.Clean(outputs.File), OutputsDir+"/") {
+			return fmt.Errorf("job '%s': outputs file %q must be under %s", name, outputs.File, OutputsDir)
+		}
+		if len(outputs.Vars) == 0 {
+			return fmt.Errorf("job '%s': outputs declare no vars", name)
+		}
+		for varName, expr := range outputs.Vars {
+			if !outputVarName.MatchString(varName) {
+				return fmt.Errorf("job '%s': invalid output var name %q", name, varName)
+			}
+			if _, err := CompileJSONPath(expr); err != nil {
+				return fmt.Errorf("job '%s': output var %s: %w", name, varName, err)
+			}
+		}
+	}
+
+	for key, value := range wf.Environment {
+		if outputReference.MatchString(value) {
+			return fmt.Errorf("workflow environment %s cannot reference job outputs, set it in the jobs that require the producing job", key)
+		}
+	}
+	for _, name := range sortedJobNames(wf) {
+		var ancestors map[string]bool
+		for _, ref := range JobOutputRefs(wf.Jobs[name]) {
+			producer, exists := wf.Jobs[ref.Job]
+			if !exists {
+				return fmt.Errorf("job '%s' references outputs of non-existent job '%s'", name, ref.Job)
+			}
+			if producer.Outputs == nil || producer.Outputs.Vars[ref.Var] == "" {
+				return fmt.Errorf("job '%s' references output %s, which job '%s' does not declare", name, ref.Var, ref.Job)
+			}
+			if ancestors == nil {
+				ancestors = requiredJobsOf(wf, name)
+			}
+			if !ancestors[ref.Job] {
+				return fmt.Errorf("job '%s' references outputs of job '%s' but does not require it", name, ref.Job)
+			}
+		}
+	}
+	return nil
+}
+
+// ExtractOutputs evaluates the declared vars against an outputs document.
+// A string is used as is, other values as JSON, and several matches are
+// joined with spaces, so "$.modules[*].name" gives "api web". A var that
+// matches nothing is an error.
+func ExtractOutputs(outputs *types.JobOutputs, doc []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var root any
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", outputs.File, err)
+	}
+
+	vars := make(map[string]string, len(outputs.Vars))
+	for varName, expr := range outputs.Vars {
+		p, err := CompileJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		matches := p.Select(root)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("output var %s: %s matched nothing in %s", varName, expr, outputs.File)
+		}
+		values := make([]string, 0, len(matches))
+		for _, match := range matches {
+			if s, ok := match.(string); ok {
+				values = append(values, s)
+				continue
+			}
+			data, err := json.Marshal(match)
+			if err != nil {
+				return nil, fmt.Errorf("output var %s: %w", varName, err)
+			}
+			values = append(values, string(data))
+		}
+		vars[varName] = strings.Join(values, " ")
+	}
+	return vars, nil
+}
+
+// ExpandOutputs replaces the output references in s with the values in
+// outputs, keyed by job name and var
+func ExpandOutputs(s string, outputs map[string]map[string]string) (string, error) {
+	var missing error
+	expanded := outputReference.ReplaceAllStringFunc(s, func(ref string) string {
+		m := outputReference.FindStringSubmatch(ref)
+		value, exists := outputs[m[1]][m[2]]
+		if !exists && missing == nil {
+			missing = fmt.Errorf("output %s of job '%s' is not available", m[2], m[1])
+		}
+		return value
+	})
+	return expanded, missing
+}
+
+// requiredJobsOf returns every job name requires, directly or through other
+// jobs
+func requiredJobsOf(wf types.WorkflowYAML, name string) map[string]bool {
+	seen := make(map[string]bool)
+	pending := RequiredJobNames(wf.Jobs[name].Requires)
+	for len(pending) > 0 {
+		dep := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+		pending = append(pending, RequiredJobNames(wf.Jobs[dep].Requires)...)
+	}
+	return seen
+}
+
+func sortedJobNames(wf types.WorkflowYAML) []string {
+	names := make([]string, 0, len(wf.Jobs))
+	for name := range wf.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
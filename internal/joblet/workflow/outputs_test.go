@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]any{
+		"commit": "abc123",
+		"modules": []any{
+			map[string]any{"name": "api", "changed": true},
+			map[string]any{"name": "web", "changed": false},
+		},
+		"build.info": map[string]any{"arch": "amd64"},
+	}
+
+	tests := []struct {
+		expr string
+		want []any
+	}{
+		{"$.commit", []any{"abc123"}},
+		{"$.modules[*].name", []any{"api", "web"}},
+		{"$.modules[-1].name", []any{"web"}},
+		{"$['build.info'].arch", []any{"amd64"}},
+		{"$.modules[5].name", nil},
+		{"$.missing", nil},
+	}
+	for _, tt := range tests {
+		p, err := CompileJSONPath(tt.expr)
+		if err != nil {
+			t.Errorf("CompileJSONPath(%q) error = %v", tt.expr, err)
+			continue
+		}
+		got := p.Select(doc)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s selected %v, want %v", tt.expr, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s selected %v, want %v", tt.expr, got, tt.want)
+			}
+		}
+	}
+
+	for _, expr := range []string{"commit", "$..name", "$.modules[x]", "$.modules[0"} {
+		if _, err := CompileJSONPath(expr); err == nil {
+			t.Errorf("CompileJSONPath(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestExtractOutputs(t *testing.T) {
+	outputs := &types.JobOutputs{
+		File: "/work/outputs.json",
+		Vars: map[string]string{
+			"MODULES": "$.modules[*].name",
+			"COUNT":   "$.count",
+			"CONFIG":  "$.config",
+		},
+	}
+	vars, err := ExtractOutputs(outputs, []byte(`{"modules": [{"name": "api"}, {"name": "web"}], "count": 2, "config": {"debug": true}}`))
+	if err != nil {
+		t.Fatalf("ExtractOutputs() error = %v", err)
+	}
+	want := map[string]string{"MODULES": "api web", "COUNT": "2", "CONFIG": `{"debug":true}`}
+	for key, value := range want {
+		if vars[key] != value {
+			t.Errorf("%s = %q, want %q", key, vars[key], value)
+		}
+	}
+
+	if _, err := ExtractOutputs(outputs, []byte(`{"modules": []}`)); err == nil || !strings.Contains(err.Error(), "matched nothing") {
+		t.Errorf("ExtractOutputs(no match) error = %v", err)
+	}
+	if _, err := ExtractOutputs(outputs, []byte(`not json`)); err == nil {
+		t.Error("ExtractOutputs(invalid JSON) succeeded, want an error")
+	}
+}
+
+func TestExpandOutputs(t *testing.T) {
+	outputs := map[string]map[string]string{"detect": {"MODULES": "api web"}}
+	got, err := ExpandOutputs("make ${jobs.detect.MODULES} ${HOME}", outputs)
+	if err != nil || got != "make api web ${HOME}" {
+		t.Errorf("ExpandOutputs() = %q, %v", got, err)
+	}
+	if _, err := ExpandOutputs("${jobs.detect.MISSING}", outputs); err == nil {
+		t.Error("ExpandOutputs(unknown var) succeeded, want an error")
+	}
+}
+
+func TestValidateOutputs(t *testing.T) {
+	workflow := func(mutate func(wf *types.WorkflowYAML)) types.WorkflowYAML {
+		wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+			"detect": {
+				Command: "python3",
+				Outputs: &types.JobOutputs{File: "/work/outputs.json", Vars: map[string]string{"MODULES": "$.modules[*]"}},
+			},
+			"build": {
+				Command:  "make",
+				Args:     []string{"${jobs.detect.MODULES}"},
+				Requires: []map[string]string{{"detect": "COMPLETED"}},
+			},
+			"deploy": {
+				Command:     "deploy.sh",
+				Environment: map[string]string{"MODULES": "${jobs.detect.MODULES}"},
+				Requires:    []map[string]string{{"build": "COMPLETED"}},
+			},
+		}}
+		if mutate != nil {
+			mutate(&wf)
+		}
+		return wf
+	}
+
+	if err := ValidateOutputs(workflow(nil)); err != nil {
+		t.Fatalf("ValidateOutputs() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(wf *types.WorkflowYAML)
+		wantErr string
+	}{
+		{"file outside /work", func(wf *types.WorkflowYAML) {
+			wf.Jobs["detect"].Outputs.File = "/tmp/outputs.json"
+		}, "must be under /work"},
+		{"invalid JSONPath", func(wf *types.WorkflowYAML) {
+			wf.Jobs["detect"].Outputs.Vars["MODULES"] = "modules"
+		}, "must start with $"},
+		{"undeclared var", func(wf *types.WorkflowYAML) {
+			build := wf.Jobs["build"]
+			build.Args = []string{"${jobs.detect.COMMIT}"}
+			wf.Jobs["build"] = build
+		}, "does not declare"},
+		{"producer not required", func(wf *types.WorkflowYAML) {
+			build := wf.Jobs["build"]
+			build.Requires = nil
+			wf.Jobs["build"] = build
+		}, "does not require it"},
+		{"workflow environment", func(wf *types.WorkflowYAML) {
+			wf.Environment = map[string]string{"MODULES": "${jobs.detect.MODULES}"}
+		}, "workflow environment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputs(workflow(tt.mutate))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateOutputs() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
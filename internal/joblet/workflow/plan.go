@@ -0,0 +1,134 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// Plan is a static analysis of a workflow, computed before it runs
+type Plan struct {
+	Stages []Stage
+	// CriticalPath is the chain of jobs with the longest expected duration,
+	// first job first
+	CriticalPath []string
+	// CriticalPathDuration sums the estimates and schedule delays along the
+	// critical path
+	CriticalPathDuration time.Duration
+	// Unestimated lists the jobs without an estimate, counted as zero
+	Unestimated []string
+}
+
+// Stage groups the jobs whose longest chain of requirements has the same
+// length. Jobs of a stage may run at the same time, so its totals are the
+// most the stage can ask of a node at once.
+type Stage struct {
+	Number        int // From 1
+	Jobs          []string
+	MaxCPU        int // Percent, 100 = one core
+	MaxMemoryMB   int
+	GPUCount      int
+	UnlimitedJobs int // Jobs without a CPU or memory limit
+}
+
+// Analyze computes the stages and critical path of a workflow. It fails on
+// requirements on unknown jobs, cycles and invalid timing fields.
+func Analyze(wf types.WorkflowYAML) (*Plan, error) {
+	names := make([]string, 0, len(wf.Jobs))
+	for name := range wf.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	requires := make(map[string][]string, len(wf.Jobs))
+	durations := make(map[string]time.Duration, len(wf.Jobs))
+	plan := &Plan{}
+	now := time.Now()
+	for _, name := range names {
+		spec := wf.Jobs[name]
+		for _, dep := range RequiredJobNames(spec.Requires) {
+			if _, exists := wf.Jobs[dep]; !exists {
+				return nil, fmt.Errorf("job '%s' depends on non-existent job '%s'", name, dep)
+			}
+			requires[name] = append(requires[name], dep)
+		}
+
+		timing, err := ParseJobTiming(spec, now)
+		if err != nil {
+			return nil, fmt.Errorf("job '%s': %w", name, err)
+		}
+		durations[name] = timing.Delay + timing.Estimate
+		if timing.Estimate == 0 {
+			plan.Unestimated = append(plan.Unestimated, name)
+		}
+	}
+
+	// Longest chain of requirements and longest expected finish per job
+	stage := make(map[string]int, len(names))
+	finish := make(map[string]time.Duration, len(names))
+	via := make(map[string]string, len(names)) // Requirement the job's critical path goes through
+	const visiting = -1
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch stage[name] {
+		case visiting:
+			return fmt.Errorf("circular dependency involving job '%s'", name)
+		case 0:
+		default:
+			return nil
+		}
+		stage[name] = visiting
+
+		depth, start := 0, time.Duration(0)
+		for _, dep := range requires[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+			depth = max(depth, stage[dep])
+			if finish[dep] > start || via[name] == "" {
+				start, via[name] = max(start, finish[dep]), dep
+			}
+		}
+		stage[name] = depth + 1
+		finish[name] = start + durations[name]
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Stages with their resource totals
+	for _, name := range names {
+		n := stage[name]
+		for len(plan.Stages) < n {
+			plan.Stages = append(plan.Stages, Stage{Number: len(plan.Stages) + 1})
+		}
+		s := &plan.Stages[n-1]
+		res := wf.Jobs[name].Resources
+		s.Jobs = append(s.Jobs, name)
+		s.MaxCPU += res.MaxCPU
+		s.MaxMemoryMB += res.MaxMemory
+		s.GPUCount += res.GPUCount
+		if res.MaxCPU == 0 || res.MaxMemory == 0 {
+			s.UnlimitedJobs++
+		}
+	}
+
+	// The critical path ends at the job expected to finish last
+	last := ""
+	for _, name := range names {
+		if last == "" || finish[name] > finish[last] {
+			last = name
+		}
+	}
+	for name := last; name != ""; name = via[name] {
+		plan.CriticalPath = append([]string{name}, plan.CriticalPath...)
+	}
+	plan.CriticalPathDuration = finish[last]
+
+	return plan, nil
+}
@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func TestAnalyze(t *testing.T) {
+	wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+		"extract": {Estimate: "10m", Resources: types.JobResources{MaxCPU: 100, MaxMemory: 512}},
+		"models":  {Estimate: "30m", Resources: types.JobResources{MaxCPU: 200, MaxMemory: 2048}},
+		"train": {
+			Estimate:  "1h",
+			Requires:  []map[string]string{{"extract": "COMPLETED"}, {"models": "COMPLETED"}},
+			Resources: types.JobResources{MaxCPU: 400, MaxMemory: 8192, GPUCount: 2},
+		},
+		"lint": {Requires: []map[string]string{{"extract": "COMPLETED"}}},
+		"report": {
+			Schedule: "5m",
+			Estimate: "2m",
+			Requires: []map[string]string{{"expression": "train=COMPLETED OR lint=FAILED"}},
+		},
+	}}
+
+	plan, err := Analyze(wf)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := []Stage{
+		{Number: 1, Jobs: []string{"extract", "models"}, MaxCPU: 300, MaxMemoryMB: 2560},
+		{Number: 2, Jobs: []string{"lint", "train"}, MaxCPU: 400, MaxMemoryMB: 8192, GPUCount: 2, UnlimitedJobs: 1},
+		{Number: 3, Jobs: []string{"report"}, UnlimitedJobs: 1},
+	}
+	if !reflect.DeepEqual(plan.Stages, want) {
+		t.Errorf("Stages = %+v, want %+v", plan.Stages, want)
+	}
+
+	// models (30m) -> train (1h) -> report (5m delay + 2m)
+	if got := strings.Join(plan.CriticalPath, " -> "); got != "models -> train -> report" {
+		t.Errorf("CriticalPath = %s", got)
+	}
+	if plan.CriticalPathDuration != 97*time.Minute {
+		t.Errorf("CriticalPathDuration = %v, want 1h37m", plan.CriticalPathDuration)
+	}
+	if !reflect.DeepEqual(plan.Unestimated, []string{"lint"}) {
+		t.Errorf("Unestimated = %v, want [lint]", plan.Unestimated)
+	}
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	for name, jobs := range map[string]map[string]types.JobSpec{
+		"cycle": {
+			"a": {Requires: []map[string]string{{"b": "COMPLETED"}}},
+			"b": {Requires: []map[string]string{{"a": "COMPLETED"}}},
+		},
+		"unknown job":      {"a": {Requires: []map[string]string{{"missing": "COMPLETED"}}}},
+		"invalid estimate": {"a": {Estimate: "soon"}},
+	} {
+		if _, err := Analyze(types.WorkflowYAML{Jobs: jobs}); err == nil {
+			t.Errorf("%s: Analyze() succeeded, want error", name)
+		}
+	}
+}
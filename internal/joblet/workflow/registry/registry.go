@@ -0,0 +1,335 @@
+// Package registry stores workflows on the server so they can be started by
+// name. Every registration of a name adds a new version; a workflow with a
+// cron schedule is started by the server whenever the schedule is due.
+//
+// Each workflow is a directory under the registry directory, holding
+// workflow.json with the workflow's metadata and v<N>.json per version.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/cron"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// ErrNotFound is returned for unknown workflows and versions
+var ErrNotFound = errors.New("registered workflow not found")
+
+const metaFile = "workflow.json"
+
+// validName keeps names usable as directory names and on the command line
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,62}$`)
+
+// Entry is a registered workflow
+type Entry struct {
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	LatestVersion int       `json:"latest_version"`
+	Schedule      string    `json:"schedule,omitempty"`
+	NextRun       time.Time `json:"next_run,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UpdatedBy     string    `json:"updated_by,omitempty"`
+	Tenant        string    `json:"tenant,omitempty"` // Tenant scheduled runs belong to
+	LastRunUUID   string    `json:"last_run_uuid,omitempty"`
+	LastRunAt     time.Time `json:"last_run_at,omitempty"`
+}
+
+// Version is one registered revision of a workflow
+type Version struct {
+	Version     int                  `json:"version"`
+	YamlContent string               `json:"yaml_content"`
+	Files       []File               `json:"files,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	CreatedBy   string               `json:"created_by,omitempty"`
+	Signature   *domain.JobSignature `json:"signature,omitempty"`
+}
+
+// File is a file uploaded with a version for its jobs' uploads
+type File struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+	Mode    uint32 `json:"mode"`
+}
+
+// Registration is what a client registers under a name
+type Registration struct {
+	Name        string
+	Description string
+	Schedule    string // Cron expression, empty = not scheduled
+	Tenant      string
+	Version     Version
+}
+
+// Registry stores registered workflows in a directory
+type Registry struct {
+	dir         string
+	maxVersions int
+
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// New opens the registry in dir, creating the directory if needed. Only the
+// newest maxVersions versions of a workflow are kept (0 = all).
+func New(dir string, maxVersions int) (*Registry, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create workflow registry directory: %w", err)
+	}
+	r := &Registry{dir: dir, maxVersions: maxVersions, entries: make(map[string]*Entry)}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow registry directory: %w", err)
+	}
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		var entry Entry
+		if err := readJSON(filepath.Join(dir, d.Name(), metaFile), &entry); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue // Registration interrupted before its metadata was written
+			}
+			return nil, fmt.Errorf("failed to load registered workflow %s: %w", d.Name(), err)
+		}
+		r.entries[entry.Name] = &entry
+	}
+	return r, nil
+}
+
+// Register stores reg as the next version of its workflow. The schedule and
+// description of the workflow are replaced by those of reg.
+func (r *Registry) Register(reg Registration, now time.Time) (Entry, error) {
+	if !validName.MatchString(reg.Name) {
+		return Entry{}, fmt.Errorf("invalid workflow name %q: use letters, digits, '.', '_' and '-'", reg.Name)
+	}
+	var nextRun time.Time
+	if reg.Schedule != "" {
+		schedule, err := cron.Parse(reg.Schedule)
+		if err != nil {
+			return Entry{}, err
+		}
+		if nextRun = schedule.Next(now); nextRun.IsZero() {
+			return Entry{}, fmt.Errorf("schedule %q never fires", reg.Schedule)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := Entry{Name: reg.Name}
+	if existing, ok := r.entries[reg.Name]; ok {
+		entry = *existing
+	} else if err := os.MkdirAll(r.path(reg.Name), 0700); err != nil {
+		return Entry{}, fmt.Errorf("failed to create workflow directory: %w", err)
+	}
+
+	version := reg.Version
+	version.Version = entry.LatestVersion + 1
+	version.CreatedAt = now
+	if err := writeJSON(r.versionPath(reg.Name, version.Version), version); err != nil {
+		return Entry{}, err
+	}
+
+	entry.Description = reg.Description
+	entry.LatestVersion = version.Version
+	entry.Schedule = reg.Schedule
+	entry.NextRun = nextRun
+	entry.UpdatedAt = now
+	entry.UpdatedBy = version.CreatedBy
+	entry.Tenant = reg.Tenant
+	if err := r.save(&entry); err != nil {
+		return Entry{}, err
+	}
+
+	r.prune(&entry)
+	return entry, nil
+}
+
+// List returns all registered workflows by name
+func (r *Registry) List() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]Entry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Get returns a registered workflow
+func (r *Registry) Get(name string) (Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[name]
+	if !ok {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// Version loads a version of a workflow, the latest for version 0
+func (r *Registry) Version(name string, version int) (*Version, error) {
+	r.mu.Lock()
+	entry, ok := r.entries[name]
+	if ok && version == 0 {
+		version = entry.LatestVersion
+	}
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	var v Version
+	if err := readJSON(r.versionPath(name, version), &v); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s version %d", ErrNotFound, name, version)
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Remove deletes a workflow with all its versions and returns how many
+// versions there were
+func (r *Registry) Remove(name string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[name]; !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	versions := len(r.versionNumbers(name))
+	if err := os.RemoveAll(r.path(name)); err != nil {
+		return 0, fmt.Errorf("failed to remove workflow %s: %w", name, err)
+	}
+	delete(r.entries, name)
+	return versions, nil
+}
+
+// RecordRun remembers the latest run of a workflow
+func (r *Registry) RecordRun(name, workflowUUID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	updated := *entry
+	updated.LastRunUUID = workflowUUID
+	updated.LastRunAt = at
+	return r.save(&updated)
+}
+
+// Due returns the scheduled workflows whose next run is at or before now, and
+// moves their next run past now. Runs missed while the server was down are
+// started once, not once per missed run.
+func (r *Registry) Due(now time.Time) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []Entry
+	for _, entry := range r.entries {
+		if entry.Schedule == "" || entry.NextRun.IsZero() || entry.NextRun.After(now) {
+			continue
+		}
+		updated := *entry
+		updated.NextRun = time.Time{}
+		if schedule, err := cron.Parse(entry.Schedule); err == nil {
+			updated.NextRun = schedule.Next(now)
+		}
+		if err := r.save(&updated); err != nil {
+			// Not firing is better than firing again on every tick
+			continue
+		}
+		due = append(due, updated)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Name < due[j].Name })
+	return due
+}
+
+// save writes entry's metadata and makes it the current entry. Callers hold mu.
+func (r *Registry) save(entry *Entry) error {
+	if err := writeJSON(filepath.Join(r.path(entry.Name), metaFile), entry); err != nil {
+		return err
+	}
+	r.entries[entry.Name] = entry
+	return nil
+}
+
+// prune removes the versions beyond maxVersions. Callers hold mu.
+func (r *Registry) prune(entry *Entry) {
+	if r.maxVersions <= 0 {
+		return
+	}
+	for _, version := range r.versionNumbers(entry.Name) {
+		if version <= entry.LatestVersion-r.maxVersions {
+			_ = os.Remove(r.versionPath(entry.Name, version))
+		}
+	}
+}
+
+// versionNumbers lists the stored versions of a workflow
+func (r *Registry) versionNumbers(name string) []int {
+	files, _ := os.ReadDir(r.path(name))
+	var versions []int
+	for _, f := range files {
+		number, ok := strings.CutPrefix(strings.TrimSuffix(f.Name(), ".json"), "v")
+		if !ok {
+			continue
+		}
+		if version, err := strconv.Atoi(number); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+func (r *Registry) path(name string) string {
+	return filepath.Join(r.dir, name)
+}
+
+func (r *Registry) versionPath(name string, version int) string {
+	return filepath.Join(r.path(name), fmt.Sprintf("v%d.json", version))
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSON replaces path atomically
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
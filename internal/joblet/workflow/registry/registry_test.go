@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func register(t *testing.T, r *Registry, name, yaml, schedule string, now time.Time) Entry {
+	t.Helper()
+	entry, err := r.Register(Registration{
+		Name:     name,
+		Schedule: schedule,
+		Version:  Version{YamlContent: yaml, CreatedBy: "ci", Files: []File{{Path: "etl.py", Content: []byte("print(1)"), Mode: 0644}}},
+	}, now)
+	if err != nil {
+		t.Fatalf("Register(%s) error = %v", name, err)
+	}
+	return entry
+}
+
+func TestRegistryVersions(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir, 2)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	register(t, r, "nightly-etl", "jobs: {a: {command: v1}}", "", now)
+	register(t, r, "nightly-etl", "jobs: {a: {command: v2}}", "", now)
+	entry := register(t, r, "nightly-etl", "jobs: {a: {command: v3}}", "", now)
+	if entry.LatestVersion != 3 || entry.UpdatedBy != "ci" {
+		t.Fatalf("entry = %+v, want version 3 by ci", entry)
+	}
+
+	latest, err := r.Version("nightly-etl", 0)
+	if err != nil || latest.Version != 3 || latest.YamlContent != "jobs: {a: {command: v3}}" {
+		t.Fatalf("Version(latest) = %+v, %v", latest, err)
+	}
+	if string(latest.Files[0].Content) != "print(1)" {
+		t.Errorf("files = %+v", latest.Files)
+	}
+	if _, err := r.Version("nightly-etl", 2); err != nil {
+		t.Errorf("Version(2) error = %v", err)
+	}
+	// Only two versions are kept
+	if _, err := r.Version("nightly-etl", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Version(1) error = %v, want ErrNotFound after pruning", err)
+	}
+
+	// Reopening the directory finds the workflow again
+	reopened, err := New(dir, 2)
+	if err != nil {
+		t.Fatalf("New() again error = %v", err)
+	}
+	if got, ok := reopened.Get("nightly-etl"); !ok || got.LatestVersion != 3 {
+		t.Fatalf("Get() after reopen = %+v, %v", got, ok)
+	}
+
+	removed, err := reopened.Remove("nightly-etl")
+	if err != nil || removed != 2 {
+		t.Fatalf("Remove() = %d, %v, want 2 versions", removed, err)
+	}
+	if len(reopened.List()) != 0 {
+		t.Errorf("List() after Remove = %v", reopened.List())
+	}
+}
+
+func TestRegistryDue(t *testing.T) {
+	r, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	entry := register(t, r, "nightly-etl", "jobs: {}", "0 2 * * *", now)
+	register(t, r, "adhoc", "jobs: {}", "", now)
+
+	if want := time.Date(2026, 3, 11, 2, 0, 0, 0, time.UTC); !entry.NextRun.Equal(want) {
+		t.Fatalf("NextRun = %v, want %v", entry.NextRun, want)
+	}
+	if due := r.Due(now.Add(time.Hour)); len(due) != 0 {
+		t.Fatalf("Due() before the schedule = %v", due)
+	}
+
+	// Two days late: started once, next run moves past now
+	late := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+	due := r.Due(late)
+	if len(due) != 1 || due[0].Name != "nightly-etl" {
+		t.Fatalf("Due() = %v, want nightly-etl", due)
+	}
+	if want := time.Date(2026, 3, 13, 2, 0, 0, 0, time.UTC); !due[0].NextRun.Equal(want) {
+		t.Errorf("NextRun after Due() = %v, want %v", due[0].NextRun, want)
+	}
+	if again := r.Due(late); len(again) != 0 {
+		t.Errorf("Due() fired twice: %v", again)
+	}
+}
+
+func TestRegistryRejectsInvalidInput(t *testing.T) {
+	r, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, reg := range []Registration{
+		{Name: "../etc"},
+		{Name: ""},
+		{Name: "etl", Schedule: "every night"},
+	} {
+		if _, err := r.Register(reg, time.Now()); err == nil {
+			t.Errorf("Register(%+v) succeeded, want error", reg)
+		}
+	}
+	if _, err := r.Version("missing", 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Version(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,60 @@
+package workflow
+
+import (
+	"sort"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// RequirementsFromYAML converts a job's requires list: {job: STATUS} entries
+// become simple requirements, {expression: "..."} entries expression
+// requirements. A job listed twice keeps its last status.
+func RequirementsFromYAML(requires []map[string]string) []Requirement {
+	statuses := make(map[string]string)
+	var expressions []string
+	for _, entry := range requires {
+		for key, value := range entry {
+			if key == types.RequiresExpressionKey {
+				expressions = append(expressions, value)
+				continue
+			}
+			statuses[key] = value
+		}
+	}
+
+	jobIDs := make([]string, 0, len(statuses))
+	for jobID := range statuses {
+		jobIDs = append(jobIDs, jobID)
+	}
+	sort.Strings(jobIDs)
+
+	var requirements []Requirement
+	for _, jobID := range jobIDs {
+		requirements = append(requirements, Requirement{
+			Type:   RequirementSimple,
+			JobID:  jobID,
+			Status: statuses[jobID],
+		})
+	}
+	for _, expr := range expressions {
+		requirements = append(requirements, Requirement{
+			Type:       RequirementExpression,
+			Expression: expr,
+		})
+	}
+	return requirements
+}
+
+// RequiredJobNames returns the jobs a requires list depends on, directly or
+// through expressions
+func RequiredJobNames(requires []map[string]string) []string {
+	var names []string
+	for _, req := range RequirementsFromYAML(requires) {
+		if req.Type == RequirementExpression {
+			names = append(names, ExpressionJobNames(req.Expression)...)
+			continue
+		}
+		names = append(names, req.JobID)
+	}
+	return names
+}
@@ -0,0 +1,67 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+func TestRequirementsFromYAML(t *testing.T) {
+	requires := []map[string]string{
+		{"extract": "COMPLETED"},
+		{"expression": "(validate=COMPLETED AND lint=COMPLETED) OR force IN (COMPLETED,FAILED)"},
+		{"build": "COMPLETED"},
+	}
+
+	want := []Requirement{
+		{Type: RequirementSimple, JobID: "build", Status: "COMPLETED"},
+		{Type: RequirementSimple, JobID: "extract", Status: "COMPLETED"},
+		{Type: RequirementExpression, Expression: "(validate=COMPLETED AND lint=COMPLETED) OR force IN (COMPLETED,FAILED)"},
+	}
+	if got := RequirementsFromYAML(requires); !reflect.DeepEqual(got, want) {
+		t.Errorf("RequirementsFromYAML() = %+v, want %+v", got, want)
+	}
+
+	wantNames := []string{"build", "extract", "validate", "lint", "force"}
+	if got := RequiredJobNames(requires); !reflect.DeepEqual(got, wantNames) {
+		t.Errorf("RequiredJobNames() = %v, want %v", got, wantNames)
+	}
+}
+
+func TestDependencyResolver_ImpossibleExpression(t *testing.T) {
+	dr := NewDependencyResolver()
+
+	jobs := map[string]*JobDependency{
+		"tests": {JobID: "tests", InternalName: "tests", Status: domain.StatusPending},
+		"lint":  {JobID: "lint", InternalName: "lint", Status: domain.StatusPending},
+		"release": {
+			JobID:        "release",
+			InternalName: "release",
+			Requirements: RequirementsFromYAML([]map[string]string{
+				{"expression": "tests=COMPLETED AND lint=COMPLETED"},
+			}),
+			Status: domain.StatusPending,
+		},
+	}
+
+	workflowID, err := dr.CreateWorkflow("wf", jobs, []string{"tests", "lint", "release"})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+
+	// Still satisfiable while lint runs
+	dr.OnJobStateChange("tests", domain.StatusRunning)
+	dr.OnJobStateChange("tests", domain.StatusFailed)
+	state, _ := dr.GetWorkflowStatus(workflowID)
+	if state.Jobs["release"].Status != domain.StatusPending {
+		t.Fatalf("release status = %v while lint is pending, want PENDING", state.Jobs["release"].Status)
+	}
+
+	dr.OnJobStateChange("lint", domain.StatusRunning)
+	dr.OnJobStateChange("lint", domain.StatusCompleted)
+	state, _ = dr.GetWorkflowStatus(workflowID)
+	if release := state.Jobs["release"]; release.Status != domain.StatusCanceled || !release.Impossible {
+		t.Errorf("release = %+v, want canceled once the expression can no longer hold", release)
+	}
+}
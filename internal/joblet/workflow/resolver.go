@@ -4,20 +4,21 @@ package workflow
 
 import (
 	"fmt"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
 )
 
-// DependencyResolver manages job dependencies and workflow execution
+// DependencyResolver manages job dependencies and workflow execution.
+//
+// Workflows are stored in a sharded table and each workflow carries its own lock
+// and evaluation caches, so state changes and readiness checks for different
+// workflows proceed in parallel. There is no resolver-wide mutex.
 type DependencyResolver struct {
-	mu              sync.RWMutex
-	workflows       map[int]*WorkflowState
-	jobToWorkflow   map[string]int
-	workflowCounter int
-	jobStateCache   map[string]domain.JobStatus
-	expressionCache map[string]bool
+	workflows       *workflowTable
+	jobToWorkflow   *jobIndex
+	workflowCounter atomic.Int64
 	eventChan       chan JobStateEvent
 }
 
@@ -36,6 +37,8 @@ type WorkflowState struct {
 	CompletedJobs int
 	FailedJobs    int
 	CanceledJobs  int
+	Canceled      bool                 // The whole workflow was canceled, so it ends CANCELED
+	Signature     *domain.JobSignature // Client signature of YamlContent (nil = unsigned)
 }
 
 // JobDependency tracks dependencies for a single job
@@ -76,6 +79,11 @@ const (
 	WorkflowStopped   WorkflowStatus = "STOPPED"
 )
 
+// Ended reports whether a workflow reached a final status
+func (s WorkflowStatus) Ended() bool {
+	return s == WorkflowCompleted || s == WorkflowFailed || s == WorkflowCanceled || s == WorkflowStopped
+}
+
 // JobStateEvent represents a job state change event
 type JobStateEvent struct {
 	JobID     string
@@ -89,11 +97,9 @@ type JobStateEvent struct {
 // Used by the WorkflowManager to coordinate job execution based on dependency requirements.
 func NewDependencyResolver() *DependencyResolver {
 	return &DependencyResolver{
-		workflows:       make(map[int]*WorkflowState),
-		jobToWorkflow:   make(map[string]int),
-		jobStateCache:   make(map[string]domain.JobStatus),
-		expressionCache: make(map[string]bool),
-		eventChan:       make(chan JobStateEvent, 1000),
+		workflows:     newWorkflowTable(),
+		jobToWorkflow: newJobIndex(),
+		eventChan:     make(chan JobStateEvent, 1000),
 	}
 }
 
@@ -111,11 +117,7 @@ func (dr *DependencyResolver) CreateWorkflow(workflow string, jobs map[string]*J
 
 // CreateWorkflowWithYaml creates a new workflow with YAML content support.
 func (dr *DependencyResolver) CreateWorkflowWithYaml(workflow string, yamlContent string, jobs map[string]*JobDependency, order []string) (int, error) {
-	dr.mu.Lock()
-	defer dr.mu.Unlock()
-
-	dr.workflowCounter++
-	workflowID := dr.workflowCounter
+	workflowID := int(dr.workflowCounter.Add(1))
 
 	workflowState := &WorkflowState{
 		ID:          workflowID,
@@ -128,31 +130,101 @@ func (dr *DependencyResolver) CreateWorkflowWithYaml(workflow string, yamlConten
 		TotalJobs:   len(jobs),
 	}
 
-	dr.workflows[workflowID] = workflowState
-
-	// Create mapping between internal names and job IDs
-	internalNameToJobID := make(map[string]string)
-
-	// Map jobs to workflow and create name mappings
-	for jobID, job := range jobs {
-		dr.jobToWorkflow[jobID] = workflowID
-		internalNameToJobID[job.InternalName] = jobID
-	}
+	entry := newWorkflowEntry(workflowState)
 
 	// Keep requirements using job names for consistency
 	// Job names are stable throughout the workflow lifecycle
 	// This enables proper dependency validation and circular detection
 
-	// Check which jobs can start immediately
+	// Check which jobs can start immediately. The entry is not yet visible to
+	// other goroutines, but the lock keeps the access pattern uniform.
+	entry.mu.Lock()
 	for _, job := range jobs {
-		if dr.canJobStart(job) {
+		if entry.canJobStart(job) {
 			job.CanStart = true
 		}
 	}
+	entry.mu.Unlock()
+
+	dr.workflows.put(workflowID, entry)
+
+	// Map jobs to workflow
+	for jobID := range jobs {
+		dr.jobToWorkflow.set(jobID, workflowID)
+	}
 
 	return workflowID, nil
 }
 
+// RestoreWorkflow stores the record of an ended workflow, such as one
+// imported from another node, under a new workflow ID. Its jobs keep the
+// statuses they ended with; jobs that never started stay unmapped so their
+// names cannot catch the jobs of live workflows.
+func (dr *DependencyResolver) RestoreWorkflow(state *WorkflowState) (int, error) {
+	if !state.Status.Ended() {
+		return 0, fmt.Errorf("workflow is %s, only ended workflows can be restored", state.Status)
+	}
+	return dr.loadWorkflow(state, false), nil
+}
+
+// ResumeWorkflow stores a workflow saved before the joblet restarted under a
+// new workflow ID, whatever its status. Unlike restored workflows, its jobs
+// that never started are mapped by name, so they can still be started and
+// renamed to their job IDs.
+func (dr *DependencyResolver) ResumeWorkflow(state *WorkflowState) int {
+	return dr.loadWorkflow(state, true)
+}
+
+// loadWorkflow stores a copy of state under a new workflow ID with its job
+// state cache rebuilt, mapping the jobs that never started when mapPending
+// is set
+func (dr *DependencyResolver) loadWorkflow(state *WorkflowState, mapPending bool) int {
+	workflowID := int(dr.workflowCounter.Add(1))
+	restored := *state
+	restored.ID = workflowID
+	restored.Jobs = make(map[string]*JobDependency, len(state.Jobs))
+	for jobID, job := range state.Jobs {
+		jobCopy := *job
+		restored.Jobs[jobID] = &jobCopy
+	}
+	entry := newWorkflowEntry(&restored)
+
+	entry.mu.Lock()
+	for _, job := range restored.Jobs {
+		if job.InternalName != "" {
+			entry.jobStateCache[job.InternalName] = job.Status
+		}
+	}
+	for _, job := range restored.Jobs {
+		if job.Status == domain.StatusPending && !job.Impossible && entry.canJobStart(job) {
+			job.CanStart = true
+		}
+	}
+	entry.mu.Unlock()
+
+	dr.workflows.put(workflowID, entry)
+	for jobID, job := range restored.Jobs {
+		if mapPending || job.JobID != job.InternalName {
+			dr.jobToWorkflow.set(jobID, workflowID)
+		}
+	}
+
+	return workflowID
+}
+
+// SetWorkflowSignature stores the client signature of the workflow's YAML
+func (dr *DependencyResolver) SetWorkflowSignature(workflowID int, signature *domain.JobSignature) error {
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
+		return fmt.Errorf("workflow %d not found", workflowID)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.state.Signature = signature
+	return nil
+}
+
 // UpdateJobID updates the job ID mapping in the dependency resolver.
 //
 // RESPONSIBILITY:
@@ -172,40 +244,50 @@ func (dr *DependencyResolver) CreateWorkflowWithYaml(workflow string, yamlConten
 // - actualJobID: Unique job identifier returned by joblet service (e.g., "42")
 //
 // THREAD SAFETY:
-// - Uses write lock to ensure atomic updates
+// - Holds only the owning workflow's lock while the job entry is moved
 // - Prevents race conditions during concurrent job state changes
 //
 // INTEGRATION:
 // - Called by WorkflowManager.UpdateJobID to maintain consistency
 // - Ensures dependency resolution works with actual job IDs
 func (dr *DependencyResolver) UpdateJobID(jobName string, actualJobID string) {
-	dr.mu.Lock()
-	defer dr.mu.Unlock()
+	_ = dr.renameJob(jobName, actualJobID)
+}
+
+// renameJob moves a workflow job from its name to the actual job ID and reports
+// why the rename could not be applied.
+func (dr *DependencyResolver) renameJob(jobName string, actualJobID string) error {
+	workflowID, exists := dr.jobToWorkflow.get(jobName)
+	if !exists {
+		return fmt.Errorf("job name %s not found in any workflow", jobName)
+	}
+
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
+		return fmt.Errorf("workflow %d not found", workflowID)
+	}
 
-	// Update jobToWorkflow mapping
-	if workflowID, exists := dr.jobToWorkflow[jobName]; exists {
-		delete(dr.jobToWorkflow, jobName)
-		dr.jobToWorkflow[actualJobID] = workflowID
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	jobDep, exists := entry.state.Jobs[jobName]
+	if !exists {
+		return fmt.Errorf("job %s not found in workflow %d", jobName, workflowID)
 	}
 
 	// Keep jobStateCache keyed by job names, not job IDs
 	// This ensures requirements can always find their dependencies by name
-	// The cache already uses jobName as key, so no changes needed here
-
-	// Update workflow Jobs map and requirements
-	for _, workflow := range dr.workflows {
-		if jobDep, exists := workflow.Jobs[jobName]; exists {
-			// Update the JobID field
-			jobDep.JobID = actualJobID
-			// Move the entry from jobName key to actualJobID key
-			delete(workflow.Jobs, jobName)
-			workflow.Jobs[actualJobID] = jobDep
-
-			// Keep requirements using job names for consistency
-			// No need to update requirements since they should always reference job names
-			break
-		}
-	}
+
+	// Update the JobID field and move the entry from jobName key to actualJobID key
+	jobDep.JobID = actualJobID
+	delete(entry.state.Jobs, jobName)
+	entry.state.Jobs[actualJobID] = jobDep
+
+	// Publish the new ID before retiring the name so state changes are never lost
+	dr.jobToWorkflow.set(actualJobID, workflowID)
+	dr.jobToWorkflow.delete(jobName)
+
+	return nil
 }
 
 // OnJobStateChange processes job status updates and cascades dependency effects.
@@ -217,42 +299,48 @@ func (dr *DependencyResolver) UpdateJobID(jobName string, actualJobID string) {
 // 5. Updates overall workflow status based on constituent job states
 // 6. Marks jobs as impossible if their dependencies can never be satisfied
 // Called by the workflow execution system whenever a job status changes.
-func (dr *DependencyResolver) OnJobStateChange(jobID string, newStatus domain.JobStatus) {
-	dr.mu.Lock()
-	defer dr.mu.Unlock()
-
+// Returns the job's workflow and whether the job's status changed.
+func (dr *DependencyResolver) OnJobStateChange(jobID string, newStatus domain.JobStatus) (int, bool) {
 	// Find workflow
-	workflowID, exists := dr.jobToWorkflow[jobID]
+	workflowID, exists := dr.jobToWorkflow.get(jobID)
 	if !exists {
-		return // Not part of a workflow
+		return 0, false // Not part of a workflow
 	}
 
-	workflow := dr.workflows[workflowID]
-	if workflow == nil {
-		return
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
+		return 0, false
 	}
 
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	workflow := entry.state
+
 	// Update job status and cache using job name for consistency
+	changed := false
 	if job, exists := workflow.Jobs[jobID]; exists {
 		// For workflow jobs, update cache using job name so requirements can find it
 		// Individual jobs don't have names or dependencies, so they don't need cache entries
 		if job.InternalName != "" {
-			dr.jobStateCache[job.InternalName] = newStatus
+			entry.setJobState(job.InternalName, newStatus)
 		}
 		oldStatus := job.Status
 		job.Status = newStatus
+		changed = oldStatus != newStatus
 
 		// Update workflow counters
 		dr.updateWorkflowCounters(workflow, oldStatus, newStatus)
 
 		// Handle terminal states
 		if isTerminalState(newStatus) {
-			dr.handleTerminalState(workflow, jobID, newStatus)
+			dr.handleTerminalState(entry, jobID, newStatus)
 		}
 
 		// Update workflow status
 		dr.updateWorkflowStatus(workflow)
 	}
+	return workflowID, changed
 }
 
 // GetReadyJobs returns a list of job IDs that are ready for execution.
@@ -264,23 +352,23 @@ func (dr *DependencyResolver) OnJobStateChange(jobID string, newStatus domain.Jo
 // This method is called by the workflow orchestration system to determine
 // which jobs should be started in the next execution cycle.
 func (dr *DependencyResolver) GetReadyJobs(workflowID int) []string {
-	dr.mu.RLock()
-	defer dr.mu.RUnlock()
-
-	workflow := dr.workflows[workflowID]
-	if workflow == nil {
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
 		return nil
 	}
 
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
 	var ready []string
-	for jobID, job := range workflow.Jobs {
+	for jobID, job := range entry.state.Jobs {
 		if job.Status == domain.StatusPending {
 			if job.Impossible {
 				// Job is marked as impossible, skip it
 				continue
 			} else if !job.CanStart {
 				// Check if job can start now
-				canStart := dr.canJobStart(job)
+				canStart := entry.canJobStart(job)
 				if canStart {
 					job.CanStart = true // Update the flag
 					ready = append(ready, jobID)
@@ -304,17 +392,16 @@ func (dr *DependencyResolver) GetReadyJobs(workflowID int) []string {
 // - Overall workflow status and timing information
 // Used by monitoring systems and CLI commands to display workflow progress.
 func (dr *DependencyResolver) GetWorkflowStatus(workflowID int) (*WorkflowState, error) {
-	dr.mu.RLock()
-	defer dr.mu.RUnlock()
-
-	workflow, exists := dr.workflows[workflowID]
+	entry, exists := dr.workflows.get(workflowID)
 	if !exists {
 		return nil, fmt.Errorf("workflow %d not found", workflowID)
 	}
 
 	// Create a copy to avoid race conditions
-	copy := *workflow
-	return &copy, nil
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	return entry.snapshot(), nil
 }
 
 // Private helper functions
@@ -322,13 +409,14 @@ func (dr *DependencyResolver) GetWorkflowStatus(workflowID int) (*WorkflowState,
 // canJobStart evaluates whether a job's dependency requirements are satisfied.
 // Returns true if all requirements are met, false otherwise.
 // A job with no requirements can always start immediately.
-func (dr *DependencyResolver) canJobStart(job *JobDependency) bool {
+// Callers must hold e.mu.
+func (e *workflowEntry) canJobStart(job *JobDependency) bool {
 	if len(job.Requirements) == 0 {
 		return true
 	}
 
 	for _, req := range job.Requirements {
-		satisfied := dr.evaluateRequirement(req)
+		satisfied := e.evaluateRequirement(req)
 		if !satisfied {
 			return false
 		}
@@ -340,17 +428,17 @@ func (dr *DependencyResolver) canJobStart(job *JobDependency) bool {
 // evaluateRequirement checks if a single dependency requirement is satisfied.
 // Handles both simple requirements (job=status) and complex expression requirements.
 // Uses the job state cache to check current job statuses for evaluation.
-func (dr *DependencyResolver) evaluateRequirement(req Requirement) bool {
+func (e *workflowEntry) evaluateRequirement(req Requirement) bool {
 	switch req.Type {
 	case RequirementSimple:
-		status, exists := dr.jobStateCache[req.JobID]
+		status, exists := e.jobStateCache[req.JobID]
 		if !exists {
 			return false
 		}
 		return string(status) == req.Status
 
 	case RequirementExpression:
-		return dr.evaluateExpression(req.Expression)
+		return e.evaluateExpression(req.Expression)
 
 	default:
 		return false
@@ -359,18 +447,19 @@ func (dr *DependencyResolver) evaluateRequirement(req Requirement) bool {
 
 // evaluateExpression evaluates complex dependency expressions with caching.
 // Checks the expression cache first for performance, then parses and evaluates.
-// Caches results to avoid re-parsing identical expressions multiple times.
-func (dr *DependencyResolver) evaluateExpression(expr string) bool {
+// Caches results to avoid re-parsing identical expressions multiple times; the
+// cache is cleared whenever a job in the workflow changes state.
+func (e *workflowEntry) evaluateExpression(expr string) bool {
 	// Check cache first
-	if result, exists := dr.expressionCache[expr]; exists {
+	if result, exists := e.expressionCache[expr]; exists {
 		return result
 	}
 
 	// Parse and evaluate expression
-	result := dr.parseAndEvaluateExpression(expr)
+	result := e.parseAndEvaluateExpression(expr)
 
 	// Cache result
-	dr.expressionCache[expr] = result
+	e.expressionCache[expr] = result
 
 	return result
 }
@@ -378,8 +467,8 @@ func (dr *DependencyResolver) evaluateExpression(expr string) bool {
 // parseAndEvaluateExpression evaluates dependency expressions using simple parsing.
 // Supports boolean operations (AND, OR), parentheses, simple comparisons (=),
 // and IN expressions. Uses the simplified expression evaluator.
-func (dr *DependencyResolver) parseAndEvaluateExpression(expr string) bool {
-	evaluator := NewSimpleExpressionEvaluator(dr.jobStateCache)
+func (e *workflowEntry) parseAndEvaluateExpression(expr string) bool {
+	evaluator := NewSimpleExpressionEvaluator(e.jobStateCache)
 	return evaluator.Evaluate(expr)
 }
 
@@ -389,7 +478,10 @@ func (dr *DependencyResolver) parseAndEvaluateExpression(expr string) bool {
 // 2. Marks jobs as impossible if their dependencies can never be satisfied
 // 3. Recursively handles cancellation chains when dependencies fail
 // This ensures workflow execution continues optimally after each job completion.
-func (dr *DependencyResolver) handleTerminalState(workflow *WorkflowState, jobID string, status domain.JobStatus) {
+// Callers must hold entry.mu.
+func (dr *DependencyResolver) handleTerminalState(entry *workflowEntry, jobID string, status domain.JobStatus) {
+	workflow := entry.state
+
 	// Check all jobs in workflow to see if any can now start or should be canceled
 	for otherJobID, otherJob := range workflow.Jobs {
 		if otherJobID == jobID || otherJob.Impossible {
@@ -415,14 +507,14 @@ func (dr *DependencyResolver) handleTerminalState(workflow *WorkflowState, jobID
 			otherJob.Status = domain.StatusCanceled
 			// Update cache using job name for consistency
 			if otherJob.InternalName != "" {
-				dr.jobStateCache[otherJob.InternalName] = domain.StatusCanceled
+				entry.setJobState(otherJob.InternalName, domain.StatusCanceled)
 			}
 			workflow.CanceledJobs++
 			// Recursively handle this cancellation
-			dr.handleTerminalState(workflow, otherJobID, domain.StatusCanceled)
+			dr.handleTerminalState(entry, otherJobID, domain.StatusCanceled)
 		} else {
 			// Check if this job can now start (requirements are satisfied)
-			if dr.canJobStart(otherJob) {
+			if entry.canJobStart(otherJob) {
 				otherJob.CanStart = true
 			}
 		}
@@ -456,9 +548,18 @@ func (dr *DependencyResolver) isRequirementImpossible(req Requirement, workflow
 		return false
 
 	case RequirementExpression:
-		// Expression requirements use complex logic evaluation
-		// Currently simplified - full implementation would parse and analyze expressions
-		return false
+		// An expression can no longer become true once every job it refers
+		// to has finished
+		states := make(map[string]domain.JobStatus, len(workflow.Jobs))
+		for _, job := range workflow.Jobs {
+			states[job.InternalName] = job.Status
+		}
+		for _, name := range ExpressionJobNames(req.Expression) {
+			if status, exists := states[name]; exists && !isTerminalState(status) {
+				return false
+			}
+		}
+		return !NewSimpleExpressionEvaluator(states).Evaluate(req.Expression)
 
 	default:
 		return false
@@ -502,7 +603,7 @@ func (dr *DependencyResolver) updateWorkflowCounters(workflow *WorkflowState, ol
 // - RUNNING: At least one job is running or has started
 // - COMPLETED: All jobs completed successfully
 // - FAILED: At least one job failed (and workflow not canceled)
-// - CANCELED: At least one job was canceled
+// - CANCELED: At least one job was canceled, or the whole workflow
 // Sets completion timestamp when workflow reaches terminal state.
 func (dr *DependencyResolver) updateWorkflowStatus(workflow *WorkflowState) {
 	allJobsTerminal := true
@@ -524,7 +625,7 @@ func (dr *DependencyResolver) updateWorkflowStatus(workflow *WorkflowState) {
 	oldStatus := workflow.Status
 
 	if allJobsTerminal {
-		if workflow.CanceledJobs > 0 {
+		if workflow.CanceledJobs > 0 || workflow.Canceled {
 			workflow.Status = WorkflowCanceled
 		} else if hasFailed || workflow.FailedJobs > 0 {
 			workflow.Status = WorkflowFailed
@@ -549,13 +650,14 @@ func (dr *DependencyResolver) updateWorkflowStatus(workflow *WorkflowState) {
 }
 
 // isTerminalState checks if a job status represents a final, unchangeable state.
-// Terminal states are: COMPLETED, FAILED, STOPPED, CANCELED.
+// Terminal states are: COMPLETED, FAILED, STOPPED, CANCELED, EXPIRED.
 // Jobs in terminal states will not change status again and affect dependency evaluation.
 func isTerminalState(status domain.JobStatus) bool {
 	return status == domain.StatusCompleted ||
 		status == domain.StatusFailed ||
 		status == domain.StatusStopped ||
-		status == domain.StatusCanceled
+		status == domain.StatusCanceled ||
+		status == domain.StatusExpired
 }
 
 // ListWorkflows returns a list of all workflows managed by this resolver.
@@ -563,14 +665,13 @@ func isTerminalState(status domain.JobStatus) bool {
 // to the internal workflow state. The list includes workflows in all states:
 // pending, running, completed, failed, and canceled.
 // Used by monitoring and administrative functions to get an overview of all workflows.
+// Workflows are locked one at a time, so listing never stalls orchestration.
 func (dr *DependencyResolver) ListWorkflows() []*WorkflowState {
-	dr.mu.RLock()
-	defer dr.mu.RUnlock()
-
 	var workflows []*WorkflowState
-	for _, wf := range dr.workflows {
-		copy := *wf
-		workflows = append(workflows, &copy)
+	for _, entry := range dr.workflows.entries() {
+		entry.mu.Lock()
+		workflows = append(workflows, entry.snapshot())
+		entry.mu.Unlock()
 	}
 
 	return workflows
@@ -582,9 +683,5 @@ func (dr *DependencyResolver) ListWorkflows() []*WorkflowState {
 // This mapping is used to route job status updates to the correct workflow
 // and to determine if job lifecycle events should trigger workflow updates.
 func (dr *DependencyResolver) GetJobWorkflow(jobID string) (int, bool) {
-	dr.mu.RLock()
-	defer dr.mu.RUnlock()
-
-	workflowID, exists := dr.jobToWorkflow[jobID]
-	return workflowID, exists
+	return dr.jobToWorkflow.get(jobID)
 }
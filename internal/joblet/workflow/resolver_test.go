@@ -14,18 +14,19 @@ func TestNewDependencyResolver(t *testing.T) {
 	}
 
 	if dr.workflows == nil {
-		t.Error("workflows map not initialized")
+		t.Error("workflows table not initialized")
 	}
 
 	if dr.jobToWorkflow == nil {
-		t.Error("jobToWorkflow map not initialized")
+		t.Error("jobToWorkflow index not initialized")
 	}
 
-	if dr.jobStateCache == nil {
+	entry := newWorkflowEntry(&WorkflowState{})
+	if entry.jobStateCache == nil {
 		t.Error("jobStateCache map not initialized")
 	}
 
-	if dr.expressionCache == nil {
+	if entry.expressionCache == nil {
 		t.Error("expressionCache map not initialized")
 	}
 }
@@ -65,12 +66,9 @@ func TestDependencyResolver_CreateWorkflow(t *testing.T) {
 	}
 
 	// Verify workflow was created
-	dr.mu.RLock()
-	workflow, exists := dr.workflows[workflowID]
-	dr.mu.RUnlock()
-
-	if !exists {
-		t.Fatal("Workflow not found")
+	workflow, err := dr.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatalf("Workflow not found: %v", err)
 	}
 
 	if workflow.Status != WorkflowPending {
@@ -83,10 +81,10 @@ func TestDependencyResolver_CreateWorkflow(t *testing.T) {
 }
 
 func TestDependencyResolver_EvaluateRequirement(t *testing.T) {
-	dr := NewDependencyResolver()
+	entry := newWorkflowEntry(&WorkflowState{})
 
 	// Set up job state cache
-	dr.jobStateCache = map[string]domain.JobStatus{
+	entry.jobStateCache = map[string]domain.JobStatus{
 		"job1": domain.StatusCompleted,
 		"job2": domain.StatusFailed,
 		"job3": domain.StatusRunning,
@@ -144,7 +142,7 @@ func TestDependencyResolver_EvaluateRequirement(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := dr.evaluateRequirement(tt.requirement)
+			result := entry.evaluateRequirement(tt.requirement)
 			if result != tt.expected {
 				t.Errorf("evaluateRequirement() = %v, want %v", result, tt.expected)
 			}
@@ -153,10 +151,10 @@ func TestDependencyResolver_EvaluateRequirement(t *testing.T) {
 }
 
 func TestDependencyResolver_ParseAndEvaluateExpression(t *testing.T) {
-	dr := NewDependencyResolver()
+	entry := newWorkflowEntry(&WorkflowState{})
 
 	// Set up job state cache
-	dr.jobStateCache = map[string]domain.JobStatus{
+	entry.jobStateCache = map[string]domain.JobStatus{
 		"job1": domain.StatusCompleted,
 		"job2": domain.StatusFailed,
 		"job3": domain.StatusRunning,
@@ -191,7 +189,7 @@ func TestDependencyResolver_ParseAndEvaluateExpression(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := dr.parseAndEvaluateExpression(tt.expr)
+			result := entry.parseAndEvaluateExpression(tt.expr)
 			if result != tt.expected {
 				t.Errorf("parseAndEvaluateExpression(%q) = %v, want %v", tt.expr, result, tt.expected)
 			}
@@ -200,10 +198,10 @@ func TestDependencyResolver_ParseAndEvaluateExpression(t *testing.T) {
 }
 
 func TestDependencyResolver_EvaluateExpression(t *testing.T) {
-	dr := NewDependencyResolver()
+	entry := newWorkflowEntry(&WorkflowState{})
 
 	// Set up job state cache
-	dr.jobStateCache = map[string]domain.JobStatus{
+	entry.jobStateCache = map[string]domain.JobStatus{
 		"job1": domain.StatusCompleted,
 	}
 
@@ -211,19 +209,19 @@ func TestDependencyResolver_EvaluateExpression(t *testing.T) {
 	expr := "job1=COMPLETED"
 
 	// First call should evaluate and cache
-	result1 := dr.evaluateExpression(expr)
+	result1 := entry.evaluateExpression(expr)
 	if !result1 {
 		t.Error("First evaluation should return true")
 	}
 
 	// Second call should use cache
-	result2 := dr.evaluateExpression(expr)
+	result2 := entry.evaluateExpression(expr)
 	if !result2 {
 		t.Error("Second evaluation should return true (from cache)")
 	}
 
 	// Check that result is cached
-	if cachedResult, exists := dr.expressionCache[expr]; !exists || !cachedResult {
+	if cachedResult, exists := entry.expressionCache[expr]; !exists || !cachedResult {
 		t.Error("Expression result should be cached")
 	}
 }
@@ -249,16 +247,22 @@ func TestDependencyResolver_OnJobStateChange(t *testing.T) {
 	// Update job state using OnJobStateChange
 	dr.OnJobStateChange("job1", domain.StatusRunning)
 
+	entry, exists := dr.workflows.get(workflowID)
+	if !exists {
+		t.Fatal("Workflow not found")
+	}
+
+	entry.mu.Lock()
+	state, cached := entry.jobStateCache["job1"]
+	jobDep := *entry.state.Jobs["job1"]
+	entry.mu.Unlock()
+
 	// Check that job state was updated in cache (using internal name)
-	if state, exists := dr.jobStateCache["job1"]; !exists || state != domain.StatusRunning {
+	if !cached || state != domain.StatusRunning {
 		t.Errorf("Job state not updated correctly: got %v, want %v", state, domain.StatusRunning)
 	}
 
 	// Check that workflow job status was updated
-	dr.mu.RLock()
-	workflow := dr.workflows[workflowID]
-	jobDep := workflow.Jobs["job1"]
-	dr.mu.RUnlock()
 
 	if jobDep.Status != domain.StatusRunning {
 		t.Errorf("Workflow job status not updated: got %v, want %v", jobDep.Status, domain.StatusRunning)
@@ -0,0 +1,285 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// SimulatedRun is how a job behaves in a simulation
+type SimulatedRun struct {
+	Duration time.Duration
+	// Status the job ends with, COMPLETED or FAILED
+	Status domain.JobStatus
+	// InfraFailures is how many launches fail on the node's side before
+	// one succeeds
+	InfraFailures int
+}
+
+// UnmarshalJSON reads a run as a duration ("5m") or as an object
+// ({"duration": "5m", "status": "FAILED", "infra_failures": 1})
+func (r *SimulatedRun) UnmarshalJSON(data []byte) error {
+	var spec struct {
+		Duration      string `json:"duration"`
+		Status        string `json:"status"`
+		InfraFailures int    `json:"infra_failures"`
+	}
+	if err := json.Unmarshal(data, &spec.Duration); err != nil {
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return fmt.Errorf("expected a duration or an object with duration, status and infra_failures")
+		}
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(spec.Duration))
+	if err != nil || duration < 0 {
+		return fmt.Errorf("invalid duration %q: expected a duration such as \"20m\"", spec.Duration)
+	}
+	status := domain.JobStatus(strings.ToUpper(spec.Status))
+	switch status {
+	case "":
+		status = domain.StatusCompleted
+	case domain.StatusCompleted, domain.StatusFailed:
+	default:
+		return fmt.Errorf("invalid status %q: expected COMPLETED or FAILED", spec.Status)
+	}
+	if spec.InfraFailures < 0 {
+		return fmt.Errorf("invalid infra_failures %d", spec.InfraFailures)
+	}
+	*r = SimulatedRun{Duration: duration, Status: status, InfraFailures: spec.InfraFailures}
+	return nil
+}
+
+// ParseSimulatedRuns reads a durations document mapping job names to runs
+func ParseSimulatedRuns(data []byte) (map[string]SimulatedRun, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON object mapping job names to durations: %w", err)
+	}
+	runs := make(map[string]SimulatedRun, len(raw))
+	for name, value := range raw {
+		var run SimulatedRun
+		if err := json.Unmarshal(value, &run); err != nil {
+			return nil, fmt.Errorf("job '%s': %w", name, err)
+		}
+		runs[name] = run
+	}
+	return runs, nil
+}
+
+// SimulationOptions are the node settings a simulation schedules with
+type SimulationOptions struct {
+	// MaxParallel is how many jobs run at once (0 = no limit)
+	MaxParallel int
+	// InfraRetries and InfraRetryDelay relaunch failed launches as
+	// joblet.infraRetries and joblet.infraRetryDelay do
+	InfraRetries    int
+	InfraRetryDelay time.Duration
+	// Start is when the workflow is submitted, for not_before and window
+	Start time.Time
+}
+
+// SimulatedJob is a job's place on the simulated timeline. Times are
+// offsets from the workflow's submission; a job that never ran has zero
+// times.
+type SimulatedJob struct {
+	Name     string
+	Status   domain.JobStatus
+	Ready    time.Duration // Its requirements were met
+	Start    time.Duration // It got a running slot
+	End      time.Duration // It ended
+	Wait     time.Duration // Spent due but waiting for a running slot
+	Attempts int           // Launches, failed ones included
+	Ran      bool
+}
+
+// Simulation is the outcome of running a workflow against synthetic
+// durations
+type Simulation struct {
+	Jobs        []SimulatedJob // In start order, jobs that never ran last
+	Status      WorkflowStatus
+	Makespan    time.Duration
+	MaxRunning  int           // Most jobs running at once
+	TotalWait   time.Duration // Time jobs spent waiting for a running slot
+	Unsimulated []string      // Jobs without a duration or estimate, run as 0
+}
+
+// Simulate runs a workflow through the dependency resolver on a virtual
+// clock, without executing anything. Jobs last as long as runs says, or
+// their estimate when runs does not name them, and wait for a running slot
+// when MaxParallel are running.
+func Simulate(wf types.WorkflowYAML, runs map[string]SimulatedRun, opts SimulationOptions) (*Simulation, error) {
+	if _, err := Analyze(wf); err != nil {
+		return nil, err
+	}
+	for name := range runs {
+		if _, exists := wf.Jobs[name]; !exists {
+			return nil, fmt.Errorf("durations name job '%s', which is not in the workflow", name)
+		}
+	}
+	if opts.Start.IsZero() {
+		opts.Start = time.Now()
+	}
+
+	order := make([]string, 0, len(wf.Jobs))
+	for name := range wf.Jobs {
+		order = append(order, name)
+	}
+	sort.Strings(order)
+
+	sim := &Simulation{}
+	jobRuns := make(map[string]SimulatedRun, len(order))
+	timings := make(map[string]JobTiming, len(order))
+	deps := make(map[string]*JobDependency, len(order))
+	for _, name := range order {
+		spec := wf.Jobs[name]
+		timing, err := ParseJobTiming(spec, opts.Start)
+		if err != nil {
+			return nil, fmt.Errorf("job '%s': %w", name, err)
+		}
+		timings[name] = timing
+		run, exists := runs[name]
+		if !exists {
+			if timing.Estimate == 0 {
+				sim.Unsimulated = append(sim.Unsimulated, name)
+			}
+			run = SimulatedRun{Duration: timing.Estimate, Status: domain.StatusCompleted}
+		}
+		jobRuns[name] = run
+		deps[name] = &JobDependency{
+			JobID:        name,
+			InternalName: name,
+			Requirements: RequirementsFromYAML(spec.Requires),
+			Status:       domain.StatusPending,
+		}
+	}
+
+	resolver := NewDependencyResolver()
+	workflowID, err := resolver.CreateWorkflow(wf.Name, deps, order)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make(map[string]*SimulatedJob, len(order))
+	due := map[string]time.Duration{} // Ready jobs held by their timing fields
+	dueAt := map[string]time.Duration{}
+	var queue []string // Due jobs waiting for a running slot
+	running := map[string]time.Duration{}
+	var now time.Duration
+
+	for {
+		// Jobs whose requirements are met now
+		ready := resolver.GetReadyJobs(workflowID)
+		sort.Strings(ready)
+		for _, name := range ready {
+			if _, seen := jobs[name]; seen {
+				continue
+			}
+			jobs[name] = &SimulatedJob{Name: name, Ready: now}
+			due[name] = timings[name].StartTime(opts.Start.Add(now)).Sub(opts.Start)
+		}
+
+		// Due jobs join the queue, in name order when due at once
+		var dueNow []string
+		for name, at := range due {
+			if at <= now {
+				dueNow = append(dueNow, name)
+			}
+		}
+		slices.Sort(dueNow)
+		for _, name := range dueNow {
+			delete(due, name)
+			dueAt[name] = now
+			queue = append(queue, name)
+		}
+
+		// Queued jobs take the free running slots
+		for len(queue) > 0 && (opts.MaxParallel <= 0 || len(running) < opts.MaxParallel) {
+			name := queue[0]
+			queue = queue[1:]
+			job := jobs[name]
+			run := jobRuns[name]
+			job.Start = now
+			job.Wait = now - dueAt[name]
+			job.Ran = true
+
+			// Failed launches hold the slot for a retry delay each, and
+			// fail the job once retries are exhausted
+			failures := min(run.InfraFailures, opts.InfraRetries+1)
+			job.Attempts = failures + 1
+			end := now + time.Duration(failures)*opts.InfraRetryDelay
+			if run.InfraFailures > opts.InfraRetries {
+				job.Attempts = failures
+				end -= opts.InfraRetryDelay
+			} else {
+				end += run.Duration
+			}
+			running[name] = end
+			resolver.OnJobStateChange(name, domain.StatusRunning)
+		}
+		sim.MaxRunning = max(sim.MaxRunning, len(running))
+
+		// Move to the next end or due time
+		next, found := time.Duration(0), false
+		for _, at := range running {
+			if !found || at < next {
+				next, found = at, true
+			}
+		}
+		for _, at := range due {
+			if !found || at < next {
+				next, found = at, true
+			}
+		}
+		if !found {
+			break
+		}
+		now = next
+
+		var ended []string
+		for name, at := range running {
+			if at <= now {
+				ended = append(ended, name)
+			}
+		}
+		slices.Sort(ended)
+		for _, name := range ended {
+			delete(running, name)
+			status := jobRuns[name].Status
+			if jobRuns[name].InfraFailures > opts.InfraRetries {
+				status = domain.StatusFailed
+			}
+			jobs[name].End = now
+			resolver.OnJobStateChange(name, status)
+		}
+	}
+
+	state, err := resolver.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	sim.Status = state.Status
+	sim.Makespan = now
+	for _, name := range order {
+		job, seen := jobs[name]
+		if !seen {
+			job = &SimulatedJob{Name: name}
+		}
+		job.Status = state.Jobs[name].Status
+		sim.TotalWait += job.Wait
+		sim.Jobs = append(sim.Jobs, *job)
+	}
+	sort.SliceStable(sim.Jobs, func(i, j int) bool {
+		a, b := sim.Jobs[i], sim.Jobs[j]
+		if a.Ran != b.Ran {
+			return a.Ran
+		}
+		return a.Start < b.Start
+	})
+	return sim, nil
+}
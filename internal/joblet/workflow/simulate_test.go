@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func TestSimulate(t *testing.T) {
+	wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+		"fetch-a": {},
+		"fetch-b": {},
+		"fetch-c": {Estimate: "5m"},
+		"train": {
+			Requires: []map[string]string{{"fetch-a": "COMPLETED"}, {"fetch-b": "COMPLETED"}},
+		},
+		"report": {Schedule: "1m", Requires: []map[string]string{{"train": "COMPLETED"}}},
+		"alert":  {Requires: []map[string]string{{"train": "FAILED"}}},
+	}}
+	runs, err := ParseSimulatedRuns([]byte(`{
+		"fetch-a": "10m",
+		"fetch-b": {"duration": "20m", "infra_failures": 1},
+		"train": "1h",
+		"report": "2m"
+	}`))
+	if err != nil {
+		t.Fatalf("ParseSimulatedRuns() error = %v", err)
+	}
+
+	sim, err := Simulate(wf, runs, SimulationOptions{MaxParallel: 2, InfraRetries: 2, InfraRetryDelay: time.Minute})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+
+	want := map[string]SimulatedJob{
+		"fetch-a": {Status: domain.StatusCompleted, Start: 0, End: 10 * time.Minute, Attempts: 1},
+		// Launched again after a failed launch, a minute later
+		"fetch-b": {Status: domain.StatusCompleted, Start: 0, End: 21 * time.Minute, Attempts: 2},
+		// Waits for fetch-a's slot
+		"fetch-c": {Status: domain.StatusCompleted, Start: 10 * time.Minute, End: 15 * time.Minute, Wait: 10 * time.Minute, Attempts: 1},
+		"train":   {Status: domain.StatusCompleted, Ready: 21 * time.Minute, Start: 21 * time.Minute, End: 81 * time.Minute, Attempts: 1},
+		// Scheduled a minute after train completed
+		"report": {Status: domain.StatusCompleted, Ready: 81 * time.Minute, Start: 82 * time.Minute, End: 84 * time.Minute, Attempts: 1},
+		"alert":  {Status: domain.StatusCanceled},
+	}
+	for _, job := range sim.Jobs {
+		w := want[job.Name]
+		w.Name = job.Name
+		w.Ran = w.Attempts > 0
+		if job != w {
+			t.Errorf("job %s = %+v, want %+v", job.Name, job, w)
+		}
+	}
+	if sim.Jobs[len(sim.Jobs)-1].Name != "alert" {
+		t.Errorf("jobs that never ran are not last: %+v", sim.Jobs)
+	}
+	if sim.Makespan != 84*time.Minute || sim.MaxRunning != 2 || sim.TotalWait != 10*time.Minute {
+		t.Errorf("makespan %v, max running %d, total wait %v", sim.Makespan, sim.MaxRunning, sim.TotalWait)
+	}
+	// alert's requirement on a failure can no longer be met
+	if sim.Status != WorkflowCanceled {
+		t.Errorf("status = %s, want CANCELED", sim.Status)
+	}
+	if len(sim.Unsimulated) != 1 || sim.Unsimulated[0] != "alert" {
+		t.Errorf("unsimulated = %v, want [alert]", sim.Unsimulated)
+	}
+}
+
+func TestSimulateExhaustedRetries(t *testing.T) {
+	wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+		"build":  {},
+		"deploy": {Requires: []map[string]string{{"build": "COMPLETED"}}},
+	}}
+	runs := map[string]SimulatedRun{"build": {Duration: time.Hour, Status: domain.StatusCompleted, InfraFailures: 3}}
+
+	sim, err := Simulate(wf, runs, SimulationOptions{InfraRetries: 2, InfraRetryDelay: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	build := sim.Jobs[0]
+	if build.Status != domain.StatusFailed || build.Attempts != 3 || build.End != time.Minute {
+		t.Errorf("build = %+v, want failed after 3 launches a minute in", build)
+	}
+	if sim.Status != WorkflowCanceled {
+		t.Errorf("status = %s, want CANCELED", sim.Status)
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{"build": {}}}
+	if _, err := Simulate(wf, map[string]SimulatedRun{"test": {}}, SimulationOptions{}); err == nil {
+		t.Error("Simulate() accepted durations for an unknown job")
+	}
+
+	for _, doc := range []string{
+		`["build"]`,
+		`{"build": "soon"}`,
+		`{"build": {"duration": "1m", "status": "STOPPED"}}`,
+		`{"build": {"duration": "1m", "infra_failures": -1}}`,
+	} {
+		if _, err := ParseSimulatedRuns([]byte(doc)); err == nil {
+			t.Errorf("ParseSimulatedRuns(%s) succeeded", doc)
+		}
+	}
+}
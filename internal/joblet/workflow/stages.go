@@ -0,0 +1,113 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// stageEndedStatuses are the statuses after which a stage's after hooks run
+const stageEndedStatuses = "(COMPLETED,FAILED,STOPPED,CANCELED,EXPIRED)"
+
+// ExpandStages turns the stages of wf into plain requirements, so the
+// resolver orders them like any other dependency:
+//
+//   - before hooks require everything of the previous stage to complete
+//   - the stage's jobs also require the before hooks to complete
+//   - after hooks require the previous stage to complete and the before
+//     hooks and the stage's jobs to have ended, whatever their status
+//
+// The jobs' own requires are kept. Jobs outside any stage are unchanged.
+func ExpandStages(wf *types.WorkflowYAML) error {
+	if len(wf.Stages) == 0 {
+		for name, spec := range wf.Jobs {
+			if spec.Stage != "" {
+				return fmt.Errorf("job '%s' is in stage '%s' but the workflow declares no stages", name, spec.Stage)
+			}
+		}
+		return nil
+	}
+
+	stages := make(map[string]bool, len(wf.Stages))
+	hooks := make(map[string]string)
+	for _, stage := range wf.Stages {
+		if stage.Name == "" {
+			return fmt.Errorf("stage without a name")
+		}
+		if stages[stage.Name] {
+			return fmt.Errorf("stage '%s' is declared twice", stage.Name)
+		}
+		stages[stage.Name] = true
+		for _, hook := range append(append([]string{}, stage.Before...), stage.After...) {
+			spec, exists := wf.Jobs[hook]
+			if !exists {
+				return fmt.Errorf("stage '%s' hook '%s' is not a job", stage.Name, hook)
+			}
+			if spec.Stage != "" {
+				return fmt.Errorf("stage '%s' hook '%s' must not have a stage of its own", stage.Name, hook)
+			}
+			if other, used := hooks[hook]; used {
+				return fmt.Errorf("job '%s' is a hook of both stage '%s' and stage '%s'", hook, other, stage.Name)
+			}
+			hooks[hook] = stage.Name
+		}
+	}
+
+	members := make(map[string][]string, len(wf.Stages))
+	for name, spec := range wf.Jobs {
+		if spec.Stage == "" {
+			continue
+		}
+		if !stages[spec.Stage] {
+			return fmt.Errorf("job '%s' is in undeclared stage '%s'", name, spec.Stage)
+		}
+		members[spec.Stage] = append(members[spec.Stage], name)
+	}
+
+	var previous []string
+	for _, stage := range wf.Stages {
+		jobs := members[stage.Name]
+		sort.Strings(jobs)
+
+		for _, hook := range stage.Before {
+			requireCompleted(wf, hook, previous)
+		}
+		for _, name := range jobs {
+			requireCompleted(wf, name, previous)
+			requireCompleted(wf, name, stage.Before)
+		}
+
+		var ended []string
+		for _, name := range append(append([]string{}, stage.Before...), jobs...) {
+			ended = append(ended, name+" IN "+stageEndedStatuses)
+		}
+		for _, hook := range stage.After {
+			requireCompleted(wf, hook, previous)
+			if len(ended) > 0 {
+				spec := wf.Jobs[hook]
+				spec.Requires = append(spec.Requires, map[string]string{types.RequiresExpressionKey: strings.Join(ended, " AND ")})
+				wf.Jobs[hook] = spec
+			}
+		}
+
+		// An empty stage leaves the next one waiting on the stage before it
+		if all := append(append(append([]string{}, stage.Before...), jobs...), stage.After...); len(all) > 0 {
+			previous = all
+		}
+	}
+	return nil
+}
+
+// requireCompleted adds a COMPLETED requirement on each of deps to job
+func requireCompleted(wf *types.WorkflowYAML, job string, deps []string) {
+	if len(deps) == 0 {
+		return
+	}
+	spec := wf.Jobs[job]
+	for _, dep := range deps {
+		spec.Requires = append(spec.Requires, map[string]string{dep: "COMPLETED"})
+	}
+	wf.Jobs[job] = spec
+}
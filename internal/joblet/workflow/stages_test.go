@@ -0,0 +1,125 @@
+package workflow
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func stagedWorkflow() *types.WorkflowYAML {
+	return &types.WorkflowYAML{
+		Stages: []types.StageSpec{
+			{Name: "build"},
+			{Name: "test", Before: []string{"start-db"}, After: []string{"stop-db"}},
+			{Name: "deploy"},
+		},
+		Jobs: map[string]types.JobSpec{
+			"compile":     {Stage: "build"},
+			"start-db":    {},
+			"unit":        {Stage: "test"},
+			"integration": {Stage: "test", Requires: []map[string]string{{"unit": "COMPLETED"}}},
+			"stop-db":     {},
+			"release":     {Stage: "deploy"},
+			"lint":        {},
+		},
+	}
+}
+
+func TestExpandStages(t *testing.T) {
+	wf := stagedWorkflow()
+	if err := ExpandStages(wf); err != nil {
+		t.Fatalf("ExpandStages() error = %v", err)
+	}
+
+	for name, want := range map[string][]string{
+		"compile":     nil,
+		"start-db":    {"compile"},
+		"unit":        {"compile", "start-db"},
+		"integration": {"unit", "compile", "start-db"},
+		"stop-db":     {"compile", "start-db", "integration", "unit"},
+		"release":     {"start-db", "integration", "unit", "stop-db"},
+		"lint":        nil,
+	} {
+		if got := RequiredJobNames(wf.Jobs[name].Requires); !slices.Equal(sorted(got), sorted(want)) {
+			t.Errorf("%s requires %v, want %v", name, got, want)
+		}
+	}
+	if _, err := Analyze(*wf); err != nil {
+		t.Errorf("Analyze() of the expanded workflow: %v", err)
+	}
+}
+
+func TestExpandStages_Invalid(t *testing.T) {
+	for name, edit := range map[string]func(*types.WorkflowYAML){
+		"unknown stage":    func(wf *types.WorkflowYAML) { wf.Jobs["lint"] = types.JobSpec{Stage: "qa"} },
+		"duplicate stage":  func(wf *types.WorkflowYAML) { wf.Stages = append(wf.Stages, types.StageSpec{Name: "build"}) },
+		"unnamed stage":    func(wf *types.WorkflowYAML) { wf.Stages = append(wf.Stages, types.StageSpec{}) },
+		"unknown hook":     func(wf *types.WorkflowYAML) { wf.Stages[0].Before = []string{"missing"} },
+		"hook in a stage":  func(wf *types.WorkflowYAML) { wf.Stages[0].After = []string{"unit"} },
+		"hook used twice":  func(wf *types.WorkflowYAML) { wf.Stages[2].Before = []string{"start-db"} },
+		"no stages at all": func(wf *types.WorkflowYAML) { wf.Stages = nil },
+	} {
+		wf := stagedWorkflow()
+		edit(wf)
+		if err := ExpandStages(wf); err == nil {
+			t.Errorf("%s: ExpandStages() succeeded", name)
+		}
+	}
+}
+
+// TestExpandStages_HookFailure runs the expanded workflow through the
+// resolver: a failed setup hook cancels its stage and the ones after it,
+// while the teardown hook still runs
+func TestExpandStages_HookFailure(t *testing.T) {
+	wf := stagedWorkflow()
+	if err := ExpandStages(wf); err != nil {
+		t.Fatal(err)
+	}
+	jobs := make(map[string]*JobDependency, len(wf.Jobs))
+	var order []string
+	for name, spec := range wf.Jobs {
+		jobs[name] = &JobDependency{JobID: name, InternalName: name, Requirements: RequirementsFromYAML(spec.Requires), Status: domain.StatusPending}
+		order = append(order, name)
+	}
+	dr := NewDependencyResolver()
+	id, err := dr.CreateWorkflow("staged.yaml", jobs, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ready := sorted(dr.GetReadyJobs(id)); !slices.Equal(ready, []string{"compile", "lint"}) {
+		t.Fatalf("ready at start = %v", ready)
+	}
+	dr.OnJobStateChange("compile", domain.StatusCompleted)
+	dr.OnJobStateChange("lint", domain.StatusCompleted)
+	if ready := dr.GetReadyJobs(id); !slices.Equal(ready, []string{"start-db"}) {
+		t.Fatalf("ready after the build stage = %v", ready)
+	}
+	dr.OnJobStateChange("start-db", domain.StatusFailed)
+	if ready := dr.GetReadyJobs(id); !slices.Equal(ready, []string{"stop-db"}) {
+		t.Fatalf("ready after the setup hook failed = %v", ready)
+	}
+	dr.OnJobStateChange("stop-db", domain.StatusCompleted)
+
+	state, err := dr.GetWorkflowStatus(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"unit", "integration", "release"} {
+		if status := state.Jobs[name].Status; status != domain.StatusCanceled {
+			t.Errorf("%s is %s, want CANCELED", name, status)
+		}
+	}
+	if state.Status == WorkflowCompleted {
+		t.Error("workflow completed despite the failed hook")
+	}
+}
+
+func sorted(names []string) []string {
+	names = slices.Clone(names)
+	sort.Strings(names)
+	return names
+}
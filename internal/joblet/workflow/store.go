@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// workflowShardCount is the number of independent shards used for workflow and
+// job index storage. It is a power of two so shard selection stays cheap, and
+// large enough that thousands of concurrent workflows rarely share a shard lock.
+const workflowShardCount = 64
+
+// workflowEntry pairs a WorkflowState with the lock and evaluation caches that
+// belong to that workflow alone. Every read or mutation of the state goes through
+// mu, so orchestration of one workflow never blocks another.
+//
+// Job states and expression results are cached per workflow because requirements
+// only ever reference jobs by name within their own workflow. Keeping the caches
+// local also means two workflows that reuse a job name cannot satisfy each
+// other's dependencies.
+type workflowEntry struct {
+	mu              sync.Mutex
+	state           *WorkflowState
+	jobStateCache   map[string]domain.JobStatus
+	expressionCache map[string]bool
+}
+
+// newWorkflowEntry wraps a workflow state with empty evaluation caches.
+func newWorkflowEntry(state *WorkflowState) *workflowEntry {
+	return &workflowEntry{
+		state:           state,
+		jobStateCache:   make(map[string]domain.JobStatus),
+		expressionCache: make(map[string]bool),
+	}
+}
+
+// snapshot returns a copy of the workflow state that is safe to use after the
+// entry lock is released. Job dependencies are copied as well since the live
+// entries keep changing while the workflow runs. Callers must hold e.mu.
+func (e *workflowEntry) snapshot() *WorkflowState {
+	copy := *e.state
+	copy.Jobs = make(map[string]*JobDependency, len(e.state.Jobs))
+	for jobID, job := range e.state.Jobs {
+		jobCopy := *job
+		copy.Jobs[jobID] = &jobCopy
+	}
+	return &copy
+}
+
+// setJobState records a job status for requirement evaluation and drops cached
+// expression results, which may no longer hold. Callers must hold e.mu.
+func (e *workflowEntry) setJobState(jobName string, status domain.JobStatus) {
+	e.jobStateCache[jobName] = status
+	clear(e.expressionCache)
+}
+
+// workflowShard holds the workflows whose IDs hash to it.
+type workflowShard struct {
+	mu        sync.RWMutex
+	workflows map[int]*workflowEntry
+}
+
+// workflowTable is a sharded map from workflow ID to workflow entry. Shard locks
+// only guard membership; they are never held while an entry lock is taken.
+type workflowTable struct {
+	shards [workflowShardCount]workflowShard
+}
+
+// newWorkflowTable creates an empty workflow table.
+func newWorkflowTable() *workflowTable {
+	t := &workflowTable{}
+	for i := range t.shards {
+		t.shards[i].workflows = make(map[int]*workflowEntry)
+	}
+	return t
+}
+
+func (t *workflowTable) shardFor(workflowID int) *workflowShard {
+	return &t.shards[uint(workflowID)%workflowShardCount]
+}
+
+// get returns the entry for a workflow ID.
+func (t *workflowTable) get(workflowID int) (*workflowEntry, bool) {
+	shard := t.shardFor(workflowID)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	entry, exists := shard.workflows[workflowID]
+	return entry, exists
+}
+
+// put stores an entry under the given workflow ID.
+func (t *workflowTable) put(workflowID int, entry *workflowEntry) {
+	shard := t.shardFor(workflowID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.workflows[workflowID] = entry
+}
+
+// entries returns every stored entry. Each shard is locked only while its
+// entries are collected, so listing never stalls the whole table.
+func (t *workflowTable) entries() []*workflowEntry {
+	var result []*workflowEntry
+	for i := range t.shards {
+		shard := &t.shards[i]
+		shard.mu.RLock()
+		for _, entry := range shard.workflows {
+			result = append(result, entry)
+		}
+		shard.mu.RUnlock()
+	}
+	return result
+}
+
+// len returns the number of stored workflows.
+func (t *workflowTable) len() int {
+	total := 0
+	for i := range t.shards {
+		shard := &t.shards[i]
+		shard.mu.RLock()
+		total += len(shard.workflows)
+		shard.mu.RUnlock()
+	}
+	return total
+}
+
+// jobIndexShard holds the job-to-workflow mappings whose keys hash to it.
+type jobIndexShard struct {
+	mu   sync.RWMutex
+	jobs map[string]int
+}
+
+// jobIndex is a sharded map from job ID (or job name before the job starts) to
+// the ID of the workflow that owns it.
+type jobIndex struct {
+	shards [workflowShardCount]jobIndexShard
+}
+
+// newJobIndex creates an empty job index.
+func newJobIndex() *jobIndex {
+	idx := &jobIndex{}
+	for i := range idx.shards {
+		idx.shards[i].jobs = make(map[string]int)
+	}
+	return idx
+}
+
+func (idx *jobIndex) shardFor(jobID string) *jobIndexShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(jobID))
+	return &idx.shards[h.Sum32()%workflowShardCount]
+}
+
+// get returns the workflow ID mapped to a job.
+func (idx *jobIndex) get(jobID string) (int, bool) {
+	shard := idx.shardFor(jobID)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	workflowID, exists := shard.jobs[jobID]
+	return workflowID, exists
+}
+
+// set maps a job to a workflow ID.
+func (idx *jobIndex) set(jobID string, workflowID int) {
+	shard := idx.shardFor(jobID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.jobs[jobID] = workflowID
+}
+
+// delete removes a job mapping.
+func (idx *jobIndex) delete(jobID string) {
+	shard := idx.shardFor(jobID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	delete(shard.jobs, jobID)
+}
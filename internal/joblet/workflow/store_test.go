@@ -0,0 +1,288 @@
+package workflow
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// newChainJobs builds a two-job workflow where "second" waits for "first".
+func newChainJobs() map[string]*JobDependency {
+	return map[string]*JobDependency{
+		"first": {
+			JobID:        "first",
+			InternalName: "first",
+			Status:       domain.StatusPending,
+		},
+		"second": {
+			JobID:        "second",
+			InternalName: "second",
+			Requirements: []Requirement{
+				{Type: RequirementSimple, JobID: "first", Status: "COMPLETED"},
+			},
+			Status: domain.StatusPending,
+		},
+	}
+}
+
+func TestJobIndex(t *testing.T) {
+	idx := newJobIndex()
+
+	idx.set("job-a", 1)
+	idx.set("job-b", 2)
+
+	if id, ok := idx.get("job-a"); !ok || id != 1 {
+		t.Errorf("get(job-a) = %d, %v, want 1, true", id, ok)
+	}
+
+	idx.delete("job-a")
+	if _, ok := idx.get("job-a"); ok {
+		t.Error("get(job-a) found a deleted mapping")
+	}
+
+	if id, ok := idx.get("job-b"); !ok || id != 2 {
+		t.Errorf("get(job-b) = %d, %v, want 2, true", id, ok)
+	}
+}
+
+func TestWorkflowTable(t *testing.T) {
+	table := newWorkflowTable()
+
+	for id := 1; id <= workflowShardCount*2; id++ {
+		table.put(id, newWorkflowEntry(&WorkflowState{ID: id}))
+	}
+
+	if n := table.len(); n != workflowShardCount*2 {
+		t.Errorf("len() = %d, want %d", n, workflowShardCount*2)
+	}
+
+	if n := len(table.entries()); n != workflowShardCount*2 {
+		t.Errorf("len(entries()) = %d, want %d", n, workflowShardCount*2)
+	}
+
+	entry, ok := table.get(workflowShardCount + 1)
+	if !ok || entry.state.ID != workflowShardCount+1 {
+		t.Errorf("get(%d) returned wrong entry", workflowShardCount+1)
+	}
+
+	if _, ok := table.get(0); ok {
+		t.Error("get(0) found a workflow that was never stored")
+	}
+}
+
+func TestWorkflowEntry_SnapshotIsDetached(t *testing.T) {
+	dr := NewDependencyResolver()
+	workflowID, err := dr.CreateWorkflow("wf", newChainJobs(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+
+	snapshot, err := dr.GetWorkflowStatus(workflowID)
+	if err != nil {
+		t.Fatalf("GetWorkflowStatus() error = %v", err)
+	}
+
+	dr.OnJobStateChange("first", domain.StatusRunning)
+
+	if got := snapshot.Jobs["first"].Status; got != domain.StatusPending {
+		t.Errorf("snapshot job status changed to %v after state change", got)
+	}
+}
+
+func TestDependencyResolver_JobNamesScopedPerWorkflow(t *testing.T) {
+	dr := NewDependencyResolver()
+
+	first, err := dr.CreateWorkflow("wf-1", newChainJobs(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+	if err := dr.renameJob("first", "wf1-first"); err != nil {
+		t.Fatalf("renameJob() error = %v", err)
+	}
+	if err := dr.renameJob("second", "wf1-second"); err != nil {
+		t.Fatalf("renameJob() error = %v", err)
+	}
+
+	second, err := dr.CreateWorkflow("wf-2", newChainJobs(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+
+	dr.OnJobStateChange("wf1-first", domain.StatusCompleted)
+
+	if ready := dr.GetReadyJobs(first); len(ready) != 1 || ready[0] != "wf1-second" {
+		t.Errorf("GetReadyJobs(%d) = %v, want [wf1-second]", first, ready)
+	}
+
+	// Completing "first" in one workflow must not release "second" in another
+	if ready := dr.GetReadyJobs(second); len(ready) != 1 || ready[0] != "first" {
+		t.Errorf("GetReadyJobs(%d) = %v, want [first]", second, ready)
+	}
+}
+
+func TestDependencyResolver_ExpressionCacheInvalidated(t *testing.T) {
+	dr := NewDependencyResolver()
+
+	jobs := map[string]*JobDependency{
+		"build": {JobID: "build", InternalName: "build", Status: domain.StatusPending},
+		"notify": {
+			JobID:        "notify",
+			InternalName: "notify",
+			Requirements: []Requirement{
+				{Type: RequirementExpression, Expression: "build IN (COMPLETED,FAILED)"},
+			},
+			Status: domain.StatusPending,
+		},
+	}
+
+	workflowID, err := dr.CreateWorkflow("wf", jobs, []string{"build", "notify"})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+
+	if ready := dr.GetReadyJobs(workflowID); len(ready) != 1 || ready[0] != "build" {
+		t.Fatalf("GetReadyJobs() = %v, want [build]", ready)
+	}
+
+	dr.OnJobStateChange("build", domain.StatusRunning)
+	dr.OnJobStateChange("build", domain.StatusFailed)
+
+	ready := dr.GetReadyJobs(workflowID)
+	if len(ready) != 1 || ready[0] != "notify" {
+		t.Errorf("GetReadyJobs() = %v, want [notify] once the expression holds", ready)
+	}
+}
+
+func TestWorkflowManager_ConcurrentWorkflows(t *testing.T) {
+	const workflows = 200
+
+	wm := NewWorkflowManager()
+
+	var wg sync.WaitGroup
+	wg.Add(workflows)
+	for i := 0; i < workflows; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			jobs := map[string]*JobDependency{
+				fmt.Sprintf("wf%d-first", i): {
+					JobID:        fmt.Sprintf("wf%d-first", i),
+					InternalName: "first",
+					Status:       domain.StatusPending,
+				},
+			}
+			workflowID, err := wm.CreateWorkflow(fmt.Sprintf("wf-%d", i), jobs, nil)
+			if err != nil {
+				t.Errorf("CreateWorkflow() error = %v", err)
+				return
+			}
+
+			for _, jobID := range wm.GetReadyJobs(workflowID) {
+				wm.OnJobStateChange(jobID, domain.StatusRunning)
+				wm.OnJobStateChange(jobID, domain.StatusCompleted)
+			}
+			_ = wm.ListWorkflows()
+		}(i)
+	}
+	wg.Wait()
+
+	list := wm.ListWorkflows()
+	if len(list) != workflows {
+		t.Fatalf("len(ListWorkflows()) = %d, want %d", len(list), workflows)
+	}
+	for _, wf := range list {
+		if wf.Status != WorkflowCompleted {
+			t.Errorf("workflow %d status = %v, want %v", wf.ID, wf.Status, WorkflowCompleted)
+		}
+	}
+}
+
+// newBenchmarkManager creates a manager holding the given number of running
+// workflows, each a fan-out of jobs waiting on a single root job.
+func newBenchmarkManager(b *testing.B, workflows, jobsPerWorkflow int) (*WorkflowManager, []int) {
+	b.Helper()
+
+	wm := NewWorkflowManager()
+	ids := make([]int, 0, workflows)
+	for w := 0; w < workflows; w++ {
+		jobs := make(map[string]*JobDependency, jobsPerWorkflow)
+		root := fmt.Sprintf("wf%d-root", w)
+		jobs[root] = &JobDependency{JobID: root, InternalName: "root", Status: domain.StatusPending}
+		for j := 1; j < jobsPerWorkflow; j++ {
+			id := fmt.Sprintf("wf%d-job%d", w, j)
+			jobs[id] = &JobDependency{
+				JobID:        id,
+				InternalName: fmt.Sprintf("job%d", j),
+				Requirements: []Requirement{{Type: RequirementSimple, JobID: "root", Status: "COMPLETED"}},
+				Status:       domain.StatusPending,
+			}
+		}
+
+		workflowID, err := wm.CreateWorkflow(fmt.Sprintf("wf-%d", w), jobs, nil)
+		if err != nil {
+			b.Fatalf("CreateWorkflow() error = %v", err)
+		}
+		wm.OnJobStateChange(root, domain.StatusRunning)
+		ids = append(ids, workflowID)
+	}
+	return wm, ids
+}
+
+// benchmarkOrchestrationTick measures one orchestration tick per workflow: the
+// readiness check and status read performed by the workflow service, while other
+// goroutines drive ticks for other workflows in parallel. With per-workflow locks
+// the time per tick should stay flat as the number of workflows grows.
+func benchmarkOrchestrationTick(b *testing.B, workflows int) {
+	wm, ids := newBenchmarkManager(b, workflows, 8)
+
+	var next atomic.Uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			workflowID := ids[next.Add(1)%uint64(len(ids))]
+			_ = wm.GetReadyJobs(workflowID)
+			if _, err := wm.GetWorkflowStatus(workflowID); err != nil {
+				b.Errorf("GetWorkflowStatus() error = %v", err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkWorkflowManager_OrchestrationTick100(b *testing.B) {
+	benchmarkOrchestrationTick(b, 100)
+}
+
+func BenchmarkWorkflowManager_OrchestrationTick1000(b *testing.B) {
+	benchmarkOrchestrationTick(b, 1000)
+}
+
+func BenchmarkWorkflowManager_OrchestrationTick10000(b *testing.B) {
+	benchmarkOrchestrationTick(b, 10000)
+}
+
+// BenchmarkWorkflowManager_MixedLoad interleaves job state changes with
+// readiness checks across 1000 workflows, the pattern seen while many
+// workflows run at once.
+func BenchmarkWorkflowManager_MixedLoad(b *testing.B) {
+	wm, ids := newBenchmarkManager(b, 1000, 8)
+
+	var next atomic.Uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := next.Add(1)
+			w := n % uint64(len(ids))
+			if n%4 == 0 {
+				wm.OnJobStateChange(fmt.Sprintf("wf%d-root", w), domain.StatusRunning)
+				continue
+			}
+			_ = wm.GetReadyJobs(ids[w])
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// JobTiming holds when a workflow job may start, from the schedule,
+// not_before and window fields of its spec, and how long it is expected to
+// run. Times of day are in the server's time zone.
+type JobTiming struct {
+	Delay     time.Duration // After the job's requirements are met
+	NotBefore time.Time     // Zero = no earliest start
+	Window    *TimeWindow   // Nil = any time of day
+	Estimate  time.Duration // Expected run time, for planning only (0 = unknown)
+}
+
+// TimeWindow is a daily time window in minutes after midnight. A window
+// whose end is before its start spans midnight (e.g., 22:00-06:00).
+type TimeWindow struct {
+	Start int
+	End   int
+}
+
+// ParseJobTiming parses a job's timing fields. A not_before time of day is
+// taken on the day the workflow was submitted.
+func ParseJobTiming(spec types.JobSpec, submitted time.Time) (JobTiming, error) {
+	var timing JobTiming
+
+	if spec.Schedule != "" {
+		delay, err := time.ParseDuration(strings.TrimSpace(spec.Schedule))
+		if err != nil || delay <= 0 {
+			return timing, fmt.Errorf("invalid schedule %q: expected a positive delay such as \"30m\" or \"2h\"", spec.Schedule)
+		}
+		timing.Delay = delay
+	}
+
+	if spec.NotBefore != "" {
+		notBefore, err := parseNotBefore(strings.TrimSpace(spec.NotBefore), submitted)
+		if err != nil {
+			return timing, err
+		}
+		timing.NotBefore = notBefore
+	}
+
+	if spec.Window != "" {
+		window, err := ParseTimeWindow(spec.Window)
+		if err != nil {
+			return timing, err
+		}
+		timing.Window = window
+	}
+
+	if spec.Estimate != "" {
+		estimate, err := time.ParseDuration(strings.TrimSpace(spec.Estimate))
+		if err != nil || estimate <= 0 {
+			return timing, fmt.Errorf("invalid estimate %q: expected a positive duration such as \"20m\"", spec.Estimate)
+		}
+		timing.Estimate = estimate
+	}
+
+	return timing, nil
+}
+
+// IsZero reports whether the job may start as soon as it is ready. The
+// estimate does not delay the start.
+func (t JobTiming) IsZero() bool {
+	return t.Delay == 0 && t.NotBefore.IsZero() && t.Window == nil
+}
+
+// StartTime returns the earliest time the job may start when its
+// requirements were met at ready
+func (t JobTiming) StartTime(ready time.Time) time.Time {
+	start := ready.Add(t.Delay)
+	if start.Before(t.NotBefore) {
+		start = t.NotBefore
+	}
+	if t.Window != nil {
+		start = t.Window.Next(start)
+	}
+	return start
+}
+
+// ParseTimeWindow parses a "HH:MM-HH:MM" window
+func ParseTimeWindow(spec string) (*TimeWindow, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q: expected \"HH:MM-HH:MM\"", spec)
+	}
+	start, err := parseClock(strings.TrimSpace(from))
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	end, err := parseClock(strings.TrimSpace(to))
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid window %q: start and end are equal", spec)
+	}
+	return &TimeWindow{Start: start, End: end}, nil
+}
+
+// Contains reports whether t falls in the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// Next returns t when it falls in the window, otherwise the window's next
+// opening
+func (w TimeWindow) Next(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	opening := time.Date(t.Year(), t.Month(), t.Day(), w.Start/60, w.Start%60, 0, 0, t.Location())
+	if opening.Before(t) {
+		opening = time.Date(t.Year(), t.Month(), t.Day()+1, w.Start/60, w.Start%60, 0, 0, t.Location())
+	}
+	return opening
+}
+
+// parseNotBefore parses an RFC3339 time or a time of day on submitted's day
+func parseNotBefore(spec string, submitted time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, spec); err == nil {
+		return t, nil
+	}
+	minute, err := parseClock(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid not_before %q: expected RFC3339 or \"HH:MM\"", spec)
+	}
+	return time.Date(submitted.Year(), submitted.Month(), submitted.Day(), minute/60, minute%60, 0, 0, submitted.Location()), nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(spec string) (int, error) {
+	h, m, ok := strings.Cut(spec, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time of day %q", spec)
+	}
+	hour, errH := strconv.Atoi(h)
+	minute, errM := strconv.Atoi(m)
+	if errH != nil || errM != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time of day %q", spec)
+	}
+	return hour*60 + minute, nil
+}
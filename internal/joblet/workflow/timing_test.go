@@ -0,0 +1,64 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.March, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestJobTimingStartTime(t *testing.T) {
+	submitted := at(10, 9, 0)
+
+	tests := []struct {
+		name  string
+		spec  types.JobSpec
+		ready time.Time
+		want  time.Time
+	}{
+		{"no timing", types.JobSpec{}, at(10, 9, 5), at(10, 9, 5)},
+		{"delay", types.JobSpec{Schedule: "90m"}, at(10, 9, 5), at(10, 10, 35)},
+		{"not before time of day", types.JobSpec{NotBefore: "22:00"}, at(10, 9, 5), at(10, 22, 0)},
+		{"not before already passed", types.JobSpec{NotBefore: "08:00"}, at(10, 9, 5), at(10, 9, 5)},
+		{"not before RFC3339", types.JobSpec{NotBefore: "2026-03-12T01:30:00Z"}, at(10, 9, 5), at(12, 1, 30)},
+		{"inside window", types.JobSpec{Window: "22:00-06:00"}, at(11, 2, 0), at(11, 2, 0)},
+		{"before window", types.JobSpec{Window: "22:00-06:00"}, at(10, 9, 5), at(10, 22, 0)},
+		{"window end is exclusive", types.JobSpec{Window: "01:00-06:00"}, at(10, 6, 0), at(11, 1, 0)},
+		{"delay into window", types.JobSpec{Schedule: "14h", Window: "22:00-06:00"}, at(10, 9, 5), at(10, 23, 5)},
+		{"delay past window", types.JobSpec{Schedule: "1h", Window: "08:00-10:00"}, at(10, 9, 5), at(11, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timing, err := ParseJobTiming(tt.spec, submitted)
+			if err != nil {
+				t.Fatalf("ParseJobTiming() error = %v", err)
+			}
+			if got := timing.StartTime(tt.ready); !got.Equal(tt.want) {
+				t.Errorf("StartTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJobTimingErrors(t *testing.T) {
+	for _, spec := range []types.JobSpec{
+		{Schedule: "tonight"},
+		{Schedule: "-5m"},
+		{NotBefore: "25:00"},
+		{NotBefore: "tomorrow"},
+		{Window: "22:00"},
+		{Window: "22:00-22:00"},
+		{Window: "9-17"},
+		{Estimate: "a while"},
+		{Estimate: "0s"},
+	} {
+		if _, err := ParseJobTiming(spec, at(10, 9, 0)); err == nil {
+			t.Errorf("ParseJobTiming(%+v) succeeded, want error", spec)
+		}
+	}
+}
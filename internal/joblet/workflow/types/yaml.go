@@ -1,24 +1,48 @@
 // Package types defines data structures for workflow YAML parsing and job specifications.
 package types
 
+import "strings"
+
 // WorkflowYAML represents the complete structure of a workflow YAML file.
-// SIMPLIFIED: Removed global environment inheritance and secret_environment separation
-// to reduce complexity. Each job now defines its own complete environment.
+// Top-level environment and secrets are inherited by every job; a job's own
+// environment overrides them.
 // Example YAML:
 //
+//	environment:
+//	  NODE_ENV: "production"
+//	secrets:
+//	  DATABASE_URL: "postgres://..."  # Always secret, whatever the name
 //	jobs:
 //	  extract-data:
 //	    command: "python3"
 //	    args: ["extract.py"]
 //	    volumes: ["data-pipeline"]
 //	    environment:
-//	      NODE_ENV: "production"
+//	      EXTRACT_SOURCE: "${DATABASE_URL}/raw"
 //	      API_TOKEN: "secret-token"  # Use env var prefix or naming convention for secrets
 type WorkflowYAML struct {
 	// Name is an optional workflow name for better identification
 	Name string `yaml:"name,omitempty"`
 	// Description is an optional workflow description
 	Description string `yaml:"description,omitempty"`
+	// Group puts every job of the workflow in a named job group for bulk
+	// list and stop; defaults to the workflow UUID
+	Group string `yaml:"group,omitempty"`
+	// Environment is inherited by all jobs; secrets are detected by naming
+	// convention as in job environments
+	Environment map[string]string `yaml:"environment,omitempty"`
+	// Secrets are inherited by all jobs as secret environment variables
+	Secrets map[string]string `yaml:"secrets,omitempty"`
+	// CgroupParams are raw cgroup v2 files set for all jobs; a job's own
+	// resources.cgroup_params override them
+	CgroupParams map[string]string `yaml:"cgroup_params,omitempty"`
+	// Annotations are notes every job of the workflow carries, such as the
+	// ticket it runs for; rnx adds those of the CI pipeline it runs in
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// Defaults are settings of every job that does not set them itself
+	Defaults *JobDefaults `yaml:"defaults,omitempty"`
+	// Stages run in order; a stage starts once the previous one completed
+	Stages []StageSpec `yaml:"stages,omitempty"`
 	// Jobs maps job names to their specifications
 	// Key: job name (used for dependency references)
 	// Value: complete job specification
@@ -41,22 +65,158 @@ type JobSpec struct {
 	Uploads *JobUploads `yaml:"uploads"`
 	// Volumes lists the volumes to mount for data persistence
 	Volumes []string `yaml:"volumes"`
-	// Requires defines dependencies on other jobs (e.g., [{"job-name": "COMPLETED"}])
+	// Requires defines dependencies on other jobs (e.g., [{"job-name": "COMPLETED"}]),
+	// or dependency expressions (e.g., [{"expression": "a=COMPLETED OR b=FAILED"}])
 	Requires []map[string]string `yaml:"requires"`
 	// Resources specifies computational limits for the job
 	Resources JobResources `yaml:"resources"`
 	// Environment defines all environment variables for the job
 	// Use naming conventions for secrets (e.g., SECRET_ or _TOKEN suffix)
 	Environment map[string]string `yaml:"environment,omitempty"`
+	// Schedule delays the job once its requirements are met (e.g., "30m", "2h")
+	Schedule string `yaml:"schedule,omitempty"`
+	// NotBefore is the earliest start, as RFC3339 or a time of day on the day
+	// the workflow is submitted (e.g., "22:00")
+	NotBefore string `yaml:"not_before,omitempty"`
+	// Window restricts the start to a daily time window (e.g., "22:00-06:00")
+	Window string `yaml:"window,omitempty"`
+	// Estimate is the expected run time (e.g., "20m"), used by
+	// 'rnx workflow run --dry-run' to compute the critical path; not enforced
+	Estimate string `yaml:"estimate,omitempty"`
+	// Labels tag the job for bulk operations (e.g., {"env": "staging"})
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Stage puts the job in one of the workflow's stages
+	Stage string `yaml:"stage,omitempty"`
+	// IPC joins a named IPC channel: the jobs of the workflow naming the
+	// same channel share /dev/shm while they run
+	IPC string `yaml:"ipc,omitempty"`
+	// Inputs are objects the server downloads into the workspace before
+	// the job starts (e.g., "s3://bucket/key:/work/data.bin")
+	Inputs []string `yaml:"inputs,omitempty"`
+	// OutputTargets are workspace files the server uploads when the job
+	// completes (e.g., "/work/out/*.parquet:s3://bucket/results/${JOB_UUID}/")
+	OutputTargets []string `yaml:"output_targets,omitempty"`
+	// Egress restricts the destinations the job may connect to, rules
+	// such as "allow:api.internal:443" matched in order (e.g., ending
+	// with "deny:*")
+	Egress []string `yaml:"egress,omitempty"`
+	// Outputs publishes values of a JSON document the job writes, for the
+	// jobs that require it to reference as ${jobs.<job>.<VAR>}
+	Outputs *JobOutputs `yaml:"outputs,omitempty"`
+	// SecretFiles delivers secret variables of the job, from the workflow's
+	// secrets or named as secrets, as read-only tmpfs files instead of
+	// through the environment (e.g., {"/root/.aws/credentials": "AWS_CREDENTIALS"})
+	SecretFiles map[string]string `yaml:"secret_files,omitempty"`
+}
+
+// JobOutputs declares a JSON document a job writes and the values read from
+// it once the job ended. Jobs that require the job, directly or not, can
+// reference the values in their environment and args.
+// Example YAML:
+//
+//	jobs:
+//	  detect-changes:
+//	    command: "python3"
+//	    args: ["detect.py"]  # writes {"modules": [{"name": "api"}, ...]}
+//	    outputs:
+//	      file: "/work/outputs.json"
+//	      vars:
+//	        MODULES: "$.modules[*].name"
+//	  build:
+//	    command: "make"
+//	    args: ["${jobs.detect-changes.MODULES}"]
+//	    requires:
+//	      - detect-changes: "COMPLETED"
+type JobOutputs struct {
+	// File is the JSON document the job writes, under /work
+	File string `yaml:"file"`
+	// Vars maps variable names to JSONPath expressions into the document
+	Vars map[string]string `yaml:"vars"`
+}
+
+// StageSpec groups the jobs that name it in their stage field. Before hooks
+// run ahead of the stage's jobs, which only start when all of them
+// completed; after hooks run once the before hooks and the stage's jobs
+// ended, whatever their status, so teardown happens even when tests fail.
+// A failed hook fails the stage and cancels the stages after it.
+// Example YAML:
+//
+//	stages:
+//	  - name: test
+//	    before: [start-db]
+//	    after: [stop-db]
+//	jobs:
+//	  unit-tests:
+//	    command: "make"
+//	    args: ["test"]
+//	    stage: test
+type StageSpec struct {
+	// Name is referenced by the jobs' stage field
+	Name string `yaml:"name"`
+	// Before lists the jobs run before the stage's jobs
+	Before []string `yaml:"before,omitempty"`
+	// After lists the jobs run after the stage's jobs
+	After []string `yaml:"after,omitempty"`
+}
+
+// JobDefaults are the settings a workflow's jobs get unless they set their
+// own. Resources are defaulted field by field and environment variable by
+// variable; the defaults' environment overrides the workflow's top-level one.
+// Example YAML:
+//
+//	defaults:
+//	  runtime: "python-3.11-ml"
+//	  network: "bridge"
+//	  resources:
+//	    max_memory: 2048
+//	  environment:
+//	    LOG_LEVEL: "info"
+//	jobs:
+//	  train:
+//	    command: "python3"
+//	    args: ["train.py"]
+//	    resources:
+//	      max_memory: 8192  # Overrides the default
+type JobDefaults struct {
+	// Runtime is the runtime of jobs without one
+	Runtime string `yaml:"runtime,omitempty"`
+	// Network is the network of jobs without one
+	Network string `yaml:"network,omitempty"`
+	// Resources are the limits of jobs that do not set them
+	Resources JobResources `yaml:"resources,omitempty"`
+	// Environment holds the variables of jobs that do not set them
+	Environment map[string]string `yaml:"environment,omitempty"`
 }
 
+// RequiresExpressionKey is the key of a requires entry that holds a
+// dependency expression rather than a job name
+const RequiresExpressionKey = "expression"
+
 // JobUploads specifies which files should be uploaded to the job's execution environment.
-// The Files slice contains relative file paths that will be uploaded from the client
-// and made available in the job's working directory during execution.
-// Essential for workflows where jobs need access to scripts, data files, or configurations.
+// Paths are relative to the workflow YAML and keep that relative path in the job's
+// working directory, unless a mapping gives them another one. Example:
+//
+//	uploads:
+//	  files: [main.py, "src/**/*.py"]
+//	  directories: [config]
+//	  mappings:
+//	    - local: dist/app.jar
+//	      remote: app.jar
 type JobUploads struct {
-	// Files lists the file paths to upload to the job's working directory
+	// Files lists the file paths to upload to the job's working directory. An
+	// entry with *, ? or [ is a pattern, in which ** matches any directories.
 	Files []string `yaml:"files"`
+	// Directories are uploaded with every file under them
+	Directories []string `yaml:"directories,omitempty"`
+	// Mappings upload a file, or a directory's files, to another path in the job
+	Mappings []UploadMapping `yaml:"mappings,omitempty"`
+}
+
+// UploadMapping uploads a local file or directory to a path of its own in the
+// job's working directory
+type UploadMapping struct {
+	Local  string `yaml:"local"`
+	Remote string `yaml:"remote"`
 }
 
 // JobResources defines the computational resource limits for a job's execution.
@@ -67,6 +227,10 @@ type JobUploads struct {
 // - CPUCores: Specific CPU cores to bind to (e.g., "0-3" or "0,2,4")
 // - GPUCount: Number of GPUs to allocate (requires GPU support enabled)
 // - GPUMemoryMB: Minimum GPU memory requirement in megabytes
+// - ShmSize: Size of the /dev/shm tmpfs (e.g., "2GB")
+// - TmpSize: Size of the /tmp tmpfs, separate from the work dir quota (e.g., "512MB")
+// - Scratch: Scratch device of the server holding the work directory (e.g., "nvme")
+// - CgroupParams: Raw cgroup v2 files the server allows (e.g., {"cpu.weight": "200"})
 // These limits are enforced by the job execution system using cgroups and device controllers.
 type JobResources struct {
 	// MaxCPU limits CPU usage as a percentage (0-100)
@@ -81,4 +245,26 @@ type JobResources struct {
 	GPUCount int `yaml:"gpu_count"`
 	// GPUMemoryMB specifies minimum GPU memory requirement in MB (0 = any)
 	GPUMemoryMB int `yaml:"gpu_memory_mb"`
+	// ShmSize specifies the /dev/shm size (e.g., "2GB"; empty = server default)
+	ShmSize string `yaml:"shm_size,omitempty"`
+	// TmpSize specifies the /tmp size (e.g., "512MB"; empty = server default)
+	TmpSize string `yaml:"tmp_size,omitempty"`
+	// Scratch places the work directory on a scratch device the server
+	// configures (filesystem.scratch), e.g. "nvme"; empty = default work dir
+	Scratch string `yaml:"scratch,omitempty"`
+	// CgroupParams sets raw cgroup v2 files listed in the server's
+	// cgroup.allowedParams (e.g., {"io.latency": "8:0 target=10"})
+	CgroupParams map[string]string `yaml:"cgroup_params,omitempty"`
+}
+
+// IsSecretKey reports whether an environment variable is a secret by naming
+// convention: keys starting with "SECRET_" or ending with "_TOKEN", "_KEY",
+// "_PASSWORD" or "_SECRET" are passed to jobs as secret environment.
+func IsSecretKey(key string) bool {
+	key = strings.ToUpper(key)
+	return strings.HasPrefix(key, "SECRET_") ||
+		strings.HasSuffix(key, "_TOKEN") ||
+		strings.HasSuffix(key, "_KEY") ||
+		strings.HasSuffix(key, "_PASSWORD") ||
+		strings.HasSuffix(key, "_SECRET")
 }
@@ -0,0 +1,267 @@
+package workflow
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// UploadTarget is a file a job uploads: the workflow file the client sent
+// and where it lands in the job's working directory
+type UploadTarget struct {
+	Source string
+	Path   string
+}
+
+// IsUploadPattern reports whether a files entry of uploads is a pattern
+func IsUploadPattern(entry string) bool {
+	return strings.ContainsAny(entry, "*?[")
+}
+
+// MatchUploadPattern reports whether the slash-separated relative name
+// matches pattern, whose ** segments match any number of directories
+func MatchUploadPattern(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// UploadSources lists the workflow files the uploads of a job need, for the
+// client to send. list returns the files under a directory relative to the
+// workflow, recursively and as slash paths, or none when it does not exist.
+func UploadSources(u *types.JobUploads, list func(dir string) ([]string, error)) ([]string, error) {
+	if u == nil {
+		return nil, nil
+	}
+	if err := validateUploads(u); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var sources []string
+	add := func(files ...string) {
+		for _, file := range files {
+			if !seen[file] {
+				seen[file] = true
+				sources = append(sources, file)
+			}
+		}
+	}
+	listDir := func(dir string) ([]string, error) {
+		files, err := list(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		return files, nil
+	}
+
+	for _, entry := range u.Files {
+		if !IsUploadPattern(entry) {
+			add(entry)
+			continue
+		}
+		files, err := listDir(patternRoot(entry))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if MatchUploadPattern(entry, file) {
+				add(file)
+			}
+		}
+	}
+	for _, dir := range u.Directories {
+		files, err := listDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		add(files...)
+	}
+	for _, m := range u.Mappings {
+		files, err := listDir(m.Local)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			files = []string{m.Local}
+		}
+		add(files...)
+	}
+	return sources, nil
+}
+
+// UploadTargets lists the files a job uploads out of the workflow files the
+// client sent, in the order of the uploads. Every entry must match a file,
+// and no two may land on the same path from different files.
+func UploadTargets(u *types.JobUploads, files []string) ([]UploadTarget, error) {
+	if u == nil {
+		return nil, nil
+	}
+	if err := validateUploads(u); err != nil {
+		return nil, err
+	}
+	sent := make(map[string]bool, len(files))
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+	for _, file := range sorted {
+		sent[file] = true
+	}
+
+	var targets []UploadTarget
+	from := make(map[string]string)
+	add := func(source, target string) error {
+		if previous, exists := from[target]; exists {
+			if previous != source {
+				return fmt.Errorf("%s is uploaded from both %s and %s", target, previous, source)
+			}
+			return nil
+		}
+		from[target] = source
+		targets = append(targets, UploadTarget{Source: source, Path: target})
+		return nil
+	}
+	under := func(dir string) []string {
+		prefix := path.Clean(dir) + "/"
+		var matches []string
+		for _, file := range sorted {
+			if prefix == "./" || strings.HasPrefix(file, prefix) {
+				matches = append(matches, file)
+			}
+		}
+		return matches
+	}
+
+	for _, entry := range u.Files {
+		if !IsUploadPattern(entry) {
+			if !sent[entry] {
+				return nil, fmt.Errorf("file %s not found in uploaded files", entry)
+			}
+			if err := add(entry, entry); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		matched := false
+		for _, file := range sorted {
+			if MatchUploadPattern(entry, file) {
+				matched = true
+				if err := add(file, file); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("pattern %s matches no uploaded file", entry)
+		}
+	}
+	for _, dir := range u.Directories {
+		matches := under(dir)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("directory %s has no uploaded files", dir)
+		}
+		for _, file := range matches {
+			if err := add(file, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, m := range u.Mappings {
+		if sent[m.Local] {
+			if err := add(m.Local, path.Clean(m.Remote)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		matches := under(m.Local)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("file %s mapped to %s not found in uploaded files", m.Local, m.Remote)
+		}
+		for _, file := range matches {
+			rel := strings.TrimPrefix(file, path.Clean(m.Local)+"/")
+			if path.Clean(m.Local) == "." {
+				rel = file
+			}
+			if err := add(file, path.Join(m.Remote, rel)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return targets, nil
+}
+
+// validateUploads checks that patterns, directories and mappings are
+// relative to the workflow, like files, and mappings land in the job's
+// working directory
+func validateUploads(u *types.JobUploads) error {
+	relative := func(kind, p string) error {
+		if p == "" {
+			return fmt.Errorf("upload %s must not be empty", kind)
+		}
+		if path.IsAbs(p) {
+			return fmt.Errorf("upload %s %s must be relative to the workflow", kind, p)
+		}
+		return nil
+	}
+	for _, entry := range u.Files {
+		if !IsUploadPattern(entry) {
+			continue
+		}
+		if _, err := path.Match(strings.ReplaceAll(entry, "**", "*"), ""); err != nil {
+			return fmt.Errorf("invalid upload pattern %s: %w", entry, err)
+		}
+		if err := relative("pattern", entry); err != nil {
+			return err
+		}
+	}
+	for _, dir := range u.Directories {
+		if err := relative("directory", dir); err != nil {
+			return err
+		}
+	}
+	for _, m := range u.Mappings {
+		if err := relative("mapping local", m.Local); err != nil {
+			return err
+		}
+		remote := path.Clean(m.Remote)
+		if m.Remote == "" || path.IsAbs(remote) || remote == "." || remote == ".." || strings.HasPrefix(remote, "../") {
+			return fmt.Errorf("upload mapping remote %q must name a path in the job's working directory", m.Remote)
+		}
+	}
+	return nil
+}
+
+// patternRoot returns the directory above the first segment of a pattern
+// with wildcards, the directory its matches are listed from
+func patternRoot(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if IsUploadPattern(segment) {
+			if i == 0 {
+				return "."
+			}
+			return path.Join(segments[:i]...)
+		}
+	}
+	return path.Dir(pattern)
+}
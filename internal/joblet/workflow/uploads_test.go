@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+func TestMatchUploadPattern(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"src/**/*.py", "src/app.py", true},
+		{"src/**/*.py", "src/pkg/sub/util.py", true},
+		{"src/**/*.py", "src/pkg/util.pyc", false},
+		{"src/**/*.py", "tests/app.py", false},
+		{"*.py", "app.py", true},
+		{"*.py", "src/app.py", false},
+		{"**/*.yml", "config.yml", true},
+		{"data/file?.csv", "data/file1.csv", true},
+		{"data/**", "data/a/b.csv", true},
+	}
+	for _, tt := range tests {
+		if got := MatchUploadPattern(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchUploadPattern(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+// project is the files next to a workflow
+var project = []string{
+	"main.py",
+	"config/app.yml",
+	"config/db/schema.sql",
+	"dist/app.jar",
+	"dist/lib/dep.jar",
+	"src/app.py",
+	"src/pkg/util.py",
+	"src/pkg/util.pyc",
+}
+
+// listProject lists the files of the project under dir
+func listProject(dir string) ([]string, error) {
+	var files []string
+	for _, file := range project {
+		if dir == "." || file == dir || strings.HasPrefix(file, dir+"/") {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func TestUploadSourcesAndTargets(t *testing.T) {
+	uploads := &types.JobUploads{
+		Files:       []string{"main.py", "src/**/*.py"},
+		Directories: []string{"config"},
+		Mappings: []types.UploadMapping{
+			{Local: "dist/app.jar", Remote: "app.jar"},
+			{Local: "dist/lib", Remote: "lib/java"},
+		},
+	}
+
+	sources, err := UploadSources(uploads, listProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(sources)
+	wantSources := []string{"config/app.yml", "config/db/schema.sql", "dist/app.jar", "dist/lib/dep.jar", "main.py", "src/app.py", "src/pkg/util.py"}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Errorf("UploadSources() = %v, want %v", sources, wantSources)
+	}
+
+	targets, err := UploadTargets(uploads, sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []UploadTarget{
+		{Source: "main.py", Path: "main.py"},
+		{Source: "src/app.py", Path: "src/app.py"},
+		{Source: "src/pkg/util.py", Path: "src/pkg/util.py"},
+		{Source: "config/app.yml", Path: "config/app.yml"},
+		{Source: "config/db/schema.sql", Path: "config/db/schema.sql"},
+		{Source: "dist/app.jar", Path: "app.jar"},
+		{Source: "dist/lib/dep.jar", Path: "lib/java/dep.jar"},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Errorf("UploadTargets() = %v, want %v", targets, want)
+	}
+}
+
+func TestUploadTargetsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		uploads types.JobUploads
+		want    string
+	}{
+		{"missing file", types.JobUploads{Files: []string{"other.py"}}, "file other.py not found"},
+		{"pattern matching nothing", types.JobUploads{Files: []string{"src/**/*.go"}}, "matches no uploaded file"},
+		{"empty directory", types.JobUploads{Directories: []string{"docs"}}, "directory docs has no uploaded files"},
+		{"missing mapping", types.JobUploads{Mappings: []types.UploadMapping{{Local: "build/app", Remote: "app"}}}, "build/app mapped to app not found"},
+		{"two files on one path", types.JobUploads{Files: []string{"main.py"}, Mappings: []types.UploadMapping{{Local: "src/app.py", Remote: "main.py"}}}, "main.py is uploaded from both"},
+		{"absolute pattern", types.JobUploads{Files: []string{"/src/*.py"}}, "must be relative"},
+		{"absolute directory", types.JobUploads{Directories: []string{"/etc"}}, "must be relative"},
+		{"remote leaving the job", types.JobUploads{Mappings: []types.UploadMapping{{Local: "main.py", Remote: "../main.py"}}}, "working directory"},
+		{"remote without a path", types.JobUploads{Mappings: []types.UploadMapping{{Local: "main.py", Remote: "."}}}, "working directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UploadTargets(&tt.uploads, project)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("UploadTargets() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if targets, err := UploadTargets(nil, project); err != nil || targets != nil {
+		t.Errorf("UploadTargets(nil) = %v, %v", targets, err)
+	}
+}
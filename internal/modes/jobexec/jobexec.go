@@ -131,12 +131,6 @@ func (je *JobExecutor) processUploads(config *environment.JobConfig) error {
 
 // executeCommand uses fork to create a child process while keeping init as PID 1
 func (je *JobExecutor) executeCommand(config *environment.JobConfig) error {
-	// Resolve command path
-	commandPath, err := je.resolveCommandPath(config.Command)
-	if err != nil {
-		return errors.WrapConfigError("job", "command", err)
-	}
-
 	// Change to workspace if uploads were processed (use os.Chdir since we're in isolated namespace)
 	if je.platform.Getenv("JOB_HAS_UPLOADS") == "true" {
 		workDir := je.config.Filesystem.WorkspaceDir
@@ -151,16 +145,31 @@ func (je *JobExecutor) executeCommand(config *environment.JobConfig) error {
 		}
 	}
 
-	// Get current environment (already set up by parent process)
-	envv := je.platform.Environ()
+	// Deliver the uploaded stdin file, if any, to the command
+	if stdinPath := je.platform.Getenv("JOB_STDIN"); stdinPath != "" {
+		if err := redirectStdin(je.config.Filesystem.WorkspaceDir, stdinPath); err != nil {
+			return errors.WrapFilesystemError(stdinPath, "stdin", err)
+		}
+	}
 
-	// Load and apply runtime environment variables from /etc/runtime.env if it exists
+	// Get current environment (already set up by parent process), without
+	// the secrets the isolation already mounted as files
+	envv := withoutSecretFiles(je.platform.Environ())
+
+	// Layer the runtime environment variables from /joblet/runtime.env, if it
+	// exists, underneath the job's environment, which already carries its PATH
 	runtimeEnv, err := je.loadRuntimeEnvironment()
 	if err == nil && len(runtimeEnv) > 0 {
-		// Apply runtime environment variables
-		envv = append(envv, runtimeEnv...)
 		je.logger.Debug("applied runtime environment variables", "count", len(runtimeEnv))
 	}
+	envv = environment.Resolve(runtimeEnv, envv)
+	path, _ := environment.Lookup(envv, "PATH")
+
+	// Resolve command path
+	commandPath, err := je.resolveCommandPath(config.Command, path)
+	if err != nil {
+		return errors.WrapConfigError("job", "command", err)
+	}
 
 	// Executing job command
 	// About to exec to replace init process with job command
@@ -168,6 +177,19 @@ func (je *JobExecutor) executeCommand(config *environment.JobConfig) error {
 	// Prepare arguments for exec - argv[0] should be the command name
 	argv := append([]string{commandPath}, config.Args...)
 
+	// Profiling: run the command under strace/perf instead
+	if tool := je.platform.Getenv("JOB_PROFILE"); tool != "" {
+		toolPath, err := je.resolveCommandPath(tool, path)
+		if err != nil {
+			return errors.WrapConfigError("job", "profile", fmt.Errorf("%s is not installed on this node: %w", tool, err))
+		}
+		if argv, err = profileCommand(tool, toolPath, commandPath, config.Args, je.config.Profiling.MaxOutputBytes); err != nil {
+			return errors.WrapConfigError("job", "profile", err)
+		}
+		commandPath = argv[0]
+		je.logger.Debug("profiling job command", "profiler", tool)
+	}
+
 	// About to exec to replace init process
 
 	// Use exec to replace the current process (init) with the job command
@@ -178,38 +200,47 @@ func (je *JobExecutor) executeCommand(config *environment.JobConfig) error {
 	return fmt.Errorf("execution failed: %w", err)
 }
 
-// resolveCommandPath resolves the full path for a command
-func (je *JobExecutor) resolveCommandPath(command string) (string, error) {
-	// Check if absolute path
-	if filepath.IsAbs(command) {
+// withoutSecretFiles drops the JOB_SECRET_FILE* variables carrying the
+// secret files to the init
+func withoutSecretFiles(envv []string) []string {
+	kept := envv[:0:0]
+	for _, entry := range envv {
+		if !strings.HasPrefix(entry, "JOB_SECRET_FILE") {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// resolveCommandPath resolves the full path for a command from the job's
+// PATH, falling back to the common locations
+func (je *JobExecutor) resolveCommandPath(command, path string) (string, error) {
+	// Absolute paths and paths relative to the working directory are used as is
+	if strings.Contains(command, "/") {
 		return command, nil
 	}
 
-	// Try common locations first - check /usr/local/bin first for runtime binaries
-	// We check these first because PATH may not be set correctly in the chroot environment
-	commonPaths := []string{
-		filepath.Join("/usr/local/bin", command), // Check runtime location first
+	var checked []string
+	for _, dir := range filepath.SplitList(path) {
+		checked = append(checked, filepath.Join(dir, command))
+	}
+	// Common locations, in case the job's PATH misses them
+	checked = append(checked,
+		filepath.Join("/usr/local/bin", command),
 		filepath.Join("/usr/bin", command),
 		filepath.Join("/bin", command),
 		filepath.Join("/sbin", command),
 		filepath.Join("/usr/sbin", command),
-	}
+	)
 
-	for _, path := range commonPaths {
-		if _, err := je.platform.Stat(path); err == nil {
-			// Resolved command at path
-			return path, nil
+	for _, candidate := range checked {
+		if info, err := je.platform.Stat(candidate); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return candidate, nil
 		}
 	}
 
-	// Fall back to PATH lookup if not found in common locations
-	if path, err := je.platform.LookPath(command); err == nil {
-		return path, nil
-	}
-
-	// Log what we checked for debugging
-	je.logger.Debug("command not found in any location", "command", command, "checked", commonPaths)
-	return "", fmt.Errorf("%w: %s", errors.ErrRuntimeNotFound, command)
+	je.logger.Debug("command not found in any location", "command", command, "checked", checked)
+	return "", fmt.Errorf("%w: %s (searched PATH=%s)", errors.ErrRuntimeNotFound, command, path)
 }
 
 // SetupCgroup sets up cgroup constraints (called before executing)
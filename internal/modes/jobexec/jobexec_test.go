@@ -0,0 +1,56 @@
+//go:build linux
+
+package jobexec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	pkgerrors "github.com/ehsaniara/joblet/pkg/errors"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/pkg/platform"
+)
+
+func TestResolveCommandPathUsesJobPath(t *testing.T) {
+	runtimeBin, jobBin := t.TempDir(), t.TempDir()
+	for _, dir := range []string{runtimeBin, jobBin} {
+		if err := os.WriteFile(filepath.Join(dir, "joblet-test-tool"), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Not executable, so it is skipped
+	if err := os.WriteFile(filepath.Join(jobBin, "joblet-test-data"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	je := NewJobExecutor(platform.NewPlatform(), logger.New(), &config.Config{})
+	path := runtimeBin + ":" + jobBin
+
+	got, err := je.resolveCommandPath("joblet-test-tool", path)
+	if err != nil || got != filepath.Join(runtimeBin, "joblet-test-tool") {
+		t.Errorf("resolveCommandPath() = %q, %v; want the first PATH entry", got, err)
+	}
+	if got, err := je.resolveCommandPath("./run.sh", path); err != nil || got != "./run.sh" {
+		t.Errorf("relative path = %q, %v", got, err)
+	}
+	if got, err := je.resolveCommandPath("sh", ""); err != nil || got == "" {
+		t.Errorf("common locations fallback = %q, %v", got, err)
+	}
+	if _, err := je.resolveCommandPath("joblet-test-data", path); !errors.Is(err, pkgerrors.ErrRuntimeNotFound) {
+		t.Errorf("non-executable file: got %v", err)
+	}
+}
+
+func TestWithoutSecretFiles(t *testing.T) {
+	envv := []string{"PATH=/bin", "JOB_SECRET_FILES_COUNT=1", "JOB_SECRET_FILE_0=/run/token", "JOB_SECRET_FILE_0_DATA=s3cr3t", "JOB_ID=1"}
+	got := withoutSecretFiles(envv)
+	if len(got) != 2 || got[0] != "PATH=/bin" || got[1] != "JOB_ID=1" {
+		t.Errorf("withoutSecretFiles() = %v", got)
+	}
+	if envv[1] != "JOB_SECRET_FILES_COUNT=1" {
+		t.Error("withoutSecretFiles() changed its argument")
+	}
+}
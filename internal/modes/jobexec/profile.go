@@ -0,0 +1,57 @@
+//go:build linux
+
+package jobexec
+
+import (
+	"fmt"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+)
+
+// profileShell runs the profiler script. It stays in the foreground after the
+// job command exits to append the profile to the job output.
+const profileShell = "/bin/sh"
+
+// profileScript runs "$@" under the profiler named by $1, then writes the
+// profile between the begin and end markers and exits with the job's code.
+// %[1]s is the profiler command line, %[2]s the command that renders the
+// profile to $out/profile.txt, %[3]d the output limit in bytes.
+const profileScript = `tool=$1; shift
+out=${TMPDIR:-/tmp}/.joblet-profile
+mkdir -p "$out" || exit 1
+%[1]s
+rc=$?
+%[2]s
+printf '\n%[4]s%[5]s\n'
+head -c %[3]d "$out/profile.txt"
+size=$(wc -c < "$out/profile.txt")
+if [ "$size" -gt %[3]d ]; then printf '\n[profile truncated from %%s to %[3]d bytes]' "$size"; fi
+printf '\n%[6]s\n'
+exit $rc`
+
+// profileCommand builds the argv that runs commandPath with args under the
+// profiler at toolPath. The profile is appended to the job output, capped at
+// maxBytes (the default when unset).
+func profileCommand(tool, toolPath, commandPath string, args []string, maxBytes int64) ([]string, error) {
+	if maxBytes <= 0 {
+		maxBytes = config.DefaultConfig.Profiling.MaxOutputBytes
+	}
+
+	var record, render string
+	switch tool {
+	case constants.ProfileStrace:
+		// -C prints the per-syscall summary after the full trace
+		record = `"$tool" -f -tt -T -C -o "$out/profile.txt" -- "$@"`
+		render = `:`
+	case constants.ProfilePerf:
+		record = `"$tool" record -g -o "$out/perf.data" -- "$@"`
+		render = `"$tool" report --stdio -i "$out/perf.data" > "$out/profile.txt" 2>&1`
+	default:
+		return nil, fmt.Errorf("unsupported profiler: %s", tool)
+	}
+
+	script := fmt.Sprintf(profileScript, record, render, maxBytes, constants.ProfileBeginMarker, tool, constants.ProfileEndMarker)
+	argv := []string{profileShell, "-c", script, "joblet-profile", toolPath, commandPath}
+	return append(argv, args...), nil
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package jobexec
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/constants"
+)
+
+// fakeProfiler stands in for strace: it writes a trace to the -o file and
+// runs the command after "--".
+const fakeProfiler = `#!/bin/sh
+while [ "$1" != "--" ]; do
+  if [ "$1" = "-o" ]; then shift; out=$1; fi
+  shift
+done
+shift
+echo "execve($1)" > "$out"
+"$@"
+`
+
+func TestProfileCommand(t *testing.T) {
+	if _, err := os.Stat(profileShell); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "strace")
+	if err := os.WriteFile(tool, []byte(fakeProfiler), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	argv, err := profileCommand(constants.ProfileStrace, tool, "/bin/sh", []string{"-c", "echo job output; exit 3"}, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), "TMPDIR="+dir)
+	output, err := cmd.CombinedOutput()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected the job's exit code 3, got %v\n%s", err, output)
+	}
+	want := "job output\n\n" + constants.ProfileBeginMarker + "strace\nexecve(/bin/sh)\n\n" + constants.ProfileEndMarker + "\n"
+	if !strings.HasSuffix(string(output), want) {
+		t.Errorf("output = %q, want suffix %q", output, want)
+	}
+
+	if _, err := profileCommand("gdb", "/usr/bin/gdb", "/bin/true", nil, 1024); err == nil {
+		t.Error("expected error for unsupported profiler")
+	}
+}
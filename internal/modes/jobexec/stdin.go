@@ -0,0 +1,29 @@
+//go:build linux
+
+package jobexec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// redirectStdin makes the uploaded file at path, relative to workspaceDir,
+// the process's stdin so the job command inherits it on exec. The file is
+// removed from the workspace once open, the job only sees it as stdin.
+func redirectStdin(workspaceDir, path string) error {
+	file, err := os.Open(filepath.Join(workspaceDir, path))
+	if err != nil {
+		return fmt.Errorf("stdin upload missing: %w", err)
+	}
+	defer file.Close()
+
+	if err := syscall.Dup3(int(file.Fd()), 0, 0); err != nil {
+		return fmt.Errorf("failed to replace stdin: %w", err)
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		return fmt.Errorf("failed to remove stdin upload: %w", err)
+	}
+	return nil
+}
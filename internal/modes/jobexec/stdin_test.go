@@ -0,0 +1,44 @@
+//go:build linux
+
+package jobexec
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestRedirectStdinMissingUpload(t *testing.T) {
+	if err := redirectStdin(t.TempDir(), ".joblet-stdin"); err == nil {
+		t.Fatal("expected error for a missing stdin upload")
+	}
+}
+
+func TestRedirectStdin(t *testing.T) {
+	saved, err := syscall.Dup(0)
+	if err != nil {
+		t.Skipf("cannot save stdin: %v", err)
+	}
+	defer func() {
+		_ = syscall.Dup3(saved, 0, 0)
+		_ = syscall.Close(saved)
+	}()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".joblet-stdin"), []byte("a,b\n1,2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := redirectStdin(dir, ".joblet-stdin"); err != nil {
+		t.Fatalf("redirectStdin: %v", err)
+	}
+
+	data := make([]byte, 64)
+	n, _ := syscall.Read(0, data)
+	if string(data[:n]) != "a,b\n1,2\n" {
+		t.Errorf("stdin = %q", data[:n])
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".joblet-stdin")); !os.IsNotExist(err) {
+		t.Errorf("stdin upload was not removed from the workspace: %v", err)
+	}
+}
@@ -0,0 +1,1358 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: backup.proto
+
+package backup
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ExportJobsRequest selects the jobs to export
+type ExportJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         string                 `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`                           // RFC 3339; only jobs created from then on, empty = all
+	EndedOnly     bool                   `protobuf:"varint,2,opt,name=ended_only,json=endedOnly,proto3" json:"ended_only,omitempty"` // Leave out jobs that have not ended
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportJobsRequest) Reset() {
+	*x = ExportJobsRequest{}
+	mi := &file_backup_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJobsRequest) ProtoMessage() {}
+
+func (x *ExportJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJobsRequest.ProtoReflect.Descriptor instead.
+func (*ExportJobsRequest) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExportJobsRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *ExportJobsRequest) GetEndedOnly() bool {
+	if x != nil {
+		return x.EndedOnly
+	}
+	return false
+}
+
+// ExportWorkflowsRequest selects the workflows to export
+type ExportWorkflowsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         string                 `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`                           // RFC 3339; only workflows created from then on, empty = all
+	EndedOnly     bool                   `protobuf:"varint,2,opt,name=ended_only,json=endedOnly,proto3" json:"ended_only,omitempty"` // Leave out workflows that have not ended
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportWorkflowsRequest) Reset() {
+	*x = ExportWorkflowsRequest{}
+	mi := &file_backup_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportWorkflowsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportWorkflowsRequest) ProtoMessage() {}
+
+func (x *ExportWorkflowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportWorkflowsRequest.ProtoReflect.Descriptor instead.
+func (*ExportWorkflowsRequest) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExportWorkflowsRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *ExportWorkflowsRequest) GetEndedOnly() bool {
+	if x != nil {
+		return x.EndedOnly
+	}
+	return false
+}
+
+// JobResources are the limits a job ran with
+type JobResources struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxCpu        int32                  `protobuf:"varint,1,opt,name=max_cpu,json=maxCpu,proto3" json:"max_cpu,omitempty"`          // Percent
+	CpuCores      string                 `protobuf:"bytes,2,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`     // e.g. "0-3"
+	MaxMemory     int32                  `protobuf:"varint,3,opt,name=max_memory,json=maxMemory,proto3" json:"max_memory,omitempty"` // MB
+	MaxIoBps      int32                  `protobuf:"varint,4,opt,name=max_io_bps,json=maxIoBps,proto3" json:"max_io_bps,omitempty"`
+	GpuCount      int32                  `protobuf:"varint,5,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	GpuMemoryMb   int64                  `protobuf:"varint,6,opt,name=gpu_memory_mb,json=gpuMemoryMb,proto3" json:"gpu_memory_mb,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobResources) Reset() {
+	*x = JobResources{}
+	mi := &file_backup_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobResources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResources) ProtoMessage() {}
+
+func (x *JobResources) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResources.ProtoReflect.Descriptor instead.
+func (*JobResources) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *JobResources) GetMaxCpu() int32 {
+	if x != nil {
+		return x.MaxCpu
+	}
+	return 0
+}
+
+func (x *JobResources) GetCpuCores() string {
+	if x != nil {
+		return x.CpuCores
+	}
+	return ""
+}
+
+func (x *JobResources) GetMaxMemory() int32 {
+	if x != nil {
+		return x.MaxMemory
+	}
+	return 0
+}
+
+func (x *JobResources) GetMaxIoBps() int32 {
+	if x != nil {
+		return x.MaxIoBps
+	}
+	return 0
+}
+
+func (x *JobResources) GetGpuCount() int32 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *JobResources) GetGpuMemoryMb() int64 {
+	if x != nil {
+		return x.GpuMemoryMb
+	}
+	return 0
+}
+
+// JobEventRecord is one entry of a job's launch timeline
+type JobEventRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          string                 `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"` // RFC 3339
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobEventRecord) Reset() {
+	*x = JobEventRecord{}
+	mi := &file_backup_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobEventRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobEventRecord) ProtoMessage() {}
+
+func (x *JobEventRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobEventRecord.ProtoReflect.Descriptor instead.
+func (*JobEventRecord) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *JobEventRecord) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *JobEventRecord) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *JobEventRecord) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ExportedJob is the record of one job. Secret values are masked and upload
+// contents left out; logs and metrics stay in the persist service.
+type ExportedJob struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identity
+	Uuid   string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                   // Workflow job name, empty for individual jobs
+	NodeId string `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"` // Node that ran the job
+	// Specification
+	Command           string            `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	Args              []string          `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	Type              string            `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	Resources         *JobResources     `protobuf:"bytes,7,opt,name=resources,proto3" json:"resources,omitempty"`
+	Network           string            `protobuf:"bytes,8,opt,name=network,proto3" json:"network,omitempty"`
+	Volumes           []string          `protobuf:"bytes,9,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	Runtime           string            `protobuf:"bytes,10,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	RequestedRuntime  string            `protobuf:"bytes,11,opt,name=requested_runtime,json=requestedRuntime,proto3" json:"requested_runtime,omitempty"`
+	WorkingDirectory  string            `protobuf:"bytes,12,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	Environment       map[string]string `protobuf:"bytes,13,rep,name=environment,proto3" json:"environment,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SecretEnvironment map[string]string `protobuf:"bytes,14,rep,name=secret_environment,json=secretEnvironment,proto3" json:"secret_environment,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Values masked
+	Uploads           []string          `protobuf:"bytes,15,rep,name=uploads,proto3" json:"uploads,omitempty"`                                                                                                                        // Paths of the uploaded files
+	CgroupParams      map[string]string `protobuf:"bytes,16,rep,name=cgroup_params,json=cgroupParams,proto3" json:"cgroup_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WorkflowUuid      string            `protobuf:"bytes,17,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`
+	Dependencies      []string          `protobuf:"bytes,18,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
+	Tenant            string            `protobuf:"bytes,19,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Group             string            `protobuf:"bytes,20,opt,name=group,proto3" json:"group,omitempty"`
+	ArrayUuid         string            `protobuf:"bytes,21,opt,name=array_uuid,json=arrayUuid,proto3" json:"array_uuid,omitempty"`
+	ArrayIndex        int32             `protobuf:"varint,22,opt,name=array_index,json=arrayIndex,proto3" json:"array_index,omitempty"`
+	// History
+	Status             string            `protobuf:"bytes,23,opt,name=status,proto3" json:"status,omitempty"`
+	StartTime          string            `protobuf:"bytes,24,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`             // RFC 3339
+	EndTime            string            `protobuf:"bytes,25,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                   // Empty while the job runs
+	ScheduledTime      string            `protobuf:"bytes,26,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"` // Empty for jobs started at once
+	ExitCode           int32             `protobuf:"varint,27,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Attempts           int32             `protobuf:"varint,28,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Events             []*JobEventRecord `protobuf:"bytes,29,rep,name=events,proto3" json:"events,omitempty"`
+	Outputs            []byte            `protobuf:"bytes,30,opt,name=outputs,proto3" json:"outputs,omitempty"` // Workflow outputs the job wrote
+	Redactions         int64             `protobuf:"varint,31,opt,name=redactions,proto3" json:"redactions,omitempty"`
+	OutputDroppedBytes int64             `protobuf:"varint,32,opt,name=output_dropped_bytes,json=outputDroppedBytes,proto3" json:"output_dropped_bytes,omitempty"`
+	OutputTruncated    bool              `protobuf:"varint,33,opt,name=output_truncated,json=outputTruncated,proto3" json:"output_truncated,omitempty"`
+	// Annotations
+	Labels        map[string]string `protobuf:"bytes,34,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Annotations   map[string]string `protobuf:"bytes,35,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedJob) Reset() {
+	*x = ExportedJob{}
+	mi := &file_backup_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedJob) ProtoMessage() {}
+
+func (x *ExportedJob) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedJob.ProtoReflect.Descriptor instead.
+func (*ExportedJob) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExportedJob) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetResources() *JobResources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetVolumes() []string {
+	if x != nil {
+		return x.Volumes
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetRuntime() string {
+	if x != nil {
+		return x.Runtime
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetRequestedRuntime() string {
+	if x != nil {
+		return x.RequestedRuntime
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetWorkingDirectory() string {
+	if x != nil {
+		return x.WorkingDirectory
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetEnvironment() map[string]string {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetSecretEnvironment() map[string]string {
+	if x != nil {
+		return x.SecretEnvironment
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetUploads() []string {
+	if x != nil {
+		return x.Uploads
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetCgroupParams() map[string]string {
+	if x != nil {
+		return x.CgroupParams
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetDependencies() []string {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetArrayUuid() string {
+	if x != nil {
+		return x.ArrayUuid
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetArrayIndex() int32 {
+	if x != nil {
+		return x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *ExportedJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetScheduledTime() string {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return ""
+}
+
+func (x *ExportedJob) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ExportedJob) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *ExportedJob) GetEvents() []*JobEventRecord {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetOutputs() []byte {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetRedactions() int64 {
+	if x != nil {
+		return x.Redactions
+	}
+	return 0
+}
+
+func (x *ExportedJob) GetOutputDroppedBytes() int64 {
+	if x != nil {
+		return x.OutputDroppedBytes
+	}
+	return 0
+}
+
+func (x *ExportedJob) GetOutputTruncated() bool {
+	if x != nil {
+		return x.OutputTruncated
+	}
+	return false
+}
+
+func (x *ExportedJob) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ExportedJob) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+// WorkflowRequirement is one condition a workflow job waited for: a job
+// ending with a status, or an expression over several jobs
+type WorkflowRequirement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           string                 `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Expression    string                 `protobuf:"bytes,3,opt,name=expression,proto3" json:"expression,omitempty"` // Set instead of job and status
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowRequirement) Reset() {
+	*x = WorkflowRequirement{}
+	mi := &file_backup_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowRequirement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowRequirement) ProtoMessage() {}
+
+func (x *WorkflowRequirement) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowRequirement.ProtoReflect.Descriptor instead.
+func (*WorkflowRequirement) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WorkflowRequirement) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *WorkflowRequirement) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowRequirement) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+// WorkflowJobRecord is one job of an exported workflow
+type WorkflowJobRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                      // Job name from the workflow YAML
+	JobUuid       string                 `protobuf:"bytes,2,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"` // Empty for jobs that never started
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Requirements  []*WorkflowRequirement `protobuf:"bytes,4,rep,name=requirements,proto3" json:"requirements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowJobRecord) Reset() {
+	*x = WorkflowJobRecord{}
+	mi := &file_backup_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowJobRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowJobRecord) ProtoMessage() {}
+
+func (x *WorkflowJobRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowJobRecord.ProtoReflect.Descriptor instead.
+func (*WorkflowJobRecord) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WorkflowJobRecord) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowJobRecord) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *WorkflowJobRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowJobRecord) GetRequirements() []*WorkflowRequirement {
+	if x != nil {
+		return x.Requirements
+	}
+	return nil
+}
+
+// ExportedWorkflow is the record of one workflow and its jobs, in workflow
+// order
+type ExportedWorkflow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Workflow      string                 `protobuf:"bytes,2,opt,name=workflow,proto3" json:"workflow,omitempty"` // Workflow file or name
+	YamlContent   string                 `protobuf:"bytes,3,opt,name=yaml_content,json=yamlContent,proto3" json:"yaml_content,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`       // RFC 3339
+	StartedAt     string                 `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`       // Empty when it never started
+	CompletedAt   string                 `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"` // Empty while it runs
+	TotalJobs     int32                  `protobuf:"varint,8,opt,name=total_jobs,json=totalJobs,proto3" json:"total_jobs,omitempty"`
+	CompletedJobs int32                  `protobuf:"varint,9,opt,name=completed_jobs,json=completedJobs,proto3" json:"completed_jobs,omitempty"`
+	FailedJobs    int32                  `protobuf:"varint,10,opt,name=failed_jobs,json=failedJobs,proto3" json:"failed_jobs,omitempty"`
+	CanceledJobs  int32                  `protobuf:"varint,11,opt,name=canceled_jobs,json=canceledJobs,proto3" json:"canceled_jobs,omitempty"`
+	Jobs          []*WorkflowJobRecord   `protobuf:"bytes,12,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedWorkflow) Reset() {
+	*x = ExportedWorkflow{}
+	mi := &file_backup_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedWorkflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedWorkflow) ProtoMessage() {}
+
+func (x *ExportedWorkflow) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedWorkflow.ProtoReflect.Descriptor instead.
+func (*ExportedWorkflow) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExportedWorkflow) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetWorkflow() string {
+	if x != nil {
+		return x.Workflow
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetYamlContent() string {
+	if x != nil {
+		return x.YamlContent
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetCompletedAt() string {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return ""
+}
+
+func (x *ExportedWorkflow) GetTotalJobs() int32 {
+	if x != nil {
+		return x.TotalJobs
+	}
+	return 0
+}
+
+func (x *ExportedWorkflow) GetCompletedJobs() int32 {
+	if x != nil {
+		return x.CompletedJobs
+	}
+	return 0
+}
+
+func (x *ExportedWorkflow) GetFailedJobs() int32 {
+	if x != nil {
+		return x.FailedJobs
+	}
+	return 0
+}
+
+func (x *ExportedWorkflow) GetCanceledJobs() int32 {
+	if x != nil {
+		return x.CanceledJobs
+	}
+	return 0
+}
+
+func (x *ExportedWorkflow) GetJobs() []*WorkflowJobRecord {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// ImportJobsRequest carries exported jobs
+type ImportJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*ExportedJob         `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportJobsRequest) Reset() {
+	*x = ImportJobsRequest{}
+	mi := &file_backup_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportJobsRequest) ProtoMessage() {}
+
+func (x *ImportJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportJobsRequest.ProtoReflect.Descriptor instead.
+func (*ImportJobsRequest) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ImportJobsRequest) GetJobs() []*ExportedJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// ImportWorkflowsRequest carries exported workflows
+type ImportWorkflowsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workflows     []*ExportedWorkflow    `protobuf:"bytes,1,rep,name=workflows,proto3" json:"workflows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportWorkflowsRequest) Reset() {
+	*x = ImportWorkflowsRequest{}
+	mi := &file_backup_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWorkflowsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWorkflowsRequest) ProtoMessage() {}
+
+func (x *ImportWorkflowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWorkflowsRequest.ProtoReflect.Descriptor instead.
+func (*ImportWorkflowsRequest) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ImportWorkflowsRequest) GetWorkflows() []*ExportedWorkflow {
+	if x != nil {
+		return x.Workflows
+	}
+	return nil
+}
+
+// SkippedRecord is a job or workflow that was not imported
+type SkippedRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // e.g. "already exists", "still running"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkippedRecord) Reset() {
+	*x = SkippedRecord{}
+	mi := &file_backup_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkippedRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkippedRecord) ProtoMessage() {}
+
+func (x *SkippedRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkippedRecord.ProtoReflect.Descriptor instead.
+func (*SkippedRecord) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SkippedRecord) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SkippedRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ImportResult counts what an import loaded
+type ImportResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Imported      int32                  `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"`
+	Skipped       []*SkippedRecord       `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportResult) Reset() {
+	*x = ImportResult{}
+	mi := &file_backup_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportResult) ProtoMessage() {}
+
+func (x *ImportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportResult.ProtoReflect.Descriptor instead.
+func (*ImportResult) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ImportResult) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportResult) GetSkipped() []*SkippedRecord {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// Dump is the file 'rnx admin export' writes, in protobuf JSON
+type Dump struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`                        // Format version, 1
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`             // Node the records were exported from
+	ExportedAt    string                 `protobuf:"bytes,3,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"` // RFC 3339
+	Jobs          []*ExportedJob         `protobuf:"bytes,4,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	Workflows     []*ExportedWorkflow    `protobuf:"bytes,5,rep,name=workflows,proto3" json:"workflows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dump) Reset() {
+	*x = Dump{}
+	mi := &file_backup_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dump) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dump) ProtoMessage() {}
+
+func (x *Dump) ProtoReflect() protoreflect.Message {
+	mi := &file_backup_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dump.ProtoReflect.Descriptor instead.
+func (*Dump) Descriptor() ([]byte, []int) {
+	return file_backup_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Dump) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Dump) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *Dump) GetExportedAt() string {
+	if x != nil {
+		return x.ExportedAt
+	}
+	return ""
+}
+
+func (x *Dump) GetJobs() []*ExportedJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *Dump) GetWorkflows() []*ExportedWorkflow {
+	if x != nil {
+		return x.Workflows
+	}
+	return nil
+}
+
+var File_backup_proto protoreflect.FileDescriptor
+
+const file_backup_proto_rawDesc = "" +
+	"\n" +
+	"\fbackup.proto\x12\rjoblet.backup\"H\n" +
+	"\x11ExportJobsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\tR\x05since\x12\x1d\n" +
+	"\n" +
+	"ended_only\x18\x02 \x01(\bR\tendedOnly\"M\n" +
+	"\x16ExportWorkflowsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\tR\x05since\x12\x1d\n" +
+	"\n" +
+	"ended_only\x18\x02 \x01(\bR\tendedOnly\"\xc2\x01\n" +
+	"\fJobResources\x12\x17\n" +
+	"\amax_cpu\x18\x01 \x01(\x05R\x06maxCpu\x12\x1b\n" +
+	"\tcpu_cores\x18\x02 \x01(\tR\bcpuCores\x12\x1d\n" +
+	"\n" +
+	"max_memory\x18\x03 \x01(\x05R\tmaxMemory\x12\x1c\n" +
+	"\n" +
+	"max_io_bps\x18\x04 \x01(\x05R\bmaxIoBps\x12\x1b\n" +
+	"\tgpu_count\x18\x05 \x01(\x05R\bgpuCount\x12\"\n" +
+	"\rgpu_memory_mb\x18\x06 \x01(\x03R\vgpuMemoryMb\"R\n" +
+	"\x0eJobEventRecord\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\tR\x04time\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x99\r\n" +
+	"\vExportedJob\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x17\n" +
+	"\anode_id\x18\x03 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\acommand\x18\x04 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x05 \x03(\tR\x04args\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x129\n" +
+	"\tresources\x18\a \x01(\v2\x1b.joblet.backup.JobResourcesR\tresources\x12\x18\n" +
+	"\anetwork\x18\b \x01(\tR\anetwork\x12\x18\n" +
+	"\avolumes\x18\t \x03(\tR\avolumes\x12\x18\n" +
+	"\aruntime\x18\n" +
+	" \x01(\tR\aruntime\x12+\n" +
+	"\x11requested_runtime\x18\v \x01(\tR\x10requestedRuntime\x12+\n" +
+	"\x11working_directory\x18\f \x01(\tR\x10workingDirectory\x12M\n" +
+	"\venvironment\x18\r \x03(\v2+.joblet.backup.ExportedJob.EnvironmentEntryR\venvironment\x12`\n" +
+	"\x12secret_environment\x18\x0e \x03(\v21.joblet.backup.ExportedJob.SecretEnvironmentEntryR\x11secretEnvironment\x12\x18\n" +
+	"\auploads\x18\x0f \x03(\tR\auploads\x12Q\n" +
+	"\rcgroup_params\x18\x10 \x03(\v2,.joblet.backup.ExportedJob.CgroupParamsEntryR\fcgroupParams\x12#\n" +
+	"\rworkflow_uuid\x18\x11 \x01(\tR\fworkflowUuid\x12\"\n" +
+	"\fdependencies\x18\x12 \x03(\tR\fdependencies\x12\x16\n" +
+	"\x06tenant\x18\x13 \x01(\tR\x06tenant\x12\x14\n" +
+	"\x05group\x18\x14 \x01(\tR\x05group\x12\x1d\n" +
+	"\n" +
+	"array_uuid\x18\x15 \x01(\tR\tarrayUuid\x12\x1f\n" +
+	"\varray_index\x18\x16 \x01(\x05R\n" +
+	"arrayIndex\x12\x16\n" +
+	"\x06status\x18\x17 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x18 \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x19 \x01(\tR\aendTime\x12%\n" +
+	"\x0escheduled_time\x18\x1a \x01(\tR\rscheduledTime\x12\x1b\n" +
+	"\texit_code\x18\x1b \x01(\x05R\bexitCode\x12\x1a\n" +
+	"\battempts\x18\x1c \x01(\x05R\battempts\x125\n" +
+	"\x06events\x18\x1d \x03(\v2\x1d.joblet.backup.JobEventRecordR\x06events\x12\x18\n" +
+	"\aoutputs\x18\x1e \x01(\fR\aoutputs\x12\x1e\n" +
+	"\n" +
+	"redactions\x18\x1f \x01(\x03R\n" +
+	"redactions\x120\n" +
+	"\x14output_dropped_bytes\x18  \x01(\x03R\x12outputDroppedBytes\x12)\n" +
+	"\x10output_truncated\x18! \x01(\bR\x0foutputTruncated\x12>\n" +
+	"\x06labels\x18\" \x03(\v2&.joblet.backup.ExportedJob.LabelsEntryR\x06labels\x12M\n" +
+	"\vannotations\x18# \x03(\v2+.joblet.backup.ExportedJob.AnnotationsEntryR\vannotations\x1a>\n" +
+	"\x10EnvironmentEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aD\n" +
+	"\x16SecretEnvironmentEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a?\n" +
+	"\x11CgroupParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"_\n" +
+	"\x13WorkflowRequirement\x12\x10\n" +
+	"\x03job\x18\x01 \x01(\tR\x03job\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x03 \x01(\tR\n" +
+	"expression\"\xa2\x01\n" +
+	"\x11WorkflowJobRecord\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bjob_uuid\x18\x02 \x01(\tR\ajobUuid\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12F\n" +
+	"\frequirements\x18\x04 \x03(\v2\".joblet.backup.WorkflowRequirementR\frequirements\"\xa0\x03\n" +
+	"\x10ExportedWorkflow\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x1a\n" +
+	"\bworkflow\x18\x02 \x01(\tR\bworkflow\x12!\n" +
+	"\fyaml_content\x18\x03 \x01(\tR\vyamlContent\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\tR\tstartedAt\x12!\n" +
+	"\fcompleted_at\x18\a \x01(\tR\vcompletedAt\x12\x1d\n" +
+	"\n" +
+	"total_jobs\x18\b \x01(\x05R\ttotalJobs\x12%\n" +
+	"\x0ecompleted_jobs\x18\t \x01(\x05R\rcompletedJobs\x12\x1f\n" +
+	"\vfailed_jobs\x18\n" +
+	" \x01(\x05R\n" +
+	"failedJobs\x12#\n" +
+	"\rcanceled_jobs\x18\v \x01(\x05R\fcanceledJobs\x124\n" +
+	"\x04jobs\x18\f \x03(\v2 .joblet.backup.WorkflowJobRecordR\x04jobs\"C\n" +
+	"\x11ImportJobsRequest\x12.\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x1a.joblet.backup.ExportedJobR\x04jobs\"W\n" +
+	"\x16ImportWorkflowsRequest\x12=\n" +
+	"\tworkflows\x18\x01 \x03(\v2\x1f.joblet.backup.ExportedWorkflowR\tworkflows\";\n" +
+	"\rSkippedRecord\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"b\n" +
+	"\fImportResult\x12\x1a\n" +
+	"\bimported\x18\x01 \x01(\x05R\bimported\x126\n" +
+	"\askipped\x18\x02 \x03(\v2\x1c.joblet.backup.SkippedRecordR\askipped\"\xc9\x01\n" +
+	"\x04Dump\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x1f\n" +
+	"\vexported_at\x18\x03 \x01(\tR\n" +
+	"exportedAt\x12.\n" +
+	"\x04jobs\x18\x04 \x03(\v2\x1a.joblet.backup.ExportedJobR\x04jobs\x12=\n" +
+	"\tworkflows\x18\x05 \x03(\v2\x1f.joblet.backup.ExportedWorkflowR\tworkflows2\xde\x02\n" +
+	"\rBackupService\x12L\n" +
+	"\n" +
+	"ExportJobs\x12 .joblet.backup.ExportJobsRequest\x1a\x1a.joblet.backup.ExportedJob0\x01\x12[\n" +
+	"\x0fExportWorkflows\x12%.joblet.backup.ExportWorkflowsRequest\x1a\x1f.joblet.backup.ExportedWorkflow0\x01\x12K\n" +
+	"\n" +
+	"ImportJobs\x12 .joblet.backup.ImportJobsRequest\x1a\x1b.joblet.backup.ImportResult\x12U\n" +
+	"\x0fImportWorkflows\x12%.joblet.backup.ImportWorkflowsRequest\x1a\x1b.joblet.backup.ImportResultB7Z5github.com/ehsaniara/joblet/internal/proto/gen/backupb\x06proto3"
+
+var (
+	file_backup_proto_rawDescOnce sync.Once
+	file_backup_proto_rawDescData []byte
+)
+
+func file_backup_proto_rawDescGZIP() []byte {
+	file_backup_proto_rawDescOnce.Do(func() {
+		file_backup_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_backup_proto_rawDesc), len(file_backup_proto_rawDesc)))
+	})
+	return file_backup_proto_rawDescData
+}
+
+var file_backup_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_backup_proto_goTypes = []any{
+	(*ExportJobsRequest)(nil),      // 0: joblet.backup.ExportJobsRequest
+	(*ExportWorkflowsRequest)(nil), // 1: joblet.backup.ExportWorkflowsRequest
+	(*JobResources)(nil),           // 2: joblet.backup.JobResources
+	(*JobEventRecord)(nil),         // 3: joblet.backup.JobEventRecord
+	(*ExportedJob)(nil),            // 4: joblet.backup.ExportedJob
+	(*WorkflowRequirement)(nil),    // 5: joblet.backup.WorkflowRequirement
+	(*WorkflowJobRecord)(nil),      // 6: joblet.backup.WorkflowJobRecord
+	(*ExportedWorkflow)(nil),       // 7: joblet.backup.ExportedWorkflow
+	(*ImportJobsRequest)(nil),      // 8: joblet.backup.ImportJobsRequest
+	(*ImportWorkflowsRequest)(nil), // 9: joblet.backup.ImportWorkflowsRequest
+	(*SkippedRecord)(nil),          // 10: joblet.backup.SkippedRecord
+	(*ImportResult)(nil),           // 11: joblet.backup.ImportResult
+	(*Dump)(nil),                   // 12: joblet.backup.Dump
+	nil,                            // 13: joblet.backup.ExportedJob.EnvironmentEntry
+	nil,                            // 14: joblet.backup.ExportedJob.SecretEnvironmentEntry
+	nil,                            // 15: joblet.backup.ExportedJob.CgroupParamsEntry
+	nil,                            // 16: joblet.backup.ExportedJob.LabelsEntry
+	nil,                            // 17: joblet.backup.ExportedJob.AnnotationsEntry
+}
+var file_backup_proto_depIdxs = []int32{
+	2,  // 0: joblet.backup.ExportedJob.resources:type_name -> joblet.backup.JobResources
+	13, // 1: joblet.backup.ExportedJob.environment:type_name -> joblet.backup.ExportedJob.EnvironmentEntry
+	14, // 2: joblet.backup.ExportedJob.secret_environment:type_name -> joblet.backup.ExportedJob.SecretEnvironmentEntry
+	15, // 3: joblet.backup.ExportedJob.cgroup_params:type_name -> joblet.backup.ExportedJob.CgroupParamsEntry
+	3,  // 4: joblet.backup.ExportedJob.events:type_name -> joblet.backup.JobEventRecord
+	16, // 5: joblet.backup.ExportedJob.labels:type_name -> joblet.backup.ExportedJob.LabelsEntry
+	17, // 6: joblet.backup.ExportedJob.annotations:type_name -> joblet.backup.ExportedJob.AnnotationsEntry
+	5,  // 7: joblet.backup.WorkflowJobRecord.requirements:type_name -> joblet.backup.WorkflowRequirement
+	6,  // 8: joblet.backup.ExportedWorkflow.jobs:type_name -> joblet.backup.WorkflowJobRecord
+	4,  // 9: joblet.backup.ImportJobsRequest.jobs:type_name -> joblet.backup.ExportedJob
+	7,  // 10: joblet.backup.ImportWorkflowsRequest.workflows:type_name -> joblet.backup.ExportedWorkflow
+	10, // 11: joblet.backup.ImportResult.skipped:type_name -> joblet.backup.SkippedRecord
+	4,  // 12: joblet.backup.Dump.jobs:type_name -> joblet.backup.ExportedJob
+	7,  // 13: joblet.backup.Dump.workflows:type_name -> joblet.backup.ExportedWorkflow
+	0,  // 14: joblet.backup.BackupService.ExportJobs:input_type -> joblet.backup.ExportJobsRequest
+	1,  // 15: joblet.backup.BackupService.ExportWorkflows:input_type -> joblet.backup.ExportWorkflowsRequest
+	8,  // 16: joblet.backup.BackupService.ImportJobs:input_type -> joblet.backup.ImportJobsRequest
+	9,  // 17: joblet.backup.BackupService.ImportWorkflows:input_type -> joblet.backup.ImportWorkflowsRequest
+	4,  // 18: joblet.backup.BackupService.ExportJobs:output_type -> joblet.backup.ExportedJob
+	7,  // 19: joblet.backup.BackupService.ExportWorkflows:output_type -> joblet.backup.ExportedWorkflow
+	11, // 20: joblet.backup.BackupService.ImportJobs:output_type -> joblet.backup.ImportResult
+	11, // 21: joblet.backup.BackupService.ImportWorkflows:output_type -> joblet.backup.ImportResult
+	18, // [18:22] is the sub-list for method output_type
+	14, // [14:18] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_backup_proto_init() }
+func file_backup_proto_init() {
+	if File_backup_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_backup_proto_rawDesc), len(file_backup_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_backup_proto_goTypes,
+		DependencyIndexes: file_backup_proto_depIdxs,
+		MessageInfos:      file_backup_proto_msgTypes,
+	}.Build()
+	File_backup_proto = out.File
+	file_backup_proto_goTypes = nil
+	file_backup_proto_depIdxs = nil
+}
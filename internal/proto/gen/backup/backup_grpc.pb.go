@@ -0,0 +1,268 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: backup.proto
+
+package backup
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BackupService_ExportJobs_FullMethodName      = "/joblet.backup.BackupService/ExportJobs"
+	BackupService_ExportWorkflows_FullMethodName = "/joblet.backup.BackupService/ExportWorkflows"
+	BackupService_ImportJobs_FullMethodName      = "/joblet.backup.BackupService/ImportJobs"
+	BackupService_ImportWorkflows_FullMethodName = "/joblet.backup.BackupService/ImportWorkflows"
+)
+
+// BackupServiceClient is the client API for BackupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BackupService dumps the job and workflow records of a node and loads them
+// into another.
+//
+// Only admins may call it. 'rnx admin export' writes the records to a
+// protobuf JSON file and 'rnx admin import' loads the file, so a node can be
+// rebuilt or moved to new hardware without losing its execution history.
+// Records are history: only ended jobs and workflows are imported, and
+// nothing is run again.
+type BackupServiceClient interface {
+	// Stream the node's jobs, oldest first
+	ExportJobs(ctx context.Context, in *ExportJobsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportedJob], error)
+	// Stream the node's workflows, oldest first
+	ExportWorkflows(ctx context.Context, in *ExportWorkflowsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportedWorkflow], error)
+	// Load exported jobs, skipping those the node already has
+	ImportJobs(ctx context.Context, in *ImportJobsRequest, opts ...grpc.CallOption) (*ImportResult, error)
+	// Load exported workflows, after ImportJobs loaded their jobs
+	ImportWorkflows(ctx context.Context, in *ImportWorkflowsRequest, opts ...grpc.CallOption) (*ImportResult, error)
+}
+
+type backupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBackupServiceClient(cc grpc.ClientConnInterface) BackupServiceClient {
+	return &backupServiceClient{cc}
+}
+
+func (c *backupServiceClient) ExportJobs(ctx context.Context, in *ExportJobsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportedJob], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BackupService_ServiceDesc.Streams[0], BackupService_ExportJobs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportJobsRequest, ExportedJob]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BackupService_ExportJobsClient = grpc.ServerStreamingClient[ExportedJob]
+
+func (c *backupServiceClient) ExportWorkflows(ctx context.Context, in *ExportWorkflowsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportedWorkflow], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BackupService_ServiceDesc.Streams[1], BackupService_ExportWorkflows_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportWorkflowsRequest, ExportedWorkflow]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BackupService_ExportWorkflowsClient = grpc.ServerStreamingClient[ExportedWorkflow]
+
+func (c *backupServiceClient) ImportJobs(ctx context.Context, in *ImportJobsRequest, opts ...grpc.CallOption) (*ImportResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportResult)
+	err := c.cc.Invoke(ctx, BackupService_ImportJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backupServiceClient) ImportWorkflows(ctx context.Context, in *ImportWorkflowsRequest, opts ...grpc.CallOption) (*ImportResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportResult)
+	err := c.cc.Invoke(ctx, BackupService_ImportWorkflows_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BackupServiceServer is the server API for BackupService service.
+// All implementations must embed UnimplementedBackupServiceServer
+// for forward compatibility.
+//
+// BackupService dumps the job and workflow records of a node and loads them
+// into another.
+//
+// Only admins may call it. 'rnx admin export' writes the records to a
+// protobuf JSON file and 'rnx admin import' loads the file, so a node can be
+// rebuilt or moved to new hardware without losing its execution history.
+// Records are history: only ended jobs and workflows are imported, and
+// nothing is run again.
+type BackupServiceServer interface {
+	// Stream the node's jobs, oldest first
+	ExportJobs(*ExportJobsRequest, grpc.ServerStreamingServer[ExportedJob]) error
+	// Stream the node's workflows, oldest first
+	ExportWorkflows(*ExportWorkflowsRequest, grpc.ServerStreamingServer[ExportedWorkflow]) error
+	// Load exported jobs, skipping those the node already has
+	ImportJobs(context.Context, *ImportJobsRequest) (*ImportResult, error)
+	// Load exported workflows, after ImportJobs loaded their jobs
+	ImportWorkflows(context.Context, *ImportWorkflowsRequest) (*ImportResult, error)
+	mustEmbedUnimplementedBackupServiceServer()
+}
+
+// UnimplementedBackupServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBackupServiceServer struct{}
+
+func (UnimplementedBackupServiceServer) ExportJobs(*ExportJobsRequest, grpc.ServerStreamingServer[ExportedJob]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportJobs not implemented")
+}
+func (UnimplementedBackupServiceServer) ExportWorkflows(*ExportWorkflowsRequest, grpc.ServerStreamingServer[ExportedWorkflow]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportWorkflows not implemented")
+}
+func (UnimplementedBackupServiceServer) ImportJobs(context.Context, *ImportJobsRequest) (*ImportResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportJobs not implemented")
+}
+func (UnimplementedBackupServiceServer) ImportWorkflows(context.Context, *ImportWorkflowsRequest) (*ImportResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportWorkflows not implemented")
+}
+func (UnimplementedBackupServiceServer) mustEmbedUnimplementedBackupServiceServer() {}
+func (UnimplementedBackupServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeBackupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BackupServiceServer will
+// result in compilation errors.
+type UnsafeBackupServiceServer interface {
+	mustEmbedUnimplementedBackupServiceServer()
+}
+
+func RegisterBackupServiceServer(s grpc.ServiceRegistrar, srv BackupServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBackupServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BackupService_ServiceDesc, srv)
+}
+
+func _BackupService_ExportJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportJobsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BackupServiceServer).ExportJobs(m, &grpc.GenericServerStream[ExportJobsRequest, ExportedJob]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BackupService_ExportJobsServer = grpc.ServerStreamingServer[ExportedJob]
+
+func _BackupService_ExportWorkflows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportWorkflowsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BackupServiceServer).ExportWorkflows(m, &grpc.GenericServerStream[ExportWorkflowsRequest, ExportedWorkflow]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BackupService_ExportWorkflowsServer = grpc.ServerStreamingServer[ExportedWorkflow]
+
+func _BackupService_ImportJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackupServiceServer).ImportJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackupService_ImportJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackupServiceServer).ImportJobs(ctx, req.(*ImportJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BackupService_ImportWorkflows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportWorkflowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackupServiceServer).ImportWorkflows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackupService_ImportWorkflows_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackupServiceServer).ImportWorkflows(ctx, req.(*ImportWorkflowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BackupService_ServiceDesc is the grpc.ServiceDesc for BackupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BackupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.backup.BackupService",
+	HandlerType: (*BackupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ImportJobs",
+			Handler:    _BackupService_ImportJobs_Handler,
+		},
+		{
+			MethodName: "ImportWorkflows",
+			Handler:    _BackupService_ImportWorkflows_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportJobs",
+			Handler:       _BackupService_ExportJobs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportWorkflows",
+			Handler:       _BackupService_ExportWorkflows_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "backup.proto",
+}
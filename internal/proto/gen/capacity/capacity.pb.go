@@ -0,0 +1,320 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: capacity.proto
+
+package capacity
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GetNodeCapacityRequest asks for the current capacity snapshot (empty)
+type GetNodeCapacityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeCapacityRequest) Reset() {
+	*x = GetNodeCapacityRequest{}
+	mi := &file_capacity_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeCapacityRequest) ProtoMessage() {}
+
+func (x *GetNodeCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_capacity_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeCapacityRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_capacity_proto_rawDescGZIP(), []int{0}
+}
+
+// ResourceCapacity describes one resource in its own unit.
+// Schedulable is total - reserved - allocated, never below zero.
+type ResourceCapacity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Reserved      int64                  `protobuf:"varint,2,opt,name=reserved,proto3" json:"reserved,omitempty"`       // Held back for the host by server configuration
+	Allocated     int64                  `protobuf:"varint,3,opt,name=allocated,proto3" json:"allocated,omitempty"`     // Claimed by active job limits or volume sizes
+	Schedulable   int64                  `protobuf:"varint,4,opt,name=schedulable,proto3" json:"schedulable,omitempty"` // Left for new jobs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourceCapacity) Reset() {
+	*x = ResourceCapacity{}
+	mi := &file_capacity_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceCapacity) ProtoMessage() {}
+
+func (x *ResourceCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_capacity_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceCapacity.ProtoReflect.Descriptor instead.
+func (*ResourceCapacity) Descriptor() ([]byte, []int) {
+	return file_capacity_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResourceCapacity) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ResourceCapacity) GetReserved() int64 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *ResourceCapacity) GetAllocated() int64 {
+	if x != nil {
+		return x.Allocated
+	}
+	return 0
+}
+
+func (x *ResourceCapacity) GetSchedulable() int64 {
+	if x != nil {
+		return x.Schedulable
+	}
+	return 0
+}
+
+// NodeCapacity is a point-in-time capacity snapshot of one node
+type NodeCapacity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                              // Unix seconds
+	Cpu           *ResourceCapacity      `protobuf:"bytes,4,opt,name=cpu,proto3" json:"cpu,omitempty"`                                           // CPU percent (100 = one core)
+	Memory        *ResourceCapacity      `protobuf:"bytes,5,opt,name=memory,proto3" json:"memory,omitempty"`                                     // Megabytes
+	Gpus          *ResourceCapacity      `protobuf:"bytes,6,opt,name=gpus,proto3" json:"gpus,omitempty"`                                         // GPU count
+	VolumeDisk    *ResourceCapacity      `protobuf:"bytes,7,opt,name=volume_disk,json=volumeDisk,proto3" json:"volume_disk,omitempty"`           // Bytes on the filesystem holding volumes
+	ActiveJobs    int32                  `protobuf:"varint,8,opt,name=active_jobs,json=activeJobs,proto3" json:"active_jobs,omitempty"`          // Running, starting and scheduled jobs
+	UnboundedJobs int32                  `protobuf:"varint,9,opt,name=unbounded_jobs,json=unboundedJobs,proto3" json:"unbounded_jobs,omitempty"` // Active jobs without a CPU or memory limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeCapacity) Reset() {
+	*x = NodeCapacity{}
+	mi := &file_capacity_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeCapacity) ProtoMessage() {}
+
+func (x *NodeCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_capacity_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeCapacity.ProtoReflect.Descriptor instead.
+func (*NodeCapacity) Descriptor() ([]byte, []int) {
+	return file_capacity_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NodeCapacity) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeCapacity) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *NodeCapacity) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetCpu() *ResourceCapacity {
+	if x != nil {
+		return x.Cpu
+	}
+	return nil
+}
+
+func (x *NodeCapacity) GetMemory() *ResourceCapacity {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *NodeCapacity) GetGpus() *ResourceCapacity {
+	if x != nil {
+		return x.Gpus
+	}
+	return nil
+}
+
+func (x *NodeCapacity) GetVolumeDisk() *ResourceCapacity {
+	if x != nil {
+		return x.VolumeDisk
+	}
+	return nil
+}
+
+func (x *NodeCapacity) GetActiveJobs() int32 {
+	if x != nil {
+		return x.ActiveJobs
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetUnboundedJobs() int32 {
+	if x != nil {
+		return x.UnboundedJobs
+	}
+	return 0
+}
+
+var File_capacity_proto protoreflect.FileDescriptor
+
+const file_capacity_proto_rawDesc = "" +
+	"\n" +
+	"\x0ecapacity.proto\x12\x0fjoblet.capacity\"\x18\n" +
+	"\x16GetNodeCapacityRequest\"\x84\x01\n" +
+	"\x10ResourceCapacity\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12\x1a\n" +
+	"\breserved\x18\x02 \x01(\x03R\breserved\x12\x1c\n" +
+	"\tallocated\x18\x03 \x01(\x03R\tallocated\x12 \n" +
+	"\vschedulable\x18\x04 \x01(\x03R\vschedulable\"\x94\x03\n" +
+	"\fNodeCapacity\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x123\n" +
+	"\x03cpu\x18\x04 \x01(\v2!.joblet.capacity.ResourceCapacityR\x03cpu\x129\n" +
+	"\x06memory\x18\x05 \x01(\v2!.joblet.capacity.ResourceCapacityR\x06memory\x125\n" +
+	"\x04gpus\x18\x06 \x01(\v2!.joblet.capacity.ResourceCapacityR\x04gpus\x12B\n" +
+	"\vvolume_disk\x18\a \x01(\v2!.joblet.capacity.ResourceCapacityR\n" +
+	"volumeDisk\x12\x1f\n" +
+	"\vactive_jobs\x18\b \x01(\x05R\n" +
+	"activeJobs\x12%\n" +
+	"\x0eunbounded_jobs\x18\t \x01(\x05R\runboundedJobs2l\n" +
+	"\x0fCapacityService\x12Y\n" +
+	"\x0fGetNodeCapacity\x12'.joblet.capacity.GetNodeCapacityRequest\x1a\x1d.joblet.capacity.NodeCapacityB9Z7github.com/ehsaniara/joblet/internal/proto/gen/capacityb\x06proto3"
+
+var (
+	file_capacity_proto_rawDescOnce sync.Once
+	file_capacity_proto_rawDescData []byte
+)
+
+func file_capacity_proto_rawDescGZIP() []byte {
+	file_capacity_proto_rawDescOnce.Do(func() {
+		file_capacity_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_capacity_proto_rawDesc), len(file_capacity_proto_rawDesc)))
+	})
+	return file_capacity_proto_rawDescData
+}
+
+var file_capacity_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_capacity_proto_goTypes = []any{
+	(*GetNodeCapacityRequest)(nil), // 0: joblet.capacity.GetNodeCapacityRequest
+	(*ResourceCapacity)(nil),       // 1: joblet.capacity.ResourceCapacity
+	(*NodeCapacity)(nil),           // 2: joblet.capacity.NodeCapacity
+}
+var file_capacity_proto_depIdxs = []int32{
+	1, // 0: joblet.capacity.NodeCapacity.cpu:type_name -> joblet.capacity.ResourceCapacity
+	1, // 1: joblet.capacity.NodeCapacity.memory:type_name -> joblet.capacity.ResourceCapacity
+	1, // 2: joblet.capacity.NodeCapacity.gpus:type_name -> joblet.capacity.ResourceCapacity
+	1, // 3: joblet.capacity.NodeCapacity.volume_disk:type_name -> joblet.capacity.ResourceCapacity
+	0, // 4: joblet.capacity.CapacityService.GetNodeCapacity:input_type -> joblet.capacity.GetNodeCapacityRequest
+	2, // 5: joblet.capacity.CapacityService.GetNodeCapacity:output_type -> joblet.capacity.NodeCapacity
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_capacity_proto_init() }
+func file_capacity_proto_init() {
+	if File_capacity_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_capacity_proto_rawDesc), len(file_capacity_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_capacity_proto_goTypes,
+		DependencyIndexes: file_capacity_proto_depIdxs,
+		MessageInfos:      file_capacity_proto_msgTypes,
+	}.Build()
+	File_capacity_proto = out.File
+	file_capacity_proto_goTypes = nil
+	file_capacity_proto_depIdxs = nil
+}
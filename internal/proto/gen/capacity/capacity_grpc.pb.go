@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: capacity.proto
+
+package capacity
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CapacityService_GetNodeCapacity_FullMethodName = "/joblet.capacity.CapacityService/GetNodeCapacity"
+)
+
+// CapacityServiceClient is the client API for CapacityService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CapacityService reports how much of a node is still free for new jobs.
+//
+// rnx uses it for 'rnx monitor capacity' and to place jobs when several
+// nodes are configured.
+type CapacityServiceClient interface {
+	// Report total, reserved and allocated resources of this node
+	GetNodeCapacity(ctx context.Context, in *GetNodeCapacityRequest, opts ...grpc.CallOption) (*NodeCapacity, error)
+}
+
+type capacityServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCapacityServiceClient(cc grpc.ClientConnInterface) CapacityServiceClient {
+	return &capacityServiceClient{cc}
+}
+
+func (c *capacityServiceClient) GetNodeCapacity(ctx context.Context, in *GetNodeCapacityRequest, opts ...grpc.CallOption) (*NodeCapacity, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeCapacity)
+	err := c.cc.Invoke(ctx, CapacityService_GetNodeCapacity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CapacityServiceServer is the server API for CapacityService service.
+// All implementations must embed UnimplementedCapacityServiceServer
+// for forward compatibility.
+//
+// CapacityService reports how much of a node is still free for new jobs.
+//
+// rnx uses it for 'rnx monitor capacity' and to place jobs when several
+// nodes are configured.
+type CapacityServiceServer interface {
+	// Report total, reserved and allocated resources of this node
+	GetNodeCapacity(context.Context, *GetNodeCapacityRequest) (*NodeCapacity, error)
+	mustEmbedUnimplementedCapacityServiceServer()
+}
+
+// UnimplementedCapacityServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCapacityServiceServer struct{}
+
+func (UnimplementedCapacityServiceServer) GetNodeCapacity(context.Context, *GetNodeCapacityRequest) (*NodeCapacity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeCapacity not implemented")
+}
+func (UnimplementedCapacityServiceServer) mustEmbedUnimplementedCapacityServiceServer() {}
+func (UnimplementedCapacityServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeCapacityServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CapacityServiceServer will
+// result in compilation errors.
+type UnsafeCapacityServiceServer interface {
+	mustEmbedUnimplementedCapacityServiceServer()
+}
+
+func RegisterCapacityServiceServer(s grpc.ServiceRegistrar, srv CapacityServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCapacityServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CapacityService_ServiceDesc, srv)
+}
+
+func _CapacityService_GetNodeCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CapacityServiceServer).GetNodeCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CapacityService_GetNodeCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CapacityServiceServer).GetNodeCapacity(ctx, req.(*GetNodeCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CapacityService_ServiceDesc is the grpc.ServiceDesc for CapacityService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CapacityService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.capacity.CapacityService",
+	HandlerType: (*CapacityServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetNodeCapacity",
+			Handler:    _CapacityService_GetNodeCapacity_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "capacity.proto",
+}
@@ -0,0 +1,148 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: events.proto
+
+package events
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	JobEventService_StreamJobEvents_FullMethodName = "/joblet.events.JobEventService/StreamJobEvents"
+)
+
+// JobEventServiceClient is the client API for JobEventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// JobEventService streams the status and the progress of a job, for UIs
+// showing progress bars.
+//
+// Jobs report progress by printing marker lines to stdout or stderr:
+//
+//	::joblet-progress::40
+//	::joblet-progress::{"percent": 40, "phase": "train", "message": "epoch 4/10"}
+//
+// Fields a marker leaves out keep their last value.
+type JobEventServiceClient interface {
+	// Stream the job's status and progress until it ends. The first event is a
+	// STATUS snapshot with the progress reported so far.
+	StreamJobEvents(ctx context.Context, in *StreamJobEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error)
+}
+
+type jobEventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobEventServiceClient(cc grpc.ClientConnInterface) JobEventServiceClient {
+	return &jobEventServiceClient{cc}
+}
+
+func (c *jobEventServiceClient) StreamJobEvents(ctx context.Context, in *StreamJobEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &JobEventService_ServiceDesc.Streams[0], JobEventService_StreamJobEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamJobEventsRequest, JobEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobEventService_StreamJobEventsClient = grpc.ServerStreamingClient[JobEvent]
+
+// JobEventServiceServer is the server API for JobEventService service.
+// All implementations must embed UnimplementedJobEventServiceServer
+// for forward compatibility.
+//
+// JobEventService streams the status and the progress of a job, for UIs
+// showing progress bars.
+//
+// Jobs report progress by printing marker lines to stdout or stderr:
+//
+//	::joblet-progress::40
+//	::joblet-progress::{"percent": 40, "phase": "train", "message": "epoch 4/10"}
+//
+// Fields a marker leaves out keep their last value.
+type JobEventServiceServer interface {
+	// Stream the job's status and progress until it ends. The first event is a
+	// STATUS snapshot with the progress reported so far.
+	StreamJobEvents(*StreamJobEventsRequest, grpc.ServerStreamingServer[JobEvent]) error
+	mustEmbedUnimplementedJobEventServiceServer()
+}
+
+// UnimplementedJobEventServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobEventServiceServer struct{}
+
+func (UnimplementedJobEventServiceServer) StreamJobEvents(*StreamJobEventsRequest, grpc.ServerStreamingServer[JobEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamJobEvents not implemented")
+}
+func (UnimplementedJobEventServiceServer) mustEmbedUnimplementedJobEventServiceServer() {}
+func (UnimplementedJobEventServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeJobEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobEventServiceServer will
+// result in compilation errors.
+type UnsafeJobEventServiceServer interface {
+	mustEmbedUnimplementedJobEventServiceServer()
+}
+
+func RegisterJobEventServiceServer(s grpc.ServiceRegistrar, srv JobEventServiceServer) {
+	// If the following call pancis, it indicates UnimplementedJobEventServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobEventService_ServiceDesc, srv)
+}
+
+func _JobEventService_StreamJobEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamJobEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobEventServiceServer).StreamJobEvents(m, &grpc.GenericServerStream[StreamJobEventsRequest, JobEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobEventService_StreamJobEventsServer = grpc.ServerStreamingServer[JobEvent]
+
+// JobEventService_ServiceDesc is the grpc.ServiceDesc for JobEventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobEventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.events.JobEventService",
+	HandlerType: (*JobEventServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamJobEvents",
+			Handler:       _JobEventService_StreamJobEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "events.proto",
+}
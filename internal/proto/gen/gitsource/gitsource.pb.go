@@ -0,0 +1,230 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: gitsource.proto
+
+package gitsource
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RunFromGitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Repository    string                 `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"` // e.g. "https://git.internal/team/app" or "git@git.internal:team/app"
+	Ref           string                 `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`               // Branch, tag or commit; empty = the default branch
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`             // Spec path relative to the repository root, e.g. "jobs/train.yaml"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunFromGitRequest) Reset() {
+	*x = RunFromGitRequest{}
+	mi := &file_gitsource_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunFromGitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunFromGitRequest) ProtoMessage() {}
+
+func (x *RunFromGitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gitsource_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunFromGitRequest.ProtoReflect.Descriptor instead.
+func (*RunFromGitRequest) Descriptor() ([]byte, []int) {
+	return file_gitsource_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RunFromGitRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *RunFromGitRequest) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *RunFromGitRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RunFromGitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`
+	Commit        string                 `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"` // Commit the ref resolved to
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`     // "workflow" or "job"
+	Jobs          []string               `protobuf:"bytes,4,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	Files         int32                  `protobuf:"varint,5,opt,name=files,proto3" json:"files,omitempty"` // Files sent to the jobs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunFromGitResponse) Reset() {
+	*x = RunFromGitResponse{}
+	mi := &file_gitsource_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunFromGitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunFromGitResponse) ProtoMessage() {}
+
+func (x *RunFromGitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gitsource_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunFromGitResponse.ProtoReflect.Descriptor instead.
+func (*RunFromGitResponse) Descriptor() ([]byte, []int) {
+	return file_gitsource_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RunFromGitResponse) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *RunFromGitResponse) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *RunFromGitResponse) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RunFromGitResponse) GetJobs() []string {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *RunFromGitResponse) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+var File_gitsource_proto protoreflect.FileDescriptor
+
+const file_gitsource_proto_rawDesc = "" +
+	"\n" +
+	"\x0fgitsource.proto\x12\x10joblet.gitsource\"Y\n" +
+	"\x11RunFromGitRequest\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x01 \x01(\tR\n" +
+	"repository\x12\x10\n" +
+	"\x03ref\x18\x02 \x01(\tR\x03ref\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\"\x8f\x01\n" +
+	"\x12RunFromGitResponse\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\x12\x16\n" +
+	"\x06commit\x18\x02 \x01(\tR\x06commit\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04jobs\x18\x04 \x03(\tR\x04jobs\x12\x14\n" +
+	"\x05files\x18\x05 \x01(\x05R\x05files2k\n" +
+	"\x10GitSourceService\x12W\n" +
+	"\n" +
+	"RunFromGit\x12#.joblet.gitsource.RunFromGitRequest\x1a$.joblet.gitsource.RunFromGitResponseB:Z8github.com/ehsaniara/joblet/internal/proto/gen/gitsourceb\x06proto3"
+
+var (
+	file_gitsource_proto_rawDescOnce sync.Once
+	file_gitsource_proto_rawDescData []byte
+)
+
+func file_gitsource_proto_rawDescGZIP() []byte {
+	file_gitsource_proto_rawDescOnce.Do(func() {
+		file_gitsource_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gitsource_proto_rawDesc), len(file_gitsource_proto_rawDesc)))
+	})
+	return file_gitsource_proto_rawDescData
+}
+
+var file_gitsource_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_gitsource_proto_goTypes = []any{
+	(*RunFromGitRequest)(nil),  // 0: joblet.gitsource.RunFromGitRequest
+	(*RunFromGitResponse)(nil), // 1: joblet.gitsource.RunFromGitResponse
+}
+var file_gitsource_proto_depIdxs = []int32{
+	0, // 0: joblet.gitsource.GitSourceService.RunFromGit:input_type -> joblet.gitsource.RunFromGitRequest
+	1, // 1: joblet.gitsource.GitSourceService.RunFromGit:output_type -> joblet.gitsource.RunFromGitResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_gitsource_proto_init() }
+func file_gitsource_proto_init() {
+	if File_gitsource_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gitsource_proto_rawDesc), len(file_gitsource_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gitsource_proto_goTypes,
+		DependencyIndexes: file_gitsource_proto_depIdxs,
+		MessageInfos:      file_gitsource_proto_msgTypes,
+	}.Build()
+	File_gitsource_proto = out.File
+	file_gitsource_proto_goTypes = nil
+	file_gitsource_proto_depIdxs = nil
+}
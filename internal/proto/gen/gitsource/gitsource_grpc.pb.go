@@ -0,0 +1,139 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: gitsource.proto
+
+package gitsource
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GitSourceService_RunFromGit_FullMethodName = "/joblet.gitsource.GitSourceService/RunFromGit"
+)
+
+// GitSourceServiceClient is the client API for GitSourceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GitSourceService runs workflows and job specs straight from a Git
+// repository, so clients don't need a checkout of it.
+//
+// The server fetches the ref with its own deploy keys, reads the spec and the
+// files its jobs upload, and starts it as a workflow; a job spec becomes a
+// workflow of that one job. Only repositories the server's git_sources allow
+// can be fetched.
+type GitSourceServiceClient interface {
+	// Fetch a ref and run the spec at a path of it
+	RunFromGit(ctx context.Context, in *RunFromGitRequest, opts ...grpc.CallOption) (*RunFromGitResponse, error)
+}
+
+type gitSourceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGitSourceServiceClient(cc grpc.ClientConnInterface) GitSourceServiceClient {
+	return &gitSourceServiceClient{cc}
+}
+
+func (c *gitSourceServiceClient) RunFromGit(ctx context.Context, in *RunFromGitRequest, opts ...grpc.CallOption) (*RunFromGitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunFromGitResponse)
+	err := c.cc.Invoke(ctx, GitSourceService_RunFromGit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GitSourceServiceServer is the server API for GitSourceService service.
+// All implementations must embed UnimplementedGitSourceServiceServer
+// for forward compatibility.
+//
+// GitSourceService runs workflows and job specs straight from a Git
+// repository, so clients don't need a checkout of it.
+//
+// The server fetches the ref with its own deploy keys, reads the spec and the
+// files its jobs upload, and starts it as a workflow; a job spec becomes a
+// workflow of that one job. Only repositories the server's git_sources allow
+// can be fetched.
+type GitSourceServiceServer interface {
+	// Fetch a ref and run the spec at a path of it
+	RunFromGit(context.Context, *RunFromGitRequest) (*RunFromGitResponse, error)
+	mustEmbedUnimplementedGitSourceServiceServer()
+}
+
+// UnimplementedGitSourceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGitSourceServiceServer struct{}
+
+func (UnimplementedGitSourceServiceServer) RunFromGit(context.Context, *RunFromGitRequest) (*RunFromGitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunFromGit not implemented")
+}
+func (UnimplementedGitSourceServiceServer) mustEmbedUnimplementedGitSourceServiceServer() {}
+func (UnimplementedGitSourceServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeGitSourceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GitSourceServiceServer will
+// result in compilation errors.
+type UnsafeGitSourceServiceServer interface {
+	mustEmbedUnimplementedGitSourceServiceServer()
+}
+
+func RegisterGitSourceServiceServer(s grpc.ServiceRegistrar, srv GitSourceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGitSourceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GitSourceService_ServiceDesc, srv)
+}
+
+func _GitSourceService_RunFromGit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunFromGitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GitSourceServiceServer).RunFromGit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GitSourceService_RunFromGit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GitSourceServiceServer).RunFromGit(ctx, req.(*RunFromGitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GitSourceService_ServiceDesc is the grpc.ServiceDesc for GitSourceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GitSourceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.gitsource.GitSourceService",
+	HandlerType: (*GitSourceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RunFromGit",
+			Handler:    _GitSourceService_RunFromGit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gitsource.proto",
+}
@@ -0,0 +1,348 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: ids.proto
+
+package ids
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// IdType is the kind of resource a UUID names
+type IdType int32
+
+const (
+	IdType_ID_TYPE_ANY      IdType = 0
+	IdType_ID_TYPE_JOB      IdType = 1
+	IdType_ID_TYPE_WORKFLOW IdType = 2
+)
+
+// Enum value maps for IdType.
+var (
+	IdType_name = map[int32]string{
+		0: "ID_TYPE_ANY",
+		1: "ID_TYPE_JOB",
+		2: "ID_TYPE_WORKFLOW",
+	}
+	IdType_value = map[string]int32{
+		"ID_TYPE_ANY":      0,
+		"ID_TYPE_JOB":      1,
+		"ID_TYPE_WORKFLOW": 2,
+	}
+)
+
+func (x IdType) Enum() *IdType {
+	p := new(IdType)
+	*p = x
+	return p
+}
+
+func (x IdType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IdType) Descriptor() protoreflect.EnumDescriptor {
+	return file_ids_proto_enumTypes[0].Descriptor()
+}
+
+func (IdType) Type() protoreflect.EnumType {
+	return &file_ids_proto_enumTypes[0]
+}
+
+func (x IdType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IdType.Descriptor instead.
+func (IdType) EnumDescriptor() ([]byte, []int) {
+	return file_ids_proto_rawDescGZIP(), []int{0}
+}
+
+// ResolveIdRequest selects the UUIDs to list
+type ResolveIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"` // Start of the UUID ("" = every UUID)
+	Type          IdType                 `protobuf:"varint,2,opt,name=type,proto3,enum=joblet.ids.IdType" json:"type,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // Most matches returned (0 = 20)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveIdRequest) Reset() {
+	*x = ResolveIdRequest{}
+	mi := &file_ids_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveIdRequest) ProtoMessage() {}
+
+func (x *ResolveIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ids_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveIdRequest.ProtoReflect.Descriptor instead.
+func (*ResolveIdRequest) Descriptor() ([]byte, []int) {
+	return file_ids_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResolveIdRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ResolveIdRequest) GetType() IdType {
+	if x != nil {
+		return x.Type
+	}
+	return IdType_ID_TYPE_ANY
+}
+
+func (x *ResolveIdRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// IdMatch is a job or workflow whose UUID starts with the prefix
+type IdMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          IdType                 `protobuf:"varint,1,opt,name=type,proto3,enum=joblet.ids.IdType" json:"type,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"` // Job name, or workflow name or file
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdMatch) Reset() {
+	*x = IdMatch{}
+	mi := &file_ids_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdMatch) ProtoMessage() {}
+
+func (x *IdMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_ids_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdMatch.ProtoReflect.Descriptor instead.
+func (*IdMatch) Descriptor() ([]byte, []int) {
+	return file_ids_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IdMatch) GetType() IdType {
+	if x != nil {
+		return x.Type
+	}
+	return IdType_ID_TYPE_ANY
+}
+
+func (x *IdMatch) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *IdMatch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IdMatch) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *IdMatch) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// ResolveIdResponse lists the matches, jobs before workflows and each by UUID
+type ResolveIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*IdMatch             `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // Every match, also those past the limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveIdResponse) Reset() {
+	*x = ResolveIdResponse{}
+	mi := &file_ids_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveIdResponse) ProtoMessage() {}
+
+func (x *ResolveIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ids_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveIdResponse.ProtoReflect.Descriptor instead.
+func (*ResolveIdResponse) Descriptor() ([]byte, []int) {
+	return file_ids_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResolveIdResponse) GetMatches() []*IdMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *ResolveIdResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_ids_proto protoreflect.FileDescriptor
+
+const file_ids_proto_rawDesc = "" +
+	"\n" +
+	"\tids.proto\x12\n" +
+	"joblet.ids\"h\n" +
+	"\x10ResolveIdRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12&\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x12.joblet.ids.IdTypeR\x04type\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x90\x01\n" +
+	"\aIdMatch\x12&\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x12.joblet.ids.IdTypeR\x04type\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\"X\n" +
+	"\x11ResolveIdResponse\x12-\n" +
+	"\amatches\x18\x01 \x03(\v2\x13.joblet.ids.IdMatchR\amatches\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total*@\n" +
+	"\x06IdType\x12\x0f\n" +
+	"\vID_TYPE_ANY\x10\x00\x12\x0f\n" +
+	"\vID_TYPE_JOB\x10\x01\x12\x14\n" +
+	"\x10ID_TYPE_WORKFLOW\x10\x022]\n" +
+	"\x11IdResolverService\x12H\n" +
+	"\tResolveId\x12\x1c.joblet.ids.ResolveIdRequest\x1a\x1d.joblet.ids.ResolveIdResponseB4Z2github.com/ehsaniara/joblet/internal/proto/gen/idsb\x06proto3"
+
+var (
+	file_ids_proto_rawDescOnce sync.Once
+	file_ids_proto_rawDescData []byte
+)
+
+func file_ids_proto_rawDescGZIP() []byte {
+	file_ids_proto_rawDescOnce.Do(func() {
+		file_ids_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ids_proto_rawDesc), len(file_ids_proto_rawDesc)))
+	})
+	return file_ids_proto_rawDescData
+}
+
+var file_ids_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_ids_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_ids_proto_goTypes = []any{
+	(IdType)(0),               // 0: joblet.ids.IdType
+	(*ResolveIdRequest)(nil),  // 1: joblet.ids.ResolveIdRequest
+	(*IdMatch)(nil),           // 2: joblet.ids.IdMatch
+	(*ResolveIdResponse)(nil), // 3: joblet.ids.ResolveIdResponse
+}
+var file_ids_proto_depIdxs = []int32{
+	0, // 0: joblet.ids.ResolveIdRequest.type:type_name -> joblet.ids.IdType
+	0, // 1: joblet.ids.IdMatch.type:type_name -> joblet.ids.IdType
+	2, // 2: joblet.ids.ResolveIdResponse.matches:type_name -> joblet.ids.IdMatch
+	1, // 3: joblet.ids.IdResolverService.ResolveId:input_type -> joblet.ids.ResolveIdRequest
+	3, // 4: joblet.ids.IdResolverService.ResolveId:output_type -> joblet.ids.ResolveIdResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_ids_proto_init() }
+func file_ids_proto_init() {
+	if File_ids_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ids_proto_rawDesc), len(file_ids_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ids_proto_goTypes,
+		DependencyIndexes: file_ids_proto_depIdxs,
+		EnumInfos:         file_ids_proto_enumTypes,
+		MessageInfos:      file_ids_proto_msgTypes,
+	}.Build()
+	File_ids_proto = out.File
+	file_ids_proto_goTypes = nil
+	file_ids_proto_depIdxs = nil
+}
@@ -0,0 +1,135 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: ids.proto
+
+package ids
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IdResolverService_ResolveId_FullMethodName = "/joblet.ids.IdResolverService/ResolveId"
+)
+
+// IdResolverServiceClient is the client API for IdResolverService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IdResolverService resolves the short UUIDs users type to the jobs and
+// workflows they may mean.
+//
+// rnx uses it to let the user pick among the matches of an ambiguous prefix
+// and to complete UUIDs in the shell.
+type IdResolverServiceClient interface {
+	// List the jobs and workflows whose UUID starts with a prefix
+	ResolveId(ctx context.Context, in *ResolveIdRequest, opts ...grpc.CallOption) (*ResolveIdResponse, error)
+}
+
+type idResolverServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIdResolverServiceClient(cc grpc.ClientConnInterface) IdResolverServiceClient {
+	return &idResolverServiceClient{cc}
+}
+
+func (c *idResolverServiceClient) ResolveId(ctx context.Context, in *ResolveIdRequest, opts ...grpc.CallOption) (*ResolveIdResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveIdResponse)
+	err := c.cc.Invoke(ctx, IdResolverService_ResolveId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IdResolverServiceServer is the server API for IdResolverService service.
+// All implementations must embed UnimplementedIdResolverServiceServer
+// for forward compatibility.
+//
+// IdResolverService resolves the short UUIDs users type to the jobs and
+// workflows they may mean.
+//
+// rnx uses it to let the user pick among the matches of an ambiguous prefix
+// and to complete UUIDs in the shell.
+type IdResolverServiceServer interface {
+	// List the jobs and workflows whose UUID starts with a prefix
+	ResolveId(context.Context, *ResolveIdRequest) (*ResolveIdResponse, error)
+	mustEmbedUnimplementedIdResolverServiceServer()
+}
+
+// UnimplementedIdResolverServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIdResolverServiceServer struct{}
+
+func (UnimplementedIdResolverServiceServer) ResolveId(context.Context, *ResolveIdRequest) (*ResolveIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveId not implemented")
+}
+func (UnimplementedIdResolverServiceServer) mustEmbedUnimplementedIdResolverServiceServer() {}
+func (UnimplementedIdResolverServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeIdResolverServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IdResolverServiceServer will
+// result in compilation errors.
+type UnsafeIdResolverServiceServer interface {
+	mustEmbedUnimplementedIdResolverServiceServer()
+}
+
+func RegisterIdResolverServiceServer(s grpc.ServiceRegistrar, srv IdResolverServiceServer) {
+	// If the following call pancis, it indicates UnimplementedIdResolverServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IdResolverService_ServiceDesc, srv)
+}
+
+func _IdResolverService_ResolveId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IdResolverServiceServer).ResolveId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IdResolverService_ResolveId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IdResolverServiceServer).ResolveId(ctx, req.(*ResolveIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IdResolverService_ServiceDesc is the grpc.ServiceDesc for IdResolverService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IdResolverService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.ids.IdResolverService",
+	HandlerType: (*IdResolverServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveId",
+			Handler:    _IdResolverService_ResolveId_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ids.proto",
+}
@@ -214,6 +214,7 @@ type LogLine struct {
 	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                      // Unix nanoseconds
 	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`                        // Sequence number
 	Content       []byte                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`                           // Log line content
+	Tenant        string                 `protobuf:"bytes,6,opt,name=tenant,proto3" json:"tenant,omitempty"`                             // Tenant of the job (empty when it belongs to none)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -283,6 +284,13 @@ func (x *LogLine) GetContent() []byte {
 	return nil
 }
 
+func (x *LogLine) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
 // Metric represents a metrics sample from a job
 type Metric struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -580,13 +588,14 @@ const file_ipc_proto_rawDesc = "" +
 	"\x06job_id\x18\x03 \x01(\tR\x05jobId\x12\x1c\n" +
 	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x1a\n" +
 	"\bsequence\x18\x05 \x01(\x04R\bsequence\x12\x12\n" +
-	"\x04data\x18\x06 \x01(\fR\x04data\"\xa4\x01\n" +
+	"\x04data\x18\x06 \x01(\fR\x04data\"\xbc\x01\n" +
 	"\aLogLine\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12.\n" +
 	"\x06stream\x18\x02 \x01(\x0e2\x16.joblet.ipc.StreamTypeR\x06stream\x12\x1c\n" +
 	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x1a\n" +
 	"\bsequence\x18\x04 \x01(\x04R\bsequence\x12\x18\n" +
-	"\acontent\x18\x05 \x01(\fR\acontent\"\x85\x01\n" +
+	"\acontent\x18\x05 \x01(\fR\acontent\x12\x16\n" +
+	"\x06tenant\x18\x06 \x01(\tR\x06tenant\"\x85\x01\n" +
 	"\x06Metric\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1c\n" +
 	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x1a\n" +
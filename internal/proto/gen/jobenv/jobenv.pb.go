@@ -0,0 +1,276 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: jobenv.proto
+
+package jobenv
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExportJobEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // "dockerfile" (default) or "oci"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportJobEnvironmentRequest) Reset() {
+	*x = ExportJobEnvironmentRequest{}
+	mi := &file_jobenv_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJobEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJobEnvironmentRequest) ProtoMessage() {}
+
+func (x *ExportJobEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobenv_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJobEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*ExportJobEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_jobenv_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExportJobEnvironmentRequest) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *ExportJobEnvironmentRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// ExportedFile is a file of the export, to write under the output directory
+type ExportedFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative path, e.g. "Dockerfile" or "config.json"
+	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"` // Permission bits
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedFile) Reset() {
+	*x = ExportedFile{}
+	mi := &file_jobenv_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedFile) ProtoMessage() {}
+
+func (x *ExportedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_jobenv_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedFile.ProtoReflect.Descriptor instead.
+func (*ExportedFile) Descriptor() ([]byte, []int) {
+	return file_jobenv_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExportedFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExportedFile) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ExportedFile) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+type ExportJobEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Files         []*ExportedFile        `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Notes         []string               `protobuf:"bytes,4,rep,name=notes,proto3" json:"notes,omitempty"` // What the export could not reproduce, e.g. uploaded files
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportJobEnvironmentResponse) Reset() {
+	*x = ExportJobEnvironmentResponse{}
+	mi := &file_jobenv_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJobEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJobEnvironmentResponse) ProtoMessage() {}
+
+func (x *ExportJobEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobenv_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJobEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*ExportJobEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_jobenv_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExportJobEnvironmentResponse) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *ExportJobEnvironmentResponse) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportJobEnvironmentResponse) GetFiles() []*ExportedFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ExportJobEnvironmentResponse) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+var File_jobenv_proto protoreflect.FileDescriptor
+
+const file_jobenv_proto_rawDesc = "" +
+	"\n" +
+	"\fjobenv.proto\x12\rjoblet.jobenv\"P\n" +
+	"\x1bExportJobEnvironmentRequest\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"P\n" +
+	"\fExportedFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\rR\x04mode\"\x9a\x01\n" +
+	"\x1cExportJobEnvironmentResponse\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x121\n" +
+	"\x05files\x18\x03 \x03(\v2\x1b.joblet.jobenv.ExportedFileR\x05files\x12\x14\n" +
+	"\x05notes\x18\x04 \x03(\tR\x05notes2\x88\x01\n" +
+	"\x15JobEnvironmentService\x12o\n" +
+	"\x14ExportJobEnvironment\x12*.joblet.jobenv.ExportJobEnvironmentRequest\x1a+.joblet.jobenv.ExportJobEnvironmentResponseB7Z5github.com/ehsaniara/joblet/internal/proto/gen/jobenvb\x06proto3"
+
+var (
+	file_jobenv_proto_rawDescOnce sync.Once
+	file_jobenv_proto_rawDescData []byte
+)
+
+func file_jobenv_proto_rawDescGZIP() []byte {
+	file_jobenv_proto_rawDescOnce.Do(func() {
+		file_jobenv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_jobenv_proto_rawDesc), len(file_jobenv_proto_rawDesc)))
+	})
+	return file_jobenv_proto_rawDescData
+}
+
+var file_jobenv_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_jobenv_proto_goTypes = []any{
+	(*ExportJobEnvironmentRequest)(nil),  // 0: joblet.jobenv.ExportJobEnvironmentRequest
+	(*ExportedFile)(nil),                 // 1: joblet.jobenv.ExportedFile
+	(*ExportJobEnvironmentResponse)(nil), // 2: joblet.jobenv.ExportJobEnvironmentResponse
+}
+var file_jobenv_proto_depIdxs = []int32{
+	1, // 0: joblet.jobenv.ExportJobEnvironmentResponse.files:type_name -> joblet.jobenv.ExportedFile
+	0, // 1: joblet.jobenv.JobEnvironmentService.ExportJobEnvironment:input_type -> joblet.jobenv.ExportJobEnvironmentRequest
+	2, // 2: joblet.jobenv.JobEnvironmentService.ExportJobEnvironment:output_type -> joblet.jobenv.ExportJobEnvironmentResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_jobenv_proto_init() }
+func file_jobenv_proto_init() {
+	if File_jobenv_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_jobenv_proto_rawDesc), len(file_jobenv_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_jobenv_proto_goTypes,
+		DependencyIndexes: file_jobenv_proto_depIdxs,
+		MessageInfos:      file_jobenv_proto_msgTypes,
+	}.Build()
+	File_jobenv_proto = out.File
+	file_jobenv_proto_goTypes = nil
+	file_jobenv_proto_depIdxs = nil
+}
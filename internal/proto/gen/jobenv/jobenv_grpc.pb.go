@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: jobenv.proto
+
+package jobenv
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	JobEnvironmentService_ExportJobEnvironment_FullMethodName = "/joblet.jobenv.JobEnvironmentService/ExportJobEnvironment"
+)
+
+// JobEnvironmentServiceClient is the client API for JobEnvironmentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// JobEnvironmentService describes the environment a job ran in as a
+// Dockerfile or an OCI runtime bundle, to reproduce it outside joblet when
+// debugging.
+//
+// The export covers the base distro of the node, the runtime's mounts, the
+// environment variables, the command and the resource limits. Secret
+// environment variables are named but never exported with their values, and
+// uploaded files are listed for the user to supply.
+type JobEnvironmentServiceClient interface {
+	// Render the environment of a job in the requested format
+	ExportJobEnvironment(ctx context.Context, in *ExportJobEnvironmentRequest, opts ...grpc.CallOption) (*ExportJobEnvironmentResponse, error)
+}
+
+type jobEnvironmentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobEnvironmentServiceClient(cc grpc.ClientConnInterface) JobEnvironmentServiceClient {
+	return &jobEnvironmentServiceClient{cc}
+}
+
+func (c *jobEnvironmentServiceClient) ExportJobEnvironment(ctx context.Context, in *ExportJobEnvironmentRequest, opts ...grpc.CallOption) (*ExportJobEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportJobEnvironmentResponse)
+	err := c.cc.Invoke(ctx, JobEnvironmentService_ExportJobEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// JobEnvironmentServiceServer is the server API for JobEnvironmentService service.
+// All implementations must embed UnimplementedJobEnvironmentServiceServer
+// for forward compatibility.
+//
+// JobEnvironmentService describes the environment a job ran in as a
+// Dockerfile or an OCI runtime bundle, to reproduce it outside joblet when
+// debugging.
+//
+// The export covers the base distro of the node, the runtime's mounts, the
+// environment variables, the command and the resource limits. Secret
+// environment variables are named but never exported with their values, and
+// uploaded files are listed for the user to supply.
+type JobEnvironmentServiceServer interface {
+	// Render the environment of a job in the requested format
+	ExportJobEnvironment(context.Context, *ExportJobEnvironmentRequest) (*ExportJobEnvironmentResponse, error)
+	mustEmbedUnimplementedJobEnvironmentServiceServer()
+}
+
+// UnimplementedJobEnvironmentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobEnvironmentServiceServer struct{}
+
+func (UnimplementedJobEnvironmentServiceServer) ExportJobEnvironment(context.Context, *ExportJobEnvironmentRequest) (*ExportJobEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportJobEnvironment not implemented")
+}
+func (UnimplementedJobEnvironmentServiceServer) mustEmbedUnimplementedJobEnvironmentServiceServer() {}
+func (UnimplementedJobEnvironmentServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeJobEnvironmentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobEnvironmentServiceServer will
+// result in compilation errors.
+type UnsafeJobEnvironmentServiceServer interface {
+	mustEmbedUnimplementedJobEnvironmentServiceServer()
+}
+
+func RegisterJobEnvironmentServiceServer(s grpc.ServiceRegistrar, srv JobEnvironmentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedJobEnvironmentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobEnvironmentService_ServiceDesc, srv)
+}
+
+func _JobEnvironmentService_ExportJobEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportJobEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobEnvironmentServiceServer).ExportJobEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobEnvironmentService_ExportJobEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobEnvironmentServiceServer).ExportJobEnvironment(ctx, req.(*ExportJobEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// JobEnvironmentService_ServiceDesc is the grpc.ServiceDesc for JobEnvironmentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobEnvironmentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.jobenv.JobEnvironmentService",
+	HandlerType: (*JobEnvironmentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExportJobEnvironment",
+			Handler:    _JobEnvironmentService_ExportJobEnvironment_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "jobenv.proto",
+}
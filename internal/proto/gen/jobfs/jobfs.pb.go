@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: jobfs.proto
+
+package jobfs
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FreezeJobFilesystemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeJobFilesystemRequest) Reset() {
+	*x = FreezeJobFilesystemRequest{}
+	mi := &file_jobfs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeJobFilesystemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeJobFilesystemRequest) ProtoMessage() {}
+
+func (x *FreezeJobFilesystemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeJobFilesystemRequest.ProtoReflect.Descriptor instead.
+func (*FreezeJobFilesystemRequest) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FreezeJobFilesystemRequest) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+type FreezeJobFilesystemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Frozen        bool                   `protobuf:"varint,1,opt,name=frozen,proto3" json:"frozen,omitempty"`                        // The job already failed and its filesystem is kept
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // When a frozen filesystem is removed, Unix nanoseconds
+	Retention     int64                  `protobuf:"varint,3,opt,name=retention,proto3" json:"retention,omitempty"`                  // How long a filesystem is kept once the job fails, in nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeJobFilesystemResponse) Reset() {
+	*x = FreezeJobFilesystemResponse{}
+	mi := &file_jobfs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeJobFilesystemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeJobFilesystemResponse) ProtoMessage() {}
+
+func (x *FreezeJobFilesystemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeJobFilesystemResponse.ProtoReflect.Descriptor instead.
+func (*FreezeJobFilesystemResponse) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FreezeJobFilesystemResponse) GetFrozen() bool {
+	if x != nil {
+		return x.Frozen
+	}
+	return false
+}
+
+func (x *FreezeJobFilesystemResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *FreezeJobFilesystemResponse) GetRetention() int64 {
+	if x != nil {
+		return x.Retention
+	}
+	return 0
+}
+
+type ListJobFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // Directory as the job saw it, e.g. "/work/out"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobFilesRequest) Reset() {
+	*x = ListJobFilesRequest{}
+	mi := &file_jobfs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobFilesRequest) ProtoMessage() {}
+
+func (x *ListJobFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobFilesRequest.ProtoReflect.Descriptor instead.
+func (*ListJobFilesRequest) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListJobFilesRequest) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *ListJobFilesRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ListJobFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*JobFile             `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // When the frozen filesystem is removed, Unix nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobFilesResponse) Reset() {
+	*x = ListJobFilesResponse{}
+	mi := &file_jobfs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobFilesResponse) ProtoMessage() {}
+
+func (x *ListJobFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobFilesResponse.ProtoReflect.Descriptor instead.
+func (*ListJobFilesResponse) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListJobFilesResponse) GetFiles() []*JobFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ListJobFilesResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type JobFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`                      // Go fs.FileMode bits, including the type
+	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"` // Unix nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobFile) Reset() {
+	*x = JobFile{}
+	mi := &file_jobfs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobFile) ProtoMessage() {}
+
+func (x *JobFile) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobFile.ProtoReflect.Descriptor instead.
+func (*JobFile) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JobFile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobFile) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *JobFile) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *JobFile) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+type ReadJobFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`      // File as the job saw it, e.g. "/work/out/result.csv"
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // First byte to send
+	Limit         int64                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`   // Bytes to send at most, 0 = to the end of the file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadJobFileRequest) Reset() {
+	*x = ReadJobFileRequest{}
+	mi := &file_jobfs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadJobFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadJobFileRequest) ProtoMessage() {}
+
+func (x *ReadJobFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadJobFileRequest.ProtoReflect.Descriptor instead.
+func (*ReadJobFileRequest) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReadJobFileRequest) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *ReadJobFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadJobFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadJobFileRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type JobFileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobFileChunk) Reset() {
+	*x = JobFileChunk{}
+	mi := &file_jobfs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobFileChunk) ProtoMessage() {}
+
+func (x *JobFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_jobfs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobFileChunk.ProtoReflect.Descriptor instead.
+func (*JobFileChunk) Descriptor() ([]byte, []int) {
+	return file_jobfs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JobFileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_jobfs_proto protoreflect.FileDescriptor
+
+const file_jobfs_proto_rawDesc = "" +
+	"\n" +
+	"\vjobfs.proto\x12\fjoblet.jobfs\"7\n" +
+	"\x1aFreezeJobFilesystemRequest\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\"r\n" +
+	"\x1bFreezeJobFilesystemResponse\x12\x16\n" +
+	"\x06frozen\x18\x01 \x01(\bR\x06frozen\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\x12\x1c\n" +
+	"\tretention\x18\x03 \x01(\x03R\tretention\"D\n" +
+	"\x13ListJobFilesRequest\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"b\n" +
+	"\x14ListJobFilesResponse\x12+\n" +
+	"\x05files\x18\x01 \x03(\v2\x15.joblet.jobfs.JobFileR\x05files\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"`\n" +
+	"\aJobFile\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\rR\x04mode\x12\x19\n" +
+	"\bmod_time\x18\x04 \x01(\x03R\amodTime\"q\n" +
+	"\x12ReadJobFileRequest\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x03R\x05limit\"\"\n" +
+	"\fJobFileChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xa8\x02\n" +
+	"\x14JobFilesystemService\x12j\n" +
+	"\x13FreezeJobFilesystem\x12(.joblet.jobfs.FreezeJobFilesystemRequest\x1a).joblet.jobfs.FreezeJobFilesystemResponse\x12U\n" +
+	"\fListJobFiles\x12!.joblet.jobfs.ListJobFilesRequest\x1a\".joblet.jobfs.ListJobFilesResponse\x12M\n" +
+	"\vReadJobFile\x12 .joblet.jobfs.ReadJobFileRequest\x1a\x1a.joblet.jobfs.JobFileChunk0\x01B6Z4github.com/ehsaniara/joblet/internal/proto/gen/jobfsb\x06proto3"
+
+var (
+	file_jobfs_proto_rawDescOnce sync.Once
+	file_jobfs_proto_rawDescData []byte
+)
+
+func file_jobfs_proto_rawDescGZIP() []byte {
+	file_jobfs_proto_rawDescOnce.Do(func() {
+		file_jobfs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_jobfs_proto_rawDesc), len(file_jobfs_proto_rawDesc)))
+	})
+	return file_jobfs_proto_rawDescData
+}
+
+var file_jobfs_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_jobfs_proto_goTypes = []any{
+	(*FreezeJobFilesystemRequest)(nil),  // 0: joblet.jobfs.FreezeJobFilesystemRequest
+	(*FreezeJobFilesystemResponse)(nil), // 1: joblet.jobfs.FreezeJobFilesystemResponse
+	(*ListJobFilesRequest)(nil),         // 2: joblet.jobfs.ListJobFilesRequest
+	(*ListJobFilesResponse)(nil),        // 3: joblet.jobfs.ListJobFilesResponse
+	(*JobFile)(nil),                     // 4: joblet.jobfs.JobFile
+	(*ReadJobFileRequest)(nil),          // 5: joblet.jobfs.ReadJobFileRequest
+	(*JobFileChunk)(nil),                // 6: joblet.jobfs.JobFileChunk
+}
+var file_jobfs_proto_depIdxs = []int32{
+	4, // 0: joblet.jobfs.ListJobFilesResponse.files:type_name -> joblet.jobfs.JobFile
+	0, // 1: joblet.jobfs.JobFilesystemService.FreezeJobFilesystem:input_type -> joblet.jobfs.FreezeJobFilesystemRequest
+	2, // 2: joblet.jobfs.JobFilesystemService.ListJobFiles:input_type -> joblet.jobfs.ListJobFilesRequest
+	5, // 3: joblet.jobfs.JobFilesystemService.ReadJobFile:input_type -> joblet.jobfs.ReadJobFileRequest
+	1, // 4: joblet.jobfs.JobFilesystemService.FreezeJobFilesystem:output_type -> joblet.jobfs.FreezeJobFilesystemResponse
+	3, // 5: joblet.jobfs.JobFilesystemService.ListJobFiles:output_type -> joblet.jobfs.ListJobFilesResponse
+	6, // 6: joblet.jobfs.JobFilesystemService.ReadJobFile:output_type -> joblet.jobfs.JobFileChunk
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_jobfs_proto_init() }
+func file_jobfs_proto_init() {
+	if File_jobfs_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_jobfs_proto_rawDesc), len(file_jobfs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_jobfs_proto_goTypes,
+		DependencyIndexes: file_jobfs_proto_depIdxs,
+		MessageInfos:      file_jobfs_proto_msgTypes,
+	}.Build()
+	File_jobfs_proto = out.File
+	file_jobfs_proto_goTypes = nil
+	file_jobfs_proto_depIdxs = nil
+}
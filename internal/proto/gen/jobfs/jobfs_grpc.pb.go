@@ -0,0 +1,223 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: jobfs.proto
+
+package jobfs
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	JobFilesystemService_FreezeJobFilesystem_FullMethodName = "/joblet.jobfs.JobFilesystemService/FreezeJobFilesystem"
+	JobFilesystemService_ListJobFiles_FullMethodName        = "/joblet.jobfs.JobFilesystemService/ListJobFiles"
+	JobFilesystemService_ReadJobFile_FullMethodName         = "/joblet.jobfs.JobFilesystemService/ReadJobFile"
+)
+
+// JobFilesystemServiceClient is the client API for JobFilesystemService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// JobFilesystemService keeps the filesystem of a failed job for postmortem
+// inspection and browses it.
+//
+// A job is armed while it has not ended. If it then fails, its root, /tmp
+// and scratch work directory are kept instead of being cleaned up, and can
+// be listed and read until the server's retention period ends. Jobs that
+// complete or are stopped are cleaned up as usual.
+type JobFilesystemServiceClient interface {
+	// Keep the job's filesystem if it fails
+	FreezeJobFilesystem(ctx context.Context, in *FreezeJobFilesystemRequest, opts ...grpc.CallOption) (*FreezeJobFilesystemResponse, error)
+	// List a directory of a frozen job filesystem
+	ListJobFiles(ctx context.Context, in *ListJobFilesRequest, opts ...grpc.CallOption) (*ListJobFilesResponse, error)
+	// Stream a file of a frozen job filesystem
+	ReadJobFile(ctx context.Context, in *ReadJobFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobFileChunk], error)
+}
+
+type jobFilesystemServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobFilesystemServiceClient(cc grpc.ClientConnInterface) JobFilesystemServiceClient {
+	return &jobFilesystemServiceClient{cc}
+}
+
+func (c *jobFilesystemServiceClient) FreezeJobFilesystem(ctx context.Context, in *FreezeJobFilesystemRequest, opts ...grpc.CallOption) (*FreezeJobFilesystemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreezeJobFilesystemResponse)
+	err := c.cc.Invoke(ctx, JobFilesystemService_FreezeJobFilesystem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobFilesystemServiceClient) ListJobFiles(ctx context.Context, in *ListJobFilesRequest, opts ...grpc.CallOption) (*ListJobFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobFilesResponse)
+	err := c.cc.Invoke(ctx, JobFilesystemService_ListJobFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobFilesystemServiceClient) ReadJobFile(ctx context.Context, in *ReadJobFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobFileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &JobFilesystemService_ServiceDesc.Streams[0], JobFilesystemService_ReadJobFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadJobFileRequest, JobFileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobFilesystemService_ReadJobFileClient = grpc.ServerStreamingClient[JobFileChunk]
+
+// JobFilesystemServiceServer is the server API for JobFilesystemService service.
+// All implementations must embed UnimplementedJobFilesystemServiceServer
+// for forward compatibility.
+//
+// JobFilesystemService keeps the filesystem of a failed job for postmortem
+// inspection and browses it.
+//
+// A job is armed while it has not ended. If it then fails, its root, /tmp
+// and scratch work directory are kept instead of being cleaned up, and can
+// be listed and read until the server's retention period ends. Jobs that
+// complete or are stopped are cleaned up as usual.
+type JobFilesystemServiceServer interface {
+	// Keep the job's filesystem if it fails
+	FreezeJobFilesystem(context.Context, *FreezeJobFilesystemRequest) (*FreezeJobFilesystemResponse, error)
+	// List a directory of a frozen job filesystem
+	ListJobFiles(context.Context, *ListJobFilesRequest) (*ListJobFilesResponse, error)
+	// Stream a file of a frozen job filesystem
+	ReadJobFile(*ReadJobFileRequest, grpc.ServerStreamingServer[JobFileChunk]) error
+	mustEmbedUnimplementedJobFilesystemServiceServer()
+}
+
+// UnimplementedJobFilesystemServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobFilesystemServiceServer struct{}
+
+func (UnimplementedJobFilesystemServiceServer) FreezeJobFilesystem(context.Context, *FreezeJobFilesystemRequest) (*FreezeJobFilesystemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeJobFilesystem not implemented")
+}
+func (UnimplementedJobFilesystemServiceServer) ListJobFiles(context.Context, *ListJobFilesRequest) (*ListJobFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobFiles not implemented")
+}
+func (UnimplementedJobFilesystemServiceServer) ReadJobFile(*ReadJobFileRequest, grpc.ServerStreamingServer[JobFileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadJobFile not implemented")
+}
+func (UnimplementedJobFilesystemServiceServer) mustEmbedUnimplementedJobFilesystemServiceServer() {}
+func (UnimplementedJobFilesystemServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeJobFilesystemServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobFilesystemServiceServer will
+// result in compilation errors.
+type UnsafeJobFilesystemServiceServer interface {
+	mustEmbedUnimplementedJobFilesystemServiceServer()
+}
+
+func RegisterJobFilesystemServiceServer(s grpc.ServiceRegistrar, srv JobFilesystemServiceServer) {
+	// If the following call pancis, it indicates UnimplementedJobFilesystemServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobFilesystemService_ServiceDesc, srv)
+}
+
+func _JobFilesystemService_FreezeJobFilesystem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeJobFilesystemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobFilesystemServiceServer).FreezeJobFilesystem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobFilesystemService_FreezeJobFilesystem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobFilesystemServiceServer).FreezeJobFilesystem(ctx, req.(*FreezeJobFilesystemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobFilesystemService_ListJobFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobFilesystemServiceServer).ListJobFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobFilesystemService_ListJobFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobFilesystemServiceServer).ListJobFiles(ctx, req.(*ListJobFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobFilesystemService_ReadJobFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadJobFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobFilesystemServiceServer).ReadJobFile(m, &grpc.GenericServerStream[ReadJobFileRequest, JobFileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobFilesystemService_ReadJobFileServer = grpc.ServerStreamingServer[JobFileChunk]
+
+// JobFilesystemService_ServiceDesc is the grpc.ServiceDesc for JobFilesystemService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobFilesystemService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.jobfs.JobFilesystemService",
+	HandlerType: (*JobFilesystemServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FreezeJobFilesystem",
+			Handler:    _JobFilesystemService_FreezeJobFilesystem_Handler,
+		},
+		{
+			MethodName: "ListJobFiles",
+			Handler:    _JobFilesystemService_ListJobFiles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadJobFile",
+			Handler:       _JobFilesystemService_ReadJobFile_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "jobfs.proto",
+}
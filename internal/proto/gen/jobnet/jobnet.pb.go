@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: jobnet.proto
+
+package jobnet
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamJobNetworkMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamJobNetworkMetricsRequest) Reset() {
+	*x = StreamJobNetworkMetricsRequest{}
+	mi := &file_jobnet_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamJobNetworkMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamJobNetworkMetricsRequest) ProtoMessage() {}
+
+func (x *StreamJobNetworkMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobnet_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamJobNetworkMetricsRequest.ProtoReflect.Descriptor instead.
+func (*StreamJobNetworkMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_jobnet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamJobNetworkMetricsRequest) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+// JobNetworkSample is the state of a job's network namespace at one point in
+// time. Counters are cumulative since the namespace was created; rates are
+// averages since the previous sample and zero on the first one.
+type JobNetworkSample struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid           string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Timestamp         int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`            // Unix nanoseconds
+	RxBytes           uint64                 `protobuf:"varint,3,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"` // All interfaces but loopback
+	TxBytes           uint64                 `protobuf:"varint,4,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	RxPackets         uint64                 `protobuf:"varint,5,opt,name=rx_packets,json=rxPackets,proto3" json:"rx_packets,omitempty"`
+	TxPackets         uint64                 `protobuf:"varint,6,opt,name=tx_packets,json=txPackets,proto3" json:"tx_packets,omitempty"`
+	RxBps             float64                `protobuf:"fixed64,7,opt,name=rx_bps,json=rxBps,proto3" json:"rx_bps,omitempty"` // Bytes per second
+	TxBps             float64                `protobuf:"fixed64,8,opt,name=tx_bps,json=txBps,proto3" json:"tx_bps,omitempty"`
+	ActiveConnections uint64                 `protobuf:"varint,9,opt,name=active_connections,json=activeConnections,proto3" json:"active_connections,omitempty"` // Established TCP connections
+	Retransmits       uint64                 `protobuf:"varint,10,opt,name=retransmits,proto3" json:"retransmits,omitempty"`                                     // TCP segments retransmitted
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *JobNetworkSample) Reset() {
+	*x = JobNetworkSample{}
+	mi := &file_jobnet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobNetworkSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobNetworkSample) ProtoMessage() {}
+
+func (x *JobNetworkSample) ProtoReflect() protoreflect.Message {
+	mi := &file_jobnet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobNetworkSample.ProtoReflect.Descriptor instead.
+func (*JobNetworkSample) Descriptor() ([]byte, []int) {
+	return file_jobnet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *JobNetworkSample) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *JobNetworkSample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetRxBytes() uint64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetTxBytes() uint64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetRxPackets() uint64 {
+	if x != nil {
+		return x.RxPackets
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetTxPackets() uint64 {
+	if x != nil {
+		return x.TxPackets
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetRxBps() float64 {
+	if x != nil {
+		return x.RxBps
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetTxBps() float64 {
+	if x != nil {
+		return x.TxBps
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetActiveConnections() uint64 {
+	if x != nil {
+		return x.ActiveConnections
+	}
+	return 0
+}
+
+func (x *JobNetworkSample) GetRetransmits() uint64 {
+	if x != nil {
+		return x.Retransmits
+	}
+	return 0
+}
+
+var File_jobnet_proto protoreflect.FileDescriptor
+
+const file_jobnet_proto_rawDesc = "" +
+	"\n" +
+	"\fjobnet.proto\x12\rjoblet.jobnet\";\n" +
+	"\x1eStreamJobNetworkMetricsRequest\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\"\xbe\x02\n" +
+	"\x10JobNetworkSample\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x19\n" +
+	"\brx_bytes\x18\x03 \x01(\x04R\arxBytes\x12\x19\n" +
+	"\btx_bytes\x18\x04 \x01(\x04R\atxBytes\x12\x1d\n" +
+	"\n" +
+	"rx_packets\x18\x05 \x01(\x04R\trxPackets\x12\x1d\n" +
+	"\n" +
+	"tx_packets\x18\x06 \x01(\x04R\ttxPackets\x12\x15\n" +
+	"\x06rx_bps\x18\a \x01(\x01R\x05rxBps\x12\x15\n" +
+	"\x06tx_bps\x18\b \x01(\x01R\x05txBps\x12-\n" +
+	"\x12active_connections\x18\t \x01(\x04R\x11activeConnections\x12 \n" +
+	"\vretransmits\x18\n" +
+	" \x01(\x04R\vretransmits2\x80\x01\n" +
+	"\x11JobNetworkService\x12k\n" +
+	"\x17StreamJobNetworkMetrics\x12-.joblet.jobnet.StreamJobNetworkMetricsRequest\x1a\x1f.joblet.jobnet.JobNetworkSample0\x01B7Z5github.com/ehsaniara/joblet/internal/proto/gen/jobnetb\x06proto3"
+
+var (
+	file_jobnet_proto_rawDescOnce sync.Once
+	file_jobnet_proto_rawDescData []byte
+)
+
+func file_jobnet_proto_rawDescGZIP() []byte {
+	file_jobnet_proto_rawDescOnce.Do(func() {
+		file_jobnet_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_jobnet_proto_rawDesc), len(file_jobnet_proto_rawDesc)))
+	})
+	return file_jobnet_proto_rawDescData
+}
+
+var file_jobnet_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_jobnet_proto_goTypes = []any{
+	(*StreamJobNetworkMetricsRequest)(nil), // 0: joblet.jobnet.StreamJobNetworkMetricsRequest
+	(*JobNetworkSample)(nil),               // 1: joblet.jobnet.JobNetworkSample
+}
+var file_jobnet_proto_depIdxs = []int32{
+	0, // 0: joblet.jobnet.JobNetworkService.StreamJobNetworkMetrics:input_type -> joblet.jobnet.StreamJobNetworkMetricsRequest
+	1, // 1: joblet.jobnet.JobNetworkService.StreamJobNetworkMetrics:output_type -> joblet.jobnet.JobNetworkSample
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_jobnet_proto_init() }
+func file_jobnet_proto_init() {
+	if File_jobnet_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_jobnet_proto_rawDesc), len(file_jobnet_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_jobnet_proto_goTypes,
+		DependencyIndexes: file_jobnet_proto_depIdxs,
+		MessageInfos:      file_jobnet_proto_msgTypes,
+	}.Build()
+	File_jobnet_proto = out.File
+	file_jobnet_proto_goTypes = nil
+	file_jobnet_proto_depIdxs = nil
+}
@@ -0,0 +1,140 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: jobnet.proto
+
+package jobnet
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	JobNetworkService_StreamJobNetworkMetrics_FullMethodName = "/joblet.jobnet.JobNetworkService/StreamJobNetworkMetrics"
+)
+
+// JobNetworkServiceClient is the client API for JobNetworkService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// JobNetworkService streams the network counters of a job's network
+// namespace, including the TCP state the public JobMetricsSample has no
+// fields for.
+//
+// Counters are read from the job's namespace every metrics interval; jobs
+// sharing the host's network have none.
+type JobNetworkServiceClient interface {
+	// Stream the job's network samples, the recent ones first, until it ends
+	StreamJobNetworkMetrics(ctx context.Context, in *StreamJobNetworkMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobNetworkSample], error)
+}
+
+type jobNetworkServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobNetworkServiceClient(cc grpc.ClientConnInterface) JobNetworkServiceClient {
+	return &jobNetworkServiceClient{cc}
+}
+
+func (c *jobNetworkServiceClient) StreamJobNetworkMetrics(ctx context.Context, in *StreamJobNetworkMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobNetworkSample], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &JobNetworkService_ServiceDesc.Streams[0], JobNetworkService_StreamJobNetworkMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamJobNetworkMetricsRequest, JobNetworkSample]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobNetworkService_StreamJobNetworkMetricsClient = grpc.ServerStreamingClient[JobNetworkSample]
+
+// JobNetworkServiceServer is the server API for JobNetworkService service.
+// All implementations must embed UnimplementedJobNetworkServiceServer
+// for forward compatibility.
+//
+// JobNetworkService streams the network counters of a job's network
+// namespace, including the TCP state the public JobMetricsSample has no
+// fields for.
+//
+// Counters are read from the job's namespace every metrics interval; jobs
+// sharing the host's network have none.
+type JobNetworkServiceServer interface {
+	// Stream the job's network samples, the recent ones first, until it ends
+	StreamJobNetworkMetrics(*StreamJobNetworkMetricsRequest, grpc.ServerStreamingServer[JobNetworkSample]) error
+	mustEmbedUnimplementedJobNetworkServiceServer()
+}
+
+// UnimplementedJobNetworkServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobNetworkServiceServer struct{}
+
+func (UnimplementedJobNetworkServiceServer) StreamJobNetworkMetrics(*StreamJobNetworkMetricsRequest, grpc.ServerStreamingServer[JobNetworkSample]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamJobNetworkMetrics not implemented")
+}
+func (UnimplementedJobNetworkServiceServer) mustEmbedUnimplementedJobNetworkServiceServer() {}
+func (UnimplementedJobNetworkServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeJobNetworkServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobNetworkServiceServer will
+// result in compilation errors.
+type UnsafeJobNetworkServiceServer interface {
+	mustEmbedUnimplementedJobNetworkServiceServer()
+}
+
+func RegisterJobNetworkServiceServer(s grpc.ServiceRegistrar, srv JobNetworkServiceServer) {
+	// If the following call pancis, it indicates UnimplementedJobNetworkServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobNetworkService_ServiceDesc, srv)
+}
+
+func _JobNetworkService_StreamJobNetworkMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamJobNetworkMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobNetworkServiceServer).StreamJobNetworkMetrics(m, &grpc.GenericServerStream[StreamJobNetworkMetricsRequest, JobNetworkSample]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobNetworkService_StreamJobNetworkMetricsServer = grpc.ServerStreamingServer[JobNetworkSample]
+
+// JobNetworkService_ServiceDesc is the grpc.ServiceDesc for JobNetworkService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobNetworkService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.jobnet.JobNetworkService",
+	HandlerType: (*JobNetworkServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamJobNetworkMetrics",
+			Handler:       _JobNetworkService_StreamJobNetworkMetrics_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "jobnet.proto",
+}
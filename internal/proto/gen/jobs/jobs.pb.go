@@ -0,0 +1,1137 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: jobs.proto
+
+package jobs
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ListJobGroupsRequest optionally selects one group
+type ListJobGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // Only this group, empty = all groups
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobGroupsRequest) Reset() {
+	*x = ListJobGroupsRequest{}
+	mi := &file_jobs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobGroupsRequest) ProtoMessage() {}
+
+func (x *ListJobGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListJobGroupsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// JobGroup is a set of jobs submitted under the same group name
+type JobGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	JobUuids      []string               `protobuf:"bytes,2,rep,name=job_uuids,json=jobUuids,proto3" json:"job_uuids,omitempty"`                                                                                        // In start order
+	StatusCounts  map[string]int32       `protobuf:"bytes,3,rep,name=status_counts,json=statusCounts,proto3" json:"status_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Jobs per status, e.g. RUNNING: 12
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobGroup) Reset() {
+	*x = JobGroup{}
+	mi := &file_jobs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobGroup) ProtoMessage() {}
+
+func (x *JobGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobGroup.ProtoReflect.Descriptor instead.
+func (*JobGroup) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *JobGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobGroup) GetJobUuids() []string {
+	if x != nil {
+		return x.JobUuids
+	}
+	return nil
+}
+
+func (x *JobGroup) GetStatusCounts() map[string]int32 {
+	if x != nil {
+		return x.StatusCounts
+	}
+	return nil
+}
+
+type ListJobGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*JobGroup            `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"` // Sorted by name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobGroupsResponse) Reset() {
+	*x = ListJobGroupsResponse{}
+	mi := &file_jobs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobGroupsResponse) ProtoMessage() {}
+
+func (x *ListJobGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListJobGroupsResponse) GetGroups() []*JobGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+// StopJobGroupRequest names the group to stop
+type StopJobGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopJobGroupRequest) Reset() {
+	*x = StopJobGroupRequest{}
+	mi := &file_jobs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopJobGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopJobGroupRequest) ProtoMessage() {}
+
+func (x *StopJobGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopJobGroupRequest.ProtoReflect.Descriptor instead.
+func (*StopJobGroupRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StopJobGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// StopAllJobsRequest selects the jobs to stop. Filters combine with AND;
+// without filters every running and scheduled job is stopped.
+type StopAllJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`                                                                       // RUNNING and/or SCHEDULED, empty = both
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Jobs carrying all of these labels
+	Group         string                 `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`                                                                             // Jobs of this job group
+	WorkflowUuid  string                 `protobuf:"bytes,4,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`                                           // Jobs of this workflow
+	JobUuids      []string               `protobuf:"bytes,5,rep,name=job_uuids,json=jobUuids,proto3" json:"job_uuids,omitempty"`                                                       // Only these jobs, e.g. the ones a dry run listed
+	DryRun        bool                   `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                            // Report the matching jobs without stopping them
+	ArrayUuid     string                 `protobuf:"bytes,7,opt,name=array_uuid,json=arrayUuid,proto3" json:"array_uuid,omitempty"`                                                    // Jobs of this job array
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopAllJobsRequest) Reset() {
+	*x = StopAllJobsRequest{}
+	mi := &file_jobs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopAllJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopAllJobsRequest) ProtoMessage() {}
+
+func (x *StopAllJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopAllJobsRequest.ProtoReflect.Descriptor instead.
+func (*StopAllJobsRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StopAllJobsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *StopAllJobsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *StopAllJobsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *StopAllJobsRequest) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *StopAllJobsRequest) GetJobUuids() []string {
+	if x != nil {
+		return x.JobUuids
+	}
+	return nil
+}
+
+func (x *StopAllJobsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *StopAllJobsRequest) GetArrayUuid() string {
+	if x != nil {
+		return x.ArrayUuid
+	}
+	return ""
+}
+
+// StopWorkflowJobsRequest names the workflow whose jobs to stop
+type StopWorkflowJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopWorkflowJobsRequest) Reset() {
+	*x = StopWorkflowJobsRequest{}
+	mi := &file_jobs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopWorkflowJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopWorkflowJobsRequest) ProtoMessage() {}
+
+func (x *StopWorkflowJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopWorkflowJobsRequest.ProtoReflect.Descriptor instead.
+func (*StopWorkflowJobsRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StopWorkflowJobsRequest) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+// JobStopFailure is a job that could not be stopped
+type JobStopFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobUuid       string                 `protobuf:"bytes,1,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStopFailure) Reset() {
+	*x = JobStopFailure{}
+	mi := &file_jobs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStopFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStopFailure) ProtoMessage() {}
+
+func (x *JobStopFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStopFailure.ProtoReflect.Descriptor instead.
+func (*JobStopFailure) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JobStopFailure) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *JobStopFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// StopJobsResponse reports the outcome of a bulk stop
+type StopJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stopped       []string               `protobuf:"bytes,1,rep,name=stopped,proto3" json:"stopped,omitempty"` // Jobs stopped or canceled
+	Failed        []*JobStopFailure      `protobuf:"bytes,2,rep,name=failed,proto3" json:"failed,omitempty"`
+	Skipped       int32                  `protobuf:"varint,3,opt,name=skipped,proto3" json:"skipped,omitempty"`             // Matching jobs that had already ended
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Nothing was stopped; stopped lists the jobs that would be
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopJobsResponse) Reset() {
+	*x = StopJobsResponse{}
+	mi := &file_jobs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopJobsResponse) ProtoMessage() {}
+
+func (x *StopJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopJobsResponse.ProtoReflect.Descriptor instead.
+func (*StopJobsResponse) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StopJobsResponse) GetStopped() []string {
+	if x != nil {
+		return x.Stopped
+	}
+	return nil
+}
+
+func (x *StopJobsResponse) GetFailed() []*JobStopFailure {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+func (x *StopJobsResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *StopJobsResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type GetRetentionPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRetentionPolicyRequest) Reset() {
+	*x = GetRetentionPolicyRequest{}
+	mi := &file_jobs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *GetRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{8}
+}
+
+// RetentionPolicy is how long finished job records are kept in the job store
+// before the reaper removes them
+type RetentionPolicy struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Enabled         bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`                                        // A keep_last or max_age limit is set
+	KeepLast        int32                  `protobuf:"varint,2,opt,name=keep_last,json=keepLast,proto3" json:"keep_last,omitempty"`                      // Finished jobs kept per name, 0 = no limit
+	MaxAgeSeconds   int64                  `protobuf:"varint,3,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`     // Finished jobs older than this are removed, 0 = no limit
+	IntervalSeconds int64                  `protobuf:"varint,4,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // Time between sweeps
+	Archive         bool                   `protobuf:"varint,5,opt,name=archive,proto3" json:"archive,omitempty"`                                        // Records are archived to persist before removal
+	LastSweep       int64                  `protobuf:"varint,6,opt,name=last_sweep,json=lastSweep,proto3" json:"last_sweep,omitempty"`                   // Unix seconds of the last sweep, 0 = none yet
+	LastRemoved     int32                  `protobuf:"varint,7,opt,name=last_removed,json=lastRemoved,proto3" json:"last_removed,omitempty"`             // Records removed by the last sweep
+	TotalRemoved    int64                  `protobuf:"varint,8,opt,name=total_removed,json=totalRemoved,proto3" json:"total_removed,omitempty"`          // Records removed since the server started
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RetentionPolicy) Reset() {
+	*x = RetentionPolicy{}
+	mi := &file_jobs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionPolicy) ProtoMessage() {}
+
+func (x *RetentionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionPolicy.ProtoReflect.Descriptor instead.
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RetentionPolicy) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RetentionPolicy) GetKeepLast() int32 {
+	if x != nil {
+		return x.KeepLast
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetMaxAgeSeconds() int64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
+func (x *RetentionPolicy) GetLastSweep() int64 {
+	if x != nil {
+		return x.LastSweep
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetLastRemoved() int32 {
+	if x != nil {
+		return x.LastRemoved
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetTotalRemoved() int64 {
+	if x != nil {
+		return x.TotalRemoved
+	}
+	return 0
+}
+
+type JobDurationHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`      // Workflow job names
+	Samples       int32                  `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"` // Most recent runs per name to average, 0 = server default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobDurationHistoryRequest) Reset() {
+	*x = JobDurationHistoryRequest{}
+	mi := &file_jobs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDurationHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDurationHistoryRequest) ProtoMessage() {}
+
+func (x *JobDurationHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDurationHistoryRequest.ProtoReflect.Descriptor instead.
+func (*JobDurationHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *JobDurationHistoryRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *JobDurationHistoryRequest) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+// JobDurationStats summarizes the recent completed runs of one job name
+type JobDurationStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Samples       int32                  `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"` // Runs found, from the job store and persist archives
+	MeanSeconds   float64                `protobuf:"fixed64,3,opt,name=mean_seconds,json=meanSeconds,proto3" json:"mean_seconds,omitempty"`
+	LastSeconds   float64                `protobuf:"fixed64,4,opt,name=last_seconds,json=lastSeconds,proto3" json:"last_seconds,omitempty"` // Most recent run
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobDurationStats) Reset() {
+	*x = JobDurationStats{}
+	mi := &file_jobs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDurationStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDurationStats) ProtoMessage() {}
+
+func (x *JobDurationStats) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDurationStats.ProtoReflect.Descriptor instead.
+func (*JobDurationStats) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *JobDurationStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobDurationStats) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *JobDurationStats) GetMeanSeconds() float64 {
+	if x != nil {
+		return x.MeanSeconds
+	}
+	return 0
+}
+
+func (x *JobDurationStats) GetLastSeconds() float64 {
+	if x != nil {
+		return x.LastSeconds
+	}
+	return 0
+}
+
+type JobDurationHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*JobDurationStats    `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"` // Names without a completed run are left out
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobDurationHistory) Reset() {
+	*x = JobDurationHistory{}
+	mi := &file_jobs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDurationHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDurationHistory) ProtoMessage() {}
+
+func (x *JobDurationHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDurationHistory.ProtoReflect.Descriptor instead.
+func (*JobDurationHistory) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *JobDurationHistory) GetStats() []*JobDurationStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// GetJobArrayRequest names a job array by its UUID or a unique prefix of it
+type GetJobArrayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobArrayRequest) Reset() {
+	*x = GetJobArrayRequest{}
+	mi := &file_jobs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobArrayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobArrayRequest) ProtoMessage() {}
+
+func (x *GetJobArrayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobArrayRequest.ProtoReflect.Descriptor instead.
+func (*GetJobArrayRequest) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetJobArrayRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+// JobArrayTask is one job of a job array
+type JobArrayTask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // JOB_ARRAY_INDEX of the job
+	JobUuid       string                 `protobuf:"bytes,2,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	ExitCode      int32                  `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobArrayTask) Reset() {
+	*x = JobArrayTask{}
+	mi := &file_jobs_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobArrayTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobArrayTask) ProtoMessage() {}
+
+func (x *JobArrayTask) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobArrayTask.ProtoReflect.Descriptor instead.
+func (*JobArrayTask) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *JobArrayTask) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *JobArrayTask) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *JobArrayTask) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobArrayTask) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+// JobArray is the set of jobs started by one array submission
+type JobArray struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`                                                                                                               // Jobs still in the job store
+	Tasks         []*JobArrayTask        `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`                                                                                                              // Ordered by index
+	StatusCounts  map[string]int32       `protobuf:"bytes,4,rep,name=status_counts,json=statusCounts,proto3" json:"status_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Jobs per status, e.g. COMPLETED: 97
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobArray) Reset() {
+	*x = JobArray{}
+	mi := &file_jobs_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobArray) ProtoMessage() {}
+
+func (x *JobArray) ProtoReflect() protoreflect.Message {
+	mi := &file_jobs_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobArray.ProtoReflect.Descriptor instead.
+func (*JobArray) Descriptor() ([]byte, []int) {
+	return file_jobs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *JobArray) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *JobArray) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *JobArray) GetTasks() []*JobArrayTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *JobArray) GetStatusCounts() map[string]int32 {
+	if x != nil {
+		return x.StatusCounts
+	}
+	return nil
+}
+
+var File_jobs_proto protoreflect.FileDescriptor
+
+const file_jobs_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"jobs.proto\x12\vjoblet.jobs\"*\n" +
+	"\x14ListJobGroupsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xca\x01\n" +
+	"\bJobGroup\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tjob_uuids\x18\x02 \x03(\tR\bjobUuids\x12L\n" +
+	"\rstatus_counts\x18\x03 \x03(\v2'.joblet.jobs.JobGroup.StatusCountsEntryR\fstatusCounts\x1a?\n" +
+	"\x11StatusCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"F\n" +
+	"\x15ListJobGroupsResponse\x12-\n" +
+	"\x06groups\x18\x01 \x03(\v2\x15.joblet.jobs.JobGroupR\x06groups\")\n" +
+	"\x13StopJobGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xc0\x02\n" +
+	"\x12StopAllJobsRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12C\n" +
+	"\x06labels\x18\x02 \x03(\v2+.joblet.jobs.StopAllJobsRequest.LabelsEntryR\x06labels\x12\x14\n" +
+	"\x05group\x18\x03 \x01(\tR\x05group\x12#\n" +
+	"\rworkflow_uuid\x18\x04 \x01(\tR\fworkflowUuid\x12\x1b\n" +
+	"\tjob_uuids\x18\x05 \x03(\tR\bjobUuids\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun\x12\x1d\n" +
+	"\n" +
+	"array_uuid\x18\a \x01(\tR\tarrayUuid\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\">\n" +
+	"\x17StopWorkflowJobsRequest\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\"A\n" +
+	"\x0eJobStopFailure\x12\x19\n" +
+	"\bjob_uuid\x18\x01 \x01(\tR\ajobUuid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x94\x01\n" +
+	"\x10StopJobsResponse\x12\x18\n" +
+	"\astopped\x18\x01 \x03(\tR\astopped\x123\n" +
+	"\x06failed\x18\x02 \x03(\v2\x1b.joblet.jobs.JobStopFailureR\x06failed\x12\x18\n" +
+	"\askipped\x18\x03 \x01(\x05R\askipped\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"\x1b\n" +
+	"\x19GetRetentionPolicyRequest\"\x9c\x02\n" +
+	"\x0fRetentionPolicy\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1b\n" +
+	"\tkeep_last\x18\x02 \x01(\x05R\bkeepLast\x12&\n" +
+	"\x0fmax_age_seconds\x18\x03 \x01(\x03R\rmaxAgeSeconds\x12)\n" +
+	"\x10interval_seconds\x18\x04 \x01(\x03R\x0fintervalSeconds\x12\x18\n" +
+	"\aarchive\x18\x05 \x01(\bR\aarchive\x12\x1d\n" +
+	"\n" +
+	"last_sweep\x18\x06 \x01(\x03R\tlastSweep\x12!\n" +
+	"\flast_removed\x18\a \x01(\x05R\vlastRemoved\x12#\n" +
+	"\rtotal_removed\x18\b \x01(\x03R\ftotalRemoved\"K\n" +
+	"\x19JobDurationHistoryRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\x12\x18\n" +
+	"\asamples\x18\x02 \x01(\x05R\asamples\"\x86\x01\n" +
+	"\x10JobDurationStats\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\asamples\x18\x02 \x01(\x05R\asamples\x12!\n" +
+	"\fmean_seconds\x18\x03 \x01(\x01R\vmeanSeconds\x12!\n" +
+	"\flast_seconds\x18\x04 \x01(\x01R\vlastSeconds\"I\n" +
+	"\x12JobDurationHistory\x123\n" +
+	"\x05stats\x18\x01 \x03(\v2\x1d.joblet.jobs.JobDurationStatsR\x05stats\"(\n" +
+	"\x12GetJobArrayRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"t\n" +
+	"\fJobArrayTask\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x19\n" +
+	"\bjob_uuid\x18\x02 \x01(\tR\ajobUuid\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1b\n" +
+	"\texit_code\x18\x04 \x01(\x05R\bexitCode\"\xf2\x01\n" +
+	"\bJobArray\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\x12/\n" +
+	"\x05tasks\x18\x03 \x03(\v2\x19.joblet.jobs.JobArrayTaskR\x05tasks\x12L\n" +
+	"\rstatus_counts\x18\x04 \x03(\v2'.joblet.jobs.JobArray.StatusCountsEntryR\fstatusCounts\x1a?\n" +
+	"\x11StatusCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x012\xe6\x04\n" +
+	"\x0eBulkJobService\x12V\n" +
+	"\rListJobGroups\x12!.joblet.jobs.ListJobGroupsRequest\x1a\".joblet.jobs.ListJobGroupsResponse\x12O\n" +
+	"\fStopJobGroup\x12 .joblet.jobs.StopJobGroupRequest\x1a\x1d.joblet.jobs.StopJobsResponse\x12M\n" +
+	"\vStopAllJobs\x12\x1f.joblet.jobs.StopAllJobsRequest\x1a\x1d.joblet.jobs.StopJobsResponse\x12W\n" +
+	"\x10StopWorkflowJobs\x12$.joblet.jobs.StopWorkflowJobsRequest\x1a\x1d.joblet.jobs.StopJobsResponse\x12Z\n" +
+	"\x12GetRetentionPolicy\x12&.joblet.jobs.GetRetentionPolicyRequest\x1a\x1c.joblet.jobs.RetentionPolicy\x12`\n" +
+	"\x15GetJobDurationHistory\x12&.joblet.jobs.JobDurationHistoryRequest\x1a\x1f.joblet.jobs.JobDurationHistory\x12E\n" +
+	"\vGetJobArray\x12\x1f.joblet.jobs.GetJobArrayRequest\x1a\x15.joblet.jobs.JobArrayB5Z3github.com/ehsaniara/joblet/internal/proto/gen/jobsb\x06proto3"
+
+var (
+	file_jobs_proto_rawDescOnce sync.Once
+	file_jobs_proto_rawDescData []byte
+)
+
+func file_jobs_proto_rawDescGZIP() []byte {
+	file_jobs_proto_rawDescOnce.Do(func() {
+		file_jobs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_jobs_proto_rawDesc), len(file_jobs_proto_rawDesc)))
+	})
+	return file_jobs_proto_rawDescData
+}
+
+var file_jobs_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_jobs_proto_goTypes = []any{
+	(*ListJobGroupsRequest)(nil),      // 0: joblet.jobs.ListJobGroupsRequest
+	(*JobGroup)(nil),                  // 1: joblet.jobs.JobGroup
+	(*ListJobGroupsResponse)(nil),     // 2: joblet.jobs.ListJobGroupsResponse
+	(*StopJobGroupRequest)(nil),       // 3: joblet.jobs.StopJobGroupRequest
+	(*StopAllJobsRequest)(nil),        // 4: joblet.jobs.StopAllJobsRequest
+	(*StopWorkflowJobsRequest)(nil),   // 5: joblet.jobs.StopWorkflowJobsRequest
+	(*JobStopFailure)(nil),            // 6: joblet.jobs.JobStopFailure
+	(*StopJobsResponse)(nil),          // 7: joblet.jobs.StopJobsResponse
+	(*GetRetentionPolicyRequest)(nil), // 8: joblet.jobs.GetRetentionPolicyRequest
+	(*RetentionPolicy)(nil),           // 9: joblet.jobs.RetentionPolicy
+	(*JobDurationHistoryRequest)(nil), // 10: joblet.jobs.JobDurationHistoryRequest
+	(*JobDurationStats)(nil),          // 11: joblet.jobs.JobDurationStats
+	(*JobDurationHistory)(nil),        // 12: joblet.jobs.JobDurationHistory
+	(*GetJobArrayRequest)(nil),        // 13: joblet.jobs.GetJobArrayRequest
+	(*JobArrayTask)(nil),              // 14: joblet.jobs.JobArrayTask
+	(*JobArray)(nil),                  // 15: joblet.jobs.JobArray
+	nil,                               // 16: joblet.jobs.JobGroup.StatusCountsEntry
+	nil,                               // 17: joblet.jobs.StopAllJobsRequest.LabelsEntry
+	nil,                               // 18: joblet.jobs.JobArray.StatusCountsEntry
+}
+var file_jobs_proto_depIdxs = []int32{
+	16, // 0: joblet.jobs.JobGroup.status_counts:type_name -> joblet.jobs.JobGroup.StatusCountsEntry
+	1,  // 1: joblet.jobs.ListJobGroupsResponse.groups:type_name -> joblet.jobs.JobGroup
+	17, // 2: joblet.jobs.StopAllJobsRequest.labels:type_name -> joblet.jobs.StopAllJobsRequest.LabelsEntry
+	6,  // 3: joblet.jobs.StopJobsResponse.failed:type_name -> joblet.jobs.JobStopFailure
+	11, // 4: joblet.jobs.JobDurationHistory.stats:type_name -> joblet.jobs.JobDurationStats
+	14, // 5: joblet.jobs.JobArray.tasks:type_name -> joblet.jobs.JobArrayTask
+	18, // 6: joblet.jobs.JobArray.status_counts:type_name -> joblet.jobs.JobArray.StatusCountsEntry
+	0,  // 7: joblet.jobs.BulkJobService.ListJobGroups:input_type -> joblet.jobs.ListJobGroupsRequest
+	3,  // 8: joblet.jobs.BulkJobService.StopJobGroup:input_type -> joblet.jobs.StopJobGroupRequest
+	4,  // 9: joblet.jobs.BulkJobService.StopAllJobs:input_type -> joblet.jobs.StopAllJobsRequest
+	5,  // 10: joblet.jobs.BulkJobService.StopWorkflowJobs:input_type -> joblet.jobs.StopWorkflowJobsRequest
+	8,  // 11: joblet.jobs.BulkJobService.GetRetentionPolicy:input_type -> joblet.jobs.GetRetentionPolicyRequest
+	10, // 12: joblet.jobs.BulkJobService.GetJobDurationHistory:input_type -> joblet.jobs.JobDurationHistoryRequest
+	13, // 13: joblet.jobs.BulkJobService.GetJobArray:input_type -> joblet.jobs.GetJobArrayRequest
+	2,  // 14: joblet.jobs.BulkJobService.ListJobGroups:output_type -> joblet.jobs.ListJobGroupsResponse
+	7,  // 15: joblet.jobs.BulkJobService.StopJobGroup:output_type -> joblet.jobs.StopJobsResponse
+	7,  // 16: joblet.jobs.BulkJobService.StopAllJobs:output_type -> joblet.jobs.StopJobsResponse
+	7,  // 17: joblet.jobs.BulkJobService.StopWorkflowJobs:output_type -> joblet.jobs.StopJobsResponse
+	9,  // 18: joblet.jobs.BulkJobService.GetRetentionPolicy:output_type -> joblet.jobs.RetentionPolicy
+	12, // 19: joblet.jobs.BulkJobService.GetJobDurationHistory:output_type -> joblet.jobs.JobDurationHistory
+	15, // 20: joblet.jobs.BulkJobService.GetJobArray:output_type -> joblet.jobs.JobArray
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_jobs_proto_init() }
+func file_jobs_proto_init() {
+	if File_jobs_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_jobs_proto_rawDesc), len(file_jobs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_jobs_proto_goTypes,
+		DependencyIndexes: file_jobs_proto_depIdxs,
+		MessageInfos:      file_jobs_proto_msgTypes,
+	}.Build()
+	File_jobs_proto = out.File
+	file_jobs_proto_goTypes = nil
+	file_jobs_proto_depIdxs = nil
+}
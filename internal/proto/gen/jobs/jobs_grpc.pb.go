@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: jobs.proto
+
+package jobs
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BulkJobService_ListJobGroups_FullMethodName         = "/joblet.jobs.BulkJobService/ListJobGroups"
+	BulkJobService_StopJobGroup_FullMethodName          = "/joblet.jobs.BulkJobService/StopJobGroup"
+	BulkJobService_StopAllJobs_FullMethodName           = "/joblet.jobs.BulkJobService/StopAllJobs"
+	BulkJobService_StopWorkflowJobs_FullMethodName      = "/joblet.jobs.BulkJobService/StopWorkflowJobs"
+	BulkJobService_GetRetentionPolicy_FullMethodName    = "/joblet.jobs.BulkJobService/GetRetentionPolicy"
+	BulkJobService_GetJobDurationHistory_FullMethodName = "/joblet.jobs.BulkJobService/GetJobDurationHistory"
+	BulkJobService_GetJobArray_FullMethodName           = "/joblet.jobs.BulkJobService/GetJobArray"
+)
+
+// BulkJobServiceClient is the client API for BulkJobService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BulkJobService manages many jobs with a single call.
+//
+// rnx uses it for 'rnx job list --group', 'rnx job stop --group',
+// 'rnx job stop-all', 'rnx config retention', the ETA of
+// 'rnx workflow status --watch' and the job array views of 'rnx job status'
+// and 'rnx job stop'.
+type BulkJobServiceClient interface {
+	// List job groups with their jobs and status counts
+	ListJobGroups(ctx context.Context, in *ListJobGroupsRequest, opts ...grpc.CallOption) (*ListJobGroupsResponse, error)
+	// Stop every running or scheduled job of a group
+	StopJobGroup(ctx context.Context, in *StopJobGroupRequest, opts ...grpc.CallOption) (*StopJobsResponse, error)
+	// Stop the running and scheduled jobs matching all given filters
+	StopAllJobs(ctx context.Context, in *StopAllJobsRequest, opts ...grpc.CallOption) (*StopJobsResponse, error)
+	// Stop every running or scheduled job of a workflow
+	StopWorkflowJobs(ctx context.Context, in *StopWorkflowJobsRequest, opts ...grpc.CallOption) (*StopJobsResponse, error)
+	// Show the finished job retention policy and the reaper's last sweep
+	GetRetentionPolicy(ctx context.Context, in *GetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error)
+	// Returns how long past completed runs of the named jobs took
+	GetJobDurationHistory(ctx context.Context, in *JobDurationHistoryRequest, opts ...grpc.CallOption) (*JobDurationHistory, error)
+	// Returns the jobs of a job array with their aggregated status
+	GetJobArray(ctx context.Context, in *GetJobArrayRequest, opts ...grpc.CallOption) (*JobArray, error)
+}
+
+type bulkJobServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBulkJobServiceClient(cc grpc.ClientConnInterface) BulkJobServiceClient {
+	return &bulkJobServiceClient{cc}
+}
+
+func (c *bulkJobServiceClient) ListJobGroups(ctx context.Context, in *ListJobGroupsRequest, opts ...grpc.CallOption) (*ListJobGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobGroupsResponse)
+	err := c.cc.Invoke(ctx, BulkJobService_ListJobGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkJobServiceClient) StopJobGroup(ctx context.Context, in *StopJobGroupRequest, opts ...grpc.CallOption) (*StopJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopJobsResponse)
+	err := c.cc.Invoke(ctx, BulkJobService_StopJobGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkJobServiceClient) StopAllJobs(ctx context.Context, in *StopAllJobsRequest, opts ...grpc.CallOption) (*StopJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopJobsResponse)
+	err := c.cc.Invoke(ctx, BulkJobService_StopAllJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkJobServiceClient) StopWorkflowJobs(ctx context.Context, in *StopWorkflowJobsRequest, opts ...grpc.CallOption) (*StopJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopJobsResponse)
+	err := c.cc.Invoke(ctx, BulkJobService_StopWorkflowJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkJobServiceClient) GetRetentionPolicy(ctx context.Context, in *GetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPolicy)
+	err := c.cc.Invoke(ctx, BulkJobService_GetRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkJobServiceClient) GetJobDurationHistory(ctx context.Context, in *JobDurationHistoryRequest, opts ...grpc.CallOption) (*JobDurationHistory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobDurationHistory)
+	err := c.cc.Invoke(ctx, BulkJobService_GetJobDurationHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkJobServiceClient) GetJobArray(ctx context.Context, in *GetJobArrayRequest, opts ...grpc.CallOption) (*JobArray, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobArray)
+	err := c.cc.Invoke(ctx, BulkJobService_GetJobArray_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BulkJobServiceServer is the server API for BulkJobService service.
+// All implementations must embed UnimplementedBulkJobServiceServer
+// for forward compatibility.
+//
+// BulkJobService manages many jobs with a single call.
+//
+// rnx uses it for 'rnx job list --group', 'rnx job stop --group',
+// 'rnx job stop-all', 'rnx config retention', the ETA of
+// 'rnx workflow status --watch' and the job array views of 'rnx job status'
+// and 'rnx job stop'.
+type BulkJobServiceServer interface {
+	// List job groups with their jobs and status counts
+	ListJobGroups(context.Context, *ListJobGroupsRequest) (*ListJobGroupsResponse, error)
+	// Stop every running or scheduled job of a group
+	StopJobGroup(context.Context, *StopJobGroupRequest) (*StopJobsResponse, error)
+	// Stop the running and scheduled jobs matching all given filters
+	StopAllJobs(context.Context, *StopAllJobsRequest) (*StopJobsResponse, error)
+	// Stop every running or scheduled job of a workflow
+	StopWorkflowJobs(context.Context, *StopWorkflowJobsRequest) (*StopJobsResponse, error)
+	// Show the finished job retention policy and the reaper's last sweep
+	GetRetentionPolicy(context.Context, *GetRetentionPolicyRequest) (*RetentionPolicy, error)
+	// Returns how long past completed runs of the named jobs took
+	GetJobDurationHistory(context.Context, *JobDurationHistoryRequest) (*JobDurationHistory, error)
+	// Returns the jobs of a job array with their aggregated status
+	GetJobArray(context.Context, *GetJobArrayRequest) (*JobArray, error)
+	mustEmbedUnimplementedBulkJobServiceServer()
+}
+
+// UnimplementedBulkJobServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBulkJobServiceServer struct{}
+
+func (UnimplementedBulkJobServiceServer) ListJobGroups(context.Context, *ListJobGroupsRequest) (*ListJobGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobGroups not implemented")
+}
+func (UnimplementedBulkJobServiceServer) StopJobGroup(context.Context, *StopJobGroupRequest) (*StopJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopJobGroup not implemented")
+}
+func (UnimplementedBulkJobServiceServer) StopAllJobs(context.Context, *StopAllJobsRequest) (*StopJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopAllJobs not implemented")
+}
+func (UnimplementedBulkJobServiceServer) StopWorkflowJobs(context.Context, *StopWorkflowJobsRequest) (*StopJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopWorkflowJobs not implemented")
+}
+func (UnimplementedBulkJobServiceServer) GetRetentionPolicy(context.Context, *GetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionPolicy not implemented")
+}
+func (UnimplementedBulkJobServiceServer) GetJobDurationHistory(context.Context, *JobDurationHistoryRequest) (*JobDurationHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobDurationHistory not implemented")
+}
+func (UnimplementedBulkJobServiceServer) GetJobArray(context.Context, *GetJobArrayRequest) (*JobArray, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobArray not implemented")
+}
+func (UnimplementedBulkJobServiceServer) mustEmbedUnimplementedBulkJobServiceServer() {}
+func (UnimplementedBulkJobServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeBulkJobServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BulkJobServiceServer will
+// result in compilation errors.
+type UnsafeBulkJobServiceServer interface {
+	mustEmbedUnimplementedBulkJobServiceServer()
+}
+
+func RegisterBulkJobServiceServer(s grpc.ServiceRegistrar, srv BulkJobServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBulkJobServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BulkJobService_ServiceDesc, srv)
+}
+
+func _BulkJobService_ListJobGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).ListJobGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_ListJobGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).ListJobGroups(ctx, req.(*ListJobGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkJobService_StopJobGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopJobGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).StopJobGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_StopJobGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).StopJobGroup(ctx, req.(*StopJobGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkJobService_StopAllJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopAllJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).StopAllJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_StopAllJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).StopAllJobs(ctx, req.(*StopAllJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkJobService_StopWorkflowJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopWorkflowJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).StopWorkflowJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_StopWorkflowJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).StopWorkflowJobs(ctx, req.(*StopWorkflowJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkJobService_GetRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).GetRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_GetRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).GetRetentionPolicy(ctx, req.(*GetRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkJobService_GetJobDurationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobDurationHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).GetJobDurationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_GetJobDurationHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).GetJobDurationHistory(ctx, req.(*JobDurationHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkJobService_GetJobArray_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobArrayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkJobServiceServer).GetJobArray(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkJobService_GetJobArray_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkJobServiceServer).GetJobArray(ctx, req.(*GetJobArrayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BulkJobService_ServiceDesc is the grpc.ServiceDesc for BulkJobService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BulkJobService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.jobs.BulkJobService",
+	HandlerType: (*BulkJobServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListJobGroups",
+			Handler:    _BulkJobService_ListJobGroups_Handler,
+		},
+		{
+			MethodName: "StopJobGroup",
+			Handler:    _BulkJobService_StopJobGroup_Handler,
+		},
+		{
+			MethodName: "StopAllJobs",
+			Handler:    _BulkJobService_StopAllJobs_Handler,
+		},
+		{
+			MethodName: "StopWorkflowJobs",
+			Handler:    _BulkJobService_StopWorkflowJobs_Handler,
+		},
+		{
+			MethodName: "GetRetentionPolicy",
+			Handler:    _BulkJobService_GetRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "GetJobDurationHistory",
+			Handler:    _BulkJobService_GetJobDurationHistory_Handler,
+		},
+		{
+			MethodName: "GetJobArray",
+			Handler:    _BulkJobService_GetJobArray_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "jobs.proto",
+}
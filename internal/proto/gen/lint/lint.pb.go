@@ -0,0 +1,270 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: lint.proto
+
+package lint
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LintSpecRequest carries the spec as written
+type LintSpecRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spec          []byte                 `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"` // Job spec or workflow YAML
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintSpecRequest) Reset() {
+	*x = LintSpecRequest{}
+	mi := &file_lint_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintSpecRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintSpecRequest) ProtoMessage() {}
+
+func (x *LintSpecRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lint_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintSpecRequest.ProtoReflect.Descriptor instead.
+func (*LintSpecRequest) Descriptor() ([]byte, []int) {
+	return file_lint_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LintSpecRequest) GetSpec() []byte {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+// LintFinding is one problem found in the spec
+type LintFinding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`         // e.g. "no-resource-limits"
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"` // "error", "warning" or "info"
+	Job           string                 `protobuf:"bytes,3,opt,name=job,proto3" json:"job,omitempty"`           // Workflow job the finding is about, empty otherwise
+	Field         string                 `protobuf:"bytes,4,opt,name=field,proto3" json:"field,omitempty"`       // Path of the offending field, e.g. "jobs.train.resources"
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintFinding) Reset() {
+	*x = LintFinding{}
+	mi := &file_lint_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintFinding) ProtoMessage() {}
+
+func (x *LintFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_lint_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintFinding.ProtoReflect.Descriptor instead.
+func (*LintFinding) Descriptor() ([]byte, []int) {
+	return file_lint_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LintFinding) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *LintFinding) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LintFinding) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *LintFinding) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *LintFinding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// LintSpecResponse lists the findings, job by job in name order
+type LintSpecResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // "Job" or "Workflow"
+	Findings      []*LintFinding         `protobuf:"bytes,2,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintSpecResponse) Reset() {
+	*x = LintSpecResponse{}
+	mi := &file_lint_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintSpecResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintSpecResponse) ProtoMessage() {}
+
+func (x *LintSpecResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lint_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintSpecResponse.ProtoReflect.Descriptor instead.
+func (*LintSpecResponse) Descriptor() ([]byte, []int) {
+	return file_lint_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LintSpecResponse) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *LintSpecResponse) GetFindings() []*LintFinding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+var File_lint_proto protoreflect.FileDescriptor
+
+const file_lint_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"lint.proto\x12\vjoblet.lint\"%\n" +
+	"\x0fLintSpecRequest\x12\x12\n" +
+	"\x04spec\x18\x01 \x01(\fR\x04spec\"\x7f\n" +
+	"\vLintFinding\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x10\n" +
+	"\x03job\x18\x03 \x01(\tR\x03job\x12\x14\n" +
+	"\x05field\x18\x04 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"\\\n" +
+	"\x10LintSpecResponse\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x124\n" +
+	"\bfindings\x18\x02 \x03(\v2\x18.joblet.lint.LintFindingR\bfindings2Z\n" +
+	"\x0fSpecLintService\x12G\n" +
+	"\bLintSpec\x12\x1c.joblet.lint.LintSpecRequest\x1a\x1d.joblet.lint.LintSpecResponseB5Z3github.com/ehsaniara/joblet/internal/proto/gen/lintb\x06proto3"
+
+var (
+	file_lint_proto_rawDescOnce sync.Once
+	file_lint_proto_rawDescData []byte
+)
+
+func file_lint_proto_rawDescGZIP() []byte {
+	file_lint_proto_rawDescOnce.Do(func() {
+		file_lint_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lint_proto_rawDesc), len(file_lint_proto_rawDesc)))
+	})
+	return file_lint_proto_rawDescData
+}
+
+var file_lint_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_lint_proto_goTypes = []any{
+	(*LintSpecRequest)(nil),  // 0: joblet.lint.LintSpecRequest
+	(*LintFinding)(nil),      // 1: joblet.lint.LintFinding
+	(*LintSpecResponse)(nil), // 2: joblet.lint.LintSpecResponse
+}
+var file_lint_proto_depIdxs = []int32{
+	1, // 0: joblet.lint.LintSpecResponse.findings:type_name -> joblet.lint.LintFinding
+	0, // 1: joblet.lint.SpecLintService.LintSpec:input_type -> joblet.lint.LintSpecRequest
+	2, // 2: joblet.lint.SpecLintService.LintSpec:output_type -> joblet.lint.LintSpecResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_lint_proto_init() }
+func file_lint_proto_init() {
+	if File_lint_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lint_proto_rawDesc), len(file_lint_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lint_proto_goTypes,
+		DependencyIndexes: file_lint_proto_depIdxs,
+		MessageInfos:      file_lint_proto_msgTypes,
+	}.Build()
+	File_lint_proto = out.File
+	file_lint_proto_goTypes = nil
+	file_lint_proto_depIdxs = nil
+}
@@ -0,0 +1,135 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: lint.proto
+
+package lint
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SpecLintService_LintSpec_FullMethodName = "/joblet.lint.SpecLintService/LintSpec"
+)
+
+// SpecLintServiceClient is the client API for SpecLintService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SpecLintService checks job and workflow specs against best practices.
+//
+// 'rnx lint --server' uses it to run the checks with what the node knows: its
+// runtime aliases and tenant pins, its networks and the limits it gives jobs
+// without any. Nothing is run.
+type SpecLintServiceClient interface {
+	// Lint a job spec or a workflow
+	LintSpec(ctx context.Context, in *LintSpecRequest, opts ...grpc.CallOption) (*LintSpecResponse, error)
+}
+
+type specLintServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSpecLintServiceClient(cc grpc.ClientConnInterface) SpecLintServiceClient {
+	return &specLintServiceClient{cc}
+}
+
+func (c *specLintServiceClient) LintSpec(ctx context.Context, in *LintSpecRequest, opts ...grpc.CallOption) (*LintSpecResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LintSpecResponse)
+	err := c.cc.Invoke(ctx, SpecLintService_LintSpec_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SpecLintServiceServer is the server API for SpecLintService service.
+// All implementations must embed UnimplementedSpecLintServiceServer
+// for forward compatibility.
+//
+// SpecLintService checks job and workflow specs against best practices.
+//
+// 'rnx lint --server' uses it to run the checks with what the node knows: its
+// runtime aliases and tenant pins, its networks and the limits it gives jobs
+// without any. Nothing is run.
+type SpecLintServiceServer interface {
+	// Lint a job spec or a workflow
+	LintSpec(context.Context, *LintSpecRequest) (*LintSpecResponse, error)
+	mustEmbedUnimplementedSpecLintServiceServer()
+}
+
+// UnimplementedSpecLintServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSpecLintServiceServer struct{}
+
+func (UnimplementedSpecLintServiceServer) LintSpec(context.Context, *LintSpecRequest) (*LintSpecResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LintSpec not implemented")
+}
+func (UnimplementedSpecLintServiceServer) mustEmbedUnimplementedSpecLintServiceServer() {}
+func (UnimplementedSpecLintServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeSpecLintServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SpecLintServiceServer will
+// result in compilation errors.
+type UnsafeSpecLintServiceServer interface {
+	mustEmbedUnimplementedSpecLintServiceServer()
+}
+
+func RegisterSpecLintServiceServer(s grpc.ServiceRegistrar, srv SpecLintServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSpecLintServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SpecLintService_ServiceDesc, srv)
+}
+
+func _SpecLintService_LintSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LintSpecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecLintServiceServer).LintSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecLintService_LintSpec_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecLintServiceServer).LintSpec(ctx, req.(*LintSpecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SpecLintService_ServiceDesc is the grpc.ServiceDesc for SpecLintService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SpecLintService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.lint.SpecLintService",
+	HandlerType: (*SpecLintServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LintSpec",
+			Handler:    _SpecLintService_LintSpec_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lint.proto",
+}
@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: logs.proto
+
+package logs
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LogService_DownloadJobLogs_FullMethodName = "/joblet.logs.LogService/DownloadJobLogs"
+	LogService_ReadLogRange_FullMethodName    = "/joblet.logs.LogService/ReadLogRange"
+	LogService_LocateLogRecord_FullMethodName = "/joblet.logs.LogService/LocateLogRecord"
+)
+
+// LogServiceClient is the client API for LogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LogService serves a job's persisted log for download and browsing.
+//
+// 'rnx job log save' uses it to write logs to a file and to resume an
+// interrupted download where it stopped. 'rnx job log view' reads windows of
+// the log and has the server search it, so large logs are browsed without
+// being downloaded.
+type LogServiceClient interface {
+	// Stream the persisted log of a job, starting after offset records
+	DownloadJobLogs(ctx context.Context, in *DownloadJobLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogChunk], error)
+	// Read a window of a job's persisted log records
+	ReadLogRange(ctx context.Context, in *ReadLogRangeRequest, opts ...grpc.CallOption) (*LogRange, error)
+	// Find the record matching a pattern or time, or the end of the log
+	LocateLogRecord(ctx context.Context, in *LocateLogRecordRequest, opts ...grpc.CallOption) (*LogLocation, error)
+}
+
+type logServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLogServiceClient(cc grpc.ClientConnInterface) LogServiceClient {
+	return &logServiceClient{cc}
+}
+
+func (c *logServiceClient) DownloadJobLogs(ctx context.Context, in *DownloadJobLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogService_ServiceDesc.Streams[0], LogService_DownloadJobLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadJobLogsRequest, LogChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogService_DownloadJobLogsClient = grpc.ServerStreamingClient[LogChunk]
+
+func (c *logServiceClient) ReadLogRange(ctx context.Context, in *ReadLogRangeRequest, opts ...grpc.CallOption) (*LogRange, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogRange)
+	err := c.cc.Invoke(ctx, LogService_ReadLogRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logServiceClient) LocateLogRecord(ctx context.Context, in *LocateLogRecordRequest, opts ...grpc.CallOption) (*LogLocation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogLocation)
+	err := c.cc.Invoke(ctx, LogService_LocateLogRecord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LogServiceServer is the server API for LogService service.
+// All implementations must embed UnimplementedLogServiceServer
+// for forward compatibility.
+//
+// LogService serves a job's persisted log for download and browsing.
+//
+// 'rnx job log save' uses it to write logs to a file and to resume an
+// interrupted download where it stopped. 'rnx job log view' reads windows of
+// the log and has the server search it, so large logs are browsed without
+// being downloaded.
+type LogServiceServer interface {
+	// Stream the persisted log of a job, starting after offset records
+	DownloadJobLogs(*DownloadJobLogsRequest, grpc.ServerStreamingServer[LogChunk]) error
+	// Read a window of a job's persisted log records
+	ReadLogRange(context.Context, *ReadLogRangeRequest) (*LogRange, error)
+	// Find the record matching a pattern or time, or the end of the log
+	LocateLogRecord(context.Context, *LocateLogRecordRequest) (*LogLocation, error)
+	mustEmbedUnimplementedLogServiceServer()
+}
+
+// UnimplementedLogServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLogServiceServer struct{}
+
+func (UnimplementedLogServiceServer) DownloadJobLogs(*DownloadJobLogsRequest, grpc.ServerStreamingServer[LogChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadJobLogs not implemented")
+}
+func (UnimplementedLogServiceServer) ReadLogRange(context.Context, *ReadLogRangeRequest) (*LogRange, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadLogRange not implemented")
+}
+func (UnimplementedLogServiceServer) LocateLogRecord(context.Context, *LocateLogRecordRequest) (*LogLocation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LocateLogRecord not implemented")
+}
+func (UnimplementedLogServiceServer) mustEmbedUnimplementedLogServiceServer() {}
+func (UnimplementedLogServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeLogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LogServiceServer will
+// result in compilation errors.
+type UnsafeLogServiceServer interface {
+	mustEmbedUnimplementedLogServiceServer()
+}
+
+func RegisterLogServiceServer(s grpc.ServiceRegistrar, srv LogServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLogServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LogService_ServiceDesc, srv)
+}
+
+func _LogService_DownloadJobLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadJobLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogServiceServer).DownloadJobLogs(m, &grpc.GenericServerStream[DownloadJobLogsRequest, LogChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogService_DownloadJobLogsServer = grpc.ServerStreamingServer[LogChunk]
+
+func _LogService_ReadLogRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadLogRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).ReadLogRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogService_ReadLogRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).ReadLogRange(ctx, req.(*ReadLogRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogService_LocateLogRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocateLogRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).LocateLogRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogService_LocateLogRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).LocateLogRecord(ctx, req.(*LocateLogRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LogService_ServiceDesc is the grpc.ServiceDesc for LogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.logs.LogService",
+	HandlerType: (*LogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReadLogRange",
+			Handler:    _LogService_ReadLogRange_Handler,
+		},
+		{
+			MethodName: "LocateLogRecord",
+			Handler:    _LogService_LocateLogRecord_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadJobLogs",
+			Handler:       _LogService_DownloadJobLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "logs.proto",
+}
@@ -0,0 +1,501 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: nodemetrics.proto
+
+package nodemetrics
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// QueryNodeMetricsRequest selects a time range
+type QueryNodeMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     int64                  `protobuf:"varint,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix nanoseconds (0 = oldest sample kept)
+	EndTime       int64                  `protobuf:"varint,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix nanoseconds (0 = now)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryNodeMetricsRequest) Reset() {
+	*x = QueryNodeMetricsRequest{}
+	mi := &file_nodemetrics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryNodeMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryNodeMetricsRequest) ProtoMessage() {}
+
+func (x *QueryNodeMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nodemetrics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryNodeMetricsRequest.ProtoReflect.Descriptor instead.
+func (*QueryNodeMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_nodemetrics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueryNodeMetricsRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *QueryNodeMetricsRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+// NodeMetricsSample is the state of the host at one point in time. Rates
+// are averages since the previous sample and zero on the first one.
+type NodeMetricsSample struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp       int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                // Unix nanoseconds
+	CpuCores        float64                `protobuf:"fixed64,2,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"` // Cores busy across the host
+	MemoryUsedBytes int64                  `protobuf:"varint,3,opt,name=memory_used_bytes,json=memoryUsedBytes,proto3" json:"memory_used_bytes,omitempty"`
+	DiskReadBps     float64                `protobuf:"fixed64,4,opt,name=disk_read_bps,json=diskReadBps,proto3" json:"disk_read_bps,omitempty"` // Bytes per second
+	DiskWriteBps    float64                `protobuf:"fixed64,5,opt,name=disk_write_bps,json=diskWriteBps,proto3" json:"disk_write_bps,omitempty"`
+	NetworkRxBps    float64                `protobuf:"fixed64,6,opt,name=network_rx_bps,json=networkRxBps,proto3" json:"network_rx_bps,omitempty"`
+	NetworkTxBps    float64                `protobuf:"fixed64,7,opt,name=network_tx_bps,json=networkTxBps,proto3" json:"network_tx_bps,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NodeMetricsSample) Reset() {
+	*x = NodeMetricsSample{}
+	mi := &file_nodemetrics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeMetricsSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeMetricsSample) ProtoMessage() {}
+
+func (x *NodeMetricsSample) ProtoReflect() protoreflect.Message {
+	mi := &file_nodemetrics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeMetricsSample.ProtoReflect.Descriptor instead.
+func (*NodeMetricsSample) Descriptor() ([]byte, []int) {
+	return file_nodemetrics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NodeMetricsSample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *NodeMetricsSample) GetCpuCores() float64 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *NodeMetricsSample) GetMemoryUsedBytes() int64 {
+	if x != nil {
+		return x.MemoryUsedBytes
+	}
+	return 0
+}
+
+func (x *NodeMetricsSample) GetDiskReadBps() float64 {
+	if x != nil {
+		return x.DiskReadBps
+	}
+	return 0
+}
+
+func (x *NodeMetricsSample) GetDiskWriteBps() float64 {
+	if x != nil {
+		return x.DiskWriteBps
+	}
+	return 0
+}
+
+func (x *NodeMetricsSample) GetNetworkRxBps() float64 {
+	if x != nil {
+		return x.NetworkRxBps
+	}
+	return 0
+}
+
+func (x *NodeMetricsSample) GetNetworkTxBps() float64 {
+	if x != nil {
+		return x.NetworkTxBps
+	}
+	return 0
+}
+
+// GetOrphanedResourcesRequest takes no options
+type GetOrphanedResourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrphanedResourcesRequest) Reset() {
+	*x = GetOrphanedResourcesRequest{}
+	mi := &file_nodemetrics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrphanedResourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrphanedResourcesRequest) ProtoMessage() {}
+
+func (x *GetOrphanedResourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nodemetrics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrphanedResourcesRequest.ProtoReflect.Descriptor instead.
+func (*GetOrphanedResourcesRequest) Descriptor() ([]byte, []int) {
+	return file_nodemetrics_proto_rawDescGZIP(), []int{2}
+}
+
+// OrphanedResourceCount counts the resources of one kind since joblet started.
+// A resource that could not be removed is found again by each pass.
+type OrphanedResourceCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // cgroup, veth or netns
+	Found         int64                  `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Cleaned       int64                  `protobuf:"varint,3,opt,name=cleaned,proto3" json:"cleaned,omitempty"`
+	Failed        int64                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrphanedResourceCount) Reset() {
+	*x = OrphanedResourceCount{}
+	mi := &file_nodemetrics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrphanedResourceCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrphanedResourceCount) ProtoMessage() {}
+
+func (x *OrphanedResourceCount) ProtoReflect() protoreflect.Message {
+	mi := &file_nodemetrics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrphanedResourceCount.ProtoReflect.Descriptor instead.
+func (*OrphanedResourceCount) Descriptor() ([]byte, []int) {
+	return file_nodemetrics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrphanedResourceCount) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *OrphanedResourceCount) GetFound() int64 {
+	if x != nil {
+		return x.Found
+	}
+	return 0
+}
+
+func (x *OrphanedResourceCount) GetCleaned() int64 {
+	if x != nil {
+		return x.Cleaned
+	}
+	return 0
+}
+
+func (x *OrphanedResourceCount) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// OrphanedResource is a resource left by a job no longer in the job store
+type OrphanedResource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Directory, interface or namespace name
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	JobId         string                 `protobuf:"bytes,4,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Job UUID, its first 8 characters for a veth
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrphanedResource) Reset() {
+	*x = OrphanedResource{}
+	mi := &file_nodemetrics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrphanedResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrphanedResource) ProtoMessage() {}
+
+func (x *OrphanedResource) ProtoReflect() protoreflect.Message {
+	mi := &file_nodemetrics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrphanedResource.ProtoReflect.Descriptor instead.
+func (*OrphanedResource) Descriptor() ([]byte, []int) {
+	return file_nodemetrics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrphanedResource) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *OrphanedResource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrphanedResource) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *OrphanedResource) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// OrphanedResources sums up the cleanup passes of the node
+type OrphanedResources struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	LastPass      int64                    `protobuf:"varint,1,opt,name=last_pass,json=lastPass,proto3" json:"last_pass,omitempty"` // Unix nanoseconds (0 = no pass yet)
+	Counts        []*OrphanedResourceCount `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty"`
+	Remaining     []*OrphanedResource      `protobuf:"bytes,3,rep,name=remaining,proto3" json:"remaining,omitempty"` // Left by the last pass, as removing them failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrphanedResources) Reset() {
+	*x = OrphanedResources{}
+	mi := &file_nodemetrics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrphanedResources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrphanedResources) ProtoMessage() {}
+
+func (x *OrphanedResources) ProtoReflect() protoreflect.Message {
+	mi := &file_nodemetrics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrphanedResources.ProtoReflect.Descriptor instead.
+func (*OrphanedResources) Descriptor() ([]byte, []int) {
+	return file_nodemetrics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrphanedResources) GetLastPass() int64 {
+	if x != nil {
+		return x.LastPass
+	}
+	return 0
+}
+
+func (x *OrphanedResources) GetCounts() []*OrphanedResourceCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *OrphanedResources) GetRemaining() []*OrphanedResource {
+	if x != nil {
+		return x.Remaining
+	}
+	return nil
+}
+
+var File_nodemetrics_proto protoreflect.FileDescriptor
+
+const file_nodemetrics_proto_rawDesc = "" +
+	"\n" +
+	"\x11nodemetrics.proto\x12\x12joblet.nodemetrics\"S\n" +
+	"\x17QueryNodeMetricsRequest\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x02 \x01(\x03R\aendTime\"\x90\x02\n" +
+	"\x11NodeMetricsSample\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1b\n" +
+	"\tcpu_cores\x18\x02 \x01(\x01R\bcpuCores\x12*\n" +
+	"\x11memory_used_bytes\x18\x03 \x01(\x03R\x0fmemoryUsedBytes\x12\"\n" +
+	"\rdisk_read_bps\x18\x04 \x01(\x01R\vdiskReadBps\x12$\n" +
+	"\x0edisk_write_bps\x18\x05 \x01(\x01R\fdiskWriteBps\x12$\n" +
+	"\x0enetwork_rx_bps\x18\x06 \x01(\x01R\fnetworkRxBps\x12$\n" +
+	"\x0enetwork_tx_bps\x18\a \x01(\x01R\fnetworkTxBps\"\x1d\n" +
+	"\x1bGetOrphanedResourcesRequest\"s\n" +
+	"\x15OrphanedResourceCount\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\x03R\x05found\x12\x18\n" +
+	"\acleaned\x18\x03 \x01(\x03R\acleaned\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x03R\x06failed\"e\n" +
+	"\x10OrphanedResource\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x15\n" +
+	"\x06job_id\x18\x04 \x01(\tR\x05jobId\"\xb7\x01\n" +
+	"\x11OrphanedResources\x12\x1b\n" +
+	"\tlast_pass\x18\x01 \x01(\x03R\blastPass\x12A\n" +
+	"\x06counts\x18\x02 \x03(\v2).joblet.nodemetrics.OrphanedResourceCountR\x06counts\x12B\n" +
+	"\tremaining\x18\x03 \x03(\v2$.joblet.nodemetrics.OrphanedResourceR\tremaining2\xee\x01\n" +
+	"\x12NodeMetricsService\x12h\n" +
+	"\x10QueryNodeMetrics\x12+.joblet.nodemetrics.QueryNodeMetricsRequest\x1a%.joblet.nodemetrics.NodeMetricsSample0\x01\x12n\n" +
+	"\x14GetOrphanedResources\x12/.joblet.nodemetrics.GetOrphanedResourcesRequest\x1a%.joblet.nodemetrics.OrphanedResourcesB<Z:github.com/ehsaniara/joblet/internal/proto/gen/nodemetricsb\x06proto3"
+
+var (
+	file_nodemetrics_proto_rawDescOnce sync.Once
+	file_nodemetrics_proto_rawDescData []byte
+)
+
+func file_nodemetrics_proto_rawDescGZIP() []byte {
+	file_nodemetrics_proto_rawDescOnce.Do(func() {
+		file_nodemetrics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nodemetrics_proto_rawDesc), len(file_nodemetrics_proto_rawDesc)))
+	})
+	return file_nodemetrics_proto_rawDescData
+}
+
+var file_nodemetrics_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_nodemetrics_proto_goTypes = []any{
+	(*QueryNodeMetricsRequest)(nil),     // 0: joblet.nodemetrics.QueryNodeMetricsRequest
+	(*NodeMetricsSample)(nil),           // 1: joblet.nodemetrics.NodeMetricsSample
+	(*GetOrphanedResourcesRequest)(nil), // 2: joblet.nodemetrics.GetOrphanedResourcesRequest
+	(*OrphanedResourceCount)(nil),       // 3: joblet.nodemetrics.OrphanedResourceCount
+	(*OrphanedResource)(nil),            // 4: joblet.nodemetrics.OrphanedResource
+	(*OrphanedResources)(nil),           // 5: joblet.nodemetrics.OrphanedResources
+}
+var file_nodemetrics_proto_depIdxs = []int32{
+	3, // 0: joblet.nodemetrics.OrphanedResources.counts:type_name -> joblet.nodemetrics.OrphanedResourceCount
+	4, // 1: joblet.nodemetrics.OrphanedResources.remaining:type_name -> joblet.nodemetrics.OrphanedResource
+	0, // 2: joblet.nodemetrics.NodeMetricsService.QueryNodeMetrics:input_type -> joblet.nodemetrics.QueryNodeMetricsRequest
+	2, // 3: joblet.nodemetrics.NodeMetricsService.GetOrphanedResources:input_type -> joblet.nodemetrics.GetOrphanedResourcesRequest
+	1, // 4: joblet.nodemetrics.NodeMetricsService.QueryNodeMetrics:output_type -> joblet.nodemetrics.NodeMetricsSample
+	5, // 5: joblet.nodemetrics.NodeMetricsService.GetOrphanedResources:output_type -> joblet.nodemetrics.OrphanedResources
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_nodemetrics_proto_init() }
+func file_nodemetrics_proto_init() {
+	if File_nodemetrics_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nodemetrics_proto_rawDesc), len(file_nodemetrics_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nodemetrics_proto_goTypes,
+		DependencyIndexes: file_nodemetrics_proto_depIdxs,
+		MessageInfos:      file_nodemetrics_proto_msgTypes,
+	}.Build()
+	File_nodemetrics_proto = out.File
+	file_nodemetrics_proto_goTypes = nil
+	file_nodemetrics_proto_depIdxs = nil
+}
@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: nodemetrics.proto
+
+package nodemetrics
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NodeMetricsService_QueryNodeMetrics_FullMethodName     = "/joblet.nodemetrics.NodeMetricsService/QueryNodeMetrics"
+	NodeMetricsService_GetOrphanedResources_FullMethodName = "/joblet.nodemetrics.NodeMetricsService/GetOrphanedResources"
+)
+
+// NodeMetricsServiceClient is the client API for NodeMetricsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NodeMetricsService serves the host metrics joblet records to persist.
+//
+// With monitoring.history enabled joblet samples the host every interval and
+// keeps the samples in persist for the retention period. The periodic job
+// cleanup also counts the cgroups and network resources it finds left by gone
+// jobs. rnx uses it for 'rnx monitor history' and 'rnx monitor orphans'.
+type NodeMetricsServiceClient interface {
+	// Stream the recorded samples in a time range, oldest first
+	QueryNodeMetrics(ctx context.Context, in *QueryNodeMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeMetricsSample], error)
+	// Count the orphaned resources the periodic cleanup removed, and list those
+	// it failed to remove
+	GetOrphanedResources(ctx context.Context, in *GetOrphanedResourcesRequest, opts ...grpc.CallOption) (*OrphanedResources, error)
+}
+
+type nodeMetricsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNodeMetricsServiceClient(cc grpc.ClientConnInterface) NodeMetricsServiceClient {
+	return &nodeMetricsServiceClient{cc}
+}
+
+func (c *nodeMetricsServiceClient) QueryNodeMetrics(ctx context.Context, in *QueryNodeMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeMetricsSample], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeMetricsService_ServiceDesc.Streams[0], NodeMetricsService_QueryNodeMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[QueryNodeMetricsRequest, NodeMetricsSample]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeMetricsService_QueryNodeMetricsClient = grpc.ServerStreamingClient[NodeMetricsSample]
+
+func (c *nodeMetricsServiceClient) GetOrphanedResources(ctx context.Context, in *GetOrphanedResourcesRequest, opts ...grpc.CallOption) (*OrphanedResources, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrphanedResources)
+	err := c.cc.Invoke(ctx, NodeMetricsService_GetOrphanedResources_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NodeMetricsServiceServer is the server API for NodeMetricsService service.
+// All implementations must embed UnimplementedNodeMetricsServiceServer
+// for forward compatibility.
+//
+// NodeMetricsService serves the host metrics joblet records to persist.
+//
+// With monitoring.history enabled joblet samples the host every interval and
+// keeps the samples in persist for the retention period. The periodic job
+// cleanup also counts the cgroups and network resources it finds left by gone
+// jobs. rnx uses it for 'rnx monitor history' and 'rnx monitor orphans'.
+type NodeMetricsServiceServer interface {
+	// Stream the recorded samples in a time range, oldest first
+	QueryNodeMetrics(*QueryNodeMetricsRequest, grpc.ServerStreamingServer[NodeMetricsSample]) error
+	// Count the orphaned resources the periodic cleanup removed, and list those
+	// it failed to remove
+	GetOrphanedResources(context.Context, *GetOrphanedResourcesRequest) (*OrphanedResources, error)
+	mustEmbedUnimplementedNodeMetricsServiceServer()
+}
+
+// UnimplementedNodeMetricsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNodeMetricsServiceServer struct{}
+
+func (UnimplementedNodeMetricsServiceServer) QueryNodeMetrics(*QueryNodeMetricsRequest, grpc.ServerStreamingServer[NodeMetricsSample]) error {
+	return status.Errorf(codes.Unimplemented, "method QueryNodeMetrics not implemented")
+}
+func (UnimplementedNodeMetricsServiceServer) GetOrphanedResources(context.Context, *GetOrphanedResourcesRequest) (*OrphanedResources, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrphanedResources not implemented")
+}
+func (UnimplementedNodeMetricsServiceServer) mustEmbedUnimplementedNodeMetricsServiceServer() {}
+func (UnimplementedNodeMetricsServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeNodeMetricsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NodeMetricsServiceServer will
+// result in compilation errors.
+type UnsafeNodeMetricsServiceServer interface {
+	mustEmbedUnimplementedNodeMetricsServiceServer()
+}
+
+func RegisterNodeMetricsServiceServer(s grpc.ServiceRegistrar, srv NodeMetricsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNodeMetricsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NodeMetricsService_ServiceDesc, srv)
+}
+
+func _NodeMetricsService_QueryNodeMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryNodeMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeMetricsServiceServer).QueryNodeMetrics(m, &grpc.GenericServerStream[QueryNodeMetricsRequest, NodeMetricsSample]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeMetricsService_QueryNodeMetricsServer = grpc.ServerStreamingServer[NodeMetricsSample]
+
+func _NodeMetricsService_GetOrphanedResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrphanedResourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeMetricsServiceServer).GetOrphanedResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeMetricsService_GetOrphanedResources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeMetricsServiceServer).GetOrphanedResources(ctx, req.(*GetOrphanedResourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NodeMetricsService_ServiceDesc is the grpc.ServiceDesc for NodeMetricsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NodeMetricsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.nodemetrics.NodeMetricsService",
+	HandlerType: (*NodeMetricsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrphanedResources",
+			Handler:    _NodeMetricsService_GetOrphanedResources_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryNodeMetrics",
+			Handler:       _NodeMetricsService_QueryNodeMetrics_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "nodemetrics.proto",
+}
@@ -0,0 +1,465 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: nodes.proto
+
+package nodes
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// NodeCapacity summarizes what a node can still schedule, see
+// joblet.capacity.NodeCapacity for the full snapshot
+type NodeCapacity struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	CpuTotal            int64                  `protobuf:"varint,1,opt,name=cpu_total,json=cpuTotal,proto3" json:"cpu_total,omitempty"` // CPU percent (100 = one core)
+	CpuSchedulable      int64                  `protobuf:"varint,2,opt,name=cpu_schedulable,json=cpuSchedulable,proto3" json:"cpu_schedulable,omitempty"`
+	MemoryTotalMb       int64                  `protobuf:"varint,3,opt,name=memory_total_mb,json=memoryTotalMb,proto3" json:"memory_total_mb,omitempty"`
+	MemorySchedulableMb int64                  `protobuf:"varint,4,opt,name=memory_schedulable_mb,json=memorySchedulableMb,proto3" json:"memory_schedulable_mb,omitempty"`
+	GpuTotal            int64                  `protobuf:"varint,5,opt,name=gpu_total,json=gpuTotal,proto3" json:"gpu_total,omitempty"`
+	GpuSchedulable      int64                  `protobuf:"varint,6,opt,name=gpu_schedulable,json=gpuSchedulable,proto3" json:"gpu_schedulable,omitempty"`
+	ActiveJobs          int32                  `protobuf:"varint,7,opt,name=active_jobs,json=activeJobs,proto3" json:"active_jobs,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *NodeCapacity) Reset() {
+	*x = NodeCapacity{}
+	mi := &file_nodes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeCapacity) ProtoMessage() {}
+
+func (x *NodeCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_nodes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeCapacity.ProtoReflect.Descriptor instead.
+func (*NodeCapacity) Descriptor() ([]byte, []int) {
+	return file_nodes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NodeCapacity) GetCpuTotal() int64 {
+	if x != nil {
+		return x.CpuTotal
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetCpuSchedulable() int64 {
+	if x != nil {
+		return x.CpuSchedulable
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetMemoryTotalMb() int64 {
+	if x != nil {
+		return x.MemoryTotalMb
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetMemorySchedulableMb() int64 {
+	if x != nil {
+		return x.MemorySchedulableMb
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetGpuTotal() int64 {
+	if x != nil {
+		return x.GpuTotal
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetGpuSchedulable() int64 {
+	if x != nil {
+		return x.GpuSchedulable
+	}
+	return 0
+}
+
+func (x *NodeCapacity) GetActiveJobs() int32 {
+	if x != nil {
+		return x.ActiveJobs
+	}
+	return 0
+}
+
+// NodeInfo is the registration of one node
+type NodeInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // host:port clients connect to
+	Hostname      string                 `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"` // joblet version
+	Labels        map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Capacity      *NodeCapacity          `protobuf:"bytes,6,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	TtlSeconds    int32                  `protobuf:"varint,7,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`          // Dropped when no heartbeat arrives for this long
+	RegisteredAt  int64                  `protobuf:"varint,8,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`    // Unix seconds, set by the registry
+	LastHeartbeat int64                  `protobuf:"varint,9,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"` // Unix seconds, set by the registry
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeInfo) Reset() {
+	*x = NodeInfo{}
+	mi := &file_nodes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeInfo) ProtoMessage() {}
+
+func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_nodes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return file_nodes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NodeInfo) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *NodeInfo) GetCapacity() *NodeCapacity {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+func (x *NodeInfo) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetLastHeartbeat() int64 {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return 0
+}
+
+// HeartbeatResponse acknowledges a heartbeat
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Registered    bool                   `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"` // True when this heartbeat registered the node
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_nodes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nodes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_nodes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HeartbeatResponse) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+// ListNodesRequest filters a node listing
+type ListNodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Labels        map[string]string      `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Only nodes carrying all of these labels
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesRequest) Reset() {
+	*x = ListNodesRequest{}
+	mi := &file_nodes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesRequest) ProtoMessage() {}
+
+func (x *ListNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nodes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return file_nodes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListNodesRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// ListNodesResponse holds the live nodes, sorted by node ID
+type ListNodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*NodeInfo            `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesResponse) Reset() {
+	*x = ListNodesResponse{}
+	mi := &file_nodes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesResponse) ProtoMessage() {}
+
+func (x *ListNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nodes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return file_nodes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListNodesResponse) GetNodes() []*NodeInfo {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+var File_nodes_proto protoreflect.FileDescriptor
+
+const file_nodes_proto_rawDesc = "" +
+	"\n" +
+	"\vnodes.proto\x12\fjoblet.nodes\"\x97\x02\n" +
+	"\fNodeCapacity\x12\x1b\n" +
+	"\tcpu_total\x18\x01 \x01(\x03R\bcpuTotal\x12'\n" +
+	"\x0fcpu_schedulable\x18\x02 \x01(\x03R\x0ecpuSchedulable\x12&\n" +
+	"\x0fmemory_total_mb\x18\x03 \x01(\x03R\rmemoryTotalMb\x122\n" +
+	"\x15memory_schedulable_mb\x18\x04 \x01(\x03R\x13memorySchedulableMb\x12\x1b\n" +
+	"\tgpu_total\x18\x05 \x01(\x03R\bgpuTotal\x12'\n" +
+	"\x0fgpu_schedulable\x18\x06 \x01(\x03R\x0egpuSchedulable\x12\x1f\n" +
+	"\vactive_jobs\x18\a \x01(\x05R\n" +
+	"activeJobs\"\x8f\x03\n" +
+	"\bNodeInfo\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x1a\n" +
+	"\bhostname\x18\x03 \x01(\tR\bhostname\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12:\n" +
+	"\x06labels\x18\x05 \x03(\v2\".joblet.nodes.NodeInfo.LabelsEntryR\x06labels\x126\n" +
+	"\bcapacity\x18\x06 \x01(\v2\x1a.joblet.nodes.NodeCapacityR\bcapacity\x12\x1f\n" +
+	"\vttl_seconds\x18\a \x01(\x05R\n" +
+	"ttlSeconds\x12#\n" +
+	"\rregistered_at\x18\b \x01(\x03R\fregisteredAt\x12%\n" +
+	"\x0elast_heartbeat\x18\t \x01(\x03R\rlastHeartbeat\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"3\n" +
+	"\x11HeartbeatResponse\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x01 \x01(\bR\n" +
+	"registered\"\x91\x01\n" +
+	"\x10ListNodesRequest\x12B\n" +
+	"\x06labels\x18\x01 \x03(\v2*.joblet.nodes.ListNodesRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"A\n" +
+	"\x11ListNodesResponse\x12,\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x16.joblet.nodes.NodeInfoR\x05nodes2\xa9\x01\n" +
+	"\x13NodeRegistryService\x12D\n" +
+	"\tHeartbeat\x12\x16.joblet.nodes.NodeInfo\x1a\x1f.joblet.nodes.HeartbeatResponse\x12L\n" +
+	"\tListNodes\x12\x1e.joblet.nodes.ListNodesRequest\x1a\x1f.joblet.nodes.ListNodesResponseB6Z4github.com/ehsaniara/joblet/internal/proto/gen/nodesb\x06proto3"
+
+var (
+	file_nodes_proto_rawDescOnce sync.Once
+	file_nodes_proto_rawDescData []byte
+)
+
+func file_nodes_proto_rawDescGZIP() []byte {
+	file_nodes_proto_rawDescOnce.Do(func() {
+		file_nodes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nodes_proto_rawDesc), len(file_nodes_proto_rawDesc)))
+	})
+	return file_nodes_proto_rawDescData
+}
+
+var file_nodes_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_nodes_proto_goTypes = []any{
+	(*NodeCapacity)(nil),      // 0: joblet.nodes.NodeCapacity
+	(*NodeInfo)(nil),          // 1: joblet.nodes.NodeInfo
+	(*HeartbeatResponse)(nil), // 2: joblet.nodes.HeartbeatResponse
+	(*ListNodesRequest)(nil),  // 3: joblet.nodes.ListNodesRequest
+	(*ListNodesResponse)(nil), // 4: joblet.nodes.ListNodesResponse
+	nil,                       // 5: joblet.nodes.NodeInfo.LabelsEntry
+	nil,                       // 6: joblet.nodes.ListNodesRequest.LabelsEntry
+}
+var file_nodes_proto_depIdxs = []int32{
+	5, // 0: joblet.nodes.NodeInfo.labels:type_name -> joblet.nodes.NodeInfo.LabelsEntry
+	0, // 1: joblet.nodes.NodeInfo.capacity:type_name -> joblet.nodes.NodeCapacity
+	6, // 2: joblet.nodes.ListNodesRequest.labels:type_name -> joblet.nodes.ListNodesRequest.LabelsEntry
+	1, // 3: joblet.nodes.ListNodesResponse.nodes:type_name -> joblet.nodes.NodeInfo
+	1, // 4: joblet.nodes.NodeRegistryService.Heartbeat:input_type -> joblet.nodes.NodeInfo
+	3, // 5: joblet.nodes.NodeRegistryService.ListNodes:input_type -> joblet.nodes.ListNodesRequest
+	2, // 6: joblet.nodes.NodeRegistryService.Heartbeat:output_type -> joblet.nodes.HeartbeatResponse
+	4, // 7: joblet.nodes.NodeRegistryService.ListNodes:output_type -> joblet.nodes.ListNodesResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_nodes_proto_init() }
+func file_nodes_proto_init() {
+	if File_nodes_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nodes_proto_rawDesc), len(file_nodes_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nodes_proto_goTypes,
+		DependencyIndexes: file_nodes_proto_depIdxs,
+		MessageInfos:      file_nodes_proto_msgTypes,
+	}.Build()
+	File_nodes_proto = out.File
+	file_nodes_proto_goTypes = nil
+	file_nodes_proto_depIdxs = nil
+}
@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: nodes.proto
+
+package nodes
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NodeRegistryService_Heartbeat_FullMethodName = "/joblet.nodes.NodeRegistryService/Heartbeat"
+	NodeRegistryService_ListNodes_FullMethodName = "/joblet.nodes.NodeRegistryService/ListNodes"
+)
+
+// NodeRegistryServiceClient is the client API for NodeRegistryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NodeRegistryService keeps the list of live joblet nodes.
+//
+// Nodes with coordination enabled send a heartbeat to the state service API
+// every few seconds; a node that misses heartbeats for its TTL is dropped.
+// joblet serves ListNodes on its main port from the same coordination
+// endpoint, so 'rnx nodes list' shows every live node through any one of them.
+type NodeRegistryServiceClient interface {
+	// Register the calling node or refresh its registration
+	Heartbeat(ctx context.Context, in *NodeInfo, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// List the nodes whose last heartbeat is within their TTL
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+}
+
+type nodeRegistryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNodeRegistryServiceClient(cc grpc.ClientConnInterface) NodeRegistryServiceClient {
+	return &nodeRegistryServiceClient{cc}
+}
+
+func (c *nodeRegistryServiceClient) Heartbeat(ctx context.Context, in *NodeInfo, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, NodeRegistryService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeRegistryServiceClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, NodeRegistryService_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NodeRegistryServiceServer is the server API for NodeRegistryService service.
+// All implementations must embed UnimplementedNodeRegistryServiceServer
+// for forward compatibility.
+//
+// NodeRegistryService keeps the list of live joblet nodes.
+//
+// Nodes with coordination enabled send a heartbeat to the state service API
+// every few seconds; a node that misses heartbeats for its TTL is dropped.
+// joblet serves ListNodes on its main port from the same coordination
+// endpoint, so 'rnx nodes list' shows every live node through any one of them.
+type NodeRegistryServiceServer interface {
+	// Register the calling node or refresh its registration
+	Heartbeat(context.Context, *NodeInfo) (*HeartbeatResponse, error)
+	// List the nodes whose last heartbeat is within their TTL
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	mustEmbedUnimplementedNodeRegistryServiceServer()
+}
+
+// UnimplementedNodeRegistryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNodeRegistryServiceServer struct{}
+
+func (UnimplementedNodeRegistryServiceServer) Heartbeat(context.Context, *NodeInfo) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedNodeRegistryServiceServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedNodeRegistryServiceServer) mustEmbedUnimplementedNodeRegistryServiceServer() {}
+func (UnimplementedNodeRegistryServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeNodeRegistryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NodeRegistryServiceServer will
+// result in compilation errors.
+type UnsafeNodeRegistryServiceServer interface {
+	mustEmbedUnimplementedNodeRegistryServiceServer()
+}
+
+func RegisterNodeRegistryServiceServer(s grpc.ServiceRegistrar, srv NodeRegistryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNodeRegistryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NodeRegistryService_ServiceDesc, srv)
+}
+
+func _NodeRegistryService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeRegistryServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeRegistryService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeRegistryServiceServer).Heartbeat(ctx, req.(*NodeInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeRegistryService_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeRegistryServiceServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeRegistryService_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeRegistryServiceServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NodeRegistryService_ServiceDesc is the grpc.ServiceDesc for NodeRegistryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NodeRegistryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.nodes.NodeRegistryService",
+	HandlerType: (*NodeRegistryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Heartbeat",
+			Handler:    _NodeRegistryService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _NodeRegistryService_ListNodes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nodes.proto",
+}
@@ -257,8 +257,14 @@ type QueryMetricsRequest struct {
 	StartTime int64 `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix nanoseconds
 	EndTime   int64 `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix nanoseconds
 	// Pagination
-	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`   // Max samples to return (0 = all)
-	Offset        int32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"` // Skip samples
+	Limit  int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`   // Max samples to return (0 = all)
+	Offset int32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"` // Skip samples
+	// Expression selecting and rolling up samples (optional), loosely after
+	// PromQL: FUNCTION(METRIC{LABEL="VALUE"}), e.g. max(memory_usage{job_id="..."}).
+	// Samples hold only the selected metric; with a function there is one
+	// sample per step, see docs/PERSISTENCE.md
+	Query         string `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"`
+	Step          int64  `protobuf:"varint,7,opt,name=step,proto3" json:"step,omitempty"` // Step in nanoseconds (0 = 1 minute with a function)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -328,6 +334,20 @@ func (x *QueryMetricsRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *QueryMetricsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryMetricsRequest) GetStep() int64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
 // LogLine represents a single log line from a job
 type LogLine struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -787,6 +807,287 @@ func (x *DeleteJobResponse) GetMessage() string {
 	return ""
 }
 
+// ArchiveJobRequest carries the job record to keep
+type ArchiveJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Record        []byte                 `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`                            // JSON job record, secret values masked
+	ArchivedAt    int64                  `protobuf:"varint,3,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"` // Unix nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveJobRequest) Reset() {
+	*x = ArchiveJobRequest{}
+	mi := &file_persist_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveJobRequest) ProtoMessage() {}
+
+func (x *ArchiveJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_persist_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveJobRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveJobRequest) Descriptor() ([]byte, []int) {
+	return file_persist_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ArchiveJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ArchiveJobRequest) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *ArchiveJobRequest) GetArchivedAt() int64 {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return 0
+}
+
+// ArchiveJobResponse indicates the result of the archive
+type ArchiveJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveJobResponse) Reset() {
+	*x = ArchiveJobResponse{}
+	mi := &file_persist_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveJobResponse) ProtoMessage() {}
+
+func (x *ArchiveJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_persist_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveJobResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveJobResponse) Descriptor() ([]byte, []int) {
+	return file_persist_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ArchiveJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ArchiveJobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// QueryJobDurationsRequest names the jobs to look up
+type QueryJobDurationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`  // Workflow job names
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // Most recent runs per name (0 = all)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryJobDurationsRequest) Reset() {
+	*x = QueryJobDurationsRequest{}
+	mi := &file_persist_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryJobDurationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryJobDurationsRequest) ProtoMessage() {}
+
+func (x *QueryJobDurationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_persist_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryJobDurationsRequest.ProtoReflect.Descriptor instead.
+func (*QueryJobDurationsRequest) Descriptor() ([]byte, []int) {
+	return file_persist_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QueryJobDurationsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *QueryJobDurationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// JobDuration is one completed run of a named job
+type JobDuration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix nanoseconds
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobDuration) Reset() {
+	*x = JobDuration{}
+	mi := &file_persist_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDuration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDuration) ProtoMessage() {}
+
+func (x *JobDuration) ProtoReflect() protoreflect.Message {
+	mi := &file_persist_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDuration.ProtoReflect.Descriptor instead.
+func (*JobDuration) Descriptor() ([]byte, []int) {
+	return file_persist_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *JobDuration) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobDuration) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobDuration) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *JobDuration) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+// QueryJobDurationsResponse holds the runs found, newest first per name
+type QueryJobDurationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Durations     []*JobDuration         `protobuf:"bytes,1,rep,name=durations,proto3" json:"durations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryJobDurationsResponse) Reset() {
+	*x = QueryJobDurationsResponse{}
+	mi := &file_persist_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryJobDurationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryJobDurationsResponse) ProtoMessage() {}
+
+func (x *QueryJobDurationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_persist_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryJobDurationsResponse.ProtoReflect.Descriptor instead.
+func (*QueryJobDurationsResponse) Descriptor() ([]byte, []int) {
+	return file_persist_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *QueryJobDurationsResponse) GetDurations() []*JobDuration {
+	if x != nil {
+		return x.Durations
+	}
+	return nil
+}
+
 var File_persist_proto protoreflect.FileDescriptor
 
 const file_persist_proto_rawDesc = "" +
@@ -803,14 +1104,16 @@ const file_persist_proto_rawDesc = "" +
 	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
 	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
 	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x06 \x01(\x05R\x06offset\"\x94\x01\n" +
+	"\x06offset\x18\x06 \x01(\x05R\x06offset\"\xbe\x01\n" +
 	"\x13QueryMetricsRequest\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1d\n" +
 	"\n" +
 	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
 	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12\x14\n" +
 	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x05 \x01(\x05R\x06offset\"\xa8\x01\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05query\x18\x06 \x01(\tR\x05query\x12\x12\n" +
+	"\x04step\x18\a \x01(\x03R\x04step\"\xa8\x01\n" +
 	"\aLogLine\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x122\n" +
 	"\x06stream\x18\x02 \x01(\x0e2\x1a.joblet.persist.StreamTypeR\x06stream\x12\x1c\n" +
@@ -848,17 +1151,39 @@ const file_persist_proto_rawDesc = "" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"G\n" +
 	"\x11DeleteJobResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage*Y\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"c\n" +
+	"\x11ArchiveJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06record\x18\x02 \x01(\fR\x06record\x12\x1f\n" +
+	"\varchived_at\x18\x03 \x01(\x03R\n" +
+	"archivedAt\"H\n" +
+	"\x12ArchiveJobResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"F\n" +
+	"\x18QueryJobDurationsRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"r\n" +
+	"\vJobDuration\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\"V\n" +
+	"\x19QueryJobDurationsResponse\x129\n" +
+	"\tdurations\x18\x01 \x03(\v2\x1b.joblet.persist.JobDurationR\tdurations*Y\n" +
 	"\n" +
 	"StreamType\x12\x1b\n" +
 	"\x17STREAM_TYPE_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12STREAM_TYPE_STDOUT\x10\x01\x12\x16\n" +
-	"\x12STREAM_TYPE_STDERR\x10\x022\xbe\x02\n" +
+	"\x12STREAM_TYPE_STDERR\x10\x022\xfd\x03\n" +
 	"\x0ePersistService\x12A\n" +
 	"\x04Ping\x12\x1b.joblet.persist.PingRequest\x1a\x1c.joblet.persist.PingResponse\x12H\n" +
 	"\tQueryLogs\x12 .joblet.persist.QueryLogsRequest\x1a\x17.joblet.persist.LogLine0\x01\x12M\n" +
 	"\fQueryMetrics\x12#.joblet.persist.QueryMetricsRequest\x1a\x16.joblet.persist.Metric0\x01\x12P\n" +
-	"\tDeleteJob\x12 .joblet.persist.DeleteJobRequest\x1a!.joblet.persist.DeleteJobResponseB8Z6github.com/ehsaniara/joblet/internal/proto/gen/persistb\x06proto3"
+	"\tDeleteJob\x12 .joblet.persist.DeleteJobRequest\x1a!.joblet.persist.DeleteJobResponse\x12S\n" +
+	"\n" +
+	"ArchiveJob\x12!.joblet.persist.ArchiveJobRequest\x1a\".joblet.persist.ArchiveJobResponse\x12h\n" +
+	"\x11QueryJobDurations\x12(.joblet.persist.QueryJobDurationsRequest\x1a).joblet.persist.QueryJobDurationsResponseB8Z6github.com/ehsaniara/joblet/internal/proto/gen/persistb\x06proto3"
 
 var (
 	file_persist_proto_rawDescOnce sync.Once
@@ -873,20 +1198,25 @@ func file_persist_proto_rawDescGZIP() []byte {
 }
 
 var file_persist_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_persist_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_persist_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_persist_proto_goTypes = []any{
-	(StreamType)(0),             // 0: joblet.persist.StreamType
-	(*PingRequest)(nil),         // 1: joblet.persist.PingRequest
-	(*PingResponse)(nil),        // 2: joblet.persist.PingResponse
-	(*QueryLogsRequest)(nil),    // 3: joblet.persist.QueryLogsRequest
-	(*QueryMetricsRequest)(nil), // 4: joblet.persist.QueryMetricsRequest
-	(*LogLine)(nil),             // 5: joblet.persist.LogLine
-	(*Metric)(nil),              // 6: joblet.persist.Metric
-	(*MetricData)(nil),          // 7: joblet.persist.MetricData
-	(*DiskIO)(nil),              // 8: joblet.persist.DiskIO
-	(*NetworkIO)(nil),           // 9: joblet.persist.NetworkIO
-	(*DeleteJobRequest)(nil),    // 10: joblet.persist.DeleteJobRequest
-	(*DeleteJobResponse)(nil),   // 11: joblet.persist.DeleteJobResponse
+	(StreamType)(0),                   // 0: joblet.persist.StreamType
+	(*PingRequest)(nil),               // 1: joblet.persist.PingRequest
+	(*PingResponse)(nil),              // 2: joblet.persist.PingResponse
+	(*QueryLogsRequest)(nil),          // 3: joblet.persist.QueryLogsRequest
+	(*QueryMetricsRequest)(nil),       // 4: joblet.persist.QueryMetricsRequest
+	(*LogLine)(nil),                   // 5: joblet.persist.LogLine
+	(*Metric)(nil),                    // 6: joblet.persist.Metric
+	(*MetricData)(nil),                // 7: joblet.persist.MetricData
+	(*DiskIO)(nil),                    // 8: joblet.persist.DiskIO
+	(*NetworkIO)(nil),                 // 9: joblet.persist.NetworkIO
+	(*DeleteJobRequest)(nil),          // 10: joblet.persist.DeleteJobRequest
+	(*DeleteJobResponse)(nil),         // 11: joblet.persist.DeleteJobResponse
+	(*ArchiveJobRequest)(nil),         // 12: joblet.persist.ArchiveJobRequest
+	(*ArchiveJobResponse)(nil),        // 13: joblet.persist.ArchiveJobResponse
+	(*QueryJobDurationsRequest)(nil),  // 14: joblet.persist.QueryJobDurationsRequest
+	(*JobDuration)(nil),               // 15: joblet.persist.JobDuration
+	(*QueryJobDurationsResponse)(nil), // 16: joblet.persist.QueryJobDurationsResponse
 }
 var file_persist_proto_depIdxs = []int32{
 	0,  // 0: joblet.persist.QueryLogsRequest.stream:type_name -> joblet.persist.StreamType
@@ -894,19 +1224,24 @@ var file_persist_proto_depIdxs = []int32{
 	7,  // 2: joblet.persist.Metric.data:type_name -> joblet.persist.MetricData
 	8,  // 3: joblet.persist.MetricData.disk_io:type_name -> joblet.persist.DiskIO
 	9,  // 4: joblet.persist.MetricData.network_io:type_name -> joblet.persist.NetworkIO
-	1,  // 5: joblet.persist.PersistService.Ping:input_type -> joblet.persist.PingRequest
-	3,  // 6: joblet.persist.PersistService.QueryLogs:input_type -> joblet.persist.QueryLogsRequest
-	4,  // 7: joblet.persist.PersistService.QueryMetrics:input_type -> joblet.persist.QueryMetricsRequest
-	10, // 8: joblet.persist.PersistService.DeleteJob:input_type -> joblet.persist.DeleteJobRequest
-	2,  // 9: joblet.persist.PersistService.Ping:output_type -> joblet.persist.PingResponse
-	5,  // 10: joblet.persist.PersistService.QueryLogs:output_type -> joblet.persist.LogLine
-	6,  // 11: joblet.persist.PersistService.QueryMetrics:output_type -> joblet.persist.Metric
-	11, // 12: joblet.persist.PersistService.DeleteJob:output_type -> joblet.persist.DeleteJobResponse
-	9,  // [9:13] is the sub-list for method output_type
-	5,  // [5:9] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	15, // 5: joblet.persist.QueryJobDurationsResponse.durations:type_name -> joblet.persist.JobDuration
+	1,  // 6: joblet.persist.PersistService.Ping:input_type -> joblet.persist.PingRequest
+	3,  // 7: joblet.persist.PersistService.QueryLogs:input_type -> joblet.persist.QueryLogsRequest
+	4,  // 8: joblet.persist.PersistService.QueryMetrics:input_type -> joblet.persist.QueryMetricsRequest
+	10, // 9: joblet.persist.PersistService.DeleteJob:input_type -> joblet.persist.DeleteJobRequest
+	12, // 10: joblet.persist.PersistService.ArchiveJob:input_type -> joblet.persist.ArchiveJobRequest
+	14, // 11: joblet.persist.PersistService.QueryJobDurations:input_type -> joblet.persist.QueryJobDurationsRequest
+	2,  // 12: joblet.persist.PersistService.Ping:output_type -> joblet.persist.PingResponse
+	5,  // 13: joblet.persist.PersistService.QueryLogs:output_type -> joblet.persist.LogLine
+	6,  // 14: joblet.persist.PersistService.QueryMetrics:output_type -> joblet.persist.Metric
+	11, // 15: joblet.persist.PersistService.DeleteJob:output_type -> joblet.persist.DeleteJobResponse
+	13, // 16: joblet.persist.PersistService.ArchiveJob:output_type -> joblet.persist.ArchiveJobResponse
+	16, // 17: joblet.persist.PersistService.QueryJobDurations:output_type -> joblet.persist.QueryJobDurationsResponse
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_persist_proto_init() }
@@ -920,7 +1255,7 @@ func file_persist_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_persist_proto_rawDesc), len(file_persist_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   11,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
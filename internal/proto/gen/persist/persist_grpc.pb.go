@@ -19,10 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PersistService_Ping_FullMethodName         = "/joblet.persist.PersistService/Ping"
-	PersistService_QueryLogs_FullMethodName    = "/joblet.persist.PersistService/QueryLogs"
-	PersistService_QueryMetrics_FullMethodName = "/joblet.persist.PersistService/QueryMetrics"
-	PersistService_DeleteJob_FullMethodName    = "/joblet.persist.PersistService/DeleteJob"
+	PersistService_Ping_FullMethodName              = "/joblet.persist.PersistService/Ping"
+	PersistService_QueryLogs_FullMethodName         = "/joblet.persist.PersistService/QueryLogs"
+	PersistService_QueryMetrics_FullMethodName      = "/joblet.persist.PersistService/QueryMetrics"
+	PersistService_DeleteJob_FullMethodName         = "/joblet.persist.PersistService/DeleteJob"
+	PersistService_ArchiveJob_FullMethodName        = "/joblet.persist.PersistService/ArchiveJob"
+	PersistService_QueryJobDurations_FullMethodName = "/joblet.persist.PersistService/QueryJobDurations"
 )
 
 // PersistServiceClient is the client API for PersistService service.
@@ -47,6 +49,10 @@ type PersistServiceClient interface {
 	QueryMetrics(ctx context.Context, in *QueryMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Metric], error)
 	// Delete all persisted data for a job (admin only)
 	DeleteJob(ctx context.Context, in *DeleteJobRequest, opts ...grpc.CallOption) (*DeleteJobResponse, error)
+	// Store a job's record next to its logs before the record leaves the job store
+	ArchiveJob(ctx context.Context, in *ArchiveJobRequest, opts ...grpc.CallOption) (*ArchiveJobResponse, error)
+	// Durations of the archived completed jobs with the given names
+	QueryJobDurations(ctx context.Context, in *QueryJobDurationsRequest, opts ...grpc.CallOption) (*QueryJobDurationsResponse, error)
 }
 
 type persistServiceClient struct {
@@ -115,6 +121,26 @@ func (c *persistServiceClient) DeleteJob(ctx context.Context, in *DeleteJobReque
 	return out, nil
 }
 
+func (c *persistServiceClient) ArchiveJob(ctx context.Context, in *ArchiveJobRequest, opts ...grpc.CallOption) (*ArchiveJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveJobResponse)
+	err := c.cc.Invoke(ctx, PersistService_ArchiveJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *persistServiceClient) QueryJobDurations(ctx context.Context, in *QueryJobDurationsRequest, opts ...grpc.CallOption) (*QueryJobDurationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryJobDurationsResponse)
+	err := c.cc.Invoke(ctx, PersistService_QueryJobDurations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PersistServiceServer is the server API for PersistService service.
 // All implementations must embed UnimplementedPersistServiceServer
 // for forward compatibility.
@@ -137,6 +163,10 @@ type PersistServiceServer interface {
 	QueryMetrics(*QueryMetricsRequest, grpc.ServerStreamingServer[Metric]) error
 	// Delete all persisted data for a job (admin only)
 	DeleteJob(context.Context, *DeleteJobRequest) (*DeleteJobResponse, error)
+	// Store a job's record next to its logs before the record leaves the job store
+	ArchiveJob(context.Context, *ArchiveJobRequest) (*ArchiveJobResponse, error)
+	// Durations of the archived completed jobs with the given names
+	QueryJobDurations(context.Context, *QueryJobDurationsRequest) (*QueryJobDurationsResponse, error)
 	mustEmbedUnimplementedPersistServiceServer()
 }
 
@@ -159,6 +189,12 @@ func (UnimplementedPersistServiceServer) QueryMetrics(*QueryMetricsRequest, grpc
 func (UnimplementedPersistServiceServer) DeleteJob(context.Context, *DeleteJobRequest) (*DeleteJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteJob not implemented")
 }
+func (UnimplementedPersistServiceServer) ArchiveJob(context.Context, *ArchiveJobRequest) (*ArchiveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveJob not implemented")
+}
+func (UnimplementedPersistServiceServer) QueryJobDurations(context.Context, *QueryJobDurationsRequest) (*QueryJobDurationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryJobDurations not implemented")
+}
 func (UnimplementedPersistServiceServer) mustEmbedUnimplementedPersistServiceServer() {}
 func (UnimplementedPersistServiceServer) testEmbeddedByValue()                        {}
 
@@ -238,6 +274,42 @@ func _PersistService_DeleteJob_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PersistService_ArchiveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PersistServiceServer).ArchiveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PersistService_ArchiveJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PersistServiceServer).ArchiveJob(ctx, req.(*ArchiveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PersistService_QueryJobDurations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryJobDurationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PersistServiceServer).QueryJobDurations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PersistService_QueryJobDurations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PersistServiceServer).QueryJobDurations(ctx, req.(*QueryJobDurationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PersistService_ServiceDesc is the grpc.ServiceDesc for PersistService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -253,6 +325,14 @@ var PersistService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteJob",
 			Handler:    _PersistService_DeleteJob_Handler,
 		},
+		{
+			MethodName: "ArchiveJob",
+			Handler:    _PersistService_ArchiveJob_Handler,
+		},
+		{
+			MethodName: "QueryJobDurations",
+			Handler:    _PersistService_QueryJobDurations_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
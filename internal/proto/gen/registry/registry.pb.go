@@ -0,0 +1,815 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: registry.proto
+
+package registry
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// WorkflowFile is a file referenced by the uploads of a workflow's jobs
+type WorkflowFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowFile) Reset() {
+	*x = WorkflowFile{}
+	mi := &file_registry_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowFile) ProtoMessage() {}
+
+func (x *WorkflowFile) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowFile.ProtoReflect.Descriptor instead.
+func (*WorkflowFile) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WorkflowFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WorkflowFile) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *WorkflowFile) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+type RegisterWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	YamlContent   string                 `protobuf:"bytes,2,opt,name=yaml_content,json=yamlContent,proto3" json:"yaml_content,omitempty"`
+	Files         []*WorkflowFile        `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Schedule      string                 `protobuf:"bytes,4,opt,name=schedule,proto3" json:"schedule,omitempty"`       // Cron expression, "@daily" or "@every 6h"; empty = started by hand only
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"` // Shown in listings; defaults to the workflow's description
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWorkflowRequest) Reset() {
+	*x = RegisterWorkflowRequest{}
+	mi := &file_registry_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWorkflowRequest) ProtoMessage() {}
+
+func (x *RegisterWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*RegisterWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterWorkflowRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterWorkflowRequest) GetYamlContent() string {
+	if x != nil {
+		return x.YamlContent
+	}
+	return ""
+}
+
+func (x *RegisterWorkflowRequest) GetFiles() []*WorkflowFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *RegisterWorkflowRequest) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *RegisterWorkflowRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// RegisteredWorkflow summarizes a workflow and its latest version
+type RegisteredWorkflow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	LatestVersion int32                  `protobuf:"varint,3,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	Schedule      string                 `protobuf:"bytes,4,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	NextRun       int64                  `protobuf:"varint,5,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`              // Unix seconds, 0 = not scheduled
+	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`        // Unix seconds of the latest version
+	UpdatedBy     string                 `protobuf:"bytes,7,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`         // Client that registered the latest version
+	LastRunUuid   string                 `protobuf:"bytes,8,opt,name=last_run_uuid,json=lastRunUuid,proto3" json:"last_run_uuid,omitempty"` // Workflow UUID of the latest run
+	LastRunAt     int64                  `protobuf:"varint,9,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`      // Unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredWorkflow) Reset() {
+	*x = RegisteredWorkflow{}
+	mi := &file_registry_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredWorkflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredWorkflow) ProtoMessage() {}
+
+func (x *RegisteredWorkflow) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredWorkflow.ProtoReflect.Descriptor instead.
+func (*RegisteredWorkflow) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisteredWorkflow) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflow) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflow) GetLatestVersion() int32 {
+	if x != nil {
+		return x.LatestVersion
+	}
+	return 0
+}
+
+func (x *RegisteredWorkflow) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflow) GetNextRun() int64 {
+	if x != nil {
+		return x.NextRun
+	}
+	return 0
+}
+
+func (x *RegisteredWorkflow) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *RegisteredWorkflow) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflow) GetLastRunUuid() string {
+	if x != nil {
+		return x.LastRunUuid
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflow) GetLastRunAt() int64 {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return 0
+}
+
+type ListRegisteredWorkflowsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRegisteredWorkflowsRequest) Reset() {
+	*x = ListRegisteredWorkflowsRequest{}
+	mi := &file_registry_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRegisteredWorkflowsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRegisteredWorkflowsRequest) ProtoMessage() {}
+
+func (x *ListRegisteredWorkflowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRegisteredWorkflowsRequest.ProtoReflect.Descriptor instead.
+func (*ListRegisteredWorkflowsRequest) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{3}
+}
+
+type ListRegisteredWorkflowsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workflows     []*RegisteredWorkflow  `protobuf:"bytes,1,rep,name=workflows,proto3" json:"workflows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRegisteredWorkflowsResponse) Reset() {
+	*x = ListRegisteredWorkflowsResponse{}
+	mi := &file_registry_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRegisteredWorkflowsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRegisteredWorkflowsResponse) ProtoMessage() {}
+
+func (x *ListRegisteredWorkflowsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRegisteredWorkflowsResponse.ProtoReflect.Descriptor instead.
+func (*ListRegisteredWorkflowsResponse) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRegisteredWorkflowsResponse) GetWorkflows() []*RegisteredWorkflow {
+	if x != nil {
+		return x.Workflows
+	}
+	return nil
+}
+
+type GetRegisteredWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // 0 = latest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRegisteredWorkflowRequest) Reset() {
+	*x = GetRegisteredWorkflowRequest{}
+	mi := &file_registry_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRegisteredWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegisteredWorkflowRequest) ProtoMessage() {}
+
+func (x *GetRegisteredWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegisteredWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*GetRegisteredWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRegisteredWorkflowRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetRegisteredWorkflowRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type RegisteredWorkflowVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	YamlContent   string                 `protobuf:"bytes,3,opt,name=yaml_content,json=yamlContent,proto3" json:"yaml_content,omitempty"`
+	FilePaths     []string               `protobuf:"bytes,4,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix seconds
+	CreatedBy     string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Signed        bool                   `protobuf:"varint,7,opt,name=signed,proto3" json:"signed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredWorkflowVersion) Reset() {
+	*x = RegisteredWorkflowVersion{}
+	mi := &file_registry_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredWorkflowVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredWorkflowVersion) ProtoMessage() {}
+
+func (x *RegisteredWorkflowVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredWorkflowVersion.ProtoReflect.Descriptor instead.
+func (*RegisteredWorkflowVersion) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisteredWorkflowVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflowVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RegisteredWorkflowVersion) GetYamlContent() string {
+	if x != nil {
+		return x.YamlContent
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflowVersion) GetFilePaths() []string {
+	if x != nil {
+		return x.FilePaths
+	}
+	return nil
+}
+
+func (x *RegisteredWorkflowVersion) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *RegisteredWorkflowVersion) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *RegisteredWorkflowVersion) GetSigned() bool {
+	if x != nil {
+		return x.Signed
+	}
+	return false
+}
+
+type RemoveRegisteredWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRegisteredWorkflowRequest) Reset() {
+	*x = RemoveRegisteredWorkflowRequest{}
+	mi := &file_registry_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRegisteredWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRegisteredWorkflowRequest) ProtoMessage() {}
+
+func (x *RemoveRegisteredWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRegisteredWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRegisteredWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemoveRegisteredWorkflowRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RemoveRegisteredWorkflowResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	VersionsRemoved int32                  `protobuf:"varint,1,opt,name=versions_removed,json=versionsRemoved,proto3" json:"versions_removed,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveRegisteredWorkflowResponse) Reset() {
+	*x = RemoveRegisteredWorkflowResponse{}
+	mi := &file_registry_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRegisteredWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRegisteredWorkflowResponse) ProtoMessage() {}
+
+func (x *RemoveRegisteredWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRegisteredWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*RemoveRegisteredWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RemoveRegisteredWorkflowResponse) GetVersionsRemoved() int32 {
+	if x != nil {
+		return x.VersionsRemoved
+	}
+	return 0
+}
+
+type StartRegisteredWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // 0 = latest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRegisteredWorkflowRequest) Reset() {
+	*x = StartRegisteredWorkflowRequest{}
+	mi := &file_registry_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRegisteredWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRegisteredWorkflowRequest) ProtoMessage() {}
+
+func (x *StartRegisteredWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRegisteredWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*StartRegisteredWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StartRegisteredWorkflowRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StartRegisteredWorkflowRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type StartRegisteredWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRegisteredWorkflowResponse) Reset() {
+	*x = StartRegisteredWorkflowResponse{}
+	mi := &file_registry_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRegisteredWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRegisteredWorkflowResponse) ProtoMessage() {}
+
+func (x *StartRegisteredWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRegisteredWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*StartRegisteredWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_registry_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StartRegisteredWorkflowResponse) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *StartRegisteredWorkflowResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_registry_proto protoreflect.FileDescriptor
+
+const file_registry_proto_rawDesc = "" +
+	"\n" +
+	"\x0eregistry.proto\x12\x0fjoblet.registry\"P\n" +
+	"\fWorkflowFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\rR\x04mode\"\xc3\x01\n" +
+	"\x17RegisterWorkflowRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fyaml_content\x18\x02 \x01(\tR\vyamlContent\x123\n" +
+	"\x05files\x18\x03 \x03(\v2\x1d.joblet.registry.WorkflowFileR\x05files\x12\x1a\n" +
+	"\bschedule\x18\x04 \x01(\tR\bschedule\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"\xaa\x02\n" +
+	"\x12RegisteredWorkflow\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12%\n" +
+	"\x0elatest_version\x18\x03 \x01(\x05R\rlatestVersion\x12\x1a\n" +
+	"\bschedule\x18\x04 \x01(\tR\bschedule\x12\x19\n" +
+	"\bnext_run\x18\x05 \x01(\x03R\anextRun\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\a \x01(\tR\tupdatedBy\x12\"\n" +
+	"\rlast_run_uuid\x18\b \x01(\tR\vlastRunUuid\x12\x1e\n" +
+	"\vlast_run_at\x18\t \x01(\x03R\tlastRunAt\" \n" +
+	"\x1eListRegisteredWorkflowsRequest\"d\n" +
+	"\x1fListRegisteredWorkflowsResponse\x12A\n" +
+	"\tworkflows\x18\x01 \x03(\v2#.joblet.registry.RegisteredWorkflowR\tworkflows\"L\n" +
+	"\x1cGetRegisteredWorkflowRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"\xe1\x01\n" +
+	"\x19RegisteredWorkflowVersion\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12!\n" +
+	"\fyaml_content\x18\x03 \x01(\tR\vyamlContent\x12\x1d\n" +
+	"\n" +
+	"file_paths\x18\x04 \x03(\tR\tfilePaths\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tR\tcreatedBy\x12\x16\n" +
+	"\x06signed\x18\a \x01(\bR\x06signed\"5\n" +
+	"\x1fRemoveRegisteredWorkflowRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"M\n" +
+	" RemoveRegisteredWorkflowResponse\x12)\n" +
+	"\x10versions_removed\x18\x01 \x01(\x05R\x0fversionsRemoved\"N\n" +
+	"\x1eStartRegisteredWorkflowRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"`\n" +
+	"\x1fStartRegisteredWorkflowResponse\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion2\xed\x04\n" +
+	"\x17WorkflowRegistryService\x12a\n" +
+	"\x10RegisterWorkflow\x12(.joblet.registry.RegisterWorkflowRequest\x1a#.joblet.registry.RegisteredWorkflow\x12|\n" +
+	"\x17ListRegisteredWorkflows\x12/.joblet.registry.ListRegisteredWorkflowsRequest\x1a0.joblet.registry.ListRegisteredWorkflowsResponse\x12r\n" +
+	"\x15GetRegisteredWorkflow\x12-.joblet.registry.GetRegisteredWorkflowRequest\x1a*.joblet.registry.RegisteredWorkflowVersion\x12\x7f\n" +
+	"\x18RemoveRegisteredWorkflow\x120.joblet.registry.RemoveRegisteredWorkflowRequest\x1a1.joblet.registry.RemoveRegisteredWorkflowResponse\x12|\n" +
+	"\x17StartRegisteredWorkflow\x12/.joblet.registry.StartRegisteredWorkflowRequest\x1a0.joblet.registry.StartRegisteredWorkflowResponseB9Z7github.com/ehsaniara/joblet/internal/proto/gen/registryb\x06proto3"
+
+var (
+	file_registry_proto_rawDescOnce sync.Once
+	file_registry_proto_rawDescData []byte
+)
+
+func file_registry_proto_rawDescGZIP() []byte {
+	file_registry_proto_rawDescOnce.Do(func() {
+		file_registry_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_proto_rawDesc), len(file_registry_proto_rawDesc)))
+	})
+	return file_registry_proto_rawDescData
+}
+
+var file_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_registry_proto_goTypes = []any{
+	(*WorkflowFile)(nil),                     // 0: joblet.registry.WorkflowFile
+	(*RegisterWorkflowRequest)(nil),          // 1: joblet.registry.RegisterWorkflowRequest
+	(*RegisteredWorkflow)(nil),               // 2: joblet.registry.RegisteredWorkflow
+	(*ListRegisteredWorkflowsRequest)(nil),   // 3: joblet.registry.ListRegisteredWorkflowsRequest
+	(*ListRegisteredWorkflowsResponse)(nil),  // 4: joblet.registry.ListRegisteredWorkflowsResponse
+	(*GetRegisteredWorkflowRequest)(nil),     // 5: joblet.registry.GetRegisteredWorkflowRequest
+	(*RegisteredWorkflowVersion)(nil),        // 6: joblet.registry.RegisteredWorkflowVersion
+	(*RemoveRegisteredWorkflowRequest)(nil),  // 7: joblet.registry.RemoveRegisteredWorkflowRequest
+	(*RemoveRegisteredWorkflowResponse)(nil), // 8: joblet.registry.RemoveRegisteredWorkflowResponse
+	(*StartRegisteredWorkflowRequest)(nil),   // 9: joblet.registry.StartRegisteredWorkflowRequest
+	(*StartRegisteredWorkflowResponse)(nil),  // 10: joblet.registry.StartRegisteredWorkflowResponse
+}
+var file_registry_proto_depIdxs = []int32{
+	0,  // 0: joblet.registry.RegisterWorkflowRequest.files:type_name -> joblet.registry.WorkflowFile
+	2,  // 1: joblet.registry.ListRegisteredWorkflowsResponse.workflows:type_name -> joblet.registry.RegisteredWorkflow
+	1,  // 2: joblet.registry.WorkflowRegistryService.RegisterWorkflow:input_type -> joblet.registry.RegisterWorkflowRequest
+	3,  // 3: joblet.registry.WorkflowRegistryService.ListRegisteredWorkflows:input_type -> joblet.registry.ListRegisteredWorkflowsRequest
+	5,  // 4: joblet.registry.WorkflowRegistryService.GetRegisteredWorkflow:input_type -> joblet.registry.GetRegisteredWorkflowRequest
+	7,  // 5: joblet.registry.WorkflowRegistryService.RemoveRegisteredWorkflow:input_type -> joblet.registry.RemoveRegisteredWorkflowRequest
+	9,  // 6: joblet.registry.WorkflowRegistryService.StartRegisteredWorkflow:input_type -> joblet.registry.StartRegisteredWorkflowRequest
+	2,  // 7: joblet.registry.WorkflowRegistryService.RegisterWorkflow:output_type -> joblet.registry.RegisteredWorkflow
+	4,  // 8: joblet.registry.WorkflowRegistryService.ListRegisteredWorkflows:output_type -> joblet.registry.ListRegisteredWorkflowsResponse
+	6,  // 9: joblet.registry.WorkflowRegistryService.GetRegisteredWorkflow:output_type -> joblet.registry.RegisteredWorkflowVersion
+	8,  // 10: joblet.registry.WorkflowRegistryService.RemoveRegisteredWorkflow:output_type -> joblet.registry.RemoveRegisteredWorkflowResponse
+	10, // 11: joblet.registry.WorkflowRegistryService.StartRegisteredWorkflow:output_type -> joblet.registry.StartRegisteredWorkflowResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_registry_proto_init() }
+func file_registry_proto_init() {
+	if File_registry_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_proto_rawDesc), len(file_registry_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_registry_proto_goTypes,
+		DependencyIndexes: file_registry_proto_depIdxs,
+		MessageInfos:      file_registry_proto_msgTypes,
+	}.Build()
+	File_registry_proto = out.File
+	file_registry_proto_goTypes = nil
+	file_registry_proto_depIdxs = nil
+}
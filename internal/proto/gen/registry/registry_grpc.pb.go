@@ -0,0 +1,298 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: registry.proto
+
+package registry
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WorkflowRegistryService_RegisterWorkflow_FullMethodName         = "/joblet.registry.WorkflowRegistryService/RegisterWorkflow"
+	WorkflowRegistryService_ListRegisteredWorkflows_FullMethodName  = "/joblet.registry.WorkflowRegistryService/ListRegisteredWorkflows"
+	WorkflowRegistryService_GetRegisteredWorkflow_FullMethodName    = "/joblet.registry.WorkflowRegistryService/GetRegisteredWorkflow"
+	WorkflowRegistryService_RemoveRegisteredWorkflow_FullMethodName = "/joblet.registry.WorkflowRegistryService/RemoveRegisteredWorkflow"
+	WorkflowRegistryService_StartRegisteredWorkflow_FullMethodName  = "/joblet.registry.WorkflowRegistryService/StartRegisteredWorkflow"
+)
+
+// WorkflowRegistryServiceClient is the client API for WorkflowRegistryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WorkflowRegistryService stores workflows on the server so they can be
+// started by name, without the YAML on the client.
+//
+// Registering a name again adds a new version; runs use the latest version
+// unless one is given. A workflow registered with a cron schedule is started
+// by the server on that schedule.
+type WorkflowRegistryServiceClient interface {
+	// Store a new version of a workflow
+	RegisterWorkflow(ctx context.Context, in *RegisterWorkflowRequest, opts ...grpc.CallOption) (*RegisteredWorkflow, error)
+	// List registered workflows
+	ListRegisteredWorkflows(ctx context.Context, in *ListRegisteredWorkflowsRequest, opts ...grpc.CallOption) (*ListRegisteredWorkflowsResponse, error)
+	// Get one version of a workflow, including its YAML
+	GetRegisteredWorkflow(ctx context.Context, in *GetRegisteredWorkflowRequest, opts ...grpc.CallOption) (*RegisteredWorkflowVersion, error)
+	// Remove a workflow and all its versions
+	RemoveRegisteredWorkflow(ctx context.Context, in *RemoveRegisteredWorkflowRequest, opts ...grpc.CallOption) (*RemoveRegisteredWorkflowResponse, error)
+	// Start a run of a registered workflow
+	StartRegisteredWorkflow(ctx context.Context, in *StartRegisteredWorkflowRequest, opts ...grpc.CallOption) (*StartRegisteredWorkflowResponse, error)
+}
+
+type workflowRegistryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkflowRegistryServiceClient(cc grpc.ClientConnInterface) WorkflowRegistryServiceClient {
+	return &workflowRegistryServiceClient{cc}
+}
+
+func (c *workflowRegistryServiceClient) RegisterWorkflow(ctx context.Context, in *RegisterWorkflowRequest, opts ...grpc.CallOption) (*RegisteredWorkflow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisteredWorkflow)
+	err := c.cc.Invoke(ctx, WorkflowRegistryService_RegisterWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRegistryServiceClient) ListRegisteredWorkflows(ctx context.Context, in *ListRegisteredWorkflowsRequest, opts ...grpc.CallOption) (*ListRegisteredWorkflowsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRegisteredWorkflowsResponse)
+	err := c.cc.Invoke(ctx, WorkflowRegistryService_ListRegisteredWorkflows_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRegistryServiceClient) GetRegisteredWorkflow(ctx context.Context, in *GetRegisteredWorkflowRequest, opts ...grpc.CallOption) (*RegisteredWorkflowVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisteredWorkflowVersion)
+	err := c.cc.Invoke(ctx, WorkflowRegistryService_GetRegisteredWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRegistryServiceClient) RemoveRegisteredWorkflow(ctx context.Context, in *RemoveRegisteredWorkflowRequest, opts ...grpc.CallOption) (*RemoveRegisteredWorkflowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveRegisteredWorkflowResponse)
+	err := c.cc.Invoke(ctx, WorkflowRegistryService_RemoveRegisteredWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRegistryServiceClient) StartRegisteredWorkflow(ctx context.Context, in *StartRegisteredWorkflowRequest, opts ...grpc.CallOption) (*StartRegisteredWorkflowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartRegisteredWorkflowResponse)
+	err := c.cc.Invoke(ctx, WorkflowRegistryService_StartRegisteredWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkflowRegistryServiceServer is the server API for WorkflowRegistryService service.
+// All implementations must embed UnimplementedWorkflowRegistryServiceServer
+// for forward compatibility.
+//
+// WorkflowRegistryService stores workflows on the server so they can be
+// started by name, without the YAML on the client.
+//
+// Registering a name again adds a new version; runs use the latest version
+// unless one is given. A workflow registered with a cron schedule is started
+// by the server on that schedule.
+type WorkflowRegistryServiceServer interface {
+	// Store a new version of a workflow
+	RegisterWorkflow(context.Context, *RegisterWorkflowRequest) (*RegisteredWorkflow, error)
+	// List registered workflows
+	ListRegisteredWorkflows(context.Context, *ListRegisteredWorkflowsRequest) (*ListRegisteredWorkflowsResponse, error)
+	// Get one version of a workflow, including its YAML
+	GetRegisteredWorkflow(context.Context, *GetRegisteredWorkflowRequest) (*RegisteredWorkflowVersion, error)
+	// Remove a workflow and all its versions
+	RemoveRegisteredWorkflow(context.Context, *RemoveRegisteredWorkflowRequest) (*RemoveRegisteredWorkflowResponse, error)
+	// Start a run of a registered workflow
+	StartRegisteredWorkflow(context.Context, *StartRegisteredWorkflowRequest) (*StartRegisteredWorkflowResponse, error)
+	mustEmbedUnimplementedWorkflowRegistryServiceServer()
+}
+
+// UnimplementedWorkflowRegistryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkflowRegistryServiceServer struct{}
+
+func (UnimplementedWorkflowRegistryServiceServer) RegisterWorkflow(context.Context, *RegisterWorkflowRequest) (*RegisteredWorkflow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorkflow not implemented")
+}
+func (UnimplementedWorkflowRegistryServiceServer) ListRegisteredWorkflows(context.Context, *ListRegisteredWorkflowsRequest) (*ListRegisteredWorkflowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRegisteredWorkflows not implemented")
+}
+func (UnimplementedWorkflowRegistryServiceServer) GetRegisteredWorkflow(context.Context, *GetRegisteredWorkflowRequest) (*RegisteredWorkflowVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegisteredWorkflow not implemented")
+}
+func (UnimplementedWorkflowRegistryServiceServer) RemoveRegisteredWorkflow(context.Context, *RemoveRegisteredWorkflowRequest) (*RemoveRegisteredWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRegisteredWorkflow not implemented")
+}
+func (UnimplementedWorkflowRegistryServiceServer) StartRegisteredWorkflow(context.Context, *StartRegisteredWorkflowRequest) (*StartRegisteredWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRegisteredWorkflow not implemented")
+}
+func (UnimplementedWorkflowRegistryServiceServer) mustEmbedUnimplementedWorkflowRegistryServiceServer() {
+}
+func (UnimplementedWorkflowRegistryServiceServer) testEmbeddedByValue() {}
+
+// UnsafeWorkflowRegistryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkflowRegistryServiceServer will
+// result in compilation errors.
+type UnsafeWorkflowRegistryServiceServer interface {
+	mustEmbedUnimplementedWorkflowRegistryServiceServer()
+}
+
+func RegisterWorkflowRegistryServiceServer(s grpc.ServiceRegistrar, srv WorkflowRegistryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWorkflowRegistryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorkflowRegistryService_ServiceDesc, srv)
+}
+
+func _WorkflowRegistryService_RegisterWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRegistryServiceServer).RegisterWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRegistryService_RegisterWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRegistryServiceServer).RegisterWorkflow(ctx, req.(*RegisterWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRegistryService_ListRegisteredWorkflows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRegisteredWorkflowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRegistryServiceServer).ListRegisteredWorkflows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRegistryService_ListRegisteredWorkflows_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRegistryServiceServer).ListRegisteredWorkflows(ctx, req.(*ListRegisteredWorkflowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRegistryService_GetRegisteredWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegisteredWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRegistryServiceServer).GetRegisteredWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRegistryService_GetRegisteredWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRegistryServiceServer).GetRegisteredWorkflow(ctx, req.(*GetRegisteredWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRegistryService_RemoveRegisteredWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRegisteredWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRegistryServiceServer).RemoveRegisteredWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRegistryService_RemoveRegisteredWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRegistryServiceServer).RemoveRegisteredWorkflow(ctx, req.(*RemoveRegisteredWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRegistryService_StartRegisteredWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRegisteredWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRegistryServiceServer).StartRegisteredWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRegistryService_StartRegisteredWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRegistryServiceServer).StartRegisteredWorkflow(ctx, req.(*StartRegisteredWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WorkflowRegistryService_ServiceDesc is the grpc.ServiceDesc for WorkflowRegistryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkflowRegistryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.registry.WorkflowRegistryService",
+	HandlerType: (*WorkflowRegistryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterWorkflow",
+			Handler:    _WorkflowRegistryService_RegisterWorkflow_Handler,
+		},
+		{
+			MethodName: "ListRegisteredWorkflows",
+			Handler:    _WorkflowRegistryService_ListRegisteredWorkflows_Handler,
+		},
+		{
+			MethodName: "GetRegisteredWorkflow",
+			Handler:    _WorkflowRegistryService_GetRegisteredWorkflow_Handler,
+		},
+		{
+			MethodName: "RemoveRegisteredWorkflow",
+			Handler:    _WorkflowRegistryService_RemoveRegisteredWorkflow_Handler,
+		},
+		{
+			MethodName: "StartRegisteredWorkflow",
+			Handler:    _WorkflowRegistryService_StartRegisteredWorkflow_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "registry.proto",
+}
@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: reports.proto
+
+package reports
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// WorkflowReportRequest selects the workflow and the report format
+type WorkflowReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"` // Full or short workflow UUID
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`                                 // "html", "json" or "junit"
+	LogLines      int32                  `protobuf:"varint,3,opt,name=log_lines,json=logLines,proto3" json:"log_lines,omitempty"`            // Log lines quoted per failed job (0 = server default)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowReportRequest) Reset() {
+	*x = WorkflowReportRequest{}
+	mi := &file_reports_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowReportRequest) ProtoMessage() {}
+
+func (x *WorkflowReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reports_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowReportRequest.ProtoReflect.Descriptor instead.
+func (*WorkflowReportRequest) Descriptor() ([]byte, []int) {
+	return file_reports_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WorkflowReportRequest) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *WorkflowReportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *WorkflowReportRequest) GetLogLines() int32 {
+	if x != nil {
+		return x.LogLines
+	}
+	return 0
+}
+
+// WorkflowReport is a rendered report
+type WorkflowReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"` // Full workflow UUID
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"` // The report document
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`   // Workflow status when the report was made
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowReport) Reset() {
+	*x = WorkflowReport{}
+	mi := &file_reports_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowReport) ProtoMessage() {}
+
+func (x *WorkflowReport) ProtoReflect() protoreflect.Message {
+	mi := &file_reports_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowReport.ProtoReflect.Descriptor instead.
+func (*WorkflowReport) Descriptor() ([]byte, []int) {
+	return file_reports_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WorkflowReport) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *WorkflowReport) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *WorkflowReport) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *WorkflowReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+var File_reports_proto protoreflect.FileDescriptor
+
+const file_reports_proto_rawDesc = "" +
+	"\n" +
+	"\rreports.proto\x12\x0ejoblet.reports\"q\n" +
+	"\x15WorkflowReportRequest\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x1b\n" +
+	"\tlog_lines\x18\x03 \x01(\x05R\blogLines\"\x7f\n" +
+	"\x0eWorkflowReport\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status2s\n" +
+	"\x15WorkflowReportService\x12Z\n" +
+	"\x11GetWorkflowReport\x12%.joblet.reports.WorkflowReportRequest\x1a\x1e.joblet.reports.WorkflowReportB8Z6github.com/ehsaniara/joblet/internal/proto/gen/reportsb\x06proto3"
+
+var (
+	file_reports_proto_rawDescOnce sync.Once
+	file_reports_proto_rawDescData []byte
+)
+
+func file_reports_proto_rawDescGZIP() []byte {
+	file_reports_proto_rawDescOnce.Do(func() {
+		file_reports_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reports_proto_rawDesc), len(file_reports_proto_rawDesc)))
+	})
+	return file_reports_proto_rawDescData
+}
+
+var file_reports_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_reports_proto_goTypes = []any{
+	(*WorkflowReportRequest)(nil), // 0: joblet.reports.WorkflowReportRequest
+	(*WorkflowReport)(nil),        // 1: joblet.reports.WorkflowReport
+}
+var file_reports_proto_depIdxs = []int32{
+	0, // 0: joblet.reports.WorkflowReportService.GetWorkflowReport:input_type -> joblet.reports.WorkflowReportRequest
+	1, // 1: joblet.reports.WorkflowReportService.GetWorkflowReport:output_type -> joblet.reports.WorkflowReport
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_reports_proto_init() }
+func file_reports_proto_init() {
+	if File_reports_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reports_proto_rawDesc), len(file_reports_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reports_proto_goTypes,
+		DependencyIndexes: file_reports_proto_depIdxs,
+		MessageInfos:      file_reports_proto_msgTypes,
+	}.Build()
+	File_reports_proto = out.File
+	file_reports_proto_goTypes = nil
+	file_reports_proto_depIdxs = nil
+}
@@ -0,0 +1,135 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: reports.proto
+
+package reports
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WorkflowReportService_GetWorkflowReport_FullMethodName = "/joblet.reports.WorkflowReportService/GetWorkflowReport"
+)
+
+// WorkflowReportServiceClient is the client API for WorkflowReportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WorkflowReportService renders reports of workflow runs.
+//
+// 'rnx workflow report' uses it to export a run as HTML, JSON or JUnit XML,
+// built from the workflow state, the job records and the logs and metrics the
+// persist service stored.
+type WorkflowReportServiceClient interface {
+	// Render the report of a workflow run
+	GetWorkflowReport(ctx context.Context, in *WorkflowReportRequest, opts ...grpc.CallOption) (*WorkflowReport, error)
+}
+
+type workflowReportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkflowReportServiceClient(cc grpc.ClientConnInterface) WorkflowReportServiceClient {
+	return &workflowReportServiceClient{cc}
+}
+
+func (c *workflowReportServiceClient) GetWorkflowReport(ctx context.Context, in *WorkflowReportRequest, opts ...grpc.CallOption) (*WorkflowReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkflowReport)
+	err := c.cc.Invoke(ctx, WorkflowReportService_GetWorkflowReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkflowReportServiceServer is the server API for WorkflowReportService service.
+// All implementations must embed UnimplementedWorkflowReportServiceServer
+// for forward compatibility.
+//
+// WorkflowReportService renders reports of workflow runs.
+//
+// 'rnx workflow report' uses it to export a run as HTML, JSON or JUnit XML,
+// built from the workflow state, the job records and the logs and metrics the
+// persist service stored.
+type WorkflowReportServiceServer interface {
+	// Render the report of a workflow run
+	GetWorkflowReport(context.Context, *WorkflowReportRequest) (*WorkflowReport, error)
+	mustEmbedUnimplementedWorkflowReportServiceServer()
+}
+
+// UnimplementedWorkflowReportServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkflowReportServiceServer struct{}
+
+func (UnimplementedWorkflowReportServiceServer) GetWorkflowReport(context.Context, *WorkflowReportRequest) (*WorkflowReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowReport not implemented")
+}
+func (UnimplementedWorkflowReportServiceServer) mustEmbedUnimplementedWorkflowReportServiceServer() {}
+func (UnimplementedWorkflowReportServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeWorkflowReportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkflowReportServiceServer will
+// result in compilation errors.
+type UnsafeWorkflowReportServiceServer interface {
+	mustEmbedUnimplementedWorkflowReportServiceServer()
+}
+
+func RegisterWorkflowReportServiceServer(s grpc.ServiceRegistrar, srv WorkflowReportServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWorkflowReportServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorkflowReportService_ServiceDesc, srv)
+}
+
+func _WorkflowReportService_GetWorkflowReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReportServiceServer).GetWorkflowReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReportService_GetWorkflowReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReportServiceServer).GetWorkflowReport(ctx, req.(*WorkflowReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WorkflowReportService_ServiceDesc is the grpc.ServiceDesc for WorkflowReportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkflowReportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.reports.WorkflowReportService",
+	HandlerType: (*WorkflowReportServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWorkflowReport",
+			Handler:    _WorkflowReportService_GetWorkflowReport_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reports.proto",
+}
@@ -0,0 +1,987 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: runtimes.proto
+
+package runtimes
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GetRuntimeInfoRequest names a runtime the way jobs do
+type GetRuntimeInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runtime       string                 `protobuf:"bytes,1,opt,name=runtime,proto3" json:"runtime,omitempty"` // e.g. "python-3.11-ml", "python-3.11-ml@1.0.0" or an alias
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuntimeInfoRequest) Reset() {
+	*x = GetRuntimeInfoRequest{}
+	mi := &file_runtimes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuntimeInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuntimeInfoRequest) ProtoMessage() {}
+
+func (x *GetRuntimeInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuntimeInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetRuntimeInfoRequest) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetRuntimeInfoRequest) GetRuntime() string {
+	if x != nil {
+		return x.Runtime
+	}
+	return ""
+}
+
+// RuntimeMount is a directory of the runtime mounted into jobs
+type RuntimeMount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"` // Relative to the runtime directory
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"` // Path in the job
+	Readonly      bool                   `protobuf:"varint,3,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	Missing       bool                   `protobuf:"varint,4,opt,name=missing,proto3" json:"missing,omitempty"` // The source does not exist, the mount is skipped
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeMount) Reset() {
+	*x = RuntimeMount{}
+	mi := &file_runtimes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeMount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeMount) ProtoMessage() {}
+
+func (x *RuntimeMount) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeMount.ProtoReflect.Descriptor instead.
+func (*RuntimeMount) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RuntimeMount) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RuntimeMount) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *RuntimeMount) GetReadonly() bool {
+	if x != nil {
+		return x.Readonly
+	}
+	return false
+}
+
+func (x *RuntimeMount) GetMissing() bool {
+	if x != nil {
+		return x.Missing
+	}
+	return false
+}
+
+// RuntimeTool is a tool the runtime ships, with the version recorded when the
+// runtime was built
+type RuntimeTool struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeTool) Reset() {
+	*x = RuntimeTool{}
+	mi := &file_runtimes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeTool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeTool) ProtoMessage() {}
+
+func (x *RuntimeTool) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeTool.ProtoReflect.Descriptor instead.
+func (*RuntimeTool) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RuntimeTool) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuntimeTool) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// RuntimeDetails describes an installed runtime
+type RuntimeDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Language      string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Resolved      string                 `protobuf:"bytes,5,opt,name=resolved,proto3" json:"resolved,omitempty"`                 // Runtime the request resolved to, with its version
+	PinnedBy      string                 `protobuf:"bytes,6,opt,name=pinned_by,json=pinnedBy,proto3" json:"pinned_by,omitempty"` // Alias or tenant pin that resolved it, empty otherwise
+	Path          string                 `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`                         // Runtime directory on the node
+	Mounts        []*RuntimeMount        `protobuf:"bytes,8,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	Environment   map[string]string      `protobuf:"bytes,9,rep,name=environment,proto3" json:"environment,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Variables the runtime exports to jobs
+	JobPath       string                 `protobuf:"bytes,10,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`                                                                   // PATH jobs start with, before their own variables
+	Tools         []*RuntimeTool         `protobuf:"bytes,11,rep,name=tools,proto3" json:"tools,omitempty"`                                                                                      // In name order
+	Packages      []string               `protobuf:"bytes,12,rep,name=packages,proto3" json:"packages,omitempty"`
+	Architectures []string               `protobuf:"bytes,13,rep,name=architectures,proto3" json:"architectures,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,14,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Disk footprint of the runtime directory
+	Files         int64                  `protobuf:"varint,15,opt,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeDetails) Reset() {
+	*x = RuntimeDetails{}
+	mi := &file_runtimes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeDetails) ProtoMessage() {}
+
+func (x *RuntimeDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeDetails.ProtoReflect.Descriptor instead.
+func (*RuntimeDetails) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RuntimeDetails) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetResolved() string {
+	if x != nil {
+		return x.Resolved
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetPinnedBy() string {
+	if x != nil {
+		return x.PinnedBy
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetMounts() []*RuntimeMount {
+	if x != nil {
+		return x.Mounts
+	}
+	return nil
+}
+
+func (x *RuntimeDetails) GetEnvironment() map[string]string {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *RuntimeDetails) GetJobPath() string {
+	if x != nil {
+		return x.JobPath
+	}
+	return ""
+}
+
+func (x *RuntimeDetails) GetTools() []*RuntimeTool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+func (x *RuntimeDetails) GetPackages() []string {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+func (x *RuntimeDetails) GetArchitectures() []string {
+	if x != nil {
+		return x.Architectures
+	}
+	return nil
+}
+
+func (x *RuntimeDetails) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *RuntimeDetails) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+type ListRuntimeChannelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRuntimeChannelsRequest) Reset() {
+	*x = ListRuntimeChannelsRequest{}
+	mi := &file_runtimes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRuntimeChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRuntimeChannelsRequest) ProtoMessage() {}
+
+func (x *ListRuntimeChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRuntimeChannelsRequest.ProtoReflect.Descriptor instead.
+func (*ListRuntimeChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{4}
+}
+
+type ListRuntimeChannelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channels      []*RuntimeChannel      `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"` // In name order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRuntimeChannelsResponse) Reset() {
+	*x = ListRuntimeChannelsResponse{}
+	mi := &file_runtimes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRuntimeChannelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRuntimeChannelsResponse) ProtoMessage() {}
+
+func (x *ListRuntimeChannelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRuntimeChannelsResponse.ProtoReflect.Descriptor instead.
+func (*ListRuntimeChannelsResponse) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRuntimeChannelsResponse) GetChannels() []*RuntimeChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+type SetRuntimeCanaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`       // Runtime name jobs ask for, e.g. "python"
+	Runtime       string                 `protobuf:"bytes,2,opt,name=runtime,proto3" json:"runtime,omitempty"` // Installed runtime, e.g. "python-3.12@2.0.0"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRuntimeCanaryRequest) Reset() {
+	*x = SetRuntimeCanaryRequest{}
+	mi := &file_runtimes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRuntimeCanaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRuntimeCanaryRequest) ProtoMessage() {}
+
+func (x *SetRuntimeCanaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRuntimeCanaryRequest.ProtoReflect.Descriptor instead.
+func (*SetRuntimeCanaryRequest) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetRuntimeCanaryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetRuntimeCanaryRequest) GetRuntime() string {
+	if x != nil {
+		return x.Runtime
+	}
+	return ""
+}
+
+type RuntimeChannelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeChannelRequest) Reset() {
+	*x = RuntimeChannelRequest{}
+	mi := &file_runtimes_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeChannelRequest) ProtoMessage() {}
+
+func (x *RuntimeChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeChannelRequest.ProtoReflect.Descriptor instead.
+func (*RuntimeChannelRequest) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RuntimeChannelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PromoteRuntimeCanaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Force         bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"` // Promote even when the canary fails more than stable allows
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromoteRuntimeCanaryRequest) Reset() {
+	*x = PromoteRuntimeCanaryRequest{}
+	mi := &file_runtimes_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoteRuntimeCanaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoteRuntimeCanaryRequest) ProtoMessage() {}
+
+func (x *PromoteRuntimeCanaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoteRuntimeCanaryRequest.ProtoReflect.Descriptor instead.
+func (*PromoteRuntimeCanaryRequest) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PromoteRuntimeCanaryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PromoteRuntimeCanaryRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// RuntimeChannel is where the channels of a runtime name point, with the
+// comparison of the canary against stable while there is one
+type RuntimeChannel struct {
+	state          protoimpl.MessageState    `protogen:"open.v1"`
+	Name           string                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Stable         string                    `protobuf:"bytes,2,opt,name=stable,proto3" json:"stable,omitempty"`                                         // Runtime jobs asking for the name run on
+	StablePinnedBy string                    `protobuf:"bytes,3,opt,name=stable_pinned_by,json=stablePinnedBy,proto3" json:"stable_pinned_by,omitempty"` // "stable channel", an alias, or empty for the name itself
+	StableSince    int64                     `protobuf:"varint,4,opt,name=stable_since,json=stableSince,proto3" json:"stable_since,omitempty"`           // Unix nanoseconds of the last promotion, 0 without one
+	Canary         string                    `protobuf:"bytes,5,opt,name=canary,proto3" json:"canary,omitempty"`                                         // Empty without a canary
+	CanarySince    int64                     `protobuf:"varint,6,opt,name=canary_since,json=canarySince,proto3" json:"canary_since,omitempty"`
+	UpdatedBy      string                    `protobuf:"bytes,7,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	Comparison     *RuntimeChannelComparison `protobuf:"bytes,8,opt,name=comparison,proto3" json:"comparison,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RuntimeChannel) Reset() {
+	*x = RuntimeChannel{}
+	mi := &file_runtimes_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeChannel) ProtoMessage() {}
+
+func (x *RuntimeChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeChannel.ProtoReflect.Descriptor instead.
+func (*RuntimeChannel) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RuntimeChannel) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuntimeChannel) GetStable() string {
+	if x != nil {
+		return x.Stable
+	}
+	return ""
+}
+
+func (x *RuntimeChannel) GetStablePinnedBy() string {
+	if x != nil {
+		return x.StablePinnedBy
+	}
+	return ""
+}
+
+func (x *RuntimeChannel) GetStableSince() int64 {
+	if x != nil {
+		return x.StableSince
+	}
+	return 0
+}
+
+func (x *RuntimeChannel) GetCanary() string {
+	if x != nil {
+		return x.Canary
+	}
+	return ""
+}
+
+func (x *RuntimeChannel) GetCanarySince() int64 {
+	if x != nil {
+		return x.CanarySince
+	}
+	return 0
+}
+
+func (x *RuntimeChannel) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *RuntimeChannel) GetComparison() *RuntimeChannelComparison {
+	if x != nil {
+		return x.Comparison
+	}
+	return nil
+}
+
+// RuntimeChannelStats counts the jobs on one side of a canary since it was pinned
+type RuntimeChannelStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runtime       string                 `protobuf:"bytes,1,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	Jobs          int32                  `protobuf:"varint,2,opt,name=jobs,proto3" json:"jobs,omitempty"`
+	Finished      int32                  `protobuf:"varint,3,opt,name=finished,proto3" json:"finished,omitempty"` // Completed or failed
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	FailureRate   float64                `protobuf:"fixed64,5,opt,name=failure_rate,json=failureRate,proto3" json:"failure_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeChannelStats) Reset() {
+	*x = RuntimeChannelStats{}
+	mi := &file_runtimes_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeChannelStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeChannelStats) ProtoMessage() {}
+
+func (x *RuntimeChannelStats) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeChannelStats.ProtoReflect.Descriptor instead.
+func (*RuntimeChannelStats) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RuntimeChannelStats) GetRuntime() string {
+	if x != nil {
+		return x.Runtime
+	}
+	return ""
+}
+
+func (x *RuntimeChannelStats) GetJobs() int32 {
+	if x != nil {
+		return x.Jobs
+	}
+	return 0
+}
+
+func (x *RuntimeChannelStats) GetFinished() int32 {
+	if x != nil {
+		return x.Finished
+	}
+	return 0
+}
+
+func (x *RuntimeChannelStats) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *RuntimeChannelStats) GetFailureRate() float64 {
+	if x != nil {
+		return x.FailureRate
+	}
+	return 0
+}
+
+type RuntimeChannelComparison struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Canary          *RuntimeChannelStats   `protobuf:"bytes,1,opt,name=canary,proto3" json:"canary,omitempty"`
+	Stable          *RuntimeChannelStats   `protobuf:"bytes,2,opt,name=stable,proto3" json:"stable,omitempty"`
+	Verdict         string                 `protobuf:"bytes,3,opt,name=verdict,proto3" json:"verdict,omitempty"`                                            // pending, healthy or regressed
+	MinSamples      int32                  `protobuf:"varint,4,opt,name=min_samples,json=minSamples,proto3" json:"min_samples,omitempty"`                   // Finished canary jobs needed for a verdict
+	MaxRateIncrease float64                `protobuf:"fixed64,5,opt,name=max_rate_increase,json=maxRateIncrease,proto3" json:"max_rate_increase,omitempty"` // Failure rate the canary may exceed stable by
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RuntimeChannelComparison) Reset() {
+	*x = RuntimeChannelComparison{}
+	mi := &file_runtimes_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeChannelComparison) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeChannelComparison) ProtoMessage() {}
+
+func (x *RuntimeChannelComparison) ProtoReflect() protoreflect.Message {
+	mi := &file_runtimes_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeChannelComparison.ProtoReflect.Descriptor instead.
+func (*RuntimeChannelComparison) Descriptor() ([]byte, []int) {
+	return file_runtimes_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RuntimeChannelComparison) GetCanary() *RuntimeChannelStats {
+	if x != nil {
+		return x.Canary
+	}
+	return nil
+}
+
+func (x *RuntimeChannelComparison) GetStable() *RuntimeChannelStats {
+	if x != nil {
+		return x.Stable
+	}
+	return nil
+}
+
+func (x *RuntimeChannelComparison) GetVerdict() string {
+	if x != nil {
+		return x.Verdict
+	}
+	return ""
+}
+
+func (x *RuntimeChannelComparison) GetMinSamples() int32 {
+	if x != nil {
+		return x.MinSamples
+	}
+	return 0
+}
+
+func (x *RuntimeChannelComparison) GetMaxRateIncrease() float64 {
+	if x != nil {
+		return x.MaxRateIncrease
+	}
+	return 0
+}
+
+var File_runtimes_proto protoreflect.FileDescriptor
+
+const file_runtimes_proto_rawDesc = "" +
+	"\n" +
+	"\x0eruntimes.proto\x12\x0fjoblet.runtimes\"1\n" +
+	"\x15GetRuntimeInfoRequest\x12\x18\n" +
+	"\aruntime\x18\x01 \x01(\tR\aruntime\"t\n" +
+	"\fRuntimeMount\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x1a\n" +
+	"\breadonly\x18\x03 \x01(\bR\breadonly\x12\x18\n" +
+	"\amissing\x18\x04 \x01(\bR\amissing\";\n" +
+	"\vRuntimeTool\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\xda\x04\n" +
+	"\x0eRuntimeDetails\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x1a\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bresolved\x18\x05 \x01(\tR\bresolved\x12\x1b\n" +
+	"\tpinned_by\x18\x06 \x01(\tR\bpinnedBy\x12\x12\n" +
+	"\x04path\x18\a \x01(\tR\x04path\x125\n" +
+	"\x06mounts\x18\b \x03(\v2\x1d.joblet.runtimes.RuntimeMountR\x06mounts\x12R\n" +
+	"\venvironment\x18\t \x03(\v20.joblet.runtimes.RuntimeDetails.EnvironmentEntryR\venvironment\x12\x19\n" +
+	"\bjob_path\x18\n" +
+	" \x01(\tR\ajobPath\x122\n" +
+	"\x05tools\x18\v \x03(\v2\x1c.joblet.runtimes.RuntimeToolR\x05tools\x12\x1a\n" +
+	"\bpackages\x18\f \x03(\tR\bpackages\x12$\n" +
+	"\rarchitectures\x18\r \x03(\tR\rarchitectures\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x0e \x01(\x03R\tsizeBytes\x12\x14\n" +
+	"\x05files\x18\x0f \x01(\x03R\x05files\x1a>\n" +
+	"\x10EnvironmentEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1c\n" +
+	"\x1aListRuntimeChannelsRequest\"Z\n" +
+	"\x1bListRuntimeChannelsResponse\x12;\n" +
+	"\bchannels\x18\x01 \x03(\v2\x1f.joblet.runtimes.RuntimeChannelR\bchannels\"G\n" +
+	"\x17SetRuntimeCanaryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aruntime\x18\x02 \x01(\tR\aruntime\"+\n" +
+	"\x15RuntimeChannelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"G\n" +
+	"\x1bPromoteRuntimeCanaryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"\xae\x02\n" +
+	"\x0eRuntimeChannel\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06stable\x18\x02 \x01(\tR\x06stable\x12(\n" +
+	"\x10stable_pinned_by\x18\x03 \x01(\tR\x0establePinnedBy\x12!\n" +
+	"\fstable_since\x18\x04 \x01(\x03R\vstableSince\x12\x16\n" +
+	"\x06canary\x18\x05 \x01(\tR\x06canary\x12!\n" +
+	"\fcanary_since\x18\x06 \x01(\x03R\vcanarySince\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\a \x01(\tR\tupdatedBy\x12I\n" +
+	"\n" +
+	"comparison\x18\b \x01(\v2).joblet.runtimes.RuntimeChannelComparisonR\n" +
+	"comparison\"\x9a\x01\n" +
+	"\x13RuntimeChannelStats\x12\x18\n" +
+	"\aruntime\x18\x01 \x01(\tR\aruntime\x12\x12\n" +
+	"\x04jobs\x18\x02 \x01(\x05R\x04jobs\x12\x1a\n" +
+	"\bfinished\x18\x03 \x01(\x05R\bfinished\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x12!\n" +
+	"\ffailure_rate\x18\x05 \x01(\x01R\vfailureRate\"\xfd\x01\n" +
+	"\x18RuntimeChannelComparison\x12<\n" +
+	"\x06canary\x18\x01 \x01(\v2$.joblet.runtimes.RuntimeChannelStatsR\x06canary\x12<\n" +
+	"\x06stable\x18\x02 \x01(\v2$.joblet.runtimes.RuntimeChannelStatsR\x06stable\x12\x18\n" +
+	"\averdict\x18\x03 \x01(\tR\averdict\x12\x1f\n" +
+	"\vmin_samples\x18\x04 \x01(\x05R\n" +
+	"minSamples\x12*\n" +
+	"\x11max_rate_increase\x18\x05 \x01(\x01R\x0fmaxRateIncrease2o\n" +
+	"\x12RuntimeInfoService\x12Y\n" +
+	"\x0eGetRuntimeInfo\x12&.joblet.runtimes.GetRuntimeInfoRequest\x1a\x1f.joblet.runtimes.RuntimeDetails2\xae\x03\n" +
+	"\x15RuntimeChannelService\x12p\n" +
+	"\x13ListRuntimeChannels\x12+.joblet.runtimes.ListRuntimeChannelsRequest\x1a,.joblet.runtimes.ListRuntimeChannelsResponse\x12]\n" +
+	"\x10SetRuntimeCanary\x12(.joblet.runtimes.SetRuntimeCanaryRequest\x1a\x1f.joblet.runtimes.RuntimeChannel\x12]\n" +
+	"\x12ClearRuntimeCanary\x12&.joblet.runtimes.RuntimeChannelRequest\x1a\x1f.joblet.runtimes.RuntimeChannel\x12e\n" +
+	"\x14PromoteRuntimeCanary\x12,.joblet.runtimes.PromoteRuntimeCanaryRequest\x1a\x1f.joblet.runtimes.RuntimeChannelB9Z7github.com/ehsaniara/joblet/internal/proto/gen/runtimesb\x06proto3"
+
+var (
+	file_runtimes_proto_rawDescOnce sync.Once
+	file_runtimes_proto_rawDescData []byte
+)
+
+func file_runtimes_proto_rawDescGZIP() []byte {
+	file_runtimes_proto_rawDescOnce.Do(func() {
+		file_runtimes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_runtimes_proto_rawDesc), len(file_runtimes_proto_rawDesc)))
+	})
+	return file_runtimes_proto_rawDescData
+}
+
+var file_runtimes_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_runtimes_proto_goTypes = []any{
+	(*GetRuntimeInfoRequest)(nil),       // 0: joblet.runtimes.GetRuntimeInfoRequest
+	(*RuntimeMount)(nil),                // 1: joblet.runtimes.RuntimeMount
+	(*RuntimeTool)(nil),                 // 2: joblet.runtimes.RuntimeTool
+	(*RuntimeDetails)(nil),              // 3: joblet.runtimes.RuntimeDetails
+	(*ListRuntimeChannelsRequest)(nil),  // 4: joblet.runtimes.ListRuntimeChannelsRequest
+	(*ListRuntimeChannelsResponse)(nil), // 5: joblet.runtimes.ListRuntimeChannelsResponse
+	(*SetRuntimeCanaryRequest)(nil),     // 6: joblet.runtimes.SetRuntimeCanaryRequest
+	(*RuntimeChannelRequest)(nil),       // 7: joblet.runtimes.RuntimeChannelRequest
+	(*PromoteRuntimeCanaryRequest)(nil), // 8: joblet.runtimes.PromoteRuntimeCanaryRequest
+	(*RuntimeChannel)(nil),              // 9: joblet.runtimes.RuntimeChannel
+	(*RuntimeChannelStats)(nil),         // 10: joblet.runtimes.RuntimeChannelStats
+	(*RuntimeChannelComparison)(nil),    // 11: joblet.runtimes.RuntimeChannelComparison
+	nil,                                 // 12: joblet.runtimes.RuntimeDetails.EnvironmentEntry
+}
+var file_runtimes_proto_depIdxs = []int32{
+	1,  // 0: joblet.runtimes.RuntimeDetails.mounts:type_name -> joblet.runtimes.RuntimeMount
+	12, // 1: joblet.runtimes.RuntimeDetails.environment:type_name -> joblet.runtimes.RuntimeDetails.EnvironmentEntry
+	2,  // 2: joblet.runtimes.RuntimeDetails.tools:type_name -> joblet.runtimes.RuntimeTool
+	9,  // 3: joblet.runtimes.ListRuntimeChannelsResponse.channels:type_name -> joblet.runtimes.RuntimeChannel
+	11, // 4: joblet.runtimes.RuntimeChannel.comparison:type_name -> joblet.runtimes.RuntimeChannelComparison
+	10, // 5: joblet.runtimes.RuntimeChannelComparison.canary:type_name -> joblet.runtimes.RuntimeChannelStats
+	10, // 6: joblet.runtimes.RuntimeChannelComparison.stable:type_name -> joblet.runtimes.RuntimeChannelStats
+	0,  // 7: joblet.runtimes.RuntimeInfoService.GetRuntimeInfo:input_type -> joblet.runtimes.GetRuntimeInfoRequest
+	4,  // 8: joblet.runtimes.RuntimeChannelService.ListRuntimeChannels:input_type -> joblet.runtimes.ListRuntimeChannelsRequest
+	6,  // 9: joblet.runtimes.RuntimeChannelService.SetRuntimeCanary:input_type -> joblet.runtimes.SetRuntimeCanaryRequest
+	7,  // 10: joblet.runtimes.RuntimeChannelService.ClearRuntimeCanary:input_type -> joblet.runtimes.RuntimeChannelRequest
+	8,  // 11: joblet.runtimes.RuntimeChannelService.PromoteRuntimeCanary:input_type -> joblet.runtimes.PromoteRuntimeCanaryRequest
+	3,  // 12: joblet.runtimes.RuntimeInfoService.GetRuntimeInfo:output_type -> joblet.runtimes.RuntimeDetails
+	5,  // 13: joblet.runtimes.RuntimeChannelService.ListRuntimeChannels:output_type -> joblet.runtimes.ListRuntimeChannelsResponse
+	9,  // 14: joblet.runtimes.RuntimeChannelService.SetRuntimeCanary:output_type -> joblet.runtimes.RuntimeChannel
+	9,  // 15: joblet.runtimes.RuntimeChannelService.ClearRuntimeCanary:output_type -> joblet.runtimes.RuntimeChannel
+	9,  // 16: joblet.runtimes.RuntimeChannelService.PromoteRuntimeCanary:output_type -> joblet.runtimes.RuntimeChannel
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_runtimes_proto_init() }
+func file_runtimes_proto_init() {
+	if File_runtimes_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_runtimes_proto_rawDesc), len(file_runtimes_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_runtimes_proto_goTypes,
+		DependencyIndexes: file_runtimes_proto_depIdxs,
+		MessageInfos:      file_runtimes_proto_msgTypes,
+	}.Build()
+	File_runtimes_proto = out.File
+	file_runtimes_proto_goTypes = nil
+	file_runtimes_proto_depIdxs = nil
+}
@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: runtimes.proto
+
+package runtimes
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RuntimeInfoService_GetRuntimeInfo_FullMethodName = "/joblet.runtimes.RuntimeInfoService/GetRuntimeInfo"
+)
+
+// RuntimeInfoServiceClient is the client API for RuntimeInfoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RuntimeInfoService describes what an installed runtime gives its jobs.
+//
+// It complements the public RuntimeService, whose GetRuntimeInfo only carries
+// the runtime's name, version and packages. 'rnx runtime info' uses it to show
+// the mounts, environment and tools a job on the runtime gets, and how much disk
+// the runtime takes, before anything runs on it.
+type RuntimeInfoServiceClient interface {
+	// Describe the runtime a name resolves to for the caller
+	GetRuntimeInfo(ctx context.Context, in *GetRuntimeInfoRequest, opts ...grpc.CallOption) (*RuntimeDetails, error)
+}
+
+type runtimeInfoServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRuntimeInfoServiceClient(cc grpc.ClientConnInterface) RuntimeInfoServiceClient {
+	return &runtimeInfoServiceClient{cc}
+}
+
+func (c *runtimeInfoServiceClient) GetRuntimeInfo(ctx context.Context, in *GetRuntimeInfoRequest, opts ...grpc.CallOption) (*RuntimeDetails, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuntimeDetails)
+	err := c.cc.Invoke(ctx, RuntimeInfoService_GetRuntimeInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RuntimeInfoServiceServer is the server API for RuntimeInfoService service.
+// All implementations must embed UnimplementedRuntimeInfoServiceServer
+// for forward compatibility.
+//
+// RuntimeInfoService describes what an installed runtime gives its jobs.
+//
+// It complements the public RuntimeService, whose GetRuntimeInfo only carries
+// the runtime's name, version and packages. 'rnx runtime info' uses it to show
+// the mounts, environment and tools a job on the runtime gets, and how much disk
+// the runtime takes, before anything runs on it.
+type RuntimeInfoServiceServer interface {
+	// Describe the runtime a name resolves to for the caller
+	GetRuntimeInfo(context.Context, *GetRuntimeInfoRequest) (*RuntimeDetails, error)
+	mustEmbedUnimplementedRuntimeInfoServiceServer()
+}
+
+// UnimplementedRuntimeInfoServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRuntimeInfoServiceServer struct{}
+
+func (UnimplementedRuntimeInfoServiceServer) GetRuntimeInfo(context.Context, *GetRuntimeInfoRequest) (*RuntimeDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuntimeInfo not implemented")
+}
+func (UnimplementedRuntimeInfoServiceServer) mustEmbedUnimplementedRuntimeInfoServiceServer() {}
+func (UnimplementedRuntimeInfoServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeRuntimeInfoServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RuntimeInfoServiceServer will
+// result in compilation errors.
+type UnsafeRuntimeInfoServiceServer interface {
+	mustEmbedUnimplementedRuntimeInfoServiceServer()
+}
+
+func RegisterRuntimeInfoServiceServer(s grpc.ServiceRegistrar, srv RuntimeInfoServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRuntimeInfoServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RuntimeInfoService_ServiceDesc, srv)
+}
+
+func _RuntimeInfoService_GetRuntimeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuntimeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeInfoServiceServer).GetRuntimeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RuntimeInfoService_GetRuntimeInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeInfoServiceServer).GetRuntimeInfo(ctx, req.(*GetRuntimeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RuntimeInfoService_ServiceDesc is the grpc.ServiceDesc for RuntimeInfoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RuntimeInfoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.runtimes.RuntimeInfoService",
+	HandlerType: (*RuntimeInfoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRuntimeInfo",
+			Handler:    _RuntimeInfoService_GetRuntimeInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "runtimes.proto",
+}
+
+const (
+	RuntimeChannelService_ListRuntimeChannels_FullMethodName  = "/joblet.runtimes.RuntimeChannelService/ListRuntimeChannels"
+	RuntimeChannelService_SetRuntimeCanary_FullMethodName     = "/joblet.runtimes.RuntimeChannelService/SetRuntimeCanary"
+	RuntimeChannelService_ClearRuntimeCanary_FullMethodName   = "/joblet.runtimes.RuntimeChannelService/ClearRuntimeCanary"
+	RuntimeChannelService_PromoteRuntimeCanary_FullMethodName = "/joblet.runtimes.RuntimeChannelService/PromoteRuntimeCanary"
+)
+
+// RuntimeChannelServiceClient is the client API for RuntimeChannelService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RuntimeChannelService moves the stable and canary channels of runtime names.
+//
+// An admin pins a new runtime version to the canary channel of a name, such as
+// python; jobs asking for "python@canary" run on it while the other jobs asking
+// for python keep their runtime. The failure rates of both are compared from
+// the jobs the node ran since, and promoting the canary makes it the runtime
+// every job asking for the name gets.
+type RuntimeChannelServiceClient interface {
+	// List the channels of every runtime name with pins
+	ListRuntimeChannels(ctx context.Context, in *ListRuntimeChannelsRequest, opts ...grpc.CallOption) (*ListRuntimeChannelsResponse, error)
+	// Pin an installed runtime to the canary channel of a name
+	SetRuntimeCanary(ctx context.Context, in *SetRuntimeCanaryRequest, opts ...grpc.CallOption) (*RuntimeChannel, error)
+	// Remove the canary of a name, sending its jobs back to stable
+	ClearRuntimeCanary(ctx context.Context, in *RuntimeChannelRequest, opts ...grpc.CallOption) (*RuntimeChannel, error)
+	// Make the canary of a name its stable runtime
+	PromoteRuntimeCanary(ctx context.Context, in *PromoteRuntimeCanaryRequest, opts ...grpc.CallOption) (*RuntimeChannel, error)
+}
+
+type runtimeChannelServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRuntimeChannelServiceClient(cc grpc.ClientConnInterface) RuntimeChannelServiceClient {
+	return &runtimeChannelServiceClient{cc}
+}
+
+func (c *runtimeChannelServiceClient) ListRuntimeChannels(ctx context.Context, in *ListRuntimeChannelsRequest, opts ...grpc.CallOption) (*ListRuntimeChannelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRuntimeChannelsResponse)
+	err := c.cc.Invoke(ctx, RuntimeChannelService_ListRuntimeChannels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeChannelServiceClient) SetRuntimeCanary(ctx context.Context, in *SetRuntimeCanaryRequest, opts ...grpc.CallOption) (*RuntimeChannel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuntimeChannel)
+	err := c.cc.Invoke(ctx, RuntimeChannelService_SetRuntimeCanary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeChannelServiceClient) ClearRuntimeCanary(ctx context.Context, in *RuntimeChannelRequest, opts ...grpc.CallOption) (*RuntimeChannel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuntimeChannel)
+	err := c.cc.Invoke(ctx, RuntimeChannelService_ClearRuntimeCanary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeChannelServiceClient) PromoteRuntimeCanary(ctx context.Context, in *PromoteRuntimeCanaryRequest, opts ...grpc.CallOption) (*RuntimeChannel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuntimeChannel)
+	err := c.cc.Invoke(ctx, RuntimeChannelService_PromoteRuntimeCanary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RuntimeChannelServiceServer is the server API for RuntimeChannelService service.
+// All implementations must embed UnimplementedRuntimeChannelServiceServer
+// for forward compatibility.
+//
+// RuntimeChannelService moves the stable and canary channels of runtime names.
+//
+// An admin pins a new runtime version to the canary channel of a name, such as
+// python; jobs asking for "python@canary" run on it while the other jobs asking
+// for python keep their runtime. The failure rates of both are compared from
+// the jobs the node ran since, and promoting the canary makes it the runtime
+// every job asking for the name gets.
+type RuntimeChannelServiceServer interface {
+	// List the channels of every runtime name with pins
+	ListRuntimeChannels(context.Context, *ListRuntimeChannelsRequest) (*ListRuntimeChannelsResponse, error)
+	// Pin an installed runtime to the canary channel of a name
+	SetRuntimeCanary(context.Context, *SetRuntimeCanaryRequest) (*RuntimeChannel, error)
+	// Remove the canary of a name, sending its jobs back to stable
+	ClearRuntimeCanary(context.Context, *RuntimeChannelRequest) (*RuntimeChannel, error)
+	// Make the canary of a name its stable runtime
+	PromoteRuntimeCanary(context.Context, *PromoteRuntimeCanaryRequest) (*RuntimeChannel, error)
+	mustEmbedUnimplementedRuntimeChannelServiceServer()
+}
+
+// UnimplementedRuntimeChannelServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRuntimeChannelServiceServer struct{}
+
+func (UnimplementedRuntimeChannelServiceServer) ListRuntimeChannels(context.Context, *ListRuntimeChannelsRequest) (*ListRuntimeChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRuntimeChannels not implemented")
+}
+func (UnimplementedRuntimeChannelServiceServer) SetRuntimeCanary(context.Context, *SetRuntimeCanaryRequest) (*RuntimeChannel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRuntimeCanary not implemented")
+}
+func (UnimplementedRuntimeChannelServiceServer) ClearRuntimeCanary(context.Context, *RuntimeChannelRequest) (*RuntimeChannel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearRuntimeCanary not implemented")
+}
+func (UnimplementedRuntimeChannelServiceServer) PromoteRuntimeCanary(context.Context, *PromoteRuntimeCanaryRequest) (*RuntimeChannel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PromoteRuntimeCanary not implemented")
+}
+func (UnimplementedRuntimeChannelServiceServer) mustEmbedUnimplementedRuntimeChannelServiceServer() {}
+func (UnimplementedRuntimeChannelServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeRuntimeChannelServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RuntimeChannelServiceServer will
+// result in compilation errors.
+type UnsafeRuntimeChannelServiceServer interface {
+	mustEmbedUnimplementedRuntimeChannelServiceServer()
+}
+
+func RegisterRuntimeChannelServiceServer(s grpc.ServiceRegistrar, srv RuntimeChannelServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRuntimeChannelServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RuntimeChannelService_ServiceDesc, srv)
+}
+
+func _RuntimeChannelService_ListRuntimeChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRuntimeChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeChannelServiceServer).ListRuntimeChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RuntimeChannelService_ListRuntimeChannels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeChannelServiceServer).ListRuntimeChannels(ctx, req.(*ListRuntimeChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeChannelService_SetRuntimeCanary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRuntimeCanaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeChannelServiceServer).SetRuntimeCanary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RuntimeChannelService_SetRuntimeCanary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeChannelServiceServer).SetRuntimeCanary(ctx, req.(*SetRuntimeCanaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeChannelService_ClearRuntimeCanary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuntimeChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeChannelServiceServer).ClearRuntimeCanary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RuntimeChannelService_ClearRuntimeCanary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeChannelServiceServer).ClearRuntimeCanary(ctx, req.(*RuntimeChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeChannelService_PromoteRuntimeCanary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteRuntimeCanaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeChannelServiceServer).PromoteRuntimeCanary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RuntimeChannelService_PromoteRuntimeCanary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeChannelServiceServer).PromoteRuntimeCanary(ctx, req.(*PromoteRuntimeCanaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RuntimeChannelService_ServiceDesc is the grpc.ServiceDesc for RuntimeChannelService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RuntimeChannelService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.runtimes.RuntimeChannelService",
+	HandlerType: (*RuntimeChannelServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRuntimeChannels",
+			Handler:    _RuntimeChannelService_ListRuntimeChannels_Handler,
+		},
+		{
+			MethodName: "SetRuntimeCanary",
+			Handler:    _RuntimeChannelService_SetRuntimeCanary_Handler,
+		},
+		{
+			MethodName: "ClearRuntimeCanary",
+			Handler:    _RuntimeChannelService_ClearRuntimeCanary_Handler,
+		},
+		{
+			MethodName: "PromoteRuntimeCanary",
+			Handler:    _RuntimeChannelService_PromoteRuntimeCanary_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "runtimes.proto",
+}
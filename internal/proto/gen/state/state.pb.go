@@ -0,0 +1,670 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: state.proto
+
+package state
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// JobStateEventType says what happened to a job state
+type JobStateEventType int32
+
+const (
+	JobStateEventType_JOB_STATE_EVENT_TYPE_UNSPECIFIED JobStateEventType = 0
+	JobStateEventType_JOB_STATE_EVENT_TYPE_SNAPSHOT    JobStateEventType = 1 // Current state, sent when the watch starts
+	JobStateEventType_JOB_STATE_EVENT_TYPE_CREATED     JobStateEventType = 2
+	JobStateEventType_JOB_STATE_EVENT_TYPE_UPDATED     JobStateEventType = 3
+	JobStateEventType_JOB_STATE_EVENT_TYPE_DELETED     JobStateEventType = 4 // Only uuid is set
+)
+
+// Enum value maps for JobStateEventType.
+var (
+	JobStateEventType_name = map[int32]string{
+		0: "JOB_STATE_EVENT_TYPE_UNSPECIFIED",
+		1: "JOB_STATE_EVENT_TYPE_SNAPSHOT",
+		2: "JOB_STATE_EVENT_TYPE_CREATED",
+		3: "JOB_STATE_EVENT_TYPE_UPDATED",
+		4: "JOB_STATE_EVENT_TYPE_DELETED",
+	}
+	JobStateEventType_value = map[string]int32{
+		"JOB_STATE_EVENT_TYPE_UNSPECIFIED": 0,
+		"JOB_STATE_EVENT_TYPE_SNAPSHOT":    1,
+		"JOB_STATE_EVENT_TYPE_CREATED":     2,
+		"JOB_STATE_EVENT_TYPE_UPDATED":     3,
+		"JOB_STATE_EVENT_TYPE_DELETED":     4,
+	}
+)
+
+func (x JobStateEventType) Enum() *JobStateEventType {
+	p := new(JobStateEventType)
+	*p = x
+	return p
+}
+
+func (x JobStateEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobStateEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_state_proto_enumTypes[0].Descriptor()
+}
+
+func (JobStateEventType) Type() protoreflect.EnumType {
+	return &file_state_proto_enumTypes[0]
+}
+
+func (x JobStateEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobStateEventType.Descriptor instead.
+func (JobStateEventType) EnumDescriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{0}
+}
+
+// JobState is the stored state of a job. Secret environment values are never included.
+type JobState struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Uuid               string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`     // Workflow job name, empty for individual jobs
+	Status             string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // PENDING, SCHEDULED, RUNNING, COMPLETED, FAILED, STOPPED, ...
+	Command            string                 `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	Args               []string               `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	NodeId             string                 `protobuf:"bytes,6,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	WorkflowUuid       string                 `protobuf:"bytes,7,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`
+	Runtime            string                 `protobuf:"bytes,8,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	Network            string                 `protobuf:"bytes,9,opt,name=network,proto3" json:"network,omitempty"`
+	StartTime          int64                  `protobuf:"varint,10,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`             // Unix seconds
+	EndTime            int64                  `protobuf:"varint,11,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                   // Unix seconds, 0 while the job is active
+	ScheduledTime      int64                  `protobuf:"varint,12,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"` // Unix seconds, 0 for immediate jobs
+	ExitCode           int32                  `protobuf:"varint,13,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	GpuCount           int32                  `protobuf:"varint,14,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	Redactions         int64                  `protobuf:"varint,15,opt,name=redactions,proto3" json:"redactions,omitempty"`                                             // Secret matches masked in the job's output
+	OutputDroppedBytes int64                  `protobuf:"varint,16,opt,name=output_dropped_bytes,json=outputDroppedBytes,proto3" json:"output_dropped_bytes,omitempty"` // Output dropped over the node's output limits
+	OutputTruncated    bool                   `protobuf:"varint,17,opt,name=output_truncated,json=outputTruncated,proto3" json:"output_truncated,omitempty"`            // The job reached the output size cap
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *JobState) Reset() {
+	*x = JobState{}
+	mi := &file_state_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobState) ProtoMessage() {}
+
+func (x *JobState) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobState.ProtoReflect.Descriptor instead.
+func (*JobState) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *JobState) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *JobState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobState) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *JobState) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *JobState) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *JobState) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *JobState) GetRuntime() string {
+	if x != nil {
+		return x.Runtime
+	}
+	return ""
+}
+
+func (x *JobState) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *JobState) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *JobState) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *JobState) GetScheduledTime() int64 {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return 0
+}
+
+func (x *JobState) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *JobState) GetGpuCount() int32 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *JobState) GetRedactions() int64 {
+	if x != nil {
+		return x.Redactions
+	}
+	return 0
+}
+
+func (x *JobState) GetOutputDroppedBytes() int64 {
+	if x != nil {
+		return x.OutputDroppedBytes
+	}
+	return 0
+}
+
+func (x *JobState) GetOutputTruncated() bool {
+	if x != nil {
+		return x.OutputTruncated
+	}
+	return false
+}
+
+// ListJobStatesRequest filters a job state listing
+type ListJobStatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`                           // Any of these statuses (empty = all)
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`                 // Only jobs run by this node
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                // Max jobs to return (0 = all)
+	NewestFirst   bool                   `protobuf:"varint,4,opt,name=newest_first,json=newestFirst,proto3" json:"newest_first,omitempty"` // Sort by start time, newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobStatesRequest) Reset() {
+	*x = ListJobStatesRequest{}
+	mi := &file_state_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobStatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobStatesRequest) ProtoMessage() {}
+
+func (x *ListJobStatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobStatesRequest.ProtoReflect.Descriptor instead.
+func (*ListJobStatesRequest) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListJobStatesRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListJobStatesRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ListJobStatesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListJobStatesRequest) GetNewestFirst() bool {
+	if x != nil {
+		return x.NewestFirst
+	}
+	return false
+}
+
+// ListJobStatesResponse holds the matching job states
+type ListJobStatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*JobState            `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobStatesResponse) Reset() {
+	*x = ListJobStatesResponse{}
+	mi := &file_state_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobStatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobStatesResponse) ProtoMessage() {}
+
+func (x *ListJobStatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobStatesResponse.ProtoReflect.Descriptor instead.
+func (*ListJobStatesResponse) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListJobStatesResponse) GetJobs() []*JobState {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// GetJobStateRequest identifies one job
+type GetJobStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobStateRequest) Reset() {
+	*x = GetJobStateRequest{}
+	mi := &file_state_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStateRequest) ProtoMessage() {}
+
+func (x *GetJobStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStateRequest.ProtoReflect.Descriptor instead.
+func (*GetJobStateRequest) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetJobStateRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+// WatchJobStatesRequest filters a job state watch stream
+type WatchJobStatesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Statuses        []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`                                       // Only changes that leave a job in one of these statuses
+	NodeId          string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`                             // Only jobs run by this node
+	Uuid            string                 `protobuf:"bytes,3,opt,name=uuid,proto3" json:"uuid,omitempty"`                                               // Only this job
+	InitialSnapshot bool                   `protobuf:"varint,4,opt,name=initial_snapshot,json=initialSnapshot,proto3" json:"initial_snapshot,omitempty"` // Send the current matching states before changes
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchJobStatesRequest) Reset() {
+	*x = WatchJobStatesRequest{}
+	mi := &file_state_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchJobStatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchJobStatesRequest) ProtoMessage() {}
+
+func (x *WatchJobStatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchJobStatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobStatesRequest) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchJobStatesRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *WatchJobStatesRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *WatchJobStatesRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *WatchJobStatesRequest) GetInitialSnapshot() bool {
+	if x != nil {
+		return x.InitialSnapshot
+	}
+	return false
+}
+
+// JobStateEvent is one change in a watch stream
+type JobStateEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          JobStateEventType      `protobuf:"varint,1,opt,name=type,proto3,enum=joblet.state.JobStateEventType" json:"type,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Job           *JobState              `protobuf:"bytes,3,opt,name=job,proto3" json:"job,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix nanoseconds when the state service saw the change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStateEvent) Reset() {
+	*x = JobStateEvent{}
+	mi := &file_state_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStateEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStateEvent) ProtoMessage() {}
+
+func (x *JobStateEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_state_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStateEvent.ProtoReflect.Descriptor instead.
+func (*JobStateEvent) Descriptor() ([]byte, []int) {
+	return file_state_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *JobStateEvent) GetType() JobStateEventType {
+	if x != nil {
+		return x.Type
+	}
+	return JobStateEventType_JOB_STATE_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *JobStateEvent) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *JobStateEvent) GetJob() *JobState {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *JobStateEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+var File_state_proto protoreflect.FileDescriptor
+
+const file_state_proto_rawDesc = "" +
+	"\n" +
+	"\vstate.proto\x12\fjoblet.state\"\x82\x04\n" +
+	"\bJobState\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x18\n" +
+	"\acommand\x18\x04 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x05 \x03(\tR\x04args\x12\x17\n" +
+	"\anode_id\x18\x06 \x01(\tR\x06nodeId\x12#\n" +
+	"\rworkflow_uuid\x18\a \x01(\tR\fworkflowUuid\x12\x18\n" +
+	"\aruntime\x18\b \x01(\tR\aruntime\x12\x18\n" +
+	"\anetwork\x18\t \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\n" +
+	" \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\v \x01(\x03R\aendTime\x12%\n" +
+	"\x0escheduled_time\x18\f \x01(\x03R\rscheduledTime\x12\x1b\n" +
+	"\texit_code\x18\r \x01(\x05R\bexitCode\x12\x1b\n" +
+	"\tgpu_count\x18\x0e \x01(\x05R\bgpuCount\x12\x1e\n" +
+	"\n" +
+	"redactions\x18\x0f \x01(\x03R\n" +
+	"redactions\x120\n" +
+	"\x14output_dropped_bytes\x18\x10 \x01(\x03R\x12outputDroppedBytes\x12)\n" +
+	"\x10output_truncated\x18\x11 \x01(\bR\x0foutputTruncated\"\x84\x01\n" +
+	"\x14ListJobStatesRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12!\n" +
+	"\fnewest_first\x18\x04 \x01(\bR\vnewestFirst\"C\n" +
+	"\x15ListJobStatesResponse\x12*\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x16.joblet.state.JobStateR\x04jobs\"(\n" +
+	"\x12GetJobStateRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x8b\x01\n" +
+	"\x15WatchJobStatesRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x12\n" +
+	"\x04uuid\x18\x03 \x01(\tR\x04uuid\x12)\n" +
+	"\x10initial_snapshot\x18\x04 \x01(\bR\x0finitialSnapshot\"\xa0\x01\n" +
+	"\rJobStateEvent\x123\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1f.joblet.state.JobStateEventTypeR\x04type\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12(\n" +
+	"\x03job\x18\x03 \x01(\v2\x16.joblet.state.JobStateR\x03job\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp*\xc2\x01\n" +
+	"\x11JobStateEventType\x12$\n" +
+	" JOB_STATE_EVENT_TYPE_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dJOB_STATE_EVENT_TYPE_SNAPSHOT\x10\x01\x12 \n" +
+	"\x1cJOB_STATE_EVENT_TYPE_CREATED\x10\x02\x12 \n" +
+	"\x1cJOB_STATE_EVENT_TYPE_UPDATED\x10\x03\x12 \n" +
+	"\x1cJOB_STATE_EVENT_TYPE_DELETED\x10\x042\x87\x02\n" +
+	"\fStateService\x12X\n" +
+	"\rListJobStates\x12\".joblet.state.ListJobStatesRequest\x1a#.joblet.state.ListJobStatesResponse\x12G\n" +
+	"\vGetJobState\x12 .joblet.state.GetJobStateRequest\x1a\x16.joblet.state.JobState\x12T\n" +
+	"\x0eWatchJobStates\x12#.joblet.state.WatchJobStatesRequest\x1a\x1b.joblet.state.JobStateEvent0\x01B6Z4github.com/ehsaniara/joblet/internal/proto/gen/stateb\x06proto3"
+
+var (
+	file_state_proto_rawDescOnce sync.Once
+	file_state_proto_rawDescData []byte
+)
+
+func file_state_proto_rawDescGZIP() []byte {
+	file_state_proto_rawDescOnce.Do(func() {
+		file_state_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_state_proto_rawDesc), len(file_state_proto_rawDesc)))
+	})
+	return file_state_proto_rawDescData
+}
+
+var file_state_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_state_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_state_proto_goTypes = []any{
+	(JobStateEventType)(0),        // 0: joblet.state.JobStateEventType
+	(*JobState)(nil),              // 1: joblet.state.JobState
+	(*ListJobStatesRequest)(nil),  // 2: joblet.state.ListJobStatesRequest
+	(*ListJobStatesResponse)(nil), // 3: joblet.state.ListJobStatesResponse
+	(*GetJobStateRequest)(nil),    // 4: joblet.state.GetJobStateRequest
+	(*WatchJobStatesRequest)(nil), // 5: joblet.state.WatchJobStatesRequest
+	(*JobStateEvent)(nil),         // 6: joblet.state.JobStateEvent
+}
+var file_state_proto_depIdxs = []int32{
+	1, // 0: joblet.state.ListJobStatesResponse.jobs:type_name -> joblet.state.JobState
+	0, // 1: joblet.state.JobStateEvent.type:type_name -> joblet.state.JobStateEventType
+	1, // 2: joblet.state.JobStateEvent.job:type_name -> joblet.state.JobState
+	2, // 3: joblet.state.StateService.ListJobStates:input_type -> joblet.state.ListJobStatesRequest
+	4, // 4: joblet.state.StateService.GetJobState:input_type -> joblet.state.GetJobStateRequest
+	5, // 5: joblet.state.StateService.WatchJobStates:input_type -> joblet.state.WatchJobStatesRequest
+	3, // 6: joblet.state.StateService.ListJobStates:output_type -> joblet.state.ListJobStatesResponse
+	1, // 7: joblet.state.StateService.GetJobState:output_type -> joblet.state.JobState
+	6, // 8: joblet.state.StateService.WatchJobStates:output_type -> joblet.state.JobStateEvent
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_state_proto_init() }
+func file_state_proto_init() {
+	if File_state_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_state_proto_rawDesc), len(file_state_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_state_proto_goTypes,
+		DependencyIndexes: file_state_proto_depIdxs,
+		EnumInfos:         file_state_proto_enumTypes,
+		MessageInfos:      file_state_proto_msgTypes,
+	}.Build()
+	File_state_proto = out.File
+	file_state_proto_goTypes = nil
+	file_state_proto_depIdxs = nil
+}
@@ -0,0 +1,225 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: state.proto
+
+package state
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	StateService_ListJobStates_FullMethodName  = "/joblet.state.StateService/ListJobStates"
+	StateService_GetJobState_FullMethodName    = "/joblet.state.StateService/GetJobState"
+	StateService_WatchJobStates_FullMethodName = "/joblet.state.StateService/WatchJobStates"
+)
+
+// StateServiceClient is the client API for StateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// StateService is a READ-ONLY view of the job states held by the state service.
+//
+// It lets dashboards and external schedulers read authoritative job state
+// without going to the storage backend (e.g. DynamoDB) directly. Writes still
+// only come from joblet over the state IPC socket.
+//
+// Served over TCP with mutual TLS using the node's certificates; admin and
+// viewer client certificates are accepted.
+type StateServiceClient interface {
+	// List job states, optionally filtered by status and node
+	ListJobStates(ctx context.Context, in *ListJobStatesRequest, opts ...grpc.CallOption) (*ListJobStatesResponse, error)
+	// Get the state of one job
+	GetJobState(ctx context.Context, in *GetJobStateRequest, opts ...grpc.CallOption) (*JobState, error)
+	// Stream job state changes as joblet records them
+	WatchJobStates(ctx context.Context, in *WatchJobStatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobStateEvent], error)
+}
+
+type stateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStateServiceClient(cc grpc.ClientConnInterface) StateServiceClient {
+	return &stateServiceClient{cc}
+}
+
+func (c *stateServiceClient) ListJobStates(ctx context.Context, in *ListJobStatesRequest, opts ...grpc.CallOption) (*ListJobStatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobStatesResponse)
+	err := c.cc.Invoke(ctx, StateService_ListJobStates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateServiceClient) GetJobState(ctx context.Context, in *GetJobStateRequest, opts ...grpc.CallOption) (*JobState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobState)
+	err := c.cc.Invoke(ctx, StateService_GetJobState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateServiceClient) WatchJobStates(ctx context.Context, in *WatchJobStatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobStateEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StateService_ServiceDesc.Streams[0], StateService_WatchJobStates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchJobStatesRequest, JobStateEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_WatchJobStatesClient = grpc.ServerStreamingClient[JobStateEvent]
+
+// StateServiceServer is the server API for StateService service.
+// All implementations must embed UnimplementedStateServiceServer
+// for forward compatibility.
+//
+// StateService is a READ-ONLY view of the job states held by the state service.
+//
+// It lets dashboards and external schedulers read authoritative job state
+// without going to the storage backend (e.g. DynamoDB) directly. Writes still
+// only come from joblet over the state IPC socket.
+//
+// Served over TCP with mutual TLS using the node's certificates; admin and
+// viewer client certificates are accepted.
+type StateServiceServer interface {
+	// List job states, optionally filtered by status and node
+	ListJobStates(context.Context, *ListJobStatesRequest) (*ListJobStatesResponse, error)
+	// Get the state of one job
+	GetJobState(context.Context, *GetJobStateRequest) (*JobState, error)
+	// Stream job state changes as joblet records them
+	WatchJobStates(*WatchJobStatesRequest, grpc.ServerStreamingServer[JobStateEvent]) error
+	mustEmbedUnimplementedStateServiceServer()
+}
+
+// UnimplementedStateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStateServiceServer struct{}
+
+func (UnimplementedStateServiceServer) ListJobStates(context.Context, *ListJobStatesRequest) (*ListJobStatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobStates not implemented")
+}
+func (UnimplementedStateServiceServer) GetJobState(context.Context, *GetJobStateRequest) (*JobState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobState not implemented")
+}
+func (UnimplementedStateServiceServer) WatchJobStates(*WatchJobStatesRequest, grpc.ServerStreamingServer[JobStateEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJobStates not implemented")
+}
+func (UnimplementedStateServiceServer) mustEmbedUnimplementedStateServiceServer() {}
+func (UnimplementedStateServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeStateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StateServiceServer will
+// result in compilation errors.
+type UnsafeStateServiceServer interface {
+	mustEmbedUnimplementedStateServiceServer()
+}
+
+func RegisterStateServiceServer(s grpc.ServiceRegistrar, srv StateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StateService_ServiceDesc, srv)
+}
+
+func _StateService_ListJobStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServiceServer).ListJobStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateService_ListJobStates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).ListJobStates(ctx, req.(*ListJobStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateService_GetJobState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServiceServer).GetJobState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateService_GetJobState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).GetJobState(ctx, req.(*GetJobStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StateService_WatchJobStates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchJobStatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StateServiceServer).WatchJobStates(m, &grpc.GenericServerStream[WatchJobStatesRequest, JobStateEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_WatchJobStatesServer = grpc.ServerStreamingServer[JobStateEvent]
+
+// StateService_ServiceDesc is the grpc.ServiceDesc for StateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.state.StateService",
+	HandlerType: (*StateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListJobStates",
+			Handler:    _StateService_ListJobStates_Handler,
+		},
+		{
+			MethodName: "GetJobState",
+			Handler:    _StateService_GetJobState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJobStates",
+			Handler:       _StateService_WatchJobStates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "state.proto",
+}
@@ -0,0 +1,481 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: uploads.proto
+
+package uploads
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FileSignatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Upload path, as in the workflow's job uploads
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileSignatureRequest) Reset() {
+	*x = FileSignatureRequest{}
+	mi := &file_uploads_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileSignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileSignatureRequest) ProtoMessage() {}
+
+func (x *FileSignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_uploads_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileSignatureRequest.ProtoReflect.Descriptor instead.
+func (*FileSignatureRequest) Descriptor() ([]byte, []int) {
+	return file_uploads_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FileSignatureRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// BlockChecksum identifies one block of the cached copy
+type BlockChecksum struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Weak          uint32                 `protobuf:"varint,1,opt,name=weak,proto3" json:"weak,omitempty"`    // Rolling checksum, cheap to slide over the client's file
+	Strong        []byte                 `protobuf:"bytes,2,opt,name=strong,proto3" json:"strong,omitempty"` // First 16 bytes of the block's SHA-256
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockChecksum) Reset() {
+	*x = BlockChecksum{}
+	mi := &file_uploads_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockChecksum) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockChecksum) ProtoMessage() {}
+
+func (x *BlockChecksum) ProtoReflect() protoreflect.Message {
+	mi := &file_uploads_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockChecksum.ProtoReflect.Descriptor instead.
+func (*BlockChecksum) Descriptor() ([]byte, []int) {
+	return file_uploads_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BlockChecksum) GetWeak() uint32 {
+	if x != nil {
+		return x.Weak
+	}
+	return 0
+}
+
+func (x *BlockChecksum) GetStrong() []byte {
+	if x != nil {
+		return x.Strong
+	}
+	return nil
+}
+
+// FileSignature describes the cached copy; without one basis is empty and
+// blocks has no entries, so the whole file is sent as data
+type FileSignature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Basis         string                 `protobuf:"bytes,1,opt,name=basis,proto3" json:"basis,omitempty"`                           // Hex SHA-256 of the cached copy
+	BlockSize     int32                  `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"` // Bytes per block, the last block may be shorter
+	Blocks        []*BlockChecksum       `protobuf:"bytes,3,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"` // Bytes of the cached copy
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileSignature) Reset() {
+	*x = FileSignature{}
+	mi := &file_uploads_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileSignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileSignature) ProtoMessage() {}
+
+func (x *FileSignature) ProtoReflect() protoreflect.Message {
+	mi := &file_uploads_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileSignature.ProtoReflect.Descriptor instead.
+func (*FileSignature) Descriptor() ([]byte, []int) {
+	return file_uploads_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileSignature) GetBasis() string {
+	if x != nil {
+		return x.Basis
+	}
+	return ""
+}
+
+func (x *FileSignature) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *FileSignature) GetBlocks() []*BlockChecksum {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *FileSignature) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// DeltaOp either copies a run of blocks of the cached copy or adds data
+type DeltaOp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Block         int64                  `protobuf:"varint,1,opt,name=block,proto3" json:"block,omitempty"` // First block to copy
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // Blocks to copy, 0 for a data op
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`    // Literal bytes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeltaOp) Reset() {
+	*x = DeltaOp{}
+	mi := &file_uploads_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeltaOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeltaOp) ProtoMessage() {}
+
+func (x *DeltaOp) ProtoReflect() protoreflect.Message {
+	mi := &file_uploads_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeltaOp.ProtoReflect.Descriptor instead.
+func (*DeltaOp) Descriptor() ([]byte, []int) {
+	return file_uploads_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeltaOp) GetBlock() int64 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type FileDelta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Basis         string                 `protobuf:"bytes,2,opt,name=basis,proto3" json:"basis,omitempty"` // Basis of the signature the delta was computed against
+	BlockSize     int32                  `protobuf:"varint,3,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"` // Hex SHA-256 of the rebuilt file, checked by the node
+	Ops           []*DeltaOp             `protobuf:"bytes,5,rep,name=ops,proto3" json:"ops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileDelta) Reset() {
+	*x = FileDelta{}
+	mi := &file_uploads_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDelta) ProtoMessage() {}
+
+func (x *FileDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_uploads_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDelta.ProtoReflect.Descriptor instead.
+func (*FileDelta) Descriptor() ([]byte, []int) {
+	return file_uploads_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FileDelta) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileDelta) GetBasis() string {
+	if x != nil {
+		return x.Basis
+	}
+	return ""
+}
+
+func (x *FileDelta) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *FileDelta) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *FileDelta) GetOps() []*DeltaOp {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
+type SyncFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ref           string                 `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`                                     // Content hash to name the file by in joblet-upload-refs-bin
+	CopiedBytes   int64                  `protobuf:"varint,2,opt,name=copied_bytes,json=copiedBytes,proto3" json:"copied_bytes,omitempty"` // Bytes reused from the cached copy
+	DataBytes     int64                  `protobuf:"varint,3,opt,name=data_bytes,json=dataBytes,proto3" json:"data_bytes,omitempty"`       // Bytes sent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncFileResponse) Reset() {
+	*x = SyncFileResponse{}
+	mi := &file_uploads_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncFileResponse) ProtoMessage() {}
+
+func (x *SyncFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_uploads_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncFileResponse.ProtoReflect.Descriptor instead.
+func (*SyncFileResponse) Descriptor() ([]byte, []int) {
+	return file_uploads_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SyncFileResponse) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *SyncFileResponse) GetCopiedBytes() int64 {
+	if x != nil {
+		return x.CopiedBytes
+	}
+	return 0
+}
+
+func (x *SyncFileResponse) GetDataBytes() int64 {
+	if x != nil {
+		return x.DataBytes
+	}
+	return 0
+}
+
+var File_uploads_proto protoreflect.FileDescriptor
+
+const file_uploads_proto_rawDesc = "" +
+	"\n" +
+	"\ruploads.proto\x12\x0ejoblet.uploads\"*\n" +
+	"\x14FileSignatureRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\";\n" +
+	"\rBlockChecksum\x12\x12\n" +
+	"\x04weak\x18\x01 \x01(\rR\x04weak\x12\x16\n" +
+	"\x06strong\x18\x02 \x01(\fR\x06strong\"\x8f\x01\n" +
+	"\rFileSignature\x12\x14\n" +
+	"\x05basis\x18\x01 \x01(\tR\x05basis\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x02 \x01(\x05R\tblockSize\x125\n" +
+	"\x06blocks\x18\x03 \x03(\v2\x1d.joblet.uploads.BlockChecksumR\x06blocks\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\"I\n" +
+	"\aDeltaOp\x12\x14\n" +
+	"\x05block\x18\x01 \x01(\x03R\x05block\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"\x97\x01\n" +
+	"\tFileDelta\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05basis\x18\x02 \x01(\tR\x05basis\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x03 \x01(\x05R\tblockSize\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\x12)\n" +
+	"\x03ops\x18\x05 \x03(\v2\x17.joblet.uploads.DeltaOpR\x03ops\"f\n" +
+	"\x10SyncFileResponse\x12\x10\n" +
+	"\x03ref\x18\x01 \x01(\tR\x03ref\x12!\n" +
+	"\fcopied_bytes\x18\x02 \x01(\x03R\vcopiedBytes\x12\x1d\n" +
+	"\n" +
+	"data_bytes\x18\x03 \x01(\x03R\tdataBytes2\xb5\x01\n" +
+	"\x11UploadSyncService\x12W\n" +
+	"\x10GetFileSignature\x12$.joblet.uploads.FileSignatureRequest\x1a\x1d.joblet.uploads.FileSignature\x12G\n" +
+	"\bSyncFile\x12\x19.joblet.uploads.FileDelta\x1a .joblet.uploads.SyncFileResponseB8Z6github.com/ehsaniara/joblet/internal/proto/gen/uploadsb\x06proto3"
+
+var (
+	file_uploads_proto_rawDescOnce sync.Once
+	file_uploads_proto_rawDescData []byte
+)
+
+func file_uploads_proto_rawDescGZIP() []byte {
+	file_uploads_proto_rawDescOnce.Do(func() {
+		file_uploads_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_uploads_proto_rawDesc), len(file_uploads_proto_rawDesc)))
+	})
+	return file_uploads_proto_rawDescData
+}
+
+var file_uploads_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_uploads_proto_goTypes = []any{
+	(*FileSignatureRequest)(nil), // 0: joblet.uploads.FileSignatureRequest
+	(*BlockChecksum)(nil),        // 1: joblet.uploads.BlockChecksum
+	(*FileSignature)(nil),        // 2: joblet.uploads.FileSignature
+	(*DeltaOp)(nil),              // 3: joblet.uploads.DeltaOp
+	(*FileDelta)(nil),            // 4: joblet.uploads.FileDelta
+	(*SyncFileResponse)(nil),     // 5: joblet.uploads.SyncFileResponse
+}
+var file_uploads_proto_depIdxs = []int32{
+	1, // 0: joblet.uploads.FileSignature.blocks:type_name -> joblet.uploads.BlockChecksum
+	3, // 1: joblet.uploads.FileDelta.ops:type_name -> joblet.uploads.DeltaOp
+	0, // 2: joblet.uploads.UploadSyncService.GetFileSignature:input_type -> joblet.uploads.FileSignatureRequest
+	4, // 3: joblet.uploads.UploadSyncService.SyncFile:input_type -> joblet.uploads.FileDelta
+	2, // 4: joblet.uploads.UploadSyncService.GetFileSignature:output_type -> joblet.uploads.FileSignature
+	5, // 5: joblet.uploads.UploadSyncService.SyncFile:output_type -> joblet.uploads.SyncFileResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_uploads_proto_init() }
+func file_uploads_proto_init() {
+	if File_uploads_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_uploads_proto_rawDesc), len(file_uploads_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_uploads_proto_goTypes,
+		DependencyIndexes: file_uploads_proto_depIdxs,
+		MessageInfos:      file_uploads_proto_msgTypes,
+	}.Build()
+	File_uploads_proto = out.File
+	file_uploads_proto_goTypes = nil
+	file_uploads_proto_depIdxs = nil
+}
@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: uploads.proto
+
+package uploads
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UploadSyncService_GetFileSignature_FullMethodName = "/joblet.uploads.UploadSyncService/GetFileSignature"
+	UploadSyncService_SyncFile_FullMethodName         = "/joblet.uploads.UploadSyncService/SyncFile"
+)
+
+// UploadSyncServiceClient is the client API for UploadSyncService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UploadSyncService sends workflow files as deltas against the copies the
+// node cached from earlier runs, rsync style: the client asks for the block
+// checksums of the cached copy, finds the blocks its file still has and sends
+// only the rest. RunWorkflow then names the synced files by content hash in
+// the joblet-upload-refs-bin header instead of carrying their content.
+//
+// Cached copies belong to the client certificate that synced them.
+type UploadSyncServiceClient interface {
+	// Block checksums of the node's cached copy of a workflow file
+	GetFileSignature(ctx context.Context, in *FileSignatureRequest, opts ...grpc.CallOption) (*FileSignature, error)
+	// Rebuild a file from a delta against the cached copy and cache the result
+	SyncFile(ctx context.Context, in *FileDelta, opts ...grpc.CallOption) (*SyncFileResponse, error)
+}
+
+type uploadSyncServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUploadSyncServiceClient(cc grpc.ClientConnInterface) UploadSyncServiceClient {
+	return &uploadSyncServiceClient{cc}
+}
+
+func (c *uploadSyncServiceClient) GetFileSignature(ctx context.Context, in *FileSignatureRequest, opts ...grpc.CallOption) (*FileSignature, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileSignature)
+	err := c.cc.Invoke(ctx, UploadSyncService_GetFileSignature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uploadSyncServiceClient) SyncFile(ctx context.Context, in *FileDelta, opts ...grpc.CallOption) (*SyncFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncFileResponse)
+	err := c.cc.Invoke(ctx, UploadSyncService_SyncFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UploadSyncServiceServer is the server API for UploadSyncService service.
+// All implementations must embed UnimplementedUploadSyncServiceServer
+// for forward compatibility.
+//
+// UploadSyncService sends workflow files as deltas against the copies the
+// node cached from earlier runs, rsync style: the client asks for the block
+// checksums of the cached copy, finds the blocks its file still has and sends
+// only the rest. RunWorkflow then names the synced files by content hash in
+// the joblet-upload-refs-bin header instead of carrying their content.
+//
+// Cached copies belong to the client certificate that synced them.
+type UploadSyncServiceServer interface {
+	// Block checksums of the node's cached copy of a workflow file
+	GetFileSignature(context.Context, *FileSignatureRequest) (*FileSignature, error)
+	// Rebuild a file from a delta against the cached copy and cache the result
+	SyncFile(context.Context, *FileDelta) (*SyncFileResponse, error)
+	mustEmbedUnimplementedUploadSyncServiceServer()
+}
+
+// UnimplementedUploadSyncServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUploadSyncServiceServer struct{}
+
+func (UnimplementedUploadSyncServiceServer) GetFileSignature(context.Context, *FileSignatureRequest) (*FileSignature, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileSignature not implemented")
+}
+func (UnimplementedUploadSyncServiceServer) SyncFile(context.Context, *FileDelta) (*SyncFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncFile not implemented")
+}
+func (UnimplementedUploadSyncServiceServer) mustEmbedUnimplementedUploadSyncServiceServer() {}
+func (UnimplementedUploadSyncServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeUploadSyncServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UploadSyncServiceServer will
+// result in compilation errors.
+type UnsafeUploadSyncServiceServer interface {
+	mustEmbedUnimplementedUploadSyncServiceServer()
+}
+
+func RegisterUploadSyncServiceServer(s grpc.ServiceRegistrar, srv UploadSyncServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUploadSyncServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UploadSyncService_ServiceDesc, srv)
+}
+
+func _UploadSyncService_GetFileSignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileSignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UploadSyncServiceServer).GetFileSignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UploadSyncService_GetFileSignature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UploadSyncServiceServer).GetFileSignature(ctx, req.(*FileSignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UploadSyncService_SyncFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileDelta)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UploadSyncServiceServer).SyncFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UploadSyncService_SyncFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UploadSyncServiceServer).SyncFile(ctx, req.(*FileDelta))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UploadSyncService_ServiceDesc is the grpc.ServiceDesc for UploadSyncService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UploadSyncService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.uploads.UploadSyncService",
+	HandlerType: (*UploadSyncServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFileSignature",
+			Handler:    _UploadSyncService_GetFileSignature_Handler,
+		},
+		{
+			MethodName: "SyncFile",
+			Handler:    _UploadSyncService_SyncFile_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "uploads.proto",
+}
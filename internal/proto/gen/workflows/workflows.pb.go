@@ -0,0 +1,554 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: workflows.proto
+
+package workflows
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CancelWorkflowJobsRequest selects the sub-tree of a workflow to cancel
+type CancelWorkflowJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"` // Full or short workflow UUID
+	Job           string                 `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`                                       // Job name from the workflow YAML
+	Cascade       bool                   `protobuf:"varint,3,opt,name=cascade,proto3" json:"cascade,omitempty"`                              // Also cancel every job requiring it, directly or not
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelWorkflowJobsRequest) Reset() {
+	*x = CancelWorkflowJobsRequest{}
+	mi := &file_workflows_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelWorkflowJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelWorkflowJobsRequest) ProtoMessage() {}
+
+func (x *CancelWorkflowJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelWorkflowJobsRequest.ProtoReflect.Descriptor instead.
+func (*CancelWorkflowJobsRequest) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CancelWorkflowJobsRequest) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *CancelWorkflowJobsRequest) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *CancelWorkflowJobsRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+// WorkflowJobOutcome is what became of one selected job
+type WorkflowJobOutcome struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	JobUuid       string                 `protobuf:"bytes,2,opt,name=job_uuid,json=jobUuid,proto3" json:"job_uuid,omitempty"` // Empty for jobs that had not started
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                  // Status when the job was selected
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                    // Why the job could not be stopped
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowJobOutcome) Reset() {
+	*x = WorkflowJobOutcome{}
+	mi := &file_workflows_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowJobOutcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowJobOutcome) ProtoMessage() {}
+
+func (x *WorkflowJobOutcome) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowJobOutcome.ProtoReflect.Descriptor instead.
+func (*WorkflowJobOutcome) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WorkflowJobOutcome) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowJobOutcome) GetJobUuid() string {
+	if x != nil {
+		return x.JobUuid
+	}
+	return ""
+}
+
+func (x *WorkflowJobOutcome) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowJobOutcome) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// CancelWorkflowJobsResponse reports the jobs of the sub-tree, in workflow
+// order
+type CancelWorkflowJobsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid   string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"`       // Full workflow UUID
+	Canceled       []*WorkflowJobOutcome  `protobuf:"bytes,2,rep,name=canceled,proto3" json:"canceled,omitempty"`                                   // Had not started and never will
+	Stopped        []*WorkflowJobOutcome  `protobuf:"bytes,3,rep,name=stopped,proto3" json:"stopped,omitempty"`                                     // Running or scheduled jobs stopped
+	Failed         []*WorkflowJobOutcome  `protobuf:"bytes,4,rep,name=failed,proto3" json:"failed,omitempty"`                                       // Running or scheduled jobs that could not be stopped
+	Skipped        []*WorkflowJobOutcome  `protobuf:"bytes,5,rep,name=skipped,proto3" json:"skipped,omitempty"`                                     // Had already ended
+	WorkflowStatus string                 `protobuf:"bytes,6,opt,name=workflow_status,json=workflowStatus,proto3" json:"workflow_status,omitempty"` // Workflow status afterwards
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CancelWorkflowJobsResponse) Reset() {
+	*x = CancelWorkflowJobsResponse{}
+	mi := &file_workflows_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelWorkflowJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelWorkflowJobsResponse) ProtoMessage() {}
+
+func (x *CancelWorkflowJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelWorkflowJobsResponse.ProtoReflect.Descriptor instead.
+func (*CancelWorkflowJobsResponse) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CancelWorkflowJobsResponse) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+func (x *CancelWorkflowJobsResponse) GetCanceled() []*WorkflowJobOutcome {
+	if x != nil {
+		return x.Canceled
+	}
+	return nil
+}
+
+func (x *CancelWorkflowJobsResponse) GetStopped() []*WorkflowJobOutcome {
+	if x != nil {
+		return x.Stopped
+	}
+	return nil
+}
+
+func (x *CancelWorkflowJobsResponse) GetFailed() []*WorkflowJobOutcome {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+func (x *CancelWorkflowJobsResponse) GetSkipped() []*WorkflowJobOutcome {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+func (x *CancelWorkflowJobsResponse) GetWorkflowStatus() string {
+	if x != nil {
+		return x.WorkflowStatus
+	}
+	return ""
+}
+
+// ValidateWorkflowRequest carries a workflow as the client would run it
+type ValidateWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YamlContent   string                 `protobuf:"bytes,1,opt,name=yaml_content,json=yamlContent,proto3" json:"yaml_content,omitempty"`
+	Files         []string               `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"` // Workflow files the client would send, relative to the YAML
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateWorkflowRequest) Reset() {
+	*x = ValidateWorkflowRequest{}
+	mi := &file_workflows_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateWorkflowRequest) ProtoMessage() {}
+
+func (x *ValidateWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*ValidateWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateWorkflowRequest) GetYamlContent() string {
+	if x != nil {
+		return x.YamlContent
+	}
+	return ""
+}
+
+func (x *ValidateWorkflowRequest) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// WorkflowProblem is one thing wrong with a workflow
+type WorkflowProblem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // Machine-readable, e.g. "missing-volume"
+	Job           string                 `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`   // Workflow job the problem is about, empty otherwise
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowProblem) Reset() {
+	*x = WorkflowProblem{}
+	mi := &file_workflows_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowProblem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowProblem) ProtoMessage() {}
+
+func (x *WorkflowProblem) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowProblem.ProtoReflect.Descriptor instead.
+func (*WorkflowProblem) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WorkflowProblem) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *WorkflowProblem) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *WorkflowProblem) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// WorkflowCheckResult is the outcome of one check, e.g. "volumes"; a
+// workflow that does not parse ends the stream with the "syntax" check
+type WorkflowCheckResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Check         string                 `protobuf:"bytes,1,opt,name=check,proto3" json:"check,omitempty"`
+	Problems      []*WorkflowProblem     `protobuf:"bytes,2,rep,name=problems,proto3" json:"problems,omitempty"` // Empty when the check passed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowCheckResult) Reset() {
+	*x = WorkflowCheckResult{}
+	mi := &file_workflows_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowCheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowCheckResult) ProtoMessage() {}
+
+func (x *WorkflowCheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowCheckResult.ProtoReflect.Descriptor instead.
+func (*WorkflowCheckResult) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WorkflowCheckResult) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *WorkflowCheckResult) GetProblems() []*WorkflowProblem {
+	if x != nil {
+		return x.Problems
+	}
+	return nil
+}
+
+// CancelWorkflowRequest names the workflow to cancel
+type CancelWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowUuid  string                 `protobuf:"bytes,1,opt,name=workflow_uuid,json=workflowUuid,proto3" json:"workflow_uuid,omitempty"` // Full or short workflow UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelWorkflowRequest) Reset() {
+	*x = CancelWorkflowRequest{}
+	mi := &file_workflows_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelWorkflowRequest) ProtoMessage() {}
+
+func (x *CancelWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflows_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*CancelWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_workflows_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CancelWorkflowRequest) GetWorkflowUuid() string {
+	if x != nil {
+		return x.WorkflowUuid
+	}
+	return ""
+}
+
+var File_workflows_proto protoreflect.FileDescriptor
+
+const file_workflows_proto_rawDesc = "" +
+	"\n" +
+	"\x0fworkflows.proto\x12\x10joblet.workflows\"l\n" +
+	"\x19CancelWorkflowJobsRequest\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\x12\x10\n" +
+	"\x03job\x18\x02 \x01(\tR\x03job\x12\x18\n" +
+	"\acascade\x18\x03 \x01(\bR\acascade\"q\n" +
+	"\x12WorkflowJobOutcome\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bjob_uuid\x18\x02 \x01(\tR\ajobUuid\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xea\x02\n" +
+	"\x1aCancelWorkflowJobsResponse\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid\x12@\n" +
+	"\bcanceled\x18\x02 \x03(\v2$.joblet.workflows.WorkflowJobOutcomeR\bcanceled\x12>\n" +
+	"\astopped\x18\x03 \x03(\v2$.joblet.workflows.WorkflowJobOutcomeR\astopped\x12<\n" +
+	"\x06failed\x18\x04 \x03(\v2$.joblet.workflows.WorkflowJobOutcomeR\x06failed\x12>\n" +
+	"\askipped\x18\x05 \x03(\v2$.joblet.workflows.WorkflowJobOutcomeR\askipped\x12'\n" +
+	"\x0fworkflow_status\x18\x06 \x01(\tR\x0eworkflowStatus\"R\n" +
+	"\x17ValidateWorkflowRequest\x12!\n" +
+	"\fyaml_content\x18\x01 \x01(\tR\vyamlContent\x12\x14\n" +
+	"\x05files\x18\x02 \x03(\tR\x05files\"Q\n" +
+	"\x0fWorkflowProblem\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x10\n" +
+	"\x03job\x18\x02 \x01(\tR\x03job\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"j\n" +
+	"\x13WorkflowCheckResult\x12\x14\n" +
+	"\x05check\x18\x01 \x01(\tR\x05check\x12=\n" +
+	"\bproblems\x18\x02 \x03(\v2!.joblet.workflows.WorkflowProblemR\bproblems\"<\n" +
+	"\x15CancelWorkflowRequest\x12#\n" +
+	"\rworkflow_uuid\x18\x01 \x01(\tR\fworkflowUuid2\xda\x02\n" +
+	"\x16WorkflowControlService\x12o\n" +
+	"\x12CancelWorkflowJobs\x12+.joblet.workflows.CancelWorkflowJobsRequest\x1a,.joblet.workflows.CancelWorkflowJobsResponse\x12g\n" +
+	"\x0eCancelWorkflow\x12'.joblet.workflows.CancelWorkflowRequest\x1a,.joblet.workflows.CancelWorkflowJobsResponse\x12f\n" +
+	"\x10ValidateWorkflow\x12).joblet.workflows.ValidateWorkflowRequest\x1a%.joblet.workflows.WorkflowCheckResult0\x01B:Z8github.com/ehsaniara/joblet/internal/proto/gen/workflowsb\x06proto3"
+
+var (
+	file_workflows_proto_rawDescOnce sync.Once
+	file_workflows_proto_rawDescData []byte
+)
+
+func file_workflows_proto_rawDescGZIP() []byte {
+	file_workflows_proto_rawDescOnce.Do(func() {
+		file_workflows_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_workflows_proto_rawDesc), len(file_workflows_proto_rawDesc)))
+	})
+	return file_workflows_proto_rawDescData
+}
+
+var file_workflows_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_workflows_proto_goTypes = []any{
+	(*CancelWorkflowJobsRequest)(nil),  // 0: joblet.workflows.CancelWorkflowJobsRequest
+	(*WorkflowJobOutcome)(nil),         // 1: joblet.workflows.WorkflowJobOutcome
+	(*CancelWorkflowJobsResponse)(nil), // 2: joblet.workflows.CancelWorkflowJobsResponse
+	(*ValidateWorkflowRequest)(nil),    // 3: joblet.workflows.ValidateWorkflowRequest
+	(*WorkflowProblem)(nil),            // 4: joblet.workflows.WorkflowProblem
+	(*WorkflowCheckResult)(nil),        // 5: joblet.workflows.WorkflowCheckResult
+	(*CancelWorkflowRequest)(nil),      // 6: joblet.workflows.CancelWorkflowRequest
+}
+var file_workflows_proto_depIdxs = []int32{
+	1, // 0: joblet.workflows.CancelWorkflowJobsResponse.canceled:type_name -> joblet.workflows.WorkflowJobOutcome
+	1, // 1: joblet.workflows.CancelWorkflowJobsResponse.stopped:type_name -> joblet.workflows.WorkflowJobOutcome
+	1, // 2: joblet.workflows.CancelWorkflowJobsResponse.failed:type_name -> joblet.workflows.WorkflowJobOutcome
+	1, // 3: joblet.workflows.CancelWorkflowJobsResponse.skipped:type_name -> joblet.workflows.WorkflowJobOutcome
+	4, // 4: joblet.workflows.WorkflowCheckResult.problems:type_name -> joblet.workflows.WorkflowProblem
+	0, // 5: joblet.workflows.WorkflowControlService.CancelWorkflowJobs:input_type -> joblet.workflows.CancelWorkflowJobsRequest
+	6, // 6: joblet.workflows.WorkflowControlService.CancelWorkflow:input_type -> joblet.workflows.CancelWorkflowRequest
+	3, // 7: joblet.workflows.WorkflowControlService.ValidateWorkflow:input_type -> joblet.workflows.ValidateWorkflowRequest
+	2, // 8: joblet.workflows.WorkflowControlService.CancelWorkflowJobs:output_type -> joblet.workflows.CancelWorkflowJobsResponse
+	2, // 9: joblet.workflows.WorkflowControlService.CancelWorkflow:output_type -> joblet.workflows.CancelWorkflowJobsResponse
+	5, // 10: joblet.workflows.WorkflowControlService.ValidateWorkflow:output_type -> joblet.workflows.WorkflowCheckResult
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_workflows_proto_init() }
+func file_workflows_proto_init() {
+	if File_workflows_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_workflows_proto_rawDesc), len(file_workflows_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_workflows_proto_goTypes,
+		DependencyIndexes: file_workflows_proto_depIdxs,
+		MessageInfos:      file_workflows_proto_msgTypes,
+	}.Build()
+	File_workflows_proto = out.File
+	file_workflows_proto_goTypes = nil
+	file_workflows_proto_depIdxs = nil
+}
@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: workflows.proto
+
+package workflows
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WorkflowControlService_CancelWorkflowJobs_FullMethodName = "/joblet.workflows.WorkflowControlService/CancelWorkflowJobs"
+	WorkflowControlService_CancelWorkflow_FullMethodName     = "/joblet.workflows.WorkflowControlService/CancelWorkflow"
+	WorkflowControlService_ValidateWorkflow_FullMethodName   = "/joblet.workflows.WorkflowControlService/ValidateWorkflow"
+)
+
+// WorkflowControlServiceClient is the client API for WorkflowControlService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WorkflowControlService changes running workflows.
+//
+// 'rnx workflow cancel' uses it to cancel one job of a workflow, and with
+// --cascade everything downstream of it, while the unrelated branches keep
+// running. 'rnx workflow stop' uses it to cancel a whole workflow.
+// 'rnx workflow validate' uses it to list every problem of a workflow at once.
+type WorkflowControlServiceClient interface {
+	// Cancel a workflow job and optionally every job that depends on it
+	CancelWorkflowJobs(ctx context.Context, in *CancelWorkflowJobsRequest, opts ...grpc.CallOption) (*CancelWorkflowJobsResponse, error)
+	// Cancel a whole workflow: stop its running jobs, cancel the ones that
+	// have not started and stop starting new ones
+	CancelWorkflow(ctx context.Context, in *CancelWorkflowRequest, opts ...grpc.CallOption) (*CancelWorkflowJobsResponse, error)
+	// Run every server-side check on a workflow without running it, streaming
+	// the result of each check as it completes
+	ValidateWorkflow(ctx context.Context, in *ValidateWorkflowRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkflowCheckResult], error)
+}
+
+type workflowControlServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkflowControlServiceClient(cc grpc.ClientConnInterface) WorkflowControlServiceClient {
+	return &workflowControlServiceClient{cc}
+}
+
+func (c *workflowControlServiceClient) CancelWorkflowJobs(ctx context.Context, in *CancelWorkflowJobsRequest, opts ...grpc.CallOption) (*CancelWorkflowJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelWorkflowJobsResponse)
+	err := c.cc.Invoke(ctx, WorkflowControlService_CancelWorkflowJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowControlServiceClient) CancelWorkflow(ctx context.Context, in *CancelWorkflowRequest, opts ...grpc.CallOption) (*CancelWorkflowJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelWorkflowJobsResponse)
+	err := c.cc.Invoke(ctx, WorkflowControlService_CancelWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowControlServiceClient) ValidateWorkflow(ctx context.Context, in *ValidateWorkflowRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkflowCheckResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowControlService_ServiceDesc.Streams[0], WorkflowControlService_ValidateWorkflow_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ValidateWorkflowRequest, WorkflowCheckResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowControlService_ValidateWorkflowClient = grpc.ServerStreamingClient[WorkflowCheckResult]
+
+// WorkflowControlServiceServer is the server API for WorkflowControlService service.
+// All implementations must embed UnimplementedWorkflowControlServiceServer
+// for forward compatibility.
+//
+// WorkflowControlService changes running workflows.
+//
+// 'rnx workflow cancel' uses it to cancel one job of a workflow, and with
+// --cascade everything downstream of it, while the unrelated branches keep
+// running. 'rnx workflow stop' uses it to cancel a whole workflow.
+// 'rnx workflow validate' uses it to list every problem of a workflow at once.
+type WorkflowControlServiceServer interface {
+	// Cancel a workflow job and optionally every job that depends on it
+	CancelWorkflowJobs(context.Context, *CancelWorkflowJobsRequest) (*CancelWorkflowJobsResponse, error)
+	// Cancel a whole workflow: stop its running jobs, cancel the ones that
+	// have not started and stop starting new ones
+	CancelWorkflow(context.Context, *CancelWorkflowRequest) (*CancelWorkflowJobsResponse, error)
+	// Run every server-side check on a workflow without running it, streaming
+	// the result of each check as it completes
+	ValidateWorkflow(*ValidateWorkflowRequest, grpc.ServerStreamingServer[WorkflowCheckResult]) error
+	mustEmbedUnimplementedWorkflowControlServiceServer()
+}
+
+// UnimplementedWorkflowControlServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkflowControlServiceServer struct{}
+
+func (UnimplementedWorkflowControlServiceServer) CancelWorkflowJobs(context.Context, *CancelWorkflowJobsRequest) (*CancelWorkflowJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelWorkflowJobs not implemented")
+}
+func (UnimplementedWorkflowControlServiceServer) CancelWorkflow(context.Context, *CancelWorkflowRequest) (*CancelWorkflowJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelWorkflow not implemented")
+}
+func (UnimplementedWorkflowControlServiceServer) ValidateWorkflow(*ValidateWorkflowRequest, grpc.ServerStreamingServer[WorkflowCheckResult]) error {
+	return status.Errorf(codes.Unimplemented, "method ValidateWorkflow not implemented")
+}
+func (UnimplementedWorkflowControlServiceServer) mustEmbedUnimplementedWorkflowControlServiceServer() {
+}
+func (UnimplementedWorkflowControlServiceServer) testEmbeddedByValue() {}
+
+// UnsafeWorkflowControlServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkflowControlServiceServer will
+// result in compilation errors.
+type UnsafeWorkflowControlServiceServer interface {
+	mustEmbedUnimplementedWorkflowControlServiceServer()
+}
+
+func RegisterWorkflowControlServiceServer(s grpc.ServiceRegistrar, srv WorkflowControlServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWorkflowControlServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorkflowControlService_ServiceDesc, srv)
+}
+
+func _WorkflowControlService_CancelWorkflowJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelWorkflowJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowControlServiceServer).CancelWorkflowJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowControlService_CancelWorkflowJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowControlServiceServer).CancelWorkflowJobs(ctx, req.(*CancelWorkflowJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowControlService_CancelWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowControlServiceServer).CancelWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowControlService_CancelWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowControlServiceServer).CancelWorkflow(ctx, req.(*CancelWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowControlService_ValidateWorkflow_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ValidateWorkflowRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowControlServiceServer).ValidateWorkflow(m, &grpc.GenericServerStream[ValidateWorkflowRequest, WorkflowCheckResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowControlService_ValidateWorkflowServer = grpc.ServerStreamingServer[WorkflowCheckResult]
+
+// WorkflowControlService_ServiceDesc is the grpc.ServiceDesc for WorkflowControlService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkflowControlService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "joblet.workflows.WorkflowControlService",
+	HandlerType: (*WorkflowControlServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CancelWorkflowJobs",
+			Handler:    _WorkflowControlService_CancelWorkflowJobs_Handler,
+		},
+		{
+			MethodName: "CancelWorkflow",
+			Handler:    _WorkflowControlService_CancelWorkflow_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ValidateWorkflow",
+			Handler:       _WorkflowControlService_ValidateWorkflow_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "workflows.proto",
+}
@@ -3,6 +3,30 @@
 // This package defines internal protos that are NOT part of the public API:
 // - ipc.proto: Binary IPC between joblet-core and persist subprocess
 // - persist.proto: gRPC service for querying historical logs/metrics
+// - capacity.proto: gRPC service reporting schedulable node resources
+// - state.proto: read-only gRPC service over the state service's job states
+// - registry.proto: gRPC service storing workflows to start by name
+// - logs.proto: gRPC service downloading and browsing persisted job logs
+// - jobs.proto: gRPC service listing and stopping jobs in bulk
+// - nodes.proto: gRPC service registering live nodes and listing them
+// - uploads.proto: gRPC service syncing workflow files as deltas
+// - artifacts.proto: gRPC service downloading files from volumes in windows
+// - nodemetrics.proto: gRPC service reading the recorded host metrics
+// - jobfs.proto: gRPC service freezing and browsing failed job filesystems
+// - gitsource.proto: gRPC service running specs from Git repositories
+// - reports.proto: gRPC service rendering workflow run reports
+// - lint.proto: gRPC service linting job and workflow specs
+// - runtimes.proto: gRPC services describing installed runtimes and moving their stable and canary channels
+// - workflows.proto: gRPC service canceling running workflows or parts of them, and validating workflows
+// - events.proto: gRPC service streaming the status and progress of jobs
+// - jobenv.proto: gRPC service exporting a job's environment as a Dockerfile or OCI bundle
+// - jobnet.proto: gRPC service streaming the network counters of jobs
+// - ids.proto: gRPC service resolving short job and workflow UUIDs
+// - backup.proto: gRPC service exporting and importing job and workflow records
+//
+// Unless its own comment says otherwise, each gRPC service here is served on
+// the main joblet port next to the public services, with the same TLS and
+// role checks.
 //
 // To regenerate proto files:
 //
@@ -17,3 +41,83 @@ package proto
 // Generate Persist protobuf (used for persist gRPC service API)
 //go:generate mkdir -p gen/persist
 //go:generate protoc --proto_path=. --go_out=gen/persist --go-grpc_out=gen/persist --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative persist.proto
+
+// Generate Capacity protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/capacity
+//go:generate protoc --proto_path=. --go_out=gen/capacity --go-grpc_out=gen/capacity --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative capacity.proto
+
+// Generate State protobuf (read-only API served by the state subprocess)
+//go:generate mkdir -p gen/state
+//go:generate protoc --proto_path=. --go_out=gen/state --go-grpc_out=gen/state --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative state.proto
+
+// Generate Registry protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/registry
+//go:generate protoc --proto_path=. --go_out=gen/registry --go-grpc_out=gen/registry --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative registry.proto
+
+// Generate Logs protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/logs
+//go:generate protoc --proto_path=. --go_out=gen/logs --go-grpc_out=gen/logs --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative logs.proto
+
+// Generate Jobs protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/jobs
+//go:generate protoc --proto_path=. --go_out=gen/jobs --go-grpc_out=gen/jobs --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative jobs.proto
+
+// Generate Nodes protobuf (served by the state API and on the main joblet port)
+//go:generate mkdir -p gen/nodes
+//go:generate protoc --proto_path=. --go_out=gen/nodes --go-grpc_out=gen/nodes --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative nodes.proto
+
+// Generate Uploads protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/uploads
+//go:generate protoc --proto_path=. --go_out=gen/uploads --go-grpc_out=gen/uploads --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative uploads.proto
+
+// Generate Artifacts protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/artifacts
+//go:generate protoc --proto_path=. --go_out=gen/artifacts --go-grpc_out=gen/artifacts --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative artifacts.proto
+
+// Generate NodeMetrics protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/nodemetrics
+//go:generate protoc --proto_path=. --go_out=gen/nodemetrics --go-grpc_out=gen/nodemetrics --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative nodemetrics.proto
+
+// Generate JobFS protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/jobfs
+//go:generate protoc --proto_path=. --go_out=gen/jobfs --go-grpc_out=gen/jobfs --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative jobfs.proto
+
+// Generate GitSource protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/gitsource
+//go:generate protoc --proto_path=. --go_out=gen/gitsource --go-grpc_out=gen/gitsource --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative gitsource.proto
+
+// Generate Reports protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/reports
+//go:generate protoc --proto_path=. --go_out=gen/reports --go-grpc_out=gen/reports --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative reports.proto
+
+// Generate Lint protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/lint
+//go:generate protoc --proto_path=. --go_out=gen/lint --go-grpc_out=gen/lint --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative lint.proto
+
+// Generate Runtimes protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/runtimes
+//go:generate protoc --proto_path=. --go_out=gen/runtimes --go-grpc_out=gen/runtimes --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative runtimes.proto
+
+// Generate Workflows protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/workflows
+//go:generate protoc --proto_path=. --go_out=gen/workflows --go-grpc_out=gen/workflows --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative workflows.proto
+
+// Generate Events protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/events
+//go:generate protoc --proto_path=. --go_out=gen/events --go-grpc_out=gen/events --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative events.proto
+
+// Generate JobEnv protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/jobenv
+//go:generate protoc --proto_path=. --go_out=gen/jobenv --go-grpc_out=gen/jobenv --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative jobenv.proto
+
+// Generate JobNet protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/jobnet
+//go:generate protoc --proto_path=. --go_out=gen/jobnet --go-grpc_out=gen/jobnet --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative jobnet.proto
+
+// Generate Ids protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/ids
+//go:generate protoc --proto_path=. --go_out=gen/ids --go-grpc_out=gen/ids --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative ids.proto
+
+// Generate Backup protobuf (served on the main joblet port for rnx)
+//go:generate mkdir -p gen/backup
+//go:generate protoc --proto_path=. --go_out=gen/backup --go-grpc_out=gen/backup --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative backup.proto
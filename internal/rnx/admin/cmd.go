@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAdminCmd creates the admin command
+func NewAdminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Administer the node (admin certificate required)",
+		Long: `Administer the node. These commands need an admin client certificate.
+
+Examples:
+  rnx admin export backup.json          # Dump every job and workflow record
+  rnx admin import backup.json          # Load the records into another node`,
+	}
+
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newImportCmd())
+
+	return cmd
+}
+
+func newExportCmd() *cobra.Command {
+	var opts jobs.BackupOptions
+
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export the node's job and workflow records as protobuf JSON",
+		Long: `Export the records of the node's jobs and workflows to a file, or to stdout
+with "-", for backup or to move the node to new hardware.
+
+The dump holds each job's spec, status history, exit code, events, labels and
+annotations, and each workflow with its YAML and the outcome of its jobs.
+Secret values are masked and the contents of uploaded files are left out.
+Logs and metrics are not included: they stay in the persist service.
+
+Examples:
+  rnx admin export backup.json
+  rnx admin export --ended-only --since 2026-01-01T00:00:00Z backup.json
+  rnx admin export - | gzip > backup.json.gz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.ExportBackup(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Only records created from this time on (RFC 3339)")
+	cmd.Flags().BoolVar(&opts.EndedOnly, "ended-only", false, "Leave out jobs and workflows that have not ended")
+
+	return cmd
+}
+
+func newImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import job and workflow records exported with 'rnx admin export'",
+		Long: `Import the records of a dump written by 'rnx admin export', from a file or from
+stdin with "-". Jobs are imported first, then the workflows that ran them.
+
+Records are history: only ended jobs and workflows are imported, and nothing
+is run again. Jobs and workflows the node already has are skipped, so an
+import can be repeated safely.
+
+Examples:
+  rnx --node new-server admin import backup.json
+  gunzip -c backup.json.gz | rnx admin import -`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.ImportBackup(args[0])
+		},
+	}
+}
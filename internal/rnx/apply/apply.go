@@ -0,0 +1,182 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+// Client is the part of the joblet API used to read and change resources.
+// *client.JobClient implements it.
+type Client interface {
+	ListVolumes(ctx context.Context) (*pb.Volumes, error)
+	CreateVolume(ctx context.Context, req *pb.CreateVolumeReq) (*pb.CreateVolumeRes, error)
+	RemoveVolume(ctx context.Context, req *pb.RemoveVolumeReq) (*pb.RemoveVolumeRes, error)
+	ListNetworks(ctx context.Context) (*pb.Networks, error)
+	CreateNetwork(ctx context.Context, req *pb.CreateNetworkReq) (*pb.CreateNetworkRes, error)
+	RemoveNetwork(ctx context.Context, req *pb.RemoveNetworkReq) (*pb.RemoveNetworkRes, error)
+	ListRuntimes(ctx context.Context) (*pb.RuntimesRes, error)
+	StreamingInstallRuntimeFromGithub(ctx context.Context, req *pb.InstallRuntimeRequest) (pb.RuntimeService_StreamingInstallRuntimeFromGithubClient, error)
+	RemoveRuntime(ctx context.Context, req *pb.RuntimeRemoveReq) (*pb.RuntimeRemoveRes, error)
+	ListJobs(ctx context.Context) (*pb.Jobs, error)
+	RunJob(ctx context.Context, req *pb.RunJobRequest) (*pb.RunJobResponse, error)
+	StopJob(ctx context.Context, id string) (*pb.StopJobRes, error)
+}
+
+// FetchState reads the current resources of the node.
+func FetchState(ctx context.Context, c Client) (*State, error) {
+	volumes, err := c.ListVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	networks, err := c.ListNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+	runtimes, err := c.ListRuntimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runtimes: %w", err)
+	}
+	jobs, err := c.ListJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return &State{
+		Volumes:  volumes.Volumes,
+		Networks: networks.Networks,
+		Runtimes: runtimes.Runtimes,
+		Jobs:     jobs.Jobs,
+	}, nil
+}
+
+// Execute applies the unblocked changes in order and stops at the first
+// failure. Progress is written to out. It returns the number of changes
+// applied.
+func Execute(ctx context.Context, c Client, changes []Change, out io.Writer) (int, error) {
+	applied := 0
+	for _, change := range changes {
+		if change.Blocked != "" {
+			continue
+		}
+		if err := execute(ctx, c, change, out); err != nil {
+			return applied, fmt.Errorf("%s %s %s: %w", change.Action, change.Kind, change.Name, err)
+		}
+		fmt.Fprintf(out, "%s %s %s: done\n", actionSymbols[change.Action], change.Kind, change.Name)
+		applied++
+	}
+	return applied, nil
+}
+
+func execute(ctx context.Context, c Client, change Change, out io.Writer) error {
+	for _, uuid := range change.staleJobs {
+		if _, err := c.StopJob(ctx, uuid); err != nil {
+			return fmt.Errorf("failed to cancel scheduled run %s: %w", uuid, err)
+		}
+	}
+
+	switch change.Kind {
+	case KindVolume:
+		if change.Action != ActionCreate {
+			if _, err := c.RemoveVolume(ctx, &pb.RemoveVolumeReq{Name: change.Name}); err != nil {
+				return err
+			}
+		}
+		if change.Action != ActionDelete {
+			_, err := c.CreateVolume(ctx, &pb.CreateVolumeReq{Name: change.volume.Name, Size: change.volume.Size, Type: change.volume.Type})
+			return err
+		}
+
+	case KindNetwork:
+		if change.Action != ActionCreate {
+			if _, err := c.RemoveNetwork(ctx, &pb.RemoveNetworkReq{Name: change.Name}); err != nil {
+				return err
+			}
+		}
+		if change.Action != ActionDelete {
+			_, err := c.CreateNetwork(ctx, &pb.CreateNetworkReq{Name: change.network.Name, Cidr: change.network.CIDR})
+			return err
+		}
+
+	case KindRuntime:
+		if change.Action == ActionDelete {
+			resp, err := c.RemoveRuntime(ctx, &pb.RuntimeRemoveReq{Runtime: change.Name})
+			if err != nil {
+				return err
+			}
+			if !resp.Success {
+				return fmt.Errorf("%s", resp.Message)
+			}
+			return nil
+		}
+		return installRuntime(ctx, c, change.runtime, change.Action == ActionReplace, out)
+
+	case KindRecurringJob:
+		if change.job != nil {
+			_, err := c.RunJob(ctx, scheduledRun(change.job, change.runAt))
+			return err
+		}
+	}
+	return nil
+}
+
+func installRuntime(ctx context.Context, c Client, rt *Runtime, force bool, out io.Writer) error {
+	stream, err := c.StreamingInstallRuntimeFromGithub(ctx, &pb.InstallRuntimeRequest{
+		RuntimeSpec:    rt.Name,
+		Repository:     rt.Repository,
+		Branch:         rt.Branch,
+		Path:           rt.Path,
+		RegistryUrl:    rt.Registry,
+		ForceReinstall: force,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case chunk.GetProgress() != nil:
+			fmt.Fprintf(out, "  %s\n", chunk.GetProgress().Message)
+		case chunk.GetResult() != nil:
+			if result := chunk.GetResult(); !result.Success {
+				return fmt.Errorf("installation failed: %s", result.Message)
+			}
+			return nil
+		}
+	}
+}
+
+// scheduledRun builds the request for the next run of a recurring job.
+func scheduledRun(job *RecurringJob, at time.Time) *pb.RunJobRequest {
+	env := make(map[string]string, len(job.Environment)+2)
+	for k, v := range job.Environment {
+		env[k] = v
+	}
+	env[LabelRecurring] = job.Name
+	env[LabelRecurringHash] = job.Hash()
+
+	return &pb.RunJobRequest{
+		Name:        job.Name,
+		Command:     job.Command,
+		Args:        job.Args,
+		Runtime:     job.Runtime,
+		Network:     job.Network,
+		Volumes:     job.Volumes,
+		Environment: env,
+		MaxCpu:      int32(job.Resources.MaxCPU),
+		MaxMemory:   int32(job.Resources.MaxMemory),
+		MaxIobps:    int32(job.Resources.MaxIOBPS),
+		CpuCores:    job.Resources.CPUCores,
+		GpuCount:    int32(job.Resources.GPUCount),
+		GpuMemoryMb: int32(job.Resources.GPUMemoryMB),
+		Schedule:    at.Format(time.RFC3339),
+	}
+}
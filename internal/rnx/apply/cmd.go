@@ -0,0 +1,148 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NewApplyCmd creates the apply command
+func NewApplyCmd() *cobra.Command {
+	var (
+		file     string
+		dryRun   bool
+		opts     Options
+		watch    bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <resources.yaml>",
+		Short: "Reconcile volumes, networks, runtimes and recurring jobs with a file",
+		Long: `Make the node match a declarative resources file.
+
+The file lists volumes, networks, runtimes and recurring jobs. apply compares
+it with what the node has, prints the plan and applies it. Running it again
+with an unchanged file is a no-op, so it can be run from CI on every change.
+
+  volumes:
+    - name: datasets
+      size: 10GB
+      type: filesystem          # or memory
+  networks:
+    - name: backend
+      cidr: 10.20.0.0/24
+  runtimes:
+    - name: python-3.11-ml      # from the default registry
+  recurring_jobs:
+    - name: nightly-report
+      schedule: "0 2 * * *"     # cron, @daily, @hourly, @every 30m
+      command: python3
+      args: [report.py]
+      runtime: python-3.11-ml
+      volumes: [datasets]
+      resources:
+        max_memory: 1024
+
+Plan symbols: + create, ~ replace, - delete.
+
+Existing resources are never removed unless --prune is given, and volumes or
+networks whose size, type or CIDR changed are only recreated with --replace
+(recreating a volume deletes its data).
+
+The server schedules single runs, so apply keeps the next run of each
+recurring job scheduled. Keep 'rnx apply --watch' running, or run apply from a
+timer, so that a new run is scheduled after each one starts.
+
+Examples:
+  rnx apply -f resources.yaml --dry-run    # Show the plan only
+  rnx apply -f resources.yaml              # Apply it
+  rnx apply -f resources.yaml --prune      # Also delete undeclared resources
+  rnx apply -f resources.yaml --watch      # Reconcile every minute`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			desired, err := Load(file)
+			if err != nil {
+				return err
+			}
+
+			jobClient, err := common.NewJobClient()
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			for {
+				if err := reconcile(ctx, jobClient, desired, opts, dryRun); err != nil {
+					if !watch {
+						return err
+					}
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				}
+				if !watch {
+					return nil
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "Resources file to apply (required)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the plan without changing anything")
+	cmd.Flags().BoolVar(&opts.Prune, "prune", false, "Delete volumes, networks, runtimes and recurring jobs that are not in the file")
+	cmd.Flags().BoolVar(&opts.Replace, "replace", false, "Recreate volumes and networks whose settings changed")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Keep reconciling every --interval")
+	cmd.Flags().DurationVar(&interval, "interval", time.Minute, "Time between reconciliations with --watch")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+func reconcile(ctx context.Context, c Client, desired *Resources, opts Options, dryRun bool) error {
+	readCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	state, err := FetchState(readCtx, c)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	changes, err := Plan(desired, state, time.Now(), opts)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("No changes, node matches the resources file")
+		return nil
+	}
+
+	blocked := 0
+	for _, change := range changes {
+		fmt.Println(change)
+		if change.Blocked != "" {
+			blocked++
+		}
+	}
+	if dryRun {
+		fmt.Printf("\nPlan: %d change(s), %d skipped. Dry run, nothing applied.\n", len(changes)-blocked, blocked)
+		return nil
+	}
+
+	fmt.Println()
+	applied, err := Execute(ctx, c, changes, os.Stdout)
+	fmt.Printf("\nApplied %d change(s), %d skipped\n", applied, blocked)
+	return err
+}
@@ -0,0 +1,285 @@
+package apply
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/cron"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+)
+
+// Resource kinds as shown in plans.
+const (
+	KindVolume       = "volume"
+	KindNetwork      = "network"
+	KindRuntime      = "runtime"
+	KindRecurringJob = "recurring_job"
+)
+
+// Environment labels on the scheduled runs of recurring jobs. The server has
+// no notion of recurring jobs, so apply keeps the next run of each one
+// scheduled and finds it again through these labels.
+const (
+	LabelRecurring     = "JOBLET_RECURRING"
+	LabelRecurringHash = "JOBLET_RECURRING_HASH"
+)
+
+// builtinNetworks are provided by every node and never created or pruned.
+var builtinNetworks = map[string]bool{"none": true, "isolated": true, "bridge": true}
+
+// Action is what a change does to a resource.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionReplace Action = "replace"
+	ActionDelete  Action = "delete"
+)
+
+var actionSymbols = map[Action]string{ActionCreate: "+", ActionReplace: "~", ActionDelete: "-"}
+
+// Change is one step of a plan.
+type Change struct {
+	Action Action
+	Kind   string
+	Name   string
+	Detail string
+	// Blocked explains why the change will not be applied with the current
+	// options. Blocked changes are shown but skipped.
+	Blocked string
+
+	volume  *Volume
+	network *Network
+	runtime *Runtime
+	job     *RecurringJob
+	runAt   time.Time
+	// staleJobs are scheduled runs to cancel before the change is applied
+	staleJobs []string
+}
+
+func (c Change) String() string {
+	line := fmt.Sprintf("%s %s %s", actionSymbols[c.Action], c.Kind, c.Name)
+	if c.Detail != "" {
+		line += " (" + c.Detail + ")"
+	}
+	if c.Blocked != "" {
+		line += " [skipped: " + c.Blocked + "]"
+	}
+	return line
+}
+
+// State is what a node currently has.
+type State struct {
+	Volumes  []*pb.Volume
+	Networks []*pb.Network
+	Runtimes []*pb.RuntimeInfo
+	Jobs     []*pb.Job
+}
+
+// Options control which kinds of changes a plan may apply.
+type Options struct {
+	// Prune deletes resources that exist on the node but are not declared.
+	Prune bool
+	// Replace allows recreating volumes and networks whose settings changed,
+	// which removes the data in a volume.
+	Replace bool
+}
+
+// Plan compares desired with current and returns the changes, in the order
+// they must be applied: volumes, networks and runtimes before the jobs that
+// use them, deletions last.
+func Plan(desired *Resources, current *State, now time.Time, opts Options) ([]Change, error) {
+	var changes, deletions []Change
+
+	volumes := make(map[string]*pb.Volume, len(current.Volumes))
+	for _, v := range current.Volumes {
+		volumes[v.Name] = v
+	}
+	declared := make(map[string]bool)
+	for i := range desired.Volumes {
+		want := &desired.Volumes[i]
+		declared[want.Name] = true
+		have, ok := volumes[want.Name]
+		if !ok {
+			changes = append(changes, Change{Action: ActionCreate, Kind: KindVolume, Name: want.Name,
+				Detail: want.Size + " " + want.Type, volume: want})
+			continue
+		}
+		if diff := volumeDiff(want, have); diff != "" {
+			change := Change{Action: ActionReplace, Kind: KindVolume, Name: want.Name, Detail: diff, volume: want}
+			if !opts.Replace {
+				change.Blocked = "replacing a volume deletes its data, use --replace"
+			} else if have.JobCount > 0 {
+				change.Blocked = fmt.Sprintf("in use by %d job(s)", have.JobCount)
+			}
+			changes = append(changes, change)
+		}
+	}
+	if opts.Prune {
+		for _, v := range current.Volumes {
+			if !declared[v.Name] {
+				deletions = append(deletions, inUse(Change{Action: ActionDelete, Kind: KindVolume, Name: v.Name}, v.JobCount))
+			}
+		}
+	}
+
+	networks := make(map[string]*pb.Network, len(current.Networks))
+	for _, n := range current.Networks {
+		networks[n.Name] = n
+	}
+	declared = make(map[string]bool)
+	for i := range desired.Networks {
+		want := &desired.Networks[i]
+		declared[want.Name] = true
+		have, ok := networks[want.Name]
+		if !ok {
+			changes = append(changes, Change{Action: ActionCreate, Kind: KindNetwork, Name: want.Name,
+				Detail: want.CIDR, network: want})
+			continue
+		}
+		if have.Cidr != want.CIDR {
+			change := Change{Action: ActionReplace, Kind: KindNetwork, Name: want.Name,
+				Detail: fmt.Sprintf("cidr %s -> %s", have.Cidr, want.CIDR), network: want}
+			if !opts.Replace {
+				change.Blocked = "use --replace"
+			} else if have.JobCount > 0 {
+				change.Blocked = fmt.Sprintf("in use by %d job(s)", have.JobCount)
+			}
+			changes = append(changes, change)
+		}
+	}
+	if opts.Prune {
+		for _, n := range current.Networks {
+			if !declared[n.Name] && !builtinNetworks[n.Name] {
+				deletions = append(deletions, inUse(Change{Action: ActionDelete, Kind: KindNetwork, Name: n.Name}, n.JobCount))
+			}
+		}
+	}
+
+	runtimes := make(map[string]*pb.RuntimeInfo, len(current.Runtimes))
+	for _, rt := range current.Runtimes {
+		runtimes[rt.Name] = rt
+	}
+	declared = make(map[string]bool)
+	for i := range desired.Runtimes {
+		want := &desired.Runtimes[i]
+		declared[want.Name] = true
+		have, ok := runtimes[want.Name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Action: ActionCreate, Kind: KindRuntime, Name: want.Name,
+				Detail: runtimeSource(want), runtime: want})
+		case !have.Available:
+			changes = append(changes, Change{Action: ActionReplace, Kind: KindRuntime, Name: want.Name,
+				Detail: "installed but unavailable, reinstall", runtime: want})
+		}
+	}
+	if opts.Prune {
+		for _, rt := range current.Runtimes {
+			if !declared[rt.Name] {
+				deletions = append(deletions, Change{Action: ActionDelete, Kind: KindRuntime, Name: rt.Name})
+			}
+		}
+	}
+
+	// Pending runs of recurring jobs, by label
+	pending := make(map[string][]*pb.Job)
+	for _, job := range current.Jobs {
+		if name := job.Environment[LabelRecurring]; name != "" && job.Status == "SCHEDULED" {
+			pending[name] = append(pending[name], job)
+		}
+	}
+	declared = make(map[string]bool)
+	for i := range desired.RecurringJobs {
+		want := &desired.RecurringJobs[i]
+		declared[want.Name] = true
+
+		schedule, err := cron.Parse(want.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("recurring job %s: %w", want.Name, err)
+		}
+		next := schedule.Next(now)
+		if next.IsZero() {
+			return nil, fmt.Errorf("recurring job %s: schedule %q never fires", want.Name, want.Schedule)
+		}
+
+		hash := want.Hash()
+		var current *pb.Job
+		var stale []string
+		for _, job := range pending[want.Name] {
+			if current == nil && job.Environment[LabelRecurringHash] == hash {
+				current = job
+				continue
+			}
+			stale = append(stale, job.Uuid)
+		}
+
+		switch {
+		case current != nil && len(stale) == 0:
+			// Next run is already scheduled with this definition
+		case current != nil:
+			changes = append(changes, Change{Action: ActionDelete, Kind: KindRecurringJob, Name: want.Name,
+				Detail: fmt.Sprintf("cancel %d duplicate run(s)", len(stale)), staleJobs: stale})
+		case len(stale) > 0:
+			changes = append(changes, Change{Action: ActionReplace, Kind: KindRecurringJob, Name: want.Name,
+				Detail: "definition changed, next run " + next.Format(time.RFC3339), job: want, runAt: next, staleJobs: stale})
+		default:
+			changes = append(changes, Change{Action: ActionCreate, Kind: KindRecurringJob, Name: want.Name,
+				Detail: "next run " + next.Format(time.RFC3339), job: want, runAt: next})
+		}
+	}
+	if opts.Prune {
+		names := make([]string, 0, len(pending))
+		for name := range pending {
+			if !declared[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var uuids []string
+			for _, job := range pending[name] {
+				uuids = append(uuids, job.Uuid)
+			}
+			// Cancel runs first so volumes and networks they use can be removed
+			deletions = append([]Change{{Action: ActionDelete, Kind: KindRecurringJob, Name: name,
+				Detail: fmt.Sprintf("cancel %d scheduled run(s)", len(uuids)), staleJobs: uuids}}, deletions...)
+		}
+	}
+
+	return append(changes, deletions...), nil
+}
+
+func volumeDiff(want *Volume, have *pb.Volume) string {
+	var diffs []string
+	wantSize, _ := domain.ParseSize(want.Size)
+	haveSize, err := domain.ParseSize(have.Size)
+	if err != nil || wantSize != haveSize {
+		diffs = append(diffs, fmt.Sprintf("size %s -> %s", have.Size, want.Size))
+	}
+	if have.Type != want.Type {
+		diffs = append(diffs, fmt.Sprintf("type %s -> %s", have.Type, want.Type))
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func inUse(change Change, jobCount int32) Change {
+	if jobCount > 0 {
+		change.Blocked = fmt.Sprintf("in use by %d job(s)", jobCount)
+	}
+	return change
+}
+
+func runtimeSource(rt *Runtime) string {
+	switch {
+	case rt.Repository != "":
+		return "from " + rt.Repository
+	case rt.Registry != "":
+		return "from " + rt.Registry
+	default:
+		return "from default registry"
+	}
+}
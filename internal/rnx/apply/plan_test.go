@@ -0,0 +1,142 @@
+package apply
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testResources = `
+volumes:
+  - name: datasets
+    size: 1GB
+  - name: cache
+    size: 256MB
+    type: memory
+networks:
+  - name: backend
+    cidr: 10.20.0.0/24
+runtimes:
+  - name: python-3.11-ml
+recurring_jobs:
+  - name: nightly-report
+    schedule: "0 2 * * *"
+    command: python3
+    args: [report.py]
+    volumes: [datasets]
+    resources:
+      max_memory: 512
+`
+
+func summarize(changes []Change) []string {
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	return lines
+}
+
+func TestPlan_CreateThenNoop(t *testing.T) {
+	desired, err := Parse([]byte(testResources))
+	require.NoError(t, err)
+	now := time.Date(2025, 7, 16, 10, 0, 0, 0, time.UTC)
+
+	changes, err := Plan(desired, &State{}, now, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"+ volume datasets (1GB filesystem)",
+		"+ volume cache (256MB memory)",
+		"+ network backend (10.20.0.0/24)",
+		"+ runtime python-3.11-ml (from default registry)",
+		"+ recurring_job nightly-report (next run 2025-07-17T02:00:00Z)",
+	}, summarize(changes))
+
+	run := scheduledRun(changes[4].job, changes[4].runAt)
+	assert.Equal(t, "2025-07-17T02:00:00Z", run.Schedule)
+	assert.Equal(t, int32(512), run.MaxMemory)
+	assert.Equal(t, "nightly-report", run.Environment[LabelRecurring])
+
+	// The state after applying: same plan again must be empty
+	applied := &State{
+		Volumes: []*pb.Volume{
+			{Name: "datasets", Size: "1024MB", Type: "filesystem"},
+			{Name: "cache", Size: "256MB", Type: "memory"},
+		},
+		Networks: []*pb.Network{{Name: "backend", Cidr: "10.20.0.0/24"}, {Name: "bridge", Cidr: "172.20.0.0/16"}},
+		Runtimes: []*pb.RuntimeInfo{{Name: "python-3.11-ml", Available: true}},
+		Jobs:     []*pb.Job{{Uuid: "j1", Status: "SCHEDULED", Environment: run.Environment}},
+	}
+	changes, err = Plan(desired, applied, now.Add(time.Hour), Options{Prune: true})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestPlan_Drift(t *testing.T) {
+	desired, err := Parse([]byte(testResources))
+	require.NoError(t, err)
+	desired.Runtimes = nil
+	now := time.Date(2025, 7, 16, 10, 0, 0, 0, time.UTC)
+
+	oldRun := scheduledRun(&RecurringJob{Name: "nightly-report", Schedule: "0 3 * * *", Command: "python3"}, now)
+	current := &State{
+		Volumes: []*pb.Volume{
+			{Name: "datasets", Size: "512MB", Type: "filesystem", JobCount: 1},
+			{Name: "cache", Size: "256MB", Type: "memory"},
+			{Name: "scratch", Size: "1GB", Type: "filesystem"},
+		},
+		Networks: []*pb.Network{{Name: "backend", Cidr: "10.30.0.0/24"}, {Name: "isolated"}},
+		Runtimes: []*pb.RuntimeInfo{{Name: "node-18", Available: true}},
+		Jobs: []*pb.Job{
+			{Uuid: "old", Status: "SCHEDULED", Environment: oldRun.Environment},
+			{Uuid: "done", Status: "COMPLETED", Environment: map[string]string{LabelRecurring: "retired"}},
+			{Uuid: "orphan", Status: "SCHEDULED", Environment: map[string]string{LabelRecurring: "retired"}},
+		},
+	}
+
+	changes, err := Plan(desired, current, now, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"~ volume datasets (size 512MB -> 1GB) [skipped: replacing a volume deletes its data, use --replace]",
+		"~ network backend (cidr 10.30.0.0/24 -> 10.20.0.0/24) [skipped: use --replace]",
+		"~ recurring_job nightly-report (definition changed, next run 2025-07-17T02:00:00Z)",
+	}, summarize(changes))
+	assert.Equal(t, []string{"old"}, changes[2].staleJobs)
+
+	changes, err = Plan(desired, current, now, Options{Prune: true, Replace: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"~ volume datasets (size 512MB -> 1GB) [skipped: in use by 1 job(s)]",
+		"~ network backend (cidr 10.30.0.0/24 -> 10.20.0.0/24)",
+		"~ recurring_job nightly-report (definition changed, next run 2025-07-17T02:00:00Z)",
+		"- recurring_job retired (cancel 1 scheduled run(s))",
+		"- volume scratch",
+		"- runtime node-18",
+	}, summarize(changes))
+	assert.Equal(t, []string{"orphan"}, changes[3].staleJobs)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":   "volume:\n  - name: a\n",
+		"duplicate":       "volumes:\n  - {name: a, size: 1GB}\n  - {name: a, size: 2GB}\n",
+		"bad size":        "volumes:\n  - {name: a, size: lots}\n",
+		"bad type":        "volumes:\n  - {name: a, size: 1GB, type: nfs}\n",
+		"builtin network": "networks:\n  - {name: bridge, cidr: 10.0.0.0/24}\n",
+		"bad cidr":        "networks:\n  - {name: n, cidr: 10.0.0.0}\n",
+		"no command":      "recurring_jobs:\n  - {name: j, schedule: '@daily'}\n",
+		"bad schedule":    "recurring_jobs:\n  - {name: j, schedule: 'daily', command: true}\n",
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(doc))
+			assert.Error(t, err)
+		})
+	}
+
+	res, err := Parse(nil)
+	require.NoError(t, err)
+	assert.Empty(t, res.Volumes)
+}
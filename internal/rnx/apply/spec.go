@@ -0,0 +1,158 @@
+// Package apply reconciles a declarative description of joblet resources
+// (volumes, networks, runtimes and recurring jobs) with the state of a node.
+package apply
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/ehsaniara/joblet/internal/joblet/cron"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Resources is the desired state read from a resources file.
+type Resources struct {
+	Volumes       []Volume       `yaml:"volumes"`
+	Networks      []Network      `yaml:"networks"`
+	Runtimes      []Runtime      `yaml:"runtimes"`
+	RecurringJobs []RecurringJob `yaml:"recurring_jobs"`
+}
+
+// Volume declares a persistent volume.
+type Volume struct {
+	Name string `yaml:"name"`
+	Size string `yaml:"size"`
+	Type string `yaml:"type"`
+}
+
+// Network declares a custom network.
+type Network struct {
+	Name string `yaml:"name"`
+	CIDR string `yaml:"cidr"`
+}
+
+// Runtime declares an installed runtime. Without a repository the server's
+// default runtime registry is used.
+type Runtime struct {
+	Name       string `yaml:"name"`
+	Repository string `yaml:"repository,omitempty"`
+	Branch     string `yaml:"branch,omitempty"`
+	Path       string `yaml:"path,omitempty"`
+	Registry   string `yaml:"registry,omitempty"`
+}
+
+// RecurringJob declares a job that runs on a cron schedule.
+type RecurringJob struct {
+	Name        string             `yaml:"name"`
+	Schedule    string             `yaml:"schedule"`
+	Command     string             `yaml:"command"`
+	Args        []string           `yaml:"args,omitempty"`
+	Runtime     string             `yaml:"runtime,omitempty"`
+	Network     string             `yaml:"network,omitempty"`
+	Volumes     []string           `yaml:"volumes,omitempty"`
+	Environment map[string]string  `yaml:"environment,omitempty"`
+	Resources   types.JobResources `yaml:"resources,omitempty"`
+}
+
+// Hash identifies the job definition so that edits reschedule the pending run.
+func (j *RecurringJob) Hash() string {
+	data, _ := json.Marshal(j)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// Load reads and validates a resources file.
+func Load(path string) (*Resources, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a resources document.
+func Parse(data []byte) (*Resources, error) {
+	var res Resources
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&res); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid resources file: %w", err)
+	}
+	if err := res.validate(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *Resources) validate() error {
+	seen := make(map[string]bool)
+	unique := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s without a name", kind)
+		}
+		key := kind + "/" + name
+		if seen[key] {
+			return fmt.Errorf("%s %s is declared twice", kind, name)
+		}
+		seen[key] = true
+		return nil
+	}
+
+	for i := range r.Volumes {
+		v := &r.Volumes[i]
+		if err := unique(KindVolume, v.Name); err != nil {
+			return err
+		}
+		if v.Type == "" {
+			v.Type = "filesystem"
+		}
+		if v.Type != "filesystem" && v.Type != "memory" {
+			return fmt.Errorf("volume %s: invalid type %q (must be 'filesystem' or 'memory')", v.Name, v.Type)
+		}
+		if _, err := domain.ParseSize(v.Size); err != nil {
+			return fmt.Errorf("volume %s: %w", v.Name, err)
+		}
+	}
+
+	for _, n := range r.Networks {
+		if err := unique(KindNetwork, n.Name); err != nil {
+			return err
+		}
+		if builtinNetworks[n.Name] {
+			return fmt.Errorf("network %s is built in and cannot be declared", n.Name)
+		}
+		if _, _, err := net.ParseCIDR(n.CIDR); err != nil {
+			return fmt.Errorf("network %s: invalid cidr %q", n.Name, n.CIDR)
+		}
+	}
+
+	for _, rt := range r.Runtimes {
+		if err := unique(KindRuntime, rt.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, j := range r.RecurringJobs {
+		if err := unique(KindRecurringJob, j.Name); err != nil {
+			return err
+		}
+		if j.Command == "" {
+			return fmt.Errorf("recurring job %s: command is required", j.Name)
+		}
+		if _, err := cron.Parse(j.Schedule); err != nil {
+			return fmt.Errorf("recurring job %s: %w", j.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show server configuration policies",
+		Long: `Show the policies configured on the connected joblet server.
+
+Use 'rnx config-help' for the client configuration file (rnx-config.yml).`,
+	}
+
+	cmd.AddCommand(newConfigRetentionCmd())
+
+	return cmd
+}
+
+func newConfigRetentionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retention",
+		Short: "Show the finished job retention policy",
+		Long: `Show how long the server keeps finished job records and what its
+background reaper removed so far.
+
+Records are removed once a job name has more than keep_last finished runs,
+or once a finished job is older than max_age. With archiving on, each record
+is stored in the persist service before it is removed, and its logs stay
+available there.
+
+Examples:
+  rnx config retention                 # Policy and sweep statistics
+  rnx config retention --json          # JSON for scripts`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigRetention(common.JSONOutput)
+		},
+	}
+}
+
+type retentionPolicyJSON struct {
+	Enabled         bool  `json:"enabled"`
+	KeepLast        int32 `json:"keepLast"`
+	MaxAgeSeconds   int64 `json:"maxAgeSeconds"`
+	IntervalSeconds int64 `json:"intervalSeconds"`
+	Archive         bool  `json:"archive"`
+	LastSweep       int64 `json:"lastSweep"`
+	LastRemoved     int32 `json:"lastRemoved"`
+	TotalRemoved    int64 `json:"totalRemoved"`
+}
+
+func runConfigRetention(jsonOutput bool) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	policy, err := jobClient.GetRetentionPolicy(context.Background())
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("this server does not support job retention; upgrade the joblet server")
+		}
+		return fmt.Errorf("couldn't get retention policy: %v", err)
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(retentionPolicyJSON{
+			Enabled:         policy.Enabled,
+			KeepLast:        policy.KeepLast,
+			MaxAgeSeconds:   policy.MaxAgeSeconds,
+			IntervalSeconds: policy.IntervalSeconds,
+			Archive:         policy.Archive,
+			LastSweep:       policy.LastSweep,
+			LastRemoved:     policy.LastRemoved,
+			TotalRemoved:    policy.TotalRemoved,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if !policy.Enabled {
+		fmt.Println("Job retention is disabled: finished jobs are kept until deleted")
+		return nil
+	}
+
+	printRetentionPolicy(policy, time.Now())
+	return nil
+}
+
+func printRetentionPolicy(policy *jobspb.RetentionPolicy, now time.Time) {
+	keepLast := "-"
+	if policy.KeepLast > 0 {
+		keepLast = fmt.Sprintf("%d per job name", policy.KeepLast)
+	}
+	maxAge := "-"
+	if policy.MaxAgeSeconds > 0 {
+		maxAge = (time.Duration(policy.MaxAgeSeconds) * time.Second).String()
+	}
+	archive := "no"
+	if policy.Archive {
+		archive = "yes (persist)"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Keep last:\t%s\n", keepLast)
+	fmt.Fprintf(w, "Max age:\t%s\n", maxAge)
+	fmt.Fprintf(w, "Sweep interval:\t%s\n", time.Duration(policy.IntervalSeconds)*time.Second)
+	fmt.Fprintf(w, "Archive:\t%s\n", archive)
+	fmt.Fprintf(w, "Last sweep:\t%s\n", formatLastSeen(policy.LastSweep, now))
+	fmt.Fprintf(w, "Last removed:\t%d\n", policy.LastRemoved)
+	fmt.Fprintf(w, "Total removed:\t%d\n", policy.TotalRemoved)
+	w.Flush()
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/keychain"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// keychainFields are the node fields holding private keys and tokens;
+// certificates are public and stay in the file
+var keychainFields = []string{"key", "signingKey", "token"}
+
+func newNodesKeychainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "keychain [node...]",
+		Short: "Move node private keys and tokens into the OS keychain",
+		Long: `Move the private keys and tokens of nodes from rnx-config.yml into the OS keychain
+(macOS Keychain, Secret Service via secret-tool on Linux, DPAPI on Windows).
+
+Each secret is stored under the account <node>.<field> and replaced in the
+configuration file by a keychain:<node>.<field> reference. Without arguments
+every node is moved.
+
+Examples:
+  rnx nodes keychain              # Move the keys of every node
+  rnx nodes keychain production   # Move the keys of one node`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNodesKeychain(args)
+		},
+	}
+}
+
+func runNodesKeychain(nodeNames []string) error {
+	if common.NodeConfig == nil || common.NodeConfig.Path == "" {
+		return fmt.Errorf("no client configuration loaded. Please ensure rnx-config.yml exists")
+	}
+	path := common.NodeConfig.Path
+
+	if len(nodeNames) == 0 {
+		nodeNames = common.NodeConfig.ListNodes()
+		sort.Strings(nodeNames)
+	}
+	for _, name := range nodeNames {
+		if _, err := common.NodeConfig.GetNode(name); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	updated, moved, err := moveKeysToKeychain(data, nodeNames, keychain.Set)
+	if err != nil {
+		return err
+	}
+	if len(moved) == 0 {
+		fmt.Println("No private keys or tokens left to move, the selected nodes already use the keychain")
+		return nil
+	}
+
+	// Replace the file atomically so an interrupted write never loses keys
+	// that are already in the keychain
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, updated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	for _, account := range moved {
+		fmt.Printf("Moved %s to the keychain (%s)\n", account, keychain.Ref(account))
+	}
+	fmt.Printf("Updated %s\n", path)
+	fmt.Println("Copies of the secrets in backups or version control of the old file are not removed")
+	return nil
+}
+
+// moveKeysToKeychain stores the private keys and tokens of the named nodes
+// with set and returns the configuration with keychain references in their
+// place, plus the accounts that were stored. Comments and layout of the file
+// are kept.
+func moveKeysToKeychain(data []byte, nodeNames []string, set func(account, secret string) error) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse client config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil, fmt.Errorf("client config is empty")
+	}
+	nodes := mappingValue(doc.Content[0], "nodes")
+	if nodes == nil {
+		return nil, nil, fmt.Errorf("no nodes configured")
+	}
+
+	var moved []string
+	for _, name := range nodeNames {
+		node := mappingValue(nodes, name)
+		if node == nil {
+			return nil, nil, fmt.Errorf("node '%s' not found in configuration", name)
+		}
+		for _, field := range keychainFields {
+			value := mappingValue(node, field)
+			if value == nil || value.Kind != yaml.ScalarNode || value.Value == "" || keychain.IsRef(value.Value) {
+				continue
+			}
+			account := name + "." + field
+			if err := set(account, value.Value); err != nil {
+				return nil, nil, fmt.Errorf("failed to store %s in keychain: %w", account, err)
+			}
+			value.Value = keychain.Ref(account)
+			value.Style = 0
+			moved = append(moved, account)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to write client config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to write client config: %w", err)
+	}
+	return buf.Bytes(), moved, nil
+}
+
+// mappingValue returns the value of key in a YAML mapping, nil if missing
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
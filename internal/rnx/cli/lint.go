@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ehsaniara/joblet/internal/joblet/lint"
+	lintpb "github.com/ehsaniara/joblet/internal/proto/gen/lint"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NewLintCmd creates the lint command
+func NewLintCmd() *cobra.Command {
+	var (
+		server bool
+		failOn string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint <job.yaml|workflow.yaml>...",
+		Short: "Check job specs and workflows against best practices",
+		Long: `Check job specs ('rnx job run -f') and workflows ('rnx workflow run') for
+common mistakes before they are submitted. Nothing is run.
+
+Rules:
+  no-resource-limits   no max_cpu or max_memory set (warning, info if one is set)
+  secret-in-plain-env  a plain environment variable named like a secret
+                       (warning) or holding a credential-shaped value (error)
+  literal-secret       a job spec secret_environment value written in the file
+                       instead of referenced as ${VAR} (warning)
+  unpinned-runtime     a runtime without @version (warning)
+  broad-network        the job runs on the default bridge network (info)
+  unknown-network      the network does not exist on the node (error, --server)
+
+Locally the checks need no configuration or node. With --server the node
+lints the files: runtime aliases and tenant pins it resolves to a pinned
+version are not flagged, networks it does not have are errors, and jobs
+without limits are told the node's default limits.
+
+The command fails when a finding is at least as severe as --fail-on, so it
+can run as a pre-commit hook. --json prints the findings for tools.
+
+Examples:
+  rnx lint job.yaml                         # Lint a job spec
+  rnx lint workflows/*.yaml --fail-on=warning
+  rnx lint pipeline.yaml --server           # Lint with the node's knowledge
+  rnx lint pipeline.yaml --json             # Machine-readable findings`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			threshold, err := lint.ParseSeverity(failOn)
+			if err != nil {
+				return err
+			}
+
+			linter := localLinter
+			if server {
+				jobClient, err := common.NewJobClient()
+				if err != nil {
+					return fmt.Errorf("failed to create client: %w", err)
+				}
+				linter = serverLinter(jobClient)
+			}
+
+			findings, err := lintFiles(cmd.Context(), args, linter)
+			if err != nil {
+				return err
+			}
+			if common.JSONOutput {
+				if err := printJSON(os.Stdout, findings); err != nil {
+					return err
+				}
+			} else {
+				printText(os.Stdout, findings)
+			}
+
+			if n := lint.Count(findings, threshold); n > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d finding(s) at or above %s", n, threshold)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&server, "server", false, "Lint on the node with its runtime aliases, networks and default limits")
+	cmd.Flags().StringVar(&failOn, "fail-on", string(lint.SeverityError), "Fail when a finding is this severe or more: error, warning or info")
+
+	return cmd
+}
+
+// linter lints one spec and returns the findings
+type linter func(ctx context.Context, spec []byte) ([]lint.Finding, error)
+
+func localLinter(_ context.Context, spec []byte) ([]lint.Finding, error) {
+	_, findings, err := lint.Lint(spec, lint.Options{})
+	return findings, err
+}
+
+// specLinter is the part of the client used to lint on the node
+type specLinter interface {
+	LintSpec(ctx context.Context, spec []byte) (*lintpb.LintSpecResponse, error)
+}
+
+func serverLinter(c specLinter) linter {
+	return func(ctx context.Context, spec []byte) ([]lint.Finding, error) {
+		resp, err := c.LintSpec(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		findings := make([]lint.Finding, 0, len(resp.Findings))
+		for _, f := range resp.Findings {
+			findings = append(findings, lint.Finding{
+				Rule:     f.Rule,
+				Severity: lint.Severity(f.Severity),
+				Job:      f.Job,
+				Field:    f.Field,
+				Message:  f.Message,
+			})
+		}
+		return findings, nil
+	}
+}
+
+// lintFiles lints each file, naming it in its findings
+func lintFiles(ctx context.Context, paths []string, lintSpec linter) ([]lint.Finding, error) {
+	var all []lint.Finding
+	for _, path := range paths {
+		spec, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		findings, err := lintSpec(ctx, spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, f := range findings {
+			f.File = path
+			all = append(all, f)
+		}
+	}
+	return all, nil
+}
+
+func summary(findings []lint.Finding) map[lint.Severity]int {
+	counts := make(map[lint.Severity]int, len(lint.Severities))
+	for _, s := range lint.Severities {
+		counts[s] = 0
+	}
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
+
+func printText(w io.Writer, findings []lint.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No findings")
+		return
+	}
+	for _, f := range findings {
+		fmt.Fprintln(w, f)
+	}
+	counts := summary(findings)
+	fmt.Fprintf(w, "\n%d finding(s): %d error(s), %d warning(s), %d info\n",
+		len(findings), counts[lint.SeverityError], counts[lint.SeverityWarning], counts[lint.SeverityInfo])
+}
+
+func printJSON(w io.Writer, findings []lint.Finding) error {
+	if findings == nil {
+		findings = []lint.Finding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(struct {
+		Findings []lint.Finding        `json:"findings"`
+		Summary  map[lint.Severity]int `json:"summary"`
+	}{findings, summary(findings)})
+}
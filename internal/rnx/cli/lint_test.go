@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/lint"
+	lintpb "github.com/ehsaniara/joblet/internal/proto/gen/lint"
+)
+
+func writeSpec(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLintFiles(t *testing.T) {
+	job := writeSpec(t, "job.yaml", "command: ls\nruntime: python-3.11\nnetwork: none\nresources: {max_cpu: 10, max_memory: 64}\n")
+	findings, err := lintFiles(context.Background(), []string{job}, localLinter)
+	if err != nil {
+		t.Fatalf("lintFiles() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != lint.RuleUnpinnedRuntime || findings[0].File != job {
+		t.Fatalf("findings = %+v", findings)
+	}
+
+	var out bytes.Buffer
+	if err := printJSON(&out, findings); err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Findings []lint.Finding        `json:"findings"`
+		Summary  map[lint.Severity]int `json:"summary"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON %s: %v", out.String(), err)
+	}
+	if len(report.Findings) != 1 || report.Summary[lint.SeverityWarning] != 1 || report.Summary[lint.SeverityError] != 0 {
+		t.Errorf("report = %+v", report)
+	}
+
+	if _, err := lintFiles(context.Background(), []string{filepath.Join(t.TempDir(), "missing.yaml")}, localLinter); err == nil {
+		t.Error("lintFiles() accepted a missing file")
+	}
+}
+
+type fakeSpecLinter struct{ resp *lintpb.LintSpecResponse }
+
+func (f fakeSpecLinter) LintSpec(context.Context, []byte) (*lintpb.LintSpecResponse, error) {
+	return f.resp, nil
+}
+
+func TestServerLinter(t *testing.T) {
+	resp := &lintpb.LintSpecResponse{Kind: lint.KindJob, Findings: []*lintpb.LintFinding{
+		{Rule: lint.RuleUnknownNetwork, Severity: "error", Field: "network", Message: "network gpu does not exist on the node"},
+	}}
+	findings, err := serverLinter(fakeSpecLinter{resp})(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Severity != lint.SeverityError || findings[0].Rule != lint.RuleUnknownNetwork {
+		t.Errorf("findings = %+v", findings)
+	}
+}
+
+func TestLintCmdFailOn(t *testing.T) {
+	// One warning: unpinned runtime
+	job := writeSpec(t, "job.yaml", "command: ls\nruntime: python-3.11\nnetwork: none\nresources: {max_cpu: 10, max_memory: 64}\n")
+
+	cmd := NewLintCmd()
+	cmd.SetArgs([]string{job})
+	cmd.SetOut(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("lint failed on a warning with --fail-on=error: %v", err)
+	}
+
+	cmd = NewLintCmd()
+	cmd.SetArgs([]string{job, "--fail-on=warning"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "1 finding(s) at or above warning") {
+		t.Errorf("lint --fail-on=warning error = %v", err)
+	}
+}
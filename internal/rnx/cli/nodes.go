@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/keychain"
 
 	"github.com/spf13/cobra"
 )
@@ -14,12 +15,18 @@ func NewNodesCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "nodes",
 		Short: "List available nodes from configuration",
-		Long:  "Display all configured nodes and their connection details from rnx-config.yml",
+		Long: `Display all configured nodes and their connection details from rnx-config.yml.
+
+Use 'rnx nodes list' to see the live nodes registered with the connected
+node's coordination endpoint instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runNodes(common.JSONOutput)
 		},
 	}
 
+	cmd.AddCommand(newNodesListCmd())
+	cmd.AddCommand(newNodesKeychainCmd())
+
 	return cmd
 }
 
@@ -106,7 +113,9 @@ func runNodes(jsonOutput bool) error {
 		fmt.Printf("   Cert:    %s\n", cert)
 
 		key := "-"
-		if node.Key != "" {
+		if keychain.IsRef(node.Key) {
+			key = node.Key
+		} else if node.Key != "" {
 			key = "***"
 		}
 		fmt.Printf("   Key:     %s\n", key)
@@ -117,6 +126,18 @@ func runNodes(jsonOutput bool) error {
 		}
 		fmt.Printf("   CA:      %s\n", ca)
 
+		if keychain.IsRef(node.SigningKey) {
+			fmt.Printf("   Signing: %s\n", node.SigningKey)
+		} else if node.SigningKey != "" {
+			fmt.Printf("   Signing: ***\n")
+		}
+
+		if keychain.IsRef(node.Token) {
+			fmt.Printf("   Token:   %s\n", node.Token)
+		} else if node.Token != "" {
+			fmt.Printf("   Token:   ***\n")
+		}
+
 		fmt.Println()
 	}
 
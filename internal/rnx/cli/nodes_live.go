@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newNodesListCmd() *cobra.Command {
+	var labels []string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List live nodes registered with the coordination endpoint",
+		Long: `List the joblet nodes that currently send heartbeats to the coordination
+endpoint (the state service or etcd), as seen by the connected node.
+
+Nodes register themselves when coordination is enabled in their server
+configuration and drop out of the list once their TTL passes without a
+heartbeat, so the list does not depend on the nodes in rnx-config.yml.
+
+Examples:
+  rnx nodes list                       # All live nodes
+  rnx nodes list --label zone=eu       # Only nodes labelled zone=eu
+  rnx nodes list --json                # JSON for scripts`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := make(map[string]string)
+			for _, label := range labels {
+				key, value, err := values.ParseLabel(label)
+				if err != nil {
+					return fmt.Errorf("invalid --label value: %w", err)
+				}
+				filter[key] = value
+			}
+			return runNodesList(filter, common.JSONOutput)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "Only list nodes with this KEY=VALUE label (repeatable)")
+
+	return cmd
+}
+
+type liveNodeJSON struct {
+	NodeId        string            `json:"nodeId"`
+	Address       string            `json:"address"`
+	Hostname      string            `json:"hostname,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	CPUPercent    int64             `json:"cpuPercent"`
+	CPUFree       int64             `json:"cpuSchedulablePercent"`
+	MemoryMB      int64             `json:"memoryMB"`
+	MemoryFreeMB  int64             `json:"memorySchedulableMB"`
+	GPUs          int64             `json:"gpus"`
+	GPUsFree      int64             `json:"gpusSchedulable"`
+	ActiveJobs    int32             `json:"activeJobs"`
+	RegisteredAt  int64             `json:"registeredAt"`
+	LastHeartbeat int64             `json:"lastHeartbeat"`
+}
+
+func runNodesList(labels map[string]string, jsonOutput bool) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	resp, err := jobClient.ListNodes(context.Background(), labels)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("node coordination is not enabled on this server; use 'rnx nodes' for configured nodes")
+		}
+		return fmt.Errorf("couldn't list nodes: %v", err)
+	}
+
+	if jsonOutput {
+		nodes := make([]liveNodeJSON, 0, len(resp.Nodes))
+		for _, node := range resp.Nodes {
+			c := node.GetCapacity()
+			nodes = append(nodes, liveNodeJSON{
+				NodeId:        node.NodeId,
+				Address:       node.Address,
+				Hostname:      node.Hostname,
+				Version:       node.Version,
+				Labels:        node.Labels,
+				CPUPercent:    c.GetCpuTotal(),
+				CPUFree:       c.GetCpuSchedulable(),
+				MemoryMB:      c.GetMemoryTotalMb(),
+				MemoryFreeMB:  c.GetMemorySchedulableMb(),
+				GPUs:          c.GetGpuTotal(),
+				GPUsFree:      c.GetGpuSchedulable(),
+				ActiveJobs:    c.GetActiveJobs(),
+				RegisteredAt:  node.RegisteredAt,
+				LastHeartbeat: node.LastHeartbeat,
+			})
+		}
+		output, err := json.MarshalIndent(nodes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if len(resp.Nodes) == 0 {
+		fmt.Println("No live nodes registered")
+		return nil
+	}
+
+	printLiveNodes(resp.Nodes, time.Now())
+	return nil
+}
+
+func printLiveNodes(nodes []*nodespb.NodeInfo, now time.Time) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE ID\tADDRESS\tVERSION\tCPU FREE\tMEMORY FREE\tGPUS FREE\tJOBS\tLAST SEEN\tLABELS")
+	for _, node := range nodes {
+		c := node.GetCapacity()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%d\t%s\t%s\n",
+			node.NodeId,
+			node.Address,
+			valueOrDash(node.Version),
+			fmt.Sprintf("%.1f/%.1f cores", float64(c.GetCpuSchedulable())/100, float64(c.GetCpuTotal())/100),
+			fmt.Sprintf("%d/%d MB", c.GetMemorySchedulableMb(), c.GetMemoryTotalMb()),
+			c.GetGpuSchedulable(), c.GetGpuTotal(),
+			c.GetActiveJobs(),
+			formatLastSeen(node.LastHeartbeat, now),
+			valueOrDash(values.FormatLabels(node.Labels)),
+		)
+	}
+	w.Flush()
+}
+
+// formatLastSeen shows how long ago the last heartbeat arrived
+func formatLastSeen(unix int64, now time.Time) string {
+	if unix <= 0 {
+		return "-"
+	}
+	ago := now.Sub(time.Unix(unix, 0)).Round(time.Second)
+	if ago < 0 {
+		ago = 0
+	}
+	return ago.String() + " ago"
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ehsaniara/joblet/internal/rnx/admin"
+	"github.com/ehsaniara/joblet/internal/rnx/apply"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
 	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+	"github.com/ehsaniara/joblet/internal/rnx/queue"
 	"github.com/ehsaniara/joblet/internal/rnx/resources"
 	"github.com/ehsaniara/joblet/internal/rnx/workflow"
 	"github.com/ehsaniara/joblet/pkg/config"
@@ -53,6 +56,26 @@ Use 'rnx <command> --help' for detailed information about any command.`,
 			return nil
 		}
 
+		// Workflow init and import only write local files and simulate only
+		// reads them; none of them contacts a node
+		if (cmd.Name() == "init" || cmd.Name() == "import" || cmd.Name() == "simulate") && cmd.Parent() != nil && cmd.Parent().Name() == "workflow" {
+			return nil
+		}
+
+		// Shell completion scripts are generated locally, and completing an
+		// argument loads the config itself so a missing one completes nothing
+		if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd ||
+			(cmd.Parent() != nil && cmd.Parent().Name() == "completion") {
+			return nil
+		}
+
+		// Local linting needs no node
+		if cmd.Name() == "lint" && cmd.Parent() == cmd.Root() {
+			if server, _ := cmd.Flags().GetBool("server"); !server {
+				return nil
+			}
+		}
+
 		// Check if --version flag is used - version commands can work without config
 		if versionFlag, _ := cmd.Flags().GetBool("version"); versionFlag || cmd.Name() == "version" {
 			// Try to load config but don't exit on failure
@@ -74,20 +97,20 @@ Use 'rnx <command> --help' for detailed information about any command.`,
 }
 
 func Execute() error {
+	defer common.CloseClients()
 	return rootCmd.Execute()
 }
 
 func init() {
-	// Disable Cobra's automatic completion command generation
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
-
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&common.ConfigPath, "config", "",
 		"Path to client configuration file (searches common locations if not specified)")
 	rootCmd.PersistentFlags().StringVar(&common.NodeName, "node", "default",
-		"Node name from configuration file")
+		"Node name from configuration file ('auto' lets job run choose by capacity)")
 	rootCmd.PersistentFlags().BoolVar(&common.JSONOutput, "json", false,
 		"Output in JSON format")
+	rootCmd.PersistentFlags().DurationVar(&common.Timeout, "timeout", 0,
+		"Deadline for each request to the server, including retries (e.g. 30s; 0 = no limit)")
 
 	// Add subcommands
 	rootCmd.AddCommand(jobs.NewJobCmd())
@@ -95,9 +118,14 @@ func init() {
 	rootCmd.AddCommand(jobs.NewMonitorCmd())
 	rootCmd.AddCommand(NewNodesCmd())
 	rootCmd.AddCommand(NewHelpConfigCmd())
+	rootCmd.AddCommand(NewConfigCmd())
 	rootCmd.AddCommand(resources.NewNetworkCmd())
 	rootCmd.AddCommand(resources.NewVolumeCmd())
 	rootCmd.AddCommand(resources.NewRuntimeCmd())
+	rootCmd.AddCommand(queue.NewQueueCmd())
+	rootCmd.AddCommand(apply.NewApplyCmd())
+	rootCmd.AddCommand(NewLintCmd())
+	rootCmd.AddCommand(admin.NewAdminCmd())
 	// Add --version flag support
 	AddVersionFlag(rootCmd)
 }
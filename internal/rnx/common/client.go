@@ -2,6 +2,8 @@ package common
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/ehsaniara/joblet/pkg/client"
 	"github.com/ehsaniara/joblet/pkg/config"
@@ -12,21 +14,52 @@ var (
 	ConfigPath string
 	NodeName   string
 	JSONOutput bool
+	Timeout    time.Duration
+
+	// NoCIAnnotations keeps jobs from being annotated with the CI pipeline
+	// rnx runs in (--no-ci-annotations)
+	NoCIAnnotations bool
 )
 
-// NewJobClient creates a client based on configuration
+var (
+	poolOnce sync.Once
+	pool     *client.Pool
+)
+
+// NewJobClient creates a client based on configuration. Clients for the same
+// node share one connection for the life of the command; closing a client is
+// harmless and CloseClients releases the connections on exit.
 func NewJobClient() (*client.JobClient, error) {
+	return NewJobClientForNode(NodeName)
+}
+
+// NewJobClientForNode creates a client for a named node rather than the one
+// selected with --node.
+func NewJobClientForNode(nodeName string) (*client.JobClient, error) {
 	// NodeConfig should be loaded by PersistentPreRun
 	if NodeConfig == nil {
 		return nil, fmt.Errorf("no configuration loaded - this should not happen")
 	}
 
 	// Get the specified node
-	node, err := NodeConfig.GetNode(NodeName)
+	node, err := NodeConfig.GetNode(nodeName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node configuration for '%s': %w", NodeName, err)
+		return nil, fmt.Errorf("failed to get node configuration for '%s': %w", nodeName, err)
 	}
 
+	poolOnce.Do(func() {
+		opts := client.DefaultOptions()
+		opts.Timeout = Timeout
+		pool = client.NewPool(opts)
+	})
+
 	// Create client directly from node (no more file path handling needed)
-	return client.NewJobClient(node)
+	return pool.Get(node)
+}
+
+// CloseClients closes the connections opened by NewJobClient.
+func CloseClients() {
+	if pool != nil {
+		_ = pool.Close()
+	}
 }
@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/client"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// withArray returns a copy of the environment map carrying the job array
+// spec as a reserved key (the server strips it before execution)
+func withArray(environment map[string]string, spec string) map[string]string {
+	if spec == "" {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvArray] = spec
+	return result
+}
+
+// runJobArray submits a job array and shows the array UUID its jobs are
+// followed and stopped by
+func runJobArray(ctx context.Context, jobClient *client.JobClient, request *pb.RunJobRequest, schedule string) error {
+	response, size, err := jobClient.RunJobArray(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to run job array: %v", err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(struct {
+			ArrayUUID     string `json:"array_uuid"`
+			Jobs          int    `json:"jobs"`
+			Status        string `json:"status"`
+			ScheduleInput string `json:"schedule_input,omitempty"`
+		}{response.JobUuid, size, response.Status, schedule})
+	}
+
+	fmt.Printf("Job array started:\n")
+	fmt.Printf("Array ID: %s\n", response.JobUuid)
+	fmt.Printf("Jobs: %d\n", size)
+	fmt.Printf("Command: %s %s\n", request.Command, strings.Join(request.Args, " "))
+	if schedule != "" {
+		fmt.Printf("Schedule Input: %s\n", schedule)
+	}
+	fmt.Printf("Status: rnx job status %s\n", response.JobUuid)
+	fmt.Printf("Stop: rnx job stop %s\n", response.JobUuid)
+	return nil
+}
+
+// findJobArray looks up id as a job array after a job lookup returned
+// lookupErr. It returns nil when lookupErr is not NotFound or no array
+// matches either, so the caller reports its own error.
+func findJobArray(ctx context.Context, jobClient *client.JobClient, id string, lookupErr error) *jobspb.JobArray {
+	if status.Code(lookupErr) != codes.NotFound {
+		return nil
+	}
+	array, err := jobClient.GetJobArray(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return array
+}
+
+// printJobArray shows the aggregated status of a job array and its jobs
+func printJobArray(array *jobspb.JobArray) error {
+	if common.JSONOutput {
+		return printRegistryJSON(array)
+	}
+
+	fmt.Printf("Job Array: %s\n", array.Uuid)
+	fmt.Printf("Jobs: %d\n", array.Size)
+	fmt.Printf("Status: %s\n", formatStatusCounts(array.StatusCounts))
+	fmt.Printf("\n%6s  %-36s  %-10s  %s\n", "INDEX", "JOB UUID", "STATUS", "EXIT")
+	for _, task := range array.Tasks {
+		statusColor, resetColor := getStatusColor(task.Status)
+		fmt.Printf("%6d  %-36s  %s%-10s%s  %s\n", task.Index, task.JobUuid, statusColor, task.Status, resetColor, arrayTaskExit(task))
+	}
+
+	if active := array.StatusCounts["RUNNING"] + array.StatusCounts["SCHEDULED"] + array.StatusCounts["QUEUED"]; active > 0 {
+		fmt.Printf("\nStop the array with 'rnx job stop %s'\n", array.Uuid)
+	}
+	return nil
+}
+
+// arrayTaskExit shows the exit code of a job that ended, "-" otherwise
+func arrayTaskExit(task *jobspb.JobArrayTask) string {
+	switch strings.ToUpper(task.Status) {
+	case "COMPLETED", "FAILED", "STOPPED":
+		return fmt.Sprintf("%d", task.ExitCode)
+	}
+	return "-"
+}
+
+// runStopArray stops every running, scheduled and queued job of a job array
+// with one call
+func runStopArray(ctx context.Context, jobClient *client.JobClient, array *jobspb.JobArray) error {
+	response, err := jobClient.StopAllJobs(ctx, &jobspb.StopAllJobsRequest{ArrayUuid: array.Uuid})
+	if err != nil {
+		return fmt.Errorf("couldn't stop job array %s: %v", array.Uuid, err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(response)
+	}
+
+	fmt.Printf("Job array %s: %d stopped, %d already finished, %d failed\n", array.Uuid, len(response.Stopped), response.Skipped, len(response.Failed))
+	for _, failure := range response.Failed {
+		fmt.Printf("  %s: %s\n", failure.JobUuid, failure.Error)
+	}
+	if len(response.Failed) > 0 {
+		return fmt.Errorf("%d job(s) of array %s could not be stopped", len(response.Failed), array.Uuid)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithArray(t *testing.T) {
+	env := map[string]string{"FOO": "bar"}
+	if got := withArray(env, ""); !reflect.DeepEqual(got, env) {
+		t.Errorf("withArray without spec changed env: %v", got)
+	}
+
+	got := withArray(env, "0-99")
+	if got[constants.EnvArray] != "0-99" || got["FOO"] != "bar" {
+		t.Errorf("withArray = %v", got)
+	}
+	if _, exists := env[constants.EnvArray]; exists {
+		t.Error("withArray modified the original environment")
+	}
+}
+
+func TestArrayTaskExit(t *testing.T) {
+	tests := []struct {
+		task *jobspb.JobArrayTask
+		want string
+	}{
+		{&jobspb.JobArrayTask{Status: "COMPLETED"}, "0"},
+		{&jobspb.JobArrayTask{Status: "FAILED", ExitCode: 3}, "3"},
+		{&jobspb.JobArrayTask{Status: "RUNNING"}, "-"},
+		{&jobspb.JobArrayTask{Status: "SCHEDULED"}, "-"},
+	}
+	for _, tt := range tests {
+		if got := arrayTaskExit(tt.task); got != tt.want {
+			t.Errorf("arrayTaskExit(%s) = %q, want %q", tt.task.Status, got, tt.want)
+		}
+	}
+}
+
+func TestFindJobArrayNeedsNotFound(t *testing.T) {
+	// Errors other than NotFound are reported as they are, without asking
+	// the server for an array
+	for _, err := range []error{errors.New("connection refused"), status.Error(codes.PermissionDenied, "denied")} {
+		if array := findJobArray(context.Background(), nil, "a1b2c3d4", err); array != nil {
+			t.Errorf("findJobArray(%v) = %v, want nil", err, array)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	backuppb "github.com/ehsaniara/joblet/internal/proto/gen/backup"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// backupFormatVersion is the version of the dumps rnx writes
+const backupFormatVersion = 1
+
+// importBatchSize bounds the records sent in one import request, keeping the
+// requests under the gRPC message size limit
+const importBatchSize = 200
+
+// BackupOptions select the records ExportBackup writes
+type BackupOptions struct {
+	Since     string // RFC 3339; only records created from then on
+	EndedOnly bool   // Leave out jobs and workflows that have not ended
+}
+
+// ExportBackup writes the job and workflow records of the node to path, or
+// to stdout for "-", as protobuf JSON
+func ExportBackup(path string, opts BackupOptions) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dump := &backuppb.Dump{Version: backupFormatVersion, ExportedAt: time.Now().UTC().Format(time.RFC3339)}
+	if node, err := common.NodeConfig.GetNode(common.NodeName); err == nil {
+		dump.NodeId = node.NodeId
+	}
+
+	jobStream, err := jobClient.ExportJobs(ctx, &backuppb.ExportJobsRequest{Since: opts.Since, EndedOnly: opts.EndedOnly})
+	if err != nil {
+		return backupError("export jobs", err)
+	}
+	for {
+		job, err := jobStream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return backupError("export jobs", err)
+		}
+		dump.Jobs = append(dump.Jobs, job)
+	}
+
+	workflowStream, err := jobClient.ExportWorkflows(ctx, &backuppb.ExportWorkflowsRequest{Since: opts.Since, EndedOnly: opts.EndedOnly})
+	if err != nil {
+		return backupError("export workflows", err)
+	}
+	for {
+		wf, err := workflowStream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return backupError("export workflows", err)
+		}
+		dump.Workflows = append(dump.Workflows, wf)
+	}
+
+	if err := writeDump(path, dump); err != nil {
+		return err
+	}
+	if path == "-" {
+		return nil
+	}
+	if common.JSONOutput {
+		return printRegistryJSON(map[string]interface{}{"file": path, "jobs": len(dump.Jobs), "workflows": len(dump.Workflows)})
+	}
+	fmt.Printf("Exported %d job(s) and %d workflow(s) to %s\n", len(dump.Jobs), len(dump.Workflows), path)
+	return nil
+}
+
+// ImportBackup loads the records of a dump written by ExportBackup into the
+// node: jobs first, then the workflows that ran them
+func ImportBackup(path string) error {
+	dump, err := readDump(path)
+	if err != nil {
+		return err
+	}
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	jobsResult := &backuppb.ImportResult{}
+	for _, batch := range batches(dump.Jobs, importBatchSize) {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		result, err := jobClient.ImportJobs(ctx, batch)
+		cancel()
+		if err != nil {
+			return backupError("import jobs", err)
+		}
+		jobsResult.Imported += result.Imported
+		jobsResult.Skipped = append(jobsResult.Skipped, result.Skipped...)
+	}
+
+	workflowsResult := &backuppb.ImportResult{}
+	for _, batch := range batches(dump.Workflows, importBatchSize) {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		result, err := jobClient.ImportWorkflows(ctx, batch)
+		cancel()
+		if err != nil {
+			return backupError("import workflows", err)
+		}
+		workflowsResult.Imported += result.Imported
+		workflowsResult.Skipped = append(workflowsResult.Skipped, result.Skipped...)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(map[string]interface{}{"jobs": jobsResult, "workflows": workflowsResult})
+	}
+	printImportResult(os.Stdout, "job", len(dump.Jobs), jobsResult)
+	printImportResult(os.Stdout, "workflow", len(dump.Workflows), workflowsResult)
+	return nil
+}
+
+// printImportResult reports how many of total records were imported and why
+// the others were not
+func printImportResult(w io.Writer, kind string, total int, result *backuppb.ImportResult) {
+	fmt.Fprintf(w, "Imported %d of %d %s(s)\n", result.Imported, total, kind)
+	if len(result.Skipped) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(tw, "  SKIPPED\t%s\t%s\n", skipped.Uuid, skipped.Reason)
+	}
+	_ = tw.Flush()
+}
+
+// writeDump writes a dump as indented protobuf JSON. The file is private to
+// the user since it holds the specs of every job.
+func writeDump(path string, dump *backuppb.Dump) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(dump)
+	if err != nil {
+		return fmt.Errorf("failed to encode dump: %w", err)
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write dump: %w", err)
+	}
+	return nil
+}
+
+// readDump reads a dump from path, or from stdin for "-"
+func readDump(path string) (*backuppb.Dump, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+
+	dump := &backuppb.Dump{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, dump); err != nil {
+		return nil, fmt.Errorf("invalid dump %s: %w", path, err)
+	}
+	if dump.Version > backupFormatVersion {
+		return nil, fmt.Errorf("dump %s has format version %d, this rnx reads up to %d; upgrade rnx", path, dump.Version, backupFormatVersion)
+	}
+	return dump, nil
+}
+
+// batches splits records into runs of at most size
+func batches[T any](records []T, size int) [][]T {
+	var result [][]T
+	for len(records) > size {
+		result = append(result, records[:size])
+		records = records[size:]
+	}
+	if len(records) > 0 {
+		result = append(result, records)
+	}
+	return result
+}
+
+// backupError explains a failed backup request, pointing at old servers
+func backupError(action string, err error) error {
+	switch status.Code(err) {
+	case codes.Unimplemented:
+		return fmt.Errorf("this server does not support backups; upgrade the joblet server")
+	case codes.PermissionDenied:
+		return fmt.Errorf("couldn't %s: backups need an admin certificate: %v", action, err)
+	}
+	return fmt.Errorf("couldn't %s: %v", action, err)
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	backuppb "github.com/ehsaniara/joblet/internal/proto/gen/backup"
+)
+
+func TestDumpRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.json")
+	dump := &backuppb.Dump{
+		Version: backupFormatVersion,
+		NodeId:  "node-1",
+		Jobs: []*backuppb.ExportedJob{{
+			Uuid:        "uuid-train",
+			Status:      "COMPLETED",
+			Annotations: map[string]string{"ci.pipeline": "42"},
+		}},
+		Workflows: []*backuppb.ExportedWorkflow{{Uuid: "uuid-pipeline", Status: "COMPLETED"}},
+	}
+	if err := writeDump(path, dump); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("dump mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	read, err := readDump(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.NodeId != "node-1" || len(read.Jobs) != 1 || read.Jobs[0].Annotations["ci.pipeline"] != "42" || len(read.Workflows) != 1 {
+		t.Errorf("readDump() = %v", read)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 2}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readDump(path); err == nil || !strings.Contains(err.Error(), "upgrade rnx") {
+		t.Errorf("readDump() of a newer dump = %v", err)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	records := []int{1, 2, 3, 4, 5}
+	got := batches(records, 2)
+	if len(got) != 3 || len(got[2]) != 1 || got[2][0] != 5 {
+		t.Errorf("batches() = %v", got)
+	}
+	if got := batches([]int{}, 2); len(got) != 0 {
+		t.Errorf("batches(empty) = %v", got)
+	}
+}
+
+func TestPrintImportResult(t *testing.T) {
+	var buf bytes.Buffer
+	printImportResult(&buf, "job", 3, &backuppb.ImportResult{
+		Imported: 2,
+		Skipped:  []*backuppb.SkippedRecord{{Uuid: "uuid-serve", Reason: "job is RUNNING, only ended jobs are imported"}},
+	})
+	out := buf.String()
+	if !strings.Contains(out, "Imported 2 of 3 job(s)") || !strings.Contains(out, "uuid-serve") {
+		t.Errorf("printImportResult() = %q", out)
+	}
+}
@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/internal/rnx/placement"
+
+	"github.com/spf13/cobra"
+)
+
+// capacityTimeout bounds each GetNodeCapacity call so one unreachable node
+// does not hold up placement.
+const capacityTimeout = 5 * time.Second
+
+func NewMonitorCapacityCmd() *cobra.Command {
+	var allNodes bool
+
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Show schedulable CPU, memory, GPUs and volume disk",
+		Long: `Show how much of a joblet server is still free for new jobs.
+
+For CPU, memory, GPUs and volume disk the server reports:
+- total:       what the host has (CPU in percent, 100 = one core)
+- reserved:    held back for the host by the server's capacity config
+- allocated:   claimed by the limits of active jobs, or by volume sizes
+- schedulable: what is left for new jobs
+
+Jobs without a CPU or memory limit claim nothing and are counted as unbounded.
+
+With --all every node in rnx-config.yml is queried, and the node that
+'rnx job run --node=auto' would pick for a job without limits is marked.
+
+Examples:
+  rnx monitor capacity                  # Capacity of the current node
+  rnx monitor capacity --all            # Compare all configured nodes
+  rnx monitor capacity --all --json     # JSON for scripts`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMonitorCapacity(allNodes, common.JSONOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&allNodes, "all", false, "Query every node in the configuration")
+
+	return cmd
+}
+
+type resourceCapacityJSON struct {
+	Total       int64 `json:"total"`
+	Reserved    int64 `json:"reserved"`
+	Allocated   int64 `json:"allocated"`
+	Schedulable int64 `json:"schedulable"`
+}
+
+type nodeCapacityJSON struct {
+	Node          string                `json:"node"`
+	NodeId        string                `json:"nodeId,omitempty"`
+	Hostname      string                `json:"hostname,omitempty"`
+	Error         string                `json:"error,omitempty"`
+	CPUPercent    *resourceCapacityJSON `json:"cpuPercent,omitempty"`
+	MemoryMB      *resourceCapacityJSON `json:"memoryMB,omitempty"`
+	GPUs          *resourceCapacityJSON `json:"gpus,omitempty"`
+	VolumeDisk    *resourceCapacityJSON `json:"volumeDiskBytes,omitempty"`
+	ActiveJobs    int32                 `json:"activeJobs"`
+	UnboundedJobs int32                 `json:"unboundedJobs"`
+	Preferred     bool                  `json:"preferred,omitempty"`
+}
+
+func runMonitorCapacity(allNodes, jsonOutput bool) error {
+	nodes := []string{common.NodeName}
+	if allNodes {
+		nodes = common.NodeConfig.ListNodes()
+		if len(nodes) == 0 {
+			return fmt.Errorf("no nodes configured in rnx-config.yml")
+		}
+	}
+
+	candidates := queryCapacities(nodes)
+	if !allNodes && candidates[0].Err != nil {
+		return fmt.Errorf("failed to get node capacity: %v", candidates[0].Err)
+	}
+
+	var preferred string
+	if allNodes {
+		preferred, _ = placement.Choose(candidates, placement.Request{})
+	}
+
+	if jsonOutput {
+		var out []nodeCapacityJSON
+		for _, c := range candidates {
+			out = append(out, capacityToJSON(c, c.Node == preferred))
+		}
+		var data []byte
+		var err error
+		if allNodes {
+			data, err = json.MarshalIndent(out, "", "  ")
+		} else {
+			data, err = json.MarshalIndent(out[0], "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for i, c := range candidates {
+		if i > 0 {
+			fmt.Println()
+		}
+		displayCapacity(c, allNodes && c.Node == preferred)
+	}
+	return nil
+}
+
+// queryCapacities asks each node for its capacity in parallel. The result is
+// sorted by node name.
+func queryCapacities(nodes []string) []placement.Candidate {
+	candidates := make([]placement.Candidate, len(nodes))
+	var wg sync.WaitGroup
+	for i, name := range nodes {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			candidates[i] = placement.Candidate{Node: name}
+
+			jobClient, err := common.NewJobClientForNode(name)
+			if err != nil {
+				candidates[i].Err = err
+				return
+			}
+			defer jobClient.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), capacityTimeout)
+			defer cancel()
+			candidates[i].Capacity, candidates[i].Err = jobClient.GetNodeCapacity(ctx)
+		}(i, name)
+	}
+	wg.Wait()
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Node < candidates[j].Node })
+	return candidates
+}
+
+// placeJob resolves --node=auto to the node with the most room for req.
+func placeJob(req placement.Request) (string, error) {
+	nodes := common.NodeConfig.ListNodes()
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("no nodes configured in rnx-config.yml")
+	}
+	return placement.Choose(queryCapacities(nodes), req)
+}
+
+func capacityToJSON(c placement.Candidate, preferred bool) nodeCapacityJSON {
+	out := nodeCapacityJSON{Node: c.Node, Preferred: preferred}
+	if c.Err != nil {
+		out.Error = c.Err.Error()
+		return out
+	}
+	convert := func(r *capacitypb.ResourceCapacity) *resourceCapacityJSON {
+		return &resourceCapacityJSON{
+			Total:       r.GetTotal(),
+			Reserved:    r.GetReserved(),
+			Allocated:   r.GetAllocated(),
+			Schedulable: r.GetSchedulable(),
+		}
+	}
+	out.NodeId = c.Capacity.NodeId
+	out.Hostname = c.Capacity.Hostname
+	out.CPUPercent = convert(c.Capacity.Cpu)
+	out.MemoryMB = convert(c.Capacity.Memory)
+	out.GPUs = convert(c.Capacity.Gpus)
+	out.VolumeDisk = convert(c.Capacity.VolumeDisk)
+	out.ActiveJobs = c.Capacity.ActiveJobs
+	out.UnboundedJobs = c.Capacity.UnboundedJobs
+	return out
+}
+
+func displayCapacity(c placement.Candidate, preferred bool) {
+	title := fmt.Sprintf("Node %s", c.Node)
+	if c.Capacity != nil && c.Capacity.Hostname != "" {
+		title += fmt.Sprintf(" (%s)", c.Capacity.Hostname)
+	}
+	if preferred {
+		title += " - preferred for --node=auto"
+	}
+	fmt.Println(title)
+
+	if c.Err != nil {
+		fmt.Printf("  Unavailable: %v\n", c.Err)
+		return
+	}
+
+	capacity := c.Capacity
+	fmt.Printf("  %-12s %12s %12s %12s %12s\n", "RESOURCE", "TOTAL", "RESERVED", "ALLOCATED", "SCHEDULABLE")
+	row := func(name string, r *capacitypb.ResourceCapacity, format func(int64) string) {
+		fmt.Printf("  %-12s %12s %12s %12s %12s\n", name,
+			format(r.GetTotal()), format(r.GetReserved()), format(r.GetAllocated()), format(r.GetSchedulable()))
+	}
+	row("CPU", capacity.Cpu, func(v int64) string { return fmt.Sprintf("%d%%", v) })
+	row("Memory", capacity.Memory, func(v int64) string { return formatBytes(v * 1024 * 1024) })
+	row("GPUs", capacity.Gpus, func(v int64) string { return fmt.Sprintf("%d", v) })
+	row("Volume disk", capacity.VolumeDisk, formatBytes)
+	fmt.Printf("  Active jobs: %d (%d without CPU or memory limits)\n", capacity.ActiveJobs, capacity.UnboundedJobs)
+}
@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// noCIAnnotationsEnv turns off the CI annotations for every rnx command of a
+// pipeline, like --no-ci-annotations does for one
+const noCIAnnotationsEnv = "RNX_NO_CI_ANNOTATIONS"
+
+// ciProvider maps the environment of a CI system to job annotations
+type ciProvider struct {
+	name   string
+	detect string            // Variable set by the CI system
+	vars   map[string]string // Annotation key -> variable
+	url    func(getenv func(string) string) string
+}
+
+var ciProviders = []ciProvider{
+	{
+		name:   "github-actions",
+		detect: "GITHUB_ACTIONS",
+		vars: map[string]string{
+			"ci.pipeline":    "GITHUB_WORKFLOW",
+			"ci.run_id":      "GITHUB_RUN_ID",
+			"ci.run_attempt": "GITHUB_RUN_ATTEMPT",
+			"ci.job":         "GITHUB_JOB",
+			"ci.repository":  "GITHUB_REPOSITORY",
+			"ci.ref":         "GITHUB_REF",
+			"git.sha":        "GITHUB_SHA",
+		},
+		url: func(getenv func(string) string) string {
+			server, repo, run := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID")
+			if server == "" || repo == "" || run == "" {
+				return ""
+			}
+			return server + "/" + repo + "/actions/runs/" + run
+		},
+	},
+	{
+		name:   "gitlab",
+		detect: "GITLAB_CI",
+		vars: map[string]string{
+			"ci.run_id":     "CI_PIPELINE_ID",
+			"ci.job":        "CI_JOB_NAME",
+			"ci.job_id":     "CI_JOB_ID",
+			"ci.repository": "CI_PROJECT_PATH",
+			"ci.ref":        "CI_COMMIT_REF_NAME",
+			"ci.url":        "CI_PIPELINE_URL",
+			"git.sha":       "CI_COMMIT_SHA",
+		},
+	},
+	{
+		name:   "jenkins",
+		detect: "JENKINS_URL",
+		vars: map[string]string{
+			"ci.pipeline": "JOB_NAME",
+			"ci.run_id":   "BUILD_NUMBER",
+			"ci.url":      "BUILD_URL",
+			"ci.ref":      "GIT_BRANCH",
+			"git.sha":     "GIT_COMMIT",
+		},
+	},
+	{
+		name:   "circleci",
+		detect: "CIRCLECI",
+		vars: map[string]string{
+			"ci.pipeline":   "CIRCLE_WORKFLOW_ID",
+			"ci.run_id":     "CIRCLE_BUILD_NUM",
+			"ci.job":        "CIRCLE_JOB",
+			"ci.repository": "CIRCLE_PROJECT_REPONAME",
+			"ci.ref":        "CIRCLE_BRANCH",
+			"ci.url":        "CIRCLE_BUILD_URL",
+			"git.sha":       "CIRCLE_SHA1",
+		},
+	},
+	{
+		name:   "buildkite",
+		detect: "BUILDKITE",
+		vars: map[string]string{
+			"ci.pipeline": "BUILDKITE_PIPELINE_SLUG",
+			"ci.run_id":   "BUILDKITE_BUILD_NUMBER",
+			"ci.job":      "BUILDKITE_LABEL",
+			"ci.ref":      "BUILDKITE_BRANCH",
+			"ci.url":      "BUILDKITE_BUILD_URL",
+			"git.sha":     "BUILDKITE_COMMIT",
+		},
+	},
+}
+
+// detectCIAnnotations returns annotations linking a job to the CI pipeline
+// rnx runs in, nil outside of a known CI system or when they are turned off.
+// Values that are not valid annotations are left out.
+func detectCIAnnotations(getenv func(string) string) map[string]string {
+	if common.NoCIAnnotations || isTrue(getenv(noCIAnnotationsEnv)) {
+		return nil
+	}
+	for _, provider := range ciProviders {
+		if getenv(provider.detect) == "" || getenv(provider.detect) == "false" {
+			continue
+		}
+		annotations := map[string]string{"ci.provider": provider.name}
+		add := func(key, value string) {
+			if value != "" && values.ValidateAnnotation(key, value) == nil {
+				annotations[key] = value
+			}
+		}
+		for key, name := range provider.vars {
+			add(key, getenv(name))
+		}
+		if provider.url != nil {
+			add("ci.url", provider.url(getenv))
+		}
+		return annotations
+	}
+	return nil
+}
+
+// isTrue reports whether a switch variable is set to a true value
+func isTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// withAnnotations returns a copy of the environment map carrying the job
+// annotations as a reserved key (the server strips it before execution). The
+// annotations given win over those detected from the CI environment.
+func withAnnotations(environment map[string]string, annotations map[string]string) map[string]string {
+	merged := detectCIAnnotations(os.Getenv)
+	if len(merged) == 0 && len(annotations) == 0 {
+		return environment
+	}
+	if merged == nil {
+		merged = make(map[string]string, len(annotations))
+	}
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvAnnotations] = values.FormatAnnotations(merged)
+	return result
+}
+
+// withCIAnnotations adds the annotations of the CI pipeline rnx runs in to a
+// RunWorkflow request, for every job of the workflow
+func withCIAnnotations(ctx context.Context) context.Context {
+	annotations := detectCIAnnotations(os.Getenv)
+	if len(annotations) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, constants.AnnotationsHeader, values.FormatAnnotations(annotations))
+}
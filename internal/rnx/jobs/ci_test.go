@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/constants"
+)
+
+func TestDetectCIAnnotations(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want map[string]string
+	}{
+		{
+			name: "github actions",
+			env: map[string]string{
+				"GITHUB_ACTIONS": "true", "GITHUB_RUN_ID": "9876", "GITHUB_WORKFLOW": "nightly",
+				"GITHUB_REPOSITORY": "acme/etl", "GITHUB_SERVER_URL": "https://github.com", "GITHUB_SHA": "4f2c1e9",
+			},
+			want: map[string]string{
+				"ci.provider": "github-actions", "ci.run_id": "9876", "ci.pipeline": "nightly",
+				"ci.repository": "acme/etl", "ci.url": "https://github.com/acme/etl/actions/runs/9876", "git.sha": "4f2c1e9",
+			},
+		},
+		{
+			name: "gitlab",
+			env: map[string]string{
+				"GITLAB_CI": "true", "CI_PIPELINE_ID": "1234", "CI_JOB_ID": "5678",
+				"CI_PIPELINE_URL": "https://gitlab.com/acme/etl/-/pipelines/1234", "CI_COMMIT_SHA": "a1b2c3",
+			},
+			want: map[string]string{
+				"ci.provider": "gitlab", "ci.run_id": "1234", "ci.job_id": "5678",
+				"ci.url": "https://gitlab.com/acme/etl/-/pipelines/1234", "git.sha": "a1b2c3",
+			},
+		},
+		{
+			name: "invalid values are left out",
+			env:  map[string]string{"JENKINS_URL": "https://ci", "BUILD_NUMBER": "7", "JOB_NAME": "etl\nnightly"},
+			want: map[string]string{"ci.provider": "jenkins", "ci.run_id": "7"},
+		},
+		{
+			name: "outside of ci",
+			env:  map[string]string{"CI": "true", "GITHUB_ACTIONS": "false"},
+		},
+		{
+			name: "turned off",
+			env:  map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_RUN_ID": "9876", noCIAnnotationsEnv: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectCIAnnotations(func(name string) string { return tt.env[name] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectCIAnnotations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAnnotations(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_RUN_ID", "9876")
+	t.Setenv("GITLAB_CI", "")
+	t.Setenv("JENKINS_URL", "")
+
+	env := withAnnotations(map[string]string{"FOO": "bar"}, map[string]string{"ci.run_id": "override", "ticket": "OPS-42"})
+	annotations, err := values.ParseAnnotations(env[constants.EnvAnnotations])
+	if err != nil || env["FOO"] != "bar" {
+		t.Fatalf("environment = %v, %v", env, err)
+	}
+	if annotations["ci.provider"] != "github-actions" || annotations["ci.run_id"] != "override" || annotations["ticket"] != "OPS-42" {
+		t.Errorf("annotations = %v", annotations)
+	}
+
+	common.NoCIAnnotations = true
+	defer func() { common.NoCIAnnotations = false }()
+	if env := withAnnotations(map[string]string{"FOO": "bar"}, nil); len(env) != 1 {
+		t.Errorf("--no-ci-annotations still annotated: %v", env)
+	}
+}
@@ -16,9 +16,9 @@ func getStatusColor(status string) (string, string) {
 		statusColor = "\033[35m" // Magenta
 	case "INITIALIZING":
 		statusColor = "\033[34m" // Blue
-	case "PENDING":
+	case "PENDING", "QUEUED":
 		statusColor = "\033[36m" // Cyan
-	case "CANCELED":
+	case "CANCELED", "EXPIRED":
 		statusColor = "\033[35m" // Magenta
 	default:
 		statusColor = ""
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
 
 	"github.com/spf13/cobra"
@@ -40,8 +41,9 @@ Examples:
   rnx job delete f47ac10b
 
 Warning: This can't be undone! The job and its logs will be gone forever.`,
-		Args: cobra.ExactArgs(1),
-		RunE: runDelete,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeIDs(idspb.IdType_ID_TYPE_JOB),
+		RunE:              runDelete,
 	}
 
 	return cmd
@@ -59,6 +61,11 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	}
 	defer jobClient.Close()
 
+	jobID, err = resolveID(jobClient, jobID, idspb.IdType_ID_TYPE_JOB)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jobenvpb "github.com/ehsaniara/joblet/internal/proto/gen/jobenv"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NewExportEnvCmd creates the command exporting a job's environment as a
+// Dockerfile or an OCI bundle
+func NewExportEnvCmd() *cobra.Command {
+	var (
+		format string
+		output string
+	)
+	cmd := &cobra.Command{
+		Use:   "export-env <job-uuid>",
+		Short: "Export a job's environment as a Dockerfile or OCI bundle",
+		Long: `Export the environment a job ran in, to reproduce it locally when debugging.
+
+The Dockerfile format starts from the image of the node's distro, copies the
+runtime's mounts in as layers and sets the job's environment variables,
+working directory and command. Its header shows the docker build and run
+commands, with the job's resource limits, volumes and secrets as flags.
+
+The oci format writes an OCI runtime bundle to run with runc on the node the
+job ran on: a config.json mounting the same host directories, runtime and
+volumes, with the job's limits.
+
+Uploaded files the server still holds are written under work/. Secret
+environment variables are named but never exported with their values.
+
+Examples:
+  # Dockerfile and uploads under ./job-f47ac10b-env
+  rnx job export-env f47ac10b
+
+  # OCI bundle in a chosen directory
+  rnx job export-env f47ac10b --format oci -o ./bundle
+
+  # Print the Dockerfile only
+  rnx job export-env f47ac10b -o -`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExportEnv(args[0], format, output)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "dockerfile", "Export format: dockerfile or oci")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Directory to write to, - prints the Dockerfile or config.json (default ./job-<uuid>-env)")
+	return cmd
+}
+
+func runExportEnv(jobID, format, output string) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := jobClient.ExportJobEnvironment(ctx, jobID, format)
+	if err != nil {
+		return fmt.Errorf("couldn't export the job environment: %v", err)
+	}
+
+	if output == "-" {
+		if len(resp.Files) == 0 {
+			return fmt.Errorf("the server exported no files")
+		}
+		_, err := os.Stdout.Write(resp.Files[0].Content)
+		for _, note := range resp.Notes {
+			fmt.Fprintf(os.Stderr, "Note: %s\n", note)
+		}
+		return err
+	}
+
+	if output == "" {
+		id, _, _ := strings.Cut(resp.JobUuid, "-")
+		output = "job-" + id + "-env"
+	}
+	written, err := writeExportedFiles(output, resp.Files)
+	if err != nil {
+		return err
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(map[string]interface{}{
+			"job_id": resp.JobUuid,
+			"format": resp.Format,
+			"dir":    output,
+			"files":  written,
+			"notes":  resp.Notes,
+		})
+	}
+	fmt.Printf("Exported the environment of job %s as %s to %s/\n", resp.JobUuid, resp.Format, output)
+	for _, path := range written {
+		fmt.Printf("  %s\n", path)
+	}
+	for _, note := range resp.Notes {
+		fmt.Printf("Note: %s\n", note)
+	}
+	return nil
+}
+
+// writeExportedFiles writes the files of an export under dir, refusing paths
+// that leave it
+func writeExportedFiles(dir string, files []*jobenvpb.ExportedFile) ([]string, error) {
+	written := make([]string, 0, len(files))
+	for _, f := range files {
+		if !filepath.IsLocal(f.Path) {
+			return written, fmt.Errorf("refusing to write %q outside %s", f.Path, dir)
+		}
+		path := filepath.Join(dir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("couldn't create %s: %w", filepath.Dir(path), err)
+		}
+		mode := fs.FileMode(f.Mode) & fs.ModePerm
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := os.WriteFile(path, f.Content, mode); err != nil {
+			return written, fmt.Errorf("couldn't write %s: %w", path, err)
+		}
+		written = append(written, f.Path)
+	}
+	return written, nil
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	jobenvpb "github.com/ehsaniara/joblet/internal/proto/gen/jobenv"
+)
+
+func TestWriteExportedFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "job-f47ac10b-env")
+	written, err := writeExportedFiles(dir, []*jobenvpb.ExportedFile{
+		{Path: "Dockerfile", Content: []byte("FROM ubuntu:22.04\n"), Mode: 0644},
+		{Path: "work/bin/run.sh", Content: []byte("#!/bin/sh\n"), Mode: 0755},
+	})
+	if err != nil || len(written) != 2 {
+		t.Fatalf("writeExportedFiles() = %v, %v", written, err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "work", "bin", "run.sh"))
+	if err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("run.sh = %v, %v, want mode 0755", info, err)
+	}
+
+	for _, path := range []string{"../escape", "/etc/passwd"} {
+		if _, err := writeExportedFiles(dir, []*jobenvpb.ExportedFile{{Path: path}}); err == nil {
+			t.Errorf("writeExportedFiles(%s) wrote outside the directory", path)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	jobfspb "github.com/ehsaniara/joblet/internal/proto/gen/jobfs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NewFreezeFSCmd creates the command arming a job so its filesystem is kept
+// if it fails
+func NewFreezeFSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "freeze-fs <job-uuid>",
+		Short: "Keep a job's filesystem for inspection if it fails",
+		Long: `Keep a job's filesystem for inspection if it fails.
+
+A failed job's directories are normally removed as soon as it ends. Once a
+job is armed with freeze-fs, a failure keeps its root, /tmp and scratch work
+directory instead, so 'rnx job fs ls' and 'rnx job fs cat' can show what it
+actually produced. The server removes them after its retention period
+(filesystem.freezeRetention, 24h by default). Jobs that complete or are
+stopped are cleaned up as usual.
+
+Arm jobs before they end. A running job's /work is usually a small RAM-backed
+directory that goes away with the job; run jobs with 'rnx job run
+--freeze-fs' to keep it on disk from the start.
+
+Examples:
+  # Keep the filesystem of a running job if it fails
+  rnx job freeze-fs f47ac10b
+
+  # Arm at submission, then browse after the failure
+  rnx job run --freeze-fs python3 train.py
+  rnx job fs ls f47ac10b /work`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFreezeFS(args[0])
+		},
+	}
+	return cmd
+}
+
+// NewFSCmd creates the command browsing the frozen filesystem of failed jobs
+func NewFSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fs",
+		Short: "Browse the filesystem kept from a failed job",
+		Long: `Browse the filesystem kept from a failed job armed with 'rnx job freeze-fs'
+or 'rnx job run --freeze-fs'. Paths are the ones the job saw, such as
+/work/out or /tmp.`,
+	}
+	cmd.AddCommand(newFSListCmd())
+	cmd.AddCommand(newFSCatCmd())
+	return cmd
+}
+
+func newFSListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls <job-uuid> [path]",
+		Short: "List a directory of a failed job's filesystem",
+		Long: `List a directory of a failed job's filesystem (default /work).
+
+Examples:
+  rnx job fs ls f47ac10b
+  rnx job fs ls f47ac10b /tmp`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "/work"
+			if len(args) == 2 {
+				path = args[1]
+			}
+			return runFSList(args[0], path)
+		},
+	}
+	return cmd
+}
+
+func newFSCatCmd() *cobra.Command {
+	var (
+		offset int64
+		limit  int64
+	)
+	cmd := &cobra.Command{
+		Use:   "cat <job-uuid> <path>",
+		Short: "Print a file of a failed job's filesystem",
+		Long: `Print a file of a failed job's filesystem to stdout.
+
+Examples:
+  rnx job fs cat f47ac10b /work/out/result.csv
+  rnx job fs cat f47ac10b /tmp/debug.log --offset 1048576 --limit 4096
+  rnx job fs cat f47ac10b /work/model.ckpt > model.ckpt`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFSCat(args[0], args[1], offset, limit)
+		},
+	}
+	cmd.Flags().Int64Var(&offset, "offset", 0, "First byte to print")
+	cmd.Flags().Int64Var(&limit, "limit", 0, "Bytes to print at most (0 = to the end of the file)")
+	return cmd
+}
+
+func runFreezeFS(jobID string) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := jobClient.FreezeJobFilesystem(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("couldn't freeze the job filesystem: %v", err)
+	}
+	retention := time.Duration(resp.Retention)
+
+	if common.JSONOutput {
+		result := map[string]interface{}{
+			"job_id":            jobID,
+			"frozen":            resp.Frozen,
+			"retention_seconds": int64(retention.Seconds()),
+		}
+		if resp.Frozen {
+			result["expires_at"] = time.Unix(0, resp.ExpiresAt).UTC().Format(time.RFC3339)
+		}
+		return printRegistryJSON(result)
+	}
+	if resp.Frozen {
+		fmt.Printf("Job %s already failed, its filesystem is kept until %s\n", jobID, time.Unix(0, resp.ExpiresAt).Format(time.RFC3339))
+		return nil
+	}
+	fmt.Printf("Job %s is armed: if it fails, its filesystem is kept for %s\n", jobID, formatDuration(retention))
+	fmt.Printf("Browse it with: rnx job fs ls %s /work\n", jobID)
+	return nil
+}
+
+func runFSList(jobID, path string) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := jobClient.ListJobFiles(ctx, jobID, path)
+	if err != nil {
+		return fmt.Errorf("couldn't list %s: %v", path, err)
+	}
+
+	if common.JSONOutput {
+		files := make([]map[string]interface{}, 0, len(resp.Files))
+		for _, f := range resp.Files {
+			files = append(files, map[string]interface{}{
+				"name":     f.Name,
+				"size":     f.Size,
+				"mode":     fs.FileMode(f.Mode).String(),
+				"is_dir":   fs.FileMode(f.Mode).IsDir(),
+				"mod_time": time.Unix(0, f.ModTime).UTC().Format(time.RFC3339),
+			})
+		}
+		return printRegistryJSON(map[string]interface{}{
+			"job_id":     jobID,
+			"path":       path,
+			"files":      files,
+			"expires_at": time.Unix(0, resp.ExpiresAt).UTC().Format(time.RFC3339),
+		})
+	}
+
+	printJobFiles(os.Stdout, resp.Files)
+	return nil
+}
+
+// printJobFiles writes a directory listing in the style of ls -l
+func printJobFiles(out io.Writer, files []*jobfspb.JobFile) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, f := range files {
+		mode := fs.FileMode(f.Mode)
+		name := f.Name
+		if mode.IsDir() {
+			name += "/"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mode, formatBytes(f.Size), time.Unix(0, f.ModTime).Format("Jan _2 15:04"), name)
+	}
+	w.Flush()
+}
+
+func runFSCat(jobID, path string, offset, limit int64) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	stream, err := jobClient.ReadJobFile(context.Background(), &jobfspb.ReadJobFileRequest{JobUuid: jobID, Path: path, Offset: offset, Limit: limit})
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %v", path, err)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't read %s: %v", path, err)
+		}
+		if _, err := os.Stdout.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
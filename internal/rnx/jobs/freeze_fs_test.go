@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"bytes"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	jobfspb "github.com/ehsaniara/joblet/internal/proto/gen/jobfs"
+)
+
+func TestPrintJobFiles(t *testing.T) {
+	modTime := time.Date(2026, 3, 2, 9, 5, 0, 0, time.Local).UnixNano()
+	var out bytes.Buffer
+	printJobFiles(&out, []*jobfspb.JobFile{
+		{Name: "out", Mode: uint32(fs.ModeDir | 0755), Size: 4096, ModTime: modTime},
+		{Name: "result.csv", Mode: 0644, Size: 2 * 1024 * 1024, ModTime: modTime},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("listing has %d lines:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[0], "drwxr-xr-x") || !strings.HasSuffix(lines[0], " out/") {
+		t.Errorf("directory line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "-rw-r--r--") || !strings.Contains(lines[1], "2.0 MB") ||
+		!strings.Contains(lines[1], "Mar  2 09:05") || !strings.HasSuffix(lines[1], " result.csv") {
+		t.Errorf("file line = %q", lines[1])
+	}
+}
@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NewGroupsCmd creates the command listing job groups
+func NewGroupsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "groups",
+		Short: "List job groups",
+		Long: `List job groups with their job counts by status.
+
+Jobs join a group with 'rnx job run --group=NAME' or the group field of a job
+spec. Workflow jobs are grouped by workflow UUID unless the workflow sets a
+top-level group.
+
+Examples:
+  rnx job groups
+  rnx job groups --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGroups()
+		},
+	}
+}
+
+func runGroups() error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := jobClient.ListJobGroups(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list job groups: %v", err)
+	}
+
+	if common.JSONOutput {
+		if response.Groups == nil {
+			response.Groups = []*jobspb.JobGroup{}
+		}
+		return printRegistryJSON(response.Groups)
+	}
+	if len(response.Groups) == 0 {
+		fmt.Println("No job groups found")
+		return nil
+	}
+
+	nameWidth := len("GROUP")
+	for _, group := range response.Groups {
+		nameWidth = max(nameWidth, len(group.Name))
+	}
+	fmt.Printf("%-*s %6s  %s\n", nameWidth, "GROUP", "JOBS", "STATUS")
+	for _, group := range response.Groups {
+		fmt.Printf("%-*s %6d  %s\n", nameWidth, group.Name, len(group.JobUuids), formatStatusCounts(group.StatusCounts))
+	}
+	return nil
+}
+
+// formatStatusCounts renders status counts as "RUNNING=3 COMPLETED=10",
+// sorted by status
+func formatStatusCounts(counts map[string]int32) string {
+	statuses := make([]string, 0, len(counts))
+	for s := range counts {
+		statuses = append(statuses, s)
+	}
+	sort.Strings(statuses)
+	parts := make([]string, len(statuses))
+	for i, s := range statuses {
+		parts[i] = fmt.Sprintf("%s=%d", s, counts[s])
+	}
+	return strings.Join(parts, " ")
+}
+
+// filterJobs keeps the jobs whose UUID is in uuids, in their original order
+func filterJobs(jobs []*pb.Job, uuids []string) []*pb.Job {
+	members := make(map[string]bool, len(uuids))
+	for _, uuid := range uuids {
+		members[uuid] = true
+	}
+	var filtered []*pb.Job
+	for _, job := range jobs {
+		if members[job.Uuid] {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
+// runStopGroup stops every running or scheduled job of a group with one call
+func runStopGroup(group string) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	response, err := jobClient.StopJobGroup(context.Background(), group)
+	if err != nil {
+		return fmt.Errorf("couldn't stop job group %s: %v", group, err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(response)
+	}
+
+	fmt.Printf("Job group %s: %d stopped, %d already finished, %d failed\n", group, len(response.Stopped), response.Skipped, len(response.Failed))
+	for _, failure := range response.Failed {
+		fmt.Printf("  %s: %s\n", failure.JobUuid, failure.Error)
+	}
+	if len(response.Failed) > 0 {
+		return fmt.Errorf("%d job(s) of group %s could not be stopped", len(response.Failed), group)
+	}
+	return nil
+}
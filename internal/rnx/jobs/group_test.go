@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/pkg/constants"
+)
+
+func TestFilterJobs(t *testing.T) {
+	jobs := []*pb.Job{{Uuid: "a"}, {Uuid: "b"}, {Uuid: "c"}, {Uuid: "d"}}
+
+	filtered := filterJobs(jobs, []string{"d", "b", "missing"})
+	var got []string
+	for _, job := range filtered {
+		got = append(got, job.Uuid)
+	}
+	if want := []string{"b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterJobs = %v, want %v in list order", got, want)
+	}
+	if filtered := filterJobs(jobs, nil); len(filtered) != 0 {
+		t.Errorf("filterJobs with no members = %v", filtered)
+	}
+}
+
+func TestFormatStatusCounts(t *testing.T) {
+	got := formatStatusCounts(map[string]int32{"RUNNING": 3, "COMPLETED": 10, "FAILED": 1})
+	if want := "COMPLETED=10 FAILED=1 RUNNING=3"; got != want {
+		t.Errorf("formatStatusCounts = %q, want %q", got, want)
+	}
+}
+
+func TestWithGroup(t *testing.T) {
+	env := map[string]string{"FOO": "bar"}
+	if got := withGroup(env, ""); !reflect.DeepEqual(got, env) {
+		t.Errorf("withGroup without group changed env: %v", got)
+	}
+
+	got := withGroup(env, "load-test-2024")
+	if got[constants.EnvGroup] != "load-test-2024" || got["FOO"] != "bar" {
+		t.Errorf("withGroup = %v", got)
+	}
+	if _, exists := env[constants.EnvGroup]; exists {
+		t.Error("withGroup modified the original environment")
+	}
+}
+
+func TestStopCmdGroupArgs(t *testing.T) {
+	cmd := NewStopCmd()
+	if err := cmd.Flags().Set("group", "load-test-2024"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Args(cmd, nil); err != nil {
+		t.Errorf("--group without a UUID: %v", err)
+	}
+	if err := cmd.Args(cmd, []string{"f47ac10b"}); err == nil {
+		t.Error("expected error for a UUID together with --group")
+	}
+}
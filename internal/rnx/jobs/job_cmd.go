@@ -14,10 +14,17 @@ func NewJobCmd() *cobra.Command {
 Available subcommands:
   run        Run a new job immediately or schedule it for later
   list       List all jobs or workflows
+  groups     List job groups
   status     Show status of a specific job
   log        Stream logs from a job
+  progress   Follow the progress a job reports
   metrics    View resource usage metrics for a job
-  stop       Stop a running job
+  profile    Save the strace/perf profile of a job run with --profile
+  freeze-fs  Keep a job's filesystem for inspection if it fails
+  fs         Browse the filesystem kept from a failed job
+  export-env Export a job's environment as a Dockerfile or OCI bundle
+  stop       Stop a running job or a job group
+  stop-all   Stop all running and scheduled jobs matching filters
   cancel     Cancel a scheduled job (status becomes CANCELED)
   delete     Delete a specific job
   delete-all Delete all non-running jobs`,
@@ -26,10 +33,17 @@ Available subcommands:
 	// Add all job-related subcommands
 	cmd.AddCommand(NewRunCmd())
 	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewGroupsCmd())
 	cmd.AddCommand(NewStatusCmd())
 	cmd.AddCommand(NewLogCmd())
+	cmd.AddCommand(NewProgressCmd())
 	cmd.AddCommand(NewMetricsCmd())
+	cmd.AddCommand(NewProfileCmd())
+	cmd.AddCommand(NewFreezeFSCmd())
+	cmd.AddCommand(NewFSCmd())
+	cmd.AddCommand(NewExportEnvCmd())
 	cmd.AddCommand(NewStopCmd())
+	cmd.AddCommand(NewStopAllCmd())
 	cmd.AddCommand(NewCancelCmd())
 	cmd.AddCommand(NewDeleteCmd())
 	cmd.AddCommand(NewDeleteAllCmd())
@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jobSpecFile is a single job described in YAML for 'rnx job run -f'. Every
+// run flag has a field, so a job can be reviewed and versioned instead of
+// living in shell history. Flags given on the command line override it.
+type jobSpecFile struct {
+	// Kind is optional; when set it must be "Job"
+	Kind              string             `yaml:"kind,omitempty"`
+	Name              string             `yaml:"name,omitempty"`
+	Command           string             `yaml:"command"`
+	Args              []string           `yaml:"args,omitempty"`
+	Runtime           string             `yaml:"runtime,omitempty"`
+	Network           string             `yaml:"network,omitempty"`
+	Volumes           []string           `yaml:"volumes,omitempty"`
+	Schedule          string             `yaml:"schedule,omitempty"`
+	Environment       map[string]string  `yaml:"environment,omitempty"`
+	SecretEnvironment map[string]string  `yaml:"secret_environment,omitempty"`
+	Uploads           jobSpecUploads     `yaml:"uploads,omitempty"`
+	Resources         types.JobResources `yaml:"resources,omitempty"`
+	// Profile runs the job under "strace" or "perf", like --profile
+	Profile string `yaml:"profile,omitempty"`
+	// Dedup returns an identical active job instead of starting a new one, like --dedup
+	Dedup bool `yaml:"dedup,omitempty"`
+	// CacheTTL reuses an identical job's result this fresh, like --cache-ttl
+	CacheTTL string `yaml:"cache_ttl,omitempty"`
+	// QueueTTL expires the job if it has not started this long after its
+	// submission or scheduled time, like --queue-ttl
+	QueueTTL string `yaml:"queue_ttl,omitempty"`
+	// IPC joins a named IPC channel, like --ipc
+	IPC string `yaml:"ipc,omitempty"`
+	// Group adds the job to a named job group, like --group
+	Group string `yaml:"group,omitempty"`
+	// Array starts one job per index of the spec, like --array
+	Array string `yaml:"array,omitempty"`
+	// Labels tag the job for bulk operations, like --label
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Annotations note where the job comes from, like --annotation
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// LogSinks copy the job's output to S3 or syslog, like --log-sink
+	LogSinks []string `yaml:"log_sinks,omitempty"`
+	// Inputs are objects the server downloads into the workspace, like --input
+	Inputs []string `yaml:"inputs,omitempty"`
+	// OutputTargets are files the server uploads once the job completes, like --output
+	OutputTargets []string `yaml:"output_targets,omitempty"`
+	// Egress restricts the destinations the job may connect to, like --egress
+	Egress []string `yaml:"egress,omitempty"`
+	// SecretFiles delivers secret_environment variables as files, like --secret-file
+	SecretFiles map[string]string `yaml:"secret_files,omitempty"`
+}
+
+// jobSpecUploads lists files and directories to upload, relative to the spec
+// file.
+type jobSpecUploads struct {
+	Files       []string `yaml:"files,omitempty"`
+	Directories []string `yaml:"directories,omitempty"`
+}
+
+// loadJobSpecFile reads a job spec. Upload paths are resolved against the
+// spec's directory, and ${VAR} references in secret_environment values are
+// expanded from the local environment so secrets stay out of the file.
+func loadJobSpecFile(path string) (*jobSpecFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job spec: %w", err)
+	}
+
+	var spec jobSpecFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		if strings.Contains(err.Error(), "field jobs not found") {
+			return nil, fmt.Errorf("%s is a workflow, use 'rnx workflow run %s'", path, path)
+		}
+		return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+	}
+
+	if spec.Kind != "" && spec.Kind != "Job" {
+		return nil, fmt.Errorf("invalid job spec %s: kind must be Job, got %s", path, spec.Kind)
+	}
+	if spec.Profile != "" && !slices.Contains(constants.ProfileTools, spec.Profile) {
+		return nil, fmt.Errorf("invalid job spec %s: profile must be one of %s", path, strings.Join(constants.ProfileTools, ", "))
+	}
+
+	if spec.Group != "" {
+		if _, err := values.NewGroupName(spec.Group); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+	if spec.Array != "" {
+		if _, err := values.ParseArrayIndices(spec.Array); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+	for key, value := range spec.Labels {
+		if err := values.ValidateLabel(key, value); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+	for key, value := range spec.Annotations {
+		if err := values.ValidateAnnotation(key, value); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+	for _, policy := range spec.Egress {
+		if _, err := values.ParseEgressPolicy(policy); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+	for filePath, key := range spec.SecretFiles {
+		if err := values.ValidateSecretFile(filePath, key); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+	for name, value := range spec.Resources.CgroupParams {
+		if err := values.ValidateCgroupParam(name, value); err != nil {
+			return nil, fmt.Errorf("invalid job spec %s: %w", path, err)
+		}
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(paths []string) []string {
+		resolved := make([]string, len(paths))
+		for i, p := range paths {
+			if filepath.IsAbs(p) {
+				resolved[i] = p
+			} else {
+				resolved[i] = filepath.Join(dir, p)
+			}
+		}
+		return resolved
+	}
+	spec.Uploads.Files = resolve(spec.Uploads.Files)
+	spec.Uploads.Directories = resolve(spec.Uploads.Directories)
+
+	for key, value := range spec.SecretEnvironment {
+		expanded := os.Expand(value, func(name string) string {
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				err = fmt.Errorf("secret_environment %s references %s, which is not set", key, name)
+			}
+			return v
+		})
+		if err != nil {
+			return nil, err
+		}
+		spec.SecretEnvironment[key] = expanded
+	}
+
+	return &spec, nil
+}
+
+// envPairs renders an environment map as sorted KEY=VALUE strings, the form
+// the --env flags are collected in.
+func envPairs(env map[string]string) []string {
+	pairs := make([]string, 0, len(env))
+	for key, value := range env {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// sizeBytes parses a resources size such as "2GB"; empty means unset.
+func sizeBytes(field, size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+	parsed, err := values.ParseMemorySize(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resources.%s '%s': %w", field, size, err)
+	}
+	return parsed.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "job.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadJobSpecFile(t *testing.T) {
+	t.Setenv("TEST_SPEC_TOKEN", "s3cret")
+
+	path := writeSpec(t, `
+kind: Job
+name: train
+command: python3
+args: [train.py, --epochs=10]
+runtime: python-3.11-ml
+volumes: [datasets]
+schedule: 30min
+environment:
+  LOG_LEVEL: info
+secret_environment:
+  API_KEY: token-${TEST_SPEC_TOKEN}
+uploads:
+  files: [train.py, /abs/data.csv]
+  directories: [configs]
+resources:
+  max_memory: 2048
+  shm_size: 2GB
+  cgroup_params:
+    cpu.weight: "50"
+group: training-runs
+labels:
+  env: staging
+annotations:
+  ticket: https://tracker.example.com/OPS-42
+`)
+
+	spec, err := loadJobSpecFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dir := filepath.Dir(path)
+	if spec.Command != "python3" || !reflect.DeepEqual(spec.Args, []string{"train.py", "--epochs=10"}) {
+		t.Errorf("command = %q %v", spec.Command, spec.Args)
+	}
+	if got := spec.SecretEnvironment["API_KEY"]; got != "token-s3cret" {
+		t.Errorf("secret was not expanded: %q", got)
+	}
+	if want := []string{filepath.Join(dir, "train.py"), "/abs/data.csv"}; !reflect.DeepEqual(spec.Uploads.Files, want) {
+		t.Errorf("upload files = %v, want %v", spec.Uploads.Files, want)
+	}
+	if want := []string{filepath.Join(dir, "configs")}; !reflect.DeepEqual(spec.Uploads.Directories, want) {
+		t.Errorf("upload directories = %v, want %v", spec.Uploads.Directories, want)
+	}
+	if spec.Resources.MaxMemory != 2048 {
+		t.Errorf("max_memory = %d", spec.Resources.MaxMemory)
+	}
+	if size, err := sizeBytes("shm_size", spec.Resources.ShmSize); err != nil || size != 2*1024*1024*1024 {
+		t.Errorf("shm_size = %d, %v", size, err)
+	}
+	if !reflect.DeepEqual(spec.Resources.CgroupParams, map[string]string{"cpu.weight": "50"}) {
+		t.Errorf("cgroup_params = %v", spec.Resources.CgroupParams)
+	}
+	if pairs := envPairs(spec.Environment); !reflect.DeepEqual(pairs, []string{"LOG_LEVEL=info"}) {
+		t.Errorf("env pairs = %v", pairs)
+	}
+	if spec.Group != "training-runs" {
+		t.Errorf("group = %q", spec.Group)
+	}
+	if !reflect.DeepEqual(spec.Labels, map[string]string{"env": "staging"}) {
+		t.Errorf("labels = %v", spec.Labels)
+	}
+	if !reflect.DeepEqual(spec.Annotations, map[string]string{"ticket": "https://tracker.example.com/OPS-42"}) {
+		t.Errorf("annotations = %v", spec.Annotations)
+	}
+}
+
+func TestLoadJobSpecFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown field", "command: ls\nmax_memory: 10\n", "field max_memory not found"},
+		{"workflow", "jobs:\n  a:\n    command: ls\n", "is a workflow"},
+		{"wrong kind", "kind: Workflow\ncommand: ls\n", "kind must be Job"},
+		{"invalid group", "command: ls\ngroup: load test\n", "invalid group name format"},
+		{"invalid label", "command: ls\nlabels:\n  env: a b\n", "invalid value for label env"},
+		{"invalid annotation", "command: ls\nannotations:\n  bad key: x\n", "invalid annotation key"},
+		{"invalid egress", "command: ls\negress: [permit:api.internal]\n", "the action must be allow or deny"},
+		{"invalid secret file", "command: ls\nsecret_files:\n  /proc/token: TOKEN\n", "must not be under /proc"},
+		{"cgroup core file", "command: ls\nresources:\n  cgroup_params:\n    cgroup.procs: \"1\"\n", "cgroup.* core files cannot be set"},
+		{"unset secret", "command: ls\nsecret_environment:\n  TOKEN: ${TEST_SPEC_UNSET_VAR}\n", "TEST_SPEC_UNSET_VAR, which is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadJobSpecFile(writeSpec(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ import (
 // The command supports JSON output format via the --json flag.
 // Lists all jobs with their basic information.
 func NewListCmd() *cobra.Command {
+	var group string
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all jobs",
@@ -30,18 +32,25 @@ Examples:
   # List jobs in JSON format
   rnx job list --json
 
+  # List the jobs of a group
+  rnx job list --group=load-test-2024
+
   # For workflows, use:
   rnx workflow list`,
-		RunE: runList,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(group)
+		},
 	}
 
+	cmd.Flags().StringVar(&group, "group", "", "Only list the jobs of this job group")
+
 	return cmd
 }
 
 // runList executes the job listing command.
 // Connects to the Joblet server, retrieves all jobs, and displays them
 // in either readable table format or JSON format based on flags.
-func runList(cmd *cobra.Command, args []string) error {
+func runList(group string) error {
 	jobClient, err := common.NewJobClient()
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
@@ -56,6 +65,14 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list jobs: %v", err)
 	}
 
+	if group != "" {
+		groups, err := jobClient.ListJobGroups(ctx, group)
+		if err != nil {
+			return fmt.Errorf("failed to list job group %s: %v", group, err)
+		}
+		response.Jobs = filterJobs(response.Jobs, groups.Groups[0].JobUuids)
+	}
+
 	if len(response.Jobs) == 0 {
 		if common.JSONOutput {
 			fmt.Println("[]")
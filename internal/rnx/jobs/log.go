@@ -6,14 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/constants"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -39,11 +44,22 @@ Examples:
   # View logs from a completed job (short-form UUID)
   rnx job log a1b2c3d4
 
-  # Stop following with Ctrl+C for running jobs`,
-		Args: cobra.ExactArgs(1),
-		RunE: runLog,
+  # Stop following with Ctrl+C for running jobs
+  # A lost connection is resumed from the last line received
+
+  # Save the full log of a finished job to a file
+  rnx job log save a1b2c3d4 --output job.log --gzip
+
+  # Browse and search a large log without downloading it
+  rnx job log view a1b2c3d4`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeIDs(idspb.IdType_ID_TYPE_JOB),
+		RunE:              runLog,
 	}
 
+	cmd.AddCommand(NewLogSaveCmd())
+	cmd.AddCommand(NewLogViewCmd())
+
 	return cmd
 }
 
@@ -71,37 +87,146 @@ func runLog(cmd *cobra.Command, args []string) error {
 	}
 	defer jobClient.Close()
 
-	stream, err := jobClient.GetJobLogs(ctx, jobID)
+	jobID, err = resolveID(jobClient, jobID, idspb.IdType_ID_TYPE_JOB)
 	if err != nil {
-		return fmt.Errorf("couldn't start reading logs: %v", err)
+		return err
 	}
 
-	// Stream all logs (server provides historical + live seamlessly)
-	for {
-		chunk, e := stream.Recv()
-		if e == io.EOF {
-			return nil // Clean exit at end of stream
-		}
-		if e != nil {
-			if errors.Is(ctx.Err(), context.Canceled) {
-				// This is an expected error due to our cancellation
+	follower := &logFollower{
+		open: func(ctx context.Context, offset int64) (jobLogStream, error) {
+			return jobClient.GetJobLogsFrom(ctx, jobID, offset)
+		},
+		write: func(chunk *pb.DataChunk) error {
+			if common.JSONOutput {
+				if err := outputLogChunkJSON(chunk); err != nil {
+					return fmt.Errorf("couldn't format output as JSON: %v", err)
+				}
 				return nil
 			}
+			fmt.Printf("%s", chunk.Payload)
+			return nil
+		},
+		notice: os.Stderr,
+	}
 
-			if s, ok := status.FromError(e); ok {
-				return fmt.Errorf("problem reading logs: %v", s.Message())
-			}
+	// Stream all logs (server provides historical + live seamlessly)
+	if err := follower.follow(ctx); err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			// This is an expected error due to our cancellation
+			return nil
+		}
 
-			return fmt.Errorf("error receiving log stream: %v", e)
+		if s, ok := status.FromError(err); ok {
+			return fmt.Errorf("problem reading logs: %v", s.Message())
 		}
 
-		if common.JSONOutput {
-			if err := outputLogChunkJSON(chunk); err != nil {
-				return fmt.Errorf("couldn't format output as JSON: %v", err)
+		return fmt.Errorf("error receiving log stream: %v", err)
+	}
+	return nil
+}
+
+const (
+	// logReconnectAttempts bounds the reconnects in a row that deliver no log
+	logReconnectAttempts = 10
+
+	// logReconnectMaxDelay caps the doubling delay between reconnects
+	logReconnectMaxDelay = 30 * time.Second
+)
+
+// logReconnectDelay is the first delay before reconnecting a lost log stream
+var logReconnectDelay = time.Second
+
+// errLogStreamIdle ends a log stream that went silent for three heartbeats
+var errLogStreamIdle = status.Error(codes.Unavailable, "no data or heartbeat from the server")
+
+// jobLogStream is the client side of a GetJobLogs call
+type jobLogStream interface {
+	Recv() (*pb.DataChunk, error)
+	Header() (metadata.MD, error)
+}
+
+// logFollower writes a job's log stream and, when the server supports
+// resuming, reconnects a lost stream from the last chunk written
+type logFollower struct {
+	open   func(ctx context.Context, offset int64) (jobLogStream, error)
+	write  func(chunk *pb.DataChunk) error
+	notice io.Writer
+}
+
+// follow streams the log until the server ends it
+func (f *logFollower) follow(ctx context.Context) error {
+	var received int64
+	resumable := false
+	failures := 0
+	delay := logReconnectDelay
+	for {
+		before := received
+		err := f.stream(ctx, &received, &resumable)
+		if err == nil || ctx.Err() != nil || !resumable || !retryableLogError(err) {
+			return err
+		}
+		if received > before {
+			failures, delay = 0, logReconnectDelay
+		}
+		failures++
+		if failures > logReconnectAttempts {
+			return err
+		}
+		fmt.Fprintf(f.notice, "Log stream lost (%s), reconnecting from chunk %d in %s\n", status.Convert(err).Message(), received, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if delay *= 2; delay > logReconnectMaxDelay {
+			delay = logReconnectMaxDelay
+		}
+	}
+}
+
+// stream reads one log stream from received, counting the non-empty chunks
+// it writes; heartbeats are empty and only reset the idle timer
+func (f *logFollower) stream(ctx context.Context, received *int64, resumable *bool) error {
+	streamCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	stream, err := f.open(streamCtx, *received)
+	if err != nil {
+		return err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	var heartbeat time.Duration
+	if values := header.Get(constants.LogHeartbeatHeader); len(values) > 0 {
+		*resumable = true
+		heartbeat, _ = time.ParseDuration(values[0])
+	}
+	idle := time.AfterFunc(time.Duration(math.MaxInt64), func() { cancel(errLogStreamIdle) })
+	defer idle.Stop()
+
+	for {
+		if heartbeat > 0 {
+			idle.Reset(3 * heartbeat)
+		}
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil // Clean exit at end of stream
+		}
+		if err != nil {
+			if ctx.Err() == nil && context.Cause(streamCtx) == errLogStreamIdle {
+				return errLogStreamIdle
 			}
-		} else {
-			fmt.Printf("%s", chunk.Payload)
+			return err
+		}
+		if len(chunk.Payload) == 0 {
+			continue
+		}
+		if err := f.write(chunk); err != nil {
+			return err
 		}
+		*received++
 	}
 }
 
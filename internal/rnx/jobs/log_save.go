@@ -0,0 +1,371 @@
+package jobs
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// logCheckpointBytes is how much log content is written between
+	// checkpoints; an interrupted download resumes from the last one
+	logCheckpointBytes = 8 * 1024 * 1024
+
+	// logSaveRetries bounds reconnects after the stream breaks
+	logSaveRetries = 5
+)
+
+// logSaveRetryDelay is the first delay between reconnects, doubled each time
+var logSaveRetryDelay = time.Second
+
+func NewLogSaveCmd() *cobra.Command {
+	var (
+		output  string
+		gzipped bool
+		restart bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save <job-uuid>",
+		Short: "Download the full persisted log of a finished job to a file",
+		Long: `Download the full persisted log of a finished job to a file.
+
+The log is written to <output>.part and renamed to the output file when the
+download completes. Progress is checkpointed every 8 MB: a broken connection
+is retried automatically, and running the same command again after an
+interruption resumes from the last checkpoint.
+
+Examples:
+  # Save the log to job.log
+  rnx job log save f47ac10b --output job.log
+
+  # Save it gzip-compressed (to f47ac10b.log.gz without --output)
+  rnx job log save f47ac10b --gzip
+
+  # Discard a partial download and start over
+  rnx job log save f47ac10b -o job.log --restart`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogSave(args[0], output, gzipped, restart)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (default <job-uuid>.log, .log.gz with --gzip)")
+	cmd.Flags().BoolVar(&gzipped, "gzip", false, "Compress the file with gzip")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Ignore a partial download and start from the beginning")
+
+	return cmd
+}
+
+func runLogSave(jobID, output string, gzipped, restart bool) error {
+	if output == "" {
+		output = jobID + ".log"
+		if gzipped {
+			output += ".gz"
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	open := func(ctx context.Context, offset int64) (logChunkStream, error) {
+		return jobClient.DownloadJobLogs(ctx, jobID, offset)
+	}
+	result, err := saveJobLog(ctx, open, jobID, output, gzipped, restart, progressWriter())
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("download interrupted, run the same command again to resume")
+		}
+		return err
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(map[string]interface{}{
+			"job_uuid": result.JobUUID,
+			"output":   output,
+			"records":  result.Records,
+			"bytes":    result.Bytes,
+			"resumed":  result.Resumed,
+		})
+	}
+	if result.Resumed {
+		fmt.Printf("Resumed download of %s\n", result.JobUUID)
+	}
+	fmt.Printf("Saved %d log records (%s) to %s\n", result.Records, formatBytes(result.Bytes), output)
+	return nil
+}
+
+// logChunkStream is the client side of a DownloadJobLogs call
+type logChunkStream interface {
+	Recv() (*logspb.LogChunk, error)
+}
+
+// logSaveState is the checkpoint of a partial download, stored next to it
+type logSaveState struct {
+	JobID   string `json:"job_id"`             // Job as given on the command line
+	JobUUID string `json:"job_uuid,omitempty"` // Full UUID reported by the server
+	Gzip    bool   `json:"gzip"`
+	Records int64  `json:"records"` // Log records in the file
+	Bytes   int64  `json:"bytes"`   // File size at the checkpoint
+}
+
+// logSaveResult describes a completed download
+type logSaveResult struct {
+	JobUUID string
+	Records int64
+	Bytes   int64 // Size of the output file
+	Resumed bool
+}
+
+// saveJobLog downloads a job's log to output through <output>.part, resuming
+// from the checkpoint in <output>.part.json when it belongs to the same job
+func saveJobLog(ctx context.Context, open func(ctx context.Context, offset int64) (logChunkStream, error),
+	jobID, output string, gzipped, restart bool, progress io.Writer) (*logSaveResult, error) {
+	partPath := output + ".part"
+	statePath := partPath + ".json"
+
+	state := logSaveState{JobID: jobID, Gzip: gzipped}
+	resumed := false
+	if !restart {
+		if previous, ok := readLogSaveState(statePath); ok && previous.JobID == jobID && previous.Gzip == gzipped {
+			state = previous
+			resumed = state.Records > 0
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if !resumed {
+		flags |= os.O_TRUNC
+		state.Records, state.Bytes = 0, 0
+	}
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", partPath, err)
+	}
+	defer file.Close()
+
+	w := &checkpointWriter{file: file, gzipped: gzipped, state: &state, statePath: statePath, progress: progress}
+	if err := w.rewind(); err != nil {
+		return nil, err
+	}
+
+	if err := w.downloadWithRetries(ctx, open); err != nil {
+		// Keep partial downloads to resume; nothing written is not worth keeping
+		if state.Records == 0 {
+			file.Close()
+			_ = os.Remove(partPath)
+			_ = os.Remove(statePath)
+		}
+		return nil, err
+	}
+	if progress != nil && state.Records > 0 {
+		fmt.Fprintln(progress)
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, output); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	_ = os.Remove(statePath)
+
+	jobUUID := state.JobUUID
+	if jobUUID == "" {
+		jobUUID = jobID
+	}
+	return &logSaveResult{JobUUID: jobUUID, Records: state.Records, Bytes: state.Bytes, Resumed: resumed}, nil
+}
+
+// downloadWithRetries downloads until the end of the log, reconnecting from
+// the last checkpoint when the stream breaks
+func (w *checkpointWriter) downloadWithRetries(ctx context.Context, open func(ctx context.Context, offset int64) (logChunkStream, error)) error {
+	delay := logSaveRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := w.download(ctx, open)
+		if err == nil {
+			return nil
+		}
+		if attempt == logSaveRetries || !retryableLogError(err) || ctx.Err() != nil {
+			if s, ok := status.FromError(err); ok {
+				return fmt.Errorf("problem downloading logs: %v", s.Message())
+			}
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Log download interrupted (%v), resuming from record %d in %s\n", status.Convert(err).Message(), w.state.Records, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+		if err := w.rewind(); err != nil {
+			return err
+		}
+	}
+}
+
+// checkpointWriter appends log chunks to the partial file and records a
+// checkpoint every logCheckpointBytes. With gzip, each checkpoint ends a gzip
+// member, so the file up to any checkpoint is a complete gzip stream.
+type checkpointWriter struct {
+	file      *os.File
+	gzipped   bool
+	state     *logSaveState
+	statePath string
+	progress  io.Writer
+
+	gz      *gzip.Writer
+	unsaved int64 // Content written since the last checkpoint
+	records int64 // Records written since the last checkpoint
+}
+
+// rewind drops whatever was written after the last checkpoint
+func (w *checkpointWriter) rewind() error {
+	if err := w.file.Truncate(w.state.Bytes); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", w.file.Name(), err)
+	}
+	if _, err := w.file.Seek(w.state.Bytes, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", w.file.Name(), err)
+	}
+	w.gz, w.unsaved, w.records = nil, 0, 0
+	return nil
+}
+
+// download streams the log from the last checkpoint to the end
+func (w *checkpointWriter) download(ctx context.Context, open func(ctx context.Context, offset int64) (logChunkStream, error)) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := open(streamCtx, w.state.Records)
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return w.checkpoint()
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.JobUuid != "" {
+			w.state.JobUUID = chunk.JobUuid
+		}
+		if err := w.write(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *checkpointWriter) write(chunk *logspb.LogChunk) error {
+	var out io.Writer = w.file
+	if w.gzipped {
+		if w.gz == nil {
+			w.gz = gzip.NewWriter(w.file)
+		}
+		out = w.gz
+	}
+	if _, err := out.Write(chunk.Content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", w.file.Name(), err)
+	}
+	w.unsaved += int64(len(chunk.Content))
+	w.records += chunk.Records
+	if w.unsaved >= logCheckpointBytes {
+		return w.checkpoint()
+	}
+	return nil
+}
+
+// checkpoint makes what was written durable and records it in the state file
+func (w *checkpointWriter) checkpoint() error {
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", w.file.Name(), err)
+		}
+		w.gz = nil
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", w.file.Name(), err)
+	}
+	offset, err := w.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	w.state.Bytes = offset
+	w.state.Records += w.records
+	w.unsaved, w.records = 0, 0
+	if err := writeLogSaveState(w.statePath, *w.state); err != nil {
+		return err
+	}
+
+	if w.progress != nil {
+		fmt.Fprintf(w.progress, "\rSaved %d records (%s)", w.state.Records, formatBytes(w.state.Bytes))
+	}
+	return nil
+}
+
+// retryableLogError reports whether a broken download is worth resuming
+func retryableLogError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+func readLogSaveState(path string) (logSaveState, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return logSaveState{}, false
+	}
+	var state logSaveState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return logSaveState{}, false
+	}
+	return state, true
+}
+
+func writeLogSaveState(path string, state logSaveState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
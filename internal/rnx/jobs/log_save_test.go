@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLogServer serves records as one-record chunks and can break the
+// stream after a number of chunks
+type fakeLogServer struct {
+	records []string
+	breakAt int // Break each stream after this many chunks, 0 = never
+	calls   []int64
+}
+
+type fakeLogStream struct {
+	server *fakeLogServer
+	next   int64
+	sent   int
+}
+
+func (f *fakeLogServer) open(ctx context.Context, offset int64) (logChunkStream, error) {
+	f.calls = append(f.calls, offset)
+	return &fakeLogStream{server: f, next: offset}, nil
+}
+
+func (s *fakeLogStream) Recv() (*logspb.LogChunk, error) {
+	if s.server.breakAt > 0 && s.sent == s.server.breakAt {
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	if s.next >= int64(len(s.server.records)) {
+		return nil, io.EOF
+	}
+	chunk := &logspb.LogChunk{Content: []byte(s.server.records[s.next]), Records: 1}
+	if s.sent == 0 {
+		chunk.JobUuid = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	}
+	s.next++
+	s.sent++
+	return chunk, nil
+}
+
+func bigRecords(n int) []string {
+	records := make([]string, n)
+	for i := range records {
+		records[i] = strings.Repeat(string(rune('a'+i%26)), logCheckpointBytes/2-1) + "\n"
+	}
+	return records
+}
+
+func TestSaveJobLogResumesAfterBrokenStream(t *testing.T) {
+	previous := logSaveRetryDelay
+	logSaveRetryDelay = 0
+	defer func() { logSaveRetryDelay = previous }()
+
+	server := &fakeLogServer{records: bigRecords(7), breakAt: 3}
+	output := filepath.Join(t.TempDir(), "job.log")
+
+	result, err := saveJobLog(context.Background(), server.open, "f47ac10b", output, false, false, nil)
+	if err != nil {
+		t.Fatalf("saveJobLog() error = %v", err)
+	}
+	if result.Records != 7 || result.JobUUID != "f47ac10b-58cc-4372-a567-0e02b2c3d479" {
+		t.Errorf("result = %+v, want 7 records of the full UUID", result)
+	}
+	// Checkpoints every two records: the third record of each stream is written again
+	if len(server.calls) < 2 || server.calls[0] != 0 || server.calls[1] != 2 {
+		t.Errorf("stream offsets = %v, want a resume from record 2", server.calls)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != strings.Join(server.records, "") {
+		t.Errorf("saved log has %d bytes, want the %d records exactly once", len(got), len(server.records))
+	}
+	if _, err := os.Stat(output + ".part.json"); !os.IsNotExist(err) {
+		t.Errorf("checkpoint file left behind: %v", err)
+	}
+}
+
+func TestSaveJobLogGzipResumesAcrossRuns(t *testing.T) {
+	records := bigRecords(5)
+	output := filepath.Join(t.TempDir(), "job.log.gz")
+
+	// First run: the stream fails for good after three records, one past
+	// the checkpoint
+	first := func(ctx context.Context, offset int64) (logChunkStream, error) {
+		stream := &fakeLogStream{server: &fakeLogServer{records: records}, next: offset}
+		return &failAfter{stream: stream, after: 3}, nil
+	}
+	if _, err := saveJobLog(context.Background(), first, "f47ac10b", output, true, false, nil); err == nil {
+		t.Fatal("first saveJobLog() succeeded, want an error")
+	}
+	if _, err := os.Stat(output + ".part"); err != nil {
+		t.Fatalf("partial download removed: %v", err)
+	}
+
+	// Second run resumes from the checkpoint
+	server := &fakeLogServer{records: records}
+	result, err := saveJobLog(context.Background(), server.open, "f47ac10b", output, true, false, nil)
+	if err != nil {
+		t.Fatalf("second saveJobLog() error = %v", err)
+	}
+	if !result.Resumed || server.calls[0] != 2 {
+		t.Errorf("resumed = %v from offset %v, want a resume from record 2", result.Resumed, server.calls)
+	}
+
+	file, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("saved file is not valid gzip: %v", err)
+	}
+	if string(got) != strings.Join(records, "") {
+		t.Errorf("decompressed log has %d bytes, want %d", len(got), len(strings.Join(records, "")))
+	}
+}
+
+// failAfter fails permanently after a number of chunks
+type failAfter struct {
+	stream logChunkStream
+	after  int
+	sent   int
+}
+
+func (f *failAfter) Recv() (*logspb.LogChunk, error) {
+	if f.sent == f.after {
+		return nil, status.Error(codes.PermissionDenied, "certificate revoked")
+	}
+	f.sent++
+	return f.stream.Recv()
+}
+
+func TestSaveJobLogDoesNotRetryPermanentErrors(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "job.log")
+	calls := 0
+	open := func(ctx context.Context, offset int64) (logChunkStream, error) {
+		calls++
+		return nil, status.Error(codes.FailedPrecondition, "job f47ac10b is still RUNNING")
+	}
+
+	_, err := saveJobLog(context.Background(), open, "f47ac10b", output, false, false, nil)
+	if err == nil || !strings.Contains(err.Error(), "still RUNNING") {
+		t.Fatalf("saveJobLog() error = %v, want the server's message", err)
+	}
+	if calls != 1 {
+		t.Errorf("open called %d times, want no retries", calls)
+	}
+	if _, err := os.Stat(output + ".part"); !os.IsNotExist(err) {
+		t.Errorf("empty partial file left behind: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeJobLogStream replays payloads, then ends with err; a nil err with
+// hang set blocks until the stream context is cancelled
+type fakeJobLogStream struct {
+	ctx      context.Context
+	header   metadata.MD
+	payloads []string
+	err      error
+	hang     bool
+}
+
+func (s *fakeJobLogStream) Header() (metadata.MD, error) { return s.header, nil }
+
+func (s *fakeJobLogStream) Recv() (*pb.DataChunk, error) {
+	if len(s.payloads) > 0 {
+		payload := s.payloads[0]
+		s.payloads = s.payloads[1:]
+		return &pb.DataChunk{Payload: []byte(payload)}, nil
+	}
+	if s.hang {
+		<-s.ctx.Done()
+		return nil, status.Error(codes.Canceled, "context canceled")
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return nil, io.EOF
+}
+
+// fakeJobLogServer hands out the prepared streams in order, recording offsets
+type fakeJobLogServer struct {
+	streams []*fakeJobLogStream
+	offsets []int64
+}
+
+func (f *fakeJobLogServer) open(ctx context.Context, offset int64) (jobLogStream, error) {
+	f.offsets = append(f.offsets, offset)
+	if len(f.streams) == 0 {
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+	stream := f.streams[0]
+	f.streams = f.streams[1:]
+	stream.ctx = ctx
+	return stream, nil
+}
+
+func followForTest(server *fakeJobLogServer) (string, string, error) {
+	var out, notice strings.Builder
+	f := &logFollower{
+		open: server.open,
+		write: func(chunk *pb.DataChunk) error {
+			out.Write(chunk.Payload)
+			return nil
+		},
+		notice: &notice,
+	}
+	err := f.follow(context.Background())
+	return out.String(), notice.String(), err
+}
+
+func TestLogFollower_Reconnect(t *testing.T) {
+	logReconnectDelay = 0
+	resumable := metadata.Pairs(constants.LogHeartbeatHeader, "1h")
+	server := &fakeJobLogServer{streams: []*fakeJobLogStream{
+		{header: resumable, payloads: []string{"a\n", "", "b\n"}, err: status.Error(codes.Unavailable, "connection reset")},
+		{header: resumable, payloads: []string{"c\n"}},
+	}}
+
+	out, notice, err := followForTest(server)
+	if err != nil {
+		t.Fatalf("follow() error = %v", err)
+	}
+	// The heartbeat is neither written nor counted
+	if out != "a\nb\nc\n" || !slices.Equal(server.offsets, []int64{0, 2}) {
+		t.Errorf("wrote %q, streams opened at %v", out, server.offsets)
+	}
+	if !strings.Contains(notice, "reconnecting from chunk 2") {
+		t.Errorf("notice = %q", notice)
+	}
+}
+
+func TestLogFollower_IdleStream(t *testing.T) {
+	logReconnectDelay = 0
+	server := &fakeJobLogServer{streams: []*fakeJobLogStream{
+		{header: metadata.Pairs(constants.LogHeartbeatHeader, "5ms"), payloads: []string{"a\n"}, hang: true},
+		{header: metadata.Pairs(constants.LogHeartbeatHeader, "5ms"), payloads: []string{"b\n"}},
+	}}
+
+	done := make(chan struct{})
+	var out string
+	var err error
+	go func() {
+		out, _, err = followForTest(server)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow() did not give up on the silent stream")
+	}
+	if err != nil || out != "a\nb\n" || !slices.Equal(server.offsets, []int64{0, 1}) {
+		t.Errorf("follow() = %q, %v; streams opened at %v", out, err, server.offsets)
+	}
+}
+
+func TestLogFollower_NoResume(t *testing.T) {
+	lost := status.Error(codes.Unavailable, "connection reset")
+
+	// Servers without the heartbeat header cannot skip what was received
+	server := &fakeJobLogServer{streams: []*fakeJobLogStream{{payloads: []string{"a\n"}, err: lost}}}
+	if _, _, err := followForTest(server); !errors.Is(err, lost) || len(server.offsets) != 1 {
+		t.Errorf("follow() = %v after %d streams, want the first error", err, len(server.offsets))
+	}
+
+	// Reconnects that deliver nothing are bounded
+	logReconnectDelay = 0
+	server = &fakeJobLogServer{streams: []*fakeJobLogStream{{header: metadata.Pairs(constants.LogHeartbeatHeader, "1h"), err: lost}}}
+	if _, _, err := followForTest(server); status.Code(err) != codes.Unavailable || len(server.offsets) != logReconnectAttempts+1 {
+		t.Errorf("follow() = %v after %d streams, want %d", err, len(server.offsets), logReconnectAttempts+1)
+	}
+}
@@ -0,0 +1,877 @@
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+// logViewWindow is how many records the viewer reads at a time
+const logViewWindow = 200
+
+// logViewHelp is shown in the status line with h
+const logViewHelp = "j/k line  space/b page  g/G start/end  / ? search  n/N next/prev  t time  m mark  [ ] marks  w export  q quit"
+
+func NewLogViewCmd() *cobra.Command {
+	var (
+		stream    string
+		bookmarks string
+		regex     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "view <job-uuid>",
+		Short: "Browse and search a job's persisted log in a pager",
+		Long: `Browse and search a job's persisted log in a pager.
+
+The log stays on the node: the pager reads the records on screen and the node
+runs searches and time jumps, so gigabyte logs open instantly. Running jobs
+can be viewed too; G picks up records written since.
+
+Keys:
+  j, k, arrows     Scroll a line            space, b, PgDn, PgUp  Scroll a page
+  d, u             Scroll half a page       g, G, Home, End       Start, end
+  /text, ?text     Search forward, back     n, N                  Next, previous match
+  t                Jump to a time (RFC3339, "2006-01-02 15:04:05", or HH:MM[:SS]
+                   on the day of the top record)
+  m                Bookmark the top record  [, ]                  Previous, next bookmark
+  w                Export bookmarks         h                     Help
+  q, Ctrl+C        Quit
+
+Searches are case-insensitive unless the text has upper case letters. With
+--stream all, stdout records come before stderr records, so a time jump finds
+the first stdout record at or after the time before any stderr record.
+
+Bookmarks are exported as text, or as JSON when the file ends in .json.
+
+Examples:
+  # Browse a job's log
+  rnx job log view f47ac10b
+
+  # Only stderr, with regular expression searches
+  rnx job log view f47ac10b --stream stderr --regex
+
+  # Export bookmarks as JSON
+  rnx job log view f47ac10b --bookmarks incident.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logStream, err := parseViewStream(stream)
+			if err != nil {
+				return err
+			}
+			if bookmarks == "" {
+				bookmarks = args[0] + ".bookmarks.txt"
+			}
+			return runLogView(args[0], logStream, bookmarks, regex)
+		},
+	}
+
+	cmd.Flags().StringVar(&stream, "stream", "all", "Records to view: all, stdout or stderr")
+	cmd.Flags().StringVar(&bookmarks, "bookmarks", "", "File bookmarks are exported to (default <job-uuid>.bookmarks.txt)")
+	cmd.Flags().BoolVar(&regex, "regex", false, "Search with regular expressions instead of plain text")
+
+	return cmd
+}
+
+func parseViewStream(stream string) (logspb.LogStream, error) {
+	switch stream {
+	case "all":
+		return logspb.LogStream_LOG_STREAM_ALL, nil
+	case "stdout":
+		return logspb.LogStream_LOG_STREAM_STDOUT, nil
+	case "stderr":
+		return logspb.LogStream_LOG_STREAM_STDERR, nil
+	}
+	return 0, fmt.Errorf("invalid --stream %q, expected all, stdout or stderr", stream)
+}
+
+func runLogView(jobID string, stream logspb.LogStream, bookmarks string, regex bool) error {
+	in, out := int(os.Stdin.Fd()), os.Stdout
+	if _, _, err := terminalSize(int(out.Fd())); err != nil {
+		return fmt.Errorf("rnx job log view needs a terminal; use 'rnx job log save' to download the log")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	v := newLogViewer(ctx, jobClient, jobID, stream)
+	v.bookmarksPath, v.regex = bookmarks, regex
+
+	// Fail before taking over the terminal
+	if _, err := v.record(0); err != nil {
+		return fmt.Errorf("problem reading logs: %v", status.Convert(err).Message())
+	}
+
+	restore, err := makeRaw(in)
+	if err != nil {
+		return fmt.Errorf("couldn't set up the terminal: %w", err)
+	}
+	defer restore()
+	fmt.Fprint(out, "\033[?1049h")
+	defer fmt.Fprint(out, "\033[?1049l")
+
+	v.progress = func(msg string) {
+		v.message = msg
+		v.render(out)
+	}
+	keys := bufio.NewReader(os.Stdin)
+	for {
+		if width, height, err := terminalSize(int(out.Fd())); err == nil {
+			v.resize(width, height)
+		}
+		v.render(out)
+		key, err := readKey(keys)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if v.handleKey(key) {
+			return nil
+		}
+	}
+}
+
+// logSource reads and searches a job's persisted log on the node
+type logSource interface {
+	ReadLogRange(ctx context.Context, req *logspb.ReadLogRangeRequest) (*logspb.LogRange, error)
+	LocateLogRecord(ctx context.Context, req *logspb.LocateLogRecordRequest) (*logspb.LogLocation, error)
+}
+
+// viewRecord is a log record split into displayable lines
+type viewRecord struct {
+	timestamp int64
+	stderr    bool
+	lines     []string
+
+	rows      []string // lines wrapped at rowsWidth
+	rowsWidth int
+}
+
+func newViewRecord(r *logspb.LogRecord) *viewRecord {
+	content := strings.TrimSuffix(string(r.Content), "\n")
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = displayLine(strings.TrimSuffix(line, "\r"))
+	}
+	return &viewRecord{timestamp: r.Timestamp, stderr: r.Stderr, lines: lines}
+}
+
+// displayLine expands tabs and makes control characters visible, so log
+// content cannot move the cursor or change colors
+func displayLine(line string) string {
+	var b strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20 || r == 0x7f:
+			b.WriteByte('^')
+			b.WriteRune(r ^ 0x40)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (r *viewRecord) wrapped(width int) []string {
+	if r.rowsWidth == width {
+		return r.rows
+	}
+	r.rows, r.rowsWidth = nil, width
+	for _, line := range r.lines {
+		runes := []rune(line)
+		for len(runes) > width {
+			r.rows = append(r.rows, string(runes[:width]))
+			runes = runes[width:]
+		}
+		r.rows = append(r.rows, string(runes))
+	}
+	return r.rows
+}
+
+// logBookmark is an exported bookmark; Record counts from 1 and Time is UTC
+type logBookmark struct {
+	Record int64  `json:"record"`
+	Time   string `json:"time"`
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+}
+
+// logViewer is the pager's state: the position in the log, the records read
+// around it, the last search and the bookmarks
+type logViewer struct {
+	ctx     context.Context
+	src     logSource
+	jobID   string
+	jobUUID string
+	stream  logspb.LogStream
+
+	bookmarksPath string
+	regex         bool
+	progress      func(msg string)
+
+	width, height int
+
+	top int64 // Record at the top of the screen
+	row int   // Wrapped row of that record at the top
+
+	records map[int64]*viewRecord
+	total   int64 // Records in the log, -1 until the end was seen
+
+	pattern   string
+	highlight *regexp.Regexp
+	backward  bool
+
+	bookmarks map[int64]logBookmark
+
+	prompt  string // Shown while reading input, "" otherwise
+	input   []rune
+	onInput func(string)
+	message string
+}
+
+func newLogViewer(ctx context.Context, src logSource, jobID string, stream logspb.LogStream) *logViewer {
+	return &logViewer{
+		ctx:       ctx,
+		src:       src,
+		jobID:     jobID,
+		jobUUID:   jobID,
+		stream:    stream,
+		width:     80,
+		height:    24,
+		records:   map[int64]*viewRecord{},
+		total:     -1,
+		bookmarks: map[int64]logBookmark{},
+	}
+}
+
+func (v *logViewer) resize(width, height int) {
+	v.width, v.height = max(width, 10), max(height, 2)
+}
+
+// page is the number of log rows on screen, below them is the status line
+func (v *logViewer) page() int { return v.height - 1 }
+
+// rowWidth leaves a column for the bookmark marker
+func (v *logViewer) rowWidth() int { return v.width - 1 }
+
+func (v *logViewer) fail(err error) {
+	v.message = status.Convert(err).Message()
+}
+
+// record returns a record, reading the window around it when needed, or nil
+// past the end of the log
+func (v *logViewer) record(offset int64) (*viewRecord, error) {
+	if offset < 0 || (v.total >= 0 && offset >= v.total) {
+		return nil, nil
+	}
+	if r, ok := v.records[offset]; ok {
+		return r, nil
+	}
+	if err := v.fetch(offset - offset%logViewWindow); err != nil {
+		return nil, err
+	}
+	if r, ok := v.records[offset]; ok || (v.total >= 0 && offset >= v.total) {
+		return r, nil
+	}
+	// Large records cut the window short of the record
+	if err := v.fetch(offset); err != nil {
+		return nil, err
+	}
+	return v.records[offset], nil
+}
+
+func (v *logViewer) fetch(start int64) error {
+	if len(v.records) >= 8*logViewWindow {
+		v.records = map[int64]*viewRecord{}
+	}
+	resp, err := v.src.ReadLogRange(v.ctx, &logspb.ReadLogRangeRequest{
+		JobUuid: v.jobID,
+		Stream:  v.stream,
+		Offset:  start,
+		Limit:   logViewWindow,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.JobUuid != "" {
+		v.jobUUID = resp.JobUuid
+	}
+	for _, r := range resp.Records {
+		v.records[r.Offset] = newViewRecord(r)
+	}
+	if resp.End && (len(resp.Records) > 0 || start == 0) {
+		v.total = start + int64(len(resp.Records))
+	}
+	return nil
+}
+
+// screenRow is a row of log on screen
+type screenRow struct {
+	text       string
+	stderr     bool
+	bookmarked bool
+}
+
+// visible returns up to a page of rows from the top of the screen
+func (v *logViewer) visible() ([]screenRow, error) {
+	var rows []screenRow
+	row := v.row
+	for offset := v.top; len(rows) < v.page(); offset++ {
+		r, err := v.record(offset)
+		if err != nil || r == nil {
+			return rows, err
+		}
+		_, bookmarked := v.bookmarks[offset]
+		for _, text := range r.wrapped(v.rowWidth())[min(row, len(r.wrapped(v.rowWidth()))-1):] {
+			if len(rows) == v.page() {
+				break
+			}
+			rows = append(rows, screenRow{text: text, stderr: r.stderr, bookmarked: bookmarked})
+		}
+		row = 0
+	}
+	return rows, nil
+}
+
+func (v *logViewer) down(n int) {
+	for ; n > 0; n-- {
+		r, err := v.record(v.top)
+		if err != nil {
+			v.fail(err)
+			return
+		}
+		if r == nil {
+			break
+		}
+		if v.row+1 < len(r.wrapped(v.rowWidth())) {
+			v.row++
+			continue
+		}
+		next, err := v.record(v.top + 1)
+		if err != nil {
+			v.fail(err)
+			return
+		}
+		if next == nil {
+			break
+		}
+		v.top, v.row = v.top+1, 0
+	}
+	v.settle()
+}
+
+func (v *logViewer) up(n int) {
+	for ; n > 0; n-- {
+		if v.row > 0 {
+			v.row--
+			continue
+		}
+		prev, err := v.record(v.top - 1)
+		if err != nil {
+			v.fail(err)
+			return
+		}
+		if prev == nil {
+			return
+		}
+		v.top, v.row = v.top-1, len(prev.wrapped(v.rowWidth()))-1
+	}
+}
+
+// settle scrolls back up when the end of the log leaves part of the screen
+// empty, so the last page is always full like in less
+func (v *logViewer) settle() {
+	rows, err := v.visible()
+	if err != nil {
+		v.fail(err)
+		return
+	}
+	if len(rows) < v.page() {
+		v.up(v.page() - len(rows))
+	}
+}
+
+func (v *logViewer) home() {
+	v.top, v.row = 0, 0
+}
+
+// end finds the current end of the log, which grows while the job runs
+func (v *logViewer) end() {
+	loc, err := v.src.LocateLogRecord(v.ctx, &logspb.LocateLogRecordRequest{JobUuid: v.jobID, Stream: v.stream, From: -1})
+	if err != nil {
+		v.fail(err)
+		return
+	}
+	v.total = loc.Records
+	if v.total == 0 {
+		v.home()
+		return
+	}
+	last, err := v.record(v.total - 1)
+	if err != nil {
+		v.fail(err)
+		return
+	}
+	if last == nil {
+		v.message = "Couldn't read the end of the log"
+		return
+	}
+	v.top, v.row = v.total-1, len(last.wrapped(v.rowWidth()))-1
+	v.up(v.page() - 1)
+}
+
+func (v *logViewer) showProgress(msg string) {
+	if v.progress != nil {
+		v.progress(msg)
+	}
+}
+
+// search starts a search; an empty pattern repeats the last one
+func (v *logViewer) search(pattern string, backward bool) {
+	if pattern != "" {
+		v.pattern = pattern
+		v.highlight = nil
+		if re, err := regexp.Compile(searchExpr(pattern, v.regex)); err == nil {
+			v.highlight = re
+		}
+	}
+	v.backward = backward
+	v.find(backward, true)
+}
+
+// searchExpr is the expression the node searches with, for highlighting
+func searchExpr(pattern string, regex bool) string {
+	expr := "(?m)" + pattern
+	if !regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase(pattern) {
+		expr = "(?i)" + expr
+	}
+	return expr
+}
+
+// ignoreCase makes searches without upper case letters case-insensitive
+func ignoreCase(pattern string) bool {
+	return pattern == strings.ToLower(pattern)
+}
+
+// find moves to the next match; a new forward search includes the top
+// record when it is shown from its first line
+func (v *logViewer) find(backward, first bool) {
+	if v.pattern == "" {
+		v.message = "No previous search"
+		return
+	}
+	from := v.top
+	if first && !backward && v.row == 0 {
+		from--
+	}
+	v.showProgress("Searching...")
+	loc, err := v.src.LocateLogRecord(v.ctx, &logspb.LocateLogRecordRequest{
+		JobUuid:    v.jobID,
+		Stream:     v.stream,
+		From:       from,
+		Pattern:    v.pattern,
+		Regex:      v.regex,
+		IgnoreCase: ignoreCase(v.pattern),
+		Backward:   backward,
+	})
+	if err != nil {
+		v.fail(err)
+		return
+	}
+	if !loc.Found {
+		v.message = "Pattern not found: " + v.pattern
+		return
+	}
+	v.top, v.row = loc.Offset, 0
+	v.message = ""
+}
+
+// jumpToTime moves to the first record at or after a time
+func (v *logViewer) jumpToTime(input string) {
+	ref := time.Now()
+	if r, _ := v.record(v.top); r != nil {
+		ref = time.Unix(0, r.timestamp)
+	}
+	t, err := parseLogTime(input, ref)
+	if err != nil {
+		v.message = err.Error()
+		return
+	}
+	v.showProgress("Searching...")
+	loc, err := v.src.LocateLogRecord(v.ctx, &logspb.LocateLogRecordRequest{
+		JobUuid:   v.jobID,
+		Stream:    v.stream,
+		From:      -1,
+		Timestamp: t.UnixNano(),
+	})
+	if err != nil {
+		v.fail(err)
+		return
+	}
+	if !loc.Found {
+		v.message = "No records at or after " + t.Format(time.DateTime)
+		return
+	}
+	v.top, v.row = loc.Offset, 0
+	v.message = ""
+}
+
+// parseLogTime parses an RFC3339 time, a local date and time, or a local
+// time of day on the day of ref
+func parseLogTime(input string, ref time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if t, err := time.Parse(time.RFC3339Nano, input); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateTime, input, ref.Location()); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if t, err := time.ParseInLocation(layout, input, ref.Location()); err == nil {
+			year, month, day := ref.Date()
+			return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, ref.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use RFC3339, YYYY-MM-DD HH:MM:SS or HH:MM[:SS]", input)
+}
+
+// toggleBookmark bookmarks the top record, or removes its bookmark
+func (v *logViewer) toggleBookmark() {
+	if _, ok := v.bookmarks[v.top]; ok {
+		delete(v.bookmarks, v.top)
+		v.message = fmt.Sprintf("Removed bookmark on record %d", v.top+1)
+		return
+	}
+	r, err := v.record(v.top)
+	if err != nil || r == nil {
+		v.message = "Nothing to bookmark"
+		return
+	}
+	stream := "stdout"
+	if r.stderr {
+		stream = "stderr"
+	}
+	v.bookmarks[v.top] = logBookmark{
+		Record: v.top + 1,
+		Time:   time.Unix(0, r.timestamp).UTC().Format(time.RFC3339Nano),
+		Stream: stream,
+		Line:   r.lines[0],
+	}
+	v.message = fmt.Sprintf("Bookmarked record %d (%d bookmarks)", v.top+1, len(v.bookmarks))
+}
+
+func (v *logViewer) sortedBookmarks() []int64 {
+	offsets := make([]int64, 0, len(v.bookmarks))
+	for offset := range v.bookmarks {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets
+}
+
+// nextBookmark moves to the bookmark after or before the top record
+func (v *logViewer) nextBookmark(backward bool) {
+	offsets := v.sortedBookmarks()
+	if backward {
+		for i := len(offsets) - 1; i >= 0; i-- {
+			if offsets[i] < v.top {
+				v.top, v.row = offsets[i], 0
+				return
+			}
+		}
+	} else {
+		for _, offset := range offsets {
+			if offset > v.top {
+				v.top, v.row = offset, 0
+				return
+			}
+		}
+	}
+	v.message = "No more bookmarks"
+}
+
+// exportBookmarks writes the bookmarks as JSON to .json files and as text
+// otherwise
+func (v *logViewer) exportBookmarks(path string) error {
+	var bookmarks []logBookmark
+	for _, offset := range v.sortedBookmarks() {
+		bookmarks = append(bookmarks, v.bookmarks[offset])
+	}
+
+	var data []byte
+	if strings.HasSuffix(path, ".json") {
+		var err error
+		data, err = json.MarshalIndent(map[string]interface{}{
+			"job_uuid":  v.jobUUID,
+			"bookmarks": bookmarks,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Bookmarks in the log of job %s\n", v.jobUUID)
+		for _, bm := range bookmarks {
+			fmt.Fprintf(&b, "%d\t%s\t%s\t%s\n", bm.Record, bm.Time, bm.Stream, bm.Line)
+		}
+		data = []byte(b.String())
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (v *logViewer) ask(prompt, initial string, onInput func(string)) {
+	v.prompt, v.input, v.onInput, v.message = prompt, []rune(initial), onInput, ""
+}
+
+// handleKey applies a key and reports whether the viewer should quit
+func (v *logViewer) handleKey(key string) bool {
+	if v.prompt != "" {
+		switch key {
+		case "enter":
+			onInput, input := v.onInput, string(v.input)
+			v.prompt, v.input, v.onInput = "", nil, nil
+			onInput(input)
+		case "esc", "ctrl-c":
+			v.prompt, v.input, v.onInput = "", nil, nil
+		case "backspace":
+			if len(v.input) > 0 {
+				v.input = v.input[:len(v.input)-1]
+			}
+		default:
+			if runes := []rune(key); len(runes) == 1 && runes[0] >= ' ' {
+				v.input = append(v.input, runes[0])
+			}
+		}
+		return false
+	}
+
+	v.message = ""
+	switch key {
+	case "q", "ctrl-c":
+		return true
+	case "j", "down", "enter":
+		v.down(1)
+	case "k", "up":
+		v.up(1)
+	case " ", "f", "pgdown":
+		v.down(v.page())
+	case "b", "pgup":
+		v.up(v.page())
+	case "d":
+		v.down(v.page() / 2)
+	case "u":
+		v.up(v.page() / 2)
+	case "g", "home":
+		v.home()
+	case "G", "end":
+		v.end()
+	case "/":
+		v.ask("/", "", func(pattern string) { v.search(pattern, false) })
+	case "?":
+		v.ask("?", "", func(pattern string) { v.search(pattern, true) })
+	case "n":
+		v.find(v.backward, false)
+	case "N":
+		v.find(!v.backward, false)
+	case "t":
+		v.ask("Jump to time: ", "", v.jumpToTime)
+	case "m":
+		v.toggleBookmark()
+	case "]":
+		v.nextBookmark(false)
+	case "[":
+		v.nextBookmark(true)
+	case "w":
+		if len(v.bookmarks) == 0 {
+			v.message = "No bookmarks, bookmark the top record with m"
+			break
+		}
+		v.ask("Export bookmarks to: ", v.bookmarksPath, func(path string) {
+			if path == "" {
+				return
+			}
+			if err := v.exportBookmarks(path); err != nil {
+				v.message = err.Error()
+				return
+			}
+			v.bookmarksPath = path
+			v.message = fmt.Sprintf("Exported %d bookmarks to %s", len(v.bookmarks), path)
+		})
+	case "h":
+		v.message = logViewHelp
+	}
+	return false
+}
+
+// render draws the screen: the log rows, then an inverse status line
+func (v *logViewer) render(w io.Writer) {
+	rows, err := v.visible()
+	if err != nil {
+		v.fail(err)
+	}
+
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for i := 0; i < v.page(); i++ {
+		if i >= len(rows) {
+			b.WriteString("~\033[K\r\n")
+			continue
+		}
+		row := rows[i]
+		if row.bookmarked {
+			b.WriteString("\033[33m*\033[0m")
+		} else {
+			b.WriteByte(' ')
+		}
+		if row.stderr {
+			b.WriteString("\033[31m")
+		}
+		b.WriteString(v.highlighted(row.text))
+		b.WriteString("\033[0m\033[K\r\n")
+	}
+
+	b.WriteString("\033[7m")
+	b.WriteString(fitWidth(v.statusLine(), v.width))
+	b.WriteString("\033[0m")
+	if v.prompt != "" {
+		b.WriteString("\033[?25h")
+	} else {
+		b.WriteString("\033[?25l")
+	}
+	fmt.Fprint(w, b.String())
+}
+
+func (v *logViewer) highlighted(text string) string {
+	if v.highlight == nil {
+		return text
+	}
+	return v.highlight.ReplaceAllStringFunc(text, func(match string) string {
+		return "\033[7m" + match + "\033[27m"
+	})
+}
+
+func (v *logViewer) statusLine() string {
+	if v.prompt != "" {
+		return v.prompt + string(v.input)
+	}
+
+	total := "?"
+	if v.total >= 0 {
+		total = fmt.Sprint(v.total)
+	}
+	status := fmt.Sprintf(" %s  record %d/%s", shortUUID(v.jobUUID), v.top+1, total)
+	if v.total == 0 {
+		status = fmt.Sprintf(" %s  empty log", shortUUID(v.jobUUID))
+	}
+	if r, _ := v.record(v.top); r != nil {
+		status += "  " + time.Unix(0, r.timestamp).Format("2006-01-02 15:04:05.000")
+	}
+	if v.stream != logspb.LogStream_LOG_STREAM_ALL {
+		status += "  [" + strings.ToLower(strings.TrimPrefix(v.stream.String(), "LOG_STREAM_")) + "]"
+	}
+	if len(v.bookmarks) > 0 {
+		status += fmt.Sprintf("  %d bookmarks", len(v.bookmarks))
+	}
+	if v.message != "" {
+		return status + "  " + v.message
+	}
+	return status + "  (h for help)"
+}
+
+func shortUUID(uuid string) string {
+	if len(uuid) > 8 {
+		return uuid[:8]
+	}
+	return uuid
+}
+
+// fitWidth pads or cuts s to width columns
+func fitWidth(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// readKey reads a key press from a terminal in raw mode
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case 3:
+		return "ctrl-c", nil
+	case '\r', '\n':
+		return "enter", nil
+	case 127, 8:
+		return "backspace", nil
+	case 27:
+		// A lone escape arrives alone, escape sequences in one read
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		if next, _, err := r.ReadRune(); err != nil || (next != '[' && next != 'O') {
+			return "esc", err
+		}
+		seq := ""
+		for {
+			c, _, err := r.ReadRune()
+			if err != nil {
+				return "", err
+			}
+			seq += string(c)
+			if c >= '@' && c <= '~' {
+				break
+			}
+		}
+		switch seq {
+		case "A":
+			return "up", nil
+		case "B":
+			return "down", nil
+		case "H", "1~", "7~":
+			return "home", nil
+		case "F", "4~", "8~":
+			return "end", nil
+		case "5~":
+			return "pgup", nil
+		case "6~":
+			return "pgdown", nil
+		}
+		return "", nil
+	}
+	return string(c), nil
+}
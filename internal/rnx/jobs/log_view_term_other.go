@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package jobs
+
+import "errors"
+
+var errNoTerminal = errors.New("the log viewer is not supported on this platform")
+
+func terminalSize(int) (int, int, error) { return 0, 0, errNoTerminal }
+
+func makeRaw(int) (func(), error) { return nil, errNoTerminal }
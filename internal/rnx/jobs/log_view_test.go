@@ -0,0 +1,290 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+)
+
+// fakeLogSource serves records from memory the way the log service does
+type fakeLogSource struct {
+	records []*logspb.LogRecord
+	reads   int
+}
+
+func newFakeLogSource(contents ...string) *fakeLogSource {
+	f := &fakeLogSource{}
+	for i, content := range contents {
+		f.records = append(f.records, &logspb.LogRecord{
+			Offset:    int64(i),
+			Timestamp: time.Date(2026, 10, 16, 12, 0, i, 0, time.UTC).UnixNano(),
+			Content:   []byte(content),
+		})
+	}
+	return f
+}
+
+func (f *fakeLogSource) ReadLogRange(_ context.Context, req *logspb.ReadLogRangeRequest) (*logspb.LogRange, error) {
+	f.reads++
+	resp := &logspb.LogRange{JobUuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479"}
+	end := req.Offset + int64(req.Limit)
+	if end > int64(len(f.records)) {
+		end = int64(len(f.records))
+	}
+	if req.Offset < end {
+		resp.Records = f.records[req.Offset:end]
+	}
+	resp.End = end == int64(len(f.records))
+	return resp, nil
+}
+
+func (f *fakeLogSource) LocateLogRecord(_ context.Context, req *logspb.LocateLogRecordRequest) (*logspb.LogLocation, error) {
+	re := regexp.MustCompile(searchExpr(req.Pattern, req.Regex))
+	matches := func(r *logspb.LogRecord) bool {
+		if req.Timestamp > 0 {
+			return r.Timestamp >= req.Timestamp
+		}
+		return req.Pattern != "" && re.Match(r.Content)
+	}
+	if req.Backward {
+		for i := req.From - 1; i >= 0; i-- {
+			if matches(f.records[i]) {
+				return &logspb.LogLocation{Found: true, Offset: i, Timestamp: f.records[i].Timestamp}, nil
+			}
+		}
+		return &logspb.LogLocation{}, nil
+	}
+	for i := req.From + 1; i < int64(len(f.records)); i++ {
+		if matches(f.records[i]) {
+			return &logspb.LogLocation{Found: true, Offset: i, Timestamp: f.records[i].Timestamp}, nil
+		}
+	}
+	return &logspb.LogLocation{Records: int64(len(f.records))}, nil
+}
+
+func numberedRecords(n int) []string {
+	records := make([]string, n)
+	for i := range records {
+		records[i] = "line " + strings.Repeat("x", i%3) + "\n"
+	}
+	return records
+}
+
+func newTestViewer(src logSource) *logViewer {
+	v := newLogViewer(context.Background(), src, "f47ac10b", logspb.LogStream_LOG_STREAM_ALL)
+	v.resize(40, 6)
+	return v
+}
+
+func TestLogViewerScrolling(t *testing.T) {
+	src := newFakeLogSource(numberedRecords(1000)...)
+	v := newTestViewer(src)
+
+	v.handleKey(" ")
+	if v.top != 5 || v.row != 0 {
+		t.Errorf("after a page at %d/%d, want record 5", v.top, v.row)
+	}
+	v.handleKey("k")
+	v.handleKey("G")
+	if v.total != 1000 || v.top != 995 {
+		t.Errorf("end at %d of %d, want the last page from 995 of 1000", v.top, v.total)
+	}
+	// The last page stays full
+	v.handleKey("j")
+	if v.top != 995 {
+		t.Errorf("scrolled past the end to %d", v.top)
+	}
+	v.handleKey("g")
+	if v.top != 0 {
+		t.Errorf("home at %d", v.top)
+	}
+	// Only the windows on screen were read
+	if src.reads > 3 {
+		t.Errorf("read %d windows, want the first and the last", src.reads)
+	}
+}
+
+func TestLogViewerWrapsLongRecords(t *testing.T) {
+	// 39 columns after the bookmark marker: 100 characters take 3 rows
+	v := newTestViewer(newFakeLogSource(strings.Repeat("a", 100)+"\nsecond\n", "third\n"))
+	rows, err := v.visible()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || rows[3].text != "second" || rows[4].text != "third" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	v.down(2)
+	if v.top != 0 || v.row != 0 {
+		t.Errorf("scrolled a log shorter than the screen to %d/%d", v.top, v.row)
+	}
+}
+
+func TestLogViewerSearch(t *testing.T) {
+	records := numberedRecords(50)
+	records[10] = "ERROR disk full\n"
+	records[30] = "error: retrying\n"
+	records[0] = "error at start\n"
+	v := newTestViewer(newFakeLogSource(records...))
+
+	// A new search from the first line includes the top record
+	v.handleKey("/")
+	for _, key := range []string{"e", "r", "r", "o", "r", "enter"} {
+		v.handleKey(key)
+	}
+	if v.top != 0 {
+		t.Errorf("first match at %d, want 0", v.top)
+	}
+	v.handleKey("n")
+	if v.top != 10 {
+		t.Errorf("next match at %d, want 10 (case-insensitive)", v.top)
+	}
+	v.handleKey("n")
+	v.handleKey("N")
+	if v.top != 10 {
+		t.Errorf("previous match at %d, want 10", v.top)
+	}
+	v.handleKey("n")
+	v.handleKey("n")
+	if v.top != 30 || !strings.Contains(v.message, "Pattern not found") {
+		t.Errorf("past the last match at %d with %q", v.top, v.message)
+	}
+
+	var out bytes.Buffer
+	v.render(&out)
+	if !strings.Contains(out.String(), "\033[7merror\033[27m: retrying") {
+		t.Errorf("match not highlighted in %q", out.String())
+	}
+
+	// Upper case letters make the search case-sensitive
+	v.handleKey("g")
+	v.search("ERROR", false)
+	if v.top != 10 {
+		t.Errorf("case-sensitive match at %d, want 10", v.top)
+	}
+}
+
+func TestLogViewerJumpToTime(t *testing.T) {
+	v := newTestViewer(newFakeLogSource(numberedRecords(50)...))
+	v.jumpToTime("2026-10-16T12:00:20Z")
+	if v.top != 20 {
+		t.Errorf("jumped to %d, want 20", v.top)
+	}
+	v.jumpToTime("2026-10-17T00:00:00Z")
+	if v.top != 20 || !strings.Contains(v.message, "No records at or after") {
+		t.Errorf("jump past the end to %d with %q", v.top, v.message)
+	}
+}
+
+func TestParseLogTime(t *testing.T) {
+	ref := time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2026-10-15T23:00:00Z": time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC),
+		"2026-10-15 23:00:05":  time.Date(2026, 10, 15, 23, 0, 5, 0, time.UTC),
+		"14:05":                time.Date(2026, 10, 16, 14, 5, 0, 0, time.UTC),
+		"14:05:09":             time.Date(2026, 10, 16, 14, 5, 9, 0, time.UTC),
+	}
+	for input, want := range tests {
+		got, err := parseLogTime(input, ref)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseLogTime(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := parseLogTime("yesterday", ref); err == nil {
+		t.Error("parseLogTime accepted yesterday")
+	}
+}
+
+func TestLogViewerBookmarks(t *testing.T) {
+	records := numberedRecords(50)
+	records[12] = "\tcheckpoint saved\n"
+	v := newTestViewer(newFakeLogSource(records...))
+	v.bookmarksPath = filepath.Join(t.TempDir(), "marks.txt")
+
+	v.handleKey("w")
+	if v.prompt != "" || !strings.Contains(v.message, "No bookmarks") {
+		t.Errorf("export without bookmarks: prompt %q, message %q", v.prompt, v.message)
+	}
+
+	v.top = 40
+	v.handleKey("m")
+	v.top = 12
+	v.handleKey("m")
+	v.handleKey("]")
+	if v.top != 40 {
+		t.Errorf("next bookmark at %d, want 40", v.top)
+	}
+	v.handleKey("[")
+	if v.top != 12 {
+		t.Errorf("previous bookmark at %d, want 12", v.top)
+	}
+
+	// The prompt starts with the --bookmarks file
+	v.handleKey("w")
+	v.handleKey("enter")
+	data, err := os.ReadFile(v.bookmarksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[1] != "13\t2026-10-16T12:00:12Z\tstdout\t    checkpoint saved" || !strings.HasPrefix(lines[2], "41\t") {
+		t.Errorf("text export =\n%s", data)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "marks.json")
+	if err := v.exportBookmarks(jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	var exported struct {
+		JobUUID   string        `json:"job_uuid"`
+		Bookmarks []logBookmark `json:"bookmarks"`
+	}
+	data, _ = os.ReadFile(jsonPath)
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if exported.JobUUID != "f47ac10b-58cc-4372-a567-0e02b2c3d479" || len(exported.Bookmarks) != 2 || exported.Bookmarks[0].Record != 13 {
+		t.Errorf("JSON export = %+v", exported)
+	}
+
+	v.handleKey("m")
+	if len(v.bookmarks) != 1 {
+		t.Errorf("m on a bookmarked record left %d bookmarks", len(v.bookmarks))
+	}
+}
+
+func TestLogViewerRenderEscapesContent(t *testing.T) {
+	v := newTestViewer(newFakeLogSource("\033[2Jcleared\n"))
+	var out bytes.Buffer
+	v.render(&out)
+	if !strings.Contains(out.String(), "^[[2Jcleared") {
+		t.Errorf("escape sequence in the log reached the terminal: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "record 1/1") {
+		t.Errorf("status line missing in %q", out.String())
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("j\033[A\033[6~\r\x7f/"))
+	var keys []string
+	for range 6 {
+		key, err := readKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	if got := strings.Join(keys, ","); got != "j,up,pgdown,enter,backspace,/" {
+		t.Errorf("keys = %s", got)
+	}
+}
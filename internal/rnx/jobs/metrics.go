@@ -44,19 +44,28 @@ Examples:
   # Output as JSON (one sample per line)
   rnx --json job metrics f47ac10b
 
+  # Follow the job's network namespace: bandwidth, TCP connections and
+  # retransmits, with a summary when the job ends
+  rnx job metrics --network a1b2c3d4
+
 Metrics Include:
   • CPU: Usage %, user/system time, throttling
   • Memory: Current/peak usage, anonymous/file cache, page faults
   • I/O: Read/write bandwidth, IOPS, total bytes
-  • Network: RX/TX bytes/packets, bandwidth
+  • Network: RX/TX bytes/packets, bandwidth (jobs with a network namespace of their own)
   • Process: Count, threads, open file descriptors
   • GPU: Utilization, memory, temperature, power (if allocated)`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if network, _ := cmd.Flags().GetBool("network"); network {
+				return runNetworkMetrics(args[0])
+			}
 			return runMetrics(cmd, args)
 		},
 	}
 
+	cmd.Flags().Bool("network", false, "Show only network counters, with TCP connections and retransmits")
+
 	return cmd
 }
 
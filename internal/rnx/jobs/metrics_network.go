@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	jobnetpb "github.com/ehsaniara/joblet/internal/proto/gen/jobnet"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"google.golang.org/grpc/status"
+)
+
+// runNetworkMetrics follows the network counters of a job's namespace and
+// sums them up when the stream ends
+func runNetworkMetrics(jobID string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	stream, err := jobClient.StreamJobNetworkMetrics(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("couldn't start reading network metrics: %v", err)
+	}
+
+	var first, last *jobnetpb.JobNetworkSample
+	var peakConnections uint64
+	encoder := json.NewEncoder(os.Stdout)
+	for {
+		sample, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			if s, ok := status.FromError(err); ok {
+				return fmt.Errorf("problem reading network metrics: %v", s.Message())
+			}
+			return fmt.Errorf("error receiving network metrics stream: %v", err)
+		}
+
+		if first == nil {
+			first = sample
+			if !common.JSONOutput {
+				fmt.Printf("%-8s  %12s  %12s  %11s  %11s\n", "TIME", "RX/s", "TX/s", "CONNECTIONS", "RETRANSMITS")
+			}
+		}
+		last = sample
+		peakConnections = max(peakConnections, sample.ActiveConnections)
+
+		if common.JSONOutput {
+			if err := encoder.Encode(sample); err != nil {
+				return fmt.Errorf("couldn't format output as JSON: %v", err)
+			}
+			continue
+		}
+		fmt.Println(formatNetworkSample(sample))
+	}
+
+	if last == nil {
+		return fmt.Errorf("no network metrics available for job %s (it may share the host's network)", jobID)
+	}
+	if !common.JSONOutput {
+		fmt.Print(formatNetworkSummary(first, last, peakConnections))
+	}
+	return nil
+}
+
+// formatNetworkSample renders a sample as a row of the network table
+func formatNetworkSample(sample *jobnetpb.JobNetworkSample) string {
+	return fmt.Sprintf("%-8s  %10s/s  %10s/s  %11d  %11d",
+		time.Unix(0, sample.Timestamp).Format("15:04:05"),
+		formatBytesFloat(sample.RxBps), formatBytesFloat(sample.TxBps),
+		sample.ActiveConnections, sample.Retransmits)
+}
+
+// formatNetworkSummary sums up the samples a stream sent: the traffic and
+// retransmits over the samples, and their average bandwidth
+func formatNetworkSummary(first, last *jobnetpb.JobNetworkSample, peakConnections uint64) string {
+	summary := "\nNetwork Summary:\n"
+	summary += fmt.Sprintf("  Received: %s (%d packets)\n", formatBytesUint(last.RxBytes), last.RxPackets)
+	summary += fmt.Sprintf("  Sent: %s (%d packets)\n", formatBytesUint(last.TxBytes), last.TxPackets)
+	if elapsed := time.Duration(last.Timestamp - first.Timestamp).Seconds(); elapsed > 0 {
+		summary += fmt.Sprintf("  Average: %s/s in, %s/s out\n",
+			formatBytesFloat(float64(last.RxBytes-first.RxBytes)/elapsed),
+			formatBytesFloat(float64(last.TxBytes-first.TxBytes)/elapsed))
+	}
+	summary += fmt.Sprintf("  Peak Connections: %d\n", peakConnections)
+	summary += fmt.Sprintf("  TCP Retransmits: %d\n", last.Retransmits)
+	return summary
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	jobnetpb "github.com/ehsaniara/joblet/internal/proto/gen/jobnet"
+)
+
+func TestFormatNetworkSummary(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := &jobnetpb.JobNetworkSample{Timestamp: start.UnixNano(), RxBytes: 1024, TxBytes: 0}
+	last := &jobnetpb.JobNetworkSample{
+		Timestamp: start.Add(10 * time.Second).UnixNano(),
+		RxBytes:   1024 + 10*2048, TxBytes: 10 * 1024, RxPackets: 300, TxPackets: 120, Retransmits: 9,
+	}
+
+	summary := formatNetworkSummary(first, last, 4)
+	for _, want := range []string{
+		"Received: 21.0 KiB (300 packets)",
+		"Sent: 10.0 KiB (120 packets)",
+		"Average: 2.0 KiB/s in, 1.0 KiB/s out",
+		"Peak Connections: 4",
+		"TCP Retransmits: 9",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary lacks %q:\n%s", want, summary)
+		}
+	}
+
+	// A single sample has no interval to average over
+	if summary := formatNetworkSummary(last, last, 4); strings.Contains(summary, "Average") {
+		t.Errorf("summary of one sample reports an average:\n%s", summary)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/client"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
 
@@ -35,6 +37,9 @@ All commands connect to the remote joblet server and support JSON output for das
 	cmd.AddCommand(NewMonitorStatusCmd())
 	cmd.AddCommand(NewMonitorTopCmd())
 	cmd.AddCommand(NewMonitorWatchCmd())
+	cmd.AddCommand(NewMonitorCapacityCmd())
+	cmd.AddCommand(NewMonitorHistoryCmd())
+	cmd.AddCommand(NewMonitorOrphansCmd())
 
 	return cmd
 }
@@ -154,7 +159,7 @@ func runMonitorStatus(jsonOutput bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := jobClient.GetSystemStatus(ctx)
+	resp, report, err := jobClient.GetSystemStatusReport(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get system status: %v", err)
 	}
@@ -162,6 +167,8 @@ func runMonitorStatus(jsonOutput bool) error {
 	if jsonOutput {
 		// Transform to UI-expected format
 		uiData := transformToUIFormat(resp)
+		uiData.ClockInfo = report.Clock
+		uiData.RateLimitInfo = report.RateLimit
 		data, err := json.MarshalIndent(uiData, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal JSON: %v", err)
@@ -170,7 +177,7 @@ func runMonitorStatus(jsonOutput bool) error {
 		return nil
 	}
 
-	displaySystemStatus(resp)
+	displaySystemStatus(resp, report)
 	return nil
 }
 
@@ -281,7 +288,7 @@ func runMonitorWatch(interval int, metricTypes []string, compact bool, jsonOutpu
 
 // Display functions
 
-func displaySystemStatus(status *pb.SystemStatusRes) {
+func displaySystemStatus(status *pb.SystemStatusRes, report *client.StatusReport) {
 	fmt.Printf("System Status - %s\n", status.Timestamp)
 	fmt.Printf("Available: %v\n\n", status.Available)
 
@@ -335,6 +342,13 @@ func displaySystemStatus(status *pb.SystemStatusRes) {
 		fmt.Println()
 	}
 
+	if report.Clock != nil {
+		displayClockStatus(report.Clock)
+	}
+	if report.RateLimit != nil {
+		displayRateLimitStatus(report.RateLimit)
+	}
+
 	// CPU Information
 	if status.Cpu != nil {
 		fmt.Printf("CPU:\n")
@@ -802,6 +816,10 @@ type UIFormat struct {
 	DisksInfo     UIDisksInfo     `json:"disksInfo"`
 	NetworkInfo   UINetworkInfo   `json:"networkInfo"`
 	ProcessesInfo UIProcessesInfo `json:"processesInfo"`
+	// Latest check of the server's clock, when the server made one
+	ClockInfo *client.ClockStatus `json:"clockInfo,omitempty"`
+	// Calls allowed and throttled, when the server limits them
+	RateLimitInfo *client.RateLimitStats `json:"rateLimitInfo,omitempty"`
 }
 
 type UIHostInfo struct {
@@ -904,6 +922,55 @@ type UIProcessInfo struct {
 	Threads     int32   `json:"threads,omitempty"` // Only show if available
 }
 
+// displayClockStatus shows the latest check of the server's clock, which
+// scheduled jobs start by
+func displayClockStatus(clock *client.ClockStatus) {
+	against := "client clock"
+	if clock.Source == "ntp" {
+		against = clock.Reference
+	}
+	offset := clock.Offset.Round(time.Millisecond)
+	direction := "ahead of"
+	if offset < 0 {
+		offset, direction = -offset, "behind"
+	}
+	state := "in sync"
+	if clock.Drifted {
+		state = fmt.Sprintf("DRIFTED (more than %s off)", clock.MaxDrift)
+	}
+
+	fmt.Printf("Clock:\n")
+	fmt.Printf("  Status:       %s\n", state)
+	fmt.Printf("  Offset:       %s %s %s (±%s)\n", offset, direction, against, clock.Uncertainty.Round(time.Millisecond))
+	fmt.Printf("  Checked:      %s\n", clock.CheckedAt.Format(time.RFC3339))
+	if clock.Error != "" {
+		fmt.Printf("  Last Error:   %s\n", clock.Error)
+	}
+	fmt.Println()
+}
+
+// displayRateLimitStatus shows how many calls the server's rate limiter
+// allowed and throttled, with the clients throttled most first
+func displayRateLimitStatus(stats *client.RateLimitStats) {
+	fmt.Printf("Rate Limiting:\n")
+	fmt.Printf("  Allowed:      %d\n", stats.Allowed)
+	fmt.Printf("  Throttled:    %d\n", stats.Throttled)
+	clients := make([]string, 0, len(stats.ThrottledByClient))
+	for name := range stats.ThrottledByClient {
+		clients = append(clients, name)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if a, b := stats.ThrottledByClient[clients[i]], stats.ThrottledByClient[clients[j]]; a != b {
+			return a > b
+		}
+		return clients[i] < clients[j]
+	})
+	for _, name := range clients {
+		fmt.Printf("    %-20s %d throttled\n", name, stats.ThrottledByClient[name])
+	}
+	fmt.Println()
+}
+
 // transformToUIFormat converts the protobuf response to UI-expected format
 func transformToUIFormat(resp *pb.SystemStatusRes) *UIFormat {
 	// Calculate total space for disks (excluding duplicates and snaps)
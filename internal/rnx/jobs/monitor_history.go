@@ -0,0 +1,174 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// sparkBlocks are the bar heights of a chart column, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func NewMonitorHistoryCmd() *cobra.Command {
+	var since, until time.Duration
+	var width int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Chart the server metrics recorded in the past",
+		Long: `Chart the host metrics the joblet server recorded to persist.
+
+The server samples CPU, memory, disk and network every monitoring.history
+interval and keeps the samples for its retention period (7 days by default).
+Each chart column shows the highest value of the samples in its time slice,
+so short spikes stay visible; empty columns are times without samples, such
+as when the server was down.
+
+Examples:
+  rnx monitor history                           # The last 24 hours
+  rnx monitor history --since 12h --until 6h    # From 12 to 6 hours ago
+  rnx monitor history --since 168h --width 120  # A wider chart of the week
+  rnx monitor history --since 1h --json         # The samples as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMonitorHistory(since, until, width, common.JSONOutput)
+		},
+	}
+
+	cmd.Flags().DurationVar(&since, "since", 24*time.Hour, "Start this long ago")
+	cmd.Flags().DurationVar(&until, "until", 0, "End this long ago (0 = now)")
+	cmd.Flags().IntVar(&width, "width", 60, "Chart width in columns")
+
+	return cmd
+}
+
+type nodeMetricsSampleJSON struct {
+	Time         time.Time `json:"time"`
+	CPUCores     float64   `json:"cpuCores"`
+	MemoryBytes  int64     `json:"memoryUsedBytes"`
+	DiskReadBps  float64   `json:"diskReadBps"`
+	DiskWriteBps float64   `json:"diskWriteBps"`
+	NetRxBps     float64   `json:"networkRxBps"`
+	NetTxBps     float64   `json:"networkTxBps"`
+}
+
+func runMonitorHistory(since, until time.Duration, width int, jsonOutput bool) error {
+	if since <= until {
+		return fmt.Errorf("--since must be further back than --until")
+	}
+	if width < 1 {
+		return fmt.Errorf("--width must be positive")
+	}
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	start, end := now.Add(-since), now.Add(-until)
+	samples, err := jobClient.QueryNodeMetrics(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to query metrics history: %w", err)
+	}
+
+	if jsonOutput {
+		out := make([]nodeMetricsSampleJSON, 0, len(samples))
+		for _, s := range samples {
+			out = append(out, nodeMetricsSampleJSON{
+				Time:         time.Unix(0, s.Timestamp),
+				CPUCores:     s.CpuCores,
+				MemoryBytes:  s.MemoryUsedBytes,
+				DiskReadBps:  s.DiskReadBps,
+				DiskWriteBps: s.DiskWriteBps,
+				NetRxBps:     s.NetworkRxBps,
+				NetTxBps:     s.NetworkTxBps,
+			})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Server metrics from %s to %s (%d samples)\n\n",
+		start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"), len(samples))
+	if len(samples) == 0 {
+		fmt.Println("No samples recorded in this period.")
+		return nil
+	}
+
+	rate := func(v float64) string { return formatBytes(int64(v)) + "/s" }
+	for _, series := range []struct {
+		name   string
+		value  func(*nodemetricspb.NodeMetricsSample) float64
+		format func(float64) string
+	}{
+		{"CPU cores", func(s *nodemetricspb.NodeMetricsSample) float64 { return s.CpuCores },
+			func(v float64) string { return fmt.Sprintf("%.1f", v) }},
+		{"Memory", func(s *nodemetricspb.NodeMetricsSample) float64 { return float64(s.MemoryUsedBytes) },
+			func(v float64) string { return formatBytes(int64(v)) }},
+		{"Disk read", func(s *nodemetricspb.NodeMetricsSample) float64 { return s.DiskReadBps }, rate},
+		{"Disk write", func(s *nodemetricspb.NodeMetricsSample) float64 { return s.DiskWriteBps }, rate},
+		{"Net rx", func(s *nodemetricspb.NodeMetricsSample) float64 { return s.NetworkRxBps }, rate},
+		{"Net tx", func(s *nodemetricspb.NodeMetricsSample) float64 { return s.NetworkTxBps }, rate},
+	} {
+		chart, peak, peakAt := sparkline(samples, series.value, start, end, width)
+		fmt.Printf("%-11s %s  peak %s at %s\n", series.name, chart, series.format(peak), peakAt.Format("01-02 15:04"))
+	}
+	fmt.Printf("%-11s %-*s%s\n", "", width-5, start.Format("15:04"), end.Format("15:04"))
+	return nil
+}
+
+// sparkline charts value over [start, end] in width columns, each the
+// highest sample of its time slice scaled to the highest sample overall.
+// It also returns that highest sample and when it was taken.
+func sparkline(samples []*nodemetricspb.NodeMetricsSample, value func(*nodemetricspb.NodeMetricsSample) float64, start, end time.Time, width int) (string, float64, time.Time) {
+	columns := make([]float64, width)
+	filled := make([]bool, width)
+	slice := end.Sub(start) / time.Duration(width)
+	if slice <= 0 {
+		slice = 1
+	}
+
+	var peak float64
+	var peakAt time.Time
+	for _, s := range samples {
+		at := time.Unix(0, s.Timestamp)
+		v := value(s)
+		if v > peak || peakAt.IsZero() {
+			peak, peakAt = v, at
+		}
+		col := max(0, min(int(at.Sub(start)/slice), width-1))
+		if !filled[col] || v > columns[col] {
+			columns[col] = v
+		}
+		filled[col] = true
+	}
+
+	var b strings.Builder
+	for col, v := range columns {
+		switch {
+		case !filled[col]:
+			b.WriteRune(' ')
+		case peak <= 0:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			b.WriteRune(sparkBlocks[int(v/peak*float64(len(sparkBlocks)-1))])
+		}
+	}
+	return b.String(), peak, peakAt
+}
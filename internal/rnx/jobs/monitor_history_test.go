@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+)
+
+func TestSparkline(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int, cores float64) *nodemetricspb.NodeMetricsSample {
+		return &nodemetricspb.NodeMetricsSample{Timestamp: start.Add(time.Duration(minutes) * time.Minute).UnixNano(), CpuCores: cores}
+	}
+	// Four 15 minute columns; the third has no samples
+	samples := []*nodemetricspb.NodeMetricsSample{at(0, 1), at(5, 0), at(20, 8), at(50, 4), at(60, 2)}
+	chart, peak, peakAt := sparkline(samples, func(s *nodemetricspb.NodeMetricsSample) float64 { return s.CpuCores }, start, start.Add(time.Hour), 4)
+
+	// Columns show the highest sample of their slice; the sample at the end
+	// falls in the last column
+	if chart != "▁█ ▄" {
+		t.Errorf("chart = %q", chart)
+	}
+	if peak != 8 || !peakAt.Equal(start.Add(20*time.Minute)) {
+		t.Errorf("peak = %v at %v", peak, peakAt)
+	}
+}
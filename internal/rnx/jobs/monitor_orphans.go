@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+func NewMonitorOrphansCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "Show the cgroups and network resources left by gone jobs",
+		Long: `Show what the server's periodic cleanup found left by jobs that are no
+longer in its job store, such as after the server crashed.
+
+Every 5 minutes the server looks for cgroup directories (job-<uuid>), host veth
+interfaces (veth-h-<id>) and mounted network namespaces of unknown jobs and
+removes them. The counts add up since the server started; resources that could
+not be removed are listed and found again by the next pass.
+
+Examples:
+  rnx monitor orphans          # Counts and the resources left
+  rnx monitor orphans --json   # JSON for dashboards`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMonitorOrphans(common.JSONOutput)
+		},
+	}
+	return cmd
+}
+
+type orphanCountJSON struct {
+	Kind    string `json:"kind"`
+	Found   int64  `json:"found"`
+	Cleaned int64  `json:"cleaned"`
+	Failed  int64  `json:"failed"`
+}
+
+type orphanJSON struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	JobID string `json:"jobId"`
+}
+
+type orphansJSON struct {
+	LastPass  *time.Time        `json:"lastPass,omitempty"`
+	Counts    []orphanCountJSON `json:"counts"`
+	Remaining []orphanJSON      `json:"remaining"`
+}
+
+func runMonitorOrphans(jsonOutput bool) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := jobClient.GetOrphanedResources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get orphaned resources: %w", err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(orphansToJSON(resp), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if resp.LastPass == 0 {
+		fmt.Println("The server has not run a cleanup pass yet.")
+		return nil
+	}
+	fmt.Printf("Orphaned resources since the server started (last pass %s)\n\n",
+		time.Unix(0, resp.LastPass).Format("2006-01-02 15:04:05"))
+	fmt.Printf("%-8s %8s %8s %8s\n", "KIND", "FOUND", "CLEANED", "FAILED")
+	for _, c := range resp.Counts {
+		fmt.Printf("%-8s %8d %8d %8d\n", c.Kind, c.Found, c.Cleaned, c.Failed)
+	}
+
+	if len(resp.Remaining) > 0 {
+		fmt.Printf("\nLeft by the last pass:\n")
+		for _, r := range resp.Remaining {
+			fmt.Printf("  %-6s %-44s job %s\n", r.Kind, r.Path, r.JobId)
+		}
+	}
+	return nil
+}
+
+func orphansToJSON(resp *nodemetricspb.OrphanedResources) orphansJSON {
+	out := orphansJSON{Counts: []orphanCountJSON{}, Remaining: []orphanJSON{}}
+	if resp.LastPass != 0 {
+		lastPass := time.Unix(0, resp.LastPass)
+		out.LastPass = &lastPass
+	}
+	for _, c := range resp.Counts {
+		out.Counts = append(out.Counts, orphanCountJSON{Kind: c.Kind, Found: c.Found, Cleaned: c.Cleaned, Failed: c.Failed})
+	}
+	for _, r := range resp.Remaining {
+		out.Remaining = append(out.Remaining, orphanJSON{Kind: r.Kind, Name: r.Name, Path: r.Path, JobID: r.JobId})
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"testing"
+
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+)
+
+func TestOrphansToJSON(t *testing.T) {
+	out := orphansToJSON(&nodemetricspb.OrphanedResources{})
+	if out.LastPass != nil || out.Counts == nil || out.Remaining == nil {
+		t.Errorf("no pass yet = %+v, want no last pass and empty lists", out)
+	}
+
+	out = orphansToJSON(&nodemetricspb.OrphanedResources{
+		LastPass:  1772438700000000000,
+		Counts:    []*nodemetricspb.OrphanedResourceCount{{Kind: "veth", Found: 2, Cleaned: 1, Failed: 1}},
+		Remaining: []*nodemetricspb.OrphanedResource{{Kind: "veth", Name: "veth-h-0b6c3a2e", JobId: "0b6c3a2e"}},
+	})
+	if out.LastPass == nil || out.LastPass.UnixNano() != 1772438700000000000 {
+		t.Errorf("last pass = %v", out.LastPass)
+	}
+	if len(out.Counts) != 1 || out.Counts[0] != (orphanCountJSON{Kind: "veth", Found: 2, Cleaned: 1, Failed: 1}) {
+		t.Errorf("counts = %+v", out.Counts)
+	}
+	if len(out.Remaining) != 1 || out.Remaining[0].JobID != "0b6c3a2e" {
+		t.Errorf("remaining = %+v", out.Remaining)
+	}
+}
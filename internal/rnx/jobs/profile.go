@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func NewProfileCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "profile <job-uuid>",
+		Short: "Save the strace/perf profile of a job run with --profile",
+		Long: `Extract the profile of a job started with 'rnx job run --profile'.
+
+The profiler output is stored at the end of the job log. This command reads
+the log, waiting for the job to finish if it is still running, and writes
+only the profile section.
+
+Examples:
+  # Print the profile
+  rnx job profile f47ac10b
+
+  # Save it to a file
+  rnx job profile f47ac10b -o trace.txt`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfile(args[0], output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the profile to a file instead of stdout")
+
+	return cmd
+}
+
+func runProfile(jobID, output string) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	stream, err := jobClient.GetJobLogs(context.Background(), jobID)
+	if err != nil {
+		return fmt.Errorf("couldn't start reading logs: %v", err)
+	}
+
+	extractor := &profileExtractor{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if s, ok := status.FromError(err); ok {
+				return fmt.Errorf("problem reading logs: %v", s.Message())
+			}
+			return fmt.Errorf("error receiving log stream: %v", err)
+		}
+		extractor.Feed(chunk.Payload)
+	}
+	extractor.Flush()
+
+	if extractor.tool == "" {
+		return fmt.Errorf("job %s has no profile in its log (was it run with --profile?)", jobID)
+	}
+	if !extractor.complete {
+		fmt.Fprintf(os.Stderr, "Warning: the %s profile is incomplete, the job may have been stopped\n", extractor.tool)
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(extractor.profile.Bytes())
+		return err
+	}
+	if err := os.WriteFile(output, extractor.profile.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("Saved %s profile (%d bytes) to %s\n", extractor.tool, extractor.profile.Len(), output)
+	return nil
+}
+
+// profileExtractor collects the lines between the profile begin and end
+// markers of a job log. Log chunks may split lines anywhere.
+type profileExtractor struct {
+	profile  bytes.Buffer
+	partial  []byte
+	tool     string
+	inside   bool
+	complete bool
+}
+
+func (e *profileExtractor) Feed(p []byte) {
+	e.partial = append(e.partial, p...)
+	for {
+		i := bytes.IndexByte(e.partial, '\n')
+		if i < 0 {
+			return
+		}
+		e.line(e.partial[:i+1])
+		e.partial = e.partial[i+1:]
+	}
+}
+
+// Flush handles a last line without a trailing newline.
+func (e *profileExtractor) Flush() {
+	if len(e.partial) > 0 {
+		e.line(e.partial)
+		e.partial = nil
+	}
+}
+
+func (e *profileExtractor) line(line []byte) {
+	text := strings.TrimRight(string(line), "\r\n")
+	switch {
+	case strings.HasPrefix(text, constants.ProfileBeginMarker):
+		// A later profile section replaces an earlier one
+		e.tool = strings.TrimPrefix(text, constants.ProfileBeginMarker)
+		e.inside, e.complete = true, false
+		e.profile.Reset()
+	case text == constants.ProfileEndMarker && e.inside:
+		e.inside, e.complete = false, true
+	case e.inside:
+		e.profile.Write(line)
+	}
+}
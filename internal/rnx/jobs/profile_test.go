@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/constants"
+)
+
+func TestProfileExtractor(t *testing.T) {
+	log := "job output\n\n" + constants.ProfileBeginMarker + "strace\n" +
+		"12:00:00 execve(\"/usr/bin/python3\") = 0\n" +
+		"% time     seconds  usecs/call     calls    errors syscall\n\n" +
+		constants.ProfileEndMarker + "\n"
+
+	// Split into awkward chunks, as the log stream may do
+	e := &profileExtractor{}
+	for i := 0; i < len(log); i += 7 {
+		end := i + 7
+		if end > len(log) {
+			end = len(log)
+		}
+		e.Feed([]byte(log[i:end]))
+	}
+	e.Flush()
+
+	if e.tool != "strace" || !e.complete {
+		t.Fatalf("tool = %q, complete = %v", e.tool, e.complete)
+	}
+	want := "12:00:00 execve(\"/usr/bin/python3\") = 0\n% time     seconds  usecs/call     calls    errors syscall\n\n"
+	if got := e.profile.String(); got != want {
+		t.Errorf("profile = %q, want %q", got, want)
+	}
+}
+
+func TestProfileExtractor_Incomplete(t *testing.T) {
+	e := &profileExtractor{}
+	e.Feed([]byte("output\n" + constants.ProfileBeginMarker + "perf\n# Samples: 1K"))
+	e.Flush()
+
+	if e.tool != "perf" || e.complete {
+		t.Fatalf("tool = %q, complete = %v", e.tool, e.complete)
+	}
+	if got := e.profile.String(); got != "# Samples: 1K" {
+		t.Errorf("profile = %q", got)
+	}
+
+	none := &profileExtractor{}
+	none.Feed([]byte("no profile here\n"))
+	if none.tool != "" {
+		t.Errorf("unexpected tool %q", none.tool)
+	}
+}
@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	eventspb "github.com/ehsaniara/joblet/internal/proto/gen/events"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+// jobProgressBarWidth is the number of cells of the job progress bar
+const jobProgressBarWidth = 30
+
+// NewProgressCmd creates the command following the progress a job reports
+func NewProgressCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "progress <job-uuid>",
+		Short: "Follow the progress a job reports",
+		Long: `Follow the progress a job reports until it ends.
+
+Jobs report progress by printing marker lines to stdout or stderr, either a
+bare percentage or a JSON object. Fields a marker leaves out keep their last
+value:
+
+  echo "::joblet-progress::40"
+  echo '::joblet-progress::{"percent": 40, "phase": "train", "message": "epoch 4/10"}'
+
+Marker lines stay in the job's log. On a terminal the progress bar is redrawn
+in place; otherwise every event is printed on its own line.
+
+Examples:
+  # Follow a running job
+  rnx job progress f47ac10b
+
+  # One JSON event per line, for UIs and scripts
+  rnx --json job progress f47ac10b`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProgress(args[0])
+		},
+	}
+	return cmd
+}
+
+func runProgress(jobID string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	stream, err := jobClient.StreamJobEvents(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("couldn't follow job progress: %v", err)
+	}
+
+	info, err := os.Stdout.Stat()
+	inPlace := err == nil && info.Mode()&os.ModeCharDevice != 0 && !common.JSONOutput
+	encoder := json.NewEncoder(os.Stdout)
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if inPlace {
+				fmt.Println()
+			}
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			if s, ok := status.FromError(err); ok {
+				return fmt.Errorf("couldn't follow job progress: %v", s.Message())
+			}
+			return fmt.Errorf("couldn't follow job progress: %v", err)
+		}
+
+		switch {
+		case common.JSONOutput:
+			if err := encoder.Encode(progressEventJSON(event)); err != nil {
+				return fmt.Errorf("couldn't format output as JSON: %v", err)
+			}
+		case inPlace:
+			fmt.Print("\r\033[K" + formatJobProgress(event))
+		default:
+			fmt.Println(formatJobProgress(event))
+		}
+	}
+	if inPlace {
+		fmt.Println()
+	}
+	return nil
+}
+
+// formatJobProgress renders an event as a progress bar followed by the
+// percentage, the phase, the message and the job's status
+func formatJobProgress(event *eventspb.JobEvent) string {
+	filled, percent := 0, "  --"
+	if event.Percent >= 0 {
+		filled = int(event.Percent * jobProgressBarWidth / 100)
+		percent = fmt.Sprintf("%3.0f%%", event.Percent)
+	}
+	parts := []string{
+		"[" + strings.Repeat("#", filled) + strings.Repeat(".", jobProgressBarWidth-filled) + "]",
+		percent,
+	}
+	if event.Phase != "" {
+		parts = append(parts, event.Phase)
+	}
+	if event.Message != "" {
+		parts = append(parts, event.Message)
+	}
+	statusColor, resetColor := getStatusColor(event.Status)
+	parts = append(parts, statusColor+event.Status+resetColor)
+	return strings.Join(parts, "  ")
+}
+
+// progressEventJSON is the --json form of an event
+func progressEventJSON(event *eventspb.JobEvent) map[string]interface{} {
+	result := map[string]interface{}{
+		"type":      event.Type,
+		"timestamp": time.Unix(0, event.Timestamp).UTC().Format(time.RFC3339Nano),
+		"job_id":    event.JobUuid,
+		"status":    event.Status,
+	}
+	if event.Percent >= 0 {
+		result["percent"] = event.Percent
+	}
+	if event.Phase != "" {
+		result["phase"] = event.Phase
+	}
+	if event.Message != "" {
+		result["message"] = event.Message
+	}
+	return result
+}
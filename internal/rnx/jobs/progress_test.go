@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+
+	eventspb "github.com/ehsaniara/joblet/internal/proto/gen/events"
+)
+
+func TestFormatJobProgress(t *testing.T) {
+	got := formatJobProgress(&eventspb.JobEvent{Status: "RUNNING", Percent: 40, Phase: "train", Message: "epoch 4/10"})
+	if want := "[############..................]   40%  train  epoch 4/10  "; !strings.HasPrefix(got, want) || !strings.Contains(got, "RUNNING") {
+		t.Errorf("formatJobProgress() = %q, want prefix %q", got, want)
+	}
+
+	// No percentage reported yet
+	got = formatJobProgress(&eventspb.JobEvent{Status: "PENDING", Percent: -1})
+	if want := "[" + strings.Repeat(".", jobProgressBarWidth) + "]    --  "; !strings.HasPrefix(got, want) {
+		t.Errorf("formatJobProgress() = %q, want prefix %q", got, want)
+	}
+
+	if _, exists := progressEventJSON(&eventspb.JobEvent{Percent: -1})["percent"]; exists {
+		t.Error("JSON event reports a percentage the job did not report")
+	}
+}
@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/gitsource"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+)
+
+// repoRunTimeout bounds a run from a repository, which includes the server's
+// fetch
+const repoRunTimeout = 5 * time.Minute
+
+// separateValueFlags are the 'rnx job run' flags whose value may follow as
+// the next argument
+var separateValueFlags = map[string]bool{
+	"--config": true, "--node": true, "--file": true, "-f": true, "--env": true, "-e": true,
+	"--secret-env": true, "-s": true, "--repo": true, "--path": true,
+}
+
+// hasRepoFlag reports whether 'rnx job run' was asked to run from a
+// repository. Flags after the job's command belong to the command.
+func hasRepoFlag(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--repo" || strings.HasPrefix(arg, "--repo="):
+			return true
+		case arg == "--" || !strings.HasPrefix(arg, "-"):
+			return false
+		case separateValueFlags[arg]:
+			i++
+		}
+	}
+	return false
+}
+
+// parseRepoArgs parses the flags of a run from a repository. The spec in the
+// repository describes the job, so job flags and a command are rejected.
+func parseRepoArgs(args []string) (repo string, specPath string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		value := func(flag string) (string, error) {
+			if strings.HasPrefix(arg, flag+"=") {
+				return strings.TrimPrefix(arg, flag+"="), nil
+			}
+			if i+1 < len(args) {
+				i++
+				return args[i], nil
+			}
+			return "", fmt.Errorf("%s requires a value", flag)
+		}
+
+		switch {
+		case arg == "--repo" || strings.HasPrefix(arg, "--repo="):
+			repo, err = value("--repo")
+		case arg == "--path" || strings.HasPrefix(arg, "--path="):
+			specPath, err = value("--path")
+		case arg == "--config" || strings.HasPrefix(arg, "--config="):
+			common.ConfigPath, err = value("--config")
+		case arg == "--node" || strings.HasPrefix(arg, "--node="):
+			common.NodeName, err = value("--node")
+		case arg == "--json":
+			common.JSONOutput = true
+		case strings.HasPrefix(arg, "-"):
+			return "", "", fmt.Errorf("%s can't be combined with --repo: the spec in the repository describes the job", arg)
+		default:
+			return "", "", fmt.Errorf("unexpected command %q with --repo: the spec in the repository describes the job", arg)
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if repo == "" || specPath == "" {
+		return "", "", fmt.Errorf("--repo=URL[@REF] and --path=FILE are both required to run from a repository")
+	}
+	return repo, specPath, nil
+}
+
+// runFromRepo has the server fetch a repository ref and run the workflow or
+// job spec at a path of it
+func runFromRepo(args []string) error {
+	repoRef, specPath, err := parseRepoArgs(args)
+	if err != nil {
+		return err
+	}
+	repo, ref := gitsource.ParseRepoRef(repoRef)
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), repoRunTimeout)
+	defer cancel()
+	response, err := jobClient.RunFromGit(ctx, repo, ref, specPath)
+	if err != nil {
+		return fmt.Errorf("failed to run %s from %s@%s: %v", specPath, repo, ref, err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(response)
+	}
+
+	fmt.Printf("Started %s from %s@%s (commit %s)\n", specPath, repo, ref, shortCommit(response.Commit))
+	fmt.Printf("Workflow UUID: %s\n", response.WorkflowUuid)
+	fmt.Printf("Jobs: %s\n", strings.Join(response.Jobs, ", "))
+	if response.Files > 0 {
+		fmt.Printf("Files: %d\n", response.Files)
+	}
+	fmt.Printf("Status: rnx workflow status %s\n", response.WorkflowUuid)
+	return nil
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHasRepoFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--repo=https://git.internal/team/app@main", "--path=jobs/train.yaml"}, true},
+		{[]string{"--node", "srv1", "--repo", "git@git.internal:team/app"}, true},
+		{[]string{"echo", "--repo=x"}, false},
+		{[]string{"--env", "A=1", "--", "tool", "--repo=x"}, false},
+		{[]string{"--max-cpu=50", "python3", "train.py"}, false},
+	}
+	for _, tt := range tests {
+		if got := hasRepoFlag(tt.args); got != tt.want {
+			t.Errorf("hasRepoFlag(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestParseRepoArgs(t *testing.T) {
+	repo, path, err := parseRepoArgs([]string{"--repo", "https://git.internal/team/app@main", "--path=jobs/train.yaml", "--json"})
+	if err != nil || repo != "https://git.internal/team/app@main" || path != "jobs/train.yaml" {
+		t.Errorf("parseRepoArgs() = %q, %q, %v", repo, path, err)
+	}
+
+	for _, tt := range []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--repo=https://git.internal/team/app"}, "both required"},
+		{[]string{"--repo=https://git.internal/team/app", "--path=a.yaml", "--max-cpu=50"}, "can't be combined"},
+		{[]string{"--repo=https://git.internal/team/app", "--path=a.yaml", "python3"}, "unexpected command"},
+		{[]string{"--repo=https://git.internal/team/app", "--path"}, "requires a value"},
+	} {
+		if _, _, err := parseRepoArgs(tt.args); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseRepoArgs(%v) error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/client"
+	"github.com/ehsaniara/joblet/pkg/config"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxIdChoices caps the matches listed for an ambiguous prefix
+const maxIdChoices = 20
+
+// resolveID turns a short UUID into the full UUID of the job or workflow it
+// means. An ambiguous prefix lets the user pick a match when rnx runs in a
+// terminal and fails with the matches otherwise. Full UUIDs, prefixes
+// matching nothing and servers without the resolver are passed through, so
+// the server reports them as before.
+func resolveID(jobClient *client.JobClient, id string, idType idspb.IdType) (string, error) {
+	if len(id) >= 36 {
+		return id, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := jobClient.ResolveId(ctx, id, idType, maxIdChoices)
+	if status.Code(err) == codes.Unimplemented {
+		return id, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("couldn't resolve %s: %v", id, err)
+	}
+	switch {
+	case resp.Total == 0:
+		return id, nil
+	case resp.Total == 1 && len(resp.Matches) == 1:
+		return resp.Matches[0].Uuid, nil
+	case !stdinIsTerminal() || common.JSONOutput:
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s is ambiguous, it matches %d:\n", id, resp.Total)
+		writeIdMatches(&b, resp)
+		b.WriteString("give more characters of the UUID")
+		return "", fmt.Errorf("%s", b.String())
+	}
+	return pickIdMatch(os.Stdin, os.Stderr, id, resp)
+}
+
+// pickIdMatch lists the matches of an ambiguous prefix and reads the number
+// of the one meant
+func pickIdMatch(in io.Reader, out io.Writer, id string, resp *idspb.ResolveIdResponse) (string, error) {
+	fmt.Fprintf(out, "%s matches %d:\n", id, resp.Total)
+	writeIdMatches(out, resp)
+	fmt.Fprintf(out, "Select [1-%d]: ", len(resp.Matches))
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(resp.Matches) {
+		return "", fmt.Errorf("no match selected for %s", id)
+	}
+	return resp.Matches[n-1].Uuid, nil
+}
+
+// writeIdMatches writes the numbered matches with their name, status and age
+func writeIdMatches(w io.Writer, resp *idspb.ResolveIdResponse) {
+	for i, m := range resp.Matches {
+		fmt.Fprintf(w, "  %2d) %-8s %s  %-24s %-10s %s\n", i+1, idTypeName(m.Type), m.Uuid,
+			truncateName(m.Name, 24), m.Status, formatIdAge(m.CreatedAt))
+	}
+	if more := int(resp.Total) - len(resp.Matches); more > 0 {
+		fmt.Fprintf(w, "  ... and %d more\n", more)
+	}
+}
+
+func idTypeName(t idspb.IdType) string {
+	if t == idspb.IdType_ID_TYPE_WORKFLOW {
+		return "workflow"
+	}
+	return "job"
+}
+
+func truncateName(name string, width int) string {
+	if len(name) > width {
+		return name[:width-3] + "..."
+	}
+	return name
+}
+
+// formatIdAge shows how long ago a match was created
+func formatIdAge(createdAt int64) string {
+	if createdAt <= 0 {
+		return ""
+	}
+	return formatDuration(time.Since(time.Unix(0, createdAt)).Truncate(time.Second)) + " ago"
+}
+
+// completeIDs completes the UUID argument of a command with the jobs or
+// workflows the server knows, showing their name and status
+func completeIDs(idType idspb.IdType) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		// Completion requests skip the PersistentPreRunE loading the config
+		if common.NodeConfig == nil {
+			nodeConfig, err := config.LoadClientConfig(common.ConfigPath)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			common.NodeConfig = nodeConfig
+		}
+		jobClient, err := common.NewJobClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		defer jobClient.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := jobClient.ResolveId(ctx, toComplete, idType, 100)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions := make([]string, 0, len(resp.Matches))
+		for _, m := range resp.Matches {
+			completions = append(completions, fmt.Sprintf("%s\t%s %s", m.Uuid, m.Name, m.Status))
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ResolveWorkflowUUID resolves a short workflow UUID for the workflow
+// commands, like the job commands do for jobs
+func ResolveWorkflowUUID(id string) (string, error) {
+	if len(id) >= 36 {
+		return id, nil
+	}
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return "", fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+	return resolveID(jobClient, id, idspb.IdType_ID_TYPE_WORKFLOW)
+}
+
+// CompleteWorkflowUUIDs completes the workflow UUID argument of a command
+func CompleteWorkflowUUIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeIDs(idspb.IdType_ID_TYPE_WORKFLOW)(cmd, args, toComplete)
+}
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
+)
+
+func ambiguousResponse() *idspb.ResolveIdResponse {
+	return &idspb.ResolveIdResponse{
+		Total: 3,
+		Matches: []*idspb.IdMatch{
+			{Type: idspb.IdType_ID_TYPE_JOB, Uuid: "3f2a1b0c-1111-4b6e-9c1d-0e9f8a762c1d", Name: "build", Status: "RUNNING"},
+			{Type: idspb.IdType_ID_TYPE_WORKFLOW, Uuid: "3f2a9d41-2222-4f8e-b5a7-2c1d0e9f8a76", Name: "nightly-pipeline", Status: "COMPLETED"},
+		},
+	}
+}
+
+func TestPickIdMatch(t *testing.T) {
+	resp := ambiguousResponse()
+
+	var out bytes.Buffer
+	uuid, err := pickIdMatch(strings.NewReader("2\n"), &out, "3f2a", resp)
+	if err != nil || uuid != resp.Matches[1].Uuid {
+		t.Fatalf("pickIdMatch(2) = %q, %v", uuid, err)
+	}
+	for _, want := range []string{"3f2a matches 3:", "1) job", "2) workflow", "nightly-pipeline", "... and 1 more", "Select [1-2]: "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("prompt %q does not contain %q", out.String(), want)
+		}
+	}
+
+	for _, answer := range []string{"3\n", "0\n", "x\n", ""} {
+		if uuid, err := pickIdMatch(strings.NewReader(answer), &bytes.Buffer{}, "3f2a", resp); err == nil {
+			t.Errorf("pickIdMatch(%q) = %q, want an error", answer, uuid)
+		}
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	if got := truncateName("short", 24); got != "short" {
+		t.Errorf("truncateName(short) = %q", got)
+	}
+	if got := truncateName("a-rather-long-workflow-name", 10); got != "a-rathe..." {
+		t.Errorf("truncateName(long) = %q", got)
+	}
+}
@@ -3,23 +3,34 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/internal/rnx/placement"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
 	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	"github.com/ehsaniara/joblet/internal/rnx/queue"
 	"github.com/ehsaniara/joblet/internal/rnx/workflows"
+	"github.com/ehsaniara/joblet/pkg/client"
 	pkgconfig "github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func NewRunCmd() *cobra.Command {
@@ -34,6 +45,7 @@ Examples:
   rnx job run python3 script.py
   rnx job run bash -c "curl https://example.com"
   rnx --node=srv1 job run ps aux
+  rnx --node=auto job run --max-memory=2048 python3 train.py   # Node with the most room
   
   # No network
   rnx job run --network=none python3 process_local.py
@@ -73,6 +85,10 @@ Runtime Examples:
   rnx job run --runtime=python-3.11-ml python train_model.py
   rnx job run --runtime=graalvmjdk-21 --upload=App.java java App.java
 
+  # Scripts: upload and pick the interpreter in one flag
+  rnx job run --script=analyze.py data.csv
+  rnx job run --runtime=python-3.11-ml --inline='import sys; print(sys.version)'
+
 Environment Variable Examples:
   # Pass regular environment variables (visible in logs)
   rnx job run --env=NODE_ENV=production --env=PORT=8080 node app.js
@@ -85,6 +101,11 @@ Environment Variable Examples:
   # Combine different types
   rnx job run --env=NODE_ENV=prod --secret-env=API_KEY=secret node app.js
 
+  # Deliver a secret as a read-only file on a tmpfs instead, for tools that
+  # only read credentials from files; it is left out of the environment
+  rnx job run --secret-env=AWS_CREDENTIALS="$(cat ~/.aws/credentials)" \
+    --secret-file=/root/.aws/credentials=AWS_CREDENTIALS aws s3 ls
+
 GPU Examples:
   # Request a single GPU for machine learning workloads
   rnx job run --gpu=1 python train_model.py
@@ -97,6 +118,177 @@ GPU Examples:
   # GPU with other resource limits
   rnx job run --gpu=1 --max-memory=4096 --max-cpu=200 python inference.py
 
+Shared Memory and Temp Space Examples:
+  # Size /dev/shm for PyTorch dataloaders and other shared memory users
+  rnx job run --shm-size=2g --gpu=1 python train.py
+
+  # Cap /tmp separately from the work directory quota
+  rnx job run --tmp-size=512m python extract.py
+
+  # Keep a data-heavy work directory on the node's NVMe scratch device
+  rnx job run --scratch=nvme --upload=shard.parquet python transform.py
+
+  # Keep the filesystem if the job fails, then look at what it wrote
+  rnx job run --freeze-fs python etl.py
+  rnx job fs ls <job-uuid> /work
+
+  # Set a raw cgroup v2 file the server allows (cgroup.allowedParams)
+  rnx job run --cgroup-param=cpu.weight=50 --cgroup-param="io.latency=8:0 target=10" ./batch.sh
+
+Profiling Examples:
+  # Trace system calls (admin only, the node must enable profiling)
+  rnx job run --profile=strace python3 slow_io.py
+
+  # Sample CPU stacks with perf, then save the report locally
+  rnx job run --profile=perf ./solver --size=large
+  rnx job profile <job-uuid> -o solver.perf.txt
+
+Deduplication Examples:
+  # Racing CI events submit the same build; only the first one runs, the
+  # others get the UUID of the job that is already running
+  rnx job run --dedup --upload-dir=src make test
+
+Job Group Examples:
+  # Start related jobs under one name, then manage them together
+  rnx job run --group=load-test-2024 ./load.sh --target=api
+  rnx job list --group=load-test-2024
+  rnx job stop --group=load-test-2024
+
+  # Tag jobs to select them later
+  rnx job run --label=env=staging --label=team=ml python3 serve.py
+  rnx job stop-all --label=env=staging
+
+  # Note where the job comes from; in GitHub Actions, GitLab CI, Jenkins,
+  # CircleCI or Buildkite the pipeline, run, URL and commit SHA are added
+  # unless --no-ci-annotations is given
+  rnx job run --annotation=ticket=https://tracker.example.com/OPS-42 ./migrate.sh
+
+Job Array Examples:
+  # Start 100 jobs, each reading its index from JOB_ARRAY_INDEX (and the
+  # array size from JOB_ARRAY_SIZE)
+  rnx job run --array=0-99 --upload=shard.py python3 shard.py
+
+  # Indices can be lists and stepped ranges
+  rnx job run --array=1,3,10-20:5 ./render.sh
+
+  # Follow and stop the whole array by its UUID
+  rnx job status <array-uuid>
+  rnx job stop <array-uuid>
+
+Log Sink Examples:
+  # Copy the output to S3 and a syslog server while the job runs
+  # (the server must enable log_sinks)
+  rnx job run --log-sink=s3://build-logs/nightly/ make all
+  rnx job run --log-sink=syslog://logs.internal --log-sink=s3://build-logs/ ./etl.sh
+
+Stdin Examples:
+  # Pipe data to a tool that only reads stdin
+  cat data.csv | rnx job run --stdin -- python3 proc.py
+
+  # Same, reading the input from a local file
+  rnx job run --stdin-file=data.csv sort -t, -k2
+
+Result Cache Examples:
+  # Reuse the result of an identical job that succeeded in the last 24 hours
+  rnx job run --cache-ttl=24h python3 daily_report.py
+
+  # Run it again anyway; the new result replaces the cached one
+  rnx job run --cache-ttl=24h --no-cache python3 daily_report.py
+
+Queue TTL Examples:
+  # Give up if a busy node or a maintenance window keeps the job from
+  # starting within 2 hours; it then ends as EXPIRED
+  rnx job run --queue-ttl=2h python3 hourly_sync.py
+
+  # Scheduled jobs count the TTL from their scheduled time
+  rnx job run --schedule="1hour" --queue-ttl=30m ./nightly.sh
+
+IPC Channel Examples:
+  # A producer and a consumer exchange Arrow buffers through a shared
+  # /dev/shm instead of TCP; both name the channel to join it
+  rnx job run --ipc=arrow-feed python3 producer.py
+  rnx job run --ipc=arrow-feed python3 consumer.py
+
+Input Examples:
+  # The server downloads the objects into the workspace before the job
+  # starts, with its own or your tenant's credentials; unchanged objects
+  # come from its cache
+  rnx job run --input=s3://datasets/imagenet/train.tar:/work/data/train.tar python3 train.py
+  rnx job run --input=s3://datasets/labels.csv --input=gs://models/v3.bin:/work/models/ ./score.sh
+
+Output Examples:
+  # Once the job completes, the server uploads the matching files under the
+  # prefix with retries, then writes manifest.json listing them; quote the
+  # argument so the local shell leaves ${JOB_UUID} alone
+  rnx job run --output='/work/out/*.parquet:s3://results/etl/${JOB_UUID}/' python3 etl.py
+  rnx job run --output='/work/report:gs://reports/daily/' ./build-report.sh
+
+Egress Examples:
+  # Rules are matched in order and the first match decides; end with deny:*
+  # to block every destination the rules before it do not allow. Names are
+  # resolved on the server when the job starts; DNS itself is a destination
+  rnx job run --egress='allow:api.internal:443,deny:*' python3 export.py
+  rnx job run --egress='allow:10.0.0.53:53,allow:db.internal:5432,deny:*' ./etl.sh
+
+Job Spec File Examples:
+  # Describe the whole invocation in YAML and keep it in git
+  rnx job run -f train.yaml
+
+  # Flags and a command on the command line override the file
+  rnx job run -f train.yaml --max-memory=4096
+  rnx job run -f train.yaml python3 train.py --epochs=1
+
+  # train.yaml
+  kind: Job                       # optional
+  name: train
+  command: python3
+  args: [train.py, --epochs=10]
+  runtime: python-3.11-ml
+  network: none
+  volumes: [datasets]
+  schedule: "30min"               # same formats as --schedule
+  environment:
+    LOG_LEVEL: info
+  secret_environment:
+    API_KEY: ${API_KEY}           # expanded from the local environment
+  secret_files:                   # same as --secret-file
+    /run/secrets/api-key: API_KEY
+  uploads:                        # relative to the spec file
+    files: [train.py]
+    directories: [configs]
+  resources:
+    max_cpu: 200
+    max_memory: 2048
+    gpu_count: 1
+    shm_size: 2GB
+    cgroup_params:                # same as --cgroup-param
+      cpu.weight: "50"
+  profile: strace                 # same as --profile
+  group: load-test-2024           # same as --group
+  array: 0-99                     # same as --array
+  labels:                         # same as --label
+    env: staging
+  annotations:                    # same as --annotation
+    ticket: https://tracker.example.com/OPS-42
+  log_sinks: [s3://build-logs/]   # same as --log-sink
+  dedup: true                     # same as --dedup
+  cache_ttl: 24h                  # same as --cache-ttl
+  queue_ttl: 2h                   # same as --queue-ttl
+  ipc: arrow-feed                 # same as --ipc
+  inputs: [s3://data/labels.csv]  # same as --input
+  output_targets:                 # same as --output
+    - /work/out/*.csv:s3://results/${JOB_UUID}/
+  egress: [allow:api.internal:443, deny:*]  # same as --egress
+
+Running From a Git Repository:
+  # The server fetches the ref with its deploy keys and runs the workflow or
+  # job spec at --path, with the files it uploads; no local checkout needed
+  rnx job run --repo=https://git.internal/team/app@main --path=jobs/train.yaml
+  rnx job run --repo=git@git.internal:team/app@v1.4.0 --path=pipelines/etl.yaml
+
+  # A job spec runs as a workflow of that one job; follow it with
+  rnx workflow status <workflow-uuid>
+
 Scheduling Formats:
   # Relative time
   --schedule="1hour"      # 1 hour from now
@@ -110,6 +302,9 @@ Scheduling Formats:
   --schedule="2025-07-18T20:02:48-07:00"     # With timezone
 
 Flags:
+  -f, --file=FILE     Read the job from a YAML spec file (flags override it)
+  --repo=URL[@REF]    Have the server fetch a Git repository ref (default branch without @REF) and run the spec at --path
+  --path=FILE         Workflow or job spec in the repository, relative to its root
   --schedule=SPEC     Schedule job for future execution
   --max-cpu=N         Max CPU percentage
   --max-memory=N      Max Memory in MB  
@@ -117,6 +312,11 @@ Flags:
   --cpu-cores=SPEC    CPU cores specification
   --upload=FILE       Upload a file to the job workspace
   --upload-dir=DIR    Upload entire directory to the job workspace
+  --script=FILE       Upload a script and run it with the interpreter of its #! line, its extension or --runtime; arguments follow the flags
+  --inline=CODE       Run code with the interpreter of --runtime (e.g., --inline='print(1)' --runtime=python-3.11)
+  --stdin             Send rnx's own stdin (e.g., a pipe) to the job's stdin
+  --stdin-file=FILE   Send a local file to the job's stdin
+  --max-upload-size=SIZE  Largest file accepted for upload (e.g., 500m; default 100m, 0 = no limit)
   --runtime=SPEC      Use pre-built runtime (e.g., openjdk-21, python-3.11-ml)
   --volume=NAME       Mount persistent volume
   --network=NAME      Use network configuration
@@ -124,8 +324,30 @@ Flags:
   -e KEY=VALUE            Short form of --env
   --secret-env=KEY=VALUE  Set secret environment variable (hidden from logs)
   -s KEY=VALUE            Short form of --secret-env
+  --secret-file=PATH=KEY  Deliver secret variable KEY as a read-only tmpfs file at PATH instead of the environment (repeatable)
   --gpu=N             Request N GPUs for the job (requires GPU support enabled)
-  --gpu-memory=SIZE   Minimum GPU memory required (e.g., 8GB, 1024MB, 2048)`,
+  --gpu-memory=SIZE   Minimum GPU memory required (e.g., 8GB, 1024MB, 2048)
+  --shm-size=SIZE     Size of /dev/shm (e.g., 2g, 512m; default set by server)
+  --tmp-size=SIZE     Size of /tmp, separate from work dir quota (e.g., 1g)
+  --scratch=NAME      Place the work directory on a scratch device of the server (e.g., nvme) instead of RAM
+  --freeze-fs         Keep the job's filesystem if it fails, to browse with 'rnx job fs ls/cat'
+  --cgroup-param=FILE=VALUE  Write a raw cgroup v2 file such as cpu.weight, if the server allows it (repeatable)
+  --profile=TOOL      Run under strace or perf; the profile is appended to the job log
+  --group=NAME        Add the job to a group, to list or stop related jobs together
+  --array=SPEC        Start one job per index (e.g., 0-99, 1,3,5-7, 0-99:10), each with JOB_ARRAY_INDEX and JOB_ARRAY_SIZE set
+  --label=KEY=VALUE   Tag the job for bulk operations such as 'rnx job stop-all --label' (repeatable)
+  --annotation=KEY=VALUE  Note where the job comes from, e.g. ticket=OPS-42; values may be URLs (repeatable)
+  --no-ci-annotations Do not annotate the job with the CI pipeline rnx runs in (also RNX_NO_CI_ANNOTATIONS=1)
+  --log-sink=URL      Also copy the output to s3://bucket/prefix/, syslog://host[:port] or syslog+tcp://host[:port] (repeatable)
+  --dedup             Return an identical active job (same command, args, runtime, uploads, env) instead of starting a new one
+  --cache-ttl=DURATION  Return an identical job that completed successfully within DURATION (e.g., 24h) instead of running again
+  --no-cache          Skip the cached result and run the job; with --cache-ttl the new result is cached
+  --queue-ttl=DURATION  Expire the job if it has not started DURATION (e.g., 2h) after its submission or scheduled time
+  --ipc=NAME          Share /dev/shm with the other running jobs of your tenant that join channel NAME, or of the tenants the server admits to it
+  --input=URL[:PATH]  Have the server download s3://bucket/key or gs://bucket/key to PATH under /work (default: /work/<name>) before the job starts (repeatable)
+  --output=PATTERN:URL  Have the server upload the files matching PATTERN under /work to s3://bucket/prefix/ or gs://bucket/prefix/ once the job completes, with a manifest (repeatable)
+  --egress=POLICY     Only let the job connect where the rules allow, e.g. allow:api.internal:443,deny:* (rules match in order; repeatable, appended)
+  --queue-offline     Queue the job locally if the server is unreachable (submit later with 'rnx queue flush')`,
 		Args:               cobra.MinimumNArgs(1),
 		RunE:               runRun,
 		DisableFlagParsing: true,
@@ -141,6 +363,9 @@ func runRun(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		}
 	}
+	if hasRepoFlag(args) {
+		return runFromRepo(args)
+	}
 	var (
 		maxCPU        int32
 		cpuCores      string
@@ -156,7 +381,35 @@ func runRun(cmd *cobra.Command, args []string) error {
 		secretEnvVars []string
 		gpuCount      int32
 		gpuMemoryMB   int32
+		shmSize       int64
+		tmpSize       int64
+		scratch       string
+		freezeFS      bool
+		queueOffline  bool
+		specFile      string
+		profile       string
+		dedup         bool
+		cacheTTL      time.Duration
+		queueTTL      time.Duration
+		ipcChannel    string
+		noCache       bool
+		group         string
+		arraySpec     string
+		logSinks      []string
+		jobInputs     []string
+		outputTargets []string
+		egress        []string
+		useStdin      bool
+		stdinFile     string
+		script        string
+		inlineCode    string
+		hasInline     bool
+		maxUploadSize int64 = constants.MaxUploadSize
 	)
+	labels := make(map[string]string)
+	annotations := make(map[string]string)
+	cgroupParams := make(map[string]string)
+	secretFiles := make(map[string]string)
 
 	commandStartIndex := -1
 
@@ -176,6 +429,51 @@ func runRun(cmd *cobra.Command, args []string) error {
 			i++ // Skip the next argument since we consumed it
 		} else if arg == "--json" {
 			common.JSONOutput = true
+		} else if arg == "--queue-offline" {
+			queueOffline = true
+		} else if arg == "--stdin" {
+			useStdin = true
+		} else if strings.HasPrefix(arg, "--stdin-file=") {
+			stdinFile = strings.TrimPrefix(arg, "--stdin-file=")
+			if stdinFile == "" {
+				return fmt.Errorf("--stdin-file requires a file path")
+			}
+		} else if arg == "--dedup" {
+			dedup = true
+		} else if arg == "--freeze-fs" {
+			freezeFS = true
+		} else if arg == "--no-cache" {
+			noCache = true
+		} else if strings.HasPrefix(arg, "--cache-ttl=") {
+			ttl, err := time.ParseDuration(strings.TrimPrefix(arg, "--cache-ttl="))
+			if err != nil || ttl <= 0 {
+				return fmt.Errorf("invalid --cache-ttl value '%s': must be a positive duration such as 24h", strings.TrimPrefix(arg, "--cache-ttl="))
+			}
+			cacheTTL = ttl
+		} else if strings.HasPrefix(arg, "--queue-ttl=") {
+			ttl, err := time.ParseDuration(strings.TrimPrefix(arg, "--queue-ttl="))
+			if err != nil || ttl <= 0 {
+				return fmt.Errorf("invalid --queue-ttl value '%s': must be a positive duration such as 2h", strings.TrimPrefix(arg, "--queue-ttl="))
+			}
+			queueTTL = ttl
+		} else if strings.HasPrefix(arg, "--ipc=") {
+			ipcChannel = strings.TrimPrefix(arg, "--ipc=")
+			if ipcChannel == "" {
+				return fmt.Errorf("--ipc requires a channel name")
+			}
+		} else if strings.HasPrefix(arg, "--file=") || strings.HasPrefix(arg, "-f=") {
+			specFile = strings.TrimPrefix(strings.TrimPrefix(arg, "--file="), "-f=")
+		} else if arg == "--file" || arg == "-f" {
+			if i+1 < len(args) {
+				specFile = args[i+1]
+				i++ // Skip the next argument
+			}
+		} else if strings.HasPrefix(arg, "--timeout=") {
+			timeout, err := time.ParseDuration(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil {
+				return fmt.Errorf("invalid --timeout: %w", err)
+			}
+			common.Timeout = timeout
 		} else if strings.HasPrefix(arg, "--schedule=") {
 			schedule = strings.TrimPrefix(arg, "--schedule=")
 		} else if strings.HasPrefix(arg, "--cpu-cores=") {
@@ -198,6 +496,22 @@ func runRun(cmd *cobra.Command, args []string) error {
 		} else if strings.HasPrefix(arg, "--upload-dir=") {
 			uploadDir := strings.TrimPrefix(arg, "--upload-dir=")
 			uploadDirs = append(uploadDirs, uploadDir)
+		} else if strings.HasPrefix(arg, "--script=") {
+			script = strings.TrimPrefix(arg, "--script=")
+		} else if arg == "--script" && i+1 < len(args) {
+			script = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if strings.HasPrefix(arg, "--inline=") {
+			inlineCode, hasInline = strings.TrimPrefix(arg, "--inline="), true
+		} else if arg == "--inline" && i+1 < len(args) {
+			inlineCode, hasInline = args[i+1], true
+			i++ // Skip the next argument since we consumed it
+		} else if strings.HasPrefix(arg, "--max-upload-size=") {
+			size, err := parseSizeFlag(arg, "--max-upload-size=")
+			if err != nil {
+				return err
+			}
+			maxUploadSize = size
 		} else if strings.HasPrefix(arg, "--network=") {
 			network = strings.TrimPrefix(arg, "--network=")
 		} else if strings.HasPrefix(arg, "--volume=") {
@@ -222,6 +536,15 @@ func runRun(cmd *cobra.Command, args []string) error {
 				secretEnvVar = strings.TrimPrefix(arg, "-s=")
 			}
 			secretEnvVars = append(secretEnvVars, secretEnvVar)
+		} else if strings.HasPrefix(arg, "--secret-file=") {
+			value := strings.TrimPrefix(arg, "--secret-file=")
+			files, err := values.ParseSecretFiles(value)
+			if err != nil {
+				return fmt.Errorf("invalid --secret-file value '%s': %v", value, err)
+			}
+			for path, key := range files {
+				secretFiles[path] = key
+			}
 		} else if arg == "--secret-env" || arg == "-s" {
 			if i+1 < len(args) {
 				secretEnvVars = append(secretEnvVars, args[i+1])
@@ -237,6 +560,83 @@ func runRun(cmd *cobra.Command, args []string) error {
 			if val, err := parseGPUMemory(gpuMemoryStr); err == nil {
 				gpuMemoryMB = int32(val)
 			}
+		} else if strings.HasPrefix(arg, "--shm-size=") {
+			size, err := parseSizeFlag(arg, "--shm-size=")
+			if err != nil {
+				return err
+			}
+			shmSize = size
+		} else if strings.HasPrefix(arg, "--tmp-size=") {
+			size, err := parseSizeFlag(arg, "--tmp-size=")
+			if err != nil {
+				return err
+			}
+			tmpSize = size
+		} else if strings.HasPrefix(arg, "--scratch=") {
+			scratch = strings.TrimPrefix(arg, "--scratch=")
+			if scratch == "" {
+				return fmt.Errorf("--scratch requires a scratch device name")
+			}
+		} else if strings.HasPrefix(arg, "--cgroup-param=") {
+			name, value, _ := strings.Cut(strings.TrimPrefix(arg, "--cgroup-param="), "=")
+			if err := values.ValidateCgroupParam(name, value); err != nil {
+				return fmt.Errorf("invalid --cgroup-param value: %w", err)
+			}
+			cgroupParams[name] = value
+		} else if strings.HasPrefix(arg, "--group=") {
+			group = strings.TrimPrefix(arg, "--group=")
+			if _, err := values.NewGroupName(group); err != nil {
+				return fmt.Errorf("invalid --group value: %w", err)
+			}
+		} else if strings.HasPrefix(arg, "--array=") {
+			arraySpec = strings.TrimPrefix(arg, "--array=")
+			if _, err := values.ParseArrayIndices(arraySpec); err != nil {
+				return fmt.Errorf("invalid --array value: %w", err)
+			}
+		} else if strings.HasPrefix(arg, "--label=") {
+			key, value, err := values.ParseLabel(strings.TrimPrefix(arg, "--label="))
+			if err != nil {
+				return fmt.Errorf("invalid --label value: %w", err)
+			}
+			labels[key] = value
+		} else if strings.HasPrefix(arg, "--annotation=") {
+			key, value, err := values.ParseAnnotation(strings.TrimPrefix(arg, "--annotation="))
+			if err != nil {
+				return fmt.Errorf("invalid --annotation value: %w", err)
+			}
+			annotations[key] = value
+		} else if arg == "--no-ci-annotations" {
+			common.NoCIAnnotations = true
+		} else if strings.HasPrefix(arg, "--log-sink=") {
+			sink := strings.TrimPrefix(arg, "--log-sink=")
+			if sink == "" || strings.Contains(sink, ",") {
+				return fmt.Errorf("invalid --log-sink value '%s': must be a single URL such as s3://bucket/prefix/ or syslog://host", sink)
+			}
+			logSinks = append(logSinks, sink)
+		} else if strings.HasPrefix(arg, "--input=") {
+			input := strings.TrimPrefix(arg, "--input=")
+			if !strings.HasPrefix(input, "s3://") && !strings.HasPrefix(input, "gs://") || strings.Contains(input, "\n") {
+				return fmt.Errorf("invalid --input value '%s': must be s3://bucket/key[:/work/path] or gs://bucket/key[:/work/path]", input)
+			}
+			jobInputs = append(jobInputs, input)
+		} else if strings.HasPrefix(arg, "--output=") {
+			target := strings.TrimPrefix(arg, "--output=")
+			if !strings.Contains(target, ":s3://") && !strings.Contains(target, ":gs://") || strings.Contains(target, "\n") {
+				return fmt.Errorf("invalid --output value '%s': must be /work/PATTERN:s3://bucket/prefix/ or /work/PATTERN:gs://bucket/prefix/", target)
+			}
+			outputTargets = append(outputTargets, target)
+		} else if strings.HasPrefix(arg, "--egress=") {
+			policy := strings.TrimPrefix(arg, "--egress=")
+			rules, err := values.ParseEgressPolicy(policy)
+			if err != nil {
+				return fmt.Errorf("invalid --egress value '%s': %v", policy, err)
+			}
+			egress = append(egress, values.FormatEgressPolicy(rules))
+		} else if strings.HasPrefix(arg, "--profile=") {
+			profile = strings.TrimPrefix(arg, "--profile=")
+			if !slices.Contains(constants.ProfileTools, profile) {
+				return fmt.Errorf("invalid --profile value '%s': must be one of %s", profile, strings.Join(constants.ProfileTools, ", "))
+			}
 		} else if arg == "--" {
 			// -- separator found, command starts at next position
 			if i+1 < len(args) {
@@ -251,13 +651,156 @@ func runRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if commandStartIndex < 0 || commandStartIndex >= len(args) {
-		return fmt.Errorf("must specify a command to run")
+	var (
+		command string
+		cmdArgs []string
+		jobName string
+	)
+	if commandStartIndex >= 0 && commandStartIndex < len(args) {
+		command = args[commandStartIndex]
+		cmdArgs = args[commandStartIndex+1:]
 	}
 
-	commandArgs := args[commandStartIndex:]
-	command := commandArgs[0]
-	cmdArgs := commandArgs[1:]
+	// A job spec file supplies defaults; flags and a command given on the
+	// command line take precedence
+	if specFile != "" {
+		spec, err := loadJobSpecFile(specFile)
+		if err != nil {
+			return err
+		}
+		if command == "" {
+			command, cmdArgs = spec.Command, spec.Args
+		}
+		jobName = spec.Name
+		if schedule == "" {
+			schedule = spec.Schedule
+		}
+		if network == "" {
+			network = spec.Network
+		}
+		if runtime == "" {
+			runtime = spec.Runtime
+		}
+		if maxCPU == 0 {
+			maxCPU = int32(spec.Resources.MaxCPU)
+		}
+		if maxMemory == 0 {
+			maxMemory = int32(spec.Resources.MaxMemory)
+		}
+		if maxIOBPS == 0 {
+			maxIOBPS = int32(spec.Resources.MaxIOBPS)
+		}
+		if cpuCores == "" {
+			cpuCores = spec.Resources.CPUCores
+		}
+		if gpuCount == 0 {
+			gpuCount = int32(spec.Resources.GPUCount)
+		}
+		if gpuMemoryMB == 0 {
+			gpuMemoryMB = int32(spec.Resources.GPUMemoryMB)
+		}
+		if shmSize == 0 {
+			if shmSize, err = sizeBytes("shm_size", spec.Resources.ShmSize); err != nil {
+				return err
+			}
+		}
+		if tmpSize == 0 {
+			if tmpSize, err = sizeBytes("tmp_size", spec.Resources.TmpSize); err != nil {
+				return err
+			}
+		}
+		if scratch == "" {
+			scratch = spec.Resources.Scratch
+		}
+		if profile == "" {
+			profile = spec.Profile
+		}
+		if group == "" {
+			group = spec.Group
+		}
+		if arraySpec == "" {
+			arraySpec = spec.Array
+		}
+		for key, value := range spec.Labels {
+			if _, exists := labels[key]; !exists {
+				labels[key] = value
+			}
+		}
+		for key, value := range spec.Annotations {
+			if _, exists := annotations[key]; !exists {
+				annotations[key] = value
+			}
+		}
+		for name, value := range spec.Resources.CgroupParams {
+			if _, exists := cgroupParams[name]; !exists {
+				cgroupParams[name] = value
+			}
+		}
+		logSinks = append(spec.LogSinks, logSinks...)
+		jobInputs = append(spec.Inputs, jobInputs...)
+		outputTargets = append(spec.OutputTargets, outputTargets...)
+		egress = append(spec.Egress, egress...)
+		dedup = dedup || spec.Dedup
+		if cacheTTL == 0 && spec.CacheTTL != "" {
+			if cacheTTL, err = time.ParseDuration(spec.CacheTTL); err != nil || cacheTTL <= 0 {
+				return fmt.Errorf("invalid job spec %s: cache_ttl must be a positive duration such as 24h", specFile)
+			}
+		}
+		if queueTTL == 0 && spec.QueueTTL != "" {
+			if queueTTL, err = time.ParseDuration(spec.QueueTTL); err != nil || queueTTL <= 0 {
+				return fmt.Errorf("invalid job spec %s: queue_ttl must be a positive duration such as 2h", specFile)
+			}
+		}
+		if ipcChannel == "" {
+			ipcChannel = spec.IPC
+		}
+		volumes = append(spec.Volumes, volumes...)
+		uploads = append(spec.Uploads.Files, uploads...)
+		uploadDirs = append(spec.Uploads.Directories, uploadDirs...)
+		envVars = append(envPairs(spec.Environment), envVars...)
+		secretEnvVars = append(envPairs(spec.SecretEnvironment), secretEnvVars...)
+		for path, key := range spec.SecretFiles {
+			if _, exists := secretFiles[path]; !exists {
+				secretFiles[path] = key
+			}
+		}
+	}
+
+	// --script and --inline supply the command; what follows the flags are
+	// the script's arguments
+	var inlineUpload *pb.FileUpload
+	if script != "" && hasInline {
+		return fmt.Errorf("--script and --inline cannot be combined")
+	}
+	if script != "" || hasInline {
+		var positional []string
+		if commandStartIndex >= 0 && commandStartIndex < len(args) {
+			positional = args[commandStartIndex:]
+		}
+		var err error
+		if script != "" {
+			command, cmdArgs, err = scriptCommand(script, runtime, positional)
+			uploads = append(uploads, script)
+		} else {
+			inlineUpload, command, cmdArgs, err = inlineCommand(inlineCode, runtime, positional)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if command == "" {
+		return fmt.Errorf("must specify a command to run")
+	}
+	if useStdin && stdinFile != "" {
+		return fmt.Errorf("--stdin and --stdin-file cannot be combined")
+	}
+	if arraySpec != "" && (dedup || cacheTTL > 0) {
+		return fmt.Errorf("--array cannot be combined with --dedup or --cache-ttl")
+	}
+	if useStdin && stdinIsTerminal() {
+		return fmt.Errorf("--stdin needs piped input, e.g. 'cat data.csv | rnx job run --stdin -- %s'", command)
+	}
 
 	// Load client configuration manually since PersistentPreRun doesn't run with DisableFlagParsing
 	var err error
@@ -266,6 +809,20 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load client config: %w", err)
 	}
 
+	// Let rnx pick the node from the capacity each configured node reports
+	if common.NodeName == placement.AutoNode {
+		node, err := placeJob(placement.Request{
+			CPUPercent: int64(maxCPU),
+			MemoryMB:   int64(maxMemory),
+			GPUs:       int64(gpuCount),
+		})
+		if err != nil {
+			return err
+		}
+		common.NodeName = node
+		fmt.Fprintf(os.Stderr, "Placed on node: %s\n", node)
+	}
+
 	// Client creation using unified config
 	jobClient, err := common.NewJobClient()
 	if err != nil {
@@ -277,11 +834,29 @@ func runRun(cmd *cobra.Command, args []string) error {
 	defer cancel()
 
 	// Process file uploads
-	fileUploads, err := processFileUploads(uploads, uploadDirs)
+	fileUploads, err := processFileUploads(uploads, uploadDirs, uploadOptions{
+		maxFileSize: maxUploadSize,
+		progress:    progressWriter(),
+	})
 	if err != nil {
 		return fmt.Errorf("file upload processing failed: %w", err)
 	}
 
+	if inlineUpload != nil {
+		fileUploads = append(fileUploads, inlineUpload)
+	}
+
+	// Input for the job's stdin travels as one more upload
+	stdinPath := ""
+	if useStdin || stdinFile != "" {
+		stdinUpload, err := readStdinUpload(stdinFile, maxUploadSize)
+		if err != nil {
+			return err
+		}
+		fileUploads = append(fileUploads, stdinUpload)
+		stdinPath = stdinUpload.Path
+	}
+
 	// Process environment variables
 	environment, err := processEnvironmentVariables(envVars)
 	if err != nil {
@@ -293,6 +868,11 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("secret environment variable processing failed: %w", err)
 	}
+	for path, key := range secretFiles {
+		if _, exists := secretEnvironment[key]; !exists {
+			return fmt.Errorf("secret file %s names %s, which is not set with --secret-env", path, key)
+		}
+	}
 
 	// Display upload summary if files are being uploaded
 	if len(fileUploads) > 0 {
@@ -319,6 +899,7 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 	// Create job request with RFC3339 formatted schedule
 	request := &pb.RunJobRequest{
+		Name:              jobName,
 		Command:           command,
 		Args:              cmdArgs,
 		MaxCpu:            maxCPU,
@@ -330,21 +911,57 @@ func runRun(cmd *cobra.Command, args []string) error {
 		Network:           network,
 		Volumes:           volumes,
 		Runtime:           runtime,
-		Environment:       environment,
+		Environment:       withSecretFiles(withEgress(withOutputTargets(withInputs(withIPC(withQueueTTL(withCgroupParams(withStdin(withLogSinks(withAnnotations(withLabels(withArray(withGroup(withReuseOptions(withProfile(withFreezeFS(withScratch(withSizeOptions(environment, shmSize, tmpSize), scratch), freezeFS), profile), dedup, cacheTTL, noCache), group), arraySpec), labels), annotations), logSinks), stdinPath), cgroupParams), queueTTL), ipcChannel), jobInputs), outputTargets), egress), secretFiles),
 		SecretEnvironment: secretEnvironment,
 		GpuCount:          gpuCount,
 		GpuMemoryMb:       gpuMemoryMB,
 	}
 
+	// With --queue-offline, make sure the server is reachable before sending
+	// a request that is not safe to retry, and keep it locally if it is not
+	if queueOffline {
+		connectCtx, cancelConnect := context.WithTimeout(ctx, offlineConnectTimeout)
+		err := jobClient.WaitForConnection(connectCtx)
+		cancelConnect()
+		if err != nil {
+			return queueJobOffline(request, err)
+		}
+	}
+
+	if arraySpec != "" {
+		return runJobArray(ctx, jobClient, request, schedule)
+	}
+
 	// Submit job
-	response, err := jobClient.RunJob(ctx, request)
+	response, details, err := jobClient.RunJobWithDetails(ctx, request)
 	if err != nil {
+		if queueOffline && status.Code(err) == codes.Unavailable {
+			return queueJobOffline(request, err)
+		}
 		return fmt.Errorf("failed to run job: %v", err)
 	}
 
 	// Output JSON if requested
 	if common.JSONOutput {
-		return outputRunJobJSON(response, schedule, len(fileUploads), len(environment), len(secretEnvironment))
+		return outputRunJobJSON(response, schedule, len(fileUploads), len(environment), len(secretEnvironment), details)
+	}
+
+	switch details.Reuse {
+	case client.DeduplicatedJob:
+		fmt.Printf("Identical job is already active, no new job was started:\n")
+		fmt.Printf("ID: %s\n", response.JobUuid)
+		statusColor, resetColor := getStatusColor(response.Status)
+		fmt.Printf("Status: %s%s%s\n", statusColor, response.Status, resetColor)
+		return nil
+	case client.CachedJob:
+		fmt.Printf("Cached result of an identical job, no new job was started:\n")
+		fmt.Printf("ID: %s\n", response.JobUuid)
+		statusColor, resetColor := getStatusColor(response.Status)
+		fmt.Printf("Status: %s%s%s\n", statusColor, response.Status, resetColor)
+		fmt.Printf("Exit Code: %d\n", response.ExitCode)
+		fmt.Printf("EndTime: %s\n", response.EndTime)
+		fmt.Printf("Logs: rnx job log %s (use --no-cache to run it again)\n", response.JobUuid)
+		return nil
 	}
 
 	fmt.Printf("Job is running:\n")
@@ -356,6 +973,9 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if schedule != "" {
 		fmt.Printf("Schedule Input: %s\n", schedule) // Show user's original input
 		fmt.Printf("Scheduled Time: %s\n", response.ScheduledTime)
+		if details.ClockWarning != "" {
+			fmt.Printf("Warning: %s\n", details.ClockWarning)
+		}
 	} else {
 		fmt.Printf("StartTime: %s\n", response.StartTime)
 	}
@@ -364,6 +984,38 @@ func runRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Files: %d uploaded successfully\n", len(fileUploads))
 	}
 
+	if details.DetectedRuntime != "" {
+		if details.RuntimeSelected {
+			fmt.Printf("Runtime: %s (selected for the uploaded %s)\n", details.DetectedRuntime, details.DependencyFile)
+		} else {
+			fmt.Printf("Runtime: none, %s matches the uploaded %s (add --runtime=%s)\n", details.DetectedRuntime, details.DependencyFile, details.DetectedRuntime)
+		}
+	}
+
+	if group != "" {
+		fmt.Printf("Group: %s (stop it with 'rnx job stop --group %s')\n", group, group)
+	}
+
+	for _, sink := range logSinks {
+		fmt.Printf("Log Sink: %s\n", sink)
+	}
+
+	for _, input := range jobInputs {
+		fmt.Printf("Input: %s\n", input)
+	}
+
+	for _, target := range outputTargets {
+		fmt.Printf("Output: %s\n", target)
+	}
+
+	if len(egress) > 0 {
+		fmt.Printf("Egress: %s\n", strings.Join(egress, ","))
+	}
+
+	if profile != "" {
+		fmt.Printf("Profile: %s (save it with 'rnx job profile %s' once the job ends)\n", profile, response.JobUuid)
+	}
+
 	if len(environment) > 0 {
 		fmt.Printf("Environment: %d variables set\n", len(environment))
 	}
@@ -372,6 +1024,47 @@ func runRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Secret Environment: %d variables set\n", len(secretEnvironment))
 	}
 
+	if len(secretFiles) > 0 {
+		fmt.Printf("Secret Files: %s\n", values.FormatSecretFiles(secretFiles))
+	}
+
+	return nil
+}
+
+// offlineConnectTimeout is how long --queue-offline waits for the server
+// before queueing the job locally.
+const offlineConnectTimeout = 5 * time.Second
+
+// queueJobOffline stores request in the local queue for 'rnx queue flush'.
+func queueJobOffline(request *pb.RunJobRequest, cause error) error {
+	dir, err := queue.DefaultDir()
+	if err != nil {
+		return err
+	}
+	entry, err := queue.NewStore(dir).Add(common.NodeName, request)
+	if err != nil {
+		return fmt.Errorf("server unreachable (%v) and the job could not be queued: %w", cause, err)
+	}
+
+	if common.JSONOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Queued  bool   `json:"queued"`
+			QueueID string `json:"queue_id"`
+			Node    string `json:"node"`
+			Reason  string `json:"reason"`
+		}{true, entry.ID, entry.Node, cause.Error()})
+	}
+
+	fmt.Printf("Server unreachable, job queued locally:\n")
+	fmt.Printf("Queue ID: %s\n", entry.ID)
+	fmt.Printf("Node: %s\n", entry.Node)
+	fmt.Printf("Reason: %v\n", cause)
+	if request.Schedule != "" {
+		fmt.Printf("Note: the schedule was resolved to %s; if that time has passed when the queue is flushed the job starts immediately\n", request.Schedule)
+	}
+	fmt.Printf("Submit it later with 'rnx queue flush'\n")
 	return nil
 }
 
@@ -380,92 +1073,286 @@ func parseIntFlag(arg, prefix string) (int, error) {
 	return strconv.Atoi(valueStr)
 }
 
-func processFileUploads(uploads []string, uploadDirs []string) ([]*pb.FileUpload, error) {
-	var result []*pb.FileUpload
+// parseSizeFlag parses a size flag such as --shm-size=2g into bytes
+func parseSizeFlag(arg, prefix string) (int64, error) {
+	valueStr := strings.TrimPrefix(arg, prefix)
+	size, err := values.ParseMemorySize(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value '%s': %w", strings.TrimSuffix(prefix, "="), valueStr, err)
+	}
+	return size.Bytes(), nil
+}
 
-	// Process individual file uploads
-	for _, uploadPath := range uploads {
-		fileInfo, err := os.Stat(uploadPath)
-		if err != nil {
-			return nil, fmt.Errorf("cannot access upload file %s: %w", uploadPath, err)
-		}
+// withSizeOptions returns a copy of the environment map carrying the /dev/shm and
+// /tmp size requests as reserved keys (the server strips them before execution)
+func withSizeOptions(environment map[string]string, shmSize, tmpSize int64) map[string]string {
+	if shmSize <= 0 && tmpSize <= 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+2)
+	for key, value := range environment {
+		result[key] = value
+	}
+	if shmSize > 0 {
+		result[constants.EnvShmSize] = strconv.FormatInt(shmSize, 10)
+	}
+	if tmpSize > 0 {
+		result[constants.EnvTmpSize] = strconv.FormatInt(tmpSize, 10)
+	}
+	return result
+}
 
-		if fileInfo.IsDir() {
-			return nil, fmt.Errorf("use --upload-dir for directories: %s", uploadPath)
-		}
+// withScratch returns a copy of the environment map carrying the scratch
+// device request as a reserved key (the server strips it before execution)
+func withScratch(environment map[string]string, scratch string) map[string]string {
+	if scratch == "" {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvScratch] = scratch
+	return result
+}
 
-		content, err := os.ReadFile(uploadPath)
-		if err != nil {
-			return nil, fmt.Errorf("cannot read upload file %s: %w", uploadPath, err)
-		}
+// withFreezeFS returns a copy of the environment map asking the server to keep
+// the job's filesystem if it fails (the server strips the key before execution)
+func withFreezeFS(environment map[string]string, freezeFS bool) map[string]string {
+	if !freezeFS {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvFreezeFS] = "true"
+	return result
+}
 
-		result = append(result, &pb.FileUpload{
-			Path:        filepath.Base(uploadPath),
-			Content:     content,
-			Mode:        uint32(fileInfo.Mode()),
-			IsDirectory: false,
-		})
+// withProfile returns a copy of the environment map carrying the profiler
+// request as a reserved key (the server strips it before execution)
+func withProfile(environment map[string]string, profile string) map[string]string {
+	if profile == "" {
+		return environment
 	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvProfile] = profile
+	return result
+}
 
-	// Process directory uploads
-	for _, uploadDir := range uploadDirs {
-		dirUploads, err := processDirectoryUpload(uploadDir)
-		if err != nil {
-			return nil, fmt.Errorf("directory upload failed for %s: %w", uploadDir, err)
-		}
+// withReuseOptions returns a copy of the environment map carrying the
+// deduplication and result cache requests as reserved keys (the server strips
+// them before execution)
+func withReuseOptions(environment map[string]string, dedup bool, cacheTTL time.Duration, noCache bool) map[string]string {
+	if !dedup && cacheTTL <= 0 && !noCache {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+3)
+	for key, value := range environment {
+		result[key] = value
+	}
+	if dedup {
+		result[constants.EnvDedup] = "true"
+	}
+	if cacheTTL > 0 {
+		result[constants.EnvCacheTTL] = cacheTTL.String()
+	}
+	if noCache {
+		result[constants.EnvNoCache] = "true"
+	}
+	return result
+}
 
-		result = append(result, dirUploads...)
+// withQueueTTL returns a copy of the environment map carrying the queue TTL
+// as a reserved key (the server strips it before execution)
+func withQueueTTL(environment map[string]string, ttl time.Duration) map[string]string {
+	if ttl <= 0 {
+		return environment
 	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvQueueTTL] = ttl.String()
+	return result
+}
 
-	return result, nil
+// withIPC returns a copy of the environment map carrying the IPC channel as a
+// reserved key (the server strips it before execution)
+func withIPC(environment map[string]string, channel string) map[string]string {
+	if channel == "" {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvIPC] = channel
+	return result
 }
 
-func processDirectoryUpload(dir string) ([]*pb.FileUpload, error) {
-	var uploads []*pb.FileUpload
+// withInputs returns a copy of the environment map carrying the inputs the
+// server downloads as a reserved key (the server strips it before execution)
+func withInputs(environment map[string]string, inputs []string) map[string]string {
+	if len(inputs) == 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvInputs] = strings.Join(inputs, "\n")
+	return result
+}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// withOutputTargets returns a copy of the environment map carrying the output
+// targets the server publishes to as a reserved key (the server strips it
+// before execution)
+func withOutputTargets(environment map[string]string, targets []string) map[string]string {
+	if len(targets) == 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvOutputTargets] = strings.Join(targets, "\n")
+	return result
+}
 
-		// Calculate relative path
-		relPath, err := filepath.Rel(dir, path)
-		if err != nil {
-			return err
-		}
+// withEgress returns a copy of the environment map carrying the egress
+// policy as a reserved key (the server strips it before execution)
+func withEgress(environment map[string]string, egress []string) map[string]string {
+	if len(egress) == 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvEgress] = strings.Join(egress, ",")
+	return result
+}
 
-		// Skip the root directory itself
-		if relPath == "." {
-			return nil
-		}
+// withSecretFiles returns a copy of the environment map carrying the paths
+// the secret variables are delivered at as a reserved key (the server strips
+// it before execution)
+func withSecretFiles(environment map[string]string, secretFiles map[string]string) map[string]string {
+	if len(secretFiles) == 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvSecretFiles] = values.FormatSecretFiles(secretFiles)
+	return result
+}
 
-		if info.IsDir() {
-			// Create directory entry
-			uploads = append(uploads, &pb.FileUpload{
-				Path:        relPath,
-				Content:     nil,
-				Mode:        uint32(info.Mode()),
-				IsDirectory: true,
-			})
-		} else {
-			// Read file content
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return fmt.Errorf("cannot read file %s: %w", path, err)
-			}
+// withGroup returns a copy of the environment map carrying the job group as a
+// reserved key (the server strips it before execution)
+func withGroup(environment map[string]string, group string) map[string]string {
+	if group == "" {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvGroup] = group
+	return result
+}
 
-			uploads = append(uploads, &pb.FileUpload{
-				Path:        relPath,
-				Content:     content,
-				Mode:        uint32(info.Mode()),
-				IsDirectory: false,
-			})
-		}
+// withLabels returns a copy of the environment map carrying the job labels as
+// a reserved key (the server strips it before execution)
+func withLabels(environment map[string]string, labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvLabels] = values.FormatLabels(labels)
+	return result
+}
 
-		return nil
-	})
+// withLogSinks returns a copy of the environment map carrying the log sink
+// URLs as a reserved key (the server strips it before execution)
+func withLogSinks(environment map[string]string, sinks []string) map[string]string {
+	if len(sinks) == 0 {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvLogSinks] = strings.Join(sinks, ",")
+	return result
+}
+
+// withStdin returns a copy of the environment map naming the upload delivered
+// to the job's stdin as a reserved key (the server strips it before execution)
+func withStdin(environment map[string]string, path string) map[string]string {
+	if path == "" {
+		return environment
+	}
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvStdin] = path
+	return result
+}
 
-	return uploads, err
+// withCgroupParams returns a copy of the environment map carrying the raw
+// cgroup files as a reserved JSON key (the server strips it before execution)
+func withCgroupParams(environment map[string]string, params map[string]string) map[string]string {
+	if len(params) == 0 {
+		return environment
+	}
+	encoded, _ := json.Marshal(params)
+	result := make(map[string]string, len(environment)+1)
+	for key, value := range environment {
+		result[key] = value
+	}
+	result[constants.EnvCgroupParams] = string(encoded)
+	return result
+}
+
+// readStdinUpload reads the job's stdin from path, or from rnx's own stdin
+// when path is empty, into an upload no larger than maxSize (0 = no limit)
+func readStdinUpload(path string, maxSize int64) (*pb.FileUpload, error) {
+	source, name := os.Stdin, "stdin"
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open --stdin-file: %w", err)
+		}
+		defer file.Close()
+		source, name = file, path
+	}
+
+	reader := io.Reader(source)
+	if maxSize > 0 {
+		reader = io.LimitReader(source, maxSize+1)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if maxSize > 0 && int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("%s is larger than --max-upload-size (%d bytes)", name, maxSize)
+	}
+	return &pb.FileUpload{
+		Path:    constants.StdinUploadPath,
+		Content: content,
+		Mode:    0600,
+	}, nil
 }
 
 // parseScheduleOnClient parses schedule specifications on the client side
@@ -692,15 +1579,16 @@ func executeWorkflowViaService(workflowPath string, workflowName string) error {
 		return fmt.Errorf("failed to read YAML file %s: %w", workflowPath, err)
 	}
 
-	var workflow types.WorkflowYAML
-	if err := yaml.Unmarshal(yamlContent, &workflow); err != nil {
-		return fmt.Errorf("failed to parse YAML: %w", err)
+	workflow, err := parseWorkflowContent(yamlContent)
+	if err != nil {
+		return err
 	}
 
 	// Validate workflow before submission
 	if err := validateWorkflowPreRequisites(workflow); err != nil {
 		return fmt.Errorf("workflow validation failed: %w", err)
 	}
+	fmt.Println("Workflow validation passed")
 
 	// Extract and upload all files referenced in jobs
 	workflowFiles, err := extractWorkflowFiles(workflowPath, workflow)
@@ -720,19 +1608,39 @@ func executeWorkflowViaService(workflowPath string, workflowName string) error {
 
 	workflowClient := pb.NewJobServiceClient(client.GetConn())
 
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Send large files as deltas against the copies the server kept from
+	// earlier runs
+	uploads := syncWorkflowFiles(ctx, client, workflowFiles)
+	if len(uploads.refs) > 0 {
+		fmt.Printf("Synced %d large files: sent %s of %s\n", len(uploads.refs), formatBytes(uploads.sent), formatBytes(uploads.total))
+	}
+	runCtx, err := uploads.withUploadRefs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to encode synced uploads: %w", err)
+	}
+
 	// Create workflow with YAML content and files
 	createReq := &pb.RunWorkflowRequest{
 		Workflow:      filepath.Base(workflowPath),
 		YamlContent:   string(yamlContent),
-		WorkflowFiles: workflowFiles,
+		WorkflowFiles: uploads.files,
 		TotalJobs:     int32(len(workflow.Jobs)),
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	createRes, err := workflowClient.RunWorkflow(ctx, createReq)
+	createRes, err := workflowClient.RunWorkflow(withCIAnnotations(runCtx), createReq)
+	if status.Code(err) == codes.FailedPrecondition && len(uploads.refs) > 0 {
+		// The server dropped a synced file meanwhile, send them all whole
+		fmt.Printf("Synced files no longer cached on the server, uploading them whole\n")
+		createReq.WorkflowFiles = workflowFiles
+		createRes, err = workflowClient.RunWorkflow(withCIAnnotations(ctx), createReq)
+	}
 	if err != nil {
+		if strings.Contains(err.Error(), "workflow validation failed") {
+			return fmt.Errorf("failed to create workflow: %w\nRun 'rnx workflow validate %s' to list every problem", err, workflowPath)
+		}
 		return fmt.Errorf("failed to create workflow: %w", err)
 	}
 
@@ -742,57 +1650,102 @@ func executeWorkflowViaService(workflowPath string, workflowName string) error {
 	return nil
 }
 
-// extractWorkflowFiles extracts and reads all files referenced in workflow jobs
-func extractWorkflowFiles(yamlPath string, workflow types.WorkflowYAML) ([]*pb.FileUpload, error) {
+// extractWorkflowFiles extracts and reads all files referenced in workflow
+// jobs. Patterns, directories and mappings are expanded here, as the server
+// expands them over the files sent.
+func extractWorkflowFiles(yamlPath string, wf types.WorkflowYAML) ([]*pb.FileUpload, error) {
 	var uploads []*pb.FileUpload
 	yamlDir := filepath.Dir(yamlPath)
 	uploadedFiles := make(map[string]bool)
 
 	// Collect all file uploads from all jobs
-	for jobName, job := range workflow.Jobs {
-		if job.Uploads != nil {
-			for _, fileName := range job.Uploads.Files {
-				if uploadedFiles[fileName] {
-					continue // Skip duplicates
-				}
+	for jobName, job := range wf.Jobs {
+		sources, err := workflow.UploadSources(job.Uploads, listWorkflowDir(yamlDir))
+		if err != nil {
+			return nil, fmt.Errorf("uploads of job %s: %w", jobName, err)
+		}
+		for _, fileName := range sources {
+			if uploadedFiles[fileName] {
+				continue // Skip duplicates
+			}
 
-				// Try relative to YAML file first, then absolute path
-				filePath := filepath.Join(yamlDir, fileName)
+			// Try relative to YAML file first, then absolute path
+			filePath := filepath.Join(yamlDir, fileName)
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				// Try absolute path
+				filePath = fileName
 				if _, err := os.Stat(filePath); os.IsNotExist(err) {
-					// Try absolute path
-					filePath = fileName
-					if _, err := os.Stat(filePath); os.IsNotExist(err) {
-						return nil, fmt.Errorf("file %s referenced in job %s not found", fileName, jobName)
-					}
+					return nil, fmt.Errorf("file %s referenced in job %s not found", fileName, jobName)
 				}
+			}
 
-				// Read file content
-				content, err := os.ReadFile(filePath)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
-				}
+			// Read file content
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+			}
 
-				// Get file info
-				fileInfo, err := os.Stat(filePath)
-				if err != nil {
-					return nil, fmt.Errorf("failed to get file info for %s: %w", filePath, err)
-				}
+			// Get file info
+			fileInfo, err := os.Stat(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get file info for %s: %w", filePath, err)
+			}
 
-				uploads = append(uploads, &pb.FileUpload{
-					Path:        fileName,
-					Content:     content,
-					Mode:        uint32(fileInfo.Mode()),
-					IsDirectory: false,
-				})
+			uploads = append(uploads, &pb.FileUpload{
+				Path:        fileName,
+				Content:     content,
+				Mode:        uint32(fileInfo.Mode()),
+				IsDirectory: false,
+			})
 
-				uploadedFiles[fileName] = true
-			}
+			uploadedFiles[fileName] = true
+		}
+	}
+
+	// Fail before sending when a pattern or directory matches nothing
+	sent := make([]string, 0, len(uploads))
+	for _, upload := range uploads {
+		sent = append(sent, upload.Path)
+	}
+	for jobName, job := range wf.Jobs {
+		if _, err := workflow.UploadTargets(job.Uploads, sent); err != nil {
+			return nil, fmt.Errorf("uploads of job %s: %w", jobName, err)
 		}
 	}
 
 	return uploads, nil
 }
 
+// listWorkflowDir lists the regular files under a directory relative to the
+// workflow YAML, as slash paths relative to it; a missing directory has none
+func listWorkflowDir(yamlDir string) func(dir string) ([]string, error) {
+	return func(dir string) ([]string, error) {
+		root := filepath.Join(yamlDir, filepath.FromSlash(dir))
+		var files []string
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == root && errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(yamlDir, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+			return nil
+		})
+		return files, err
+	}
+}
+
 // validateWorkflowPreRequisites performs comprehensive validation of workflow before submission
 func validateWorkflowPreRequisites(workflow types.WorkflowYAML) error {
 	// Fail-fast validation - stop immediately on first error
@@ -822,8 +1775,6 @@ func validateWorkflowPreRequisites(workflow types.WorkflowYAML) error {
 		return fmt.Errorf("there's an issue with job dependencies: %w", err)
 	}
 
-	// Only show success output if ALL validations pass
-	fmt.Println("Workflow validation passed")
 	return nil
 }
 
@@ -899,7 +1850,7 @@ func extractJobNamesFromExpression(expr string) []string {
 
 // isStatusOrOperator checks if a token is a status value or operator
 func isStatusOrOperator(token string) bool {
-	statuses := []string{"COMPLETED", "FAILED", "CANCELED", "STOPPED", "RUNNING", "PENDING", "SCHEDULED"}
+	statuses := []string{"COMPLETED", "FAILED", "CANCELED", "STOPPED", "RUNNING", "PENDING", "SCHEDULED", "QUEUED", "EXPIRED"}
 	operators := []string{"AND", "OR", "NOT", "IN", "NOT_IN", "&&", "||", "!"}
 
 	for _, status := range statuses {
@@ -1242,7 +2193,8 @@ func validateJobDependencies(workflow types.WorkflowYAML) error {
 }
 
 // outputRunJobJSON outputs the run job response in JSON format
-func outputRunJobJSON(response *pb.RunJobResponse, scheduleInput string, fileCount, envCount, secretEnvCount int) error {
+func outputRunJobJSON(response *pb.RunJobResponse, scheduleInput string, fileCount, envCount, secretEnvCount int, details client.RunJobDetails) error {
+	reuse := details.Reuse
 	// Create a structured response that includes additional context
 	output := struct {
 		JobUUID       string   `json:"job_uuid"`
@@ -1255,6 +2207,16 @@ func outputRunJobJSON(response *pb.RunJobResponse, scheduleInput string, fileCou
 		FilesUploaded int      `json:"files_uploaded,omitempty"`
 		EnvVars       int      `json:"env_vars,omitempty"`
 		SecretEnvVars int      `json:"secret_env_vars,omitempty"`
+		Deduplicated  bool     `json:"deduplicated,omitempty"`
+		Cached        bool     `json:"cached,omitempty"`
+		EndTime       string   `json:"end_time,omitempty"`
+		ExitCode      *int32   `json:"exit_code,omitempty"`
+		// Runtime detected for an uploaded dependency file when none was given
+		SuggestedRuntime string `json:"suggested_runtime,omitempty"`
+		SelectedRuntime  string `json:"selected_runtime,omitempty"`
+		DependencyFile   string `json:"dependency_file,omitempty"`
+		// Scheduled time within the uncertainty of the server's clock
+		ClockWarning string `json:"clock_warning,omitempty"`
 	}{
 		JobUUID:       response.JobUuid,
 		Command:       response.Command,
@@ -1266,6 +2228,21 @@ func outputRunJobJSON(response *pb.RunJobResponse, scheduleInput string, fileCou
 		FilesUploaded: fileCount,
 		EnvVars:       envCount,
 		SecretEnvVars: secretEnvCount,
+		Deduplicated:  reuse == client.DeduplicatedJob,
+		Cached:        reuse == client.CachedJob,
+		EndTime:       response.EndTime,
+		ClockWarning:  details.ClockWarning,
+	}
+	if details.DetectedRuntime != "" {
+		output.DependencyFile = details.DependencyFile
+		if details.RuntimeSelected {
+			output.SelectedRuntime = details.DetectedRuntime
+		} else {
+			output.SuggestedRuntime = details.DetectedRuntime
+		}
+	}
+	if reuse == client.CachedJob {
+		output.ExitCode = &response.ExitCode
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+// scriptLanguage is a language --script and --inline know how to run
+type scriptLanguage struct {
+	runtimes    []string // Prefixes of the runtime names providing it
+	extensions  []string // Script extensions, the first one used for --inline
+	interpreter string
+}
+
+var scriptLanguages = []scriptLanguage{
+	{runtimes: []string{"python"}, extensions: []string{".py"}, interpreter: "python3"},
+	{runtimes: []string{"node"}, extensions: []string{".js", ".mjs"}, interpreter: "node"},
+	{runtimes: []string{"openjdk", "graalvmjdk", "java"}, extensions: []string{".java"}, interpreter: "java"},
+	{runtimes: []string{"ruby"}, extensions: []string{".rb"}, interpreter: "ruby"},
+	{extensions: []string{".sh"}, interpreter: "bash"},
+}
+
+// runtimeLanguage returns the language of a runtime such as python-3.11-ml
+// or python@stable
+func runtimeLanguage(runtime string) (scriptLanguage, bool) {
+	for _, lang := range scriptLanguages {
+		for _, prefix := range lang.runtimes {
+			if strings.HasPrefix(runtime, prefix) {
+				return lang, true
+			}
+		}
+	}
+	return scriptLanguage{}, false
+}
+
+// extensionLanguage returns the language of a script by its extension
+func extensionLanguage(script string) (scriptLanguage, bool) {
+	ext := filepath.Ext(script)
+	for _, lang := range scriptLanguages {
+		for _, e := range lang.extensions {
+			if e == ext {
+				return lang, true
+			}
+		}
+	}
+	return scriptLanguage{}, false
+}
+
+// scriptCommand returns the command running a script uploaded with --script:
+// the interpreter its #! line names, otherwise the one of its extension,
+// otherwise the one of the runtime. What the script says about itself wins,
+// so a .sh helper runs with bash in a python runtime. The script runs from
+// /work under its base name, followed by args.
+func scriptCommand(script, runtime string, args []string) (string, []string, error) {
+	name := filepath.Base(script)
+	scriptArgs := append([]string{name}, args...)
+
+	interpreter, interpreterArgs, err := shebang(script)
+	if err != nil {
+		return "", nil, err
+	}
+	if interpreter != "" {
+		return interpreter, append(interpreterArgs, scriptArgs...), nil
+	}
+	if lang, ok := extensionLanguage(script); ok {
+		return lang.interpreter, scriptArgs, nil
+	}
+	if lang, ok := runtimeLanguage(runtime); ok {
+		return lang.interpreter, scriptArgs, nil
+	}
+	return "", nil, fmt.Errorf("cannot tell which interpreter runs %s: give it a known extension, a #! line or a --runtime", script)
+}
+
+// shebang returns the interpreter and its arguments from the #! line of a
+// script, with /usr/bin/env (and its -S) left out; empty when there is no
+// #! line. An env without a command to run is an error.
+func shebang(script string) (string, []string, error) {
+	file, err := os.Open(script)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot access script %s: %w", script, err)
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && line == "" {
+		return "", nil, nil
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return "", nil, nil
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) > 0 && filepath.Base(fields[0]) == "env" {
+		fields = fields[1:]
+		if len(fields) > 0 && fields[0] == "-S" {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			return "", nil, fmt.Errorf("the #! line of %s runs env without a command", script)
+		}
+	}
+	if len(fields) == 0 {
+		return "", nil, nil
+	}
+	return fields[0], fields[1:], nil
+}
+
+// inlineCommand returns the upload holding code given with --inline and the
+// command running it with the interpreter of the runtime. The code travels
+// as a file rather than an argument, so it needs no shell quoting.
+func inlineCommand(code, runtime string, args []string) (*pb.FileUpload, string, []string, error) {
+	if runtime == "" {
+		return nil, "", nil, fmt.Errorf("--inline needs a --runtime to choose an interpreter, e.g. --runtime=python-3.11")
+	}
+	lang, ok := runtimeLanguage(runtime)
+	if !ok {
+		return nil, "", nil, fmt.Errorf("cannot tell which interpreter runtime %s provides for --inline; upload a script with --script instead", runtime)
+	}
+	upload := &pb.FileUpload{
+		Path:    inlineScriptName + lang.extensions[0],
+		Content: []byte(code),
+		Mode:    0644,
+	}
+	return upload, lang.interpreter, append([]string{upload.Path}, args...), nil
+}
+
+// inlineScriptName is the base name of the file --inline code is run from
+const inlineScriptName = "rnx-inline"
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestScriptCommand(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	analyze := write("analyze.py", "print(1)\n")
+	report := write("report", "#!/usr/bin/env -S ruby -w\nputs 1\n")
+	plain := write("plain", "echo hi\n")
+	setup := write("setup.sh", "pip install -r requirements.txt\n")
+	train := write("train.py", "#!/usr/bin/env python3.12\nprint(1)\n")
+
+	tests := []struct {
+		name, script, runtime string
+		command               string
+		args                  []string
+	}{
+		{"extension", analyze, "", "python3", []string{"analyze.py", "data.csv"}},
+		{"runtime", plain, "node-20", "node", []string{"plain", "data.csv"}},
+		{"extension over runtime", setup, "python@stable", "bash", []string{"setup.sh", "data.csv"}},
+		{"shebang", report, "", "ruby", []string{"-w", "report", "data.csv"}},
+		{"shebang over runtime", report, "python-3.11", "ruby", []string{"-w", "report", "data.csv"}},
+		{"shebang over extension", train, "", "python3.12", []string{"train.py", "data.csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, args, err := scriptCommand(tt.script, tt.runtime, []string{"data.csv"})
+			if err != nil {
+				t.Fatalf("scriptCommand() error = %v", err)
+			}
+			if command != tt.command || !slices.Equal(args, tt.args) {
+				t.Errorf("scriptCommand() = %s %v, want %s %v", command, args, tt.command, tt.args)
+			}
+		})
+	}
+
+	if _, _, err := scriptCommand(plain, "", nil); err == nil {
+		t.Error("scriptCommand() without extension, #! line or runtime succeeded")
+	}
+	for _, content := range []string{"#!/usr/bin/env\necho hi\n", "#!/usr/bin/env -S\necho hi\n"} {
+		if _, _, err := scriptCommand(write("bare-env", content), "python-3.11", nil); err == nil {
+			t.Errorf("scriptCommand() with %q succeeded", content)
+		}
+	}
+}
+
+func TestInlineCommand(t *testing.T) {
+	upload, command, args, err := inlineCommand("print(1)", "python-3.11-ml", []string{"-v"})
+	if err != nil {
+		t.Fatalf("inlineCommand() error = %v", err)
+	}
+	if upload.Path != "rnx-inline.py" || string(upload.Content) != "print(1)" {
+		t.Errorf("inlineCommand() upload = %s %q", upload.Path, upload.Content)
+	}
+	if command != "python3" || !slices.Equal(args, []string{"rnx-inline.py", "-v"}) {
+		t.Errorf("inlineCommand() = %s %v", command, args)
+	}
+
+	if _, _, _, err := inlineCommand("print(1)", "", nil); err == nil {
+		t.Error("inlineCommand() without runtime succeeded")
+	}
+	if _, _, _, err := inlineCommand("print(1)", "custom-tools", nil); err == nil {
+		t.Error("inlineCommand() with an unknown runtime succeeded")
+	}
+}
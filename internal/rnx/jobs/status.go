@@ -4,14 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/client"
+	"github.com/ehsaniara/joblet/pkg/constants"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func NewStatusCmd() *cobra.Command {
@@ -47,6 +54,9 @@ Examples:
   # Get job status in JSON format (all fields)
   rnx job status --json f47ac10b
 
+  # Aggregated status of a job array started with 'rnx job run --array'
+  rnx job status <array-uuid>
+
   # For workflow status, use:
   rnx workflow status <workflow-uuid>
 
@@ -68,8 +78,9 @@ Job Status Information Displayed:
 Output Formats:
   • Default: Readable formatted output with sections
   • --json: Machine-readable JSON with all available fields`,
-		Args: cobra.ExactArgs(1),
-		RunE: runStatus,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeIDs(idspb.IdType_ID_TYPE_JOB),
+		RunE:              runStatus,
 	}
 
 	return cmd
@@ -87,16 +98,24 @@ func getJobStatus(jobID string) error {
 	}
 	defer jobClient.Close()
 
+	jobID, err = resolveID(jobClient, jobID, idspb.IdType_ID_TYPE_JOB)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	response, err := jobClient.GetJobStatus(ctx, jobID)
+	response, details, err := jobClient.GetJobStatusWithDetails(ctx, jobID)
 	if err != nil {
+		if array := findJobArray(ctx, jobClient, jobID, err); array != nil {
+			return printJobArray(array)
+		}
 		return fmt.Errorf("couldn't get job status: %v", err)
 	}
 
 	if common.JSONOutput {
-		return outputJobStatusJSON(response)
+		return outputJobStatusJSON(response, details)
 	}
 
 	// Display basic job information
@@ -271,21 +290,90 @@ func getJobStatus(jobID string) error {
 	}
 
 	// Display exit code for completed jobs
-	if response.Status != "RUNNING" && response.Status != "SCHEDULED" && response.Status != "INITIALIZING" {
+	if response.Status != "RUNNING" && response.Status != "SCHEDULED" && response.Status != "QUEUED" && response.Status != "INITIALIZING" {
 		fmt.Printf("\nResult:\n")
 		fmt.Printf("  Exit Code: %d\n", response.ExitCode)
 	}
 
+	// Secrets the server masked in the job's output
+	if details.Redactions > 0 {
+		fmt.Printf("\nOutput Redaction:\n")
+		fmt.Printf("  Redactions: %d\n", details.Redactions)
+	}
+
+	// Output the server dropped over its rate limit or size cap
+	if details.OutputDropped > 0 {
+		fmt.Printf("\nOutput Limits:\n")
+		fmt.Printf("  Dropped: %s\n", formatBytes(details.OutputDropped))
+		if details.OutputTruncated {
+			fmt.Printf("  Truncated: yes, the job reached the output size cap\n")
+		}
+	}
+
+	// Submission signature, checked again by the server for this request
+	if details.SignatureStatus != "" {
+		fmt.Printf("\nSignature:\n")
+		fmt.Printf("  Status: %s\n", details.SignatureStatus)
+		fmt.Printf("  Signer: %s\n", details.Signer)
+	}
+
+	// Environment the job started in, to compare with other runs
+	if fp := details.Fingerprint; fp != nil {
+		fmt.Printf("\nEnvironment Fingerprint:\n")
+		fmt.Printf("  Digest: %s\n", fp.Digest)
+		if fp.Runtime != "" {
+			fmt.Printf("  Runtime: %s %s (checksum %s)\n", fp.Runtime, fp.RuntimeVersion, shortHash(fp.RuntimeChecksum))
+		}
+		fmt.Printf("  Chroot Mounts: %s\n", shortHash(fp.MountsHash))
+		fmt.Printf("  Kernel: %s\n", fp.KernelVersion)
+		fmt.Printf("  Joblet: %s\n", fp.JobletVersion)
+	}
+
+	// Run flagged for taking far longer than the usual runs of its name
+	if a := details.Anomaly; a != nil {
+		fmt.Printf("\nDuration Anomaly:\n")
+		fmt.Printf("  %s\n", describeAnomaly(a))
+		fmt.Printf("  Detected: %s (while %s)\n", a.DetectedAt.Local().Format("2006-01-02 15:04:05"), a.Status)
+	}
+
+	// Network activity of the job's own network namespace over its run
+	if n := details.Network; n != nil {
+		fmt.Printf("\nNetwork:\n")
+		fmt.Printf("  Received: %s (%d packets)\n", formatBytesUint(n.RxBytes), n.RxPackets)
+		fmt.Printf("  Sent: %s (%d packets)\n", formatBytesUint(n.TxBytes), n.TxPackets)
+		fmt.Printf("  Peak Connections: %d\n", n.PeakConnections)
+		fmt.Printf("  TCP Retransmits: %d\n", n.Retransmits)
+	}
+
+	// Notes on where the job comes from, such as its CI pipeline
+	if len(details.Annotations) > 0 {
+		fmt.Printf("\nAnnotations:\n")
+		for _, key := range slices.Sorted(maps.Keys(details.Annotations)) {
+			fmt.Printf("  %s: %s\n", key, details.Annotations[key])
+		}
+	}
+
+	// Launch attempts, with the infrastructure failures that were retried
+	if len(details.Events) > 0 {
+		fmt.Printf("\nEvents:\n")
+		for _, event := range details.Events {
+			fmt.Printf("  %s  %-13s  %s\n", event.Time.Format("2006-01-02 15:04:05"), event.Type, event.Message)
+		}
+	}
+
 	// Provide helpful next steps based on job status
 	fmt.Printf("\nAvailable Actions:\n")
 	switch response.Status {
 	case "SCHEDULED":
 		fmt.Printf("  • rnx job stop %s     # Cancel scheduled job\n", response.Uuid)
 		fmt.Printf("  • rnx job status %s   # Check status again\n", response.Uuid)
+	case "QUEUED":
+		fmt.Printf("  • rnx job stop %s     # Cancel queued job\n", response.Uuid)
+		fmt.Printf("  • rnx job status %s   # Check status again\n", response.Uuid)
 	case "RUNNING":
 		fmt.Printf("  • rnx job log %s      # Stream live logs\n", response.Uuid)
 		fmt.Printf("  • rnx job stop %s     # Stop running job\n", response.Uuid)
-	case "COMPLETED", "FAILED", "STOPPED":
+	case "COMPLETED", "FAILED", "STOPPED", "EXPIRED":
 		fmt.Printf("  • rnx job log %s      # View job logs\n", response.Uuid)
 	default:
 		fmt.Printf("  • rnx job log %s      # View job logs\n", response.Uuid)
@@ -295,6 +383,17 @@ func getJobStatus(jobID string) error {
 	return nil
 }
 
+// shortHash shortens a hex hash for display, "-" when there is none
+func shortHash(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
 // formatTimestamp formats a timestamp string for display
 func formatTimestamp(timestamp string) string {
 	if timestamp == "" {
@@ -334,7 +433,7 @@ func formatDuration(d time.Duration) string {
 }
 
 // outputJobStatusJSON outputs the job status in JSON format
-func outputJobStatusJSON(response *pb.GetJobStatusRes) error {
+func outputJobStatusJSON(response *pb.GetJobStatusRes, details client.JobStatusDetails) error {
 	// Create a structured output that includes all fields, even when empty
 	output := map[string]interface{}{
 		"uuid":              response.Uuid,
@@ -356,6 +455,36 @@ func outputJobStatusJSON(response *pb.GetJobStatusRes) error {
 		"uploads":           response.Uploads,
 		"dependencies":      response.Dependencies,
 		"workflowUuid":      response.WorkflowUuid,
+		"redactions":        details.Redactions,
+		"outputDropped":     details.OutputDropped,
+		"outputTruncated":   details.OutputTruncated,
+	}
+
+	if len(details.Events) > 0 {
+		output["events"] = details.Events
+	}
+
+	if details.Fingerprint != nil {
+		output["fingerprint"] = details.Fingerprint
+	}
+
+	if details.Anomaly != nil {
+		output["anomaly"] = details.Anomaly
+	}
+
+	if details.Network != nil {
+		output["network"] = details.Network
+	}
+
+	if len(details.Annotations) > 0 {
+		output["annotations"] = details.Annotations
+	}
+
+	if details.SignatureStatus != "" {
+		output["signature"] = map[string]string{
+			"status": details.SignatureStatus,
+			"signer": details.Signer,
+		}
 	}
 
 	// Include resource limits if set
@@ -416,14 +545,14 @@ func outputJobStatusJSON(response *pb.GetJobStatusRes) error {
 // RETURNS:
 // - error: If client creation fails, request fails, or formatting errors occur
 func GetWorkflowStatus(workflowID string, showDetail bool) error {
-	client, err := common.NewJobClient()
+	jobClient, err := common.NewJobClient()
 	if err != nil {
 		return fmt.Errorf("couldn't connect to joblet server: %w", err)
 	}
-	defer client.Close()
+	defer jobClient.Close()
 
 	// Create workflow service client
-	workflowClient := pb.NewJobServiceClient(client.GetConn())
+	workflowClient := pb.NewJobServiceClient(jobClient.GetConn())
 
 	req := &pb.GetWorkflowStatusRequest{
 		WorkflowUuid: workflowID,
@@ -432,13 +561,25 @@ func GetWorkflowStatus(workflowID string, showDetail bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	res, err := workflowClient.GetWorkflowStatus(ctx, req)
+	var header metadata.MD
+	res, err := workflowClient.GetWorkflowStatus(ctx, req, grpc.Header(&header))
 	if err != nil {
 		return fmt.Errorf("couldn't get workflow status: %w", err)
 	}
 
+	// Environments the started jobs ran in, by job name
+	var fingerprints map[string]client.JobFingerprint
+	if values := header.Get(constants.WorkflowFingerprintsHeader); len(values) > 0 {
+		_ = json.Unmarshal([]byte(values[0]), &fingerprints)
+	}
+	// Jobs flagged for taking far longer than usual, by job name
+	var anomalies map[string]client.DurationAnomaly
+	if values := header.Get(constants.WorkflowAnomaliesHeader); len(values) > 0 {
+		_ = json.Unmarshal([]byte(values[0]), &anomalies)
+	}
+
 	if common.JSONOutput {
-		return outputWorkflowStatusJSON(res, showDetail)
+		return outputWorkflowStatusJSON(res, fingerprints, anomalies, showDetail)
 	}
 
 	workflow := res.Workflow
@@ -463,7 +604,15 @@ func GetWorkflowStatus(workflowID string, showDetail bool) error {
 	if workflow.FailedJobs > 0 {
 		fmt.Printf(" (%d failed)", workflow.FailedJobs)
 	}
-	fmt.Printf("\n\n")
+	fmt.Printf("\n")
+	if signatureStatus := header.Get(constants.SignatureStatusHeader); len(signatureStatus) > 0 {
+		fmt.Printf("Signature: %s", signatureStatus[0])
+		if signer := header.Get(constants.SignerHeader); len(signer) > 0 {
+			fmt.Printf(" (signer %s)", signer[0])
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n")
 
 	// Display timing information
 	fmt.Printf("Timing:\n")
@@ -538,8 +687,14 @@ func GetWorkflowStatus(workflowID string, showDetail bool) error {
 					duration := endTime.Sub(startTime)
 					fmt.Printf("  Duration: %s", formatDuration(duration))
 				}
+				if fp, ok := fingerprints[job.JobName]; ok {
+					fmt.Printf("  Env: %s", fp.Digest)
+				}
 				fmt.Printf("\n")
 			}
+			if a, ok := anomalies[job.JobName]; ok {
+				fmt.Printf("                                        Duration anomaly: %s\n", describeAnomaly(&a))
+			}
 		}
 		fmt.Printf("\n")
 	}
@@ -561,7 +716,7 @@ func GetWorkflowStatus(workflowID string, showDetail bool) error {
 }
 
 // outputWorkflowStatusJSON outputs workflow status in JSON format
-func outputWorkflowStatusJSON(res *pb.GetWorkflowStatusResponse, showDetail bool) error {
+func outputWorkflowStatusJSON(res *pb.GetWorkflowStatusResponse, fingerprints map[string]client.JobFingerprint, anomalies map[string]client.DurationAnomaly, showDetail bool) error {
 	// Convert protobuf workflow status to JSON structure
 	statusData := map[string]interface{}{
 		"uuid":           res.Workflow.Uuid,
@@ -599,6 +754,12 @@ func outputWorkflowStatusJSON(res *pb.GetWorkflowStatusResponse, showDetail bool
 		if job.EndTime != nil && job.EndTime.Seconds > 0 {
 			jobData["end_time"] = job.EndTime
 		}
+		if fp, ok := fingerprints[job.JobName]; ok {
+			jobData["fingerprint"] = fp
+		}
+		if a, ok := anomalies[job.JobName]; ok {
+			jobData["anomaly"] = a
+		}
 		statusData["jobs"] = append(statusData["jobs"].([]map[string]interface{}), jobData)
 	}
 
@@ -607,6 +768,16 @@ func outputWorkflowStatusJSON(res *pb.GetWorkflowStatusResponse, showDetail bool
 	return encoder.Encode(statusData)
 }
 
+// describeAnomaly summarizes how far a run exceeded its usual duration
+func describeAnomaly(a *client.DurationAnomaly) string {
+	elapsed := time.Duration(a.Elapsed * float64(time.Second)).Round(time.Second)
+	mean := time.Duration(a.Mean * float64(time.Second)).Round(time.Second)
+	if a.Sigmas > 0 {
+		return fmt.Sprintf("ran %s, %.1fσ above the mean of %s over %d runs", formatDuration(elapsed), a.Sigmas, formatDuration(mean), a.Samples)
+	}
+	return fmt.Sprintf("ran %s, against a mean of %s over %d runs", formatDuration(elapsed), formatDuration(mean), a.Samples)
+}
+
 // displayWorkflowYAMLContent displays YAML content directly from server
 func displayWorkflowYAMLContent(yamlContent string) {
 	fmt.Printf("YAML Content:\n")
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/ehsaniara/joblet/pkg/client"
 )
 
 func TestFormatTimestamp(t *testing.T) {
@@ -97,6 +99,19 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+func TestDescribeAnomaly(t *testing.T) {
+	a := &client.DurationAnomaly{Elapsed: 4 * 3600, Mean: 3600, StdDev: 300, Sigmas: 36, Samples: 30}
+	if got, want := describeAnomaly(a), "ran 4h, 36.0σ above the mean of 1h over 30 runs"; got != want {
+		t.Errorf("describeAnomaly() = %q, want %q", got, want)
+	}
+
+	// Runs that all took as long have no deviation to count in
+	a = &client.DurationAnomaly{Elapsed: 90, Mean: 60, Samples: 5}
+	if got, want := describeAnomaly(a), "ran 1.5m, against a mean of 1.0m over 5 runs"; got != want {
+		t.Errorf("describeAnomaly() = %q, want %q", got, want)
+	}
+}
+
 func TestGetStatusColor(t *testing.T) {
 	// Test that getStatusColor function exists and returns consistent values
 	// This is a basic test since we can't test actual terminal colors easily
@@ -8,15 +8,19 @@ import (
 	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
 
 	"github.com/spf13/cobra"
 )
 
 // NewStopCmd creates a new cobra command for stopping jobs.
-// The command requires exactly one argument: the job UUID to stop.
-// Sends a stop request to the Joblet server for the specified job.
+// The command takes the job UUID to stop, or --group to stop every job of a
+// job group with a single bulk request. A job array UUID stops every job of
+// the array.
 func NewStopCmd() *cobra.Command {
+	var group string
+
 	cmd := &cobra.Command{
 		Use:   "stop <job-uuid>",
 		Short: "Stop a running job",
@@ -30,11 +34,33 @@ Examples:
   rnx job stop f47ac10b-58cc-4372-a567-0e02b2c3d479
 
   # Cancel a job that's waiting to run
-  rnx job stop a1b2c3d4-5678-90ab-cdef-1234567890ab`,
-		Args: cobra.ExactArgs(1),
-		RunE: runStop,
+  rnx job stop a1b2c3d4-5678-90ab-cdef-1234567890ab
+
+  # Stop every running or scheduled job of a group in one call
+  rnx job stop --group=load-test-2024
+
+  # Stop every job of a job array started with 'rnx job run --array'
+  rnx job stop <array-uuid>`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if group == "" {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			if len(args) > 0 {
+				return fmt.Errorf("give either a job UUID or --group, not both")
+			}
+			return nil
+		},
+		ValidArgsFunction: completeIDs(idspb.IdType_ID_TYPE_JOB),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if group != "" {
+				return runStopGroup(group)
+			}
+			return runStop(cmd, args)
+		},
 	}
 
+	cmd.Flags().StringVar(&group, "group", "", "Stop every running or scheduled job of this job group")
+
 	return cmd
 }
 
@@ -50,11 +76,19 @@ func runStop(cmd *cobra.Command, args []string) error {
 	}
 	defer jobClient.Close()
 
+	jobID, err = resolveID(jobClient, jobID, idspb.IdType_ID_TYPE_JOB)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	response, err := jobClient.StopJob(ctx, jobID)
 	if err != nil {
+		if array := findJobArray(ctx, jobClient, jobID, err); array != nil {
+			return runStopArray(context.Background(), jobClient, array)
+		}
 		return fmt.Errorf("couldn't stop the job: %v", err)
 	}
 
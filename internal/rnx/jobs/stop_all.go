@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain/values"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NewStopAllCmd creates the command stopping every job matching a set of filters
+func NewStopAllCmd() *cobra.Command {
+	var (
+		statuses []string
+		labels   []string
+		group    string
+		workflow string
+		array    string
+		dryRun   bool
+		yes      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stop-all",
+		Short: "Stop all running and scheduled jobs matching filters",
+		Long: `Stop all running jobs and cancel all scheduled and queued jobs matching every filter.
+
+Without filters every running, scheduled and queued job is selected. The matching jobs
+are listed and the command asks for confirmation before stopping them; use
+--yes to skip the prompt in scripts, or --dry-run to only list them.
+
+Examples:
+  # Stop all running staging jobs
+  rnx job stop-all --status RUNNING --label env=staging
+
+  # Show which jobs of a workflow would be stopped
+  rnx job stop-all --workflow a1b2c3d4 --dry-run
+
+  # Stop a group without prompting
+  rnx job stop-all --group load-test-2024 --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &jobspb.StopAllJobsRequest{
+				Statuses:     statuses,
+				Group:        group,
+				WorkflowUuid: workflow,
+				ArrayUuid:    array,
+			}
+			for _, label := range labels {
+				key, value, err := values.ParseLabel(label)
+				if err != nil {
+					return fmt.Errorf("invalid --label value: %w", err)
+				}
+				if req.Labels == nil {
+					req.Labels = make(map[string]string)
+				}
+				req.Labels[key] = value
+			}
+			return runStopAll(req, dryRun, yes)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&statuses, "status", nil, "Only stop jobs with this status: RUNNING, SCHEDULED or QUEUED (repeatable)")
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "Only stop jobs with this KEY=VALUE label (repeatable)")
+	cmd.Flags().StringVar(&group, "group", "", "Only stop jobs of this job group")
+	cmd.Flags().StringVar(&workflow, "workflow", "", "Only stop jobs of this workflow (UUID or prefix)")
+	cmd.Flags().StringVar(&array, "array", "", "Only stop jobs of this job array (UUID or prefix)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the matching jobs without stopping them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Stop the matching jobs without asking for confirmation")
+
+	return cmd
+}
+
+func runStopAll(req *jobspb.StopAllJobsRequest, dryRun, yes bool) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	// Resolve the matching jobs first, so the user confirms an exact list and
+	// jobs started in the meantime are left alone
+	req.DryRun = true
+	preview, err := jobClient.StopAllJobs(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("couldn't list matching jobs: %v", err)
+	}
+
+	if dryRun {
+		if common.JSONOutput {
+			return printRegistryJSON(preview)
+		}
+		if len(preview.Stopped) == 0 {
+			fmt.Println("No running or scheduled jobs match the filters")
+			return nil
+		}
+		fmt.Printf("%d job(s) would be stopped:\n", len(preview.Stopped))
+		for _, uuid := range preview.Stopped {
+			fmt.Printf("  %s\n", uuid)
+		}
+		return nil
+	}
+
+	if len(preview.Stopped) == 0 {
+		if common.JSONOutput {
+			return printRegistryJSON(preview)
+		}
+		fmt.Println("No running or scheduled jobs match the filters")
+		return nil
+	}
+
+	if !yes {
+		if !stdinIsTerminal() {
+			return fmt.Errorf("refusing to stop %d job(s) without confirmation: use --yes or --dry-run", len(preview.Stopped))
+		}
+		fmt.Printf("Matching jobs:\n")
+		for _, uuid := range preview.Stopped {
+			fmt.Printf("  %s\n", uuid)
+		}
+		if !confirm(os.Stdin, os.Stdout, fmt.Sprintf("Stop %d job(s)?", len(preview.Stopped))) {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	req.DryRun = false
+	req.JobUuids = preview.Stopped
+	response, err := jobClient.StopAllJobs(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("couldn't stop jobs: %v", err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(response)
+	}
+
+	fmt.Printf("%d stopped, %d already finished, %d failed\n", len(response.Stopped), response.Skipped, len(response.Failed))
+	for _, failure := range response.Failed {
+		fmt.Printf("  %s: %s\n", failure.JobUuid, failure.Error)
+	}
+	if len(response.Failed) > 0 {
+		return fmt.Errorf("%d job(s) could not be stopped", len(response.Failed))
+	}
+	return nil
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes;
+// anything else, including end of input, means no
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprintf(out, "%s [y/N]: ", prompt)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// stdinIsTerminal reports whether a user can answer a prompt
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
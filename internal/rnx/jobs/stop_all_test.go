@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/pkg/constants"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"sure\n", false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirm(strings.NewReader(tt.input), &out, "Stop 2 job(s)?"); got != tt.want {
+			t.Errorf("confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if out.String() != "Stop 2 job(s)? [y/N]: " {
+			t.Errorf("prompt = %q", out.String())
+		}
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	env := map[string]string{"FOO": "bar"}
+	if got := withLabels(env, nil); len(got) != 1 {
+		t.Errorf("withLabels without labels changed env: %v", got)
+	}
+
+	got := withLabels(env, map[string]string{"team": "ml", "env": "staging"})
+	if got[constants.EnvLabels] != "env=staging,team=ml" || got["FOO"] != "bar" {
+		t.Errorf("withLabels = %v", got)
+	}
+	if _, exists := env[constants.EnvLabels]; exists {
+		t.Error("withLabels modified the original environment")
+	}
+}
+
+func TestStopAllCmdFlags(t *testing.T) {
+	cmd := NewStopAllCmd()
+	for _, name := range []string{"status", "label", "group", "workflow", "dry-run", "yes"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("missing --%s flag", name)
+		}
+	}
+	if err := cmd.Args(cmd, []string{"f47ac10b"}); err == nil {
+		t.Error("expected error for positional arguments")
+	}
+}
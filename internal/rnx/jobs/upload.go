@@ -0,0 +1,276 @@
+package jobs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+)
+
+const (
+	// maxUploadWorkers bounds concurrent file reads; more rarely helps on one disk
+	maxUploadWorkers = 8
+
+	// progressMinBytes keeps the progress bar away from small uploads
+	progressMinBytes = 1024 * 1024
+
+	progressInterval = 100 * time.Millisecond
+	progressBarWidth = 30
+)
+
+// uploadOptions controls how upload files are read
+type uploadOptions struct {
+	maxFileSize int64     // Largest file accepted, 0 = no limit
+	progress    io.Writer // Where the progress bar goes, nil = no progress bar
+}
+
+// uploadEntry is a file or directory to upload and where it comes from
+type uploadEntry struct {
+	source string
+	upload *pb.FileUpload
+	size   int64
+}
+
+func processFileUploads(uploads []string, uploadDirs []string, opts uploadOptions) ([]*pb.FileUpload, error) {
+	var entries []uploadEntry
+
+	// Individual files are uploaded by base name
+	for _, uploadPath := range uploads {
+		fileInfo, err := os.Stat(uploadPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot access upload file %s: %w", uploadPath, err)
+		}
+
+		if fileInfo.IsDir() {
+			return nil, fmt.Errorf("use --upload-dir for directories: %s", uploadPath)
+		}
+
+		entries = append(entries, uploadEntry{
+			source: uploadPath,
+			upload: &pb.FileUpload{Path: filepath.Base(uploadPath), Mode: uint32(fileInfo.Mode())},
+			size:   fileInfo.Size(),
+		})
+	}
+
+	// Directories keep their layout below the directory
+	for _, uploadDir := range uploadDirs {
+		dirEntries, err := collectDirectoryUpload(uploadDir)
+		if err != nil {
+			return nil, fmt.Errorf("directory upload failed for %s: %w", uploadDir, err)
+		}
+		entries = append(entries, dirEntries...)
+	}
+
+	// Check sizes before reading anything so every oversized file is reported at once
+	if err := checkUploadSizes(entries, opts.maxFileSize); err != nil {
+		return nil, err
+	}
+
+	if err := readUploads(entries, opts.progress); err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.FileUpload, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.upload
+	}
+	return result, nil
+}
+
+// collectDirectoryUpload lists the files and directories below dir without
+// reading the files
+func collectDirectoryUpload(dir string) ([]uploadEntry, error) {
+	var entries []uploadEntry
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Calculate relative path
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		// Skip the root directory itself
+		if relPath == "." {
+			return nil
+		}
+
+		entry := uploadEntry{
+			source: path,
+			upload: &pb.FileUpload{Path: relPath, Mode: uint32(info.Mode()), IsDirectory: info.IsDir()},
+		}
+		if !info.IsDir() {
+			entry.size = info.Size()
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+
+	return entries, err
+}
+
+// checkUploadSizes fails with the list of files larger than maxFileSize
+func checkUploadSizes(entries []uploadEntry, maxFileSize int64) error {
+	if maxFileSize <= 0 {
+		return nil
+	}
+	var oversized []uploadEntry
+	for _, entry := range entries {
+		if !entry.upload.IsDirectory && entry.size > maxFileSize {
+			oversized = append(oversized, entry)
+		}
+	}
+	if len(oversized) == 0 {
+		return nil
+	}
+
+	sort.Slice(oversized, func(i, j int) bool { return oversized[i].size > oversized[j].size })
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d file(s) larger than --max-upload-size %s:", len(oversized), formatBytes(maxFileSize))
+	for _, entry := range oversized {
+		fmt.Fprintf(&b, "\n  %s (%s)", entry.source, formatBytes(entry.size))
+	}
+	b.WriteString("\nraise --max-upload-size or leave these files out")
+	return fmt.Errorf("%s", b.String())
+}
+
+// readUploads reads the content of every file entry with a pool of workers.
+// The first error stops the remaining reads.
+func readUploads(entries []uploadEntry, progressOut io.Writer) error {
+	var files []int
+	var totalBytes int64
+	for i, entry := range entries {
+		if !entry.upload.IsDirectory {
+			files = append(files, i)
+			totalBytes += entry.size
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	var progress *uploadProgress
+	if progressOut != nil && totalBytes >= progressMinBytes {
+		progress = newUploadProgress(progressOut, len(files), totalBytes)
+		defer progress.finish()
+	}
+
+	workers := min(min(runtime.NumCPU(), maxUploadWorkers), len(files))
+	work := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if failed() {
+					continue
+				}
+				content, err := os.ReadFile(entries[i].source)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("cannot read upload file %s: %w", entries[i].source, err)
+					}
+					mu.Unlock()
+					continue
+				}
+				entries[i].upload.Content = content
+				if progress != nil {
+					progress.add(int64(len(content)))
+				}
+			}
+		}()
+	}
+	for _, i := range files {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	return firstErr
+}
+
+// uploadProgress draws a single-line progress bar of files and bytes read
+type uploadProgress struct {
+	out        io.Writer
+	totalFiles int
+	totalBytes int64
+
+	mu       sync.Mutex
+	files    int
+	bytes    int64
+	lastDraw time.Time
+}
+
+func newUploadProgress(out io.Writer, totalFiles int, totalBytes int64) *uploadProgress {
+	p := &uploadProgress{out: out, totalFiles: totalFiles, totalBytes: totalBytes}
+	p.draw()
+	return p
+}
+
+// add records a file that was read, redrawing at most every progressInterval
+func (p *uploadProgress) add(bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files++
+	p.bytes += bytes
+	if time.Since(p.lastDraw) >= progressInterval {
+		p.draw()
+	}
+}
+
+// finish draws the final state and ends the line
+func (p *uploadProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.draw()
+	fmt.Fprintln(p.out)
+}
+
+// draw writes the bar; callers hold mu except during construction
+func (p *uploadProgress) draw() {
+	p.lastDraw = time.Now()
+	fraction := float64(p.bytes) / float64(p.totalBytes)
+	if fraction > 1 {
+		fraction = 1 // Files can grow between listing and reading
+	}
+	filled := int(fraction * progressBarWidth)
+	fmt.Fprintf(p.out, "\rReading uploads [%s%s] %3.0f%%  %d/%d files  %s/%s",
+		strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), fraction*100,
+		p.files, p.totalFiles, formatBytes(p.bytes), formatBytes(p.totalBytes))
+}
+
+// progressWriter returns stderr when it is a terminal, so scripts and JSON
+// output never see the progress bar
+func progressWriter() io.Writer {
+	if common.JSONOutput {
+		return nil
+	}
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return os.Stderr
+}
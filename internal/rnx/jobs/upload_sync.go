@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/uploadsync"
+	uploadspb "github.com/ehsaniara/joblet/internal/proto/gen/uploads"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// minDeltaSize is the smallest workflow file synced as a delta; smaller
+// files are cheaper to send whole than to sign
+const minDeltaSize = 1024 * 1024
+
+// uploadSyncer is the part of the job client syncing workflow files
+type uploadSyncer interface {
+	GetFileSignature(ctx context.Context, path string) (*uploadspb.FileSignature, error)
+	SyncFile(ctx context.Context, delta *uploadspb.FileDelta) (*uploadspb.SyncFileResponse, error)
+}
+
+// syncedUploads are the workflow files after syncing the large ones to the
+// server's upload cache
+type syncedUploads struct {
+	files []*pb.FileUpload  // Synced files have no content
+	refs  map[string]string // Content hash of each synced file by path
+	sent  int64             // Bytes of the synced files sent as data
+	total int64             // Bytes of the synced files
+}
+
+// syncWorkflowFiles syncs the workflow files of at least minDeltaSize as
+// deltas against the copies the server cached from earlier runs. Files that
+// fail to sync, and all files when the server has upload sync disabled, are
+// kept whole.
+func syncWorkflowFiles(ctx context.Context, syncer uploadSyncer, files []*pb.FileUpload) syncedUploads {
+	res := syncedUploads{files: make([]*pb.FileUpload, 0, len(files)), refs: make(map[string]string)}
+	disabled := false
+	for _, file := range files {
+		if disabled || file.IsDirectory || len(file.Content) < minDeltaSize {
+			res.files = append(res.files, file)
+			continue
+		}
+		ref, sent, err := syncFile(ctx, syncer, file)
+		if err != nil {
+			if status.Code(err) == codes.Unimplemented {
+				disabled = true
+			} else {
+				fmt.Printf("Warning: sending %s whole, sync failed: %v\n", file.Path, err)
+			}
+			res.files = append(res.files, file)
+			continue
+		}
+		res.refs[file.Path] = ref
+		res.sent += sent
+		res.total += int64(len(file.Content))
+		res.files = append(res.files, &pb.FileUpload{Path: file.Path, Mode: file.Mode})
+	}
+	return res
+}
+
+// syncFile sends one file as a delta and returns its content hash and the
+// bytes sent as data
+func syncFile(ctx context.Context, syncer uploadSyncer, file *pb.FileUpload) (string, int64, error) {
+	sum := sha256.Sum256(file.Content)
+	ref := hex.EncodeToString(sum[:])
+
+	sig, err := syncer.GetFileSignature(ctx, file.Path)
+	if err != nil {
+		return "", 0, err
+	}
+	if sig.Basis == ref {
+		return ref, 0, nil // Unchanged since the last run
+	}
+
+	signature := uploadsync.Signature{Basis: sig.Basis, Size: sig.Size, BlockSize: int(sig.BlockSize)}
+	for _, block := range sig.Blocks {
+		signature.Blocks = append(signature.Blocks, uploadsync.Block{Weak: block.Weak, Strong: block.Strong})
+	}
+	delta := &uploadspb.FileDelta{Path: file.Path, Basis: sig.Basis, BlockSize: sig.BlockSize, Sha256: ref}
+	for _, op := range uploadsync.Delta(signature, file.Content) {
+		delta.Ops = append(delta.Ops, &uploadspb.DeltaOp{Block: op.Block, Count: op.Count, Data: op.Data})
+	}
+
+	synced, err := syncer.SyncFile(ctx, delta)
+	if err != nil {
+		return "", 0, err
+	}
+	return synced.Ref, synced.DataBytes, nil
+}
+
+// withUploadRefs names the synced files in the outgoing RunWorkflow headers
+func (u syncedUploads) withUploadRefs(ctx context.Context) (context.Context, error) {
+	if len(u.refs) == 0 {
+		return ctx, nil
+	}
+	refs, err := json.Marshal(u.refs)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, constants.UploadRefsHeader, string(refs)), nil
+}
@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/internal/joblet/uploadsync"
+	uploadspb "github.com/ehsaniara/joblet/internal/proto/gen/uploads"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// cacheSyncer syncs against an upload cache the way the server does
+type cacheSyncer struct {
+	cache *uploadsync.Cache
+	syncs int
+}
+
+func (s *cacheSyncer) GetFileSignature(_ context.Context, path string) (*uploadspb.FileSignature, error) {
+	sig, err := s.cache.Signature("rnx", path)
+	if err != nil {
+		return nil, err
+	}
+	res := &uploadspb.FileSignature{Basis: sig.Basis, Size: sig.Size, BlockSize: int32(sig.BlockSize)}
+	for _, block := range sig.Blocks {
+		res.Blocks = append(res.Blocks, &uploadspb.BlockChecksum{Weak: block.Weak, Strong: block.Strong})
+	}
+	return res, nil
+}
+
+func (s *cacheSyncer) SyncFile(_ context.Context, delta *uploadspb.FileDelta) (*uploadspb.SyncFileResponse, error) {
+	s.syncs++
+	var ops []uploadsync.Op
+	for _, op := range delta.Ops {
+		ops = append(ops, uploadsync.Op{Block: op.Block, Count: op.Count, Data: op.Data})
+	}
+	copied, added, err := s.cache.Apply("rnx", delta.Path, delta.Basis, int(delta.BlockSize), ops, delta.Sha256)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadspb.SyncFileResponse{Ref: delta.Sha256, CopiedBytes: copied, DataBytes: added}, nil
+}
+
+type unimplementedSyncer struct{}
+
+func (unimplementedSyncer) GetFileSignature(context.Context, string) (*uploadspb.FileSignature, error) {
+	return nil, status.Error(codes.Unimplemented, "unknown service")
+}
+
+func (unimplementedSyncer) SyncFile(context.Context, *uploadspb.FileDelta) (*uploadspb.SyncFileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "unknown service")
+}
+
+func TestSyncWorkflowFiles(t *testing.T) {
+	cache, err := uploadsync.NewCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncer := &cacheSyncer{cache: cache}
+	model := make([]byte, 4*minDeltaSize)
+	for i := range model {
+		model[i] = byte(i * 7 % 251)
+	}
+	files := []*pb.FileUpload{
+		{Path: "model.bin", Content: model, Mode: 0644},
+		{Path: "train.py", Content: []byte("print('train')"), Mode: 0755},
+	}
+
+	first := syncWorkflowFiles(context.Background(), syncer, files)
+	if first.sent != int64(len(model)) || first.refs["model.bin"] == "" || first.files[0].Content != nil {
+		t.Fatalf("first sync sent %d bytes, refs %v", first.sent, first.refs)
+	}
+	if _, ok := first.refs["train.py"]; ok || string(first.files[1].Content) != "print('train')" {
+		t.Error("small file was synced instead of sent whole")
+	}
+
+	// A change in the middle only sends the blocks around it
+	changed := append([]byte{}, model...)
+	copy(changed[len(changed)/2:], "retrained")
+	second := syncWorkflowFiles(context.Background(), syncer, []*pb.FileUpload{{Path: "model.bin", Content: changed}})
+	if second.sent == 0 || second.sent > int64(2*uploadsync.MaxBlockSize) || second.total != int64(len(changed)) {
+		t.Errorf("second sync sent %d of %d bytes", second.sent, second.total)
+	}
+	if content, err := cache.Content("rnx", "model.bin", second.refs["model.bin"]); err != nil || !bytes.Equal(content, changed) {
+		t.Errorf("cached copy differs from the file: %v", err)
+	}
+
+	// An unchanged file is not synced again
+	syncs := syncer.syncs
+	third := syncWorkflowFiles(context.Background(), syncer, []*pb.FileUpload{{Path: "model.bin", Content: changed}})
+	if third.sent != 0 || syncer.syncs != syncs || third.refs["model.bin"] != second.refs["model.bin"] {
+		t.Errorf("unchanged file: sent %d bytes, %d syncs", third.sent, syncer.syncs-syncs)
+	}
+
+	ctx, err := third.withUploadRefs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	var refs map[string]string
+	if values := md.Get(constants.UploadRefsHeader); len(values) != 1 || json.Unmarshal([]byte(values[0]), &refs) != nil || refs["model.bin"] != third.refs["model.bin"] {
+		t.Errorf("refs header = %v", md.Get(constants.UploadRefsHeader))
+	}
+}
+
+func TestSyncWorkflowFiles_ServerWithoutSync(t *testing.T) {
+	files := []*pb.FileUpload{{Path: "model.bin", Content: make([]byte, minDeltaSize)}}
+	res := syncWorkflowFiles(context.Background(), unimplementedSyncer{}, files)
+	if len(res.refs) != 0 || len(res.files[0].Content) != minDeltaSize {
+		t.Errorf("files were not sent whole: refs %v", res.refs)
+	}
+	ctx, _ := res.withUploadRefs(context.Background())
+	if _, ok := metadata.FromOutgoingContext(ctx); ok {
+		t.Error("refs header set without synced files")
+	}
+}
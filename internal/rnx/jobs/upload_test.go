@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeUploadTree(t *testing.T, files map[string]int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, bytes.Repeat([]byte(name[:1]), size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestProcessFileUploadsReadsDirectoryConcurrently(t *testing.T) {
+	files := map[string]int{"main.py": 10, "README": 4}
+	for i := 0; i < 40; i++ {
+		files[fmt.Sprintf("pkg/mod%02d.py", i)] = 100 + i
+	}
+	dir := writeUploadTree(t, files)
+
+	uploads, err := processFileUploads([]string{filepath.Join(dir, "main.py")}, []string{dir}, uploadOptions{})
+	if err != nil {
+		t.Fatalf("processFileUploads() error = %v", err)
+	}
+
+	// main.py once by base name, then the tree with the pkg directory
+	if len(uploads) != 1+len(files)+1 {
+		t.Fatalf("got %d uploads, want %d", len(uploads), 1+len(files)+1)
+	}
+	if uploads[0].Path != "main.py" || len(uploads[0].Content) != 10 {
+		t.Errorf("first upload = %s (%d bytes), want main.py", uploads[0].Path, len(uploads[0].Content))
+	}
+	for _, upload := range uploads[1:] {
+		if upload.IsDirectory {
+			if upload.Path != "pkg" || upload.Content != nil {
+				t.Errorf("directory upload = %+v", upload)
+			}
+			continue
+		}
+		if want := files[upload.Path]; len(upload.Content) != want {
+			t.Errorf("%s has %d bytes, want %d", upload.Path, len(upload.Content), want)
+		}
+	}
+}
+
+func TestProcessFileUploadsRejectsOversizedFiles(t *testing.T) {
+	dir := writeUploadTree(t, map[string]int{"small.txt": 10, "data/big.bin": 4096, "data/bigger.bin": 8192})
+
+	_, err := processFileUploads(nil, []string{dir}, uploadOptions{maxFileSize: 1024})
+	if err == nil {
+		t.Fatal("processFileUploads() succeeded, want an error for oversized files")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "2 file(s) larger than --max-upload-size 1.0 KB") {
+		t.Errorf("error = %q, want the number of oversized files", msg)
+	}
+	// Largest first, the small file is not listed
+	bigger := strings.Index(msg, filepath.Join("data", "bigger.bin"))
+	big := strings.Index(msg, filepath.Join("data", "big.bin"))
+	if bigger < 0 || big < 0 || bigger > big || strings.Contains(msg, "small.txt") {
+		t.Errorf("error = %q, want data/bigger.bin then data/big.bin", msg)
+	}
+
+	if _, err := processFileUploads(nil, []string{dir}, uploadOptions{}); err != nil {
+		t.Errorf("processFileUploads() without a limit error = %v", err)
+	}
+}
+
+func TestProcessFileUploadsProgress(t *testing.T) {
+	dir := writeUploadTree(t, map[string]int{"a.bin": progressMinBytes, "b.bin": 1024})
+
+	var out bytes.Buffer
+	if _, err := processFileUploads(nil, []string{dir}, uploadOptions{progress: &out}); err != nil {
+		t.Fatalf("processFileUploads() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "100%  2/2 files  1.0 MB/1.0 MB") || !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("progress = %q, want a completed bar", out.String())
+	}
+
+	// Small uploads draw nothing
+	out.Reset()
+	small := writeUploadTree(t, map[string]int{"a.txt": 10})
+	if _, err := processFileUploads(nil, []string{small}, uploadOptions{progress: &out}); err != nil {
+		t.Fatalf("processFileUploads() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("progress for a small upload = %q, want none", out.String())
+	}
+}
+
+func TestReadStdinUpload(t *testing.T) {
+	dir := writeUploadTree(t, map[string]int{"data.csv": 64})
+	path := filepath.Join(dir, "data.csv")
+
+	upload, err := readStdinUpload(path, 0)
+	if err != nil {
+		t.Fatalf("readStdinUpload() error = %v", err)
+	}
+	if upload.Path != ".joblet-stdin" || len(upload.Content) != 64 || upload.IsDirectory {
+		t.Errorf("upload = %s, %d bytes", upload.Path, len(upload.Content))
+	}
+
+	if _, err := readStdinUpload(path, 64); err != nil {
+		t.Errorf("input at the limit: %v", err)
+	}
+	if _, err := readStdinUpload(path, 63); err == nil || !strings.Contains(err.Error(), "--max-upload-size") {
+		t.Errorf("expected size error, got %v", err)
+	}
+	if _, err := readStdinUpload(filepath.Join(dir, "missing.csv"), 0); err == nil {
+		t.Error("expected error for a missing --stdin-file")
+	}
+}
@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CancelWorkflowJobs cancels a job of a running workflow and, with cascade,
+// every job downstream of it, then prints what became of each job
+func CancelWorkflowJobs(workflowUUID, job string, cascade bool) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	response, err := jobClient.CancelWorkflowJobs(ctx, workflowUUID, job, cascade)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("this server does not support canceling workflow jobs; upgrade the joblet server")
+		}
+		return fmt.Errorf("couldn't cancel workflow jobs: %v", err)
+	}
+
+	if common.JSONOutput {
+		if err := printRegistryJSON(response); err != nil {
+			return err
+		}
+	} else {
+		printWorkflowCancel(os.Stdout, response)
+	}
+	if len(response.Failed) > 0 {
+		return fmt.Errorf("%d job(s) could not be stopped", len(response.Failed))
+	}
+	return nil
+}
+
+// StopWorkflow cancels a whole workflow, then prints what became of each of
+// its jobs
+func StopWorkflow(workflowUUID string) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	response, err := jobClient.CancelWorkflow(ctx, workflowUUID)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("this server does not support stopping workflows; upgrade the joblet server or use 'rnx job stop-all --workflow'")
+		}
+		return fmt.Errorf("couldn't stop workflow: %v", err)
+	}
+
+	if common.JSONOutput {
+		if err := printRegistryJSON(response); err != nil {
+			return err
+		}
+	} else {
+		printWorkflowCancel(os.Stdout, response)
+	}
+	if len(response.Failed) > 0 {
+		return fmt.Errorf("%d job(s) could not be stopped", len(response.Failed))
+	}
+	return nil
+}
+
+// printWorkflowCancel lists the jobs of a canceled sub-tree with what became
+// of them
+func printWorkflowCancel(w io.Writer, response *workflowspb.CancelWorkflowJobsResponse) {
+	fmt.Fprintf(w, "Workflow %s: %d canceled, %d stopped, %d already finished, %d failed\n",
+		response.WorkflowUuid, len(response.Canceled), len(response.Stopped), len(response.Skipped), len(response.Failed))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(outcome *workflowspb.WorkflowJobOutcome, action, detail string) {
+		jobUUID := outcome.JobUuid
+		if jobUUID == "" {
+			jobUUID = "-"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", action, outcome.Name, jobUUID, detail)
+	}
+	for _, outcome := range response.Canceled {
+		row(outcome, "CANCELED", "not started")
+	}
+	for _, outcome := range response.Stopped {
+		row(outcome, "STOPPED", "was "+outcome.Status)
+	}
+	for _, outcome := range response.Skipped {
+		row(outcome, "SKIPPED", "already "+outcome.Status)
+	}
+	for _, outcome := range response.Failed {
+		row(outcome, "FAILED", outcome.Error)
+	}
+	_ = tw.Flush()
+
+	if response.WorkflowStatus != "" {
+		fmt.Fprintf(w, "Workflow status: %s\n", response.WorkflowStatus)
+	}
+}
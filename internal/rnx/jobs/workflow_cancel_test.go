@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
+)
+
+func TestPrintWorkflowCancel(t *testing.T) {
+	var out bytes.Buffer
+	printWorkflowCancel(&out, &workflowspb.CancelWorkflowJobsResponse{
+		WorkflowUuid:   "7b1d2c3e-4f5a-6789-abcd-ef0123456789",
+		Canceled:       []*workflowspb.WorkflowJobOutcome{{Name: "train", Status: "PENDING"}},
+		Stopped:        []*workflowspb.WorkflowJobOutcome{{Name: "fetch", JobUuid: "f47ac10b-58cc-4372-a567-0e02b2c3d479", Status: "RUNNING"}},
+		Skipped:        []*workflowspb.WorkflowJobOutcome{{Name: "lint", JobUuid: "9a8b7c6d-58cc-4372-a567-0e02b2c3d479", Status: "COMPLETED"}},
+		WorkflowStatus: "RUNNING",
+	})
+
+	got := out.String()
+	for _, want := range []string{
+		"Workflow 7b1d2c3e-4f5a-6789-abcd-ef0123456789: 1 canceled, 1 stopped, 1 already finished, 0 failed",
+		"CANCELED  train  -",
+		"STOPPED   fetch  f47ac10b-58cc-4372-a567-0e02b2c3d479  was RUNNING",
+		"already COMPLETED",
+		"Workflow status: RUNNING",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
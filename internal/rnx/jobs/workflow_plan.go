@@ -0,0 +1,211 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	pkgconfig "github.com/ehsaniara/joblet/pkg/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PlanWorkflow validates a workflow against the server and prints its stages,
+// resource totals, critical path and capacity warnings without running it
+func PlanWorkflow(workflowPath string) error {
+	var err error
+	common.NodeConfig, err = pkgconfig.LoadClientConfig(common.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load client config for workflow: %w", err)
+	}
+
+	yamlContent, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read YAML file %s: %w", workflowPath, err)
+	}
+	wf, err := parseWorkflowContent(yamlContent)
+	if err != nil {
+		return err
+	}
+
+	if err := validateWorkflowPreRequisites(wf); err != nil {
+		return fmt.Errorf("workflow validation failed: %w", err)
+	}
+
+	plan, err := workflow.Analyze(wf)
+	if err != nil {
+		return fmt.Errorf("workflow analysis failed: %w", err)
+	}
+
+	capacity, capacityErr := nodeCapacity()
+	var warnings []string
+	if capacityErr == nil {
+		warnings = capacityWarnings(wf, plan, capacity)
+	}
+
+	if common.JSONOutput {
+		return outputWorkflowPlanJSON(plan, warnings, capacityErr)
+	}
+	printWorkflowPlan(workflowPath, plan, warnings, capacityErr)
+	return nil
+}
+
+// nodeCapacity asks the current node for its capacity
+// parseWorkflowContent parses a workflow file, expands its stages into
+// requirements and applies its defaults, as the server does before running it
+func parseWorkflowContent(yamlContent []byte) (types.WorkflowYAML, error) {
+	var wf types.WorkflowYAML
+	if err := yaml.Unmarshal(yamlContent, &wf); err != nil {
+		return wf, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if err := workflow.ExpandStages(&wf); err != nil {
+		return wf, fmt.Errorf("invalid stages: %w", err)
+	}
+	workflow.ApplyDefaults(&wf)
+	return wf, nil
+}
+
+func nodeCapacity() (*capacitypb.NodeCapacity, error) {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return nil, err
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), capacityTimeout)
+	defer cancel()
+	return jobClient.GetNodeCapacity(ctx)
+}
+
+// forJobs returns what the node offers jobs at all: its total less the
+// reserved share
+func forJobs(r *capacitypb.ResourceCapacity) int64 {
+	if r == nil {
+		return 0
+	}
+	return r.Total - r.Reserved
+}
+
+// capacityWarnings flags jobs that can never fit on the node and stages that
+// need more at once than the node has schedulable now
+func capacityWarnings(wf types.WorkflowYAML, plan *workflow.Plan, capacity *capacitypb.NodeCapacity) []string {
+	var warnings []string
+	for _, stage := range plan.Stages {
+		for _, name := range stage.Jobs {
+			res := wf.Jobs[name].Resources
+			if limit := forJobs(capacity.Cpu); capacity.Cpu != nil && int64(res.MaxCPU) > limit {
+				warnings = append(warnings, fmt.Sprintf("job %s asks for %d%% CPU, the node has %d%% for jobs", name, res.MaxCPU, limit))
+			}
+			if limit := forJobs(capacity.Memory); capacity.Memory != nil && int64(res.MaxMemory) > limit {
+				warnings = append(warnings, fmt.Sprintf("job %s asks for %dMB memory, the node has %dMB for jobs", name, res.MaxMemory, limit))
+			}
+			if limit := forJobs(capacity.Gpus); res.GPUCount > 0 && int64(res.GPUCount) > limit {
+				warnings = append(warnings, fmt.Sprintf("job %s asks for %d GPUs, the node has %d for jobs", name, res.GPUCount, limit))
+			}
+		}
+
+		if len(stage.Jobs) < 2 {
+			continue
+		}
+		if capacity.Cpu != nil && int64(stage.MaxCPU) > capacity.Cpu.Schedulable {
+			warnings = append(warnings, fmt.Sprintf("stage %d needs %d%% CPU at once, %d%% is schedulable now", stage.Number, stage.MaxCPU, capacity.Cpu.Schedulable))
+		}
+		if capacity.Memory != nil && int64(stage.MaxMemoryMB) > capacity.Memory.Schedulable {
+			warnings = append(warnings, fmt.Sprintf("stage %d needs %dMB memory at once, %dMB is schedulable now", stage.Number, stage.MaxMemoryMB, capacity.Memory.Schedulable))
+		}
+		if stage.GPUCount > 0 && capacity.Gpus != nil && int64(stage.GPUCount) > capacity.Gpus.Schedulable {
+			warnings = append(warnings, fmt.Sprintf("stage %d needs %d GPUs at once, %d are schedulable now", stage.Number, stage.GPUCount, capacity.Gpus.Schedulable))
+		}
+	}
+	return warnings
+}
+
+// printWorkflowPlan prints the plan as a table
+func printWorkflowPlan(workflowPath string, plan *workflow.Plan, warnings []string, capacityErr error) {
+	jobCount := 0
+	for _, stage := range plan.Stages {
+		jobCount += len(stage.Jobs)
+	}
+	fmt.Printf("\nWorkflow plan: %s (%d jobs, %d stages)\n\n", workflowPath, jobCount, len(plan.Stages))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tJOBS\tCPU\tMEMORY\tGPUS\tUNLIMITED")
+	for _, stage := range plan.Stages {
+		fmt.Fprintf(w, "%d\t%s\t%d%%\t%dMB\t%d\t%d\n",
+			stage.Number, strings.Join(stage.Jobs, ", "), stage.MaxCPU, stage.MaxMemoryMB, stage.GPUCount, stage.UnlimitedJobs)
+	}
+	w.Flush()
+	fmt.Println("\nStage totals assume all jobs of a stage run at once; UNLIMITED jobs have no CPU or memory limit.")
+
+	fmt.Printf("\nCritical path: %s (%s)\n", strings.Join(plan.CriticalPath, " -> "), plan.CriticalPathDuration)
+	if len(plan.Unestimated) > 0 {
+		fmt.Printf("  %d jobs have no estimate and count as 0: %s\n", len(plan.Unestimated), strings.Join(plan.Unestimated, ", "))
+	}
+
+	if capacityErr != nil {
+		fmt.Printf("\nCapacity checks skipped: %v\n", capacityErr)
+		return
+	}
+	if len(warnings) == 0 {
+		fmt.Println("\nEvery job and stage fits the node's capacity.")
+		return
+	}
+	fmt.Println("\nWarnings:")
+	for _, warning := range warnings {
+		fmt.Printf("  ! %s\n", warning)
+	}
+}
+
+// outputWorkflowPlanJSON prints the plan for scripts
+func outputWorkflowPlanJSON(plan *workflow.Plan, warnings []string, capacityErr error) error {
+	type stageJSON struct {
+		Stage         int      `json:"stage"`
+		Jobs          []string `json:"jobs"`
+		MaxCPU        int      `json:"maxCpu"`
+		MaxMemoryMB   int      `json:"maxMemoryMB"`
+		GPUCount      int      `json:"gpuCount"`
+		UnlimitedJobs int      `json:"unlimitedJobs"`
+	}
+	out := struct {
+		Stages               []stageJSON `json:"stages"`
+		CriticalPath         []string    `json:"criticalPath"`
+		CriticalPathDuration string      `json:"criticalPathDuration"`
+		CriticalPathSeconds  float64     `json:"criticalPathSeconds"`
+		UnestimatedJobs      []string    `json:"unestimatedJobs,omitempty"`
+		Warnings             []string    `json:"warnings"`
+		CapacityError        string      `json:"capacityError,omitempty"`
+	}{
+		CriticalPath:         plan.CriticalPath,
+		CriticalPathDuration: plan.CriticalPathDuration.String(),
+		CriticalPathSeconds:  plan.CriticalPathDuration.Seconds(),
+		UnestimatedJobs:      plan.Unestimated,
+		Warnings:             warnings,
+	}
+	for _, stage := range plan.Stages {
+		out.Stages = append(out.Stages, stageJSON{
+			Stage:         stage.Number,
+			Jobs:          stage.Jobs,
+			MaxCPU:        stage.MaxCPU,
+			MaxMemoryMB:   stage.MaxMemoryMB,
+			GPUCount:      stage.GPUCount,
+			UnlimitedJobs: stage.UnlimitedJobs,
+		})
+	}
+	if out.Warnings == nil {
+		out.Warnings = []string{}
+	}
+	if capacityErr != nil {
+		out.CapacityError = capacityErr.Error()
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
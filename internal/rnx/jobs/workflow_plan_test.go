@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+)
+
+func TestCapacityWarnings(t *testing.T) {
+	wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+		"extract": {Resources: types.JobResources{MaxCPU: 100, MaxMemory: 1024}},
+		"train":   {Resources: types.JobResources{MaxCPU: 300, MaxMemory: 2048, GPUCount: 2}, Requires: []map[string]string{{"extract": "COMPLETED"}}},
+		"report":  {Resources: types.JobResources{MaxCPU: 200, MaxMemory: 8192}, Requires: []map[string]string{{"extract": "COMPLETED"}}},
+	}}
+	plan, err := workflow.Analyze(wf)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	capacity := &capacitypb.NodeCapacity{
+		Cpu:    &capacitypb.ResourceCapacity{Total: 400, Reserved: 50, Schedulable: 300},
+		Memory: &capacitypb.ResourceCapacity{Total: 16384, Reserved: 1024, Schedulable: 8192},
+		Gpus:   &capacitypb.ResourceCapacity{Total: 1, Schedulable: 1},
+	}
+
+	got := capacityWarnings(wf, plan, capacity)
+	want := []string{
+		"job train asks for 2 GPUs",
+		"stage 2 needs 500% CPU at once",
+		"stage 2 needs 10240MB memory at once",
+		"stage 2 needs 2 GPUs at once",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("warnings = %q, want %d", got, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("warning %d = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+
+	// A stage with one job is only checked against the job limits
+	single := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+		"big": {Resources: types.JobResources{MaxCPU: 350}},
+	}}
+	plan, _ = workflow.Analyze(single)
+	if got := capacityWarnings(single, plan, capacity); len(got) != 0 {
+		t.Errorf("warnings = %q, want none", got)
+	}
+}
@@ -0,0 +1,280 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	registrypb "github.com/ehsaniara/joblet/internal/proto/gen/registry"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+)
+
+// registryTimeout bounds registry calls; registration uploads the workflow's files
+const registryTimeout = 30 * time.Second
+
+// RegisterWorkflow stores a workflow file and the files its jobs upload on
+// the server under name. Without a name the file name is used.
+func RegisterWorkflow(workflowPath, name, schedule, description string) error {
+	yamlContent, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read YAML file %s: %w", workflowPath, err)
+	}
+
+	workflow, err := parseWorkflowContent(yamlContent)
+	if err != nil {
+		return err
+	}
+	if err := validateWorkflowPreRequisites(workflow); err != nil {
+		return fmt.Errorf("workflow validation failed: %w", err)
+	}
+	fmt.Println("Workflow validation passed")
+
+	uploads, err := extractWorkflowFiles(workflowPath, workflow)
+	if err != nil {
+		return fmt.Errorf("failed to extract workflow files: %w", err)
+	}
+	files := make([]*registrypb.WorkflowFile, 0, len(uploads))
+	for _, upload := range uploads {
+		files = append(files, &registrypb.WorkflowFile{Path: upload.Path, Content: upload.Content, Mode: upload.Mode})
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(workflowPath), filepath.Ext(workflowPath))
+	}
+
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	registered, err := client.RegisterWorkflow(ctx, &registrypb.RegisterWorkflowRequest{
+		Name:        name,
+		YamlContent: string(yamlContent),
+		Files:       files,
+		Schedule:    schedule,
+		Description: description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register workflow: %w", err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(registeredWorkflowToJSON(registered))
+	}
+	fmt.Printf("Registered %s version %d (%d files)\n", registered.Name, registered.LatestVersion, len(files))
+	if registered.NextRun > 0 {
+		fmt.Printf("Next scheduled run: %s\n", time.Unix(registered.NextRun, 0).Format(time.RFC3339))
+	}
+	fmt.Printf("Use 'rnx workflow start %s' to run it\n", registered.Name)
+	return nil
+}
+
+// StartRegisteredWorkflow starts a run of a registered workflow. ref is a
+// name, optionally followed by @version.
+func StartRegisteredWorkflow(ref string) error {
+	name, version, err := parseWorkflowRef(ref)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	resp, err := client.StartRegisteredWorkflow(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("failed to start workflow %s: %w", ref, err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(map[string]interface{}{
+			"name":          name,
+			"version":       resp.Version,
+			"workflow_uuid": resp.WorkflowUuid,
+		})
+	}
+	fmt.Printf("Started %s version %d\n", name, resp.Version)
+	fmt.Printf("Workflow created with UUID: %s\n", resp.WorkflowUuid)
+	fmt.Printf("Use 'rnx workflow status %s' to monitor progress\n", resp.WorkflowUuid)
+	return nil
+}
+
+// ListRegisteredWorkflows lists the workflows registered on the server
+func ListRegisteredWorkflows() error {
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	resp, err := client.ListRegisteredWorkflows(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list registered workflows: %w", err)
+	}
+
+	if common.JSONOutput {
+		out := make([]registeredWorkflowJSON, 0, len(resp.Workflows))
+		for _, w := range resp.Workflows {
+			out = append(out, registeredWorkflowToJSON(w))
+		}
+		return printRegistryJSON(out)
+	}
+
+	if len(resp.Workflows) == 0 {
+		fmt.Println("No registered workflows")
+		return nil
+	}
+	fmt.Printf("%-24s %-8s %-16s %-20s %-20s %s\n", "NAME", "VERSION", "SCHEDULE", "NEXT RUN", "LAST RUN", "DESCRIPTION")
+	for _, w := range resp.Workflows {
+		schedule := w.Schedule
+		if schedule == "" {
+			schedule = "-"
+		}
+		fmt.Printf("%-24s %-8d %-16s %-20s %-20s %s\n", w.Name, w.LatestVersion, schedule,
+			formatUnix(w.NextRun), formatUnix(w.LastRunAt), w.Description)
+	}
+	return nil
+}
+
+// ShowRegisteredWorkflow prints a version of a registered workflow with its YAML
+func ShowRegisteredWorkflow(ref string) error {
+	name, version, err := parseWorkflowRef(ref)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	v, err := client.GetRegisteredWorkflow(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("failed to get workflow %s: %w", ref, err)
+	}
+
+	if common.JSONOutput {
+		return printRegistryJSON(map[string]interface{}{
+			"name":         v.Name,
+			"version":      v.Version,
+			"created_at":   time.Unix(v.CreatedAt, 0).Format(time.RFC3339),
+			"created_by":   v.CreatedBy,
+			"signed":       v.Signed,
+			"files":        v.FilePaths,
+			"yaml_content": v.YamlContent,
+		})
+	}
+	fmt.Printf("Name:      %s\n", v.Name)
+	fmt.Printf("Version:   %d\n", v.Version)
+	fmt.Printf("Created:   %s by %s\n", formatUnix(v.CreatedAt), v.CreatedBy)
+	fmt.Printf("Signed:    %t\n", v.Signed)
+	if len(v.FilePaths) > 0 {
+		fmt.Printf("Files:     %s\n", strings.Join(v.FilePaths, ", "))
+	}
+	fmt.Printf("\n%s", v.YamlContent)
+	if !strings.HasSuffix(v.YamlContent, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
+
+// RemoveRegisteredWorkflow removes a registered workflow with all its versions
+func RemoveRegisteredWorkflow(name string) error {
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	resp, err := client.RemoveRegisteredWorkflow(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove workflow %s: %w", name, err)
+	}
+	fmt.Printf("Removed %s (%d versions)\n", name, resp.VersionsRemoved)
+	return nil
+}
+
+// parseWorkflowRef splits "name@version"; without a version the latest is used
+func parseWorkflowRef(ref string) (string, int32, error) {
+	name, versionText, hasVersion := strings.Cut(ref, "@")
+	if !hasVersion {
+		return ref, 0, nil
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(versionText, "v"))
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("invalid workflow version %q in %s", versionText, ref)
+	}
+	return name, int32(version), nil
+}
+
+type registeredWorkflowJSON struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	LatestVersion int32  `json:"latest_version"`
+	Schedule      string `json:"schedule,omitempty"`
+	NextRun       string `json:"next_run,omitempty"`
+	UpdatedAt     string `json:"updated_at"`
+	UpdatedBy     string `json:"updated_by,omitempty"`
+	LastRunUUID   string `json:"last_run_uuid,omitempty"`
+	LastRunAt     string `json:"last_run_at,omitempty"`
+}
+
+func registeredWorkflowToJSON(w *registrypb.RegisteredWorkflow) registeredWorkflowJSON {
+	out := registeredWorkflowJSON{
+		Name:          w.Name,
+		Description:   w.Description,
+		LatestVersion: w.LatestVersion,
+		Schedule:      w.Schedule,
+		UpdatedAt:     time.Unix(w.UpdatedAt, 0).Format(time.RFC3339),
+		UpdatedBy:     w.UpdatedBy,
+		LastRunUUID:   w.LastRunUuid,
+	}
+	if w.NextRun > 0 {
+		out.NextRun = time.Unix(w.NextRun, 0).Format(time.RFC3339)
+	}
+	if w.LastRunAt > 0 {
+		out.LastRunAt = time.Unix(w.LastRunAt, 0).Format(time.RFC3339)
+	}
+	return out
+}
+
+func printRegistryJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// formatUnix formats Unix seconds in local time, "-" for unset
+func formatUnix(seconds int64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return time.Unix(seconds, 0).Format("2006-01-02 15:04:05")
+}
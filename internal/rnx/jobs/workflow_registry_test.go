@@ -0,0 +1,28 @@
+package jobs
+
+import "testing"
+
+func TestParseWorkflowRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		name    string
+		version int32
+		wantErr bool
+	}{
+		{"nightly-etl", "nightly-etl", 0, false},
+		{"nightly-etl@3", "nightly-etl", 3, false},
+		{"nightly-etl@v3", "nightly-etl", 3, false},
+		{"nightly-etl@latest", "", 0, true},
+		{"nightly-etl@0", "", 0, true},
+	}
+	for _, tt := range tests {
+		name, version, err := parseWorkflowRef(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseWorkflowRef(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if name != tt.name || version != tt.version {
+			t.Errorf("parseWorkflowRef(%q) = %q, %d, want %q, %d", tt.ref, name, version, tt.name, tt.version)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+)
+
+// WorkflowReport has the server render the report of a workflow run and
+// writes it to output, or to stdout when output is empty
+func WorkflowReport(workflowUUID, format, output string, logLines int32) error {
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	report, err := jobClient.GetWorkflowReport(ctx, workflowUUID, format, logLines)
+	if err != nil {
+		return fmt.Errorf("failed to get workflow report: %w", err)
+	}
+
+	if err := writeWorkflowReport(report.Content, output, os.Stdout); err != nil {
+		return err
+	}
+	if output != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %s report of workflow %s (%s) to %s\n", report.Format, report.WorkflowUuid, report.Status, output)
+	}
+	return nil
+}
+
+// writeWorkflowReport writes the report to a file, or to stdout when output
+// is empty. The file is written aside and renamed so CI never reads half a report.
+func writeWorkflowReport(content []byte, output string, stdout io.Writer) error {
+	if output == "" {
+		_, err := stdout.Write(content)
+		return err
+	}
+	tmp := output + ".part"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteWorkflowReport(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := writeWorkflowReport([]byte("<testsuites/>"), "", &stdout); err != nil || stdout.String() != "<testsuites/>" {
+		t.Errorf("stdout = %q, %v", stdout.String(), err)
+	}
+
+	output := filepath.Join(t.TempDir(), "report.xml")
+	if err := writeWorkflowReport([]byte("<testsuites/>"), output, &stdout); err != nil {
+		t.Fatalf("writeWorkflowReport() error = %v", err)
+	}
+	if data, err := os.ReadFile(output); err != nil || string(data) != "<testsuites/>" {
+		t.Errorf("report file = %q, %v", data, err)
+	}
+	if _, err := os.Stat(output + ".part"); !os.IsNotExist(err) {
+		t.Errorf("partial file left behind: %v", err)
+	}
+}
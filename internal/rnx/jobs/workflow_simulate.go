@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+)
+
+// timelineWidth is the width of the timeline bars
+const timelineWidth = 40
+
+// SimulateWorkflow schedules a workflow against synthetic job durations,
+// without a server or executing anything, and prints the resulting timeline
+func SimulateWorkflow(workflowPath, durationsPath string, opts workflow.SimulationOptions) error {
+	yamlContent, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read YAML file %s: %w", workflowPath, err)
+	}
+	wf, err := parseWorkflowContent(yamlContent)
+	if err != nil {
+		return err
+	}
+
+	runs := map[string]workflow.SimulatedRun{}
+	if durationsPath != "" {
+		data, err := os.ReadFile(durationsPath)
+		if err != nil {
+			return fmt.Errorf("failed to read durations file: %w", err)
+		}
+		if runs, err = workflow.ParseSimulatedRuns(data); err != nil {
+			return fmt.Errorf("invalid durations file %s: %w", durationsPath, err)
+		}
+	}
+
+	sim, err := workflow.Simulate(wf, runs, opts)
+	if err != nil {
+		return fmt.Errorf("workflow simulation failed: %w", err)
+	}
+
+	if common.JSONOutput {
+		return outputWorkflowSimulationJSON(os.Stdout, sim)
+	}
+	printWorkflowSimulation(os.Stdout, workflowPath, sim, opts)
+	return nil
+}
+
+// printWorkflowSimulation prints the simulated timeline with a bar per job:
+// '-' while it waits for a running slot, '#' while it runs
+func printWorkflowSimulation(w io.Writer, workflowPath string, sim *workflow.Simulation, opts workflow.SimulationOptions) {
+	parallel := "unlimited"
+	if opts.MaxParallel > 0 {
+		parallel = fmt.Sprint(opts.MaxParallel)
+	}
+	fmt.Fprintf(w, "\nWorkflow simulation: %s (max parallel %s, %d infra retries)\n\n", workflowPath, parallel, opts.InfraRetries)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "JOB\tSTATUS\tSTART\tEND\tWAIT\tATTEMPTS\tTIMELINE")
+	for _, job := range sim.Jobs {
+		if !job.Ran {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t0\t\n", job.Name, job.Status)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t|%s|\n", job.Name, job.Status,
+			simulatedTime(job.Start), simulatedTime(job.End), simulatedTime(job.Wait), job.Attempts, timelineBar(job, sim.Makespan))
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nWorkflow status: %s\n", sim.Status)
+	fmt.Fprintf(w, "Makespan: %s, at most %d jobs running at once, %s spent waiting for a slot\n",
+		simulatedTime(sim.Makespan), sim.MaxRunning, simulatedTime(sim.TotalWait))
+	if len(sim.Unsimulated) > 0 {
+		fmt.Fprintf(w, "  %d jobs have no duration or estimate and count as 0: %s\n", len(sim.Unsimulated), strings.Join(sim.Unsimulated, ", "))
+	}
+}
+
+// timelineBar draws a job's wait and run over the makespan
+func timelineBar(job workflow.SimulatedJob, makespan time.Duration) string {
+	column := func(d time.Duration) int {
+		if makespan <= 0 {
+			return 0
+		}
+		return int(int64(d) * timelineWidth / int64(makespan))
+	}
+	waitFrom, start, end := column(job.Start-job.Wait), column(job.Start), column(job.End)
+	if end == start {
+		end = min(start+1, timelineWidth)
+		start = end - 1
+	}
+	bar := []byte(strings.Repeat(" ", timelineWidth))
+	for i := waitFrom; i < start; i++ {
+		bar[i] = '-'
+	}
+	for i := start; i < end; i++ {
+		bar[i] = '#'
+	}
+	return string(bar)
+}
+
+// simulatedTime formats an offset from the workflow's submission
+func simulatedTime(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// outputWorkflowSimulationJSON prints the simulation for scripts, times in
+// seconds from the workflow's submission
+func outputWorkflowSimulationJSON(w io.Writer, sim *workflow.Simulation) error {
+	type jobJSON struct {
+		Name         string  `json:"name"`
+		Status       string  `json:"status"`
+		Ran          bool    `json:"ran"`
+		ReadySeconds float64 `json:"readySeconds"`
+		StartSeconds float64 `json:"startSeconds"`
+		EndSeconds   float64 `json:"endSeconds"`
+		WaitSeconds  float64 `json:"waitSeconds"`
+		Attempts     int     `json:"attempts"`
+	}
+	out := struct {
+		Status           string    `json:"status"`
+		MakespanSeconds  float64   `json:"makespanSeconds"`
+		MaxRunning       int       `json:"maxRunning"`
+		TotalWaitSeconds float64   `json:"totalWaitSeconds"`
+		UnsimulatedJobs  []string  `json:"unsimulatedJobs,omitempty"`
+		Jobs             []jobJSON `json:"jobs"`
+	}{
+		Status:           string(sim.Status),
+		MakespanSeconds:  sim.Makespan.Seconds(),
+		MaxRunning:       sim.MaxRunning,
+		TotalWaitSeconds: sim.TotalWait.Seconds(),
+		UnsimulatedJobs:  sim.Unsimulated,
+	}
+	for _, job := range sim.Jobs {
+		out.Jobs = append(out.Jobs, jobJSON{
+			Name:         job.Name,
+			Status:       string(job.Status),
+			Ran:          job.Ran,
+			ReadySeconds: job.Ready.Seconds(),
+			StartSeconds: job.Start.Seconds(),
+			EndSeconds:   job.End.Seconds(),
+			WaitSeconds:  job.Wait.Seconds(),
+			Attempts:     job.Attempts,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
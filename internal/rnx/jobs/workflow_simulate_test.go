@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+)
+
+func TestPrintWorkflowSimulation(t *testing.T) {
+	sim := &workflow.Simulation{
+		Jobs: []workflow.SimulatedJob{
+			{Name: "fetch", Status: domain.StatusCompleted, End: 20 * time.Minute, Attempts: 1, Ran: true},
+			{Name: "train", Status: domain.StatusCompleted, Ready: 20 * time.Minute, Start: 30 * time.Minute, End: 40 * time.Minute, Wait: 10 * time.Minute, Attempts: 2, Ran: true},
+			{Name: "alert", Status: domain.StatusCanceled},
+		},
+		Status:      workflow.WorkflowCanceled,
+		Makespan:    40 * time.Minute,
+		MaxRunning:  1,
+		TotalWait:   10 * time.Minute,
+		Unsimulated: []string{"alert"},
+	}
+
+	var out bytes.Buffer
+	printWorkflowSimulation(&out, "pipeline.yaml", sim, workflow.SimulationOptions{MaxParallel: 1, InfraRetries: 2})
+	got := out.String()
+	for _, want := range []string{
+		"Workflow simulation: pipeline.yaml (max parallel 1, 2 infra retries)",
+		"fetch  COMPLETED  0s     20m0s  0s     1         |####################                    |",
+		"train  COMPLETED  30m0s  40m0s  10m0s  2         |                    ----------##########|",
+		"alert  CANCELED   -      -      -      0",
+		"Makespan: 40m0s, at most 1 jobs running at once, 10m0s spent waiting for a slot",
+		"1 jobs have no duration or estimate and count as 0: alert",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestTimelineBarShowsInstantJobs(t *testing.T) {
+	bar := timelineBar(workflow.SimulatedJob{Start: time.Hour, End: time.Hour}, time.Hour)
+	if len(bar) != timelineWidth || !strings.HasSuffix(bar, " #") {
+		t.Errorf("bar = %q, want a single mark at the end", bar)
+	}
+}
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ValidateWorkflow runs the server's checks on a workflow file without
+// running it and prints the result of every check as it arrives
+func ValidateWorkflow(workflowPath string) error {
+	yamlContent, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read YAML file %s: %w", workflowPath, err)
+	}
+	// A workflow that does not parse is reported by the server's syntax
+	// check, with no files to check the uploads against
+	var files []string
+	if wf, err := parseWorkflowContent(yamlContent); err == nil {
+		files = workflowUploadFiles(workflowPath, wf)
+	}
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer jobClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	stream, err := jobClient.ValidateWorkflow(ctx, string(yamlContent), files)
+	if err != nil {
+		return fmt.Errorf("couldn't validate workflow: %v", err)
+	}
+	if !common.JSONOutput {
+		fmt.Printf("Validating %s\n", workflowPath)
+	}
+	var results []*workflowspb.WorkflowCheckResult
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if status.Code(err) == codes.Unimplemented {
+				return fmt.Errorf("this server does not support validating workflows; upgrade the joblet server")
+			}
+			return fmt.Errorf("couldn't validate workflow: %v", err)
+		}
+		results = append(results, result)
+		if !common.JSONOutput {
+			printWorkflowCheck(os.Stdout, result)
+		}
+	}
+
+	problems := 0
+	for _, result := range results {
+		problems += len(result.Problems)
+	}
+	if common.JSONOutput {
+		if results == nil {
+			results = []*workflowspb.WorkflowCheckResult{}
+		}
+		if err := printRegistryJSON(map[string]interface{}{"valid": problems == 0, "checks": results}); err != nil {
+			return err
+		}
+	} else {
+		printWorkflowCheckSummary(os.Stdout, results)
+	}
+	if problems > 0 {
+		return fmt.Errorf("workflow has %d problem(s)", problems)
+	}
+	return nil
+}
+
+// workflowUploadFiles lists the workflow files the jobs' uploads would send
+// that exist next to the YAML, leaving the missing ones for the server's
+// uploads check to report
+func workflowUploadFiles(yamlPath string, wf types.WorkflowYAML) []string {
+	yamlDir := filepath.Dir(yamlPath)
+	seen := make(map[string]bool)
+	var files []string
+	for _, job := range wf.Jobs {
+		sources, err := workflow.UploadSources(job.Uploads, listWorkflowDir(yamlDir))
+		if err != nil {
+			continue
+		}
+		for _, source := range sources {
+			if seen[source] {
+				continue
+			}
+			seen[source] = true
+			if info, err := os.Stat(filepath.Join(yamlDir, filepath.FromSlash(source))); err == nil && !info.IsDir() {
+				files = append(files, source)
+			}
+		}
+	}
+	return files
+}
+
+// printWorkflowCheck shows a check of the checklist with its problems
+func printWorkflowCheck(w io.Writer, result *workflowspb.WorkflowCheckResult) {
+	if len(result.Problems) == 0 {
+		fmt.Fprintf(w, "  ✓ %s\n", result.Check)
+		return
+	}
+	fmt.Fprintf(w, "  ✗ %s\n", result.Check)
+	for _, p := range result.Problems {
+		if p.Job != "" {
+			fmt.Fprintf(w, "      %s: %s [%s]\n", p.Job, p.Message, p.Code)
+		} else {
+			fmt.Fprintf(w, "      %s [%s]\n", p.Message, p.Code)
+		}
+	}
+}
+
+// printWorkflowCheckSummary counts the failed checks and their problems
+func printWorkflowCheckSummary(w io.Writer, results []*workflowspb.WorkflowCheckResult) {
+	failed, problems := 0, 0
+	for _, result := range results {
+		if len(result.Problems) > 0 {
+			failed++
+			problems += len(result.Problems)
+		}
+	}
+	if failed == 0 {
+		fmt.Fprintf(w, "Workflow is valid: %d checks passed\n", len(results))
+		return
+	}
+	fmt.Fprintf(w, "%d of %d checks failed with %d problem(s)\n", failed, len(results), problems)
+}
@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
+)
+
+func TestWorkflowUploadFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.py", "src/app.py", "src/pkg/util.py"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("print()"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wf := types.WorkflowYAML{Jobs: map[string]types.JobSpec{
+		"a": {Uploads: &types.JobUploads{Files: []string{"main.py", "missing.py"}}},
+		"b": {Uploads: &types.JobUploads{Files: []string{"main.py", "src/**/*.py"}}},
+	}}
+
+	files := workflowUploadFiles(filepath.Join(dir, "workflow.yaml"), wf)
+	sort.Strings(files)
+	if want := []string{"main.py", "src/app.py", "src/pkg/util.py"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("workflowUploadFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestPrintWorkflowChecks(t *testing.T) {
+	results := []*workflowspb.WorkflowCheckResult{
+		{Check: "dependencies"},
+		{Check: "volumes", Problems: []*workflowspb.WorkflowProblem{
+			{Code: "missing-volume", Job: "train", Message: "volume 'models' does not exist"},
+		}},
+		{Check: "labels", Problems: []*workflowspb.WorkflowProblem{
+			{Code: "invalid-group", Message: "invalid group name"},
+		}},
+	}
+
+	var out bytes.Buffer
+	for _, result := range results {
+		printWorkflowCheck(&out, result)
+	}
+	printWorkflowCheckSummary(&out, results)
+	for _, want := range []string{
+		"  ✓ dependencies\n",
+		"  ✗ volumes\n      train: volume 'models' does not exist [missing-volume]\n",
+		"      invalid group name [invalid-group]\n",
+		"2 of 3 checks failed with 2 problem(s)\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printWorkflowCheckSummary(&out, results[:1])
+	if out.String() != "Workflow is valid: 1 checks passed\n" {
+		t.Errorf("summary of a valid workflow = %q", out.String())
+	}
+}
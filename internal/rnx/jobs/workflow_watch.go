@@ -0,0 +1,261 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+// workflowBarWidth is the number of cells of the watch view's progress bar
+const workflowBarWidth = 30
+
+// finishedJobStatuses are the job statuses a workflow job does not leave
+var finishedJobStatuses = map[string]bool{
+	"COMPLETED": true,
+	"FAILED":    true,
+	"STOPPED":   true,
+	"CANCELED":  true,
+	"EXPIRED":   true,
+}
+
+// finishedWorkflowStatuses end 'rnx workflow status --watch'
+var finishedWorkflowStatuses = map[string]bool{
+	"COMPLETED": true,
+	"FAILED":    true,
+	"CANCELED":  true,
+	"STOPPED":   true,
+}
+
+// WatchWorkflowStatus redraws a progress view of the workflow every interval
+// until the workflow finishes. The ETA comes from the durations of earlier
+// completed runs of the same job names, kept by the server and persist.
+func WatchWorkflowStatus(workflowID string, interval time.Duration) error {
+	if common.JSONOutput {
+		return fmt.Errorf("--watch cannot be combined with --json")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("couldn't connect to joblet server: %w", err)
+	}
+	defer client.Close()
+	workflowClient := pb.NewJobServiceClient(client.GetConn())
+
+	var history map[string]time.Duration
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		res, err := workflowClient.GetWorkflowStatus(ctx, &pb.GetWorkflowStatusRequest{WorkflowUuid: workflowID})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("couldn't get workflow status: %w", err)
+		}
+
+		// Past durations do not change while we watch
+		if history == nil {
+			history = jobDurationHistory(client.GetJobDurationHistory, res.Jobs)
+		}
+
+		fmt.Print("\033[2J\033[H")
+		renderWorkflowProgress(os.Stdout, res, history, time.Now())
+		if finishedWorkflowStatuses[res.Workflow.Status] {
+			return nil
+		}
+		fmt.Printf("\nRefreshing every %s. Press Ctrl+C to stop.\n", interval)
+		time.Sleep(interval)
+	}
+}
+
+// jobDurationHistory returns the mean duration of earlier completed runs by
+// job name. Without history the view shows no ETA rather than failing.
+func jobDurationHistory(get func(context.Context, []string, int32) (*jobspb.JobDurationHistory, error), jobs []*pb.WorkflowJob) map[string]time.Duration {
+	names := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		names = append(names, job.JobName)
+	}
+
+	history := make(map[string]time.Duration)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := get(ctx, names, 0)
+	if err != nil {
+		return history
+	}
+	for _, stat := range res.Stats {
+		history[stat.Name] = time.Duration(stat.MeanSeconds * float64(time.Second))
+	}
+	return history
+}
+
+// workflowStages groups jobs by the length of their longest chain of
+// dependencies, each stage ordered by name
+func workflowStages(jobs []*pb.WorkflowJob) [][]*pb.WorkflowJob {
+	byName := make(map[string]*pb.WorkflowJob, len(jobs))
+	for _, job := range jobs {
+		byName[job.JobName] = job
+	}
+
+	depth := make(map[string]int, len(jobs))
+	var visit func(name string) int
+	visit = func(name string) int {
+		if d, done := depth[name]; done {
+			return d
+		}
+		depth[name] = 0 // Guards against cycles, which validation rejects
+		d := 0
+		for _, dep := range byName[name].Dependencies {
+			if _, exists := byName[dep]; exists {
+				d = max(d, visit(dep)+1)
+			}
+		}
+		depth[name] = d
+		return d
+	}
+
+	var stages [][]*pb.WorkflowJob
+	for _, job := range jobs {
+		d := visit(job.JobName)
+		for len(stages) <= d {
+			stages = append(stages, nil)
+		}
+		stages[d] = append(stages[d], job)
+	}
+	for _, stage := range stages {
+		sort.Slice(stage, func(i, j int) bool { return stage[i].JobName < stage[j].JobName })
+	}
+	return stages
+}
+
+// workflowETA estimates the time until every job has finished: each job
+// still to finish takes its historical mean, less what a running job has
+// already run, after its dependencies. known is false when a job still to
+// finish has no history, in which case the ETA counts it as zero.
+func workflowETA(jobs []*pb.WorkflowJob, history map[string]time.Duration, now time.Time) (eta time.Duration, known bool) {
+	byName := make(map[string]*pb.WorkflowJob, len(jobs))
+	for _, job := range jobs {
+		byName[job.JobName] = job
+	}
+
+	known = true
+	finish := make(map[string]time.Duration, len(jobs))
+	var visit func(name string) time.Duration
+	visit = func(name string) time.Duration {
+		if f, done := finish[name]; done {
+			return f
+		}
+		finish[name] = 0 // Guards against cycles, which validation rejects
+		job := byName[name]
+		if finishedJobStatuses[job.Status] {
+			return 0
+		}
+
+		start := time.Duration(0)
+		for _, dep := range job.Dependencies {
+			if _, exists := byName[dep]; exists {
+				start = max(start, visit(dep))
+			}
+		}
+		remaining, ok := history[name]
+		if !ok {
+			known = false
+		}
+		if job.Status == "RUNNING" && job.StartTime != nil {
+			remaining = max(0, remaining-now.Sub(time.Unix(job.StartTime.Seconds, int64(job.StartTime.Nanos))))
+		}
+		finish[name] = start + remaining
+		return finish[name]
+	}
+	for _, job := range jobs {
+		eta = max(eta, visit(job.JobName))
+	}
+	return eta, known
+}
+
+// renderWorkflowProgress writes the watch view: a summary with progress bar
+// and ETA, then the jobs stage by stage with their durations
+func renderWorkflowProgress(w io.Writer, res *pb.GetWorkflowStatusResponse, history map[string]time.Duration, now time.Time) {
+	workflow := res.Workflow
+	statusColor, resetColor := getStatusColor(workflow.Status)
+	fmt.Fprintf(w, "Workflow %s  %s%s%s\n", workflow.Uuid, statusColor, workflow.Status, resetColor)
+
+	finished := 0
+	for _, job := range res.Jobs {
+		if finishedJobStatuses[job.Status] {
+			finished++
+		}
+	}
+	filled := 0
+	if len(res.Jobs) > 0 {
+		filled = finished * workflowBarWidth / len(res.Jobs)
+	}
+	fmt.Fprintf(w, "[%s%s] %d/%d jobs finished", strings.Repeat("#", filled), strings.Repeat("-", workflowBarWidth-filled), finished, len(res.Jobs))
+	if workflow.FailedJobs > 0 {
+		fmt.Fprintf(w, " (%d failed)", workflow.FailedJobs)
+	}
+	fmt.Fprintln(w)
+
+	if workflow.StartedAt != nil && workflow.StartedAt.Seconds > 0 {
+		fmt.Fprintf(w, "Elapsed: %s", formatDuration(now.Sub(time.Unix(workflow.StartedAt.Seconds, 0))))
+		if !finishedWorkflowStatuses[workflow.Status] {
+			if eta, known := workflowETA(res.Jobs, history, now); eta > 0 || known {
+				prefix := "~"
+				if !known {
+					prefix = "at least "
+				}
+				fmt.Fprintf(w, "   ETA: %s%s (%s)", prefix, formatDuration(eta), now.Add(eta).Format("15:04"))
+			} else {
+				fmt.Fprintf(w, "   ETA: unknown, no earlier runs")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	for i, stage := range workflowStages(res.Jobs) {
+		fmt.Fprintf(w, "\nStage %d\n", i+1)
+		for j, job := range stage {
+			branch := "├─"
+			if j == len(stage)-1 {
+				branch = "└─"
+			}
+			jobColor, _ := getStatusColor(job.Status)
+			fmt.Fprintf(w, "  %s %-24s %s%-12s%s %s", branch, job.JobName, jobColor, job.Status, resetColor, jobTiming(job, history, now))
+			if len(job.Dependencies) > 0 {
+				fmt.Fprintf(w, "  needs %s", strings.Join(job.Dependencies, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// jobTiming is how long a job ran or has been running, against its
+// historical mean when there is one
+func jobTiming(job *pb.WorkflowJob, history map[string]time.Duration, now time.Time) string {
+	mean, hasHistory := history[job.JobName]
+	if job.StartTime == nil || job.StartTime.Seconds == 0 {
+		if hasHistory {
+			return fmt.Sprintf("%-10s", "~"+formatDuration(mean))
+		}
+		return fmt.Sprintf("%-10s", "-")
+	}
+
+	start := time.Unix(job.StartTime.Seconds, int64(job.StartTime.Nanos))
+	if job.EndTime != nil && job.EndTime.Seconds > 0 {
+		return fmt.Sprintf("%-10s", formatDuration(time.Unix(job.EndTime.Seconds, int64(job.EndTime.Nanos)).Sub(start)))
+	}
+	running := formatDuration(now.Sub(start))
+	if hasHistory {
+		running += " of ~" + formatDuration(mean)
+	}
+	return fmt.Sprintf("%-10s", running)
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+)
+
+func watchTestJobs(now time.Time) []*pb.WorkflowJob {
+	started := &pb.Timestamp{Seconds: now.Add(-5 * time.Minute).Unix()}
+	return []*pb.WorkflowJob{
+		{JobName: "report", Status: "PENDING", Dependencies: []string{"train", "lint"}},
+		{JobName: "train", Status: "RUNNING", Dependencies: []string{"extract"}, StartTime: started},
+		{JobName: "lint", Status: "COMPLETED", Dependencies: []string{"extract"}},
+		{JobName: "extract", Status: "COMPLETED"},
+	}
+}
+
+func TestWorkflowStages(t *testing.T) {
+	stages := workflowStages(watchTestJobs(time.Now()))
+	var got []string
+	for _, stage := range stages {
+		var names []string
+		for _, job := range stage {
+			names = append(names, job.JobName)
+		}
+		got = append(got, strings.Join(names, ","))
+	}
+	if want := "extract|lint,train|report"; strings.Join(got, "|") != want {
+		t.Errorf("stages = %s, want %s", strings.Join(got, "|"), want)
+	}
+}
+
+func TestWorkflowETA(t *testing.T) {
+	now := time.Date(2025, 8, 3, 10, 0, 0, 0, time.UTC)
+	jobs := watchTestJobs(now)
+	history := map[string]time.Duration{"train": 20 * time.Minute, "report": 2 * time.Minute}
+
+	// train has 15m left, report runs 2m after it
+	eta, known := workflowETA(jobs, history, now)
+	if eta != 17*time.Minute || !known {
+		t.Errorf("ETA = %s, %v, want 17m0s, true", eta, known)
+	}
+
+	// A job running past its mean counts as about to finish
+	eta, _ = workflowETA(jobs, map[string]time.Duration{"train": time.Minute, "report": 2 * time.Minute}, now)
+	if eta != 2*time.Minute {
+		t.Errorf("ETA = %s, want 2m0s", eta)
+	}
+
+	// Without history for report the ETA is a lower bound
+	eta, known = workflowETA(jobs, map[string]time.Duration{"train": 20 * time.Minute}, now)
+	if eta != 15*time.Minute || known {
+		t.Errorf("ETA = %s, %v, want 15m0s, false", eta, known)
+	}
+}
+
+func TestRenderWorkflowProgress(t *testing.T) {
+	now := time.Date(2025, 8, 3, 10, 0, 0, 0, time.UTC)
+	res := &pb.GetWorkflowStatusResponse{
+		Workflow: &pb.WorkflowInfo{Uuid: "wf-1", Status: "RUNNING", StartedAt: &pb.Timestamp{Seconds: now.Add(-10 * time.Minute).Unix()}},
+		Jobs:     watchTestJobs(now),
+	}
+	var out bytes.Buffer
+	renderWorkflowProgress(&out, res, map[string]time.Duration{"train": 20 * time.Minute, "report": 2 * time.Minute}, now)
+
+	for _, want := range []string{
+		"2/4 jobs finished",
+		"ETA: ~17.0m (10:17)",
+		"Stage 3",
+		"5.0m of ~20.0m",
+		"needs train, lint",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+}
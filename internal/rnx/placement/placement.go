@@ -0,0 +1,81 @@
+// Package placement picks a node for a job from the capacity reported by
+// each configured node. The choice only depends on the reported numbers and
+// node names, so the same inputs always place a job on the same node.
+package placement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+)
+
+// AutoNode is the --node value that asks rnx to choose the node.
+const AutoNode = "auto"
+
+// Request is what a job needs from a node. Zero means the job sets no limit
+// for that resource and fits anywhere.
+type Request struct {
+	CPUPercent int64
+	MemoryMB   int64
+	GPUs       int64
+}
+
+// Candidate is a configured node with its capacity, or the error returned
+// when asking it.
+type Candidate struct {
+	Node     string
+	Capacity *capacitypb.NodeCapacity
+	Err      error
+}
+
+// Fits reports why a node cannot take the request, or nil if it can.
+func Fits(c *capacitypb.NodeCapacity, req Request) error {
+	if req.CPUPercent > c.GetCpu().GetSchedulable() {
+		return fmt.Errorf("needs %d%% CPU, %d%% schedulable", req.CPUPercent, c.GetCpu().GetSchedulable())
+	}
+	if req.MemoryMB > c.GetMemory().GetSchedulable() {
+		return fmt.Errorf("needs %dMB memory, %dMB schedulable", req.MemoryMB, c.GetMemory().GetSchedulable())
+	}
+	if req.GPUs > c.GetGpus().GetSchedulable() {
+		return fmt.Errorf("needs %d GPUs, %d schedulable", req.GPUs, c.GetGpus().GetSchedulable())
+	}
+	return nil
+}
+
+// Choose returns the node to run req on. Among the nodes that fit, it takes
+// the one with the most memory left after placing the job, then the most CPU
+// left, then the first name in sort order.
+func Choose(candidates []Candidate, req Request) (string, error) {
+	var fitting []Candidate
+	var rejected []string
+	for _, c := range candidates {
+		if c.Err != nil {
+			rejected = append(rejected, fmt.Sprintf("%s: %v", c.Node, c.Err))
+			continue
+		}
+		if err := Fits(c.Capacity, req); err != nil {
+			rejected = append(rejected, fmt.Sprintf("%s: %v", c.Node, err))
+			continue
+		}
+		fitting = append(fitting, c)
+	}
+
+	if len(fitting) == 0 {
+		sort.Strings(rejected)
+		return "", fmt.Errorf("no node has capacity for the job:\n  %s", strings.Join(rejected, "\n  "))
+	}
+
+	sort.Slice(fitting, func(i, j int) bool {
+		a, b := fitting[i].Capacity, fitting[j].Capacity
+		if am, bm := a.GetMemory().GetSchedulable(), b.GetMemory().GetSchedulable(); am != bm {
+			return am > bm
+		}
+		if ac, bc := a.GetCpu().GetSchedulable(), b.GetCpu().GetSchedulable(); ac != bc {
+			return ac > bc
+		}
+		return fitting[i].Node < fitting[j].Node
+	})
+	return fitting[0].Node, nil
+}
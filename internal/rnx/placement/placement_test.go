@@ -0,0 +1,58 @@
+package placement
+
+import (
+	"errors"
+	"testing"
+
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func node(name string, cpu, memory, gpus int64) Candidate {
+	return Candidate{
+		Node: name,
+		Capacity: &capacitypb.NodeCapacity{
+			Cpu:    &capacitypb.ResourceCapacity{Schedulable: cpu},
+			Memory: &capacitypb.ResourceCapacity{Schedulable: memory},
+			Gpus:   &capacitypb.ResourceCapacity{Schedulable: gpus},
+		},
+	}
+}
+
+func TestChoosePrefersMostMemoryThenCPUThenName(t *testing.T) {
+	candidates := []Candidate{
+		node("c", 400, 8192, 0),
+		node("b", 800, 8192, 0),
+		node("a", 800, 8192, 0),
+		node("d", 1600, 4096, 0),
+	}
+
+	chosen, err := Choose(candidates, Request{MemoryMB: 1024})
+	require.NoError(t, err)
+	assert.Equal(t, "a", chosen)
+
+	// Order of the input must not matter
+	reversed := []Candidate{candidates[3], candidates[2], candidates[1], candidates[0]}
+	chosen, err = Choose(reversed, Request{MemoryMB: 1024})
+	require.NoError(t, err)
+	assert.Equal(t, "a", chosen)
+}
+
+func TestChooseSkipsNodesThatDoNotFit(t *testing.T) {
+	candidates := []Candidate{
+		node("big", 1600, 65536, 0),
+		node("gpu", 400, 4096, 2),
+		{Node: "down", Err: errors.New("connection refused")},
+	}
+
+	chosen, err := Choose(candidates, Request{GPUs: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "gpu", chosen)
+
+	_, err = Choose(candidates, Request{GPUs: 4})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "big: needs 4 GPUs, 0 schedulable")
+	assert.Contains(t, err.Error(), "down: connection refused")
+}
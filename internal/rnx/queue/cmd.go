@@ -0,0 +1,271 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// connectTimeout bounds how long a flush waits for a node before leaving its
+// entries queued.
+const connectTimeout = 5 * time.Second
+
+// NewQueueCmd creates the queue command
+func NewQueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Manage job submissions queued while offline",
+		Long: `Manage jobs that 'rnx job run --queue-offline' stored locally because the
+server was unreachable.
+
+Queued requests are kept in ~/.rnx/queue (override with RNX_QUEUE_DIR) with
+owner-only permissions, since they may contain secret environment variables.
+
+Examples:
+  rnx queue list                      # Show queued submissions
+  rnx queue flush                     # Submit everything that can reach its node
+  rnx queue flush --watch             # Keep retrying in the background until empty
+  rnx queue remove 20250718-200248-a1b2c3`,
+	}
+
+	cmd.AddCommand(newQueueListCmd())
+	cmd.AddCommand(newQueueFlushCmd())
+	cmd.AddCommand(newQueueRemoveCmd())
+
+	return cmd
+}
+
+func newQueueListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List queued job submissions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := defaultStore()
+			if err != nil {
+				return err
+			}
+			entries, err := store.List()
+			if err != nil {
+				return err
+			}
+
+			if common.JSONOutput {
+				return outputEntriesJSON(entries)
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No queued jobs")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNODE\tQUEUED\tATTEMPTS\tCOMMAND\tLAST ERROR")
+			for _, entry := range entries {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+					entry.ID, entry.Node, entry.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+					entry.Attempts, entry.Summary(), entry.LastError)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newQueueFlushCmd() *cobra.Command {
+	var (
+		watch    bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Submit queued jobs to their nodes",
+		Long: `Submit queued jobs in the order they were queued. Entries for nodes that are
+still unreachable stay queued; entries the server rejects stay queued with the
+error recorded so they can be inspected and removed.
+
+With --watch the command keeps retrying every --interval until the queue is
+empty, which is convenient to leave running in the background on a laptop.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := defaultStore()
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			for {
+				remaining, err := flush(ctx, store)
+				if err != nil || !watch || remaining == 0 {
+					return err
+				}
+
+				fmt.Printf("%d job(s) still queued, retrying in %s\n", remaining, interval)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&watch, "watch", false, "Keep retrying until the queue is empty")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Time between retries with --watch")
+
+	return cmd
+}
+
+func newQueueRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <id>...",
+		Short: "Remove queued job submissions without submitting them",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := defaultStore()
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				if err := store.Remove(id); err != nil {
+					return err
+				}
+				fmt.Printf("Removed %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+// flush submits every entry whose node is reachable and returns how many
+// entries remain queued.
+func flush(ctx context.Context, store *Store) (int, error) {
+	entries, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	unreachable := make(map[string]bool)
+	remaining := 0
+
+	for _, entry := range entries {
+		if ctx.Err() != nil || unreachable[entry.Node] {
+			remaining++
+			continue
+		}
+
+		jobUUID, err := submit(ctx, entry)
+		if err == nil {
+			if err := store.Remove(entry.ID); err != nil {
+				return remaining, fmt.Errorf("job %s was submitted as %s but could not be removed from the queue: %w", entry.ID, jobUUID, err)
+			}
+			fmt.Printf("Submitted %s to %s as job %s\n", entry.ID, entry.Node, jobUUID)
+			continue
+		}
+
+		remaining++
+		entry.Attempts++
+		entry.LastError = err.Error()
+		if updateErr := store.Update(entry); updateErr != nil {
+			return remaining, updateErr
+		}
+
+		if isUnreachable(err) {
+			unreachable[entry.Node] = true
+			fmt.Printf("Node %s is unreachable, keeping its jobs queued\n", entry.Node)
+		} else {
+			fmt.Printf("Server rejected %s: %v\n", entry.ID, err)
+		}
+	}
+
+	return remaining, nil
+}
+
+// unreachableError marks a node that could not be connected to at all, so the
+// request was never sent.
+type unreachableError struct{ err error }
+
+func (e *unreachableError) Error() string { return e.err.Error() }
+func (e *unreachableError) Unwrap() error { return e.err }
+
+func isUnreachable(err error) bool {
+	if _, ok := err.(*unreachableError); ok {
+		return true
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// submit sends one queued request and returns the new job UUID.
+func submit(ctx context.Context, entry *Entry) (string, error) {
+	req, err := entry.RunJobRequest()
+	if err != nil {
+		return "", err
+	}
+
+	jobClient, err := common.NewJobClientForNode(entry.Node)
+	if err != nil {
+		return "", err
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	if err := jobClient.WaitForConnection(connectCtx); err != nil {
+		return "", &unreachableError{err}
+	}
+
+	runCtx, cancelRun := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelRun()
+	resp, err := jobClient.RunJob(runCtx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.JobUuid, nil
+}
+
+func defaultStore() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(dir), nil
+}
+
+func outputEntriesJSON(entries []*Entry) error {
+	type entryJSON struct {
+		ID        string    `json:"id"`
+		Node      string    `json:"node"`
+		CreatedAt time.Time `json:"created_at"`
+		Attempts  int       `json:"attempts"`
+		Command   string    `json:"command"`
+		LastError string    `json:"last_error,omitempty"`
+	}
+
+	output := make([]entryJSON, 0, len(entries))
+	for _, entry := range entries {
+		output = append(output, entryJSON{
+			ID:        entry.ID,
+			Node:      entry.Node,
+			CreatedAt: entry.CreatedAt,
+			Attempts:  entry.Attempts,
+			Command:   entry.Summary(),
+			LastError: entry.LastError,
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
@@ -0,0 +1,171 @@
+// Package queue keeps job submissions that could not reach the server on the
+// local disk so they can be submitted once the node is reachable again.
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// DirEnv overrides the queue directory.
+const DirEnv = "RNX_QUEUE_DIR"
+
+// Entry is one queued job submission.
+type Entry struct {
+	ID        string          `json:"id"`
+	Node      string          `json:"node"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	Request   json.RawMessage `json:"request"`
+}
+
+// RunJobRequest decodes the queued request.
+func (e *Entry) RunJobRequest() (*pb.RunJobRequest, error) {
+	var req pb.RunJobRequest
+	if err := protojson.Unmarshal(e.Request, &req); err != nil {
+		return nil, fmt.Errorf("corrupt queued request %s: %w", e.ID, err)
+	}
+	return &req, nil
+}
+
+// Summary describes the queued command for listings.
+func (e *Entry) Summary() string {
+	req, err := e.RunJobRequest()
+	if err != nil {
+		return "<unreadable>"
+	}
+	return strings.TrimSpace(req.Command + " " + strings.Join(req.Args, " "))
+}
+
+// Store keeps one JSON file per entry in a directory. Files are written with
+// owner-only permissions because requests may carry secret environment
+// variables and uploaded file contents.
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns $RNX_QUEUE_DIR, or ~/.rnx/queue next to the client config.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate queue directory: %w", err)
+	}
+	return filepath.Join(home, ".rnx", "queue"), nil
+}
+
+// NewStore creates a store rooted at dir. The directory is created on the
+// first Add.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Add queues req for node and returns the new entry.
+func (s *Store) Add(node string, req *pb.RunJobRequest) (*Entry, error) {
+	data, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate queue id: %w", err)
+	}
+
+	now := time.Now().UTC()
+	entry := &Entry{
+		ID:        now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		Node:      node,
+		CreatedAt: now,
+		Request:   data,
+	}
+	if err := s.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Update writes entry, replacing any previous version atomically.
+func (s *Store) Update(entry *Entry) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode queue entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to write queue entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write queue entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write queue entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path(entry.ID))
+}
+
+// List returns all entries, oldest first.
+func (s *Store) List() ([]*Entry, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read queue entry: %w", err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("corrupt queue entry %s: %w", filepath.Base(file), err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// Remove deletes the entry with the given id.
+func (s *Store) Remove(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no queued job with id %s", id)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
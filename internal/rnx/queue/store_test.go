@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_RoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir() + "/queue")
+
+	request := &pb.RunJobRequest{
+		Command:           "python3",
+		Args:              []string{"train.py", "--epochs=3"},
+		MaxMemory:         512,
+		SecretEnvironment: map[string]string{"API_KEY": "secret"},
+		Uploads:           []*pb.FileUpload{{Path: "train.py", Content: []byte("print(1)"), Mode: 0644}},
+	}
+
+	entry, err := store.Add("default", request)
+	require.NoError(t, err)
+
+	info, err := os.Stat(store.path(entry.ID))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "queued requests may hold secrets")
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "default", entries[0].Node)
+	assert.Equal(t, "python3 train.py --epochs=3", entries[0].Summary())
+
+	decoded, err := entries[0].RunJobRequest()
+	require.NoError(t, err)
+	assert.Equal(t, request.Command, decoded.Command)
+	assert.Equal(t, request.Args, decoded.Args)
+	assert.Equal(t, request.SecretEnvironment, decoded.SecretEnvironment)
+	assert.Equal(t, request.Uploads[0].Content, decoded.Uploads[0].Content)
+
+	require.NoError(t, store.Remove(entry.ID))
+	entries, err = store.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Error(t, store.Remove(entry.ID))
+}
+
+func TestStore_ListOrderAndUpdate(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	first, err := store.Add("srv1", &pb.RunJobRequest{Command: "first"})
+	require.NoError(t, err)
+	second, err := store.Add("srv2", &pb.RunJobRequest{Command: "second"})
+	require.NoError(t, err)
+
+	// Force a distinct order regardless of clock resolution
+	first.CreatedAt = second.CreatedAt.Add(-time.Second)
+	first.Attempts = 2
+	first.LastError = "connection refused"
+	require.NoError(t, store.Update(first))
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first.ID, entries[0].ID)
+	assert.Equal(t, 2, entries[0].Attempts)
+	assert.Equal(t, "connection refused", entries[0].LastError)
+	assert.Equal(t, second.ID, entries[1].ID)
+}
+
+func TestStore_ListMissingDir(t *testing.T) {
+	entries, err := NewStore(t.TempDir() + "/absent").List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
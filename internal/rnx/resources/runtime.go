@@ -12,14 +12,20 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/ehsaniara/joblet/internal/joblet/runtime/channels"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
 	"github.com/ehsaniara/joblet/pkg/client"
+	"github.com/ehsaniara/joblet/pkg/constants"
 	"github.com/ehsaniara/joblet/pkg/registry"
 	"github.com/ehsaniara/joblet/pkg/runtime"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func NewRuntimeCmd() *cobra.Command {
@@ -49,7 +55,12 @@ Examples:
   rnx runtime test openjdk-21
   
   # Remove a runtime
-  rnx runtime remove python-3.11-ml`,
+  rnx runtime remove python-3.11-ml
+
+  # Try a new version on the jobs asking for python@canary, then promote it
+  rnx runtime canary python python-3.12@2.0.0
+  rnx runtime channels
+  rnx runtime promote python`,
 	}
 
 	cmd.AddCommand(NewRuntimeListCmd())
@@ -58,6 +69,9 @@ Examples:
 	cmd.AddCommand(NewRuntimeInstallCmd())
 	cmd.AddCommand(NewRuntimeValidateCmd())
 	cmd.AddCommand(NewRuntimeRemoveCmd())
+	cmd.AddCommand(NewRuntimeChannelsCmd())
+	cmd.AddCommand(NewRuntimeCanaryCmd())
+	cmd.AddCommand(NewRuntimePromoteCmd())
 
 	return cmd
 }
@@ -65,6 +79,7 @@ Examples:
 func NewRuntimeListCmd() *cobra.Command {
 	var githubRepo string
 	var registryURL string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -75,6 +90,9 @@ Examples:
   # List locally installed runtimes
   rnx runtime list
 
+  # List installed runtimes of every tenant (admin only)
+  rnx runtime list --all
+
   # List available runtimes from the default registry
   rnx runtime list --registry
 
@@ -84,20 +102,22 @@ Examples:
   # List available runtimes from a GitHub repository
   rnx runtime list --github-repo=owner/repo/tree/main/runtimes`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRuntimeList(cmd, args, githubRepo, registryURL)
+			return runRuntimeList(cmd, args, githubRepo, registryURL, all)
 		},
 	}
 
 	cmd.Flags().StringVar(&githubRepo, "github-repo", "", "List runtimes from GitHub repository instead of local files. Supports formats: owner/repo, owner/repo/tree/branch/path")
 	cmd.Flags().StringVar(&registryURL, "registry", "", "List available runtimes from GitHub registry (default: ehsaniara/joblet-runtimes). Format: owner/repo")
 
+	cmd.Flags().BoolVar(&all, "all", false, "List installed runtimes of every tenant, not only those your tenant may use (admin only)")
+
 	// Set NoOptDefVal so --registry works without a value
 	cmd.Flags().Lookup("registry").NoOptDefVal = "ehsaniara/joblet-runtimes"
 
 	return cmd
 }
 
-func runRuntimeList(cmd *cobra.Command, args []string, githubRepo string, registryURL string) error {
+func runRuntimeList(cmd *cobra.Command, args []string, githubRepo string, registryURL string, all bool) error {
 	// Check for conflicting flags
 	if githubRepo != "" && registryURL != "" {
 		return fmt.Errorf("cannot use both --github-repo and --registry flags together")
@@ -128,6 +148,9 @@ func runRuntimeList(cmd *cobra.Command, args []string, githubRepo string, regist
 	// Get runtimes from server via gRPC
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	if all {
+		ctx = metadata.AppendToOutgoingContext(ctx, constants.ListAllHeader, "true")
+	}
 
 	resp, err := client.ListRuntimes(ctx)
 	if err != nil {
@@ -192,17 +215,29 @@ func NewRuntimeInfoCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "info <runtime>",
 		Short: "Get detailed information about a runtime",
-		Long:  `Display detailed information about a specific runtime including installed packages, mount points, and requirements.`,
-		Args:  cobra.ExactArgs(1),
-		RunE:  runRuntimeInfo,
+		Long: `Display detailed information about a specific runtime: what it mounts into jobs,
+the environment it exports and the PATH jobs start with, the tool versions
+recorded when it was built, its packages, and the disk it takes.
+
+The runtime is named the way jobs name it, so aliases, tenant pins and
+name@version and name@canary resolve as they would for a job.
+
+Examples:
+  rnx runtime info python-3.11-ml
+  rnx runtime info python-3.11-ml@1.0.0
+  rnx --json runtime info python`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRuntimeInfo,
 	}
 }
 
 func runRuntimeInfo(cmd *cobra.Command, args []string) error {
 	runtimeSpec := args[0]
 
-	// Parse runtime spec to extract name and version
-	spec, err := runtime.ParseRuntimeSpec(runtimeSpec)
+	// Parse runtime spec to extract name and version; the server resolves
+	// channels such as python@canary
+	name, _, _ := channels.SplitChannel(runtimeSpec)
+	spec, err := runtime.ParseRuntimeSpec(name)
 	if err != nil {
 		return fmt.Errorf("invalid runtime specification: %w", err)
 	}
@@ -218,6 +253,134 @@ func runRuntimeInfo(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	details, err := client.DescribeRuntime(ctx, runtimeSpec)
+	switch status.Code(err) {
+	case codes.OK:
+		if common.JSONOutput {
+			return outputRuntimeDetailsJSON(os.Stdout, details, runtimeSpec)
+		}
+		printRuntimeDetails(os.Stdout, details, runtimeSpec)
+		return nil
+	case codes.NotFound:
+		return fmt.Errorf("%s", status.Convert(err).Message())
+	case codes.Unimplemented:
+		// Servers without the runtime info service only have the summary
+		return runRuntimeSummary(ctx, client, spec, runtimeSpec)
+	}
+	return fmt.Errorf("failed to get runtime info: %w", err)
+}
+
+// printRuntimeDetails shows what a job on the runtime gets
+func printRuntimeDetails(w io.Writer, rt *runtimespb.RuntimeDetails, runtimeSpec string) {
+	fmt.Fprintf(w, "Runtime: %s\n", rt.Name)
+	fmt.Fprintf(w, "Version: %s\n", rt.Version)
+	if rt.PinnedBy != "" {
+		fmt.Fprintf(w, "Resolved: %s (%s)\n", rt.Resolved, rt.PinnedBy)
+	}
+	if rt.Language != "" {
+		fmt.Fprintf(w, "Language: %s\n", rt.Language)
+	}
+	fmt.Fprintf(w, "Description: %s\n", rt.Description)
+	fmt.Fprintf(w, "Path: %s\n", rt.Path)
+	fmt.Fprintf(w, "Disk: %s in %d files\n", formatSize(rt.SizeBytes), rt.Files)
+	if len(rt.Architectures) > 0 {
+		fmt.Fprintf(w, "Architectures: %s\n", strings.Join(rt.Architectures, ", "))
+	}
+
+	if len(rt.Mounts) > 0 {
+		fmt.Fprintln(w, "\nMounts:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, m := range rt.Mounts {
+			var notes []string
+			if m.Readonly {
+				notes = append(notes, "read-only")
+			}
+			if m.Missing {
+				notes = append(notes, "missing, not mounted")
+			}
+			note := ""
+			if len(notes) > 0 {
+				note = "(" + strings.Join(notes, ", ") + ")"
+			}
+			fmt.Fprintf(tw, "  %s\t<- %s\t%s\n", m.Target, m.Source, note)
+		}
+		tw.Flush()
+	}
+
+	fmt.Fprintln(w, "\nEnvironment:")
+	keys := make([]string, 0, len(rt.Environment))
+	for key := range rt.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s=%s\n", key, rt.Environment[key])
+	}
+	fmt.Fprintf(w, "  Job PATH: %s\n", rt.JobPath)
+
+	if len(rt.Tools) > 0 {
+		fmt.Fprintln(w, "\nTools:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, tool := range rt.Tools {
+			fmt.Fprintf(tw, "  %s\t%s\n", tool.Name, tool.Version)
+		}
+		tw.Flush()
+	}
+
+	if len(rt.Packages) > 0 {
+		fmt.Fprintln(w, "\nPre-installed Packages:")
+		for _, pkg := range rt.Packages {
+			fmt.Fprintf(w, "  - %s\n", pkg)
+		}
+	}
+
+	fmt.Fprintln(w, "\nUsage:")
+	fmt.Fprintf(w, "  rnx job run --runtime=%s <command>\n", runtimeSpec)
+}
+
+func outputRuntimeDetailsJSON(w io.Writer, rt *runtimespb.RuntimeDetails, runtimeSpec string) error {
+	type mount struct {
+		Source   string `json:"source"`
+		Target   string `json:"target"`
+		ReadOnly bool   `json:"readonly"`
+		Missing  bool   `json:"missing,omitempty"`
+	}
+	tools := make(map[string]string, len(rt.Tools))
+	for _, tool := range rt.Tools {
+		tools[tool.Name] = tool.Version
+	}
+	mounts := make([]mount, 0, len(rt.Mounts))
+	for _, m := range rt.Mounts {
+		mounts = append(mounts, mount{Source: m.Source, Target: m.Target, ReadOnly: m.Readonly, Missing: m.Missing})
+	}
+	output := map[string]interface{}{
+		"name":          rt.Name,
+		"version":       rt.Version,
+		"resolved":      rt.Resolved,
+		"language":      rt.Language,
+		"description":   rt.Description,
+		"path":          rt.Path,
+		"size_bytes":    rt.SizeBytes,
+		"files":         rt.Files,
+		"architectures": rt.Architectures,
+		"mounts":        mounts,
+		"environment":   rt.Environment,
+		"job_path":      rt.JobPath,
+		"tools":         tools,
+		"packages":      rt.Packages,
+		"usage":         fmt.Sprintf("rnx job run --runtime=%s <command>", runtimeSpec),
+	}
+	if rt.PinnedBy != "" {
+		output["pinned_by"] = rt.PinnedBy
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// runRuntimeSummary shows the public runtime summary
+func runRuntimeSummary(ctx context.Context, client *client.JobClient, spec *runtime.RuntimeSpec, runtimeSpec string) error {
 	req := &pb.RuntimeInfoReq{Runtime: spec.Name}
 	resp, err := client.GetRuntimeInfo(ctx, req)
 	if err != nil {
@@ -306,12 +469,40 @@ func NewRuntimeTestCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "test <runtime>",
 		Short: "Test a runtime environment",
-		Long:  `Run basic validation tests on a runtime to ensure it's working correctly.`,
-		Args:  cobra.ExactArgs(1),
-		RunE:  runRuntimeTest,
+		Long: `Run basic validation tests on a runtime to ensure it's working correctly.
+
+With a command after "--", also run it in a short job on the runtime. The job
+prints the PATH the runtime and server assemble and where the command resolves,
+so "command not found" problems show up before real jobs hit them.
+
+Examples:
+  # Check that the runtime is installed
+  rnx runtime test openjdk-21
+
+  # Check that a command resolves and runs on the runtime
+  rnx runtime test openjdk-21 -- java -version
+  rnx runtime test python-3.11-ml -- python3 -c "import numpy"`,
+		Args: runtimeTestArgs,
+		RunE: runRuntimeTest,
 	}
 }
 
+// runtimeTestArgs accepts the runtime name, optionally followed by "--" and
+// the command to validate
+func runtimeTestArgs(cmd *cobra.Command, args []string) error {
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 {
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+	if dash != 1 {
+		return fmt.Errorf("expected one runtime before \"--\", got %d", dash)
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("expected a command after \"--\"")
+	}
+	return nil
+}
+
 func runRuntimeTest(cmd *cobra.Command, args []string) error {
 	runtimeSpec := args[0]
 
@@ -348,11 +539,94 @@ func runRuntimeTest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("runtime test failed")
 	}
 
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		return runRuntimeValidationJob(client, runtimeSpec, args[dash:])
+	}
+
 	testCmd := "echo 'Runtime available'"
 
 	fmt.Printf("\nTo test the runtime in a job:\n")
 	fmt.Printf("  rnx job run --runtime=%s %s\n", runtimeSpec, testCmd)
+	fmt.Printf("  rnx runtime test %s -- <command>\n", runtimeSpec)
+
+	return nil
+}
+
+// runtimeValidationScript reports the job's PATH and where the command
+// resolves, then replaces itself with the command. It exits 127 like a shell
+// when the command is not found.
+const runtimeValidationScript = `printf 'PATH=%s\n' "$PATH"
+resolved=$(command -v "$1") || { printf '%s: command not found in PATH\n' "$1" >&2; exit 127; }
+printf 'Command: %s\n---\n' "$resolved"
+exec "$@"`
+
+// finishedJobStatuses are the statuses a validation job does not leave
+var finishedJobStatuses = map[string]bool{
+	"COMPLETED": true,
+	"FAILED":    true,
+	"STOPPED":   true,
+	"CANCELED":  true,
+}
+
+// runtimeValidationTimeout bounds how long the validation job may run
+const runtimeValidationTimeout = 5 * time.Minute
+
+// runRuntimeValidationJob runs command in a job on the runtime, prints its
+// output and fails unless it exits 0
+func runRuntimeValidationJob(client *client.JobClient, runtimeSpec string, command []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeValidationTimeout)
+	defer cancel()
+
+	job, err := client.RunJob(ctx, &pb.RunJobRequest{
+		Name:    "runtime-test-" + runtimeSpec,
+		Command: "/bin/sh",
+		Args:    append([]string{"-c", runtimeValidationScript, "runtime-test"}, command...),
+		Runtime: runtimeSpec,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start validation job: %w", err)
+	}
+	fmt.Printf("\nValidation job %s: %s\n", job.JobUuid, strings.Join(command, " "))
+
+	stream, err := client.GetJobLogs(ctx, job.JobUuid)
+	if err != nil {
+		return fmt.Errorf("couldn't read validation job logs: %w", err)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error receiving validation job logs: %w", err)
+		}
+		fmt.Printf("%s", chunk.Payload)
+	}
 
+	// The log stream ends with the job, the status may lag a moment behind
+	var status *pb.GetJobStatusRes
+	for {
+		if status, err = client.GetJobStatus(ctx, job.JobUuid); err != nil {
+			return fmt.Errorf("couldn't get validation job status: %w", err)
+		}
+		if finishedJobStatuses[status.Status] {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("validation job %s did not finish within %s", job.JobUuid, runtimeValidationTimeout)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	if status.Status != "COMPLETED" || status.ExitCode != 0 {
+		fmt.Printf("\n✗ Validation job %s (exit code %d)\n", strings.ToLower(status.Status), status.ExitCode)
+		if status.ExitCode == 127 {
+			fmt.Printf("Check the runtime's environment (PATH, PATH_PREPEND) in runtime.yml, or pass the command's full path\n")
+		}
+		return fmt.Errorf("runtime validation failed")
+	}
+	fmt.Printf("\nRuntime validation passed\n")
 	return nil
 }
 
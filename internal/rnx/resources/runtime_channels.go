@@ -0,0 +1,262 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRuntimeChannelsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "channels",
+		Short: "Show the stable and canary channels of runtime names",
+		Long: `Show where the stable and canary channels of runtime names point and, for each
+canary, how its jobs fail against the jobs on stable since it was pinned.
+
+Jobs asking for a name, such as --runtime=python, run on its stable runtime.
+Jobs asking for name@canary run on the canary while there is one.
+
+Verdicts:
+  pending    Fewer finished canary jobs than the server's min_samples
+  healthy    The canary fails no more than max_rate_increase above stable
+  regressed  The canary fails more; promoting it needs --force
+
+Examples:
+  rnx runtime channels
+  rnx --json runtime channels`,
+		Args: cobra.NoArgs,
+		RunE: runRuntimeChannels,
+	}
+}
+
+func NewRuntimeCanaryCmd() *cobra.Command {
+	var clearCanary bool
+
+	cmd := &cobra.Command{
+		Use:   "canary <name> [runtime]",
+		Short: "Pin an installed runtime to the canary channel of a name",
+		Long: `Pin an installed runtime to the canary channel of a runtime name. Jobs asking
+for name@canary run on it; every other job asking for the name keeps running on
+stable until the canary is promoted.
+
+Examples:
+  # Try python-3.12@2.0.0 on the jobs that opt in
+  rnx runtime canary python python-3.12@2.0.0
+  rnx job run --runtime=python@canary python3 test.py
+
+  # Send the canary jobs back to stable
+  rnx runtime canary python --clear`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clearCanary {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRuntimeCanary(args, clearCanary)
+		},
+	}
+
+	cmd.Flags().BoolVar(&clearCanary, "clear", false, "Remove the canary instead of pinning one")
+
+	return cmd
+}
+
+func NewRuntimePromoteCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "promote <name>",
+		Short: "Make the canary of a runtime name its stable runtime",
+		Long: `Make the canary of a runtime name its stable runtime, so every job asking for
+the name runs on it. A canary whose jobs failed more than stable allows is only
+promoted with --force.
+
+Examples:
+  rnx runtime promote python
+  rnx runtime promote python --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRuntimePromote(args[0], force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Promote even when the canary regressed")
+
+	return cmd
+}
+
+func runRuntimeChannels(cmd *cobra.Command, args []string) error {
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := client.ListRuntimeChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list runtime channels: %w", err)
+	}
+
+	if common.JSONOutput {
+		return outputRuntimeChannelsJSON(os.Stdout, resp.Channels)
+	}
+	if len(resp.Channels) == 0 {
+		fmt.Println("No runtime channels pinned.")
+		return nil
+	}
+	printRuntimeChannels(os.Stdout, resp.Channels)
+	return nil
+}
+
+func runRuntimeCanary(args []string, clearCanary bool) error {
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if clearCanary {
+		channel, err := client.ClearRuntimeCanary(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to clear runtime canary: %w", err)
+		}
+		fmt.Printf("Canary of %s cleared, its jobs run on %s\n", channel.Name, channel.Stable)
+		return nil
+	}
+
+	channel, err := client.SetRuntimeCanary(ctx, args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("failed to pin runtime canary: %w", err)
+	}
+	fmt.Printf("Canary of %s pinned to %s (stable: %s)\n", channel.Name, channel.Canary, channel.Stable)
+	fmt.Printf("Jobs opt in with --runtime=%s@canary\n", channel.Name)
+	return nil
+}
+
+func runRuntimePromote(name string, force bool) error {
+	client, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	channel, err := client.PromoteRuntimeCanary(ctx, name, force)
+	if err != nil {
+		return fmt.Errorf("failed to promote runtime canary: %w", err)
+	}
+	fmt.Printf("Canary of %s promoted, jobs asking for %s now run on %s\n", channel.Name, channel.Name, channel.Stable)
+	return nil
+}
+
+// printRuntimeChannels shows a line per runtime name and the comparison of
+// each canary against stable
+func printRuntimeChannels(w io.Writer, list []*runtimespb.RuntimeChannel) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTABLE\tCANARY\tCANARY FAILED\tSTABLE FAILED\tVERDICT")
+	for _, ch := range list {
+		canary, canaryFailed, stableFailed, verdict := "-", "-", "-", "-"
+		if ch.Canary != "" {
+			canary = ch.Canary
+		}
+		if c := ch.Comparison; c != nil {
+			canaryFailed = formatChannelStats(c.Canary)
+			stableFailed = formatChannelStats(c.Stable)
+			verdict = c.Verdict
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", ch.Name, ch.Stable, canary, canaryFailed, stableFailed, verdict)
+	}
+	tw.Flush()
+}
+
+// formatChannelStats shows the failure rate over the finished jobs
+func formatChannelStats(stats *runtimespb.RuntimeChannelStats) string {
+	if stats == nil || stats.Finished == 0 {
+		return "0 jobs"
+	}
+	return fmt.Sprintf("%.1f%% of %d", stats.FailureRate*100, stats.Finished)
+}
+
+func outputRuntimeChannelsJSON(w io.Writer, list []*runtimespb.RuntimeChannel) error {
+	type stats struct {
+		Runtime     string  `json:"runtime"`
+		Jobs        int32   `json:"jobs"`
+		Finished    int32   `json:"finished"`
+		Failed      int32   `json:"failed"`
+		FailureRate float64 `json:"failure_rate"`
+	}
+	type comparison struct {
+		Canary          stats   `json:"canary"`
+		Stable          stats   `json:"stable"`
+		Verdict         string  `json:"verdict"`
+		MinSamples      int32   `json:"min_samples"`
+		MaxRateIncrease float64 `json:"max_rate_increase"`
+	}
+	type channel struct {
+		Name           string      `json:"name"`
+		Stable         string      `json:"stable"`
+		StablePinnedBy string      `json:"stable_pinned_by,omitempty"`
+		StableSince    string      `json:"stable_since,omitempty"`
+		Canary         string      `json:"canary,omitempty"`
+		CanarySince    string      `json:"canary_since,omitempty"`
+		UpdatedBy      string      `json:"updated_by,omitempty"`
+		Comparison     *comparison `json:"comparison,omitempty"`
+	}
+	toStats := func(s *runtimespb.RuntimeChannelStats) stats {
+		if s == nil {
+			return stats{}
+		}
+		return stats{Runtime: s.Runtime, Jobs: s.Jobs, Finished: s.Finished, Failed: s.Failed, FailureRate: s.FailureRate}
+	}
+	formatTime := func(nanos int64) string {
+		if nanos == 0 {
+			return ""
+		}
+		return time.Unix(0, nanos).UTC().Format(time.RFC3339)
+	}
+
+	output := make([]channel, 0, len(list))
+	for _, ch := range list {
+		c := channel{
+			Name:           ch.Name,
+			Stable:         ch.Stable,
+			StablePinnedBy: ch.StablePinnedBy,
+			StableSince:    formatTime(ch.StableSince),
+			Canary:         ch.Canary,
+			CanarySince:    formatTime(ch.CanarySince),
+			UpdatedBy:      ch.UpdatedBy,
+		}
+		if cmp := ch.Comparison; cmp != nil {
+			c.Comparison = &comparison{
+				Canary:          toStats(cmp.Canary),
+				Stable:          toStats(cmp.Stable),
+				Verdict:         cmp.Verdict,
+				MinSamples:      cmp.MinSamples,
+				MaxRateIncrease: cmp.MaxRateIncrease,
+			}
+		}
+		output = append(output, c)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
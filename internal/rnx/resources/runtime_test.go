@@ -2,11 +2,13 @@ package resources
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
 	"github.com/ehsaniara/joblet/internal/rnx/common"
 
 	"github.com/spf13/cobra"
@@ -397,16 +399,37 @@ func TestRuntimeCommandValidation(t *testing.T) {
 			args:      []string{},
 			expectErr: true,
 		},
+		{
+			name:      "runtime test with command",
+			cmdFunc:   NewRuntimeTestCmd,
+			args:      []string{"openjdk-21", "--", "java", "-version"},
+			expectErr: false,
+		},
+		{
+			name:      "runtime test with empty command",
+			cmdFunc:   NewRuntimeTestCmd,
+			args:      []string{"openjdk-21", "--"},
+			expectErr: true,
+		},
+		{
+			name:      "runtime test with two runtimes",
+			cmdFunc:   NewRuntimeTestCmd,
+			args:      []string{"openjdk-21", "python-3.11", "--", "java"},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cmd := tt.cmdFunc()
 			cmd.SetArgs(tt.args)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
 
 			// Test argument validation (Args field)
 			if cmd.Args != nil {
-				err := cmd.Args(cmd, tt.args)
+				err := cmd.Args(cmd, cmd.Flags().Args())
 				if tt.expectErr && err == nil {
 					t.Error("Expected error but got none")
 				}
@@ -455,3 +478,56 @@ func TestMain(m *testing.M) {
 
 	os.Exit(code)
 }
+
+func TestPrintRuntimeDetails(t *testing.T) {
+	rt := &runtimespb.RuntimeDetails{
+		Name:        "python-3.11-ml",
+		Version:     "1.2.0",
+		Resolved:    "python-3.11-ml@1.2.0",
+		PinnedBy:    "server alias",
+		Language:    "python",
+		Path:        "/opt/joblet/runtimes/python-3.11-ml/1.2.0",
+		SizeBytes:   3 * 1024 * 1024,
+		Files:       42,
+		Mounts:      []*runtimespb.RuntimeMount{{Source: "isolated/usr/bin", Target: "/usr/bin", Readonly: true}, {Source: "isolated/lib64", Target: "/lib64", Missing: true}},
+		Environment: map[string]string{"PYTHONDONTWRITEBYTECODE": "1"},
+		JobPath:     "/opt/venv/bin:/usr/bin:/bin",
+		Tools:       []*runtimespb.RuntimeTool{{Name: "pip", Version: "24.0"}, {Name: "python3", Version: "3.11.9"}},
+	}
+
+	var out bytes.Buffer
+	printRuntimeDetails(&out, rt, "python")
+	for _, want := range []string{
+		"Resolved: python-3.11-ml@1.2.0 (server alias)",
+		"Disk: 3.0MB in 42 files",
+		"/usr/bin  <- isolated/usr/bin  (read-only)",
+		"/lib64    <- isolated/lib64    (missing, not mounted)",
+		"PYTHONDONTWRITEBYTECODE=1",
+		"Job PATH: /opt/venv/bin:/usr/bin:/bin",
+		"python3  3.11.9",
+		"rnx job run --runtime=python <command>",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := outputRuntimeDetailsJSON(&out, rt, "python"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Tools  map[string]string `json:"tools"`
+		Mounts []struct {
+			Target  string `json:"target"`
+			Missing bool   `json:"missing"`
+		} `json:"mounts"`
+		PinnedBy string `json:"pinned_by"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON %s: %v", out.String(), err)
+	}
+	if decoded.Tools["pip"] != "24.0" || !decoded.Mounts[1].Missing || decoded.PinnedBy != "server alias" {
+		t.Errorf("JSON = %s", out.String())
+	}
+}
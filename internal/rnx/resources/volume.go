@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/constants"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
 )
 
 func NewVolumeCmd() *cobra.Command {
@@ -25,6 +27,7 @@ func NewVolumeCmd() *cobra.Command {
 	cmd.AddCommand(NewVolumeCreateCmd())
 	cmd.AddCommand(NewVolumeListCmd())
 	cmd.AddCommand(NewVolumeRemoveCmd())
+	cmd.AddCommand(NewVolumeDownloadCmd())
 
 	return cmd
 }
@@ -60,16 +63,23 @@ Examples:
 }
 
 func NewVolumeListCmd() *cobra.Command {
+	var all bool
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all volumes",
-		Long:  "Display all available volumes with their size, type, and usage information",
-		Args:  cobra.NoArgs,
+		Long: `Display all available volumes with their size, type, and usage information.
+
+Clients of a tenant only see the volumes their tenant may use; admins can
+list every volume with --all.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runVolumeList(common.JSONOutput)
+			return runVolumeList(common.JSONOutput, all)
 		},
 	}
 
+	cmd.Flags().BoolVar(&all, "all", false, "List the volumes of every tenant (admin only)")
+
 	return cmd
 }
 
@@ -132,7 +142,7 @@ type VolumeInfo struct {
 	CreatedTime string `json:"created_time"`
 }
 
-func runVolumeList(jsonOutput bool) error {
+func runVolumeList(jsonOutput, all bool) error {
 	jobClient, err := common.NewJobClient()
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
@@ -141,6 +151,9 @@ func runVolumeList(jsonOutput bool) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	if all {
+		ctx = metadata.AppendToOutgoingContext(ctx, constants.ListAllHeader, "true")
+	}
 
 	resp, err := jobClient.ListVolumes(ctx)
 	if err != nil {
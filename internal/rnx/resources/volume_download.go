@@ -0,0 +1,351 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	artifactspb "github.com/ehsaniara/joblet/internal/proto/gen/artifacts"
+	"github.com/ehsaniara/joblet/internal/rnx/common"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// artifactRetries bounds the retries of a window that broke or failed its
+// checksum
+const artifactRetries = 5
+
+// artifactRetryDelay is the first delay between retries, doubled each time
+var artifactRetryDelay = time.Second
+
+func NewVolumeDownloadCmd() *cobra.Command {
+	var (
+		output  string
+		window  string
+		restart bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download <volume> <path>",
+		Short: "Download a file from a volume",
+		Long: `Download a file a job left in a volume, such as a model or a dataset.
+
+The file is fetched in windows of --window bytes. Each window is checked
+against the SHA-256 the server sends after it and retried when it breaks or
+does not match. The file is written to <output>.part and renamed once
+complete; running the same command again after an interruption resumes from
+the last complete window.
+
+Examples:
+  # Download /volumes/models/run-42/model.bin to model.bin
+  rnx volume download models run-42/model.bin
+
+  # Smaller windows over a lossy link
+  rnx volume download models run-42/model.bin -o model.bin --window 8MB`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVolumeDownload(args[0], args[1], output, window, restart)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (default the file's base name)")
+	cmd.Flags().StringVar(&window, "window", "64MB", "Bytes requested per window")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Ignore a partial download and start from the beginning")
+
+	return cmd
+}
+
+func runVolumeDownload(volume, filePath, output, window string, restart bool) error {
+	windowBytes, err := domain.ParseSize(window)
+	if err != nil || windowBytes <= 0 {
+		return fmt.Errorf("invalid window size %q", window)
+	}
+	if output == "" {
+		output = path.Base(filePath)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	jobClient, err := common.NewJobClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer jobClient.Close()
+
+	d := &artifactDownload{
+		open: func(ctx context.Context, offset int64) (artifactChunkStream, error) {
+			return jobClient.DownloadArtifact(ctx, &artifactspb.DownloadArtifactRequest{
+				Volume: volume, Path: filePath, Offset: offset, Window: windowBytes,
+			})
+		},
+		state: artifactState{Volume: volume, Path: filePath},
+	}
+	if !common.JSONOutput {
+		d.progress = os.Stderr
+	}
+	result, err := d.run(ctx, output, restart)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("download interrupted, run the same command again to resume")
+		}
+		return err
+	}
+
+	if common.JSONOutput {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"volume":  volume,
+			"path":    filePath,
+			"output":  output,
+			"bytes":   result.Bytes,
+			"windows": result.Windows,
+			"resumed": result.Resumed,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if result.Resumed {
+		fmt.Printf("Resumed download of %s\n", filePath)
+	}
+	fmt.Printf("Saved %s (%s, %d verified windows) to %s\n", filePath, formatSize(result.Bytes), result.Windows, output)
+	return nil
+}
+
+// artifactChunkStream is the client side of a DownloadArtifact call
+type artifactChunkStream interface {
+	Recv() (*artifactspb.ArtifactChunk, error)
+	Trailer() metadata.MD
+}
+
+// artifactState is the checkpoint of a partial download, stored next to it
+type artifactState struct {
+	Volume  string `json:"volume"`
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`     // File size when the download started
+	ModTime int64  `json:"mod_time"` // File modification time when the download started
+	Bytes   int64  `json:"bytes"`    // Bytes of verified windows in the partial file
+}
+
+// artifactResult describes a completed download
+type artifactResult struct {
+	Bytes   int64
+	Windows int
+	Resumed bool
+}
+
+// artifactDownload fetches a file window by window into a partial file
+type artifactDownload struct {
+	open     func(ctx context.Context, offset int64) (artifactChunkStream, error)
+	state    artifactState
+	progress io.Writer
+}
+
+// run downloads to output through <output>.part, resuming from the state in
+// <output>.part.json when it is for the same file
+func (d *artifactDownload) run(ctx context.Context, output string, restart bool) (*artifactResult, error) {
+	partPath := output + ".part"
+	statePath := partPath + ".json"
+
+	resumed := false
+	if !restart {
+		if previous, ok := readArtifactState(statePath); ok && previous.Volume == d.state.Volume && previous.Path == d.state.Path {
+			d.state = previous
+			resumed = d.state.Bytes > 0
+		}
+	}
+	if !resumed {
+		d.state.Size, d.state.ModTime, d.state.Bytes = 0, 0, 0
+	}
+
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", partPath, err)
+	}
+	defer file.Close()
+
+	windows := 0
+	for first := true; first || d.state.Bytes < d.state.Size; first = false {
+		if err := d.windowWithRetries(ctx, file, statePath); err != nil {
+			return nil, err
+		}
+		windows++
+		if d.progress != nil && d.state.Size > 0 {
+			fmt.Fprintf(d.progress, "\rDownloaded %s of %s", formatSize(d.state.Bytes), formatSize(d.state.Size))
+		}
+	}
+	if d.progress != nil && d.state.Size > 0 {
+		fmt.Fprintln(d.progress)
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, output); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	_ = os.Remove(statePath)
+	return &artifactResult{Bytes: d.state.Bytes, Windows: windows, Resumed: resumed}, nil
+}
+
+// windowWithRetries downloads the next window, retrying it from the last
+// verified byte when the stream breaks or the checksum does not match
+func (d *artifactDownload) windowWithRetries(ctx context.Context, file *os.File, statePath string) error {
+	delay := artifactRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := d.window(ctx, file)
+		if err == nil {
+			return writeArtifactState(statePath, d.state)
+		}
+		if attempt == artifactRetries || !retryableArtifactError(err) || ctx.Err() != nil {
+			if s, ok := status.FromError(err); ok {
+				return fmt.Errorf("problem downloading artifact: %v", s.Message())
+			}
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Window at %s failed (%v), retrying in %s\n", formatSize(d.state.Bytes), status.Convert(err).Message(), delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// errWindowChecksum marks a window whose bytes did not match its trailer
+var errWindowChecksum = status.Error(codes.DataLoss, "window checksum mismatch")
+
+// window streams one window after the verified bytes and checks it against
+// the trailer; the partial file only counts it once it matches
+func (d *artifactDownload) window(ctx context.Context, file *os.File) error {
+	if err := file.Truncate(d.state.Bytes); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", file.Name(), err)
+	}
+	if _, err := file.Seek(d.state.Bytes, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", file.Name(), err)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := d.open(streamCtx, d.state.Bytes)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	out := io.MultiWriter(file, h)
+	received := int64(0)
+	for first := true; ; first = false {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.Offset != d.state.Bytes+received {
+			return status.Errorf(codes.DataLoss, "chunk at offset %d, expected %d", chunk.Offset, d.state.Bytes+received)
+		}
+		if first {
+			if err := d.checkUnchanged(chunk); err != nil {
+				return err
+			}
+		}
+		if _, err := out.Write(chunk.Data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name(), err)
+		}
+		received += int64(len(chunk.Data))
+	}
+
+	sums := stream.Trailer().Get(constants.ArtifactSHA256Trailer)
+	if len(sums) == 0 {
+		return fmt.Errorf("server sent no window checksum")
+	}
+	if hex.EncodeToString(h.Sum(nil)) != sums[0] {
+		return errWindowChecksum
+	}
+	if received == 0 && d.state.Bytes < d.state.Size {
+		return fmt.Errorf("server sent an empty window at %d of %d bytes", d.state.Bytes, d.state.Size)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Name(), err)
+	}
+	d.state.Bytes += received
+	return nil
+}
+
+// checkUnchanged records the file's size and modification time from the
+// first chunk of the first window and rejects later windows of a file that
+// changed since
+func (d *artifactDownload) checkUnchanged(chunk *artifactspb.ArtifactChunk) error {
+	if d.state.Size == 0 && d.state.ModTime == 0 {
+		d.state.Size, d.state.ModTime = chunk.Size, chunk.ModTime
+		return nil
+	}
+	if chunk.Size != d.state.Size || chunk.ModTime != d.state.ModTime {
+		return fmt.Errorf("%s changed on the server since the download started, run again with --restart", d.state.Path)
+	}
+	return nil
+}
+
+// retryableArtifactError reports whether a failed window is worth retrying
+func retryableArtifactError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+func readArtifactState(path string) (artifactState, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return artifactState{}, false
+	}
+	var state artifactState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return artifactState{}, false
+	}
+	return state, true
+}
+
+func writeArtifactState(path string, state artifactState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	artifactspb "github.com/ehsaniara/joblet/internal/proto/gen/artifacts"
+	"github.com/ehsaniara/joblet/pkg/constants"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeArtifactServer serves windows of content the way the artifact service
+// does, optionally corrupting or breaking the first attempt at an offset
+type fakeArtifactServer struct {
+	content   []byte
+	window    int64
+	modTime   int64
+	corruptAt int64 // Flip a byte in the window at this offset once (-1 = never)
+	breakAt   int64 // Break the stream of the window at this offset once (-1 = never)
+	calls     []int64
+}
+
+type fakeArtifactStream struct {
+	chunks  []*artifactspb.ArtifactChunk
+	trailer metadata.MD
+	broken  bool
+}
+
+func (f *fakeArtifactServer) open(_ context.Context, offset int64) (artifactChunkStream, error) {
+	f.calls = append(f.calls, offset)
+	end := min(offset+f.window, int64(len(f.content)))
+	data := append([]byte{}, f.content[offset:end]...)
+	sum := sha256.Sum256(data)
+	stream := &fakeArtifactStream{trailer: metadata.Pairs(constants.ArtifactSHA256Trailer, hex.EncodeToString(sum[:]))}
+	if offset == f.corruptAt && len(data) > 0 {
+		data[len(data)/2] ^= 0xff
+		f.corruptAt = -1
+	}
+	if offset == f.breakAt {
+		stream.broken = true
+		f.breakAt = -1
+	}
+	half := len(data) / 2
+	stream.chunks = []*artifactspb.ArtifactChunk{
+		{Offset: offset, Data: data[:half], Size: int64(len(f.content)), ModTime: f.modTime},
+		{Offset: offset + int64(half), Data: data[half:]},
+	}
+	return stream, nil
+}
+
+func (s *fakeArtifactStream) Recv() (*artifactspb.ArtifactChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	if s.broken && len(s.chunks) == 1 {
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *fakeArtifactStream) Trailer() metadata.MD { return s.trailer }
+
+func testArtifact(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	return content
+}
+
+func TestArtifactDownload(t *testing.T) {
+	artifactRetryDelay = 0
+	content := testArtifact(10_000)
+	server := &fakeArtifactServer{content: content, window: 4096, modTime: 1, corruptAt: 4096, breakAt: 8192}
+	output := filepath.Join(t.TempDir(), "model.bin")
+
+	d := &artifactDownload{open: server.open, state: artifactState{Volume: "models", Path: "model.bin"}}
+	result, err := d.run(context.Background(), output, false)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	if !bytes.Equal(got, content) || result.Bytes != int64(len(content)) || result.Windows != 3 {
+		t.Errorf("downloaded %d bytes in %d windows, want %d in 3", len(got), result.Windows, len(content))
+	}
+	// The corrupted and the broken window are each fetched again
+	if want := []int64{0, 4096, 4096, 8192, 8192}; !slices.Equal(server.calls, want) {
+		t.Errorf("windows requested at %v, want %v", server.calls, want)
+	}
+	if _, err := os.Stat(output + ".part.json"); !os.IsNotExist(err) {
+		t.Error("state file left behind after a complete download")
+	}
+}
+
+func TestArtifactDownload_Resume(t *testing.T) {
+	content := testArtifact(10_000)
+	output := filepath.Join(t.TempDir(), "model.bin")
+	state := artifactState{Volume: "models", Path: "model.bin", Size: int64(len(content)), ModTime: 1, Bytes: 4096}
+	if err := os.WriteFile(output+".part", append(content[:4096:4096], "unverified"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeArtifactState(output+".part.json", state); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fakeArtifactServer{content: content, window: 4096, modTime: 1, corruptAt: -1, breakAt: -1}
+	d := &artifactDownload{open: server.open, state: artifactState{Volume: "models", Path: "model.bin"}}
+	result, err := d.run(context.Background(), output, false)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	got, _ := os.ReadFile(output)
+	if !bytes.Equal(got, content) || !result.Resumed || !slices.Equal(server.calls, []int64{4096, 8192}) {
+		t.Errorf("resumed download: %d bytes, resumed %v, windows at %v", len(got), result.Resumed, server.calls)
+	}
+
+	// A file changed on the server since cannot be resumed
+	if err := os.WriteFile(output+".part", content[:4096], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeArtifactState(output+".part.json", state); err != nil {
+		t.Fatal(err)
+	}
+	server = &fakeArtifactServer{content: content, window: 4096, modTime: 2, corruptAt: -1, breakAt: -1}
+	d = &artifactDownload{open: server.open, state: artifactState{Volume: "models", Path: "model.bin"}}
+	if _, err := d.run(context.Background(), output, false); err == nil {
+		t.Error("resuming a changed file succeeded")
+	}
+	d = &artifactDownload{open: server.open, state: artifactState{Volume: "models", Path: "model.bin"}}
+	if _, err := d.run(context.Background(), output, true); err != nil {
+		t.Errorf("run() with restart error = %v", err)
+	}
+}
+
+func TestArtifactDownload_EmptyFile(t *testing.T) {
+	server := &fakeArtifactServer{window: 4096, modTime: 1, corruptAt: -1, breakAt: -1}
+	output := filepath.Join(t.TempDir(), "empty")
+	d := &artifactDownload{open: server.open, state: artifactState{Volume: "models", Path: "empty"}}
+	result, err := d.run(context.Background(), output, false)
+	if err != nil || result.Bytes != 0 || result.Windows != 1 {
+		t.Fatalf("run() = %+v, %v", result, err)
+	}
+	if info, err := os.Stat(output); err != nil || info.Size() != 0 {
+		t.Errorf("empty file not written: %v", err)
+	}
+}
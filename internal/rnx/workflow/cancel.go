@@ -0,0 +1,53 @@
+package workflow
+
+import (
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowCancelCmd creates the command canceling part of a running workflow
+func NewWorkflowCancelCmd() *cobra.Command {
+	var (
+		job     string
+		cascade bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel <workflow-uuid> --job <name>",
+		Short: "Cancel a job of a running workflow and its dependents",
+		Long: `Cancel one job of a running workflow while the rest of the workflow carries on.
+
+The job is stopped if it is running and canceled if it is scheduled or has not
+started yet. Without --cascade, the jobs requiring it then follow their own
+requirements: a job waiting for it to complete is canceled, a job waiting for
+it to fail or stop may still run. With --cascade, every job that depends on
+it, directly or through other jobs, is canceled too, whatever status it
+requires. Jobs outside that sub-tree keep running and the workflow ends as
+CANCELED once they are done.
+
+UUID supports short-form (first 8 characters) if unique.
+
+Examples:
+  # Cancel training and everything downstream of it, keep the docs branch
+  rnx workflow cancel 386148ef --job train-model --cascade
+
+  # Stop a single job and let its dependents decide
+  rnx workflow cancel 386148ef --job slow-report`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: jobs.CompleteWorkflowUUIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowUUID, err := jobs.ResolveWorkflowUUID(args[0])
+			if err != nil {
+				return err
+			}
+			return jobs.CancelWorkflowJobs(workflowUUID, job, cascade)
+		},
+	}
+
+	cmd.Flags().StringVar(&job, "job", "", "Name of the workflow job to cancel (required)")
+	cmd.Flags().BoolVar(&cascade, "cascade", false, "Also cancel every job that depends on it")
+	_ = cmd.MarkFlagRequired("job")
+
+	return cmd
+}
@@ -21,15 +21,19 @@ UUID supports short-form (first 8 characters) if unique.
 Examples:
   rnx workflow delete 386148ef                    # Delete workflow (short UUID)
   rnx workflow delete 386148ef-e591-461a-a823     # Delete workflow (full UUID)`,
-		Args: cobra.ExactArgs(1),
-		RunE: deleteWorkflow,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: jobs.CompleteWorkflowUUIDs,
+		RunE:              deleteWorkflow,
 	}
 
 	return cmd
 }
 
 func deleteWorkflow(cmd *cobra.Command, args []string) error {
-	workflowUUID := args[0]
+	workflowUUID, err := jobs.ResolveWorkflowUUID(args[0])
+	if err != nil {
+		return err
+	}
 
 	// Reuse existing workflow delete logic from jobs package
 	return jobs.DeleteWorkflow(workflowUUID)
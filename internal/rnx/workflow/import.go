@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/rnx/workflows/importer"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowImportCmd creates the workflow import command
+func NewWorkflowImportCmd() *cobra.Command {
+	var (
+		from   string
+		output string
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Convert a pipeline from another tool into a workflow YAML",
+		Long: `Convert a pipeline definition from another tool into a joblet workflow YAML.
+
+Supported formats:
+  github-actions   GitHub Actions workflow; each job becomes one joblet job
+                   running its 'run' steps, 'needs' become requirements
+  airflow          Airflow DAG file; BashOperator tasks and >>, <<,
+                   set_upstream/set_downstream dependencies
+  makefile         Makefile; each explicit target becomes a job and target
+                   prerequisites become requirements
+
+Conversion is best effort. Anything without a joblet equivalent (actions,
+non-bash operators, pattern rules, templating) is left out and listed as a
+WARNING comment at the top of the generated file. Review the result, add
+runtimes and uploads as needed, then run it with 'rnx workflow run'.
+
+Examples:
+  rnx workflow import --from github-actions .github/workflows/ci.yml
+  rnx workflow import --from airflow dags/etl.py -o etl.yaml
+  rnx workflow import --from makefile Makefile -o build.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+
+			result, err := importer.Convert(from, data)
+			if err != nil {
+				return err
+			}
+			if result.Workflow.Name == "" {
+				result.Workflow.Name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			}
+
+			rendered, err := importer.Render(result, filepath.Base(args[0]))
+			if err != nil {
+				return fmt.Errorf("failed to render workflow: %w", err)
+			}
+
+			if output == "" || output == "-" {
+				_, err = os.Stdout.Write(rendered)
+				return err
+			}
+
+			if _, err := os.Stat(output); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+			}
+			if err := os.WriteFile(output, rendered, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+
+			fmt.Printf("Wrote %d job(s) to %s\n", len(result.Workflow.Jobs), output)
+			if len(result.Warnings) > 0 {
+				fmt.Printf("%d warning(s), see the comments at the top of the file\n", len(result.Warnings))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Source format: "+strings.Join(importer.Formats(), ", "))
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the workflow to a file instead of stdout")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite the output file if it exists")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ehsaniara/joblet/internal/rnx/workflows/scaffold"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowInitCmd creates the workflow init command
+func NewWorkflowInitCmd() *cobra.Command {
+	var (
+		output string
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a workflow YAML by answering a few questions",
+		Long: `Create a new workflow interactively.
+
+The wizard asks for the workflow name and then for each job: its command,
+runtime, the jobs it runs after, the files it needs uploaded and optional
+memory and CPU limits. Leave the job name empty to finish.
+
+The generated file is validated before it is written. Upload files that do
+not exist yet are created next to it as placeholders, so the example runs
+as-is with 'rnx workflow run'. Existing files are never overwritten.
+
+Examples:
+  rnx workflow init                        # Write workflow.yaml
+  rnx workflow init -o pipeline.yaml       # Choose the file name
+  rnx workflow init -o etl/workflow.yaml   # Scaffold into a new directory`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(output); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+			}
+
+			wf, err := scaffold.NewWizard(cmd.InOrStdin(), cmd.OutOrStdout()).Run()
+			if err != nil {
+				return err
+			}
+
+			rendered, err := scaffold.Render(wf)
+			if err != nil {
+				return fmt.Errorf("failed to render workflow: %w", err)
+			}
+			if err := scaffold.Validate(rendered); err != nil {
+				return fmt.Errorf("generated workflow is invalid: %w", err)
+			}
+
+			dir := filepath.Dir(output)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+			if err := os.WriteFile(output, rendered, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			created, err := scaffold.WriteUploadStubs(dir, wf)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "\nWrote %d job(s) to %s", len(wf.Jobs), output)
+			if len(created) > 0 {
+				fmt.Fprintf(out, " and %d placeholder upload file(s)", len(created))
+			}
+			fmt.Fprintf(out, "\n\n%s\nNext: rnx workflow run %s\n", scaffold.Layout(output, wf), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "workflow.yaml", "File to write the workflow to")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite the output file if it exists")
+
+	return cmd
+}
@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowRegisterCmd creates the workflow register command
+func NewWorkflowRegisterCmd() *cobra.Command {
+	var name, schedule, description string
+
+	cmd := &cobra.Command{
+		Use:   "register <workflow-file>",
+		Short: "Store a workflow on the server to start it by name",
+		Long: `Store a workflow and the files its jobs upload on the server, so it can be
+started by name without the YAML on the client.
+
+Registering a name again adds a new version; 'rnx workflow start' runs the
+latest version unless one is given. With --schedule the server starts the
+workflow on a cron schedule, in the server's time zone.
+
+Examples:
+  rnx workflow register pipeline.yaml --name nightly-etl
+  rnx workflow register pipeline.yaml --name nightly-etl --schedule "0 2 * * *"
+  rnx workflow register report.yaml --schedule "@every 6h"   # Registered as "report"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(args[0]); os.IsNotExist(err) {
+				return fmt.Errorf("workflow file not found: %s", args[0])
+			}
+			return jobs.RegisterWorkflow(args[0], name, schedule, description)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name to register the workflow under (default: file name without extension)")
+	cmd.Flags().StringVar(&schedule, "schedule", "", "Cron schedule to start the workflow on (e.g. \"0 2 * * *\", \"@daily\", \"@every 6h\")")
+	cmd.Flags().StringVar(&description, "description", "", "Description shown in 'rnx workflow registry' (default: the workflow's description)")
+
+	return cmd
+}
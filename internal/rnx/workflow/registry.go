@@ -0,0 +1,53 @@
+package workflow
+
+import (
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowRegistryCmd creates the workflow registry command
+func NewWorkflowRegistryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "List, show and remove registered workflows",
+		Long: `Manage the workflows stored on the server with 'rnx workflow register'.
+
+Examples:
+  rnx workflow registry                      # List registered workflows
+  rnx workflow registry show nightly-etl     # Show the latest version's YAML
+  rnx workflow registry show nightly-etl@2   # Show version 2
+  rnx workflow registry remove nightly-etl   # Remove all versions`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.ListRegisteredWorkflows()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List registered workflows",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.ListRegisteredWorkflows()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show <name>[@version]",
+		Short: "Show a registered workflow's YAML",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.ShowRegisteredWorkflow(args[0])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a registered workflow and all its versions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.RemoveRegisteredWorkflow(args[0])
+		},
+	})
+
+	return cmd
+}
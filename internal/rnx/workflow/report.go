@@ -0,0 +1,56 @@
+package workflow
+
+import (
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowReportCmd creates the workflow report command
+func NewWorkflowReportCmd() *cobra.Command {
+	var (
+		format   string
+		output   string
+		logLines int32
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report <workflow-uuid>",
+		Short: "Export a report of a workflow run",
+		Long: `Export a report of a workflow run, rendered by the server from the
+workflow's history and the metrics and logs stored by persist.
+
+The report lists every job with its status, exit code, dependencies, duration
+and resource usage (average and peak CPU, peak memory, disk and network I/O),
+and quotes the last log lines of each failed job.
+
+Formats:
+  html    A standalone page to read or archive
+  json    Machine-readable, for scripts and dashboards
+  junit   JUnit XML: the workflow is a test suite and each job a test case,
+          failed jobs fail with their log excerpt. CI systems (GitLab, Jenkins,
+          GitHub Actions test reporters) show it as test results.
+
+UUID supports short-form (first 8 characters) if unique.
+
+Examples:
+  rnx workflow report 386148ef -o report.html
+  rnx workflow report 386148ef --format json | jq '.jobs[] | select(.status=="FAILED")'
+  rnx workflow report 386148ef --format junit -o junit.xml --log-lines 200`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: jobs.CompleteWorkflowUUIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowUUID, err := jobs.ResolveWorkflowUUID(args[0])
+			if err != nil {
+				return err
+			}
+			return jobs.WorkflowReport(workflowUUID, format, output, logLines)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "html", "Report format: html, json or junit")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the report to (default stdout)")
+	cmd.Flags().Int32Var(&logLines, "log-lines", 0, "Log lines quoted per failed job (default 50, max 1000)")
+
+	return cmd
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/ehsaniara/joblet/internal/rnx/common"
 	"github.com/ehsaniara/joblet/internal/rnx/jobs"
 
 	"github.com/spf13/cobra"
@@ -22,14 +23,28 @@ complex pipelines for data processing, ML training, and automated tasks.
 
 The workflow file must be a valid YAML file defining jobs and their dependencies.
 
+Run from a CI pipeline (GitHub Actions, GitLab CI, Jenkins, CircleCI or
+Buildkite), every job of the workflow is annotated with the pipeline, run ID,
+URL and commit SHA, unless --no-ci-annotations or RNX_NO_CI_ANNOTATIONS=1 is
+given.
+
+With --dry-run the workflow is validated and analyzed but not started: rnx
+prints its stages with their aggregate CPU, memory and GPU needs, the critical
+path from the jobs' "estimate" fields, and warnings for jobs or stages that do
+not fit the node's capacity.
+
 Examples:
   rnx workflow run pipeline.yaml                    # Run workflow from current directory
   rnx workflow run examples/ml-pipeline.yaml        # Run workflow from path
-  rnx workflow run /path/to/workflow.yaml           # Run workflow with absolute path`,
+  rnx workflow run /path/to/workflow.yaml           # Run workflow with absolute path
+  rnx workflow run pipeline.yaml --dry-run          # Show stages, critical path and capacity warnings`,
 		Args: cobra.ExactArgs(1),
 		RunE: runWorkflow,
 	}
 
+	cmd.Flags().Bool("dry-run", false, "Analyze the workflow without running it")
+	cmd.Flags().BoolVar(&common.NoCIAnnotations, "no-ci-annotations", false, "Do not annotate the jobs with the CI pipeline rnx runs in")
+
 	return cmd
 }
 
@@ -47,6 +62,10 @@ func runWorkflow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return jobs.PlanWorkflow(absPath)
+	}
+
 	// Reuse existing workflow execution logic from jobs package
 	// This calls the same backend implementation
 	return jobs.ExecuteWorkflow(absPath)
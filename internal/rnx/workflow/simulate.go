@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow"
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowSimulateCmd creates the command simulating a workflow's schedule
+func NewWorkflowSimulateCmd() *cobra.Command {
+	var (
+		durations string
+		opts      workflow.SimulationOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate <workflow-file> [--durations durations.json]",
+		Short: "Simulate a workflow's schedule against synthetic job durations",
+		Long: `Simulate how a workflow would be scheduled, without a server and without
+running anything, to tune the parallelism of large pipelines.
+
+The simulation applies the workflow's requirements, stages, schedule,
+not_before and window fields as the server does, runs at most --max-parallel
+jobs at once and relaunches failed launches --infra-retries times, then
+prints each job's start, end, time spent waiting for a running slot and a
+timeline.
+
+The durations file maps job names to how long they run, either as a duration
+or as an object that also sets the job's outcome and how many of its
+launches fail on the node's side:
+
+  {
+    "fetch": "10m",
+    "train": {"duration": "1h", "status": "FAILED"},
+    "upload": {"duration": "2m", "infra_failures": 1}
+  }
+
+Jobs it does not name run for their "estimate", or not at all without one.
+
+Examples:
+  rnx workflow simulate pipeline.yaml --durations durations.json
+  rnx workflow simulate pipeline.yaml --durations durations.json --max-parallel 4
+  rnx workflow simulate pipeline.yaml --max-parallel 8 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.SimulateWorkflow(args[0], durations, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&durations, "durations", "", "JSON file of synthetic job durations (default: the jobs' estimates)")
+	cmd.Flags().IntVar(&opts.MaxParallel, "max-parallel", 0, "Most jobs running at once (0 = no limit)")
+	cmd.Flags().IntVar(&opts.InfraRetries, "infra-retries", 2, "Relaunches of a job whose launch failed on the node's side")
+	cmd.Flags().DurationVar(&opts.InfraRetryDelay, "infra-retry-delay", 2*time.Second, "Delay between relaunches")
+
+	return cmd
+}
@@ -0,0 +1,28 @@
+package workflow
+
+import (
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowStartCmd creates the workflow start command
+func NewWorkflowStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start <name>[@version]",
+		Short: "Start a registered workflow",
+		Long: `Start a run of a workflow stored with 'rnx workflow register'.
+
+The latest version runs unless a version is given.
+
+Examples:
+  rnx workflow start nightly-etl         # Run the latest version
+  rnx workflow start nightly-etl@3       # Run version 3`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobs.StartRegisteredWorkflow(args[0])
+		},
+	}
+
+	return cmd
+}
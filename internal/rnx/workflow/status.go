@@ -1,12 +1,18 @@
 package workflow
 
 import (
+	"time"
+
 	"github.com/ehsaniara/joblet/internal/rnx/jobs"
 
 	"github.com/spf13/cobra"
 )
 
-var detailFlag bool
+var (
+	detailFlag    bool
+	watchFlag     bool
+	watchInterval time.Duration
+)
 
 // NewWorkflowStatusCmd creates the workflow status command
 func NewWorkflowStatusCmd() *cobra.Command {
@@ -18,24 +24,40 @@ func NewWorkflowStatusCmd() *cobra.Command {
 Displays workflow execution status, timing information, and the status of each
 job within the workflow along with their dependencies.
 
+With --watch the view redraws until the workflow finishes: jobs grouped in
+stages with a tree of their dependencies, how long each job ran or has been
+running, and an ETA from the durations of earlier completed runs of the same
+job names.
+
 UUID supports short-form (first 8 characters) if unique.
 
 Examples:
   rnx workflow status 386148ef                    # Short UUID
   rnx workflow status 386148ef-e591-461a-a823     # Full UUID
   rnx workflow status 386148ef --detail           # Include YAML content
-  rnx workflow status 386148ef --json             # JSON output`,
-		Args: cobra.ExactArgs(1),
-		RunE: getWorkflowStatus,
+  rnx workflow status 386148ef --json             # JSON output
+  rnx workflow status 386148ef --watch            # Live progress view with ETA`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: jobs.CompleteWorkflowUUIDs,
+		RunE:              getWorkflowStatus,
 	}
 
 	cmd.Flags().BoolVarP(&detailFlag, "detail", "d", false, "Show YAML content when displaying workflow status")
+	cmd.Flags().BoolVarP(&watchFlag, "watch", "w", false, "Redraw a progress view until the workflow finishes")
+	cmd.Flags().DurationVar(&watchInterval, "interval", 2*time.Second, "Time between refreshes with --watch")
 
 	return cmd
 }
 
 func getWorkflowStatus(cmd *cobra.Command, args []string) error {
-	workflowUUID := args[0]
+	workflowUUID, err := jobs.ResolveWorkflowUUID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if watchFlag {
+		return jobs.WatchWorkflowStatus(workflowUUID, watchInterval)
+	}
 
 	// Reuse existing workflow status logic from jobs package
 	return jobs.GetWorkflowStatus(workflowUUID, detailFlag)
@@ -0,0 +1,37 @@
+package workflow
+
+import (
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowStopCmd creates the command canceling a whole running workflow
+func NewWorkflowStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop <workflow-uuid>",
+		Short: "Stop a running workflow",
+		Long: `Stop a running workflow: no new job of it starts, its running and scheduled
+jobs are stopped and the jobs that have not started are canceled. The
+workflow ends as CANCELED.
+
+To stop only part of a workflow, use 'rnx workflow cancel --job'.
+
+UUID supports short-form (first 8 characters) if unique.
+
+Examples:
+  rnx workflow stop 386148ef
+  rnx workflow stop 386148ef --json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: jobs.CompleteWorkflowUUIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowUUID, err := jobs.ResolveWorkflowUUID(args[0])
+			if err != nil {
+				return err
+			}
+			return jobs.StopWorkflow(workflowUUID)
+		},
+	}
+
+	return cmd
+}
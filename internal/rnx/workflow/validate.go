@@ -0,0 +1,36 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ehsaniara/joblet/internal/rnx/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorkflowValidateCmd creates the command checking a workflow on the server
+func NewWorkflowValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate <workflow-file>",
+		Short: "Check a workflow on the server without running it",
+		Long: `Run every check the server makes before running a workflow and list all the
+problems at once: missing volumes and runtimes, unknown or circular
+dependencies, invalid environment variables, schedules, labels, cgroup
+parameters and outputs, and uploads not found next to the workflow file.
+
+Each problem has a machine-readable code, e.g. missing-volume, shown in
+brackets and in the --json output. The command fails when any check does.
+
+Examples:
+  rnx workflow validate pipeline.yaml
+  rnx --json workflow validate pipeline.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(args[0]); os.IsNotExist(err) {
+				return fmt.Errorf("workflow file not found: %s", args[0])
+			}
+			return jobs.ValidateWorkflow(args[0])
+		},
+	}
+}
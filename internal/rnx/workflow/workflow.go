@@ -16,15 +16,34 @@ complex data pipelines, ML training workflows, and automated task sequences.
 
 Examples:
   rnx workflow run pipeline.yaml           # Run a workflow
+  rnx workflow validate pipeline.yaml      # List every problem without running it
+  rnx workflow register pipeline.yaml --name nightly-etl   # Store it on the server
+  rnx workflow start nightly-etl           # Run a registered workflow by name
   rnx workflow list                        # List all workflows
-  rnx workflow status <uuid>               # Check workflow status`,
+  rnx workflow status <uuid>               # Check workflow status
+  rnx workflow cancel <uuid> --job train --cascade   # Cancel a job and its dependents
+  rnx workflow stop <uuid>                 # Stop the whole workflow
+  rnx workflow simulate pipeline.yaml --durations d.json   # Simulate its schedule
+  rnx workflow report <uuid> --format junit -o junit.xml   # Export a run report
+  rnx workflow init                        # Create a workflow step by step
+  rnx workflow import --from makefile Makefile   # Convert another tool's pipeline`,
 		DisableFlagsInUseLine: true,
 	}
 
 	// Add subcommands
 	workflowCmd.AddCommand(NewWorkflowRunCmd())
+	workflowCmd.AddCommand(NewWorkflowValidateCmd())
 	workflowCmd.AddCommand(NewWorkflowListCmd())
 	workflowCmd.AddCommand(NewWorkflowStatusCmd())
+	workflowCmd.AddCommand(NewWorkflowInitCmd())
+	workflowCmd.AddCommand(NewWorkflowImportCmd())
+	workflowCmd.AddCommand(NewWorkflowRegisterCmd())
+	workflowCmd.AddCommand(NewWorkflowStartCmd())
+	workflowCmd.AddCommand(NewWorkflowRegistryCmd())
+	workflowCmd.AddCommand(NewWorkflowReportCmd())
+	workflowCmd.AddCommand(NewWorkflowCancelCmd())
+	workflowCmd.AddCommand(NewWorkflowStopCmd())
+	workflowCmd.AddCommand(NewWorkflowSimulateCmd())
 
 	return workflowCmd
 }
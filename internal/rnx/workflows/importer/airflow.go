@@ -0,0 +1,236 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+// Airflow DAGs are Python, so the converter only recognizes the common static
+// patterns: BashOperator assignments and >>/<< or set_upstream/set_downstream
+// dependencies between task variables.
+var (
+	airflowDagID = regexp.MustCompile(`(?:DAG\(\s*|dag_id\s*=\s*)["']([^"']+)["']`)
+	// `name = SomeOperator(` starts a task definition
+	airflowTask = regexp.MustCompile(`(?m)^\s*(\w+)\s*=\s*(\w+Operator|\w+Sensor)\s*\(`)
+	// keyword="value" or keyword='''value''' inside an operator call
+	airflowKwarg  = regexp.MustCompile(`(?s)(\w+)\s*=\s*[rf]?("""(.*?)"""|'''(.*?)'''|"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)')`)
+	airflowEnvArg = regexp.MustCompile(`\benv\s*=\s*\{`)
+	airflowEnv    = regexp.MustCompile(`["'](\w+)["']\s*:\s*["']([^"']*)["']`)
+	// Dependency chains made of task variables and [a, b] lists
+	airflowChain    = regexp.MustCompile(`(?m)^\s*((?:\[[\w\s,]+\]|\w+)(?:\s*(?:>>|<<)\s*(?:\[[\w\s,]+\]|\w+))+)\s*$`)
+	airflowSetRel   = regexp.MustCompile(`(\w+)\.set_(upstream|downstream)\(\s*(\[[\w\s,]+\]|\w+)\s*\)`)
+	airflowTemplate = regexp.MustCompile(`\{\{.*?\}\}`)
+	airflowShift    = regexp.MustCompile(`>>|<<`)
+)
+
+type airflowTaskDef struct {
+	variable string
+	id       string
+	operator string
+	command  string
+	env      map[string]string
+}
+
+// convertAirflow maps BashOperator tasks to shell jobs. Other operators are
+// reported and left out, and dependencies on them are dropped.
+func convertAirflow(data []byte) (*Result, error) {
+	source := string(data)
+	result := &Result{Workflow: &types.WorkflowYAML{Jobs: make(map[string]types.JobSpec)}}
+
+	if m := airflowDagID.FindStringSubmatch(source); m != nil {
+		result.Workflow.Name = m[1]
+	}
+
+	tasks := make(map[string]*airflowTaskDef)
+	for _, loc := range airflowTask.FindAllStringSubmatchIndex(source, -1) {
+		task := &airflowTaskDef{
+			variable: source[loc[2]:loc[3]],
+			operator: source[loc[4]:loc[5]],
+		}
+		args, ok := callArguments(source[loc[1]:])
+		if !ok {
+			return nil, fmt.Errorf("unterminated %s call for %s", task.operator, task.variable)
+		}
+		for _, kw := range airflowKwarg.FindAllStringSubmatch(args, -1) {
+			value := firstNonEmpty(kw[3], kw[4], unescapePython(kw[5]), unescapePython(kw[6]))
+			switch kw[1] {
+			case "task_id":
+				task.id = value
+			case "bash_command":
+				task.command = value
+			}
+		}
+		if envArgs := envArgument(args); envArgs != "" {
+			task.env = make(map[string]string)
+			for _, m := range airflowEnv.FindAllStringSubmatch(envArgs, -1) {
+				task.env[m[1]] = m[2]
+			}
+		}
+		if task.id == "" {
+			task.id = task.variable
+		}
+		tasks[task.variable] = task
+	}
+
+	upstream := make(map[string][]string)
+	addEdge := func(from, to string) {
+		upstream[to] = append(upstream[to], from)
+	}
+	for _, m := range airflowChain.FindAllStringSubmatch(source, -1) {
+		parseChain(m[1], addEdge)
+	}
+	for _, m := range airflowSetRel.FindAllStringSubmatch(source, -1) {
+		for _, other := range taskGroup(m[3]) {
+			if m[2] == "downstream" {
+				addEdge(m[1], other)
+			} else {
+				addEdge(other, m[1])
+			}
+		}
+	}
+
+	variables := make([]string, 0, len(tasks))
+	for variable := range tasks {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+
+	converted := make(map[string]bool)
+	for _, variable := range variables {
+		task := tasks[variable]
+		if task.operator != "BashOperator" || task.command == "" {
+			result.warnf("task %s: %s has no joblet equivalent and was skipped", task.id, task.operator)
+			continue
+		}
+		converted[variable] = true
+	}
+
+	for _, variable := range variables {
+		if !converted[variable] {
+			continue
+		}
+		task := tasks[variable]
+		name := jobName(task.id)
+
+		spec := shellJob(task.command)
+		if len(task.env) > 0 {
+			spec.Environment = task.env
+		}
+		if airflowTemplate.MatchString(task.command) {
+			result.warnf("job %s: Jinja templates are copied verbatim and must be replaced", name)
+		}
+
+		var deps []string
+		seen := make(map[string]bool)
+		for _, dep := range upstream[variable] {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if !converted[dep] {
+				if t, ok := tasks[dep]; ok {
+					result.warnf("job %s: dependency on skipped task %s was dropped", name, t.id)
+				}
+				continue
+			}
+			deps = append(deps, jobName(tasks[dep].id))
+		}
+		sort.Strings(deps)
+		spec.Requires = requiresCompleted(deps)
+
+		result.Workflow.Jobs[name] = spec
+	}
+
+	return result, nil
+}
+
+// callArguments returns the text up to the parenthesis closing the call whose
+// opening parenthesis precedes s.
+func callArguments(s string) (string, bool) {
+	depth := 1
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			if depth == 0 {
+				return s[:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// envArgument returns the dict literal passed as env=, if any.
+func envArgument(args string) string {
+	idx := airflowEnvArg.FindStringIndex(args)
+	if idx == nil {
+		return ""
+	}
+	end := strings.Index(args[idx[1]:], "}")
+	if end < 0 {
+		return ""
+	}
+	return args[idx[1] : idx[1]+end]
+}
+
+// parseChain walks `a >> [b, c] >> d` style expressions, calling addEdge for
+// every upstream/downstream pair.
+func parseChain(chain string, addEdge func(from, to string)) {
+	tokens := airflowShift.Split(chain, -1)
+	operators := airflowShift.FindAllString(chain, -1)
+	for i, op := range operators {
+		left, right := taskGroup(tokens[i]), taskGroup(tokens[i+1])
+		for _, l := range left {
+			for _, r := range right {
+				if op == ">>" {
+					addEdge(l, r)
+				} else {
+					addEdge(r, l)
+				}
+			}
+		}
+	}
+}
+
+func taskGroup(token string) []string {
+	token = strings.Trim(strings.TrimSpace(token), "[]")
+	var names []string
+	for _, part := range strings.Split(token, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+// pythonEscapes covers the escapes that show up in shell commands.
+var pythonEscapes = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`, `\n`, "\n", `\t`, "\t")
+
+func unescapePython(s string) string {
+	return pythonEscapes.Replace(s)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ghaWorkflow is the subset of a GitHub Actions workflow the importer reads.
+type ghaWorkflow struct {
+	Name string            `yaml:"name"`
+	Env  map[string]string `yaml:"env"`
+	Jobs map[string]ghaJob `yaml:"jobs"`
+}
+
+type ghaJob struct {
+	Name      string            `yaml:"name"`
+	Needs     stringList        `yaml:"needs"`
+	Env       map[string]string `yaml:"env"`
+	Steps     []ghaStep         `yaml:"steps"`
+	Strategy  yaml.Node         `yaml:"strategy"`
+	Container yaml.Node         `yaml:"container"`
+	Services  yaml.Node         `yaml:"services"`
+	If        string            `yaml:"if"`
+	Defaults  struct {
+		Run struct {
+			WorkingDirectory string `yaml:"working-directory"`
+		} `yaml:"run"`
+	} `yaml:"defaults"`
+}
+
+type ghaStep struct {
+	Name             string            `yaml:"name"`
+	Run              string            `yaml:"run"`
+	Uses             string            `yaml:"uses"`
+	With             map[string]string `yaml:"with"`
+	Env              map[string]string `yaml:"env"`
+	If               string            `yaml:"if"`
+	WorkingDirectory string            `yaml:"working-directory"`
+}
+
+// stringList accepts either a single string or a list of strings, as `needs`
+// does.
+type stringList []string
+
+func (s *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = []string{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+var ghaExpression = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
+// setupRuntimes maps setup actions to the runtime family a joblet job would use.
+var setupRuntimes = map[string]string{
+	"actions/setup-python": "python",
+	"actions/setup-node":   "nodejs",
+	"actions/setup-java":   "openjdk",
+	"actions/setup-go":     "go",
+}
+
+// convertGitHubActions maps each GitHub Actions job to one joblet job whose
+// `run` steps execute in order in a single bash script. `needs` become
+// COMPLETED requirements and env is merged from workflow, job and step level.
+func convertGitHubActions(data []byte) (*Result, error) {
+	var source ghaWorkflow
+	if err := yaml.Unmarshal(data, &source); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Workflow: &types.WorkflowYAML{
+		Name: source.Name,
+		Jobs: make(map[string]types.JobSpec, len(source.Jobs)),
+	}}
+
+	ids := make([]string, 0, len(source.Jobs))
+	for id := range source.Jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		job := source.Jobs[id]
+		name := jobName(id)
+
+		if !job.Strategy.IsZero() {
+			result.warnf("job %s: matrix strategy is not supported; only one variant is generated", name)
+		}
+		if !job.Container.IsZero() || !job.Services.IsZero() {
+			result.warnf("job %s: container and services are ignored; use a joblet runtime instead", name)
+		}
+		if job.If != "" {
+			result.warnf("job %s: condition %q is ignored", name, job.If)
+		}
+
+		var script strings.Builder
+		if dir := job.Defaults.Run.WorkingDirectory; dir != "" {
+			fmt.Fprintf(&script, "cd %s\n", shellQuote(dir))
+		}
+
+		runSteps := 0
+		for i, step := range job.Steps {
+			label := step.Name
+			if label == "" {
+				label = fmt.Sprintf("step %d", i+1)
+			}
+
+			if step.Uses != "" {
+				action := strings.SplitN(step.Uses, "@", 2)[0]
+				if family, ok := setupRuntimes[action]; ok {
+					result.warnf("job %s: %s was skipped; set a %s runtime instead (see 'rnx runtime list')", name, step.Uses, family)
+				} else if action != "actions/checkout" {
+					result.warnf("job %s: action %s in %q has no equivalent and was skipped", name, step.Uses, label)
+				}
+				continue
+			}
+			if step.Run == "" {
+				continue
+			}
+			if step.If != "" {
+				result.warnf("job %s: condition on %q is ignored", name, label)
+			}
+
+			runSteps++
+			fmt.Fprintf(&script, "# %s\n", label)
+			// Step env and working directory apply to that step only
+			scoped := len(step.Env) > 0 || step.WorkingDirectory != ""
+			if scoped {
+				script.WriteString("(\n")
+				for _, key := range sortedKeys(step.Env) {
+					fmt.Fprintf(&script, "export %s=%s\n", key, shellQuote(step.Env[key]))
+				}
+				if step.WorkingDirectory != "" {
+					fmt.Fprintf(&script, "cd %s\n", shellQuote(step.WorkingDirectory))
+				}
+			}
+			script.WriteString(strings.TrimRight(step.Run, "\n"))
+			script.WriteString("\n")
+			if scoped {
+				script.WriteString(")\n")
+			}
+		}
+
+		if runSteps == 0 {
+			result.warnf("job %s: no run steps; generated a no-op job", name)
+			script.WriteString("true\n")
+		}
+
+		spec := shellJob(script.String())
+		spec.Environment = mergeEnv(source.Env, job.Env)
+
+		deps := make([]string, 0, len(job.Needs))
+		for _, dep := range job.Needs {
+			if _, ok := source.Jobs[dep]; !ok {
+				return nil, fmt.Errorf("job %s needs unknown job %s", id, dep)
+			}
+			deps = append(deps, jobName(dep))
+		}
+		spec.Requires = requiresCompleted(deps)
+
+		if ghaExpression.MatchString(spec.Args[1]) || envHasExpression(spec.Environment) {
+			result.warnf("job %s: ${{ }} expressions are copied verbatim and must be replaced", name)
+		}
+
+		result.Workflow.Jobs[name] = spec
+	}
+
+	return result, nil
+}
+
+func mergeEnv(layers ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, layer := range layers {
+		for key, value := range layer {
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func envHasExpression(env map[string]string) bool {
+	for _, value := range env {
+		if ghaExpression.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// shellQuote quotes s for use as a single bash word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
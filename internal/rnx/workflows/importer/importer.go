@@ -0,0 +1,141 @@
+// Package importer translates pipeline definitions from other tools into
+// joblet workflow YAML. Conversions are best effort: anything without a
+// joblet equivalent is dropped and reported as a warning so the generated
+// workflow can be reviewed before it is run.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Result is a converted workflow plus the notes collected while converting.
+type Result struct {
+	Workflow *types.WorkflowYAML
+	Warnings []string
+}
+
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// converter turns the source document into a workflow.
+type converter func(data []byte) (*Result, error)
+
+var converters = map[string]converter{
+	"github-actions": convertGitHubActions,
+	"airflow":        convertAirflow,
+	"makefile":       convertMakefile,
+}
+
+// Formats lists the supported source formats.
+func Formats() []string {
+	formats := make([]string, 0, len(converters))
+	for format := range converters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Convert translates data in the given format.
+func Convert(format string, data []byte) (*Result, error) {
+	convert, ok := converters[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(Formats(), ", "))
+	}
+
+	result, err := convert(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s definition: %w", format, err)
+	}
+	if len(result.Workflow.Jobs) == 0 {
+		return nil, fmt.Errorf("no jobs found in %s definition", format)
+	}
+	return result, nil
+}
+
+// Render writes the workflow as YAML with the warnings as leading comments.
+func Render(result *Result, source string) ([]byte, error) {
+	// Mirror of the workflow types that leaves out empty fields, so generated
+	// files only contain what the import actually set
+	type jobOut struct {
+		Command     string              `yaml:"command"`
+		Args        []string            `yaml:"args,omitempty"`
+		Runtime     string              `yaml:"runtime,omitempty"`
+		Requires    []map[string]string `yaml:"requires,omitempty"`
+		Environment map[string]string   `yaml:"environment,omitempty"`
+	}
+	type workflowOut struct {
+		Name        string            `yaml:"name,omitempty"`
+		Description string            `yaml:"description,omitempty"`
+		Jobs        map[string]jobOut `yaml:"jobs"`
+	}
+
+	out := workflowOut{
+		Name:        result.Workflow.Name,
+		Description: result.Workflow.Description,
+		Jobs:        make(map[string]jobOut, len(result.Workflow.Jobs)),
+	}
+	for name, job := range result.Workflow.Jobs {
+		out.Jobs[name] = jobOut{
+			Command:     job.Command,
+			Args:        job.Args,
+			Runtime:     job.Runtime,
+			Requires:    job.Requires,
+			Environment: job.Environment,
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Imported from %s by 'rnx workflow import'. Review before running.\n", source)
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(&buf, "# WARNING: %s\n", warning)
+	}
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(out); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// jobName normalizes an external task name so it can be referenced from
+// dependency expressions.
+func jobName(name string) string {
+	name = unsafeNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// shellJob runs script with bash, stopping at the first failing line.
+func shellJob(script string) types.JobSpec {
+	return types.JobSpec{
+		Command: "bash",
+		Args:    []string{"-ec", strings.TrimSpace(script)},
+	}
+}
+
+// requiresCompleted builds the requires list for a job that waits on deps.
+func requiresCompleted(deps []string) []map[string]string {
+	if len(deps) == 0 {
+		return nil
+	}
+	requires := make([]map[string]string, 0, len(deps))
+	for _, dep := range deps {
+		requires = append(requires, map[string]string{dep: "COMPLETED"})
+	}
+	return requires
+}
@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConvert_GitHubActions(t *testing.T) {
+	source := `
+name: CI
+env:
+  GOFLAGS: -mod=readonly
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    env:
+      CGO_ENABLED: "0"
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+      - name: Build
+        run: go build ./...
+      - name: Vet
+        run: |
+          go vet ./...
+        env:
+          GOOS: linux
+  test:
+    needs: build
+    steps:
+      - run: go test ./... -run ${{ matrix.pattern }}
+  release:
+    needs: [build, test]
+    steps:
+      - uses: softprops/action-gh-release@v2
+`
+	result, err := Convert("github-actions", []byte(source))
+	require.NoError(t, err)
+
+	wf := result.Workflow
+	assert.Equal(t, "CI", wf.Name)
+	require.Len(t, wf.Jobs, 3)
+
+	build := wf.Jobs["build"]
+	assert.Equal(t, "bash", build.Command)
+	assert.Equal(t, "-ec", build.Args[0])
+	assert.Contains(t, build.Args[1], "go build ./...")
+	assert.Contains(t, build.Args[1], "(\nexport GOOS='linux'\ngo vet ./...\n)")
+	assert.Equal(t, map[string]string{"GOFLAGS": "-mod=readonly", "CGO_ENABLED": "0"}, build.Environment)
+	assert.Empty(t, build.Requires)
+
+	assert.Equal(t, []map[string]string{{"build": "COMPLETED"}}, wf.Jobs["test"].Requires)
+	assert.Equal(t, []map[string]string{{"build": "COMPLETED"}, {"test": "COMPLETED"}}, wf.Jobs["release"].Requires)
+	assert.Equal(t, "true", wf.Jobs["release"].Args[1])
+
+	warnings := strings.Join(result.Warnings, "\n")
+	assert.Contains(t, warnings, "go runtime")
+	assert.Contains(t, warnings, "softprops/action-gh-release@v2")
+	assert.Contains(t, warnings, "job test: ${{ }} expressions")
+	assert.NotContains(t, warnings, "actions/checkout")
+}
+
+func TestConvert_GitHubActionsUnknownNeed(t *testing.T) {
+	_, err := Convert("github-actions", []byte("jobs:\n  a:\n    needs: missing\n    steps:\n      - run: echo\n"))
+	assert.ErrorContains(t, err, "unknown job missing")
+}
+
+func TestConvert_Airflow(t *testing.T) {
+	source := `
+from airflow import DAG
+from airflow.operators.bash import BashOperator
+from airflow.operators.python import PythonOperator
+
+with DAG("nightly_etl", schedule="@daily") as dag:
+    extract = BashOperator(
+        task_id="extract",
+        bash_command="python3 extract.py --date {{ ds }}",
+        env={"SOURCE": "s3://raw", "REGION": "eu-west-1"},
+    )
+    transform = BashOperator(task_id="Transform Data", bash_command='python3 transform.py "input"')
+    notify = PythonOperator(task_id="notify", python_callable=send)
+    load = BashOperator(task_id="load", bash_command="""
+set -x
+python3 load.py
+""")
+    report = BashOperator(task_id="report", bash_command="echo done")
+
+    extract >> transform >> [load, notify]
+    report << load
+    notify.set_upstream(transform)
+`
+	result, err := Convert("airflow", []byte(source))
+	require.NoError(t, err)
+
+	wf := result.Workflow
+	assert.Equal(t, "nightly_etl", wf.Name)
+	require.Len(t, wf.Jobs, 4)
+
+	extract := wf.Jobs["extract"]
+	assert.Equal(t, "python3 extract.py --date {{ ds }}", extract.Args[1])
+	assert.Equal(t, map[string]string{"SOURCE": "s3://raw", "REGION": "eu-west-1"}, extract.Environment)
+
+	assert.Equal(t, `python3 transform.py "input"`, wf.Jobs["transform-data"].Args[1])
+	assert.Equal(t, []map[string]string{{"extract": "COMPLETED"}}, wf.Jobs["transform-data"].Requires)
+	assert.Equal(t, "set -x\npython3 load.py", wf.Jobs["load"].Args[1])
+	assert.Equal(t, []map[string]string{{"transform-data": "COMPLETED"}}, wf.Jobs["load"].Requires)
+	assert.Equal(t, []map[string]string{{"load": "COMPLETED"}}, wf.Jobs["report"].Requires)
+
+	warnings := strings.Join(result.Warnings, "\n")
+	assert.Contains(t, warnings, "task notify: PythonOperator")
+	assert.Contains(t, warnings, "job extract: Jinja templates")
+}
+
+func TestConvert_Makefile(t *testing.T) {
+	source := "PYTHON ?= python3\n" +
+		"OUT := build/out\n" +
+		"\n" +
+		".PHONY: all test clean\n" +
+		"all: test package\n" +
+		"\n" +
+		"deps: requirements.txt\n" +
+		"\t@$(PYTHON) -m pip install -r $<\n" +
+		"\n" +
+		"test: deps\n" +
+		"\t$(PYTHON) -m pytest \\\n" +
+		"\t  -q tests\n" +
+		"\n" +
+		"package: deps\n" +
+		"\tmkdir -p $(OUT)\n" +
+		"\t-rm -f $(OUT)/*.tar\n" +
+		"\ttar cf $(OUT)/$@.tar $$(git ls-files)\n" +
+		"\n" +
+		"%.o: %.c\n" +
+		"\tcc -c $<\n"
+
+	result, err := Convert("makefile", []byte(source))
+	require.NoError(t, err)
+
+	wf := result.Workflow
+	require.Len(t, wf.Jobs, 4)
+
+	assert.Equal(t, "true", wf.Jobs["all"].Args[1])
+	assert.Equal(t, []map[string]string{{"test": "COMPLETED"}, {"package": "COMPLETED"}}, wf.Jobs["all"].Requires)
+
+	deps := wf.Jobs["deps"]
+	assert.Equal(t, "${PYTHON} -m pip install -r requirements.txt", deps.Args[1])
+	assert.Empty(t, deps.Requires, "file prerequisites are not jobs")
+	assert.Equal(t, map[string]string{"PYTHON": "python3"}, deps.Environment)
+
+	assert.Equal(t, "${PYTHON} -m pytest  -q tests", wf.Jobs["test"].Args[1])
+
+	pkg := wf.Jobs["package"]
+	assert.Equal(t, "mkdir -p ${OUT}\nrm -f ${OUT}/*.tar || true\ntar cf ${OUT}/package.tar $(git ls-files)", pkg.Args[1])
+	assert.Equal(t, map[string]string{"OUT": "build/out"}, pkg.Environment)
+
+	assert.Contains(t, strings.Join(result.Warnings, "\n"), "pattern rule %.o ignored")
+}
+
+func TestConvert_Errors(t *testing.T) {
+	_, err := Convert("jenkins", nil)
+	assert.ErrorContains(t, err, "unsupported format")
+
+	_, err = Convert("makefile", []byte("CC = gcc\n"))
+	assert.ErrorContains(t, err, "no jobs found")
+}
+
+func TestRender(t *testing.T) {
+	result := &Result{
+		Workflow: &types.WorkflowYAML{
+			Name: "ci",
+			Jobs: map[string]types.JobSpec{
+				"build": shellJob("make"),
+				"test":  {Command: "bash", Args: []string{"-ec", "make test"}, Requires: requiresCompleted([]string{"build"})},
+			},
+		},
+		Warnings: []string{"action x was skipped"},
+	}
+
+	out, err := Render(result, "ci.yml")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "# Imported from ci.yml"))
+	assert.Contains(t, string(out), "# WARNING: action x was skipped\n")
+	assert.NotContains(t, string(out), "uploads")
+
+	var parsed types.WorkflowYAML
+	require.NoError(t, yaml.Unmarshal(out, &parsed))
+	assert.Equal(t, result.Workflow.Jobs["test"].Requires, parsed.Jobs["test"].Requires)
+	assert.Equal(t, []string{"-ec", "make"}, parsed.Jobs["build"].Args)
+}
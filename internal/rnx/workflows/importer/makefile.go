@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+)
+
+var (
+	makeVariable = regexp.MustCompile(`^(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*(?:[:?]?=|::=)\s*(.*)$`)
+	makeRule     = regexp.MustCompile(`^([^:=\s][^:=]*?)\s*::?\s*([^=]*)$`)
+	makeVarRef   = regexp.MustCompile(`\$[({]([A-Za-z_][A-Za-z0-9_]*)[)}]`)
+	makeFunction = regexp.MustCompile(`\$[({](shell|wildcard|patsubst|subst|foreach|call|if|filter|dir|notdir|basename|addprefix|addsuffix)\s`)
+)
+
+type makeTarget struct {
+	name    string
+	deps    []string
+	recipe  []string
+	defined int
+}
+
+// convertMakefile maps every explicit target to a job and its target
+// prerequisites to requirements. Make runs each recipe line in its own shell;
+// lines are kept separate with subshells when the recipe changes directory.
+func convertMakefile(data []byte) (*Result, error) {
+	result := &Result{Workflow: &types.WorkflowYAML{Jobs: make(map[string]types.JobSpec)}}
+
+	variables := make(map[string]string)
+	targets := make(map[string]*makeTarget)
+	phony := make(map[string]bool)
+	var current *makeTarget
+
+	for _, line := range logicalLines(data) {
+		if strings.HasPrefix(line, "\t") {
+			if current != nil {
+				if recipe := strings.TrimSpace(line); recipe != "" && !strings.HasPrefix(recipe, "#") {
+					current.recipe = append(current.recipe, recipe)
+				}
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(stripComment(line))
+		if trimmed == "" {
+			continue
+		}
+		current = nil
+
+		if m := makeVariable.FindStringSubmatch(trimmed); m != nil {
+			if _, set := variables[m[1]]; !set || !strings.Contains(trimmed, "?=") {
+				variables[m[1]] = strings.TrimSpace(m[2])
+			}
+			continue
+		}
+
+		m := makeRule.FindStringSubmatch(trimmed)
+		if m == nil {
+			if !strings.HasPrefix(trimmed, "include") && !strings.HasPrefix(trimmed, "ifeq") &&
+				!strings.HasPrefix(trimmed, "ifdef") && !strings.HasPrefix(trimmed, "ifndef") &&
+				!strings.HasPrefix(trimmed, "else") && !strings.HasPrefix(trimmed, "endif") {
+				result.warnf("unrecognized line ignored: %s", trimmed)
+			} else {
+				result.warnf("directive ignored: %s", trimmed)
+			}
+			continue
+		}
+
+		deps := strings.Fields(strings.SplitN(m[2], "|", 2)[0])
+		for _, name := range strings.Fields(m[1]) {
+			switch {
+			case name == ".PHONY":
+				for _, dep := range deps {
+					phony[dep] = true
+				}
+				continue
+			case strings.HasPrefix(name, "."):
+				continue
+			case strings.Contains(name, "%"):
+				result.warnf("pattern rule %s ignored", name)
+				continue
+			}
+
+			target, ok := targets[name]
+			if !ok {
+				target = &makeTarget{name: name, defined: len(targets)}
+				targets[name] = target
+			}
+			target.deps = append(target.deps, deps...)
+			current = target
+		}
+	}
+
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return targets[names[i]].defined < targets[names[j]].defined })
+
+	for _, name := range names {
+		target := targets[name]
+		job := jobName(name)
+
+		var deps []string
+		for _, dep := range target.deps {
+			// Prerequisites that are not targets are source files
+			if _, ok := targets[dep]; ok {
+				deps = append(deps, jobName(dep))
+			}
+		}
+
+		if len(target.recipe) == 0 {
+			if !phony[name] && len(deps) == 0 {
+				result.warnf("target %s has no recipe and was skipped", name)
+				continue
+			}
+			spec := shellJob("true")
+			spec.Requires = requiresCompleted(deps)
+			result.Workflow.Jobs[job] = spec
+			continue
+		}
+
+		changesDir, warned := false, false
+		lines := make([]string, 0, len(target.recipe))
+		for _, recipe := range target.recipe {
+			line, ignoreErrors := recipeLine(recipe, target)
+			if !warned && makeFunction.MatchString(line) {
+				warned = true
+				result.warnf("target %s: make functions are copied verbatim and must be replaced", name)
+			}
+			// $$ escapes a literal $ for the shell; keep it out of the
+			// variable rewrite
+			line = strings.ReplaceAll(line, "$$", "\x00")
+			line = makeVarRef.ReplaceAllString(line, "$${$1}")
+			line = strings.ReplaceAll(line, "\x00", "$")
+			if ignoreErrors {
+				line += " || true"
+			}
+			if strings.HasPrefix(line, "cd ") || strings.Contains(line, "&& cd ") {
+				changesDir = true
+			}
+			lines = append(lines, line)
+		}
+		if changesDir && len(lines) > 1 {
+			for i, line := range lines {
+				lines[i] = "(" + line + ")"
+			}
+		}
+
+		spec := shellJob(strings.Join(lines, "\n"))
+		spec.Requires = requiresCompleted(deps)
+		result.Workflow.Jobs[job] = spec
+	}
+
+	// Variables become the environment of every job that references them
+	for job, spec := range result.Workflow.Jobs {
+		for name, value := range variables {
+			if strings.Contains(spec.Args[1], "${"+name+"}") {
+				if spec.Environment == nil {
+					spec.Environment = make(map[string]string)
+				}
+				spec.Environment[name] = makeVarRef.ReplaceAllString(value, "$${$1}")
+			}
+		}
+		result.Workflow.Jobs[job] = spec
+	}
+
+	return result, nil
+}
+
+// recipeLine strips the @ and - prefixes and expands automatic variables.
+func recipeLine(recipe string, target *makeTarget) (string, bool) {
+	ignoreErrors := false
+	for len(recipe) > 0 && strings.ContainsRune("@-+", rune(recipe[0])) {
+		if recipe[0] == '-' {
+			ignoreErrors = true
+		}
+		recipe = strings.TrimSpace(recipe[1:])
+	}
+
+	first := ""
+	if len(target.deps) > 0 {
+		first = target.deps[0]
+	}
+	recipe = strings.NewReplacer(
+		"$$", "$$",
+		"$@", target.name,
+		"$(@)", target.name,
+		"$<", first,
+		"$^", strings.Join(target.deps, " "),
+	).Replace(recipe)
+	return recipe, ignoreErrors
+}
+
+// logicalLines splits data into lines, joining backslash continuations.
+func logicalLines(data []byte) []string {
+	var lines []string
+	var pending strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasSuffix(line, `\`) {
+			pending.WriteString(strings.TrimSuffix(line, `\`))
+			pending.WriteString(" ")
+			continue
+		}
+		if pending.Len() > 0 {
+			pending.WriteString(strings.TrimLeft(line, " \t"))
+			line = pending.String()
+			pending.Reset()
+		}
+		lines = append(lines, line)
+	}
+	if pending.Len() > 0 {
+		lines = append(lines, pending.String())
+	}
+	return lines
+}
+
+func stripComment(line string) string {
+	if idx := strings.Index(line, "#"); idx >= 0 {
+		return line[:idx]
+	}
+	return line
+}
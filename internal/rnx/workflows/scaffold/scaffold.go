@@ -0,0 +1,503 @@
+// Package scaffold builds a starter workflow by asking the user a few
+// questions: the jobs, their commands, runtimes, dependencies, uploads and
+// resource hints. The result is validated the same way 'rnx workflow run'
+// reads it, so a new user starts from a file that is known to work.
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+	"github.com/ehsaniara/joblet/internal/rnx/workflows"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInputClosed is returned when input ends before the wizard is done.
+var ErrInputClosed = errors.New("input closed before the workflow was complete")
+
+var validJobName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// scriptExtensions are argument suffixes that suggest a local file the job
+// needs uploaded.
+var scriptExtensions = map[string]bool{
+	".py": true, ".sh": true, ".js": true, ".ts": true, ".rb": true, ".pl": true,
+	".r": true, ".jar": true, ".sql": true, ".json": true, ".yaml": true, ".yml": true,
+	".csv": true, ".txt": true,
+}
+
+// Wizard asks for the workflow definition one question at a time.
+type Wizard struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewWizard creates a wizard reading answers from in and writing prompts to
+// out.
+func NewWizard(in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{in: bufio.NewScanner(in), out: out}
+}
+
+// Run asks for the workflow and returns it. Jobs are added until an empty job
+// name is entered.
+func (w *Wizard) Run() (*types.WorkflowYAML, error) {
+	wf := &types.WorkflowYAML{Jobs: make(map[string]types.JobSpec)}
+
+	var err error
+	if wf.Name, err = w.ask("Workflow name", "my-workflow", nil); err != nil {
+		return nil, err
+	}
+	if wf.Description, err = w.ask("Description", "", nil); err != nil {
+		return nil, err
+	}
+
+	var order []string
+	for {
+		fmt.Fprintf(w.out, "\nJob %d (leave the name empty to finish)\n", len(order)+1)
+		name, err := w.ask("  Job name", "", func(answer string) error {
+			if answer == "" {
+				if len(order) == 0 {
+					return fmt.Errorf("a workflow needs at least one job")
+				}
+				return nil
+			}
+			if !validJobName.MatchString(answer) {
+				return fmt.Errorf("use letters, digits, '-' and '_' only")
+			}
+			if _, exists := wf.Jobs[answer]; exists {
+				return fmt.Errorf("job %s already exists", answer)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			break
+		}
+
+		job, err := w.askJob(order)
+		if err != nil {
+			return nil, err
+		}
+		wf.Jobs[name] = job
+		order = append(order, name)
+	}
+
+	return wf, nil
+}
+
+func (w *Wizard) askJob(previous []string) (types.JobSpec, error) {
+	var job types.JobSpec
+
+	commandLine, err := w.ask("  Command (e.g. python3 process.py --limit 10)", "", func(answer string) error {
+		fields, err := SplitCommandLine(answer)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return fmt.Errorf("a command is required")
+		}
+		return nil
+	})
+	if err != nil {
+		return job, err
+	}
+	fields, _ := SplitCommandLine(commandLine)
+	job.Command, job.Args = fields[0], fields[1:]
+
+	if job.Runtime, err = w.ask("  Runtime (e.g. python-3.11, empty for the host tools)", "", nil); err != nil {
+		return job, err
+	}
+
+	if len(previous) > 0 {
+		known := make(map[string]bool, len(previous))
+		for _, name := range previous {
+			known[name] = true
+		}
+		answer, err := w.ask(fmt.Sprintf("  Runs after (comma-separated, from: %s)", strings.Join(previous, ", ")), "", func(answer string) error {
+			for _, dep := range splitList(answer) {
+				if !known[dep] {
+					return fmt.Errorf("unknown job %s", dep)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return job, err
+		}
+		for _, dep := range splitList(answer) {
+			job.Requires = append(job.Requires, map[string]string{dep: "COMPLETED"})
+		}
+	}
+
+	answer, err := w.ask("  Files to upload (comma-separated, relative to the workflow file)", strings.Join(guessUploads(job.Args), ", "), func(answer string) error {
+		for _, file := range splitList(answer) {
+			if filepath.IsAbs(file) || strings.HasPrefix(filepath.Clean(file), "..") {
+				return fmt.Errorf("%s must be inside the workflow directory", file)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return job, err
+	}
+	if files := splitList(answer); len(files) > 0 {
+		job.Uploads = &types.JobUploads{Files: files}
+	}
+
+	if job.Resources.MaxMemory, err = w.askInt("  Max memory in MB (empty for no limit)", 0); err != nil {
+		return job, err
+	}
+	if job.Resources.MaxCPU, err = w.askInt("  Max CPU percent, 100 per core (empty for no limit)", 10000); err != nil {
+		return job, err
+	}
+
+	return job, nil
+}
+
+// ask prints the prompt and reads one answer, falling back to def when the
+// answer is empty. Invalid answers are explained and asked again.
+func (w *Wizard) ask(prompt, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", prompt, def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", prompt)
+		}
+
+		if !w.in.Scan() {
+			if err := w.in.Err(); err != nil {
+				return "", err
+			}
+			return "", ErrInputClosed
+		}
+		answer := strings.TrimSpace(w.in.Text())
+		if answer == "" {
+			answer = def
+		}
+
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				fmt.Fprintf(w.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// askInt asks for an optional non-negative number up to max; empty means 0.
+func (w *Wizard) askInt(prompt string, max int) (int, error) {
+	answer, err := w.ask(prompt, "", func(answer string) error {
+		if answer == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 0 {
+			return fmt.Errorf("enter a whole number")
+		}
+		if max > 0 && n > max {
+			return fmt.Errorf("the maximum is %d", max)
+		}
+		return nil
+	})
+	if err != nil || answer == "" {
+		return 0, err
+	}
+	return strconv.Atoi(answer)
+}
+
+// guessUploads suggests the arguments that look like local files.
+func guessUploads(args []string) []string {
+	var files []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || filepath.IsAbs(arg) || strings.HasPrefix(filepath.Clean(arg), "..") {
+			continue
+		}
+		if scriptExtensions[strings.ToLower(filepath.Ext(arg))] {
+			files = append(files, arg)
+		}
+	}
+	return files
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SplitCommandLine splits a command line into words, honouring single and
+// double quotes and backslash escapes the way a shell would.
+func SplitCommandLine(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("command ends with a backslash")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// Render writes the workflow as YAML, leaving out fields the wizard did not
+// set.
+func Render(wf *types.WorkflowYAML) ([]byte, error) {
+	type resourcesOut struct {
+		MaxCPU    int `yaml:"max_cpu,omitempty"`
+		MaxMemory int `yaml:"max_memory,omitempty"`
+	}
+	type uploadsOut struct {
+		Files []string `yaml:"files"`
+	}
+	type jobOut struct {
+		Command   string              `yaml:"command"`
+		Args      []string            `yaml:"args,omitempty"`
+		Runtime   string              `yaml:"runtime,omitempty"`
+		Uploads   *uploadsOut         `yaml:"uploads,omitempty"`
+		Requires  []map[string]string `yaml:"requires,omitempty"`
+		Resources *resourcesOut       `yaml:"resources,omitempty"`
+	}
+	type workflowOut struct {
+		Name        string            `yaml:"name,omitempty"`
+		Description string            `yaml:"description,omitempty"`
+		Jobs        map[string]jobOut `yaml:"jobs"`
+	}
+
+	out := workflowOut{
+		Name:        wf.Name,
+		Description: wf.Description,
+		Jobs:        make(map[string]jobOut, len(wf.Jobs)),
+	}
+	for name, job := range wf.Jobs {
+		j := jobOut{
+			Command:  job.Command,
+			Args:     job.Args,
+			Runtime:  job.Runtime,
+			Requires: job.Requires,
+		}
+		if job.Uploads != nil && len(job.Uploads.Files) > 0 {
+			j.Uploads = &uploadsOut{Files: job.Uploads.Files}
+		}
+		if job.Resources.MaxCPU > 0 || job.Resources.MaxMemory > 0 {
+			j.Resources = &resourcesOut{MaxCPU: job.Resources.MaxCPU, MaxMemory: job.Resources.MaxMemory}
+		}
+		out.Jobs[name] = j
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by 'rnx workflow init'. Run it with 'rnx workflow run'.\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(out); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Validate parses rendered workflow YAML the way the client and server do
+// and checks that every dependency exists and there are no cycles.
+func Validate(data []byte) error {
+	var wf types.WorkflowYAML
+	if err := yaml.Unmarshal(data, &wf); err != nil {
+		return fmt.Errorf("invalid workflow YAML: %w", err)
+	}
+	if len(wf.Jobs) == 0 {
+		return fmt.Errorf("workflow has no jobs")
+	}
+	for name, job := range wf.Jobs {
+		if job.Command == "" {
+			return fmt.Errorf("job %s has no command", name)
+		}
+		for _, req := range job.Requires {
+			for dep := range req {
+				if _, ok := wf.Jobs[dep]; !ok {
+					return fmt.Errorf("job %s depends on non-existent job %s", name, dep)
+				}
+			}
+		}
+	}
+
+	var set workflows.WorkflowJobSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("invalid workflow YAML: %w", err)
+	}
+	if _, err := workflows.BuildDependencyGraph(set.Jobs); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UploadFiles lists every file the workflow uploads, sorted and without
+// duplicates.
+func UploadFiles(wf *types.WorkflowYAML) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, job := range wf.Jobs {
+		if job.Uploads == nil {
+			continue
+		}
+		for _, file := range job.Uploads.Files {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// WriteUploadStubs creates a placeholder for every upload that does not
+// exist yet under dir, so the example layout can be run straight away.
+// Existing files are left untouched. It returns the files it created.
+func WriteUploadStubs(dir string, wf *types.WorkflowYAML) ([]string, error) {
+	var created []string
+	for _, file := range UploadFiles(wf) {
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return created, fmt.Errorf("failed to create directory for %s: %w", file, err)
+		}
+
+		mode := os.FileMode(0644)
+		if filepath.Ext(file) == ".sh" {
+			mode = 0755
+		}
+		if err := os.WriteFile(path, stubContent(file), mode); err != nil {
+			return created, fmt.Errorf("failed to create %s: %w", file, err)
+		}
+		created = append(created, file)
+	}
+	return created, nil
+}
+
+// stubContent is a placeholder body for file, a comment where the file type
+// has one.
+func stubContent(file string) []byte {
+	note := "Placeholder created by 'rnx workflow init', replace with your code"
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".py":
+		return []byte("#!/usr/bin/env python3\n# " + note + "\nprint(\"hello from " + filepath.Base(file) + "\")\n")
+	case ".sh":
+		return []byte("#!/bin/bash\nset -e\n# " + note + "\necho \"hello from " + filepath.Base(file) + "\"\n")
+	case ".rb", ".pl", ".r", ".yaml", ".yml":
+		return []byte("# " + note + "\n")
+	case ".js", ".ts":
+		return []byte("// " + note + "\nconsole.log(\"hello from " + filepath.Base(file) + "\");\n")
+	case ".sql":
+		return []byte("-- " + note + "\n")
+	default:
+		return nil
+	}
+}
+
+// Layout draws the workflow file and its uploads as a tree rooted at the
+// workflow's directory.
+func Layout(workflowFile string, wf *types.WorkflowYAML) string {
+	type node struct {
+		children map[string]*node
+	}
+	root := &node{children: map[string]*node{}}
+	add := func(path string) {
+		current := root
+		for _, part := range strings.Split(filepath.ToSlash(filepath.Clean(path)), "/") {
+			child, ok := current.children[part]
+			if !ok {
+				child = &node{children: map[string]*node{}}
+				current.children[part] = child
+			}
+			current = child
+		}
+	}
+	add(filepath.Base(workflowFile))
+	for _, file := range UploadFiles(wf) {
+		add(file)
+	}
+
+	var b strings.Builder
+	if dir := filepath.Dir(workflowFile); dir == "." {
+		b.WriteString(".\n")
+	} else {
+		b.WriteString(filepath.ToSlash(dir) + "/\n")
+	}
+	var walk func(n *node, prefix string)
+	walk = func(n *node, prefix string) {
+		names := make([]string, 0, len(n.children))
+		for name := range n.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			branch, next := "├── ", "│   "
+			if i == len(names)-1 {
+				branch, next = "└── ", "    "
+			}
+			child := n.children[name]
+			if len(child.children) > 0 {
+				name += "/"
+			}
+			b.WriteString(prefix + branch + name + "\n")
+			walk(child, prefix+next)
+		}
+	}
+	walk(root, "")
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/workflow/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWizard_Run(t *testing.T) {
+	answers := strings.Join([]string{
+		"etl",         // workflow name
+		"Nightly ETL", // description
+		"extract",
+		`python3 extract.py --day "2025-07-16"`,
+		"python-3.11",
+		"",    // uploads: accept the guessed extract.py
+		"512", // memory
+		"",    // cpu
+		"load",
+		"bash scripts/load.sh",
+		"",        // runtime
+		"missing", // unknown dependency, asked again
+		"extract",
+		"scripts/load.sh, ../secret", // outside the directory, asked again
+		"",
+		"lots", // not a number, asked again
+		"",
+		"150",
+		"", // finish
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	wf, err := NewWizard(strings.NewReader(answers), &out).Run()
+	require.NoError(t, err)
+
+	assert.Equal(t, "etl", wf.Name)
+	assert.Equal(t, "python3", wf.Jobs["extract"].Command)
+	assert.Equal(t, "python-3.11", wf.Jobs["extract"].Runtime)
+	assert.Equal(t, []string{"extract.py", "--day", "2025-07-16"}, wf.Jobs["extract"].Args)
+	assert.Equal(t, &types.JobUploads{Files: []string{"extract.py"}}, wf.Jobs["extract"].Uploads)
+	assert.Equal(t, 512, wf.Jobs["extract"].Resources.MaxMemory)
+
+	load := wf.Jobs["load"]
+	assert.Equal(t, []map[string]string{{"extract": "COMPLETED"}}, load.Requires)
+	assert.Equal(t, []string{"scripts/load.sh"}, load.Uploads.Files)
+	assert.Equal(t, 150, load.Resources.MaxCPU)
+
+	assert.Contains(t, out.String(), "unknown job missing")
+	assert.Contains(t, out.String(), "../secret must be inside the workflow directory")
+	assert.Contains(t, out.String(), "enter a whole number")
+
+	rendered, err := Render(wf)
+	require.NoError(t, err)
+	require.NoError(t, Validate(rendered))
+	assert.NotContains(t, string(rendered), "network")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extract.py"), []byte("print(1)\n"), 0644))
+	created, err := WriteUploadStubs(dir, wf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scripts/load.sh"}, created)
+	existing, _ := os.ReadFile(filepath.Join(dir, "extract.py"))
+	assert.Equal(t, "print(1)\n", string(existing))
+
+	assert.Equal(t, ".\n├── extract.py\n├── scripts/\n│   └── load.sh\n└── workflow.yaml\n", Layout("workflow.yaml", wf))
+}
+
+func TestWizard_InputClosed(t *testing.T) {
+	_, err := NewWizard(strings.NewReader("etl\n\n"), &bytes.Buffer{}).Run()
+	assert.ErrorIs(t, err, ErrInputClosed)
+}
+
+func TestValidate(t *testing.T) {
+	assert.Error(t, Validate([]byte("jobs:\n  a:\n    command: ls\n    requires:\n      - b: COMPLETED\n")))
+	assert.Error(t, Validate([]byte("jobs:\n  a:\n    command: ls\n    requires:\n      - b: COMPLETED\n  b:\n    command: ls\n    requires:\n      - a: COMPLETED\n")))
+	assert.Error(t, Validate([]byte("jobs: {}\n")))
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	words, err := SplitCommandLine(`bash -c 'echo "$HOME"' a\ b ""`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bash", "-c", `echo "$HOME"`, "a b", ""}, words)
+
+	_, err = SplitCommandLine(`echo "open`)
+	assert.Error(t, err)
+}
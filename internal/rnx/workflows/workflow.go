@@ -287,7 +287,7 @@ func extractJobNamesFromExpression(expr string) []string {
 		}
 		// Skip status values
 		if token == "COMPLETED" || token == "FAILED" || token == "CANCELED" ||
-			token == "STOPPED" || token == "RUNNING" || token == "PENDING" || token == "SCHEDULED" {
+			token == "STOPPED" || token == "RUNNING" || token == "PENDING" || token == "SCHEDULED" || token == "EXPIRED" {
 			continue
 		}
 		// Remaining tokens should be job names
@@ -8,6 +8,9 @@ import (
 	"syscall"
 
 	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/leader"
+	"github.com/ehsaniara/joblet/internal/joblet/sdnotify"
+	"github.com/ehsaniara/joblet/internal/joblet/state"
 	"github.com/ehsaniara/joblet/persist/internal/config"
 	"github.com/ehsaniara/joblet/persist/internal/ipc"
 	"github.com/ehsaniara/joblet/persist/internal/server"
@@ -15,6 +18,8 @@ import (
 	"github.com/ehsaniara/joblet/pkg/logger"
 )
 
+const defaultStateSocket = "/opt/joblet/run/state-ipc.sock"
+
 var (
 	configPath = flag.String("config", "/opt/joblet/config/joblet-config.yml", "Path to configuration file")
 	version    = "1.0.0-dev"
@@ -56,6 +61,41 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Under systemd, feed the watchdog for as long as this process runs
+	go sdnotify.RunWatchdog(ctx, nil, log)
+
+	// With ha, wait as a standby until this instance holds the persist lease
+	// in the state service; only the holder opens storage and the sockets
+	var elector *leader.Elector
+	held := make(chan error, 1)
+	holdCtx, stopHolding := context.WithCancel(context.Background())
+	defer stopHolding()
+	if result.HA.Enabled {
+		stateSocket := result.State.Socket
+		if stateSocket == "" {
+			stateSocket = defaultStateSocket
+		}
+		stateClient := state.NewPooledClient(stateSocket, 2, log.WithField("component", "persist-lease"))
+		defer stateClient.Close()
+
+		elector = leader.NewElector(state.NewLease(stateClient, "persist"), leader.Holder(), result.HA.LeaseTTL, log)
+		log.Info("Waiting for the persist lease", "stateSocket", stateSocket)
+		// A standby is up as far as systemd is concerned
+		_, _ = sdnotify.Notify(sdnotify.Ready, sdnotify.Status("standby, waiting for the persist lease"))
+		if err := elector.Campaign(ctx); err != nil {
+			log.Error("Failed to acquire the persist lease", "error", err)
+			os.Exit(1)
+		}
+
+		// Runs after the servers below stopped and removed their sockets:
+		// releasing the lease lets a standby take over without waiting for
+		// it to expire
+		defer func() {
+			stopHolding()
+			<-held
+		}()
+	}
+
 	// Initialize storage backend (pass nodeID for multi-node CloudWatch deployments)
 	backend, err := storage.NewBackend(&cfg.Storage, result.NodeID, log)
 	if err != nil {
@@ -99,11 +139,29 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Keep the lease renewed while serving
+	if elector != nil {
+		go func() { held <- elector.Hold(holdCtx) }()
+	}
+
+	if _, err := sdnotify.Notify(sdnotify.Ready, sdnotify.Status("serving")); err != nil {
+		log.Warn("Failed to notify systemd", "error", err)
+	}
+
 	log.Info("persist is running. Press Ctrl+C to stop.")
 
-	// Block until signal received
-	sig := <-sigChan
-	log.Info("Received signal, shutting down gracefully...", "signal", sig)
+	// Block until signal received or the lease is lost
+	select {
+	case sig := <-sigChan:
+		log.Info("Received signal, shutting down gracefully...", "signal", sig)
+	case err := <-held:
+		// Another instance serves the sockets now; exit without removing
+		// them, the supervisor restarts this one as a standby
+		log.Error("Lost the persist lease, exiting", "error", err)
+		os.Exit(1)
+	}
+
+	_, _ = sdnotify.Notify(sdnotify.Stopping)
 
 	// Cancel context to trigger shutdown
 	cancel()
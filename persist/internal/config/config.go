@@ -21,7 +21,9 @@ type ServerConfig struct {
 	GRPCAddress    string     `yaml:"grpc_address"` // TCP address (optional, can be empty to disable)
 	GRPCSocket     string     `yaml:"grpc_socket"`  // Unix socket for internal IPC (e.g., /opt/joblet/run/persist-grpc.sock)
 	MaxConnections int        `yaml:"max_connections"`
-	TLS            *TLSConfig `yaml:"tls,omitempty"` // Optional: defaults to inherited security
+	TLS            *TLSConfig `yaml:"tls,omitempty"`  // Optional: defaults to inherited security
+	HealthService  bool       `yaml:"health_service"` // Serve grpc.health.v1
+	Reflection     bool       `yaml:"reflection"`     // Serve gRPC server reflection
 }
 
 // TLSConfig contains TLS/mTLS settings
@@ -45,9 +47,10 @@ type IPCConfig struct {
 
 // StorageConfig contains storage backend settings
 type StorageConfig struct {
-	Type        string            `yaml:"type"` // "local", "cloudwatch", "s3"
+	Type        string            `yaml:"type"` // "local", "cloudwatch", "clickhouse", "s3"
 	Local       LocalConfig       `yaml:"local"`
 	CloudWatch  CloudWatchConfig  `yaml:"cloudwatch"`
+	ClickHouse  ClickHouseConfig  `yaml:"clickhouse"`
 	Retention   RetentionConfig   `yaml:"retention"`
 	Compression CompressionConfig `yaml:"compression"`
 }
@@ -56,6 +59,25 @@ type StorageConfig struct {
 type LocalConfig struct {
 	Logs    LogStorageConfig    `yaml:"logs"`
 	Metrics MetricStorageConfig `yaml:"metrics"`
+	// Layout is "files" (a directory per job, the default) or "segments"
+	// (shared append-only segment files with an index)
+	Layout     string           `yaml:"layout"`
+	Segments   SegmentsConfig   `yaml:"segments"`
+	Encryption EncryptionConfig `yaml:"encryption"`
+}
+
+// EncryptionConfig contains at-rest encryption of job logs ("files" layout).
+// Key files hold a 32-byte AES-256 key, raw or hex encoded.
+type EncryptionConfig struct {
+	Enabled    bool              `yaml:"enabled"`
+	KeyFile    string            `yaml:"key_file"`    // Key of jobs without a tenant key of their own
+	TenantKeys map[string]string `yaml:"tenant_keys"` // Tenant name -> key file
+}
+
+// SegmentsConfig contains settings of the "segments" local layout
+type SegmentsConfig struct {
+	MaxSizeMB          int    `yaml:"max_size_mb"`         // Size at which a new segment is started (default: 64)
+	CompactionInterval string `yaml:"compaction_interval"` // How often sealed segments are compacted (default: 10m)
 }
 
 // CloudWatchConfig contains AWS CloudWatch storage settings
@@ -80,6 +102,26 @@ type CloudWatchConfig struct {
 	// 0 or not set = default to 7 days, -1 = never expire
 }
 
+// ClickHouseConfig contains ClickHouse metrics storage settings
+// Metrics are written to ClickHouse over its HTTP interface; logs stay on the local backend
+type ClickHouseConfig struct {
+	URL      string `yaml:"url"`      // HTTP interface (default: http://localhost:8123)
+	Database string `yaml:"database"` // Database, created if missing (default: joblet)
+	Table    string `yaml:"table"`    // Metrics table, created if missing (default: job_metrics)
+	Username string `yaml:"username"` // Empty = ClickHouse default user
+	Password string `yaml:"password"`
+	NodeID   string `yaml:"-"` // Node ID (inherited from server.nodeId, not from YAML)
+
+	// Batch settings
+	BatchSize       int    `yaml:"batch_size"`        // Rows per INSERT (default: 1000)
+	FlushInterval   string `yaml:"flush_interval"`    // Max delay before buffered rows are inserted (default: 5s)
+	MaxBufferedRows int    `yaml:"max_buffered_rows"` // Rows kept while ClickHouse is unreachable (default: 100000)
+	Timeout         string `yaml:"timeout"`           // HTTP request timeout (default: 30s)
+
+	// Retention settings
+	TTLDays int `yaml:"ttl_days"` // Table TTL in days (0 = use retention.metrics_days, -1 = never expire)
+}
+
 // LogStorageConfig contains log storage settings
 type LogStorageConfig struct {
 	Directory string         `yaml:"directory"`
@@ -145,6 +187,18 @@ type ServerInfo struct {
 	NodeID string `yaml:"nodeId"` // Node identifier for distributed deployments
 }
 
+// HAInfo is the parent's ha section: with it enabled, persist instances
+// elect the one serving the sockets through a lease in the state service
+type HAInfo struct {
+	Enabled  bool          `yaml:"enabled"`
+	LeaseTTL time.Duration `yaml:"lease_ttl"`
+}
+
+// StateInfo is the part of the parent's state section persist needs
+type StateInfo struct {
+	Socket string `yaml:"socket"` // State service IPC socket holding the lease
+}
+
 // RootConfig wraps the persist config to support nested structure
 // and includes shared configurations from parent (joblet)
 type RootConfig struct {
@@ -152,6 +206,8 @@ type RootConfig struct {
 	Persist  *Config        `yaml:"persist"`
 	Logging  LoggingConfig  `yaml:"logging"`  // Inherited logging config
 	Security SecurityConfig `yaml:"security"` // Inherited TLS certificates
+	HA       HAInfo         `yaml:"ha"`       // Inherited active/standby settings
+	State    StateInfo      `yaml:"state"`    // Inherited state socket
 }
 
 // LoadResult contains persist config and inherited parent configurations
@@ -160,6 +216,8 @@ type LoadResult struct {
 	NodeID   string         // Inherited from parent (server.nodeId)
 	Logging  LoggingConfig  // Inherited from parent
 	Security SecurityConfig // Inherited from parent (TLS certificates)
+	HA       HAInfo         // Inherited from parent (standalone: disabled)
+	State    StateInfo      // Inherited from parent
 }
 
 // Load loads configuration from a YAML file
@@ -190,6 +248,8 @@ func Load(path string) (*LoadResult, error) {
 			NodeID:   rootCfg.Server.NodeID,
 			Logging:  rootCfg.Logging,
 			Security: rootCfg.Security,
+			HA:       rootCfg.HA,
+			State:    rootCfg.State,
 		}, nil
 	}
 
@@ -285,6 +345,26 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("storage.type is required")
 	}
 
+	switch c.Storage.Local.Layout {
+	case "", "files", "segments":
+	default:
+		return fmt.Errorf("storage.local.layout must be \"files\" or \"segments\", got %q", c.Storage.Local.Layout)
+	}
+
+	if enc := c.Storage.Local.Encryption; enc.Enabled {
+		if c.Storage.Local.Layout == "segments" {
+			return fmt.Errorf("storage.local.encryption requires the \"files\" layout")
+		}
+		if enc.KeyFile == "" {
+			return fmt.Errorf("storage.local.encryption.key_file is required when encryption is enabled")
+		}
+		for tenant, keyFile := range enc.TenantKeys {
+			if tenant == "" || keyFile == "" {
+				return fmt.Errorf("storage.local.encryption.tenant_keys entries need a tenant and a key file")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -64,6 +65,49 @@ func TestValidate_EmptySocketPath(t *testing.T) {
 	}
 }
 
+func TestValidate_LocalLayout(t *testing.T) {
+	for layout, valid := range map[string]bool{"": true, "files": true, "segments": true, "sqlite": false} {
+		cfg := DefaultConfig()
+		cfg.Storage.Local.Layout = layout
+		if err := cfg.Validate(); (err == nil) != valid {
+			t.Errorf("layout %q: Validate() error = %v", layout, err)
+		}
+	}
+}
+
+func TestValidate_Encryption(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*EncryptionConfig, *LocalConfig)
+		valid bool
+	}{
+		{"disabled", func(e *EncryptionConfig, l *LocalConfig) {}, true},
+		{"default key", func(e *EncryptionConfig, l *LocalConfig) { e.KeyFile = "/keys/default.key" }, true},
+		{"tenant keys", func(e *EncryptionConfig, l *LocalConfig) {
+			e.KeyFile = "/keys/default.key"
+			e.TenantKeys = map[string]string{"acme": "/keys/acme.key"}
+		}, true},
+		{"missing key file", func(e *EncryptionConfig, l *LocalConfig) {}, false},
+		{"empty tenant key", func(e *EncryptionConfig, l *LocalConfig) {
+			e.KeyFile = "/keys/default.key"
+			e.TenantKeys = map[string]string{"acme": ""}
+		}, false},
+		{"segments layout", func(e *EncryptionConfig, l *LocalConfig) {
+			e.KeyFile = "/keys/default.key"
+			l.Layout = "segments"
+		}, false},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		enc := &cfg.Storage.Local.Encryption
+		enc.Enabled = tt.name != "disabled"
+		tt.setup(enc, &cfg.Storage.Local)
+		if err := cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate() error = %v", tt.name, err)
+		}
+	}
+}
+
 func TestLoadConfig_NonExistentFile(t *testing.T) {
 	_, err := Load("/nonexistent/config.yml")
 	if err == nil {
@@ -142,6 +186,13 @@ server:
   address: "0.0.0.0"
   port: 50051
 
+ha:
+  enabled: true
+  lease_ttl: "15s"
+
+state:
+  socket: "/tmp/state.sock"
+
 persist:
   server:
     grpc_address: ":50054"
@@ -187,6 +238,10 @@ persist:
 		t.Errorf("Expected socket /tmp/nested.sock, got %s", result.Config.IPC.Socket)
 	}
 
+	if !result.HA.Enabled || result.HA.LeaseTTL != 15*time.Second || result.State.Socket != "/tmp/state.sock" {
+		t.Errorf("Expected ha and the state socket inherited, got %+v, %+v", result.HA, result.State)
+	}
+
 	// Logging inherits from parent root level in nested config (may be empty if not specified)
 	// This is expected behavior - logging config comes from parent joblet-config.yml
 	if result.Logging.Level == "" {
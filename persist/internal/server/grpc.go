@@ -11,9 +11,11 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/status"
 
 	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/introspection"
 	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
 	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
 	"github.com/ehsaniara/joblet/persist/internal/config"
@@ -108,6 +110,7 @@ type GRPCServer struct {
 	backend  storage.Backend
 	logger   *logger.Logger
 	grpcSrv  *grpc.Server
+	health   *health.Server // nil unless server.health_service is set
 	listener net.Listener
 }
 
@@ -219,6 +222,10 @@ func (s *GRPCServer) Start(ctx context.Context) error {
 
 	s.grpcSrv = grpc.NewServer(opts...)
 	persistpb.RegisterPersistServiceServer(s.grpcSrv, s)
+	if s.config.HealthService || s.config.Reflection {
+		s.health = introspection.Register(s.grpcSrv, s.config.HealthService, s.config.Reflection)
+		s.logger.Info("gRPC introspection enabled", "health", s.config.HealthService, "reflection", s.config.Reflection)
+	}
 
 	s.logger.Info("gRPC server starting", "address", s.config.GRPCAddress)
 
@@ -236,6 +243,11 @@ func (s *GRPCServer) Start(ctx context.Context) error {
 func (s *GRPCServer) Stop() error {
 	s.logger.Info("Stopping gRPC server")
 
+	// Report NOT_SERVING to health checkers while in-flight queries finish
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+
 	if s.grpcSrv != nil {
 		s.grpcSrv.GracefulStop()
 	}
@@ -327,14 +339,38 @@ func (s *GRPCServer) QueryMetrics(req *persistpb.QueryMetricsRequest, stream per
 		return err
 	}
 
-	s.logger.Info("QueryMetrics request", "jobID", req.JobId, "limit", req.Limit, "offset", req.Offset)
+	s.logger.Info("QueryMetrics request", "jobID", req.JobId, "query", req.Query, "step", req.Step, "limit", req.Limit, "offset", req.Offset)
 
 	// Build query
 	query := &storage.MetricQuery{
 		JobID:  req.JobId,
+		Step:   req.Step,
 		Limit:  int(req.Limit),
 		Offset: int(req.Offset),
 	}
+	if req.Step < 0 {
+		return status.Errorf(codes.InvalidArgument, "step cannot be negative")
+	}
+
+	// Apply the query expression: the metric to keep, the job its job_id
+	// matcher names and the aggregation per step
+	if req.Query != "" {
+		expr, err := storage.ParseMetricExpr(req.Query)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid metrics query: %v", err)
+		}
+		if expr.JobID != "" {
+			if req.JobId != "" && req.JobId != expr.JobID {
+				return status.Errorf(codes.InvalidArgument, "job_id %q does not match the query's job_id %q", req.JobId, expr.JobID)
+			}
+			query.JobID = expr.JobID
+		}
+		query.Metric = expr.Metric
+		query.Aggregation = expr.Function
+	}
+	if query.JobID == "" {
+		return status.Errorf(codes.InvalidArgument, "job_id is required")
+	}
 
 	// Add time range if specified
 	if req.StartTime > 0 {
@@ -345,9 +381,9 @@ func (s *GRPCServer) QueryMetrics(req *persistpb.QueryMetricsRequest, stream per
 	}
 
 	// Read metrics from backend
-	reader, err := s.backend.ReadMetrics(stream.Context(), query)
+	reader, err := storage.ReadMetricSeries(stream.Context(), s.backend, query)
 	if err != nil {
-		s.logger.Error("Failed to read metrics", "error", err, "jobID", req.JobId)
+		s.logger.Error("Failed to read metrics", "error", err, "jobID", query.JobID)
 		return status.Errorf(codes.Internal, "failed to read metrics: %v", err)
 	}
 
@@ -391,6 +427,72 @@ func (s *GRPCServer) QueryMetrics(req *persistpb.QueryMetricsRequest, stream per
 	}
 }
 
+// ArchiveJob implements the ArchiveJob RPC
+func (s *GRPCServer) ArchiveJob(ctx context.Context, req *persistpb.ArchiveJobRequest) (*persistpb.ArchiveJobResponse, error) {
+	// Check authorization
+	if err := s.auth.Authorized(ctx, auth.ArchiveJobOp); err != nil {
+		return &persistpb.ArchiveJobResponse{
+			Success: false,
+			Message: fmt.Sprintf("Unauthorized: %v", err),
+		}, nil
+	}
+
+	s.logger.Info("ArchiveJob request", "jobID", req.JobId, "bytes", len(req.Record))
+
+	if req.JobId == "" {
+		return &persistpb.ArchiveJobResponse{
+			Success: false,
+			Message: "Job ID cannot be empty",
+		}, nil
+	}
+	if len(req.Record) == 0 {
+		return &persistpb.ArchiveJobResponse{
+			Success: false,
+			Message: "Job record cannot be empty",
+		}, nil
+	}
+
+	if err := s.backend.ArchiveJob(req.JobId, req.Record); err != nil {
+		s.logger.Error("Failed to archive job", "jobID", req.JobId, "error", err)
+		return &persistpb.ArchiveJobResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to archive job: %v", err),
+		}, nil
+	}
+
+	return &persistpb.ArchiveJobResponse{
+		Success: true,
+		Message: "Job archived successfully",
+	}, nil
+}
+
+// QueryJobDurations implements the QueryJobDurations RPC
+func (s *GRPCServer) QueryJobDurations(ctx context.Context, req *persistpb.QueryJobDurationsRequest) (*persistpb.QueryJobDurationsResponse, error) {
+	// Check authorization
+	if err := s.auth.Authorized(ctx, auth.QueryMetricsOp); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("QueryJobDurations request", "names", len(req.Names), "limit", req.Limit)
+
+	runs, err := s.backend.JobDurations(req.Names, int(req.Limit))
+	if err != nil {
+		s.logger.Error("Failed to read job durations", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to read job durations: %v", err)
+	}
+
+	resp := &persistpb.QueryJobDurationsResponse{}
+	for _, run := range runs {
+		resp.Durations = append(resp.Durations, &persistpb.JobDuration{
+			JobId:     run.JobID,
+			Name:      run.Name,
+			StartTime: run.Start.UnixNano(),
+			EndTime:   run.End.UnixNano(),
+		})
+	}
+	return resp, nil
+}
+
 // DeleteJob implements the DeleteJob RPC
 func (s *GRPCServer) DeleteJob(ctx context.Context, req *persistpb.DeleteJobRequest) (*persistpb.DeleteJobResponse, error) {
 	// Check authorization
@@ -6,6 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/ehsaniara/joblet/internal/joblet/auth/authfakes"
 	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
 	persistpb "github.com/ehsaniara/joblet/internal/proto/gen/persist"
@@ -366,6 +369,47 @@ func TestQueryMetricsSuccess(t *testing.T) {
 	}
 }
 
+func TestQueryMetricsExpression(t *testing.T) {
+	backend := &storagefakes.FakeBackend{}
+	authorization := &authfakes.FakeGRPCAuthorization{}
+	reader := &storage.MetricReader{
+		Channel: make(chan *ipcpb.Metric, 10),
+		Error:   make(chan error, 1),
+		Done:    make(chan struct{}),
+	}
+	for i, memory := range []int64{1024, 4096, 2048} {
+		reader.Channel <- &ipcpb.Metric{JobId: "test-job", Timestamp: int64(i) * int64(time.Second), Data: &ipcpb.MetricData{CpuUsage: 1, MemoryUsage: memory}}
+	}
+	close(reader.Error)
+	close(reader.Channel)
+	backend.ReadMetricsReturns(reader, nil)
+	server := NewGRPCServer(&config.ServerConfig{}, backend, logger.New(), authorization, &config.SecurityConfig{})
+
+	stream := &mockQueryMetricsServer{ctx: context.Background()}
+	err := server.QueryMetrics(&persistpb.QueryMetricsRequest{Query: `max(memory_usage{job_id="test-job"})`}, stream)
+	if err != nil {
+		t.Fatalf("QueryMetrics: %v", err)
+	}
+	if len(stream.sentMetrics) != 1 || stream.sentMetrics[0].Data.MemoryUsage != 4096 || stream.sentMetrics[0].Data.CpuUsage != 0 {
+		t.Errorf("sent %v, want one sample of the largest memory usage", stream.sentMetrics)
+	}
+	if _, query := backend.ReadMetricsArgsForCall(0); query.JobID != "test-job" {
+		t.Errorf("backend queried job %q", query.JobID)
+	}
+
+	for _, req := range []*persistpb.QueryMetricsRequest{
+		{JobId: "test-job", Query: "median(cpu_usage)"},
+		{JobId: "other-job", Query: `cpu_usage{job_id="test-job"}`},
+		{Query: "cpu_usage"},
+		{JobId: "test-job", Step: -1},
+	} {
+		err := server.QueryMetrics(req, &mockQueryMetricsServer{ctx: context.Background()})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("QueryMetrics(%v) = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
 func TestDeleteJobSuccess(t *testing.T) {
 	backend := &storagefakes.FakeBackend{}
 	log := logger.New()
@@ -442,3 +486,47 @@ func TestDeleteJobEmptyID(t *testing.T) {
 		t.Errorf("Expected empty ID error message, got '%s'", resp.Message)
 	}
 }
+
+func TestArchiveJob(t *testing.T) {
+	backend := &storagefakes.FakeBackend{}
+	log := logger.New()
+	authorization := &authfakes.FakeGRPCAuthorization{}
+
+	authorization.AuthorizedReturns(nil)
+	backend.ArchiveJobReturns(nil)
+
+	cfg := &config.ServerConfig{
+		GRPCAddress: ":50053",
+	}
+	security := &config.SecurityConfig{}
+
+	server := NewGRPCServer(cfg, backend, log, authorization, security)
+
+	resp, err := server.ArchiveJob(context.Background(), &persistpb.ArchiveJobRequest{
+		JobId:  "test-job",
+		Record: []byte(`{"id":"test-job"}`),
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if !resp.Success {
+		t.Errorf("Expected success, got '%s'", resp.Message)
+	}
+
+	if backend.ArchiveJobCallCount() != 1 {
+		t.Fatal("Expected ArchiveJob to be called once")
+	}
+	jobID, record := backend.ArchiveJobArgsForCall(0)
+	if jobID != "test-job" || string(record) != `{"id":"test-job"}` {
+		t.Errorf("Expected test-job and its record, got '%s' %s", jobID, record)
+	}
+
+	// An empty record is rejected without reaching the backend
+	resp, _ = server.ArchiveJob(context.Background(), &persistpb.ArchiveJobRequest{JobId: "test-job"})
+	if resp.Success || resp.Message != "Job record cannot be empty" {
+		t.Errorf("Expected empty record error, got '%s'", resp.Message)
+	}
+	if backend.ArchiveJobCallCount() != 1 {
+		t.Error("Expected ArchiveJob not to be called for an empty record")
+	}
+}
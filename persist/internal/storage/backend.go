@@ -2,7 +2,10 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
 	"github.com/ehsaniara/joblet/persist/internal/config"
@@ -21,6 +24,12 @@ type Backend interface {
 
 	// Management operations
 	DeleteJob(jobID string) error
+	// ArchiveJob stores the JSON record of a job removed from the joblet
+	// server; DeleteJob removes it along with the job's logs
+	ArchiveJob(jobID string, record []byte) error
+	// JobDurations returns the archived completed runs of the named jobs,
+	// newest first and at most limit per name (0 = all)
+	JobDurations(names []string, limit int) ([]JobDuration, error)
 
 	// Lifecycle
 	Close() error
@@ -43,10 +52,67 @@ type MetricQuery struct {
 	StartTime   *int64
 	EndTime     *int64
 	Aggregation string
+	Metric      string // Only field of the samples returned, empty for all
+	Step        int64  // Aggregation step in nanoseconds, 0 = 1 minute
 	Limit       int
 	Offset      int
 }
 
+// JobDuration is one completed run of a named job, read from its archived
+// record
+type JobDuration struct {
+	JobID string
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// archivedRecord holds the fields of an archived job record that
+// JobDurations reads
+type archivedRecord struct {
+	Uuid      string
+	Name      string
+	Status    string
+	StartTime time.Time
+	EndTime   *time.Time
+}
+
+// completedRun returns the run recorded in an archived job record when the
+// job completed and its name is wanted
+func completedRun(data []byte, wanted map[string]bool) (JobDuration, bool) {
+	var record archivedRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return JobDuration{}, false
+	}
+	if !wanted[record.Name] || record.Status != "COMPLETED" || record.EndTime == nil {
+		return JobDuration{}, false
+	}
+	return JobDuration{JobID: record.Uuid, Name: record.Name, Start: record.StartTime, End: *record.EndTime}, true
+}
+
+// newestRuns orders runs newest first per name and keeps at most limit of
+// each name (0 = all)
+func newestRuns(runs []JobDuration, limit int) []JobDuration {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].Name != runs[j].Name {
+			return runs[i].Name < runs[j].Name
+		}
+		return runs[i].End.After(runs[j].End)
+	})
+	if limit <= 0 {
+		return runs
+	}
+	kept := runs[:0]
+	count := make(map[string]int)
+	for _, run := range runs {
+		if count[run.Name] < limit {
+			kept = append(kept, run)
+			count[run.Name]++
+		}
+	}
+	return kept
+}
+
 // LogReader provides streaming access to logs
 type LogReader struct {
 	Channel chan *ipcpb.LogLine
@@ -65,9 +131,11 @@ type MetricReader struct {
 func NewBackend(cfg *config.StorageConfig, nodeID string, log *logger.Logger) (Backend, error) {
 	switch cfg.Type {
 	case "local":
-		return NewLocalBackend(cfg, log)
+		return newLocalStorage(cfg, log)
 	case "cloudwatch":
 		return NewCloudWatchBackend(cfg, nodeID, log)
+	case "clickhouse":
+		return NewClickHouseBackend(cfg, nodeID, log)
 	case "s3":
 		return nil, fmt.Errorf("S3 backend not implemented yet (v2.0)")
 	default:
@@ -0,0 +1,610 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	"github.com/ehsaniara/joblet/persist/internal/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// clickHouseIdentifier restricts database and table names, which are
+// interpolated into SQL
+var clickHouseIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// clickHouseTimeFormat is the DateTime64(9) text format accepted on insert
+const clickHouseTimeFormat = "2006-01-02 15:04:05.000000000"
+
+// clickHouseAggregations maps MetricQuery.Aggregation to ClickHouse
+// expressions of a column
+var clickHouseAggregations = map[string]string{
+	"avg":   "avg(%s)",
+	"min":   "min(%s)",
+	"max":   "max(%s)",
+	"sum":   "sum(%s)",
+	"count": "count(%s)",
+	"last":  "argMax(%s, timestamp)",
+}
+
+// ClickHouseBackend implements the Backend interface with metrics stored in
+// ClickHouse for long-range analytics and logs on the local filesystem.
+//
+// Metric samples are buffered and inserted in batches over the ClickHouse HTTP
+// interface. The table is partitioned by month and expires rows with a TTL, so
+// dashboards can aggregate months of samples directly in ClickHouse.
+type ClickHouseBackend struct {
+	config *config.ClickHouseConfig
+	logs   Backend
+	client *http.Client
+	table  string // database.table
+	ttl    int    // Days, 0 = never expire
+	logger *logger.Logger
+
+	flushInterval time.Duration
+
+	// Rows waiting for the next batch insert
+	pending   []clickHouseInsertRow
+	pendingMu sync.Mutex
+	flushMu   sync.Mutex // Serializes inserts so rows stay in order
+
+	flushNow chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// clickHouseMetricValues are the metric columns shared by inserts and selects
+type clickHouseMetricValues struct {
+	CPUUsage       float64 `json:"cpu_usage"`
+	MemoryUsage    int64   `json:"memory_usage"`
+	GPUUsage       float64 `json:"gpu_usage"`
+	DiskReadBytes  int64   `json:"disk_read_bytes"`
+	DiskWriteBytes int64   `json:"disk_write_bytes"`
+	DiskReadOps    int64   `json:"disk_read_ops"`
+	DiskWriteOps   int64   `json:"disk_write_ops"`
+	NetRxBytes     int64   `json:"net_rx_bytes"`
+	NetTxBytes     int64   `json:"net_tx_bytes"`
+	NetRxPackets   int64   `json:"net_rx_packets"`
+	NetTxPackets   int64   `json:"net_tx_packets"`
+}
+
+// clickHouseInsertRow is one metric sample in JSONEachRow insert format
+type clickHouseInsertRow struct {
+	NodeID    string `json:"node_id"`
+	JobID     string `json:"job_id"`
+	Timestamp string `json:"timestamp"`
+	Sequence  uint64 `json:"sequence"`
+	clickHouseMetricValues
+}
+
+// clickHouseSelectRow is one metric sample returned by a query
+type clickHouseSelectRow struct {
+	TimestampNs int64 `json:"timestamp_ns"`
+	clickHouseMetricValues
+}
+
+// NewClickHouseBackend creates a ClickHouse metrics backend, creating the
+// database and table if they don't exist
+func NewClickHouseBackend(cfg *config.StorageConfig, nodeID string, log *logger.Logger) (Backend, error) {
+	if log == nil {
+		log = logger.New().WithField("component", "clickhouse-backend")
+	}
+
+	chConfig := cfg.ClickHouse
+	chConfig.NodeID = nodeID
+
+	// Set defaults
+	if chConfig.URL == "" {
+		chConfig.URL = "http://localhost:8123"
+	}
+	if chConfig.Database == "" {
+		chConfig.Database = "joblet"
+	}
+	if chConfig.Table == "" {
+		chConfig.Table = "job_metrics"
+	}
+	if chConfig.BatchSize == 0 {
+		chConfig.BatchSize = 1000
+	}
+	if chConfig.MaxBufferedRows == 0 {
+		chConfig.MaxBufferedRows = 100000
+	}
+	if !clickHouseIdentifier.MatchString(chConfig.Database) {
+		return nil, fmt.Errorf("invalid clickhouse database name: %s", chConfig.Database)
+	}
+	if !clickHouseIdentifier.MatchString(chConfig.Table) {
+		return nil, fmt.Errorf("invalid clickhouse table name: %s", chConfig.Table)
+	}
+	if _, err := url.Parse(chConfig.URL); err != nil {
+		return nil, fmt.Errorf("invalid clickhouse url: %w", err)
+	}
+
+	flushInterval, err := parseDurationOrDefault(chConfig.FlushInterval, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clickhouse flush_interval: %w", err)
+	}
+	timeout, err := parseDurationOrDefault(chConfig.Timeout, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clickhouse timeout: %w", err)
+	}
+
+	// 0 = follow the general metrics retention, -1 = never expire
+	ttl := chConfig.TTLDays
+	if ttl == 0 {
+		ttl = cfg.Retention.MetricsDays
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	// Logs are not a good fit for ClickHouse and stay on disk
+	logs, err := newLocalStorage(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &ClickHouseBackend{
+		config:        &chConfig,
+		logs:          logs,
+		client:        &http.Client{Timeout: timeout},
+		table:         chConfig.Database + "." + chConfig.Table,
+		ttl:           ttl,
+		logger:        log.WithField("backend", "clickhouse"),
+		flushInterval: flushInterval,
+		flushNow:      make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	if err := backend.ensureSchema(context.Background()); err != nil {
+		_ = logs.Close()
+		return nil, fmt.Errorf("failed to create clickhouse schema: %w", err)
+	}
+
+	go backend.flushLoop()
+
+	log.Info("ClickHouse backend initialized",
+		"url", chConfig.URL,
+		"table", backend.table,
+		"ttlDays", ttl,
+		"batchSize", chConfig.BatchSize)
+
+	return backend, nil
+}
+
+// parseDurationOrDefault parses a config duration, using def when empty
+func parseDurationOrDefault(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := config.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive: %s", value)
+	}
+	return d, nil
+}
+
+// ensureSchema creates the database and the metrics table. Rows are ordered
+// by job and time for per-job queries and partitioned by month so the TTL
+// drops whole partitions.
+func (b *ClickHouseBackend) ensureSchema(ctx context.Context) error {
+	if err := b.exec(ctx, "CREATE DATABASE IF NOT EXISTS "+b.config.Database); err != nil {
+		return err
+	}
+	return b.exec(ctx, b.createTableQuery())
+}
+
+// createTableQuery returns the CREATE TABLE statement of the metrics table
+func (b *ClickHouseBackend) createTableQuery() string {
+	var q strings.Builder
+	fmt.Fprintf(&q, "CREATE TABLE IF NOT EXISTS %s (\n", b.table)
+	q.WriteString(`  node_id LowCardinality(String),
+  job_id String,
+  timestamp DateTime64(9, 'UTC'),
+  sequence UInt64,
+  cpu_usage Float64,
+  memory_usage Int64,
+  gpu_usage Float64,
+  disk_read_bytes Int64,
+  disk_write_bytes Int64,
+  disk_read_ops Int64,
+  disk_write_ops Int64,
+  net_rx_bytes Int64,
+  net_tx_bytes Int64,
+  net_rx_packets Int64,
+  net_tx_packets Int64
+)
+ENGINE = MergeTree
+PARTITION BY toYYYYMM(timestamp)
+ORDER BY (job_id, timestamp)`)
+	if b.ttl > 0 {
+		fmt.Fprintf(&q, "\nTTL toDateTime(timestamp) + INTERVAL %d DAY DELETE", b.ttl)
+	}
+	return q.String()
+}
+
+// WriteLogs writes log lines to the local backend
+func (b *ClickHouseBackend) WriteLogs(jobID string, logs []*ipcpb.LogLine) error {
+	return b.logs.WriteLogs(jobID, logs)
+}
+
+// WriteMetrics buffers metric samples for the next batch insert
+func (b *ClickHouseBackend) WriteMetrics(jobID string, metrics []*ipcpb.Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	b.pendingMu.Lock()
+	for _, metric := range metrics {
+		if metric.Data == nil {
+			continue
+		}
+		b.pending = append(b.pending, b.toInsertRow(jobID, metric))
+	}
+	if dropped := len(b.pending) - b.config.MaxBufferedRows; dropped > 0 {
+		// ClickHouse has been unreachable for a while; keep the newest samples
+		b.pending = append(b.pending[:0], b.pending[dropped:]...)
+		b.logger.Warn("metric buffer full, dropped oldest samples", "dropped", dropped)
+	}
+	full := len(b.pending) >= b.config.BatchSize
+	b.pendingMu.Unlock()
+
+	if full {
+		select {
+		case b.flushNow <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// toInsertRow flattens a metric sample into table columns
+func (b *ClickHouseBackend) toInsertRow(jobID string, metric *ipcpb.Metric) clickHouseInsertRow {
+	data := metric.Data
+	row := clickHouseInsertRow{
+		NodeID:    b.config.NodeID,
+		JobID:     jobID,
+		Timestamp: time.Unix(0, metric.Timestamp).UTC().Format(clickHouseTimeFormat),
+		Sequence:  metric.Sequence,
+		clickHouseMetricValues: clickHouseMetricValues{
+			CPUUsage:    data.CpuUsage,
+			MemoryUsage: data.MemoryUsage,
+			GPUUsage:    data.GpuUsage,
+		},
+	}
+	if data.DiskIo != nil {
+		row.DiskReadBytes = data.DiskIo.ReadBytes
+		row.DiskWriteBytes = data.DiskIo.WriteBytes
+		row.DiskReadOps = data.DiskIo.ReadOps
+		row.DiskWriteOps = data.DiskIo.WriteOps
+	}
+	if data.NetworkIo != nil {
+		row.NetRxBytes = data.NetworkIo.RxBytes
+		row.NetTxBytes = data.NetworkIo.TxBytes
+		row.NetRxPackets = data.NetworkIo.RxPackets
+		row.NetTxPackets = data.NetworkIo.TxPackets
+	}
+	return row
+}
+
+// flushLoop inserts buffered rows when a batch fills up or the flush interval
+// elapses
+func (b *ClickHouseBackend) flushLoop() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		case <-b.flushNow:
+		}
+		if err := b.flush(context.Background()); err != nil {
+			b.logger.Warn("failed to insert metrics into ClickHouse, will retry", "error", err)
+		}
+	}
+}
+
+// flush inserts all buffered rows in batches. Rows of a failed batch are put
+// back in front of the buffer for the next attempt.
+func (b *ClickHouseBackend) flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.pendingMu.Lock()
+	rows := b.pending
+	b.pending = nil
+	b.pendingMu.Unlock()
+
+	for len(rows) > 0 {
+		n := min(len(rows), b.config.BatchSize)
+		if err := b.insert(ctx, rows[:n]); err != nil {
+			b.pendingMu.Lock()
+			b.pending = append(rows, b.pending...)
+			b.pendingMu.Unlock()
+			return err
+		}
+		rows = rows[n:]
+	}
+	return nil
+}
+
+// insert writes one batch of rows with a single INSERT
+func (b *ClickHouseBackend) insert(ctx context.Context, rows []clickHouseInsertRow) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode metric row: %w", err)
+		}
+	}
+
+	resp, err := b.do(ctx, "INSERT INTO "+b.table+" FORMAT JSONEachRow", &body)
+	if err != nil {
+		return err
+	}
+	resp.Close()
+
+	b.logger.Debug("inserted metrics into ClickHouse", "rows", len(rows), "table", b.table)
+	return nil
+}
+
+// ReadLogs reads log lines from the local backend
+func (b *ClickHouseBackend) ReadLogs(ctx context.Context, query *LogQuery) (*LogReader, error) {
+	return b.logs.ReadLogs(ctx, query)
+}
+
+// ReadMetrics queries metric samples of a job from ClickHouse. With an
+// aggregation (avg, min, max, sum, count, last) samples are rolled up per
+// step, a minute by default.
+func (b *ClickHouseBackend) ReadMetrics(ctx context.Context, query *MetricQuery) (*MetricReader, error) {
+	sql, err := b.selectQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make buffered samples of running jobs visible to the query
+	if err := b.flush(ctx); err != nil {
+		b.logger.Warn("failed to flush metrics before query", "error", err)
+	}
+
+	reader := &MetricReader{
+		Channel: make(chan *ipcpb.Metric, 100),
+		Error:   make(chan error, 1),
+		Done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(reader.Channel)
+		defer close(reader.Error)
+		defer close(reader.Done)
+
+		if err := b.streamMetrics(ctx, query.JobID, sql, reader.Channel); err != nil {
+			reader.Error <- err
+		}
+	}()
+
+	return reader, nil
+}
+
+// aggregatesMetrics marks the backend as rolling up samples in ClickHouse
+func (b *ClickHouseBackend) aggregatesMetrics() {}
+
+// selectQuery maps a MetricQuery to SQL
+func (b *ClickHouseBackend) selectQuery(query *MetricQuery) (string, error) {
+	var columns string
+	switch query.Aggregation {
+	case "", "none":
+		columns = `toUnixTimestamp64Nano(timestamp) AS timestamp_ns, cpu_usage, memory_usage, gpu_usage,
+  disk_read_bytes, disk_write_bytes, disk_read_ops, disk_write_ops,
+  net_rx_bytes, net_tx_bytes, net_rx_packets, net_tx_packets`
+	default:
+		expr, ok := clickHouseAggregations[query.Aggregation]
+		if !ok {
+			return "", fmt.Errorf("unsupported metric aggregation: %s", query.Aggregation)
+		}
+		fn := func(column string) string { return fmt.Sprintf(expr, column) }
+		if query.Step > 0 {
+			columns = fmt.Sprintf("intDiv(toUnixTimestamp64Nano(timestamp), %[1]d) * %[1]d AS timestamp_ns, ", query.Step)
+		} else {
+			columns = "toInt64(toUnixTimestamp(toStartOfMinute(timestamp))) * 1000000000 AS timestamp_ns, "
+		}
+		columns += fmt.Sprintf("%s AS cpu_usage, toInt64(%s) AS memory_usage, %s AS gpu_usage,\n",
+			fn("cpu_usage"), fn("memory_usage"), fn("gpu_usage"))
+		for i, column := range []string{"disk_read_bytes", "disk_write_bytes", "disk_read_ops", "disk_write_ops",
+			"net_rx_bytes", "net_tx_bytes", "net_rx_packets", "net_tx_packets"} {
+			if i > 0 {
+				columns += ", "
+			}
+			columns += fmt.Sprintf("toInt64(%s) AS %s", fn(column), column)
+		}
+	}
+
+	var q strings.Builder
+	fmt.Fprintf(&q, "SELECT %s\nFROM %s\nWHERE job_id = %s", columns, b.table, quoteClickHouseString(query.JobID))
+	if query.StartTime != nil {
+		fmt.Fprintf(&q, " AND timestamp >= fromUnixTimestamp64Nano(toInt64(%d), 'UTC')", *query.StartTime)
+	}
+	if query.EndTime != nil {
+		fmt.Fprintf(&q, " AND timestamp <= fromUnixTimestamp64Nano(toInt64(%d), 'UTC')", *query.EndTime)
+	}
+	if query.Aggregation != "" && query.Aggregation != "none" {
+		q.WriteString("\nGROUP BY timestamp_ns")
+	}
+	q.WriteString("\nORDER BY timestamp_ns")
+	if query.Limit > 0 {
+		fmt.Fprintf(&q, "\nLIMIT %d", query.Limit)
+	}
+	if query.Offset > 0 {
+		fmt.Fprintf(&q, "\nOFFSET %d", query.Offset)
+	}
+	q.WriteString("\nFORMAT JSONEachRow")
+	return q.String(), nil
+}
+
+// streamMetrics runs a select and sends each returned row to ch
+func (b *ClickHouseBackend) streamMetrics(ctx context.Context, jobID, sql string, ch chan<- *ipcpb.Metric) error {
+	resp, err := b.do(ctx, sql, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	scanner := bufio.NewScanner(resp)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var row clickHouseSelectRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return fmt.Errorf("failed to decode metric row: %w", err)
+		}
+
+		metric := &ipcpb.Metric{
+			JobId:     jobID,
+			Timestamp: row.TimestampNs,
+			Data: &ipcpb.MetricData{
+				CpuUsage:    row.CPUUsage,
+				MemoryUsage: row.MemoryUsage,
+				GpuUsage:    row.GPUUsage,
+				DiskIo: &ipcpb.DiskIO{
+					ReadBytes:  row.DiskReadBytes,
+					WriteBytes: row.DiskWriteBytes,
+					ReadOps:    row.DiskReadOps,
+					WriteOps:   row.DiskWriteOps,
+				},
+				NetworkIo: &ipcpb.NetworkIO{
+					RxBytes:   row.NetRxBytes,
+					TxBytes:   row.NetTxBytes,
+					RxPackets: row.NetRxPackets,
+					TxPackets: row.NetTxPackets,
+				},
+			},
+		}
+
+		select {
+		case ch <- metric:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read metrics from ClickHouse: %w", err)
+	}
+	return nil
+}
+
+// ArchiveJob keeps the job record next to the job's logs on the local
+// filesystem
+func (b *ClickHouseBackend) ArchiveJob(jobID string, record []byte) error {
+	return b.logs.ArchiveJob(jobID, record)
+}
+
+// JobDurations reads the job records archived on the local filesystem
+func (b *ClickHouseBackend) JobDurations(names []string, limit int) ([]JobDuration, error) {
+	return b.logs.JobDurations(names, limit)
+}
+
+// DeleteJob deletes the logs and metric samples of a job. ClickHouse removes
+// the rows asynchronously.
+func (b *ClickHouseBackend) DeleteJob(jobID string) error {
+	b.pendingMu.Lock()
+	kept := b.pending[:0]
+	for _, row := range b.pending {
+		if row.JobID != jobID {
+			kept = append(kept, row)
+		}
+	}
+	b.pending = kept
+	b.pendingMu.Unlock()
+
+	var errs []error
+	if err := b.logs.DeleteJob(jobID); err != nil {
+		errs = append(errs, fmt.Errorf("logs: %w", err))
+	}
+	if err := b.exec(context.Background(), fmt.Sprintf("ALTER TABLE %s DELETE WHERE job_id = %s", b.table, quoteClickHouseString(jobID))); err != nil {
+		errs = append(errs, fmt.Errorf("metrics: %w", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to delete job: %v", errs)
+	}
+
+	b.logger.Info("deleted job from ClickHouse backend", "jobId", jobID, "table", b.table)
+	return nil
+}
+
+// Close inserts the remaining buffered rows and closes the local log backend
+func (b *ClickHouseBackend) Close() error {
+	close(b.stop)
+	<-b.done
+
+	var errs []error
+	if err := b.flush(context.Background()); err != nil {
+		errs = append(errs, fmt.Errorf("flush metrics: %w", err))
+	}
+	if err := b.logs.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close logs: %w", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close ClickHouse backend: %v", errs)
+	}
+
+	b.logger.Info("ClickHouse backend closed")
+	return nil
+}
+
+// exec runs a statement that returns no rows
+func (b *ClickHouseBackend) exec(ctx context.Context, sql string) error {
+	resp, err := b.do(ctx, sql, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
+
+// do sends a query to the ClickHouse HTTP interface. The query goes in the
+// URL and body carries insert data, if any.
+func (b *ClickHouseBackend) do(ctx context.Context, sql string, body io.Reader) (io.ReadCloser, error) {
+	params := url.Values{}
+	params.Set("query", sql)
+	// Return 64-bit integers as JSON numbers instead of strings
+	params.Set("output_format_json_quote_64bit_integers", "0")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(b.config.URL, "/")+"/?"+params.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clickhouse request: %w", err)
+	}
+	if b.config.Username != "" {
+		req.Header.Set("X-ClickHouse-User", b.config.Username)
+		req.Header.Set("X-ClickHouse-Key", b.config.Password)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("clickhouse returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp.Body, nil
+}
+
+// quoteClickHouseString quotes s as a ClickHouse string literal
+func quoteClickHouseString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,403 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	"github.com/ehsaniara/joblet/persist/internal/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// fakeClickHouse records the queries and insert bodies sent to the HTTP
+// interface and answers selects with canned JSONEachRow output
+type fakeClickHouse struct {
+	mu         sync.Mutex
+	queries    []string
+	inserted   []string
+	selectRows string
+	fail       bool
+}
+
+func (f *fakeClickHouse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query().Get("query")
+	f.queries = append(f.queries, query)
+	if f.fail {
+		http.Error(w, "Code: 241. DB::Exception: Memory limit exceeded", http.StatusInternalServerError)
+		return
+	}
+	if strings.HasPrefix(query, "INSERT") {
+		body, _ := io.ReadAll(r.Body)
+		scanner := bufio.NewScanner(strings.NewReader(string(body)))
+		for scanner.Scan() {
+			f.inserted = append(f.inserted, scanner.Text())
+		}
+		return
+	}
+	if strings.HasPrefix(query, "SELECT") {
+		_, _ = io.WriteString(w, f.selectRows)
+	}
+}
+
+func (f *fakeClickHouse) snapshot() (queries, inserted []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.queries...), append([]string(nil), f.inserted...)
+}
+
+func newTestClickHouseBackend(t *testing.T, fake *fakeClickHouse, chConfig config.ClickHouseConfig) *ClickHouseBackend {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	tmpDir := t.TempDir()
+	chConfig.URL = server.URL
+	cfg := &config.StorageConfig{
+		Type: "clickhouse",
+		Local: config.LocalConfig{
+			Logs:    config.LogStorageConfig{Directory: filepath.Join(tmpDir, "logs")},
+			Metrics: config.MetricStorageConfig{Directory: filepath.Join(tmpDir, "metrics")},
+		},
+		ClickHouse: chConfig,
+		Retention:  config.RetentionConfig{MetricsDays: 180},
+	}
+
+	backend, err := NewBackend(cfg, "node-1", logger.New())
+	if err != nil {
+		t.Fatalf("Failed to create clickhouse backend: %v", err)
+	}
+	t.Cleanup(func() { _ = backend.Close() })
+	return backend.(*ClickHouseBackend)
+}
+
+func TestNewClickHouseBackend_Schema(t *testing.T) {
+	fake := &fakeClickHouse{}
+	newTestClickHouseBackend(t, fake, config.ClickHouseConfig{Database: "analytics"})
+
+	queries, _ := fake.snapshot()
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 schema queries, got %d: %v", len(queries), queries)
+	}
+	if queries[0] != "CREATE DATABASE IF NOT EXISTS analytics" {
+		t.Errorf("Unexpected database query: %s", queries[0])
+	}
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS analytics.job_metrics",
+		"timestamp DateTime64(9, 'UTC')",
+		"PARTITION BY toYYYYMM(timestamp)",
+		"ORDER BY (job_id, timestamp)",
+		"TTL toDateTime(timestamp) + INTERVAL 180 DAY DELETE",
+	} {
+		if !strings.Contains(queries[1], want) {
+			t.Errorf("Table query missing %q:\n%s", want, queries[1])
+		}
+	}
+}
+
+func TestNewClickHouseBackend_NoTTL(t *testing.T) {
+	fake := &fakeClickHouse{}
+	backend := newTestClickHouseBackend(t, fake, config.ClickHouseConfig{TTLDays: -1})
+
+	if strings.Contains(backend.createTableQuery(), "TTL") {
+		t.Errorf("Expected no TTL with ttl_days: -1:\n%s", backend.createTableQuery())
+	}
+}
+
+func TestNewClickHouseBackend_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.ClickHouseConfig
+	}{
+		{"database with quote", config.ClickHouseConfig{Database: "joblet'; DROP"}},
+		{"table with dot", config.ClickHouseConfig{Table: "a.b"}},
+		{"bad flush interval", config.ClickHouseConfig{FlushInterval: "soon"}},
+		{"negative timeout", config.ClickHouseConfig{Timeout: "-1s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.StorageConfig{Type: "clickhouse", ClickHouse: tt.config}
+			if _, err := NewBackend(cfg, "node-1", logger.New()); err == nil {
+				t.Error("Expected error for invalid config")
+			}
+		})
+	}
+}
+
+func TestNewClickHouseBackend_Unreachable(t *testing.T) {
+	fake := &fakeClickHouse{fail: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	cfg := &config.StorageConfig{
+		Type: "clickhouse",
+		Local: config.LocalConfig{
+			Logs:    config.LogStorageConfig{Directory: filepath.Join(tmpDir, "logs")},
+			Metrics: config.MetricStorageConfig{Directory: filepath.Join(tmpDir, "metrics")},
+		},
+		ClickHouse: config.ClickHouseConfig{URL: server.URL},
+	}
+
+	_, err := NewBackend(cfg, "node-1", logger.New())
+	if err == nil || !strings.Contains(err.Error(), "Memory limit exceeded") {
+		t.Errorf("Expected ClickHouse error message, got %v", err)
+	}
+}
+
+func TestClickHouseBackend_WriteMetrics_Batches(t *testing.T) {
+	fake := &fakeClickHouse{}
+	backend := newTestClickHouseBackend(t, fake, config.ClickHouseConfig{BatchSize: 2, FlushInterval: "1h"})
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC).UnixNano()
+	metrics := []*ipcpb.Metric{
+		{JobId: "job-1", Timestamp: ts, Sequence: 1, Data: &ipcpb.MetricData{
+			CpuUsage:    1.5,
+			MemoryUsage: 1024,
+			DiskIo:      &ipcpb.DiskIO{ReadBytes: 10},
+			NetworkIo:   &ipcpb.NetworkIO{TxPackets: 7},
+		}},
+		{JobId: "job-1", Timestamp: ts + 1, Sequence: 2},
+		{JobId: "job-1", Timestamp: ts + 2, Sequence: 3, Data: &ipcpb.MetricData{}},
+		{JobId: "job-1", Timestamp: ts + 3, Sequence: 4, Data: &ipcpb.MetricData{}},
+	}
+	if err := backend.WriteMetrics("job-1", metrics); err != nil {
+		t.Fatalf("WriteMetrics failed: %v", err)
+	}
+	if err := backend.flush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	queries, inserted := fake.snapshot()
+	var inserts int
+	for _, q := range queries {
+		if q == "INSERT INTO joblet.job_metrics FORMAT JSONEachRow" {
+			inserts++
+		}
+	}
+	if inserts != 2 {
+		t.Errorf("Expected 3 rows in 2 batches, got %d inserts", inserts)
+	}
+	if len(inserted) != 3 {
+		t.Fatalf("Expected 3 rows (sample without data skipped), got %d", len(inserted))
+	}
+
+	var row map[string]any
+	if err := json.Unmarshal([]byte(inserted[0]), &row); err != nil {
+		t.Fatalf("Invalid JSON row: %v", err)
+	}
+	want := map[string]any{
+		"node_id":         "node-1",
+		"job_id":          "job-1",
+		"timestamp":       "2024-05-01 12:00:00.123456789",
+		"cpu_usage":       1.5,
+		"memory_usage":    1024.0,
+		"disk_read_bytes": 10.0,
+		"net_tx_packets":  7.0,
+	}
+	for key, value := range want {
+		if row[key] != value {
+			t.Errorf("row[%s] = %v, want %v", key, row[key], value)
+		}
+	}
+}
+
+func TestClickHouseBackend_FlushRetainsRowsOnError(t *testing.T) {
+	fake := &fakeClickHouse{}
+	backend := newTestClickHouseBackend(t, fake, config.ClickHouseConfig{FlushInterval: "1h", MaxBufferedRows: 3})
+
+	fake.mu.Lock()
+	fake.fail = true
+	fake.mu.Unlock()
+
+	for i := 0; i < 5; i++ {
+		_ = backend.WriteMetrics("job-1", []*ipcpb.Metric{{Timestamp: int64(i), Sequence: uint64(i), Data: &ipcpb.MetricData{}}})
+	}
+	if err := backend.flush(context.Background()); err == nil {
+		t.Fatal("Expected flush error")
+	}
+
+	backend.pendingMu.Lock()
+	pending := len(backend.pending)
+	first := backend.pending[0].Sequence
+	backend.pendingMu.Unlock()
+	if pending != 3 || first != 2 {
+		t.Errorf("Expected the 3 newest rows to be kept, got %d rows starting at %d", pending, first)
+	}
+
+	fake.mu.Lock()
+	fake.fail = false
+	fake.mu.Unlock()
+	if err := backend.flush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if _, inserted := fake.snapshot(); len(inserted) != 3 {
+		t.Errorf("Expected 3 rows inserted after recovery, got %d", len(inserted))
+	}
+}
+
+func TestClickHouseBackend_SelectQuery(t *testing.T) {
+	backend := &ClickHouseBackend{table: "joblet.job_metrics"}
+	start, end := int64(1714564800000000000), int64(1717243200000000000)
+
+	tests := []struct {
+		name    string
+		query   *MetricQuery
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "raw samples",
+			query:   &MetricQuery{JobID: "job-1"},
+			want:    []string{"toUnixTimestamp64Nano(timestamp) AS timestamp_ns", "WHERE job_id = 'job-1'", "ORDER BY timestamp_ns", "FORMAT JSONEachRow"},
+			notWant: []string{"GROUP BY", "LIMIT", "OFFSET", "timestamp >="},
+		},
+		{
+			name:  "time range and pagination",
+			query: &MetricQuery{JobID: "job-1", StartTime: &start, EndTime: &end, Limit: 100, Offset: 200},
+			want: []string{
+				"timestamp >= fromUnixTimestamp64Nano(toInt64(1714564800000000000), 'UTC')",
+				"timestamp <= fromUnixTimestamp64Nano(toInt64(1717243200000000000), 'UTC')",
+				"LIMIT 100",
+				"OFFSET 200",
+			},
+		},
+		{
+			name:  "aggregation",
+			query: &MetricQuery{JobID: "job-1", Aggregation: "max"},
+			want:  []string{"toStartOfMinute(timestamp)", "max(cpu_usage) AS cpu_usage", "toInt64(max(net_tx_bytes)) AS net_tx_bytes", "GROUP BY timestamp_ns"},
+		},
+		{
+			name:  "last sample per step",
+			query: &MetricQuery{JobID: "job-1", Aggregation: "last", Step: 300000000000},
+			want: []string{
+				"intDiv(toUnixTimestamp64Nano(timestamp), 300000000000) * 300000000000 AS timestamp_ns",
+				"argMax(cpu_usage, timestamp) AS cpu_usage",
+				"toInt64(argMax(disk_read_ops, timestamp)) AS disk_read_ops",
+				"GROUP BY timestamp_ns",
+			},
+			notWant: []string{"toStartOfMinute"},
+		},
+		{
+			name:  "quoted job ID",
+			query: &MetricQuery{JobID: `x' OR '1'='1`},
+			want:  []string{`WHERE job_id = 'x\' OR \'1\'=\'1'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := backend.selectQuery(tt.query)
+			if err != nil {
+				t.Fatalf("selectQuery failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sql, want) {
+					t.Errorf("Query missing %q:\n%s", want, sql)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(sql, notWant) {
+					t.Errorf("Query should not contain %q:\n%s", notWant, sql)
+				}
+			}
+		})
+	}
+
+	if _, err := backend.selectQuery(&MetricQuery{JobID: "job-1", Aggregation: "median"}); err == nil {
+		t.Error("Expected error for unsupported aggregation")
+	}
+}
+
+func TestClickHouseBackend_ReadMetrics(t *testing.T) {
+	fake := &fakeClickHouse{selectRows: `{"timestamp_ns":1714564800000000000,"cpu_usage":0.5,"memory_usage":2048,"gpu_usage":0,"disk_read_bytes":1,"disk_write_bytes":2,"disk_read_ops":3,"disk_write_ops":4,"net_rx_bytes":5,"net_tx_bytes":6,"net_rx_packets":7,"net_tx_packets":8}
+{"timestamp_ns":1714564801000000000,"cpu_usage":0.75,"memory_usage":4096,"gpu_usage":0,"disk_read_bytes":0,"disk_write_bytes":0,"disk_read_ops":0,"disk_write_ops":0,"net_rx_bytes":0,"net_tx_bytes":0,"net_rx_packets":0,"net_tx_packets":0}
+`}
+	backend := newTestClickHouseBackend(t, fake, config.ClickHouseConfig{FlushInterval: "1h"})
+
+	reader, err := backend.ReadMetrics(context.Background(), &MetricQuery{JobID: "job-1"})
+	if err != nil {
+		t.Fatalf("ReadMetrics failed: %v", err)
+	}
+
+	var metrics []*ipcpb.Metric
+	for metric := range reader.Channel {
+		metrics = append(metrics, metric)
+	}
+	if err := <-reader.Error; err != nil {
+		t.Fatalf("Reader error: %v", err)
+	}
+
+	if len(metrics) != 2 {
+		t.Fatalf("Expected 2 metrics, got %d", len(metrics))
+	}
+	first := metrics[0]
+	if first.JobId != "job-1" || first.Timestamp != 1714564800000000000 || first.Data.MemoryUsage != 2048 {
+		t.Errorf("Unexpected first metric: %v", first)
+	}
+	if first.Data.DiskIo.WriteOps != 4 || first.Data.NetworkIo.TxPackets != 8 {
+		t.Errorf("I/O counters not mapped: %v", first.Data)
+	}
+}
+
+func TestClickHouseBackend_DeleteJob(t *testing.T) {
+	fake := &fakeClickHouse{}
+	backend := newTestClickHouseBackend(t, fake, config.ClickHouseConfig{FlushInterval: "1h"})
+
+	_ = backend.WriteMetrics("job-1", []*ipcpb.Metric{{Data: &ipcpb.MetricData{}}})
+	_ = backend.WriteMetrics("job-2", []*ipcpb.Metric{{Data: &ipcpb.MetricData{}}})
+
+	if err := backend.DeleteJob("job-1"); err != nil {
+		t.Fatalf("DeleteJob failed: %v", err)
+	}
+
+	queries, _ := fake.snapshot()
+	if last := queries[len(queries)-1]; last != "ALTER TABLE joblet.job_metrics DELETE WHERE job_id = 'job-1'" {
+		t.Errorf("Unexpected delete query: %s", last)
+	}
+	backend.pendingMu.Lock()
+	defer backend.pendingMu.Unlock()
+	if len(backend.pending) != 1 || backend.pending[0].JobID != "job-2" {
+		t.Errorf("Expected only job-2 rows to stay buffered, got %v", backend.pending)
+	}
+}
+
+func TestClickHouseBackend_CloseFlushes(t *testing.T) {
+	fake := &fakeClickHouse{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	cfg := &config.StorageConfig{
+		Type: "clickhouse",
+		Local: config.LocalConfig{
+			Logs:    config.LogStorageConfig{Directory: filepath.Join(tmpDir, "logs")},
+			Metrics: config.MetricStorageConfig{Directory: filepath.Join(tmpDir, "metrics")},
+		},
+		ClickHouse: config.ClickHouseConfig{URL: server.URL, FlushInterval: "1h"},
+	}
+	backend, err := NewBackend(cfg, "node-1", logger.New())
+	if err != nil {
+		t.Fatalf("Failed to create clickhouse backend: %v", err)
+	}
+
+	_ = backend.WriteMetrics("job-1", []*ipcpb.Metric{{Data: &ipcpb.MetricData{}}})
+	if err := backend.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, inserted := fake.snapshot(); len(inserted) != 1 {
+		t.Errorf("Expected buffered row to be inserted on close, got %d", len(inserted))
+	}
+}
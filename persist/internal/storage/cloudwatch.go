@@ -811,6 +811,77 @@ func (b *CloudWatchBackend) readMetricsFromStream(ctx context.Context, query *Me
 	return nil
 }
 
+// ArchiveJob writes the job record as a single event to the job's
+// {jobID}-record log stream
+func (b *CloudWatchBackend) ArchiveJob(jobID string, record []byte) error {
+	ctx := context.Background()
+	logGroup := fmt.Sprintf("%s/%s/jobs", b.config.LogGroupPrefix, b.config.NodeID)
+	logStream := fmt.Sprintf("%s-record", jobID)
+
+	if err := b.ensureLogGroup(ctx, logGroup); err != nil {
+		return fmt.Errorf("failed to ensure log group: %w", err)
+	}
+	if err := b.ensureLogStream(ctx, logGroup, logStream); err != nil {
+		return fmt.Errorf("failed to ensure log stream: %w", err)
+	}
+
+	event := types.InputLogEvent{
+		Message:   aws.String(string(record)),
+		Timestamp: aws.Int64(time.Now().UnixMilli()),
+	}
+	if err := b.putLogEvents(ctx, logGroup, logStream, []types.InputLogEvent{event}); err != nil {
+		return fmt.Errorf("failed to put job record: %w", err)
+	}
+
+	b.logger.Debug("archived job record to CloudWatch", "jobId", jobID, "logGroup", logGroup, "logStream", logStream)
+	return nil
+}
+
+// maxRecordPages bounds the FilterLogEvents pages JobDurations reads
+const maxRecordPages = 20
+
+// JobDurations filters the archived job records of the node's log group by
+// job name and status
+func (b *CloudWatchBackend) JobDurations(names []string, limit int) ([]JobDuration, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ctx := context.Background()
+	logGroup := fmt.Sprintf("%s/%s/jobs", b.config.LogGroupPrefix, b.config.NodeID)
+
+	wanted := make(map[string]bool, len(names))
+	nameFilters := make([]string, 0, len(names))
+	for _, name := range names {
+		wanted[name] = true
+		nameFilters = append(nameFilters, fmt.Sprintf("$.Name = %s", strconv.Quote(name)))
+	}
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName:  aws.String(logGroup),
+		FilterPattern: aws.String(fmt.Sprintf(`{ ($.Status = "COMPLETED") && (%s) }`, strings.Join(nameFilters, " || "))),
+	}
+
+	var runs []JobDuration
+	for page := 0; page < maxRecordPages; page++ {
+		resp, err := b.logsClient.FilterLogEvents(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter job records: %w", err)
+		}
+		for _, event := range resp.Events {
+			if event.LogStreamName == nil || !strings.HasSuffix(*event.LogStreamName, "-record") || event.Message == nil {
+				continue
+			}
+			if run, ok := completedRun([]byte(*event.Message), wanted); ok {
+				runs = append(runs, run)
+			}
+		}
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	return newestRuns(runs, limit), nil
+}
+
 // DeleteJob deletes all CloudWatch log streams for a job
 // Note: Metrics are stored in CloudWatch Metrics API and cannot be deleted individually
 func (b *CloudWatchBackend) DeleteJob(jobID string) error {
@@ -818,10 +889,12 @@ func (b *CloudWatchBackend) DeleteJob(jobID string) error {
 	// Single log group per node - only delete job-specific log streams
 	logGroup := fmt.Sprintf("%s/%s/jobs", b.config.LogGroupPrefix, b.config.NodeID)
 
-	// Define the log streams for this job (stdout and stderr only, metrics are in CloudWatch Metrics)
+	// Define the log streams for this job (stdout, stderr and the archived
+	// record; metrics are in CloudWatch Metrics)
 	streams := []string{
 		fmt.Sprintf("%s-stdout", jobID),
 		fmt.Sprintf("%s-stderr", jobID),
+		fmt.Sprintf("%s-record", jobID),
 	}
 
 	// Delete each log stream for this job
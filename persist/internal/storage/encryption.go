@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ehsaniara/joblet/persist/internal/config"
+)
+
+// Encrypted log files are a sequence of chunks, one per written batch:
+//
+//	magic "JLE1" | key id length (1 byte) | key id | nonce (12 bytes) |
+//	ciphertext length (4 bytes, big endian) | AES-256-GCM ciphertext
+//
+// The plaintext of a chunk is a gzip member of JSONL log lines, as in the
+// unencrypted files. The key id is the tenant whose key sealed the chunk
+// (empty for the default key). The GCM tag is the chunk's integrity check;
+// the job, stream and the chunk's offset in the file are authenticated with
+// it, so chunks cannot be swapped between files or reordered unnoticed.
+const (
+	encryptedLogSuffix = ".enc"
+	chunkMagic         = "JLE1"
+	maxChunkSize       = 64 << 20
+)
+
+// ErrChunkIntegrity is returned when an encrypted chunk fails authentication
+var ErrChunkIntegrity = errors.New("log chunk failed integrity check")
+
+// logCipher seals and opens encrypted log chunks with per-tenant keys
+type logCipher struct {
+	keys map[string]cipher.AEAD // Key id (tenant, "" = default) -> cipher
+}
+
+// newLogCipher loads the configured keys; it returns nil when encryption is
+// disabled
+func newLogCipher(cfg config.EncryptionConfig) (*logCipher, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	files := map[string]string{"": cfg.KeyFile}
+	for tenant, keyFile := range cfg.TenantKeys {
+		if len(tenant) > 255 {
+			return nil, fmt.Errorf("tenant name %.32q... too long for a key id", tenant)
+		}
+		files[tenant] = keyFile
+	}
+
+	lc := &logCipher{keys: make(map[string]cipher.AEAD, len(files))}
+	for id, keyFile := range files {
+		aead, err := loadKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		lc.keys[id] = aead
+	}
+	return lc, nil
+}
+
+// loadKey reads a 32-byte AES-256 key, raw or hex encoded
+func loadKey(path string) (cipher.AEAD, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+	key := bytes.TrimSpace(data)
+	if len(key) == 64 {
+		decoded, err := hex.DecodeString(string(key))
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %w", path, err)
+		}
+		key = decoded
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key %s must be 32 bytes (or 64 hex characters), got %d", path, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key %s: %w", path, err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyFor returns the key id sealing the logs of tenant: its own key, or the
+// default key when it has none
+func (lc *logCipher) keyFor(tenant string) string {
+	if _, ok := lc.keys[tenant]; ok && tenant != "" {
+		return tenant
+	}
+	return ""
+}
+
+// chunkAAD is the data authenticated along with a chunk
+func chunkAAD(jobID, stream string, offset int64) []byte {
+	return []byte(jobID + "/" + stream + "/" + strconv.FormatInt(offset, 10))
+}
+
+// seal encrypts plaintext as the chunk starting at offset of the file of
+// jobID's stream
+func (lc *logCipher) seal(tenant, jobID, stream string, offset int64, plaintext []byte) ([]byte, error) {
+	keyID := lc.keyFor(tenant)
+	aead := lc.keys[keyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	ciphertext := aead.Seal(nil, nonce, plaintext, chunkAAD(jobID, stream, offset))
+
+	chunk := make([]byte, 0, len(chunkMagic)+1+len(keyID)+len(nonce)+4+len(ciphertext))
+	chunk = append(chunk, chunkMagic...)
+	chunk = append(chunk, byte(len(keyID)))
+	chunk = append(chunk, keyID...)
+	chunk = append(chunk, nonce...)
+	chunk = binary.BigEndian.AppendUint32(chunk, uint32(len(ciphertext)))
+	return append(chunk, ciphertext...), nil
+}
+
+// openAll reads the chunks of an encrypted file of jobID's stream and writes
+// their plaintext to w, stopping at the first chunk that fails its check
+func (lc *logCipher) openAll(r io.Reader, w io.Writer, jobID, stream string) error {
+	var offset int64
+	header := make([]byte, len(chunkMagic)+1)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("chunk at offset %d: %w", offset, ErrChunkIntegrity)
+		}
+		if string(header[:len(chunkMagic)]) != chunkMagic {
+			return fmt.Errorf("chunk at offset %d: bad magic: %w", offset, ErrChunkIntegrity)
+		}
+
+		keyID := make([]byte, header[len(chunkMagic)])
+		if _, err := io.ReadFull(r, keyID); err != nil {
+			return fmt.Errorf("chunk at offset %d: %w", offset, ErrChunkIntegrity)
+		}
+		aead, ok := lc.keys[string(keyID)]
+		if !ok {
+			return fmt.Errorf("chunk at offset %d is sealed with the key of tenant %q, which is not configured", offset, keyID)
+		}
+
+		rest := make([]byte, aead.NonceSize()+4)
+		if _, err := io.ReadFull(r, rest); err != nil {
+			return fmt.Errorf("chunk at offset %d: %w", offset, ErrChunkIntegrity)
+		}
+		nonce := rest[:aead.NonceSize()]
+		size := binary.BigEndian.Uint32(rest[aead.NonceSize():])
+		if size > maxChunkSize {
+			return fmt.Errorf("chunk at offset %d: size %d: %w", offset, size, ErrChunkIntegrity)
+		}
+		ciphertext := make([]byte, size)
+		if _, err := io.ReadFull(r, ciphertext); err != nil {
+			return fmt.Errorf("chunk at offset %d: %w", offset, ErrChunkIntegrity)
+		}
+
+		plaintext, err := aead.Open(nil, nonce, ciphertext, chunkAAD(jobID, stream, offset))
+		if err != nil {
+			return fmt.Errorf("chunk at offset %d: %w", offset, ErrChunkIntegrity)
+		}
+		if _, err := w.Write(plaintext); err != nil {
+			return err
+		}
+		offset += int64(len(header) + len(keyID) + len(rest) + len(ciphertext))
+	}
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	"github.com/ehsaniara/joblet/persist/internal/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// writeKey writes a hex encoded key of repeated b and returns its path
+func writeKey(t *testing.T, dir string, b byte) string {
+	t.Helper()
+	path := filepath.Join(dir, fmt.Sprintf("key-%d", b))
+	key := strings.Repeat(fmt.Sprintf("%02x", b), 32)
+	if err := os.WriteFile(path, []byte(key+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func encryptedBackend(t *testing.T, tmpDir string, enc config.EncryptionConfig) *LocalBackend {
+	t.Helper()
+	cfg := &config.StorageConfig{
+		Type: "local",
+		Local: config.LocalConfig{
+			Logs:       config.LogStorageConfig{Directory: filepath.Join(tmpDir, "logs")},
+			Metrics:    config.MetricStorageConfig{Directory: filepath.Join(tmpDir, "metrics")},
+			Encryption: enc,
+		},
+	}
+	backend, err := NewLocalBackend(cfg, logger.New())
+	if err != nil {
+		t.Fatalf("NewLocalBackend() error = %v", err)
+	}
+	return backend
+}
+
+// readAll collects the lines of a job, or the read error
+func readAll(t *testing.T, backend *LocalBackend, jobID string) ([]string, error) {
+	t.Helper()
+	reader, err := backend.ReadLogs(context.Background(), &LogQuery{JobID: jobID})
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for line := range reader.Channel {
+		lines = append(lines, string(line.Content))
+	}
+	return lines, <-reader.Error
+}
+
+func TestLocalBackend_EncryptedLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	backend := encryptedBackend(t, tmpDir, config.EncryptionConfig{
+		Enabled:    true,
+		KeyFile:    writeKey(t, tmpDir, 1),
+		TenantKeys: map[string]string{"acme": writeKey(t, tmpDir, 2)},
+	})
+	defer backend.Close()
+
+	for i, content := range []string{"password=hunter2", "second batch"} {
+		logs := []*ipcpb.LogLine{
+			{JobId: "job-1", Tenant: "acme", Stream: ipcpb.StreamType_STREAM_TYPE_STDOUT, Timestamp: time.Now().UnixNano(), Sequence: uint64(i), Content: []byte(content)},
+		}
+		if err := backend.WriteLogs("job-1", logs); err != nil {
+			t.Fatalf("WriteLogs() error = %v", err)
+		}
+	}
+
+	path := filepath.Join(tmpDir, "logs", "job-1", "stdout.log.gz.enc")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("encrypted log file missing: %v", err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) {
+		t.Error("log content stored in the clear")
+	}
+	if !bytes.Contains(data, []byte("acme")) {
+		t.Error("chunks not sealed with the tenant key")
+	}
+
+	lines, err := readAll(t, backend, "job-1")
+	if err != nil {
+		t.Fatalf("ReadLogs() error = %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "password=hunter2" || lines[1] != "second batch" {
+		t.Errorf("lines = %q", lines)
+	}
+
+	// The key of a tenant is needed to read its logs
+	backend.Close()
+	other := encryptedBackend(t, tmpDir, config.EncryptionConfig{Enabled: true, KeyFile: writeKey(t, tmpDir, 1)})
+	defer other.Close()
+	if _, err := readAll(t, other, "job-1"); err == nil {
+		t.Error("read logs without the tenant key")
+	}
+}
+
+func TestLocalBackend_EncryptedLogsDetectTampering(t *testing.T) {
+	tmpDir := t.TempDir()
+	enc := config.EncryptionConfig{Enabled: true, KeyFile: writeKey(t, tmpDir, 1)}
+	backend := encryptedBackend(t, tmpDir, enc)
+	defer backend.Close()
+
+	for _, jobID := range []string{"job-1", "job-2"} {
+		logs := []*ipcpb.LogLine{
+			{JobId: jobID, Stream: ipcpb.StreamType_STREAM_TYPE_STDOUT, Timestamp: time.Now().UnixNano(), Content: []byte("output of " + jobID)},
+		}
+		if err := backend.WriteLogs(jobID, logs); err != nil {
+			t.Fatalf("WriteLogs() error = %v", err)
+		}
+	}
+	backend.Close()
+
+	path := func(jobID string) string { return filepath.Join(tmpDir, "logs", jobID, "stdout.log.gz.enc") }
+
+	// A chunk copied from another job fails its check
+	chunk, err := os.ReadFile(path("job-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := os.ReadFile(path("job-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path("job-1"), chunk, 0644); err != nil {
+		t.Fatal(err)
+	}
+	reopened := encryptedBackend(t, tmpDir, enc)
+	defer reopened.Close()
+	if _, err := readAll(t, reopened, "job-1"); !errors.Is(err, ErrChunkIntegrity) {
+		t.Errorf("swapped chunk: error = %v, want ErrChunkIntegrity", err)
+	}
+
+	// So does a flipped bit
+	original[len(original)-1] ^= 1
+	if err := os.WriteFile(path("job-1"), original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readAll(t, reopened, "job-1"); !errors.Is(err, ErrChunkIntegrity) {
+		t.Errorf("corrupted chunk: error = %v, want ErrChunkIntegrity", err)
+	}
+}
+
+func TestNewLogCipher_InvalidKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	short := filepath.Join(tmpDir, "short.key")
+	if err := os.WriteFile(short, []byte("too short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newLogCipher(config.EncryptionConfig{Enabled: true, KeyFile: short}); err == nil {
+		t.Error("accepted a short key")
+	}
+	if _, err := newLogCipher(config.EncryptionConfig{Enabled: true, KeyFile: filepath.Join(tmpDir, "missing.key")}); err == nil {
+		t.Error("accepted a missing key file")
+	}
+	if lc, err := newLogCipher(config.EncryptionConfig{}); lc != nil || err != nil {
+		t.Errorf("disabled encryption: cipher = %v, error = %v", lc, err)
+	}
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -21,6 +22,7 @@ import (
 type LocalBackend struct {
 	config *config.StorageConfig
 	logger *logger.Logger
+	cipher *logCipher // Encrypts logs at rest (nil = disabled)
 
 	// File handles cache
 	logFiles    map[string]*logFile
@@ -32,10 +34,28 @@ type logFile struct {
 	jobID    string
 	stdout   *os.File
 	stderr   *os.File
-	gzStdout *gzip.Writer
+	gzStdout *gzip.Writer // nil when the files are encrypted
 	gzStderr *gzip.Writer
 }
 
+// close closes the gzip streams and files of a job
+func (lf *logFile) close() {
+	if lf.gzStdout != nil {
+		lf.gzStdout.Close()
+		lf.gzStderr.Close()
+	}
+	lf.stdout.Close()
+	lf.stderr.Close()
+}
+
+// streamFile is a log file of one stream of a job
+type streamFile struct {
+	path   string
+	stream ipcpb.StreamType
+	name   string // "stdout" or "stderr"
+	sealed bool   // Encrypted chunks
+}
+
 type metricFile struct {
 	jobID    string
 	file     *os.File
@@ -44,9 +64,15 @@ type metricFile struct {
 
 // NewLocalBackend creates a new local storage backend
 func NewLocalBackend(cfg *config.StorageConfig, log *logger.Logger) (*LocalBackend, error) {
+	lc, err := newLogCipher(cfg.Local.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
 	backend := &LocalBackend{
 		config:      cfg,
 		logger:      log.WithField("backend", "local"),
+		cipher:      lc,
 		logFiles:    make(map[string]*logFile),
 		metricFiles: make(map[string]*metricFile),
 	}
@@ -62,7 +88,8 @@ func NewLocalBackend(cfg *config.StorageConfig, log *logger.Logger) (*LocalBacke
 
 	log.Info("Local storage backend initialized",
 		"logsDir", cfg.Local.Logs.Directory,
-		"metricsDir", cfg.Local.Metrics.Directory)
+		"metricsDir", cfg.Local.Metrics.Directory,
+		"encrypted", lc != nil)
 
 	return backend, nil
 }
@@ -77,6 +104,10 @@ func (lb *LocalBackend) WriteLogs(jobID string, logs []*ipcpb.LogLine) error {
 		return err
 	}
 
+	if lb.cipher != nil {
+		return lb.writeSealedLogs(lf, logs)
+	}
+
 	for _, log := range logs {
 		// Marshal to JSON
 		data, err := json.Marshal(log)
@@ -124,6 +155,69 @@ func (lb *LocalBackend) WriteLogs(jobID string, logs []*ipcpb.LogLine) error {
 	return nil
 }
 
+// writeSealedLogs writes the lines of each stream in the batch as one
+// encrypted chunk, sealed with the key of the job's tenant
+func (lb *LocalBackend) writeSealedLogs(lf *logFile, logs []*ipcpb.LogLine) error {
+	var stdout, stderr bytes.Buffer
+	gzStdout, gzStderr := gzip.NewWriter(&stdout), gzip.NewWriter(&stderr)
+	var tenant string
+	var stdoutLines, stderrLines int
+
+	for _, log := range logs {
+		data, err := json.Marshal(log)
+		if err != nil {
+			return fmt.Errorf("failed to marshal log: %w", err)
+		}
+		data = append(data, '\n') // JSONL format
+
+		writer := gzStderr
+		if log.Stream == ipcpb.StreamType_STREAM_TYPE_STDOUT {
+			writer = gzStdout
+			stdoutLines++
+		} else {
+			stderrLines++
+		}
+		if _, err := writer.Write(data); err != nil {
+			return fmt.Errorf("failed to write log: %w", err)
+		}
+		if log.Tenant != "" {
+			tenant = log.Tenant
+		}
+	}
+
+	if stdoutLines > 0 {
+		if err := lb.appendSealed(lf.stdout, gzStdout, &stdout, tenant, lf.jobID, "stdout"); err != nil {
+			return err
+		}
+	}
+	if stderrLines > 0 {
+		if err := lb.appendSealed(lf.stderr, gzStderr, &stderr, tenant, lf.jobID, "stderr"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendSealed finishes a stream's gzip member and appends it to file as an
+// encrypted chunk
+func (lb *LocalBackend) appendSealed(file *os.File, gz *gzip.Writer, buf *bytes.Buffer, tenant, jobID, stream string) error {
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to close %s gzip writer: %w", stream, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	chunk, err := lb.cipher.seal(tenant, jobID, stream, info.Size(), buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(chunk); err != nil {
+		return fmt.Errorf("failed to write %s log chunk: %w", stream, err)
+	}
+	return file.Sync()
+}
+
 // WriteMetrics writes metrics to disk
 func (lb *LocalBackend) WriteMetrics(jobID string, metrics []*ipcpb.Metric) error {
 	lb.filesMu.Lock()
@@ -177,15 +271,20 @@ func (lb *LocalBackend) getOrCreateLogFile(jobID string) (*logFile, error) {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
+	suffix := ""
+	if lb.cipher != nil {
+		suffix = encryptedLogSuffix
+	}
+
 	// Open stdout file
-	stdoutPath := filepath.Join(logDir, "stdout.log.gz")
+	stdoutPath := filepath.Join(logDir, "stdout.log.gz"+suffix)
 	stdout, err := os.OpenFile(stdoutPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open stdout file: %w", err)
 	}
 
 	// Open stderr file
-	stderrPath := filepath.Join(logDir, "stderr.log.gz")
+	stderrPath := filepath.Join(logDir, "stderr.log.gz"+suffix)
 	stderr, err := os.OpenFile(stderrPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		stdout.Close()
@@ -193,11 +292,13 @@ func (lb *LocalBackend) getOrCreateLogFile(jobID string) (*logFile, error) {
 	}
 
 	lf := &logFile{
-		jobID:    jobID,
-		stdout:   stdout,
-		stderr:   stderr,
-		gzStdout: gzip.NewWriter(stdout),
-		gzStderr: gzip.NewWriter(stderr),
+		jobID:  jobID,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	if lb.cipher == nil {
+		lf.gzStdout = gzip.NewWriter(stdout)
+		lf.gzStderr = gzip.NewWriter(stderr)
 	}
 
 	lb.logFiles[jobID] = lf
@@ -263,30 +364,21 @@ func (lb *LocalBackend) ReadLogs(ctx context.Context, query *LogQuery) (*LogRead
 		defer close(reader.Error)
 		defer close(reader.Done)
 
-		// Determine which files to read based on stream filter
-		var files []struct {
-			path   string
-			stream ipcpb.StreamType
-		}
-
-		if query.Stream == ipcpb.StreamType_STREAM_TYPE_UNSPECIFIED || query.Stream == ipcpb.StreamType_STREAM_TYPE_STDOUT {
-			files = append(files, struct {
-				path   string
-				stream ipcpb.StreamType
-			}{
-				path:   filepath.Join(logDir, "stdout.log.gz"),
-				stream: ipcpb.StreamType_STREAM_TYPE_STDOUT,
-			})
-		}
-
-		if query.Stream == ipcpb.StreamType_STREAM_TYPE_UNSPECIFIED || query.Stream == ipcpb.StreamType_STREAM_TYPE_STDERR {
-			files = append(files, struct {
-				path   string
-				stream ipcpb.StreamType
-			}{
-				path:   filepath.Join(logDir, "stderr.log.gz"),
-				stream: ipcpb.StreamType_STREAM_TYPE_STDERR,
-			})
+		// Determine which files to read based on stream filter. Encrypted
+		// files follow the plain ones, written before encryption was enabled.
+		var files []streamFile
+		for _, f := range []streamFile{
+			{name: "stdout", stream: ipcpb.StreamType_STREAM_TYPE_STDOUT},
+			{name: "stderr", stream: ipcpb.StreamType_STREAM_TYPE_STDERR},
+		} {
+			if query.Stream != ipcpb.StreamType_STREAM_TYPE_UNSPECIFIED && query.Stream != f.stream {
+				continue
+			}
+			f.path = filepath.Join(logDir, f.name+".log.gz")
+			files = append(files, f)
+			f.path += encryptedLogSuffix
+			f.sealed = true
+			files = append(files, f)
 		}
 
 		count := 0
@@ -299,7 +391,7 @@ func (lb *LocalBackend) ReadLogs(ctx context.Context, query *LogQuery) (*LogRead
 				continue
 			}
 
-			file, err := os.Open(fileInfo.path)
+			file, err := lb.openLogFile(fileInfo, query.JobID)
 			if err != nil {
 				reader.Error <- fmt.Errorf("failed to open log file %s: %w", fileInfo.path, err)
 				return
@@ -395,6 +487,27 @@ func (lb *LocalBackend) ReadLogs(ctx context.Context, query *LogQuery) (*LogRead
 	return reader, nil
 }
 
+// openLogFile opens a log file of a job for reading. The chunks of encrypted
+// files are decrypted and checked as they are read; a chunk failing its check
+// ends the read with ErrChunkIntegrity.
+func (lb *LocalBackend) openLogFile(f streamFile, jobID string) (io.ReadCloser, error) {
+	file, err := os.Open(f.path)
+	if err != nil || !f.sealed {
+		return file, err
+	}
+	if lb.cipher == nil {
+		file.Close()
+		return nil, fmt.Errorf("logs are encrypted but storage.local.encryption is not enabled")
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer file.Close()
+		pw.CloseWithError(lb.cipher.openAll(bufio.NewReader(file), pw, jobID, f.name))
+	}()
+	return pr, nil
+}
+
 // contains is a simple case-insensitive substring check helper
 func contains(s, substr string) bool {
 	return len(substr) == 0 || len(s) >= len(substr) && (s == substr ||
@@ -548,10 +661,7 @@ func (lb *LocalBackend) DeleteJob(jobID string) error {
 
 	// Close open files
 	if lf, exists := lb.logFiles[jobID]; exists {
-		lf.gzStdout.Close()
-		lf.gzStderr.Close()
-		lf.stdout.Close()
-		lf.stderr.Close()
+		lf.close()
 		delete(lb.logFiles, jobID)
 	}
 
@@ -577,6 +687,60 @@ func (lb *LocalBackend) DeleteJob(jobID string) error {
 	return nil
 }
 
+// ArchiveJob writes the job record to job.json in the job's log directory
+func (lb *LocalBackend) ArchiveJob(jobID string, record []byte) error {
+	logDir := filepath.Join(lb.config.Local.Logs.Directory, jobID)
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	// Write to a temporary file first so a reader never sees half a record
+	path := filepath.Join(logDir, "job.json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, record, 0644); err != nil {
+		return fmt.Errorf("failed to write job record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write job record: %w", err)
+	}
+
+	lb.logger.Debug("Archived job record", "jobID", jobID, "bytes", len(record))
+	return nil
+}
+
+// JobDurations reads the job.json records in the log directories. Every
+// record is read on each call; there is no index by name.
+func (lb *LocalBackend) JobDurations(names []string, limit int) ([]JobDuration, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	entries, err := os.ReadDir(lb.config.Local.Logs.Directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	var runs []JobDuration
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(lb.config.Local.Logs.Directory, entry.Name(), "job.json"))
+		if err != nil {
+			continue
+		}
+		if run, ok := completedRun(data, wanted); ok {
+			runs = append(runs, run)
+		}
+	}
+	return newestRuns(runs, limit), nil
+}
+
 // Close closes the backend and all open files
 func (lb *LocalBackend) Close() error {
 	lb.filesMu.Lock()
@@ -584,10 +748,7 @@ func (lb *LocalBackend) Close() error {
 
 	// Close all log files
 	for jobID, lf := range lb.logFiles {
-		lf.gzStdout.Close()
-		lf.gzStderr.Close()
-		lf.stdout.Close()
-		lf.stderr.Close()
+		lf.close()
 		lb.logger.Debug("Closed log files", "jobID", jobID)
 	}
 
@@ -0,0 +1,405 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	"github.com/ehsaniara/joblet/persist/internal/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+const (
+	defaultSegmentMaxSizeMB   = 64
+	defaultCompactionInterval = 10 * time.Minute
+)
+
+// SegmentBackend implements the "segments" layout of the local storage:
+// the records of all jobs are appended to shared segment files under
+// <logs.directory>/segments and <metrics.directory>/segments instead of a
+// directory per job, so busy nodes do not pile up thousands of directories
+// and open files. Sealed segments are compacted in the background.
+type SegmentBackend struct {
+	logs    *segmentStore // Log lines, archived job records
+	metrics *segmentStore
+	logger  *logger.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newLocalStorage creates the local backend in the configured layout
+func newLocalStorage(cfg *config.StorageConfig, log *logger.Logger) (Backend, error) {
+	if cfg.Local.Layout == "segments" {
+		return NewSegmentBackend(cfg, log)
+	}
+	return NewLocalBackend(cfg, log)
+}
+
+// NewSegmentBackend opens the segment stores and starts compacting them
+func NewSegmentBackend(cfg *config.StorageConfig, log *logger.Logger) (*SegmentBackend, error) {
+	maxSizeMB := cfg.Local.Segments.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultSegmentMaxSizeMB
+	}
+	interval := defaultCompactionInterval
+	if cfg.Local.Segments.CompactionInterval != "" {
+		parsed, err := time.ParseDuration(cfg.Local.Segments.CompactionInterval)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid segments compaction_interval %q", cfg.Local.Segments.CompactionInterval)
+		}
+		interval = parsed
+	}
+
+	log = log.WithField("backend", "local-segments")
+	maxSize := int64(maxSizeMB) << 20
+	logs, err := openSegmentStore(filepath.Join(cfg.Local.Logs.Directory, "segments"), maxSize, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log segments: %w", err)
+	}
+	metrics, err := openSegmentStore(filepath.Join(cfg.Local.Metrics.Directory, "segments"), maxSize, log)
+	if err != nil {
+		logs.close()
+		return nil, fmt.Errorf("failed to open metric segments: %w", err)
+	}
+
+	backend := &SegmentBackend{
+		logs:    logs,
+		metrics: metrics,
+		logger:  log,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go backend.compactLoop(interval)
+
+	log.Info("Local segment storage backend initialized",
+		"logsDir", logs.dir,
+		"metricsDir", metrics.dir,
+		"segmentMaxSizeMB", maxSizeMB,
+		"compactionInterval", interval)
+
+	return backend, nil
+}
+
+func (sb *SegmentBackend) compactLoop(interval time.Duration) {
+	defer close(sb.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sb.stop:
+			return
+		case <-ticker.C:
+			sb.Compact()
+		}
+	}
+}
+
+// Compact rewrites the sealed segments that are mostly dead or small. It
+// runs every compaction interval.
+func (sb *SegmentBackend) Compact() {
+	for name, store := range map[string]*segmentStore{"logs": sb.logs, "metrics": sb.metrics} {
+		removed, err := store.compact()
+		if err != nil {
+			sb.logger.Warn("Segment compaction failed", "store", name, "error", err)
+			continue
+		}
+		if removed > 0 {
+			sb.logger.Info("Segment compaction finished", "store", name, "segmentsRemoved", removed)
+		}
+	}
+}
+
+// WriteLogs appends the batch as one record per stream
+func (sb *SegmentBackend) WriteLogs(jobID string, logs []*ipcpb.LogLine) error {
+	var stdout, stderr []any
+	for _, log := range logs {
+		if log.Stream == ipcpb.StreamType_STREAM_TYPE_STDOUT {
+			stdout = append(stdout, log)
+		} else {
+			stderr = append(stderr, log)
+		}
+	}
+
+	var records []pendingRecord
+	for _, batch := range []struct {
+		kind  recordKind
+		lines []any
+	}{{kindStdout, stdout}, {kindStderr, stderr}} {
+		if len(batch.lines) == 0 {
+			continue
+		}
+		data, err := gzipJSONLines(batch.lines)
+		if err != nil {
+			return fmt.Errorf("failed to encode logs: %w", err)
+		}
+		records = append(records, pendingRecord{kind: batch.kind, data: data})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return sb.logs.append(jobID, records...)
+}
+
+// WriteMetrics appends the batch as one record
+func (sb *SegmentBackend) WriteMetrics(jobID string, metrics []*ipcpb.Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	lines := make([]any, len(metrics))
+	for i, metric := range metrics {
+		lines[i] = metric
+	}
+	data, err := gzipJSONLines(lines)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	return sb.metrics.append(jobID, pendingRecord{kind: kindMetrics, data: data})
+}
+
+// gzipJSONLines encodes values as gzipped JSON lines, the format of the
+// per-job files
+func gzipJSONLines(values []any) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		gz.Write(append(data, '\n'))
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// eachJSONLine calls fn with every line of a gzipped JSON lines record
+// until fn returns false
+func eachJSONLine(record []byte, fn func(line []byte) bool) error {
+	gz, err := gzip.NewReader(bytes.NewReader(record))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // 64KB initial, 1MB max
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 && !fn(line) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadLogs streams a job's log lines, stdout before stderr like the per-job
+// files
+func (sb *SegmentBackend) ReadLogs(ctx context.Context, query *LogQuery) (*LogReader, error) {
+	var kinds []recordKind
+	if query.Stream == ipcpb.StreamType_STREAM_TYPE_UNSPECIFIED || query.Stream == ipcpb.StreamType_STREAM_TYPE_STDOUT {
+		kinds = append(kinds, kindStdout)
+	}
+	if query.Stream == ipcpb.StreamType_STREAM_TYPE_UNSPECIFIED || query.Stream == ipcpb.StreamType_STREAM_TYPE_STDERR {
+		kinds = append(kinds, kindStderr)
+	}
+
+	var records [][]byte
+	for _, kind := range kinds {
+		data, exists, err := sb.logs.read(query.JobID, kind)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("no logs found for job %s", query.JobID)
+		}
+		records = append(records, data...)
+	}
+
+	reader := &LogReader{
+		Channel: make(chan *ipcpb.LogLine, 100),
+		Error:   make(chan error, 1),
+		Done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(reader.Channel)
+		defer close(reader.Error)
+		defer close(reader.Done)
+
+		count, skipped := 0, 0
+		stopped := false
+		for _, record := range records {
+			err := eachJSONLine(record, func(line []byte) bool {
+				var logLine ipcpb.LogLine
+				if err := json.Unmarshal(line, &logLine); err != nil {
+					sb.logger.Warn("Failed to unmarshal log line", "error", err, "line", string(line[:min(len(line), 100)]))
+					return true
+				}
+				if query.StartTime != nil && logLine.Timestamp < *query.StartTime {
+					return true
+				}
+				if query.EndTime != nil && logLine.Timestamp > *query.EndTime {
+					return true
+				}
+				if query.Filter != "" && !contains(string(logLine.Content), query.Filter) {
+					return true
+				}
+				if skipped < query.Offset {
+					skipped++
+					return true
+				}
+				if query.Limit > 0 && count >= query.Limit {
+					stopped = true
+					return false
+				}
+				select {
+				case reader.Channel <- &logLine:
+					count++
+					return true
+				case <-ctx.Done():
+					stopped = true
+					return false
+				}
+			})
+			if err != nil {
+				reader.Error <- fmt.Errorf("error reading logs of job %s: %w", query.JobID, err)
+				return
+			}
+			if stopped {
+				return
+			}
+		}
+	}()
+
+	return reader, nil
+}
+
+// ReadMetrics streams a job's metrics
+func (sb *SegmentBackend) ReadMetrics(ctx context.Context, query *MetricQuery) (*MetricReader, error) {
+	records, exists, err := sb.metrics.read(query.JobID, kindMetrics)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("no metrics found for job %s", query.JobID)
+	}
+
+	reader := &MetricReader{
+		Channel: make(chan *ipcpb.Metric, 100),
+		Error:   make(chan error, 1),
+		Done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(reader.Channel)
+		defer close(reader.Error)
+		defer close(reader.Done)
+
+		count, skipped := 0, 0
+		stopped := false
+		for _, record := range records {
+			err := eachJSONLine(record, func(line []byte) bool {
+				var metric ipcpb.Metric
+				if err := json.Unmarshal(line, &metric); err != nil {
+					sb.logger.Warn("Failed to unmarshal metric", "error", err, "line", string(line[:min(len(line), 100)]))
+					return true
+				}
+				if query.StartTime != nil && metric.Timestamp < *query.StartTime {
+					return true
+				}
+				if query.EndTime != nil && metric.Timestamp > *query.EndTime {
+					return true
+				}
+				if skipped < query.Offset {
+					skipped++
+					return true
+				}
+				if query.Limit > 0 && count >= query.Limit {
+					stopped = true
+					return false
+				}
+				select {
+				case reader.Channel <- &metric:
+					count++
+					return true
+				case <-ctx.Done():
+					stopped = true
+					return false
+				}
+			})
+			if err != nil {
+				reader.Error <- fmt.Errorf("error reading metrics of job %s: %w", query.JobID, err)
+				return
+			}
+			if stopped {
+				return
+			}
+		}
+	}()
+
+	return reader, nil
+}
+
+// DeleteJob kills the job's records; compaction reclaims their space
+func (sb *SegmentBackend) DeleteJob(jobID string) error {
+	if err := sb.logs.delete(jobID); err != nil {
+		return fmt.Errorf("failed to delete logs: %w", err)
+	}
+	if err := sb.metrics.delete(jobID); err != nil {
+		return fmt.Errorf("failed to delete metrics: %w", err)
+	}
+	sb.logger.Info("Deleted job data", "jobID", jobID)
+	return nil
+}
+
+// ArchiveJob appends the job record to the log segments
+func (sb *SegmentBackend) ArchiveJob(jobID string, record []byte) error {
+	if err := sb.logs.append(jobID, pendingRecord{kind: kindArchive, data: record}); err != nil {
+		return fmt.Errorf("failed to write job record: %w", err)
+	}
+	sb.logger.Debug("Archived job record", "jobID", jobID, "bytes", len(record))
+	return nil
+}
+
+// JobDurations reads the archived job records through the index
+func (sb *SegmentBackend) JobDurations(names []string, limit int) ([]JobDuration, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	records, err := sb.logs.archived()
+	if err != nil {
+		return nil, err
+	}
+	var runs []JobDuration
+	for _, data := range records {
+		if run, ok := completedRun(data, wanted); ok {
+			runs = append(runs, run)
+		}
+	}
+	return newestRuns(runs, limit), nil
+}
+
+// Close stops compaction and closes the segment files
+func (sb *SegmentBackend) Close() error {
+	close(sb.stop)
+	<-sb.done
+
+	logsErr := sb.logs.close()
+	metricsErr := sb.metrics.close()
+	sb.logger.Info("Local segment storage backend closed")
+	if logsErr != nil {
+		return logsErr
+	}
+	return metricsErr
+}
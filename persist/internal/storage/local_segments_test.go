@@ -0,0 +1,385 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+	"github.com/ehsaniara/joblet/persist/internal/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+func layoutConfig(dir, layout string) *config.StorageConfig {
+	return &config.StorageConfig{
+		Type: "local",
+		Local: config.LocalConfig{
+			Logs:    config.LogStorageConfig{Directory: filepath.Join(dir, "logs")},
+			Metrics: config.MetricStorageConfig{Directory: filepath.Join(dir, "metrics")},
+			Layout:  layout,
+		},
+	}
+}
+
+func newLayoutBackend(tb testing.TB, dir, layout string) Backend {
+	tb.Helper()
+	backend, err := newLocalStorage(layoutConfig(dir, layout), logger.New())
+	if err != nil {
+		tb.Fatalf("Failed to create %s backend: %v", layout, err)
+	}
+	return backend
+}
+
+func logBatch(jobID string, stream ipcpb.StreamType, contents ...string) []*ipcpb.LogLine {
+	var lines []*ipcpb.LogLine
+	for i, content := range contents {
+		lines = append(lines, &ipcpb.LogLine{JobId: jobID, Stream: stream, Timestamp: int64(i + 1), Content: []byte(content)})
+	}
+	return lines
+}
+
+func readLogContents(t *testing.T, backend Backend, query *LogQuery) []string {
+	t.Helper()
+	reader, err := backend.ReadLogs(context.Background(), query)
+	if err != nil {
+		t.Fatalf("ReadLogs(%s) error = %v", query.JobID, err)
+	}
+	var contents []string
+	for line := range reader.Channel {
+		contents = append(contents, string(line.Content))
+	}
+	if err := <-reader.Error; err != nil {
+		t.Fatalf("ReadLogs(%s) stream error = %v", query.JobID, err)
+	}
+	return contents
+}
+
+func TestSegmentBackend_WriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	backend := newLayoutBackend(t, dir, "segments")
+
+	if err := backend.WriteLogs("job-a", append(logBatch("job-a", ipcpb.StreamType_STREAM_TYPE_STDOUT, "out 1", "out 2"),
+		logBatch("job-a", ipcpb.StreamType_STREAM_TYPE_STDERR, "err 1")...)); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.WriteLogs("job-b", logBatch("job-b", ipcpb.StreamType_STREAM_TYPE_STDOUT, "b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.WriteLogs("job-a", logBatch("job-a", ipcpb.StreamType_STREAM_TYPE_STDOUT, "out 3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.WriteMetrics("job-a", []*ipcpb.Metric{{JobId: "job-a", Timestamp: 1}, {JobId: "job-a", Timestamp: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(backend Backend) {
+		if got := readLogContents(t, backend, &LogQuery{JobID: "job-a"}); !slices.Equal(got, []string{"out 1", "out 2", "out 3", "err 1"}) {
+			t.Errorf("job-a logs = %v", got)
+		}
+		if got := readLogContents(t, backend, &LogQuery{JobID: "job-a", Stream: ipcpb.StreamType_STREAM_TYPE_STDOUT, Offset: 1, Limit: 1}); !slices.Equal(got, []string{"out 2"}) {
+			t.Errorf("job-a stdout page = %v", got)
+		}
+		if got := readLogContents(t, backend, &LogQuery{JobID: "job-a", Filter: "err"}); !slices.Equal(got, []string{"err 1"}) {
+			t.Errorf("job-a filtered logs = %v", got)
+		}
+
+		reader, err := backend.ReadMetrics(context.Background(), &MetricQuery{JobID: "job-a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for range reader.Channel {
+			count++
+		}
+		if count != 2 {
+			t.Errorf("read %d metrics, want 2", count)
+		}
+
+		if _, err := backend.ReadLogs(context.Background(), &LogQuery{JobID: "missing"}); err == nil {
+			t.Error("ReadLogs() of an unknown job succeeded")
+		}
+		if _, err := backend.ReadMetrics(context.Background(), &MetricQuery{JobID: "job-b"}); err == nil {
+			t.Error("ReadMetrics() of a job without metrics succeeded")
+		}
+	}
+	check(backend)
+
+	// The index of the active segment is rebuilt from the segment itself
+	backend.Close()
+	backend = newLayoutBackend(t, dir, "segments")
+	defer backend.Close()
+	check(backend)
+
+	// No per-job directories are created
+	entries, _ := os.ReadDir(filepath.Join(dir, "logs"))
+	if len(entries) != 1 || entries[0].Name() != "segments" {
+		t.Errorf("logs directory holds %v", entries)
+	}
+}
+
+func TestSegmentBackend_DeleteAndArchive(t *testing.T) {
+	dir := t.TempDir()
+	backend := newLayoutBackend(t, dir, "segments")
+
+	if err := backend.WriteLogs("run-1", logBatch("run-1", ipcpb.StreamType_STREAM_TYPE_STDOUT, "x")); err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []string{
+		`{"Uuid":"run-1","Name":"train","Status":"RUNNING","StartTime":"2025-08-01T10:00:00Z"}`,
+		`{"Uuid":"run-1","Name":"train","Status":"COMPLETED","StartTime":"2025-08-01T10:00:00Z","EndTime":"2025-08-01T10:20:00Z"}`,
+	} {
+		if err := backend.ArchiveJob("run-1", []byte(record)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := backend.ArchiveJob("run-2", []byte(`{"Uuid":"run-2","Name":"train","Status":"COMPLETED","StartTime":"2025-08-02T10:00:00Z","EndTime":"2025-08-02T10:05:00Z"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := backend.JobDurations([]string{"train"}, 0)
+	if err != nil || len(runs) != 2 || runs[0].JobID != "run-2" || runs[1].End.Sub(runs[1].Start) != 20*time.Minute {
+		t.Fatalf("JobDurations() = %+v, %v", runs, err)
+	}
+
+	if err := backend.DeleteJob("run-1"); err != nil {
+		t.Fatal(err)
+	}
+	backend.Close()
+
+	// The tombstone keeps the job deleted after a restart
+	backend = newLayoutBackend(t, dir, "segments")
+	defer backend.Close()
+	if _, err := backend.ReadLogs(context.Background(), &LogQuery{JobID: "run-1"}); err == nil {
+		t.Error("ReadLogs() of a deleted job succeeded")
+	}
+	if runs, err := backend.JobDurations([]string{"train"}, 0); err != nil || len(runs) != 1 || runs[0].JobID != "run-2" {
+		t.Errorf("JobDurations() after delete = %+v, %v", runs, err)
+	}
+}
+
+func openTestStore(t *testing.T, dir string) *segmentStore {
+	t.Helper()
+	store, err := openSegmentStore(dir, 1024, logger.New())
+	if err != nil {
+		t.Fatalf("openSegmentStore() error = %v", err)
+	}
+	return store
+}
+
+func storeContents(t *testing.T, store *segmentStore, jobID string) []string {
+	t.Helper()
+	data, _, err := store.read(jobID, kindStdout)
+	if err != nil {
+		t.Fatalf("read(%s) error = %v", jobID, err)
+	}
+	var contents []string
+	for _, d := range data {
+		contents = append(contents, string(d))
+	}
+	return contents
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestSegmentStore_TornTail(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+	if err := store.append("job", pendingRecord{kind: kindStdout, data: []byte("one")}); err != nil {
+		t.Fatal(err)
+	}
+	store.close()
+
+	// A crash in the middle of an append leaves part of a record behind
+	path := segmentPath(dir, 1)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write(encodeRecord(kindStdout, "job", []byte("two"))[:10])
+	f.Close()
+
+	store = openTestStore(t, dir)
+	if err := store.append("job", pendingRecord{kind: kindStdout, data: []byte("three")}); err != nil {
+		t.Fatal(err)
+	}
+	store.close()
+
+	store = openTestStore(t, dir)
+	defer store.close()
+	if got := storeContents(t, store, "job"); !slices.Equal(got, []string{"one", "three"}) {
+		t.Errorf("records after a torn write = %v", got)
+	}
+}
+
+func TestSegmentStore_Compact(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+
+	want := make(map[string][]string)
+	for i := range 60 {
+		jobID := fmt.Sprintf("job-%d", i%6)
+		content := fmt.Sprintf("%s line %02d %s", jobID, i, "padding to fill the segments quickly")
+		if err := store.append(jobID, pendingRecord{kind: kindStdout, data: []byte(content)}); err != nil {
+			t.Fatal(err)
+		}
+		want[jobID] = append(want[jobID], content)
+	}
+	for _, jobID := range []string{"job-0", "job-2", "job-3", "job-4"} {
+		if err := store.delete(jobID); err != nil {
+			t.Fatal(err)
+		}
+		delete(want, jobID)
+	}
+
+	before := len(segmentFiles(t, dir))
+	// Keep a copy of the oldest segments to fake a compaction interrupted
+	// before the run's older segments were removed
+	leftovers := make(map[string][]byte)
+	for _, path := range segmentFiles(t, dir)[:2] {
+		data, _ := os.ReadFile(path)
+		leftovers[path] = data
+	}
+
+	removed, err := store.compact()
+	if err != nil {
+		t.Fatalf("compact() error = %v", err)
+	}
+	after := len(segmentFiles(t, dir))
+	if removed == 0 || after != before-removed {
+		t.Fatalf("compact() removed %d segments, %d before and %d after", removed, before, after)
+	}
+
+	check := func(store *segmentStore) {
+		for jobID, contents := range want {
+			if got := storeContents(t, store, jobID); !slices.Equal(got, contents) {
+				t.Errorf("%s after compaction = %v, want %v", jobID, got, contents)
+			}
+		}
+		for _, jobID := range []string{"job-0", "job-2", "job-3", "job-4"} {
+			if _, exists, _ := store.read(jobID, kindStdout); exists {
+				t.Errorf("deleted %s is back after compaction", jobID)
+			}
+		}
+	}
+	check(store)
+	// Writes go on after compaction
+	if err := store.append("job-1", pendingRecord{kind: kindStdout, data: []byte("after")}); err != nil {
+		t.Fatal(err)
+	}
+	want["job-1"] = append(want["job-1"], "after")
+	store.close()
+
+	for path, data := range leftovers {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			os.WriteFile(path, data, 0644)
+		}
+	}
+	store = openTestStore(t, dir)
+	defer store.close()
+	check(store)
+	if got := len(segmentFiles(t, dir)); got != after {
+		t.Errorf("%d segments after reopening, want the %d compacted ones", got, after)
+	}
+}
+
+// The benchmarks compare the per-job "files" layout with "segments" on a
+// node holding benchmarkJobs jobs, e.g.
+//
+//	go test ./internal/storage -run '^$' -bench Layout -benchmem
+const benchmarkJobs = 1000
+
+var benchmarkLayouts = []string{"files", "segments"}
+
+func benchmarkBackend(b *testing.B, layout string, batches int) Backend {
+	backend := newLayoutBackend(b, b.TempDir(), layout)
+	b.Cleanup(func() { backend.Close() })
+	for batch := range batches {
+		for job := range benchmarkJobs {
+			jobID := fmt.Sprintf("job-%04d", job)
+			if err := backend.WriteLogs(jobID, benchmarkLogBatch(jobID, batch)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	return backend
+}
+
+func benchmarkLogBatch(jobID string, batch int) []*ipcpb.LogLine {
+	lines := make([]*ipcpb.LogLine, 10)
+	for i := range lines {
+		lines[i] = &ipcpb.LogLine{
+			JobId:     jobID,
+			Stream:    ipcpb.StreamType_STREAM_TYPE_STDOUT,
+			Timestamp: time.Now().UnixNano(),
+			Sequence:  uint64(batch*len(lines) + i),
+			Content:   []byte("epoch 12 step 3400 loss=0.0312 accuracy=0.9871"),
+		}
+	}
+	return lines
+}
+
+func BenchmarkLayout_WriteLogs(b *testing.B) {
+	for _, layout := range benchmarkLayouts {
+		b.Run(layout, func(b *testing.B) {
+			backend := benchmarkBackend(b, layout, 0)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				jobID := fmt.Sprintf("job-%04d", i%benchmarkJobs)
+				if err := backend.WriteLogs(jobID, benchmarkLogBatch(jobID, i)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLayout_ReadLogs(b *testing.B) {
+	for _, layout := range benchmarkLayouts {
+		b.Run(layout, func(b *testing.B) {
+			backend := benchmarkBackend(b, layout, 5)
+			if sb, ok := backend.(*SegmentBackend); ok {
+				sb.Compact()
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				reader, err := backend.ReadLogs(context.Background(), &LogQuery{JobID: fmt.Sprintf("job-%04d", i%benchmarkJobs)})
+				if err != nil {
+					b.Fatal(err)
+				}
+				for range reader.Channel {
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLayout_JobDurations(b *testing.B) {
+	for _, layout := range benchmarkLayouts {
+		b.Run(layout, func(b *testing.B) {
+			backend := benchmarkBackend(b, layout, 1)
+			for job := range benchmarkJobs {
+				record := fmt.Sprintf(`{"Uuid":"job-%04d","Name":"train","Status":"COMPLETED","StartTime":"2025-08-01T10:00:00Z","EndTime":"2025-08-01T10:20:00Z"}`, job)
+				if err := backend.ArchiveJob(fmt.Sprintf("job-%04d", job), []byte(record)); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := backend.JobDurations([]string{"train"}, 5); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -421,6 +421,100 @@ func TestLocalBackend_DeleteJob(t *testing.T) {
 	}
 }
 
+func TestLocalBackend_ArchiveJob(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.StorageConfig{
+		Type: "local",
+		Local: config.LocalConfig{
+			Logs: config.LogStorageConfig{
+				Directory: filepath.Join(tmpDir, "logs"),
+			},
+			Metrics: config.MetricStorageConfig{
+				Directory: filepath.Join(tmpDir, "metrics"),
+			},
+		},
+	}
+
+	log := logger.New()
+	backend, err := NewLocalBackend(cfg, log)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer backend.Close()
+
+	jobID := "test-job-archive"
+	record := []byte(`{"id":"test-job-archive","status":"COMPLETED"}`)
+
+	if err := backend.ArchiveJob(jobID, record); err != nil {
+		t.Fatalf("Failed to archive job: %v", err)
+	}
+
+	path := filepath.Join(cfg.Local.Logs.Directory, jobID, "job.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read archived record: %v", err)
+	}
+	if string(data) != string(record) {
+		t.Errorf("Expected record %s, got %s", record, data)
+	}
+
+	// Deleting the job removes its archived record as well
+	if err := backend.DeleteJob(jobID); err != nil {
+		t.Errorf("Failed to delete job: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected archived record to be deleted")
+	}
+}
+
+func TestLocalBackend_JobDurations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.StorageConfig{
+		Type: "local",
+		Local: config.LocalConfig{
+			Logs: config.LogStorageConfig{
+				Directory: filepath.Join(tmpDir, "logs"),
+			},
+			Metrics: config.MetricStorageConfig{
+				Directory: filepath.Join(tmpDir, "metrics"),
+			},
+		},
+	}
+
+	log := logger.New()
+	backend, err := NewLocalBackend(cfg, log)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer backend.Close()
+
+	records := map[string]string{
+		"run-1":  `{"Uuid":"run-1","Name":"train","Status":"COMPLETED","StartTime":"2025-08-01T10:00:00Z","EndTime":"2025-08-01T10:20:00Z"}`,
+		"run-2":  `{"Uuid":"run-2","Name":"train","Status":"COMPLETED","StartTime":"2025-08-02T10:00:00Z","EndTime":"2025-08-02T10:30:00Z"}`,
+		"run-3":  `{"Uuid":"run-3","Name":"train","Status":"COMPLETED","StartTime":"2025-08-03T10:00:00Z","EndTime":"2025-08-03T10:25:00Z"}`,
+		"failed": `{"Uuid":"failed","Name":"train","Status":"FAILED","StartTime":"2025-08-04T10:00:00Z","EndTime":"2025-08-04T10:01:00Z"}`,
+		"other":  `{"Uuid":"other","Name":"report","Status":"COMPLETED","StartTime":"2025-08-04T10:00:00Z","EndTime":"2025-08-04T10:01:00Z"}`,
+	}
+	for jobID, record := range records {
+		if err := backend.ArchiveJob(jobID, []byte(record)); err != nil {
+			t.Fatalf("Failed to archive job: %v", err)
+		}
+	}
+
+	runs, err := backend.JobDurations([]string{"train"}, 2)
+	if err != nil {
+		t.Fatalf("Failed to read job durations: %v", err)
+	}
+	if len(runs) != 2 || runs[0].JobID != "run-3" || runs[1].JobID != "run-2" {
+		t.Fatalf("Expected the two newest completed train runs, got %+v", runs)
+	}
+	if d := runs[0].End.Sub(runs[0].Start); d != 25*time.Minute {
+		t.Errorf("Expected a 25m run, got %s", d)
+	}
+}
+
 func TestLocalBackend_Close(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,330 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+)
+
+// MetricFunctions are the aggregations a metrics query may apply per step
+var MetricFunctions = []string{"avg", "min", "max", "sum", "count", "last"}
+
+// defaultMetricStep is the aggregation step of queries that don't set one
+const defaultMetricStep = int64(time.Minute)
+
+// metricField is one value of a metric sample, under the name queries and
+// the ClickHouse columns use
+type metricField struct {
+	name string
+	get  func(*ipcpb.MetricData) float64
+	set  func(*ipcpb.MetricData, float64)
+}
+
+var metricFields = []metricField{
+	{"cpu_usage", func(d *ipcpb.MetricData) float64 { return d.GetCpuUsage() }, func(d *ipcpb.MetricData, v float64) { d.CpuUsage = v }},
+	{"memory_usage", func(d *ipcpb.MetricData) float64 { return float64(d.GetMemoryUsage()) }, func(d *ipcpb.MetricData, v float64) { d.MemoryUsage = round(v) }},
+	{"gpu_usage", func(d *ipcpb.MetricData) float64 { return d.GetGpuUsage() }, func(d *ipcpb.MetricData, v float64) { d.GpuUsage = v }},
+	{"disk_read_bytes", func(d *ipcpb.MetricData) float64 { return float64(d.GetDiskIo().GetReadBytes()) }, func(d *ipcpb.MetricData, v float64) { diskIO(d).ReadBytes = round(v) }},
+	{"disk_write_bytes", func(d *ipcpb.MetricData) float64 { return float64(d.GetDiskIo().GetWriteBytes()) }, func(d *ipcpb.MetricData, v float64) { diskIO(d).WriteBytes = round(v) }},
+	{"disk_read_ops", func(d *ipcpb.MetricData) float64 { return float64(d.GetDiskIo().GetReadOps()) }, func(d *ipcpb.MetricData, v float64) { diskIO(d).ReadOps = round(v) }},
+	{"disk_write_ops", func(d *ipcpb.MetricData) float64 { return float64(d.GetDiskIo().GetWriteOps()) }, func(d *ipcpb.MetricData, v float64) { diskIO(d).WriteOps = round(v) }},
+	{"net_rx_bytes", func(d *ipcpb.MetricData) float64 { return float64(d.GetNetworkIo().GetRxBytes()) }, func(d *ipcpb.MetricData, v float64) { networkIO(d).RxBytes = round(v) }},
+	{"net_tx_bytes", func(d *ipcpb.MetricData) float64 { return float64(d.GetNetworkIo().GetTxBytes()) }, func(d *ipcpb.MetricData, v float64) { networkIO(d).TxBytes = round(v) }},
+	{"net_rx_packets", func(d *ipcpb.MetricData) float64 { return float64(d.GetNetworkIo().GetRxPackets()) }, func(d *ipcpb.MetricData, v float64) { networkIO(d).RxPackets = round(v) }},
+	{"net_tx_packets", func(d *ipcpb.MetricData) float64 { return float64(d.GetNetworkIo().GetTxPackets()) }, func(d *ipcpb.MetricData, v float64) { networkIO(d).TxPackets = round(v) }},
+}
+
+func round(v float64) int64 {
+	return int64(math.Round(v))
+}
+
+func diskIO(d *ipcpb.MetricData) *ipcpb.DiskIO {
+	if d.DiskIo == nil {
+		d.DiskIo = &ipcpb.DiskIO{}
+	}
+	return d.DiskIo
+}
+
+func networkIO(d *ipcpb.MetricData) *ipcpb.NetworkIO {
+	if d.NetworkIo == nil {
+		d.NetworkIo = &ipcpb.NetworkIO{}
+	}
+	return d.NetworkIo
+}
+
+// MetricNames returns the metric names a query may select
+func MetricNames() []string {
+	names := make([]string, len(metricFields))
+	for i, field := range metricFields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// MetricExpr is a parsed metrics query expression
+type MetricExpr struct {
+	Function string // One of MetricFunctions, empty for raw samples
+	Metric   string // Empty for every metric
+	JobID    string // From a job_id matcher
+}
+
+var (
+	metricCallPattern    = regexp.MustCompile(`^([a-z_]+)\s*\((.*)\)$`)
+	metricSelectPattern  = regexp.MustCompile(`^([a-z_]*)\s*(?:\{(.*)\})?$`)
+	metricMatcherPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(=~|!~|!=|=)\s*("(?:[^"\\]|\\.)*")\s*(?:,|$)`)
+)
+
+// ParseMetricExpr parses a metrics query expression, loosely after PromQL:
+//
+//	[FUNCTION(]METRIC[{LABEL="VALUE", ...}][)]
+//
+// such as max(memory_usage{job_id="f47ac10b-..."}). Samples carry a single
+// label, job_id, which may only be matched with =.
+func ParseMetricExpr(raw string) (MetricExpr, error) {
+	var expr MetricExpr
+	selector := strings.TrimSpace(raw)
+	if m := metricCallPattern.FindStringSubmatch(selector); m != nil {
+		if !slices.Contains(MetricFunctions, m[1]) {
+			return expr, fmt.Errorf("unknown function %s, want one of %s", m[1], strings.Join(MetricFunctions, ", "))
+		}
+		expr.Function, selector = m[1], strings.TrimSpace(m[2])
+	}
+
+	m := metricSelectPattern.FindStringSubmatch(selector)
+	if m == nil || selector == "" {
+		return expr, fmt.Errorf("invalid expression %q: want [FUNCTION(]METRIC[{LABEL=\"VALUE\"}][)]", raw)
+	}
+	if m[1] != "" && !slices.Contains(MetricNames(), m[1]) {
+		return expr, fmt.Errorf("unknown metric %s, want one of %s", m[1], strings.Join(MetricNames(), ", "))
+	}
+	expr.Metric = m[1]
+
+	for matchers := m[2]; strings.TrimSpace(matchers) != ""; {
+		mm := metricMatcherPattern.FindStringSubmatch(matchers)
+		if mm == nil {
+			return expr, fmt.Errorf("invalid label matchers {%s}", m[2])
+		}
+		matchers = matchers[len(mm[0]):]
+		label, op := mm[1], mm[2]
+		value, err := strconv.Unquote(mm[3])
+		if err != nil {
+			return expr, fmt.Errorf("invalid label value %s", mm[3])
+		}
+		if label != "job_id" {
+			return expr, fmt.Errorf("unknown label %s, samples only carry job_id", label)
+		}
+		if op != "=" {
+			return expr, fmt.Errorf("job_id can only be matched with =")
+		}
+		if expr.JobID != "" && expr.JobID != value {
+			return expr, fmt.Errorf("conflicting job_id matchers")
+		}
+		expr.JobID = value
+	}
+	return expr, nil
+}
+
+// metricAggregator is implemented by backends that apply MetricQuery
+// aggregations and steps themselves
+type metricAggregator interface {
+	aggregatesMetrics()
+}
+
+// ReadMetricSeries reads the samples query selects, keeping only
+// query.Metric when set. With an aggregation there is one sample per step,
+// stamped with the start of the step; a step without an aggregation keeps the
+// last sample of each step. Backends that cannot aggregate are read raw and
+// their samples rolled up here, which assumes samples come oldest first.
+func ReadMetricSeries(ctx context.Context, backend Backend, query *MetricQuery) (*MetricReader, error) {
+	if query.Aggregation == "none" {
+		query.Aggregation = ""
+	}
+	if query.Aggregation == "" && query.Step > 0 {
+		query.Aggregation = "last"
+	}
+	if query.Aggregation != "" && !slices.Contains(MetricFunctions, query.Aggregation) {
+		return nil, fmt.Errorf("unsupported metric aggregation: %s", query.Aggregation)
+	}
+	if query.Metric != "" && !slices.Contains(MetricNames(), query.Metric) {
+		return nil, fmt.Errorf("unknown metric %s, want one of %s", query.Metric, strings.Join(MetricNames(), ", "))
+	}
+
+	_, native := backend.(metricAggregator)
+	if query.Aggregation == "" || native {
+		reader, err := backend.ReadMetrics(ctx, query)
+		if err != nil || query.Metric == "" {
+			return reader, err
+		}
+		field := lookupMetricField(query.Metric)
+		return pipeMetrics(ctx, reader, func(metric *ipcpb.Metric, emit func(*ipcpb.Metric) bool) bool {
+			data := &ipcpb.MetricData{}
+			field.set(data, field.get(metric.Data))
+			return emit(&ipcpb.Metric{JobId: metric.JobId, Timestamp: metric.Timestamp, Sequence: metric.Sequence, Data: data})
+		}, nil), nil
+	}
+
+	raw := *query
+	raw.Aggregation, raw.Metric, raw.Step, raw.Limit, raw.Offset = "", "", 0, 0, 0
+	reader, err := backend.ReadMetrics(ctx, &raw)
+	if err != nil {
+		return nil, err
+	}
+	rollup := newMetricRollup(query)
+	return pipeMetrics(ctx, reader, rollup.add, rollup.flush), nil
+}
+
+// lookupMetricField returns the field of a metric name, which callers have
+// checked against MetricNames
+func lookupMetricField(name string) metricField {
+	for _, field := range metricFields {
+		if field.name == name {
+			return field
+		}
+	}
+	panic("unknown metric " + name)
+}
+
+// pipeMetrics returns a reader of the samples process emits for those of in,
+// followed by those flush emits once in is drained
+func pipeMetrics(ctx context.Context, in *MetricReader, process func(*ipcpb.Metric, func(*ipcpb.Metric) bool) bool, flush func(func(*ipcpb.Metric) bool)) *MetricReader {
+	out := &MetricReader{
+		Channel: make(chan *ipcpb.Metric, 100),
+		Error:   make(chan error, 1),
+		Done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(out.Channel)
+		defer close(out.Error)
+		defer close(out.Done)
+
+		emit := func(metric *ipcpb.Metric) bool {
+			select {
+			case out.Channel <- metric:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for metric := range in.Channel {
+			if !process(metric, emit) {
+				return
+			}
+		}
+		// Readers close Error before Channel
+		select {
+		case err := <-in.Error:
+			if err != nil {
+				out.Error <- err
+				return
+			}
+		default:
+		}
+		if flush != nil {
+			flush(emit)
+		}
+	}()
+
+	return out
+}
+
+// metricRollup aggregates samples per step
+type metricRollup struct {
+	query    *MetricQuery
+	step     int64
+	fields   []metricField
+	start    int64 // Of the current step
+	samples  int
+	values   []float64
+	skipped  int
+	emitted  int
+	finished bool // Limit reached
+}
+
+func newMetricRollup(query *MetricQuery) *metricRollup {
+	r := &metricRollup{query: query, step: query.Step, fields: metricFields}
+	if r.step <= 0 {
+		r.step = defaultMetricStep
+	}
+	if query.Metric != "" {
+		r.fields = []metricField{lookupMetricField(query.Metric)}
+	}
+	r.values = make([]float64, len(r.fields))
+	return r
+}
+
+// add folds a sample into its step, emitting the previous step when the
+// sample starts a new one
+func (r *metricRollup) add(metric *ipcpb.Metric, emit func(*ipcpb.Metric) bool) bool {
+	if r.finished {
+		return true // Drain the reader
+	}
+	start := metric.Timestamp - metric.Timestamp%r.step
+	if r.samples > 0 && start != r.start {
+		if !r.emit(emit) {
+			return false
+		}
+	}
+	r.start = start
+	r.samples++
+	for i, field := range r.fields {
+		v := field.get(metric.Data)
+		switch r.query.Aggregation {
+		case "min":
+			if r.samples == 1 || v < r.values[i] {
+				r.values[i] = v
+			}
+		case "max":
+			if r.samples == 1 || v > r.values[i] {
+				r.values[i] = v
+			}
+		case "avg", "sum":
+			r.values[i] += v
+		case "count":
+			r.values[i]++
+		case "last":
+			r.values[i] = v
+		}
+	}
+	return true
+}
+
+// flush emits the last step
+func (r *metricRollup) flush(emit func(*ipcpb.Metric) bool) {
+	if r.samples > 0 && !r.finished {
+		r.emit(emit)
+	}
+}
+
+// emit sends the current step, within the query's offset and limit, and
+// resets it
+func (r *metricRollup) emit(emit func(*ipcpb.Metric) bool) bool {
+	data := &ipcpb.MetricData{}
+	for i, field := range r.fields {
+		v := r.values[i]
+		if r.query.Aggregation == "avg" {
+			v /= float64(r.samples)
+		}
+		field.set(data, v)
+		r.values[i] = 0
+	}
+	r.samples = 0
+
+	if r.skipped < r.query.Offset {
+		r.skipped++
+		return true
+	}
+	if r.query.Limit > 0 && r.emitted >= r.query.Limit {
+		r.finished = true
+		return true
+	}
+	r.emitted++
+	return emit(&ipcpb.Metric{JobId: r.query.JobID, Timestamp: r.start, Data: data})
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ipcpb "github.com/ehsaniara/joblet/internal/proto/gen/ipc"
+)
+
+func TestParseMetricExpr(t *testing.T) {
+	valid := map[string]MetricExpr{
+		"cpu_usage":                                {Metric: "cpu_usage"},
+		`max(memory_usage{job_id="job-1"})`:        {Function: "max", Metric: "memory_usage", JobID: "job-1"},
+		` avg( net_rx_bytes { job_id = "a\"b" } )`: {Function: "avg", Metric: "net_rx_bytes", JobID: `a"b`},
+		`count({job_id="job-1"})`:                  {Function: "count", JobID: "job-1"},
+		`last(gpu_usage{})`:                        {Function: "last", Metric: "gpu_usage"},
+	}
+	for raw, want := range valid {
+		got, err := ParseMetricExpr(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseMetricExpr(%s) = %+v, %v, want %+v", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{
+		"",
+		"median(cpu_usage)",
+		"cpu_percent",
+		`cpu_usage{node_id="n1"}`,
+		`cpu_usage{job_id=~"job-.*"}`,
+		`cpu_usage{job_id="a",job_id="b"}`,
+		`cpu_usage{job_id=a}`,
+		"max(cpu_usage",
+	} {
+		if _, err := ParseMetricExpr(raw); err == nil {
+			t.Errorf("ParseMetricExpr(%q) succeeded, want an error", raw)
+		}
+	}
+}
+
+// sliceBackend serves metrics from a slice, raw like the local backends
+type sliceBackend struct {
+	Backend
+	metrics []*ipcpb.Metric
+	err     error
+	queries []MetricQuery
+}
+
+func (b *sliceBackend) ReadMetrics(ctx context.Context, query *MetricQuery) (*MetricReader, error) {
+	b.queries = append(b.queries, *query)
+	reader := &MetricReader{
+		Channel: make(chan *ipcpb.Metric, len(b.metrics)),
+		Error:   make(chan error, 1),
+		Done:    make(chan struct{}),
+	}
+	for _, metric := range b.metrics {
+		reader.Channel <- metric
+	}
+	if b.err != nil {
+		reader.Error <- b.err
+	}
+	close(reader.Error)
+	close(reader.Channel)
+	return reader, nil
+}
+
+func metricSample(seconds int64, cpu float64, memory int64) *ipcpb.Metric {
+	return &ipcpb.Metric{
+		JobId:     "job-1",
+		Timestamp: seconds * int64(time.Second),
+		Data: &ipcpb.MetricData{
+			CpuUsage:    cpu,
+			MemoryUsage: memory,
+			NetworkIo:   &ipcpb.NetworkIO{RxBytes: seconds},
+		},
+	}
+}
+
+func readSeries(t *testing.T, reader *MetricReader) []*ipcpb.Metric {
+	t.Helper()
+	var metrics []*ipcpb.Metric
+	for metric := range reader.Channel {
+		metrics = append(metrics, metric)
+	}
+	if err := <-reader.Error; err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+	return metrics
+}
+
+func TestReadMetricSeries(t *testing.T) {
+	// Three 10s steps of two, one and three samples
+	backend := &sliceBackend{metrics: []*ipcpb.Metric{
+		metricSample(0, 1, 100), metricSample(5, 3, 300),
+		metricSample(10, 2, 200),
+		metricSample(20, 4, 50), metricSample(25, 6, 150), metricSample(29, 5, 100),
+	}}
+	step := int64(10 * time.Second)
+
+	tests := []struct {
+		name  string
+		query MetricQuery
+		want  [][2]float64 // Step start in seconds, value
+	}{
+		{"avg", MetricQuery{Aggregation: "avg", Metric: "cpu_usage", Step: step}, [][2]float64{{0, 2}, {10, 2}, {20, 5}}},
+		{"max", MetricQuery{Aggregation: "max", Metric: "memory_usage", Step: step}, [][2]float64{{0, 300}, {10, 200}, {20, 150}}},
+		{"min", MetricQuery{Aggregation: "min", Metric: "cpu_usage", Step: step}, [][2]float64{{0, 1}, {10, 2}, {20, 4}}},
+		{"sum", MetricQuery{Aggregation: "sum", Metric: "net_rx_bytes", Step: step}, [][2]float64{{0, 5}, {10, 10}, {20, 74}}},
+		{"count", MetricQuery{Aggregation: "count", Metric: "cpu_usage", Step: step}, [][2]float64{{0, 2}, {10, 1}, {20, 3}}},
+		{"step without a function keeps the last sample", MetricQuery{Metric: "cpu_usage", Step: step}, [][2]float64{{0, 3}, {10, 2}, {20, 5}}},
+		{"default step of a minute", MetricQuery{Aggregation: "max", Metric: "cpu_usage"}, [][2]float64{{0, 6}}},
+		{"offset and limit apply to steps", MetricQuery{Aggregation: "avg", Metric: "cpu_usage", Step: step, Offset: 1, Limit: 1}, [][2]float64{{10, 2}}},
+		{"raw samples of one metric", MetricQuery{Metric: "cpu_usage"}, [][2]float64{{0, 1}, {5, 3}, {10, 2}, {20, 4}, {25, 6}, {29, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			query.JobID = "job-1"
+			reader, err := ReadMetricSeries(context.Background(), backend, &query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			metrics := readSeries(t, reader)
+			if len(metrics) != len(tt.want) {
+				t.Fatalf("got %d samples, want %d", len(metrics), len(tt.want))
+			}
+			field := lookupMetricField(tt.query.Metric)
+			for i, metric := range metrics {
+				got := [2]float64{float64(metric.Timestamp / int64(time.Second)), field.get(metric.Data)}
+				if got != tt.want[i] || metric.JobId != "job-1" {
+					t.Errorf("sample %d = %v of %s, want %v", i, got, metric.JobId, tt.want[i])
+				}
+				if tt.query.Metric != "memory_usage" && metric.Data.MemoryUsage != 0 {
+					t.Errorf("sample %d keeps metrics the query did not select: %v", i, metric.Data)
+				}
+			}
+		})
+	}
+
+	// Backends are read raw for the rollup
+	if first := backend.queries[0]; first.Aggregation != "" || first.Metric != "" || first.Step != 0 {
+		t.Errorf("raw query = %+v", first)
+	}
+
+	if _, err := ReadMetricSeries(context.Background(), backend, &MetricQuery{JobID: "job-1", Aggregation: "median"}); err == nil {
+		t.Error("expected error for an unsupported aggregation")
+	}
+	for _, query := range []MetricQuery{{JobID: "job-1", Metric: "gpu_temperature"}, {JobID: "job-1", Aggregation: "avg", Metric: "gpu_temperature"}} {
+		if _, err := ReadMetricSeries(context.Background(), backend, &query); err == nil {
+			t.Errorf("expected error for an unknown metric with aggregation %q", query.Aggregation)
+		}
+	}
+
+	failing := &sliceBackend{metrics: []*ipcpb.Metric{metricSample(0, 1, 1)}, err: errors.New("corrupt segment")}
+	reader, err := ReadMetricSeries(context.Background(), failing, &MetricQuery{JobID: "job-1", Aggregation: "avg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range reader.Channel {
+	}
+	if err := <-reader.Error; err == nil || err.Error() != "corrupt segment" {
+		t.Errorf("reader error = %v, want the backend's", err)
+	}
+}
+
+func TestReadMetricSeries_NativeAggregation(t *testing.T) {
+	backend := &nativeBackend{sliceBackend{metrics: []*ipcpb.Metric{metricSample(60, 0.5, 2048)}}}
+	reader, err := ReadMetricSeries(context.Background(), backend, &MetricQuery{JobID: "job-1", Aggregation: "max", Metric: "memory_usage", Step: int64(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := readSeries(t, reader)
+	if len(metrics) != 1 || metrics[0].Data.MemoryUsage != 2048 || metrics[0].Data.CpuUsage != 0 {
+		t.Errorf("metrics = %v", metrics)
+	}
+	if query := backend.queries[0]; query.Aggregation != "max" || query.Step != int64(time.Minute) {
+		t.Errorf("backend query = %+v, want the aggregation passed down", query)
+	}
+}
+
+// nativeBackend aggregates itself, like ClickHouse
+type nativeBackend struct {
+	sliceBackend
+}
+
+func (b *nativeBackend) aggregatesMetrics() {}
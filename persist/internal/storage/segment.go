@@ -0,0 +1,814 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ehsaniara/joblet/pkg/logger"
+)
+
+// A segment store appends the records of all jobs to shared segment files
+// instead of keeping files per job:
+//
+//	<dir>/00000001.seg  sealed, with its index in 00000001.idx
+//	<dir>/00000002.seg  active, records are appended to it
+//
+// A segment starts with a header holding the ID of the oldest segment it
+// covers: its own ID, or the first segment of a compacted run. Records follow:
+//
+//	size uint32 | crc32 uint32 | kind byte | job ID size uint16 | job ID | data
+//
+// size and the CRC cover everything after the CRC. The in-memory index maps
+// each job to its records; it is loaded from the .idx files of the sealed
+// segments and by scanning the active one, whose torn tail left by a crash is
+// cut off.
+
+const (
+	segmentMagic      = "JSEG"
+	segmentIndexMagic = "JIDX"
+	segmentVersion    = 1
+
+	segmentHeaderSize = 4 + 1 + 4 // magic, version, first covered segment
+	recordHeaderSize  = 4 + 4     // size, CRC
+
+	// compactionGarbageRatio is the share of dead bytes at which a sealed
+	// segment is rewritten
+	compactionGarbageRatio = 0.5
+)
+
+// recordKind tells what a record holds
+type recordKind byte
+
+const (
+	kindStdout  recordKind = 1 // Gzipped JSONL log lines
+	kindStderr  recordKind = 2 // Gzipped JSONL log lines
+	kindMetrics recordKind = 3 // Gzipped JSONL metrics
+	kindArchive recordKind = 4 // Archived job record, the latest one wins
+	kindDelete  recordKind = 5 // Tombstone: the job's earlier records are dead
+)
+
+// recordRef locates a record
+type recordRef struct {
+	segment uint32
+	offset  int64
+	size    int64 // Including the record header
+	kind    recordKind
+}
+
+// indexEntry is a record of a segment's index
+type indexEntry struct {
+	jobID string
+	ref   recordRef
+}
+
+type segmentInfo struct {
+	id    uint32
+	first uint32 // Oldest segment covered, the segment itself unless compacted
+	file  *os.File
+	size  int64
+	live  int64 // Bytes of live records and tombstones
+	index []indexEntry
+}
+
+// garbage returns the share of the segment's records that are dead
+func (s *segmentInfo) garbage() float64 {
+	records := s.size - segmentHeaderSize
+	if records <= 0 {
+		return 0
+	}
+	return 1 - float64(s.live)/float64(records)
+}
+
+type segmentStore struct {
+	dir     string
+	maxSize int64
+	logger  *logger.Logger
+
+	mu       sync.RWMutex
+	segments map[uint32]*segmentInfo
+	order    []uint32 // Segment IDs, oldest first; the last one is active
+	jobs     map[string][]recordRef
+}
+
+func segmentPath(dir string, id uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("%08d.seg", id))
+}
+
+func indexPath(dir string, id uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("%08d.idx", id))
+}
+
+// openSegmentStore loads the segments in dir, creating it when missing
+func openSegmentStore(dir string, maxSize int64, log *logger.Logger) (*segmentStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create segment directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read segment directory: %w", err)
+	}
+
+	var ids []uint32
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".tmp") {
+			// Left behind by a compaction or index write that did not finish
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if !strings.HasSuffix(name, ".seg") {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, ".seg"), 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint32(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	ss := &segmentStore{
+		dir:      dir,
+		maxSize:  maxSize,
+		logger:   log,
+		segments: make(map[uint32]*segmentInfo),
+		jobs:     make(map[string][]recordRef),
+	}
+
+	// A compacted segment supersedes the segments of its run; they are
+	// still there when the compaction was interrupted after the rename
+	firsts := make(map[uint32]uint32, len(ids))
+	for _, id := range ids {
+		first, err := readSegmentHeader(segmentPath(dir, id))
+		if err != nil {
+			ss.closeFiles()
+			return nil, err
+		}
+		firsts[id] = first
+	}
+	var kept []uint32
+	for i, id := range ids {
+		superseded := false
+		for _, later := range ids[i+1:] {
+			if firsts[later] <= id {
+				superseded = true
+				break
+			}
+		}
+		if superseded {
+			log.Info("Removing segment superseded by compaction", "segment", id)
+			os.Remove(segmentPath(dir, id))
+			os.Remove(indexPath(dir, id))
+			continue
+		}
+		kept = append(kept, id)
+	}
+
+	for i, id := range kept {
+		active := i == len(kept)-1
+		if err := ss.load(id, firsts[id], active); err != nil {
+			ss.closeFiles()
+			return nil, err
+		}
+	}
+
+	if len(ss.order) == 0 || ss.activeSegment().size >= maxSize {
+		if err := ss.rotate(); err != nil {
+			ss.closeFiles()
+			return nil, err
+		}
+	}
+	return ss, nil
+}
+
+// load opens segment id and adds its records to the index. Sealed segments
+// are indexed from their .idx file when it is intact; the active segment is
+// always scanned.
+func (ss *segmentStore) load(id, first uint32, active bool) error {
+	path := segmentPath(ss.dir, id)
+	flags := os.O_RDONLY
+	if active {
+		flags = os.O_RDWR
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open segment %d: %w", id, err)
+	}
+	seg := &segmentInfo{id: id, first: first, file: file}
+
+	var entries []indexEntry
+	loaded := false
+	if !active {
+		entries, err = readIndex(indexPath(ss.dir, id))
+		loaded = err == nil
+	}
+	if loaded {
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to stat segment %d: %w", id, err)
+		}
+		seg.size = stat.Size()
+	} else {
+		var end int64
+		entries, end, err = scanSegment(file, id)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to stat segment %d: %w", id, err)
+		}
+		if end < stat.Size() {
+			ss.logger.Warn("Cutting off torn segment tail", "segment", id, "validBytes", end, "size", stat.Size())
+			if active {
+				if err := file.Truncate(end); err != nil {
+					file.Close()
+					return fmt.Errorf("failed to truncate segment %d: %w", id, err)
+				}
+			}
+		}
+		seg.size = end
+		if !active {
+			if err := writeIndex(indexPath(ss.dir, id), entries); err != nil {
+				ss.logger.Warn("Failed to write segment index", "segment", id, "error", err)
+			}
+		}
+	}
+
+	ss.segments[id] = seg
+	ss.order = append(ss.order, id)
+	for _, entry := range entries {
+		ss.apply(seg, entry)
+	}
+	return nil
+}
+
+// apply adds a record of seg to the index
+func (ss *segmentStore) apply(seg *segmentInfo, entry indexEntry) {
+	seg.index = append(seg.index, entry)
+	seg.live += entry.ref.size
+
+	switch entry.ref.kind {
+	case kindDelete:
+		for _, ref := range ss.jobs[entry.jobID] {
+			ss.segments[ref.segment].live -= ref.size
+		}
+		delete(ss.jobs, entry.jobID)
+		return
+	case kindArchive:
+		refs := ss.jobs[entry.jobID][:0]
+		for _, ref := range ss.jobs[entry.jobID] {
+			if ref.kind == kindArchive {
+				ss.segments[ref.segment].live -= ref.size
+				continue
+			}
+			refs = append(refs, ref)
+		}
+		ss.jobs[entry.jobID] = refs
+	}
+	ss.jobs[entry.jobID] = append(ss.jobs[entry.jobID], entry.ref)
+}
+
+func (ss *segmentStore) activeSegment() *segmentInfo {
+	return ss.segments[ss.order[len(ss.order)-1]]
+}
+
+// rotate seals the active segment, writing its index, and starts a new one
+func (ss *segmentStore) rotate() error {
+	id := uint32(1)
+	if len(ss.order) > 0 {
+		active := ss.activeSegment()
+		if err := writeIndex(indexPath(ss.dir, active.id), active.index); err != nil {
+			return fmt.Errorf("failed to write index of segment %d: %w", active.id, err)
+		}
+		id = active.id + 1
+	}
+
+	file, err := os.OpenFile(segmentPath(ss.dir, id), os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create segment %d: %w", id, err)
+	}
+	if _, err := file.Write(segmentHeader(id)); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write segment %d header: %w", id, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	syncDir(ss.dir)
+
+	ss.segments[id] = &segmentInfo{id: id, first: id, file: file, size: segmentHeaderSize}
+	ss.order = append(ss.order, id)
+	ss.logger.Debug("Started segment", "segment", id)
+	return nil
+}
+
+// pendingRecord is a record to append
+type pendingRecord struct {
+	kind recordKind
+	data []byte
+}
+
+// append writes records of a job to the active segment and syncs it
+func (ss *segmentStore) append(jobID string, records ...pendingRecord) error {
+	if len(jobID) > 0xffff {
+		return fmt.Errorf("job ID too long")
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.activeSegment().size >= ss.maxSize {
+		if err := ss.rotate(); err != nil {
+			return err
+		}
+	}
+	active := ss.activeSegment()
+
+	var buf []byte
+	var entries []indexEntry
+	for _, record := range records {
+		encoded := encodeRecord(record.kind, jobID, record.data)
+		entries = append(entries, indexEntry{jobID: jobID, ref: recordRef{
+			segment: active.id,
+			offset:  active.size + int64(len(buf)),
+			size:    int64(len(encoded)),
+			kind:    record.kind,
+		}})
+		buf = append(buf, encoded...)
+	}
+	if _, err := active.file.WriteAt(buf, active.size); err != nil {
+		return fmt.Errorf("failed to append to segment %d: %w", active.id, err)
+	}
+	if err := active.file.Sync(); err != nil {
+		return err
+	}
+
+	active.size += int64(len(buf))
+	for _, entry := range entries {
+		ss.apply(active, entry)
+	}
+	return nil
+}
+
+// read returns the data of a job's records of the given kinds, in the order
+// they were written. exists is false when the store has no record of the job.
+func (ss *segmentStore) read(jobID string, kinds ...recordKind) (data [][]byte, exists bool, err error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	refs, exists := ss.jobs[jobID]
+	if !exists {
+		return nil, false, nil
+	}
+	for _, ref := range refs {
+		if !hasKind(kinds, ref.kind) {
+			continue
+		}
+		_, _, payload, err := ss.readRecord(ref)
+		if err != nil {
+			return nil, true, err
+		}
+		data = append(data, payload)
+	}
+	return data, true, nil
+}
+
+// archived returns the latest archived record of every job that has one
+func (ss *segmentStore) archived() ([][]byte, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var records [][]byte
+	for _, refs := range ss.jobs {
+		for _, ref := range refs {
+			if ref.kind != kindArchive {
+				continue
+			}
+			_, _, payload, err := ss.readRecord(ref)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, payload)
+		}
+	}
+	return records, nil
+}
+
+// delete drops the job's records from the index and appends a tombstone so
+// they stay dead after a restart
+func (ss *segmentStore) delete(jobID string) error {
+	ss.mu.RLock()
+	_, exists := ss.jobs[jobID]
+	ss.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+	return ss.append(jobID, pendingRecord{kind: kindDelete})
+}
+
+func (ss *segmentStore) readRecord(ref recordRef) (recordKind, string, []byte, error) {
+	seg := ss.segments[ref.segment]
+	buf := make([]byte, ref.size)
+	if _, err := seg.file.ReadAt(buf, ref.offset); err != nil {
+		return 0, "", nil, fmt.Errorf("failed to read segment %d at %d: %w", ref.segment, ref.offset, err)
+	}
+	kind, jobID, data, err := decodeRecord(buf)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("segment %d at %d: %w", ref.segment, ref.offset, err)
+	}
+	return kind, jobID, data, nil
+}
+
+// compact rewrites runs of sealed segments that are mostly dead or small
+// into single segments holding each job's live records next to each other.
+// Writes wait while a run is rewritten. It returns the number of segments
+// removed.
+func (ss *segmentStore) compact() (int, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	removed := 0
+	for {
+		run := ss.compactionRun()
+		if run == nil {
+			return removed, nil
+		}
+		if err := ss.rewrite(run); err != nil {
+			return removed, err
+		}
+		removed += len(run) - 1
+	}
+}
+
+// compactionRun returns the oldest run of adjacent sealed segments worth
+// rewriting: segments at least compactionGarbageRatio dead or less than half
+// full, whose live records fit in one segment. A run of one segment is only
+// worth it when the segment is mostly dead.
+func (ss *segmentStore) compactionRun() []*segmentInfo {
+	sealed := ss.order[:len(ss.order)-1]
+	candidate := func(seg *segmentInfo) bool {
+		return seg.garbage() >= compactionGarbageRatio || seg.size < ss.maxSize/2
+	}
+
+	for i := 0; i < len(sealed); i++ {
+		seg := ss.segments[sealed[i]]
+		if !candidate(seg) {
+			continue
+		}
+		run := []*segmentInfo{seg}
+		live := seg.live
+		for _, id := range sealed[i+1:] {
+			next := ss.segments[id]
+			if !candidate(next) || live+next.live > ss.maxSize {
+				break
+			}
+			run = append(run, next)
+			live += next.live
+		}
+		if len(run) > 1 || seg.garbage() >= compactionGarbageRatio {
+			return run
+		}
+	}
+	return nil
+}
+
+// rewrite replaces a run of sealed segments with one segment, named after
+// the last of the run, holding their live records grouped by job. The
+// tombstones of the run are kept unless it starts at the oldest segment,
+// since older segments may still hold records they killed.
+func (ss *segmentStore) rewrite(run []*segmentInfo) error {
+	first, last := run[0], run[len(run)-1]
+	inRun := make(map[uint32]bool, len(run))
+	for _, seg := range run {
+		inRun[seg.id] = true
+	}
+	keepTombstones := first.id != ss.order[0]
+
+	tmpPath := segmentPath(ss.dir, last.id) + ".tmp"
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted segment: %w", err)
+	}
+	fail := func(err error) error {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	w := bufio.NewWriterSize(out, 1<<20)
+	if _, err := w.Write(segmentHeader(first.id)); err != nil {
+		return fail(err)
+	}
+	offset := int64(segmentHeaderSize)
+	var entries []indexEntry
+	copyRecord := func(jobID string, ref recordRef) error {
+		buf := make([]byte, ref.size)
+		if _, err := ss.segments[ref.segment].file.ReadAt(buf, ref.offset); err != nil {
+			return fmt.Errorf("failed to read segment %d at %d: %w", ref.segment, ref.offset, err)
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		entries = append(entries, indexEntry{jobID: jobID, ref: recordRef{segment: last.id, offset: offset, size: ref.size, kind: ref.kind}})
+		offset += ref.size
+		return nil
+	}
+
+	// Tombstones go first: a job's live records all follow its tombstones
+	if keepTombstones {
+		for _, seg := range run {
+			for _, entry := range seg.index {
+				if entry.ref.kind == kindDelete {
+					if err := copyRecord(entry.jobID, entry.ref); err != nil {
+						return fail(err)
+					}
+				}
+			}
+		}
+	}
+
+	jobIDs := make([]string, 0, len(ss.jobs))
+	for jobID := range ss.jobs {
+		jobIDs = append(jobIDs, jobID)
+	}
+	sort.Strings(jobIDs)
+	for _, jobID := range jobIDs {
+		for _, ref := range ss.jobs[jobID] {
+			if inRun[ref.segment] {
+				if err := copyRecord(jobID, ref); err != nil {
+					return fail(err)
+				}
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := out.Sync(); err != nil {
+		return fail(err)
+	}
+
+	// Stale indexes go before the rename so a crash never pairs the new
+	// segment with the old index. A crash after the rename leaves the older
+	// segments of the run, which the header marks as superseded.
+	for _, seg := range run {
+		os.Remove(indexPath(ss.dir, seg.id))
+	}
+	if err := os.Rename(tmpPath, segmentPath(ss.dir, last.id)); err != nil {
+		return fail(fmt.Errorf("failed to install compacted segment: %w", err))
+	}
+	syncDir(ss.dir)
+	for _, seg := range run {
+		seg.file.Close()
+		delete(ss.segments, seg.id)
+		if seg != last {
+			os.Remove(segmentPath(ss.dir, seg.id))
+		}
+	}
+	if err := writeIndex(indexPath(ss.dir, last.id), entries); err != nil {
+		ss.logger.Warn("Failed to write segment index", "segment", last.id, "error", err)
+	}
+
+	compacted := &segmentInfo{id: last.id, first: first.id, file: out, size: offset, index: entries}
+	for _, entry := range entries {
+		compacted.live += entry.ref.size
+	}
+	ss.segments[last.id] = compacted
+
+	order := ss.order[:0]
+	for _, id := range ss.order {
+		if !inRun[id] || id == last.id {
+			order = append(order, id)
+		}
+	}
+	ss.order = order
+
+	moved := make(map[string][]recordRef)
+	for _, entry := range entries {
+		if entry.ref.kind != kindDelete {
+			moved[entry.jobID] = append(moved[entry.jobID], entry.ref)
+		}
+	}
+	for jobID, refs := range moved {
+		var kept []recordRef
+		for _, ref := range ss.jobs[jobID] {
+			if !inRun[ref.segment] {
+				kept = append(kept, ref)
+			}
+		}
+		kept = append(kept, refs...)
+		sort.Slice(kept, func(i, j int) bool {
+			if kept[i].segment != kept[j].segment {
+				return kept[i].segment < kept[j].segment
+			}
+			return kept[i].offset < kept[j].offset
+		})
+		ss.jobs[jobID] = kept
+	}
+
+	ss.logger.Info("Compacted segments", "from", first.id, "to", last.id, "bytes", offset)
+	return nil
+}
+
+// close closes the segment files
+func (ss *segmentStore) close() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.closeFiles()
+}
+
+func (ss *segmentStore) closeFiles() error {
+	var errs []error
+	for _, seg := range ss.segments {
+		if err := seg.file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	ss.segments = map[uint32]*segmentInfo{}
+	return errors.Join(errs...)
+}
+
+func hasKind(kinds []recordKind, kind recordKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentHeader(first uint32) []byte {
+	header := make([]byte, segmentHeaderSize)
+	copy(header, segmentMagic)
+	header[4] = segmentVersion
+	binary.LittleEndian.PutUint32(header[5:], first)
+	return header
+}
+
+func readSegmentHeader(path string) (uint32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, segmentHeaderSize)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return 0, fmt.Errorf("failed to read header of %s: %w", path, err)
+	}
+	if string(header[:4]) != segmentMagic || header[4] != segmentVersion {
+		return 0, fmt.Errorf("%s is not a version %d segment", path, segmentVersion)
+	}
+	return binary.LittleEndian.Uint32(header[5:]), nil
+}
+
+func encodeRecord(kind recordKind, jobID string, data []byte) []byte {
+	size := 1 + 2 + len(jobID) + len(data)
+	buf := make([]byte, recordHeaderSize+size)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(size))
+	buf[8] = byte(kind)
+	binary.LittleEndian.PutUint16(buf[9:], uint16(len(jobID)))
+	copy(buf[11:], jobID)
+	copy(buf[11+len(jobID):], data)
+	binary.LittleEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(buf[recordHeaderSize:]))
+	return buf
+}
+
+func decodeRecord(buf []byte) (recordKind, string, []byte, error) {
+	if len(buf) < recordHeaderSize+3 {
+		return 0, "", nil, fmt.Errorf("record too short")
+	}
+	body := buf[recordHeaderSize:]
+	if int(binary.LittleEndian.Uint32(buf[0:])) != len(body) {
+		return 0, "", nil, fmt.Errorf("record size mismatch")
+	}
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(buf[4:]) {
+		return 0, "", nil, fmt.Errorf("record checksum mismatch")
+	}
+	idLen := int(binary.LittleEndian.Uint16(body[1:]))
+	if 3+idLen > len(body) {
+		return 0, "", nil, fmt.Errorf("record job ID out of bounds")
+	}
+	return recordKind(body[0]), string(body[3 : 3+idLen]), body[3+idLen:], nil
+}
+
+// scanSegment reads the records of a segment, stopping at the first one
+// that is incomplete or fails its checksum. It returns the records and where
+// the valid part of the segment ends.
+func scanSegment(file *os.File, id uint32) ([]indexEntry, int64, error) {
+	if _, err := file.Seek(segmentHeaderSize, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("failed to scan segment %d: %w", id, err)
+	}
+	r := bufio.NewReaderSize(file, 1<<20)
+	offset := int64(segmentHeaderSize)
+	var entries []indexEntry
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return entries, offset, nil
+		}
+		size := int64(binary.LittleEndian.Uint32(header))
+		buf := make([]byte, recordHeaderSize+size)
+		copy(buf, header)
+		if _, err := io.ReadFull(r, buf[recordHeaderSize:]); err != nil {
+			return entries, offset, nil
+		}
+		kind, jobID, _, err := decodeRecord(buf)
+		if err != nil {
+			return entries, offset, nil
+		}
+		entries = append(entries, indexEntry{jobID: jobID, ref: recordRef{segment: id, offset: offset, size: int64(len(buf)), kind: kind}})
+		offset += int64(len(buf))
+	}
+}
+
+// writeIndex stores the records of a sealed segment:
+//
+//	magic | version | entries | crc32 of everything before
+//
+// with each entry: kind byte | job ID size uint16 | job ID | offset int64 | size uint32
+func writeIndex(path string, entries []indexEntry) error {
+	buf := []byte(segmentIndexMagic)
+	buf = append(buf, segmentVersion)
+	for _, entry := range entries {
+		buf = append(buf, byte(entry.ref.kind))
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(entry.jobID)))
+		buf = append(buf, entry.jobID...)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(entry.ref.offset))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(entry.ref.size))
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// readIndex loads an index written by writeIndex. The segment ID of the
+// entries is taken from the index file name.
+func readIndex(path string) ([]indexEntry, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) < 5+4 || string(buf[:4]) != segmentIndexMagic || buf[4] != segmentVersion {
+		return nil, fmt.Errorf("%s is not a version %d index", path, segmentVersion)
+	}
+	body := buf[:len(buf)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(buf[len(buf)-4:]) {
+		return nil, fmt.Errorf("%s: checksum mismatch", path)
+	}
+	id, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), ".idx"), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []indexEntry
+	for pos := 5; pos < len(body); {
+		if pos+3 > len(body) {
+			return nil, fmt.Errorf("%s: truncated entry", path)
+		}
+		kind := recordKind(body[pos])
+		idLen := int(binary.LittleEndian.Uint16(body[pos+1:]))
+		pos += 3
+		if pos+idLen+12 > len(body) {
+			return nil, fmt.Errorf("%s: truncated entry", path)
+		}
+		jobID := string(body[pos : pos+idLen])
+		pos += idLen
+		offset := int64(binary.LittleEndian.Uint64(body[pos:]))
+		size := int64(binary.LittleEndian.Uint32(body[pos+8:]))
+		pos += 12
+		entries = append(entries, indexEntry{jobID: jobID, ref: recordRef{segment: uint32(id), offset: offset, size: size, kind: kind}})
+	}
+	return entries, nil
+}
+
+// syncDir makes renames and new files in dir durable
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -10,6 +10,18 @@ import (
 )
 
 type FakeBackend struct {
+	ArchiveJobStub        func(string, []byte) error
+	archiveJobMutex       sync.RWMutex
+	archiveJobArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+	}
+	archiveJobReturns struct {
+		result1 error
+	}
+	archiveJobReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CloseStub        func() error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -31,6 +43,20 @@ type FakeBackend struct {
 	deleteJobReturnsOnCall map[int]struct {
 		result1 error
 	}
+	JobDurationsStub        func([]string, int) ([]storage.JobDuration, error)
+	jobDurationsMutex       sync.RWMutex
+	jobDurationsArgsForCall []struct {
+		arg1 []string
+		arg2 int
+	}
+	jobDurationsReturns struct {
+		result1 []storage.JobDuration
+		result2 error
+	}
+	jobDurationsReturnsOnCall map[int]struct {
+		result1 []storage.JobDuration
+		result2 error
+	}
 	ReadLogsStub        func(context.Context, *storage.LogQuery) (*storage.LogReader, error)
 	readLogsMutex       sync.RWMutex
 	readLogsArgsForCall []struct {
@@ -87,6 +113,73 @@ type FakeBackend struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeBackend) ArchiveJob(arg1 string, arg2 []byte) error {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.archiveJobMutex.Lock()
+	ret, specificReturn := fake.archiveJobReturnsOnCall[len(fake.archiveJobArgsForCall)]
+	fake.archiveJobArgsForCall = append(fake.archiveJobArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+	}{arg1, arg2Copy})
+	stub := fake.ArchiveJobStub
+	fakeReturns := fake.archiveJobReturns
+	fake.recordInvocation("ArchiveJob", []interface{}{arg1, arg2Copy})
+	fake.archiveJobMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackend) ArchiveJobCallCount() int {
+	fake.archiveJobMutex.RLock()
+	defer fake.archiveJobMutex.RUnlock()
+	return len(fake.archiveJobArgsForCall)
+}
+
+func (fake *FakeBackend) ArchiveJobCalls(stub func(string, []byte) error) {
+	fake.archiveJobMutex.Lock()
+	defer fake.archiveJobMutex.Unlock()
+	fake.ArchiveJobStub = stub
+}
+
+func (fake *FakeBackend) ArchiveJobArgsForCall(i int) (string, []byte) {
+	fake.archiveJobMutex.RLock()
+	defer fake.archiveJobMutex.RUnlock()
+	argsForCall := fake.archiveJobArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBackend) ArchiveJobReturns(result1 error) {
+	fake.archiveJobMutex.Lock()
+	defer fake.archiveJobMutex.Unlock()
+	fake.ArchiveJobStub = nil
+	fake.archiveJobReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackend) ArchiveJobReturnsOnCall(i int, result1 error) {
+	fake.archiveJobMutex.Lock()
+	defer fake.archiveJobMutex.Unlock()
+	fake.ArchiveJobStub = nil
+	if fake.archiveJobReturnsOnCall == nil {
+		fake.archiveJobReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.archiveJobReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackend) Close() error {
 	fake.closeMutex.Lock()
 	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
@@ -201,6 +294,76 @@ func (fake *FakeBackend) DeleteJobReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBackend) JobDurations(arg1 []string, arg2 int) ([]storage.JobDuration, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.jobDurationsMutex.Lock()
+	ret, specificReturn := fake.jobDurationsReturnsOnCall[len(fake.jobDurationsArgsForCall)]
+	fake.jobDurationsArgsForCall = append(fake.jobDurationsArgsForCall, struct {
+		arg1 []string
+		arg2 int
+	}{arg1Copy, arg2})
+	stub := fake.JobDurationsStub
+	fakeReturns := fake.jobDurationsReturns
+	fake.recordInvocation("JobDurations", []interface{}{arg1Copy, arg2})
+	fake.jobDurationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBackend) JobDurationsCallCount() int {
+	fake.jobDurationsMutex.RLock()
+	defer fake.jobDurationsMutex.RUnlock()
+	return len(fake.jobDurationsArgsForCall)
+}
+
+func (fake *FakeBackend) JobDurationsCalls(stub func([]string, int) ([]storage.JobDuration, error)) {
+	fake.jobDurationsMutex.Lock()
+	defer fake.jobDurationsMutex.Unlock()
+	fake.JobDurationsStub = stub
+}
+
+func (fake *FakeBackend) JobDurationsArgsForCall(i int) ([]string, int) {
+	fake.jobDurationsMutex.RLock()
+	defer fake.jobDurationsMutex.RUnlock()
+	argsForCall := fake.jobDurationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBackend) JobDurationsReturns(result1 []storage.JobDuration, result2 error) {
+	fake.jobDurationsMutex.Lock()
+	defer fake.jobDurationsMutex.Unlock()
+	fake.JobDurationsStub = nil
+	fake.jobDurationsReturns = struct {
+		result1 []storage.JobDuration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBackend) JobDurationsReturnsOnCall(i int, result1 []storage.JobDuration, result2 error) {
+	fake.jobDurationsMutex.Lock()
+	defer fake.jobDurationsMutex.Unlock()
+	fake.JobDurationsStub = nil
+	if fake.jobDurationsReturnsOnCall == nil {
+		fake.jobDurationsReturnsOnCall = make(map[int]struct {
+			result1 []storage.JobDuration
+			result2 error
+		})
+	}
+	fake.jobDurationsReturnsOnCall[i] = struct {
+		result1 []storage.JobDuration
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBackend) ReadLogs(arg1 context.Context, arg2 *storage.LogQuery) (*storage.LogReader, error) {
 	fake.readLogsMutex.Lock()
 	ret, specificReturn := fake.readLogsReturnsOnCall[len(fake.readLogsArgsForCall)]
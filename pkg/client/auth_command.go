@@ -0,0 +1,106 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// authCommandTimeout bounds how long the node's auth command may take
+const authCommandTimeout = 30 * time.Second
+
+// authHeaderTTL is how long the auth command's header is reused before the
+// command runs again, well within the lifetime of a Kerberos ticket
+const authHeaderTTL = 5 * time.Minute
+
+// commandCredentials sends the "authorization" header printed by the node's
+// auth command, e.g. a SPNEGO ticket obtained from the user's Kerberos cache,
+// with every unary and streaming call. The header is reused for
+// authHeaderTTL; a failed command is retried on the next call.
+type commandCredentials struct {
+	command []string
+	now     func() time.Time
+
+	mu      sync.Mutex
+	header  string
+	expires time.Time
+}
+
+func newCommandCredentials(command []string) *commandCredentials {
+	return &commandCredentials{command: command, now: time.Now}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (c *commandCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.header == "" || !c.now().Before(c.expires) {
+		header, err := runAuthCommand(ctx, c.command)
+		if err != nil {
+			return nil, err
+		}
+		c.header, c.expires = header, c.now().Add(authHeaderTTL)
+	}
+	return map[string]string{"authorization": c.header}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials; tokens
+// are never sent over plaintext connections
+func (c *commandCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// runAuthCommand runs the auth command and returns the header value it
+// printed
+func runAuthCommand(ctx context.Context, command []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), authCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("auth command %s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	header := strings.TrimSpace(stdout.String())
+	if header == "" {
+		return "", fmt.Errorf("auth command %s printed nothing", command[0])
+	}
+	return authorizationHeader(header), nil
+}
+
+// tokenCredentials sends the node's static token as the "authorization"
+// header with every call
+type tokenCredentials struct {
+	header string
+}
+
+func newTokenCredentials(token string) tokenCredentials {
+	return tokenCredentials{header: authorizationHeader(strings.TrimSpace(token))}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (c tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": c.header}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// authorizationHeader returns the header value for a token; a bare token is
+// sent as a bearer token
+func authorizationHeader(token string) string {
+	if !strings.Contains(token, " ") {
+		return "Bearer " + token
+	}
+	return token
+}
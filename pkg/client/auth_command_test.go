@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenCredentials(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{token: "s3cr3t", want: "Bearer s3cr3t"},
+		{token: "s3cr3t\n", want: "Bearer s3cr3t"},
+		{token: "Negotiate YIIB", want: "Negotiate YIIB"},
+	}
+	for _, tt := range tests {
+		md, err := newTokenCredentials(tt.token).GetRequestMetadata(context.Background())
+		if err != nil {
+			t.Fatalf("GetRequestMetadata(%q) error = %v", tt.token, err)
+		}
+		if md["authorization"] != tt.want {
+			t.Errorf("GetRequestMetadata(%q) authorization = %q, want %q", tt.token, md["authorization"], tt.want)
+		}
+	}
+}
+
+func TestCommandCredentials(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	// The command prints how often it ran and fails on its first run
+	count := filepath.Join(t.TempDir(), "count")
+	script := `n=$(($(cat "$0" 2>/dev/null || echo 0) + 1)); echo $n > "$0"; [ $n -gt 1 ] || exit 1; echo token-$n`
+	creds := newCommandCredentials([]string{"sh", "-c", script, count})
+	now := time.Now()
+	creds.now = func() time.Time { return now }
+
+	if _, err := creds.GetRequestMetadata(context.Background()); err == nil {
+		t.Fatal("GetRequestMetadata() succeeded on a failing command, want error")
+	}
+
+	md, err := creds.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("GetRequestMetadata() after a failure error = %v, want the command to run again", err)
+	}
+	if md["authorization"] != "Bearer token-2" {
+		t.Errorf("authorization = %q, want Bearer token-2", md["authorization"])
+	}
+
+	md, _ = creds.GetRequestMetadata(context.Background())
+	if md["authorization"] != "Bearer token-2" {
+		t.Errorf("authorization within the TTL = %q, want the cached Bearer token-2", md["authorization"])
+	}
+
+	now = now.Add(authHeaderTTL)
+	md, _ = creds.GetRequestMetadata(context.Background())
+	if md["authorization"] != "Bearer token-3" {
+		t.Errorf("authorization after the TTL = %q, want Bearer token-3", md["authorization"])
+	}
+}
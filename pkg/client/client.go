@@ -2,15 +2,43 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	artifactspb "github.com/ehsaniara/joblet/internal/proto/gen/artifacts"
+	backuppb "github.com/ehsaniara/joblet/internal/proto/gen/backup"
+	capacitypb "github.com/ehsaniara/joblet/internal/proto/gen/capacity"
+	eventspb "github.com/ehsaniara/joblet/internal/proto/gen/events"
+	gitsourcepb "github.com/ehsaniara/joblet/internal/proto/gen/gitsource"
+	idspb "github.com/ehsaniara/joblet/internal/proto/gen/ids"
+	jobenvpb "github.com/ehsaniara/joblet/internal/proto/gen/jobenv"
+	jobfspb "github.com/ehsaniara/joblet/internal/proto/gen/jobfs"
+	jobnetpb "github.com/ehsaniara/joblet/internal/proto/gen/jobnet"
+	jobspb "github.com/ehsaniara/joblet/internal/proto/gen/jobs"
+	lintpb "github.com/ehsaniara/joblet/internal/proto/gen/lint"
+	logspb "github.com/ehsaniara/joblet/internal/proto/gen/logs"
+	nodemetricspb "github.com/ehsaniara/joblet/internal/proto/gen/nodemetrics"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	registrypb "github.com/ehsaniara/joblet/internal/proto/gen/registry"
+	reportspb "github.com/ehsaniara/joblet/internal/proto/gen/reports"
+	runtimespb "github.com/ehsaniara/joblet/internal/proto/gen/runtimes"
+	uploadspb "github.com/ehsaniara/joblet/internal/proto/gen/uploads"
+	workflowspb "github.com/ehsaniara/joblet/internal/proto/gen/workflows"
 	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -20,15 +48,49 @@ type JobClient struct {
 	volumeClient     pb.VolumeServiceClient
 	monitoringClient pb.MonitoringServiceClient
 	runtimeClient    pb.RuntimeServiceClient
+	capacityClient   capacitypb.CapacityServiceClient
+	registryClient   registrypb.WorkflowRegistryServiceClient
+	logsClient       logspb.LogServiceClient
+	bulkClient       jobspb.BulkJobServiceClient
+	nodesClient      nodespb.NodeRegistryServiceClient
+	uploadsClient    uploadspb.UploadSyncServiceClient
+	artifactsClient  artifactspb.ArtifactServiceClient
+	metricsClient    nodemetricspb.NodeMetricsServiceClient
+	jobfsClient      jobfspb.JobFilesystemServiceClient
+	gitsourceClient  gitsourcepb.GitSourceServiceClient
+	reportsClient    reportspb.WorkflowReportServiceClient
+	lintClient       lintpb.SpecLintServiceClient
+	runtimesClient   runtimespb.RuntimeInfoServiceClient
+	channelsClient   runtimespb.RuntimeChannelServiceClient
+	workflowsClient  workflowspb.WorkflowControlServiceClient
+	eventsClient     eventspb.JobEventServiceClient
+	jobnetClient     jobnetpb.JobNetworkServiceClient
+	jobenvClient     jobenvpb.JobEnvironmentServiceClient
+	idsClient        idspb.IdResolverServiceClient
+	backupClient     backuppb.BackupServiceClient
 	conn             *grpc.ClientConn
+
+	// shared clients belong to a Pool, which owns closing the connection
+	shared bool
 }
 
 // NewJobClient creates a new job client from a node configuration
 func NewJobClient(node *config.Node) (*JobClient, error) {
+	return NewJobClientWithOptions(node, DefaultOptions())
+}
+
+// NewJobClientWithOptions creates a job client with custom keepalive,
+// timeout and retry settings.
+func NewJobClientWithOptions(node *config.Node, opts Options) (*JobClient, error) {
 	if node == nil {
 		return nil, fmt.Errorf("node configuration cannot be nil")
 	}
 
+	node, err := node.ResolveKeychain()
+	if err != nil {
+		return nil, err
+	}
+
 	// Get TLS configuration from embedded certificates
 	tlsConfig, err := node.GetClientTLSConfig()
 	if err != nil {
@@ -37,11 +99,40 @@ func NewJobClient(node *config.Node) (*JobClient, error) {
 
 	creds := credentials.NewTLS(tlsConfig)
 
-	conn, err := grpc.NewClient(
-		node.Address,
+	// Keepalive set on the node overrides the options
+	if node.KeepAliveTime > 0 {
+		opts.KeepAliveTime = node.KeepAliveTime
+	}
+	if node.KeepAliveTimeout > 0 {
+		opts.KeepAliveTimeout = node.KeepAliveTimeout
+	}
+
+	interceptors := []grpc.UnaryClientInterceptor{opts.unaryInterceptor()}
+	if node.SigningKey != "" {
+		key, err := jobsign.ParsePrivateKey(node.SigningKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signingKey: %w", err)
+		}
+		interceptors = append(interceptors, signingInterceptor(key))
+	}
+
+	dialOptions := []grpc.DialOption{
 		grpc.WithTransportCredentials(creds),
 		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
-	)
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                opts.KeepAliveTime,
+			Timeout:             opts.KeepAliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+	}
+	if len(node.AuthCommand) > 0 {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(newCommandCredentials(node.AuthCommand)))
+	} else if node.Token != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(newTokenCredentials(node.Token)))
+	}
+
+	conn, err := grpc.NewClient(node.Address, dialOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to server %s: %w", node.Address, err)
 	}
@@ -52,29 +143,275 @@ func NewJobClient(node *config.Node) (*JobClient, error) {
 		volumeClient:     pb.NewVolumeServiceClient(conn),
 		monitoringClient: pb.NewMonitoringServiceClient(conn),
 		runtimeClient:    pb.NewRuntimeServiceClient(conn),
+		capacityClient:   capacitypb.NewCapacityServiceClient(conn),
+		registryClient:   registrypb.NewWorkflowRegistryServiceClient(conn),
+		logsClient:       logspb.NewLogServiceClient(conn),
+		bulkClient:       jobspb.NewBulkJobServiceClient(conn),
+		nodesClient:      nodespb.NewNodeRegistryServiceClient(conn),
+		uploadsClient:    uploadspb.NewUploadSyncServiceClient(conn),
+		artifactsClient:  artifactspb.NewArtifactServiceClient(conn),
+		metricsClient:    nodemetricspb.NewNodeMetricsServiceClient(conn),
+		jobfsClient:      jobfspb.NewJobFilesystemServiceClient(conn),
+		gitsourceClient:  gitsourcepb.NewGitSourceServiceClient(conn),
+		reportsClient:    reportspb.NewWorkflowReportServiceClient(conn),
+		lintClient:       lintpb.NewSpecLintServiceClient(conn),
+		runtimesClient:   runtimespb.NewRuntimeInfoServiceClient(conn),
+		channelsClient:   runtimespb.NewRuntimeChannelServiceClient(conn),
+		workflowsClient:  workflowspb.NewWorkflowControlServiceClient(conn),
+		eventsClient:     eventspb.NewJobEventServiceClient(conn),
+		jobnetClient:     jobnetpb.NewJobNetworkServiceClient(conn),
+		jobenvClient:     jobenvpb.NewJobEnvironmentServiceClient(conn),
+		idsClient:        idspb.NewIdResolverServiceClient(conn),
+		backupClient:     backuppb.NewBackupServiceClient(conn),
 		conn:             conn,
 	}, nil
 }
 
+// Close closes the connection. It is a no-op for clients handed out by a
+// Pool, whose connections stay open until the pool is closed.
 func (c *JobClient) Close() error {
+	if c.shared {
+		return nil
+	}
 	if c.conn != nil {
 		return c.conn.Close()
 	}
 	return nil
 }
 
+// WaitForConnection blocks until the connection is ready or ctx ends. It lets
+// callers tell an unreachable server apart from a slow one before sending a
+// request that is not safe to retry.
+func (c *JobClient) WaitForConnection(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("server %s unreachable (connection %s): %w", c.conn.Target(), strings.ToLower(state.String()), ctx.Err())
+		}
+	}
+}
+
 func (c *JobClient) GetConn() *grpc.ClientConn {
 	return c.conn
 }
 
 func (c *JobClient) RunJob(ctx context.Context, job *pb.RunJobRequest) (*pb.RunJobResponse, error) {
-	return c.jobClient.RunJob(ctx, job)
+	return c.jobClient.RunJob(withClientTime(ctx, job), job)
+}
+
+// withClientTime sends the client's clock with scheduled jobs, for the
+// server to compare with its own
+func withClientTime(ctx context.Context, job *pb.RunJobRequest) context.Context {
+	if job.Schedule == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, constants.ClientTimeHeader, time.Now().Format(time.RFC3339Nano))
+}
+
+// JobReuse says whether RunJob started a new job or answered with an
+// existing identical one.
+type JobReuse int
+
+const (
+	NewJob          JobReuse = iota
+	DeduplicatedJob          // An identical job was still active (JOBLET_DEDUP)
+	CachedJob                // An identical job completed within the cache TTL (JOBLET_CACHE_TTL)
+)
+
+// RunJobWithReuse runs a job like RunJob and also reports whether the server
+// answered with an existing identical one instead of starting a new one.
+func (c *JobClient) RunJobWithReuse(ctx context.Context, job *pb.RunJobRequest) (*pb.RunJobResponse, JobReuse, error) {
+	resp, details, err := c.RunJobWithDetails(ctx, job)
+	return resp, details.Reuse, err
+}
+
+// RunJobDetails is what the server reports about a RunJob request besides
+// the response
+type RunJobDetails struct {
+	Reuse JobReuse
+	// Uploaded dependency file a runtime was detected for, when the job
+	// named none, and that runtime; RuntimeSelected says the job runs with
+	// it, otherwise the server only suggested it
+	DependencyFile  string
+	DetectedRuntime string
+	RuntimeSelected bool
+	// Why a scheduled job may start early or late: its scheduled time is
+	// within the uncertainty of the server's clock
+	ClockWarning string
+}
+
+// RunJobWithDetails runs a job like RunJob and also returns the details the
+// server reported with the response
+func (c *JobClient) RunJobWithDetails(ctx context.Context, job *pb.RunJobRequest) (*pb.RunJobResponse, RunJobDetails, error) {
+	var header metadata.MD
+	resp, err := c.jobClient.RunJob(withClientTime(ctx, job), job, grpc.Header(&header))
+	if err != nil {
+		return nil, RunJobDetails{}, err
+	}
+	isSet := func(key string) bool {
+		values := header.Get(key)
+		return len(values) > 0 && values[0] == "true"
+	}
+
+	var details RunJobDetails
+	switch {
+	case isSet(constants.CachedHeader):
+		details.Reuse = CachedJob
+	case isSet(constants.DeduplicatedHeader):
+		details.Reuse = DeduplicatedJob
+	}
+	for key, selected := range map[string]bool{constants.RuntimeSuggestedHeader: false, constants.RuntimeSelectedHeader: true} {
+		if values := header.Get(key); len(values) > 0 {
+			if file, runtime, ok := strings.Cut(values[0], "="); ok {
+				details.DependencyFile, details.DetectedRuntime, details.RuntimeSelected = file, runtime, selected
+			}
+		}
+	}
+	if values := header.Get(constants.ClockWarningHeader); len(values) > 0 {
+		details.ClockWarning = values[0]
+	}
+	return resp, details, nil
+}
+
+// RunJobArray runs a job array submission, a request carrying
+// JOBLET_ARRAY, and returns the response holding the array UUID and the
+// number of jobs the server started
+func (c *JobClient) RunJobArray(ctx context.Context, job *pb.RunJobRequest) (*pb.RunJobResponse, int, error) {
+	var header metadata.MD
+	resp, err := c.jobClient.RunJob(ctx, job, grpc.Header(&header))
+	if err != nil {
+		return nil, 0, err
+	}
+	values := header.Get(constants.ArraySizeHeader)
+	if len(values) == 0 {
+		return nil, 0, fmt.Errorf("server does not support job arrays, it started a single job %s", resp.JobUuid)
+	}
+	size, err := strconv.Atoi(values[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid %s header %q", constants.ArraySizeHeader, values[0])
+	}
+	return resp, size, nil
 }
 
 func (c *JobClient) GetJobStatus(ctx context.Context, id string) (*pb.GetJobStatusRes, error) {
 	return c.jobClient.GetJobStatus(ctx, &pb.GetJobStatusReq{Uuid: id})
 }
 
+// JobStatusDetails is job status information the server returns as
+// response headers.
+type JobStatusDetails struct {
+	Redactions      int64  // Secret matches masked in the job's output
+	OutputDropped   int64  // Output bytes dropped over the server's output limits
+	OutputTruncated bool   // The job reached the server's output size cap
+	SignatureStatus string // "valid", "invalid" or "untrusted"; empty for unsigned jobs
+	Signer          string // Fingerprint of the signing key
+	Events          []JobEvent
+	Fingerprint     *JobFingerprint    // Environment the job last started in, nil before it started
+	Anomaly         *DurationAnomaly   // Set when the run took far longer than usual
+	Network         *JobNetworkSummary // Network activity of the ended job's namespace
+	Annotations     map[string]string  // Notes such as the CI pipeline that submitted the job
+}
+
+// JobNetworkSummary is the network activity of a job's network namespace
+// over its run
+type JobNetworkSummary struct {
+	RxBytes         uint64 `json:"rxBytes"`
+	TxBytes         uint64 `json:"txBytes"`
+	RxPackets       uint64 `json:"rxPackets"`
+	TxPackets       uint64 `json:"txPackets"`
+	PeakConnections uint64 `json:"peakConnections"` // Most established TCP connections in a sample
+	Retransmits     uint64 `json:"retransmits"`     // TCP segments retransmitted
+}
+
+// JobEvent is an entry in a job's event timeline: a launch attempt, an
+// infrastructure failure or a retry.
+type JobEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // STARTED, INFRA_FAILURE or RETRYING
+	Message string    `json:"message"`
+}
+
+// JobFingerprint describes the environment a job ran in. Two runs with the
+// same Digest ran on the same runtime build, chroot mounts, kernel and
+// joblet version.
+type JobFingerprint struct {
+	Digest          string `json:"digest"`
+	Runtime         string `json:"runtime,omitempty"`
+	RuntimeVersion  string `json:"runtimeVersion,omitempty"`
+	RuntimeChecksum string `json:"runtimeChecksum,omitempty"`
+	MountsHash      string `json:"mountsHash"`
+	KernelVersion   string `json:"kernelVersion"`
+	JobletVersion   string `json:"jobletVersion"`
+}
+
+// DurationAnomaly is a run flagged for taking far longer than the usual
+// runs of its job name.
+type DurationAnomaly struct {
+	JobID        string    `json:"jobId"`
+	Name         string    `json:"name"`
+	WorkflowUuid string    `json:"workflowUuid,omitempty"`
+	Status       string    `json:"status"` // Status when the run was flagged
+	Elapsed      float64   `json:"elapsedSeconds"`
+	Mean         float64   `json:"meanSeconds"`
+	StdDev       float64   `json:"stddevSeconds"`
+	Sigmas       float64   `json:"sigmas"`  // Standard deviations above the mean
+	Samples      int       `json:"samples"` // Completed runs the mean was taken over
+	DetectedAt   time.Time `json:"detectedAt"`
+	NodeID       string    `json:"nodeId,omitempty"`
+}
+
+// GetJobStatusWithDetails is GetJobStatus that also returns the details the
+// server sends as response headers.
+func (c *JobClient) GetJobStatusWithDetails(ctx context.Context, id string) (*pb.GetJobStatusRes, JobStatusDetails, error) {
+	var header metadata.MD
+	resp, err := c.jobClient.GetJobStatus(ctx, &pb.GetJobStatusReq{Uuid: id}, grpc.Header(&header))
+	if err != nil {
+		return nil, JobStatusDetails{}, err
+	}
+	value := func(key string) string {
+		if values := header.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	details := JobStatusDetails{
+		SignatureStatus: value(constants.SignatureStatusHeader),
+		Signer:          value(constants.SignerHeader),
+	}
+	details.Redactions, _ = strconv.ParseInt(value(constants.RedactionsHeader), 10, 64)
+	details.OutputDropped, _ = strconv.ParseInt(value(constants.OutputDroppedHeader), 10, 64)
+	details.OutputTruncated = value(constants.OutputTruncatedHeader) == "true"
+	if events := value(constants.EventsHeader); events != "" {
+		_ = json.Unmarshal([]byte(events), &details.Events)
+	}
+	if fingerprint := value(constants.FingerprintHeader); fingerprint != "" {
+		details.Fingerprint = &JobFingerprint{}
+		if err := json.Unmarshal([]byte(fingerprint), details.Fingerprint); err != nil {
+			details.Fingerprint = nil
+		}
+	}
+	if anomaly := value(constants.DurationAnomalyHeader); anomaly != "" {
+		details.Anomaly = &DurationAnomaly{}
+		if err := json.Unmarshal([]byte(anomaly), details.Anomaly); err != nil {
+			details.Anomaly = nil
+		}
+	}
+	if network := value(constants.NetworkSummaryHeader); network != "" {
+		details.Network = &JobNetworkSummary{}
+		if err := json.Unmarshal([]byte(network), details.Network); err != nil {
+			details.Network = nil
+		}
+	}
+	if annotations := value(constants.AnnotationsHeader); annotations != "" {
+		_ = json.Unmarshal([]byte(annotations), &details.Annotations)
+	}
+	return resp, details, nil
+}
+
 func (c *JobClient) StopJob(ctx context.Context, id string) (*pb.StopJobRes, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -91,6 +428,65 @@ func (c *JobClient) StopJob(ctx context.Context, id string) (*pb.StopJobRes, err
 	return resp, nil
 }
 
+// StopJobGroup stops every running or scheduled job of a group in one call
+func (c *JobClient) StopJobGroup(ctx context.Context, name string) (*jobspb.StopJobsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	resp, err := c.bulkClient.StopJobGroup(ctx, &jobspb.StopJobGroupRequest{Name: name})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			if s.Code() == codes.DeadlineExceeded {
+				return nil, fmt.Errorf("timeout while stopping job group %s: server may still be processing the request", name)
+			}
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// StopAllJobs stops the running and scheduled jobs matching the request's
+// filters, or lists them when req.DryRun is set
+func (c *JobClient) StopAllJobs(ctx context.Context, req *jobspb.StopAllJobsRequest) (*jobspb.StopJobsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	resp, err := c.bulkClient.StopAllJobs(ctx, req)
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			if s.Code() == codes.DeadlineExceeded {
+				return nil, fmt.Errorf("timeout while stopping jobs: server may still be processing the request")
+			}
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListJobGroups lists job groups with their jobs; a non-empty name selects
+// that group only
+func (c *JobClient) ListJobGroups(ctx context.Context, name string) (*jobspb.ListJobGroupsResponse, error) {
+	return c.bulkClient.ListJobGroups(ctx, &jobspb.ListJobGroupsRequest{Name: name})
+}
+
+// GetJobArray returns the jobs of a job array, named by its UUID or a
+// unique prefix of it
+func (c *JobClient) GetJobArray(ctx context.Context, uuid string) (*jobspb.JobArray, error) {
+	return c.bulkClient.GetJobArray(ctx, &jobspb.GetJobArrayRequest{Uuid: uuid})
+}
+
+// GetRetentionPolicy returns the server's finished job retention policy and
+// the outcome of its sweeps
+func (c *JobClient) GetRetentionPolicy(ctx context.Context) (*jobspb.RetentionPolicy, error) {
+	return c.bulkClient.GetRetentionPolicy(ctx, &jobspb.GetRetentionPolicyRequest{})
+}
+
+// GetJobDurationHistory returns how long recent completed runs of the named
+// jobs took; samples 0 uses the server's default
+func (c *JobClient) GetJobDurationHistory(ctx context.Context, names []string, samples int32) (*jobspb.JobDurationHistory, error) {
+	return c.bulkClient.GetJobDurationHistory(ctx, &jobspb.JobDurationHistoryRequest{Names: names, Samples: samples})
+}
+
 func (c *JobClient) DeleteJob(ctx context.Context, id string) (*pb.DeleteJobRes, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -135,6 +531,32 @@ func (c *JobClient) GetJobLogs(ctx context.Context, id string) (pb.JobService_Ge
 	return stream, nil
 }
 
+// GetJobLogsFrom streams a job's logs like GetJobLogs, skipping the first
+// offset non-empty chunks a previous stream already delivered. Servers that
+// support resuming advertise their heartbeat in the stream header.
+func (c *JobClient) GetJobLogsFrom(ctx context.Context, id string, offset int64) (pb.JobService_GetJobLogsClient, error) {
+	if offset > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, constants.LogOffsetHeader, strconv.FormatInt(offset, 10))
+	}
+	return c.GetJobLogs(ctx, id)
+}
+
+// DownloadJobLogs streams a finished job's persisted log, skipping the first
+// offset records.
+func (c *JobClient) DownloadJobLogs(ctx context.Context, id string, offset int64) (logspb.LogService_DownloadJobLogsClient, error) {
+	return c.logsClient.DownloadJobLogs(ctx, &logspb.DownloadJobLogsRequest{JobUuid: id, Offset: offset})
+}
+
+// ReadLogRange reads a window of a job's persisted log records
+func (c *JobClient) ReadLogRange(ctx context.Context, req *logspb.ReadLogRangeRequest) (*logspb.LogRange, error) {
+	return c.logsClient.ReadLogRange(ctx, req)
+}
+
+// LocateLogRecord searches a job's persisted log on the node
+func (c *JobClient) LocateLogRecord(ctx context.Context, req *logspb.LocateLogRecordRequest) (*logspb.LogLocation, error) {
+	return c.logsClient.LocateLogRecord(ctx, req)
+}
+
 func (c *JobClient) GetJobMetrics(ctx context.Context, id string) (pb.JobService_GetJobMetricsClient, error) {
 	stream, err := c.jobClient.GetJobMetrics(ctx, &pb.JobMetricsRequest{Uuid: id})
 	if err != nil {
@@ -173,12 +595,276 @@ func (c *JobClient) GetSystemStatus(ctx context.Context) (*pb.SystemStatusRes, e
 	return c.monitoringClient.GetSystemStatus(ctx, &pb.EmptyRequest{})
 }
 
+// ClockStatus is the latest check of the server's clock, against an NTP
+// server or, without one, the clock of a client that scheduled a job
+type ClockStatus struct {
+	Source      string        `json:"source"`              // "ntp" or "client"
+	Reference   string        `json:"reference,omitempty"` // NTP server that answered
+	Offset      time.Duration `json:"offset"`              // Server clock minus the reference; positive when the server is ahead
+	Uncertainty time.Duration `json:"uncertainty"`
+	MaxDrift    time.Duration `json:"maxDrift"`
+	Drifted     bool          `json:"drifted"`
+	CheckedAt   time.Time     `json:"checkedAt"`
+	Error       string        `json:"error,omitempty"` // Why the last NTP check failed
+}
+
+// RateLimitStats counts the calls the server's rate limiter allowed and
+// throttled since it started
+type RateLimitStats struct {
+	Allowed           int64            `json:"allowed"`
+	Throttled         int64            `json:"throttled"`
+	ThrottledByClient map[string]int64 `json:"throttledByClient,omitempty"` // Recently active clients only
+}
+
+// StatusReport is what the server reports next to its system status; each
+// part is nil when the server reports none
+type StatusReport struct {
+	Clock     *ClockStatus
+	RateLimit *RateLimitStats
+}
+
+// GetSystemStatusReport gets the system status like GetSystemStatus, with
+// the latest check of the server's clock and its rate limiting counters
+func (c *JobClient) GetSystemStatusReport(ctx context.Context) (*pb.SystemStatusRes, *StatusReport, error) {
+	var header metadata.MD
+	resp, err := c.monitoringClient.GetSystemStatus(ctx, &pb.EmptyRequest{}, grpc.Header(&header))
+	if err != nil {
+		return nil, nil, err
+	}
+	report := &StatusReport{}
+	if values := header.Get(constants.ClockStatusHeader); len(values) > 0 {
+		clock := &ClockStatus{}
+		if err := json.Unmarshal([]byte(values[0]), clock); err == nil {
+			report.Clock = clock
+		}
+	}
+	if values := header.Get(constants.RateLimitStatusHeader); len(values) > 0 {
+		stats := &RateLimitStats{}
+		if err := json.Unmarshal([]byte(values[0]), stats); err == nil {
+			report.RateLimit = stats
+		}
+	}
+	return resp, report, nil
+}
+
 func (c *JobClient) StreamSystemMetrics(ctx context.Context, req *pb.StreamMetricsReq) (pb.MonitoringService_StreamSystemMetricsClient, error) {
 	return c.monitoringClient.StreamSystemMetrics(ctx, req)
 }
 
 // Runtime service methods
 
+// GetNodeCapacity reports the total, reserved and allocated resources of the node.
+func (c *JobClient) GetNodeCapacity(ctx context.Context) (*capacitypb.NodeCapacity, error) {
+	return c.capacityClient.GetNodeCapacity(ctx, &capacitypb.GetNodeCapacityRequest{})
+}
+
+// QueryNodeMetrics reads the host metrics the node recorded between start
+// and end, oldest first. A zero start or end leaves that side open.
+func (c *JobClient) QueryNodeMetrics(ctx context.Context, start, end time.Time) ([]*nodemetricspb.NodeMetricsSample, error) {
+	req := &nodemetricspb.QueryNodeMetricsRequest{}
+	if !start.IsZero() {
+		req.StartTime = start.UnixNano()
+	}
+	if !end.IsZero() {
+		req.EndTime = end.UnixNano()
+	}
+	stream, err := c.metricsClient.QueryNodeMetrics(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var samples []*nodemetricspb.NodeMetricsSample
+	for {
+		sample, err := stream.Recv()
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+}
+
+// GetOrphanedResources returns the counts of the cgroups and network
+// resources of gone jobs that the node's periodic cleanup found
+func (c *JobClient) GetOrphanedResources(ctx context.Context) (*nodemetricspb.OrphanedResources, error) {
+	return c.metricsClient.GetOrphanedResources(ctx, &nodemetricspb.GetOrphanedResourcesRequest{})
+}
+
+// ListNodes lists the live nodes registered with the server's coordination
+// endpoint that carry all of the given labels.
+func (c *JobClient) ListNodes(ctx context.Context, labels map[string]string) (*nodespb.ListNodesResponse, error) {
+	return c.nodesClient.ListNodes(ctx, &nodespb.ListNodesRequest{Labels: labels})
+}
+
+// GetFileSignature returns the block checksums of the server's cached copy
+// of a workflow file, for syncing it as a delta
+func (c *JobClient) GetFileSignature(ctx context.Context, path string) (*uploadspb.FileSignature, error) {
+	return c.uploadsClient.GetFileSignature(ctx, &uploadspb.FileSignatureRequest{Path: path})
+}
+
+// SyncFile sends a workflow file as a delta against the server's cached copy
+func (c *JobClient) SyncFile(ctx context.Context, delta *uploadspb.FileDelta) (*uploadspb.SyncFileResponse, error) {
+	return c.uploadsClient.SyncFile(ctx, delta)
+}
+
+// DownloadArtifact streams one window of a file in a volume. The window's
+// SHA-256 is in the stream trailer once Recv returns io.EOF.
+func (c *JobClient) DownloadArtifact(ctx context.Context, req *artifactspb.DownloadArtifactRequest) (artifactspb.ArtifactService_DownloadArtifactClient, error) {
+	return c.artifactsClient.DownloadArtifact(ctx, req)
+}
+
+// FreezeJobFilesystem arms a job so the server keeps its filesystem if it fails
+func (c *JobClient) FreezeJobFilesystem(ctx context.Context, jobID string) (*jobfspb.FreezeJobFilesystemResponse, error) {
+	return c.jobfsClient.FreezeJobFilesystem(ctx, &jobfspb.FreezeJobFilesystemRequest{JobUuid: jobID})
+}
+
+// ListJobFiles lists a directory of a failed job's frozen filesystem
+func (c *JobClient) ListJobFiles(ctx context.Context, jobID, path string) (*jobfspb.ListJobFilesResponse, error) {
+	return c.jobfsClient.ListJobFiles(ctx, &jobfspb.ListJobFilesRequest{JobUuid: jobID, Path: path})
+}
+
+// ReadJobFile streams a file of a failed job's frozen filesystem
+func (c *JobClient) ReadJobFile(ctx context.Context, req *jobfspb.ReadJobFileRequest) (jobfspb.JobFilesystemService_ReadJobFileClient, error) {
+	return c.jobfsClient.ReadJobFile(ctx, req)
+}
+
+// StreamJobEvents streams a job's status and the progress it reports until
+// it ends
+func (c *JobClient) StreamJobEvents(ctx context.Context, jobID string) (eventspb.JobEventService_StreamJobEventsClient, error) {
+	return c.eventsClient.StreamJobEvents(ctx, &eventspb.StreamJobEventsRequest{JobUuid: jobID})
+}
+
+// ExportJobEnvironment has the server render the environment of a job as a
+// Dockerfile or an OCI bundle (format "dockerfile" or "oci")
+func (c *JobClient) ExportJobEnvironment(ctx context.Context, jobID, format string) (*jobenvpb.ExportJobEnvironmentResponse, error) {
+	return c.jobenvClient.ExportJobEnvironment(ctx, &jobenvpb.ExportJobEnvironmentRequest{JobUuid: jobID, Format: format})
+}
+
+// StreamJobNetworkMetrics streams the network counters of a job's network
+// namespace until it ends
+func (c *JobClient) StreamJobNetworkMetrics(ctx context.Context, jobID string) (jobnetpb.JobNetworkService_StreamJobNetworkMetricsClient, error) {
+	return c.jobnetClient.StreamJobNetworkMetrics(ctx, &jobnetpb.StreamJobNetworkMetricsRequest{JobUuid: jobID})
+}
+
+// RunFromGit has the server fetch a ref of a repository and run the workflow
+// or job spec at path
+func (c *JobClient) RunFromGit(ctx context.Context, repository, ref, path string) (*gitsourcepb.RunFromGitResponse, error) {
+	return c.gitsourceClient.RunFromGit(ctx, &gitsourcepb.RunFromGitRequest{Repository: repository, Ref: ref, Path: path})
+}
+
+// GetWorkflowReport has the server render the report of a workflow run in
+// a format ("html", "json" or "junit"), quoting logLines log lines of each
+// failed job (0 = server default)
+func (c *JobClient) GetWorkflowReport(ctx context.Context, workflowUUID, format string, logLines int32) (*reportspb.WorkflowReport, error) {
+	return c.reportsClient.GetWorkflowReport(ctx, &reportspb.WorkflowReportRequest{WorkflowUuid: workflowUUID, Format: format, LogLines: logLines})
+}
+
+// LintSpec has the server lint a job spec or workflow with its runtime
+// aliases, networks and default limits
+func (c *JobClient) LintSpec(ctx context.Context, spec []byte) (*lintpb.LintSpecResponse, error) {
+	return c.lintClient.LintSpec(ctx, &lintpb.LintSpecRequest{Spec: spec})
+}
+
+// DescribeRuntime returns the mounts, environment, tools and disk footprint
+// of the runtime a name resolves to
+func (c *JobClient) DescribeRuntime(ctx context.Context, runtime string) (*runtimespb.RuntimeDetails, error) {
+	return c.runtimesClient.GetRuntimeInfo(ctx, &runtimespb.GetRuntimeInfoRequest{Runtime: runtime})
+}
+
+// ListRuntimeChannels returns the stable and canary channels of the runtime
+// names with pins, comparing each canary against stable
+func (c *JobClient) ListRuntimeChannels(ctx context.Context) (*runtimespb.ListRuntimeChannelsResponse, error) {
+	return c.channelsClient.ListRuntimeChannels(ctx, &runtimespb.ListRuntimeChannelsRequest{})
+}
+
+// SetRuntimeCanary pins an installed runtime to the canary channel of a name
+func (c *JobClient) SetRuntimeCanary(ctx context.Context, name, runtime string) (*runtimespb.RuntimeChannel, error) {
+	return c.channelsClient.SetRuntimeCanary(ctx, &runtimespb.SetRuntimeCanaryRequest{Name: name, Runtime: runtime})
+}
+
+// ClearRuntimeCanary removes the canary of a name
+func (c *JobClient) ClearRuntimeCanary(ctx context.Context, name string) (*runtimespb.RuntimeChannel, error) {
+	return c.channelsClient.ClearRuntimeCanary(ctx, &runtimespb.RuntimeChannelRequest{Name: name})
+}
+
+// PromoteRuntimeCanary makes the canary of a name its stable runtime; force
+// promotes a canary failing more than stable allows
+func (c *JobClient) PromoteRuntimeCanary(ctx context.Context, name string, force bool) (*runtimespb.RuntimeChannel, error) {
+	return c.channelsClient.PromoteRuntimeCanary(ctx, &runtimespb.PromoteRuntimeCanaryRequest{Name: name, Force: force})
+}
+
+// CancelWorkflowJobs cancels a job of a running workflow and, with cascade,
+// every job downstream of it, leaving the other branches running
+func (c *JobClient) CancelWorkflowJobs(ctx context.Context, workflowUUID, job string, cascade bool) (*workflowspb.CancelWorkflowJobsResponse, error) {
+	return c.workflowsClient.CancelWorkflowJobs(ctx, &workflowspb.CancelWorkflowJobsRequest{WorkflowUuid: workflowUUID, Job: job, Cascade: cascade})
+}
+
+// CancelWorkflow cancels a whole workflow: its running jobs are stopped, the
+// ones that have not started are canceled and no new job starts
+func (c *JobClient) CancelWorkflow(ctx context.Context, workflowUUID string) (*workflowspb.CancelWorkflowJobsResponse, error) {
+	return c.workflowsClient.CancelWorkflow(ctx, &workflowspb.CancelWorkflowRequest{WorkflowUuid: workflowUUID})
+}
+
+// ValidateWorkflow runs the server's checks on a workflow without running
+// it, streaming the result of each check. files are the workflow files the
+// client would send, relative to the YAML.
+func (c *JobClient) ValidateWorkflow(ctx context.Context, yamlContent string, files []string) (workflowspb.WorkflowControlService_ValidateWorkflowClient, error) {
+	return c.workflowsClient.ValidateWorkflow(ctx, &workflowspb.ValidateWorkflowRequest{YamlContent: yamlContent, Files: files})
+}
+
+// ResolveId lists up to limit jobs and workflows, as idType selects, whose
+// UUID starts with prefix, and counts every match
+func (c *JobClient) ResolveId(ctx context.Context, prefix string, idType idspb.IdType, limit int32) (*idspb.ResolveIdResponse, error) {
+	return c.idsClient.ResolveId(ctx, &idspb.ResolveIdRequest{Prefix: prefix, Type: idType, Limit: limit})
+}
+
+// ExportJobs streams the node's job records, oldest first
+func (c *JobClient) ExportJobs(ctx context.Context, req *backuppb.ExportJobsRequest) (backuppb.BackupService_ExportJobsClient, error) {
+	return c.backupClient.ExportJobs(ctx, req)
+}
+
+// ExportWorkflows streams the node's workflow records, oldest first
+func (c *JobClient) ExportWorkflows(ctx context.Context, req *backuppb.ExportWorkflowsRequest) (backuppb.BackupService_ExportWorkflowsClient, error) {
+	return c.backupClient.ExportWorkflows(ctx, req)
+}
+
+// ImportJobs loads exported job records into the node
+func (c *JobClient) ImportJobs(ctx context.Context, jobs []*backuppb.ExportedJob) (*backuppb.ImportResult, error) {
+	return c.backupClient.ImportJobs(ctx, &backuppb.ImportJobsRequest{Jobs: jobs})
+}
+
+// ImportWorkflows loads exported workflow records into the node, after
+// ImportJobs loaded their jobs
+func (c *JobClient) ImportWorkflows(ctx context.Context, workflows []*backuppb.ExportedWorkflow) (*backuppb.ImportResult, error) {
+	return c.backupClient.ImportWorkflows(ctx, &backuppb.ImportWorkflowsRequest{Workflows: workflows})
+}
+
+// RegisterWorkflow stores a new version of a workflow on the server.
+func (c *JobClient) RegisterWorkflow(ctx context.Context, req *registrypb.RegisterWorkflowRequest) (*registrypb.RegisteredWorkflow, error) {
+	return c.registryClient.RegisterWorkflow(ctx, req)
+}
+
+// ListRegisteredWorkflows lists the workflows registered on the server.
+func (c *JobClient) ListRegisteredWorkflows(ctx context.Context) (*registrypb.ListRegisteredWorkflowsResponse, error) {
+	return c.registryClient.ListRegisteredWorkflows(ctx, &registrypb.ListRegisteredWorkflowsRequest{})
+}
+
+// GetRegisteredWorkflow returns a version of a registered workflow (0 = latest).
+func (c *JobClient) GetRegisteredWorkflow(ctx context.Context, name string, version int32) (*registrypb.RegisteredWorkflowVersion, error) {
+	return c.registryClient.GetRegisteredWorkflow(ctx, &registrypb.GetRegisteredWorkflowRequest{Name: name, Version: version})
+}
+
+// RemoveRegisteredWorkflow removes a registered workflow with all its versions.
+func (c *JobClient) RemoveRegisteredWorkflow(ctx context.Context, name string) (*registrypb.RemoveRegisteredWorkflowResponse, error) {
+	return c.registryClient.RemoveRegisteredWorkflow(ctx, &registrypb.RemoveRegisteredWorkflowRequest{Name: name})
+}
+
+// StartRegisteredWorkflow starts a run of a registered workflow (version 0 = latest).
+func (c *JobClient) StartRegisteredWorkflow(ctx context.Context, name string, version int32) (*registrypb.StartRegisteredWorkflowResponse, error) {
+	return c.registryClient.StartRegisteredWorkflow(ctx, &registrypb.StartRegisteredWorkflowRequest{Name: name, Version: version})
+}
+
 func (c *JobClient) ListRuntimes(ctx context.Context) (*pb.RuntimesRes, error) {
 	return c.runtimeClient.ListRuntimes(ctx, &pb.EmptyRequest{})
 }
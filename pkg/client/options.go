@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Options tunes how a JobClient keeps its connection alive and recovers from
+// transient failures.
+type Options struct {
+	// Timeout bounds each unary call that has no deadline of its own,
+	// including retries. Zero leaves calls unbounded. Streams are never
+	// bounded by it since log following can run indefinitely.
+	Timeout time.Duration
+
+	// KeepAliveTime is how often an idle connection is pinged and
+	// KeepAliveTimeout how long a ping may go unanswered before the
+	// connection is considered dead. The server rejects pings more frequent
+	// than half its own keepalive time.
+	KeepAliveTime    time.Duration
+	KeepAliveTimeout time.Duration
+
+	// MaxRetries is how many times an idempotent call is retried after a
+	// transient failure. Backoff between attempts grows exponentially from
+	// InitialBackoff up to MaxBackoff with full jitter.
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultOptions returns the options used by NewJobClient.
+func DefaultOptions() Options {
+	return Options{
+		KeepAliveTime:    30 * time.Second,
+		KeepAliveTimeout: 10 * time.Second,
+		MaxRetries:       4,
+		InitialBackoff:   200 * time.Millisecond,
+		MaxBackoff:       5 * time.Second,
+	}
+}
+
+// idempotentMethods are the unary calls that can safely be sent again after a
+// failure whose outcome is unknown. Calls that create or change state, such as
+// RunJob or DeleteJob, are never retried.
+var idempotentMethods = map[string]bool{
+	pb.JobService_GetJobStatus_FullMethodName:            true,
+	pb.JobService_ListJobs_FullMethodName:                true,
+	pb.JobService_GetWorkflowStatus_FullMethodName:       true,
+	pb.JobService_ListWorkflows_FullMethodName:           true,
+	pb.JobService_GetWorkflowJobs_FullMethodName:         true,
+	pb.NetworkService_ListNetworks_FullMethodName:        true,
+	pb.VolumeService_ListVolumes_FullMethodName:          true,
+	pb.MonitoringService_GetSystemStatus_FullMethodName:  true,
+	pb.RuntimeService_ListRuntimes_FullMethodName:        true,
+	pb.RuntimeService_GetRuntimeInfo_FullMethodName:      true,
+	pb.RuntimeService_ValidateRuntimeSpec_FullMethodName: true,
+}
+
+// isTransient reports whether a failed call may succeed if sent again:
+// the server was unreachable, or it throttled the client.
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before retry attempt n (starting at 0), chosen
+// uniformly between zero and the exponential cap so that many clients
+// recovering from the same outage do not retry in lockstep.
+func (o Options) backoff(n int) time.Duration {
+	ceiling := o.InitialBackoff << n
+	if ceiling <= 0 || ceiling > o.MaxBackoff {
+		ceiling = o.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// unaryInterceptor applies the default timeout and retries idempotent calls
+// that failed transiently.
+func (o Options) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, hasDeadline := ctx.Deadline(); !hasDeadline && o.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+			defer cancel()
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !idempotentMethods[method] {
+			return err
+		}
+
+		for attempt := 0; attempt < o.MaxRetries && isTransient(err); attempt++ {
+			timer := time.NewTimer(o.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			err = invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return err
+	}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingInvoker fails with code for the first failures calls and then succeeds.
+func failingInvoker(code codes.Code, failures int, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "transient")
+		}
+		return nil
+	}
+}
+
+func fastOptions() Options {
+	opts := DefaultOptions()
+	opts.InitialBackoff = time.Millisecond
+	opts.MaxBackoff = 2 * time.Millisecond
+	return opts
+}
+
+func TestUnaryInterceptor_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		code      codes.Code
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"idempotent call recovers", pb.JobService_GetJobStatus_FullMethodName, codes.Unavailable, 2, 3, false},
+		{"throttled call recovers", pb.JobService_ListJobs_FullMethodName, codes.ResourceExhausted, 1, 2, false},
+		{"retries are bounded", pb.JobService_ListJobs_FullMethodName, codes.Unavailable, 10, 5, true},
+		{"permanent errors are not retried", pb.JobService_GetJobStatus_FullMethodName, codes.NotFound, 1, 1, true},
+		{"mutating calls are not retried", pb.JobService_RunJob_FullMethodName, codes.Unavailable, 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			interceptor := fastOptions().unaryInterceptor()
+			err := interceptor(context.Background(), tt.method, nil, nil, nil, failingInvoker(tt.code, tt.failures, &calls))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("invoker called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestUnaryInterceptor_Timeout(t *testing.T) {
+	opts := fastOptions()
+	opts.Timeout = 50 * time.Millisecond
+	interceptor := opts.unaryInterceptor()
+
+	var deadline time.Time
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, callOpts ...grpc.CallOption) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	}
+
+	start := time.Now()
+	if err := interceptor(context.Background(), pb.JobService_ListJobs_FullMethodName, nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if deadline.IsZero() || deadline.Sub(start) > opts.Timeout+10*time.Millisecond {
+		t.Errorf("deadline = %v, want about %v after start", deadline, opts.Timeout)
+	}
+
+	// A caller's own deadline wins
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	if err := interceptor(ctx, pb.JobService_ListJobs_FullMethodName, nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if !deadline.Equal(want) {
+		t.Errorf("deadline = %v, want caller deadline %v", deadline, want)
+	}
+}
+
+func TestUnaryInterceptor_StopsRetryingWhenContextEnds(t *testing.T) {
+	opts := DefaultOptions()
+	opts.InitialBackoff = time.Hour
+	opts.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	err := opts.unaryInterceptor()(ctx, pb.JobService_ListJobs_FullMethodName, nil, nil, nil, failingInvoker(codes.Unavailable, 10, &calls))
+	if status.Code(err) != codes.Unavailable || calls != 1 {
+		t.Errorf("got %v after %d calls, want the first Unavailable error without waiting out the backoff", err, calls)
+	}
+}
+
+func TestOptions_Backoff(t *testing.T) {
+	opts := DefaultOptions()
+	for attempt := 0; attempt < 10; attempt++ {
+		ceiling := opts.InitialBackoff << attempt
+		if ceiling > opts.MaxBackoff {
+			ceiling = opts.MaxBackoff
+		}
+		for i := 0; i < 100; i++ {
+			if d := opts.backoff(attempt); d <= 0 || d > ceiling {
+				t.Fatalf("backoff(%d) = %v, want within (0, %v]", attempt, d, ceiling)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ehsaniara/joblet/pkg/config"
+)
+
+// Pool shares one connection per node address between callers in the same
+// process, so commands that talk to the server several times reuse a single
+// TLS session instead of dialing for each call.
+type Pool struct {
+	opts Options
+
+	mu      sync.Mutex
+	clients map[string]*JobClient
+}
+
+// NewPool creates an empty pool whose connections use opts.
+func NewPool(opts Options) *Pool {
+	return &Pool{
+		opts:    opts,
+		clients: make(map[string]*JobClient),
+	}
+}
+
+// Get returns the shared client for node, connecting on first use. Calling
+// Close on the returned client does nothing; the pool closes it.
+func (p *Pool) Get(node *config.Node) (*JobClient, error) {
+	if node == nil {
+		return nil, errors.New("node configuration cannot be nil")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, exists := p.clients[node.Address]; exists {
+		return c, nil
+	}
+
+	c, err := NewJobClientWithOptions(node, p.opts)
+	if err != nil {
+		return nil, err
+	}
+	c.shared = true
+	p.clients[node.Address] = c
+	return c, nil
+}
+
+// Close closes every pooled connection.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for address, c := range p.clients {
+		if err := c.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.clients, address)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	registrypb "github.com/ehsaniara/joblet/internal/proto/gen/registry"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// signingInterceptor signs RunJob, RunWorkflow and RegisterWorkflow requests
+// with the node's signing key. Other calls pass through unchanged.
+func signingInterceptor(key ed25519.PrivateKey) grpc.UnaryClientInterceptor {
+	publicKey := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		signedAt := time.Now()
+		var digest []byte
+		switch r := req.(type) {
+		case *pb.RunJobRequest:
+			digest = jobsign.JobDigest(r.Command, r.Args, r.Runtime, signedAt)
+		case *pb.RunWorkflowRequest:
+			digest = jobsign.WorkflowDigest(r.YamlContent, signedAt)
+		case *registrypb.RegisterWorkflowRequest:
+			digest = jobsign.WorkflowDigest(r.YamlContent, signedAt)
+		}
+		if digest != nil {
+			ctx = metadata.AppendToOutgoingContext(ctx,
+				constants.SignatureHeader, base64.StdEncoding.EncodeToString(jobsign.Sign(key, digest)),
+				constants.SignatureKeyHeader, publicKey,
+				constants.SignedAtHeader, strconv.FormatInt(signedAt.Unix(), 10),
+			)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	pb "github.com/ehsaniara/joblet-proto/v2/gen"
+	"github.com/ehsaniara/joblet/pkg/constants"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestSigningInterceptor(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	interceptor := signingInterceptor(privateKey)
+
+	var sent metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	req := &pb.RunJobRequest{Command: "echo", Args: []string{"hello"}, Runtime: "python-3.11"}
+	if err := interceptor(context.Background(), "/joblet.JobService/RunJob", req, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor error = %v", err)
+	}
+
+	signature, _ := base64.StdEncoding.DecodeString(sent.Get(constants.SignatureHeader)[0])
+	key, _ := base64.StdEncoding.DecodeString(sent.Get(constants.SignatureKeyHeader)[0])
+	signedAt, _ := strconv.ParseInt(sent.Get(constants.SignedAtHeader)[0], 10, 64)
+	if !publicKey.Equal(ed25519.PublicKey(key)) {
+		t.Error("interceptor sent a different public key")
+	}
+	digest := jobsign.JobDigest(req.Command, req.Args, req.Runtime, time.Unix(signedAt, 0))
+	if !jobsign.Verify(key, digest, signature) {
+		t.Error("interceptor signature does not verify")
+	}
+
+	// Other calls are not signed
+	sent = nil
+	if err := interceptor(context.Background(), "/joblet.JobService/ListJobs", &pb.EmptyRequest{}, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor error = %v", err)
+	}
+	if len(sent.Get(constants.SignatureHeader)) != 0 {
+		t.Error("interceptor signed a non-submission call")
+	}
+}
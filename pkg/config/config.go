@@ -1,13 +1,24 @@
 package config
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/ehsaniara/joblet/internal/joblet/cron"
+	"github.com/ehsaniara/joblet/internal/joblet/sdbus"
+	"github.com/ehsaniara/joblet/pkg/jobsign"
+	"github.com/ehsaniara/joblet/pkg/keychain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,6 +40,36 @@ type Config struct {
 	GPU        GPUConfig        `yaml:"gpu" json:"gpu"`
 	IPC        IPCConfig        `yaml:"ipc" json:"ipc"`
 	State      StateConfig      `yaml:"state" json:"state"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit" json:"rate_limit"`
+	Profiling  ProfilingConfig  `yaml:"profiling" json:"profiling"`
+	Capacity   CapacityConfig   `yaml:"capacity" json:"capacity"`
+	Redaction  RedactionConfig  `yaml:"redaction" json:"redaction"`
+	UploadScan UploadScanConfig `yaml:"upload_scan" json:"upload_scan"`
+	UploadSync UploadSyncConfig `yaml:"upload_sync" json:"upload_sync"`
+	Signing    SigningConfig    `yaml:"signing" json:"signing"`
+	Tenants    []TenantConfig   `yaml:"tenants" json:"tenants"`
+
+	CloudCredentials CloudCredentialsConfig `yaml:"cloud_credentials" json:"cloud_credentials"`
+	WorkflowRegistry WorkflowRegistryConfig `yaml:"workflow_registry" json:"workflow_registry"`
+	WorkflowState    WorkflowStateConfig    `yaml:"workflow_state" json:"workflow_state"`
+	Coordination     CoordinationConfig     `yaml:"coordination" json:"coordination"`
+	Retention        RetentionConfig        `yaml:"retention" json:"retention"`
+	LogSinks         LogSinksConfig         `yaml:"log_sinks" json:"log_sinks"`
+	FairShare        FairShareConfig        `yaml:"fair_share" json:"fair_share"`
+	Scheduling       SchedulingConfig       `yaml:"scheduling" json:"scheduling"`
+	DurationAnomaly  DurationAnomalyConfig  `yaml:"duration_anomaly" json:"duration_anomaly"`
+	OutputLimits     OutputLimitsConfig     `yaml:"output_limits" json:"output_limits"`
+	Identity         IdentityConfig         `yaml:"identity" json:"identity"`
+	GitSources       GitSourcesConfig       `yaml:"git_sources" json:"git_sources"`
+	HA               HAConfig               `yaml:"ha" json:"ha"`
+	Maintenance      MaintenanceConfig      `yaml:"maintenance" json:"maintenance"`
+	Hooks            HooksConfig            `yaml:"hooks" json:"hooks"`
+	JobIPC           JobIPCConfig           `yaml:"job_ipc" json:"job_ipc"`
+	Inputs           InputsConfig           `yaml:"inputs" json:"inputs"`
+	OutputTargets    OutputTargetsConfig    `yaml:"output_targets" json:"output_targets"`
+	Digest           DigestConfig           `yaml:"digest" json:"digest"`
+	Clock            ClockConfig            `yaml:"clock" json:"clock"`
+	Execution        ExecutionConfig        `yaml:"execution" json:"execution"`
 }
 
 type NetworkConfig struct {
@@ -79,6 +120,11 @@ type JobletConfig struct {
 	MaxConcurrentJobs  int           `yaml:"maxConcurrentJobs" json:"maxConcurrentJobs"`
 	JobTimeout         time.Duration `yaml:"jobTimeout" json:"jobTimeout"`
 	CleanupTimeout     time.Duration `yaml:"cleanupTimeout" json:"cleanupTimeout"`
+
+	// Jobs failing on node setup (filesystem, network, mounts) are relaunched
+	// up to InfraRetries times, InfraRetryDelay apart, before they fail
+	InfraRetries    int           `yaml:"infraRetries" json:"infraRetries"`
+	InfraRetryDelay time.Duration `yaml:"infraRetryDelay" json:"infraRetryDelay"`
 }
 
 // CgroupConfig holds cgroup-related configuration
@@ -87,6 +133,63 @@ type CgroupConfig struct {
 	NamespaceMount    string        `yaml:"namespaceMount" json:"namespaceMount"`
 	EnableControllers []string      `yaml:"enableControllers" json:"enableControllers"`
 	CleanupTimeout    time.Duration `yaml:"cleanupTimeout" json:"cleanupTimeout"`
+
+	// AllowedParams lists the raw cgroup v2 files, such as cpu.weight or
+	// io.latency, jobs may set through cgroup_params (empty = none)
+	AllowedParams []string `yaml:"allowedParams" json:"allowedParams"`
+
+	// Driver is how job cgroups are made: "cgroupfs" creates them under
+	// BaseDir, "systemd" asks systemd over D-Bus for a delegated scope per
+	// job under Slice, so slice-level limits set by operators apply
+	Driver string `yaml:"driver" json:"driver"`
+	Slice  string `yaml:"slice" json:"slice"`
+}
+
+// Cgroup drivers
+const (
+	CgroupDriverCgroupfs = "cgroupfs"
+	CgroupDriverSystemd  = "systemd"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted on the host
+const cgroupRoot = "/sys/fs/cgroup"
+
+// SystemdDriver reports whether job cgroups are systemd scopes
+func (c CgroupConfig) SystemdDriver() bool {
+	return c.Driver == CgroupDriverSystemd
+}
+
+// JobsDir returns the directory holding the cgroup of every job: BaseDir,
+// or the slice's directory with the systemd driver
+func (c CgroupConfig) JobsDir() string {
+	if !c.SystemdDriver() {
+		return c.BaseDir
+	}
+	dir, _ := sdbus.SliceDir(c.Slice) // Checked by Validate
+	return filepath.Join(cgroupRoot, dir)
+}
+
+// JobScope returns the name of the scope unit of a job with the systemd
+// driver
+func (c CgroupConfig) JobScope(jobID string) string {
+	return "job-" + jobID + ".scope"
+}
+
+// JobCgroupPath returns the cgroup directory of a job: job-<id> under
+// BaseDir, or its scope under the slice with the systemd driver
+func (c CgroupConfig) JobCgroupPath(jobID string) string {
+	if c.SystemdDriver() {
+		return filepath.Join(c.JobsDir(), c.JobScope(jobID))
+	}
+	return filepath.Join(c.BaseDir, "job-"+jobID)
+}
+
+// cgroupParamPattern matches a controller's cgroup v2 interface file
+var cgroupParamPattern = regexp.MustCompile(`^[a-z]+(\.[a-zA-Z0-9_]+)+$`)
+
+// AllowsParam reports whether jobs may set the cgroup file name
+func (c CgroupConfig) AllowsParam(name string) bool {
+	return slices.Contains(c.AllowedParams, name)
 }
 
 // FilesystemConfig holds filesystem configuration
@@ -96,6 +199,50 @@ type FilesystemConfig struct {
 	WorkspaceDir  string   `yaml:"workspaceDir" json:"workspaceDir"`
 	AllowedMounts []string `yaml:"allowedMounts" json:"allowedMounts"`
 	BlockDevices  bool     `yaml:"blockDevices" json:"blockDevices"`
+	ShmSizeBytes  int64    `yaml:"shmSizeBytes" json:"shmSizeBytes"` // Default /dev/shm tmpfs size per job
+	TmpSizeBytes  int64    `yaml:"tmpSizeBytes" json:"tmpSizeBytes"` // Default /tmp tmpfs size per job (0 = host-backed, unlimited)
+
+	SkeletonPoolSize int `yaml:"skeletonPoolSize" json:"skeletonPoolSize"` // Pre-created job root directories kept ready (0 = disabled)
+
+	// Scratch devices jobs may place their work directory on (--scratch=NAME), keyed by name
+	Scratch map[string]ScratchConfig `yaml:"scratch" json:"scratch"`
+
+	// How long the filesystem of a failed job armed with rnx job freeze-fs is kept for browsing (0 = freezing disabled)
+	FreezeRetention time.Duration `yaml:"freezeRetention" json:"freezeRetention"`
+
+	// Binary every job starts as its init process (empty = /opt/joblet/bin/joblet).
+	// The minimal joblet-init binary runs init mode only and is a fraction of the size.
+	InitBinary string `yaml:"initBinary" json:"initBinary"`
+
+	// Distro library layout mounted into every job's chroot on top of allowedMounts:
+	// "auto" detects the host's distro at startup, "none" mounts allowedMounts only,
+	// or one of ChrootProfiles (empty = auto). Runtimes may override it in runtime.yml.
+	ChrootProfile string `yaml:"chrootProfile" json:"chrootProfile"`
+}
+
+// Chroot profile settings besides the names of ChrootProfiles
+const (
+	ChrootProfileAuto = "auto"
+	ChrootProfileNone = "none"
+)
+
+// ChrootProfiles are the distro families whose library layout jobs can be given
+var ChrootProfiles = []string{"debian", "rhel", "suse", "alpine", "arch", "generic"}
+
+// IsChrootProfile reports whether name is one of ChrootProfiles
+func IsChrootProfile(name string) bool {
+	return slices.Contains(ChrootProfiles, name)
+}
+
+// ScratchConfig is a dedicated block device, such as a local NVMe drive,
+// holding the work directories of the jobs that select it instead of the
+// RAM-backed default. Each job gets a directory under Path capped by a
+// project quota, so the filesystem on the device must be mounted with project
+// quotas enabled (xfs prjquota, or ext4 with the project feature and prjquota).
+type ScratchConfig struct {
+	Path       string `yaml:"path" json:"path"`             // Directory on the device's filesystem holding the job directories
+	QuotaBytes int64  `yaml:"quotaBytes" json:"quotaBytes"` // Per-job project quota (0 = no quota, only the device size)
+	Device     string `yaml:"device" json:"device"`         // Block device of Path for quotactl (empty = looked up in the mount table)
 }
 
 // GRPCConfig holds gRPC-specific configuration
@@ -105,11 +252,23 @@ type GRPCConfig struct {
 	MaxHeaderListSize     int32         `yaml:"maxHeaderListSize" json:"maxHeaderListSize"`
 	KeepAliveTime         time.Duration `yaml:"keepAliveTime" json:"keepAliveTime"`
 	KeepAliveTimeout      time.Duration `yaml:"keepAliveTimeout" json:"keepAliveTimeout"`
+	KeepAliveMinTime      time.Duration `yaml:"keepAliveMinTime" json:"keepAliveMinTime"` // Most frequent client pings accepted (0 = keepAliveTime/2)
 	MaxConcurrentStreams  uint32        `yaml:"maxConcurrentStreams" json:"maxConcurrentStreams"`
 	ConnectionTimeout     time.Duration `yaml:"connectionTimeout" json:"connectionTimeout"`
 	MaxConnectionIdle     time.Duration `yaml:"maxConnectionIdle" json:"maxConnectionIdle"`
 	MaxConnectionAge      time.Duration `yaml:"maxConnectionAge" json:"maxConnectionAge"`
 	MaxConnectionAgeGrace time.Duration `yaml:"maxConnectionAgeGrace" json:"maxConnectionAgeGrace"`
+
+	// Log streams send an empty chunk after LogStreamHeartbeat without output,
+	// so NAT mappings stay alive and clients notice dead streams (0 = off). A
+	// send blocked for LogStreamSendTimeout abandons the stream.
+	LogStreamHeartbeat   time.Duration `yaml:"logStreamHeartbeat" json:"logStreamHeartbeat"`
+	LogStreamSendTimeout time.Duration `yaml:"logStreamSendTimeout" json:"logStreamSendTimeout"`
+
+	// Standard grpc.health.v1 and server reflection services, for grpcurl,
+	// load balancers and probes. Callers still need a client certificate.
+	HealthService bool `yaml:"healthService" json:"healthService"`
+	Reflection    bool `yaml:"reflection" json:"reflection"`
 }
 
 // LoggingConfig holds logging configuration
@@ -124,27 +283,62 @@ type MonitoringConfig struct {
 	Enabled        bool          `yaml:"enabled" json:"enabled"`
 	SystemInterval time.Duration `yaml:"system_interval" json:"system_interval"`
 	CloudDetection bool          `yaml:"cloud_detection" json:"cloud_detection"`
+	// History records host metrics to persist for 'rnx monitor history'
+	History MonitoringHistoryConfig `yaml:"history" json:"history"`
+}
+
+// MonitoringHistoryConfig controls the host metrics kept in persist. Samples
+// are stored per day, and a day is removed once all of it is older than
+// Retention.
+type MonitoringHistoryConfig struct {
+	Enabled   bool          `yaml:"enabled" json:"enabled"`
+	Interval  time.Duration `yaml:"interval" json:"interval"`   // Time between samples
+	Retention time.Duration `yaml:"retention" json:"retention"` // How long samples are kept
 }
 
 // ClientConfig represents the client-side configuration with multiple nodes
 type ClientConfig struct {
 	Version string           `yaml:"version"`
 	Nodes   map[string]*Node `yaml:"nodes"`
+
+	Path string `yaml:"-"` // File the configuration was loaded from
 }
 
-// Node represents a single server configuration with embedded certificates
+// Node represents a single server configuration with embedded certificates.
+// Cert, Key, CA, SigningKey and Token may instead be "keychain:<account>"
+// references to secrets in the OS keychain; see ResolveKeychain.
 type Node struct {
 	Address string `yaml:"address"`
 	NodeId  string `yaml:"nodeId,omitempty"` // Unique identifier of the Joblet node (optional, for display purposes)
 	Cert    string `yaml:"cert"`             // Embedded PEM certificate
 	Key     string `yaml:"key"`              // Embedded PEM private key
 	CA      string `yaml:"ca"`               // Embedded PEM CA certificate
+
+	SigningKey string `yaml:"signingKey,omitempty"` // Embedded PEM Ed25519 key signing RunJob and RunWorkflow requests (optional)
+
+	// Command printing an "authorization" header value sent with every
+	// request, "Negotiate <base64 ticket>" for Kerberos or "Bearer <token>"
+	// (optional). Nodes with an auth command need no cert and key when the
+	// server's identity provider is enabled.
+	AuthCommand []string `yaml:"authCommand,omitempty"`
+
+	// Static "authorization" header value, or a bare token sent as "Bearer
+	// <token>", for nodes without an auth command (optional). Usually a
+	// keychain reference rather than the token itself.
+	Token string `yaml:"token,omitempty"`
+
+	// Client keepalive pings, overriding rnx's defaults of 30s and 10s
+	// (optional). Pings more frequent than the node's keepAliveMinTime are
+	// rejected.
+	KeepAliveTime    time.Duration `yaml:"keepAliveTime,omitempty"`
+	KeepAliveTimeout time.Duration `yaml:"keepAliveTimeout,omitempty"`
 }
 
 // BuffersConfig holds buffer and pub-sub configuration
 type BuffersConfig struct {
 	PubsubBufferSize int `yaml:"pubsub_buffer_size" json:"pubsub_buffer_size"` // Pub-sub channel buffer size
 	ChunkSize        int `yaml:"chunk_size" json:"chunk_size"`                 // Chunk size for streaming
+	LogTailKB        int `yaml:"log_tail_kb" json:"log_tail_kb"`               // In-memory log tail per job in KB (0 = unbounded)
 }
 
 // VolumesConfig holds volume management configuration
@@ -155,8 +349,294 @@ type VolumesConfig struct {
 
 // RuntimeConfig holds runtime system configuration
 type RuntimeConfig struct {
-	BasePath    string   `yaml:"base_path" json:"base_path"`
-	CommonPaths []string `yaml:"common_paths" json:"common_paths"`
+	BasePath    string                 `yaml:"base_path" json:"base_path"`
+	CommonPaths []string               `yaml:"common_paths" json:"common_paths"`
+	Aliases     map[string]string      `yaml:"aliases" json:"aliases"` // Name jobs may ask for -> exact runtime ("python" -> "python-3.11@1.2.0")
+	Detection   RuntimeDetectionConfig `yaml:"detection" json:"detection"`
+	Channels    RuntimeChannelsConfig  `yaml:"channels" json:"channels"`
+}
+
+// RuntimeChannelsConfig holds the stable and canary channels of runtime
+// names, which admins move between runtime versions at run time; jobs asking
+// for "python@canary" run on the canary pinned for python
+type RuntimeChannelsConfig struct {
+	File            string  `yaml:"file" json:"file"`                           // Where the channel pins are kept
+	MinSamples      int     `yaml:"min_samples" json:"min_samples"`             // Finished canary jobs before failure rates are compared
+	MaxRateIncrease float64 `yaml:"max_rate_increase" json:"max_rate_increase"` // Failure rate the canary may exceed stable by (0.05 = 5 points)
+}
+
+// RuntimeDetectionConfig picks a runtime for jobs that name none but upload a
+// dependency file (requirements.txt, package.json, go.mod)
+type RuntimeDetectionConfig struct {
+	Policy      string            `yaml:"policy" json:"policy"`             // "off" (or empty), "suggest" (tell the client) or "auto" (run with the runtime)
+	Runtimes    map[string]string `yaml:"runtimes" json:"runtimes"`         // Dependency file -> runtime, instead of the newest installed one
+	Install     bool              `yaml:"install" json:"install"`           // With "auto", install the dependencies before the command
+	CacheVolume string            `yaml:"cache_volume" json:"cache_volume"` // Volume installed dependencies are kept in, by file content (empty = install every run)
+}
+
+// WorkflowRegistryConfig holds the workflows registered to be started by name
+type WorkflowRegistryConfig struct {
+	Dir         string `yaml:"dir" json:"dir"`                   // Where registered workflows are stored
+	MaxVersions int    `yaml:"max_versions" json:"max_versions"` // Versions kept per workflow, older ones are pruned (0 = all)
+}
+
+// WorkflowStateConfig holds the workflows saved in the state service so they
+// survive restarts
+type WorkflowStateConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"` // Save workflows as they run (false = kept in memory only)
+
+	// How long an ended workflow stays saved, so it is listed again after a restart (0 = forever)
+	Retention time.Duration `yaml:"retention" json:"retention"`
+}
+
+// CoordinationConfig registers this node with a coordination endpoint and
+// keeps the registration alive with heartbeats, so clients can discover live
+// nodes instead of listing every node in their own configuration
+type CoordinationConfig struct {
+	Enabled           bool              `yaml:"enabled" json:"enabled"`
+	Backend           string            `yaml:"backend" json:"backend"`                       // "state" (state service API) or "etcd"
+	Endpoint          string            `yaml:"endpoint" json:"endpoint"`                     // State API host:port, or etcd client URL
+	AdvertiseAddress  string            `yaml:"advertise_address" json:"advertise_address"`   // Address clients connect to (default: hostname and server port)
+	Labels            map[string]string `yaml:"labels" json:"labels"`                         // Published with the registration
+	HeartbeatInterval time.Duration     `yaml:"heartbeat_interval" json:"heartbeat_interval"` // Time between heartbeats
+	TTL               time.Duration     `yaml:"ttl" json:"ttl"`                               // Node is dropped when no heartbeat arrives for this long
+	ClientCert        string            `yaml:"client_cert" json:"client_cert"`               // PEM client certificate (OU=node), CA from the security section
+	ClientKey         string            `yaml:"client_key" json:"client_key"`                 // PEM client key
+	Prefix            string            `yaml:"prefix" json:"prefix"`                         // etcd key prefix
+}
+
+// RetentionConfig limits how long finished job records stay in the job store.
+// A background reaper removes records beyond either limit, archiving them to
+// persist first; logs and metrics in persist are kept.
+type RetentionConfig struct {
+	KeepLast int           `yaml:"keep_last" json:"keep_last"` // Finished jobs kept per name, unnamed jobs per command (0 = no limit)
+	MaxAge   time.Duration `yaml:"max_age" json:"max_age"`     // Finished jobs older than this are removed (0 = no limit)
+	Interval time.Duration `yaml:"interval" json:"interval"`   // Time between sweeps
+	Archive  bool          `yaml:"archive" json:"archive"`     // Archive records to persist before removal
+}
+
+// Enabled reports whether a retention limit is set
+func (r RetentionConfig) Enabled() bool {
+	return r.KeepLast > 0 || r.MaxAge > 0
+}
+
+// LogSinksConfig controls the external destinations jobs can copy their
+// output to with 'rnx job run --log-sink'. Output is redacted before it
+// reaches a sink, as it is for persist.
+type LogSinksConfig struct {
+	Enabled       bool          `yaml:"enabled" json:"enabled"`                 // Accept jobs that request log sinks
+	FlushInterval time.Duration `yaml:"flush_interval" json:"flush_interval"`   // Longest time output waits before it is sent
+	MaxChunkBytes int           `yaml:"max_chunk_bytes" json:"max_chunk_bytes"` // S3: output uploaded as soon as this much is buffered
+	S3            S3SinkConfig  `yaml:"s3" json:"s3"`
+}
+
+// S3SinkConfig holds the credentials and endpoint for s3:// log sinks. Empty
+// credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables of the server.
+type S3SinkConfig struct {
+	Region          string `yaml:"region" json:"region"`
+	Endpoint        string `yaml:"endpoint" json:"endpoint"` // S3-compatible endpoint URL, path-style requests (empty = AWS)
+	AccessKeyID     string `yaml:"access_key_id" json:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" json:"secret_access_key"`
+	SessionToken    string `yaml:"session_token" json:"session_token"`
+}
+
+// FairShareConfig queues jobs while the node runs max_running_jobs and starts
+// them as jobs finish, the tenant with the least weighted usage over the
+// window first. Usage is the time tenants' jobs held a running slot.
+type FairShareConfig struct {
+	Enabled        bool          `yaml:"enabled" json:"enabled"`
+	MaxRunningJobs int           `yaml:"max_running_jobs" json:"max_running_jobs"` // Running jobs that saturate the node (0 = joblet.maxConcurrentJobs)
+	Window         time.Duration `yaml:"window" json:"window"`                     // Usage older than this is forgotten
+	Slice          time.Duration `yaml:"slice" json:"slice"`                       // Usage is kept per slice, it leaves the window a slice at a time
+}
+
+// SchedulingConfig queues jobs while the node runs max_running_jobs and starts
+// them as jobs finish, in the order the policy picks. Policies are fifo,
+// priority, fair_share, bin_packing or one compiled into the server. Setting
+// fair_share.enabled is the same as enabling the fair_share policy.
+type SchedulingConfig struct {
+	Enabled        bool              `yaml:"enabled" json:"enabled"`
+	Policy         string            `yaml:"policy" json:"policy"`                     // Picks the queued job to start next
+	MaxRunningJobs int               `yaml:"max_running_jobs" json:"max_running_jobs"` // Running jobs that saturate the node (0 = joblet.maxConcurrentJobs)
+	PriorityLabel  string            `yaml:"priority_label" json:"priority_label"`     // Job label holding the priority policy's integer priority
+	Options        map[string]string `yaml:"options" json:"options"`                   // Settings of compiled-in policies
+}
+
+// AdmissionPolicy returns the scheduling policy queued jobs start in and the
+// number of running jobs that saturate the node, 0 when jobs never queue
+func (c *Config) AdmissionPolicy() (string, int) {
+	var policy string
+	var maxRunning int
+	switch {
+	case c.Scheduling.Enabled:
+		policy, maxRunning = c.Scheduling.Policy, c.Scheduling.MaxRunningJobs
+	case c.FairShare.Enabled:
+		policy, maxRunning = "fair_share", c.FairShare.MaxRunningJobs
+	default:
+		return "", 0
+	}
+	if maxRunning == 0 {
+		maxRunning = c.Joblet.MaxConcurrentJobs
+	}
+	return policy, maxRunning
+}
+
+// DurationAnomalyConfig flags named jobs that run much longer than they
+// usually do. The usual duration is the mean of the job name's latest
+// completed runs, from the job store and the records archived to persist; a
+// run is flagged once it exceeds the mean by threshold standard deviations.
+type DurationAnomalyConfig struct {
+	Enabled    bool          `yaml:"enabled" json:"enabled"`
+	Threshold  float64       `yaml:"threshold" json:"threshold"`     // Standard deviations above the mean
+	Samples    int           `yaml:"samples" json:"samples"`         // Latest completed runs per name the distribution is built from
+	MinSamples int           `yaml:"min_samples" json:"min_samples"` // Runs a name needs before its jobs are checked
+	Interval   time.Duration `yaml:"interval" json:"interval"`       // Time between checks of the running jobs
+	WebhookURL string        `yaml:"webhook_url" json:"webhook_url"` // Anomalies are POSTed here as JSON (empty = log only)
+}
+
+// MaintenanceConfig defers job starts during recurring maintenance windows,
+// e.g. while OS patch automation updates and reboots the node. Jobs submitted
+// or due during a window are queued with a MAINTENANCE event and start when
+// it ends; running jobs are left alone. Notifications are sent notice_before
+// a window starts, when it starts and when it ends.
+type MaintenanceConfig struct {
+	Windows      []MaintenanceWindow `yaml:"windows" json:"windows"`
+	NoticeBefore time.Duration       `yaml:"notice_before" json:"notice_before"` // Lead time of the upcoming window notification
+	WebhookURL   string              `yaml:"webhook_url" json:"webhook_url"`     // Notifications are POSTed here as JSON (empty = log only)
+}
+
+// MaintenanceWindow is a recurring window during which no job is started
+type MaintenanceWindow struct {
+	Name     string        `yaml:"name" json:"name"`
+	Schedule string        `yaml:"schedule" json:"schedule"` // Cron expression of the window starts, in the node's local time
+	Duration time.Duration `yaml:"duration" json:"duration"`
+}
+
+// HooksConfig runs site scripts on the node around each job, e.g. to register
+// jobs in a CMDB. pre_start hooks run before the job's process is launched,
+// post_stop hooks once it has ended, whatever the outcome. Hooks get the job's
+// metadata in JOBLET_* variables, never its environment or secrets, and run as
+// user with a minimal environment, no stdin and a hard timeout.
+type HooksConfig struct {
+	PreStart []HookConfig `yaml:"pre_start" json:"pre_start"`
+	PostStop []HookConfig `yaml:"post_stop" json:"post_stop"`
+	User     string       `yaml:"user" json:"user"` // Account the hooks run as when joblet runs as root
+}
+
+// HookConfig is one hook script
+type HookConfig struct {
+	Name     string        `yaml:"name" json:"name"`
+	Command  string        `yaml:"command" json:"command"` // Absolute path of the executable
+	Args     []string      `yaml:"args" json:"args"`
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`   // The hook is killed after this long (0 = 10s)
+	Required bool          `yaml:"required" json:"required"` // pre_start only: the job fails when the hook fails
+}
+
+// JobIPCConfig lets co-located jobs that opt in with the same channel name share
+// a memory-backed /dev/shm, e.g. to exchange Arrow buffers without going
+// through the network. A channel named in channels is shared by the jobs of
+// its tenants; any other name is private to the submitting job's tenant.
+type JobIPCConfig struct {
+	Enabled   bool                  `yaml:"enabled" json:"enabled"`
+	BaseDir   string                `yaml:"base_dir" json:"base_dir"`     // Channel tmpfs mounts live here
+	SizeBytes int64                 `yaml:"size_bytes" json:"size_bytes"` // Default channel size
+	Channels  []JobIPCChannelConfig `yaml:"channels" json:"channels"`
+}
+
+// JobIPCChannelConfig is a channel jobs of several tenants may share
+type JobIPCChannelConfig struct {
+	Name      string   `yaml:"name" json:"name"`
+	Tenants   []string `yaml:"tenants" json:"tenants"`       // Tenants whose jobs may join (empty = every tenant)
+	SizeBytes int64    `yaml:"size_bytes" json:"size_bytes"` // Channel size (0 = job_ipc.size_bytes)
+}
+
+// InputsConfig lets jobs name objects in S3 or Google Cloud Storage that the
+// server downloads into their workspace before they start ('rnx job run
+// --input'). Downloads are cached by ETag, so jobs reading the same dataset
+// fetch it once.
+type InputsConfig struct {
+	Enabled        bool          `yaml:"enabled" json:"enabled"`
+	CacheDir       string        `yaml:"cache_dir" json:"cache_dir"`               // Downloaded objects, named after their URL and ETag
+	MaxCacheBytes  int64         `yaml:"max_cache_bytes" json:"max_cache_bytes"`   // Least recently used objects are evicted beyond this (0 = no cache)
+	MaxObjectBytes int64         `yaml:"max_object_bytes" json:"max_object_bytes"` // Largest object a job may fetch
+	Timeout        time.Duration `yaml:"timeout" json:"timeout"`                   // Longest fetching all of a job's inputs may take
+	S3             S3SinkConfig  `yaml:"s3" json:"s3"`                             // s3:// credentials when the job carries no AWS keys
+	GCS            S3SinkConfig  `yaml:"gcs" json:"gcs"`                           // gs:// HMAC keys; endpoint defaults to the XML API
+}
+
+// OutputTargetsConfig lets jobs declare files under /work that the server
+// uploads to S3 or Google Cloud Storage when they complete successfully ('rnx
+// job run --output'), followed by a manifest.json listing them.
+type OutputTargetsConfig struct {
+	Enabled        bool          `yaml:"enabled" json:"enabled"`
+	MaxFiles       int           `yaml:"max_files" json:"max_files"`               // Most files a job may publish
+	MaxObjectBytes int64         `yaml:"max_object_bytes" json:"max_object_bytes"` // Largest file a job may publish (single PUT, at most 5GB)
+	Retries        int           `yaml:"retries" json:"retries"`                   // Further attempts per file after a failed upload
+	RetryDelay     time.Duration `yaml:"retry_delay" json:"retry_delay"`           // Wait before the first retry, doubled for each next one
+	Timeout        time.Duration `yaml:"timeout" json:"timeout"`                   // Longest publishing all of a job's outputs may take
+	S3             S3SinkConfig  `yaml:"s3" json:"s3"`                             // s3:// credentials when the job carries no AWS keys
+	GCS            S3SinkConfig  `yaml:"gcs" json:"gcs"`                           // gs:// HMAC keys; endpoint defaults to the XML API
+}
+
+// DigestConfig sends digests of workflow runs, such as a morning summary of the
+// nightly pipelines: on each digest's schedule, the runs that ended within its
+// window and whose jobs carry its label are summed up in one notification.
+type DigestConfig struct {
+	Digests    []WorkflowDigest `yaml:"digests" json:"digests"`
+	WebhookURL string           `yaml:"webhook_url" json:"webhook_url"` // Digests are POSTed here as JSON (empty = log only)
+}
+
+// WorkflowDigest is one recurring digest
+type WorkflowDigest struct {
+	Name       string        `yaml:"name" json:"name"`
+	Label      string        `yaml:"label" json:"label"`             // Runs with a job labeled key=value, or carrying the key
+	Schedule   string        `yaml:"schedule" json:"schedule"`       // Cron expression of the sends, in the node's local time
+	Window     time.Duration `yaml:"window" json:"window"`           // How far back runs are summed up (0 = 24h)
+	WebhookURL string        `yaml:"webhook_url" json:"webhook_url"` // Overrides digest.webhook_url for this digest
+}
+
+// ClockConfig checks the node's clock, which scheduled jobs start by, against
+// NTP servers at startup and every check_interval. Without NTP servers, or
+// when none answers, the clocks of clients submitting scheduled jobs are the
+// reference. An offset above max_drift is flagged in monitor status.
+type ClockConfig struct {
+	NTPServers    []string      `yaml:"ntp_servers" json:"ntp_servers"`       // host or host:port, asked in order (empty = compare with clients only)
+	CheckInterval time.Duration `yaml:"check_interval" json:"check_interval"` // Time between NTP checks
+	MaxDrift      time.Duration `yaml:"max_drift" json:"max_drift"`           // Offset flagged as drift (0 = never flagged)
+}
+
+// ExecutionConfig selects how this node runs jobs. The "namespace" backend
+// isolates them itself with namespaces, a chroot and cgroups. The
+// experimental "container" backend hands each job to a container runtime
+// such as podman instead, for hosts where those kernel prerequisites are not
+// available. Its jobs cannot use runtimes, runtime builds, volumes, GPUs, IO
+// bandwidth limits, scratch devices, egress policies, joblet networks, secret
+// files, cgroup parameters, stdin uploads, IPC channels, /dev/shm and /tmp
+// sizes, profilers or working directories other than /work.
+type ExecutionConfig struct {
+	Backend   string                 `yaml:"backend" json:"backend"` // namespace or container (empty = namespace)
+	Container ContainerBackendConfig `yaml:"container" json:"container"`
+}
+
+// ContainerBackendConfig configures the container execution backend, which
+// runs every job as "<runtime> run [extra_args] <image> <command> <args>"
+type ContainerBackendConfig struct {
+	Runtime   string   `yaml:"runtime" json:"runtime"`       // Container runtime binary, name or absolute path
+	Image     string   `yaml:"image" json:"image"`           // Image jobs run in
+	ExtraArgs []string `yaml:"extra_args" json:"extra_args"` // Added to every run before the image, e.g. --security-opt
+}
+
+// Execution backends
+const (
+	ExecutionBackendNamespace = "namespace"
+	ExecutionBackendContainer = "container"
+)
+
+// ContainerBackend reports whether jobs run with the experimental container
+// backend
+func (c ExecutionConfig) ContainerBackend() bool {
+	return c.Backend == ExecutionBackendContainer
 }
 
 // GPUConfig holds GPU support configuration
@@ -173,6 +653,197 @@ type IPCConfig struct {
 	BufferSize     int           `yaml:"buffer_size" json:"buffer_size"`         // Message buffer size
 	ReconnectDelay time.Duration `yaml:"reconnect_delay" json:"reconnect_delay"` // Reconnection delay
 	MaxReconnects  int           `yaml:"max_reconnects" json:"max_reconnects"`   // Max reconnection attempts (0 = infinite)
+	BatchSize      int           `yaml:"batch_size" json:"batch_size"`           // Max messages coalesced into one socket write
+	FlushInterval  time.Duration `yaml:"flush_interval" json:"flush_interval"`   // Max time a batch waits to fill before flushing
+}
+
+// RateLimitConfig throttles gRPC calls per client certificate identity.
+// Every call draws from a general token bucket; RunJob and RunWorkflow also
+// draw from a separate, tighter bucket, and server streams such as log
+// following are capped by how many a client may hold open at once.
+type RateLimitConfig struct {
+	Enabled           bool                   `yaml:"enabled" json:"enabled"`
+	RequestsPerSecond float64                `yaml:"requests_per_second" json:"requests_per_second"` // Sustained rate for all RPCs
+	Burst             int                    `yaml:"burst" json:"burst"`                             // Calls allowed above the sustained rate
+	RunJobPerSecond   float64                `yaml:"run_job_per_second" json:"run_job_per_second"`   // Sustained rate for job and workflow submission
+	RunJobBurst       int                    `yaml:"run_job_burst" json:"run_job_burst"`             // Submissions allowed above the sustained rate
+	MaxStreams        int                    `yaml:"max_streams" json:"max_streams"`                 // Concurrent server streams per client (0 = unlimited)
+	Clients           map[string]ClientQuota `yaml:"clients" json:"clients"`                         // Per-client overrides keyed by certificate CN
+}
+
+// ClientQuota overrides the rate limit defaults for one client. Zero fields
+// inherit the value from RateLimitConfig.
+type ClientQuota struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second" json:"requests_per_second"`
+	Burst             int     `yaml:"burst" json:"burst"`
+	RunJobPerSecond   float64 `yaml:"run_job_per_second" json:"run_job_per_second"`
+	RunJobBurst       int     `yaml:"run_job_burst" json:"run_job_burst"`
+	MaxStreams        int     `yaml:"max_streams" json:"max_streams"`
+}
+
+// ProfilingConfig controls 'rnx job run --profile', which wraps the job
+// command with strace or perf inside its namespace. The tools must be
+// installed on the node and the job needs ptrace/perf_event access, so
+// profiling is off by default and only admin clients may request it.
+type ProfilingConfig struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled"`
+	Tools          []string `yaml:"tools" json:"tools"`                       // Profilers clients may request: strace, perf
+	MaxOutputBytes int64    `yaml:"max_output_bytes" json:"max_output_bytes"` // Profile output appended to the job log, truncated beyond this
+}
+
+// CapacityConfig holds part of the node back from jobs, for the host and
+// services running next to joblet. GetNodeCapacity subtracts it from the
+// totals when reporting what is schedulable; it does not limit jobs itself.
+type CapacityConfig struct {
+	ReservedCPUPercent      int32 `yaml:"reserved_cpu_percent" json:"reserved_cpu_percent"`             // 100 = one core
+	ReservedMemoryMB        int64 `yaml:"reserved_memory_mb" json:"reserved_memory_mb"`                 // Memory kept for the host
+	ReservedGPUs            int32 `yaml:"reserved_gpus" json:"reserved_gpus"`                           // GPUs not offered to placement
+	ReservedVolumeDiskBytes int64 `yaml:"reserved_volume_disk_bytes" json:"reserved_volume_disk_bytes"` // Disk kept free under volumes.base_path
+}
+
+// RedactionConfig masks secrets in job output before it is buffered,
+// streamed or sent to persist. The number of masked matches is kept with the
+// job and shown by 'rnx job status'.
+type RedactionConfig struct {
+	SecretValues    bool               `yaml:"secret_values" json:"secret_values"`         // Mask the values of the job's secret environment variables
+	MinSecretLength int                `yaml:"min_secret_length" json:"min_secret_length"` // Shorter secret values are not masked, to avoid masking common words
+	Replacement     string             `yaml:"replacement" json:"replacement"`             // Written in place of each match
+	Patterns        []RedactionPattern `yaml:"patterns" json:"patterns"`                   // Additional regular expressions to mask in every job's output
+}
+
+// RedactionPattern is a named regular expression (Go RE2 syntax)
+type RedactionPattern struct {
+	Name  string `yaml:"name" json:"name"`
+	Regex string `yaml:"regex" json:"regex"`
+}
+
+// OutputLimitsConfig bounds how much output each job can push into the log
+// buffer, live streams and persist. Output over a limit is dropped, with a
+// marker line in the stream where it happened. Zero disables a limit.
+type OutputLimitsConfig struct {
+	RateBytesPerSec int64 `yaml:"rate_bytes_per_sec" json:"rate_bytes_per_sec"` // Sustained output rate per job
+	BurstBytes      int64 `yaml:"burst_bytes" json:"burst_bytes"`               // Output a job can write at once above the rate (0 = one second of rate)
+	MaxBytes        int64 `yaml:"max_bytes" json:"max_bytes"`                   // Total output kept per job, the rest is truncated
+}
+
+// UploadScanConfig runs a job's uploads through a scanner command (clamscan,
+// a policy script, ...) before the job starts. The scanner gets a directory
+// holding the uploads as its last argument; exit status 0 means clean, 1
+// rejects the job with the scanner's output, anything else is a failure.
+type UploadScanConfig struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	Command  string        `yaml:"command" json:"command"`     // Scanner executable, e.g. /usr/bin/clamscan
+	Args     []string      `yaml:"args" json:"args"`           // Arguments before the upload directory
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`     // Per scan; a timeout counts as a scanner failure
+	FailOpen bool          `yaml:"fail_open" json:"fail_open"` // Start jobs unscanned when the scanner itself fails
+	WorkDir  string        `yaml:"work_dir" json:"work_dir"`   // Where uploads are staged for scanning (empty = system temp dir)
+}
+
+// UploadSyncConfig caches the workflow files clients upload, so later runs
+// of a workflow only send the blocks of its files that changed
+type UploadSyncConfig struct {
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+	Dir       string `yaml:"dir" json:"dir"`                 // Where synced files are cached
+	MaxSizeMB int64  `yaml:"max_size_mb" json:"max_size_mb"` // Least recently used files are dropped beyond this (0 = no limit)
+}
+
+// SigningConfig controls signed job submissions. Clients with a signingKey in
+// their node configuration sign RunJob and RunWorkflow requests; the
+// signature is stored with the job or workflow and verified again on read.
+type SigningConfig struct {
+	Required    bool     `yaml:"required" json:"required"`         // Reject unsigned RunJob and RunWorkflow requests
+	TrustedKeys []string `yaml:"trusted_keys" json:"trusted_keys"` // Embedded PEM Ed25519 public keys (empty = any key)
+}
+
+// TenantConfig groups clients, identified by the common name of their
+// certificate, into a tenant. Jobs of a tenant with an AWS role get short-lived
+// credentials for that role in their environment when they start.
+type TenantConfig struct {
+	Name    string        `yaml:"name" json:"name"`
+	Clients []string      `yaml:"clients" json:"clients"`   // Client certificate common names
+	AWSRole AWSRoleConfig `yaml:"aws_role" json:"aws_role"` // Role assumed for the tenant's jobs (optional)
+	Weight  float64       `yaml:"weight" json:"weight"`     // Fair-share weight relative to other tenants (0 = 1)
+	// Runtime names pinned to an exact runtime for this tenant, overriding runtime.aliases
+	RuntimePins map[string]string `yaml:"runtime_pins" json:"runtime_pins"`
+	// Globs of the volumes and runtimes the tenant's jobs may use and its
+	// clients see listed (empty = all)
+	Volumes  []string `yaml:"volumes" json:"volumes"`
+	Runtimes []string `yaml:"runtimes" json:"runtimes"`
+}
+
+// AWSRoleConfig is the IAM role a tenant's jobs act as
+type AWSRoleConfig struct {
+	RoleARN  string        `yaml:"role_arn" json:"role_arn"`
+	Duration time.Duration `yaml:"duration" json:"duration"` // Credential lifetime, 15m to 12h (0 = 1h)
+}
+
+// IdentityConfig lets clients without a certificate of their own, such as
+// enterprise users with Kerberos or LDAP accounts, authenticate with a token
+// sent in the "authorization" request header ("Negotiate <ticket>" or
+// "Bearer <token>"). A helper validates the token and reports the principal
+// and its groups, which Mappings turn into a role and tenant. Clients with a
+// certificate keep using mTLS.
+type IdentityConfig struct {
+	Enabled  bool              `yaml:"enabled" json:"enabled"`
+	Command  string            `yaml:"command" json:"command"`     // Helper reading the token on stdin and printing the identity as JSON
+	Args     []string          `yaml:"args" json:"args"`           // Arguments passed to the helper
+	Timeout  time.Duration     `yaml:"timeout" json:"timeout"`     // Longest a validation may take
+	CacheTTL time.Duration     `yaml:"cache_ttl" json:"cache_ttl"` // Validated tokens are trusted this long without asking the helper again (0 = always ask)
+	Mappings []IdentityMapping `yaml:"mappings" json:"mappings"`   // First match wins; unmapped principals are denied
+}
+
+// IdentityMapping grants a role, and optionally a tenant, to the principals
+// matching Principal (a path.Match pattern such as "*@CORP.EXAMPLE") and
+// belonging to Group. An empty field matches every principal.
+type IdentityMapping struct {
+	Principal string `yaml:"principal" json:"principal"`
+	Group     string `yaml:"group" json:"group"`   // Group as the helper reports it, e.g. an LDAP DN
+	Role      string `yaml:"role" json:"role"`     // admin or viewer
+	Tenant    string `yaml:"tenant" json:"tenant"` // Tenant of the principal's jobs (empty = none)
+}
+
+// GitSourcesConfig lets clients run a workflow or job spec straight from a
+// Git repository ('rnx run --repo'). The server fetches the ref with git into
+// a checkout under WorkDir, reads the spec and the files it uploads, and
+// removes the checkout again. Only repositories matching an entry of
+// Repositories can be fetched.
+type GitSourcesConfig struct {
+	Enabled      bool                  `yaml:"enabled" json:"enabled"`
+	Command      string                `yaml:"command" json:"command"`           // git executable
+	WorkDir      string                `yaml:"work_dir" json:"work_dir"`         // Parent of the short-lived checkouts
+	Timeout      time.Duration         `yaml:"timeout" json:"timeout"`           // Longest a fetch may take
+	MaxSizeMB    int64                 `yaml:"max_size_mb" json:"max_size_mb"`   // Cap on the spec and files sent to the jobs (0 = no limit)
+	Repositories []GitRepositoryConfig `yaml:"repositories" json:"repositories"` // Repositories that may be fetched; the longest matching URL wins
+}
+
+// HAConfig runs standby persist and state processes next to the active ones.
+// All instances of a service campaign for a lease (persist's in the state
+// service, state's in its storage backend); the holder binds the service's
+// sockets, and a standby binds them once the holder's lease expires. State
+// standbys need a shared backend such as dynamodb, with the memory backend
+// only persist gets standbys.
+type HAConfig struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	Standbys int           `yaml:"standbys" json:"standbys"`   // Standby processes per service
+	LeaseTTL time.Duration `yaml:"lease_ttl" json:"lease_ttl"` // How long a dead active process keeps the lease
+}
+
+// GitRepositoryConfig allows the repositories whose URL starts with URL and
+// sets how they are fetched
+type GitRepositoryConfig struct {
+	URL        string `yaml:"url" json:"url"`                 // e.g. "git@git.internal:team/" or "https://git.internal/team/app"
+	DeployKey  string `yaml:"deploy_key" json:"deploy_key"`   // SSH private key file (optional)
+	KnownHosts string `yaml:"known_hosts" json:"known_hosts"` // known_hosts file the SSH host key must be in (optional)
+}
+
+// CloudCredentialsConfig configures the helper that mints tenant credentials.
+// The helper is run for every job start with JOBLET_TENANT, JOBLET_ROLE_ARN,
+// JOBLET_ROLE_SESSION_NAME and JOBLET_DURATION_SECONDS set, and prints the
+// credentials as JSON, in 'aws sts assume-role' or credential_process format.
+type CloudCredentialsConfig struct {
+	Command string        `yaml:"command" json:"command"` // Helper executable
+	Args    []string      `yaml:"args" json:"args"`
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
 }
 
 // StateConfig holds job state persistence configuration
@@ -185,6 +856,16 @@ type StateConfig struct {
 	ReconnectDelay time.Duration      `yaml:"reconnect_delay" json:"reconnect_delay"` // Reconnection delay
 	PoolSize       int                `yaml:"pool_size" json:"pool_size"`             // Connection pool size (0 = use default 20)
 	Storage        StateStorageConfig `yaml:"storage" json:"storage"`                 // Backend-specific configuration
+	API            StateAPIConfig     `yaml:"api" json:"api"`                         // Read-only gRPC API for external consumers
+}
+
+// StateAPIConfig exposes the state service's job states read-only over gRPC
+// for dashboards and schedulers. It uses the security section's certificates
+// with mutual TLS and the same admin/viewer roles as the main server.
+type StateAPIConfig struct {
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	Address     string `yaml:"address" json:"address"`           // TCP listen address, e.g. "0.0.0.0:50054"
+	MaxWatchers int    `yaml:"max_watchers" json:"max_watchers"` // Concurrent WatchJobStates streams (0 = unlimited)
 }
 
 // StateStorageConfig holds backend-specific storage configuration
@@ -236,12 +917,16 @@ var DefaultConfig = Config{
 		MaxConcurrentJobs:  100,
 		JobTimeout:         1 * time.Hour,
 		CleanupTimeout:     5 * time.Second,
+		InfraRetries:       2,
+		InfraRetryDelay:    2 * time.Second,
 	},
 	Cgroup: CgroupConfig{
 		BaseDir:           "/sys/fs/cgroup/joblet.slice/joblet.service",
 		NamespaceMount:    "/sys/fs/cgroup",
 		EnableControllers: []string{"cpu", "memory", "io", "pids", "cpuset", "devices"},
 		CleanupTimeout:    5 * time.Second,
+		Driver:            CgroupDriverCgroupfs,
+		Slice:             "joblet-jobs.slice",
 	},
 	Filesystem: FilesystemConfig{
 		BaseDir:       "/opt/joblet/jobs",
@@ -249,6 +934,13 @@ var DefaultConfig = Config{
 		WorkspaceDir:  "/work",
 		AllowedMounts: []string{"/usr/bin", "/bin", "/lib", "/lib64"},
 		BlockDevices:  false,
+		ShmSizeBytes:  67108864, // 64MB, same as the Docker default
+		TmpSizeBytes:  0,        // Bind mount host-backed tmp dir without a size cap
+
+		SkeletonPoolSize: 8, // Enough to absorb a burst of short-lived jobs
+		FreezeRetention:  24 * time.Hour,
+		InitBinary:       "/opt/joblet/bin/joblet",
+		ChrootProfile:    ChrootProfileAuto,
 	},
 	GRPC: GRPCConfig{
 		MaxRecvMsgSize:        134217728,          // 128MB for production traffic
@@ -261,6 +953,8 @@ var DefaultConfig = Config{
 		MaxConnectionIdle:     300 * time.Second,  // 5min idle
 		MaxConnectionAge:      1800 * time.Second, // 30min max age
 		MaxConnectionAgeGrace: 30 * time.Second,   // 30s grace period
+		LogStreamHeartbeat:    15 * time.Second,   // Below common NAT idle timeouts
+		LogStreamSendTimeout:  30 * time.Second,   // Client stopped reading
 	},
 	Logging: LoggingConfig{
 		Level:  "INFO",
@@ -287,10 +981,16 @@ var DefaultConfig = Config{
 		Enabled:        true,
 		SystemInterval: 10 * time.Second,
 		CloudDetection: true,
+		History: MonitoringHistoryConfig{
+			Enabled:   true,
+			Interval:  time.Minute,
+			Retention: 7 * 24 * time.Hour,
+		},
 	},
 	Buffers: BuffersConfig{
 		PubsubBufferSize: 10000,   // Pub-sub buffer for real-time streaming
 		ChunkSize:        1048576, // 1MB chunks
+		LogTailKB:        1024,    // 1MB of recent output kept in memory per job
 	},
 	Volumes: VolumesConfig{
 		BasePath:              "/opt/joblet/volumes",
@@ -305,6 +1005,14 @@ var DefaultConfig = Config{
 			"/usr/local/node",
 			"/usr/local/go",
 		},
+		Detection: RuntimeDetectionConfig{
+			Policy: "suggest",
+		},
+		Channels: RuntimeChannelsConfig{
+			File:            "/opt/joblet/config/runtime-channels.json",
+			MinSamples:      20,
+			MaxRateIncrease: 0.05,
+		},
 	},
 	GPU: GPUConfig{
 		Enabled:            false,       // Off by default - opt-in only
@@ -317,9 +1025,145 @@ var DefaultConfig = Config{
 	IPC: IPCConfig{
 		Enabled:        false, // Disabled by default - opt-in for persist integration
 		Socket:         "/opt/joblet/run/persist-ipc.sock",
-		BufferSize:     10000,           // 10k message buffer
-		ReconnectDelay: 5 * time.Second, // Retry every 5 seconds
-		MaxReconnects:  0,               // Infinite retries
+		BufferSize:     10000,                 // 10k message buffer
+		ReconnectDelay: 5 * time.Second,       // Retry every 5 seconds
+		MaxReconnects:  0,                     // Infinite retries
+		BatchSize:      256,                   // Up to 256 messages per write
+		FlushInterval:  50 * time.Millisecond, // Flush partial batches every 50ms
+	},
+	RateLimit: RateLimitConfig{
+		Enabled:           true,
+		RequestsPerSecond: 50,  // Generous for interactive use and scripts
+		Burst:             100, // Absorbs rnx status/log polling spikes
+		RunJobPerSecond:   10,  // Job submissions are the expensive calls
+		RunJobBurst:       50,  // Allows a batch of submissions at once
+		MaxStreams:        64,  // Concurrent log/metric streams per client
+	},
+	Profiling: ProfilingConfig{
+		Enabled:        false, // Opt-in: exposes syscall-level detail of jobs
+		Tools:          []string{"strace", "perf"},
+		MaxOutputBytes: 16777216, // 16MB
+	},
+	Redaction: RedactionConfig{
+		SecretValues:    true,
+		MinSecretLength: 4,
+		Replacement:     "***", // Same mask as secret values in job status
+	},
+	OutputLimits: OutputLimitsConfig{
+		RateBytesPerSec: 1048576,    // 1MB/s
+		BurstBytes:      8388608,    // 8MB
+		MaxBytes:        1073741824, // 1GB
+	},
+	UploadScan: UploadScanConfig{
+		Enabled: false,
+		Timeout: 60 * time.Second,
+	},
+	UploadSync: UploadSyncConfig{
+		Enabled:   true,
+		Dir:       "/opt/joblet/upload-cache",
+		MaxSizeMB: 10240,
+	},
+	CloudCredentials: CloudCredentialsConfig{
+		Timeout: 30 * time.Second,
+	},
+	Identity: IdentityConfig{
+		Enabled:  false,
+		Timeout:  10 * time.Second,
+		CacheTTL: 5 * time.Minute,
+	},
+	GitSources: GitSourcesConfig{
+		Enabled:   false,
+		Command:   "git",
+		WorkDir:   "/opt/joblet/git",
+		Timeout:   2 * time.Minute,
+		MaxSizeMB: 100,
+	},
+	HA: HAConfig{
+		Enabled:  false,
+		Standbys: 1,
+		LeaseTTL: 10 * time.Second,
+	},
+	WorkflowRegistry: WorkflowRegistryConfig{
+		Dir:         "/opt/joblet/workflows",
+		MaxVersions: 20,
+	},
+	WorkflowState: WorkflowStateConfig{
+		Enabled:   true,
+		Retention: 7 * 24 * time.Hour,
+	},
+	Coordination: CoordinationConfig{
+		Enabled:           false,
+		Backend:           "state",
+		HeartbeatInterval: 10 * time.Second,
+		TTL:               30 * time.Second,
+		Prefix:            "/joblet/nodes/",
+	},
+	Retention: RetentionConfig{
+		Interval: 10 * time.Minute,
+		Archive:  true,
+	},
+	LogSinks: LogSinksConfig{
+		Enabled:       false,
+		FlushInterval: 10 * time.Second,
+		MaxChunkBytes: 5 * 1024 * 1024, // 5MB
+		S3: S3SinkConfig{
+			Region: "us-east-1",
+		},
+	},
+	FairShare: FairShareConfig{
+		Enabled: false,
+		Window:  time.Hour,
+		Slice:   time.Minute,
+	},
+	Scheduling: SchedulingConfig{
+		Enabled:       false,
+		Policy:        "fifo",
+		PriorityLabel: "priority",
+	},
+	DurationAnomaly: DurationAnomalyConfig{
+		Enabled:    true,
+		Threshold:  3,
+		Samples:    30,
+		MinSamples: 5,
+		Interval:   30 * time.Second,
+	},
+	Maintenance: MaintenanceConfig{
+		NoticeBefore: 15 * time.Minute,
+	},
+	Hooks: HooksConfig{
+		User: "nobody",
+	},
+	JobIPC: JobIPCConfig{
+		Enabled:   false,
+		BaseDir:   "/opt/joblet/ipc",
+		SizeBytes: 268435456, // 256MB
+	},
+	Inputs: InputsConfig{
+		Enabled:        false,
+		CacheDir:       "/opt/joblet/input-cache",
+		MaxCacheBytes:  10737418240, // 10GB
+		MaxObjectBytes: 5368709120,  // 5GB
+		Timeout:        10 * time.Minute,
+	},
+	OutputTargets: OutputTargetsConfig{
+		Enabled:        false,
+		MaxFiles:       1000,
+		MaxObjectBytes: 5368709120, // 5GB
+		Retries:        3,
+		RetryDelay:     2 * time.Second,
+		Timeout:        30 * time.Minute,
+	},
+	Clock: ClockConfig{
+		NTPServers:    []string{"pool.ntp.org"},
+		CheckInterval: 15 * time.Minute,
+		MaxDrift:      time.Second,
+	},
+	Execution: ExecutionConfig{
+		Backend: ExecutionBackendNamespace,
+		Container: ContainerBackendConfig{
+			Runtime: "podman",
+			Image:   "docker.io/library/debian:stable-slim",
+		},
 	},
 }
 
@@ -334,9 +1178,10 @@ func (c *Config) GetServerAddress() string {
 // GetCgroupPath constructs the full cgroup path for a specific job.
 // Takes a job ID and combines it with the configured cgroup base directory
 // to create a unique cgroup path for resource isolation.
-// Returns path in format: "/sys/fs/cgroup/joblet.slice/joblet.service/job-{jobID}"
+// Returns path in format: "/sys/fs/cgroup/joblet.slice/joblet.service/job-{jobID}",
+// or "/sys/fs/cgroup/joblet.slice/joblet-jobs.slice/job-{jobID}.scope" with the systemd driver
 func (c *Config) GetCgroupPath(jobID string) string {
-	return filepath.Join(c.Cgroup.BaseDir, "job-"+jobID)
+	return c.Cgroup.JobCgroupPath(jobID)
 }
 
 // GetServerTLSConfig creates a server-side TLS configuration from embedded certificates.
@@ -364,10 +1209,16 @@ func (c *Config) GetServerTLSConfig() (*tls.Config, error) {
 		return nil, fmt.Errorf("failed to parse CA certificate")
 	}
 
-	// Create TLS configuration
+	// Create TLS configuration. With the identity provider, clients may
+	// authenticate with a token instead of a certificate; certificates that
+	// are presented are still verified.
+	clientAuth := tls.RequireAndVerifyClientCert
+	if c.Identity.Enabled {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
 	tlsConfig := &tls.Config{
 		Certificates: []tls.Certificate{serverCert},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientAuth:   clientAuth,
 		ClientCAs:    caCertPool,
 		MinVersion:   tls.VersionTLS13,
 	}
@@ -378,21 +1229,27 @@ func (c *Config) GetServerTLSConfig() (*tls.Config, error) {
 // GetClientTLSConfig creates a client-side TLS configuration from node certificates.
 // Parses the PEM-encoded client certificate, private key, and CA certificate
 // from the node configuration to create a TLS config that:
-//   - Presents client certificate for mTLS authentication
+//   - Presents client certificate for mTLS authentication, unless the node
+//     has an AuthCommand or Token and no certificate
 //   - Validates server certificate against the configured CA
 //   - Uses TLS 1.3 minimum version for security
 //   - Sets server name to "joblet" for certificate validation
 //
 // Returns configured tls.Config or error if certificate parsing fails.
 func (n *Node) GetClientTLSConfig() (*tls.Config, error) {
-	if n.Cert == "" || n.Key == "" || n.CA == "" {
+	tokenOnly := (len(n.AuthCommand) > 0 || n.Token != "") && n.Cert == "" && n.Key == ""
+	if n.CA == "" || (!tokenOnly && (n.Cert == "" || n.Key == "")) {
 		return nil, fmt.Errorf("client certificates are not configured for node")
 	}
 
 	// Load client certificate and key from embedded PEM
-	clientCert, err := tls.X509KeyPair([]byte(n.Cert), []byte(n.Key))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	var certificates []tls.Certificate
+	if !tokenOnly {
+		clientCert, err := tls.X509KeyPair([]byte(n.Cert), []byte(n.Key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		certificates = append(certificates, clientCert)
 	}
 
 	// Load CA certificate from embedded PEM
@@ -403,7 +1260,7 @@ func (n *Node) GetClientTLSConfig() (*tls.Config, error) {
 
 	// Create TLS configuration
 	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{clientCert},
+		Certificates: certificates,
 		RootCAs:      caCertPool,
 		MinVersion:   tls.VersionTLS13,
 		ServerName:   "joblet", // Must match server certificate
@@ -412,6 +1269,29 @@ func (n *Node) GetClientTLSConfig() (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// GetCoordinationTLSConfig creates the client TLS configuration used to reach
+// the coordination endpoint from the coordination client certificate and the
+// security section's CA.
+func (c *Config) GetCoordinationTLSConfig() (*tls.Config, error) {
+	node := Node{Cert: c.Coordination.ClientCert, Key: c.Coordination.ClientKey, CA: c.Security.CACert}
+	return node.GetClientTLSConfig()
+}
+
+// ResolveKeychain returns a copy of the node with its keychain references
+// replaced by the secrets they refer to. Secrets are only read when a client
+// connects, so listing nodes never touches the keychain.
+func (n *Node) ResolveKeychain() (*Node, error) {
+	resolved := *n
+	for _, field := range []*string{&resolved.Cert, &resolved.Key, &resolved.CA, &resolved.SigningKey, &resolved.Token} {
+		value, err := keychain.Resolve(*field)
+		if err != nil {
+			return nil, err
+		}
+		*field = value
+	}
+	return &resolved, nil
+}
+
 // LoadConfig loads the main server configuration from file and environment variables.
 //  1. Path specified in JOBLET_CONFIG_PATH environment variable
 //  2. /opt/joblet/config/joblet-config.yml
@@ -523,6 +1403,210 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid max concurrent jobs: %d", c.Joblet.MaxConcurrentJobs)
 	}
 
+	if c.Joblet.InfraRetries < 0 {
+		return fmt.Errorf("invalid infrastructure retries: %d", c.Joblet.InfraRetries)
+	}
+
+	if c.Joblet.InfraRetryDelay < 0 {
+		return fmt.Errorf("invalid infrastructure retry delay: %v", c.Joblet.InfraRetryDelay)
+	}
+
+	if c.Filesystem.ShmSizeBytes < 0 {
+		return fmt.Errorf("invalid /dev/shm size: %d", c.Filesystem.ShmSizeBytes)
+	}
+
+	if c.Filesystem.TmpSizeBytes < 0 {
+		return fmt.Errorf("invalid /tmp size: %d", c.Filesystem.TmpSizeBytes)
+	}
+
+	if c.Filesystem.SkeletonPoolSize < 0 {
+		return fmt.Errorf("invalid skeleton pool size: %d", c.Filesystem.SkeletonPoolSize)
+	}
+
+	for name, scratch := range c.Filesystem.Scratch {
+		if name == "" || strings.ContainsAny(name, "/:, ") {
+			return fmt.Errorf("invalid scratch device name %q", name)
+		}
+		if !filepath.IsAbs(scratch.Path) || filepath.Clean(scratch.Path) == "/" {
+			return fmt.Errorf("invalid scratch device %q: path must be an absolute directory other than /", name)
+		}
+		if scratch.QuotaBytes < 0 {
+			return fmt.Errorf("invalid scratch device %q: negative quotaBytes %d", name, scratch.QuotaBytes)
+		}
+	}
+
+	if c.Filesystem.FreezeRetention < 0 {
+		return fmt.Errorf("invalid freeze retention: %v", c.Filesystem.FreezeRetention)
+	}
+
+	if c.WorkflowState.Retention < 0 {
+		return fmt.Errorf("invalid workflow state retention: %v", c.WorkflowState.Retention)
+	}
+
+	if c.Filesystem.InitBinary != "" && !filepath.IsAbs(c.Filesystem.InitBinary) {
+		return fmt.Errorf("invalid init binary %q: path must be absolute", c.Filesystem.InitBinary)
+	}
+	if p := c.Filesystem.ChrootProfile; p != "" && p != ChrootProfileAuto && p != ChrootProfileNone && !IsChrootProfile(p) {
+		return fmt.Errorf("invalid chroot profile %q: must be auto, none or one of %s", p, strings.Join(ChrootProfiles, ", "))
+	}
+
+	if c.GRPC.KeepAliveMinTime < 0 || c.GRPC.LogStreamHeartbeat < 0 || c.GRPC.LogStreamSendTimeout < 0 {
+		return fmt.Errorf("invalid grpc keepalive: keepAliveMinTime, logStreamHeartbeat and logStreamSendTimeout cannot be negative")
+	}
+
+	if c.Buffers.LogTailKB < 0 {
+		return fmt.Errorf("invalid log tail size: %d", c.Buffers.LogTailKB)
+	}
+
+	if c.WorkflowRegistry.MaxVersions < 0 {
+		return fmt.Errorf("invalid workflow registry max versions: %d", c.WorkflowRegistry.MaxVersions)
+	}
+
+	if err := c.Coordination.validate(); err != nil {
+		return err
+	}
+
+	if c.Retention.KeepLast < 0 || c.Retention.MaxAge < 0 {
+		return fmt.Errorf("invalid retention: keep_last and max_age cannot be negative")
+	}
+
+	if c.Retention.Enabled() && c.Retention.Interval <= 0 {
+		return fmt.Errorf("invalid retention interval: %v", c.Retention.Interval)
+	}
+
+	if h := c.Monitoring.History; h.Enabled && (h.Interval < time.Second || h.Retention < h.Interval) {
+		return fmt.Errorf("invalid monitoring history: interval must be at least 1s and retention at least the interval")
+	}
+
+	if c.LogSinks.Enabled && (c.LogSinks.FlushInterval <= 0 || c.LogSinks.MaxChunkBytes <= 0) {
+		return fmt.Errorf("invalid log sinks: flush_interval and max_chunk_bytes must be positive")
+	}
+
+	if err := c.validateScheduling(); err != nil {
+		return err
+	}
+
+	if (c.FairShare.Enabled || (c.Scheduling.Enabled && c.Scheduling.Policy == "fair_share")) && (c.FairShare.MaxRunningJobs < 0 || c.FairShare.Window <= 0 || c.FairShare.Slice <= 0 || c.FairShare.Slice > c.FairShare.Window) {
+		return fmt.Errorf("invalid fair share: max_running_jobs cannot be negative and slice must be positive and at most window")
+	}
+
+	if a := c.DurationAnomaly; a.Enabled {
+		if a.Threshold <= 0 || a.MinSamples < 2 || a.Samples < a.MinSamples || a.Interval < time.Second {
+			return fmt.Errorf("invalid duration anomaly: threshold must be positive, min_samples at least 2 and at most samples, and interval at least 1s")
+		}
+		if a.WebhookURL != "" {
+			if u, err := url.Parse(a.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid duration anomaly webhook_url %q: must be an http or https URL", a.WebhookURL)
+			}
+		}
+	}
+
+	if c.IPC.BatchSize < 0 {
+		return fmt.Errorf("invalid IPC batch size: %d", c.IPC.BatchSize)
+	}
+
+	if c.IPC.FlushInterval < 0 {
+		return fmt.Errorf("invalid IPC flush interval: %v", c.IPC.FlushInterval)
+	}
+
+	if err := c.RateLimit.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Redaction.validate(); err != nil {
+		return err
+	}
+
+	if l := c.OutputLimits; l.RateBytesPerSec < 0 || l.BurstBytes < 0 || l.MaxBytes < 0 {
+		return fmt.Errorf("invalid output limits: rate %d, burst %d and max %d bytes cannot be negative", l.RateBytesPerSec, l.BurstBytes, l.MaxBytes)
+	}
+
+	if c.UploadSync.MaxSizeMB < 0 {
+		return fmt.Errorf("invalid upload sync max size: %d", c.UploadSync.MaxSizeMB)
+	}
+
+	if c.UploadScan.Enabled && c.UploadScan.Command == "" {
+		return fmt.Errorf("upload_scan.command is required when upload scanning is enabled")
+	}
+
+	for i, key := range c.Signing.TrustedKeys {
+		if _, err := jobsign.ParsePublicKey(key); err != nil {
+			return fmt.Errorf("invalid signing trusted key %d: %w", i+1, err)
+		}
+	}
+
+	if err := c.validateTenants(); err != nil {
+		return err
+	}
+
+	if err := c.validateIdentity(); err != nil {
+		return err
+	}
+
+	if err := c.validateGitSources(); err != nil {
+		return err
+	}
+
+	if err := c.validateRuntimeAliases(); err != nil {
+		return err
+	}
+
+	if err := c.validateRuntimeDetection(); err != nil {
+		return err
+	}
+
+	if c.Runtime.Channels.MinSamples < 0 {
+		return fmt.Errorf("invalid runtime channels min samples: %d", c.Runtime.Channels.MinSamples)
+	}
+	if r := c.Runtime.Channels.MaxRateIncrease; r < 0 || r > 1 {
+		return fmt.Errorf("invalid runtime channels max rate increase: %v (must be between 0 and 1)", r)
+	}
+
+	if err := c.validateHA(); err != nil {
+		return err
+	}
+
+	if err := c.validateMaintenance(); err != nil {
+		return err
+	}
+
+	if err := c.validateDigest(); err != nil {
+		return err
+	}
+
+	if len(c.Clock.NTPServers) > 0 && c.Clock.CheckInterval < time.Minute {
+		return fmt.Errorf("invalid clock check_interval: must be at least 1m, got %v", c.Clock.CheckInterval)
+	}
+	if c.Clock.MaxDrift < 0 {
+		return fmt.Errorf("invalid clock max_drift: %v", c.Clock.MaxDrift)
+	}
+
+	switch c.Execution.Backend {
+	case "", ExecutionBackendNamespace:
+	case ExecutionBackendContainer:
+		if c.Execution.Container.Runtime == "" || c.Execution.Container.Image == "" {
+			return fmt.Errorf("invalid execution container backend: runtime and image are required")
+		}
+	default:
+		return fmt.Errorf("invalid execution backend %q: must be namespace or container", c.Execution.Backend)
+	}
+
+	if err := c.validateHooks(); err != nil {
+		return err
+	}
+
+	if err := c.validateJobIPC(); err != nil {
+		return err
+	}
+
+	if err := c.validateInputs(); err != nil {
+		return err
+	}
+
+	if err := c.validateOutputTargets(); err != nil {
+		return err
+	}
+
 	// Note: We don't validate certificates here as they might be populated later
 	// Certificate validation happens in GetServerTLSConfig()
 
@@ -530,6 +1614,20 @@ func (c *Config) Validate() error {
 	if !filepath.IsAbs(c.Cgroup.BaseDir) {
 		return fmt.Errorf("cgroup base directory must be absolute path: %s", c.Cgroup.BaseDir)
 	}
+	switch c.Cgroup.Driver {
+	case "", CgroupDriverCgroupfs:
+	case CgroupDriverSystemd:
+		if _, err := sdbus.SliceDir(c.Cgroup.Slice); err != nil || c.Cgroup.Slice == "-.slice" {
+			return fmt.Errorf("cgroup slice must name a slice below the root, such as joblet-jobs.slice: %q", c.Cgroup.Slice)
+		}
+	default:
+		return fmt.Errorf("invalid cgroup driver %q: expected %s or %s", c.Cgroup.Driver, CgroupDriverCgroupfs, CgroupDriverSystemd)
+	}
+	for _, name := range c.Cgroup.AllowedParams {
+		if !cgroupParamPattern.MatchString(name) || strings.HasPrefix(name, "cgroup.") {
+			return fmt.Errorf("invalid cgroup allowedParams entry %q: expected a controller file such as cpu.weight", name)
+		}
+	}
 
 	// Validate logging level
 	validLevels := map[string]bool{
@@ -543,6 +1641,581 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validate checks an enabled coordination section; a node whose TTL is not
+// longer than its heartbeat interval would keep dropping out of the listing.
+func (c *CoordinationConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Backend {
+	case "state", "etcd":
+	default:
+		return fmt.Errorf("invalid coordination backend: %q (must be state or etcd)", c.Backend)
+	}
+	if c.Endpoint == "" {
+		return fmt.Errorf("coordination.endpoint is required when coordination is enabled")
+	}
+	if c.HeartbeatInterval <= 0 {
+		return fmt.Errorf("invalid coordination heartbeat interval: %v", c.HeartbeatInterval)
+	}
+	if c.TTL <= c.HeartbeatInterval {
+		return fmt.Errorf("coordination ttl (%v) must be longer than the heartbeat interval (%v)", c.TTL, c.HeartbeatInterval)
+	}
+	if c.Backend == "state" && (c.ClientCert == "" || c.ClientKey == "") {
+		return fmt.Errorf("coordination.client_cert and client_key are required for the state backend")
+	}
+	return nil
+}
+
+// validate checks that an enabled rate limiter can admit at least one call
+// and that per-client overrides are not negative.
+func (r *RateLimitConfig) validate() error {
+	if !r.Enabled {
+		return nil
+	}
+
+	if r.RequestsPerSecond <= 0 || r.Burst < 1 {
+		return fmt.Errorf("invalid rate limit: %v requests/s with burst %d", r.RequestsPerSecond, r.Burst)
+	}
+
+	if r.RunJobPerSecond <= 0 || r.RunJobBurst < 1 {
+		return fmt.Errorf("invalid run job rate limit: %v requests/s with burst %d", r.RunJobPerSecond, r.RunJobBurst)
+	}
+
+	if r.MaxStreams < 0 {
+		return fmt.Errorf("invalid max streams: %d", r.MaxStreams)
+	}
+
+	for client, quota := range r.Clients {
+		if quota.RequestsPerSecond < 0 || quota.Burst < 0 || quota.RunJobPerSecond < 0 ||
+			quota.RunJobBurst < 0 || quota.MaxStreams < 0 {
+			return fmt.Errorf("invalid rate limit quota for client %q: values must not be negative", client)
+		}
+	}
+
+	return nil
+}
+
+// validateTenants checks that tenant names and clients are unique and that
+// AWS roles can be assumed
+func (c *Config) validateTenants() error {
+	names := make(map[string]bool)
+	clients := make(map[string]string)
+	for _, tenant := range c.Tenants {
+		if tenant.Name == "" {
+			return fmt.Errorf("tenant name must not be empty")
+		}
+		if names[tenant.Name] {
+			return fmt.Errorf("duplicate tenant %q", tenant.Name)
+		}
+		names[tenant.Name] = true
+
+		if tenant.Weight < 0 {
+			return fmt.Errorf("tenant %q: weight cannot be negative", tenant.Name)
+		}
+		for _, pattern := range slices.Concat(tenant.Volumes, tenant.Runtimes) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("tenant %q: invalid pattern %q: %w", tenant.Name, pattern, err)
+			}
+		}
+
+		for _, client := range tenant.Clients {
+			if other, ok := clients[client]; ok {
+				return fmt.Errorf("client %q belongs to tenants %q and %q", client, other, tenant.Name)
+			}
+			clients[client] = tenant.Name
+		}
+
+		role := tenant.AWSRole
+		if role.RoleARN == "" {
+			continue
+		}
+		if !strings.HasPrefix(role.RoleARN, "arn:") {
+			return fmt.Errorf("tenant %q: invalid role_arn %q", tenant.Name, role.RoleARN)
+		}
+		// STS limits
+		if role.Duration != 0 && (role.Duration < 15*time.Minute || role.Duration > 12*time.Hour) {
+			return fmt.Errorf("tenant %q: aws_role duration must be between 15m and 12h, got %v", tenant.Name, role.Duration)
+		}
+		if c.CloudCredentials.Command == "" {
+			return fmt.Errorf("cloud_credentials.command is required when a tenant has an aws_role")
+		}
+	}
+	return nil
+}
+
+// validateIdentity checks that the identity helper is set and that every
+// mapping grants a known role and tenant
+func (c *Config) validateIdentity() error {
+	id := c.Identity
+	if !id.Enabled {
+		return nil
+	}
+	if id.Command == "" {
+		return fmt.Errorf("identity.command is required when the identity provider is enabled")
+	}
+	if id.Timeout <= 0 || id.CacheTTL < 0 {
+		return fmt.Errorf("invalid identity: timeout must be positive and cache_ttl cannot be negative")
+	}
+	for i, m := range id.Mappings {
+		if m.Principal == "" && m.Group == "" {
+			return fmt.Errorf("identity mapping %d: principal or group is required", i+1)
+		}
+		if _, err := path.Match(m.Principal, ""); err != nil {
+			return fmt.Errorf("identity mapping %d: invalid principal pattern %q", i+1, m.Principal)
+		}
+		if m.Role != "admin" && m.Role != "viewer" {
+			return fmt.Errorf("identity mapping %d: role must be admin or viewer, got %q", i+1, m.Role)
+		}
+		if _, ok := c.Tenant(m.Tenant); m.Tenant != "" && !ok {
+			return fmt.Errorf("identity mapping %d: unknown tenant %q", i+1, m.Tenant)
+		}
+	}
+	return nil
+}
+
+// validateGitSources checks that an enabled git source can fetch something
+func (c *Config) validateGitSources() error {
+	g := c.GitSources
+	if !g.Enabled {
+		return nil
+	}
+	if g.Command == "" || g.WorkDir == "" {
+		return fmt.Errorf("git_sources.command and git_sources.work_dir are required when git sources are enabled")
+	}
+	if g.Timeout <= 0 || g.MaxSizeMB < 0 {
+		return fmt.Errorf("invalid git_sources: timeout must be positive and max_size_mb cannot be negative")
+	}
+	if len(g.Repositories) == 0 {
+		return fmt.Errorf("git_sources.repositories must allow at least one repository")
+	}
+	for i, repo := range g.Repositories {
+		if repo.URL == "" || strings.HasPrefix(repo.URL, "-") {
+			return fmt.Errorf("git_sources repository %d: invalid url %q", i+1, repo.URL)
+		}
+	}
+	return nil
+}
+
+// validateRuntimeAliases checks that aliases and tenant pins name an exact
+// runtime. Aliases resolve once, so a target cannot be an alias itself.
+func (c *Config) validateRuntimeAliases() error {
+	check := func(owner string, aliases map[string]string) error {
+		for name, target := range aliases {
+			if name == "" || target == "" {
+				return fmt.Errorf("%s: runtime alias %q -> %q needs a name and a target", owner, name, target)
+			}
+			if _, isAlias := c.Runtime.Aliases[target]; isAlias && target != name {
+				return fmt.Errorf("%s: runtime alias %q points to alias %q, aliases must name an exact runtime", owner, name, target)
+			}
+		}
+		return nil
+	}
+	if err := check("runtime.aliases", c.Runtime.Aliases); err != nil {
+		return err
+	}
+	for _, tenant := range c.Tenants {
+		if err := check(fmt.Sprintf("tenant %q runtime_pins", tenant.Name), tenant.RuntimePins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRuntimeDetection checks the runtime detection policy and that
+// installs are only asked for when the runtime is selected
+func (c *Config) validateRuntimeDetection() error {
+	d := c.Runtime.Detection
+	switch d.Policy {
+	case "", "off", "suggest", "auto":
+	default:
+		return fmt.Errorf("invalid runtime.detection.policy %q: expected off, suggest or auto", d.Policy)
+	}
+	if d.Install && d.Policy != "auto" {
+		return fmt.Errorf("runtime.detection.install needs policy auto, got %s", d.Policy)
+	}
+	for file, runtime := range d.Runtimes {
+		if file == "" || runtime == "" {
+			return fmt.Errorf("runtime.detection.runtimes: %q -> %q needs a dependency file and a runtime", file, runtime)
+		}
+	}
+	return nil
+}
+
+// validateHA checks an enabled ha section. The lease is renewed three times
+// per TTL, so a TTL under a second would mean renewals faster than the
+// state service is meant to be asked.
+func (c *Config) validateHA() error {
+	if !c.HA.Enabled {
+		return nil
+	}
+	if c.HA.Standbys < 1 {
+		return fmt.Errorf("ha.standbys must be at least 1, got %d", c.HA.Standbys)
+	}
+	if c.HA.LeaseTTL < time.Second {
+		return fmt.Errorf("ha.lease_ttl must be at least 1s, got %v", c.HA.LeaseTTL)
+	}
+	return nil
+}
+
+// validateMaintenance checks the maintenance windows and their notifications
+func (c *Config) validateMaintenance() error {
+	m := c.Maintenance
+	names := make(map[string]bool, len(m.Windows))
+	for _, w := range m.Windows {
+		if w.Name == "" || names[w.Name] {
+			return fmt.Errorf("invalid maintenance window %q: names must be set and unique", w.Name)
+		}
+		names[w.Name] = true
+		if strings.HasPrefix(strings.TrimSpace(w.Schedule), "@every") {
+			return fmt.Errorf("invalid maintenance window %q: schedule must be a cron expression, not @every", w.Name)
+		}
+		if _, err := cron.Parse(w.Schedule); err != nil {
+			return fmt.Errorf("invalid maintenance window %q: %w", w.Name, err)
+		}
+		if w.Duration < time.Minute {
+			return fmt.Errorf("invalid maintenance window %q: duration must be at least 1m, got %v", w.Name, w.Duration)
+		}
+	}
+	if m.NoticeBefore < 0 {
+		return fmt.Errorf("invalid maintenance notice_before: %v", m.NoticeBefore)
+	}
+	if m.WebhookURL != "" {
+		if u, err := url.Parse(m.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid maintenance webhook_url %q: must be an http or https URL", m.WebhookURL)
+		}
+	}
+	return nil
+}
+
+// validateDigest checks the workflow digests and where they are sent
+func (c *Config) validateDigest() error {
+	d := c.Digest
+	names := make(map[string]bool, len(d.Digests))
+	for _, w := range d.Digests {
+		if w.Name == "" || names[w.Name] {
+			return fmt.Errorf("invalid digest %q: names must be set and unique", w.Name)
+		}
+		names[w.Name] = true
+		if key, _, _ := strings.Cut(w.Label, "="); strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid digest %q: label must be key=value or key, got %q", w.Name, w.Label)
+		}
+		if strings.HasPrefix(strings.TrimSpace(w.Schedule), "@every") {
+			return fmt.Errorf("invalid digest %q: schedule must be a cron expression, not @every", w.Name)
+		}
+		if _, err := cron.Parse(w.Schedule); err != nil {
+			return fmt.Errorf("invalid digest %q: %w", w.Name, err)
+		}
+		if w.Window != 0 && w.Window < time.Minute {
+			return fmt.Errorf("invalid digest %q: window must be at least 1m, got %v", w.Name, w.Window)
+		}
+		if err := validateWebhookURL(w.WebhookURL); err != nil {
+			return fmt.Errorf("invalid digest %q: %w", w.Name, err)
+		}
+	}
+	if err := validateWebhookURL(d.WebhookURL); err != nil {
+		return fmt.Errorf("invalid digest: %w", err)
+	}
+	return nil
+}
+
+// validateWebhookURL checks a webhook URL, which may be left empty
+func validateWebhookURL(webhook string) error {
+	if webhook == "" {
+		return nil
+	}
+	if u, err := url.Parse(webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook_url %q must be an http or https URL", webhook)
+	}
+	return nil
+}
+
+// schedulingPolicyPattern is the shape of a scheduling policy name; whether a
+// policy of that name is compiled in is checked when the server starts
+var schedulingPolicyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateScheduling checks the admission scheduling settings
+func (c *Config) validateScheduling() error {
+	s := c.Scheduling
+	if !s.Enabled {
+		return nil
+	}
+	if !schedulingPolicyPattern.MatchString(s.Policy) {
+		return fmt.Errorf("invalid scheduling policy %q: expected a name such as fifo, priority, fair_share or bin_packing", s.Policy)
+	}
+	if s.MaxRunningJobs < 0 {
+		return fmt.Errorf("invalid scheduling max_running_jobs: %d", s.MaxRunningJobs)
+	}
+	if c.FairShare.Enabled && s.Policy != "fair_share" {
+		return fmt.Errorf("fair_share.enabled selects the fair_share policy but scheduling.policy is %q: disable one of them", s.Policy)
+	}
+	return nil
+}
+
+// MaxHookTimeout is the longest a hook may run; pre_start hooks hold up the
+// job's start for that long
+const MaxHookTimeout = 5 * time.Minute
+
+// validateHooks checks the job lifecycle hooks
+func (c *Config) validateHooks() error {
+	for phase, hooks := range map[string][]HookConfig{"pre_start": c.Hooks.PreStart, "post_stop": c.Hooks.PostStop} {
+		names := make(map[string]bool, len(hooks))
+		for _, h := range hooks {
+			if h.Name == "" || names[h.Name] {
+				return fmt.Errorf("invalid %s hook %q: names must be set and unique", phase, h.Name)
+			}
+			names[h.Name] = true
+			if !filepath.IsAbs(h.Command) {
+				return fmt.Errorf("invalid %s hook %q: command must be an absolute path, got %q", phase, h.Name, h.Command)
+			}
+			if h.Timeout < 0 || h.Timeout > MaxHookTimeout {
+				return fmt.Errorf("invalid %s hook %q: timeout must be between 0 and %v, got %v", phase, h.Name, MaxHookTimeout, h.Timeout)
+			}
+			if h.Required && phase != "pre_start" {
+				return fmt.Errorf("invalid %s hook %q: only pre_start hooks can be required", phase, h.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ipcChannelPattern is the shape of an IPC channel name, which names its
+// directory under job_ipc.base_dir
+var ipcChannelPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+// validateJobIPC checks the job IPC channel settings
+func (c *Config) validateJobIPC() error {
+	if !c.JobIPC.Enabled {
+		return nil
+	}
+	if !filepath.IsAbs(c.JobIPC.BaseDir) {
+		return fmt.Errorf("invalid job_ipc base_dir %q: must be an absolute path", c.JobIPC.BaseDir)
+	}
+	if c.JobIPC.SizeBytes <= 0 {
+		return fmt.Errorf("invalid job_ipc size_bytes: %d", c.JobIPC.SizeBytes)
+	}
+	names := make(map[string]bool, len(c.JobIPC.Channels))
+	for _, ch := range c.JobIPC.Channels {
+		if !ipcChannelPattern.MatchString(ch.Name) || names[ch.Name] {
+			return fmt.Errorf("invalid job_ipc channel %q: names must be unique letters, digits, '.', '_' or '-'", ch.Name)
+		}
+		names[ch.Name] = true
+		if ch.SizeBytes < 0 {
+			return fmt.Errorf("invalid job_ipc channel %q: size_bytes cannot be negative", ch.Name)
+		}
+	}
+	return nil
+}
+
+// validateInputs checks the job input settings
+func (c *Config) validateInputs() error {
+	if !c.Inputs.Enabled {
+		return nil
+	}
+	if !filepath.IsAbs(c.Inputs.CacheDir) {
+		return fmt.Errorf("invalid inputs cache_dir %q: must be an absolute path", c.Inputs.CacheDir)
+	}
+	if c.Inputs.MaxCacheBytes < 0 {
+		return fmt.Errorf("invalid inputs max_cache_bytes: %d", c.Inputs.MaxCacheBytes)
+	}
+	if c.Inputs.MaxObjectBytes <= 0 {
+		return fmt.Errorf("invalid inputs max_object_bytes: %d", c.Inputs.MaxObjectBytes)
+	}
+	if c.Inputs.Timeout <= 0 {
+		return fmt.Errorf("invalid inputs timeout: %v", c.Inputs.Timeout)
+	}
+	return nil
+}
+
+// maxPutBytes is the largest object a single S3 PUT request may create
+const maxPutBytes = 5 << 30
+
+// validateOutputTargets checks the job output target settings
+func (c *Config) validateOutputTargets() error {
+	o := c.OutputTargets
+	if !o.Enabled {
+		return nil
+	}
+	if o.MaxFiles <= 0 {
+		return fmt.Errorf("invalid output_targets max_files: %d", o.MaxFiles)
+	}
+	if o.MaxObjectBytes <= 0 || o.MaxObjectBytes > maxPutBytes {
+		return fmt.Errorf("invalid output_targets max_object_bytes: %d (must be between 1 and %d)", o.MaxObjectBytes, int64(maxPutBytes))
+	}
+	if o.Retries < 0 || o.RetryDelay < 0 {
+		return fmt.Errorf("invalid output_targets retries: %d, retry_delay: %v", o.Retries, o.RetryDelay)
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("invalid output_targets timeout: %v", o.Timeout)
+	}
+	return nil
+}
+
+// JobIPCChannel returns the host directory and size of the IPC channel a
+// tenant's job asks to join, or why it may not. Channels named in
+// job_ipc.channels live under shared/ and admit their tenants; other names live
+// under a directory of the tenant's own so tenants never meet by accident.
+func (c *Config) JobIPCChannel(tenant, name string) (dir string, sizeBytes int64, err error) {
+	if !c.JobIPC.Enabled {
+		return "", 0, fmt.Errorf("ipc channels are disabled on this node (job_ipc.enabled)")
+	}
+	if !ipcChannelPattern.MatchString(name) {
+		return "", 0, fmt.Errorf("invalid ipc channel name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	for _, ch := range c.JobIPC.Channels {
+		if ch.Name != name {
+			continue
+		}
+		if len(ch.Tenants) > 0 && !slices.Contains(ch.Tenants, tenant) {
+			return "", 0, fmt.Errorf("ipc channel %q does not admit tenant %q", name, tenant)
+		}
+		sizeBytes = ch.SizeBytes
+		if sizeBytes == 0 {
+			sizeBytes = c.JobIPC.SizeBytes
+		}
+		return filepath.Join(c.JobIPC.BaseDir, "shared", name), sizeBytes, nil
+	}
+	sum := sha256.Sum256([]byte(tenant))
+	return filepath.Join(c.JobIPC.BaseDir, "tenants", hex.EncodeToString(sum[:6]), name), c.JobIPC.SizeBytes, nil
+}
+
+// StateStandbys returns how many standby state processes run: none without
+// ha or with a backend that isn't shared between processes
+func (c *Config) StateStandbys() int {
+	if !c.HA.Enabled || c.State.Backend == "" || c.State.Backend == "memory" {
+		return 0
+	}
+	return c.HA.Standbys
+}
+
+// PersistStandbys returns how many standby persist processes run
+func (c *Config) PersistStandbys() int {
+	if !c.HA.Enabled {
+		return 0
+	}
+	return c.HA.Standbys
+}
+
+// ResolveRuntime returns the exact runtime a tenant's job asking for spec
+// runs on: the tenant's pin for it, else the server alias, else spec itself.
+// pinnedBy describes where the resolution came from, empty when spec was not
+// an alias.
+func (c *Config) ResolveRuntime(tenant, spec string) (resolved, pinnedBy string) {
+	if spec == "" {
+		return spec, ""
+	}
+	if target, pinnedBy, pinned := c.TenantRuntimePin(tenant, spec); pinned {
+		return target, pinnedBy
+	}
+	if target, aliased := c.Runtime.Aliases[spec]; aliased && target != spec {
+		return target, "server alias"
+	}
+	return spec, ""
+}
+
+// TenantRuntimePin returns the exact runtime a tenant pinned spec to
+func (c *Config) TenantRuntimePin(tenant, spec string) (target, pinnedBy string, pinned bool) {
+	if t, ok := c.Tenant(tenant); ok && tenant != "" {
+		if target, pinned := t.RuntimePins[spec]; pinned && target != spec {
+			return target, fmt.Sprintf("tenant %s pin", tenant), true
+		}
+	}
+	return "", "", false
+}
+
+// RuntimeAliases returns the runtime names a tenant's jobs may ask for and
+// the exact runtime each resolves to
+func (c *Config) RuntimeAliases(tenant string) map[string]string {
+	aliases := make(map[string]string, len(c.Runtime.Aliases))
+	for name := range c.Runtime.Aliases {
+		aliases[name], _ = c.ResolveRuntime(tenant, name)
+	}
+	if t, ok := c.Tenant(tenant); ok && tenant != "" {
+		for name := range t.RuntimePins {
+			aliases[name], _ = c.ResolveRuntime(tenant, name)
+		}
+	}
+	return aliases
+}
+
+// TenantOf returns the tenant a client belongs to, "" when it belongs to none
+func (c *Config) TenantOf(client string) string {
+	for _, tenant := range c.Tenants {
+		for _, name := range tenant.Clients {
+			if name == client {
+				return tenant.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Tenant returns the named tenant's configuration
+func (c *Config) Tenant(name string) (TenantConfig, bool) {
+	for _, tenant := range c.Tenants {
+		if tenant.Name == name {
+			return tenant, true
+		}
+	}
+	return TenantConfig{}, false
+}
+
+// TenantCanUseVolume reports whether a tenant's jobs may use a volume.
+// Clients outside any tenant, and tenants listing no volumes, use them all.
+func (c *Config) TenantCanUseVolume(tenant, volume string) bool {
+	t, ok := c.Tenant(tenant)
+	return !ok || tenant == "" || matchesAny(t.Volumes, volume)
+}
+
+// TenantCanUseRuntime reports whether a tenant's jobs may use a runtime, like
+// TenantCanUseVolume does for volumes
+func (c *Config) TenantCanUseRuntime(tenant, runtime string) bool {
+	t, ok := c.Tenant(tenant)
+	return !ok || tenant == "" || matchesAny(t.Runtimes, runtime)
+}
+
+// matchesAny reports whether name matches one of the glob patterns, or
+// whether there are none
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantWeights returns the fair-share weight of every tenant with one set
+func (c *Config) TenantWeights() map[string]float64 {
+	weights := make(map[string]float64)
+	for _, tenant := range c.Tenants {
+		if tenant.Weight > 0 {
+			weights[tenant.Name] = tenant.Weight
+		}
+	}
+	return weights
+}
+
+// validate checks that the redaction patterns compile and that matches are
+// replaced by something
+func (r *RedactionConfig) validate() error {
+	if r.Replacement == "" && (r.SecretValues || len(r.Patterns) > 0) {
+		return fmt.Errorf("redaction replacement must not be empty")
+	}
+	for _, pattern := range r.Patterns {
+		if pattern.Regex == "" {
+			return fmt.Errorf("redaction pattern %q has no regex", pattern.Name)
+		}
+		if _, err := regexp.Compile(pattern.Regex); err != nil {
+			return fmt.Errorf("invalid redaction pattern %q: %w", pattern.Name, err)
+		}
+	}
+	return nil
+}
+
 // LoadClientConfig loads RNX client configuration from the specified file.
 //
 //  1. Path from RNX_CONFIG environment variable
@@ -589,6 +2262,7 @@ func LoadClientConfig(configPath string) (*ClientConfig, error) {
 	if len(config.Nodes) == 0 {
 		return nil, fmt.Errorf("no nodes configured in %s", configPath)
 	}
+	config.Path = configPath
 
 	return &config, nil
 }
@@ -4,7 +4,9 @@ import (
 	"crypto/tls"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -132,6 +134,17 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid default CPU limit",
 		},
+		{
+			name: "negative infrastructure retries",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1, InfraRetries: -1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+			},
+			wantErr: true,
+			errMsg:  "invalid infrastructure retries",
+		},
 		{
 			name: "relative cgroup path",
 			config: Config{
@@ -154,6 +167,560 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid log level",
 		},
+		{
+			name: "enabled rate limit without a rate",
+			config: Config{
+				Server:    ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:    JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:    CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:   LoggingConfig{Level: "INFO"},
+				RateLimit: RateLimitConfig{Enabled: true, Burst: 10, RunJobPerSecond: 1, RunJobBurst: 1},
+			},
+			wantErr: true,
+			errMsg:  "invalid rate limit",
+		},
+		{
+			name: "invalid redaction pattern",
+			config: Config{
+				Server:    ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:    JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:    CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:   LoggingConfig{Level: "INFO"},
+				Redaction: RedactionConfig{Replacement: "***", Patterns: []RedactionPattern{{Name: "broken", Regex: "AKIA[0-9"}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid redaction pattern",
+		},
+		{
+			name: "negative output limit",
+			config: Config{
+				Server:       ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:       JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:       CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:      LoggingConfig{Level: "INFO"},
+				OutputLimits: OutputLimitsConfig{RateBytesPerSec: -1},
+			},
+			wantErr: true,
+			errMsg:  "invalid output limits",
+		},
+		{
+			name: "client in two tenants",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Tenants: []TenantConfig{
+					{Name: "analytics", Clients: []string{"ci"}},
+					{Name: "billing", Clients: []string{"ci"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "belongs to tenants",
+		},
+		{
+			name: "tenant volume pattern invalid",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Tenants: []TenantConfig{{Name: "analytics", Volumes: []string{"analytics-["}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid pattern",
+		},
+		{
+			name: "tenant role without credentials helper",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Tenants: []TenantConfig{
+					{Name: "analytics", AWSRole: AWSRoleConfig{RoleARN: "arn:aws:iam::123456789012:role/analytics"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "cloud_credentials.command is required",
+		},
+		{
+			name: "coordination ttl not longer than heartbeat",
+			config: Config{
+				Server:       ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:       JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:       CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:      LoggingConfig{Level: "INFO"},
+				Coordination: CoordinationConfig{Enabled: true, Backend: "etcd", Endpoint: "http://etcd:2379", HeartbeatInterval: 10 * time.Second, TTL: 10 * time.Second},
+			},
+			wantErr: true,
+			errMsg:  "must be longer than the heartbeat interval",
+		},
+		{
+			name: "coordination state backend without client certificate",
+			config: Config{
+				Server:       ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:       JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:       CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:      LoggingConfig{Level: "INFO"},
+				Coordination: CoordinationConfig{Enabled: true, Backend: "state", Endpoint: "state:50054", HeartbeatInterval: 10 * time.Second, TTL: 30 * time.Second},
+			},
+			wantErr: true,
+			errMsg:  "client_cert and client_key are required",
+		},
+		{
+			name: "retention without sweep interval",
+			config: Config{
+				Server:    ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:    JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:    CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:   LoggingConfig{Level: "INFO"},
+				Retention: RetentionConfig{KeepLast: 10},
+			},
+			wantErr: true,
+			errMsg:  "invalid retention interval",
+		},
+		{
+			name: "monitoring history shorter than its interval",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Monitoring: MonitoringConfig{History: MonitoringHistoryConfig{Enabled: true, Interval: time.Hour, Retention: time.Minute}},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitoring history",
+		},
+		{
+			name: "duration anomaly webhook without a scheme",
+			config: Config{
+				Server:          ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:          JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:          CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:         LoggingConfig{Level: "INFO"},
+				DurationAnomaly: DurationAnomalyConfig{Enabled: true, Threshold: 3, Samples: 30, MinSamples: 5, Interval: time.Minute, WebhookURL: "hooks.example.com/joblet"},
+			},
+			wantErr: true,
+			errMsg:  "invalid duration anomaly webhook_url",
+		},
+		{
+			name: "duration anomaly with one sample",
+			config: Config{
+				Server:          ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:          JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:          CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:         LoggingConfig{Level: "INFO"},
+				DurationAnomaly: DurationAnomalyConfig{Enabled: true, Threshold: 3, Samples: 30, MinSamples: 1, Interval: time.Minute},
+			},
+			wantErr: true,
+			errMsg:  "invalid duration anomaly",
+		},
+		{
+			name: "scratch device with a relative path",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Filesystem: FilesystemConfig{Scratch: map[string]ScratchConfig{"nvme": {Path: "mnt/nvme"}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid scratch device \"nvme\"",
+		},
+		{
+			name: "scratch device with a negative quota",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Filesystem: FilesystemConfig{Scratch: map[string]ScratchConfig{"nvme": {Path: "/mnt/nvme", QuotaBytes: -1}}},
+			},
+			wantErr: true,
+			errMsg:  "negative quotaBytes",
+		},
+		{
+			name: "negative freeze retention",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Filesystem: FilesystemConfig{FreezeRetention: -time.Hour},
+			},
+			wantErr: true,
+			errMsg:  "invalid freeze retention",
+		},
+		{
+			name: "negative workflow state retention",
+			config: Config{
+				Server:        ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:        JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:        CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:       LoggingConfig{Level: "INFO"},
+				WorkflowState: WorkflowStateConfig{Retention: -time.Hour},
+			},
+			wantErr: true,
+			errMsg:  "invalid workflow state retention",
+		},
+		{
+			name: "relative init binary",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Filesystem: FilesystemConfig{InitBinary: "bin/joblet-init"},
+			},
+			wantErr: true,
+			errMsg:  "path must be absolute",
+		},
+		{
+			name: "unknown execution backend",
+			config: Config{
+				Server:    ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:    JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:    CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:   LoggingConfig{Level: "INFO"},
+				Execution: ExecutionConfig{Backend: "wsl2"},
+			},
+			wantErr: true,
+			errMsg:  "must be namespace or container",
+		},
+		{
+			name: "container backend without image",
+			config: Config{
+				Server:    ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:    JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:    CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:   LoggingConfig{Level: "INFO"},
+				Execution: ExecutionConfig{Backend: ExecutionBackendContainer, Container: ContainerBackendConfig{Runtime: "podman"}},
+			},
+			wantErr: true,
+			errMsg:  "runtime and image are required",
+		},
+		{
+			name: "unknown chroot profile",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Filesystem: FilesystemConfig{ChrootProfile: "gentoo"},
+			},
+			wantErr: true,
+			errMsg:  "invalid chroot profile",
+		},
+		{
+			name: "negative upload sync size",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				UploadSync: UploadSyncConfig{Enabled: true, MaxSizeMB: -1},
+			},
+			wantErr: true,
+			errMsg:  "invalid upload sync max size",
+		},
+		{
+			name: "log sinks without flush interval",
+			config: Config{
+				Server:   ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:   JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:   CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:  LoggingConfig{Level: "INFO"},
+				LogSinks: LogSinksConfig{Enabled: true, MaxChunkBytes: 1024},
+			},
+			wantErr: true,
+			errMsg:  "invalid log sinks",
+		},
+		{
+			name: "fair share slice longer than window",
+			config: Config{
+				Server:    ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:    JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:    CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:   LoggingConfig{Level: "INFO"},
+				FairShare: FairShareConfig{Enabled: true, Window: time.Minute, Slice: time.Hour},
+			},
+			wantErr: true,
+			errMsg:  "invalid fair share",
+		},
+		{
+			name: "scheduling policy with an invalid name",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				Scheduling: SchedulingConfig{Enabled: true, Policy: "Round Robin"},
+			},
+			wantErr: true,
+			errMsg:  "invalid scheduling policy",
+		},
+		{
+			name: "scheduling policy conflicting with fair share",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				FairShare:  FairShareConfig{Enabled: true, Window: time.Hour, Slice: time.Minute},
+				Scheduling: SchedulingConfig{Enabled: true, Policy: "priority"},
+			},
+			wantErr: true,
+			errMsg:  "disable one of them",
+		},
+		{
+			name: "job ipc with a relative base dir",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				JobIPC:  JobIPCConfig{Enabled: true, BaseDir: "ipc", SizeBytes: 1024},
+			},
+			wantErr: true,
+			errMsg:  "invalid job_ipc base_dir",
+		},
+		{
+			name: "inputs without an object size limit",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Inputs:  InputsConfig{Enabled: true, CacheDir: "/var/cache/joblet", Timeout: time.Minute},
+			},
+			wantErr: true,
+			errMsg:  "invalid inputs max_object_bytes",
+		},
+		{
+			name: "output targets over the single PUT limit",
+			config: Config{
+				Server:        ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:        JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:        CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:       LoggingConfig{Level: "INFO"},
+				OutputTargets: OutputTargetsConfig{Enabled: true, MaxFiles: 10, MaxObjectBytes: 6 << 30, Timeout: time.Minute},
+			},
+			wantErr: true,
+			errMsg:  "invalid output_targets max_object_bytes",
+		},
+		{
+			name: "runtime alias to an alias",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Runtime: RuntimeConfig{Aliases: map[string]string{"python": "py", "py": "python-3.11@1.2.0"}},
+			},
+			wantErr: true,
+			errMsg:  "points to alias",
+		},
+		{
+			name: "tenant runtime pin without target",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Tenants: []TenantConfig{{Name: "ml", RuntimePins: map[string]string{"python": ""}}},
+			},
+			wantErr: true,
+			errMsg:  "needs a name and a target",
+		},
+		{
+			name: "negative log stream heartbeat",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				GRPC:    GRPCConfig{LogStreamHeartbeat: -time.Second},
+			},
+			wantErr: true,
+			errMsg:  "invalid grpc keepalive",
+		},
+		{
+			name: "cgroup core file allowed",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup", AllowedParams: []string{"cpu.weight", "cgroup.procs"}},
+				Logging: LoggingConfig{Level: "INFO"},
+			},
+			wantErr: true,
+			errMsg:  "invalid cgroup allowedParams",
+		},
+		{
+			name: "unknown cgroup driver",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup", Driver: "docker"},
+				Logging: LoggingConfig{Level: "INFO"},
+			},
+			wantErr: true,
+			errMsg:  "invalid cgroup driver",
+		},
+		{
+			name: "systemd driver without a slice",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup", Driver: CgroupDriverSystemd, Slice: "joblet.service"},
+				Logging: LoggingConfig{Level: "INFO"},
+			},
+			wantErr: true,
+			errMsg:  "cgroup slice must name a slice",
+		},
+		{
+			name: "identity mapping with unknown role",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Identity: IdentityConfig{
+					Enabled: true, Command: "/usr/lib/joblet/identity-helper", Timeout: 10 * time.Second,
+					Mappings: []IdentityMapping{{Group: "ml-engineers", Role: "operator"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "role must be admin or viewer",
+		},
+		{
+			name: "git sources without repositories",
+			config: Config{
+				Server:     ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:     JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:     CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging:    LoggingConfig{Level: "INFO"},
+				GitSources: GitSourcesConfig{Enabled: true, Command: "git", WorkDir: "/opt/joblet/git", Timeout: time.Minute},
+			},
+			wantErr: true,
+			errMsg:  "must allow at least one repository",
+		},
+		{
+			name: "runtime detection install without auto",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Runtime: RuntimeConfig{Detection: RuntimeDetectionConfig{Policy: "suggest", Install: true}},
+			},
+			wantErr: true,
+			errMsg:  "needs policy auto",
+		},
+		{
+			name: "ha lease ttl too short",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				HA:      HAConfig{Enabled: true, Standbys: 1, LeaseTTL: 100 * time.Millisecond},
+			},
+			wantErr: true,
+			errMsg:  "ha.lease_ttl must be at least 1s",
+		},
+		{
+			name: "maintenance window with invalid schedule",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Maintenance: MaintenanceConfig{Windows: []MaintenanceWindow{
+					{Name: "patching", Schedule: "0 25 * * *", Duration: time.Hour},
+				}},
+			},
+			wantErr: true,
+			errMsg:  `invalid maintenance window "patching"`,
+		},
+		{
+			name: "maintenance window too short",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Maintenance: MaintenanceConfig{Windows: []MaintenanceWindow{
+					{Name: "patching", Schedule: "0 3 * * 0", Duration: time.Second},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "duration must be at least 1m",
+		},
+		{
+			name: "digest without a label",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Digest: DigestConfig{Digests: []WorkflowDigest{
+					{Name: "nightly", Label: "=nightly", Schedule: "0 7 * * *"},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "label must be key=value or key",
+		},
+		{
+			name: "clock checked too often",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Clock:   ClockConfig{NTPServers: []string{"pool.ntp.org"}, CheckInterval: time.Second},
+			},
+			wantErr: true,
+			errMsg:  "invalid clock check_interval",
+		},
+		{
+			name: "digest with an invalid webhook",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Digest: DigestConfig{Digests: []WorkflowDigest{
+					{Name: "nightly", Label: "schedule=nightly", Schedule: "0 7 * * *", WebhookURL: "ftp://hooks.example.com"},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "must be an http or https URL",
+		},
+		{
+			name: "hook with relative command",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Hooks:   HooksConfig{PreStart: []HookConfig{{Name: "cmdb", Command: "cmdb-register"}}},
+			},
+			wantErr: true,
+			errMsg:  "command must be an absolute path",
+		},
+		{
+			name: "required post_stop hook",
+			config: Config{
+				Server:  ServerConfig{Port: 50051, Mode: "server"},
+				Joblet:  JobletConfig{MaxConcurrentJobs: 1},
+				Cgroup:  CgroupConfig{BaseDir: "/sys/fs/cgroup"},
+				Logging: LoggingConfig{Level: "INFO"},
+				Hooks:   HooksConfig{PostStop: []HookConfig{{Name: "cmdb", Command: "/usr/local/bin/cmdb", Required: true}}},
+			},
+			wantErr: true,
+			errMsg:  "only pre_start hooks can be required",
+		},
 	}
 
 	for _, tt := range tests {
@@ -171,6 +738,81 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestAdmissionPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            Config
+		wantPolicy     string
+		wantMaxRunning int
+	}{
+		{
+			name: "off by default",
+			cfg:  Config{Joblet: JobletConfig{MaxConcurrentJobs: 100}, Scheduling: SchedulingConfig{Policy: "fifo"}},
+		},
+		{
+			name:           "scheduling falls back to maxConcurrentJobs",
+			cfg:            Config{Joblet: JobletConfig{MaxConcurrentJobs: 100}, Scheduling: SchedulingConfig{Enabled: true, Policy: "bin_packing"}},
+			wantPolicy:     "bin_packing",
+			wantMaxRunning: 100,
+		},
+		{
+			name:           "fair share enables its policy",
+			cfg:            Config{Joblet: JobletConfig{MaxConcurrentJobs: 100}, FairShare: FairShareConfig{Enabled: true, MaxRunningJobs: 8}},
+			wantPolicy:     "fair_share",
+			wantMaxRunning: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, maxRunning := tt.cfg.AdmissionPolicy()
+			if policy != tt.wantPolicy || maxRunning != tt.wantMaxRunning {
+				t.Errorf("AdmissionPolicy() = %q, %d, want %q, %d", policy, maxRunning, tt.wantPolicy, tt.wantMaxRunning)
+			}
+		})
+	}
+}
+
+func TestJobIPCChannel(t *testing.T) {
+	cfg := Config{JobIPC: JobIPCConfig{
+		Enabled:   true,
+		BaseDir:   "/opt/joblet/ipc",
+		SizeBytes: 1024,
+		Channels: []JobIPCChannelConfig{
+			{Name: "arrow", Tenants: []string{"ml", "etl"}, SizeBytes: 4096},
+			{Name: "open"},
+		},
+	}}
+
+	dir, size, err := cfg.JobIPCChannel("etl", "arrow")
+	if err != nil || dir != "/opt/joblet/ipc/shared/arrow" || size != 4096 {
+		t.Errorf("declared channel = %q, %d, %v", dir, size, err)
+	}
+	if _, _, err := cfg.JobIPCChannel("web", "arrow"); err == nil || !strings.Contains(err.Error(), "does not admit") {
+		t.Errorf("tenant outside the channel's list: error = %v", err)
+	}
+	if dir, size, err := cfg.JobIPCChannel("web", "open"); err != nil || dir != "/opt/joblet/ipc/shared/open" || size != 1024 {
+		t.Errorf("channel open to every tenant = %q, %d, %v", dir, size, err)
+	}
+
+	// Undeclared channels are private to the tenant
+	mlDir, _, _ := cfg.JobIPCChannel("ml", "scratch")
+	webDir, _, _ := cfg.JobIPCChannel("web", "scratch")
+	if mlDir == webDir || !strings.HasPrefix(mlDir, "/opt/joblet/ipc/tenants/") {
+		t.Errorf("tenant channels = %q and %q", mlDir, webDir)
+	}
+
+	for _, name := range []string{"", "../etc", "a/b"} {
+		if _, _, err := cfg.JobIPCChannel("ml", name); err == nil {
+			t.Errorf("expected error for channel name %q", name)
+		}
+	}
+	cfg.JobIPC.Enabled = false
+	if _, _, err := cfg.JobIPCChannel("ml", "arrow"); err == nil {
+		t.Error("expected error with ipc channels disabled")
+	}
+}
+
 func TestGetServerTLSConfig(t *testing.T) {
 	// Valid certificates for testing (self-signed)
 	validCert := `-----BEGIN CERTIFICATE-----
@@ -457,3 +1099,74 @@ func containsMiddle(s, substr string) bool {
 	}
 	return false
 }
+
+func TestResolveRuntime(t *testing.T) {
+	cfg := Config{
+		Runtime: RuntimeConfig{Aliases: map[string]string{"python": "python-3.11@1.2.0", "java": "openjdk-21"}},
+		Tenants: []TenantConfig{{Name: "ml", RuntimePins: map[string]string{"python": "python-3.11-ml@2.0.0"}}},
+	}
+
+	tests := []struct {
+		tenant, spec       string
+		resolved, pinnedBy string
+	}{
+		{"", "python", "python-3.11@1.2.0", "server alias"},
+		{"web", "python", "python-3.11@1.2.0", "server alias"},
+		{"ml", "python", "python-3.11-ml@2.0.0", "tenant ml pin"},
+		{"ml", "java", "openjdk-21", "server alias"},
+		{"ml", "openjdk-21", "openjdk-21", ""},
+		{"ml", "", "", ""},
+	}
+	for _, tt := range tests {
+		resolved, pinnedBy := cfg.ResolveRuntime(tt.tenant, tt.spec)
+		if resolved != tt.resolved || pinnedBy != tt.pinnedBy {
+			t.Errorf("ResolveRuntime(%q, %q) = %q, %q; want %q, %q", tt.tenant, tt.spec, resolved, pinnedBy, tt.resolved, tt.pinnedBy)
+		}
+	}
+}
+
+func TestCgroupConfigJobCgroupPath(t *testing.T) {
+	cgroupfs := CgroupConfig{BaseDir: "/sys/fs/cgroup/joblet.slice/joblet.service", Slice: "joblet-jobs.slice"}
+	if got := cgroupfs.JobCgroupPath("abc"); got != "/sys/fs/cgroup/joblet.slice/joblet.service/job-abc" {
+		t.Errorf("cgroupfs JobCgroupPath() = %q", got)
+	}
+
+	systemd := cgroupfs
+	systemd.Driver = CgroupDriverSystemd
+	if got := systemd.JobsDir(); got != "/sys/fs/cgroup/joblet.slice/joblet-jobs.slice" {
+		t.Errorf("systemd JobsDir() = %q", got)
+	}
+	if got := systemd.JobCgroupPath("abc"); got != "/sys/fs/cgroup/joblet.slice/joblet-jobs.slice/job-abc.scope" {
+		t.Errorf("systemd JobCgroupPath() = %q", got)
+	}
+}
+
+func TestTenantResourceAccess(t *testing.T) {
+	cfg := Config{
+		Tenants: []TenantConfig{
+			{Name: "ml", Volumes: []string{"ml-*", "shared"}, Runtimes: []string{"python-*"}},
+			{Name: "web"},
+		},
+	}
+
+	tests := []struct {
+		tenant, name    string
+		volume, runtime bool
+	}{
+		{"ml", "ml-datasets", true, false},
+		{"ml", "shared", true, false},
+		{"ml", "web-assets", false, false},
+		{"ml", "python-3.11-ml", false, true},
+		{"web", "ml-datasets", true, true},
+		{"unknown", "ml-datasets", true, true},
+		{"", "ml-datasets", true, true},
+	}
+	for _, tt := range tests {
+		if got := cfg.TenantCanUseVolume(tt.tenant, tt.name); got != tt.volume {
+			t.Errorf("TenantCanUseVolume(%q, %q) = %v, want %v", tt.tenant, tt.name, got, tt.volume)
+		}
+		if got := cfg.TenantCanUseRuntime(tt.tenant, tt.name); got != tt.runtime {
+			t.Errorf("TenantCanUseRuntime(%q, %q) = %v, want %v", tt.tenant, tt.name, got, tt.runtime)
+		}
+	}
+}
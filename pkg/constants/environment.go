@@ -0,0 +1,204 @@
+package constants
+
+// Reserved request environment keys.
+// The job API has no dedicated fields for these options, so clients send them
+// through the RunJobRequest environment map. The server strips them before the
+// environment reaches the job process.
+const (
+	// EnvShmSize requests a /dev/shm tmpfs size in bytes
+	EnvShmSize = "JOBLET_SHM_SIZE"
+	// EnvTmpSize requests a /tmp tmpfs size in bytes (separate from the work dir quota)
+	EnvTmpSize = "JOBLET_TMP_SIZE"
+	// EnvScratch places the work directory on a scratch device the server configures ("nvme")
+	EnvScratch = "JOBLET_SCRATCH"
+	// EnvFreezeFS keeps the job's filesystem for browsing if it fails ("true")
+	EnvFreezeFS = "JOBLET_FREEZE_FS"
+	// EnvProfile asks for the job to run under a profiler ("strace" or "perf")
+	EnvProfile = "JOBLET_PROFILE"
+	// EnvDedup asks the server to return an identical active job instead of starting a new one ("true")
+	EnvDedup = "JOBLET_DEDUP"
+	// EnvCacheTTL reuses an identical job that completed successfully within this Go duration ("24h")
+	EnvCacheTTL = "JOBLET_CACHE_TTL"
+	// EnvNoCache skips the cache lookup, the new result still replaces the cached one ("true")
+	EnvNoCache = "JOBLET_NO_CACHE"
+	// EnvGroup adds the job to a named group for bulk list and stop ("load-test-2024")
+	EnvGroup = "JOBLET_GROUP"
+	// EnvLabels tags the job for bulk operations, comma-separated KEY=VALUE pairs ("env=staging,team=ml")
+	EnvLabels = "JOBLET_LABELS"
+	// EnvAnnotations attaches notes to the job such as the CI pipeline that submitted it, a JSON object ({"ci.run_id":"42"})
+	EnvAnnotations = "JOBLET_ANNOTATIONS"
+	// EnvLogSinks copies the job's output to external destinations, comma-separated URLs ("s3://bucket/prefix/,syslog://host")
+	EnvLogSinks = "JOBLET_LOG_SINKS"
+	// EnvStdin names the uploaded file, relative to the workspace, the job reads as its stdin (".joblet-stdin")
+	EnvStdin = "JOBLET_STDIN"
+	// EnvCgroupParams sets raw cgroup v2 files the server allows, a JSON object ({"cpu.weight":"200"})
+	EnvCgroupParams = "JOBLET_CGROUP_PARAMS"
+	// EnvArray submits a job array, one job per index of the spec ("0-99", "1,3,5-7", "0-99:10")
+	EnvArray = "JOBLET_ARRAY"
+	// EnvQueueTTL expires the job if it has not started this Go duration after its submission or scheduled time ("2h")
+	EnvQueueTTL = "JOBLET_QUEUE_TTL"
+	// EnvIPC joins a named IPC channel, a /dev/shm shared with the co-located jobs that join it ("arrow")
+	EnvIPC = "JOBLET_IPC"
+	// EnvInputs downloads objects into the workspace before the job starts, newline-separated ("s3://bucket/key:/work/data.bin")
+	EnvInputs = "JOBLET_INPUTS"
+	// EnvOutputTargets uploads workspace files to object storage when the job completes, newline-separated ("/work/out/*.parquet:s3://bucket/results/${JOB_UUID}/")
+	EnvOutputTargets = "JOBLET_OUTPUT_TARGETS"
+	// EnvEgress restricts the destinations the job may connect to, comma-separated rules matched in order ("allow:api.internal:443,deny:*")
+	EnvEgress = "JOBLET_EGRESS"
+	// EnvSecretFiles delivers secret environment variables as tmpfs files instead, comma-separated PATH=KEY pairs ("/root/.aws/credentials=AWS_CREDENTIALS")
+	EnvSecretFiles = "JOBLET_SECRET_FILES"
+)
+
+// Environment variables every job of a job array gets
+const (
+	// ArrayIDVar holds the UUID of the job array
+	ArrayIDVar = "JOB_ARRAY_ID"
+	// ArrayIndexVar holds the job's index in the array
+	ArrayIndexVar = "JOB_ARRAY_INDEX"
+	// ArraySizeVar holds the number of jobs in the array
+	ArraySizeVar = "JOB_ARRAY_SIZE"
+)
+
+// StdinUploadPath is where rnx uploads the content of --stdin and
+// --stdin-file. The job's init removes the file once it is open as stdin.
+const StdinUploadPath = ".joblet-stdin"
+
+// DeduplicatedHeader is the RunJob response header the server sets to "true"
+// when a deduplicated request returned an existing job instead of starting one.
+const DeduplicatedHeader = "joblet-deduplicated"
+
+// CachedHeader is the RunJob response header the server sets to "true" when
+// a cached result was returned instead of starting a job.
+const CachedHeader = "joblet-cached"
+
+// ArraySizeHeader is the RunJob response header holding the number of jobs
+// started for a JOBLET_ARRAY submission. The response then carries the array
+// UUID instead of a job UUID.
+const ArraySizeHeader = "joblet-array-size"
+
+// RuntimeSuggestedHeader and RuntimeSelectedHeader are the RunJob response
+// headers the server sets when a job naming no runtime uploads a dependency
+// file (requirements.txt, package.json, go.mod), as "<file>=<runtime>". The
+// runtime is only suggested, or the job was started with it, depending on
+// the server's runtime.detection policy.
+const (
+	RuntimeSuggestedHeader = "joblet-runtime-suggested"
+	RuntimeSelectedHeader  = "joblet-runtime-selected"
+)
+
+// ClientTimeHeader is the RunJob request header holding the client's clock
+// when it sent a scheduled job, as RFC 3339 with nanoseconds. The server
+// compares it with its own clock when no NTP server is available.
+const ClientTimeHeader = "joblet-client-time"
+
+// ClockWarningHeader is the RunJob response header warning that the job's
+// scheduled time falls within the uncertainty of the server's clock, so it
+// may start early or late. It is only set for such jobs.
+const ClockWarningHeader = "joblet-clock-warning"
+
+// ClockStatusHeader is the GetSystemStatus response header holding the
+// latest check of the server's clock (offset against NTP or a client, its
+// uncertainty and whether it drifted) as a JSON object. It is only set once
+// the clock was checked.
+const ClockStatusHeader = "joblet-clock-bin"
+
+// RateLimitStatusHeader is the GetSystemStatus response header holding the
+// rate limiter's counters (calls allowed, calls throttled, in total and per
+// client) as a JSON object. It is only set when rate limiting is enabled.
+const RateLimitStatusHeader = "joblet-rate-limit-bin"
+
+// RedactionsHeader is the GetJobStatus response header holding the number of
+// secret matches masked in the job's output. It is only set when non-zero.
+const RedactionsHeader = "joblet-redactions"
+
+// OutputDroppedHeader is the GetJobStatus response header holding the number
+// of output bytes dropped over the server's output limits. It is only set
+// when non-zero.
+const OutputDroppedHeader = "joblet-output-dropped"
+
+// OutputTruncatedHeader is the GetJobStatus response header the server sets
+// to "true" when the job reached the output size cap.
+const OutputTruncatedHeader = "joblet-output-truncated"
+
+// EventsHeader is the GetJobStatus response header holding the job's event
+// timeline (launch attempts, infrastructure failures, retries) as a JSON
+// array. It is a binary header so failure messages need no escaping, and is
+// only set when the job has events.
+const EventsHeader = "joblet-events-bin"
+
+// FingerprintHeader is the GetJobStatus response header holding the
+// environment the job last started in (runtime build, chroot mounts, kernel
+// and joblet version) as a JSON object. It is only set once the job started.
+const FingerprintHeader = "joblet-fingerprint-bin"
+
+// WorkflowFingerprintsHeader is the GetWorkflowStatus response header holding
+// the environment fingerprints of the workflow's started jobs as a JSON
+// object keyed by job name. It is only set when a job has started.
+const WorkflowFingerprintsHeader = "joblet-fingerprints-bin"
+
+// DurationAnomalyHeader is the GetJobStatus response header holding the
+// duration anomaly flagged for the job (its elapsed time against the mean and
+// standard deviation of its name's usual runs) as a JSON object. It is only
+// set when the job was flagged.
+const DurationAnomalyHeader = "joblet-duration-anomaly-bin"
+
+// WorkflowAnomaliesHeader is the GetWorkflowStatus response header holding
+// the duration anomalies of the workflow's jobs as a JSON object keyed by job
+// name. It is only set when a job was flagged.
+const WorkflowAnomaliesHeader = "joblet-duration-anomalies-bin"
+
+// NetworkSummaryHeader is the GetJobStatus response header holding the
+// network activity of the job's namespace over its run (bytes, packets, peak
+// TCP connections and retransmits) as a JSON object. It is only set once a
+// job with a network namespace of its own ended.
+const NetworkSummaryHeader = "joblet-network-summary-bin"
+
+// AnnotationsHeader carries job annotations as a JSON object: on RunWorkflow
+// requests, those every job of the workflow gets unless the workflow file sets
+// the key; on GetJobStatus responses, the job's annotations, only set when it
+// has any.
+const AnnotationsHeader = "joblet-annotations-bin"
+
+// ListAllHeader is the ListVolumes and ListRuntimes request header, set to
+// "true", asking for every volume or runtime of the node instead of those the
+// caller's tenant may use. Only admins may set it.
+const ListAllHeader = "joblet-list-all"
+
+// UploadRefsHeader is the RunWorkflow request header naming workflow files
+// synced to the node's upload cache beforehand, as a JSON object of upload
+// path to content hash. The named files are sent without content.
+const UploadRefsHeader = "joblet-upload-refs-bin"
+
+// ArtifactSHA256Trailer is the DownloadArtifact response trailer holding the
+// hex SHA-256 of the bytes the window sent
+const ArtifactSHA256Trailer = "joblet-artifact-sha256"
+
+// LogOffsetHeader is the GetJobLogs request header resuming a log stream:
+// the number of non-empty chunks the client already received, which the
+// server skips
+const LogOffsetHeader = "joblet-log-offset"
+
+// LogHeartbeatHeader is the GetJobLogs response header with the interval of
+// the empty chunks the server sends on idle log streams, as a Go duration
+// ("0s" = none). Its presence tells clients the server honors
+// LogOffsetHeader.
+const LogHeartbeatHeader = "joblet-log-heartbeat"
+
+// Request signature metadata, sent by clients with a signing key on RunJob and
+// RunWorkflow requests. See pkg/jobsign.
+const (
+	// SignatureHeader holds the base64 Ed25519 signature of the submission digest
+	SignatureHeader = "joblet-signature"
+	// SignatureKeyHeader holds the signer's base64 raw Ed25519 public key
+	SignatureKeyHeader = "joblet-signature-key"
+	// SignedAtHeader holds the signing time in Unix seconds
+	SignedAtHeader = "joblet-signed-at"
+)
+
+// SignatureStatusHeader is the GetJobStatus and GetWorkflowStatus response
+// header reporting the stored signature checked again on read: "valid",
+// "invalid" or "untrusted". It is only set for signed records.
+const SignatureStatusHeader = "joblet-signature-status"
+
+// SignerHeader is the response header holding the signer's key fingerprint
+const SignerHeader = "joblet-signer"
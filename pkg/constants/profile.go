@@ -0,0 +1,19 @@
+package constants
+
+// Job profiling ('rnx job run --profile').
+// The profiler output is appended to the job log between a begin and an end
+// marker line, so it is stored and retrieved like any other job output.
+const (
+	// ProfileStrace traces system calls of the job and its children
+	ProfileStrace = "strace"
+	// ProfilePerf samples CPU stacks of the job and its children
+	ProfilePerf = "perf"
+
+	// ProfileBeginMarker starts the profile section, followed by the tool name
+	ProfileBeginMarker = "=== joblet profile begin: "
+	// ProfileEndMarker ends the profile section
+	ProfileEndMarker = "=== joblet profile end ==="
+)
+
+// ProfileTools lists the supported profilers.
+var ProfileTools = []string{ProfileStrace, ProfilePerf}
@@ -140,6 +140,22 @@ func (e *ConfigError) Unwrap() error {
 	return e.Err
 }
 
+// InfrastructureError is a job failure caused by the node rather than the
+// job itself: isolation, filesystem, network or runtime setup failed before
+// the job's command ran. Another attempt may succeed.
+type InfrastructureError struct {
+	Stage string
+	Err   error
+}
+
+func (e *InfrastructureError) Error() string {
+	return fmt.Sprintf("infrastructure failure during %s: %v", e.Stage, e.Err)
+}
+
+func (e *InfrastructureError) Unwrap() error {
+	return e.Err
+}
+
 // Error wrapping constructors
 func WrapJobError(jobID, operation string, err error) error {
 	if err == nil {
@@ -183,6 +199,13 @@ func WrapConfigError(component, field string, err error) error {
 	return &ConfigError{Component: component, Field: field, Err: err}
 }
 
+func WrapInfrastructureError(stage string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &InfrastructureError{Stage: stage, Err: err}
+}
+
 // Error classification functions
 func IsJobError(err error) bool {
 	var je *JobError
@@ -214,6 +237,11 @@ func IsConfigError(err error) bool {
 	return errors.As(err, &ce)
 }
 
+func IsInfrastructureError(err error) bool {
+	var ie *InfrastructureError
+	return errors.As(err, &ie)
+}
+
 // Specific error type checks
 func IsResourceError(err error) bool {
 	return errors.Is(err, ErrResourceExhausted) ||
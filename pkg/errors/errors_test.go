@@ -306,6 +306,29 @@ func TestWrapRuntimeError(t *testing.T) {
 	}
 }
 
+func TestWrapInfrastructureError(t *testing.T) {
+	if WrapInfrastructureError("network", nil) != nil {
+		t.Error("WrapInfrastructureError(nil) should return nil")
+	}
+
+	originalErr := errors.New("failed to attach veth")
+	wrappedErr := fmt.Errorf("start job: %w", WrapInfrastructureError("network", originalErr))
+
+	if !IsInfrastructureError(wrappedErr) {
+		t.Fatal("IsInfrastructureError() = false for a wrapped InfrastructureError")
+	}
+	if !errors.Is(wrappedErr, originalErr) {
+		t.Error("InfrastructureError should unwrap to the original error")
+	}
+	expectedMsg := "start job: infrastructure failure during network: failed to attach veth"
+	if wrappedErr.Error() != expectedMsg {
+		t.Errorf("Error() = %v, want %v", wrappedErr.Error(), expectedMsg)
+	}
+	if IsInfrastructureError(originalErr) {
+		t.Error("IsInfrastructureError() = true for a plain error")
+	}
+}
+
 // Test error cause extraction
 func TestGetJobID(t *testing.T) {
 	tests := []struct {
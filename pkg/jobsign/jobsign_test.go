@@ -0,0 +1,85 @@
+package jobsign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signedAt := time.Unix(1700000000, 0)
+	digest := JobDigest("python3", []string{"train.py", "--epochs=10"}, "python-3.11", signedAt)
+	signature := Sign(privateKey, digest)
+
+	if !Verify(publicKey, digest, signature) {
+		t.Fatal("Verify() = false for an untouched job")
+	}
+
+	tampered := []struct {
+		name   string
+		digest []byte
+	}{
+		{"command", JobDigest("python", []string{"train.py", "--epochs=10"}, "python-3.11", signedAt)},
+		{"args", JobDigest("python3", []string{"train.py", "--epochs=100"}, "python-3.11", signedAt)},
+		{"arg boundaries", JobDigest("python3", []string{"train.py --epochs=10"}, "python-3.11", signedAt)},
+		{"runtime", JobDigest("python3", []string{"train.py", "--epochs=10"}, "python-3.12", signedAt)},
+		{"signing time", JobDigest("python3", []string{"train.py", "--epochs=10"}, "python-3.11", signedAt.Add(time.Second))},
+		{"workflow digest", WorkflowDigest("python3", signedAt)},
+	}
+	for _, tt := range tampered {
+		if Verify(publicKey, tt.digest, signature) {
+			t.Errorf("Verify() = true after changing %s", tt.name)
+		}
+	}
+
+	if Verify(publicKey[:10], digest, signature) {
+		t.Error("Verify() = true for a truncated public key")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+
+	parsedPrivate, err := ParsePrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	if !parsedPrivate.Equal(privateKey) {
+		t.Error("ParsePrivateKey() returned a different key")
+	}
+
+	parsedPublic, err := ParsePublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})))
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	if !parsedPublic.Equal(publicKey) {
+		t.Error("ParsePublicKey() returned a different key")
+	}
+
+	if _, err := ParsePrivateKey("not a key"); err == nil {
+		t.Error("ParsePrivateKey() expected error for non-PEM input")
+	}
+
+	if fingerprint := Fingerprint(publicKey); !strings.HasPrefix(fingerprint, "SHA256:") {
+		t.Errorf("Fingerprint() = %q, want SHA256: prefix", fingerprint)
+	}
+}
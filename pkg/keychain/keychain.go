@@ -0,0 +1,96 @@
+// Package keychain keeps client secrets in the operating system's credential
+// store instead of rnx-config.yml: the macOS Keychain, the Secret Service
+// (GNOME Keyring, KWallet) through libsecret on Linux, and DPAPI-protected
+// files on Windows.
+//
+// A configuration value of the form "keychain:<account>" refers to the secret
+// stored under that account for the "rnx" service.
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Service is the service name rnx secrets are stored under
+const Service = "rnx"
+
+// RefPrefix marks a configuration value as a keychain reference
+const RefPrefix = "keychain:"
+
+var (
+	// ErrNotFound is returned when no secret is stored for an account
+	ErrNotFound = errors.New("secret not found in keychain")
+	// ErrUnsupported is returned when the platform has no supported credential store
+	ErrUnsupported = errors.New("keychain is not supported on this platform")
+)
+
+// validAccount keeps account names safe as command arguments and file names
+var validAccount = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// backend is a platform credential store
+type backend interface {
+	get(account string) (string, error)
+	set(account, secret string) error
+	delete(account string) error
+}
+
+// store is the platform's credential store, replaced in tests
+var store backend = platformBackend()
+
+// Get returns the secret stored for account
+func Get(account string) (string, error) {
+	if err := checkAccount(account); err != nil {
+		return "", err
+	}
+	return store.get(account)
+}
+
+// Set stores secret for account, replacing any secret stored before
+func Set(account, secret string) error {
+	if err := checkAccount(account); err != nil {
+		return err
+	}
+	return store.set(account, secret)
+}
+
+// Delete removes the secret stored for account
+func Delete(account string) error {
+	if err := checkAccount(account); err != nil {
+		return err
+	}
+	return store.delete(account)
+}
+
+// Ref returns the configuration value referring to account
+func Ref(account string) string {
+	return RefPrefix + account
+}
+
+// IsRef reports whether a configuration value is a keychain reference
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// Resolve returns the secret a keychain reference refers to. Other values are
+// returned unchanged.
+func Resolve(value string) (string, error) {
+	account, ok := strings.CutPrefix(value, RefPrefix)
+	if !ok {
+		return value, nil
+	}
+	secret, err := Get(account)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from keychain: %w", account, err)
+	}
+	return secret, nil
+}
+
+func checkAccount(account string) error {
+	if !validAccount.MatchString(account) {
+		return fmt.Errorf("invalid keychain account %q: use letters, digits, '.', '_' and '-'", account)
+	}
+	return nil
+}
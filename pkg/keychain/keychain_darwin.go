@@ -0,0 +1,66 @@
+//go:build darwin
+
+package keychain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityItemNotFound is the exit code of security(1) for a missing item
+const securityItemNotFound = 44
+
+// macKeychain stores secrets as generic passwords in the login keychain with
+// the security command. Secrets are base64 encoded so PEM line breaks survive
+// the command line.
+type macKeychain struct{}
+
+func platformBackend() backend {
+	return macKeychain{}
+}
+
+func (macKeychain) get(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", Service, "-a", account, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("security find-generic-password: %w", err)
+	}
+	secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("keychain item %s was not stored by rnx: %w", account, err)
+	}
+	return string(secret), nil
+}
+
+func (macKeychain) set(account, secret string) error {
+	// Interactive mode reads the command from stdin, keeping the secret out
+	// of the process list
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		Service, account, base64.StdEncoding.EncodeToString([]byte(secret))))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("security add-generic-password: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (macKeychain) delete(account string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", Service, "-a", account).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("security delete-generic-password: %w", err)
+	}
+	return nil
+}
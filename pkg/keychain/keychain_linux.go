@@ -0,0 +1,66 @@
+//go:build linux
+
+package keychain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretService stores secrets in the desktop's Secret Service (GNOME
+// Keyring, KWallet) with secret-tool from libsecret
+type secretService struct{}
+
+func platformBackend() backend {
+	return secretService{}
+}
+
+func (secretService) get(account string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := secretTool(nil, &stdout, &stderr, "lookup", "service", Service, "account", account); err != nil {
+		// lookup fails silently when nothing matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stdout.Len() == 0 && stderr.Len() == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secret-tool lookup: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (secretService) set(account, secret string) error {
+	// store reads the secret from stdin, keeping it out of the process list
+	var stderr bytes.Buffer
+	if err := secretTool(strings.NewReader(secret), nil, &stderr, "store", "--label", Service+" "+account,
+		"service", Service, "account", account); err != nil {
+		return fmt.Errorf("secret-tool store: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (secretService) delete(account string) error {
+	var stderr bytes.Buffer
+	if err := secretTool(nil, nil, &stderr, "clear", "service", Service, "account", account); err != nil {
+		return fmt.Errorf("secret-tool clear: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func secretTool(stdin *strings.Reader, stdout, stderr *bytes.Buffer, args ...string) error {
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return fmt.Errorf("%w: secret-tool not found, install libsecret-tools", ErrUnsupported)
+	}
+	cmd := exec.Command(path, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
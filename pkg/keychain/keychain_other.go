@@ -0,0 +1,14 @@
+//go:build !darwin && !linux && !windows
+
+package keychain
+
+// unsupported is the backend for platforms without a supported credential store
+type unsupported struct{}
+
+func platformBackend() backend {
+	return unsupported{}
+}
+
+func (unsupported) get(string) (string, error) { return "", ErrUnsupported }
+func (unsupported) set(string, string) error   { return ErrUnsupported }
+func (unsupported) delete(string) error        { return ErrUnsupported }
@@ -0,0 +1,94 @@
+//go:build windows
+
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// dpapiStore keeps secrets in files under the user's config directory,
+// encrypted with DPAPI so only the same Windows user can decrypt them
+type dpapiStore struct{}
+
+func platformBackend() backend {
+	return dpapiStore{}
+}
+
+func (dpapiStore) get(account string) (string, error) {
+	path, err := secretPath(account)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	secret, err := dpapi(encrypted, windows.CryptUnprotectData)
+	if err != nil {
+		return "", fmt.Errorf("CryptUnprotectData: %w", err)
+	}
+	return string(secret), nil
+}
+
+func (dpapiStore) set(account, secret string) error {
+	path, err := secretPath(account)
+	if err != nil {
+		return err
+	}
+	encrypted, err := dpapi([]byte(secret), func(in *windows.DataBlob, _ **uint16, entropy *windows.DataBlob, reserved uintptr, prompt *windows.CryptProtectPromptStruct, flags uint32, out *windows.DataBlob) error {
+		return windows.CryptProtectData(in, nil, entropy, reserved, prompt, flags, out)
+	})
+	if err != nil {
+		return fmt.Errorf("CryptProtectData: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, encrypted, 0600)
+}
+
+func (dpapiStore) delete(account string) error {
+	path, err := secretPath(account)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func secretPath(account string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, Service, "keychain", account+".dpapi"), nil
+}
+
+type dpapiFunc func(in *windows.DataBlob, name **uint16, entropy *windows.DataBlob, reserved uintptr, prompt *windows.CryptProtectPromptStruct, flags uint32, out *windows.DataBlob) error
+
+// dpapi runs CryptProtectData or CryptUnprotectData on data without UI
+func dpapi(data []byte, fn dpapiFunc) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty secret")
+	}
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	var out windows.DataBlob
+	if err := fn(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
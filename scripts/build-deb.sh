@@ -31,6 +31,9 @@ if [ -f "./joblet" ] && [ -f "./rnx" ] && [ -f "./persist" ] && [ -f "./state" ]
     cp ./rnx "$BIN_DIR/rnx"
     cp ./persist "$BIN_DIR/persist"
     cp ./state "$BIN_DIR/state"
+    if [ -f "./joblet-init" ]; then
+        cp ./joblet-init "$BIN_DIR/joblet-init"
+    fi
     chmod +x "$BIN_DIR"/*
 elif [ ! -f "$BIN_DIR/joblet" ] || [ ! -f "$BIN_DIR/rnx" ] || [ ! -f "$BIN_DIR/persist" ] || [ ! -f "$BIN_DIR/state" ]; then
     # Build all binaries if they don't exist
@@ -43,6 +46,15 @@ else
     echo "📦 Using existing binaries from $BIN_DIR/..."
 fi
 
+# The minimal init binary is newer than the others, build it when missing
+if [ ! -f "$BIN_DIR/joblet-init" ]; then
+    echo "📦 Building joblet-init for $ARCH..."
+    ARCH=$ARCH make joblet-init || {
+        echo "❌ Build failed!"
+        exit 1
+    }
+fi
+
 # Clean and create build directory
 mkdir -p "$BUILDS_DIR"
 rm -rf "$BUILD_DIR"
@@ -79,6 +91,7 @@ if [ ! -f "$BIN_DIR/state" ]; then
     exit 1
 fi
 cp "$BIN_DIR/state" "$BUILD_DIR/opt/joblet/bin/"
+cp "$BIN_DIR/joblet-init" "$BUILD_DIR/opt/joblet/bin/"
 
 # Copy template files (NOT actual configs with certificates)
 if [ -f "./scripts/joblet-config-template.yml" ]; then
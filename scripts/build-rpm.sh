@@ -52,6 +52,9 @@ if [ -f "./joblet" ] && [ -f "./rnx" ] && [ -f "./persist" ] && [ -f "./state" ]
     cp ./rnx "$BIN_DIR/rnx"
     cp ./persist "$BIN_DIR/persist"
     cp ./state "$BIN_DIR/state"
+    if [ -f "./joblet-init" ]; then
+        cp ./joblet-init "$BIN_DIR/joblet-init"
+    fi
     chmod +x "$BIN_DIR"/*
 elif [ ! -f "$BIN_DIR/joblet" ] || [ ! -f "$BIN_DIR/rnx" ] || [ ! -f "$BIN_DIR/persist" ] || [ ! -f "$BIN_DIR/state" ]; then
     # Build all binaries if they don't exist
@@ -64,6 +67,15 @@ else
     echo "📦 Using existing binaries from $BIN_DIR/..."
 fi
 
+# The minimal init binary is newer than the others, build it when missing
+if [ ! -f "$BIN_DIR/joblet-init" ]; then
+    echo "📦 Building joblet-init for $BIN_ARCH..."
+    ARCH=$BIN_ARCH make joblet-init || {
+        echo "❌ Build failed!"
+        exit 1
+    }
+fi
+
 # Get the current date for changelog
 CHANGELOG_DATE=$(date '+%a %b %d %Y')
 
@@ -99,6 +111,7 @@ if [ ! -f "$BIN_DIR/state" ]; then
     exit 1
 fi
 cp "$BIN_DIR/state" "$BUILD_DIR/SOURCES/${PACKAGE_NAME}-${CLEAN_VERSION}/"
+cp "$BIN_DIR/joblet-init" "$BUILD_DIR/SOURCES/${PACKAGE_NAME}-${CLEAN_VERSION}/"
 
 # Copy scripts and configs
 cp -r ./scripts "$BUILD_DIR/SOURCES/${PACKAGE_NAME}-${CLEAN_VERSION}/" || {
@@ -144,6 +157,8 @@ Requires:       iptables
 Requires:       iproute
 Requires:       bridge-utils
 Requires:       procps-ng
+# Job egress policies are loaded with nft
+Recommends:     nftables
 
 # Distribution-specific dependencies
 %if 0%{?rhel} >= 8 || 0%{?fedora} >= 30
@@ -187,6 +202,7 @@ cp joblet \$RPM_BUILD_ROOT/opt/joblet/bin/
 cp rnx \$RPM_BUILD_ROOT/opt/joblet/bin/
 cp persist \$RPM_BUILD_ROOT/opt/joblet/bin/
 cp state \$RPM_BUILD_ROOT/opt/joblet/bin/
+cp joblet-init \$RPM_BUILD_ROOT/opt/joblet/bin/
 
 # Install config templates and scripts
 cp scripts/joblet-config-template.yml \$RPM_BUILD_ROOT/opt/joblet/scripts/
@@ -393,6 +409,7 @@ fi
 /opt/joblet/bin/rnx
 /opt/joblet/bin/persist
 /opt/joblet/bin/state
+/opt/joblet/bin/joblet-init
 /opt/joblet/scripts/joblet-config-template.yml
 /opt/joblet/scripts/rnx-config-template.yml
 /opt/joblet/scripts/common-install-functions.sh
@@ -8,10 +8,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/leader"
+	"github.com/ehsaniara/joblet/internal/joblet/sdnotify"
 	"github.com/ehsaniara/joblet/pkg/config"
 	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/state/internal/api"
 	"github.com/ehsaniara/joblet/state/internal/ipc"
 	"github.com/ehsaniara/joblet/state/internal/storage"
+	"github.com/ehsaniara/joblet/state/internal/watch"
 	"gopkg.in/yaml.v3"
 )
 
@@ -58,13 +63,52 @@ func main() {
 
 	log.Info("[STATE] Storage backend initialized successfully", "backend", cfg.State.Backend)
 
+	// Under systemd, feed the watchdog while the storage backend answers
+	watchdogCtx, stopWatchdog := context.WithCancel(context.Background())
+	defer stopWatchdog()
+	go sdnotify.RunWatchdog(watchdogCtx, backend.HealthCheck, log)
+
+	// Persist instances elect their active process through leases kept here
+	leases, _ := backend.(storage.LeaseStore)
+
+	// With ha and a shared backend, wait as a standby until this instance
+	// holds the state lease; only the holder serves the sockets
+	var elector *leader.Elector
+	held := make(chan error, 1)
+	holdCtx, stopHolding := context.WithCancel(context.Background())
+	defer stopHolding()
+	if cfg.StateStandbys() > 0 && leases != nil {
+		elector = leader.NewElector(storage.Lease(leases, "state"), leader.Holder(), cfg.HA.LeaseTTL, log)
+		log.Info("[STATE] Waiting for the state lease")
+		// A standby is up as far as systemd is concerned
+		_, _ = sdnotify.Notify(sdnotify.Ready, sdnotify.Status("standby, waiting for the state lease"))
+		if err := elector.Campaign(context.Background()); err != nil {
+			log.Fatal("failed to acquire the state lease", "error", err)
+		}
+
+		// Runs after the servers below stopped and removed their sockets
+		defer func() {
+			stopHolding()
+			<-held
+		}()
+	}
+
 	// Create IPC server
 	socketPath := cfg.State.Socket
 	if socketPath == "" {
 		socketPath = defaultSocketPath
 	}
 
-	server := ipc.NewServer(socketPath, backend)
+	// Writes over IPC are published to the hub for read API watchers
+	hub := watch.NewHub(0)
+	server := ipc.NewServer(socketPath, watch.NewBackend(backend, hub))
+	if leases != nil {
+		server.SetLeaseStore(leases)
+	}
+	// The joblet checkpoints its workflows here so they survive its restarts
+	if workflows, ok := backend.(storage.WorkflowStore); ok {
+		server.SetWorkflowStore(workflows)
+	}
 
 	// Start IPC server
 	if err := server.Start(); err != nil {
@@ -74,17 +118,49 @@ func main() {
 
 	log.Info("[STATE] IPC server started successfully", "socket", socketPath)
 
+	// Start read-only API for external consumers
+	var apiServer *api.Server
+	if cfg.State.API.Enabled {
+		apiServer = api.NewServer(cfg, backend, hub, auth.NewGRPCAuthorization())
+		if err := apiServer.Start(); err != nil {
+			log.Fatal("failed to start state API", "error", err)
+		}
+		log.Info("[STATE] State API started successfully", "address", cfg.State.API.Address)
+	}
+
 	// Wait for shutdown signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Keep the lease renewed while serving
+	if elector != nil {
+		go func() { held <- elector.Hold(holdCtx) }()
+	}
+
 	log.Info("[STATE] state service is ready")
+	if _, err := sdnotify.Notify(sdnotify.Ready, sdnotify.Status("serving")); err != nil {
+		log.Warn("failed to notify systemd", "error", err)
+	}
 
-	// Block until signal received
-	sig := <-sigChan
-	log.Info("[STATE] Received shutdown signal, stopping service...", "signal", sig)
+	// Block until signal received or the lease is lost
+	select {
+	case sig := <-sigChan:
+		log.Info("[STATE] Received shutdown signal, stopping service...", "signal", sig)
+	case err := <-held:
+		// Another instance serves the socket now; exit without removing it,
+		// the supervisor restarts this one as a standby
+		log.Error("[STATE] Lost the state lease, exiting", "error", err)
+		os.Exit(1)
+	}
 
 	// Graceful shutdown
+	_, _ = sdnotify.Notify(sdnotify.Stopping)
+	if apiServer != nil {
+		if err := apiServer.Stop(); err != nil {
+			log.Error("error stopping state API", "error", err)
+		}
+	}
+
 	if err := server.Stop(); err != nil {
 		log.Error("error stopping IPC server", "error", err)
 	}
@@ -144,6 +220,10 @@ func validateConfig(cfg *config.Config) error {
 		}
 	}
 
+	if cfg.State.API.Enabled && cfg.State.API.Address == "" {
+		return fmt.Errorf("state api address is required when the api is enabled")
+	}
+
 	return nil
 }
 
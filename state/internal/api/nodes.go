@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/state/internal/nodes"
+)
+
+// NodeRegistry is the coordination endpoint joblet nodes register with
+// when coordination.backend is "state"
+type NodeRegistry struct {
+	nodespb.UnimplementedNodeRegistryServiceServer
+	auth     auth.GRPCAuthorization
+	registry *nodes.Registry
+	logger   *logger.Logger
+}
+
+// NewNodeRegistry serves the given registry
+func NewNodeRegistry(registry *nodes.Registry, authorization auth.GRPCAuthorization) *NodeRegistry {
+	return &NodeRegistry{
+		auth:     authorization,
+		registry: registry,
+		logger:   logger.WithField("component", "node-registry"),
+	}
+}
+
+// Heartbeat registers the calling node or refreshes its registration
+func (n *NodeRegistry) Heartbeat(ctx context.Context, node *nodespb.NodeInfo) (*nodespb.HeartbeatResponse, error) {
+	if err := n.auth.Authorized(ctx, auth.RegisterNodeOp); err != nil {
+		return nil, err
+	}
+	if node.NodeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "node_id is required")
+	}
+
+	registered := n.registry.Heartbeat(node)
+	if registered {
+		n.logger.Info("node registered", "nodeId", node.NodeId, "address", node.Address, "ttlSeconds", node.TtlSeconds)
+	}
+	return &nodespb.HeartbeatResponse{Registered: registered}, nil
+}
+
+// ListNodes returns the live nodes carrying all of the requested labels
+func (n *NodeRegistry) ListNodes(ctx context.Context, req *nodespb.ListNodesRequest) (*nodespb.ListNodesResponse, error) {
+	if err := n.auth.Authorized(ctx, auth.ListNodesOp); err != nil {
+		return nil, err
+	}
+	return &nodespb.ListNodesResponse{Nodes: n.registry.List(req.Labels)}, nil
+}
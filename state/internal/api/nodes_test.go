@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	"github.com/ehsaniara/joblet/state/internal/nodes"
+)
+
+func TestNodeRegistry(t *testing.T) {
+	n := NewNodeRegistry(nodes.NewRegistry(), auth.NewNoOpAuthorization())
+	ctx := context.Background()
+
+	resp, err := n.Heartbeat(ctx, &nodespb.NodeInfo{NodeId: "node-1", Address: "10.0.0.5:50051"})
+	if err != nil || !resp.Registered {
+		t.Fatalf("Heartbeat = %v, %v", resp, err)
+	}
+	resp, err = n.Heartbeat(ctx, &nodespb.NodeInfo{NodeId: "node-1", Address: "10.0.0.5:50051"})
+	if err != nil || resp.Registered {
+		t.Errorf("second Heartbeat = %v, %v, want already registered", resp, err)
+	}
+
+	list, err := n.ListNodes(ctx, &nodespb.ListNodesRequest{})
+	if err != nil || len(list.Nodes) != 1 || list.Nodes[0].Address != "10.0.0.5:50051" {
+		t.Errorf("ListNodes = %v, %v", list, err)
+	}
+
+	if _, err := n.Heartbeat(ctx, &nodespb.NodeInfo{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing node ID: got %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,294 @@
+// Package api serves the state service's job states read-only over gRPC so
+// dashboards and external schedulers do not have to read the storage backend
+// directly. It is also the "state" coordination endpoint joblet nodes send
+// heartbeats to.
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+	statepb "github.com/ehsaniara/joblet/internal/proto/gen/state"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/pkg/logger"
+	"github.com/ehsaniara/joblet/state/internal/nodes"
+	"github.com/ehsaniara/joblet/state/internal/storage"
+	"github.com/ehsaniara/joblet/state/internal/watch"
+)
+
+// Server is the read-only StateService
+type Server struct {
+	statepb.UnimplementedStateServiceServer
+	auth     auth.GRPCAuthorization
+	cfg      *config.Config
+	backend  storage.Backend
+	hub      *watch.Hub
+	logger   *logger.Logger
+	grpcSrv  *grpc.Server
+	listener net.Listener
+}
+
+// NewServer creates the state API. Writes recorded through a watch.Backend
+// on the same hub are streamed to WatchJobStates callers.
+func NewServer(cfg *config.Config, backend storage.Backend, hub *watch.Hub, authorization auth.GRPCAuthorization) *Server {
+	return &Server{
+		auth:    authorization,
+		cfg:     cfg,
+		backend: backend,
+		hub:     hub,
+		logger:  logger.WithField("component", "state-api"),
+	}
+}
+
+// Start listens on the configured TCP address. TLS is mandatory, the
+// security section's certificates are used with client verification.
+func (s *Server) Start() error {
+	address := s.cfg.State.API.Address
+	if address == "" {
+		return fmt.Errorf("state api address is not configured")
+	}
+
+	tlsConfig, err := s.cfg.GetServerTLSConfig()
+	if err != nil {
+		return fmt.Errorf("state api requires TLS: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	s.listener = listener
+
+	s.grpcSrv = grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+	statepb.RegisterStateServiceServer(s.grpcSrv, s)
+	nodespb.RegisterNodeRegistryServiceServer(s.grpcSrv, NewNodeRegistry(nodes.NewRegistry(), s.auth))
+
+	go func() {
+		if err := s.grpcSrv.Serve(listener); err != nil {
+			s.logger.Error("state api server error", "error", err)
+		}
+	}()
+
+	s.logger.Info("state api listening", "address", address, "maxWatchers", s.cfg.State.API.MaxWatchers)
+	return nil
+}
+
+// Stop stops the state API, ending open watch streams
+func (s *Server) Stop() error {
+	if s.grpcSrv != nil {
+		s.grpcSrv.GracefulStop()
+	}
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	return nil
+}
+
+// ListJobStates returns the stored job states matching the request
+func (s *Server) ListJobStates(ctx context.Context, req *statepb.ListJobStatesRequest) (*statepb.ListJobStatesResponse, error) {
+	if err := s.auth.Authorized(ctx, auth.ListJobsOp); err != nil {
+		return nil, err
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	jobs, err := s.backend.List(ctx, &storage.Filter{
+		Statuses: req.Statuses,
+		NodeID:   req.NodeId,
+		Limit:    int(req.Limit),
+		SortBy:   "startTime",
+		SortDesc: req.NewestFirst,
+	})
+	if err != nil {
+		s.logger.Error("failed to list job states", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to list job states: %v", err)
+	}
+
+	resp := &statepb.ListJobStatesResponse{Jobs: make([]*statepb.JobState, 0, len(jobs))}
+	for _, job := range jobs {
+		resp.Jobs = append(resp.Jobs, jobToProto(job))
+	}
+	return resp, nil
+}
+
+// GetJobState returns the stored state of one job
+func (s *Server) GetJobState(ctx context.Context, req *statepb.GetJobStateRequest) (*statepb.JobState, error) {
+	if err := s.auth.Authorized(ctx, auth.GetJobStatusOp); err != nil {
+		return nil, err
+	}
+	if req.Uuid == "" {
+		return nil, status.Error(codes.InvalidArgument, "uuid is required")
+	}
+
+	job, err := s.backend.Get(ctx, req.Uuid)
+	if err != nil {
+		if errors.Is(err, storage.ErrJobNotFound) {
+			return nil, status.Errorf(codes.NotFound, "job %s not found", req.Uuid)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get job state: %v", err)
+	}
+	return jobToProto(job), nil
+}
+
+// WatchJobStates streams job state changes. The subscription is taken before
+// the snapshot is read so no change in between is lost; a change may then
+// appear both in the snapshot and as an event. A watcher that falls behind is
+// ended with ResourceExhausted and should reconnect with initial_snapshot.
+func (s *Server) WatchJobStates(req *statepb.WatchJobStatesRequest, stream statepb.StateService_WatchJobStatesServer) error {
+	ctx := stream.Context()
+	if err := s.auth.Authorized(ctx, auth.StreamJobsOp); err != nil {
+		return err
+	}
+
+	if limit := s.cfg.State.API.MaxWatchers; limit > 0 && s.hub.Subscribers() >= limit {
+		return status.Errorf(codes.ResourceExhausted, "too many watchers (max %d)", limit)
+	}
+
+	sub := s.hub.Subscribe()
+	defer sub.Close()
+
+	if req.InitialSnapshot {
+		if err := s.sendSnapshot(ctx, req, stream); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.Events:
+			if !ok {
+				if sub.Dropped() {
+					return status.Error(codes.ResourceExhausted, "watcher fell behind, reconnect with initial_snapshot")
+				}
+				return nil
+			}
+			if !matchesWatch(req, e) {
+				continue
+			}
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) sendSnapshot(ctx context.Context, req *statepb.WatchJobStatesRequest, stream statepb.StateService_WatchJobStatesServer) error {
+	var jobs []*domain.Job
+	if req.Uuid != "" {
+		job, err := s.backend.Get(ctx, req.Uuid)
+		if err != nil && !errors.Is(err, storage.ErrJobNotFound) {
+			return status.Errorf(codes.Internal, "failed to get job state: %v", err)
+		}
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	} else {
+		var err error
+		jobs, err = s.backend.List(ctx, &storage.Filter{NodeID: req.NodeId, SortBy: "startTime"})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to list job states: %v", err)
+		}
+	}
+
+	for _, job := range jobs {
+		e := watch.Event{Type: watch.Updated, JobID: job.Uuid, Job: job}
+		if !matchesWatch(req, e) {
+			continue
+		}
+		if err := stream.Send(&statepb.JobStateEvent{
+			Type: statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_SNAPSHOT,
+			Uuid: job.Uuid,
+			Job:  jobToProto(job),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesWatch reports whether e passes the watch filters. Deletes carry no
+// job, so only the uuid filter applies to them.
+func matchesWatch(req *statepb.WatchJobStatesRequest, e watch.Event) bool {
+	if req.Uuid != "" && e.JobID != req.Uuid {
+		return false
+	}
+	if e.Job == nil {
+		return true
+	}
+	if req.NodeId != "" && e.Job.NodeId != req.NodeId {
+		return false
+	}
+	if len(req.Statuses) > 0 {
+		for _, s := range req.Statuses {
+			if string(e.Job.Status) == s {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func eventToProto(e watch.Event) *statepb.JobStateEvent {
+	out := &statepb.JobStateEvent{
+		Uuid:      e.JobID,
+		Timestamp: e.Time.UnixNano(),
+	}
+	switch e.Type {
+	case watch.Created:
+		out.Type = statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_CREATED
+	case watch.Updated:
+		out.Type = statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_UPDATED
+	case watch.Deleted:
+		out.Type = statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_DELETED
+	}
+	if e.Job != nil {
+		out.Job = jobToProto(e.Job)
+	}
+	return out
+}
+
+// jobToProto converts a stored job. Environment variables are left out on
+// purpose, they may hold secrets.
+func jobToProto(job *domain.Job) *statepb.JobState {
+	out := &statepb.JobState{
+		Uuid:         job.Uuid,
+		Name:         job.Name,
+		Status:       string(job.Status),
+		Command:      job.Command,
+		Args:         job.Args,
+		NodeId:       job.NodeId,
+		WorkflowUuid: job.WorkflowUuid,
+		Runtime:      job.Runtime,
+		Network:      job.Network,
+		ExitCode:     job.ExitCode,
+		GpuCount:     job.GPUCount,
+		Redactions:   job.Redactions,
+
+		OutputDroppedBytes: job.OutputDroppedBytes,
+		OutputTruncated:    job.OutputTruncated,
+	}
+	if !job.StartTime.IsZero() {
+		out.StartTime = job.StartTime.Unix()
+	}
+	if job.EndTime != nil {
+		out.EndTime = job.EndTime.Unix()
+	}
+	if job.ScheduledTime != nil {
+		out.ScheduledTime = job.ScheduledTime.Unix()
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ehsaniara/joblet/internal/joblet/auth"
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	statepb "github.com/ehsaniara/joblet/internal/proto/gen/state"
+	"github.com/ehsaniara/joblet/pkg/config"
+	"github.com/ehsaniara/joblet/state/internal/storage"
+	"github.com/ehsaniara/joblet/state/internal/watch"
+)
+
+// fakeWatchStream collects sent events and hands them to the test
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *statepb.JobStateEvent
+}
+
+func (f *fakeWatchStream) Context() context.Context { return f.ctx }
+
+func (f *fakeWatchStream) Send(e *statepb.JobStateEvent) error {
+	f.events <- e
+	return nil
+}
+
+func newTestServer(t *testing.T, maxWatchers int) (*Server, *watch.Backend) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.State.API.MaxWatchers = maxWatchers
+
+	hub := watch.NewHub(16)
+	backend := watch.NewBackend(storage.NewMemoryBackend(), hub)
+	return NewServer(cfg, backend, hub, auth.NewNoOpAuthorization()), backend
+}
+
+func TestListJobStates(t *testing.T) {
+	server, backend := newTestServer(t, 0)
+	ctx := context.Background()
+	base := time.Unix(1700000000, 0)
+	end := base.Add(time.Minute)
+
+	jobs := []*domain.Job{
+		{Uuid: "a", Status: domain.StatusCompleted, NodeId: "n1", StartTime: base, EndTime: &end},
+		{Uuid: "b", Status: domain.StatusRunning, NodeId: "n1", StartTime: base.Add(time.Second)},
+		{Uuid: "c", Status: domain.StatusRunning, NodeId: "n2", StartTime: base.Add(2 * time.Second)},
+	}
+	for _, job := range jobs {
+		if err := backend.Create(ctx, job); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	}
+
+	resp, err := server.ListJobStates(ctx, &statepb.ListJobStatesRequest{NodeId: "n1", NewestFirst: true})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(resp.Jobs) != 2 || resp.Jobs[0].Uuid != "b" || resp.Jobs[1].Uuid != "a" {
+		t.Fatalf("unexpected jobs: %v", resp.Jobs)
+	}
+	if resp.Jobs[1].StartTime != base.Unix() || resp.Jobs[1].EndTime != end.Unix() {
+		t.Errorf("unexpected times: %v", resp.Jobs[1])
+	}
+
+	resp, err = server.ListJobStates(ctx, &statepb.ListJobStatesRequest{Statuses: []string{"RUNNING"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(resp.Jobs) != 1 || resp.Jobs[0].Uuid != "b" {
+		t.Fatalf("unexpected jobs: %v", resp.Jobs)
+	}
+
+	_, err = server.ListJobStates(ctx, &statepb.ListJobStatesRequest{Limit: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGetJobState(t *testing.T) {
+	server, backend := newTestServer(t, 0)
+	ctx := context.Background()
+
+	if err := backend.Create(ctx, &domain.Job{Uuid: "a", Status: domain.StatusRunning, GPUCount: 2}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	job, err := server.GetJobState(ctx, &statepb.GetJobStateRequest{Uuid: "a"})
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if job.Status != "RUNNING" || job.GpuCount != 2 {
+		t.Errorf("unexpected job: %v", job)
+	}
+
+	_, err = server.GetJobState(ctx, &statepb.GetJobStateRequest{Uuid: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestWatchJobStates(t *testing.T) {
+	server, backend := newTestServer(t, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := backend.Create(ctx, &domain.Job{Uuid: "old", Status: domain.StatusRunning, NodeId: "n1"}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	stream := &fakeWatchStream{ctx: ctx, events: make(chan *statepb.JobStateEvent, 16)}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchJobStates(&statepb.WatchJobStatesRequest{NodeId: "n1", InitialSnapshot: true}, stream)
+	}()
+
+	e := <-stream.events
+	if e.Type != statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_SNAPSHOT || e.Uuid != "old" {
+		t.Fatalf("expected snapshot of old, got %v", e)
+	}
+
+	// Only one watcher is allowed
+	for server.hub.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	err := server.WatchJobStates(&statepb.WatchJobStatesRequest{}, &fakeWatchStream{ctx: ctx})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+
+	// Jobs on other nodes are filtered out
+	if err := backend.Create(ctx, &domain.Job{Uuid: "other", NodeId: "n2"}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if err := backend.Create(ctx, &domain.Job{Uuid: "new", Status: domain.StatusPending, NodeId: "n1"}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if err := backend.Delete(ctx, "old"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	e = <-stream.events
+	if e.Type != statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_CREATED || e.Uuid != "new" || e.Job.Status != "PENDING" {
+		t.Fatalf("expected create of new, got %v", e)
+	}
+	e = <-stream.events
+	if e.Type != statepb.JobStateEventType_JOB_STATE_EVENT_TYPE_DELETED || e.Uuid != "old" || e.Job != nil {
+		t.Fatalf("expected delete of old, got %v", e)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("watch returned %v", err)
+	}
+}
@@ -18,6 +18,8 @@ import (
 type Server struct {
 	socketPath  string
 	backend     storage.Backend
+	leases      storage.LeaseStore
+	workflows   storage.WorkflowStore
 	listener    net.Listener
 	mu          sync.Mutex
 	connections map[string]*connection
@@ -46,6 +48,18 @@ func NewServer(socketPath string, backend storage.Backend) *Server {
 	}
 }
 
+// SetLeaseStore serves the lease operations persist instances elect their
+// active process with; without it they fail
+func (s *Server) SetLeaseStore(leases storage.LeaseStore) {
+	s.leases = leases
+}
+
+// SetWorkflowStore serves the operations the joblet checkpoints workflows
+// with; without it they fail
+func (s *Server) SetWorkflowStore(workflows storage.WorkflowStore) {
+	s.workflows = workflows
+}
+
 // Start begins listening for IPC connections
 func (s *Server) Start() error {
 	// Remove existing socket file
@@ -177,6 +191,18 @@ func (s *Server) processMessage(msg Message) *Response {
 		return s.handleSync(ctx, msg)
 	case OpPing:
 		return s.handlePing(ctx, msg)
+	case OpAcquireLease:
+		return s.handleAcquireLease(ctx, msg)
+	case OpReleaseLease:
+		return s.handleReleaseLease(ctx, msg)
+	case OpPutWorkflow:
+		return s.handlePutWorkflow(ctx, msg)
+	case OpGetWorkflow:
+		return s.handleGetWorkflow(ctx, msg)
+	case OpDeleteWorkflow:
+		return s.handleDeleteWorkflow(ctx, msg)
+	case OpListWorkflows:
+		return s.handleListWorkflows(ctx, msg)
 	default:
 		return &Response{
 			RequestID: msg.RequestID,
@@ -286,6 +312,117 @@ func (s *Server) handlePing(ctx context.Context, msg Message) *Response {
 	}
 }
 
+func (s *Server) handleAcquireLease(ctx context.Context, msg Message) *Response {
+	if s.leases == nil {
+		return s.makeError(msg.RequestID, "LEASE_ERROR", "leases are not supported")
+	}
+	if msg.Lease == nil || msg.Lease.Name == "" || msg.Lease.Holder == "" || msg.Lease.TTLMillis <= 0 {
+		return s.makeError(msg.RequestID, "LEASE_ERROR", "lease name, holder and ttl are required")
+	}
+
+	acquired, err := s.leases.AcquireLease(ctx, msg.Lease.Name, msg.Lease.Holder, time.Duration(msg.Lease.TTLMillis)*time.Millisecond)
+	if err != nil {
+		return s.makeError(msg.RequestID, "LEASE_ERROR", err.Error())
+	}
+
+	return &Response{
+		RequestID: msg.RequestID,
+		Success:   true,
+		Acquired:  acquired,
+	}
+}
+
+func (s *Server) handleReleaseLease(ctx context.Context, msg Message) *Response {
+	if s.leases == nil {
+		return s.makeError(msg.RequestID, "LEASE_ERROR", "leases are not supported")
+	}
+	if msg.Lease == nil || msg.Lease.Name == "" || msg.Lease.Holder == "" {
+		return s.makeError(msg.RequestID, "LEASE_ERROR", "lease name and holder are required")
+	}
+
+	if err := s.leases.ReleaseLease(ctx, msg.Lease.Name, msg.Lease.Holder); err != nil {
+		return s.makeError(msg.RequestID, "LEASE_ERROR", err.Error())
+	}
+
+	return &Response{
+		RequestID: msg.RequestID,
+		Success:   true,
+	}
+}
+
+func (s *Server) handlePutWorkflow(ctx context.Context, msg Message) *Response {
+	if s.workflows == nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflows are not supported")
+	}
+	if msg.Workflow == nil || msg.Workflow.UUID == "" || msg.Workflow.State == nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflow uuid and state are required")
+	}
+
+	if err := s.workflows.PutWorkflow(ctx, msg.Workflow); err != nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", err.Error())
+	}
+
+	return &Response{
+		RequestID: msg.RequestID,
+		Success:   true,
+	}
+}
+
+func (s *Server) handleGetWorkflow(ctx context.Context, msg Message) *Response {
+	if s.workflows == nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflows are not supported")
+	}
+	if msg.Workflow == nil || msg.Workflow.UUID == "" {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflow uuid is required")
+	}
+
+	wf, err := s.workflows.GetWorkflow(ctx, msg.Workflow.UUID)
+	if err != nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", err.Error())
+	}
+
+	return &Response{
+		RequestID: msg.RequestID,
+		Success:   true,
+		Workflow:  wf,
+	}
+}
+
+func (s *Server) handleDeleteWorkflow(ctx context.Context, msg Message) *Response {
+	if s.workflows == nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflows are not supported")
+	}
+	if msg.Workflow == nil || msg.Workflow.UUID == "" {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflow uuid is required")
+	}
+
+	if err := s.workflows.DeleteWorkflow(ctx, msg.Workflow.UUID); err != nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", err.Error())
+	}
+
+	return &Response{
+		RequestID: msg.RequestID,
+		Success:   true,
+	}
+}
+
+func (s *Server) handleListWorkflows(ctx context.Context, msg Message) *Response {
+	if s.workflows == nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", "workflows are not supported")
+	}
+
+	uuids, err := s.workflows.ListWorkflows(ctx)
+	if err != nil {
+		return s.makeError(msg.RequestID, "WORKFLOW_ERROR", err.Error())
+	}
+
+	return &Response{
+		RequestID:     msg.RequestID,
+		Success:       true,
+		WorkflowUUIDs: uuids,
+	}
+}
+
 func (s *Server) makeError(requestID, code, message string) *Response {
 	return &Response{
 		RequestID: requestID,
@@ -311,24 +448,44 @@ const (
 	OpList   Operation = "list"
 	OpSync   Operation = "sync"
 	OpPing   Operation = "ping"
+
+	OpAcquireLease Operation = "acquireLease"
+	OpReleaseLease Operation = "releaseLease"
+
+	OpPutWorkflow    Operation = "putWorkflow"
+	OpGetWorkflow    Operation = "getWorkflow"
+	OpDeleteWorkflow Operation = "deleteWorkflow"
+	OpListWorkflows  Operation = "listWorkflows"
 )
 
 // Message represents an IPC request message
 type Message struct {
-	Operation Operation       `json:"op"`
-	JobID     string          `json:"jobId,omitempty"`
-	Job       *domain.Job     `json:"job,omitempty"`
-	Jobs      []*domain.Job   `json:"jobs,omitempty"`
-	Filter    *storage.Filter `json:"filter,omitempty"`
-	RequestID string          `json:"requestId"`
-	Timestamp int64           `json:"timestamp"`
+	Operation Operation         `json:"op"`
+	JobID     string            `json:"jobId,omitempty"`
+	Job       *domain.Job       `json:"job,omitempty"`
+	Jobs      []*domain.Job     `json:"jobs,omitempty"`
+	Filter    *storage.Filter   `json:"filter,omitempty"`
+	Lease     *Lease            `json:"lease,omitempty"`
+	Workflow  *storage.Workflow `json:"workflow,omitempty"`
+	RequestID string            `json:"requestId"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// Lease names a lease and its holder for the lease operations
+type Lease struct {
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	TTLMillis int64  `json:"ttlMs,omitempty"`
 }
 
 // Response represents an IPC response message
 type Response struct {
-	RequestID string        `json:"requestId"`
-	Success   bool          `json:"success"`
-	Job       *domain.Job   `json:"job,omitempty"`
-	Jobs      []*domain.Job `json:"jobs,omitempty"`
-	Error     string        `json:"error,omitempty"`
+	RequestID     string            `json:"requestId"`
+	Success       bool              `json:"success"`
+	Job           *domain.Job       `json:"job,omitempty"`
+	Jobs          []*domain.Job     `json:"jobs,omitempty"`
+	Acquired      bool              `json:"acquired,omitempty"` // Lease taken by the requesting holder
+	Workflow      *storage.Workflow `json:"workflow,omitempty"`
+	WorkflowUUIDs []string          `json:"workflowUuids,omitempty"`
+	Error         string            `json:"error,omitempty"`
 }
@@ -540,3 +540,103 @@ func TestServer_StopGraceful(t *testing.T) {
 		t.Error("expected socket to be removed after stop")
 	}
 }
+
+func TestServer_LeaseOperations(t *testing.T) {
+	backend := storage.NewMemoryBackend()
+	socketPath := "/tmp/test-state-lease-" + time.Now().Format("20060102150405") + ".sock"
+
+	server := NewServer(socketPath, backend)
+	server.SetLeaseStore(backend.(storage.LeaseStore))
+	if err := server.Start(); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Stop()
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+
+	send := func(op Operation, holder string) Response {
+		t.Helper()
+		data, _ := json.Marshal(Message{
+			Operation: op,
+			Lease:     &Lease{Name: "persist", Holder: holder, TTLMillis: 60000},
+			RequestID: "req-lease",
+		})
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		var response Response
+		if err := dec.Decode(&response); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		if !response.Success {
+			t.Fatalf("%s for %s failed: %s", op, holder, response.Error)
+		}
+		return response
+	}
+
+	if !send(OpAcquireLease, "active").Acquired {
+		t.Error("active could not take a free lease")
+	}
+	if send(OpAcquireLease, "standby").Acquired {
+		t.Error("standby took the active's lease")
+	}
+	send(OpReleaseLease, "active")
+	if !send(OpAcquireLease, "standby").Acquired {
+		t.Error("standby could not take the released lease")
+	}
+}
+
+func TestServer_WorkflowOperations(t *testing.T) {
+	backend := storage.NewMemoryBackend()
+	socketPath := "/tmp/test-state-workflow-" + time.Now().Format("20060102150405") + ".sock"
+
+	server := NewServer(socketPath, backend)
+	server.SetWorkflowStore(backend.(storage.WorkflowStore))
+	if err := server.Start(); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Stop()
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+
+	send := func(op Operation, wf *storage.Workflow) Response {
+		t.Helper()
+		data, _ := json.Marshal(Message{Operation: op, Workflow: wf, RequestID: "req-workflow"})
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		var response Response
+		if err := dec.Decode(&response); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		if !response.Success {
+			t.Fatalf("%s failed: %s", op, response.Error)
+		}
+		return response
+	}
+
+	send(OpPutWorkflow, &storage.Workflow{UUID: "wf-1", Meta: []byte(`{"tenant":"a"}`), State: []byte(`{"status":"RUNNING"}`)})
+	send(OpPutWorkflow, &storage.Workflow{UUID: "wf-1", State: []byte(`{"status":"COMPLETED"}`)})
+
+	if uuids := send(OpListWorkflows, nil).WorkflowUUIDs; len(uuids) != 1 || uuids[0] != "wf-1" {
+		t.Errorf("listWorkflows = %v, want [wf-1]", uuids)
+	}
+	wf := send(OpGetWorkflow, &storage.Workflow{UUID: "wf-1"}).Workflow
+	if wf == nil || string(wf.Meta) != `{"tenant":"a"}` || string(wf.State) != `{"status":"COMPLETED"}` {
+		t.Errorf("getWorkflow = %+v, want the saved meta and the latest state", wf)
+	}
+	send(OpDeleteWorkflow, &storage.Workflow{UUID: "wf-1"})
+	if uuids := send(OpListWorkflows, nil).WorkflowUUIDs; len(uuids) != 0 {
+		t.Errorf("listWorkflows after delete = %v, want none", uuids)
+	}
+}
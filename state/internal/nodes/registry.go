@@ -0,0 +1,83 @@
+// Package nodes keeps the live joblet nodes registered with the state
+// service. Registrations live in memory only: after a restart nodes
+// reappear with their next heartbeat.
+package nodes
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ehsaniara/joblet/internal/joblet/coordination"
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+)
+
+// DefaultTTL applies to nodes registering without a TTL
+const DefaultTTL = 30 * time.Second
+
+// Registry holds node registrations until their TTL passes without a heartbeat
+type Registry struct {
+	mu    sync.Mutex
+	nodes map[string]*nodespb.NodeInfo
+	now   func() time.Time
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		nodes: make(map[string]*nodespb.NodeInfo),
+		now:   time.Now,
+	}
+}
+
+// Heartbeat stores the node's registration and reports whether the node was
+// new, or had expired before this heartbeat. The registry sets the
+// registration and heartbeat times itself, so node clocks do not matter.
+func (r *Registry) Heartbeat(node *nodespb.NodeInfo) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	stored := proto.Clone(node).(*nodespb.NodeInfo)
+	stored.LastHeartbeat = now.Unix()
+	stored.RegisteredAt = now.Unix()
+
+	previous, ok := r.nodes[node.NodeId]
+	registered := !ok || r.expired(previous, now)
+	if !registered {
+		stored.RegisteredAt = previous.RegisteredAt
+	}
+	r.nodes[node.NodeId] = stored
+	return registered
+}
+
+// List returns the live nodes carrying all of the given labels, sorted by
+// node ID. Expired registrations are dropped on the way.
+func (r *Registry) List(labels map[string]string) []*nodespb.NodeInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	nodes := make([]*nodespb.NodeInfo, 0, len(r.nodes))
+	for id, node := range r.nodes {
+		if r.expired(node, now) {
+			delete(r.nodes, id)
+			continue
+		}
+		if coordination.MatchesLabels(node, labels) {
+			nodes = append(nodes, proto.Clone(node).(*nodespb.NodeInfo))
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeId < nodes[j].NodeId })
+	return nodes
+}
+
+func (r *Registry) expired(node *nodespb.NodeInfo, now time.Time) bool {
+	ttl := time.Duration(node.TtlSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return now.Sub(time.Unix(node.LastHeartbeat, 0)) > ttl
+}
@@ -0,0 +1,70 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	nodespb "github.com/ehsaniara/joblet/internal/proto/gen/nodes"
+)
+
+func TestRegistry_HeartbeatKeepsRegistrationTime(t *testing.T) {
+	r := NewRegistry()
+	clock := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return clock }
+
+	if !r.Heartbeat(&nodespb.NodeInfo{NodeId: "node-1", TtlSeconds: 30}) {
+		t.Fatal("first heartbeat should register the node")
+	}
+	clock = clock.Add(10 * time.Second)
+	if r.Heartbeat(&nodespb.NodeInfo{NodeId: "node-1", TtlSeconds: 30, Address: "10.0.0.5:50051"}) {
+		t.Error("second heartbeat within the TTL should not register again")
+	}
+
+	nodes := r.List(nil)
+	if len(nodes) != 1 {
+		t.Fatalf("nodes = %v", nodes)
+	}
+	if nodes[0].RegisteredAt != 1700000000 || nodes[0].LastHeartbeat != 1700000010 || nodes[0].Address != "10.0.0.5:50051" {
+		t.Errorf("node = %v", nodes[0])
+	}
+}
+
+func TestRegistry_ExpiresNodes(t *testing.T) {
+	r := NewRegistry()
+	clock := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return clock }
+
+	r.Heartbeat(&nodespb.NodeInfo{NodeId: "short", TtlSeconds: 5})
+	r.Heartbeat(&nodespb.NodeInfo{NodeId: "default"})
+
+	clock = clock.Add(10 * time.Second)
+	nodes := r.List(nil)
+	if len(nodes) != 1 || nodes[0].NodeId != "default" {
+		t.Fatalf("nodes = %v, want only the node with the default TTL", nodes)
+	}
+
+	if !r.Heartbeat(&nodespb.NodeInfo{NodeId: "short", TtlSeconds: 60}) {
+		t.Error("heartbeat after expiry should register the node again")
+	}
+
+	clock = clock.Add(DefaultTTL)
+	if nodes := r.List(nil); len(nodes) != 1 || nodes[0].NodeId != "short" {
+		t.Errorf("nodes = %v, want the default TTL node expired", nodes)
+	}
+}
+
+func TestRegistry_ListFiltersLabels(t *testing.T) {
+	r := NewRegistry()
+	r.Heartbeat(&nodespb.NodeInfo{NodeId: "b", Labels: map[string]string{"zone": "eu", "gpu": "true"}})
+	r.Heartbeat(&nodespb.NodeInfo{NodeId: "a", Labels: map[string]string{"zone": "eu"}})
+	r.Heartbeat(&nodespb.NodeInfo{NodeId: "c", Labels: map[string]string{"zone": "us"}})
+
+	nodes := r.List(map[string]string{"zone": "eu"})
+	if len(nodes) != 2 || nodes[0].NodeId != "a" || nodes[1].NodeId != "b" {
+		t.Errorf("zone=eu = %v", nodes)
+	}
+	nodes = r.List(map[string]string{"zone": "eu", "gpu": "true"})
+	if len(nodes) != 1 || nodes[0].NodeId != "b" {
+		t.Errorf("zone=eu,gpu=true = %v", nodes)
+	}
+}
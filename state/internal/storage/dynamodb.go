@@ -176,8 +176,15 @@ func (d *dynamoDBBackend) List(ctx context.Context, filter *Filter) ([]*domain.J
 		return nil, &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to scan jobs", Err: err}
 	}
 
-	// Convert items to jobs
+	// Convert items to jobs, skipping the leases and workflows kept in the
+	// same table
 	for _, item := range result.Items {
+		if _, isLease := item["leaseHolder"]; isLease {
+			continue
+		}
+		if _, isWorkflow := item["workflowState"]; isWorkflow {
+			continue
+		}
 		job, err := itemToJob(item)
 		if err != nil {
 			// Log error but continue with other items
@@ -416,3 +416,96 @@ func TestDynamoDB_Close(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestDynamoDB_Lease(t *testing.T) {
+	mockClient := &storagefakes.FakeDynamoDBAPI{}
+	leases := storage.NewDynamoDBBackendWithClient(mockClient, "test-table", 30).(storage.LeaseStore)
+	ctx := context.Background()
+
+	mockClient.PutItemReturns(&dynamodb.PutItemOutput{}, nil)
+	if ok, err := leases.AcquireLease(ctx, "state", "host/1", 10*time.Second); err != nil || !ok {
+		t.Fatalf("AcquireLease() = %v, %v", ok, err)
+	}
+	_, input, _ := mockClient.PutItemArgsForCall(0)
+	if key := input.Item["jobId"].(*types.AttributeValueMemberS).Value; key != "lease#state" {
+		t.Errorf("lease key = %q", key)
+	}
+	if holder := input.ExpressionAttributeValues[":holder"].(*types.AttributeValueMemberS).Value; holder != "host/1" ||
+		*input.ConditionExpression != "attribute_not_exists(jobId) OR leaseHolder = :holder OR leaseExpires < :now" {
+		t.Errorf("condition = %s with holder %q", *input.ConditionExpression, holder)
+	}
+
+	// Another holder's unexpired lease fails the condition
+	mockClient.PutItemReturns(nil, &types.ConditionalCheckFailedException{Message: aws.String("held")})
+	if ok, err := leases.AcquireLease(ctx, "state", "host/2", 10*time.Second); err != nil || ok {
+		t.Errorf("AcquireLease() of a held lease = %v, %v", ok, err)
+	}
+
+	mockClient.PutItemReturns(nil, fmt.Errorf("throttled"))
+	if _, err := leases.AcquireLease(ctx, "state", "host/2", 10*time.Second); err == nil {
+		t.Error("AcquireLease() hid a DynamoDB error")
+	}
+}
+
+func TestDynamoDB_List_SkipsLeases(t *testing.T) {
+	mockClient := &storagefakes.FakeDynamoDBAPI{}
+	backend := storage.NewDynamoDBBackendWithClient(mockClient, "test-table", 30)
+
+	mockClient.ScanReturns(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+		{"jobId": &types.AttributeValueMemberS{Value: "job-1"}, "jobStatus": &types.AttributeValueMemberS{Value: "RUNNING"}},
+		{"jobId": &types.AttributeValueMemberS{Value: "lease#persist"}, "leaseHolder": &types.AttributeValueMemberS{Value: "host/1"}},
+		{"jobId": &types.AttributeValueMemberS{Value: "workflow#wf-1"}, "workflowState": &types.AttributeValueMemberB{Value: []byte("{}")}},
+	}}, nil)
+
+	jobs, err := backend.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Uuid != "job-1" {
+		t.Errorf("List() = %+v, want only job-1", jobs)
+	}
+}
+
+func TestDynamoDB_Workflows(t *testing.T) {
+	mockClient := &storagefakes.FakeDynamoDBAPI{}
+	workflows := storage.NewDynamoDBBackendWithClient(mockClient, "test-table", 30).(storage.WorkflowStore)
+	ctx := context.Background()
+
+	mockClient.PutItemReturns(&dynamodb.PutItemOutput{}, nil)
+	if err := workflows.PutWorkflow(ctx, &storage.Workflow{UUID: "wf-1", Meta: []byte("{}"), State: []byte("{}")}); err != nil {
+		t.Fatalf("PutWorkflow() error = %v", err)
+	}
+	_, input, _ := mockClient.PutItemArgsForCall(0)
+	if key := input.Item["jobId"].(*types.AttributeValueMemberS).Value; key != "workflow#wf-1" {
+		t.Errorf("workflow key = %q", key)
+	}
+
+	// A state alone only updates the state attribute, keeping the meta
+	mockClient.UpdateItemReturns(&dynamodb.UpdateItemOutput{}, nil)
+	if err := workflows.PutWorkflow(ctx, &storage.Workflow{UUID: "wf-1", State: []byte("{}")}); err != nil {
+		t.Fatalf("PutWorkflow(state only) error = %v", err)
+	}
+	if mockClient.PutItemCallCount() != 1 || mockClient.UpdateItemCallCount() != 1 {
+		t.Errorf("PutWorkflow(state only) made %d puts and %d updates, want one update", mockClient.PutItemCallCount()-1, mockClient.UpdateItemCallCount())
+	}
+	mockClient.UpdateItemReturns(nil, &types.ConditionalCheckFailedException{Message: aws.String("missing")})
+	if err := workflows.PutWorkflow(ctx, &storage.Workflow{UUID: "wf-2", State: []byte("{}")}); err != storage.ErrWorkflowNotFound {
+		t.Errorf("PutWorkflow(state only) of a missing workflow error = %v, want ErrWorkflowNotFound", err)
+	}
+
+	// Listing follows the scan's pages
+	mockClient.ScanReturnsOnCall(0, &dynamodb.ScanOutput{
+		Items:            []map[string]types.AttributeValue{{"jobId": &types.AttributeValueMemberS{Value: "workflow#wf-1"}}},
+		LastEvaluatedKey: map[string]types.AttributeValue{"jobId": &types.AttributeValueMemberS{Value: "workflow#wf-1"}},
+	}, nil)
+	mockClient.ScanReturnsOnCall(1, &dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{{"jobId": &types.AttributeValueMemberS{Value: "workflow#wf-3"}}},
+	}, nil)
+	uuids, err := workflows.ListWorkflows(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkflows() error = %v", err)
+	}
+	if len(uuids) != 2 || uuids[0] != "wf-1" || uuids[1] != "wf-3" {
+		t.Errorf("ListWorkflows() = %v, want [wf-1 wf-3]", uuids)
+	}
+}
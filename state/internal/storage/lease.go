@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ehsaniara/joblet/internal/joblet/leader"
+)
+
+// LeaseStore keeps the named leases electing the active persist and state
+// processes of a node (see package leader)
+type LeaseStore interface {
+	// AcquireLease takes or extends the lease for holder; false when another
+	// holder's lease has not expired
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLease gives the lease up if holder has it
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// leaseKeyPrefix keys lease items in the jobs table apart from jobs
+const leaseKeyPrefix = "lease#"
+
+// Lease returns the named lease of a store as a leader.Lease
+func Lease(store LeaseStore, name string) leader.Lease {
+	return &storeLease{store: store, name: name}
+}
+
+type storeLease struct {
+	store LeaseStore
+	name  string
+}
+
+func (l *storeLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	return l.store.AcquireLease(ctx, l.name, holder, ttl)
+}
+
+func (l *storeLease) Release(ctx context.Context, holder string) error {
+	return l.store.ReleaseLease(ctx, l.name, holder)
+}
+
+func (m *memoryBackend) lease(name string) *leader.MemoryLease {
+	m.leasesMu.Lock()
+	defer m.leasesMu.Unlock()
+	l, ok := m.leases[name]
+	if !ok {
+		l = leader.NewMemoryLease()
+		m.leases[name] = l
+	}
+	return l
+}
+
+func (m *memoryBackend) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	return m.lease(name).Acquire(ctx, holder, ttl)
+}
+
+func (m *memoryBackend) ReleaseLease(ctx context.Context, name, holder string) error {
+	return m.lease(name).Release(ctx, holder)
+}
+
+// AcquireLease writes the lease item only if it is missing, already held by
+// holder or expired, so of two instances racing for it one write fails
+func (d *dynamoDBBackend) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	expires := now.Add(ttl)
+	item := map[string]types.AttributeValue{
+		"jobId":        &types.AttributeValueMemberS{Value: leaseKeyPrefix + name},
+		"leaseHolder":  &types.AttributeValueMemberS{Value: holder},
+		"leaseExpires": &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.UnixMilli(), 10)},
+	}
+	if d.ttlDays > 0 {
+		// Let the table's TTL remove leases of nodes that are gone
+		item["expiresAt"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.Add(24*time.Hour).Unix(), 10)}
+	}
+
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(jobId) OR leaseHolder = :holder OR leaseExpires < :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":holder": &types.AttributeValueMemberS{Value: holder},
+			":now":    &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+		},
+	})
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return false, nil
+		}
+		return false, &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to acquire lease", Err: err}
+	}
+	return true, nil
+}
+
+// ReleaseLease deletes the lease item if holder has it
+func (d *dynamoDBBackend) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"jobId": &types.AttributeValueMemberS{Value: leaseKeyPrefix + name},
+		},
+		ConditionExpression: aws.String("leaseHolder = :holder"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":holder": &types.AttributeValueMemberS{Value: holder},
+		},
+	})
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return nil
+		}
+		return &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to release lease", Err: err}
+	}
+	return nil
+}
+
+var (
+	_ LeaseStore = (*memoryBackend)(nil)
+	_ LeaseStore = (*dynamoDBBackend)(nil)
+)
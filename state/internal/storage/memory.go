@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/internal/joblet/leader"
 )
 
 // memoryBackend is an in-memory implementation of Backend interface.
@@ -15,12 +16,20 @@ import (
 type memoryBackend struct {
 	mu   sync.RWMutex
 	jobs map[string]*domain.Job
+
+	leasesMu sync.Mutex
+	leases   map[string]*leader.MemoryLease
+
+	workflowsMu sync.Mutex
+	workflows   map[string]*Workflow
 }
 
 // NewMemoryBackend creates a new in-memory storage backend
 func NewMemoryBackend() Backend {
 	return &memoryBackend{
-		jobs: make(map[string]*domain.Job),
+		jobs:      make(map[string]*domain.Job),
+		leases:    make(map[string]*leader.MemoryLease),
+		workflows: make(map[string]*Workflow),
 	}
 }
 
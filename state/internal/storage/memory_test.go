@@ -255,3 +255,59 @@ func TestMemoryBackend_Close(t *testing.T) {
 		t.Errorf("Close failed: %v", err)
 	}
 }
+
+func TestMemoryBackend_Leases(t *testing.T) {
+	leases := NewMemoryBackend().(LeaseStore)
+	ctx := context.Background()
+
+	if ok, err := leases.AcquireLease(ctx, "persist", "a", time.Minute); err != nil || !ok {
+		t.Fatalf("AcquireLease(a) = %v, %v", ok, err)
+	}
+	if ok, _ := leases.AcquireLease(ctx, "persist", "b", time.Minute); ok {
+		t.Error("b took a's lease")
+	}
+	// Leases are independent by name
+	if ok, _ := leases.AcquireLease(ctx, "state", "b", time.Minute); !ok {
+		t.Error("b could not take another lease")
+	}
+	if err := leases.ReleaseLease(ctx, "persist", "a"); err != nil {
+		t.Fatalf("ReleaseLease() error = %v", err)
+	}
+	if ok, _ := leases.AcquireLease(ctx, "persist", "b", time.Minute); !ok {
+		t.Error("b could not take the released lease")
+	}
+}
+
+func TestMemoryBackend_Workflows(t *testing.T) {
+	workflows := NewMemoryBackend().(WorkflowStore)
+	ctx := context.Background()
+
+	// A state alone needs a workflow saved with its meta first
+	if err := workflows.PutWorkflow(ctx, &Workflow{UUID: "wf-1", State: []byte(`{"status":"RUNNING"}`)}); err != ErrWorkflowNotFound {
+		t.Errorf("PutWorkflow(state only) of a new workflow error = %v, want ErrWorkflowNotFound", err)
+	}
+	if err := workflows.PutWorkflow(ctx, &Workflow{UUID: "wf-1", Meta: []byte(`{"tenant":"a"}`), State: []byte(`{"status":"RUNNING"}`)}); err != nil {
+		t.Fatalf("PutWorkflow() error = %v", err)
+	}
+	if err := workflows.PutWorkflow(ctx, &Workflow{UUID: "wf-1", State: []byte(`{"status":"COMPLETED"}`)}); err != nil {
+		t.Fatalf("PutWorkflow(state only) error = %v", err)
+	}
+
+	wf, err := workflows.GetWorkflow(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("GetWorkflow() error = %v", err)
+	}
+	if string(wf.Meta) != `{"tenant":"a"}` || string(wf.State) != `{"status":"COMPLETED"}` {
+		t.Errorf("GetWorkflow() = meta %s, state %s, want the meta kept and the state replaced", wf.Meta, wf.State)
+	}
+
+	if uuids, _ := workflows.ListWorkflows(ctx); len(uuids) != 1 || uuids[0] != "wf-1" {
+		t.Errorf("ListWorkflows() = %v, want [wf-1]", uuids)
+	}
+	if err := workflows.DeleteWorkflow(ctx, "wf-1"); err != nil {
+		t.Fatalf("DeleteWorkflow() error = %v", err)
+	}
+	if _, err := workflows.GetWorkflow(ctx, "wf-1"); err != ErrWorkflowNotFound {
+		t.Errorf("GetWorkflow() after delete error = %v, want ErrWorkflowNotFound", err)
+	}
+}
@@ -100,6 +100,21 @@ type FakeDynamoDBAPI struct {
 		result1 *dynamodb.ScanOutput
 		result2 error
 	}
+	UpdateItemStub        func(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	updateItemMutex       sync.RWMutex
+	updateItemArgsForCall []struct {
+		arg1 context.Context
+		arg2 *dynamodb.UpdateItemInput
+		arg3 []func(*dynamodb.Options)
+	}
+	updateItemReturns struct {
+		result1 *dynamodb.UpdateItemOutput
+		result2 error
+	}
+	updateItemReturnsOnCall map[int]struct {
+		result1 *dynamodb.UpdateItemOutput
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -500,6 +515,72 @@ func (fake *FakeDynamoDBAPI) ScanReturnsOnCall(i int, result1 *dynamodb.ScanOutp
 	}{result1, result2}
 }
 
+func (fake *FakeDynamoDBAPI) UpdateItem(arg1 context.Context, arg2 *dynamodb.UpdateItemInput, arg3 ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	fake.updateItemMutex.Lock()
+	ret, specificReturn := fake.updateItemReturnsOnCall[len(fake.updateItemArgsForCall)]
+	fake.updateItemArgsForCall = append(fake.updateItemArgsForCall, struct {
+		arg1 context.Context
+		arg2 *dynamodb.UpdateItemInput
+		arg3 []func(*dynamodb.Options)
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateItemStub
+	fakeReturns := fake.updateItemReturns
+	fake.recordInvocation("UpdateItem", []interface{}{arg1, arg2, arg3})
+	fake.updateItemMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDynamoDBAPI) UpdateItemCallCount() int {
+	fake.updateItemMutex.RLock()
+	defer fake.updateItemMutex.RUnlock()
+	return len(fake.updateItemArgsForCall)
+}
+
+func (fake *FakeDynamoDBAPI) UpdateItemCalls(stub func(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)) {
+	fake.updateItemMutex.Lock()
+	defer fake.updateItemMutex.Unlock()
+	fake.UpdateItemStub = stub
+}
+
+func (fake *FakeDynamoDBAPI) UpdateItemArgsForCall(i int) (context.Context, *dynamodb.UpdateItemInput, []func(*dynamodb.Options)) {
+	fake.updateItemMutex.RLock()
+	defer fake.updateItemMutex.RUnlock()
+	argsForCall := fake.updateItemArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeDynamoDBAPI) UpdateItemReturns(result1 *dynamodb.UpdateItemOutput, result2 error) {
+	fake.updateItemMutex.Lock()
+	defer fake.updateItemMutex.Unlock()
+	fake.UpdateItemStub = nil
+	fake.updateItemReturns = struct {
+		result1 *dynamodb.UpdateItemOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDynamoDBAPI) UpdateItemReturnsOnCall(i int, result1 *dynamodb.UpdateItemOutput, result2 error) {
+	fake.updateItemMutex.Lock()
+	defer fake.updateItemMutex.Unlock()
+	fake.UpdateItemStub = nil
+	if fake.updateItemReturnsOnCall == nil {
+		fake.updateItemReturnsOnCall = make(map[int]struct {
+			result1 *dynamodb.UpdateItemOutput
+			result2 error
+		})
+	}
+	fake.updateItemReturnsOnCall[i] = struct {
+		result1 *dynamodb.UpdateItemOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeDynamoDBAPI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// WorkflowStore keeps the joblet's workflow checkpoints, so workflows survive
+// joblet restarts. Checkpoints are opaque to the state service.
+type WorkflowStore interface {
+	// PutWorkflow saves a checkpoint. Without Meta only the state is
+	// replaced, and the workflow must have been saved with its Meta before.
+	PutWorkflow(ctx context.Context, wf *Workflow) error
+	// GetWorkflow returns a checkpoint, ErrWorkflowNotFound if missing
+	GetWorkflow(ctx context.Context, uuid string) (*Workflow, error)
+	// DeleteWorkflow removes a checkpoint; missing ones are ignored
+	DeleteWorkflow(ctx context.Context, uuid string) error
+	// ListWorkflows returns the UUIDs of every checkpoint
+	ListWorkflows(ctx context.Context) ([]string, error)
+}
+
+// Workflow is a workflow checkpoint
+type Workflow struct {
+	UUID  string          `json:"uuid"`
+	Meta  json.RawMessage `json:"meta,omitempty"`  // What resuming the workflow needs besides its state
+	State json.RawMessage `json:"state,omitempty"` // Dependency state, replaced on every change
+}
+
+// ErrWorkflowNotFound is returned for a workflow without a checkpoint
+var ErrWorkflowNotFound = &StorageError{Code: "WORKFLOW_NOT_FOUND", Message: "workflow not found"}
+
+// workflowKeyPrefix keys workflow items in the jobs table apart from jobs
+const workflowKeyPrefix = "workflow#"
+
+func (m *memoryBackend) PutWorkflow(ctx context.Context, wf *Workflow) error {
+	m.workflowsMu.Lock()
+	defer m.workflowsMu.Unlock()
+
+	saved := *wf
+	if saved.Meta == nil {
+		existing, ok := m.workflows[wf.UUID]
+		if !ok {
+			return ErrWorkflowNotFound
+		}
+		saved.Meta = existing.Meta
+	}
+	m.workflows[wf.UUID] = &saved
+	return nil
+}
+
+func (m *memoryBackend) GetWorkflow(ctx context.Context, uuid string) (*Workflow, error) {
+	m.workflowsMu.Lock()
+	defer m.workflowsMu.Unlock()
+
+	wf, ok := m.workflows[uuid]
+	if !ok {
+		return nil, ErrWorkflowNotFound
+	}
+	saved := *wf
+	return &saved, nil
+}
+
+func (m *memoryBackend) DeleteWorkflow(ctx context.Context, uuid string) error {
+	m.workflowsMu.Lock()
+	defer m.workflowsMu.Unlock()
+
+	delete(m.workflows, uuid)
+	return nil
+}
+
+func (m *memoryBackend) ListWorkflows(ctx context.Context) ([]string, error) {
+	m.workflowsMu.Lock()
+	defer m.workflowsMu.Unlock()
+
+	uuids := make([]string, 0, len(m.workflows))
+	for uuid := range m.workflows {
+		uuids = append(uuids, uuid)
+	}
+	return uuids, nil
+}
+
+// PutWorkflow writes the whole item when Meta is set, otherwise replaces the
+// state attribute of the existing item only, so frequent state changes do not
+// rewrite the uploaded files held in Meta
+func (d *dynamoDBBackend) PutWorkflow(ctx context.Context, wf *Workflow) error {
+	key := &types.AttributeValueMemberS{Value: workflowKeyPrefix + wf.UUID}
+	if wf.Meta != nil {
+		_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(d.tableName),
+			Item: map[string]types.AttributeValue{
+				"jobId":         key,
+				"workflowMeta":  &types.AttributeValueMemberB{Value: wf.Meta},
+				"workflowState": &types.AttributeValueMemberB{Value: wf.State},
+			},
+		})
+		if err != nil {
+			return &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to save workflow", Err: err}
+		}
+		return nil
+	}
+
+	_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(d.tableName),
+		Key:                 map[string]types.AttributeValue{"jobId": key},
+		UpdateExpression:    aws.String("SET workflowState = :state"),
+		ConditionExpression: aws.String("attribute_exists(jobId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":state": &types.AttributeValueMemberB{Value: wf.State},
+		},
+	})
+	if err != nil {
+		if _, ok := err.(*types.ConditionalCheckFailedException); ok {
+			return ErrWorkflowNotFound
+		}
+		return &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to save workflow state", Err: err}
+	}
+	return nil
+}
+
+func (d *dynamoDBBackend) GetWorkflow(ctx context.Context, uuid string) (*Workflow, error) {
+	result, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"jobId": &types.AttributeValueMemberS{Value: workflowKeyPrefix + uuid},
+		},
+	})
+	if err != nil {
+		return nil, &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to get workflow", Err: err}
+	}
+	if result.Item == nil {
+		return nil, ErrWorkflowNotFound
+	}
+
+	wf := &Workflow{UUID: uuid}
+	if v, ok := result.Item["workflowMeta"].(*types.AttributeValueMemberB); ok {
+		wf.Meta = v.Value
+	}
+	if v, ok := result.Item["workflowState"].(*types.AttributeValueMemberB); ok {
+		wf.State = v.Value
+	}
+	return wf, nil
+}
+
+func (d *dynamoDBBackend) DeleteWorkflow(ctx context.Context, uuid string) error {
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"jobId": &types.AttributeValueMemberS{Value: workflowKeyPrefix + uuid},
+		},
+	})
+	if err != nil {
+		return &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to delete workflow", Err: err}
+	}
+	return nil
+}
+
+// ListWorkflows scans the keys of the workflow items only, page by page
+func (d *dynamoDBBackend) ListWorkflows(ctx context.Context) ([]string, error) {
+	input := &dynamodb.ScanInput{
+		TableName:            aws.String(d.tableName),
+		FilterExpression:     aws.String("begins_with(jobId, :prefix)"),
+		ProjectionExpression: aws.String("jobId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prefix": &types.AttributeValueMemberS{Value: workflowKeyPrefix},
+		},
+	}
+
+	var uuids []string
+	for {
+		result, err := d.client.Scan(ctx, input)
+		if err != nil {
+			return nil, &StorageError{Code: "DYNAMODB_ERROR", Message: "failed to scan workflows", Err: err}
+		}
+		for _, item := range result.Items {
+			if key, ok := item["jobId"].(*types.AttributeValueMemberS); ok {
+				uuids = append(uuids, strings.TrimPrefix(key.Value, workflowKeyPrefix))
+			}
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return uuids, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+var (
+	_ WorkflowStore = (*memoryBackend)(nil)
+	_ WorkflowStore = (*dynamoDBBackend)(nil)
+)
@@ -0,0 +1,162 @@
+// Package watch fans out job state changes recorded over IPC to the
+// WatchJobStates streams of the read API.
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/state/internal/storage"
+)
+
+// EventType says what happened to a job state.
+type EventType int
+
+const (
+	Created EventType = iota + 1
+	Updated
+	Deleted
+)
+
+// Event is one job state change. Job is nil for Deleted.
+type Event struct {
+	Type  EventType
+	JobID string
+	Job   *domain.Job
+	Time  time.Time
+}
+
+// Subscription receives events until it is closed or falls behind. A
+// subscriber that does not keep up is dropped rather than slowing down IPC
+// writes; its channel is closed and Dropped reports true.
+type Subscription struct {
+	Events <-chan Event
+
+	hub     *Hub
+	id      uint64
+	ch      chan Event
+	dropped bool
+}
+
+// Dropped reports whether the hub closed the subscription because its
+// buffer was full.
+func (s *Subscription) Dropped() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.dropped
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s.id]; ok {
+		delete(s.hub.subs, s.id)
+		close(s.ch)
+	}
+}
+
+// Hub broadcasts job state changes to subscribers.
+type Hub struct {
+	mu         sync.Mutex
+	subs       map[uint64]*Subscription
+	nextID     uint64
+	bufferSize int
+}
+
+// NewHub creates a hub whose subscribers buffer up to bufferSize events.
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = 256
+	}
+	return &Hub{
+		subs:       make(map[uint64]*Subscription),
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscribe registers a new subscriber.
+func (h *Hub) Subscribe() *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	ch := make(chan Event, h.bufferSize)
+	sub := &Subscription{Events: ch, hub: h, id: h.nextID, ch: ch}
+	h.subs[sub.id] = sub
+	return sub
+}
+
+// Subscribers returns the number of active subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Publish delivers e to every subscriber without blocking.
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, sub := range h.subs {
+		select {
+		case sub.ch <- e:
+		default:
+			sub.dropped = true
+			delete(h.subs, id)
+			close(sub.ch)
+		}
+	}
+}
+
+// Backend wraps a storage backend and publishes every successful write to
+// the hub. Reads pass through unchanged.
+type Backend struct {
+	storage.Backend
+	hub *Hub
+	now func() time.Time
+}
+
+// NewBackend wraps backend so its writes are published to hub.
+func NewBackend(backend storage.Backend, hub *Hub) *Backend {
+	return &Backend{Backend: backend, hub: hub, now: time.Now}
+}
+
+func (b *Backend) Create(ctx context.Context, job *domain.Job) error {
+	if err := b.Backend.Create(ctx, job); err != nil {
+		return err
+	}
+	b.hub.Publish(Event{Type: Created, JobID: job.Uuid, Job: job.DeepCopy(), Time: b.now()})
+	return nil
+}
+
+func (b *Backend) Update(ctx context.Context, job *domain.Job) error {
+	if err := b.Backend.Update(ctx, job); err != nil {
+		return err
+	}
+	b.hub.Publish(Event{Type: Updated, JobID: job.Uuid, Job: job.DeepCopy(), Time: b.now()})
+	return nil
+}
+
+func (b *Backend) Delete(ctx context.Context, jobID string) error {
+	if err := b.Backend.Delete(ctx, jobID); err != nil {
+		return err
+	}
+	b.hub.Publish(Event{Type: Deleted, JobID: jobID, Time: b.now()})
+	return nil
+}
+
+// Sync publishes each reconciled job as an update.
+func (b *Backend) Sync(ctx context.Context, jobs []*domain.Job) error {
+	if err := b.Backend.Sync(ctx, jobs); err != nil {
+		return err
+	}
+	now := b.now()
+	for _, job := range jobs {
+		b.hub.Publish(Event{Type: Updated, JobID: job.Uuid, Job: job.DeepCopy(), Time: now})
+	}
+	return nil
+}
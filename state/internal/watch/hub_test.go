@@ -0,0 +1,97 @@
+package watch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ehsaniara/joblet/internal/joblet/domain"
+	"github.com/ehsaniara/joblet/state/internal/storage"
+)
+
+func TestBackend_PublishesWrites(t *testing.T) {
+	hub := NewHub(10)
+	sub := hub.Subscribe()
+	defer sub.Close()
+
+	backend := NewBackend(storage.NewMemoryBackend(), hub)
+	ctx := context.Background()
+
+	job := &domain.Job{Uuid: "job-1", Status: domain.StatusRunning}
+	if err := backend.Create(ctx, job); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	job.Status = domain.StatusCompleted
+	if err := backend.Update(ctx, job); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := backend.Delete(ctx, "job-1"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	// A failed write is not published
+	if err := backend.Update(ctx, &domain.Job{Uuid: "missing"}); err == nil {
+		t.Fatal("expected update of missing job to fail")
+	}
+
+	want := []struct {
+		typ    EventType
+		status domain.JobStatus
+	}{
+		{Created, domain.StatusRunning},
+		{Updated, domain.StatusCompleted},
+		{Deleted, ""},
+	}
+	for i, w := range want {
+		e := <-sub.Events
+		if e.Type != w.typ || e.JobID != "job-1" {
+			t.Fatalf("event %d: got %v %s, want %v job-1", i, e.Type, e.JobID, w.typ)
+		}
+		if w.typ == Deleted {
+			if e.Job != nil {
+				t.Errorf("event %d: delete should carry no job", i)
+			}
+			continue
+		}
+		if e.Job.Status != w.status {
+			t.Errorf("event %d: got status %s, want %s", i, e.Job.Status, w.status)
+		}
+	}
+
+	select {
+	case e := <-sub.Events:
+		t.Fatalf("unexpected event %+v", e)
+	default:
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(1)
+	slow := hub.Subscribe()
+	fast := hub.Subscribe()
+	defer fast.Close()
+
+	hub.Publish(Event{Type: Created, JobID: "a"})
+	<-fast.Events
+	hub.Publish(Event{Type: Created, JobID: "b"})
+
+	if !slow.Dropped() {
+		t.Fatal("expected slow subscriber to be dropped")
+	}
+	if fast.Dropped() {
+		t.Fatal("fast subscriber should not be dropped")
+	}
+	if hub.Subscribers() != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", hub.Subscribers())
+	}
+
+	// The buffered event is still delivered before the channel closes
+	if e, ok := <-slow.Events; !ok || e.JobID != "a" {
+		t.Fatalf("expected buffered event a, got %+v ok=%v", e, ok)
+	}
+	if _, ok := <-slow.Events; ok {
+		t.Fatal("expected closed channel")
+	}
+
+	// Closing a dropped subscription is a no-op
+	slow.Close()
+}